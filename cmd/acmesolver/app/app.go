@@ -18,22 +18,47 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 
 	"github.com/jetstack/cert-manager/cmd/util"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/http/solver"
+	tlsalpnsolver "github.com/jetstack/cert-manager/pkg/issuer/acme/tlsalpn/solver"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
 
+// challengeServer is a server that responds to ACME validation requests for
+// a single challenge.
+type challengeServer interface {
+	Listen(log logr.Logger) error
+	Shutdown(ctx context.Context) error
+}
+
 func NewACMESolverCommand(stopCh <-chan struct{}) *cobra.Command {
-	s := new(solver.HTTP01Solver)
+	h := new(solver.HTTP01Solver)
+	challengeType := "http-01"
 
 	cmd := &cobra.Command{
 		Use:   "acmesolver",
-		Short: "HTTP server used to solve ACME challenges.",
+		Short: "Server used to solve ACME HTTP-01 and TLS-ALPN-01 challenges.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var s challengeServer
+			switch challengeType {
+			case "http-01":
+				s = h
+			case "tls-alpn-01":
+				s = &tlsalpnsolver.TLSALPN01Solver{
+					ListenPort: h.ListenPort,
+					Domain:     h.Domain,
+					Key:        h.Key,
+				}
+			default:
+				return fmt.Errorf("unsupported challenge type %q, must be one of http-01 or tls-alpn-01", challengeType)
+			}
+
 			rootCtx := util.ContextWithStopCh(context.Background(), stopCh)
 			rootCtx = logf.NewContext(rootCtx, nil, "acmesolver")
 			log := logf.FromContext(rootCtx)
@@ -61,10 +86,11 @@ func NewACMESolverCommand(stopCh <-chan struct{}) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&s.ListenPort, "listen-port", 8089, "the port number to listen on for connections")
-	cmd.Flags().StringVar(&s.Domain, "domain", "", "the domain name to verify")
-	cmd.Flags().StringVar(&s.Token, "token", "", "the challenge token to verify against")
-	cmd.Flags().StringVar(&s.Key, "key", "", "the challenge key to respond with")
+	cmd.Flags().StringVar(&challengeType, "challenge-type", challengeType, "the type of challenge to solve, one of http-01 or tls-alpn-01")
+	cmd.Flags().IntVar(&h.ListenPort, "listen-port", 8089, "the port number to listen on for connections")
+	cmd.Flags().StringVar(&h.Domain, "domain", "", "the domain name to verify")
+	cmd.Flags().StringVar(&h.Token, "token", "", "the challenge token to verify against (http-01 only)")
+	cmd.Flags().StringVar(&h.Key, "key", "", "the challenge key to respond with")
 
 	return cmd
 }
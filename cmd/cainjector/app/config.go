@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configvalidation "github.com/jetstack/cert-manager/internal/apis/config/validation"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/util/configfile"
+)
+
+// LoadConfigFile sets the flags in fs that were not set on the command line
+// to the values of the configuration file given by --config, if any.
+func (o *InjectorControllerOptions) LoadConfigFile(fs *pflag.FlagSet) error {
+	if o.Config == "" {
+		return nil
+	}
+
+	cfg := &configv1alpha1.CAInjectorConfiguration{}
+	if err := configfile.Load(o.Config, cfg); err != nil {
+		return err
+	}
+	if err := validateCAInjectorConfiguration(cfg); err != nil {
+		return err
+	}
+
+	s := configfile.NewFlagSetter(fs)
+	s.String("namespace", cfg.Namespace)
+	s.LeaderElection(cfg.LeaderElection)
+	s.Int32("shard-count", cfg.ShardCount)
+	s.Int32("shard-index", cfg.ShardIndex)
+	s.Logging(cfg.Logging)
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	o.configFlags = s
+	return nil
+}
+
+// WatchConfigFile reloads the configuration file given by --config every
+// time it changes, until ctx is done.
+func (o *InjectorControllerOptions) WatchConfigFile(ctx context.Context) {
+	if o.configFlags == nil {
+		return
+	}
+	o.configFlags.Watch(ctx, o.Config,
+		func() runtime.Object { return &configv1alpha1.CAInjectorConfiguration{} },
+		func(obj runtime.Object) error {
+			return validateCAInjectorConfiguration(obj.(*configv1alpha1.CAInjectorConfiguration))
+		},
+		func(obj runtime.Object) **configv1alpha1.LoggingConfig {
+			return &obj.(*configv1alpha1.CAInjectorConfiguration).Logging
+		},
+	)
+}
+
+func validateCAInjectorConfiguration(cfg *configv1alpha1.CAInjectorConfiguration) error {
+	if err := configvalidation.ValidateCAInjectorConfiguration(cfg).ToAggregate(); err != nil {
+		return fmt.Errorf("invalid configuration file: %v", err)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -36,10 +37,17 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/cainjector"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/configfile"
 	"github.com/jetstack/cert-manager/pkg/util/profiling"
 )
 
 type InjectorControllerOptions struct {
+	// Config is the path to a CAInjectorConfiguration file. Flags that are
+	// set on the command line take precedence over the file.
+	Config string
+	// configFlags applies the configuration file when it is reloaded.
+	configFlags *configfile.FlagSetter
+
 	Namespace               string
 	LeaderElect             bool
 	LeaderElectionNamespace string
@@ -47,6 +55,15 @@ type InjectorControllerOptions struct {
 	RenewDeadline           time.Duration
 	RetryPeriod             time.Duration
 
+	// InjectCAIntoKinds is a list of additional kinds, in the form
+	// Kind.version.group, that CAs are injected into.
+	InjectCAIntoKinds []string
+
+	// ShardIndex and ShardCount split injection targets across multiple
+	// instances of the cainjector.
+	ShardIndex int
+	ShardCount int
+
 	StdOut io.Writer
 	StdErr io.Writer
 
@@ -61,6 +78,9 @@ type InjectorControllerOptions struct {
 }
 
 func (o *InjectorControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Config, "config", "", ""+
+		"Path to a CAInjectorConfiguration file. Flags set on the command line take precedence "+
+		"over the file. Changes to the log verbosity are applied without a restart.")
 	fs.StringVar(&o.Namespace, "namespace", "", ""+
 		"If set, this limits the scope of cainjector to a single namespace. "+
 		"If set, cainjector will not update resources with certificates outside of the "+
@@ -84,6 +104,20 @@ func (o *InjectorControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"The duration the clients should wait between attempting acquisition and renewal "+
 		"of a leadership. This is only applicable if leader election is enabled.")
 
+	fs.StringSliceVar(&o.InjectCAIntoKinds, "inject-ca-into-kinds", []string{}, ""+
+		"Additional kinds, in the form Kind.version.group (e.g. Foo.v1.example.com), that CAs are "+
+		"injected into. Resources of these kinds must set the 'cert-manager.io/inject-ca-into' "+
+		"annotation to the field that the CA is injected into, e.g. 'spec.caBundle'. The cainjector "+
+		"must be granted permission to get, list, watch and update these resources.")
+
+	fs.IntVar(&o.ShardCount, "shard-count", 1, ""+
+		"The number of shards that injection targets are split into by the hash of their namespace "+
+		"and name. Run one or more instances of cainjector for every shard, each with a different "+
+		"--shard-index. Leader election is performed separately for every shard.")
+	fs.IntVar(&o.ShardIndex, "shard-index", 0, ""+
+		"The shard of injection targets, from 0 to --shard-count minus 1, that this instance of "+
+		"cainjector injects CAs into.")
+
 	fs.BoolVar(&o.EnablePprof, "enable-profiling", cmdutil.DefaultEnableProfiling, "Enable Go profiler (pprof) should be run.")
 	fs.StringVar(&o.PprofAddr, "profiler-address", cmdutil.DefaultProfilerAddr, "Address of the Go profiler (pprof) if enabled. This should never be exposed on a public interface.")
 }
@@ -116,6 +150,12 @@ servers and webhook servers.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			o.log = logf.Log.WithName("ca-injector")
 
+			if err := o.LoadConfigFile(cmd.Flags()); err != nil {
+				return fmt.Errorf("error loading configuration file: %s", err)
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
 			logf.V(logf.InfoLevel).InfoS("starting", "version", util.AppVersion, "revision", util.AppGitCommit)
 			return o.RunInjectorController(ctx)
 		},
@@ -127,13 +167,57 @@ servers and webhook servers.`,
 	return cmd
 }
 
+// Validate checks the options of the cainjector.
+func (o InjectorControllerOptions) Validate() error {
+	if _, err := o.genericKinds(); err != nil {
+		return err
+	}
+	return o.shard().Validate()
+}
+
+func (o InjectorControllerOptions) shard() cainjector.Shard {
+	return cainjector.Shard{Index: o.ShardIndex, Count: o.ShardCount}
+}
+
+// leaderElectionID returns the ID used for leader election, which is
+// separate for every shard.
+func (o InjectorControllerOptions) leaderElectionID() string {
+	id := "cert-manager-cainjector-leader-election"
+	if o.shard().Enabled() {
+		id = fmt.Sprintf("%s-shard-%d", id, o.ShardIndex)
+	}
+	return id
+}
+
+// genericKinds parses the kinds given using the --inject-ca-into-kinds flag.
+func (o InjectorControllerOptions) genericKinds() ([]schema.GroupVersionKind, error) {
+	var kinds []schema.GroupVersionKind
+	for _, arg := range o.InjectCAIntoKinds {
+		gvk, _ := schema.ParseKindArg(arg)
+		if gvk == nil || gvk.Version == "" || gvk.Group == "" {
+			return nil, fmt.Errorf("invalid kind %q passed to --inject-ca-into-kinds, must be of the form Kind.version.group", arg)
+		}
+		kinds = append(kinds, *gvk)
+	}
+	return kinds, nil
+}
+
 func (o InjectorControllerOptions) RunInjectorController(ctx context.Context) error {
+	genericKinds, err := o.genericKinds()
+	if err != nil {
+		return err
+	}
+	opts := cainjector.Options{
+		GenericKinds: genericKinds,
+		Shard:        o.shard(),
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                        api.Scheme,
 		Namespace:                     o.Namespace,
 		LeaderElection:                o.LeaderElect,
 		LeaderElectionNamespace:       o.LeaderElectionNamespace,
-		LeaderElectionID:              "cert-manager-cainjector-leader-election",
+		LeaderElectionID:              o.leaderElectionID(),
 		LeaderElectionReleaseOnCancel: true,
 		LeaseDuration:                 &o.LeaseDuration,
 		RenewDeadline:                 &o.RenewDeadline,
@@ -146,6 +230,8 @@ func (o InjectorControllerOptions) RunInjectorController(ctx context.Context) er
 
 	g, gctx := errgroup.WithContext(ctx)
 
+	go o.WatchConfigFile(gctx)
+
 	// if a PprofAddr is provided, start the pprof listener
 	if o.EnablePprof {
 		pprofListener, err := net.Listen("tcp", o.PprofAddr)
@@ -207,7 +293,7 @@ func (o InjectorControllerOptions) RunInjectorController(ctx context.Context) er
 	// Never retry if the controller exits cleanly.
 	g.Go(func() (err error) {
 		for {
-			err = cainjector.RegisterCertificateBased(gctx, mgr)
+			err = cainjector.RegisterCertificateBased(gctx, mgr, opts)
 			if err == nil {
 				return
 			}
@@ -226,7 +312,7 @@ func (o InjectorControllerOptions) RunInjectorController(ctx context.Context) er
 	// We do not retry this controller because it only interacts with core APIs
 	// which should always be in a working state.
 	g.Go(func() (err error) {
-		if err = cainjector.RegisterSecretBased(gctx, mgr); err != nil {
+		if err = cainjector.RegisterSecretBased(gctx, mgr, opts); err != nil {
 			return fmt.Errorf("error registering secret controller: %v", err)
 		}
 		return
@@ -22,14 +22,18 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -40,10 +44,10 @@ import (
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
-	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha1"
-	gwclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
-	gwscheme "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/scheme"
-	gwinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwclient "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
+	gwscheme "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned/scheme"
+	gwinformers "sigs.k8s.io/gateway-api/pkg/client/informers/gateway/externalversions"
 
 	"github.com/jetstack/cert-manager/cmd/controller/app/options"
 	cmdutil "github.com/jetstack/cert-manager/cmd/util"
@@ -52,11 +56,15 @@ import (
 	intscheme "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/scheme"
 	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
 	"github.com/jetstack/cert-manager/pkg/controller"
+	shimhelper "github.com/jetstack/cert-manager/pkg/controller/certificate-shim"
 	"github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
+	"github.com/jetstack/cert-manager/pkg/controller/sharding"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	"github.com/jetstack/cert-manager/pkg/feature"
 	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/tracing"
 	"github.com/jetstack/cert-manager/pkg/util"
 	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/profiling"
@@ -77,6 +85,8 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 	rootCtx = logf.NewContext(rootCtx, nil, "controller")
 	log := logf.FromContext(rootCtx)
 
+	go opts.WatchConfigFile(rootCtx)
+
 	ctx, kubeCfg, err := buildControllerContext(rootCtx, opts)
 	if err != nil {
 		return fmt.Errorf("error building controller context (options %v): %v", opts, err)
@@ -111,6 +121,24 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 		return nil
 	})
 
+	// Export traces of certificate issuance if a collector is configured
+	shutdownTracing, err := tracing.Setup(rootCtx, tracing.Options{
+		Endpoint:      opts.TracingOTLPEndpoint,
+		SamplingRatio: opts.TracingSamplingRatio,
+		ServiceName:   "cert-manager-controller",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %v", err)
+	}
+	g.Go(func() error {
+		<-rootCtx.Done()
+		// allow a timeout for pending spans to be exported
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		return shutdownTracing(ctx)
+	})
+
 	// Start profiler if it is enabled
 	if opts.EnablePprof {
 		profilerLn, err := net.Listen("tcp", opts.PprofAddress)
@@ -145,6 +173,9 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 	}
 
 	elected := make(chan struct{})
+	// shardIndex is the index of the shard whose lease has been acquired. It
+	// is set before elected is closed.
+	var shardIndex int
 	if opts.LeaderElect {
 		g.Go(func() error {
 			log.V(logf.InfoLevel).Info("starting leader election")
@@ -154,7 +185,7 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 			}
 
 			errorCh := make(chan error, 1)
-			if err := startLeaderElection(rootCtx, opts, leaderElectionClient, ctx.Recorder, leaderelection.LeaderCallbacks{
+			callbacks := leaderelection.LeaderCallbacks{
 				OnStartedLeading: func(_ context.Context) {
 					close(elected)
 				},
@@ -167,7 +198,13 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 						errorCh <- errors.New("leader election lost")
 					}
 				},
-			}); err != nil {
+			}
+			if opts.ShardCount > 1 {
+				err = startShardLeaderElection(rootCtx, opts, leaderElectionClient, ctx.Recorder, func(index int) { shardIndex = index }, callbacks)
+			} else {
+				err = startLeaderElection(rootCtx, opts, leaderElectionClient, ctx.Recorder, callbacks)
+			}
+			if err != nil {
 				return err
 			}
 
@@ -190,6 +227,16 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 		// Continue with setting up controller
 	}
 
+	if opts.ShardCount > 1 {
+		log.V(logf.InfoLevel).Info("processing resources of shard", "shard", shardIndex, "shards", opts.ShardCount)
+		ctx.Shard = sharding.New(shardIndex, opts.ShardCount, ctx.KubeSharedInformerFactory.Core().V1().Namespaces())
+	}
+
+	workerCounts, err := opts.WorkerCounts()
+	if err != nil {
+		return err
+	}
+
 	for n, fn := range controller.Known() {
 		log := log.WithValues("controller", n)
 
@@ -217,11 +264,9 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) error {
 			return err
 		}
 
+		workers := options.WorkersFor(workerCounts, n)
 		g.Go(func() error {
-			log.V(logf.InfoLevel).Info("starting controller")
-
-			// TODO: make this either a constant or a command line flag
-			workers := 5
+			log.V(logf.InfoLevel).Info("starting controller", "workers", workers)
 			return iface.Run(workers, rootCtx.Done())
 		})
 	}
@@ -322,6 +367,24 @@ func buildControllerContext(ctx context.Context, opts *options.ControllerOptions
 		return nil, nil, fmt.Errorf("error parsing ACMEHTTP01SolverResourceLimitsMemory: %s", err.Error())
 	}
 
+	var HTTP01SelfCheckProxy *url.URL
+	if len(opts.ACMEHTTP01SelfCheckProxy) > 0 {
+		HTTP01SelfCheckProxy, err = url.Parse(opts.ACMEHTTP01SelfCheckProxy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing ACMEHTTP01SelfCheckProxy: %s", err.Error())
+		}
+	}
+
+	rateLimiters, err := opts.RateLimiterOptions()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing controller backoff options: %s", err.Error())
+	}
+
+	ingressClassDefaultIssuers, err := shimhelper.ParseIngressClassDefaultIssuers(opts.IngressClassDefaultIssuers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing ingress class default issuers: %s", err.Error())
+	}
+
 	// Create event broadcaster
 	// Add cert-manager types to the default Kubernetes Scheme so Events can be
 	// logged properly
@@ -333,9 +396,40 @@ func buildControllerContext(ctx context.Context, opts *options.ControllerOptions
 	eventBroadcaster.StartRecordingToSink(&clientv1.EventSinkImpl{Interface: cl.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
-	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(intcl, resyncPeriod, informers.WithNamespace(opts.Namespace))
-	kubeSharedInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(cl, resyncPeriod, kubeinformers.WithNamespace(opts.Namespace))
-	gwSharedInformerFactory := gwinformers.NewSharedInformerFactoryWithOptions(gwcl, resyncPeriod, gwinformers.WithNamespace(opts.Namespace))
+	var sharedInformerFactory informers.SharedInformerFactory
+	var kubeSharedInformerFactory kubeinformers.SharedInformerFactory
+	var gwSharedInformerFactory gwinformers.SharedInformerFactory
+	if len(opts.Namespaces) > 1 {
+		// Each of the namespaces is watched by separate informers, as well as
+		// the cluster resource namespace that holds the Secrets of
+		// ClusterIssuers, so that resources in other namespaces are not held
+		// in memory.
+		watched := sets.NewString(opts.Namespaces...).Insert(opts.ClusterResourceNamespace).List()
+		sharedInformerFactory = controller.NewMultiNamespaceInformerFactory(intcl, resyncPeriod, watched)
+		kubeSharedInformerFactory = controller.NewMultiNamespaceKubeInformerFactory(cl, resyncPeriod, watched, opts.FilterSecretsByLabel)
+		gwSharedInformerFactory = controller.NewMultiNamespaceGatewayInformerFactory(gwcl, resyncPeriod, watched)
+	} else {
+		sharedInformerFactory = informers.NewSharedInformerFactoryWithOptions(intcl, resyncPeriod, informers.WithNamespace(opts.ScopedNamespace()))
+		if opts.FilterSecretsByLabel {
+			kubeSharedInformerFactory = controller.NewSecretFilteringInformerFactory(cl, resyncPeriod, opts.ScopedNamespace())
+		} else {
+			kubeSharedInformerFactory = kubeinformers.NewSharedInformerFactoryWithOptions(cl, resyncPeriod, kubeinformers.WithNamespace(opts.ScopedNamespace()))
+		}
+		gwSharedInformerFactory = gwinformers.NewSharedInformerFactoryWithOptions(gwcl, resyncPeriod, gwinformers.WithNamespace(opts.ScopedNamespace()))
+	}
+
+	// A single namespace is watched by scoping the informers above. Otherwise
+	// the resources of the watched namespaces that are not selected, such as
+	// those in the cluster resource namespace or in namespaces not matching
+	// the namespace selector, are skipped by the controllers.
+	var namespaceScope *controller.NamespaceScope
+	if len(opts.Namespaces) > 1 || opts.NamespaceSelector != "" {
+		selector, err := labels.Parse(opts.NamespaceSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing namespace selector: %v", err)
+		}
+		namespaceScope = controller.NewNamespaceScope(opts.Namespaces, selector, kubeSharedInformerFactory.Core().V1().Namespaces())
+	}
 
 	acmeAccountRegistry := accounts.NewDefaultRegistry()
 
@@ -352,9 +446,11 @@ func buildControllerContext(ctx context.Context, opts *options.ControllerOptions
 		SharedInformerFactory:     sharedInformerFactory,
 		GWShared:                  gwSharedInformerFactory,
 		GatewaySolverEnabled:      gatewayAvailable,
-		Namespace:                 opts.Namespace,
+		Namespace:                 opts.ScopedNamespace(),
+		NamespaceScope:            namespaceScope,
 		Clock:                     clock.RealClock{},
 		Metrics:                   metrics.New(log, clock.RealClock{}),
+		StatusPatcher:             statuspatch.New(opts.StatusUpdateQPS, opts.StatusUpdateBurst),
 		ACMEOptions: controller.ACMEOptions{
 			HTTP01SolverImage:                 opts.ACMEHTTP01SolverImage,
 			HTTP01SolverResourceRequestCPU:    HTTP01SolverResourceRequestCPU,
@@ -365,25 +461,41 @@ func buildControllerContext(ctx context.Context, opts *options.ControllerOptions
 			DNS01Nameservers:                  nameservers,
 			AccountRegistry:                   acmeAccountRegistry,
 			DNS01CheckRetryPeriod:             opts.DNS01CheckRetryPeriod,
+			DNS01CleanupDryRunProviders:       opts.DNS01CleanupDryRunProviders,
+			HTTP01SelfCheckMode:               controller.HTTP01SelfCheckMode(opts.ACMEHTTP01SelfCheckMode),
+			HTTP01SelfCheckProxy:              HTTP01SelfCheckProxy,
 		},
 		IssuerOptions: controller.IssuerOptions{
 			ClusterIssuerAmbientCredentials: opts.ClusterIssuerAmbientCredentials,
 			IssuerAmbientCredentials:        opts.IssuerAmbientCredentials,
 			ClusterResourceNamespace:        opts.ClusterResourceNamespace,
+			SetupTimeout:                    opts.IssuerSetupTimeout,
+			SignTimeout:                     opts.IssuerSignTimeout,
+			EnableCAIssuerAIAFetching:       opts.EnableCAIssuerAIAFetching,
+			IssuerTypes:                     opts.IssuerTypePolicy(),
 		},
 		IngressShimOptions: controller.IngressShimOptions{
 			DefaultIssuerName:                 opts.DefaultIssuerName,
 			DefaultIssuerKind:                 opts.DefaultIssuerKind,
 			DefaultIssuerGroup:                opts.DefaultIssuerGroup,
 			DefaultAutoCertificateAnnotations: opts.DefaultAutoCertificateAnnotations,
+			IngressClassDefaultIssuers:        ingressClassDefaultIssuers,
+			DefaultSubjectOrganizations:       opts.DefaultSubjectOrganizations,
+			DefaultSubjectOrganizationalUnits: opts.DefaultSubjectOrganizationalUnits,
 		},
 		CertificateOptions: controller.CertificateOptions{
 			EnableOwnerRef:           opts.EnableCertificateOwnerRef,
+			StrictSecretOwnership:    opts.EnableStrictSecretOwnership,
 			CopiedAnnotationPrefixes: opts.CopiedAnnotationPrefixes,
+			SecretHashAnnotation:     opts.SecretHashAnnotation,
+			ExternalKeySignerURLs:    opts.ExternalKeySignerURLs,
 		},
 		SchedulerOptions: controller.SchedulerOptions{
 			MaxConcurrentChallenges: opts.MaxConcurrentChallenges,
 		},
+		WorkqueueOptions: controller.WorkqueueOptions{
+			RateLimiters: rateLimiters,
+		},
 	}, kubeCfg, nil
 }
 
@@ -430,3 +542,98 @@ func startLeaderElection(ctx context.Context, opts *options.ControllerOptions, l
 
 	return nil
 }
+
+// startShardLeaderElection competes for the leases of all shards at once. The
+// first lease to be acquired is kept and passed to onShard before the
+// callbacks are run, and the others are released so that they can be
+// acquired by other replicas.
+func startShardLeaderElection(ctx context.Context, opts *options.ControllerOptions, leaderElectionClient kubernetes.Interface, recorder record.EventRecorder, onShard func(index int), callbacks leaderelection.LeaderCallbacks) error {
+	id, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("error getting hostname: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		claimed = -1
+	)
+	cancels := make([]context.CancelFunc, opts.ShardCount)
+	electors := make([]*leaderelection.LeaderElector, opts.ShardCount)
+	for i := range electors {
+		i := i
+		lock, err := resourcelock.New(resourcelock.LeasesResourceLock,
+			opts.LeaderElectionNamespace,
+			sharding.LeaseName("cert-manager-controller", i),
+			leaderElectionClient.CoreV1(),
+			leaderElectionClient.CoordinationV1(),
+			resourcelock.ResourceLockConfig{
+				Identity:      id + "-external-cert-manager-controller",
+				EventRecorder: recorder,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("error creating leader election lock for shard %d: %v", i, err)
+		}
+
+		electors[i], err = leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   opts.LeaderElectionLeaseDuration,
+			RenewDeadline:   opts.LeaderElectionRenewDeadline,
+			RetryPeriod:     opts.LeaderElectionRetryPeriod,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(leaderCtx context.Context) {
+					mu.Lock()
+					if claimed != -1 {
+						// Another shard has already been acquired.
+						mu.Unlock()
+						cancels[i]()
+						return
+					}
+					claimed = i
+					mu.Unlock()
+
+					for j, cancel := range cancels {
+						if j != i {
+							cancel()
+						}
+					}
+					onShard(i)
+					callbacks.OnStartedLeading(leaderCtx)
+				},
+				OnStoppedLeading: func() {
+					// This is also called by the electors of the shards that
+					// were released or never acquired.
+					mu.Lock()
+					defer mu.Unlock()
+					if claimed == i {
+						callbacks.OnStoppedLeading()
+					}
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// All contexts must exist before any of the electors can acquire a lease
+	// and cancel the others.
+	shardCtxs := make([]context.Context, opts.ShardCount)
+	for i := range shardCtxs {
+		shardCtxs[i], cancels[i] = context.WithCancel(ctx)
+		defer cancels[i]()
+	}
+
+	var wg sync.WaitGroup
+	for i, le := range electors {
+		wg.Add(1)
+		go func(le *leaderelection.LeaderElector, ctx context.Context) {
+			defer wg.Done()
+			le.Run(ctx)
+		}(le, shardCtxs[i])
+	}
+	wg.Wait()
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configvalidation "github.com/jetstack/cert-manager/internal/apis/config/validation"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/util/configfile"
+)
+
+// LoadConfigFile sets the flags in fs that were not set on the command line
+// to the values of the configuration file given by --config, if any.
+func (o *ControllerOptions) LoadConfigFile(fs *pflag.FlagSet) error {
+	if o.Config == "" {
+		return nil
+	}
+
+	cfg := &configv1alpha1.ControllerConfiguration{}
+	if err := configfile.Load(o.Config, cfg); err != nil {
+		return err
+	}
+	if err := validateControllerConfiguration(cfg); err != nil {
+		return err
+	}
+
+	s := configfile.NewFlagSetter(fs)
+	s.Float32("kube-api-qps", cfg.KubernetesAPIQPS)
+	s.Int32("kube-api-burst", cfg.KubernetesAPIBurst)
+	s.String("cluster-resource-namespace", cfg.ClusterResourceNamespace)
+	s.Strings("namespace", cfg.Namespaces)
+	s.String("namespace-selector", cfg.NamespaceSelector)
+	s.LeaderElection(cfg.LeaderElection)
+	s.Int32("shard-count", cfg.ShardCount)
+	s.Strings("controllers", cfg.Controllers)
+	s.BoolMap("feature-gates", cfg.FeatureGates)
+	s.Int32Map("controller-workers", cfg.ControllerWorkers)
+	s.ControllerBackoff(cfg.ControllerBackoff)
+	s.Int32("max-concurrent-challenges", cfg.MaxConcurrentChallenges)
+	s.String("metrics-listen-address", cfg.MetricsListenAddress)
+	s.Logging(cfg.Logging)
+	s.Bool("issuer-ambient-credentials", cfg.IssuerAmbientCredentials)
+	s.Bool("cluster-issuer-ambient-credentials", cfg.ClusterIssuerAmbientCredentials)
+	s.Duration("issuer-setup-timeout", cfg.IssuerSetupTimeout)
+	s.Duration("issuer-sign-timeout", cfg.IssuerSignTimeout)
+	s.Bool("enable-ca-issuer-aia-fetching", cfg.EnableCAIssuerAIAFetching)
+	s.Strings("allowed-issuer-types", cfg.AllowedIssuerTypes)
+	s.Strings("denied-issuer-types", cfg.DeniedIssuerTypes)
+	if shim := cfg.IngressShim; shim != nil {
+		s.String("default-issuer-name", shim.DefaultIssuerName)
+		s.String("default-issuer-kind", shim.DefaultIssuerKind)
+		s.String("default-issuer-group", shim.DefaultIssuerGroup)
+		s.StringMap("ingress-class-default-issuers", shim.IngressClassDefaultIssuers)
+	}
+	if dns01 := cfg.ACMEDNS01; dns01 != nil {
+		s.Strings("dns01-recursive-nameservers", dns01.RecursiveNameservers)
+		s.Bool("dns01-recursive-nameservers-only", dns01.RecursiveNameserversOnly)
+		s.Duration("dns01-check-retry-period", dns01.CheckRetryPeriod)
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	o.configFlags = s
+	return nil
+}
+
+// WatchConfigFile reloads the configuration file given by --config every
+// time it changes, until ctx is done.
+func (o *ControllerOptions) WatchConfigFile(ctx context.Context) {
+	if o.configFlags == nil {
+		return
+	}
+	o.configFlags.Watch(ctx, o.Config,
+		func() runtime.Object { return &configv1alpha1.ControllerConfiguration{} },
+		func(obj runtime.Object) error {
+			return validateControllerConfiguration(obj.(*configv1alpha1.ControllerConfiguration))
+		},
+		func(obj runtime.Object) **configv1alpha1.LoggingConfig {
+			return &obj.(*configv1alpha1.ControllerConfiguration).Logging
+		},
+	)
+}
+
+func validateControllerConfiguration(cfg *configv1alpha1.ControllerConfiguration) error {
+	if err := configvalidation.ValidateControllerConfiguration(cfg).ToAggregate(); err != nil {
+		return fmt.Errorf("invalid configuration file: %v", err)
+	}
+	return nil
+}
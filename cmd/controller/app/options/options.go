@@ -19,30 +19,46 @@ package options
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	cmdutil "github.com/jetstack/cert-manager/cmd/util"
-	cm "github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/controller"
 	challengescontroller "github.com/jetstack/cert-manager/pkg/controller/acmechallenges"
 	orderscontroller "github.com/jetstack/cert-manager/pkg/controller/acmeorders"
+	bundlescontroller "github.com/jetstack/cert-manager/pkg/controller/bundles"
+	cacrlcontroller "github.com/jetstack/cert-manager/pkg/controller/cacrl"
+	shimhelper "github.com/jetstack/cert-manager/pkg/controller/certificate-shim"
 	shimgatewaycontroller "github.com/jetstack/cert-manager/pkg/controller/certificate-shim/gateways"
 	shimingresscontroller "github.com/jetstack/cert-manager/pkg/controller/certificate-shim/ingresses"
 	cracmecontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/acme"
 	crapprovercontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/approver"
 	crcacontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/ca"
+	crpolicyapprovercontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/policyapprover"
 	crselfsignedcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/selfsigned"
 	crvaultcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/vault"
 	crvenaficontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/venafi"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/issuing"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/keymanager"
 	certificatesmetricscontroller "github.com/jetstack/cert-manager/pkg/controller/certificates/metrics"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/podreadiness"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/readiness"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/requestmanager"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/revisionmanager"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/revocation"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/secretlabeler"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/secretnotifier"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/secretsinks"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger"
 	csracmecontroller "github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests/acme"
 	csrcacontroller "github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests/ca"
@@ -52,19 +68,32 @@ import (
 	clusterissuerscontroller "github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
 	issuerscontroller "github.com/jetstack/cert-manager/pkg/controller/issuers"
 	"github.com/jetstack/cert-manager/pkg/feature"
+	dnsprovider "github.com/jetstack/cert-manager/pkg/issuer/acme/dns"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/configfile"
 	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
 
 type ControllerOptions struct {
+	// Config is the path to a ControllerConfiguration file. Flags that are
+	// set on the command line take precedence over the file.
+	Config string
+	// configFlags applies the configuration file when it is reloaded.
+	configFlags *configfile.FlagSetter
+
 	APIServerHost      string
 	Kubeconfig         string
 	KubernetesAPIQPS   float32
 	KubernetesAPIBurst int
 
 	ClusterResourceNamespace string
-	Namespace                string
+	// Namespaces limits the scope of cert-manager to the given namespaces.
+	// If empty, all namespaces are watched.
+	Namespaces []string
+	// NamespaceSelector is a label selector that namespaces must match for
+	// their resources to be processed.
+	NamespaceSelector string
 
 	LeaderElect                 bool
 	LeaderElectionNamespace     string
@@ -72,6 +101,16 @@ type ControllerOptions struct {
 	LeaderElectionRenewDeadline time.Duration
 	LeaderElectionRetryPeriod   time.Duration
 
+	// ShardCount is the number of shards that namespaced resources are split
+	// between. If greater than 1, each replica acquires the lease of a single
+	// shard and only processes the resources of that shard.
+	ShardCount int
+
+	// FilterSecretsByLabel restricts the Secrets that are cached by the
+	// controller to those labelled as part of cert-manager. Other Secrets
+	// are read from the apiserver when they are needed.
+	FilterSecretsByLabel bool
+
 	controllers []string
 
 	ACMEHTTP01SolverImage                 string
@@ -80,23 +119,50 @@ type ControllerOptions struct {
 	ACMEHTTP01SolverResourceLimitsCPU     string
 	ACMEHTTP01SolverResourceLimitsMemory  string
 
+	ACMEHTTP01SelfCheckMode  string
+	ACMEHTTP01SelfCheckProxy string
+
 	ClusterIssuerAmbientCredentials bool
 	IssuerAmbientCredentials        bool
 
+	IssuerSetupTimeout time.Duration
+	IssuerSignTimeout  time.Duration
+
+	EnableCAIssuerAIAFetching bool
+
+	// Issuer types that may or may not be used in the cluster
+	AllowedIssuerTypes []string
+	DeniedIssuerTypes  []string
+
 	// Default issuer/certificates details consumed by ingress-shim
 	DefaultIssuerName                 string
 	DefaultIssuerKind                 string
 	DefaultIssuerGroup                string
 	DefaultAutoCertificateAnnotations []string
+	// Default issuers keyed by IngressClass name, in the form
+	// <kind>[.<group>]/<name>
+	IngressClassDefaultIssuers map[string]string
+	// Templates for the subject of Certificates created for Ingresses and
+	// Gateways
+	DefaultSubjectOrganizations       []string
+	DefaultSubjectOrganizationalUnits []string
 
 	// Allows specifying a list of custom nameservers to perform DNS checks on.
 	DNS01RecursiveNameservers []string
 	// Allows controlling if recursive nameservers are only used for all checks.
 	// Normally authoritative nameservers are used for checking propagation.
 	DNS01RecursiveNameserversOnly bool
+	// Names of the DNS01 providers whose challenge records are not deleted
+	// when challenges are cleaned up.
+	DNS01CleanupDryRunProviders []string
 
 	EnableCertificateOwnerRef bool
 
+	// EnableStrictSecretOwnership makes cert-manager prune data it does not
+	// manage from the Secrets of Certificates, and keep the labels and
+	// annotations of their secretTemplate in sync.
+	EnableStrictSecretOwnership bool
+
 	MaxConcurrentChallenges int
 
 	// The host and port address, separated by a ':', that the Prometheus server
@@ -108,38 +174,89 @@ type ControllerOptions struct {
 	// EnablePprof determines whether pprof should be enabled.
 	EnablePprof bool
 
+	// TracingOTLPEndpoint is the host and port of an OTLP gRPC collector that
+	// traces of certificate issuance are exported to. Tracing is disabled if
+	// it is empty.
+	TracingOTLPEndpoint string
+	// TracingSamplingRatio is the fraction of issuances that are traced.
+	TracingSamplingRatio float64
+
 	DNS01CheckRetryPeriod time.Duration
 
 	// Annotations copied Certificate -> CertificateRequest,
 	// CertificateRequest -> Order. Slice of string literals that are
 	// treated as prefixes for annotation keys.
 	CopiedAnnotationPrefixes []string
+
+	// SecretHashAnnotation is the Pod template annotation that the
+	// certificates-secret-update-notifier controller records the hash of
+	// the certificates referenced by a Deployment or StatefulSet in.
+	SecretHashAnnotation string
+
+	// ExternalKeySignerURLs are the signer service URLs that Certificates
+	// may reference in spec.privateKey.externalRef.
+	ExternalKeySignerURLs []string
+
+	// ControllerBackoffBaseDelay, ControllerBackoffMaxDelay and
+	// ControllerBackoffJitter override the exponential backoff applied by
+	// controller workqueues when an item fails to sync. They are keyed by
+	// controller name, or "*" to apply to all controllers.
+	ControllerBackoffBaseDelay map[string]string
+	ControllerBackoffMaxDelay  map[string]string
+	ControllerBackoffJitter    map[string]string
+
+	// ControllerWorkers overrides the number of items each controller
+	// processes concurrently. It is keyed by controller name, or "*" to apply
+	// to all controllers.
+	ControllerWorkers map[string]string
+
+	// StatusUpdateQPS and StatusUpdateBurst limit the rate at which the status
+	// of resources is written to the apiserver by all controllers combined.
+	StatusUpdateQPS   float32
+	StatusUpdateBurst int
 }
 
 const (
-	defaultAPIServerHost              = ""
-	defaultKubeconfig                 = ""
-	defaultKubernetesAPIQPS   float32 = 20
-	defaultKubernetesAPIBurst         = 50
+	defaultAPIServerHost      = ""
+	defaultKubeconfig         = ""
+	defaultKubernetesAPIQPS   = configv1alpha1.DefaultKubernetesAPIQPS
+	defaultKubernetesAPIBurst = configv1alpha1.DefaultKubernetesAPIBurst
+
+	defaultClusterResourceNamespace = configv1alpha1.DefaultClusterResourceNamespace
 
-	defaultClusterResourceNamespace = "kube-system"
-	defaultNamespace                = ""
+	defaultClusterIssuerAmbientCredentials = configv1alpha1.DefaultClusterIssuerAmbientCredentials
+	defaultIssuerAmbientCredentials        = configv1alpha1.DefaultIssuerAmbientCredentials
 
-	defaultClusterIssuerAmbientCredentials = true
-	defaultIssuerAmbientCredentials        = false
+	defaultIssuerSetupTimeout = configv1alpha1.DefaultIssuerSetupTimeout
+	defaultIssuerSignTimeout  = configv1alpha1.DefaultIssuerSignTimeout
+
+	defaultEnableCAIssuerAIAFetching = configv1alpha1.DefaultEnableCAIssuerAIAFetching
 
 	defaultTLSACMEIssuerName         = ""
-	defaultTLSACMEIssuerKind         = "Issuer"
-	defaultTLSACMEIssuerGroup        = cm.GroupName
+	defaultTLSACMEIssuerKind         = configv1alpha1.DefaultIngressShimIssuerKind
+	defaultTLSACMEIssuerGroup        = configv1alpha1.DefaultIngressShimIssuerGroup
 	defaultEnableCertificateOwnerRef = false
 
-	defaultDNS01RecursiveNameserversOnly = false
+	defaultEnableStrictSecretOwnership = false
+
+	defaultDNS01RecursiveNameserversOnly = configv1alpha1.DefaultDNS01RecursiveNameserversOnly
+
+	defaultMaxConcurrentChallenges = configv1alpha1.DefaultMaxConcurrentChallenges
 
-	defaultMaxConcurrentChallenges = 60
+	defaultSecretHashAnnotation = "cert-manager.io/certificate-hash"
 
-	defaultPrometheusMetricsServerAddress = "0.0.0.0:9402"
+	defaultControllerWorkers = 5
 
-	defaultDNS01CheckRetryPeriod = 10 * time.Second
+	defaultStatusUpdateQPS   float32 = 10
+	defaultStatusUpdateBurst         = 25
+
+	defaultPrometheusMetricsServerAddress = configv1alpha1.DefaultMetricsListenAddress
+
+	defaultTracingSamplingRatio = 1.0
+
+	defaultDNS01CheckRetryPeriod = configv1alpha1.DefaultDNS01CheckRetryPeriod
+
+	defaultShardCount = configv1alpha1.DefaultShardCount
 )
 
 var (
@@ -149,6 +266,8 @@ var (
 	defaultACMEHTTP01SolverResourceLimitsCPU     = "100m"
 	defaultACMEHTTP01SolverResourceLimitsMemory  = "64Mi"
 
+	defaultACMEHTTP01SelfCheckMode = string(controller.HTTP01SelfCheckExternal)
+
 	defaultAutoCertificateAnnotations = []string{"kubernetes.io/tls-acme"}
 
 	allControllers = []string{
@@ -172,6 +291,15 @@ var (
 		requestmanager.ControllerName,
 		readiness.ControllerName,
 		revisionmanager.ControllerName,
+		revocation.ControllerName,
+		// optional controllers
+		cacrlcontroller.ControllerName,
+		crpolicyapprovercontroller.ControllerName,
+		bundlescontroller.ControllerName,
+		podreadiness.ControllerName,
+		secretnotifier.ControllerName,
+		secretlabeler.ControllerName,
+		secretsinks.ControllerName,
 	}
 
 	defaultEnabledControllers = []string{
@@ -194,6 +322,7 @@ var (
 		requestmanager.ControllerName,
 		readiness.ControllerName,
 		revisionmanager.ControllerName,
+		revocation.ControllerName,
 	}
 
 	experimentalCertificateSigningRequestControllers = []string{
@@ -219,30 +348,44 @@ func NewControllerOptions() *ControllerOptions {
 		ClusterResourceNamespace:          defaultClusterResourceNamespace,
 		KubernetesAPIQPS:                  defaultKubernetesAPIQPS,
 		KubernetesAPIBurst:                defaultKubernetesAPIBurst,
-		Namespace:                         defaultNamespace,
+		Namespaces:                        []string{},
 		LeaderElect:                       cmdutil.DefaultLeaderElect,
 		LeaderElectionNamespace:           cmdutil.DefaultLeaderElectionNamespace,
 		LeaderElectionLeaseDuration:       cmdutil.DefaultLeaderElectionLeaseDuration,
 		LeaderElectionRenewDeadline:       cmdutil.DefaultLeaderElectionRenewDeadline,
 		LeaderElectionRetryPeriod:         cmdutil.DefaultLeaderElectionRetryPeriod,
+		ShardCount:                        defaultShardCount,
 		controllers:                       defaultEnabledControllers,
 		ClusterIssuerAmbientCredentials:   defaultClusterIssuerAmbientCredentials,
 		IssuerAmbientCredentials:          defaultIssuerAmbientCredentials,
+		IssuerSetupTimeout:                defaultIssuerSetupTimeout,
+		IssuerSignTimeout:                 defaultIssuerSignTimeout,
+		EnableCAIssuerAIAFetching:         defaultEnableCAIssuerAIAFetching,
 		DefaultIssuerName:                 defaultTLSACMEIssuerName,
 		DefaultIssuerKind:                 defaultTLSACMEIssuerKind,
 		DefaultIssuerGroup:                defaultTLSACMEIssuerGroup,
 		DefaultAutoCertificateAnnotations: defaultAutoCertificateAnnotations,
 		DNS01RecursiveNameservers:         []string{},
 		DNS01RecursiveNameserversOnly:     defaultDNS01RecursiveNameserversOnly,
+		DNS01CleanupDryRunProviders:       []string{},
+		ACMEHTTP01SelfCheckMode:           defaultACMEHTTP01SelfCheckMode,
 		EnableCertificateOwnerRef:         defaultEnableCertificateOwnerRef,
+		EnableStrictSecretOwnership:       defaultEnableStrictSecretOwnership,
 		MetricsListenAddress:              defaultPrometheusMetricsServerAddress,
 		DNS01CheckRetryPeriod:             defaultDNS01CheckRetryPeriod,
 		EnablePprof:                       cmdutil.DefaultEnableProfiling,
 		PprofAddress:                      cmdutil.DefaultProfilerAddr,
+		TracingSamplingRatio:              defaultTracingSamplingRatio,
+		SecretHashAnnotation:              defaultSecretHashAnnotation,
+		StatusUpdateQPS:                   defaultStatusUpdateQPS,
+		StatusUpdateBurst:                 defaultStatusUpdateBurst,
 	}
 }
 
 func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Config, "config", "", ""+
+		"Path to a ControllerConfiguration file. Flags set on the command line take precedence "+
+		"over the file. Changes to the log verbosity are applied without a restart.")
 	fs.StringVar(&s.APIServerHost, "master", defaultAPIServerHost, ""+
 		"Optional apiserver host address to connect to. If not specified, autoconfiguration "+
 		"will be attempted.")
@@ -253,9 +396,15 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ClusterResourceNamespace, "cluster-resource-namespace", defaultClusterResourceNamespace, ""+
 		"Namespace to store resources owned by cluster scoped resources such as ClusterIssuer in. "+
 		"This must be specified if ClusterIssuers are enabled.")
-	fs.StringVar(&s.Namespace, "namespace", defaultNamespace, ""+
-		"If set, this limits the scope of cert-manager to a single namespace and ClusterIssuers are disabled. "+
-		"If not specified, all namespaces will be watched")
+	fs.StringSliceVar(&s.Namespaces, "namespace", []string{}, ""+
+		"If set, this limits the scope of cert-manager to the given comma separated list of namespaces. "+
+		"If a single namespace is given, only resources in that namespace are watched and ClusterIssuers "+
+		"are disabled. If several are given, resources in each of them, and Secrets in the cluster resource "+
+		"namespace, are watched separately. If not specified, all namespaces will be watched")
+	fs.StringVar(&s.NamespaceSelector, "namespace-selector", "", ""+
+		"A label selector, e.g. 'tenant=team-a', that namespaces must match for cert-manager to process "+
+		"their resources. Resources in all namespaces, or in the namespaces given with --namespace, "+
+		"are still watched.")
 	fs.BoolVar(&s.LeaderElect, "leader-elect", cmdutil.DefaultLeaderElect, ""+
 		"If true, cert-manager will perform leader election between instances to ensure no more "+
 		"than one instance of cert-manager operates at a time")
@@ -274,6 +423,21 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&s.LeaderElectionRetryPeriod, "leader-election-retry-period", cmdutil.DefaultLeaderElectionRetryPeriod, ""+
 		"The duration the clients should wait between attempting acquisition and renewal "+
 		"of a leadership. This is only applicable if leader election is enabled.")
+	fs.IntVar(&s.ShardCount, "shard-count", defaultShardCount, ""+
+		"The number of shards that Certificates and other namespaced resources are split between. "+
+		"If greater than 1, each replica acquires the lease of one shard and only processes the "+
+		"resources in the namespaces assigned to it, so at least this many replicas must be run. "+
+		"Namespaces are assigned to a shard by a hash of their name, or by their "+
+		"'"+cmapi.ShardLabelKey+"' label. Requires leader election to be enabled.")
+	fs.BoolVar(&s.FilterSecretsByLabel, "filter-secrets-by-label", false, ""+
+		"If true, only Secrets with the '"+cmapi.PartOfCertManagerControllerLabelKey+"=true' label are "+
+		"cached, which reduces the memory used by the controller in clusters with many Secrets. "+
+		"cert-manager adds the label to the Secrets it writes, and the "+secretlabeler.ControllerName+" "+
+		"controller, which is enabled by '*' if this flag is set, adds it to the existing Secrets of "+
+		"Certificates. Other Secrets, such as those referenced by issuers, are read from the apiserver "+
+		"each time they are needed, and changes to them are not watched: CA Secrets used for CRLs and "+
+		"source Secrets of Bundles are only re-read once something else changes, unless they are "+
+		"labelled with '"+cmapi.PartOfCertManagerControllerLabelKey+"=true'.")
 
 	fs.StringSliceVar(&s.controllers, "controllers", []string{"*"}, fmt.Sprintf(""+
 		"A list of controllers to enable. '--controllers=*' enables all "+
@@ -298,6 +462,15 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ACMEHTTP01SolverResourceLimitsMemory, "acme-http01-solver-resource-limits-memory", defaultACMEHTTP01SolverResourceLimitsMemory, ""+
 		"Defines the resource limits Memory size when spawning new ACME HTTP01 challenge solver pods.")
 
+	fs.StringVar(&s.ACMEHTTP01SelfCheckMode, "acme-http01-self-check-mode", defaultACMEHTTP01SelfCheckMode, ""+
+		"Controls where the ACME HTTP01 self-check is performed against. One of 'external' or 'local'. "+
+		"'external' queries the challenge's domain in the same way as the ACME server. "+
+		"'local' only verifies that the solver pod is answering by querying it directly, which is useful "+
+		"in clusters that cannot reach their own public IP addresses.")
+	fs.StringVar(&s.ACMEHTTP01SelfCheckProxy, "acme-http01-self-check-proxy", "", ""+
+		"The URL of an HTTP proxy to send external ACME HTTP01 self-check requests through. "+
+		"If not specified, the proxy configured through the HTTP_PROXY and NO_PROXY environment variables is used.")
+
 	fs.BoolVar(&s.ClusterIssuerAmbientCredentials, "cluster-issuer-ambient-credentials", defaultClusterIssuerAmbientCredentials, ""+
 		"Whether a cluster-issuer may make use of ambient credentials for issuers. 'Ambient Credentials' are credentials drawn from the environment, metadata services, or local files which are not explicitly configured in the ClusterIssuer API object. "+
 		"When this flag is enabled, the following sources for credentials are also used: "+
@@ -306,15 +479,45 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"Whether an issuer may make use of ambient credentials. 'Ambient Credentials' are credentials drawn from the environment, metadata services, or local files which are not explicitly configured in the Issuer API object. "+
 		"When this flag is enabled, the following sources for credentials are also used: "+
 		"AWS - All sources the Go SDK defaults to, notably including any EC2 IAM roles available via instance metadata.")
+	fs.DurationVar(&s.IssuerSetupTimeout, "issuer-setup-timeout", defaultIssuerSetupTimeout, ""+
+		"The maximum time an Issuer or ClusterIssuer may take to set itself up, e.g. to register an ACME account or to "+
+		"verify its connection to Vault or Venafi. Calls to upstream services still running after this time are "+
+		"cancelled and retried later. A value of 0 disables the timeout.")
+	fs.DurationVar(&s.IssuerSignTimeout, "issuer-sign-timeout", defaultIssuerSignTimeout, ""+
+		"The maximum time an issuer may take to sign a single CertificateRequest or CertificateSigningRequest. "+
+		"Calls to upstream signing services still running after this time are cancelled and retried later. "+
+		"A value of 0 disables the timeout.")
+	fs.BoolVar(&s.EnableCAIssuerAIAFetching, "enable-ca-issuer-aia-fetching", defaultEnableCAIssuerAIAFetching, ""+
+		"Whether CA issuers which set fetchIntermediateCertificates may fetch missing CA certificates from the "+
+		"CA Issuers URLs of their certificates. Set to false to disable all outbound fetching, e.g. in air-gapped clusters.")
+	fs.StringSliceVar(&s.AllowedIssuerTypes, "allowed-issuer-types", nil, ""+
+		"The types of Issuers and ClusterIssuers that may be used, out of "+strings.Join(apiutil.IssuerTypes, ", ")+". "+
+		"Issuers of other types are marked as not Ready and are not used to sign requests. All types are allowed if empty.")
+	fs.StringSliceVar(&s.DeniedIssuerTypes, "denied-issuer-types", nil, ""+
+		"The types of Issuers and ClusterIssuers that may not be used, for example 'selfsigned'. "+
+		"Takes precedence over --allowed-issuer-types.")
 	fs.StringSliceVar(&s.DefaultAutoCertificateAnnotations, "auto-certificate-annotations", defaultAutoCertificateAnnotations, ""+
 		"The annotation consumed by the ingress-shim controller to indicate a ingress is requesting a certificate")
 
+	fs.StringArrayVar(&s.DefaultSubjectOrganizations, "default-subject-organizations", nil, ""+
+		"A Go template for a subject organization of Certificates created for Ingresses and Gateways, evaluated against the "+
+		"namespace of the Ingress or Gateway, e.g. '{{ .Namespace.Labels.org }}'. May be given multiple times. Templates that "+
+		"evaluate to an empty string are ignored. Overridden by the cert-manager.io/subject-organizations annotation.")
+	fs.StringArrayVar(&s.DefaultSubjectOrganizationalUnits, "default-subject-organizational-units", nil, ""+
+		"A Go template for a subject organizational unit of Certificates created for Ingresses and Gateways, in the same format "+
+		"as --default-subject-organizations. Overridden by the cert-manager.io/subject-organizationalunits annotation.")
+
 	fs.StringVar(&s.DefaultIssuerName, "default-issuer-name", defaultTLSACMEIssuerName, ""+
 		"Name of the Issuer to use when the tls is requested but issuer name is not specified on the ingress resource.")
 	fs.StringVar(&s.DefaultIssuerKind, "default-issuer-kind", defaultTLSACMEIssuerKind, ""+
 		"Kind of the Issuer to use when the tls is requested but issuer kind is not specified on the ingress resource.")
 	fs.StringVar(&s.DefaultIssuerGroup, "default-issuer-group", defaultTLSACMEIssuerGroup, ""+
 		"Group of the Issuer to use when the tls is requested but issuer group is not specified on the ingress resource.")
+	fs.StringToStringVar(&s.IngressClassDefaultIssuers, "ingress-class-default-issuers", nil, ""+
+		"Default issuers to use for Ingresses of a given IngressClass, in place of --default-issuer-name, "+
+		"--default-issuer-kind and --default-issuer-group, for example 'nginx=ClusterIssuer/letsencrypt,"+
+		"internal=VenafiClusterIssuer.jetstack.io/tpp'. The group defaults to cert-manager.io. "+
+		"The class is read from spec.ingressClassName or the kubernetes.io/ingress.class annotation.")
 	fs.StringSliceVar(&s.DNS01RecursiveNameservers, "dns01-recursive-nameservers",
 		[]string{}, "A list of comma separated dns server endpoints used for "+
 			"DNS01 check requests. This should be a list containing host and "+
@@ -327,16 +530,58 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 			"Enabling this option could cause the DNS01 self check to take longer "+
 			"due to caching performed by the recursive nameservers.")
 
+	fs.StringSliceVar(&s.DNS01CleanupDryRunProviders, "dns01-cleanup-dry-run-providers",
+		[]string{}, "A list of comma separated DNS01 providers whose challenge records "+
+			"are logged rather than deleted when a challenge is cleaned up, for example "+
+			"cloudflare,route53. This is useful when investigating whether a provider "+
+			"removes records it should not. Valid providers are: "+strings.Join(dnsprovider.ProviderNames, ", ")+".")
+
 	fs.BoolVar(&s.EnableCertificateOwnerRef, "enable-certificate-owner-ref", defaultEnableCertificateOwnerRef, ""+
 		"Whether to set the certificate resource as an owner of secret where the tls certificate is stored. "+
 		"When this flag is enabled, the secret will be automatically removed when the certificate resource is deleted.")
+	fs.BoolVar(&s.EnableStrictSecretOwnership, "enable-strict-secret-ownership", defaultEnableStrictSecretOwnership, ""+
+		"Whether cert-manager owns the whole of the secrets where tls certificates are stored. "+
+		"When this flag is enabled, data keys that cert-manager does not write, such as those of removed keystores and additional output formats, "+
+		"are pruned from the secret unless listed in its '"+cmapi.SecretUnmanagedDataKeysAnnotationKey+"' annotation, "+
+		"and labels and annotations removed from a certificate's secretTemplate are removed from its secret.")
 	fs.StringSliceVar(&s.CopiedAnnotationPrefixes, "copied-annotation-prefixes", defaultCopiedAnnotationPrefixes, "Specify which annotations should/shouldn't be copied"+
 		"from Certificate to CertificateRequest and Order, as well as from CertificateSigningRequest to Order, by passing a list of annotation key prefixes."+
 		"A prefix starting with a dash(-) specifies an annotation that shouldn't be copied. Example: '*,-kubectl.kuberenetes.io/'- all annotations"+
 		"will be copied apart from the ones where the key is prefixed with 'kubectl.kubernetes.io/'.")
+	fs.StringVar(&s.SecretHashAnnotation, "secret-update-notification-annotation", defaultSecretHashAnnotation, ""+
+		"The Pod template annotation that the "+secretnotifier.ControllerName+" controller records a hash of the certificates in. "+
+		"Deployments and StatefulSets that list Secrets in the '"+cmapi.RestartOnSecretUpdateAnnotationKey+"' annotation are "+
+		"rolled out whenever the hash changes.")
+	fs.StringSliceVar(&s.ExternalKeySignerURLs, "external-key-signer-urls", nil, ""+
+		"The URLs of the external signer services that Certificates may reference in spec.privateKey.externalRef. "+
+		"A Certificate's signerURL must equal, or be below the path of, one of these URLs. "+
+		"External private keys are disabled if no URLs are given.")
+
+	fs.StringToStringVar(&s.ControllerBackoffBaseDelay, "controller-backoff-base-delay", nil, ""+
+		"Override the delay before a controller first retries an item that failed to sync, for example "+
+		"'certificaterequests-issuer-venafi=30s'. The delay doubles on each subsequent failure. "+
+		"Use '*' as the controller name to set the delay for all controllers.")
+	fs.StringToStringVar(&s.ControllerBackoffMaxDelay, "controller-backoff-max-delay", nil, ""+
+		"Override the maximum delay between retries of an item that failed to sync, for example "+
+		"'certificaterequests-issuer-venafi=30m'. "+
+		"Use '*' as the controller name to set the delay for all controllers.")
+	fs.StringToStringVar(&s.ControllerBackoffJitter, "controller-backoff-jitter", nil, ""+
+		"Add random jitter of up to the given fraction of each retry delay, for example "+
+		"'certificaterequests-issuer-venafi=0.2' adds up to 20%. "+
+		"Use '*' as the controller name to set the jitter for all controllers.")
+	fs.StringToStringVar(&s.ControllerWorkers, "controller-workers", nil, ""+
+		fmt.Sprintf("Override the number of items a controller processes concurrently, which defaults to %d, ", defaultControllerWorkers)+
+		"for example 'certificaterequests-issuer-acme=10'. "+
+		"Use '*' as the controller name to set the number of workers for all controllers.")
 
 	fs.IntVar(&s.MaxConcurrentChallenges, "max-concurrent-challenges", defaultMaxConcurrentChallenges, ""+
 		"The maximum number of challenges that can be scheduled as 'processing' at once.")
+	fs.Float32Var(&s.StatusUpdateQPS, "status-update-qps", defaultStatusUpdateQPS, ""+
+		"The maximum average number of status updates per second sent to the Kubernetes apiserver by all controllers combined. "+
+		"Status updates to the same resource that are waiting to be sent are merged into a single request. "+
+		"Set to 0 to disable the limit.")
+	fs.IntVar(&s.StatusUpdateBurst, "status-update-burst", defaultStatusUpdateBurst, ""+
+		"The maximum burst of status updates sent to the Kubernetes apiserver by all controllers combined.")
 	fs.DurationVar(&s.DNS01CheckRetryPeriod, "dns01-check-retry-period", defaultDNS01CheckRetryPeriod, ""+
 		"The duration the controller should wait between checking if a ACME dns entry exists."+
 		"This should be a valid duration string, for example 180s or 1h")
@@ -347,6 +592,12 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"Enable profiling for controller.")
 	fs.StringVar(&s.PprofAddress, "profiler-address", cmdutil.DefaultProfilerAddr,
 		"The host and port that Go profiler should listen on, i.e localhost:6060. Ensure that profiler is not exposed on a public address. Profiler will be served at /debug/pprof.")
+
+	fs.StringVar(&s.TracingOTLPEndpoint, "tracing-otlp-endpoint", "", ""+
+		"The host and port of an OpenTelemetry collector that traces of certificate issuance "+
+		"should be exported to using OTLP over gRPC. If empty, tracing is disabled.")
+	fs.Float64Var(&s.TracingSamplingRatio, "tracing-sampling-ratio", defaultTracingSamplingRatio, ""+
+		"The fraction of certificate issuances that should be traced, between 0 and 1.")
 }
 
 func (o *ControllerOptions) Validate() error {
@@ -357,6 +608,22 @@ func (o *ControllerOptions) Validate() error {
 		return fmt.Errorf("invalid default issuer kind: %v", o.DefaultIssuerKind)
 	}
 
+	if err := o.IssuerTypePolicy().Validate(); err != nil {
+		return fmt.Errorf("invalid value for allowed-issuer-types or denied-issuer-types: %v", err)
+	}
+
+	if _, err := shimhelper.ParseIngressClassDefaultIssuers(o.IngressClassDefaultIssuers); err != nil {
+		return fmt.Errorf("invalid value for ingress-class-default-issuers: %v", err)
+	}
+
+	if err := shimhelper.ValidateSubjectTemplates(o.DefaultSubjectOrganizations); err != nil {
+		return fmt.Errorf("invalid value for default-subject-organizations: %v", err)
+	}
+
+	if err := shimhelper.ValidateSubjectTemplates(o.DefaultSubjectOrganizationalUnits); err != nil {
+		return fmt.Errorf("invalid value for default-subject-organizational-units: %v", err)
+	}
+
 	if o.KubernetesAPIBurst <= 0 {
 		return fmt.Errorf("invalid value for kube-api-burst: %v must be higher than 0", o.KubernetesAPIBurst)
 	}
@@ -369,6 +636,77 @@ func (o *ControllerOptions) Validate() error {
 		return fmt.Errorf("invalid value for kube-api-burst: %v must be higher or equal to kube-api-qps: %v", o.KubernetesAPIQPS, o.KubernetesAPIQPS)
 	}
 
+	if o.StatusUpdateQPS < 0 {
+		return fmt.Errorf("invalid value for status-update-qps: %v must not be negative", o.StatusUpdateQPS)
+	}
+
+	if o.StatusUpdateQPS > 0 && float32(o.StatusUpdateBurst) < o.StatusUpdateQPS {
+		return fmt.Errorf("invalid value for status-update-burst: %v must be higher or equal to status-update-qps: %v", o.StatusUpdateBurst, o.StatusUpdateQPS)
+	}
+
+	if o.TracingSamplingRatio < 0 || o.TracingSamplingRatio > 1 {
+		return fmt.Errorf("invalid value for tracing-sampling-ratio: %v must be between 0 and 1", o.TracingSamplingRatio)
+	}
+
+	for _, namespace := range o.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid value for namespace: %q: %s", namespace, strings.Join(errs, "; "))
+		}
+	}
+
+	if _, err := labels.Parse(o.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid value for namespace-selector: %v", err)
+	}
+
+	if o.ShardCount < 1 {
+		return fmt.Errorf("invalid value for shard-count: %v must be at least 1", o.ShardCount)
+	}
+
+	if o.ShardCount > 1 && !o.LeaderElect {
+		return fmt.Errorf("shard-count can only be set if leader-elect is enabled")
+	}
+
+	if o.ShardCount > 1 && o.ScopedNamespace() != "" {
+		return fmt.Errorf("shard-count cannot be set if cert-manager is scoped to a single namespace")
+	}
+
+	switch controller.HTTP01SelfCheckMode(o.ACMEHTTP01SelfCheckMode) {
+	case controller.HTTP01SelfCheckExternal, controller.HTTP01SelfCheckLocal:
+	default:
+		return fmt.Errorf("invalid value for acme-http01-self-check-mode: %q must be one of %q or %q",
+			o.ACMEHTTP01SelfCheckMode, controller.HTTP01SelfCheckExternal, controller.HTTP01SelfCheckLocal)
+	}
+
+	if len(o.ACMEHTTP01SelfCheckProxy) > 0 {
+		u, err := url.Parse(o.ACMEHTTP01SelfCheckProxy)
+		if err != nil {
+			return fmt.Errorf("invalid value for acme-http01-self-check-proxy: %v", err)
+		}
+		if len(u.Scheme) == 0 || len(u.Host) == 0 {
+			return fmt.Errorf("invalid value for acme-http01-self-check-proxy: %q must be an absolute URL", o.ACMEHTTP01SelfCheckProxy)
+		}
+	}
+
+	if errs := validation.IsQualifiedName(o.SecretHashAnnotation); len(errs) > 0 {
+		return fmt.Errorf("invalid value for secret-update-notification-annotation: %q: %s", o.SecretHashAnnotation, strings.Join(errs, "; "))
+	}
+
+	for _, signerURL := range o.ExternalKeySignerURLs {
+		u, err := url.Parse(signerURL)
+		if err != nil {
+			return fmt.Errorf("invalid value for external-key-signer-urls: %v", err)
+		}
+		if u.Scheme != "https" || len(u.Host) == 0 {
+			return fmt.Errorf("invalid value for external-key-signer-urls: %q must be an absolute https URL", signerURL)
+		}
+	}
+
+	for _, provider := range o.DNS01CleanupDryRunProviders {
+		if !sets.NewString(dnsprovider.ProviderNames...).Has(provider) {
+			return fmt.Errorf("invalid value for dns01-cleanup-dry-run-providers: %q must be one of %s", provider, strings.Join(dnsprovider.ProviderNames, ", "))
+		}
+	}
+
 	for _, server := range o.DNS01RecursiveNameservers {
 		// ensure all servers have a port number
 		_, _, err := net.SplitHostPort(server)
@@ -390,13 +728,122 @@ func (o *ControllerOptions) Validate() error {
 		}
 	}
 
+	if enabled := o.EnabledControllers(); enabled.Has(crapprovercontroller.ControllerName) && enabled.Has(crpolicyapprovercontroller.ControllerName) {
+		errs = append(errs, fmt.Errorf("%q must be disabled when enabling %q", crapprovercontroller.ControllerName, crpolicyapprovercontroller.ControllerName))
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("validation failed for '--controllers': %v", errs)
 	}
 
+	if _, err := o.RateLimiterOptions(); err != nil {
+		return err
+	}
+
+	if _, err := o.WorkerCounts(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// RateLimiterOptions parses the controller backoff flags into workqueue rate
+// limiter overrides, keyed by controller name.
+func (o *ControllerOptions) RateLimiterOptions() (map[string]controller.RateLimiterOptions, error) {
+	knownControllers := sets.NewString(allControllers...).Insert(experimentalCertificateSigningRequestControllers...).Insert("*")
+	rateLimiters := make(map[string]controller.RateLimiterOptions)
+
+	parseDurations := func(flag string, values map[string]string, set func(*controller.RateLimiterOptions, time.Duration)) error {
+		for name, value := range values {
+			if !knownControllers.Has(name) {
+				return fmt.Errorf("invalid value for %s: %q is not in the list of known controllers", flag, name)
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %s: %v", flag, name, err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("invalid value for %s: %s: %v must be higher than 0", flag, name, d)
+			}
+			opts := rateLimiters[name]
+			set(&opts, d)
+			rateLimiters[name] = opts
+		}
+		return nil
+	}
+
+	if err := parseDurations("controller-backoff-base-delay", o.ControllerBackoffBaseDelay, func(opts *controller.RateLimiterOptions, d time.Duration) {
+		opts.BaseDelay = d
+	}); err != nil {
+		return nil, err
+	}
+	if err := parseDurations("controller-backoff-max-delay", o.ControllerBackoffMaxDelay, func(opts *controller.RateLimiterOptions, d time.Duration) {
+		opts.MaxDelay = d
+	}); err != nil {
+		return nil, err
+	}
+
+	for name, value := range o.ControllerBackoffJitter {
+		if !knownControllers.Has(name) {
+			return nil, fmt.Errorf("invalid value for controller-backoff-jitter: %q is not in the list of known controllers", name)
+		}
+		jitter, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for controller-backoff-jitter: %s: %v", name, err)
+		}
+		if jitter < 0 {
+			return nil, fmt.Errorf("invalid value for controller-backoff-jitter: %s: %v must not be negative", name, jitter)
+		}
+		opts := rateLimiters[name]
+		opts.Jitter = jitter
+		rateLimiters[name] = opts
+	}
+
+	for name, opts := range rateLimiters {
+		if opts.BaseDelay > 0 && opts.MaxDelay > 0 && opts.MaxDelay < opts.BaseDelay {
+			return nil, fmt.Errorf("invalid value for controller-backoff-max-delay: %s: %v must be higher or equal to controller-backoff-base-delay: %v", name, opts.MaxDelay, opts.BaseDelay)
+		}
+	}
+
+	return rateLimiters, nil
+}
+
+// WorkerCounts parses the controller workers flag into the number of workers
+// to run, keyed by controller name.
+func (o *ControllerOptions) WorkerCounts() (map[string]int, error) {
+	knownControllers := sets.NewString(allControllers...).Insert(experimentalCertificateSigningRequestControllers...).Insert("*")
+	workers := make(map[string]int)
+
+	for name, value := range o.ControllerWorkers {
+		if !knownControllers.Has(name) {
+			return nil, fmt.Errorf("invalid value for controller-workers: %q is not in the list of known controllers", name)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for controller-workers: %s: %v", name, err)
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid value for controller-workers: %s: %v must be higher than 0", name, n)
+		}
+		workers[name] = n
+	}
+
+	return workers, nil
+}
+
+// WorkersFor returns the number of workers to run for the named controller,
+// given the counts returned by WorkerCounts. The count configured for the
+// controller takes precedence over the count configured for all controllers.
+func WorkersFor(workers map[string]int, controllerName string) int {
+	if n, ok := workers[controllerName]; ok {
+		return n
+	}
+	if n, ok := workers["*"]; ok {
+		return n
+	}
+	return defaultControllerWorkers
+}
+
 func (o *ControllerOptions) EnabledControllers() sets.String {
 	var disabled []string
 	enabled := sets.NewString()
@@ -405,6 +852,9 @@ func (o *ControllerOptions) EnabledControllers() sets.String {
 		switch {
 		case controller == "*":
 			enabled = enabled.Insert(defaultEnabledControllers...)
+			if o.FilterSecretsByLabel {
+				enabled = enabled.Insert(secretlabeler.ControllerName)
+			}
 		case strings.HasPrefix(controller, "-"):
 			disabled = append(disabled, strings.TrimPrefix(controller, "-"))
 		default:
@@ -426,3 +876,20 @@ func (o *ControllerOptions) EnabledControllers() sets.String {
 
 	return enabled
 }
+
+// IssuerTypePolicy returns the issuer types that may be used in the cluster.
+func (o *ControllerOptions) IssuerTypePolicy() apiutil.IssuerTypePolicy {
+	return apiutil.IssuerTypePolicy{
+		Allowed: o.AllowedIssuerTypes,
+		Denied:  o.DeniedIssuerTypes,
+	}
+}
+
+// ScopedNamespace returns the namespace that informers are scoped to, which
+// is only set if a single namespace is watched.
+func (o *ControllerOptions) ScopedNamespace() string {
+	if len(o.Namespaces) == 1 {
+		return o.Namespaces[0]
+	}
+	return ""
+}
@@ -17,15 +17,21 @@ limitations under the License.
 package options
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/secretlabeler"
 )
 
 func TestEnabledControllers(t *testing.T) {
 	tests := map[string]struct {
-		controllers []string
-		expEnabled  sets.String
+		controllers          []string
+		filterSecretsByLabel bool
+		expEnabled           sets.String
 	}{
 		"if no controllers enabled, return empty": {
 			controllers: []string{},
@@ -47,12 +53,23 @@ func TestEnabledControllers(t *testing.T) {
 			controllers: []string{"*", "-clusterissuers", "-issuers"},
 			expEnabled:  sets.NewString(defaultEnabledControllers...).Delete("clusterissuers", "issuers"),
 		},
+		"if secrets are filtered by label, all default controllers include the secret labeler": {
+			controllers:          []string{"*"},
+			filterSecretsByLabel: true,
+			expEnabled:           sets.NewString(defaultEnabledControllers...).Insert(secretlabeler.ControllerName),
+		},
+		"if secrets are filtered by label, the secret labeler can be disabled": {
+			controllers:          []string{"*", "-" + secretlabeler.ControllerName},
+			filterSecretsByLabel: true,
+			expEnabled:           sets.NewString(defaultEnabledControllers...),
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			o := ControllerOptions{
-				controllers: test.controllers,
+				controllers:          test.controllers,
+				FilterSecretsByLabel: test.filterSecretsByLabel,
 			}
 
 			got := o.EnabledControllers()
@@ -63,3 +80,302 @@ func TestEnabledControllers(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimiterOptions(t *testing.T) {
+	tests := map[string]struct {
+		baseDelay, maxDelay, jitter map[string]string
+		exp                         map[string]controller.RateLimiterOptions
+		expErr                      bool
+	}{
+		"if no flags set, return empty": {
+			exp: map[string]controller.RateLimiterOptions{},
+		},
+		"if flags set, merge them per controller": {
+			baseDelay: map[string]string{"*": "2s", "issuers": "1s"},
+			maxDelay:  map[string]string{"issuers": "1m"},
+			jitter:    map[string]string{"*": "0.1"},
+			exp: map[string]controller.RateLimiterOptions{
+				"*":       {BaseDelay: time.Second * 2, Jitter: 0.1},
+				"issuers": {BaseDelay: time.Second, MaxDelay: time.Minute},
+			},
+		},
+		"experimental controller names are accepted": {
+			baseDelay: map[string]string{"certificatesigningrequests-issuer-ca": "1s"},
+			exp: map[string]controller.RateLimiterOptions{
+				"certificatesigningrequests-issuer-ca": {BaseDelay: time.Second},
+			},
+		},
+		"if unknown controller, error": {
+			baseDelay: map[string]string{"foo": "1s"},
+			expErr:    true,
+		},
+		"if invalid duration, error": {
+			maxDelay: map[string]string{"issuers": "soon"},
+			expErr:   true,
+		},
+		"if negative jitter, error": {
+			jitter: map[string]string{"issuers": "-1"},
+			expErr: true,
+		},
+		"if max delay lower than base delay, error": {
+			baseDelay: map[string]string{"issuers": "1m"},
+			maxDelay:  map[string]string{"issuers": "1s"},
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := ControllerOptions{
+				ControllerBackoffBaseDelay: test.baseDelay,
+				ControllerBackoffMaxDelay:  test.maxDelay,
+				ControllerBackoffJitter:    test.jitter,
+			}
+
+			got, err := o.RateLimiterOptions()
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if !test.expErr && !reflect.DeepEqual(got, test.exp) {
+				t.Errorf("got unexpected rate limiter options, exp=%v got=%v", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestWorkerCounts(t *testing.T) {
+	tests := map[string]struct {
+		workers map[string]string
+		exp     map[string]int
+		expErr  bool
+	}{
+		"if no flags set, return empty": {
+			exp: map[string]int{},
+		},
+		"if flags set, return them per controller": {
+			workers: map[string]string{"*": "2", "issuers": "10"},
+			exp:     map[string]int{"*": 2, "issuers": 10},
+		},
+		"if unknown controller, error": {
+			workers: map[string]string{"foo": "1"},
+			expErr:  true,
+		},
+		"if not a number, error": {
+			workers: map[string]string{"issuers": "many"},
+			expErr:  true,
+		},
+		"if zero workers, error": {
+			workers: map[string]string{"issuers": "0"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := ControllerOptions{ControllerWorkers: test.workers}
+
+			got, err := o.WorkerCounts()
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if !test.expErr && !reflect.DeepEqual(got, test.exp) {
+				t.Errorf("got unexpected worker counts, exp=%v got=%v", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestWorkersFor(t *testing.T) {
+	workers := map[string]int{"*": 2, "issuers": 10}
+	if n := WorkersFor(workers, "issuers"); n != 10 {
+		t.Errorf("expected the controller's workers to be used, got %d", n)
+	}
+	if n := WorkersFor(workers, "clusterissuers"); n != 2 {
+		t.Errorf("expected the workers for all controllers to be used, got %d", n)
+	}
+	if n := WorkersFor(nil, "issuers"); n != defaultControllerWorkers {
+		t.Errorf("expected the default workers to be used, got %d", n)
+	}
+}
+
+func TestValidateControllers(t *testing.T) {
+	tests := map[string]struct {
+		controllers []string
+		expErr      bool
+	}{
+		"default controllers are valid": {
+			controllers: []string{"*"},
+		},
+		"if unknown controller, error": {
+			controllers: []string{"*", "foo"},
+			expErr:      true,
+		},
+		"policy approver replacing the default approver is valid": {
+			controllers: []string{"*", "-certificaterequests-approver", "certificaterequests-policy-approver"},
+		},
+		"if both approvers enabled, error": {
+			controllers: []string{"*", "certificaterequests-policy-approver"},
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.controllers = test.controllers
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDNS01CleanupDryRunProviders(t *testing.T) {
+	tests := map[string]struct {
+		providers []string
+		expErr    bool
+	}{
+		"no providers is valid": {},
+		"known providers are valid": {
+			providers: []string{"cloudflare", "route53"},
+		},
+		"if unknown provider, error": {
+			providers: []string{"cloudflare", "foo"},
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.DNS01CleanupDryRunProviders = test.providers
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateTracingSamplingRatio(t *testing.T) {
+	tests := map[string]struct {
+		ratio  float64
+		expErr bool
+	}{
+		"sampling nothing is valid": {
+			ratio: 0,
+		},
+		"sampling everything is valid": {
+			ratio: 1,
+		},
+		"if negative, error": {
+			ratio:  -0.1,
+			expErr: true,
+		},
+		"if greater than 1, error": {
+			ratio:  1.5,
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.TracingSamplingRatio = test.ratio
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateShardCount(t *testing.T) {
+	tests := map[string]struct {
+		shardCount  int
+		leaderElect bool
+		namespaces  []string
+		expErr      bool
+	}{
+		"a single shard is valid": {
+			shardCount: 1,
+		},
+		"multiple shards with leader election are valid": {
+			shardCount:  3,
+			leaderElect: true,
+		},
+		"if less than 1, error": {
+			shardCount:  0,
+			leaderElect: true,
+			expErr:      true,
+		},
+		"if multiple shards without leader election, error": {
+			shardCount: 3,
+			expErr:     true,
+		},
+		"if multiple shards and scoped to a namespace, error": {
+			shardCount:  3,
+			leaderElect: true,
+			namespaces:  []string{"cert-manager"},
+			expErr:      true,
+		},
+		"multiple shards watching several namespaces are valid": {
+			shardCount:  3,
+			leaderElect: true,
+			namespaces:  []string{"team-a", "team-b"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.ShardCount = test.shardCount
+			o.LeaderElect = test.leaderElect
+			o.Namespaces = test.namespaces
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateNamespaces(t *testing.T) {
+	tests := map[string]struct {
+		namespaces []string
+		selector   string
+		expErr     bool
+	}{
+		"all namespaces are valid": {},
+		"several namespaces and a selector are valid": {
+			namespaces: []string{"team-a", "team-b"},
+			selector:   "tenant in (a, b)",
+		},
+		"if a namespace is not a valid name, error": {
+			namespaces: []string{"team-a", "Team_B"},
+			expErr:     true,
+		},
+		"if the selector cannot be parsed, error": {
+			selector: "tenant in a",
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.Namespaces = test.namespaces
+			o.NamespaceSelector = test.selector
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
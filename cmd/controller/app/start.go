@@ -68,6 +68,9 @@ It will ensure certificates are valid and up to date periodically, and attempt
 to renew certificates at an appropriate time before expiry.`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.ControllerOptions.LoadConfigFile(cmd.Flags()); err != nil {
+				return fmt.Errorf("error loading configuration file: %s", err)
+			}
 			if err := o.Validate(args); err != nil {
 				return fmt.Errorf("error validating options: %s", err)
 			}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/csi"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+const (
+	defaultEndpoint   = "unix:///plugin/csi.sock"
+	defaultDriverName = "csi.cert-manager.io"
+	defaultDataRoot   = "/csi-data-dir"
+)
+
+// CSIDriverOptions are the options of the CSI driver.
+type CSIDriverOptions struct {
+	APIServerHost string
+	Kubeconfig    string
+
+	Endpoint   string
+	DriverName string
+	NodeID     string
+	DataRoot   string
+
+	PreserveCertificateRequests bool
+}
+
+func (o *CSIDriverOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.APIServerHost, "master", "", ""+
+		"Optional apiserver host address to connect to. If not specified, autoconfiguration "+
+		"will be attempted.")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", ""+
+		"Paths to a kubeconfig. Only required if out-of-cluster.")
+	fs.StringVar(&o.Endpoint, "endpoint", defaultEndpoint, ""+
+		"The unix socket that the CSI driver should listen on.")
+	fs.StringVar(&o.DriverName, "driver-name", defaultDriverName, ""+
+		"The name of the CSI driver. Must match the name of the CSIDriver object.")
+	fs.StringVar(&o.NodeID, "node-id", "", ""+
+		"The name of the node that the CSI driver is running on.")
+	fs.StringVar(&o.DataRoot, "data-root", defaultDataRoot, ""+
+		"The directory that the state and files of volumes are stored in. It must be on the "+
+		"host, and use bidirectional mount propagation, so that volumes survive restarts of "+
+		"the driver.")
+	fs.BoolVar(&o.PreserveCertificateRequests, "preserve-certificate-requests", false, ""+
+		"If true, CertificateRequests are not deleted once they have been signed or have failed. "+
+		"They are still garbage collected along with the pod that requested them.")
+}
+
+func (o *CSIDriverOptions) Validate() error {
+	if len(o.NodeID) == 0 {
+		return fmt.Errorf("--node-id must be set")
+	}
+	if len(o.DriverName) == 0 {
+		return fmt.Errorf("--driver-name must not be empty")
+	}
+	if len(o.DataRoot) == 0 {
+		return fmt.Errorf("--data-root must not be empty")
+	}
+	if !strings.HasPrefix(o.Endpoint, "unix://") {
+		return fmt.Errorf("invalid endpoint %q: must be a unix socket of the form unix:///path", o.Endpoint)
+	}
+	return nil
+}
+
+func NewCSIDriverCommand(ctx context.Context) *cobra.Command {
+	o := &CSIDriverOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "csidriver",
+		Short: fmt.Sprintf("CSI driver for ephemeral cert-manager certificates (%s) (%s)", util.AppVersion, util.AppGitCommit),
+		Long: `
+The cert-manager CSI driver mounts a certificate issued by a cert-manager
+issuer into each pod that uses one of its ephemeral inline volumes. A new
+private key is generated on the node for each volume, and a certificate is
+requested for it using a CertificateRequest in the pod's namespace. The
+certificate is renewed in place for as long as the pod runs.
+
+Private keys are only ever stored in memory backed volumes on the node, and
+are never written to Secrets.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			logf.V(logf.InfoLevel).InfoS("starting", "version", util.AppVersion, "revision", util.AppGitCommit)
+			return o.Run(ctx)
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run starts the CSI driver and blocks until the context is cancelled.
+func (o *CSIDriverOptions) Run(ctx context.Context) error {
+	log := logf.FromContext(ctx, "csidriver")
+
+	kubeCfg, err := clientcmd.BuildConfigFromFlags(o.APIServerHost, o.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error creating rest config: %v", err)
+	}
+	kubeCfg = rest.AddUserAgent(kubeCfg, util.CertManagerUserAgent)
+
+	intcl, err := clientset.NewForConfig(kubeCfg)
+	if err != nil {
+		return fmt.Errorf("error creating internal group client: %v", err)
+	}
+
+	if err := os.MkdirAll(o.DataRoot, 0700); err != nil {
+		return fmt.Errorf("failed to create data root: %v", err)
+	}
+
+	manager := csi.NewManager(intcl, csi.NewMounter(), o.DataRoot, o.PreserveCertificateRequests, log)
+	if err := manager.Resume(); err != nil {
+		return fmt.Errorf("failed to resume management of existing volumes: %v", err)
+	}
+	defer manager.Stop()
+
+	server := csi.NewGRPCServer(&csi.Driver{
+		Name:    o.DriverName,
+		Version: util.AppVersion,
+		NodeID:  o.NodeID,
+		Manager: manager,
+	})
+
+	socketPath := strings.TrimPrefix(o.Endpoint, "unix://")
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove existing socket %s: %v", socketPath, err)
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", socketPath, err)
+	}
+
+	completedCh := make(chan struct{})
+	go func() {
+		defer close(completedCh)
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	log.V(logf.InfoLevel).Info("starting CSI driver", "endpoint", o.Endpoint, "node", o.NodeID)
+	if err := server.Serve(ln); err != nil {
+		return err
+	}
+	<-completedCh
+
+	return nil
+}
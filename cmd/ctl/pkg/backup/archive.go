@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// archiveVersion is the version of the archive format written by backup.
+const archiveVersion = 1
+
+// archiveMagic prefixes every archive so that restore can reject files that
+// were not written by backup before attempting to decrypt them.
+var archiveMagic = []byte("CMCTLBAK")
+
+const (
+	saltSize = 16
+	keySize  = 32
+
+	// scrypt cost parameters, as recommended for interactive use.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Archive is the content of a backup.
+type Archive struct {
+	// Version of the archive format.
+	Version int `json:"version"`
+	// CreatedAt is the time the backup was taken.
+	CreatedAt metav1.Time `json:"createdAt"`
+
+	// Issuers and ClusterIssuers are stored with their ACME status, so that
+	// restored issuers keep using their existing ACME account.
+	Issuers        []cmapi.Issuer        `json:"issuers,omitempty"`
+	ClusterIssuers []cmapi.ClusterIssuer `json:"clusterIssuers,omitempty"`
+	Certificates   []cmapi.Certificate   `json:"certificates,omitempty"`
+
+	// Secrets holds the ACME account private keys and the Secrets of the
+	// backed up Certificates.
+	Secrets []corev1.Secret `json:"secrets,omitempty"`
+
+	// CredentialRefs lists the Secrets that issuers read credentials from.
+	// These are not backed up, and must be provisioned in the new cluster
+	// by whatever manages them.
+	CredentialRefs []CredentialRef `json:"credentialRefs,omitempty"`
+}
+
+// CredentialRef is a reference from an issuer to a Secret holding credentials.
+type CredentialRef struct {
+	IssuerKind string `json:"issuerKind"`
+	IssuerName string `json:"issuerName"`
+	Namespace  string `json:"namespace"`
+	SecretName string `json:"secretName"`
+}
+
+// encryptArchive serializes and compresses the archive, then encrypts it with
+// AES-256-GCM using a key derived from passphrase with scrypt.
+func encryptArchive(a *Archive, passphrase []byte) ([]byte, error) {
+	var plaintext bytes.Buffer
+	zw := gzip.NewWriter(&plaintext)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return nil, fmt.Errorf("failed to encode archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(archiveMagic)+saltSize+len(nonce)+plaintext.Len()+aead.Overhead())
+	out = append(out, archiveMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext.Bytes(), archiveMagic), nil
+}
+
+// decryptArchive reverses encryptArchive.
+func decryptArchive(data, passphrase []byte) (*Archive, error) {
+	if !bytes.HasPrefix(data, archiveMagic) {
+		return nil, errors.New("file is not a backup written by this tool")
+	}
+	data = data[len(archiveMagic):]
+	if len(data) < saltSize {
+		return nil, errors.New("backup file is truncated")
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("backup file is truncated")
+	}
+	nonce, data := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, data, archiveMagic)
+	if err != nil {
+		return nil, errors.New("failed to decrypt backup, the passphrase is incorrect or the file is corrupt")
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archive: %w", err)
+	}
+	a := new(Archive)
+	if err := json.NewDecoder(zr).Decode(a); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+	if a.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d, expected %d", a.Version, archiveVersion)
+	}
+	return a, nil
+}
+
+func newAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readPassphrase reads the passphrase from path, ignoring a trailing newline.
+func readPassphrase(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	passphrase := strings.TrimRight(string(data), "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase file %q is empty", path)
+	}
+	return []byte(passphrase), nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestArchiveEncryption(t *testing.T) {
+	a := &Archive{
+		Version:      archiveVersion,
+		Certificates: []cmapi.Certificate{{ObjectMeta: metav1.ObjectMeta{Name: "test-crt", Namespace: "ns1"}}},
+	}
+	data, err := encryptArchive(a, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		data       []byte
+		passphrase string
+		expErr     bool
+	}{
+		"correct passphrase decrypts the archive": {
+			data:       data,
+			passphrase: "passphrase",
+		},
+		"wrong passphrase is rejected": {
+			data:       data,
+			passphrase: "wrong",
+			expErr:     true,
+		},
+		"tampered archive is rejected": {
+			data:       append(append([]byte{}, data[:len(data)-1]...), data[len(data)-1]^0xff),
+			passphrase: "passphrase",
+			expErr:     true,
+		},
+		"truncated archive is rejected": {
+			data:       data[:len(archiveMagic)+4],
+			passphrase: "passphrase",
+			expErr:     true,
+		},
+		"file that is not an archive is rejected": {
+			data:       []byte("apiVersion: v1\nkind: Secret\n"),
+			passphrase: "passphrase",
+			expErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := decryptArchive(test.data, []byte(test.passphrase))
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Certificates) != 1 || got.Certificates[0].Name != "test-crt" {
+				t.Errorf("unexpected archive contents: %+v", got)
+			}
+		})
+	}
+}
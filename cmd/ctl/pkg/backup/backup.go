@@ -0,0 +1,357 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+var (
+	backupLong = templates.LongDesc(i18n.T(`
+Back up cert-manager state into an encrypted archive.
+
+The archive contains Issuers and ClusterIssuers along with their ACME account
+status, the ACME account private keys, Certificates and the Secrets that they
+have been issued into. Restoring the archive into a new cluster with
+'restore' lets ACME issuers continue to use their existing accounts, and
+Certificates are not issued again.
+
+Secrets that issuers read credentials from, such as DNS provider API tokens,
+are not backed up. The archive records which Secrets are referenced, and
+restore reports any that are missing from the new cluster.
+
+The archive is encrypted with a key derived from the contents of
+--passphrase-file, which is required to restore it.`))
+
+	backupExample = templates.Examples(i18n.T(build.WithTemplate(`
+# Back up cert-manager resources in all namespaces.
+{{.BuildName}} backup cert-manager.bak --passphrase-file ./passphrase -A
+
+# Back up cert-manager resources in the 'sandbox' namespace, and all ClusterIssuers.
+{{.BuildName}} backup sandbox.bak --passphrase-file ./passphrase -n sandbox`)))
+)
+
+// BackupOptions is a struct to support backup command
+type BackupOptions struct {
+	PassphraseFile           string
+	AllNamespaces            bool
+	ClusterResourceNamespace string
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// NewBackupOptions returns initialized BackupOptions
+func NewBackupOptions(ioStreams genericclioptions.IOStreams) *BackupOptions {
+	return &BackupOptions{
+		ClusterResourceNamespace: "cert-manager",
+		IOStreams:                ioStreams,
+	}
+}
+
+// NewCmdBackup returns a cobra command for backing up cert-manager state
+func NewCmdBackup(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewBackupOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "backup FILE",
+		Short:   "Back up cert-manager resources and ACME accounts into an encrypted archive",
+		Long:    backupLong,
+		Example: backupExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx, args))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.PassphraseFile, "passphrase-file", o.PassphraseFile, "Path to a file containing the passphrase used to encrypt the archive.")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, back up resources across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringVar(&o.ClusterResourceNamespace, "cluster-resource-namespace", o.ClusterResourceNamespace, "The namespace that cert-manager reads ClusterIssuer Secrets from.")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *BackupOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("the path of the archive to write has to be provided as the only argument")
+	}
+	if len(o.PassphraseFile) == 0 {
+		return errors.New("--passphrase-file must be specified")
+	}
+	if len(o.ClusterResourceNamespace) == 0 {
+		return errors.New("--cluster-resource-namespace must not be empty")
+	}
+	return nil
+}
+
+// Run executes backup command
+func (o *BackupOptions) Run(ctx context.Context, args []string) error {
+	passphrase, err := readPassphrase(o.PassphraseFile)
+	if err != nil {
+		return err
+	}
+
+	a, err := o.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := encryptArchive(a, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(args[0], data, 0600); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	fmt.Fprintf(o.Out, "Backed up %d Issuers, %d ClusterIssuers, %d Certificates and %d Secrets to %s\n",
+		len(a.Issuers), len(a.ClusterIssuers), len(a.Certificates), len(a.Secrets), args[0])
+	return nil
+}
+
+// secretKey identifies a Secret to be backed up.
+type secretKey struct {
+	namespace, name string
+}
+
+// collect reads the resources to back up from the cluster.
+func (o *BackupOptions) collect(ctx context.Context) (*Archive, error) {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	a := &Archive{
+		Version:   archiveVersion,
+		CreatedAt: metav1.NewTime(time.Now()),
+	}
+	secrets := make(map[secretKey]bool)
+
+	issuers, err := o.CMClient.CertmanagerV1().Issuers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Issuers: %w", err)
+	}
+	for _, iss := range issuers.Items {
+		a.Issuers = append(a.Issuers, cmapi.Issuer{
+			TypeMeta:   typeMeta(cmapi.IssuerKind),
+			ObjectMeta: cleanObjectMeta(iss.ObjectMeta),
+			Spec:       iss.Spec,
+			Status:     cmapi.IssuerStatus{ACME: iss.Status.ACME},
+		})
+		if iss.Spec.ACME != nil {
+			secrets[secretKey{iss.Namespace, iss.Spec.ACME.PrivateKey.Name}] = true
+		}
+		a.CredentialRefs = append(a.CredentialRefs, credentialRefs(cmapi.IssuerKind, iss.Name, iss.Namespace, &iss.Spec)...)
+	}
+
+	clusterIssuers, err := o.CMClient.CertmanagerV1().ClusterIssuers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterIssuers: %w", err)
+	}
+	for _, iss := range clusterIssuers.Items {
+		a.ClusterIssuers = append(a.ClusterIssuers, cmapi.ClusterIssuer{
+			TypeMeta:   typeMeta(cmapi.ClusterIssuerKind),
+			ObjectMeta: cleanObjectMeta(iss.ObjectMeta),
+			Spec:       iss.Spec,
+			Status:     cmapi.IssuerStatus{ACME: iss.Status.ACME},
+		})
+		if iss.Spec.ACME != nil {
+			secrets[secretKey{o.ClusterResourceNamespace, iss.Spec.ACME.PrivateKey.Name}] = true
+		}
+		a.CredentialRefs = append(a.CredentialRefs, credentialRefs(cmapi.ClusterIssuerKind, iss.Name, o.ClusterResourceNamespace, &iss.Spec)...)
+	}
+
+	crts, err := o.CMClient.CertmanagerV1().Certificates(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Certificates: %w", err)
+	}
+	for _, crt := range crts.Items {
+		// The issued Secret is always backed up so that it does not need to
+		// be issued again, but Certificates managed by another resource are
+		// recreated by that resource's controller.
+		secrets[secretKey{crt.Namespace, crt.Spec.SecretName}] = true
+		if owner := metav1.GetControllerOf(&crt); owner != nil {
+			fmt.Fprintf(o.ErrOut, "Skipping Certificate %s/%s as it is managed by %s %s\n", crt.Namespace, crt.Name, owner.Kind, owner.Name)
+			continue
+		}
+		a.Certificates = append(a.Certificates, cmapi.Certificate{
+			TypeMeta:   typeMeta(cmapi.CertificateKind),
+			ObjectMeta: cleanObjectMeta(crt.ObjectMeta),
+			Spec:       crt.Spec,
+		})
+	}
+
+	keys := make([]secretKey, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+	for _, key := range keys {
+		secret, err := o.KubeClient.CoreV1().Secrets(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(o.ErrOut, "Skipping Secret %s/%s as it does not exist\n", key.namespace, key.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s/%s: %w", key.namespace, key.name, err)
+		}
+		a.Secrets = append(a.Secrets, corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: cleanObjectMeta(secret.ObjectMeta),
+			Type:       secret.Type,
+			Data:       secret.Data,
+		})
+	}
+
+	return a, nil
+}
+
+// credentialRefs returns the Secrets that the issuer reads credentials from.
+// The ACME account private key is not included as it is backed up.
+func credentialRefs(kind, name, namespace string, spec *cmapi.IssuerSpec) []CredentialRef {
+	var names []string
+	add := func(secretName string) {
+		if len(secretName) > 0 {
+			names = append(names, secretName)
+		}
+	}
+
+	switch {
+	case spec.ACME != nil:
+		if eab := spec.ACME.ExternalAccountBinding; eab != nil {
+			add(eab.Key.Name)
+		}
+		for _, solver := range spec.ACME.Solvers {
+			dns01 := solver.DNS01
+			if dns01 == nil {
+				continue
+			}
+			switch {
+			case dns01.Akamai != nil:
+				add(dns01.Akamai.ClientToken.Name)
+				add(dns01.Akamai.ClientSecret.Name)
+				add(dns01.Akamai.AccessToken.Name)
+			case dns01.CloudDNS != nil && dns01.CloudDNS.ServiceAccount != nil:
+				add(dns01.CloudDNS.ServiceAccount.Name)
+			case dns01.Cloudflare != nil:
+				if dns01.Cloudflare.APIKey != nil {
+					add(dns01.Cloudflare.APIKey.Name)
+				}
+				if dns01.Cloudflare.APIToken != nil {
+					add(dns01.Cloudflare.APIToken.Name)
+				}
+			case dns01.Route53 != nil:
+				add(dns01.Route53.SecretAccessKey.Name)
+			case dns01.AzureDNS != nil && dns01.AzureDNS.ClientSecret != nil:
+				add(dns01.AzureDNS.ClientSecret.Name)
+			case dns01.DigitalOcean != nil:
+				add(dns01.DigitalOcean.Token.Name)
+			case dns01.AcmeDNS != nil:
+				add(dns01.AcmeDNS.AccountSecret.Name)
+			case dns01.RFC2136 != nil:
+				add(dns01.RFC2136.TSIGSecret.Name)
+			}
+		}
+	case spec.CA != nil:
+		add(spec.CA.SecretName)
+	case spec.Vault != nil:
+		if spec.Vault.Auth.TokenSecretRef != nil {
+			add(spec.Vault.Auth.TokenSecretRef.Name)
+		}
+		if spec.Vault.Auth.AppRole != nil {
+			add(spec.Vault.Auth.AppRole.SecretRef.Name)
+		}
+		if spec.Vault.Auth.Kubernetes != nil {
+			add(spec.Vault.Auth.Kubernetes.SecretRef.Name)
+		}
+	case spec.Venafi != nil:
+		if spec.Venafi.TPP != nil {
+			add(spec.Venafi.TPP.CredentialsRef.Name)
+		}
+		if spec.Venafi.Cloud != nil {
+			add(spec.Venafi.Cloud.APITokenSecretRef.Name)
+		}
+	}
+
+	var refs []CredentialRef
+	seen := make(map[string]bool)
+	for _, secretName := range names {
+		if seen[secretName] {
+			continue
+		}
+		seen[secretName] = true
+		refs = append(refs, CredentialRef{
+			IssuerKind: kind,
+			IssuerName: name,
+			Namespace:  namespace,
+			SecretName: secretName,
+		})
+	}
+	return refs
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: cmapi.SchemeGroupVersion.String(),
+		Kind:       kind,
+	}
+}
+
+// cleanObjectMeta returns a copy of the given ObjectMeta that only contains
+// the fields a user would set when creating the resource.
+func cleanObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	cleaned := metav1.ObjectMeta{
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Labels:    meta.Labels,
+	}
+	for k, v := range meta.Annotations {
+		if k == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
+		if cleaned.Annotations == nil {
+			cleaned.Annotations = make(map[string]string)
+		}
+		cleaned.Annotations[k] = v
+	}
+	return cleaned
+}
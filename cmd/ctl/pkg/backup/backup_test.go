@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *BackupOptions
+		args    []string
+		expErr  bool
+	}{
+		"file and passphrase are valid": {
+			options: &BackupOptions{PassphraseFile: "pass", ClusterResourceNamespace: "cert-manager"},
+			args:    []string{"backup.bak"},
+		},
+		"file is required": {
+			options: &BackupOptions{PassphraseFile: "pass", ClusterResourceNamespace: "cert-manager"},
+			expErr:  true,
+		},
+		"passphrase is required": {
+			options: &BackupOptions{ClusterResourceNamespace: "cert-manager"},
+			args:    []string{"backup.bak"},
+			expErr:  true,
+		},
+		"cluster resource namespace is required": {
+			options: &BackupOptions{PassphraseFile: "pass"},
+			args:    []string{"backup.bak"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("correct horse\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	archiveFile := filepath.Join(dir, "backup.bak")
+
+	acmeStatus := &cmacme.ACMEIssuerStatus{
+		URI:                 "https://acme.example.com/acct/1234",
+		LastRegisteredEmail: "ops@example.com",
+	}
+	issuer := &cmapi.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme", Namespace: "ns1", ResourceVersion: "10", UID: "abc"},
+		Spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{ACME: &cmacme.ACMEIssuer{
+			Server:     "https://acme.example.com/directory",
+			Email:      "ops@example.com",
+			PrivateKey: cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "acme-account"}},
+			Solvers: []cmacme.ACMEChallengeSolver{{DNS01: &cmacme.ACMEChallengeSolverDNS01{
+				Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{
+					APIToken: &cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "cloudflare-token"}},
+				},
+			}}},
+		}}},
+		Status: cmapi.IssuerStatus{ACME: acmeStatus},
+	}
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ns1"},
+		Spec:       cmapi.CertificateSpec{SecretName: "example-tls", IssuerRef: cmmeta.ObjectReference{Name: "acme"}},
+	}
+	ownedCrt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress-tls", Namespace: "ns1", OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "web", Controller: pointer.BoolPtr(true),
+		}}},
+		Spec: cmapi.CertificateSpec{SecretName: "ingress-tls", IssuerRef: cmmeta.ObjectReference{Name: "acme"}},
+	}
+	otherNamespaceCrt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns2"},
+		Spec:       cmapi.CertificateSpec{SecretName: "other-tls"},
+	}
+	secrets := []runtime.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "acme-account", Namespace: "ns1"}, Data: map[string][]byte{"tls.key": []byte("account-key")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "example-tls", Namespace: "ns1", ResourceVersion: "4"}, Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ingress-tls", Namespace: "ns1"}, Type: corev1.SecretTypeTLS},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cloudflare-token", Namespace: "ns1"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other-tls", Namespace: "ns2"}},
+	}
+
+	backupErrOut := new(bytes.Buffer)
+	backup := &BackupOptions{
+		PassphraseFile:           passphraseFile,
+		ClusterResourceNamespace: "cert-manager",
+		IOStreams:                genericclioptions.IOStreams{Out: new(bytes.Buffer), ErrOut: backupErrOut},
+		Factory: &factory.Factory{
+			Namespace:  "ns1",
+			CMClient:   cmfake.NewSimpleClientset(issuer, crt, ownedCrt, otherNamespaceCrt),
+			KubeClient: kubefake.NewSimpleClientset(secrets...),
+		},
+	}
+	if err := backup.Run(ctx, []string{archiveFile}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(backupErrOut.String(), "Skipping Certificate ns1/ingress-tls as it is managed by Ingress web") {
+		t.Errorf("expected owned Certificate to be skipped, got: %s", backupErrOut)
+	}
+
+	// The new cluster already has the Certificate, and is missing the
+	// credentials Secret.
+	existingCrt := crt.DeepCopy()
+	cmClient := cmfake.NewSimpleClientset(existingCrt)
+	kubeClient := kubefake.NewSimpleClientset()
+	restoreOut, restoreErrOut := new(bytes.Buffer), new(bytes.Buffer)
+	restore := &RestoreOptions{
+		PassphraseFile: passphraseFile,
+		IOStreams:      genericclioptions.IOStreams{Out: restoreOut, ErrOut: restoreErrOut},
+		Factory:        &factory.Factory{CMClient: cmClient, KubeClient: kubeClient},
+	}
+	if err := restore.Run(ctx, []string{archiveFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	gotIssuer, err := cmClient.CertmanagerV1().Issuers("ns1").Get(ctx, "acme", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotIssuer.Status.ACME, acmeStatus) {
+		t.Errorf("expected ACME status to be restored, got: %+v", gotIssuer.Status.ACME)
+	}
+	if !reflect.DeepEqual(gotIssuer.Spec, issuer.Spec) {
+		t.Errorf("unexpected Issuer spec: %+v", gotIssuer.Spec)
+	}
+	if gotIssuer.UID == issuer.UID {
+		t.Errorf("expected UID not to be restored")
+	}
+
+	gotSecrets, err := kubeClient.CoreV1().Secrets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotSecretNames []string
+	for _, s := range gotSecrets.Items {
+		gotSecretNames = append(gotSecretNames, s.Namespace+"/"+s.Name)
+	}
+	expSecretNames := []string{"ns1/acme-account", "ns1/example-tls", "ns1/ingress-tls"}
+	if !reflect.DeepEqual(gotSecretNames, expSecretNames) {
+		t.Errorf("expected Secrets %v to be restored, got %v", expSecretNames, gotSecretNames)
+	}
+
+	if _, err := cmClient.CertmanagerV1().Certificates("ns1").Get(ctx, "ingress-tls", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected owned Certificate not to be restored")
+	}
+	if _, err := cmClient.CertmanagerV1().Certificates("ns2").Get(ctx, "other", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected Certificate from another namespace not to be restored")
+	}
+
+	for _, exp := range []string{
+		"Skipping Certificate ns1/example as it already exists",
+		"Warning: Secret ns1/cloudflare-token referenced by Issuer acme does not exist",
+	} {
+		if !strings.Contains(restoreErrOut.String(), exp) {
+			t.Errorf("expected output to contain %q, got: %s", exp, restoreErrOut)
+		}
+	}
+	if exp := "Restored 4 resources, 1 already existed\n"; restoreOut.String() != exp {
+		t.Errorf("expected %q, got %q", exp, restoreOut)
+	}
+}
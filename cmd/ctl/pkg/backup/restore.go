@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+)
+
+var (
+	restoreLong = templates.LongDesc(i18n.T(`
+Restore cert-manager state from an archive written by 'backup'.
+
+Secrets are restored first, followed by ClusterIssuers, Issuers and
+Certificates. The ACME account status of each issuer is restored along with
+its account private key, so that cert-manager continues to use the existing
+ACME account rather than registering a new one.
+
+Resources that already exist are left untouched. Namespaces are not created,
+and must exist before restoring. Secrets holding issuer credentials are not
+part of the archive; any that are missing are reported once the restore has
+finished.`))
+
+	restoreExample = templates.Examples(i18n.T(build.WithTemplate(`
+# Restore cert-manager resources from an archive.
+{{.BuildName}} restore cert-manager.bak --passphrase-file ./passphrase`)))
+)
+
+// RestoreOptions is a struct to support restore command
+type RestoreOptions struct {
+	PassphraseFile string
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// NewRestoreOptions returns initialized RestoreOptions
+func NewRestoreOptions(ioStreams genericclioptions.IOStreams) *RestoreOptions {
+	return &RestoreOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdRestore returns a cobra command for restoring cert-manager state
+func NewCmdRestore(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewRestoreOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "restore FILE",
+		Short:   "Restore cert-manager resources and ACME accounts from an archive written by backup",
+		Long:    restoreLong,
+		Example: restoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx, args))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.PassphraseFile, "passphrase-file", o.PassphraseFile, "Path to a file containing the passphrase the archive was encrypted with.")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *RestoreOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("the path of the archive to restore has to be provided as the only argument")
+	}
+	if len(o.PassphraseFile) == 0 {
+		return errors.New("--passphrase-file must be specified")
+	}
+	return nil
+}
+
+// Run executes restore command
+func (o *RestoreOptions) Run(ctx context.Context, args []string) error {
+	passphrase, err := readPassphrase(o.PassphraseFile)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	a, err := decryptArchive(data, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return o.restore(ctx, a)
+}
+
+// restore creates the resources in the archive. Failures are collected so
+// that one bad resource does not prevent the rest from being restored.
+func (o *RestoreOptions) restore(ctx context.Context, a *Archive) error {
+	var (
+		errs              []error
+		created, existing int
+	)
+	// record reports the outcome of creating a single resource.
+	record := func(kind, name string, err error) bool {
+		switch {
+		case err == nil:
+			created++
+			return true
+		case apierrors.IsAlreadyExists(err):
+			existing++
+			fmt.Fprintf(o.ErrOut, "Skipping %s %s as it already exists\n", kind, name)
+		default:
+			errs = append(errs, fmt.Errorf("failed to restore %s %s: %w", kind, name, err))
+		}
+		return false
+	}
+
+	for i := range a.Secrets {
+		secret := &a.Secrets[i]
+		_, err := o.KubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		record("Secret", secret.Namespace+"/"+secret.Name, err)
+	}
+
+	// The status of issuers can only be set once they have been created. An
+	// ACME status is restored so that the account URL is preserved.
+	for i := range a.ClusterIssuers {
+		iss := a.ClusterIssuers[i].DeepCopy()
+		newIss, err := o.CMClient.CertmanagerV1().ClusterIssuers().Create(ctx, iss, metav1.CreateOptions{})
+		if !record("ClusterIssuer", iss.Name, err) || iss.Status.ACME == nil {
+			continue
+		}
+		newIss.Status.ACME = iss.Status.ACME
+		if _, err := o.CMClient.CertmanagerV1().ClusterIssuers().UpdateStatus(ctx, newIss, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore ACME account status of ClusterIssuer %s: %w", iss.Name, err))
+		}
+	}
+	for i := range a.Issuers {
+		iss := a.Issuers[i].DeepCopy()
+		newIss, err := o.CMClient.CertmanagerV1().Issuers(iss.Namespace).Create(ctx, iss, metav1.CreateOptions{})
+		if !record("Issuer", iss.Namespace+"/"+iss.Name, err) || iss.Status.ACME == nil {
+			continue
+		}
+		newIss.Status.ACME = iss.Status.ACME
+		if _, err := o.CMClient.CertmanagerV1().Issuers(iss.Namespace).UpdateStatus(ctx, newIss, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore ACME account status of Issuer %s/%s: %w", iss.Namespace, iss.Name, err))
+		}
+	}
+
+	for i := range a.Certificates {
+		crt := &a.Certificates[i]
+		_, err := o.CMClient.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
+		record("Certificate", crt.Namespace+"/"+crt.Name, err)
+	}
+
+	fmt.Fprintf(o.Out, "Restored %d resources, %d already existed\n", created, existing)
+
+	for _, ref := range a.CredentialRefs {
+		_, err := o.KubeClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.SecretName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(o.ErrOut, "Warning: Secret %s/%s referenced by %s %s does not exist\n", ref.Namespace, ref.SecretName, ref.IssuerKind, ref.IssuerName)
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check Secret %s/%s: %w", ref.Namespace, ref.SecretName, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -24,14 +24,18 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/approve"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/backup"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/check"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/convert"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/deny"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/experimental"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/export"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/get"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/inspect"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/renew"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/status"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/version"
 )
@@ -56,6 +60,11 @@ func Commands() []RegisterCommandFunc {
 		approve.NewCmdApprove,
 		deny.NewCmdDeny,
 		check.NewCmdCheck,
+		export.NewCmdExport,
+		backup.NewCmdBackup,
+		backup.NewCmdRestore,
+		report.NewCmdReport,
+		get.NewCmdGet,
 
 		// Experimental features
 		experimental.NewCmdExperimental,
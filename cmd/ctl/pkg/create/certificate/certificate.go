@@ -0,0 +1,396 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/internal/apis/certmanager/validation"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/ctl"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Create a new Certificate resource from flags, or by answering prompts with --interactive.
+
+By default the Certificate manifest is printed to stdout as YAML, so that it
+can be reviewed or committed. With --apply the Certificate is created in the
+cluster, and with --wait the command blocks until the Certificate is Ready.`))
+
+	example = templates.Examples(i18n.T(build.WithTemplate(`
+# Print a Certificate manifest for 'example.com' issued by the ClusterIssuer 'letsencrypt'.
+{{.BuildName}} create certificate my-crt --dns-names example.com,www.example.com --issuer letsencrypt --issuer-kind ClusterIssuer
+
+# Create a Certificate with an ECDSA key and a PKCS#12 keystore, and wait for it to be Ready.
+{{.BuildName}} create certificate my-crt --dns-names example.com --issuer my-issuer --key-algorithm ECDSA \
+  --keystores pkcs12 --keystore-password-secret my-keystore-password --apply --wait
+
+# Answer prompts for any values not given as flags.
+{{.BuildName}} create certificate my-crt --interactive
+`)))
+)
+
+const (
+	keystorePKCS12 = "pkcs12"
+	keystoreJKS    = "jks"
+)
+
+// Options is a struct to support create certificate command
+type Options struct {
+	CommonName     string
+	DNSNames       []string
+	IPAddresses    []string
+	URIs           []string
+	EmailAddresses []string
+	// Name of the Secret the certificate is stored in.
+	// If not specified, defaults to <NameOfCertificate>-tls
+	SecretName string
+
+	IssuerName  string
+	IssuerKind  string
+	IssuerGroup string
+
+	KeyAlgorithm string
+	KeySize      int
+
+	Duration    time.Duration
+	RenewBefore time.Duration
+
+	// Keystores are the additional keystore formats written to the Secret,
+	// any of pkcs12 or jks
+	Keystores              []string
+	KeystorePasswordSecret string
+	KeystorePasswordKey    string
+
+	// If true, prompt for any values that were not given as flags
+	Interactive bool
+	// If true, create the Certificate in the cluster rather than printing it
+	Apply bool
+	// If true, wait for the applied Certificate to become Ready
+	Wait bool
+	// Length of time the command blocks waiting for the Certificate to
+	// become Ready if --wait is set
+	Timeout time.Duration
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IssuerKind:          cmapi.IssuerKind,
+		KeyAlgorithm:        string(cmapi.RSAKeyAlgorithm),
+		KeystorePasswordKey: "password",
+		Timeout:             5 * time.Minute,
+		IOStreams:           ioStreams,
+	}
+}
+
+// NewCmdCreateCertificate returns a cobra command for create Certificate
+func NewCmdCreateCertificate(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:     "certificate",
+		Aliases: []string{"cert"},
+		Short:   "Create a cert-manager Certificate resource from flags or prompts",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(args))
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx, args))
+		},
+	}
+	cmd.Flags().StringVar(&o.CommonName, "common-name", o.CommonName, "The common name of the certificate")
+	cmd.Flags().StringSliceVar(&o.DNSNames, "dns-names", o.DNSNames, "DNS subject alternative names of the certificate")
+	cmd.Flags().StringSliceVar(&o.IPAddresses, "ip-addresses", o.IPAddresses, "IP address subject alternative names of the certificate")
+	cmd.Flags().StringSliceVar(&o.URIs, "uris", o.URIs, "URI subject alternative names of the certificate")
+	cmd.Flags().StringSliceVar(&o.EmailAddresses, "email-addresses", o.EmailAddresses, "Email address subject alternative names of the certificate")
+	cmd.Flags().StringVar(&o.SecretName, "secret-name", o.SecretName, "Name of the Secret the certificate is stored in, defaults to <name>-tls")
+	cmd.Flags().StringVar(&o.IssuerName, "issuer", o.IssuerName, "Name of the issuer that signs the certificate")
+	cmd.Flags().StringVar(&o.IssuerKind, "issuer-kind", o.IssuerKind, "Kind of the issuer that signs the certificate, e.g. Issuer or ClusterIssuer")
+	cmd.Flags().StringVar(&o.IssuerGroup, "issuer-group", o.IssuerGroup, "API group of the issuer, for issuers that are not part of cert-manager.io")
+	cmd.Flags().StringVar(&o.KeyAlgorithm, "key-algorithm", o.KeyAlgorithm, "Algorithm of the private key, one of: RSA, ECDSA, Ed25519")
+	cmd.Flags().IntVar(&o.KeySize, "key-size", o.KeySize, "Size of the private key, defaults to 2048 for RSA and 256 for ECDSA")
+	cmd.Flags().DurationVar(&o.Duration, "duration", o.Duration, "Requested lifetime of the certificate, e.g. 2160h")
+	cmd.Flags().DurationVar(&o.RenewBefore, "renew-before", o.RenewBefore, "How long before expiry the certificate is renewed, e.g. 360h")
+	cmd.Flags().StringSliceVar(&o.Keystores, "keystores", o.Keystores, "Additional keystores written to the Secret, any of: pkcs12, jks")
+	cmd.Flags().StringVar(&o.KeystorePasswordSecret, "keystore-password-secret", o.KeystorePasswordSecret, "Name of the Secret holding the password of the keystores")
+	cmd.Flags().StringVar(&o.KeystorePasswordKey, "keystore-password-key", o.KeystorePasswordKey, "Key in the keystore password Secret holding the password")
+	cmd.Flags().BoolVarP(&o.Interactive, "interactive", "i", o.Interactive, "Prompt for any values not given as flags")
+	cmd.Flags().BoolVar(&o.Apply, "apply", o.Apply, "Create the Certificate in the cluster instead of printing it")
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "Wait for the Certificate to become Ready, requires --apply")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "Time before timeout when waiting for the Certificate to become Ready, must include unit, e.g. 10m or 1h")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// Complete prompts for any values that were not given as flags if
+// --interactive is set
+func (o *Options) Complete(args []string) error {
+	if !o.Interactive {
+		return nil
+	}
+
+	p := &prompter{in: bufio.NewReader(o.In), out: o.ErrOut}
+	var err error
+	if len(o.CommonName) == 0 && len(o.DNSNames) == 0 && len(o.IPAddresses) == 0 && len(o.URIs) == 0 && len(o.EmailAddresses) == 0 {
+		if o.DNSNames, err = p.list("DNS names (comma separated)"); err != nil {
+			return err
+		}
+	}
+	if len(o.IssuerName) == 0 {
+		if o.IssuerName, err = p.value("Issuer name", ""); err != nil {
+			return err
+		}
+		if o.IssuerKind, err = p.value("Issuer kind", o.IssuerKind); err != nil {
+			return err
+		}
+	}
+	if len(o.SecretName) == 0 && len(args) == 1 {
+		if o.SecretName, err = p.value("Secret name", defaultSecretName(args[0])); err != nil {
+			return err
+		}
+	}
+	if o.KeyAlgorithm, err = p.value("Key algorithm (RSA, ECDSA, Ed25519)", o.KeyAlgorithm); err != nil {
+		return err
+	}
+	if len(o.Keystores) == 0 {
+		if o.Keystores, err = p.list("Additional keystores (pkcs12, jks, blank for none)"); err != nil {
+			return err
+		}
+	}
+	if len(o.Keystores) > 0 && len(o.KeystorePasswordSecret) == 0 {
+		if o.KeystorePasswordSecret, err = p.value("Name of the Secret holding the keystore password", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the Certificate to be created has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the Certificate")
+	}
+
+	if len(o.IssuerName) == 0 {
+		return errors.New("the issuer of the Certificate must be specified with --issuer")
+	}
+	for _, ks := range o.Keystores {
+		if ks != keystorePKCS12 && ks != keystoreJKS {
+			return fmt.Errorf("unsupported keystore %q, must be one of: pkcs12, jks", ks)
+		}
+	}
+	if len(o.Keystores) > 0 && len(o.KeystorePasswordSecret) == 0 {
+		return errors.New("--keystore-password-secret must be specified when writing keystores")
+	}
+	if o.Wait && !o.Apply {
+		return errors.New("--wait can only be used with --apply")
+	}
+
+	return nil
+}
+
+// Run executes create certificate command
+func (o *Options) Run(ctx context.Context, args []string) error {
+	crt := o.buildCertificate(args[0])
+	if err := validateCertificate(crt); err != nil {
+		return err
+	}
+
+	if !o.Apply {
+		return (&printers.YAMLPrinter{}).PrintObj(crt, o.Out)
+	}
+
+	crt, err := o.CMClient.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating Certificate: %w", err)
+	}
+	fmt.Fprintf(o.ErrOut, "Certificate %s has been created in namespace %s\n", crt.Name, crt.Namespace)
+
+	if !o.Wait {
+		return nil
+	}
+
+	fmt.Fprintf(o.ErrOut, "Waiting for Certificate %s in namespace %s to become Ready...\n", crt.Name, crt.Namespace)
+	err = wait.PollImmediate(time.Second, o.Timeout, func() (bool, error) {
+		crt, err = o.CMClient.CertmanagerV1().Certificates(crt.Namespace).Get(ctx, crt.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		return apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+			Type:   cmapi.CertificateConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}), nil
+	})
+	if err != nil {
+		return fmt.Errorf("error when waiting for Certificate to become Ready, run '%s status certificate %s -n %s' for details: %w",
+			build.Name(), crt.Name, crt.Namespace, err)
+	}
+	fmt.Fprintf(o.ErrOut, "Certificate %s in namespace %s is Ready\n", crt.Name, crt.Namespace)
+
+	return nil
+}
+
+// buildCertificate returns the Certificate described by the options.
+func (o *Options) buildCertificate(name string) *cmapi.Certificate {
+	secretName := o.SecretName
+	if len(secretName) == 0 {
+		secretName = defaultSecretName(name)
+	}
+
+	crt := &cmapi.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: cmapi.SchemeGroupVersion.String(),
+			Kind:       cmapi.CertificateKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: o.Namespace,
+		},
+		Spec: cmapi.CertificateSpec{
+			CommonName:     o.CommonName,
+			DNSNames:       o.DNSNames,
+			IPAddresses:    o.IPAddresses,
+			URIs:           o.URIs,
+			EmailAddresses: o.EmailAddresses,
+			SecretName:     secretName,
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  o.IssuerName,
+				Kind:  o.IssuerKind,
+				Group: o.IssuerGroup,
+			},
+			PrivateKey: &cmapi.CertificatePrivateKey{
+				Algorithm: cmapi.PrivateKeyAlgorithm(o.KeyAlgorithm),
+				Size:      o.KeySize,
+			},
+		},
+	}
+	if o.Duration > 0 {
+		crt.Spec.Duration = &metav1.Duration{Duration: o.Duration}
+	}
+	if o.RenewBefore > 0 {
+		crt.Spec.RenewBefore = &metav1.Duration{Duration: o.RenewBefore}
+	}
+
+	passwordRef := cmmeta.SecretKeySelector{
+		LocalObjectReference: cmmeta.LocalObjectReference{Name: o.KeystorePasswordSecret},
+		Key:                  o.KeystorePasswordKey,
+	}
+	for _, ks := range o.Keystores {
+		if crt.Spec.Keystores == nil {
+			crt.Spec.Keystores = &cmapi.CertificateKeystores{}
+		}
+		switch ks {
+		case keystorePKCS12:
+			crt.Spec.Keystores.PKCS12 = &cmapi.PKCS12Keystore{Create: true, PasswordSecretRef: passwordRef}
+		case keystoreJKS:
+			crt.Spec.Keystores.JKS = &cmapi.JKSKeystore{Create: true, PasswordSecretRef: passwordRef}
+		}
+	}
+
+	return crt
+}
+
+// validateCertificate runs the same validation as the cert-manager webhook,
+// so that mistakes are reported before the manifest is applied.
+func validateCertificate(crt *cmapi.Certificate) error {
+	internalCrt := new(internalcmapi.Certificate)
+	if err := ctl.Scheme.Convert(crt, internalCrt, nil); err != nil {
+		return fmt.Errorf("failed to convert Certificate for validation: %w", err)
+	}
+	if errs := validation.ValidateCertificateSpec(&internalCrt.Spec, nil); len(errs) > 0 {
+		return fmt.Errorf("invalid Certificate: %w", errs.ToAggregate())
+	}
+	return nil
+}
+
+func defaultSecretName(name string) string {
+	return name + "-tls"
+}
+
+// prompter asks for values on out and reads the answers from in, one line
+// per answer.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// value prompts for a single value, returning def if the answer is empty.
+func (p *prompter) value(question, def string) (string, error) {
+	if len(def) > 0 {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		if err == io.EOF {
+			return "", errors.New("unexpected end of input while prompting")
+		}
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); len(answer) > 0 {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// list prompts for a comma separated list of values.
+func (p *prompter) list(question string) ([]string, error) {
+	answer, err := p.value(question, "")
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, v := range strings.Split(answer, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"name and issuer are valid": {
+			options: &Options{IssuerName: "my-issuer"},
+			args:    []string{"my-crt"},
+		},
+		"name is required": {
+			options: &Options{IssuerName: "my-issuer"},
+			expErr:  true,
+		},
+		"only one name may be given": {
+			options: &Options{IssuerName: "my-issuer"},
+			args:    []string{"my-crt", "other"},
+			expErr:  true,
+		},
+		"issuer is required": {
+			options: &Options{},
+			args:    []string{"my-crt"},
+			expErr:  true,
+		},
+		"unknown keystore": {
+			options: &Options{IssuerName: "my-issuer", Keystores: []string{"pem"}, KeystorePasswordSecret: "pw"},
+			args:    []string{"my-crt"},
+			expErr:  true,
+		},
+		"keystores require a password secret": {
+			options: &Options{IssuerName: "my-issuer", Keystores: []string{keystorePKCS12}},
+			args:    []string{"my-crt"},
+			expErr:  true,
+		},
+		"wait requires apply": {
+			options: &Options{IssuerName: "my-issuer", Wait: true},
+			args:    []string{"my-crt"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildCertificate(t *testing.T) {
+	passwordRef := cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "pw"}, Key: "password"}
+
+	tests := map[string]struct {
+		options *Options
+		expSpec cmapi.CertificateSpec
+		expErr  bool
+	}{
+		"defaults are applied": {
+			options: &Options{DNSNames: []string{"example.com"}, IssuerName: "my-issuer", IssuerKind: "Issuer", KeyAlgorithm: "RSA"},
+			expSpec: cmapi.CertificateSpec{
+				DNSNames:   []string{"example.com"},
+				SecretName: "my-crt-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "my-issuer", Kind: "Issuer"},
+				PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.RSAKeyAlgorithm},
+			},
+		},
+		"all options are set": {
+			options: &Options{
+				CommonName:             "example.com",
+				DNSNames:               []string{"example.com"},
+				IPAddresses:            []string{"10.0.0.1"},
+				SecretName:             "my-secret",
+				IssuerName:             "my-issuer",
+				IssuerKind:             "ClusterIssuer",
+				KeyAlgorithm:           "ECDSA",
+				KeySize:                384,
+				Duration:               time.Hour * 24 * 30,
+				RenewBefore:            time.Hour * 24 * 10,
+				Keystores:              []string{keystorePKCS12, keystoreJKS},
+				KeystorePasswordSecret: "pw",
+				KeystorePasswordKey:    "password",
+			},
+			expSpec: cmapi.CertificateSpec{
+				CommonName:  "example.com",
+				DNSNames:    []string{"example.com"},
+				IPAddresses: []string{"10.0.0.1"},
+				SecretName:  "my-secret",
+				IssuerRef:   cmmeta.ObjectReference{Name: "my-issuer", Kind: "ClusterIssuer"},
+				PrivateKey:  &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm, Size: 384},
+				Duration:    &metav1.Duration{Duration: time.Hour * 24 * 30},
+				RenewBefore: &metav1.Duration{Duration: time.Hour * 24 * 10},
+				Keystores: &cmapi.CertificateKeystores{
+					PKCS12: &cmapi.PKCS12Keystore{Create: true, PasswordSecretRef: passwordRef},
+					JKS:    &cmapi.JKSKeystore{Create: true, PasswordSecretRef: passwordRef},
+				},
+			},
+		},
+		"certificate without any subject fails validation": {
+			options: &Options{IssuerName: "my-issuer", IssuerKind: "Issuer", KeyAlgorithm: "RSA"},
+			expErr:  true,
+		},
+		"invalid key size fails validation": {
+			options: &Options{DNSNames: []string{"example.com"}, IssuerName: "my-issuer", IssuerKind: "Issuer", KeyAlgorithm: "ECDSA", KeySize: 100},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.options.Factory = &factory.Factory{Namespace: "ns1"}
+			crt := test.options.buildCertificate("my-crt")
+			err := validateCertificate(crt)
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if err != nil {
+				return
+			}
+			assert.Equal(t, "ns1", crt.Namespace)
+			assert.Equal(t, test.expSpec, crt.Spec)
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		input   string
+		exp     *Options
+		expErr  bool
+	}{
+		"prompts for missing values and uses defaults for empty answers": {
+			options: &Options{Interactive: true, IssuerKind: "Issuer", KeyAlgorithm: "RSA"},
+			input:   "example.com, www.example.com\nmy-issuer\nClusterIssuer\n\nECDSA\npkcs12\npw\n",
+			exp: &Options{
+				Interactive:            true,
+				DNSNames:               []string{"example.com", "www.example.com"},
+				IssuerName:             "my-issuer",
+				IssuerKind:             "ClusterIssuer",
+				SecretName:             "my-crt-tls",
+				KeyAlgorithm:           "ECDSA",
+				Keystores:              []string{keystorePKCS12},
+				KeystorePasswordSecret: "pw",
+			},
+		},
+		"values given as flags are not prompted for": {
+			options: &Options{Interactive: true, DNSNames: []string{"example.com"}, IssuerName: "my-issuer", IssuerKind: "Issuer", SecretName: "my-secret", KeyAlgorithm: "RSA"},
+			input:   "\n\n",
+			exp:     &Options{Interactive: true, DNSNames: []string{"example.com"}, IssuerName: "my-issuer", IssuerKind: "Issuer", SecretName: "my-secret", KeyAlgorithm: "RSA"},
+		},
+		"values are not prompted for if not interactive": {
+			options: &Options{},
+			exp:     &Options{},
+		},
+		"running out of input is an error": {
+			options: &Options{Interactive: true},
+			input:   "example.com\n",
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.options.IOStreams = genericclioptions.IOStreams{In: strings.NewReader(test.input), ErrOut: new(bytes.Buffer)}
+			err := test.options.Complete([]string{"my-crt"})
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if err != nil {
+				return
+			}
+			test.options.IOStreams = genericclioptions.IOStreams{}
+			assert.Equal(t, test.exp, test.options)
+		})
+	}
+}
+
+func TestRunApply(t *testing.T) {
+	cmClient := cmfake.NewSimpleClientset()
+	o := &Options{
+		DNSNames:     []string{"example.com"},
+		IssuerName:   "my-issuer",
+		IssuerKind:   "Issuer",
+		KeyAlgorithm: "RSA",
+		Apply:        true,
+		IOStreams:    genericclioptions.IOStreams{Out: new(bytes.Buffer), ErrOut: new(bytes.Buffer)},
+		Factory:      &factory.Factory{Namespace: "ns1", CMClient: cmClient},
+	}
+	if err := o.Run(context.Background(), []string{"my-crt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	crt, err := cmClient.CertmanagerV1().Certificates("ns1").Get(context.Background(), "my-crt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "my-crt-tls", crt.Spec.SecretName)
+	assert.Empty(t, o.Out.(*bytes.Buffer).String(), "expected manifest not to be printed when applying")
+}
@@ -22,12 +22,16 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create/certificate"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create/certificaterequest"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create/kubeconfig"
 )
 
 func NewCmdCreate(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
 	cmds := NewCmdCreateBare()
+	cmds.AddCommand(certificate.NewCmdCreateCertificate(ctx, ioStreams))
 	cmds.AddCommand(certificaterequest.NewCmdCreateCR(ctx, ioStreams))
+	cmds.AddCommand(kubeconfig.NewCmdCreateKubeconfig(ctx, ioStreams))
 
 	return cmds
 }
@@ -37,6 +41,6 @@ func NewCmdCreateBare() *cobra.Command {
 	return &cobra.Command{
 		Use:   "create",
 		Short: "Create cert-manager resources",
-		Long:  `Create cert-manager resources e.g. a CertificateRequest`,
+		Long:  `Create cert-manager resources e.g. a Certificate, a CertificateRequest, or a kubeconfig authenticating with a client certificate`,
 	}
 }
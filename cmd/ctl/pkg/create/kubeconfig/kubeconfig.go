@@ -0,0 +1,308 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/kubeconfig"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Create a kubeconfig that authenticates with a client certificate issued by a cert-manager Issuer.
+
+A private key is generated locally and a CertificateRequest for a client certificate with the given username as common
+name and the given groups as organizations is created. Once the CertificateRequest has been signed, a kubeconfig
+embedding the private key and certificate is written. The API server must trust the CA of the issuer for client
+authentication.`))
+
+	example = templates.Examples(i18n.T(build.WithTemplate(`
+# Create a kubeconfig for user 'alice' in group 'developers', signed by the ClusterIssuer 'client-ca', and write it to 'alice.kubeconfig'.
+{{.BuildName}} create kubeconfig alice --username alice --group developers --issuer client-ca --issuer-kind ClusterIssuer --output-file alice.kubeconfig
+
+# Create a kubeconfig valid for 8 hours for a different API server, and print it to stdout.
+{{.BuildName}} create kubeconfig alice --username alice --issuer client-ca --duration 8h --cluster-server https://prod.example.com:6443 --cluster-certificate-authority prod-ca.crt
+`)))
+)
+
+// Options is a struct to support create kubeconfig command
+type Options struct {
+	// Username is the user that the client certificate authenticates as
+	Username string
+	// Groups are the groups that the user is a member of
+	Groups []string
+	// IssuerName, IssuerKind and IssuerGroup reference the issuer that signs
+	// the client certificate
+	IssuerName  string
+	IssuerKind  string
+	IssuerGroup string
+	// Duration is the requested validity of the client certificate
+	Duration time.Duration
+	// KeyAlgorithm and KeySize configure the generated private key
+	KeyAlgorithm string
+	KeySize      int
+
+	// Server is the URL of the API server. Defaults to the server of the
+	// current kubeconfig context.
+	Server string
+	// CertificateAuthorityFile is the path to a PEM bundle used to verify
+	// the API server. Defaults to the CA of the current kubeconfig context.
+	CertificateAuthorityFile string
+	// ClusterName is the name of the cluster in the kubeconfig. Defaults to
+	// the host name of the server.
+	ClusterName string
+
+	// OutputFile is the file the kubeconfig is written to. The kubeconfig
+	// is written to stdout if not set.
+	OutputFile string
+	// Timeout is the time to wait for the CertificateRequest to be signed
+	Timeout time.Duration
+
+	// pollInterval is the interval at which the CertificateRequest is
+	// checked for being signed
+	pollInterval time.Duration
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    ioStreams,
+		pollInterval: time.Second,
+	}
+}
+
+// NewCmdCreateKubeconfig returns a cobra command for create kubeconfig
+func NewCmdCreateKubeconfig(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:     "kubeconfig",
+		Short:   "Create a kubeconfig authenticating with a client certificate signed by a cert-manager issuer",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx, args))
+		},
+	}
+	o.addFlags(cmd.Flags())
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// addFlags registers the command's flags. They must not collide with the
+// Kubernetes access flags, such as --server, that are registered by the
+// Factory.
+func (o *Options) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Username, "username", o.Username,
+		"The username that the client certificate authenticates as, used as its common name")
+	fs.StringSliceVar(&o.Groups, "group", o.Groups,
+		"A group that the user is a member of, used as an organization of the client certificate. May be repeated")
+	fs.StringVar(&o.IssuerName, "issuer", o.IssuerName,
+		"The name of the issuer that signs the client certificate")
+	fs.StringVar(&o.IssuerKind, "issuer-kind", cmapi.IssuerKind,
+		"The kind of the issuer that signs the client certificate")
+	fs.StringVar(&o.IssuerGroup, "issuer-group", certmanager.GroupName,
+		"The API group of the issuer that signs the client certificate")
+	fs.DurationVar(&o.Duration, "duration", o.Duration,
+		"The requested validity of the client certificate, e.g. 8h. The issuer's default is used if not set")
+	fs.StringVar(&o.KeyAlgorithm, "key-algorithm", string(cmapi.ECDSAKeyAlgorithm),
+		"The algorithm of the generated private key, one of RSA or ECDSA")
+	fs.IntVar(&o.KeySize, "key-size", o.KeySize,
+		"The size of the generated private key. The default size of the algorithm is used if not set")
+	fs.StringVar(&o.Server, "cluster-server", o.Server,
+		"The URL of the API server. Defaults to the server of the current context")
+	fs.StringVar(&o.CertificateAuthorityFile, "cluster-certificate-authority", o.CertificateAuthorityFile,
+		"Path to a PEM bundle used to verify the API server. Defaults to the certificate authority of the current context")
+	fs.StringVar(&o.ClusterName, "cluster-name", o.ClusterName,
+		"The name of the cluster in the kubeconfig. Defaults to the host name of the API server")
+	fs.StringVar(&o.OutputFile, "output-file", o.OutputFile,
+		"Name of the file the kubeconfig is written to. The kubeconfig is written to stdout if not set")
+	fs.DurationVar(&o.Timeout, "timeout", 5*time.Minute,
+		"Time before timeout when waiting for the CertificateRequest to be signed, must include unit, e.g. 10m or 1h")
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the CertificateRequest to be created has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the CertificateRequest")
+	}
+	if o.Username == "" {
+		return errors.New("the username cannot be empty, please specify by using --username flag")
+	}
+	if o.IssuerName == "" {
+		return errors.New("the issuer cannot be empty, please specify by using --issuer flag")
+	}
+	switch cmapi.PrivateKeyAlgorithm(o.KeyAlgorithm) {
+	case cmapi.RSAKeyAlgorithm, cmapi.ECDSAKeyAlgorithm:
+	default:
+		return fmt.Errorf("unsupported key algorithm %q, must be one of RSA or ECDSA", o.KeyAlgorithm)
+	}
+	if o.Duration < 0 {
+		return errors.New("the duration cannot be negative")
+	}
+	return nil
+}
+
+// Run executes create kubeconfig command
+func (o *Options) Run(ctx context.Context, args []string) error {
+	cluster, err := o.cluster()
+	if err != nil {
+		return err
+	}
+
+	req, keyPEM, err := kubeconfig.NewCertificateRequest(args[0], kubeconfig.ClientCertificateOptions{
+		Username:     o.Username,
+		Groups:       o.Groups,
+		Duration:     o.Duration,
+		KeyAlgorithm: cmapi.PrivateKeyAlgorithm(o.KeyAlgorithm),
+		KeySize:      o.KeySize,
+		IssuerRef: cmmeta.ObjectReference{
+			Name:  o.IssuerName,
+			Kind:  o.IssuerKind,
+			Group: o.IssuerGroup,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error when building CertificateRequest: %w", err)
+	}
+
+	req, err = o.CMClient.CertmanagerV1().CertificateRequests(o.Namespace).Create(ctx, req, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating CertificateRequest: %w", err)
+	}
+	fmt.Fprintf(o.ErrOut, "CertificateRequest %s has been created in namespace %s\n", req.Name, req.Namespace)
+
+	fmt.Fprintf(o.ErrOut, "CertificateRequest %v in namespace %v has not been signed yet. Wait until it is signed...\n",
+		req.Name, req.Namespace)
+	var failure error
+	err = wait.PollImmediate(o.pollInterval, o.Timeout, func() (bool, error) {
+		req, err = o.CMClient.CertmanagerV1().CertificateRequests(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		if apiutil.CertificateRequestIsDenied(req) {
+			failure = errors.New("CertificateRequest has been denied")
+			return true, nil
+		}
+		if apiutil.CertificateRequestReadyReason(req) == cmapi.CertificateRequestReasonFailed {
+			cond := apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady)
+			failure = fmt.Errorf("CertificateRequest has failed: %s", cond.Message)
+			return true, nil
+		}
+		return apiutil.CertificateRequestHasCondition(req, cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}) && len(req.Status.Certificate) > 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error when waiting for CertificateRequest to be signed: %w", err)
+	}
+	if failure != nil {
+		return failure
+	}
+	fmt.Fprintf(o.ErrOut, "CertificateRequest %v in namespace %v has been signed\n", req.Name, req.Namespace)
+
+	config, err := kubeconfig.Build(cluster, o.Username, req.Status.Certificate, keyPEM)
+	if err != nil {
+		return fmt.Errorf("error when building kubeconfig: %w", err)
+	}
+	data, err := kubeconfig.Encode(config)
+	if err != nil {
+		return fmt.Errorf("error when encoding kubeconfig: %w", err)
+	}
+
+	if o.OutputFile == "" {
+		_, err = o.Out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(o.OutputFile, data, 0600); err != nil {
+		return fmt.Errorf("error when writing kubeconfig to file: %w", err)
+	}
+	fmt.Fprintf(o.ErrOut, "Kubeconfig written to file %s\n", o.OutputFile)
+
+	return nil
+}
+
+// cluster returns the cluster that the kubeconfig connects to, defaulting to
+// the cluster of the current context.
+func (o *Options) cluster() (kubeconfig.Cluster, error) {
+	cluster := kubeconfig.Cluster{
+		Name:   o.ClusterName,
+		Server: o.Server,
+	}
+
+	if cluster.Server == "" {
+		cluster.Server = o.RESTConfig.Host
+		cluster.TLSServerName = o.RESTConfig.ServerName
+	}
+
+	switch {
+	case o.CertificateAuthorityFile != "":
+		data, err := os.ReadFile(o.CertificateAuthorityFile)
+		if err != nil {
+			return cluster, fmt.Errorf("error when reading certificate authority file: %w", err)
+		}
+		cluster.CertificateAuthorityData = data
+	case len(o.RESTConfig.CAData) > 0:
+		cluster.CertificateAuthorityData = o.RESTConfig.CAData
+	case o.RESTConfig.CAFile != "":
+		data, err := os.ReadFile(o.RESTConfig.CAFile)
+		if err != nil {
+			return cluster, fmt.Errorf("error when reading certificate authority file of the current context: %w", err)
+		}
+		cluster.CertificateAuthorityData = data
+	}
+
+	if cluster.Name == "" {
+		u, err := url.Parse(cluster.Server)
+		if err != nil || u.Hostname() == "" {
+			return cluster, fmt.Errorf("invalid server URL %q", cluster.Server)
+		}
+		cluster.Name = u.Hostname()
+	}
+
+	return cluster, nil
+}
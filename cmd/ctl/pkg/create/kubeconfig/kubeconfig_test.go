@@ -0,0 +1,271 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+// TestFlags ensures that the cluster flags of the command are registered
+// alongside the Kubernetes access flags of the Factory, and that they
+// configure the cluster written to the kubeconfig rather than the cluster the
+// CertificateRequest is created in.
+func TestFlags(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caFile, []byte("prod-ca"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	o := NewOptions(genericclioptions.NewTestIOStreamsDiscard())
+	cmd := &cobra.Command{}
+	o.addFlags(cmd.Flags())
+	factory.New(context.TODO(), cmd)
+
+	if err := cmd.ParseFlags([]string{
+		"--server", "https://current.example.com:6443",
+		"--certificate-authority", "current-ca.crt",
+		"--cluster-server", "https://prod.example.com:6443",
+		"--cluster-certificate-authority", caFile,
+	}); err != nil {
+		t.Fatalf("unexpected error parsing flags: %v", err)
+	}
+	if server := cmd.Flags().Lookup("server").Value.String(); server != "https://current.example.com:6443" {
+		t.Errorf("expected --server to configure the Kubernetes client, got %q", server)
+	}
+
+	o.Factory = &factory.Factory{RESTConfig: &rest.Config{Host: "https://current.example.com:6443"}}
+	cluster, err := o.cluster()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cluster.Name != "prod.example.com" || cluster.Server != "https://prod.example.com:6443" || string(cluster.CertificateAuthorityData) != "prod-ca" {
+		t.Errorf("expected the cluster flags to configure the kubeconfig cluster, got %+v", cluster)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		username     string
+		issuer       string
+		keyAlgorithm string
+
+		expErrMsg string
+	}{
+		"valid options": {
+			args:         []string{"alice"},
+			username:     "alice",
+			issuer:       "client-ca",
+			keyAlgorithm: "ECDSA",
+		},
+		"CR name not passed as arg throws error": {
+			username:     "alice",
+			issuer:       "client-ca",
+			keyAlgorithm: "ECDSA",
+			expErrMsg:    "the name of the CertificateRequest to be created has to be provided as argument",
+		},
+		"username is required": {
+			args:         []string{"alice"},
+			issuer:       "client-ca",
+			keyAlgorithm: "ECDSA",
+			expErrMsg:    "the username cannot be empty, please specify by using --username flag",
+		},
+		"issuer is required": {
+			args:         []string{"alice"},
+			username:     "alice",
+			keyAlgorithm: "ECDSA",
+			expErrMsg:    "the issuer cannot be empty, please specify by using --issuer flag",
+		},
+		"unsupported key algorithms throw error": {
+			args:         []string{"alice"},
+			username:     "alice",
+			issuer:       "client-ca",
+			keyAlgorithm: "DSA",
+			expErrMsg:    `unsupported key algorithm "DSA", must be one of RSA or ECDSA`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Username:     test.username,
+				IssuerName:   test.issuer,
+				KeyAlgorithm: test.keyAlgorithm,
+			}
+			err := opts.Validate(test.args)
+			switch {
+			case err == nil && test.expErrMsg != "":
+				t.Errorf("expected error %q but got none", test.expErrMsg)
+			case err != nil && err.Error() != test.expErrMsg:
+				t.Errorf("unexpected error, exp=%q got=%q", test.expErrMsg, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	signed := func(cr *cmapi.CertificateRequest) *cmapi.CertificateRequest {
+		return gen.CertificateRequestFrom(cr,
+			gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionReady,
+				Status: cmmeta.ConditionTrue,
+				Reason: cmapi.CertificateRequestReasonIssued,
+			}),
+			gen.SetCertificateRequestCertificate([]byte("signed-certificate")),
+		)
+	}
+	denied := func(cr *cmapi.CertificateRequest) *cmapi.CertificateRequest {
+		return gen.CertificateRequestFrom(cr,
+			gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionDenied,
+				Status: cmmeta.ConditionTrue,
+			}),
+		)
+	}
+	failed := func(cr *cmapi.CertificateRequest) *cmapi.CertificateRequest {
+		return gen.CertificateRequestFrom(cr,
+			gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+				Type:    cmapi.CertificateRequestConditionReady,
+				Status:  cmmeta.ConditionFalse,
+				Reason:  cmapi.CertificateRequestReasonFailed,
+				Message: "issuer is not ready",
+			}),
+		)
+	}
+
+	tests := map[string]struct {
+		server  string
+		outcome func(*cmapi.CertificateRequest) *cmapi.CertificateRequest
+
+		expErrMsg   string
+		expCluster  string
+		expServer   string
+		expCAData   string
+		expCertData string
+	}{
+		"writes a kubeconfig for the current cluster once the CertificateRequest is signed": {
+			outcome:     signed,
+			expCluster:  "current.example.com",
+			expServer:   "https://current.example.com:6443",
+			expCAData:   "current-ca",
+			expCertData: "signed-certificate",
+		},
+		"uses the given server": {
+			server:      "https://other.example.com",
+			outcome:     signed,
+			expCluster:  "other.example.com",
+			expServer:   "https://other.example.com",
+			expCAData:   "current-ca",
+			expCertData: "signed-certificate",
+		},
+		"fails if the CertificateRequest is denied": {
+			outcome:   denied,
+			expErrMsg: "CertificateRequest has been denied",
+		},
+		"fails if the CertificateRequest has failed": {
+			outcome:   failed,
+			expErrMsg: "CertificateRequest has failed: issuer is not ready",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := cmfake.NewSimpleClientset()
+			client.PrependReactor("get", "certificaterequests", func(action coretesting.Action) (bool, runtime.Object, error) {
+				obj, err := client.Tracker().Get(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), action.GetNamespace(), action.(coretesting.GetAction).GetName())
+				if err != nil {
+					return true, nil, err
+				}
+				return true, test.outcome(obj.(*cmapi.CertificateRequest)), nil
+			})
+
+			outputFile := filepath.Join(t.TempDir(), "kubeconfig")
+			streams, _, _, _ := genericclioptions.NewTestIOStreams()
+			opts := NewOptions(streams)
+			opts.Username = "alice"
+			opts.Groups = []string{"developers"}
+			opts.IssuerName = "client-ca"
+			opts.KeyAlgorithm = string(cmapi.ECDSAKeyAlgorithm)
+			opts.Server = test.server
+			opts.OutputFile = outputFile
+			opts.Timeout = time.Second
+			opts.pollInterval = time.Millisecond
+			opts.Factory = &factory.Factory{
+				Namespace: "testns",
+				RESTConfig: &rest.Config{
+					Host:            "https://current.example.com:6443",
+					TLSClientConfig: rest.TLSClientConfig{CAData: []byte("current-ca")},
+				},
+				CMClient: client,
+			}
+
+			err := opts.Run(context.Background(), []string{"alice"})
+			if test.expErrMsg != "" {
+				if err == nil || err.Error() != test.expErrMsg {
+					t.Fatalf("unexpected error, exp=%q got=%v", test.expErrMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, err := client.CertmanagerV1().CertificateRequests("testns").Get(context.Background(), "alice", metav1.GetOptions{}); err != nil {
+				t.Errorf("expected CertificateRequest to be created: %v", err)
+			}
+
+			data, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := clientcmd.Load(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cluster, ok := config.Clusters[test.expCluster]
+			if !ok {
+				t.Fatalf("expected cluster %q in kubeconfig, got %v", test.expCluster, config.Clusters)
+			}
+			if cluster.Server != test.expServer || string(cluster.CertificateAuthorityData) != test.expCAData {
+				t.Errorf("unexpected cluster %+v", cluster)
+			}
+			user := config.AuthInfos["alice"]
+			if user == nil || string(user.ClientCertificateData) != test.expCertData || !bytes.Contains(user.ClientKeyData, []byte("PRIVATE KEY")) {
+				t.Errorf("unexpected user %+v", user)
+			}
+		})
+	}
+}
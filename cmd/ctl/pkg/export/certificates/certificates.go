@@ -0,0 +1,369 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Export issued certificates from their Secrets into a directory or a tar archive.
+
+Secrets that have been issued by cert-manager and match the given selector are
+read from the cluster, and their certificate, private key and CA are written
+out in each of the requested formats. The pem format writes the certificate,
+private key and CA into files ending in .crt, .key and -ca.crt. The pkcs12 and
+jks formats write a single keystore ending in .p12 or .jks.
+
+The PKCS#12 and JKS keystores are encrypted with the contents of
+--password-file, which is required for JKS. The file names are generated from
+--filename-template, which is evaluated against the Namespace and Name of the
+Secret, the CertificateName it was issued for and the CommonName of the
+certificate.
+
+When --archive ends in .tar.gz or .tgz, the archive is gzip compressed.`))
+
+	example = templates.Examples(i18n.T(build.WithTemplate(`
+# Export all issued certificates in the current context namespace as PEM files into ./certs.
+{{.BuildName}} export certificates --output-dir ./certs
+
+# Export certificates with the label 'app=my-service' across all namespaces into a compressed archive.
+{{.BuildName}} export certificates -A -l app=my-service --archive certs.tar.gz
+
+# Export certificates as PEM and PKCS#12 files named after their common name.
+{{.BuildName}} export certificates --formats pem,pkcs12 --password-file ./password --filename-template '{{"{{"}} .CommonName {{"}}"}}' --output-dir ./certs`)))
+)
+
+const (
+	// FormatPEM writes the certificate, private key and CA as separate PEM
+	// files.
+	FormatPEM = "pem"
+	// FormatPKCS12 writes a PKCS#12 keystore containing the private key,
+	// certificate chain and CA.
+	FormatPKCS12 = "pkcs12"
+	// FormatJKS writes a Java keystore containing the private key,
+	// certificate chain and CA.
+	FormatJKS = "jks"
+
+	defaultFilenameTemplate = "{{ .Namespace }}/{{ .Name }}"
+)
+
+// Options is a struct to support export certificates command
+type Options struct {
+	LabelSelector    string
+	AllNamespaces    bool
+	Formats          []string
+	OutputDir        string
+	Archive          string
+	FilenameTemplate string
+	PasswordFile     string
+
+	filenameTemplate *template.Template
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// filenameData is the data that --filename-template is evaluated against.
+type filenameData struct {
+	Namespace       string
+	Name            string
+	CertificateName string
+	CommonName      string
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Formats:          []string{FormatPEM},
+		FilenameTemplate: defaultFilenameTemplate,
+		IOStreams:        ioStreams,
+	}
+}
+
+// NewCmdExportCertificates returns a cobra command for exporting issued
+// certificates from their Secrets
+func NewCmdExportCertificates(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "certificates",
+		Short:   "Export issued certificates into PEM, PKCS#12 or JKS files",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter Secrets on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, export certificates across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringSliceVar(&o.Formats, "formats", o.Formats, "The formats to export each certificate in, any of: pem, pkcs12, jks.")
+	cmd.Flags().StringVar(&o.OutputDir, "output-dir", o.OutputDir, "The directory to write the exported files to.")
+	cmd.Flags().StringVar(&o.Archive, "archive", o.Archive, "The tar archive to write the exported files to. Compressed with gzip if it ends in .tar.gz or .tgz.")
+	cmd.Flags().StringVar(&o.FilenameTemplate, "filename-template", o.FilenameTemplate, "Go template used to name the exported files, without their extension. Evaluated against .Namespace, .Name, .CertificateName and .CommonName.")
+	cmd.Flags().StringVar(&o.PasswordFile, "password-file", o.PasswordFile, "Path to a file containing the password used to encrypt PKCS#12 and JKS keystores.")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("export certificates does not accept arguments, use --selector to choose Secrets")
+	}
+
+	if len(o.OutputDir) == 0 && len(o.Archive) == 0 {
+		return errors.New("one of --output-dir or --archive must be specified")
+	}
+	if len(o.OutputDir) > 0 && len(o.Archive) > 0 {
+		return errors.New("only one of --output-dir or --archive may be specified")
+	}
+
+	if len(o.Formats) == 0 {
+		return errors.New("at least one format must be specified with --formats")
+	}
+	for _, format := range o.Formats {
+		switch format {
+		case FormatPEM, FormatPKCS12:
+		case FormatJKS:
+			if len(o.PasswordFile) == 0 {
+				return errors.New("--password-file must be specified when exporting JKS keystores")
+			}
+		default:
+			return fmt.Errorf("unsupported format %q, must be one of: pem, pkcs12, jks", format)
+		}
+	}
+
+	tmpl, err := template.New("filename").Option("missingkey=error").Parse(o.FilenameTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid --filename-template: %v", err)
+	}
+	o.filenameTemplate = tmpl
+
+	return nil
+}
+
+// Run executes export certificates command
+func (o *Options) Run(ctx context.Context) error {
+	var password string
+	if len(o.PasswordFile) > 0 {
+		data, err := os.ReadFile(o.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("failed to read password file: %v", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	secrets, err := o.collect(ctx)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		fmt.Fprintln(o.ErrOut, "No issued certificates found to export")
+		return nil
+	}
+
+	var w fileWriter
+	if len(o.Archive) > 0 {
+		w, err = newTarWriter(o.Archive, time.Now())
+	} else {
+		w, err = newDirWriter(o.OutputDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	exported := 0
+	seen := make(map[string]string)
+	for _, secret := range secrets {
+		ref := secret.Namespace + "/" + secret.Name
+
+		base, err := o.fileName(secret)
+		if err != nil {
+			w.Close()
+			return fmt.Errorf("failed to generate file name for Secret %s: %v", ref, err)
+		}
+		if other, ok := seen[base]; ok {
+			w.Close()
+			return fmt.Errorf("Secrets %s and %s both export to %q, use a --filename-template that is unique per Secret", other, ref, base)
+		}
+		seen[base] = ref
+
+		files, err := encodeFiles(secret, base, o.Formats, password)
+		if err != nil {
+			fmt.Fprintf(o.ErrOut, "Skipping Secret %s: %v\n", ref, err)
+			continue
+		}
+		for _, f := range files {
+			if err := w.WriteFile(f.name, f.data, f.mode); err != nil {
+				w.Close()
+				return err
+			}
+		}
+		exported++
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	dest := o.OutputDir
+	if len(o.Archive) > 0 {
+		dest = o.Archive
+	}
+	fmt.Fprintf(o.Out, "Exported %d certificates to %s\n", exported, dest)
+	return nil
+}
+
+// collect lists the Secrets matching the selector that have been issued by
+// cert-manager, sorted by namespace and name.
+func (o *Options) collect(ctx context.Context) ([]corev1.Secret, error) {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	list, err := o.KubeClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: o.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []corev1.Secret
+	for _, secret := range list.Items {
+		if _, ok := secret.Annotations[cmapi.CertificateNameKey]; !ok {
+			continue
+		}
+		secrets = append(secrets, secret)
+	}
+
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].Namespace != secrets[j].Namespace {
+			return secrets[i].Namespace < secrets[j].Namespace
+		}
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	return secrets, nil
+}
+
+// fileName evaluates the filename template for the given Secret, returning
+// a relative, slash separated path without an extension.
+func (o *Options) fileName(secret corev1.Secret) (string, error) {
+	data := filenameData{
+		Namespace:       secret.Namespace,
+		Name:            secret.Name,
+		CertificateName: secret.Annotations[cmapi.CertificateNameKey],
+	}
+	if cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey]); err == nil {
+		data.CommonName = cert.Subject.CommonName
+	}
+
+	var buf bytes.Buffer
+	if err := o.filenameTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSpace(buf.String())
+	if len(name) == 0 {
+		return "", errors.New("template evaluated to an empty file name")
+	}
+	name = path.Clean(name)
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("file name %q must be relative to the output", name)
+	}
+	return name, nil
+}
+
+// exportedFile is a single file written for an exported certificate.
+type exportedFile struct {
+	name string
+	data []byte
+	mode os.FileMode
+}
+
+// encodeFiles returns the files to write for the given Secret in each of
+// the requested formats.
+func encodeFiles(secret corev1.Secret, base string, formats []string, password string) ([]exportedFile, error) {
+	certPEM := secret.Data[corev1.TLSCertKey]
+	keyPEM := secret.Data[corev1.TLSPrivateKeyKey]
+	caPEM := secret.Data[cmmeta.TLSCAKey]
+
+	if len(certPEM) == 0 {
+		return nil, fmt.Errorf("no certificate found in %q", corev1.TLSCertKey)
+	}
+
+	var files []exportedFile
+	for _, format := range formats {
+		switch format {
+		case FormatPEM:
+			files = append(files, exportedFile{name: base + ".crt", data: certPEM, mode: 0644})
+			if len(keyPEM) > 0 {
+				files = append(files, exportedFile{name: base + ".key", data: keyPEM, mode: 0600})
+			}
+			if len(caPEM) > 0 {
+				files = append(files, exportedFile{name: base + "-ca.crt", data: caPEM, mode: 0644})
+			}
+		case FormatPKCS12:
+			if len(keyPEM) == 0 {
+				return nil, fmt.Errorf("no private key found in %q, required for %s", corev1.TLSPrivateKeyKey, format)
+			}
+			data, err := encodePKCS12(password, keyPEM, certPEM, caPEM)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode PKCS#12 keystore: %v", err)
+			}
+			files = append(files, exportedFile{name: base + ".p12", data: data, mode: 0600})
+		case FormatJKS:
+			if len(keyPEM) == 0 {
+				return nil, fmt.Errorf("no private key found in %q, required for %s", corev1.TLSPrivateKeyKey, format)
+			}
+			data, err := encodeJKS([]byte(password), keyPEM, certPEM, caPEM)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode JKS keystore: %v", err)
+			}
+			files = append(files, exportedFile{name: base + ".jks", data: data, mode: 0600})
+		}
+	}
+
+	return files, nil
+}
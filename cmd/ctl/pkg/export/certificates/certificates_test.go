@@ -0,0 +1,333 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"an output directory with the default options is valid": {
+			options: &Options{OutputDir: "out", Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+		},
+		"an archive with the default options is valid": {
+			options: &Options{Archive: "out.tar", Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+		},
+		"arguments are not accepted": {
+			options: &Options{OutputDir: "out", Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+			args:    []string{"my-secret"},
+			expErr:  true,
+		},
+		"an output is required": {
+			options: &Options{Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+			expErr:  true,
+		},
+		"only one output may be given": {
+			options: &Options{OutputDir: "out", Archive: "out.tar", Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+			expErr:  true,
+		},
+		"unknown format": {
+			options: &Options{OutputDir: "out", Formats: []string{"der"}, FilenameTemplate: defaultFilenameTemplate},
+			expErr:  true,
+		},
+		"jks requires a password file": {
+			options: &Options{OutputDir: "out", Formats: []string{FormatJKS}, FilenameTemplate: defaultFilenameTemplate},
+			expErr:  true,
+		},
+		"jks with a password file is valid": {
+			options: &Options{OutputDir: "out", Formats: []string{FormatJKS}, PasswordFile: "password", FilenameTemplate: defaultFilenameTemplate},
+		},
+		"invalid filename template": {
+			options: &Options{OutputDir: "out", Formats: []string{FormatPEM}, FilenameTemplate: "{{ .Name"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t, got: %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	keyPEM, certPEM := generateCertificate(t, "example.com")
+
+	issuedSecret := func(namespace, name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Annotations: map[string]string{cmapi.CertificateNameKey: name},
+				Labels:      map[string]string{"app": "my-service"},
+			},
+			Data: data,
+		}
+	}
+
+	objects := []runtime.Object{
+		issuedSecret("ns-1", "full", map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			"ca.crt":                certPEM,
+		}),
+		issuedSecret("ns-2", "no-key", map[string][]byte{
+			corev1.TLSCertKey: certPEM,
+		}),
+		issuedSecret("ns-2", "empty", nil),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "not-issued"},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		options  *Options
+		archive  bool
+		expFiles []string
+		expErr   bool
+	}{
+		"exports PEM files for issued Secrets in all namespaces": {
+			options: &Options{AllNamespaces: true, Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+			expFiles: []string{
+				"ns-1/full-ca.crt",
+				"ns-1/full.crt",
+				"ns-1/full.key",
+				"ns-2/no-key.crt",
+			},
+		},
+		"only exports Secrets in the current namespace": {
+			options: &Options{Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+			expFiles: []string{
+				"ns-1/full-ca.crt",
+				"ns-1/full.crt",
+				"ns-1/full.key",
+			},
+		},
+		"skips Secrets without a private key when exporting PKCS#12": {
+			options: &Options{AllNamespaces: true, Formats: []string{FormatPEM, FormatPKCS12}, FilenameTemplate: defaultFilenameTemplate},
+			expFiles: []string{
+				"ns-1/full-ca.crt",
+				"ns-1/full.crt",
+				"ns-1/full.key",
+				"ns-1/full.p12",
+			},
+		},
+		"names files using the filename template": {
+			options: &Options{AllNamespaces: true, Formats: []string{FormatJKS}, FilenameTemplate: "{{ .CommonName }}-{{ .Namespace }}"},
+			expFiles: []string{
+				"example.com-ns-1.jks",
+			},
+		},
+		"writes a compressed archive": {
+			options: &Options{AllNamespaces: true, Formats: []string{FormatPEM}, FilenameTemplate: defaultFilenameTemplate},
+			archive: true,
+			expFiles: []string{
+				"ns-1/full-ca.crt",
+				"ns-1/full.crt",
+				"ns-1/full.key",
+				"ns-2/no-key.crt",
+			},
+		},
+		"errors when two Secrets export to the same file name": {
+			options: &Options{AllNamespaces: true, Formats: []string{FormatPEM}, FilenameTemplate: "{{ .CommonName }}"},
+			expErr:  true,
+		},
+		"errors when the file name escapes the output": {
+			options: &Options{AllNamespaces: true, Formats: []string{FormatPEM}, FilenameTemplate: "../{{ .Name }}"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			passwordFile := filepath.Join(dir, "password")
+			if err := os.WriteFile(passwordFile, []byte("changeit\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			outDir := filepath.Join(dir, "out")
+			archive := filepath.Join(dir, "out.tar.gz")
+			if test.archive {
+				test.options.Archive = archive
+			} else {
+				test.options.OutputDir = outDir
+			}
+			test.options.PasswordFile = passwordFile
+			test.options.IOStreams = genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+			test.options.Factory = &factory.Factory{
+				Namespace:  "ns-1",
+				KubeClient: kubefake.NewSimpleClientset(objects...),
+			}
+
+			if err := test.options.Validate(nil); err != nil {
+				t.Fatal(err)
+			}
+			err := test.options.Run(context.TODO())
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t, got: %v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+
+			var files map[string][]byte
+			if test.archive {
+				files = readArchive(t, archive)
+			} else {
+				files = readDir(t, outDir)
+			}
+
+			var names []string
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !equalStrings(names, test.expFiles) {
+				t.Errorf("unexpected files, exp=%v got=%v", test.expFiles, names)
+			}
+
+			if data, ok := files["ns-1/full.key"]; ok && !bytes.Equal(data, keyPEM) {
+				t.Errorf("unexpected private key, exp=%s got=%s", keyPEM, data)
+			}
+			if data, ok := files["ns-1/full.p12"]; ok {
+				if _, _, _, err := pkcs12.DecodeChain(data, "changeit"); err != nil {
+					t.Errorf("failed to decode PKCS#12 keystore with password: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func generateCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	key, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pki.EncodePKCS1PrivateKey(key), certPEM
+}
+
+func readDir(t *testing.T, dir string) map[string][]byte {
+	files := make(map[string][]byte)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func readArchive(t *testing.T, path string) map[string][]byte {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = data
+	}
+	return files
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
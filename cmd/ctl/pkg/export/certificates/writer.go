@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jks "github.com/pavel-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// fileWriter writes exported files to their destination. Names are relative,
+// slash separated paths.
+type fileWriter interface {
+	WriteFile(name string, data []byte, mode os.FileMode) error
+	Close() error
+}
+
+// dirWriter writes exported files into a directory.
+type dirWriter struct {
+	dir string
+}
+
+func newDirWriter(dir string) (*dirWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &dirWriter{dir: dir}, nil
+}
+
+func (w *dirWriter) WriteFile(name string, data []byte, mode os.FileMode) error {
+	p := filepath.Join(w.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %v", p, err)
+	}
+	return nil
+}
+
+func (w *dirWriter) Close() error {
+	return nil
+}
+
+// tarWriter writes exported files into a tar archive, which is gzip
+// compressed if the file name ends in .tar.gz or .tgz.
+type tarWriter struct {
+	file    *os.File
+	gzip    *gzip.Writer
+	tar     *tar.Writer
+	modTime time.Time
+}
+
+func newTarWriter(path string, modTime time.Time) (*tarWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &tarWriter{file: f, modTime: modTime}
+	var out io.Writer = f
+	if strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz") {
+		w.gzip = gzip.NewWriter(f)
+		out = w.gzip
+	}
+	w.tar = tar.NewWriter(out)
+	return w, nil
+}
+
+func (w *tarWriter) WriteFile(name string, data []byte, mode os.FileMode) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(mode),
+		Size:    int64(len(data)),
+		ModTime: w.modTime,
+	}
+	if err := w.tar.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := w.tar.Write(data)
+	return err
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.tar.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if w.gzip != nil {
+		if err := w.gzip.Close(); err != nil {
+			w.file.Close()
+			return err
+		}
+	}
+	return w.file.Close()
+}
+
+// encodePKCS12 encodes a PKCS#12 keystore using the password provided. If
+// the certificate data contains multiple certificates, the first is used as
+// the keystore's certificate and the rest are prepended to the CAs.
+func encodePKCS12(password string, keyPEM, certPEM, caPEM []byte) ([]byte, error) {
+	key, err := pki.DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := pki.DecodeX509CertificateChainBytes(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	var cas []*x509.Certificate
+	if len(caPEM) > 0 {
+		cas, err = pki.DecodeX509CertificateChainBytes(caPEM)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(certs) > 1 {
+		cas = append(certs[1:], cas...)
+	}
+	return pkcs12.Encode(rand.Reader, key, certs[0], cas, password)
+}
+
+// encodeJKS encodes a JKS keystore using the password provided, containing
+// the private key and certificate chain as 'certificate' and the CA, if
+// any, as 'ca'.
+func encodeJKS(password, keyPEM, certPEM, caPEM []byte) ([]byte, error) {
+	key, err := pki.DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := pki.DecodeX509CertificateChainBytes(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	certs := make([]jks.Certificate, len(chain))
+	for i, cert := range chain {
+		certs[i] = jks.Certificate{Type: "X509", Content: cert.Raw}
+	}
+
+	ks := jks.New()
+	if err := ks.SetPrivateKeyEntry("certificate", jks.PrivateKeyEntry{
+		CreationTime:     time.Now(),
+		PrivateKey:       keyDER,
+		CertificateChain: certs,
+	}, password); err != nil {
+		return nil, err
+	}
+
+	if len(caPEM) > 0 {
+		ca, err := pki.DecodeX509CertificateBytes(caPEM)
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.SetTrustedCertificateEntry("ca", jks.TrustedCertificateEntry{
+			CreationTime: time.Now(),
+			Certificate:  jks.Certificate{Type: "X509", Content: ca.Raw},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := ks.Store(buf, password); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,430 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/export/certificates"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Export existing cert-manager resources into apply-ready manifests.
+
+Certificates, Issuers and ClusterIssuers are read from the cluster and their
+status, managedFields and other server populated fields are removed, so that
+the output can be committed to a GitOps repository and applied again.
+Certificates that are managed by another resource, such as those created by
+ingress-shim for an Ingress, are skipped.
+
+By default the manifests are printed to stdout as YAML. With --format kustomize
+or --format helm, one file is written per resource into --output-dir, along
+with a kustomization.yaml or a Chart.yaml respectively.
+
+To export the issued certificates themselves, rather than the resources that
+request them, use 'export certificates'.`))
+
+	example = templates.Examples(i18n.T(build.WithTemplate(`
+# Export all Certificates and Issuers in the current context namespace to stdout.
+{{.BuildName}} export
+
+# Export Certificates and Issuers with the label 'app=my-service' across all namespaces.
+{{.BuildName}} export --all-namespaces -l app=my-service
+
+# Export Certificates, Issuers and ClusterIssuers into a kustomization.
+{{.BuildName}} export -A --kinds certificates,issuers,clusterissuers --format kustomize --output-dir ./cert-manager
+
+# Export Certificates in the 'sandbox' namespace into a Helm chart called 'sandbox-certs'.
+{{.BuildName}} export -n sandbox --kinds certificates --format helm --chart-name sandbox-certs --output-dir ./sandbox-certs`)))
+)
+
+const (
+	// FormatYAML prints all exported resources to stdout as a YAML stream.
+	FormatYAML = "yaml"
+	// FormatKustomize writes one file per resource along with a
+	// kustomization.yaml listing them.
+	FormatKustomize = "kustomize"
+	// FormatHelm writes one template per resource along with a Chart.yaml.
+	FormatHelm = "helm"
+
+	kindCertificates   = "certificates"
+	kindIssuers        = "issuers"
+	kindClusterIssuers = "clusterissuers"
+)
+
+// strippedAnnotations are annotations populated by clients or controllers
+// that should not be part of an exported manifest.
+var strippedAnnotations = map[string]bool{
+	"kubectl.kubernetes.io/last-applied-configuration": true,
+	"deployment.kubernetes.io/revision":                true,
+	"meta.helm.sh/release-name":                        true,
+	"meta.helm.sh/release-namespace":                   true,
+}
+
+// Options is a struct to support export command
+type Options struct {
+	LabelSelector string
+	AllNamespaces bool
+	Kinds         []string
+	Format        string
+	OutputDir     string
+	ChartName     string
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Kinds:     []string{kindCertificates, kindIssuers},
+		Format:    FormatYAML,
+		ChartName: "cert-manager-resources",
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdExport returns a cobra command for exporting cert-manager resources
+func NewCmdExport(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export cert-manager resources as apply-ready manifests",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, export resources across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringSliceVar(&o.Kinds, "kinds", o.Kinds, "The kinds of resources to export, any of: certificates, issuers, clusterissuers.")
+	cmd.Flags().StringVar(&o.Format, "format", o.Format, "The output format, one of: yaml, kustomize, helm.")
+	cmd.Flags().StringVar(&o.OutputDir, "output-dir", o.OutputDir, "The directory to write manifests to. Required when --format is kustomize or helm.")
+	cmd.Flags().StringVar(&o.ChartName, "chart-name", o.ChartName, "The name of the Helm chart written when --format is helm.")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	cmd.AddCommand(certificates.NewCmdExportCertificates(ctx, ioStreams))
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("export does not accept arguments, use --kinds and --selector to choose resources")
+	}
+
+	switch o.Format {
+	case FormatYAML:
+		if len(o.OutputDir) > 0 {
+			return errors.New("--output-dir can only be used with --format kustomize or --format helm")
+		}
+	case FormatKustomize, FormatHelm:
+		if len(o.OutputDir) == 0 {
+			return fmt.Errorf("--output-dir must be specified when using --format %s", o.Format)
+		}
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of: yaml, kustomize, helm", o.Format)
+	}
+
+	if len(o.Kinds) == 0 {
+		return errors.New("at least one kind must be specified with --kinds")
+	}
+	for _, kind := range o.Kinds {
+		switch kind {
+		case kindCertificates, kindIssuers, kindClusterIssuers:
+		default:
+			return fmt.Errorf("unsupported kind %q, must be one of: certificates, issuers, clusterissuers", kind)
+		}
+	}
+
+	return nil
+}
+
+// Run executes export command
+func (o *Options) Run(ctx context.Context) error {
+	objs, err := o.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(objs) == 0 {
+		fmt.Fprintln(o.ErrOut, "No resources found to export")
+		return nil
+	}
+
+	switch o.Format {
+	case FormatKustomize:
+		return o.writeKustomization(objs)
+	case FormatHelm:
+		return o.writeHelmChart(objs)
+	default:
+		printer := &printers.YAMLPrinter{}
+		for _, obj := range objs {
+			if err := printer.PrintObj(obj.object, o.Out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// exportedObject is a cleaned resource along with the name of the file it is
+// written to when exporting into a directory.
+type exportedObject struct {
+	object   *unstructured.Unstructured
+	fileName string
+}
+
+// collect fetches all requested resources from the cluster and returns them
+// cleaned of any server populated fields, sorted by file name.
+func (o *Options) collect(ctx context.Context) ([]exportedObject, error) {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	listOpts := metav1.ListOptions{LabelSelector: o.LabelSelector}
+
+	var objs []exportedObject
+	for _, kind := range o.Kinds {
+		switch kind {
+		case kindCertificates:
+			list, err := o.CMClient.CertmanagerV1().Certificates(namespace).List(ctx, listOpts)
+			if err != nil {
+				return nil, err
+			}
+			for _, crt := range list.Items {
+				if owner := metav1.GetControllerOf(&crt); owner != nil {
+					fmt.Fprintf(o.ErrOut, "Skipping Certificate %s/%s as it is managed by %s %s\n", crt.Namespace, crt.Name, owner.Kind, owner.Name)
+					continue
+				}
+				obj, err := newExportedObject(&cmapi.Certificate{
+					TypeMeta:   typeMeta(cmapi.CertificateKind),
+					ObjectMeta: cleanObjectMeta(crt.ObjectMeta),
+					Spec:       crt.Spec,
+				})
+				if err != nil {
+					return nil, err
+				}
+				objs = append(objs, obj)
+			}
+		case kindIssuers:
+			list, err := o.CMClient.CertmanagerV1().Issuers(namespace).List(ctx, listOpts)
+			if err != nil {
+				return nil, err
+			}
+			for _, iss := range list.Items {
+				obj, err := newExportedObject(&cmapi.Issuer{
+					TypeMeta:   typeMeta(cmapi.IssuerKind),
+					ObjectMeta: cleanObjectMeta(iss.ObjectMeta),
+					Spec:       iss.Spec,
+				})
+				if err != nil {
+					return nil, err
+				}
+				objs = append(objs, obj)
+			}
+		case kindClusterIssuers:
+			list, err := o.CMClient.CertmanagerV1().ClusterIssuers().List(ctx, listOpts)
+			if err != nil {
+				return nil, err
+			}
+			for _, iss := range list.Items {
+				obj, err := newExportedObject(&cmapi.ClusterIssuer{
+					TypeMeta:   typeMeta(cmapi.ClusterIssuerKind),
+					ObjectMeta: cleanObjectMeta(iss.ObjectMeta),
+					Spec:       iss.Spec,
+				})
+				if err != nil {
+					return nil, err
+				}
+				objs = append(objs, obj)
+			}
+		}
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].fileName < objs[j].fileName
+	})
+
+	return objs, nil
+}
+
+// newExportedObject converts the given resource to its unstructured form,
+// dropping the status and the empty creationTimestamp that would otherwise
+// be serialized.
+func newExportedObject(obj runtime.Object) (exportedObject, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return exportedObject{}, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	unstructured.RemoveNestedField(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+
+	kind := strings.ToLower(u.GetKind())
+	fileName := fmt.Sprintf("%s-%s.yaml", kind, u.GetName())
+	if len(u.GetNamespace()) > 0 {
+		fileName = fmt.Sprintf("%s-%s-%s.yaml", kind, u.GetNamespace(), u.GetName())
+	}
+	return exportedObject{object: u, fileName: fileName}, nil
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: cmapi.SchemeGroupVersion.String(),
+		Kind:       kind,
+	}
+}
+
+// cleanObjectMeta returns a copy of the given ObjectMeta that only contains
+// the fields a user would set when creating the resource.
+func cleanObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	cleaned := metav1.ObjectMeta{
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Labels:    meta.Labels,
+	}
+	for k, v := range meta.Annotations {
+		if strippedAnnotations[k] {
+			continue
+		}
+		if cleaned.Annotations == nil {
+			cleaned.Annotations = make(map[string]string)
+		}
+		cleaned.Annotations[k] = v
+	}
+	return cleaned
+}
+
+// kustomization is the subset of a kustomize Kustomization written by export.
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+func (o *Options) writeKustomization(objs []exportedObject) error {
+	if err := writeObjects(o.OutputDir, objs, nil); err != nil {
+		return err
+	}
+
+	k := kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+	}
+	for _, obj := range objs {
+		k.Resources = append(k.Resources, obj.fileName)
+	}
+	if err := writeYAML(filepath.Join(o.OutputDir, "kustomization.yaml"), k); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Exported %d resources to kustomization %s\n", len(objs), o.OutputDir)
+	return nil
+}
+
+// chart is the subset of a Helm Chart.yaml written by export.
+type chart struct {
+	APIVersion  string `json:"apiVersion"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+}
+
+// helmTemplateEscaper escapes template delimiters in exported objects, so
+// that Helm renders values such as annotations containing `{{` verbatim
+// rather than evaluating them as template actions.
+var helmTemplateEscaper = strings.NewReplacer(`{{`, `{{ "{{" }}`, `}}`, `{{ "}}" }}`)
+
+func (o *Options) writeHelmChart(objs []exportedObject) error {
+	if err := writeObjects(filepath.Join(o.OutputDir, "templates"), objs, helmTemplateEscaper); err != nil {
+		return err
+	}
+
+	c := chart{
+		APIVersion:  "v2",
+		Name:        o.ChartName,
+		Description: "cert-manager resources exported by " + build.Name(),
+		Type:        "application",
+		Version:     "0.1.0",
+	}
+	if err := writeYAML(filepath.Join(o.OutputDir, "Chart.yaml"), c); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Exported %d resources to Helm chart %s\n", len(objs), o.OutputDir)
+	return nil
+}
+
+// writeObjects writes each object to its own file in dir. If escaper is not
+// nil, it is applied to the marshalled YAML of each object.
+func writeObjects(dir string, objs []exportedObject, escaper *strings.Replacer) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := writeEscapedYAML(filepath.Join(dir, obj.fileName), obj.object.Object, escaper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeYAML(path string, obj interface{}) error {
+	return writeEscapedYAML(path, obj, nil)
+}
+
+func writeEscapedYAML(path string, obj interface{}, escaper *strings.Replacer) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", path, err)
+	}
+	if escaper != nil {
+		data = []byte(escaper.Replace(string(data)))
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"default options are valid": {
+			options: NewOptions(genericclioptions.IOStreams{}),
+		},
+		"arguments are not accepted": {
+			options: NewOptions(genericclioptions.IOStreams{}),
+			args:    []string{"my-cert"},
+			expErr:  true,
+		},
+		"kustomize requires an output directory": {
+			options: &Options{Format: FormatKustomize, Kinds: []string{kindCertificates}},
+			expErr:  true,
+		},
+		"helm with an output directory is valid": {
+			options: &Options{Format: FormatHelm, OutputDir: "out", Kinds: []string{kindCertificates}},
+		},
+		"yaml does not accept an output directory": {
+			options: &Options{Format: FormatYAML, OutputDir: "out", Kinds: []string{kindCertificates}},
+			expErr:  true,
+		},
+		"unknown format": {
+			options: &Options{Format: "json", Kinds: []string{kindCertificates}},
+			expErr:  true,
+		},
+		"unknown kind": {
+			options: &Options{Format: FormatYAML, Kinds: []string{"secrets"}},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-cert",
+			Namespace:       "default",
+			ResourceVersion: "42",
+			UID:             "uid",
+			Generation:      3,
+			Labels:          map[string]string{"app": "my-app"},
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"example.com/owner": "team-a",
+				"example.com/note":  "rendered by {{ .Release.Name }}",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: cmapi.CertificateSpec{
+			SecretName: "my-cert-tls",
+			DNSNames:   []string{"example.com"},
+			IssuerRef:  cmmeta.ObjectReference{Name: "ca-issuer"},
+		},
+		Status: cmapi.CertificateStatus{
+			Revision: func() *int { i := 2; return &i }(),
+		},
+	}
+	ingressCrt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-tls",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "networking.k8s.io/v1",
+				Kind:       "Ingress",
+				Name:       "my-ingress",
+				Controller: func() *bool { b := true; return &b }(),
+			}},
+		},
+	}
+	issuer := &cmapi.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-issuer", Namespace: "default"},
+		Spec: cmapi.IssuerSpec{
+			IssuerConfig: cmapi.IssuerConfig{
+				CA: &cmapi.CAIssuer{SecretName: "ca"},
+			},
+		},
+	}
+
+	newOptions := func(format, outputDir string) (*Options, *bytes.Buffer, *bytes.Buffer) {
+		streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+		o := NewOptions(streams)
+		o.Format = format
+		o.OutputDir = outputDir
+		o.Factory = &factory.Factory{
+			Namespace: "default",
+			CMClient:  cmfake.NewSimpleClientset(crt, ingressCrt, issuer),
+		}
+		return o, out, errOut
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		o, out, errOut := newOptions(FormatYAML, "")
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+
+		expOut := `apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  annotations:
+    example.com/note: rendered by {{ .Release.Name }}
+    example.com/owner: team-a
+  labels:
+    app: my-app
+  name: my-cert
+  namespace: default
+spec:
+  dnsNames:
+  - example.com
+  issuerRef:
+    name: ca-issuer
+  secretName: my-cert-tls
+---
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: ca-issuer
+  namespace: default
+spec:
+  ca:
+    secretName: ca
+`
+		if out.String() != expOut {
+			t.Errorf("unexpected output, exp=\n%s\ngot=\n%s", expOut, out.String())
+		}
+		expErrOut := "Skipping Certificate default/ingress-tls as it is managed by Ingress my-ingress\n"
+		if errOut.String() != expErrOut {
+			t.Errorf("unexpected error output, exp=%q got=%q", expErrOut, errOut.String())
+		}
+	})
+
+	t.Run("kustomize", func(t *testing.T) {
+		dir := t.TempDir()
+		o, _, _ := newOptions(FormatKustomize, dir)
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+
+		kustomization, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expKustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- certificate-default-my-cert.yaml
+- issuer-default-ca-issuer.yaml
+`
+		if string(kustomization) != expKustomization {
+			t.Errorf("unexpected kustomization, exp=\n%s\ngot=\n%s", expKustomization, kustomization)
+		}
+		for _, f := range []string{"certificate-default-my-cert.yaml", "issuer-default-ca-issuer.yaml"} {
+			if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+				t.Errorf("expected resource file %s to be written: %v", f, err)
+			}
+		}
+	})
+
+	t.Run("helm", func(t *testing.T) {
+		dir := t.TempDir()
+		o, _, _ := newOptions(FormatHelm, dir)
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, f := range []string{"Chart.yaml", "templates/certificate-default-my-cert.yaml", "templates/issuer-default-ca-issuer.yaml"} {
+			if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+				t.Errorf("expected chart file %s to be written: %v", f, err)
+			}
+		}
+
+		// Values containing template delimiters must be rendered verbatim
+		// rather than evaluated by Helm.
+		data, err := os.ReadFile(filepath.Join(dir, "templates/certificate-default-my-cert.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl, err := template.New("certificate").Parse(string(data))
+		if err != nil {
+			t.Fatalf("exported template does not parse: %v", err)
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, nil); err != nil {
+			t.Fatalf("exported template does not render: %v", err)
+		}
+		if exp := "example.com/note: rendered by {{ .Release.Name }}\n"; !strings.Contains(rendered.String(), exp) {
+			t.Errorf("expected the rendered template to contain %q, got=\n%s", exp, rendered.String())
+		}
+	})
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	k8sclock "k8s.io/utils/clock"
+	utilexec "k8s.io/utils/exec"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/status/util"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+List the Certificates whose issued certificate expires within the given window.
+
+Certificates are listed by the expiry time recorded in their status, so
+Certificates which have not been issued yet are never listed.
+
+When at least one Certificate expires within the window, the command exits
+with the code given by --exit-code (2 by default), so that it can be used to
+gate CI jobs on impending expirations. Any other error exits with code 1.`))
+
+	example = templates.Examples(i18n.T(build.WithTemplate(`
+# List Certificates in the current context namespace expiring within 30 days.
+{{.BuildName}} get expiring
+
+# List Certificates across all namespaces expiring within 7 days, sorted by name.
+{{.BuildName}} get expiring -A --within 7d --sort-by name
+
+# Fail a nightly job if any Certificate labelled team=web expires within 2 weeks.
+{{.BuildName}} get expiring -A -l team=web --within 336h -o json`)))
+)
+
+const (
+	// OutputTable prints a human readable table.
+	OutputTable = "table"
+	// OutputJSON prints a JSON list.
+	OutputJSON = "json"
+
+	// SortByNotAfter sorts by expiry time, soonest first.
+	SortByNotAfter = "notAfter"
+	// SortByName sorts by namespace and name.
+	SortByName = "name"
+	// SortByNamespace sorts by namespace, then by expiry time.
+	SortByNamespace = "namespace"
+)
+
+var clock k8sclock.Clock = k8sclock.RealClock{}
+
+// Options is a struct to support get expiring command
+type Options struct {
+	LabelSelector string
+	AllNamespaces bool
+	Within        string
+	SortBy        string
+	Output        string
+	ExitCode      int
+
+	within time.Duration
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// ExpiringCertificate is a Certificate expiring within the window.
+type ExpiringCertificate struct {
+	Namespace  string      `json:"namespace"`
+	Name       string      `json:"name"`
+	SecretName string      `json:"secretName"`
+	IssuerKind string      `json:"issuerKind"`
+	IssuerName string      `json:"issuerName"`
+	Ready      bool        `json:"ready"`
+	NotAfter   metav1.Time `json:"notAfter"`
+	// ExpiresIn is the number of seconds until the certificate expires. It
+	// is negative for certificates which have already expired.
+	ExpiresIn int64 `json:"expiresIn"`
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Within:    "30d",
+		SortBy:    SortByNotAfter,
+		Output:    OutputTable,
+		ExitCode:  2,
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdGetExpiring returns a cobra command for listing expiring Certificates
+func NewCmdGetExpiring(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "expiring",
+		Short:   "List Certificates expiring within a window",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, list Certificates across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringVar(&o.Within, "within", o.Within, "The window from now in which Certificates expire, as a duration such as 720h or a number of days such as 30d.")
+	cmd.Flags().StringVar(&o.SortBy, "sort-by", o.SortBy, "The order of the listed Certificates, one of: notAfter, name, namespace.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "The output format, one of: table, json.")
+	cmd.Flags().IntVar(&o.ExitCode, "exit-code", o.ExitCode, "The exit code when at least one Certificate expires within the window. Set to 0 to always exit successfully.")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("get expiring does not accept arguments, use --selector to choose Certificates")
+	}
+
+	within, err := parseWithin(o.Within)
+	if err != nil {
+		return fmt.Errorf("invalid --within %q: %w", o.Within, err)
+	}
+	o.within = within
+
+	switch o.SortBy {
+	case SortByNotAfter, SortByName, SortByNamespace:
+	default:
+		return fmt.Errorf("unsupported --sort-by %q, must be one of: notAfter, name, namespace", o.SortBy)
+	}
+
+	switch o.Output {
+	case OutputTable, OutputJSON:
+	default:
+		return fmt.Errorf("unsupported output format %q, must be one of: table, json", o.Output)
+	}
+
+	if o.ExitCode < 0 || o.ExitCode > 255 {
+		return fmt.Errorf("--exit-code must be between 0 and 255, got %d", o.ExitCode)
+	}
+
+	return nil
+}
+
+// Run executes get expiring command
+func (o *Options) Run(ctx context.Context) error {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	list, err := o.CMClient.CertmanagerV1().Certificates(namespace).List(ctx, metav1.ListOptions{LabelSelector: o.LabelSelector})
+	if err != nil {
+		return err
+	}
+
+	now := clock.Now()
+	expiring := expiringCertificates(list.Items, now, o.within)
+	sortCertificates(expiring, o.SortBy)
+
+	if err := o.write(o.Out, expiring, now); err != nil {
+		return err
+	}
+
+	if len(expiring) > 0 && o.ExitCode != 0 {
+		return utilexec.CodeExitError{
+			Err:  fmt.Errorf("%d Certificate(s) expire within %s", len(expiring), o.Within),
+			Code: o.ExitCode,
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) write(w io.Writer, crts []ExpiringCertificate, now time.Time) error {
+	if o.Output == OutputJSON {
+		if crts == nil {
+			crts = []ExpiringCertificate{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(crts)
+	}
+
+	if len(crts) == 0 {
+		fmt.Fprintf(o.ErrOut, "No Certificates expire within %s\n", o.Within)
+		return nil
+	}
+
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tSECRET\tISSUER\tREADY\tNOT AFTER\tEXPIRES IN")
+	for _, crt := range crts {
+		expiresIn := duration.HumanDuration(crt.NotAfter.Sub(now))
+		if crt.ExpiresIn < 0 {
+			expiresIn = "expired " + duration.HumanDuration(now.Sub(crt.NotAfter.Time)) + " ago"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s/%s\t%t\t%s\t%s\n",
+			crt.Namespace, crt.Name, crt.SecretName, crt.IssuerKind, crt.IssuerName,
+			crt.Ready, crt.NotAfter.UTC().Format(time.RFC3339), expiresIn)
+	}
+	return tw.Flush()
+}
+
+// expiringCertificates returns the Certificates which have been issued a
+// certificate that expires before now+within, including those which have
+// already expired.
+func expiringCertificates(crts []cmapi.Certificate, now time.Time, within time.Duration) []ExpiringCertificate {
+	windowEnd := now.Add(within)
+
+	var expiring []ExpiringCertificate
+	for i := range crts {
+		crt := &crts[i]
+		if crt.Status.NotAfter == nil || crt.Status.NotAfter.Time.After(windowEnd) {
+			continue
+		}
+
+		issuerKind := crt.Spec.IssuerRef.Kind
+		if len(issuerKind) == 0 {
+			issuerKind = cmapi.IssuerKind
+		}
+
+		expiring = append(expiring, ExpiringCertificate{
+			Namespace:  crt.Namespace,
+			Name:       crt.Name,
+			SecretName: crt.Spec.SecretName,
+			IssuerKind: issuerKind,
+			IssuerName: crt.Spec.IssuerRef.Name,
+			Ready: apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+				Type:   cmapi.CertificateConditionReady,
+				Status: cmmeta.ConditionTrue,
+			}),
+			NotAfter:  *crt.Status.NotAfter,
+			ExpiresIn: int64(crt.Status.NotAfter.Sub(now) / time.Second),
+		})
+	}
+
+	return expiring
+}
+
+func sortCertificates(crts []ExpiringCertificate, sortBy string) {
+	byName := func(i, j int) bool {
+		if crts[i].Namespace != crts[j].Namespace {
+			return crts[i].Namespace < crts[j].Namespace
+		}
+		return crts[i].Name < crts[j].Name
+	}
+
+	sort.SliceStable(crts, func(i, j int) bool {
+		switch sortBy {
+		case SortByName:
+			return byName(i, j)
+		case SortByNamespace:
+			if crts[i].Namespace != crts[j].Namespace {
+				return crts[i].Namespace < crts[j].Namespace
+			}
+		}
+		if !crts[i].NotAfter.Equal(&crts[j].NotAfter) {
+			return crts[i].NotAfter.Before(&crts[j].NotAfter)
+		}
+		return byName(i, j)
+	})
+}
+
+// parseWithin parses a Go duration, or a whole number of days suffixed with
+// "d".
+func parseWithin(s string) (time.Duration, error) {
+	var within time.Duration
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errors.New("must be a duration such as 720h or a number of days such as 30d")
+		}
+		within = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		within, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if within <= 0 {
+		return 0, errors.New("must be positive")
+	}
+
+	return within, nil
+}
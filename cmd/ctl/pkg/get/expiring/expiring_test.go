@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakeclock "k8s.io/utils/clock/testing"
+	utilexec "k8s.io/utils/exec"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options   *Options
+		args      []string
+		expErr    bool
+		expWithin time.Duration
+	}{
+		"default options are valid": {
+			options:   NewOptions(genericclioptions.IOStreams{}),
+			expWithin: 30 * 24 * time.Hour,
+		},
+		"arguments are not accepted": {
+			options: NewOptions(genericclioptions.IOStreams{}),
+			args:    []string{"my-cert"},
+			expErr:  true,
+		},
+		"a Go duration is accepted": {
+			options:   &Options{Within: "36h", SortBy: SortByName, Output: OutputJSON},
+			expWithin: 36 * time.Hour,
+		},
+		"a fractional number of days is not accepted": {
+			options: &Options{Within: "1.5d", SortBy: SortByName, Output: OutputJSON},
+			expErr:  true,
+		},
+		"the window must be positive": {
+			options: &Options{Within: "0d", SortBy: SortByName, Output: OutputJSON},
+			expErr:  true,
+		},
+		"unknown sort order": {
+			options: &Options{Within: "1d", SortBy: "issuer", Output: OutputJSON},
+			expErr:  true,
+		},
+		"unknown output format": {
+			options: &Options{Within: "1d", SortBy: SortByName, Output: "yaml"},
+			expErr:  true,
+		},
+		"exit code out of range": {
+			options: &Options{Within: "1d", SortBy: SortByName, Output: OutputJSON, ExitCode: 256},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+			if err == nil && test.options.within != test.expWithin {
+				t.Errorf("unexpected window, exp=%s got=%s", test.expWithin, test.options.within)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	clock = fakeclock.NewFakeClock(now)
+
+	timePtr := func(t time.Time) *metav1.Time { mt := metav1.NewTime(t); return &mt }
+	ready := []cmapi.CertificateCondition{{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}}
+
+	crts := []*cmapi.Certificate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "later", Namespace: "team-a"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "later-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+			},
+			Status: cmapi.CertificateStatus{Conditions: ready, NotAfter: timePtr(now.Add(20 * 24 * time.Hour))},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "soon", Namespace: "team-b"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "soon-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "acme", Kind: cmapi.ClusterIssuerKind},
+			},
+			Status: cmapi.CertificateStatus{Conditions: ready, NotAfter: timePtr(now.Add(2 * 24 * time.Hour))},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "expired", Namespace: "team-b"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "expired-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "acme", Kind: cmapi.ClusterIssuerKind},
+			},
+			Status: cmapi.CertificateStatus{NotAfter: timePtr(now.Add(-time.Hour))},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "valid", Namespace: "team-a"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "valid-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+			},
+			Status: cmapi.CertificateStatus{Conditions: ready, NotAfter: timePtr(now.Add(90 * 24 * time.Hour))},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "team-a"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "pending-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+			},
+		},
+	}
+
+	newOptions := func(within, sortBy, output string, crts ...*cmapi.Certificate) (*Options, *bytes.Buffer) {
+		streams, _, out, _ := genericclioptions.NewTestIOStreams()
+		o := NewOptions(streams)
+		o.Within = within
+		o.SortBy = sortBy
+		o.Output = output
+		o.AllNamespaces = true
+		if err := o.Validate(nil); err != nil {
+			t.Fatal(err)
+		}
+		client := cmfake.NewSimpleClientset()
+		for _, crt := range crts {
+			if err := client.Tracker().Add(crt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		o.Factory = &factory.Factory{CMClient: client}
+		return o, out
+	}
+
+	expectExitCode := func(t *testing.T, err error, code int) {
+		var exitErr utilexec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitStatus() != code {
+			t.Errorf("expected exit code %d, got error %v", code, err)
+		}
+	}
+
+	t.Run("table sorted by expiry", func(t *testing.T) {
+		o, out := newOptions("30d", SortByNotAfter, OutputTable, crts...)
+		expectExitCode(t, o.Run(context.TODO()), 2)
+
+		expOut := `NAMESPACE  NAME     SECRET       ISSUER              READY  NOT AFTER             EXPIRES IN
+team-b     expired  expired-tls  ClusterIssuer/acme  false  2021-10-01T11:00:00Z  expired 60m ago
+team-b     soon     soon-tls     ClusterIssuer/acme  true   2021-10-03T12:00:00Z  2d
+team-a     later    later-tls    Issuer/ca           true   2021-10-21T12:00:00Z  20d
+`
+		if out.String() != expOut {
+			t.Errorf("unexpected output, exp=\n%s\ngot=\n%s", expOut, out.String())
+		}
+	})
+
+	t.Run("json sorted by name", func(t *testing.T) {
+		o, out := newOptions("7d", SortByName, OutputJSON, crts...)
+		expectExitCode(t, o.Run(context.TODO()), 2)
+
+		var got []ExpiringCertificate
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Name != "expired" || got[1].Name != "soon" {
+			t.Fatalf("unexpected certificates: %+v", got)
+		}
+		if got[1].ExpiresIn != int64(2*24*time.Hour/time.Second) {
+			t.Errorf("unexpected expiresIn: %d", got[1].ExpiresIn)
+		}
+	})
+
+	t.Run("no matches exits successfully", func(t *testing.T) {
+		o, out := newOptions("30d", SortByNotAfter, OutputJSON, crts[3:]...)
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != "[]\n" {
+			t.Errorf("unexpected output: %q", out.String())
+		}
+	})
+
+	t.Run("exit code 0 always exits successfully", func(t *testing.T) {
+		o, _ := newOptions("30d", SortByNotAfter, OutputTable, crts...)
+		o.ExitCode = 0
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
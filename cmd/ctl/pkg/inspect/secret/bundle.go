@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	jks "github.com/pavel-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	formatPEM    = "PEM"
+	formatPKCS12 = "PKCS#12"
+	formatJKS    = "JKS"
+)
+
+// jksMagic is the magic number that all JKS keystores start with.
+var jksMagic = []byte{0xFE, 0xED, 0xFE, 0xED}
+
+// certificateBundle is the decoded contents of a Secret or file being
+// inspected.
+type certificateBundle struct {
+	// certs is the PEM encoded certificate chain, in the order it was found.
+	// The first certificate is the one that will be described.
+	certs [][]byte
+	// key is the private key found alongside the certificates, if any.
+	key crypto.Signer
+	// ca is the PEM encoded CA certificate, if one was stored separately to
+	// the certificate chain.
+	ca []byte
+	// keystore describes the entries of the keystore the bundle was decoded
+	// from. It is nil if the bundle was not decoded from a keystore.
+	keystore *keystoreContents
+}
+
+type keystoreContents struct {
+	format  string
+	entries []keystoreEntry
+}
+
+type keystoreEntry struct {
+	// alias is the name of the entry. PKCS#12 entries do not have an alias.
+	alias string
+	// hasKey is true if the entry contains a private key.
+	hasKey bool
+	// certs are the certificates stored in the entry.
+	certs []*x509.Certificate
+}
+
+// decodeBundle decodes a PEM, PKCS#12 or JKS encoded certificate bundle. The
+// password is used to decrypt PKCS#12 and JKS keystores.
+func decodeBundle(data []byte, password string) (*certificateBundle, error) {
+	switch {
+	case len(data) == 0:
+		return nil, errors.New("no data found")
+	case bytes.HasPrefix(data, jksMagic):
+		return decodeJKSBundle(data, password)
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		return decodePEMBundle(data)
+	}
+
+	return decodePKCS12Bundle(data, password)
+}
+
+func decodePEMBundle(data []byte) (*certificateBundle, error) {
+	b := &certificateBundle{}
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+
+		switch block.Type {
+		case "CERTIFICATE":
+			b.certs = append(b.certs, pem.EncodeToMemory(block))
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+			if b.key != nil {
+				return nil, errors.New("found more than one private key in PEM data")
+			}
+			key, err := pki.DecodePrivateKeyBytes(pem.EncodeToMemory(block))
+			if err != nil {
+				return nil, fmt.Errorf("error when parsing private key: %w", err)
+			}
+			b.key = key
+		}
+	}
+
+	if len(b.certs) == 0 {
+		return nil, errors.New("no certificates found in PEM data")
+	}
+
+	return b, nil
+}
+
+func decodePKCS12Bundle(data []byte, password string) (*certificateBundle, error) {
+	key, leaf, cas, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		// Truststores do not contain a private key, and so cannot be decoded
+		// as a chain.
+		var truststoreErr error
+		cas, truststoreErr = pkcs12.DecodeTrustStore(data, password)
+		if truststoreErr != nil {
+			return nil, fmt.Errorf("error when decoding PKCS#12 data: %w", err)
+		}
+	}
+
+	b := &certificateBundle{keystore: &keystoreContents{format: formatPKCS12}}
+	if leaf != nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T in PKCS#12 data", key)
+		}
+		b.key = signer
+		b.certs = append(b.certs, encodeCertificatePEM(leaf))
+		b.keystore.entries = append(b.keystore.entries, keystoreEntry{hasKey: true, certs: []*x509.Certificate{leaf}})
+	}
+	for _, ca := range cas {
+		b.certs = append(b.certs, encodeCertificatePEM(ca))
+		b.keystore.entries = append(b.keystore.entries, keystoreEntry{certs: []*x509.Certificate{ca}})
+	}
+
+	if len(b.certs) == 0 {
+		return nil, errors.New("no certificates found in PKCS#12 data")
+	}
+
+	return b, nil
+}
+
+func decodeJKSBundle(data []byte, password string) (*certificateBundle, error) {
+	ks := jks.New(jks.WithOrderedAliases())
+	if err := ks.Load(bytes.NewReader(data), []byte(password)); err != nil {
+		return nil, fmt.Errorf("error when decoding JKS data: %w", err)
+	}
+
+	b := &certificateBundle{keystore: &keystoreContents{format: formatJKS}}
+	var trusted [][]byte
+	for _, alias := range ks.Aliases() {
+		entry := keystoreEntry{alias: alias}
+
+		switch {
+		case ks.IsPrivateKeyEntry(alias):
+			pke, err := ks.GetPrivateKeyEntry(alias, []byte(password))
+			if err != nil {
+				return nil, fmt.Errorf("error when reading JKS entry %q: %w", alias, err)
+			}
+			entry.hasKey = true
+			for _, c := range pke.CertificateChain {
+				cert, err := x509.ParseCertificate(c.Content)
+				if err != nil {
+					return nil, fmt.Errorf("error when parsing certificate in JKS entry %q: %w", alias, err)
+				}
+				entry.certs = append(entry.certs, cert)
+			}
+
+			// only the first private key entry is described
+			if b.key != nil {
+				break
+			}
+			key, err := x509.ParsePKCS8PrivateKey(pke.PrivateKey)
+			if err != nil {
+				return nil, fmt.Errorf("error when parsing private key in JKS entry %q: %w", alias, err)
+			}
+			signer, ok := key.(crypto.Signer)
+			if !ok {
+				return nil, fmt.Errorf("unsupported private key type %T in JKS entry %q", key, alias)
+			}
+			b.key = signer
+			for _, cert := range entry.certs {
+				b.certs = append(b.certs, encodeCertificatePEM(cert))
+			}
+
+		case ks.IsTrustedCertificateEntry(alias):
+			tce, err := ks.GetTrustedCertificateEntry(alias)
+			if err != nil {
+				return nil, fmt.Errorf("error when reading JKS entry %q: %w", alias, err)
+			}
+			cert, err := x509.ParseCertificate(tce.Certificate.Content)
+			if err != nil {
+				return nil, fmt.Errorf("error when parsing certificate in JKS entry %q: %w", alias, err)
+			}
+			entry.certs = []*x509.Certificate{cert}
+			trusted = append(trusted, encodeCertificatePEM(cert))
+		}
+
+		b.keystore.entries = append(b.keystore.entries, entry)
+	}
+
+	switch {
+	case len(b.certs) > 0 && len(trusted) > 0:
+		b.ca = trusted[0]
+	case len(b.certs) == 0:
+		// a truststore, so describe the trusted certificates
+		b.certs = trusted
+	}
+
+	if len(b.certs) == 0 {
+		return nil, errors.New("no certificates found in JKS data")
+	}
+
+	return b, nil
+}
+
+func encodeCertificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
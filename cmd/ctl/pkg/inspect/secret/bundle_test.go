@@ -0,0 +1,258 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	jks "github.com/pavel-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+type testChain struct {
+	leafKey, caKey crypto.Signer
+	leaf, ca       *x509.Certificate
+}
+
+func mustCreateChain(t *testing.T) testChain {
+	caKey, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafKey, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	ca := mustSignCertificate(t, caTmpl, caTmpl, caKey, caKey)
+
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-leaf"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leaf := mustSignCertificate(t, leafTmpl, ca, leafKey, caKey)
+
+	return testChain{leafKey: leafKey, caKey: caKey, leaf: leaf, ca: ca}
+}
+
+func mustSignCertificate(t *testing.T, tmpl, parent *x509.Certificate, key, parentKey crypto.Signer) *x509.Certificate {
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func mustEncodeJKS(t *testing.T, chain testChain, password string) []byte {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(chain.leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks := jks.New()
+	if err := ks.SetPrivateKeyEntry("certificate", jks.PrivateKeyEntry{
+		CreationTime:     time.Now(),
+		PrivateKey:       keyDER,
+		CertificateChain: []jks.Certificate{{Type: "X509", Content: chain.leaf.Raw}},
+	}, []byte(password)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.SetTrustedCertificateEntry("ca", jks.TrustedCertificateEntry{
+		CreationTime: time.Now(),
+		Certificate:  jks.Certificate{Type: "X509", Content: chain.ca.Raw},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := ks.Store(&buf, []byte(password)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_decodeBundle(t *testing.T) {
+	chain := mustCreateChain(t)
+	leafPEM := encodeCertificatePEM(chain.leaf)
+	caPEM := encodeCertificatePEM(chain.ca)
+	keyPEM, err := pki.EncodePKCS8PrivateKey(chain.leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keystore, err := pkcs12.Encode(rand.Reader, chain.leafKey, chain.leaf, []*x509.Certificate{chain.ca}, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	truststore, err := pkcs12.EncodeTrustStore(rand.Reader, []*x509.Certificate{chain.ca}, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		data     []byte
+		password string
+
+		expectedCerts    [][]byte
+		expectedCA       []byte
+		expectKey        bool
+		expectedKeystore *keystoreContents
+		expectErr        bool
+	}{
+		"PEM chain and key": {
+			data:          bytes.Join([][]byte{leafPEM, caPEM, keyPEM}, nil),
+			expectedCerts: [][]byte{leafPEM, caPEM},
+			expectKey:     true,
+		},
+		"PEM certificate without key": {
+			data:          leafPEM,
+			expectedCerts: [][]byte{leafPEM},
+		},
+		"PEM key without certificate": {
+			data:      keyPEM,
+			expectErr: true,
+		},
+		"PKCS#12 keystore": {
+			data:          keystore,
+			password:      "password",
+			expectedCerts: [][]byte{leafPEM, caPEM},
+			expectKey:     true,
+			expectedKeystore: &keystoreContents{
+				format: formatPKCS12,
+				entries: []keystoreEntry{
+					{hasKey: true, certs: []*x509.Certificate{chain.leaf}},
+					{certs: []*x509.Certificate{chain.ca}},
+				},
+			},
+		},
+		"PKCS#12 truststore": {
+			data:          truststore,
+			password:      "password",
+			expectedCerts: [][]byte{caPEM},
+			expectedKeystore: &keystoreContents{
+				format: formatPKCS12,
+				entries: []keystoreEntry{
+					{certs: []*x509.Certificate{chain.ca}},
+				},
+			},
+		},
+		"PKCS#12 keystore with wrong password": {
+			data:      keystore,
+			password:  "wrong",
+			expectErr: true,
+		},
+		"JKS keystore": {
+			data:          mustEncodeJKS(t, chain, "password"),
+			password:      "password",
+			expectedCerts: [][]byte{leafPEM},
+			expectedCA:    caPEM,
+			expectKey:     true,
+			expectedKeystore: &keystoreContents{
+				format: formatJKS,
+				entries: []keystoreEntry{
+					{alias: "ca", certs: []*x509.Certificate{chain.ca}},
+					{alias: "certificate", hasKey: true, certs: []*x509.Certificate{chain.leaf}},
+				},
+			},
+		},
+		"JKS keystore with wrong password": {
+			data:      mustEncodeJKS(t, chain, "password"),
+			password:  "wrong",
+			expectErr: true,
+		},
+		"no data": {
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := decodeBundle(test.data, test.password)
+			if (err != nil) != test.expectErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !bytes.Equal(bytes.Join(b.certs, nil), bytes.Join(test.expectedCerts, nil)) {
+				t.Errorf("unexpected certificates, exp=%s got=%s", bytes.Join(test.expectedCerts, nil), bytes.Join(b.certs, nil))
+			}
+			if !bytes.Equal(b.ca, test.expectedCA) {
+				t.Errorf("unexpected CA, exp=%s got=%s", test.expectedCA, b.ca)
+			}
+			if (b.key != nil) != test.expectKey {
+				t.Errorf("unexpected private key, exp=%t got=%v", test.expectKey, b.key)
+			}
+			if b.key != nil {
+				if ok, _ := pki.PublicKeyMatchesCertificate(b.key.Public(), chain.leaf); !ok {
+					t.Errorf("decoded private key does not match leaf certificate")
+				}
+			}
+
+			if (b.keystore == nil) != (test.expectedKeystore == nil) {
+				t.Fatalf("unexpected keystore, exp=%+v got=%+v", test.expectedKeystore, b.keystore)
+			}
+			if b.keystore == nil {
+				return
+			}
+			if b.keystore.format != test.expectedKeystore.format {
+				t.Errorf("unexpected keystore format, exp=%s got=%s", test.expectedKeystore.format, b.keystore.format)
+			}
+			if len(b.keystore.entries) != len(test.expectedKeystore.entries) {
+				t.Fatalf("unexpected keystore entries, exp=%+v got=%+v", test.expectedKeystore.entries, b.keystore.entries)
+			}
+			for i, entry := range b.keystore.entries {
+				exp := test.expectedKeystore.entries[i]
+				if entry.alias != exp.alias || entry.hasKey != exp.hasKey || len(entry.certs) != len(exp.certs) {
+					t.Errorf("unexpected keystore entry %d, exp=%+v got=%+v", i, exp, entry)
+					continue
+				}
+				for j := range entry.certs {
+					if !entry.certs[j].Equal(exp.certs[j]) {
+						t.Errorf("unexpected certificate %d in keystore entry %d", j, i)
+					}
+				}
+			}
+		})
+	}
+}
@@ -19,10 +19,13 @@ package secret
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"os"
 	"strings"
 	"text/template"
 	"time"
@@ -81,18 +84,46 @@ const debuggingTemplate = `Debugging:
 	CRL Status:	{{ .CRLStatus }}
 	OCSP Status:	{{ .OCSPStatus }}`
 
+const chainTemplate = `Chain:{{ range .Certificates }}
+	{{ .Index }}:	{{ .Subject }}, issued by {{ .Issuer }}, expires {{ .NotAfter }}{{ end }}
+	Order:	{{ .Order }}`
+
+const privateKeyTemplate = `Private Key:
+	Algorithm:	{{ .Algorithm }}
+	Matches certificate:	{{ .MatchesCertificate }}`
+
+const keystoreTemplate = `Keystore:
+	Format:	{{ .Format }}
+	Entries:{{ range .Entries }}
+		- {{ . }}{{ end }}`
+
 var (
 	long = templates.LongDesc(i18n.T(`
-Get details about a kubernetes.io/tls typed secret`))
+Get details about a kubernetes.io/tls typed secret.
+
+Instead of a Secret, a PEM, PKCS#12 or JKS encoded bundle can be inspected
+from a file or stdin using --file, without access to a cluster.`))
 
 	example = templates.Examples(i18n.T(build.WithTemplate(`
 # Query information about a secret with name 'my-crt' in namespace 'my-namespace'
 {{.BuildName}} inspect secret my-crt --namespace my-namespace
+
+# Query information about an exported PKCS#12 keystore
+{{.BuildName}} inspect secret --file keystore.p12 --password changeit
+
+# Query information about a PEM bundle read from stdin
+cat tls.crt tls.key | {{.BuildName}} inspect secret --file -
 `)))
 )
 
 // Options is a struct to support status certificate command
 type Options struct {
+	// Filename is the path of a PEM, PKCS#12 or JKS file to inspect instead
+	// of a Secret. A value of "-" reads from stdin.
+	Filename string
+	// Password is used to decrypt PKCS#12 and JKS keystores.
+	Password string
+
 	genericclioptions.IOStreams
 	*factory.Factory
 }
@@ -120,13 +151,37 @@ func NewCmdInspectSecret(ctx context.Context, ioStreams genericclioptions.IOStre
 		},
 	}
 
+	cmd.Flags().StringVarP(&o.Filename, "file", "f", o.Filename,
+		"Path to a PEM, PKCS#12 or JKS encoded file to inspect instead of a Secret. Use '-' to read from stdin.")
+	cmd.Flags().StringVar(&o.Password, "password", o.Password,
+		"Password used to decrypt the PKCS#12 or JKS keystore given by --file.")
+
 	o.Factory = factory.New(ctx, cmd)
 
+	// Inspecting a file does not require access to a cluster, so skip
+	// populating the Factory.
+	factoryPreRun := cmd.PreRun
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		if len(o.Filename) > 0 {
+			return
+		}
+		factoryPreRun(cmd, args)
+	}
+
 	return cmd
 }
 
 // Validate validates the provided options
 func (o *Options) Validate(args []string) error {
+	if len(o.Filename) > 0 {
+		if len(args) > 0 {
+			return errors.New("the name of a Secret cannot be provided when inspecting a file")
+		}
+		return nil
+	}
+	if len(o.Password) > 0 {
+		return errors.New("--password can only be used when inspecting a file")
+	}
 	if len(args) < 1 {
 		return errors.New("the name of the Secret has to be provided as argument")
 	}
@@ -138,29 +193,33 @@ func (o *Options) Validate(args []string) error {
 
 // Run executes status certificate command
 func (o *Options) Run(ctx context.Context, args []string) error {
-	secret, err := o.KubeClient.CoreV1().Secrets(o.Namespace).Get(ctx, args[0], metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("error when finding Secret %q: %w\n", args[0], err)
+	var (
+		b   *certificateBundle
+		err error
+	)
+	if len(o.Filename) > 0 {
+		b, err = o.bundleFromFile()
+	} else {
+		b, err = o.bundleFromSecret(ctx, args[0])
 	}
-
-	certData := secret.Data[corev1.TLSCertKey]
-	certs, err := splitPEMs(certData)
 	if err != nil {
 		return err
 	}
-	if len(certs) < 1 {
-		return errors.New("no PEM data found in secret")
-	}
 
 	intermediates := [][]byte(nil)
-	if len(certs) > 1 {
-		intermediates = certs[1:]
+	if len(b.certs) > 1 {
+		intermediates = b.certs[1:]
 	}
 
 	// we only want to inspect the leaf certificate
-	x509Cert, err := pki.DecodeX509CertificateBytes(certs[0])
+	x509Cert, err := pki.DecodeX509CertificateBytes(b.certs[0])
+	if err != nil {
+		return fmt.Errorf("error when parsing certificate: %w", err)
+	}
+
+	chain, err := pki.DecodeX509CertificateChainBytes(bytes.Join(b.certs, nil))
 	if err != nil {
-		return fmt.Errorf("error when parsing 'tls.crt': %w", err)
+		return fmt.Errorf("error when parsing certificate chain: %w", err)
 	}
 
 	out := []string{
@@ -169,14 +228,78 @@ func (o *Options) Run(ctx context.Context, args []string) error {
 		describeIssuedBy(x509Cert),
 		describeIssuedFor(x509Cert),
 		describeCertificate(x509Cert),
-		describeDebugging(x509Cert, intermediates, secret.Data[cmmeta.TLSCAKey]),
+		describeChain(chain),
+	}
+	if b.key != nil {
+		out = append(out, describePrivateKey(x509Cert, b.key))
 	}
+	if b.keystore != nil {
+		out = append(out, describeKeystore(b.keystore))
+	}
+	out = append(out, describeDebugging(x509Cert, intermediates, b.ca))
 
-	fmt.Println(strings.Join(out, "\n\n"))
+	fmt.Fprintln(o.Out, strings.Join(out, "\n\n"))
 
 	return nil
 }
 
+// bundleFromSecret reads the certificate chain, private key and CA from the
+// named kubernetes.io/tls Secret.
+func (o *Options) bundleFromSecret(ctx context.Context, name string) (*certificateBundle, error) {
+	secret, err := o.KubeClient.CoreV1().Secrets(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when finding Secret %q: %w\n", name, err)
+	}
+
+	certData := secret.Data[corev1.TLSCertKey]
+	certs, err := splitPEMs(certData)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) < 1 {
+		return nil, errors.New("no PEM data found in secret")
+	}
+
+	b := &certificateBundle{
+		certs: certs,
+		ca:    secret.Data[cmmeta.TLSCAKey],
+	}
+
+	if keyData := secret.Data[corev1.TLSPrivateKeyKey]; len(keyData) > 0 {
+		// The certificate can still be inspected without the private key.
+		b.key, err = pki.DecodePrivateKeyBytes(keyData)
+		if err != nil {
+			fmt.Fprintf(o.ErrOut, "Warning: error when parsing 'tls.key', not inspecting the private key: %v\n", err)
+		}
+	}
+
+	return b, nil
+}
+
+// bundleFromFile decodes the PEM, PKCS#12 or JKS encoded file given by
+// Filename, reading from stdin if it is "-".
+func (o *Options) bundleFromFile() (*certificateBundle, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if o.Filename == "-" {
+		data, err = io.ReadAll(o.In)
+	} else {
+		data, err = os.ReadFile(o.Filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when reading %q: %w", o.Filename, err)
+	}
+
+	b, err := decodeBundle(data, o.Password)
+	if err != nil {
+		return nil, fmt.Errorf("error when decoding %q: %w", o.Filename, err)
+	}
+
+	return b, nil
+}
+
 func describeValidFor(cert *x509.Certificate) string {
 	var b bytes.Buffer
 	template.Must(template.New("validForTemplate").Parse(validForTemplate)).Execute(&b, struct {
@@ -281,6 +404,104 @@ func describeDebugging(cert *x509.Certificate, intermediates [][]byte, ca []byte
 	return b.String()
 }
 
+func describeChain(chain []*x509.Certificate) string {
+	type chainCertificate struct {
+		Index    int
+		Subject  string
+		Issuer   string
+		NotAfter string
+	}
+
+	var certs []chainCertificate
+	for i, cert := range chain {
+		certs = append(certs, chainCertificate{
+			Index:    i,
+			Subject:  printOrNone(cert.Subject.String()),
+			Issuer:   printOrNone(cert.Issuer.String()),
+			NotAfter: cert.NotAfter.Format(time.RFC1123),
+		})
+	}
+
+	var b bytes.Buffer
+	template.Must(template.New("chainTemplate").Parse(chainTemplate)).Execute(&b, struct {
+		Certificates []chainCertificate
+		Order        string
+	}{
+		Certificates: certs,
+		Order:        describeChainOrder(chain),
+	})
+
+	return b.String()
+}
+
+// describeChainOrder checks that each certificate in the chain is signed by
+// the certificate following it.
+func describeChainOrder(chain []*x509.Certificate) string {
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return fmt.Sprintf("invalid: certificate %d was not issued by certificate %d", i, i+1)
+		}
+	}
+
+	return "valid"
+}
+
+func describePrivateKey(cert *x509.Certificate, key crypto.Signer) string {
+	matches := "yes"
+	if ok, err := pki.PublicKeyMatchesCertificate(key.Public(), cert); err != nil {
+		matches = fmt.Sprintf("error: %s", err.Error())
+	} else if !ok {
+		matches = "no"
+	}
+
+	var b bytes.Buffer
+	template.Must(template.New("privateKeyTemplate").Parse(privateKeyTemplate)).Execute(&b, struct {
+		Algorithm          string
+		MatchesCertificate string
+	}{
+		Algorithm:          privateKeyAlgorithm(key),
+		MatchesCertificate: matches,
+	})
+
+	return b.String()
+}
+
+func describeKeystore(ks *keystoreContents) string {
+	var entries []string
+	for _, entry := range ks.entries {
+		entries = append(entries, describeKeystoreEntry(entry))
+	}
+
+	var b bytes.Buffer
+	template.Must(template.New("keystoreTemplate").Parse(keystoreTemplate)).Execute(&b, struct {
+		Format  string
+		Entries []string
+	}{
+		Format:  ks.format,
+		Entries: entries,
+	})
+
+	return b.String()
+}
+
+func describeKeystoreEntry(entry keystoreEntry) string {
+	var desc string
+	switch {
+	case entry.hasKey:
+		desc = fmt.Sprintf("private key with %d certificate(s)", len(entry.certs))
+	default:
+		desc = "trusted certificate"
+	}
+	if len(entry.certs) > 0 {
+		desc = fmt.Sprintf("%s (%s)", desc, printOrNone(entry.certs[0].Subject.String()))
+	}
+	if len(entry.alias) > 0 {
+		desc = fmt.Sprintf("%s: %s", entry.alias, desc)
+	}
+
+	return desc
+}
+
 func describeCRL(cert *x509.Certificate) string {
 	if len(cert.CRLDistributionPoints) < 1 {
 		return "No CRL endpoints set"
@@ -17,13 +17,20 @@ limitations under the License.
 package secret
 
 import (
+	"context"
+	"crypto"
 	"crypto/x509"
 	"strings"
 	"testing"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	fakeclock "k8s.io/utils/clock/testing"
 
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -54,6 +61,32 @@ func MustParseCertificate(t *testing.T, certData string) *x509.Certificate {
 	return x509Cert
 }
 
+func Test_bundleFromSecret(t *testing.T) {
+	streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+	o := NewOptions(streams)
+	o.Factory = &factory.Factory{
+		Namespace: "default",
+		KubeClient: kubefake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls"},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       []byte(testCert),
+				corev1.TLSPrivateKeyKey: []byte("not a private key"),
+			},
+		}),
+	}
+
+	b, err := o.bundleFromSecret(context.TODO(), "tls")
+	if err != nil {
+		t.Fatalf("expected an unparsable private key not to fail, got: %v", err)
+	}
+	if len(b.certs) != 1 || b.key != nil {
+		t.Errorf("expected the certificate without a private key, got %d certificates and key %v", len(b.certs), b.key)
+	}
+	if !strings.Contains(errOut.String(), "Warning: error when parsing 'tls.key'") {
+		t.Errorf("expected a warning about the private key, got %q", errOut.String())
+	}
+}
+
 func Test_describeCRL(t *testing.T) {
 	tests := []struct {
 		name string
@@ -317,3 +350,115 @@ func makeInvisibleVisible(in string) string {
 
 	return in
 }
+
+func Test_describeChain(t *testing.T) {
+	chain := mustCreateChain(t)
+	tests := []struct {
+		name  string
+		chain []*x509.Certificate
+		want  string
+	}{
+		{
+			name:  "Describe chain in order",
+			chain: []*x509.Certificate{chain.leaf, chain.ca},
+			want: `Chain:
+	0:	CN=test-leaf, issued by CN=test-ca, expires ` + chain.leaf.NotAfter.Format(time.RFC1123) + `
+	1:	CN=test-ca, issued by CN=test-ca, expires ` + chain.ca.NotAfter.Format(time.RFC1123) + `
+	Order:	valid`,
+		},
+		{
+			name:  "Describe chain out of order",
+			chain: []*x509.Certificate{chain.ca, chain.leaf},
+			want: `Chain:
+	0:	CN=test-ca, issued by CN=test-ca, expires ` + chain.ca.NotAfter.Format(time.RFC1123) + `
+	1:	CN=test-leaf, issued by CN=test-ca, expires ` + chain.leaf.NotAfter.Format(time.RFC1123) + `
+	Order:	invalid: certificate 0 was not issued by certificate 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeChain(tt.chain); got != tt.want {
+				t.Errorf("describeChain() = %v, want %v", makeInvisibleVisible(got), makeInvisibleVisible(tt.want))
+			}
+		})
+	}
+}
+
+func Test_describePrivateKey(t *testing.T) {
+	chain := mustCreateChain(t)
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		key  crypto.Signer
+		want string
+	}{
+		{
+			name: "Describe matching private key",
+			cert: chain.leaf,
+			key:  chain.leafKey,
+			want: `Private Key:
+	Algorithm:	ECDSA
+	Matches certificate:	yes`,
+		},
+		{
+			name: "Describe mismatched private key",
+			cert: chain.leaf,
+			key:  chain.caKey,
+			want: `Private Key:
+	Algorithm:	ECDSA
+	Matches certificate:	no`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describePrivateKey(tt.cert, tt.key); got != tt.want {
+				t.Errorf("describePrivateKey() = %v, want %v", makeInvisibleVisible(got), makeInvisibleVisible(tt.want))
+			}
+		})
+	}
+}
+
+func Test_describeKeystore(t *testing.T) {
+	chain := mustCreateChain(t)
+	tests := []struct {
+		name     string
+		keystore *keystoreContents
+		want     string
+	}{
+		{
+			name: "Describe JKS keystore",
+			keystore: &keystoreContents{
+				format: formatJKS,
+				entries: []keystoreEntry{
+					{alias: "ca", certs: []*x509.Certificate{chain.ca}},
+					{alias: "certificate", hasKey: true, certs: []*x509.Certificate{chain.leaf, chain.ca}},
+				},
+			},
+			want: `Keystore:
+	Format:	JKS
+	Entries:
+		- ca: trusted certificate (CN=test-ca)
+		- certificate: private key with 2 certificate(s) (CN=test-leaf)`,
+		},
+		{
+			name: "Describe PKCS#12 truststore",
+			keystore: &keystoreContents{
+				format: formatPKCS12,
+				entries: []keystoreEntry{
+					{certs: []*x509.Certificate{chain.ca}},
+				},
+			},
+			want: `Keystore:
+	Format:	PKCS#12
+	Entries:
+		- trusted certificate (CN=test-ca)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeKeystore(tt.keystore); got != tt.want {
+				t.Errorf("describeKeystore() = %v, want %v", makeInvisibleVisible(got), makeInvisibleVisible(tt.want))
+			}
+		})
+	}
+}
@@ -19,6 +19,9 @@ package secret
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/pem"
@@ -183,3 +186,16 @@ func splitPEMs(certData []byte) ([][]byte, error) {
 	}
 	return certs, nil
 }
+
+func privateKeyAlgorithm(key crypto.Signer) string {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return x509.RSA.String()
+	case *ecdsa.PrivateKey:
+		return x509.ECDSA.String()
+	case ed25519.PrivateKey:
+		return x509.Ed25519.String()
+	default:
+		return fmt.Sprintf("unknown (%T)", key)
+	}
+}
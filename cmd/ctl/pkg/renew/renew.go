@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -46,11 +47,27 @@ Mark cert-manager Certificate resources for manual renewal.`))
 # Renew the Certificates named 'my-app' and 'vault' in the current context namespace.
 {{.BuildName}} renew my-app vault
 
+# Renew the Certificate named 'my-app', recording the reason for the renewal in its status.
+{{.BuildName}} renew my-app --reason "private key leaked in CI logs"
+
 # Renew all Certificates in the 'kube-system' namespace.
 {{.BuildName}} renew --namespace kube-system --all
 
 # Renew all Certificates in all namespaces, provided those Certificates have the label 'app=my-service'
-{{.BuildName}} renew --all-namespaces -l app=my-service`)))
+{{.BuildName}} renew --all-namespaces -l app=my-service
+
+# Renew all Certificates in all namespaces, triggering at most 2 renewals per second
+{{.BuildName}} renew --all-namespaces --all --qps 2`)))
+)
+
+const (
+	// defaultQPS is the default maximum number of Certificates marked for
+	// renewal per second.
+	defaultQPS = 10
+
+	// defaultChunkSize is the default number of Certificates requested from
+	// the apiserver at a time.
+	defaultChunkSize = 500
 )
 
 // Options is a struct to support renew command
@@ -59,6 +76,16 @@ type Options struct {
 	All           bool
 	AllNamespaces bool
 
+	// QPS is the maximum number of Certificates marked for renewal per
+	// second. Zero means no limit.
+	QPS float32
+	// ChunkSize is the number of Certificates requested from the apiserver at
+	// a time when listing Certificates. Zero means all at once.
+	ChunkSize int64
+	// Reason is the reason for the renewal, which is recorded in the status
+	// of the Certificates along with the identity of the requester.
+	Reason string
+
 	genericclioptions.IOStreams
 	*factory.Factory
 }
@@ -88,6 +115,9 @@ func NewCmdRenew(ctx context.Context, ioStreams genericclioptions.IOStreams) *co
 	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, mark Certificates across namespaces for manual renewal. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().BoolVar(&o.All, "all", o.All, "Renew all Certificates in the given Namespace, or all namespaces with --all-namespaces enabled.")
+	cmd.Flags().Float32Var(&o.QPS, "qps", defaultQPS, "Maximum number of Certificates to mark for renewal per second, to avoid overwhelming issuers when renewing many Certificates. 0 means no limit.")
+	cmd.Flags().StringVar(&o.Reason, "reason", o.Reason, "The reason for the renewal, which is recorded in the status of the Certificates along with the user that requested it.")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", defaultChunkSize, "Return large lists of Certificates in chunks rather than all at once. Pass 0 to disable.")
 
 	o.Factory = factory.New(ctx, cmd)
 
@@ -112,6 +142,14 @@ func (o *Options) Validate(cmd *cobra.Command, args []string) error {
 		return errors.New("cannot specify --namespace flag in conjunction with --all flag")
 	}
 
+	if o.QPS < 0 {
+		return errors.New("--qps must not be negative")
+	}
+
+	if o.ChunkSize < 0 {
+		return errors.New("--chunk-size must not be negative")
+	}
+
 	return nil
 }
 
@@ -138,18 +176,61 @@ func (o *Options) Complete(f cmdutil.Factory) error {
 
 // Run executes renew command
 func (o *Options) Run(ctx context.Context, args []string) error {
+	crts, err := o.certificates(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if len(crts) == 0 {
+		if o.AllNamespaces {
+			fmt.Fprintln(o.ErrOut, "No Certificates found")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No Certificates found in %s namespace.\n", o.Namespace)
+		}
+
+		return nil
+	}
+
+	limiter := flowcontrol.NewFakeAlwaysRateLimiter()
+	if o.QPS > 0 {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(o.QPS, 1)
+	}
+
+	for i := range crts {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if err := o.renewCertificate(ctx, &crts[i]); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "[%d/%d] Manually triggered issuance of Certificate %s/%s\n", i+1, len(crts), crts[i].Namespace, crts[i].Name)
+	}
+
+	return nil
+}
+
+// certificates returns the Certificates selected by the options and the
+// given Certificate names.
+func (o *Options) certificates(ctx context.Context, args []string) ([]cmapi.Certificate, error) {
+	if o.All || len(o.LabelSelector) > 0 {
+		namespace := o.Namespace
+		if o.AllNamespaces {
+			namespace = metav1.NamespaceAll
+		}
+		return o.listCertificates(ctx, namespace)
+	}
 
 	nss := []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: o.Namespace}}}
 
 	if o.AllNamespaces {
 		kubeClient, err := kubernetes.NewForConfig(o.RESTConfig)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		nsList, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		nss = nsList.Items
@@ -157,54 +238,53 @@ func (o *Options) Run(ctx context.Context, args []string) error {
 
 	var crts []cmapi.Certificate
 	for _, ns := range nss {
-		switch {
-		case o.All, len(o.LabelSelector) > 0:
-			crtsList, err := o.CMClient.CertmanagerV1().Certificates(ns.Name).List(ctx, metav1.ListOptions{
-				LabelSelector: o.LabelSelector,
-			})
+		for _, crtName := range args {
+			crt, err := o.CMClient.CertmanagerV1().Certificates(ns.Name).Get(ctx, crtName, metav1.GetOptions{})
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			crts = append(crts, crtsList.Items...)
-
-		default:
-			for _, crtName := range args {
-				crt, err := o.CMClient.CertmanagerV1().Certificates(ns.Name).Get(ctx, crtName, metav1.GetOptions{})
-				if err != nil {
-					return err
-				}
-
-				crts = append(crts, *crt)
-			}
+			crts = append(crts, *crt)
 		}
 	}
 
-	if len(crts) == 0 {
-		if o.AllNamespaces {
-			fmt.Fprintln(o.ErrOut, "No Certificates found")
-		} else {
-			fmt.Fprintf(o.ErrOut, "No Certificates found in %s namespace.\n", o.Namespace)
-		}
+	return crts, nil
+}
 
-		return nil
+// listCertificates lists the Certificates matching the label selector in the
+// given namespace, in chunks of ChunkSize.
+func (o *Options) listCertificates(ctx context.Context, namespace string) ([]cmapi.Certificate, error) {
+	var crts []cmapi.Certificate
+	opts := metav1.ListOptions{
+		LabelSelector: o.LabelSelector,
+		Limit:         o.ChunkSize,
 	}
+	for {
+		crtsList, err := o.CMClient.CertmanagerV1().Certificates(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, crt := range crts {
-		if err := o.renewCertificate(ctx, &crt); err != nil {
-			return err
+		crts = append(crts, crtsList.Items...)
+
+		if crtsList.Continue == "" {
+			return crts, nil
 		}
+		opts.Continue = crtsList.Continue
 	}
-
-	return nil
 }
 
 func (o *Options) renewCertificate(ctx context.Context, crt *cmapi.Certificate) error {
-	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, "ManuallyTriggered", "Certificate re-issuance manually triggered")
+	message := "Certificate re-issuance manually triggered"
+	if o.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, o.Reason)
+	}
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, cmapi.CertificateReasonManuallyTriggered, message)
+	// The requester and time of the renewal are recorded by the webhook.
+	crt.Status.RenewalRequest = &cmapi.CertificateRenewalRequest{Reason: o.Reason}
 	_, err := o.CMClient.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to trigger issuance of Certificate %s/%s: %v", crt.Namespace, crt.Name, err)
 	}
-	fmt.Fprintf(o.Out, "Manually triggered issuance of Certificate %s/%s\n", crt.Namespace, crt.Name)
 	return nil
 }
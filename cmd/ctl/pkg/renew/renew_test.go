@@ -20,7 +20,16 @@ import (
 	"context"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	coretesting "k8s.io/client-go/testing"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
 type stringFlag struct {
@@ -63,6 +72,13 @@ func TestValidate(t *testing.T) {
 			},
 			expErr: false,
 		},
+		"If a negative QPS is specified, error": {
+			options: &Options{
+				All: true,
+				QPS: -1,
+			},
+			expErr: true,
+		},
 		"If --namespace and --all namespace specified, error": {
 			options: &Options{
 				All: true,
@@ -99,3 +115,70 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestRunAllNamespacesInChunks(t *testing.T) {
+	crt1 := gen.Certificate("crt1", gen.SetCertificateNamespace("ns1"))
+	crt2 := gen.Certificate("crt2", gen.SetCertificateNamespace("ns2"))
+	cmClient := cmfake.NewSimpleClientset(crt1, crt2)
+
+	// Return one Certificate per page to check that all chunks are listed
+	lists := 0
+	cmClient.PrependReactor("list", "certificates", func(action coretesting.Action) (bool, runtime.Object, error) {
+		lists++
+		if action.GetNamespace() != metav1.NamespaceAll {
+			t.Errorf("expected certificates to be listed in all namespaces, got %q", action.GetNamespace())
+		}
+		if lists == 1 {
+			return true, &cmapi.CertificateList{ListMeta: metav1.ListMeta{Continue: "next"}, Items: []cmapi.Certificate{*crt1}}, nil
+		}
+		return true, &cmapi.CertificateList{Items: []cmapi.Certificate{*crt2}}, nil
+	})
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	o := &Options{
+		All:           true,
+		AllNamespaces: true,
+		ChunkSize:     1,
+		QPS:           100,
+		Factory:       &factory.Factory{CMClient: cmClient},
+		IOStreams:     streams,
+	}
+	if err := o.Run(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if lists != 2 {
+		t.Errorf("expected 2 list requests, got %d", lists)
+	}
+	expOut := "[1/2] Manually triggered issuance of Certificate ns1/crt1\n" +
+		"[2/2] Manually triggered issuance of Certificate ns2/crt2\n"
+	if out.String() != expOut {
+		t.Errorf("unexpected output, exp=%q got=%q", expOut, out.String())
+	}
+}
+
+func TestRunWithReason(t *testing.T) {
+	cmClient := cmfake.NewSimpleClientset(gen.Certificate("crt", gen.SetCertificateNamespace("ns")))
+
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	o := &Options{
+		Reason:    "key compromise",
+		Factory:   &factory.Factory{CMClient: cmClient, Namespace: "ns"},
+		IOStreams: streams,
+	}
+	if err := o.Run(context.TODO(), []string{"crt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	crt, err := cmClient.CertmanagerV1().Certificates("ns").Get(context.TODO(), "crt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	if cond == nil || cond.Reason != cmapi.CertificateReasonManuallyTriggered || cond.Message != "Certificate re-issuance manually triggered: key compromise" {
+		t.Errorf("unexpected Issuing condition: %+v", cond)
+	}
+	if crt.Status.RenewalRequest == nil || crt.Status.RenewalRequest.Reason != "key compromise" {
+		t.Errorf("expected the reason to be recorded in the renewal request, got %+v", crt.Status.RenewalRequest)
+	}
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	k8sclock "k8s.io/utils/clock"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Generate a machine-readable report of certificate issuance per namespace.
+
+Every Certificate is listed along with its issuer, validity and readiness.
+Certificates that were issued for the first time, renewed, or that failed to
+be issued within the --since window are marked with the corresponding event,
+and a summary of these counts is included per namespace in the JSON format.
+
+By default the report is printed to stdout. With --output-dir, one file is
+written per namespace whose name contains the time the report was generated,
+so that the command can be run periodically from a CronJob writing to a
+mounted volume such as a PersistentVolumeClaim, or a bucket mounted by a CSI
+driver, without overwriting earlier reports.`))
+
+	example = templates.Examples(i18n.T(build.WithTemplate(`
+# Print a CSV report of Certificates in the current context namespace for the last 24 hours.
+{{.BuildName}} report
+
+# Print a JSON report for all namespaces covering the last 7 days.
+{{.BuildName}} report --all-namespaces --format json --since 168h
+
+# Write one CSV report per namespace into /reports, e.g. from a CronJob with a PersistentVolumeClaim mounted there.
+{{.BuildName}} report -A --output-dir /reports`)))
+)
+
+const (
+	// FormatCSV writes one row per Certificate.
+	FormatCSV = "csv"
+	// FormatJSON writes one report per namespace, including a summary.
+	FormatJSON = "json"
+
+	// EventIssued is reported for Certificates that were issued for the first
+	// time within the report window.
+	EventIssued = "Issued"
+	// EventRenewed is reported for Certificates that were re-issued within
+	// the report window.
+	EventRenewed = "Renewed"
+	// EventFailed is reported for Certificates whose last issuance attempt
+	// failed within the report window.
+	EventFailed = "Failed"
+)
+
+var clock k8sclock.Clock = k8sclock.RealClock{}
+
+var csvHeader = []string{
+	"namespace", "name", "secretName", "issuerKind", "issuerName", "ready",
+	"notBefore", "notAfter", "renewalTime", "revision", "lastFailureTime", "event",
+}
+
+// Options is a struct to support report command
+type Options struct {
+	LabelSelector string
+	AllNamespaces bool
+	Format        string
+	OutputDir     string
+	Since         time.Duration
+
+	genericclioptions.IOStreams
+	*factory.Factory
+}
+
+// NamespaceReport is the report for all Certificates in a single namespace.
+type NamespaceReport struct {
+	Namespace    string              `json:"namespace"`
+	GeneratedAt  metav1.Time         `json:"generatedAt"`
+	Since        metav1.Time         `json:"since"`
+	Summary      Summary             `json:"summary"`
+	Certificates []CertificateReport `json:"certificates"`
+}
+
+// Summary counts the Certificates in a namespace and the issuance events
+// within the report window.
+type Summary struct {
+	Certificates int `json:"certificates"`
+	Ready        int `json:"ready"`
+	Issued       int `json:"issued"`
+	Renewed      int `json:"renewed"`
+	Failed       int `json:"failed"`
+}
+
+// CertificateReport is the reported state of a single Certificate.
+type CertificateReport struct {
+	Name            string       `json:"name"`
+	SecretName      string       `json:"secretName"`
+	IssuerKind      string       `json:"issuerKind"`
+	IssuerName      string       `json:"issuerName"`
+	Ready           bool         `json:"ready"`
+	NotBefore       *metav1.Time `json:"notBefore,omitempty"`
+	NotAfter        *metav1.Time `json:"notAfter,omitempty"`
+	RenewalTime     *metav1.Time `json:"renewalTime,omitempty"`
+	Revision        *int         `json:"revision,omitempty"`
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	Event           string       `json:"event,omitempty"`
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Format:    FormatCSV,
+		Since:     24 * time.Hour,
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdReport returns a cobra command for reporting on certificate issuance
+func NewCmdReport(ctx context.Context, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "report",
+		Short:   "Generate a machine-readable report of certificate issuance per namespace",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, report on Certificates across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringVar(&o.Format, "format", o.Format, "The output format, one of: csv, json.")
+	cmd.Flags().StringVar(&o.OutputDir, "output-dir", o.OutputDir, "If set, one report file per namespace is written to this directory instead of stdout.")
+	cmd.Flags().DurationVar(&o.Since, "since", o.Since, "The window before now in which issuance, renewal and failure events are reported.")
+
+	o.Factory = factory.New(ctx, cmd)
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("report does not accept arguments, use --selector to choose Certificates")
+	}
+
+	switch o.Format {
+	case FormatCSV, FormatJSON:
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of: csv, json", o.Format)
+	}
+
+	if o.Since <= 0 {
+		return errors.New("--since must be a positive duration")
+	}
+
+	return nil
+}
+
+// Run executes report command
+func (o *Options) Run(ctx context.Context) error {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	list, err := o.CMClient.CertmanagerV1().Certificates(namespace).List(ctx, metav1.ListOptions{LabelSelector: o.LabelSelector})
+	if err != nil {
+		return err
+	}
+
+	reports := buildReports(list.Items, clock.Now(), o.Since)
+
+	if len(o.OutputDir) == 0 {
+		return o.write(o.Out, reports)
+	}
+
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		fileName := fmt.Sprintf("%s-%s.%s", report.Namespace, report.GeneratedAt.UTC().Format("20060102T150405Z"), o.Format)
+		path := filepath.Join(o.OutputDir, fileName)
+		if err := o.writeFile(path, report); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Wrote report for namespace %s to %s\n", report.Namespace, path)
+	}
+
+	return nil
+}
+
+func (o *Options) writeFile(path string, report NamespaceReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := o.write(f, []NamespaceReport{report}); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (o *Options) write(w io.Writer, reports []NamespaceReport) error {
+	if o.Format == FormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		for _, crt := range report.Certificates {
+			if err := cw.Write(csvRecord(report.Namespace, crt)); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// buildReports groups the given Certificates by namespace, sorted by
+// namespace and then Certificate name.
+func buildReports(crts []cmapi.Certificate, now time.Time, since time.Duration) []NamespaceReport {
+	sort.Slice(crts, func(i, j int) bool {
+		if crts[i].Namespace != crts[j].Namespace {
+			return crts[i].Namespace < crts[j].Namespace
+		}
+		return crts[i].Name < crts[j].Name
+	})
+
+	windowStart := now.Add(-since)
+
+	var reports []NamespaceReport
+	for i := range crts {
+		crt := &crts[i]
+		if len(reports) == 0 || reports[len(reports)-1].Namespace != crt.Namespace {
+			reports = append(reports, NamespaceReport{
+				Namespace:    crt.Namespace,
+				GeneratedAt:  metav1.NewTime(now),
+				Since:        metav1.NewTime(windowStart),
+				Certificates: []CertificateReport{},
+			})
+		}
+		report := &reports[len(reports)-1]
+
+		crtReport := certificateReport(crt, windowStart)
+		report.Certificates = append(report.Certificates, crtReport)
+
+		report.Summary.Certificates++
+		if crtReport.Ready {
+			report.Summary.Ready++
+		}
+		switch crtReport.Event {
+		case EventIssued:
+			report.Summary.Issued++
+		case EventRenewed:
+			report.Summary.Renewed++
+		case EventFailed:
+			report.Summary.Failed++
+		}
+	}
+
+	return reports
+}
+
+func certificateReport(crt *cmapi.Certificate, windowStart time.Time) CertificateReport {
+	issuerKind := crt.Spec.IssuerRef.Kind
+	if len(issuerKind) == 0 {
+		issuerKind = cmapi.IssuerKind
+	}
+
+	return CertificateReport{
+		Name:       crt.Name,
+		SecretName: crt.Spec.SecretName,
+		IssuerKind: issuerKind,
+		IssuerName: crt.Spec.IssuerRef.Name,
+		Ready: apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+			Type:   cmapi.CertificateConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}),
+		NotBefore:       crt.Status.NotBefore,
+		NotAfter:        crt.Status.NotAfter,
+		RenewalTime:     crt.Status.RenewalTime,
+		Revision:        crt.Status.Revision,
+		LastFailureTime: crt.Status.LastFailureTime,
+		Event:           certificateEvent(crt, windowStart),
+	}
+}
+
+// certificateEvent returns the issuance event of the Certificate within the
+// report window. A failure is reported over a successful issuance, since the
+// last failure time is reset once a Certificate has been issued again.
+func certificateEvent(crt *cmapi.Certificate, windowStart time.Time) string {
+	inWindow := func(t *metav1.Time) bool {
+		return t != nil && !t.Time.Before(windowStart)
+	}
+
+	switch {
+	case inWindow(crt.Status.LastFailureTime):
+		return EventFailed
+	case inWindow(crt.Status.NotBefore) && crt.Status.Revision != nil && *crt.Status.Revision > 1:
+		return EventRenewed
+	case inWindow(crt.Status.NotBefore):
+		return EventIssued
+	default:
+		return ""
+	}
+}
+
+func csvRecord(namespace string, crt CertificateReport) []string {
+	formatTime := func(t *metav1.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	revision := ""
+	if crt.Revision != nil {
+		revision = strconv.Itoa(*crt.Revision)
+	}
+
+	return []string{
+		namespace,
+		crt.Name,
+		crt.SecretName,
+		crt.IssuerKind,
+		crt.IssuerName,
+		strconv.FormatBool(crt.Ready),
+		formatTime(crt.NotBefore),
+		formatTime(crt.NotAfter),
+		formatTime(crt.RenewalTime),
+		revision,
+		formatTime(crt.LastFailureTime),
+		crt.Event,
+	}
+}
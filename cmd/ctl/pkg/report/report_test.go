@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/factory"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"default options are valid": {
+			options: NewOptions(genericclioptions.IOStreams{}),
+		},
+		"arguments are not accepted": {
+			options: NewOptions(genericclioptions.IOStreams{}),
+			args:    []string{"my-cert"},
+			expErr:  true,
+		},
+		"json with an output directory is valid": {
+			options: &Options{Format: FormatJSON, OutputDir: "out", Since: time.Hour},
+		},
+		"unknown format": {
+			options: &Options{Format: "yaml", Since: time.Hour},
+			expErr:  true,
+		},
+		"since must be positive": {
+			options: &Options{Format: FormatCSV},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	clock = fakeclock.NewFakeClock(now)
+
+	timePtr := func(t time.Time) *metav1.Time { mt := metav1.NewTime(t); return &mt }
+	intPtr := func(i int) *int { return &i }
+	ready := []cmapi.CertificateCondition{{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}}
+
+	crts := []*cmapi.Certificate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "renewed", Namespace: "team-a"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "renewed-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca", Kind: cmapi.ClusterIssuerKind},
+			},
+			Status: cmapi.CertificateStatus{
+				Conditions:  ready,
+				NotBefore:   timePtr(now.Add(-time.Hour)),
+				NotAfter:    timePtr(now.Add(90 * 24 * time.Hour)),
+				RenewalTime: timePtr(now.Add(60 * 24 * time.Hour)),
+				Revision:    intPtr(3),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "issued", Namespace: "team-a"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "issued-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+			},
+			Status: cmapi.CertificateStatus{
+				Conditions: ready,
+				NotBefore:  timePtr(now.Add(-2 * time.Hour)),
+				Revision:   intPtr(1),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "team-b"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "failed-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "acme"},
+			},
+			Status: cmapi.CertificateStatus{
+				LastFailureTime: timePtr(now.Add(-30 * time.Minute)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unchanged", Namespace: "team-b"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "unchanged-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "acme"},
+			},
+			Status: cmapi.CertificateStatus{
+				Conditions: ready,
+				NotBefore:  timePtr(now.Add(-48 * time.Hour)),
+				Revision:   intPtr(2),
+			},
+		},
+	}
+
+	newOptions := func(format, outputDir string) (*Options, *bytes.Buffer) {
+		streams, _, out, _ := genericclioptions.NewTestIOStreams()
+		o := NewOptions(streams)
+		o.Format = format
+		o.OutputDir = outputDir
+		o.AllNamespaces = true
+		client := cmfake.NewSimpleClientset()
+		for _, crt := range crts {
+			if err := client.Tracker().Add(crt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		o.Factory = &factory.Factory{CMClient: client}
+		return o, out
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		o, out := newOptions(FormatCSV, "")
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+
+		expOut := `namespace,name,secretName,issuerKind,issuerName,ready,notBefore,notAfter,renewalTime,revision,lastFailureTime,event
+team-a,issued,issued-tls,Issuer,ca,true,2021-10-01T10:00:00Z,,,1,,Issued
+team-a,renewed,renewed-tls,ClusterIssuer,ca,true,2021-10-01T11:00:00Z,2021-12-30T12:00:00Z,2021-11-30T12:00:00Z,3,,Renewed
+team-b,failed,failed-tls,Issuer,acme,false,,,,,2021-10-01T11:30:00Z,Failed
+team-b,unchanged,unchanged-tls,Issuer,acme,true,2021-09-29T12:00:00Z,,,2,,
+`
+		if out.String() != expOut {
+			t.Errorf("unexpected output, exp=\n%s\ngot=\n%s", expOut, out.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		o, out := newOptions(FormatJSON, "")
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+
+		var reports []NamespaceReport
+		if err := json.Unmarshal(out.Bytes(), &reports); err != nil {
+			t.Fatal(err)
+		}
+		expSummaries := map[string]Summary{
+			"team-a": {Certificates: 2, Ready: 2, Issued: 1, Renewed: 1},
+			"team-b": {Certificates: 2, Ready: 1, Failed: 1},
+		}
+		if len(reports) != len(expSummaries) {
+			t.Fatalf("expected %d namespace reports, got %d", len(expSummaries), len(reports))
+		}
+		for _, report := range reports {
+			if report.Summary != expSummaries[report.Namespace] {
+				t.Errorf("unexpected summary for namespace %s, exp=%+v got=%+v", report.Namespace, expSummaries[report.Namespace], report.Summary)
+			}
+			if !report.Since.Time.Equal(now.Add(-24 * time.Hour)) {
+				t.Errorf("unexpected report window start: %s", report.Since)
+			}
+		}
+	})
+
+	t.Run("output-dir", func(t *testing.T) {
+		dir := t.TempDir()
+		o, _ := newOptions(FormatCSV, dir)
+		if err := o.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, f := range []string{"team-a-20211001T120000Z.csv", "team-b-20211001T120000Z.csv"} {
+			if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+				t.Errorf("expected report file %s to be written: %v", f, err)
+			}
+		}
+	})
+}
@@ -48,6 +48,9 @@ Get details about the current status of a cert-manager Certificate resource, inc
 	example = templates.Examples(i18n.T(build.WithTemplate(`
 # Query status of Certificate with name 'my-crt' in namespace 'my-namespace'
 {{.BuildName}} status certificate my-crt --namespace my-namespace
+
+# Print a diagnosis tree of Certificate 'my-crt' and its related resources, with hints for common failures
+{{.BuildName}} status certificate my-crt --namespace my-namespace --tree
 `)))
 )
 
@@ -55,6 +58,10 @@ Get details about the current status of a cert-manager Certificate resource, inc
 type Options struct {
 	genericclioptions.IOStreams
 	*factory.Factory
+
+	// Tree prints the related resources as a tree, followed by remediation
+	// hints for any problems found
+	Tree bool
 }
 
 // Data is a struct containing the information to build a CertificateStatus
@@ -73,8 +80,11 @@ type Data struct {
 	ReqEvents    *corev1.EventList
 	Order        *cmacme.Order
 	OrderError   error
+	OrderEvents  *corev1.EventList
 	Challenges   []*cmacme.Challenge
 	ChallengeErr error
+	// ChallengeEvents holds the events of each Challenge, keyed by name
+	ChallengeEvents map[string]*corev1.EventList
 }
 
 // NewOptions returns initialized Options
@@ -100,6 +110,9 @@ func NewCmdStatusCert(ctx context.Context, ioStreams genericclioptions.IOStreams
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.Tree, "tree", o.Tree, "Print the Certificate and its related CertificateRequest, Order, Challenges and Issuer as a tree, "+
+		"followed by hints on how to resolve common failures")
+
 	o.Factory = factory.New(ctx, cmd)
 
 	return cmd
@@ -123,6 +136,11 @@ func (o *Options) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
+	if o.Tree {
+		fmt.Fprint(o.Out, TreeFromResources(data, time.Now()))
+		return nil
+	}
+
 	// Build status of Certificate with data gathered
 	status := StatusFromResources(data)
 
@@ -210,10 +228,12 @@ func (o *Options) GetResources(ctx context.Context, crtName string) (*Data, erro
 	}
 
 	var (
-		order        *cmacme.Order
-		orderErr     error
-		challenges   []*cmacme.Challenge
-		challengeErr error
+		order           *cmacme.Order
+		orderErr        error
+		orderEvents     *corev1.EventList
+		challenges      []*cmacme.Challenge
+		challengeErr    error
+		challengeEvents map[string]*corev1.EventList
 	)
 
 	// Nothing to output about Order and Challenge if no CR or not ACME Issuer
@@ -227,12 +247,34 @@ func (o *Options) GetResources(ctx context.Context, crtName string) (*Data, erro
 		}
 
 		if order != nil {
+			orderRef, err := reference.GetReference(ctl.Scheme, order)
+			if err != nil {
+				return nil, err
+			}
+			// If no events found, orderEvents would be nil and handled down the line in DescribeEvents
+			orderEvents, err = clientSet.CoreV1().Events(order.Namespace).Search(ctl.Scheme, orderRef)
+			if err != nil {
+				return nil, err
+			}
+
 			challenges, challengeErr = findMatchingChallenges(o.CMClient, ctx, order)
 			if challengeErr != nil {
 				challengeErr = fmt.Errorf("error when finding Challenges: %w\n", challengeErr)
 			} else if len(challenges) == 0 {
 				challengeErr = errors.New("No Challenges found for this Certificate\n")
 			}
+
+			challengeEvents = make(map[string]*corev1.EventList, len(challenges))
+			for _, ch := range challenges {
+				chRef, err := reference.GetReference(ctl.Scheme, ch)
+				if err != nil {
+					return nil, err
+				}
+				challengeEvents[ch.Name], err = clientSet.CoreV1().Events(ch.Namespace).Search(ctl.Scheme, chRef)
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 
@@ -251,8 +293,11 @@ func (o *Options) GetResources(ctx context.Context, crtName string) (*Data, erro
 		ReqEvents:    reqEvents,
 		Order:        order,
 		OrderError:   orderErr,
+		OrderEvents:  orderEvents,
 		Challenges:   challenges,
 		ChallengeErr: challengeErr,
+
+		ChallengeEvents: challengeEvents,
 	}, nil
 }
 
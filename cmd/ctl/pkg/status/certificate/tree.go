@@ -0,0 +1,392 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/build"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// maxTreeEvents is the number of most recent events printed for each
+// resource in the tree.
+const maxTreeEvents = 3
+
+// treeNode is a single resource printed in the diagnosis tree.
+type treeNode struct {
+	text     string
+	details  []string
+	children []*treeNode
+}
+
+// Finding is a problem detected with the Certificate or one of its related
+// resources, along with a hint on how to resolve it.
+type Finding struct {
+	// Problem describes what is wrong
+	Problem string
+	// Hint suggests how the problem may be resolved
+	Hint string
+}
+
+// TreeFromResources renders the Certificate and its related resources in data
+// as a tree, followed by the Findings returned by Diagnose. now is used to
+// print the age of events.
+func TreeFromResources(data *Data, now time.Time) string {
+	var b strings.Builder
+	certificateNode(data, now).write(&b, "", "")
+
+	b.WriteString("\nDiagnosis:\n")
+	findings := Diagnose(data)
+	if len(findings) == 0 {
+		b.WriteString("  No problems found.\n")
+	}
+	for _, f := range findings {
+		fmt.Fprintf(&b, "  - %s\n    Hint: %s\n", f.Problem, f.Hint)
+	}
+
+	return b.String()
+}
+
+// write prints the node to b, indenting its details and children so that they
+// hang off the node's branch.
+func (n *treeNode) write(b *strings.Builder, linePrefix, childPrefix string) {
+	fmt.Fprintf(b, "%s%s\n", linePrefix, n.text)
+
+	detailPrefix := childPrefix + "    "
+	if len(n.children) > 0 {
+		detailPrefix = childPrefix + "│   "
+	}
+	for _, d := range n.details {
+		fmt.Fprintf(b, "%s%s\n", detailPrefix, d)
+	}
+
+	for i, c := range n.children {
+		if i == len(n.children)-1 {
+			c.write(b, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			c.write(b, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func certificateNode(data *Data, now time.Time) *treeNode {
+	crt := data.Certificate
+	node := &treeNode{text: fmt.Sprintf("Certificate %s/%s", crt.Namespace, crt.Name)}
+	for _, c := range crt.Status.Conditions {
+		node.details = append(node.details, conditionDetail(string(c.Type), c.Status, c.Reason, c.Message))
+	}
+	node.details = append(node.details, eventDetails(data.CrtEvents, now)...)
+
+	node.children = append(node.children, issuerNode(data, now), secretNode(data, now), requestNode(data, now))
+	return node
+}
+
+func issuerNode(data *Data, now time.Time) *treeNode {
+	kind := data.IssuerKind
+	if kind == "" {
+		kind = data.Certificate.Spec.IssuerRef.Kind
+	}
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	node := &treeNode{text: fmt.Sprintf("%s %s", kind, data.Certificate.Spec.IssuerRef.Name)}
+	if data.IssuerError != nil || data.Issuer == nil {
+		node.details = errorDetail(data.IssuerError)
+		return node
+	}
+
+	for _, c := range data.Issuer.GetStatus().Conditions {
+		node.details = append(node.details, conditionDetail(string(c.Type), c.Status, c.Reason, c.Message))
+	}
+	node.details = append(node.details, eventDetails(data.IssuerEvents, now)...)
+	return node
+}
+
+func secretNode(data *Data, now time.Time) *treeNode {
+	node := &treeNode{text: fmt.Sprintf("Secret %s", data.Certificate.Spec.SecretName)}
+	if data.SecretError != nil || data.Secret == nil {
+		node.details = errorDetail(data.SecretError)
+		return node
+	}
+	node.details = eventDetails(data.SecretEvents, now)
+	return node
+}
+
+func requestNode(data *Data, now time.Time) *treeNode {
+	if data.ReqError != nil || data.Req == nil {
+		return &treeNode{text: "CertificateRequest", details: errorDetail(data.ReqError)}
+	}
+
+	node := &treeNode{text: fmt.Sprintf("CertificateRequest %s", data.Req.Name)}
+	for _, c := range data.Req.Status.Conditions {
+		node.details = append(node.details, conditionDetail(string(c.Type), c.Status, c.Reason, c.Message))
+	}
+	node.details = append(node.details, eventDetails(data.ReqEvents, now)...)
+
+	// Orders are only looked up for ACME issuers
+	if data.Order != nil || data.OrderError != nil {
+		node.children = append(node.children, orderNode(data, now))
+	}
+	return node
+}
+
+func orderNode(data *Data, now time.Time) *treeNode {
+	if data.OrderError != nil || data.Order == nil {
+		return &treeNode{text: "Order", details: errorDetail(data.OrderError)}
+	}
+
+	order := data.Order
+	node := &treeNode{text: fmt.Sprintf("Order %s", order.Name)}
+	node.details = append(node.details, stateDetail(order.Status.State, order.Status.Reason))
+	node.details = append(node.details, eventDetails(data.OrderEvents, now)...)
+
+	if data.ChallengeErr != nil {
+		node.children = append(node.children, &treeNode{text: "Challenges", details: errorDetail(data.ChallengeErr)})
+		return node
+	}
+	for _, ch := range data.Challenges {
+		child := &treeNode{
+			text: fmt.Sprintf("Challenge %s (%s %s)", ch.Name, ch.Spec.Type, ch.Spec.DNSName),
+			details: []string{
+				stateDetail(ch.Status.State, ch.Status.Reason),
+				fmt.Sprintf("Presented: %t, Processing: %t", ch.Status.Presented, ch.Status.Processing),
+			},
+		}
+		child.details = append(child.details, eventDetails(data.ChallengeEvents[ch.Name], now)...)
+		node.children = append(node.children, child)
+	}
+	return node
+}
+
+func conditionDetail(conditionType string, status cmmeta.ConditionStatus, reason, message string) string {
+	s := fmt.Sprintf("%s: %s", conditionType, status)
+	if reason != "" {
+		s += fmt.Sprintf(" (%s)", reason)
+	}
+	if message != "" {
+		s += ": " + message
+	}
+	return s
+}
+
+func stateDetail(state cmacme.State, reason string) string {
+	if state == "" {
+		state = "<none>"
+	}
+	if reason == "" {
+		return fmt.Sprintf("State: %s", state)
+	}
+	return fmt.Sprintf("State: %s, Reason: %s", state, reason)
+}
+
+func errorDetail(err error) []string {
+	if err == nil {
+		return []string{"Error: not found"}
+	}
+	return []string{"Error: " + strings.TrimSpace(err.Error())}
+}
+
+// eventDetails returns the most recent events in the list, newest first.
+func eventDetails(events *corev1.EventList, now time.Time) []string {
+	if events == nil || len(events.Items) == 0 {
+		return nil
+	}
+
+	items := make([]corev1.Event, len(events.Items))
+	copy(items, events.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return eventTime(items[i]).After(eventTime(items[j]))
+	})
+	if len(items) > maxTreeEvents {
+		items = items[:maxTreeEvents]
+	}
+
+	var details []string
+	for _, e := range items {
+		age := "<unknown>"
+		if t := eventTime(e); !t.IsZero() {
+			age = duration.HumanDuration(now.Sub(t)) + " ago"
+		}
+		details = append(details, fmt.Sprintf("Event: %s %s (%s): %s", e.Type, e.Reason, age, strings.TrimSpace(e.Message)))
+	}
+	return details
+}
+
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.FirstTimestamp.Time
+	}
+}
+
+// Diagnose inspects the resources in data for common failure patterns and
+// returns a Finding for each problem detected, ordered from the Issuer down
+// to the Challenges.
+func Diagnose(data *Data) []Finding {
+	var findings []Finding
+	findings = append(findings, diagnoseIssuer(data)...)
+	findings = append(findings, diagnoseSecret(data)...)
+	findings = append(findings, diagnoseRequest(data)...)
+	findings = append(findings, diagnoseOrder(data)...)
+	findings = append(findings, diagnoseChallenges(data)...)
+	return findings
+}
+
+func diagnoseIssuer(data *Data) []Finding {
+	ref := data.Certificate.Spec.IssuerRef
+	if data.IssuerError != nil {
+		if apierrors.IsNotFound(data.IssuerError) {
+			return []Finding{{
+				Problem: fmt.Sprintf("The issuer %q referenced by the Certificate does not exist", ref.Name),
+				Hint:    "Create the issuer, or correct spec.issuerRef on the Certificate.",
+			}}
+		}
+		return nil
+	}
+	if data.Issuer == nil {
+		return nil
+	}
+
+	if !apiutil.IssuerHasCondition(data.Issuer, cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}) {
+		problem := fmt.Sprintf("%s %q is not ready", data.IssuerKind, data.Issuer.GetObjectMeta().Name)
+		for _, c := range data.Issuer.GetStatus().Conditions {
+			if c.Type == cmapi.IssuerConditionReady && c.Message != "" {
+				problem += ": " + c.Message
+			}
+		}
+		return []Finding{{
+			Problem: problem,
+			Hint:    fmt.Sprintf("Fix the %s configuration; no certificates will be issued until it is Ready. Run 'kubectl describe %s %s' for details.", data.IssuerKind, strings.ToLower(data.IssuerKind), data.Issuer.GetObjectMeta().Name),
+		}}
+	}
+	return nil
+}
+
+func diagnoseSecret(data *Data) []Finding {
+	if data.SecretError == nil || !apierrors.IsNotFound(data.SecretError) {
+		return nil
+	}
+
+	f := Finding{Problem: fmt.Sprintf("The Secret %q does not exist", data.Certificate.Spec.SecretName)}
+	if apiutil.CertificateHasCondition(data.Certificate, cmapi.CertificateCondition{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}) {
+		f.Hint = "The Secret was removed after the certificate was issued; cert-manager will re-issue the certificate into a new Secret."
+	} else {
+		f.Hint = "The Secret is created once the certificate has been issued; see the CertificateRequest for why issuance has not completed."
+	}
+	return []Finding{f}
+}
+
+func diagnoseRequest(data *Data) []Finding {
+	req := data.Req
+	if req == nil {
+		return nil
+	}
+
+	switch {
+	case apiutil.CertificateRequestIsDenied(req):
+		cond := apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionDenied)
+		return []Finding{{
+			Problem: fmt.Sprintf("CertificateRequest %q was denied: %s", req.Name, cond.Message),
+			Hint: fmt.Sprintf("Review the approval policy that applies to this issuer, then request a new certificate with '%s renew %s -n %s'.",
+				build.Name(), data.Certificate.Name, data.Certificate.Namespace),
+		}}
+	case apiutil.CertificateRequestHasInvalidRequest(req):
+		return []Finding{{
+			Problem: fmt.Sprintf("CertificateRequest %q was rejected by the issuer: %s", req.Name, apiutil.CertificateRequestInvalidRequestMessage(req)),
+			Hint:    "Correct the Certificate spec so that the issuer accepts it; a new CertificateRequest is created when the spec changes.",
+		}}
+	case !apiutil.CertificateRequestIsApproved(req):
+		return []Finding{{
+			Problem: fmt.Sprintf("CertificateRequest %q has not been approved", req.Name),
+			Hint: fmt.Sprintf("Check that an approver is running for this issuer, or approve the request manually with '%s approve %s -n %s'.",
+				build.Name(), req.Name, req.Namespace),
+		}}
+	case apiutil.CertificateRequestReadyReason(req) == cmapi.CertificateRequestReasonFailed:
+		cond := apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady)
+		return []Finding{{
+			Problem: fmt.Sprintf("CertificateRequest %q failed: %s", req.Name, cond.Message),
+			Hint:    "cert-manager retries failed issuance with an exponential backoff; check the issuer and the events above for the cause.",
+		}}
+	}
+	return nil
+}
+
+func diagnoseOrder(data *Data) []Finding {
+	order := data.Order
+	if order == nil {
+		return nil
+	}
+
+	switch order.Status.State {
+	case cmacme.Invalid, cmacme.Errored, cmacme.Expired:
+		return []Finding{{
+			Problem: fmt.Sprintf("Order %q is %s: %s", order.Name, order.Status.State, order.Status.Reason),
+			Hint:    "A new Order is created after a backoff; check the Challenges for the cause of the failure.",
+		}}
+	}
+	return nil
+}
+
+func diagnoseChallenges(data *Data) []Finding {
+	var findings []Finding
+	for _, ch := range data.Challenges {
+		switch ch.Status.State {
+		case cmacme.Invalid, cmacme.Errored, cmacme.Expired:
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("%s challenge %q for %q failed: %s", ch.Spec.Type, ch.Name, ch.Spec.DNSName, ch.Status.Reason),
+				Hint:    "The ACME server could not validate the challenge; check the solver configuration and the challenge events.",
+			})
+			continue
+		case cmacme.Valid:
+			continue
+		}
+
+		if !strings.Contains(ch.Status.Reason, "propagation") {
+			continue
+		}
+		switch ch.Spec.Type {
+		case cmacme.ACMEChallengeTypeDNS01:
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("DNS-01 challenge %q is waiting for the TXT record for %q to propagate", ch.Name, ch.Spec.DNSName),
+				Hint: fmt.Sprintf("Check that the TXT record _acme-challenge.%s is visible from public resolvers. Propagation may take several minutes; "+
+					"if the record never appears, check the DNS provider credentials and the --dns01-recursive-nameservers controller flag.", ch.Spec.DNSName),
+			})
+		case cmacme.ACMEChallengeTypeHTTP01:
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("HTTP-01 challenge %q is waiting for the solver for %q to become reachable", ch.Name, ch.Spec.DNSName),
+				Hint: fmt.Sprintf("Check that http://%s/.well-known/acme-challenge/%s is reachable and routed to the solver pod by the Ingress or Gateway.",
+					ch.Spec.DNSName, ch.Spec.Token),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestDiagnose(t *testing.T) {
+	ns := "ns1"
+	crt := gen.Certificate("test-crt",
+		gen.SetCertificateNamespace(ns),
+		gen.SetCertificateSecretName("test-tls"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: "Issuer"}),
+	)
+	readyIssuer := gen.Issuer("test-issuer", gen.AddIssuerCondition(cmapi.IssuerCondition{
+		Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue,
+	}))
+	approved := cmapi.CertificateRequestCondition{Type: cmapi.CertificateRequestConditionApproved, Status: cmmeta.ConditionTrue}
+	secretNotFound := apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "test-tls")
+
+	tests := map[string]struct {
+		data *Data
+		exp  []string
+	}{
+		"no problems found for a ready Issuer and approved request": {
+			data: &Data{
+				Certificate: crt,
+				Issuer:      readyIssuer,
+				IssuerKind:  "Issuer",
+				Req: gen.CertificateRequest("test-req", gen.SetCertificateRequestNamespace(ns),
+					gen.AddCertificateRequestStatusCondition(approved)),
+			},
+			exp: nil,
+		},
+		"Issuer that is not found is reported": {
+			data: &Data{
+				Certificate: crt,
+				IssuerError: apierrors.NewNotFound(schema.GroupResource{Resource: "issuers"}, "test-issuer"),
+			},
+			exp: []string{`The issuer "test-issuer" referenced by the Certificate does not exist`},
+		},
+		"Issuer that is not ready is reported with its message": {
+			data: &Data{
+				Certificate: crt,
+				Issuer: gen.Issuer("test-issuer", gen.AddIssuerCondition(cmapi.IssuerCondition{
+					Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionFalse, Message: "secret not found",
+				})),
+				IssuerKind: "Issuer",
+			},
+			exp: []string{`Issuer "test-issuer" is not ready: secret not found`},
+		},
+		"missing Secret is reported": {
+			data: &Data{
+				Certificate: crt,
+				Issuer:      readyIssuer,
+				IssuerKind:  "Issuer",
+				SecretError: secretNotFound,
+			},
+			exp: []string{`The Secret "test-tls" does not exist`},
+		},
+		"denied request is reported": {
+			data: &Data{
+				Certificate: crt,
+				Issuer:      readyIssuer,
+				IssuerKind:  "Issuer",
+				Req: gen.CertificateRequest("test-req", gen.SetCertificateRequestNamespace(ns),
+					gen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type: cmapi.CertificateRequestConditionDenied, Status: cmmeta.ConditionTrue, Message: "not allowed",
+					})),
+			},
+			exp: []string{`CertificateRequest "test-req" was denied: not allowed`},
+		},
+		"request waiting for approval is reported": {
+			data: &Data{
+				Certificate: crt,
+				Issuer:      readyIssuer,
+				IssuerKind:  "Issuer",
+				Req:         gen.CertificateRequest("test-req", gen.SetCertificateRequestNamespace(ns)),
+			},
+			exp: []string{`CertificateRequest "test-req" has not been approved`},
+		},
+		"pending DNS propagation and failed Order are reported": {
+			data: &Data{
+				Certificate: crt,
+				Issuer:      readyIssuer,
+				IssuerKind:  "Issuer",
+				Req: gen.CertificateRequest("test-req", gen.SetCertificateRequestNamespace(ns),
+					gen.AddCertificateRequestStatusCondition(approved)),
+				Order: &cmacme.Order{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-order", Namespace: ns},
+					Status:     cmacme.OrderStatus{State: cmacme.Errored, Reason: "boom"},
+				},
+				Challenges: []*cmacme.Challenge{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-challenge1", Namespace: ns},
+						Spec:       cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeDNS01, DNSName: "example.com"},
+						Status: cmacme.ChallengeStatus{
+							State:     cmacme.Pending,
+							Presented: true,
+							Reason:    "Waiting for DNS-01 challenge propagation: DNS record for \"example.com\" not yet propagated",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-challenge2", Namespace: ns},
+						Spec:       cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeDNS01, DNSName: "www.example.com"},
+						Status:     cmacme.ChallengeStatus{State: cmacme.Valid},
+					},
+				},
+			},
+			exp: []string{
+				`Order "test-order" is errored: boom`,
+				`DNS-01 challenge "test-challenge1" is waiting for the TXT record for "example.com" to propagate`,
+			},
+		},
+		"errors other than not found are not diagnosed": {
+			data: &Data{
+				Certificate: crt,
+				IssuerError: errors.New("forbidden"),
+				SecretError: errors.New("forbidden"),
+				ReqError:    errors.New("forbidden"),
+			},
+			exp: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var problems []string
+			for _, f := range Diagnose(test.data) {
+				assert.NotEmpty(t, f.Hint)
+				problems = append(problems, f.Problem)
+			}
+			assert.Equal(t, test.exp, problems)
+		})
+	}
+}
+
+func TestTreeFromResources(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	ns := "ns1"
+
+	data := &Data{
+		Certificate: gen.Certificate("test-crt",
+			gen.SetCertificateNamespace(ns),
+			gen.SetCertificateSecretName("test-tls"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: "Issuer"}),
+			gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+				Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionFalse, Reason: "DoesNotExist", Message: "Issuing certificate",
+			}),
+		),
+		Issuer: gen.Issuer("test-issuer", gen.AddIssuerCondition(cmapi.IssuerCondition{
+			Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue, Reason: "ACMEAccountRegistered",
+		})),
+		IssuerKind:  "Issuer",
+		SecretError: apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "test-tls"),
+		Req: gen.CertificateRequest("test-req",
+			gen.SetCertificateRequestNamespace(ns),
+			gen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+				Type: cmapi.CertificateRequestConditionApproved, Status: cmmeta.ConditionTrue,
+			}),
+		),
+		ReqEvents: &corev1.EventList{Items: []corev1.Event{
+			{Type: "Normal", Reason: "OrderCreated", Message: "Created Order resource",
+				LastTimestamp: metav1.NewTime(now.Add(-5 * time.Minute))},
+			{Type: "Normal", Reason: "cert-manager.io", Message: "Certificate request has been approved",
+				LastTimestamp: metav1.NewTime(now.Add(-6 * time.Minute))},
+		}},
+		Order: &cmacme.Order{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-order", Namespace: ns},
+			Status:     cmacme.OrderStatus{State: cmacme.Pending},
+		},
+		Challenges: []*cmacme.Challenge{{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-challenge", Namespace: ns},
+			Spec:       cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeDNS01, DNSName: "example.com"},
+			Status: cmacme.ChallengeStatus{
+				State:     cmacme.Pending,
+				Presented: true,
+				Reason:    "Waiting for DNS-01 challenge propagation",
+			},
+		}},
+	}
+
+	exp := `Certificate ns1/test-crt
+│   Ready: False (DoesNotExist): Issuing certificate
+├── Issuer test-issuer
+│       Ready: True (ACMEAccountRegistered)
+├── Secret test-tls
+│       Error: secrets "test-tls" not found
+└── CertificateRequest test-req
+    │   Approved: True
+    │   Event: Normal OrderCreated (5m ago): Created Order resource
+    │   Event: Normal cert-manager.io (6m ago): Certificate request has been approved
+    └── Order test-order
+        │   State: pending
+        └── Challenge test-challenge (DNS-01 example.com)
+                State: pending, Reason: Waiting for DNS-01 challenge propagation
+                Presented: true, Processing: false
+
+Diagnosis:
+  - The Secret "test-tls" does not exist
+    Hint: The Secret is created once the certificate has been issued; see the CertificateRequest for why issuance has not completed.
+  - DNS-01 challenge "test-challenge" is waiting for the TXT record for "example.com" to propagate
+    Hint: Check that the TXT record _acme-challenge.example.com is visible from public resolvers. Propagation may take several minutes; if the record never appears, check the DNS provider credentials and the --dns01-recursive-nameservers controller flag.
+`
+	assert.Equal(t, exp, TreeFromResources(data, now))
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/istioca"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/spiffe"
+	servertls "github.com/jetstack/cert-manager/pkg/webhook/server/tls"
+)
+
+const (
+	defaultListenAddress        = "0.0.0.0:6443"
+	defaultCertificateNamespace = "istio-system"
+	defaultTrustDomain          = "cluster.local"
+	defaultMaxDuration          = 24 * time.Hour
+)
+
+// IstioCAOptions are the options of the Istio CA.
+type IstioCAOptions struct {
+	APIServerHost string
+	Kubeconfig    string
+
+	ListenAddress string
+	TLSCertFile   string
+	TLSKeyFile    string
+
+	CertificateNamespace        string
+	IssuerName                  string
+	IssuerKind                  string
+	IssuerGroup                 string
+	TrustDomain                 string
+	Audiences                   []string
+	MaxDuration                 time.Duration
+	PreserveCertificateRequests bool
+}
+
+func (o *IstioCAOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.APIServerHost, "master", "", ""+
+		"Optional apiserver host address to connect to. If not specified, autoconfiguration "+
+		"will be attempted.")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", ""+
+		"Paths to a kubeconfig. Only required if out-of-cluster.")
+	fs.StringVar(&o.ListenAddress, "listen-address", defaultListenAddress, ""+
+		"The host and port that the Istio CA gRPC service should listen on.")
+	fs.StringVar(&o.TLSCertFile, "tls-cert-file", "", ""+
+		"Path to the file containing the TLS certificate to serve with. "+
+		"The file is reloaded when it changes.")
+	fs.StringVar(&o.TLSKeyFile, "tls-private-key-file", "", ""+
+		"Path to the file containing the TLS private key to serve with. "+
+		"The file is reloaded when it changes.")
+	fs.StringVar(&o.CertificateNamespace, "certificate-namespace", defaultCertificateNamespace, ""+
+		"Namespace that CertificateRequests for workload certificates are created in. "+
+		"If issuer-kind is Issuer, the issuer must be in this namespace.")
+	fs.StringVar(&o.IssuerName, "issuer-name", "", ""+
+		"Name of the issuer that signs workload certificates.")
+	fs.StringVar(&o.IssuerKind, "issuer-kind", cmapi.IssuerKind, ""+
+		"Kind of the issuer that signs workload certificates.")
+	fs.StringVar(&o.IssuerGroup, "issuer-group", cmapi.SchemeGroupVersion.Group, ""+
+		"Group of the issuer that signs workload certificates.")
+	fs.StringVar(&o.TrustDomain, "trust-domain", defaultTrustDomain, ""+
+		"The trust domain of the mesh. Workloads are issued certificates for the SPIFFE "+
+		"identity spiffe://<trust domain>/ns/<namespace>/sa/<service account>.")
+	fs.StringSliceVar(&o.Audiences, "audiences", []string{"istio-ca"}, ""+
+		"The audiences that workload service account tokens must be valid for. "+
+		"The API server's default audiences are used if empty.")
+	fs.DurationVar(&o.MaxDuration, "max-duration", defaultMaxDuration, ""+
+		"The maximum duration of workload certificates. Also used for workloads that "+
+		"do not request a duration.")
+	fs.BoolVar(&o.PreserveCertificateRequests, "preserve-certificate-requests", false, ""+
+		"If true, CertificateRequests are not deleted once they have been signed or have failed.")
+}
+
+func (o *IstioCAOptions) Validate() error {
+	if len(o.TLSCertFile) == 0 || len(o.TLSKeyFile) == 0 {
+		return fmt.Errorf("both --tls-cert-file and --tls-private-key-file must be set")
+	}
+	if len(o.IssuerName) == 0 {
+		return fmt.Errorf("--issuer-name must be set")
+	}
+	if err := spiffe.ValidateTrustDomain(o.TrustDomain); err != nil {
+		return fmt.Errorf("invalid --trust-domain: %w", err)
+	}
+	if o.MaxDuration < cmapi.MinimumCertificateDuration {
+		return fmt.Errorf("invalid max duration %s: must be at least %s", o.MaxDuration, cmapi.MinimumCertificateDuration)
+	}
+	return nil
+}
+
+func NewIstioCACommand(ctx context.Context) *cobra.Command {
+	o := &IstioCAOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "istioca",
+		Short: fmt.Sprintf("Istio CA backed by cert-manager issuers (%s) (%s)", util.AppVersion, util.AppGitCommit),
+		Long: `
+The cert-manager Istio CA serves the Istio CA (IstioCertificateService) gRPC
+API, so that the certificates of workloads in an Istio service mesh are signed
+by a cert-manager issuer instead of istiod's built-in CA. Workloads
+authenticate with their service account token, and are issued certificates for
+the SPIFFE identity of their service account.
+
+Configure Istio to use it by setting the CA_ADDR of workload proxies to the
+address of this service, and disabling istiod's built-in CA.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			logf.V(logf.InfoLevel).InfoS("starting", "version", util.AppVersion, "revision", util.AppGitCommit)
+			return o.Run(ctx)
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run starts the Istio CA and blocks until the context is cancelled.
+func (o *IstioCAOptions) Run(ctx context.Context) error {
+	log := logf.FromContext(ctx, "istioca")
+
+	kubeCfg, err := clientcmd.BuildConfigFromFlags(o.APIServerHost, o.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error creating rest config: %v", err)
+	}
+	kubeCfg = rest.AddUserAgent(kubeCfg, util.CertManagerUserAgent)
+
+	intcl, err := clientset.NewForConfig(kubeCfg)
+	if err != nil {
+		return fmt.Errorf("error creating internal group client: %v", err)
+	}
+	cl, err := kubernetes.NewForConfig(kubeCfg)
+	if err != nil {
+		return fmt.Errorf("error creating kubernetes client: %v", err)
+	}
+
+	source := &servertls.FileCertificateSource{
+		CertPath: o.TLSCertFile,
+		KeyPath:  o.TLSKeyFile,
+		Log:      log,
+	}
+	sourceErrCh := make(chan error, 1)
+	go func() {
+		sourceErrCh <- source.Run(ctx.Done())
+	}()
+
+	server := istioca.NewGRPCServer(&istioca.Server{
+		KubeClient: cl,
+		CMClient:   intcl,
+		Namespace:  o.CertificateNamespace,
+		IssuerRef: cmmeta.ObjectReference{
+			Name:  o.IssuerName,
+			Kind:  o.IssuerKind,
+			Group: o.IssuerGroup,
+		},
+		TrustDomain:                 o.TrustDomain,
+		Audiences:                   o.Audiences,
+		MaxDuration:                 o.MaxDuration,
+		PreserveCertificateRequests: o.PreserveCertificateRequests,
+		Log:                         log,
+	}, grpc.Creds(credentials.NewTLS(&tls.Config{
+		GetCertificate: source.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	})))
+
+	ln, err := net.Listen("tcp", o.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", o.ListenAddress, err)
+	}
+
+	var sourceErr error
+	completedCh := make(chan struct{})
+	go func() {
+		defer close(completedCh)
+		select {
+		case <-ctx.Done():
+		case sourceErr = <-sourceErrCh:
+		}
+		server.GracefulStop()
+	}()
+
+	log.V(logf.InfoLevel).Info("starting Istio CA", "address", ln.Addr())
+	if err := server.Serve(ln); err != nil {
+		return err
+	}
+	<-completedCh
+
+	if sourceErr != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", sourceErr)
+	}
+	return nil
+}
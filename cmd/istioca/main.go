@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/jetstack/cert-manager/cmd/istioca/app"
+	"github.com/jetstack/cert-manager/cmd/util"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// istioca serves the Istio CA API, signing the certificates of workloads in an
+// Istio service mesh using a cert-manager issuer. It is an optional component
+// that is intended to run as a pod in the target kubernetes cluster.
+
+func main() {
+	stopCh, exit := util.SetupExitHandler(util.GracefulShutdown)
+	defer exit() // This function might call os.Exit, so defer last
+
+	logf.InitLogs(flag.CommandLine)
+	defer logf.FlushLogs()
+
+	ctx := util.ContextWithStopCh(context.Background(), stopCh)
+
+	cmd := app.NewIstioCACommand(ctx)
+	cmd.Flags().AddGoFlagSet(flag.CommandLine)
+
+	flag.CommandLine.Parse([]string{})
+	if err := cmd.Execute(); err != nil {
+		cmd.PrintErrln(err)
+		util.SetExitCode(err)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/clock"
+
+	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/ocspresponder"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+const (
+	defaultListenAddress            = "0.0.0.0:8080"
+	defaultClusterResourceNamespace = "kube-system"
+	defaultResponseValidity         = time.Hour
+	resyncPeriod                    = 10 * time.Hour
+)
+
+// OCSPResponderOptions are the options of the OCSP responder.
+type OCSPResponderOptions struct {
+	APIServerHost string
+	Kubeconfig    string
+
+	// Namespace limits the responder to CA Issuers in a single namespace.
+	// ClusterIssuers are not served if set.
+	Namespace                string
+	ClusterResourceNamespace string
+
+	ListenAddress    string
+	ResponseValidity time.Duration
+}
+
+func (o *OCSPResponderOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.APIServerHost, "master", "", ""+
+		"Optional apiserver host address to connect to. If not specified, autoconfiguration "+
+		"will be attempted.")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", ""+
+		"Paths to a kubeconfig. Only required if out-of-cluster.")
+	fs.StringVar(&o.Namespace, "namespace", "", ""+
+		"If set, this limits the scope of the OCSP responder to CA Issuers in a single namespace. "+
+		"ClusterIssuers are not served if set.")
+	fs.StringVar(&o.ClusterResourceNamespace, "cluster-resource-namespace", defaultClusterResourceNamespace, ""+
+		"Namespace to store resources owned by cluster scoped resources such as ClusterIssuer in. "+
+		"This must be the same namespace that the cert-manager controller is configured with.")
+	fs.StringVar(&o.ListenAddress, "listen-address", defaultListenAddress, ""+
+		"The host and port that the OCSP responder should listen on for requests.")
+	fs.DurationVar(&o.ResponseValidity, "response-validity", defaultResponseValidity, ""+
+		"The duration for which OCSP responses are valid and may be cached by clients. "+
+		"Revocations may take this long to be observed by clients.")
+}
+
+func (o *OCSPResponderOptions) Validate() error {
+	if o.ResponseValidity <= 0 {
+		return fmt.Errorf("invalid response validity %s: must be positive", o.ResponseValidity)
+	}
+	return nil
+}
+
+func NewOCSPResponderCommand(ctx context.Context) *cobra.Command {
+	o := &OCSPResponderOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ocspresponder",
+		Short: fmt.Sprintf("OCSP responder for cert-manager CA issuers (%s) (%s)", util.AppVersion, util.AppGitCommit),
+		Long: `
+The cert-manager OCSP responder serves the revocation status of certificates
+issued by CA Issuers and ClusterIssuers. Certificates are revoked by creating
+CertificateRevocation resources.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			logf.V(logf.InfoLevel).InfoS("starting", "version", util.AppVersion, "revision", util.AppGitCommit)
+			return o.Run(ctx)
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run starts the OCSP responder and blocks until the context is cancelled.
+func (o *OCSPResponderOptions) Run(ctx context.Context) error {
+	log := logf.FromContext(ctx, "ocspresponder")
+
+	kubeCfg, err := clientcmd.BuildConfigFromFlags(o.APIServerHost, o.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error creating rest config: %v", err)
+	}
+	kubeCfg = rest.AddUserAgent(kubeCfg, util.CertManagerUserAgent)
+
+	intcl, err := clientset.NewForConfig(kubeCfg)
+	if err != nil {
+		return fmt.Errorf("error creating internal group client: %v", err)
+	}
+	cl, err := kubernetes.NewForConfig(kubeCfg)
+	if err != nil {
+		return fmt.Errorf("error creating kubernetes client: %v", err)
+	}
+
+	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(intcl, resyncPeriod, informers.WithNamespace(o.Namespace))
+	kubeSharedInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(cl, resyncPeriod, kubeinformers.WithNamespace(o.Namespace))
+
+	crInformer := sharedInformerFactory.Certmanager().V1().CertificateRequests().Informer()
+	if err := ocspresponder.AddIndexers(crInformer); err != nil {
+		return fmt.Errorf("error adding CertificateRequest indexers: %v", err)
+	}
+
+	responder := &ocspresponder.Responder{
+		IssuerLister:              sharedInformerFactory.Certmanager().V1().Issuers().Lister(),
+		CertificateRequestIndexer: crInformer.GetIndexer(),
+		RevocationLister:          sharedInformerFactory.Revocation().V1alpha1().CertificateRevocations().Lister(),
+		SecretLister:              kubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+		ClusterResourceNamespace:  o.ClusterResourceNamespace,
+		ResponseValidity:          o.ResponseValidity,
+		Clock:                     clock.RealClock{},
+		Log:                       log,
+	}
+	// ClusterIssuers are only served if not scoped to a single namespace.
+	if o.Namespace == "" {
+		responder.ClusterIssuerLister = sharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister()
+	}
+
+	sharedInformerFactory.Start(ctx.Done())
+	kubeSharedInformerFactory.Start(ctx.Done())
+	for informer, synced := range sharedInformerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("error waiting for %v informer to sync", informer)
+		}
+	}
+	for informer, synced := range kubeSharedInformerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("error waiting for %v informer to sync", informer)
+		}
+	}
+
+	ln, err := net.Listen("tcp", o.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", o.ListenAddress, err)
+	}
+	server := &http.Server{
+		Handler:      responder,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	completedCh := make(chan struct{})
+	go func() {
+		defer close(completedCh)
+		<-ctx.Done()
+		// allow a timeout for graceful shutdown
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "error shutting down OCSP responder")
+		}
+	}()
+
+	log.V(logf.InfoLevel).Info("starting OCSP responder", "address", ln.Addr())
+	if err := server.Serve(ln); err != http.ErrServerClosed {
+		return err
+	}
+	<-completedCh
+
+	return nil
+}
@@ -17,15 +17,15 @@ limitations under the License.
 package util
 
 import (
-	"time"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
 )
 
 const (
-	DefaultLeaderElect                 = true
-	DefaultLeaderElectionNamespace     = "kube-system"
-	DefaultLeaderElectionLeaseDuration = 60 * time.Second
-	DefaultLeaderElectionRenewDeadline = 40 * time.Second
-	DefaultLeaderElectionRetryPeriod   = 15 * time.Second
+	DefaultLeaderElect                 = configv1alpha1.DefaultLeaderElect
+	DefaultLeaderElectionNamespace     = configv1alpha1.DefaultLeaderElectionNamespace
+	DefaultLeaderElectionLeaseDuration = configv1alpha1.DefaultLeaderElectionLeaseDuration
+	DefaultLeaderElectionRenewDeadline = configv1alpha1.DefaultLeaderElectionRenewDeadline
+	DefaultLeaderElectionRetryPeriod   = configv1alpha1.DefaultLeaderElectionRetryPeriod
 
 	DefaultEnableProfiling = false
 	DefaultProfilerAddr    = "localhost:6060"
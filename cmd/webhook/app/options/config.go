@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configvalidation "github.com/jetstack/cert-manager/internal/apis/config/validation"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/util/configfile"
+)
+
+// LoadConfigFile sets the flags in fs that were not set on the command line
+// to the values of the configuration file given by --config, if any.
+func (o *WebhookOptions) LoadConfigFile(fs *pflag.FlagSet) error {
+	if o.Config == "" {
+		return nil
+	}
+
+	cfg := &configv1alpha1.WebhookConfiguration{}
+	if err := configfile.Load(o.Config, cfg); err != nil {
+		return err
+	}
+	if err := validateWebhookConfiguration(cfg); err != nil {
+		return err
+	}
+
+	s := configfile.NewFlagSetter(fs)
+	s.Int32("secure-port", cfg.SecurePort)
+	s.Int32("healthz-port", cfg.HealthzPort)
+	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
+		s.String("tls-cert-file", tlsConfig.CertFile)
+		s.String("tls-private-key-file", tlsConfig.KeyFile)
+		s.Strings("tls-cipher-suites", tlsConfig.CipherSuites)
+		s.String("tls-min-version", tlsConfig.MinTLSVersion)
+	}
+	s.Logging(cfg.Logging)
+	s.Strings("allowed-issuer-types", cfg.AllowedIssuerTypes)
+	s.Strings("denied-issuer-types", cfg.DeniedIssuerTypes)
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	o.configFlags = s
+	return nil
+}
+
+// WatchConfigFile reloads the configuration file given by --config every
+// time it changes, until ctx is done.
+func (o *WebhookOptions) WatchConfigFile(ctx context.Context) {
+	if o.configFlags == nil {
+		return
+	}
+	o.configFlags.Watch(ctx, o.Config,
+		func() runtime.Object { return &configv1alpha1.WebhookConfiguration{} },
+		func(obj runtime.Object) error {
+			return validateWebhookConfiguration(obj.(*configv1alpha1.WebhookConfiguration))
+		},
+		func(obj runtime.Object) **configv1alpha1.LoggingConfig {
+			return &obj.(*configv1alpha1.WebhookConfiguration).Logging
+		},
+	)
+}
+
+func validateWebhookConfiguration(cfg *configv1alpha1.WebhookConfiguration) error {
+	if err := configvalidation.ValidateWebhookConfiguration(cfg).ToAggregate(); err != nil {
+		return fmt.Errorf("invalid configuration file: %v", err)
+	}
+	return nil
+}
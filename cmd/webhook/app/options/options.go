@@ -17,22 +17,34 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	cliflag "k8s.io/component-base/cli/flag"
 
 	cmdutil "github.com/jetstack/cert-manager/cmd/util"
+	"github.com/jetstack/cert-manager/internal/apis/certmanager/validation/plugins"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/util/configfile"
 )
 
 const (
 	// Default port on which /validate, /mutate, /convert endpoints will be served
-	defaultListeningPort = 6443
+	defaultListeningPort = configv1alpha1.DefaultWebhookSecurePort
 	// Default health check port
-	defaultHealthPort = 6080
+	defaultHealthPort = configv1alpha1.DefaultWebhookHealthzPort
 )
 
 type WebhookOptions struct {
+	// Config is the path to a WebhookConfiguration file. Flags that are set
+	// on the command line take precedence over the file.
+	Config string
+	// configFlags applies the configuration file when it is reloaded.
+	configFlags *configfile.FlagSetter
+
 	ListenPort  int
 	HealthzPort int
 
@@ -51,6 +63,9 @@ type WebhookOptions struct {
 	DynamicServingCASecretName      string
 	// List of DNSNames that must be present on serving certificates.
 	DynamicServingDNSNames []string
+	// The amount of time that both the previous and the new CA are published
+	// when the dynamic serving CA is rotated.
+	DynamicServingCARotationOverlap time.Duration
 
 	// Optional path to the kubeconfig used to connect to the apiserver when
 	// using the 'dynamic serving' certificate sources.
@@ -72,9 +87,32 @@ type WebhookOptions struct {
 	// Address on which /debug/pprof endpoint will be served if enabled. Default is
 	// localhost:6060.
 	PprofAddress string
+
+	// CertificateSecretNameCollisions determines whether Certificates whose
+	// secretName is already used by another Certificate in the same
+	// namespace are admitted ("Ignore"), admitted with a warning ("Warn") or
+	// rejected ("Deny").
+	CertificateSecretNameCollisions string
+
+	// EnablePodCertificateInjection determines whether Pods that reference
+	// a Certificate using the 'cert-manager.io/pod-certificate' annotation
+	// are mutated on the /mutate-pods endpoint.
+	EnablePodCertificateInjection bool
+
+	// EnableSelfTest determines whether the /selftest endpoint is served on
+	// the healthz port.
+	EnableSelfTest bool
+
+	// AllowedIssuerTypes and DeniedIssuerTypes restrict the types of Issuers
+	// and ClusterIssuers that may be created.
+	AllowedIssuerTypes []string
+	DeniedIssuerTypes  []string
 }
 
 func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Config, "config", "", ""+
+		"Path to a WebhookConfiguration file. Flags set on the command line take precedence "+
+		"over the file. Changes to the log verbosity are applied without a restart.")
 	// TODO: rename secure-port to listen-port
 	fs.IntVar(&o.ListenPort, "secure-port", defaultListeningPort, "port number to listen on for secure TLS connections")
 	fs.IntVar(&o.HealthzPort, "healthz-port", defaultHealthPort, "port number to listen on for insecure healthz connections")
@@ -83,6 +121,10 @@ func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.DynamicServingCASecretNamespace, "dynamic-serving-ca-secret-namespace", "", "namespace of the secret used to store the CA that signs serving certificates")
 	fs.StringVar(&o.DynamicServingCASecretName, "dynamic-serving-ca-secret-name", "", "name of the secret used to store the CA that signs serving certificates certificates")
 	fs.StringSliceVar(&o.DynamicServingDNSNames, "dynamic-serving-dns-names", []string{""}, "DNS names that should be present on certificates generated by the dynamic serving CA")
+	fs.DurationVar(&o.DynamicServingCARotationOverlap, "dynamic-serving-ca-rotation-overlap", time.Hour, ""+
+		"Amount of time that serving certificates continue to be signed by the previous dynamic serving CA after it "+
+		"has been rotated, while the previous and new CA are both published for injection by the cainjector. "+
+		"Must be greater than the time taken by the cainjector to update the webhook configurations.")
 	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", "optional path to the kubeconfig used to connect to the apiserver. If not specified, in-cluster-config will be used")
 	fs.StringVar(&o.APIServerHost, "api-server-host", "", ""+
 		"Optional apiserver host address to connect to. If not specified, autoconfiguration "+
@@ -91,6 +133,23 @@ func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
 		"Enable profiling for controller.")
 	fs.StringVar(&o.PprofAddress, "profiler-address", cmdutil.DefaultProfilerAddr,
 		"Address of the Go profiler (pprof). This should never be exposed on a public interface. If this flag is not set, the profiler is not run.")
+	fs.StringVar(&o.CertificateSecretNameCollisions, "certificate-secret-name-collisions", string(plugins.SecretNameCollisionIgnore), ""+
+		"How to admit Certificates whose secretName is already used by another Certificate in the same namespace. "+
+		"One of Ignore, Warn or Deny. Warn and Deny require the webhook to list and watch Certificates.")
+	fs.BoolVar(&o.EnablePodCertificateInjection, "enable-pod-certificate-injection", false, ""+
+		"Serve the /mutate-pods endpoint, which mounts the Secret of the Certificate referenced by the "+
+		"'cert-manager.io/pod-certificate' annotation into Pods and adds a readiness gate for the Certificate. "+
+		"Requires the webhook to get Certificates.")
+	fs.BoolVar(&o.EnableSelfTest, "enable-self-test", false, ""+
+		"Serve the /selftest endpoint on the healthz port, which runs a set of conversion and validation "+
+		"scenarios against the webhook and responds with a 500 status code if any of them fail. "+
+		"Intended to gate upgrades on the functional health of the webhook rather than only its liveness.")
+	fs.StringSliceVar(&o.AllowedIssuerTypes, "allowed-issuer-types", nil, ""+
+		"The types of Issuers and ClusterIssuers that may be created, out of "+strings.Join(apiutil.IssuerTypes, ", ")+". "+
+		"All types are allowed if empty.")
+	fs.StringSliceVar(&o.DeniedIssuerTypes, "denied-issuer-types", nil, ""+
+		"The types of Issuers and ClusterIssuers that may not be created, for example 'selfsigned'. "+
+		"Takes precedence over --allowed-issuer-types.")
 	tlsCipherPossibleValues := cliflag.TLSCipherPossibleValues()
 	fs.StringSliceVar(&o.TLSCipherSuites, "tls-cipher-suites", o.TLSCipherSuites,
 		"Comma-separated list of cipher suites for the server. "+
@@ -103,6 +162,27 @@ func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
 
 }
 
+// PluginOptions returns the options used to initialise the validating
+// admission plugins, or an error if they are invalid.
+func PluginOptions(o WebhookOptions) (plugins.Options, error) {
+	policy := plugins.SecretNameCollisionPolicy(o.CertificateSecretNameCollisions)
+	switch policy {
+	case plugins.SecretNameCollisionIgnore, plugins.SecretNameCollisionWarn, plugins.SecretNameCollisionDeny:
+	default:
+		return plugins.Options{}, fmt.Errorf("invalid value for --certificate-secret-name-collisions %q: must be one of Ignore, Warn or Deny", o.CertificateSecretNameCollisions)
+	}
+
+	issuerTypes := apiutil.IssuerTypePolicy{
+		Allowed: o.AllowedIssuerTypes,
+		Denied:  o.DeniedIssuerTypes,
+	}
+	if err := issuerTypes.Validate(); err != nil {
+		return plugins.Options{}, fmt.Errorf("invalid value for --allowed-issuer-types or --denied-issuer-types: %v", err)
+	}
+
+	return plugins.Options{SecretNameCollisions: policy, IssuerTypes: issuerTypes}, nil
+}
+
 func FileTLSSourceEnabled(o WebhookOptions) bool {
 	if o.TLSCertFile != "" || o.TLSKeyFile != "" {
 		return true
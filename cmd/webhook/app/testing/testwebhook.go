@@ -95,7 +95,7 @@ func StartWebhookServer(t *testing.T, ctx context.Context, args []string) (Serve
 
 	stopCh := make(chan struct{})
 	errCh := make(chan error)
-	srv, err := app.NewServerWithOptions(log, opts)
+	srv, err := app.NewServerWithOptions(ctx, log, opts)
 	if err != nil {
 		t.Fatal(err)
 	}
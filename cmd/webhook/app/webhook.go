@@ -27,20 +27,23 @@ import (
 
 	cmdutil "github.com/jetstack/cert-manager/cmd/util"
 	"github.com/jetstack/cert-manager/cmd/webhook/app/options"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/pkg/webhook"
 	"github.com/jetstack/cert-manager/pkg/webhook/authority"
 	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+	"github.com/jetstack/cert-manager/pkg/webhook/podcertificates"
 	"github.com/jetstack/cert-manager/pkg/webhook/server"
 	"github.com/jetstack/cert-manager/pkg/webhook/server/tls"
 )
 
-var validationHook handlers.ValidatingAdmissionHook = handlers.NewRegistryBackedValidator(logf.Log, webhook.Scheme, webhook.ValidationRegistry)
-var mutationHook handlers.MutatingAdmissionHook = handlers.NewRegistryBackedMutator(logf.Log, webhook.Scheme, webhook.MutationRegistry)
-var conversionHook handlers.ConversionHook = handlers.NewSchemeBackedConverter(logf.Log, webhook.Scheme)
+func NewServerWithOptions(ctx context.Context, log logr.Logger, opts options.WebhookOptions) (*server.Server, error) {
+	pluginOpts, err := options.PluginOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 
-func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server.Server, error) {
 	restcfg, err := clientcmd.BuildConfigFromFlags(opts.APIServerHost, opts.Kubeconfig)
 	if err != nil {
 		return nil, err
@@ -50,7 +53,16 @@ func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server
 	if err != nil {
 		return nil, fmt.Errorf("error creating kubernetes client: %s", err)
 	}
-	validationHook.InitPlugins(cl)
+	cmClient, err := cmclient.NewForConfig(restcfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cert-manager client: %s", err)
+	}
+	// The hooks are built here rather than at package initialisation, so
+	// that they log using the logger configured by the command line flags.
+	validationHook := handlers.NewRegistryBackedValidator(log, webhook.Scheme, webhook.ValidationRegistry)
+	mutationHook := handlers.NewRegistryBackedMutator(log, webhook.Scheme, webhook.MutationRegistry)
+	conversionHook := handlers.NewSchemeBackedConverter(log, webhook.Scheme)
+	validationHook.InitPlugins(ctx, cl, cmClient, pluginOpts)
 
 	var source tls.CertificateSource
 	switch {
@@ -71,10 +83,11 @@ func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server
 		source = &tls.DynamicSource{
 			DNSNames: opts.DynamicServingDNSNames,
 			Authority: &authority.DynamicAuthority{
-				SecretNamespace: opts.DynamicServingCASecretNamespace,
-				SecretName:      opts.DynamicServingCASecretName,
-				RESTConfig:      restcfg,
-				Log:             log,
+				SecretNamespace:   opts.DynamicServingCASecretNamespace,
+				SecretName:        opts.DynamicServingCASecretName,
+				CARotationOverlap: opts.DynamicServingCARotationOverlap,
+				RESTConfig:        restcfg,
+				Log:               log,
 			},
 			Log: log,
 		}
@@ -82,18 +95,25 @@ func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server
 		log.V(logf.WarnLevel).Info("serving insecurely as tls certificate data not provided")
 	}
 
+	var podMutationHook handlers.MutatingAdmissionHook
+	if opts.EnablePodCertificateInjection {
+		podMutationHook = podcertificates.NewMutator(log, cmClient)
+	}
+
 	return &server.Server{
-		ListenAddr:        fmt.Sprintf(":%d", opts.ListenPort),
-		HealthzAddr:       fmt.Sprintf(":%d", opts.HealthzPort),
-		PprofAddr:         opts.PprofAddress,
-		EnablePprof:       opts.EnablePprof,
-		CertificateSource: source,
-		CipherSuites:      opts.TLSCipherSuites,
-		MinTLSVersion:     opts.MinTLSVersion,
-		ValidationWebhook: validationHook,
-		MutationWebhook:   mutationHook,
-		ConversionWebhook: conversionHook,
-		Log:               log,
+		ListenAddr:         fmt.Sprintf(":%d", opts.ListenPort),
+		HealthzAddr:        fmt.Sprintf(":%d", opts.HealthzPort),
+		EnableSelfTest:     opts.EnableSelfTest,
+		PprofAddr:          opts.PprofAddress,
+		EnablePprof:        opts.EnablePprof,
+		CertificateSource:  source,
+		CipherSuites:       opts.TLSCipherSuites,
+		MinTLSVersion:      opts.MinTLSVersion,
+		ValidationWebhook:  validationHook,
+		MutationWebhook:    mutationHook,
+		ConversionWebhook:  conversionHook,
+		PodMutationWebhook: podMutationHook,
+		Log:                log,
 	}, nil
 }
 
@@ -104,11 +124,17 @@ func NewServerCommand(stopCh <-chan struct{}) *cobra.Command {
 		Use:   "webhook",
 		Short: fmt.Sprintf("Webhook component providing API validation, mutation and conversion functionality for cert-manager (%s) (%s)", util.AppVersion, util.AppGitCommit),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.LoadConfigFile(cmd.Flags()); err != nil {
+				return fmt.Errorf("error loading configuration file: %s", err)
+			}
+
 			ctx := cmdutil.ContextWithStopCh(context.Background(), stopCh)
 			ctx = logf.NewContext(ctx, nil, "webhook")
 			log := logf.FromContext(ctx)
 
-			srv, err := NewServerWithOptions(log, opts)
+			go opts.WatchConfigFile(ctx)
+
+			srv, err := NewServerWithOptions(ctx, log, opts)
 			if err != nil {
 				return err
 			}
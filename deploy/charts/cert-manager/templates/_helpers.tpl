@@ -140,6 +140,93 @@ Create the name of the service account to use
 {{- end -}}
 {{- end -}}
 
+{{/*
+ocspresponder templates
+*/}}
+
+{{- define "ocspresponder.name" -}}
+{{- printf "ocspresponder" -}}
+{{- end -}}
+
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+If release name contains chart name it will be used as a full name.
+*/}}
+{{- define "ocspresponder.fullname" -}}
+{{- $trimmedName := printf "%s" (include "cert-manager.fullname" .) | trunc 49 | trimSuffix "-" -}}
+{{- printf "%s-ocspresponder" $trimmedName | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create the name of the service account to use
+*/}}
+{{- define "ocspresponder.serviceAccountName" -}}
+{{- if .Values.ocspResponder.serviceAccount.create -}}
+    {{ default (include "ocspresponder.fullname" .) .Values.ocspResponder.serviceAccount.name }}
+{{- else -}}
+    {{ default "default" .Values.ocspResponder.serviceAccount.name }}
+{{- end -}}
+{{- end -}}
+
+{{/*
+csidriver templates
+*/}}
+
+{{- define "csidriver.name" -}}
+{{- printf "csidriver" -}}
+{{- end -}}
+
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+If release name contains chart name it will be used as a full name.
+*/}}
+{{- define "csidriver.fullname" -}}
+{{- $trimmedName := printf "%s" (include "cert-manager.fullname" .) | trunc 53 | trimSuffix "-" -}}
+{{- printf "%s-csidriver" $trimmedName | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create the name of the service account to use
+*/}}
+{{- define "csidriver.serviceAccountName" -}}
+{{- if .Values.csiDriver.serviceAccount.create -}}
+    {{ default (include "csidriver.fullname" .) .Values.csiDriver.serviceAccount.name }}
+{{- else -}}
+    {{ default "default" .Values.csiDriver.serviceAccount.name }}
+{{- end -}}
+{{- end -}}
+
+{{/*
+istioca templates
+*/}}
+
+{{- define "istioca.name" -}}
+{{- printf "istioca" -}}
+{{- end -}}
+
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+If release name contains chart name it will be used as a full name.
+*/}}
+{{- define "istioca.fullname" -}}
+{{- $trimmedName := printf "%s" (include "cert-manager.fullname" .) | trunc 55 | trimSuffix "-" -}}
+{{- printf "%s-istioca" $trimmedName | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create the name of the service account to use
+*/}}
+{{- define "istioca.serviceAccountName" -}}
+{{- if .Values.istioCA.serviceAccount.create -}}
+    {{ default (include "istioca.fullname" .) .Values.istioCA.serviceAccount.name }}
+{{- else -}}
+    {{ default "default" .Values.istioCA.serviceAccount.name }}
+{{- end -}}
+{{- end -}}
+
 {{/*
 Create chart name and version as used by the chart label.
 */}}
@@ -157,3 +244,69 @@ app.kubernetes.io/managed-by: {{ .Release.Service }}
 helm.sh/chart: {{ include "chartName" . }}
 {{- end -}}
 {{- end -}}
+
+{{/*
+Namespaces in which the controller is granted the permissions of its
+ClusterRoles when it is limited to .Values.namespaces: those namespaces and
+the cluster resource namespace.
+*/}}
+{{- define "cert-manager.controllerNamespaces" -}}
+{{- $namespaces := append .Values.namespaces (.Values.clusterResourceNamespace | default .Release.Namespace) -}}
+{{- join "," (uniq $namespaces) -}}
+{{- end -}}
+
+{{/*
+Binds the controller ClusterRole with the given name suffix to the controller
+ServiceAccount. The role is bound in each of the controller namespaces if
+.Values.namespaces is set, and cluster wide otherwise.
+Expects a dict with the keys "root", the root context, and "role".
+*/}}
+{{- define "cert-manager.controllerRoleBinding" -}}
+{{- $root := .root -}}
+{{- $role := .role -}}
+{{- if $root.Values.namespaces }}
+{{- range $i, $namespace := splitList "," (include "cert-manager.controllerNamespaces" $root) }}
+{{- if $i }}
+---
+{{- end }}
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: {{ template "cert-manager.fullname" $root }}-controller-{{ $role }}
+  namespace: {{ $namespace | quote }}
+  labels:
+    app: {{ include "cert-manager.name" $root }}
+    app.kubernetes.io/name: {{ include "cert-manager.name" $root }}
+    app.kubernetes.io/instance: {{ $root.Release.Name }}
+    app.kubernetes.io/component: "controller"
+    {{- include "labels" $root | nindent 4 }}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: {{ template "cert-manager.fullname" $root }}-controller-{{ $role }}
+subjects:
+  - name: {{ template "cert-manager.serviceAccountName" $root }}
+    namespace: {{ $root.Release.Namespace | quote }}
+    kind: ServiceAccount
+{{- end }}
+{{- else -}}
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{ template "cert-manager.fullname" $root }}-controller-{{ $role }}
+  labels:
+    app: {{ include "cert-manager.name" $root }}
+    app.kubernetes.io/name: {{ include "cert-manager.name" $root }}
+    app.kubernetes.io/instance: {{ $root.Release.Name }}
+    app.kubernetes.io/component: "controller"
+    {{- include "labels" $root | nindent 4 }}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: {{ template "cert-manager.fullname" $root }}-controller-{{ $role }}
+subjects:
+  - name: {{ template "cert-manager.serviceAccountName" $root }}
+    namespace: {{ $root.Release.Namespace | quote }}
+    kind: ServiceAccount
+{{- end }}
+{{- end -}}
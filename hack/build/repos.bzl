@@ -613,6 +613,15 @@ def go_repositories():
         version = "v1.0.2",
     )
 
+    go_repository(
+        name = "com_github_container_storage_interface_spec",
+        build_file_generation = "on",
+        build_file_proto_mode = "disable",
+        importpath = "github.com/container-storage-interface/spec",
+        sum = "h1:lvKxe3uLgqQeVQcrnL2CPQKISoKjTJxojEs9cBk+HXo=",
+        version = "v1.5.0",
+    )
+
     go_repository(
         name = "com_github_containerd_containerd",
         build_file_generation = "on",
@@ -4117,8 +4126,8 @@ def go_repositories():
         build_file_generation = "on",
         build_file_proto_mode = "disable",
         importpath = "k8s.io/klog/v2",
-        sum = "h1:D7HV+n1V57XeZ0m6tdRkfknthUaM06VFbWldOFh8kzM=",
-        version = "v2.9.0",
+        sum = "h1:R2HDMDJsHVTHA2n4RjwbeYXdOcBymXdX/JRb1v0VGhE=",
+        version = "v2.10.0",
     )
 
     go_repository(
@@ -4165,6 +4174,15 @@ def go_repositories():
         version = "v0.22.1",
     )
 
+    go_repository(
+        name = "io_k8s_mount_utils",
+        build_file_generation = "on",
+        build_file_proto_mode = "disable",
+        importpath = "k8s.io/mount-utils",
+        sum = "h1:w/CJq+Cofkr81Rp89UkokgEbuu8Js0LwMI/RWWEE+gs=",
+        version = "v0.22.2",
+    )
+
     go_repository(
         name = "io_k8s_sigs_apiserver_network_proxy_konnectivity_client",
         build_file_generation = "on",
@@ -4197,8 +4215,8 @@ def go_repositories():
         build_file_generation = "on",
         build_file_proto_mode = "disable",
         importpath = "sigs.k8s.io/gateway-api",
-        sum = "h1:mKbQRlRIIY3dsCCbNF9Jv30V9vvOf6SRG82l0MfJQ9U=",
-        version = "v0.3.0",
+        sum = "h1:Tof9/PNSZXyfDuTTe1XFvaTlvBRE6bKq1kmV6jj6rQE=",
+        version = "v0.4.1",
     )
 
     go_repository(
@@ -4269,8 +4287,8 @@ def go_repositories():
         build_file_generation = "on",
         build_file_proto_mode = "disable",
         importpath = "k8s.io/utils",
-        sum = "h1:8dYfu/Fc9Gz2rNJKB9IQRGgQOh2clmRzNIPPY1xLY5g=",
-        version = "v0.0.0-20210819203725-bdf08cb9a70a",
+        sum = "h1:ldQh+neBabomh7+89dTpiFAB8tGdfVmuIzAHbvtl+9I=",
+        version = "v0.0.0-20210820185131-d34e5cb4466e",
     )
 
     go_repository(
@@ -46,6 +46,10 @@ deepcopy_inputs=(
   internal/apis/acme \
   pkg/apis/meta/v1 \
   internal/apis/meta \
+  pkg/apis/config/v1alpha1 \
+  pkg/apis/policy/v1alpha1 \
+  pkg/apis/revocation/v1alpha1 \
+  pkg/apis/trust/v1alpha1 \
   pkg/webhook/handlers/testdata/apis/testgroup/v2 \
   pkg/webhook/handlers/testdata/apis/testgroup/v1 \
   pkg/webhook/handlers/testdata/apis/testgroup \
@@ -64,6 +68,9 @@ client_inputs=(
   pkg/apis/acme/v1alpha3 \
   pkg/apis/acme/v1beta1 \
   pkg/apis/acme/v1 \
+  pkg/apis/policy/v1alpha1 \
+  pkg/apis/revocation/v1alpha1 \
+  pkg/apis/trust/v1alpha1 \
 )
 
 # Generate defaulting functions to be used by the mutating webhook
@@ -77,6 +84,7 @@ defaulter_inputs=(
   internal/apis/acme/v1beta1 \
   internal/apis/acme/v1 \
   internal/apis/meta/v1 \
+  pkg/apis/config/v1alpha1 \
   pkg/webhook/handlers/testdata/apis/testgroup/v2 \
   pkg/webhook/handlers/testdata/apis/testgroup/v1 \
 )
@@ -230,6 +238,7 @@ gen-informers() {
 
 gen-defaulters() {
   clean internal/apis 'zz_generated.defaults.go'
+  clean pkg/apis/config 'zz_generated.defaults.go'
   clean pkg/webhook/handlers/testdata/apis 'zz_generated.defaults.go'
   echo "Generating defaulting functions..." >&2
   prefixed_inputs=( "${defaulter_inputs[@]/#/$module_name/}" )
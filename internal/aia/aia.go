@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aia completes certificate chains by fetching missing CA
+// certificates from the CA Issuers URLs of the authority information access
+// extension, see RFC 5280, 4.2.2.1.
+package aia
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+const (
+	// fetchTimeout is the maximum time a single request for a CA certificate
+	// may take.
+	fetchTimeout = 15 * time.Second
+
+	// maxResponseSize is the maximum size of a CA certificate that is read.
+	maxResponseSize = 1 << 20
+
+	// maxFetchedCertificates is the maximum number of CA certificates that
+	// are fetched to complete a single chain.
+	maxFetchedCertificates = 8
+
+	// cacheTTL is how long fetched CA certificates are cached for.
+	cacheTTL = time.Hour
+)
+
+// Fetcher completes certificate chains by fetching CA certificates from AIA
+// CA Issuers URLs. Fetched certificates are cached by URL, so that a chain
+// is only fetched once per cache period for all certificates signed by the
+// same CA.
+type Fetcher struct {
+	httpClient *http.Client
+	clock      clock.Clock
+
+	lock  sync.Mutex
+	cache map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	cert    *x509.Certificate
+	expires time.Time
+}
+
+// New returns a Fetcher which fetches CA certificates using a default HTTP
+// client.
+func New() *Fetcher {
+	return &Fetcher{
+		httpClient: &http.Client{Timeout: fetchTimeout},
+		clock:      clock.RealClock{},
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// CompleteChain returns the given CA certificates with any CA certificates
+// missing up to a self-signed root appended to them. The first certificate
+// must be the issuing CA; the chain is followed upwards from it through the
+// given certificates, and from the top-most of those through the CA Issuers
+// URLs of each certificate.
+// The chain is returned as far as it could be completed when the top-most
+// certificate has no CA Issuers URLs. An error is returned if none of the
+// CA Issuers URLs of a certificate yield its issuer.
+func (f *Fetcher) CompleteChain(ctx context.Context, certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("no CA certificates given")
+	}
+
+	top := topOfChain(certs)
+	chain := append([]*x509.Certificate{}, certs...)
+	for fetched := 0; !isSelfSigned(top); fetched++ {
+		if len(top.IssuingCertificateURL) == 0 {
+			return chain, nil
+		}
+		if fetched == maxFetchedCertificates {
+			return nil, fmt.Errorf("certificate chain is longer than %d fetched certificates", maxFetchedCertificates)
+		}
+
+		issuer, err := f.fetchIssuer(ctx, top)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issuer of certificate %q: %w", top.Subject, err)
+		}
+
+		chain = append(chain, issuer)
+		top = issuer
+	}
+
+	return chain, nil
+}
+
+// fetchIssuer returns the first certificate fetched from the CA Issuers URLs
+// of cert which signed it.
+func (f *Fetcher) fetchIssuer(ctx context.Context, cert *x509.Certificate) (*x509.Certificate, error) {
+	var errs []string
+	for _, u := range cert.IssuingCertificateURL {
+		issuer, err := f.fetch(ctx, u)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := cert.CheckSignatureFrom(issuer); err != nil {
+			errs = append(errs, fmt.Sprintf("certificate from %q is not the issuer: %s", u, err))
+			continue
+		}
+		return issuer, nil
+	}
+
+	return nil, errors.New(strings.Join(errs, "; "))
+}
+
+// fetch returns the certificate at the given URL, from the cache if present.
+func (f *Fetcher) fetch(ctx context.Context, u string) (*x509.Certificate, error) {
+	f.lock.Lock()
+	entry, ok := f.cache[u]
+	f.lock.Unlock()
+	if ok && f.clock.Now().Before(entry.expires) {
+		return entry.cert, nil
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA Issuers URL %q: %w", u, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme of CA Issuers URL %q", u)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %q: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %q: unexpected status code %d", u, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", u, err)
+	}
+
+	cert, err := parseCertificate(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate from %q: %w", u, err)
+	}
+
+	now := f.clock.Now()
+	f.lock.Lock()
+	// Drop expired certificates, so that the cache does not keep growing
+	// with the URLs of CA certificates that are no longer used.
+	for k, entry := range f.cache {
+		if !now.Before(entry.expires) {
+			delete(f.cache, k)
+		}
+	}
+	f.cache[u] = cacheEntry{cert: cert, expires: now.Add(cacheTTL)}
+	f.lock.Unlock()
+
+	return cert, nil
+}
+
+// parseCertificate parses a DER or PEM encoded certificate.
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(bytes.TrimSpace(data)); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		data = block.Bytes
+	}
+
+	return x509.ParseCertificate(data)
+}
+
+// topOfChain follows the chain upwards from the first certificate through the
+// given certificates, and returns the top-most certificate reached.
+func topOfChain(certs []*x509.Certificate) *x509.Certificate {
+	top := certs[0]
+	for range certs {
+		if isSelfSigned(top) {
+			return top
+		}
+		next := top
+		for _, cert := range certs {
+			if cert != top && top.CheckSignatureFrom(cert) == nil {
+				next = cert
+				break
+			}
+		}
+		if next == top {
+			return top
+		}
+		top = next
+	}
+	return top
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aia
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func generateCA(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, issuerURLs ...string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		IssuingCertificateURL: issuerURLs,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func TestCompleteChain(t *testing.T) {
+	var (
+		lock      sync.Mutex
+		responses = make(map[string][]byte)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	root, rootKey := generateCA(t, "root", nil, nil)
+	intermediate, intermediateKey := generateCA(t, "intermediate", root, rootKey, server.URL+"/root.pem")
+	issuing, _ := generateCA(t, "issuing", intermediate, intermediateKey, server.URL+"/missing.crt", server.URL+"/intermediate.crt")
+	orphan, _ := generateCA(t, "orphan", intermediate, intermediateKey)
+	unrelated, _ := generateCA(t, "unrelated", root, rootKey, server.URL+"/root.pem")
+	wrongIssuer, _ := generateCA(t, "wrong-issuer", intermediate, intermediateKey, server.URL+"/root.pem")
+
+	responses["/root.pem"] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})
+	responses["/intermediate.crt"] = intermediate.Raw
+
+	tests := map[string]struct {
+		certs    []*x509.Certificate
+		expChain []*x509.Certificate
+		expErr   bool
+	}{
+		"a complete chain should be returned unchanged": {
+			certs:    []*x509.Certificate{issuing, intermediate, root},
+			expChain: []*x509.Certificate{issuing, intermediate, root},
+		},
+		"a chain missing its root should be completed": {
+			certs:    []*x509.Certificate{issuing, intermediate},
+			expChain: []*x509.Certificate{issuing, intermediate, root},
+		},
+		"a chain of only the issuing CA should be completed through all CA Issuers URLs": {
+			certs:    []*x509.Certificate{issuing},
+			expChain: []*x509.Certificate{issuing, intermediate, root},
+		},
+		"a certificate without CA Issuers URLs should be returned as is": {
+			certs:    []*x509.Certificate{orphan},
+			expChain: []*x509.Certificate{orphan},
+		},
+		"unrelated certificates should not be treated as part of the chain": {
+			certs:    []*x509.Certificate{issuing, unrelated},
+			expChain: []*x509.Certificate{issuing, unrelated, intermediate, root},
+		},
+		"a certificate which is not the issuer should not be used": {
+			certs:  []*x509.Certificate{wrongIssuer},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain, err := New().CompleteChain(context.Background(), test.certs)
+			assert.Equal(t, test.expErr, err != nil, "%v", err)
+			assert.Equal(t, test.expChain, chain)
+		})
+	}
+}
+
+func TestCompleteChainCache(t *testing.T) {
+	root, rootKey := generateCA(t, "root", nil, nil)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write(root.Raw)
+	}))
+	defer server.Close()
+
+	issuing, _ := generateCA(t, "issuing", root, rootKey, server.URL+"/root.crt")
+
+	clock := fakeclock.NewFakeClock(time.Now())
+	fetcher := New()
+	fetcher.clock = clock
+
+	for i := 0; i < 2; i++ {
+		_, err := fetcher.CompleteChain(context.Background(), []*x509.Certificate{issuing})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, requests, "expected the root to be fetched once and then cached")
+
+	clock.Step(cacheTTL)
+	_, err := fetcher.CompleteChain(context.Background(), []*x509.Certificate{issuing})
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "expected the root to be fetched again once the cache expired")
+
+	// Expired certificates of other URLs are pruned when a certificate is
+	// cached.
+	other, _ := generateCA(t, "other", root, rootKey, server.URL+"/other.crt")
+	clock.Step(cacheTTL)
+	_, err = fetcher.CompleteChain(context.Background(), []*x509.Certificate{other})
+	require.NoError(t, err)
+	assert.Len(t, fetcher.cache, 1)
+	assert.Contains(t, fetcher.cache, server.URL+"/other.crt")
+}
@@ -88,9 +88,14 @@ type ChallengeSpec struct {
 	// If the Issuer is not an 'ACME' Issuer, an error will be returned and the
 	// Challenge will be marked as failed.
 	IssuerRef cmmeta.ObjectReference
+
+	// Preflight is true if this Challenge is for an authorization on the
+	// issuer's preflight ACME server, in which case it is accepted using the
+	// issuer's preflight account.
+	Preflight bool
 }
 
-// The type of ACME challenge. Only HTTP-01 and DNS-01 are supported.
+// The type of ACME challenge. Only HTTP-01, DNS-01 and TLS-ALPN-01 are supported.
 type ACMEChallengeType string
 
 const (
@@ -101,6 +106,10 @@ const (
 	// ACMEChallengeTypeDNS01 denotes a Challenge is of type dns-01
 	// More info: https://letsencrypt.org/docs/challenge-types/#dns-01-challenge
 	ACMEChallengeTypeDNS01 ACMEChallengeType = "DNS-01"
+
+	// ACMEChallengeTypeTLSALPN01 denotes a Challenge is of type tls-alpn-01
+	// More info: https://letsencrypt.org/docs/challenge-types/#tls-alpn-01
+	ACMEChallengeTypeTLSALPN01 ACMEChallengeType = "TLS-ALPN-01"
 )
 
 type ChallengeStatus struct {
@@ -128,4 +137,21 @@ type ChallengeStatus struct {
 	// State contains the current 'state' of the challenge.
 	// If not set, the state of the challenge is unknown.
 	State State
+
+	// DNS01 records where the DNS01 challenge record was presented, so that
+	// the same record is cleaned up even if DNS has changed since.
+	DNS01 *ChallengeDNS01Status
+}
+
+// ChallengeDNS01Status records where a DNS01 challenge record was presented.
+type ChallengeDNS01Status struct {
+	// FQDN is the fully qualified domain name that the TXT record was
+	// presented at, after following any CNAME records.
+	FQDN string
+
+	// DelegatedZone is the zone that the record was presented in using the
+	// Delegate CNAME strategy, by the DNS01 solver on the issuer whose
+	// dnsZones selector matches it. It is empty if the record was presented
+	// using the solver of the Challenge.
+	DelegatedZone string
 }
@@ -109,6 +109,33 @@ type OrderStatus struct {
 	// FailureTime stores the time that this order failed.
 	// This is used to influence garbage collection and back-off.
 	FailureTime *metav1.Time
+
+	// Preflight contains the status of the Order placed against the issuer's
+	// preflight ACME server before this Order was placed against the issuer's
+	// server. It is only set if the issuer has a preflight server configured
+	// and this is the first Order for the Certificate.
+	Preflight *OrderPreflightStatus
+}
+
+// OrderPreflightStatus is the status of an Order placed against the issuer's
+// preflight ACME server.
+type OrderPreflightStatus struct {
+	// URL of the Order on the preflight ACME server.
+	URL string
+
+	// Authorizations contains data returned from the preflight ACME server on
+	// what authorizations must be completed in order to validate the DNS names
+	// specified on the Order.
+	Authorizations []ACMEAuthorization
+
+	// State contains the current state of the Order on the preflight ACME
+	// server. Once it is 'ready', the Order is placed against the issuer's
+	// server.
+	State State
+
+	// Reason optionally provides more information about why the preflight
+	// Order is in the current state.
+	Reason string
 }
 
 // ACMEAuthorization contains data returned from the ACME server on an
@@ -103,6 +103,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), (*acme.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(a.(*v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef), b.(*acme.ACMEChallengeSolverHTTP01GatewayParentRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), (*v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef(a.(*acme.ACMEChallengeSolverHTTP01GatewayParentRef), b.(*v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverHTTP01Ingress)(nil), (*acme.ACMEChallengeSolverHTTP01Ingress)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ACMEChallengeSolverHTTP01Ingress_To_acme_ACMEChallengeSolverHTTP01Ingress(a.(*v1alpha2.ACMEChallengeSolverHTTP01Ingress), b.(*acme.ACMEChallengeSolverHTTP01Ingress), scope)
 	}); err != nil {
@@ -163,6 +173,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverHTTP01NodePortRange)(nil), (*acme.ACMEChallengeSolverHTTP01NodePortRange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(a.(*v1alpha2.ACMEChallengeSolverHTTP01NodePortRange), b.(*acme.ACMEChallengeSolverHTTP01NodePortRange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01NodePortRange)(nil), (*v1alpha2.ACMEChallengeSolverHTTP01NodePortRange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange(a.(*acme.ACMEChallengeSolverHTTP01NodePortRange), b.(*v1alpha2.ACMEChallengeSolverHTTP01NodePortRange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec)(nil), (*acme.ACMEChallengeSolverHTTP01ServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(a.(*v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec), b.(*acme.ACMEChallengeSolverHTTP01ServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01ServiceSpec)(nil), (*v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec(a.(*acme.ACMEChallengeSolverHTTP01ServiceSpec), b.(*v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), (*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(a.(*v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate), b.(*acme.ACMEChallengeSolverHTTP01ServiceTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), (*v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate(a.(*acme.ACMEChallengeSolverHTTP01ServiceTemplate), b.(*v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverTLSALPN01)(nil), (*acme.ACMEChallengeSolverTLSALPN01)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(a.(*v1alpha2.ACMEChallengeSolverTLSALPN01), b.(*acme.ACMEChallengeSolverTLSALPN01), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverTLSALPN01)(nil), (*v1alpha2.ACMEChallengeSolverTLSALPN01)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverTLSALPN01_To_v1alpha2_ACMEChallengeSolverTLSALPN01(a.(*acme.ACMEChallengeSolverTLSALPN01), b.(*v1alpha2.ACMEChallengeSolverTLSALPN01), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEExternalAccountBinding)(nil), (*acme.ACMEExternalAccountBinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ACMEExternalAccountBinding_To_acme_ACMEExternalAccountBinding(a.(*v1alpha2.ACMEExternalAccountBinding), b.(*acme.ACMEExternalAccountBinding), scope)
 	}); err != nil {
@@ -263,6 +313,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEIssuerPreflight)(nil), (*acme.ACMEIssuerPreflight)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(a.(*v1alpha2.ACMEIssuerPreflight), b.(*acme.ACMEIssuerPreflight), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEIssuerPreflight)(nil), (*v1alpha2.ACMEIssuerPreflight)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEIssuerPreflight_To_v1alpha2_ACMEIssuerPreflight(a.(*acme.ACMEIssuerPreflight), b.(*v1alpha2.ACMEIssuerPreflight), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEIssuerStatus)(nil), (*acme.ACMEIssuerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ACMEIssuerStatus_To_acme_ACMEIssuerStatus(a.(*v1alpha2.ACMEIssuerStatus), b.(*acme.ACMEIssuerStatus), scope)
 	}); err != nil {
@@ -273,6 +333,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEMaintenanceWindow)(nil), (*acme.ACMEMaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(a.(*v1alpha2.ACMEMaintenanceWindow), b.(*acme.ACMEMaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEMaintenanceWindow)(nil), (*v1alpha2.ACMEMaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEMaintenanceWindow_To_v1alpha2_ACMEMaintenanceWindow(a.(*acme.ACMEMaintenanceWindow), b.(*v1alpha2.ACMEMaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.AzureManagedIdentity)(nil), (*acme.AzureManagedIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AzureManagedIdentity_To_acme_AzureManagedIdentity(a.(*v1alpha2.AzureManagedIdentity), b.(*acme.AzureManagedIdentity), scope)
 	}); err != nil {
@@ -303,6 +373,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ChallengeDNS01Status)(nil), (*acme.ChallengeDNS01Status)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(a.(*v1alpha2.ChallengeDNS01Status), b.(*acme.ChallengeDNS01Status), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ChallengeDNS01Status)(nil), (*v1alpha2.ChallengeDNS01Status)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ChallengeDNS01Status_To_v1alpha2_ChallengeDNS01Status(a.(*acme.ChallengeDNS01Status), b.(*v1alpha2.ChallengeDNS01Status), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.ChallengeList)(nil), (*acme.ChallengeList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ChallengeList_To_acme_ChallengeList(a.(*v1alpha2.ChallengeList), b.(*acme.ChallengeList), scope)
 	}); err != nil {
@@ -343,6 +423,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.OrderPreflightStatus)(nil), (*acme.OrderPreflightStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OrderPreflightStatus_To_acme_OrderPreflightStatus(a.(*v1alpha2.OrderPreflightStatus), b.(*acme.OrderPreflightStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.OrderPreflightStatus)(nil), (*v1alpha2.OrderPreflightStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_OrderPreflightStatus_To_v1alpha2_OrderPreflightStatus(a.(*acme.OrderPreflightStatus), b.(*v1alpha2.OrderPreflightStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.OrderStatus)(nil), (*acme.OrderStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OrderStatus_To_acme_OrderStatus(a.(*v1alpha2.OrderStatus), b.(*acme.OrderStatus), scope)
 	}); err != nil {
@@ -450,6 +540,7 @@ func autoConvert_v1alpha2_ACMEChallengeSolver_To_acme_ACMEChallengeSolver(in *v1
 	} else {
 		out.DNS01 = nil
 	}
+	out.TLSALPN01 = (*acme.ACMEChallengeSolverTLSALPN01)(unsafe.Pointer(in.TLSALPN01))
 	return nil
 }
 
@@ -470,6 +561,7 @@ func autoConvert_acme_ACMEChallengeSolver_To_v1alpha2_ACMEChallengeSolver(in *ac
 	} else {
 		out.DNS01 = nil
 	}
+	out.TLSALPN01 = (*v1alpha2.ACMEChallengeSolverTLSALPN01)(unsafe.Pointer(in.TLSALPN01))
 	return nil
 }
 
@@ -480,6 +572,8 @@ func Convert_acme_ACMEChallengeSolver_To_v1alpha2_ACMEChallengeSolver(in *acme.A
 
 func autoConvert_v1alpha2_ACMEChallengeSolverDNS01_To_acme_ACMEChallengeSolverDNS01(in *v1alpha2.ACMEChallengeSolverDNS01, out *acme.ACMEChallengeSolverDNS01, s conversion.Scope) error {
 	out.CNAMEStrategy = acme.CNAMEStrategy(in.CNAMEStrategy)
+	out.DelegatedZones = *(*[]string)(unsafe.Pointer(&in.DelegatedZones))
+	out.SecretsFromChallengeNamespace = in.SecretsFromChallengeNamespace
 	if in.Akamai != nil {
 		in, out := &in.Akamai, &out.Akamai
 		*out = new(acme.ACMEIssuerDNS01ProviderAkamai)
@@ -563,6 +657,8 @@ func Convert_v1alpha2_ACMEChallengeSolverDNS01_To_acme_ACMEChallengeSolverDNS01(
 
 func autoConvert_acme_ACMEChallengeSolverDNS01_To_v1alpha2_ACMEChallengeSolverDNS01(in *acme.ACMEChallengeSolverDNS01, out *v1alpha2.ACMEChallengeSolverDNS01, s conversion.Scope) error {
 	out.CNAMEStrategy = v1alpha2.CNAMEStrategy(in.CNAMEStrategy)
+	out.DelegatedZones = *(*[]string)(unsafe.Pointer(&in.DelegatedZones))
+	out.SecretsFromChallengeNamespace = in.SecretsFromChallengeNamespace
 	if in.Akamai != nil {
 		in, out := &in.Akamai, &out.Akamai
 		*out = new(v1alpha2.ACMEIssuerDNS01ProviderAkamai)
@@ -669,6 +765,8 @@ func Convert_acme_ACMEChallengeSolverHTTP01_To_v1alpha2_ACMEChallengeSolverHTTP0
 func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute(in *v1alpha2.ACMEChallengeSolverHTTP01GatewayHTTPRoute, out *acme.ACMEChallengeSolverHTTP01GatewayHTTPRoute, s conversion.Scope) error {
 	out.ServiceType = v1.ServiceType(in.ServiceType)
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.ParentRefs = *(*[]acme.ACMEChallengeSolverHTTP01GatewayParentRef)(unsafe.Pointer(&in.ParentRefs))
+	out.ServiceTemplate = (*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -680,6 +778,8 @@ func Convert_v1alpha2_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_acme_ACMEChal
 func autoConvert_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayHTTPRoute(in *acme.ACMEChallengeSolverHTTP01GatewayHTTPRoute, out *v1alpha2.ACMEChallengeSolverHTTP01GatewayHTTPRoute, s conversion.Scope) error {
 	out.ServiceType = v1.ServiceType(in.ServiceType)
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.ParentRefs = *(*[]v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef)(unsafe.Pointer(&in.ParentRefs))
+	out.ServiceTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -688,12 +788,35 @@ func Convert_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_v1alpha2_ACMEChal
 	return autoConvert_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayHTTPRoute(in, out, s)
 }
 
+func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(in *v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef, out *acme.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(in *v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef, out *acme.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef(in *acme.ACMEChallengeSolverHTTP01GatewayParentRef, out *v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef(in *acme.ACMEChallengeSolverHTTP01GatewayParentRef, out *v1alpha2.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1alpha2_ACMEChallengeSolverHTTP01GatewayParentRef(in, out, s)
+}
+
 func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01Ingress_To_acme_ACMEChallengeSolverHTTP01Ingress(in *v1alpha2.ACMEChallengeSolverHTTP01Ingress, out *acme.ACMEChallengeSolverHTTP01Ingress, s conversion.Scope) error {
 	out.ServiceType = v1.ServiceType(in.ServiceType)
 	out.Class = (*string)(unsafe.Pointer(in.Class))
 	out.Name = in.Name
 	out.PodTemplate = (*acme.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	out.IngressTemplate = (*acme.ACMEChallengeSolverHTTP01IngressTemplate)(unsafe.Pointer(in.IngressTemplate))
+	out.ServiceTemplate = (*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -708,6 +831,7 @@ func autoConvert_acme_ACMEChallengeSolverHTTP01Ingress_To_v1alpha2_ACMEChallenge
 	out.Name = in.Name
 	out.PodTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	out.IngressTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01IngressTemplate)(unsafe.Pointer(in.IngressTemplate))
+	out.ServiceTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -842,6 +966,100 @@ func Convert_acme_ACMEChallengeSolverHTTP01IngressTemplate_To_v1alpha2_ACMEChall
 	return autoConvert_acme_ACMEChallengeSolverHTTP01IngressTemplate_To_v1alpha2_ACMEChallengeSolverHTTP01IngressTemplate(in, out, s)
 }
 
+func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(in *v1alpha2.ACMEChallengeSolverHTTP01NodePortRange, out *acme.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	out.Min = in.Min
+	out.Max = in.Max
+	return nil
+}
+
+// Convert_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(in *v1alpha2.ACMEChallengeSolverHTTP01NodePortRange, out *acme.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange(in *acme.ACMEChallengeSolverHTTP01NodePortRange, out *v1alpha2.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	out.Min = in.Min
+	out.Max = in.Max
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange(in *acme.ACMEChallengeSolverHTTP01NodePortRange, out *v1alpha2.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1alpha2_ACMEChallengeSolverHTTP01NodePortRange(in, out, s)
+}
+
+func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(in *v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec, out *acme.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	out.NodePortRange = (*acme.ACMEChallengeSolverHTTP01NodePortRange)(unsafe.Pointer(in.NodePortRange))
+	out.ExternalTrafficPolicy = v1.ServiceExternalTrafficPolicyType(in.ExternalTrafficPolicy)
+	out.LoadBalancerClass = (*string)(unsafe.Pointer(in.LoadBalancerClass))
+	return nil
+}
+
+// Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(in *v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec, out *acme.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec(in *acme.ACMEChallengeSolverHTTP01ServiceSpec, out *v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	out.NodePortRange = (*v1alpha2.ACMEChallengeSolverHTTP01NodePortRange)(unsafe.Pointer(in.NodePortRange))
+	out.ExternalTrafficPolicy = v1.ServiceExternalTrafficPolicyType(in.ExternalTrafficPolicy)
+	out.LoadBalancerClass = (*string)(unsafe.Pointer(in.LoadBalancerClass))
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec(in *acme.ACMEChallengeSolverHTTP01ServiceSpec, out *v1alpha2.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(in *v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate, out *acme.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	if err := Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(in *v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate, out *acme.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate(in *acme.ACMEChallengeSolverHTTP01ServiceTemplate, out *v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	if err := Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate(in *acme.ACMEChallengeSolverHTTP01ServiceTemplate, out *v1alpha2.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1alpha2_ACMEChallengeSolverHTTP01ServiceTemplate(in, out, s)
+}
+
+func autoConvert_v1alpha2_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(in *v1alpha2.ACMEChallengeSolverTLSALPN01, out *acme.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	out.ServiceType = v1.ServiceType(in.ServiceType)
+	out.ServiceLabels = *(*map[string]string)(unsafe.Pointer(&in.ServiceLabels))
+	out.PodTemplate = (*acme.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
+	return nil
+}
+
+// Convert_v1alpha2_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01 is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(in *v1alpha2.ACMEChallengeSolverTLSALPN01, out *acme.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverTLSALPN01_To_v1alpha2_ACMEChallengeSolverTLSALPN01(in *acme.ACMEChallengeSolverTLSALPN01, out *v1alpha2.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	out.ServiceType = v1.ServiceType(in.ServiceType)
+	out.ServiceLabels = *(*map[string]string)(unsafe.Pointer(&in.ServiceLabels))
+	out.PodTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverTLSALPN01_To_v1alpha2_ACMEChallengeSolverTLSALPN01 is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverTLSALPN01_To_v1alpha2_ACMEChallengeSolverTLSALPN01(in *acme.ACMEChallengeSolverTLSALPN01, out *v1alpha2.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverTLSALPN01_To_v1alpha2_ACMEChallengeSolverTLSALPN01(in, out, s)
+}
+
 func autoConvert_v1alpha2_ACMEExternalAccountBinding_To_acme_ACMEExternalAccountBinding(in *v1alpha2.ACMEExternalAccountBinding, out *acme.ACMEExternalAccountBinding, s conversion.Scope) error {
 	out.KeyID = in.KeyID
 	if err := metav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.Key, &out.Key, s); err != nil {
@@ -900,6 +1118,8 @@ func autoConvert_v1alpha2_ACMEIssuer_To_acme_ACMEIssuer(in *v1alpha2.ACMEIssuer,
 	}
 	out.DisableAccountKeyGeneration = in.DisableAccountKeyGeneration
 	out.EnableDurationFeature = in.EnableDurationFeature
+	out.MaintenanceWindows = *(*[]acme.ACMEMaintenanceWindow)(unsafe.Pointer(&in.MaintenanceWindows))
+	out.Preflight = (*acme.ACMEIssuerPreflight)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -933,6 +1153,8 @@ func autoConvert_acme_ACMEIssuer_To_v1alpha2_ACMEIssuer(in *acme.ACMEIssuer, out
 	}
 	out.DisableAccountKeyGeneration = in.DisableAccountKeyGeneration
 	out.EnableDurationFeature = in.EnableDurationFeature
+	out.MaintenanceWindows = *(*[]v1alpha2.ACMEMaintenanceWindow)(unsafe.Pointer(&in.MaintenanceWindows))
+	out.Preflight = (*v1alpha2.ACMEIssuerPreflight)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -1256,9 +1478,32 @@ func Convert_acme_ACMEIssuerDNS01ProviderWebhook_To_v1alpha2_ACMEIssuerDNS01Prov
 	return autoConvert_acme_ACMEIssuerDNS01ProviderWebhook_To_v1alpha2_ACMEIssuerDNS01ProviderWebhook(in, out, s)
 }
 
+func autoConvert_v1alpha2_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(in *v1alpha2.ACMEIssuerPreflight, out *acme.ACMEIssuerPreflight, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_v1alpha2_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(in *v1alpha2.ACMEIssuerPreflight, out *acme.ACMEIssuerPreflight, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(in, out, s)
+}
+
+func autoConvert_acme_ACMEIssuerPreflight_To_v1alpha2_ACMEIssuerPreflight(in *acme.ACMEIssuerPreflight, out *v1alpha2.ACMEIssuerPreflight, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_acme_ACMEIssuerPreflight_To_v1alpha2_ACMEIssuerPreflight is an autogenerated conversion function.
+func Convert_acme_ACMEIssuerPreflight_To_v1alpha2_ACMEIssuerPreflight(in *acme.ACMEIssuerPreflight, out *v1alpha2.ACMEIssuerPreflight, s conversion.Scope) error {
+	return autoConvert_acme_ACMEIssuerPreflight_To_v1alpha2_ACMEIssuerPreflight(in, out, s)
+}
+
 func autoConvert_v1alpha2_ACMEIssuerStatus_To_acme_ACMEIssuerStatus(in *v1alpha2.ACMEIssuerStatus, out *acme.ACMEIssuerStatus, s conversion.Scope) error {
 	out.URI = in.URI
 	out.LastRegisteredEmail = in.LastRegisteredEmail
+	out.LastRegisteredEABKeyHash = in.LastRegisteredEABKeyHash
+	out.LastAccountKeyRollover = in.LastAccountKeyRollover
+	out.PreflightURI = in.PreflightURI
 	return nil
 }
 
@@ -1270,6 +1515,9 @@ func Convert_v1alpha2_ACMEIssuerStatus_To_acme_ACMEIssuerStatus(in *v1alpha2.ACM
 func autoConvert_acme_ACMEIssuerStatus_To_v1alpha2_ACMEIssuerStatus(in *acme.ACMEIssuerStatus, out *v1alpha2.ACMEIssuerStatus, s conversion.Scope) error {
 	out.URI = in.URI
 	out.LastRegisteredEmail = in.LastRegisteredEmail
+	out.LastRegisteredEABKeyHash = in.LastRegisteredEABKeyHash
+	out.LastAccountKeyRollover = in.LastAccountKeyRollover
+	out.PreflightURI = in.PreflightURI
 	return nil
 }
 
@@ -1278,6 +1526,30 @@ func Convert_acme_ACMEIssuerStatus_To_v1alpha2_ACMEIssuerStatus(in *acme.ACMEIss
 	return autoConvert_acme_ACMEIssuerStatus_To_v1alpha2_ACMEIssuerStatus(in, out, s)
 }
 
+func autoConvert_v1alpha2_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(in *v1alpha2.ACMEMaintenanceWindow, out *acme.ACMEMaintenanceWindow, s conversion.Scope) error {
+	out.Start = in.Start
+	out.End = (*pkgapismetav1.Time)(unsafe.Pointer(in.End))
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_v1alpha2_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(in *v1alpha2.ACMEMaintenanceWindow, out *acme.ACMEMaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(in, out, s)
+}
+
+func autoConvert_acme_ACMEMaintenanceWindow_To_v1alpha2_ACMEMaintenanceWindow(in *acme.ACMEMaintenanceWindow, out *v1alpha2.ACMEMaintenanceWindow, s conversion.Scope) error {
+	out.Start = in.Start
+	out.End = (*pkgapismetav1.Time)(unsafe.Pointer(in.End))
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_acme_ACMEMaintenanceWindow_To_v1alpha2_ACMEMaintenanceWindow is an autogenerated conversion function.
+func Convert_acme_ACMEMaintenanceWindow_To_v1alpha2_ACMEMaintenanceWindow(in *acme.ACMEMaintenanceWindow, out *v1alpha2.ACMEMaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_acme_ACMEMaintenanceWindow_To_v1alpha2_ACMEMaintenanceWindow(in, out, s)
+}
+
 func autoConvert_v1alpha2_AzureManagedIdentity_To_acme_AzureManagedIdentity(in *v1alpha2.AzureManagedIdentity, out *acme.AzureManagedIdentity, s conversion.Scope) error {
 	out.ClientID = in.ClientID
 	out.ResourceID = in.ResourceID
@@ -1356,6 +1628,28 @@ func Convert_acme_Challenge_To_v1alpha2_Challenge(in *acme.Challenge, out *v1alp
 	return autoConvert_acme_Challenge_To_v1alpha2_Challenge(in, out, s)
 }
 
+func autoConvert_v1alpha2_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(in *v1alpha2.ChallengeDNS01Status, out *acme.ChallengeDNS01Status, s conversion.Scope) error {
+	out.FQDN = in.FQDN
+	out.DelegatedZone = in.DelegatedZone
+	return nil
+}
+
+// Convert_v1alpha2_ChallengeDNS01Status_To_acme_ChallengeDNS01Status is an autogenerated conversion function.
+func Convert_v1alpha2_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(in *v1alpha2.ChallengeDNS01Status, out *acme.ChallengeDNS01Status, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(in, out, s)
+}
+
+func autoConvert_acme_ChallengeDNS01Status_To_v1alpha2_ChallengeDNS01Status(in *acme.ChallengeDNS01Status, out *v1alpha2.ChallengeDNS01Status, s conversion.Scope) error {
+	out.FQDN = in.FQDN
+	out.DelegatedZone = in.DelegatedZone
+	return nil
+}
+
+// Convert_acme_ChallengeDNS01Status_To_v1alpha2_ChallengeDNS01Status is an autogenerated conversion function.
+func Convert_acme_ChallengeDNS01Status_To_v1alpha2_ChallengeDNS01Status(in *acme.ChallengeDNS01Status, out *v1alpha2.ChallengeDNS01Status, s conversion.Scope) error {
+	return autoConvert_acme_ChallengeDNS01Status_To_v1alpha2_ChallengeDNS01Status(in, out, s)
+}
+
 func autoConvert_v1alpha2_ChallengeList_To_acme_ChallengeList(in *v1alpha2.ChallengeList, out *acme.ChallengeList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1412,6 +1706,7 @@ func autoConvert_v1alpha2_ChallengeSpec_To_acme_ChallengeSpec(in *v1alpha2.Chall
 	if err := metav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
+	out.Preflight = in.Preflight
 	return nil
 }
 
@@ -1429,6 +1724,7 @@ func autoConvert_acme_ChallengeSpec_To_v1alpha2_ChallengeSpec(in *acme.Challenge
 	if err := metav1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
+	out.Preflight = in.Preflight
 	return nil
 }
 
@@ -1437,6 +1733,7 @@ func autoConvert_v1alpha2_ChallengeStatus_To_acme_ChallengeStatus(in *v1alpha2.C
 	out.Presented = in.Presented
 	out.Reason = in.Reason
 	out.State = acme.State(in.State)
+	out.DNS01 = (*acme.ChallengeDNS01Status)(unsafe.Pointer(in.DNS01))
 	return nil
 }
 
@@ -1450,6 +1747,7 @@ func autoConvert_acme_ChallengeStatus_To_v1alpha2_ChallengeStatus(in *acme.Chall
 	out.Presented = in.Presented
 	out.Reason = in.Reason
 	out.State = v1alpha2.State(in.State)
+	out.DNS01 = (*v1alpha2.ChallengeDNS01Status)(unsafe.Pointer(in.DNS01))
 	return nil
 }
 
@@ -1532,6 +1830,32 @@ func Convert_acme_OrderList_To_v1alpha2_OrderList(in *acme.OrderList, out *v1alp
 	return autoConvert_acme_OrderList_To_v1alpha2_OrderList(in, out, s)
 }
 
+func autoConvert_v1alpha2_OrderPreflightStatus_To_acme_OrderPreflightStatus(in *v1alpha2.OrderPreflightStatus, out *acme.OrderPreflightStatus, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Authorizations = *(*[]acme.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
+	out.State = acme.State(in.State)
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_v1alpha2_OrderPreflightStatus_To_acme_OrderPreflightStatus is an autogenerated conversion function.
+func Convert_v1alpha2_OrderPreflightStatus_To_acme_OrderPreflightStatus(in *v1alpha2.OrderPreflightStatus, out *acme.OrderPreflightStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OrderPreflightStatus_To_acme_OrderPreflightStatus(in, out, s)
+}
+
+func autoConvert_acme_OrderPreflightStatus_To_v1alpha2_OrderPreflightStatus(in *acme.OrderPreflightStatus, out *v1alpha2.OrderPreflightStatus, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Authorizations = *(*[]v1alpha2.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
+	out.State = v1alpha2.State(in.State)
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_acme_OrderPreflightStatus_To_v1alpha2_OrderPreflightStatus is an autogenerated conversion function.
+func Convert_acme_OrderPreflightStatus_To_v1alpha2_OrderPreflightStatus(in *acme.OrderPreflightStatus, out *v1alpha2.OrderPreflightStatus, s conversion.Scope) error {
+	return autoConvert_acme_OrderPreflightStatus_To_v1alpha2_OrderPreflightStatus(in, out, s)
+}
+
 func autoConvert_v1alpha2_OrderSpec_To_acme_OrderSpec(in *v1alpha2.OrderSpec, out *acme.OrderSpec, s conversion.Scope) error {
 	// WARNING: in.CSR requires manual conversion: does not exist in peer-type
 	if err := metav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
@@ -1564,6 +1888,7 @@ func autoConvert_v1alpha2_OrderStatus_To_acme_OrderStatus(in *v1alpha2.OrderStat
 	out.State = acme.State(in.State)
 	out.Reason = in.Reason
 	out.FailureTime = (*pkgapismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.Preflight = (*acme.OrderPreflightStatus)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -1580,6 +1905,7 @@ func autoConvert_acme_OrderStatus_To_v1alpha2_OrderStatus(in *acme.OrderStatus,
 	out.Reason = in.Reason
 	out.Authorizations = *(*[]v1alpha2.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
 	out.FailureTime = (*pkgapismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.Preflight = (*v1alpha2.OrderPreflightStatus)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -103,6 +103,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), (*acme.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(a.(*v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef), b.(*acme.ACMEChallengeSolverHTTP01GatewayParentRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), (*v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef(a.(*acme.ACMEChallengeSolverHTTP01GatewayParentRef), b.(*v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEChallengeSolverHTTP01Ingress)(nil), (*acme.ACMEChallengeSolverHTTP01Ingress)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ACMEChallengeSolverHTTP01Ingress_To_acme_ACMEChallengeSolverHTTP01Ingress(a.(*v1beta1.ACMEChallengeSolverHTTP01Ingress), b.(*acme.ACMEChallengeSolverHTTP01Ingress), scope)
 	}); err != nil {
@@ -163,6 +173,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEChallengeSolverHTTP01NodePortRange)(nil), (*acme.ACMEChallengeSolverHTTP01NodePortRange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(a.(*v1beta1.ACMEChallengeSolverHTTP01NodePortRange), b.(*acme.ACMEChallengeSolverHTTP01NodePortRange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01NodePortRange)(nil), (*v1beta1.ACMEChallengeSolverHTTP01NodePortRange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1beta1_ACMEChallengeSolverHTTP01NodePortRange(a.(*acme.ACMEChallengeSolverHTTP01NodePortRange), b.(*v1beta1.ACMEChallengeSolverHTTP01NodePortRange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEChallengeSolverHTTP01ServiceSpec)(nil), (*acme.ACMEChallengeSolverHTTP01ServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(a.(*v1beta1.ACMEChallengeSolverHTTP01ServiceSpec), b.(*acme.ACMEChallengeSolverHTTP01ServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01ServiceSpec)(nil), (*v1beta1.ACMEChallengeSolverHTTP01ServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec(a.(*acme.ACMEChallengeSolverHTTP01ServiceSpec), b.(*v1beta1.ACMEChallengeSolverHTTP01ServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), (*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(a.(*v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate), b.(*acme.ACMEChallengeSolverHTTP01ServiceTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), (*v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate(a.(*acme.ACMEChallengeSolverHTTP01ServiceTemplate), b.(*v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEChallengeSolverTLSALPN01)(nil), (*acme.ACMEChallengeSolverTLSALPN01)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(a.(*v1beta1.ACMEChallengeSolverTLSALPN01), b.(*acme.ACMEChallengeSolverTLSALPN01), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverTLSALPN01)(nil), (*v1beta1.ACMEChallengeSolverTLSALPN01)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverTLSALPN01_To_v1beta1_ACMEChallengeSolverTLSALPN01(a.(*acme.ACMEChallengeSolverTLSALPN01), b.(*v1beta1.ACMEChallengeSolverTLSALPN01), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEExternalAccountBinding)(nil), (*acme.ACMEExternalAccountBinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ACMEExternalAccountBinding_To_acme_ACMEExternalAccountBinding(a.(*v1beta1.ACMEExternalAccountBinding), b.(*acme.ACMEExternalAccountBinding), scope)
 	}); err != nil {
@@ -263,6 +313,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEIssuerPreflight)(nil), (*acme.ACMEIssuerPreflight)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(a.(*v1beta1.ACMEIssuerPreflight), b.(*acme.ACMEIssuerPreflight), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEIssuerPreflight)(nil), (*v1beta1.ACMEIssuerPreflight)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEIssuerPreflight_To_v1beta1_ACMEIssuerPreflight(a.(*acme.ACMEIssuerPreflight), b.(*v1beta1.ACMEIssuerPreflight), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEIssuerStatus)(nil), (*acme.ACMEIssuerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ACMEIssuerStatus_To_acme_ACMEIssuerStatus(a.(*v1beta1.ACMEIssuerStatus), b.(*acme.ACMEIssuerStatus), scope)
 	}); err != nil {
@@ -273,6 +333,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ACMEMaintenanceWindow)(nil), (*acme.ACMEMaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(a.(*v1beta1.ACMEMaintenanceWindow), b.(*acme.ACMEMaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEMaintenanceWindow)(nil), (*v1beta1.ACMEMaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEMaintenanceWindow_To_v1beta1_ACMEMaintenanceWindow(a.(*acme.ACMEMaintenanceWindow), b.(*v1beta1.ACMEMaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureManagedIdentity)(nil), (*acme.AzureManagedIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AzureManagedIdentity_To_acme_AzureManagedIdentity(a.(*v1beta1.AzureManagedIdentity), b.(*acme.AzureManagedIdentity), scope)
 	}); err != nil {
@@ -303,6 +373,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ChallengeDNS01Status)(nil), (*acme.ChallengeDNS01Status)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(a.(*v1beta1.ChallengeDNS01Status), b.(*acme.ChallengeDNS01Status), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ChallengeDNS01Status)(nil), (*v1beta1.ChallengeDNS01Status)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ChallengeDNS01Status_To_v1beta1_ChallengeDNS01Status(a.(*acme.ChallengeDNS01Status), b.(*v1beta1.ChallengeDNS01Status), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.ChallengeList)(nil), (*acme.ChallengeList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ChallengeList_To_acme_ChallengeList(a.(*v1beta1.ChallengeList), b.(*acme.ChallengeList), scope)
 	}); err != nil {
@@ -353,6 +433,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.OrderPreflightStatus)(nil), (*acme.OrderPreflightStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_OrderPreflightStatus_To_acme_OrderPreflightStatus(a.(*v1beta1.OrderPreflightStatus), b.(*acme.OrderPreflightStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.OrderPreflightStatus)(nil), (*v1beta1.OrderPreflightStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_OrderPreflightStatus_To_v1beta1_OrderPreflightStatus(a.(*acme.OrderPreflightStatus), b.(*v1beta1.OrderPreflightStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.OrderSpec)(nil), (*acme.OrderSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_OrderSpec_To_acme_OrderSpec(a.(*v1beta1.OrderSpec), b.(*acme.OrderSpec), scope)
 	}); err != nil {
@@ -450,6 +540,7 @@ func autoConvert_v1beta1_ACMEChallengeSolver_To_acme_ACMEChallengeSolver(in *v1b
 	} else {
 		out.DNS01 = nil
 	}
+	out.TLSALPN01 = (*acme.ACMEChallengeSolverTLSALPN01)(unsafe.Pointer(in.TLSALPN01))
 	return nil
 }
 
@@ -470,6 +561,7 @@ func autoConvert_acme_ACMEChallengeSolver_To_v1beta1_ACMEChallengeSolver(in *acm
 	} else {
 		out.DNS01 = nil
 	}
+	out.TLSALPN01 = (*v1beta1.ACMEChallengeSolverTLSALPN01)(unsafe.Pointer(in.TLSALPN01))
 	return nil
 }
 
@@ -480,6 +572,8 @@ func Convert_acme_ACMEChallengeSolver_To_v1beta1_ACMEChallengeSolver(in *acme.AC
 
 func autoConvert_v1beta1_ACMEChallengeSolverDNS01_To_acme_ACMEChallengeSolverDNS01(in *v1beta1.ACMEChallengeSolverDNS01, out *acme.ACMEChallengeSolverDNS01, s conversion.Scope) error {
 	out.CNAMEStrategy = acme.CNAMEStrategy(in.CNAMEStrategy)
+	out.DelegatedZones = *(*[]string)(unsafe.Pointer(&in.DelegatedZones))
+	out.SecretsFromChallengeNamespace = in.SecretsFromChallengeNamespace
 	if in.Akamai != nil {
 		in, out := &in.Akamai, &out.Akamai
 		*out = new(acme.ACMEIssuerDNS01ProviderAkamai)
@@ -563,6 +657,8 @@ func Convert_v1beta1_ACMEChallengeSolverDNS01_To_acme_ACMEChallengeSolverDNS01(i
 
 func autoConvert_acme_ACMEChallengeSolverDNS01_To_v1beta1_ACMEChallengeSolverDNS01(in *acme.ACMEChallengeSolverDNS01, out *v1beta1.ACMEChallengeSolverDNS01, s conversion.Scope) error {
 	out.CNAMEStrategy = v1beta1.CNAMEStrategy(in.CNAMEStrategy)
+	out.DelegatedZones = *(*[]string)(unsafe.Pointer(&in.DelegatedZones))
+	out.SecretsFromChallengeNamespace = in.SecretsFromChallengeNamespace
 	if in.Akamai != nil {
 		in, out := &in.Akamai, &out.Akamai
 		*out = new(v1beta1.ACMEIssuerDNS01ProviderAkamai)
@@ -669,6 +765,8 @@ func Convert_acme_ACMEChallengeSolverHTTP01_To_v1beta1_ACMEChallengeSolverHTTP01
 func autoConvert_v1beta1_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute(in *v1beta1.ACMEChallengeSolverHTTP01GatewayHTTPRoute, out *acme.ACMEChallengeSolverHTTP01GatewayHTTPRoute, s conversion.Scope) error {
 	out.ServiceType = v1.ServiceType(in.ServiceType)
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.ParentRefs = *(*[]acme.ACMEChallengeSolverHTTP01GatewayParentRef)(unsafe.Pointer(&in.ParentRefs))
+	out.ServiceTemplate = (*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -680,6 +778,8 @@ func Convert_v1beta1_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_acme_ACMEChall
 func autoConvert_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_v1beta1_ACMEChallengeSolverHTTP01GatewayHTTPRoute(in *acme.ACMEChallengeSolverHTTP01GatewayHTTPRoute, out *v1beta1.ACMEChallengeSolverHTTP01GatewayHTTPRoute, s conversion.Scope) error {
 	out.ServiceType = v1.ServiceType(in.ServiceType)
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.ParentRefs = *(*[]v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef)(unsafe.Pointer(&in.ParentRefs))
+	out.ServiceTemplate = (*v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -688,12 +788,35 @@ func Convert_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_v1beta1_ACMEChall
 	return autoConvert_acme_ACMEChallengeSolverHTTP01GatewayHTTPRoute_To_v1beta1_ACMEChallengeSolverHTTP01GatewayHTTPRoute(in, out, s)
 }
 
+func autoConvert_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(in *v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef, out *acme.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef is an autogenerated conversion function.
+func Convert_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(in *v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef, out *acme.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef_To_acme_ACMEChallengeSolverHTTP01GatewayParentRef(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef(in *acme.ACMEChallengeSolverHTTP01GatewayParentRef, out *v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef(in *acme.ACMEChallengeSolverHTTP01GatewayParentRef, out *v1beta1.ACMEChallengeSolverHTTP01GatewayParentRef, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01GatewayParentRef_To_v1beta1_ACMEChallengeSolverHTTP01GatewayParentRef(in, out, s)
+}
+
 func autoConvert_v1beta1_ACMEChallengeSolverHTTP01Ingress_To_acme_ACMEChallengeSolverHTTP01Ingress(in *v1beta1.ACMEChallengeSolverHTTP01Ingress, out *acme.ACMEChallengeSolverHTTP01Ingress, s conversion.Scope) error {
 	out.ServiceType = v1.ServiceType(in.ServiceType)
 	out.Class = (*string)(unsafe.Pointer(in.Class))
 	out.Name = in.Name
 	out.PodTemplate = (*acme.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	out.IngressTemplate = (*acme.ACMEChallengeSolverHTTP01IngressTemplate)(unsafe.Pointer(in.IngressTemplate))
+	out.ServiceTemplate = (*acme.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -708,6 +831,7 @@ func autoConvert_acme_ACMEChallengeSolverHTTP01Ingress_To_v1beta1_ACMEChallengeS
 	out.Name = in.Name
 	out.PodTemplate = (*v1beta1.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	out.IngressTemplate = (*v1beta1.ACMEChallengeSolverHTTP01IngressTemplate)(unsafe.Pointer(in.IngressTemplate))
+	out.ServiceTemplate = (*v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate)(unsafe.Pointer(in.ServiceTemplate))
 	return nil
 }
 
@@ -842,6 +966,100 @@ func Convert_acme_ACMEChallengeSolverHTTP01IngressTemplate_To_v1beta1_ACMEChalle
 	return autoConvert_acme_ACMEChallengeSolverHTTP01IngressTemplate_To_v1beta1_ACMEChallengeSolverHTTP01IngressTemplate(in, out, s)
 }
 
+func autoConvert_v1beta1_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(in *v1beta1.ACMEChallengeSolverHTTP01NodePortRange, out *acme.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	out.Min = in.Min
+	out.Max = in.Max
+	return nil
+}
+
+// Convert_v1beta1_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange is an autogenerated conversion function.
+func Convert_v1beta1_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(in *v1beta1.ACMEChallengeSolverHTTP01NodePortRange, out *acme.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEChallengeSolverHTTP01NodePortRange_To_acme_ACMEChallengeSolverHTTP01NodePortRange(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1beta1_ACMEChallengeSolverHTTP01NodePortRange(in *acme.ACMEChallengeSolverHTTP01NodePortRange, out *v1beta1.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	out.Min = in.Min
+	out.Max = in.Max
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1beta1_ACMEChallengeSolverHTTP01NodePortRange is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1beta1_ACMEChallengeSolverHTTP01NodePortRange(in *acme.ACMEChallengeSolverHTTP01NodePortRange, out *v1beta1.ACMEChallengeSolverHTTP01NodePortRange, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01NodePortRange_To_v1beta1_ACMEChallengeSolverHTTP01NodePortRange(in, out, s)
+}
+
+func autoConvert_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(in *v1beta1.ACMEChallengeSolverHTTP01ServiceSpec, out *acme.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	out.NodePortRange = (*acme.ACMEChallengeSolverHTTP01NodePortRange)(unsafe.Pointer(in.NodePortRange))
+	out.ExternalTrafficPolicy = v1.ServiceExternalTrafficPolicyType(in.ExternalTrafficPolicy)
+	out.LoadBalancerClass = (*string)(unsafe.Pointer(in.LoadBalancerClass))
+	return nil
+}
+
+// Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec is an autogenerated conversion function.
+func Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(in *v1beta1.ACMEChallengeSolverHTTP01ServiceSpec, out *acme.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec(in *acme.ACMEChallengeSolverHTTP01ServiceSpec, out *v1beta1.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	out.NodePortRange = (*v1beta1.ACMEChallengeSolverHTTP01NodePortRange)(unsafe.Pointer(in.NodePortRange))
+	out.ExternalTrafficPolicy = v1.ServiceExternalTrafficPolicyType(in.ExternalTrafficPolicy)
+	out.LoadBalancerClass = (*string)(unsafe.Pointer(in.LoadBalancerClass))
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec(in *acme.ACMEChallengeSolverHTTP01ServiceSpec, out *v1beta1.ACMEChallengeSolverHTTP01ServiceSpec, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(in *v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate, out *acme.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	if err := Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec_To_acme_ACMEChallengeSolverHTTP01ServiceSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate is an autogenerated conversion function.
+func Convert_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(in *v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate, out *acme.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate_To_acme_ACMEChallengeSolverHTTP01ServiceTemplate(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate(in *acme.ACMEChallengeSolverHTTP01ServiceTemplate, out *v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	if err := Convert_acme_ACMEChallengeSolverHTTP01ServiceSpec_To_v1beta1_ACMEChallengeSolverHTTP01ServiceSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate(in *acme.ACMEChallengeSolverHTTP01ServiceTemplate, out *v1beta1.ACMEChallengeSolverHTTP01ServiceTemplate, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHTTP01ServiceTemplate_To_v1beta1_ACMEChallengeSolverHTTP01ServiceTemplate(in, out, s)
+}
+
+func autoConvert_v1beta1_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(in *v1beta1.ACMEChallengeSolverTLSALPN01, out *acme.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	out.ServiceType = v1.ServiceType(in.ServiceType)
+	out.ServiceLabels = *(*map[string]string)(unsafe.Pointer(&in.ServiceLabels))
+	out.PodTemplate = (*acme.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
+	return nil
+}
+
+// Convert_v1beta1_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01 is an autogenerated conversion function.
+func Convert_v1beta1_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(in *v1beta1.ACMEChallengeSolverTLSALPN01, out *acme.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEChallengeSolverTLSALPN01_To_acme_ACMEChallengeSolverTLSALPN01(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverTLSALPN01_To_v1beta1_ACMEChallengeSolverTLSALPN01(in *acme.ACMEChallengeSolverTLSALPN01, out *v1beta1.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	out.ServiceType = v1.ServiceType(in.ServiceType)
+	out.ServiceLabels = *(*map[string]string)(unsafe.Pointer(&in.ServiceLabels))
+	out.PodTemplate = (*v1beta1.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverTLSALPN01_To_v1beta1_ACMEChallengeSolverTLSALPN01 is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverTLSALPN01_To_v1beta1_ACMEChallengeSolverTLSALPN01(in *acme.ACMEChallengeSolverTLSALPN01, out *v1beta1.ACMEChallengeSolverTLSALPN01, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverTLSALPN01_To_v1beta1_ACMEChallengeSolverTLSALPN01(in, out, s)
+}
+
 func autoConvert_v1beta1_ACMEExternalAccountBinding_To_acme_ACMEExternalAccountBinding(in *v1beta1.ACMEExternalAccountBinding, out *acme.ACMEExternalAccountBinding, s conversion.Scope) error {
 	out.KeyID = in.KeyID
 	if err := metav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.Key, &out.Key, s); err != nil {
@@ -900,6 +1118,8 @@ func autoConvert_v1beta1_ACMEIssuer_To_acme_ACMEIssuer(in *v1beta1.ACMEIssuer, o
 	}
 	out.DisableAccountKeyGeneration = in.DisableAccountKeyGeneration
 	out.EnableDurationFeature = in.EnableDurationFeature
+	out.MaintenanceWindows = *(*[]acme.ACMEMaintenanceWindow)(unsafe.Pointer(&in.MaintenanceWindows))
+	out.Preflight = (*acme.ACMEIssuerPreflight)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -933,6 +1153,8 @@ func autoConvert_acme_ACMEIssuer_To_v1beta1_ACMEIssuer(in *acme.ACMEIssuer, out
 	}
 	out.DisableAccountKeyGeneration = in.DisableAccountKeyGeneration
 	out.EnableDurationFeature = in.EnableDurationFeature
+	out.MaintenanceWindows = *(*[]v1beta1.ACMEMaintenanceWindow)(unsafe.Pointer(&in.MaintenanceWindows))
+	out.Preflight = (*v1beta1.ACMEIssuerPreflight)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -1256,9 +1478,32 @@ func Convert_acme_ACMEIssuerDNS01ProviderWebhook_To_v1beta1_ACMEIssuerDNS01Provi
 	return autoConvert_acme_ACMEIssuerDNS01ProviderWebhook_To_v1beta1_ACMEIssuerDNS01ProviderWebhook(in, out, s)
 }
 
+func autoConvert_v1beta1_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(in *v1beta1.ACMEIssuerPreflight, out *acme.ACMEIssuerPreflight, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_v1beta1_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight is an autogenerated conversion function.
+func Convert_v1beta1_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(in *v1beta1.ACMEIssuerPreflight, out *acme.ACMEIssuerPreflight, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEIssuerPreflight_To_acme_ACMEIssuerPreflight(in, out, s)
+}
+
+func autoConvert_acme_ACMEIssuerPreflight_To_v1beta1_ACMEIssuerPreflight(in *acme.ACMEIssuerPreflight, out *v1beta1.ACMEIssuerPreflight, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_acme_ACMEIssuerPreflight_To_v1beta1_ACMEIssuerPreflight is an autogenerated conversion function.
+func Convert_acme_ACMEIssuerPreflight_To_v1beta1_ACMEIssuerPreflight(in *acme.ACMEIssuerPreflight, out *v1beta1.ACMEIssuerPreflight, s conversion.Scope) error {
+	return autoConvert_acme_ACMEIssuerPreflight_To_v1beta1_ACMEIssuerPreflight(in, out, s)
+}
+
 func autoConvert_v1beta1_ACMEIssuerStatus_To_acme_ACMEIssuerStatus(in *v1beta1.ACMEIssuerStatus, out *acme.ACMEIssuerStatus, s conversion.Scope) error {
 	out.URI = in.URI
 	out.LastRegisteredEmail = in.LastRegisteredEmail
+	out.LastRegisteredEABKeyHash = in.LastRegisteredEABKeyHash
+	out.LastAccountKeyRollover = in.LastAccountKeyRollover
+	out.PreflightURI = in.PreflightURI
 	return nil
 }
 
@@ -1270,6 +1515,9 @@ func Convert_v1beta1_ACMEIssuerStatus_To_acme_ACMEIssuerStatus(in *v1beta1.ACMEI
 func autoConvert_acme_ACMEIssuerStatus_To_v1beta1_ACMEIssuerStatus(in *acme.ACMEIssuerStatus, out *v1beta1.ACMEIssuerStatus, s conversion.Scope) error {
 	out.URI = in.URI
 	out.LastRegisteredEmail = in.LastRegisteredEmail
+	out.LastRegisteredEABKeyHash = in.LastRegisteredEABKeyHash
+	out.LastAccountKeyRollover = in.LastAccountKeyRollover
+	out.PreflightURI = in.PreflightURI
 	return nil
 }
 
@@ -1278,6 +1526,30 @@ func Convert_acme_ACMEIssuerStatus_To_v1beta1_ACMEIssuerStatus(in *acme.ACMEIssu
 	return autoConvert_acme_ACMEIssuerStatus_To_v1beta1_ACMEIssuerStatus(in, out, s)
 }
 
+func autoConvert_v1beta1_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(in *v1beta1.ACMEMaintenanceWindow, out *acme.ACMEMaintenanceWindow, s conversion.Scope) error {
+	out.Start = in.Start
+	out.End = (*pkgapismetav1.Time)(unsafe.Pointer(in.End))
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_v1beta1_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow is an autogenerated conversion function.
+func Convert_v1beta1_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(in *v1beta1.ACMEMaintenanceWindow, out *acme.ACMEMaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_v1beta1_ACMEMaintenanceWindow_To_acme_ACMEMaintenanceWindow(in, out, s)
+}
+
+func autoConvert_acme_ACMEMaintenanceWindow_To_v1beta1_ACMEMaintenanceWindow(in *acme.ACMEMaintenanceWindow, out *v1beta1.ACMEMaintenanceWindow, s conversion.Scope) error {
+	out.Start = in.Start
+	out.End = (*pkgapismetav1.Time)(unsafe.Pointer(in.End))
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_acme_ACMEMaintenanceWindow_To_v1beta1_ACMEMaintenanceWindow is an autogenerated conversion function.
+func Convert_acme_ACMEMaintenanceWindow_To_v1beta1_ACMEMaintenanceWindow(in *acme.ACMEMaintenanceWindow, out *v1beta1.ACMEMaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_acme_ACMEMaintenanceWindow_To_v1beta1_ACMEMaintenanceWindow(in, out, s)
+}
+
 func autoConvert_v1beta1_AzureManagedIdentity_To_acme_AzureManagedIdentity(in *v1beta1.AzureManagedIdentity, out *acme.AzureManagedIdentity, s conversion.Scope) error {
 	out.ClientID = in.ClientID
 	out.ResourceID = in.ResourceID
@@ -1356,6 +1628,28 @@ func Convert_acme_Challenge_To_v1beta1_Challenge(in *acme.Challenge, out *v1beta
 	return autoConvert_acme_Challenge_To_v1beta1_Challenge(in, out, s)
 }
 
+func autoConvert_v1beta1_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(in *v1beta1.ChallengeDNS01Status, out *acme.ChallengeDNS01Status, s conversion.Scope) error {
+	out.FQDN = in.FQDN
+	out.DelegatedZone = in.DelegatedZone
+	return nil
+}
+
+// Convert_v1beta1_ChallengeDNS01Status_To_acme_ChallengeDNS01Status is an autogenerated conversion function.
+func Convert_v1beta1_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(in *v1beta1.ChallengeDNS01Status, out *acme.ChallengeDNS01Status, s conversion.Scope) error {
+	return autoConvert_v1beta1_ChallengeDNS01Status_To_acme_ChallengeDNS01Status(in, out, s)
+}
+
+func autoConvert_acme_ChallengeDNS01Status_To_v1beta1_ChallengeDNS01Status(in *acme.ChallengeDNS01Status, out *v1beta1.ChallengeDNS01Status, s conversion.Scope) error {
+	out.FQDN = in.FQDN
+	out.DelegatedZone = in.DelegatedZone
+	return nil
+}
+
+// Convert_acme_ChallengeDNS01Status_To_v1beta1_ChallengeDNS01Status is an autogenerated conversion function.
+func Convert_acme_ChallengeDNS01Status_To_v1beta1_ChallengeDNS01Status(in *acme.ChallengeDNS01Status, out *v1beta1.ChallengeDNS01Status, s conversion.Scope) error {
+	return autoConvert_acme_ChallengeDNS01Status_To_v1beta1_ChallengeDNS01Status(in, out, s)
+}
+
 func autoConvert_v1beta1_ChallengeList_To_acme_ChallengeList(in *v1beta1.ChallengeList, out *acme.ChallengeList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1412,6 +1706,7 @@ func autoConvert_v1beta1_ChallengeSpec_To_acme_ChallengeSpec(in *v1beta1.Challen
 	if err := metav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
+	out.Preflight = in.Preflight
 	return nil
 }
 
@@ -1434,6 +1729,7 @@ func autoConvert_acme_ChallengeSpec_To_v1beta1_ChallengeSpec(in *acme.ChallengeS
 	if err := metav1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
+	out.Preflight = in.Preflight
 	return nil
 }
 
@@ -1447,6 +1743,7 @@ func autoConvert_v1beta1_ChallengeStatus_To_acme_ChallengeStatus(in *v1beta1.Cha
 	out.Presented = in.Presented
 	out.Reason = in.Reason
 	out.State = acme.State(in.State)
+	out.DNS01 = (*acme.ChallengeDNS01Status)(unsafe.Pointer(in.DNS01))
 	return nil
 }
 
@@ -1460,6 +1757,7 @@ func autoConvert_acme_ChallengeStatus_To_v1beta1_ChallengeStatus(in *acme.Challe
 	out.Presented = in.Presented
 	out.Reason = in.Reason
 	out.State = v1beta1.State(in.State)
+	out.DNS01 = (*v1beta1.ChallengeDNS01Status)(unsafe.Pointer(in.DNS01))
 	return nil
 }
 
@@ -1542,6 +1840,32 @@ func Convert_acme_OrderList_To_v1beta1_OrderList(in *acme.OrderList, out *v1beta
 	return autoConvert_acme_OrderList_To_v1beta1_OrderList(in, out, s)
 }
 
+func autoConvert_v1beta1_OrderPreflightStatus_To_acme_OrderPreflightStatus(in *v1beta1.OrderPreflightStatus, out *acme.OrderPreflightStatus, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Authorizations = *(*[]acme.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
+	out.State = acme.State(in.State)
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_v1beta1_OrderPreflightStatus_To_acme_OrderPreflightStatus is an autogenerated conversion function.
+func Convert_v1beta1_OrderPreflightStatus_To_acme_OrderPreflightStatus(in *v1beta1.OrderPreflightStatus, out *acme.OrderPreflightStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_OrderPreflightStatus_To_acme_OrderPreflightStatus(in, out, s)
+}
+
+func autoConvert_acme_OrderPreflightStatus_To_v1beta1_OrderPreflightStatus(in *acme.OrderPreflightStatus, out *v1beta1.OrderPreflightStatus, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Authorizations = *(*[]v1beta1.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
+	out.State = v1beta1.State(in.State)
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_acme_OrderPreflightStatus_To_v1beta1_OrderPreflightStatus is an autogenerated conversion function.
+func Convert_acme_OrderPreflightStatus_To_v1beta1_OrderPreflightStatus(in *acme.OrderPreflightStatus, out *v1beta1.OrderPreflightStatus, s conversion.Scope) error {
+	return autoConvert_acme_OrderPreflightStatus_To_v1beta1_OrderPreflightStatus(in, out, s)
+}
+
 func autoConvert_v1beta1_OrderSpec_To_acme_OrderSpec(in *v1beta1.OrderSpec, out *acme.OrderSpec, s conversion.Scope) error {
 	out.Request = *(*[]byte)(unsafe.Pointer(&in.Request))
 	if err := metav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
@@ -1584,6 +1908,7 @@ func autoConvert_v1beta1_OrderStatus_To_acme_OrderStatus(in *v1beta1.OrderStatus
 	out.State = acme.State(in.State)
 	out.Reason = in.Reason
 	out.FailureTime = (*pkgapismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.Preflight = (*acme.OrderPreflightStatus)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -1600,6 +1925,7 @@ func autoConvert_acme_OrderStatus_To_v1beta1_OrderStatus(in *acme.OrderStatus, o
 	out.Reason = in.Reason
 	out.Authorizations = *(*[]v1beta1.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
 	out.FailureTime = (*pkgapismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.Preflight = (*v1beta1.OrderPreflightStatus)(unsafe.Pointer(in.Preflight))
 	return nil
 }
 
@@ -19,18 +19,34 @@ limitations under the License.
 package certificaterequests
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 
 	"github.com/jetstack/cert-manager/internal/api/validation"
 	cmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
 	"github.com/jetstack/cert-manager/pkg/util"
 )
 
+const (
+	// maxObjectSize is the default maximum size of a request to etcd, which
+	// limits the size of any resource that can be stored by the apiserver.
+	maxObjectSize = 1536 * 1024
+
+	// objectSizeWarningThreshold is the size of a CertificateRequest above
+	// which a warning is returned, as appending to its approval history or
+	// conditions will soon cause updates to be rejected.
+	objectSizeWarningThreshold = maxObjectSize * 9 / 10
+)
+
 func ValidateCreate(req *admissionv1.AdmissionRequest, obj runtime.Object) (field.ErrorList, validation.WarningList) {
 	cr := obj.(*cmapi.CertificateRequest)
 	fldPath := field.NewPath("spec")
@@ -48,8 +64,11 @@ func ValidateCreate(req *admissionv1.AdmissionRequest, obj runtime.Object) (fiel
 	if !extrasMatch(cr.Spec.Extra, req.UserInfo.Extra) {
 		el = append(el, field.Forbidden(fldPath.Child("extra"), "extra identity must be that of the requester"))
 	}
+	if !reflect.DeepEqual(cr.Spec.RequesterIdentity, requesterIdentity(req.UserInfo)) {
+		el = append(el, field.Forbidden(fldPath.Child("requesterIdentity"), "requesterIdentity must be that of the requester"))
+	}
 
-	return el, nil
+	return el, objectSizeWarnings(cr)
 }
 
 func extrasMatch(crExtra map[string][]string, reqExtra map[string]authenticationv1.ExtraValue) bool {
@@ -88,8 +107,24 @@ func ValidateUpdate(_ *admissionv1.AdmissionRequest, oldObj, newObj runtime.Obje
 	if !reflect.DeepEqual(oldCR.Spec.Extra, newCR.Spec.Extra) {
 		el = append(el, field.Forbidden(fldPath.Child("extra"), "extra identity cannot be changed once set"))
 	}
+	if !reflect.DeepEqual(oldCR.Spec.RequesterIdentity, newCR.Spec.RequesterIdentity) {
+		el = append(el, field.Forbidden(fldPath.Child("requesterIdentity"), "requesterIdentity cannot be changed once set"))
+	}
+
+	return el, objectSizeWarnings(newCR)
+}
+
+// objectSizeWarnings returns a warning if the serialized CertificateRequest,
+// including any approval records appended by the webhook, is approaching the
+// maximum object size.
+func objectSizeWarnings(cr *cmapi.CertificateRequest) validation.WarningList {
+	data, err := json.Marshal(cr)
+	if err != nil || len(data) <= objectSizeWarningThreshold {
+		return nil
+	}
 
-	return el, nil
+	return validation.WarningList{fmt.Sprintf("CertificateRequest is approximately %d bytes, close to the maximum object size of %d bytes; "+
+		"further updates to its status, such as approval records, may be rejected", len(data), maxObjectSize)}
 }
 
 func MutateCreate(req *admissionv1.AdmissionRequest, obj runtime.Object) {
@@ -103,7 +138,74 @@ func MutateCreate(req *admissionv1.AdmissionRequest, obj runtime.Object) {
 	for k, v := range userInfo.Extra {
 		cr.Spec.Extra[k] = v
 	}
+	cr.Spec.RequesterIdentity = requesterIdentity(userInfo)
+
+	// Any approval history submitted by the requester is discarded.
+	cr.Status.ApprovalHistory = appendApprovalRecords(req, nil, nil, cr)
+}
+
+// MutateUpdate records the identity of the requester in the approval history
+// of the CertificateRequest if the request approves or denies it. Any other
+// changes made to the approval history are discarded.
+func MutateUpdate(req *admissionv1.AdmissionRequest, oldObj, newObj runtime.Object) {
+	oldCR, newCR := oldObj.(*cmapi.CertificateRequest), newObj.(*cmapi.CertificateRequest)
+	newCR.Status.ApprovalHistory = appendApprovalRecords(req, oldCR.Status.ApprovalHistory, oldCR, newCR)
 }
 
-func MutateUpdate(_ *admissionv1.AdmissionRequest, _, _ runtime.Object) {
+// requesterIdentity resolves the namespace, the ServiceAccount and, for bound
+// tokens, the Pod of the requester. It returns nil if the requester is not a
+// ServiceAccount.
+func requesterIdentity(userInfo authenticationv1.UserInfo) *cmapi.CertificateRequestRequesterIdentity {
+	namespace, name, err := serviceaccount.SplitUsername(userInfo.Username)
+	if err != nil {
+		return nil
+	}
+
+	identity := &cmapi.CertificateRequestRequesterIdentity{
+		Namespace:      namespace,
+		ServiceAccount: name,
+	}
+	if podNames := userInfo.Extra[serviceaccount.PodNameKey]; len(podNames) == 1 {
+		identity.Pod = podNames[0]
+	}
+
+	return identity
+}
+
+// appendApprovalRecords appends a record to history for each of the Approved
+// and Denied conditions that are set to True on newCR, but not on oldCR.
+func appendApprovalRecords(req *admissionv1.AdmissionRequest, history []cmapi.CertificateRequestApprovalRecord, oldCR, newCR *cmapi.CertificateRequest) []cmapi.CertificateRequestApprovalRecord {
+	history = append([]cmapi.CertificateRequestApprovalRecord(nil), history...)
+
+	for _, condType := range []cmapi.CertificateRequestConditionType{cmapi.CertificateRequestConditionApproved, cmapi.CertificateRequestConditionDenied} {
+		cond := getTrueCondition(newCR, condType)
+		if cond == nil || (oldCR != nil && getTrueCondition(oldCR, condType) != nil) {
+			continue
+		}
+
+		decisionTime := metav1.Now()
+		if cond.LastTransitionTime != nil {
+			decisionTime = *cond.LastTransitionTime
+		}
+
+		history = append(history, cmapi.CertificateRequestApprovalRecord{
+			Type:     condType,
+			Username: req.UserInfo.Username,
+			Groups:   append([]string(nil), req.UserInfo.Groups...),
+			Reason:   cond.Reason,
+			Message:  cond.Message,
+			Time:     decisionTime,
+		})
+	}
+
+	return history
+}
+
+func getTrueCondition(cr *cmapi.CertificateRequest, condType cmapi.CertificateRequestConditionType) *cmapi.CertificateRequestCondition {
+	for i, cond := range cr.Status.Conditions {
+		if cond.Type == condType && cond.Status == cmmeta.ConditionTrue {
+			return &cr.Status.Conditions[i]
+		}
+	}
+	return nil
 }
@@ -17,15 +17,19 @@ limitations under the License.
 package certificaterequests
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/internal/api/validation"
 	cmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
 )
 
 func TestValidateCreate(t *testing.T) {
@@ -67,6 +71,68 @@ func TestValidateCreate(t *testing.T) {
 				field.Forbidden(fldPath.Child("extra"), "extra identity must be that of the requester"),
 			},
 		},
+		"if the requester identity is not that of the requesting ServiceAccount, should fail": {
+			req: &admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:team-b:other",
+				},
+			},
+			cr: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					Username: "system:serviceaccount:team-b:other",
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-a",
+						ServiceAccount: "app",
+					},
+				},
+			},
+			wantE: field.ErrorList{
+				field.Forbidden(fldPath.Child("requesterIdentity"), "requesterIdentity must be that of the requester"),
+			},
+		},
+		"if a requester identity is set for a requester that is not a ServiceAccount, should fail": {
+			req: &admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "user-1",
+				},
+			},
+			cr: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					Username: "user-1",
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-a",
+						ServiceAccount: "app",
+					},
+				},
+			},
+			wantE: field.ErrorList{
+				field.Forbidden(fldPath.Child("requesterIdentity"), "requesterIdentity must be that of the requester"),
+			},
+		},
+		"if the requester identity matches the requesting ServiceAccount, should pass": {
+			req: &admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:team-a:app",
+					Extra: map[string]authenticationv1.ExtraValue{
+						"authentication.kubernetes.io/pod-name": []string{"app-1234"},
+					},
+				},
+			},
+			cr: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					Username: "system:serviceaccount:team-a:app",
+					Extra: map[string][]string{
+						"authentication.kubernetes.io/pod-name": {"app-1234"},
+					},
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-a",
+						ServiceAccount: "app",
+						Pod:            "app-1234",
+					},
+				},
+			},
+			wantE: nil,
+		},
 		"if identity fields match that of requester, should pass": {
 			req: &admissionv1.AdmissionRequest{
 				UserInfo: authenticationv1.UserInfo{
@@ -110,6 +176,21 @@ func TestValidateCreate(t *testing.T) {
 func TestValidateUpdate(t *testing.T) {
 	fldPath := field.NewPath("spec")
 
+	largeCR := &cmapi.CertificateRequest{
+		Status: cmapi.CertificateRequestStatus{
+			Certificate: make([]byte, objectSizeWarningThreshold),
+			ApprovalHistory: []cmapi.CertificateRequestApprovalRecord{
+				{Type: cmapi.CertificateRequestConditionApproved, Username: "approver"},
+			},
+		},
+	}
+	largeCRData, err := json.Marshal(largeCR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	largeCRWarning := fmt.Sprintf("CertificateRequest is approximately %d bytes, close to the maximum object size of 1572864 bytes; "+
+		"further updates to its status, such as approval records, may be rejected", len(largeCRData))
+
 	tests := map[string]struct {
 		oldCR, newCR *cmapi.CertificateRequest
 		wantE        field.ErrorList
@@ -170,6 +251,32 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			wantE: nil,
 		},
+		"if the requester identity doesn't match that of the old CertificateRequest, should fail": {
+			oldCR: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-a",
+						ServiceAccount: "app",
+					},
+				},
+			},
+			newCR: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-b",
+						ServiceAccount: "other",
+					},
+				},
+			},
+			wantE: field.ErrorList{
+				field.Forbidden(fldPath.Child("requesterIdentity"), "requesterIdentity cannot be changed once set"),
+			},
+		},
+		"if the CertificateRequest is close to the maximum object size, should warn": {
+			oldCR: largeCR,
+			newCR: largeCR,
+			wantW: validation.WarningList{largeCRWarning},
+		},
 	}
 
 	for name, test := range tests {
@@ -250,6 +357,59 @@ func TestMutateCreate(t *testing.T) {
 				},
 			},
 		},
+		"should record the requester identity if the requester is a ServiceAccount": {
+			req: &admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					UID:      "abc",
+					Username: "system:serviceaccount:team-a:app",
+					Groups:   []string{"system:serviceaccounts"},
+					Extra: map[string]authenticationv1.ExtraValue{
+						"authentication.kubernetes.io/pod-name": []string{"app-1234"},
+					},
+				},
+			},
+			existingCR: new(cmapi.CertificateRequest),
+			expectedCR: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					UID:      "abc",
+					Username: "system:serviceaccount:team-a:app",
+					Groups:   []string{"system:serviceaccounts"},
+					Extra: map[string][]string{
+						"authentication.kubernetes.io/pod-name": {"app-1234"},
+					},
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-a",
+						ServiceAccount: "app",
+						Pod:            "app-1234",
+					},
+				},
+			},
+		},
+		"should not record a pod if the ServiceAccount token is not bound to one": {
+			req: &admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					UID:      "abc",
+					Username: "system:serviceaccount:team-a:app",
+					Extra: map[string]authenticationv1.ExtraValue{
+						"1": []string{"abc"},
+					},
+				},
+			},
+			existingCR: new(cmapi.CertificateRequest),
+			expectedCR: &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					UID:      "abc",
+					Username: "system:serviceaccount:team-a:app",
+					Extra: map[string][]string{
+						"1": {"abc"},
+					},
+					RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+						Namespace:      "team-a",
+						ServiceAccount: "app",
+					},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -262,3 +422,79 @@ func TestMutateCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestMutateUpdate(t *testing.T) {
+	decisionTime := metav1.Now()
+	req := &admissionv1.AdmissionRequest{
+		UserInfo: authenticationv1.UserInfo{
+			Username: "user-1",
+			Groups:   []string{"group-1"},
+		},
+	}
+	existingRecord := cmapi.CertificateRequestApprovalRecord{
+		Type:     cmapi.CertificateRequestConditionApproved,
+		Username: "user-2",
+		Reason:   "cert-manager.io",
+		Time:     decisionTime,
+	}
+	condition := func(condType cmapi.CertificateRequestConditionType, status cmmeta.ConditionStatus) cmapi.CertificateRequestCondition {
+		return cmapi.CertificateRequestCondition{
+			Type:               condType,
+			Status:             status,
+			Reason:             "policy.cert-manager.io",
+			Message:            `Denied by CertificateRequestPolicy "team-a"`,
+			LastTransitionTime: &decisionTime,
+		}
+	}
+	cr := func(history []cmapi.CertificateRequestApprovalRecord, conditions ...cmapi.CertificateRequestCondition) *cmapi.CertificateRequest {
+		return &cmapi.CertificateRequest{
+			Status: cmapi.CertificateRequestStatus{
+				Conditions:      conditions,
+				ApprovalHistory: history,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		oldCR, newCR *cmapi.CertificateRequest
+		expHistory   []cmapi.CertificateRequestApprovalRecord
+	}{
+		"should not add a record if the request is not approved or denied": {
+			oldCR: cr(nil),
+			newCR: cr(nil, condition(cmapi.CertificateRequestConditionReady, cmmeta.ConditionFalse)),
+		},
+		"should add a record with the identity of the requester when the request is denied": {
+			oldCR: cr(nil),
+			newCR: cr(nil, condition(cmapi.CertificateRequestConditionDenied, cmmeta.ConditionTrue)),
+			expHistory: []cmapi.CertificateRequestApprovalRecord{
+				{
+					Type:     cmapi.CertificateRequestConditionDenied,
+					Username: "user-1",
+					Groups:   []string{"group-1"},
+					Reason:   "policy.cert-manager.io",
+					Message:  `Denied by CertificateRequestPolicy "team-a"`,
+					Time:     decisionTime,
+				},
+			},
+		},
+		"should not add a record if the condition was already set": {
+			oldCR:      cr([]cmapi.CertificateRequestApprovalRecord{existingRecord}, condition(cmapi.CertificateRequestConditionApproved, cmmeta.ConditionTrue)),
+			newCR:      cr([]cmapi.CertificateRequestApprovalRecord{existingRecord}, condition(cmapi.CertificateRequestConditionApproved, cmmeta.ConditionTrue)),
+			expHistory: []cmapi.CertificateRequestApprovalRecord{existingRecord},
+		},
+		"should discard changes made to the approval history by the requester": {
+			oldCR:      cr([]cmapi.CertificateRequestApprovalRecord{existingRecord}),
+			newCR:      cr(nil),
+			expHistory: []cmapi.CertificateRequestApprovalRecord{existingRecord},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			MutateUpdate(req, test.oldCR, test.newCR)
+			if !reflect.DeepEqual(test.expHistory, test.newCR.Status.ApprovalHistory) {
+				t.Errorf("unexpected approval history, exp=%+v got=%+v", test.expHistory, test.newCR.Status.ApprovalHistory)
+			}
+		})
+	}
+}
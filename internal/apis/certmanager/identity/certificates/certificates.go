@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certificates records the identity of users that manually renew
+// Certificate resources.
+package certificates
+
+import (
+	admissionv1 "k8s.io/api/admission/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+)
+
+// MutateUpdate records the renewal request of the Certificate, along with
+// the identity of the requester, if the request manually triggers a renewal
+// by setting the Issuing condition to True with the reason
+// ManuallyTriggered. Any other changes made to the renewal request are
+// discarded.
+func MutateUpdate(req *admissionv1.AdmissionRequest, oldObj, newObj runtime.Object) {
+	oldCrt, newCrt := oldObj.(*cmapi.Certificate), newObj.(*cmapi.Certificate)
+
+	cond := manualRenewalCondition(newCrt)
+	if cond == nil || manualRenewalCondition(oldCrt) != nil {
+		newCrt.Status.RenewalRequest = oldCrt.Status.RenewalRequest.DeepCopy()
+		return
+	}
+
+	requestTime := metav1.Now()
+	if cond.LastTransitionTime != nil {
+		requestTime = *cond.LastTransitionTime
+	}
+
+	// Clients that trigger a renewal without giving a reason send back the
+	// previous renewal request unchanged, which must not be recorded again.
+	var reason string
+	if newRequest := newCrt.Status.RenewalRequest; newRequest != nil && !apiequality.Semantic.DeepEqual(newRequest, oldCrt.Status.RenewalRequest) {
+		reason = newRequest.Reason
+	}
+
+	newCrt.Status.RenewalRequest = &cmapi.CertificateRenewalRequest{
+		Reason:   reason,
+		Username: req.UserInfo.Username,
+		Time:     &requestTime,
+	}
+}
+
+// manualRenewalCondition returns the Issuing condition of the Certificate if
+// it is True with the reason ManuallyTriggered.
+func manualRenewalCondition(crt *cmapi.Certificate) *cmapi.CertificateCondition {
+	for i, cond := range crt.Status.Conditions {
+		if cond.Type == cmapi.CertificateConditionIssuing && cond.Status == cmmeta.ConditionTrue &&
+			cond.Reason == cmapi.CertificateReasonManuallyTriggered {
+			return &crt.Status.Conditions[i]
+		}
+	}
+	return nil
+}
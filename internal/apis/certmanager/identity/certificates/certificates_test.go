@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+)
+
+func TestMutateUpdate(t *testing.T) {
+	requestTime := metav1.Now()
+	req := &admissionv1.AdmissionRequest{
+		UserInfo: authenticationv1.UserInfo{
+			Username: "user-1",
+		},
+	}
+	existingRequest := &cmapi.CertificateRenewalRequest{
+		Reason:   "key compromise",
+		Username: "user-2",
+		Time:     &requestTime,
+	}
+	issuing := func(reason string) cmapi.CertificateCondition {
+		return cmapi.CertificateCondition{
+			Type:               cmapi.CertificateConditionIssuing,
+			Status:             cmmeta.ConditionTrue,
+			Reason:             reason,
+			LastTransitionTime: &requestTime,
+		}
+	}
+	crt := func(request *cmapi.CertificateRenewalRequest, conditions ...cmapi.CertificateCondition) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			Status: cmapi.CertificateStatus{
+				Conditions:     conditions,
+				RenewalRequest: request,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		oldCrt, newCrt *cmapi.Certificate
+		expRequest     *cmapi.CertificateRenewalRequest
+	}{
+		"should record the reason and identity of the requester when a renewal is manually triggered": {
+			oldCrt: crt(nil),
+			newCrt: crt(&cmapi.CertificateRenewalRequest{Reason: "rotating CA", Username: "someone-else"}, issuing(cmapi.CertificateReasonManuallyTriggered)),
+			expRequest: &cmapi.CertificateRenewalRequest{
+				Reason:   "rotating CA",
+				Username: "user-1",
+				Time:     &requestTime,
+			},
+		},
+		"should not record the reason of a previous renewal request": {
+			oldCrt: crt(existingRequest),
+			newCrt: crt(existingRequest, issuing(cmapi.CertificateReasonManuallyTriggered)),
+			expRequest: &cmapi.CertificateRenewalRequest{
+				Username: "user-1",
+				Time:     &requestTime,
+			},
+		},
+		"should not record renewals triggered by cert-manager": {
+			oldCrt:     crt(existingRequest),
+			newCrt:     crt(existingRequest, issuing("Expiring")),
+			expRequest: existingRequest,
+		},
+		"should not record a renewal that was already triggered": {
+			oldCrt:     crt(existingRequest, issuing(cmapi.CertificateReasonManuallyTriggered)),
+			newCrt:     crt(&cmapi.CertificateRenewalRequest{Reason: "changed"}, issuing(cmapi.CertificateReasonManuallyTriggered)),
+			expRequest: existingRequest,
+		},
+		"should discard changes made to the renewal request": {
+			oldCrt:     crt(existingRequest),
+			newCrt:     crt(&cmapi.CertificateRenewalRequest{Reason: "changed", Username: "user-1"}),
+			expRequest: existingRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			MutateUpdate(req, test.oldCrt, test.newCrt)
+			if !reflect.DeepEqual(test.expRequest, test.newCrt.Status.RenewalRequest) {
+				t.Errorf("unexpected renewal request, exp=%+v got=%+v", test.expRequest, test.newCrt.Status.RenewalRequest)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/jetstack/cert-manager/internal/api/validation"
 	cmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/internal/apis/certmanager/identity/certificaterequests"
+	"github.com/jetstack/cert-manager/internal/apis/certmanager/identity/certificates"
 )
 
 func AddToValidationRegistry(reg *validation.Registry) error {
@@ -41,6 +42,9 @@ func AddToMutationRegistry(reg *mutation.Registry) error {
 	if err := reg.AddMutateUpdateFunc(&cmapi.CertificateRequest{}, certificaterequests.MutateUpdate); err != nil {
 		return err
 	}
+	if err := reg.AddMutateUpdateFunc(&cmapi.Certificate{}, certificates.MutateUpdate); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -76,6 +76,18 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// CertificateRequestDurationAnnotationKey is the annotation key used to
+	// request a particular duration, represented as a Go Duration, for
+	// CertificateRequests that do not set spec.duration.
+	// It is currently only honoured by the 'self signing' issuer type.
+	CertificateRequestDurationAnnotationKey = "cert-manager.io/request-duration"
+
+	// CertificateRequestMaxPathLenAnnotationKey is the annotation key used to
+	// request the maximum number of intermediate CAs that may follow a CA
+	// certificate in a chain. It may only be set when requesting a CA.
+	// It is currently only honoured by the 'self signing' issuer type.
+	CertificateRequestMaxPathLenAnnotationKey = "cert-manager.io/request-max-path-len"
 )
 
 const (
@@ -85,6 +97,32 @@ const (
 	// stored in the target Secret resource whilst the real Issuer is processing
 	// the certificate request.
 	IssueTemporaryCertificateAnnotation = "cert-manager.io/issue-temporary-certificate"
+
+	// AdoptExistingSecretAnnotation is an annotation that can be added to
+	// Certificate resources.
+	// If set to "true" and the Certificate has not yet been issued, an existing
+	// certificate and private key in the target Secret resource will be
+	// adopted without re-issuance, provided they are valid and match the
+	// Certificate's spec.
+	AdoptExistingSecretAnnotation = "cert-manager.io/adopt-existing-secret"
+
+	// RevokeCertificateAnnotation is an annotation that can be added to
+	// CertificateRequest resources.
+	// If set to "true", the certificate issued for the CertificateRequest is
+	// added to the certificate revocation list of its CA issuer, if the
+	// issuer is configured to maintain one.
+	RevokeCertificateAnnotation = "cert-manager.io/revoke"
+
+	// PrivateKeyExternalRefAnnotationKey is set on CertificateRequests and
+	// Secrets for certificates whose private key is held by an external
+	// signer. Its value identifies the external key that the certificate was
+	// requested or issued for.
+	PrivateKeyExternalRefAnnotationKey = "cert-manager.io/private-key-external-ref"
+
+	// RevokeOnDeleteFinalizer is added to Certificate resources that have
+	// `spec.revokeOnDelete` set, so that their certificate can be revoked
+	// before they are deleted.
+	RevokeOnDeleteFinalizer = "cert-manager.io/revoke-on-delete"
 )
 
 // Common/known resource kinds.
@@ -118,6 +156,50 @@ const (
 	// If an injectable references a Secret that does NOT have this annotation,
 	// the cainjector will refuse to inject the secret.
 	AllowsInjectionFromSecretAnnotation = "cert-manager.io/allow-direct-injection"
+
+	// WantInjectIntoFieldAnnotation is the annotation that specifies the field
+	// of a generic injection target that the CA is injected into, e.g.
+	// `spec.caBundle`. Generic injection targets are resources whose kind has
+	// been passed to the cainjector using the `--inject-ca-into-kinds` flag.
+	WantInjectIntoFieldAnnotation = "cert-manager.io/inject-ca-into"
+
+	// WantInjectFormatAnnotation is the annotation that specifies how the CA is
+	// encoded when it is injected into the field of a generic injection target.
+	// One of `base64` (the default, matching the encoding of []byte fields such
+	// as `caBundle`) or `pem`.
+	WantInjectFormatAnnotation = "cert-manager.io/inject-ca-format"
+)
+
+// Pod certificate annotations and conditions
+const (
+	// PodCertificateAnnotationKey is the annotation on a Pod that names a
+	// Certificate in the namespace of the Pod. If pod certificate injection is
+	// enabled in the webhook, the Secret of the Certificate is mounted into
+	// every container of the Pod, and a readiness gate is added to the Pod
+	// that is only satisfied once the Certificate is Ready.
+	PodCertificateAnnotationKey = "cert-manager.io/pod-certificate"
+
+	// PodCertificateMountPathAnnotationKey is the annotation on a Pod that sets
+	// the path that the Secret of the Certificate named by the
+	// `cert-manager.io/pod-certificate` annotation is mounted at.
+	// Defaults to `/var/run/secrets/cert-manager.io/certificate`.
+	PodCertificateMountPathAnnotationKey = "cert-manager.io/pod-certificate-mount-path"
+
+	// PodConditionCertificateReady is the type of the Pod readiness gate
+	// condition that reflects whether the Certificate named by the
+	// `cert-manager.io/pod-certificate` annotation is Ready.
+	PodConditionCertificateReady = "cert-manager.io/certificate-ready"
+)
+
+// Secret update notification annotations
+const (
+	// RestartOnSecretUpdateAnnotationKey is the annotation on a Deployment or
+	// StatefulSet that lists the names of Secrets in the same namespace,
+	// separated by commas. If the secret update notifier controller is
+	// enabled, it records a hash of the certificates in those Secrets as an
+	// annotation on the Pod template of the workload, so that the workload is
+	// rolled out whenever one of the certificates is renewed.
+	RestartOnSecretUpdateAnnotationKey = "cert-manager.io/restart-on-secret-update"
 )
 
 // Issuer specific Annotations
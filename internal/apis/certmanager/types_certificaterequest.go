@@ -107,6 +107,10 @@ type CertificateRequestSpec struct {
 	// Extra contains extra attributes of the user that created the CertificateRequest.
 	// Populated by the cert-manager webhook on creation and immutable.
 	Extra map[string][]string
+	// RequesterIdentity holds attributes of the identity of the user that
+	// created the CertificateRequest, if it is a ServiceAccount.
+	// Populated by the cert-manager webhook on creation and immutable.
+	RequesterIdentity *CertificateRequestRequesterIdentity
 }
 
 // CertificateRequestStatus defines the observed state of CertificateRequest and
@@ -132,6 +136,48 @@ type CertificateRequestStatus struct {
 	// FailureTime stores the time that this CertificateRequest failed. This is
 	// used to influence garbage collection and back-off.
 	FailureTime *metav1.Time
+
+	// ApprovalHistory records each decision to approve or deny this
+	// CertificateRequest, in the order they were made.
+	ApprovalHistory []CertificateRequestApprovalRecord
+}
+
+// CertificateRequestRequesterIdentity holds attributes of the identity of the
+// user or ServiceAccount that created a CertificateRequest.
+type CertificateRequestRequesterIdentity struct {
+	// Namespace of the ServiceAccount that created the CertificateRequest.
+	Namespace string
+
+	// ServiceAccount is the name of the ServiceAccount that created the
+	// CertificateRequest.
+	ServiceAccount string
+
+	// Pod is the name of the Pod that the requester's bound ServiceAccount
+	// token was issued for.
+	Pod string
+}
+
+// CertificateRequestApprovalRecord records who approved or denied a
+// CertificateRequest, when, and why.
+type CertificateRequestApprovalRecord struct {
+	// Type of the decision, either `Approved` or `Denied`.
+	Type CertificateRequestConditionType
+
+	// Username of the user or controller that made the decision.
+	Username string
+
+	// Groups of the user or controller that made the decision.
+	Groups []string
+
+	// Reason of the Approved or Denied condition, identifying the approver.
+	Reason string
+
+	// Message of the Approved or Denied condition, e.g. the policy that
+	// permitted or rejected the request.
+	Message string
+
+	// Time at which the decision was made.
+	Time metav1.Time
 }
 
 // CertificateRequestCondition contains condition information for a CertificateRequest.
@@ -28,11 +28,11 @@ import (
 	acmev1alpha3 "github.com/jetstack/cert-manager/internal/apis/acme/v1alpha3"
 	certmanager "github.com/jetstack/cert-manager/internal/apis/certmanager"
 	meta "github.com/jetstack/cert-manager/internal/apis/meta"
-	apismetav1 "github.com/jetstack/cert-manager/internal/apis/meta/v1"
+	v1 "github.com/jetstack/cert-manager/internal/apis/meta/v1"
 	apisacmev1alpha3 "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha3"
 	v1alpha3 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha3"
 	metav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apismetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -44,6 +44,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.AWSSecretsManagerSecretSink)(nil), (*certmanager.AWSSecretsManagerSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(a.(*v1alpha3.AWSSecretsManagerSecretSink), b.(*certmanager.AWSSecretsManagerSecretSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.AWSSecretsManagerSecretSink)(nil), (*v1alpha3.AWSSecretsManagerSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_AWSSecretsManagerSecretSink_To_v1alpha3_AWSSecretsManagerSecretSink(a.(*certmanager.AWSSecretsManagerSecretSink), b.(*v1alpha3.AWSSecretsManagerSecretSink), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CAIssuer_To_certmanager_CAIssuer(a.(*v1alpha3.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -54,6 +64,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuerCRL)(nil), (*certmanager.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(a.(*v1alpha3.CAIssuerCRL), b.(*certmanager.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerCRL)(nil), (*v1alpha3.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(a.(*certmanager.CAIssuerCRL), b.(*v1alpha3.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuerCertificateTransparency)(nil), (*certmanager.CAIssuerCertificateTransparency)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(a.(*v1alpha3.CAIssuerCertificateTransparency), b.(*certmanager.CAIssuerCertificateTransparency), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerCertificateTransparency)(nil), (*v1alpha3.CAIssuerCertificateTransparency)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerCertificateTransparency_To_v1alpha3_CAIssuerCertificateTransparency(a.(*certmanager.CAIssuerCertificateTransparency), b.(*v1alpha3.CAIssuerCertificateTransparency), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuerSerialNumber)(nil), (*certmanager.CAIssuerSerialNumber)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(a.(*v1alpha3.CAIssuerSerialNumber), b.(*certmanager.CAIssuerSerialNumber), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerSerialNumber)(nil), (*v1alpha3.CAIssuerSerialNumber)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerSerialNumber_To_v1alpha3_CAIssuerSerialNumber(a.(*certmanager.CAIssuerSerialNumber), b.(*v1alpha3.CAIssuerSerialNumber), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuerStatus)(nil), (*certmanager.CAIssuerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CAIssuerStatus_To_certmanager_CAIssuerStatus(a.(*v1alpha3.CAIssuerStatus), b.(*certmanager.CAIssuerStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerStatus)(nil), (*v1alpha3.CAIssuerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerStatus_To_v1alpha3_CAIssuerStatus(a.(*certmanager.CAIssuerStatus), b.(*v1alpha3.CAIssuerStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CATrustAnchor)(nil), (*certmanager.CATrustAnchor)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CATrustAnchor_To_certmanager_CATrustAnchor(a.(*v1alpha3.CATrustAnchor), b.(*certmanager.CATrustAnchor), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CATrustAnchor)(nil), (*v1alpha3.CATrustAnchor)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CATrustAnchor_To_v1alpha3_CATrustAnchor(a.(*certmanager.CATrustAnchor), b.(*v1alpha3.CATrustAnchor), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Certificate_To_certmanager_Certificate(a.(*v1alpha3.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -64,6 +124,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateAdditionalOutputFormat)(nil), (*certmanager.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(a.(*v1alpha3.CertificateAdditionalOutputFormat), b.(*certmanager.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateAdditionalOutputFormat)(nil), (*v1alpha3.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(a.(*certmanager.CertificateAdditionalOutputFormat), b.(*v1alpha3.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateCAConstraints)(nil), (*certmanager.CertificateCAConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(a.(*v1alpha3.CertificateCAConstraints), b.(*certmanager.CertificateCAConstraints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateCAConstraints)(nil), (*v1alpha3.CertificateCAConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateCAConstraints_To_v1alpha3_CertificateCAConstraints(a.(*certmanager.CertificateCAConstraints), b.(*v1alpha3.CertificateCAConstraints), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateCondition)(nil), (*certmanager.CertificateCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CertificateCondition_To_certmanager_CertificateCondition(a.(*v1alpha3.CertificateCondition), b.(*certmanager.CertificateCondition), scope)
 	}); err != nil {
@@ -99,6 +179,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateRenewalRequest)(nil), (*certmanager.CertificateRenewalRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(a.(*v1alpha3.CertificateRenewalRequest), b.(*certmanager.CertificateRenewalRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalRequest)(nil), (*v1alpha3.CertificateRenewalRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalRequest_To_v1alpha3_CertificateRenewalRequest(a.(*certmanager.CertificateRenewalRequest), b.(*v1alpha3.CertificateRenewalRequest), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateRequest)(nil), (*certmanager.CertificateRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CertificateRequest_To_certmanager_CertificateRequest(a.(*v1alpha3.CertificateRequest), b.(*certmanager.CertificateRequest), scope)
 	}); err != nil {
@@ -109,6 +199,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateRequestApprovalRecord)(nil), (*certmanager.CertificateRequestApprovalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(a.(*v1alpha3.CertificateRequestApprovalRecord), b.(*certmanager.CertificateRequestApprovalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRequestApprovalRecord)(nil), (*v1alpha3.CertificateRequestApprovalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRequestApprovalRecord_To_v1alpha3_CertificateRequestApprovalRecord(a.(*certmanager.CertificateRequestApprovalRecord), b.(*v1alpha3.CertificateRequestApprovalRecord), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateRequestCondition)(nil), (*certmanager.CertificateRequestCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CertificateRequestCondition_To_certmanager_CertificateRequestCondition(a.(*v1alpha3.CertificateRequestCondition), b.(*certmanager.CertificateRequestCondition), scope)
 	}); err != nil {
@@ -129,6 +229,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateRequestRequesterIdentity)(nil), (*certmanager.CertificateRequestRequesterIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(a.(*v1alpha3.CertificateRequestRequesterIdentity), b.(*certmanager.CertificateRequestRequesterIdentity), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRequestRequesterIdentity)(nil), (*v1alpha3.CertificateRequestRequesterIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRequestRequesterIdentity_To_v1alpha3_CertificateRequestRequesterIdentity(a.(*certmanager.CertificateRequestRequesterIdentity), b.(*v1alpha3.CertificateRequestRequesterIdentity), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateRequestStatus)(nil), (*certmanager.CertificateRequestStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CertificateRequestStatus_To_certmanager_CertificateRequestStatus(a.(*v1alpha3.CertificateRequestStatus), b.(*certmanager.CertificateRequestStatus), scope)
 	}); err != nil {
@@ -139,6 +249,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateSecretSink)(nil), (*certmanager.CertificateSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateSecretSink_To_certmanager_CertificateSecretSink(a.(*v1alpha3.CertificateSecretSink), b.(*certmanager.CertificateSecretSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateSecretSink)(nil), (*v1alpha3.CertificateSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateSecretSink_To_v1alpha3_CertificateSecretSink(a.(*certmanager.CertificateSecretSink), b.(*v1alpha3.CertificateSecretSink), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateSecretTemplate)(nil), (*certmanager.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(a.(*v1alpha3.CertificateSecretTemplate), b.(*certmanager.CertificateSecretTemplate), scope)
 	}); err != nil {
@@ -249,6 +369,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.JKSTruststore)(nil), (*certmanager.JKSTruststore)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_JKSTruststore_To_certmanager_JKSTruststore(a.(*v1alpha3.JKSTruststore), b.(*certmanager.JKSTruststore), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.JKSTruststore)(nil), (*v1alpha3.JKSTruststore)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_JKSTruststore_To_v1alpha3_JKSTruststore(a.(*certmanager.JKSTruststore), b.(*v1alpha3.JKSTruststore), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.NameConstraintItem)(nil), (*certmanager.NameConstraintItem)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NameConstraintItem_To_certmanager_NameConstraintItem(a.(*v1alpha3.NameConstraintItem), b.(*certmanager.NameConstraintItem), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.NameConstraintItem)(nil), (*v1alpha3.NameConstraintItem)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_NameConstraintItem_To_v1alpha3_NameConstraintItem(a.(*certmanager.NameConstraintItem), b.(*v1alpha3.NameConstraintItem), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.NameConstraints)(nil), (*certmanager.NameConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NameConstraints_To_certmanager_NameConstraints(a.(*v1alpha3.NameConstraints), b.(*certmanager.NameConstraints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.NameConstraints)(nil), (*v1alpha3.NameConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_NameConstraints_To_v1alpha3_NameConstraints(a.(*certmanager.NameConstraints), b.(*v1alpha3.NameConstraints), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.PKCS12Keystore)(nil), (*certmanager.PKCS12Keystore)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_PKCS12Keystore_To_certmanager_PKCS12Keystore(a.(*v1alpha3.PKCS12Keystore), b.(*certmanager.PKCS12Keystore), scope)
 	}); err != nil {
@@ -259,6 +409,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.PrivateKeyExternalRef)(nil), (*certmanager.PrivateKeyExternalRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(a.(*v1alpha3.PrivateKeyExternalRef), b.(*certmanager.PrivateKeyExternalRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.PrivateKeyExternalRef)(nil), (*v1alpha3.PrivateKeyExternalRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_PrivateKeyExternalRef_To_v1alpha3_PrivateKeyExternalRef(a.(*certmanager.PrivateKeyExternalRef), b.(*v1alpha3.PrivateKeyExternalRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.SPIFFEPolicy)(nil), (*certmanager.SPIFFEPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(a.(*v1alpha3.SPIFFEPolicy), b.(*certmanager.SPIFFEPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SPIFFEPolicy)(nil), (*v1alpha3.SPIFFEPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SPIFFEPolicy_To_v1alpha3_SPIFFEPolicy(a.(*certmanager.SPIFFEPolicy), b.(*v1alpha3.SPIFFEPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.SelfSignedIssuer)(nil), (*certmanager.SelfSignedIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(a.(*v1alpha3.SelfSignedIssuer), b.(*certmanager.SelfSignedIssuer), scope)
 	}); err != nil {
@@ -269,6 +439,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.ServiceAccountRef)(nil), (*certmanager.ServiceAccountRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ServiceAccountRef_To_certmanager_ServiceAccountRef(a.(*v1alpha3.ServiceAccountRef), b.(*certmanager.ServiceAccountRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ServiceAccountRef)(nil), (*v1alpha3.ServiceAccountRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ServiceAccountRef_To_v1alpha3_ServiceAccountRef(a.(*certmanager.ServiceAccountRef), b.(*v1alpha3.ServiceAccountRef), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.VaultAppRole)(nil), (*certmanager.VaultAppRole)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VaultAppRole_To_certmanager_VaultAppRole(a.(*v1alpha3.VaultAppRole), b.(*certmanager.VaultAppRole), scope)
 	}); err != nil {
@@ -279,6 +459,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.VaultAppRoleSecretIDRotation)(nil), (*certmanager.VaultAppRoleSecretIDRotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(a.(*v1alpha3.VaultAppRoleSecretIDRotation), b.(*certmanager.VaultAppRoleSecretIDRotation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VaultAppRoleSecretIDRotation)(nil), (*v1alpha3.VaultAppRoleSecretIDRotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VaultAppRoleSecretIDRotation_To_v1alpha3_VaultAppRoleSecretIDRotation(a.(*certmanager.VaultAppRoleSecretIDRotation), b.(*v1alpha3.VaultAppRoleSecretIDRotation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.VaultAuth)(nil), (*certmanager.VaultAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VaultAuth_To_certmanager_VaultAuth(a.(*v1alpha3.VaultAuth), b.(*certmanager.VaultAuth), scope)
 	}); err != nil {
@@ -309,6 +499,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.VaultSecretSink)(nil), (*certmanager.VaultSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VaultSecretSink_To_certmanager_VaultSecretSink(a.(*v1alpha3.VaultSecretSink), b.(*certmanager.VaultSecretSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VaultSecretSink)(nil), (*v1alpha3.VaultSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VaultSecretSink_To_v1alpha3_VaultSecretSink(a.(*certmanager.VaultSecretSink), b.(*v1alpha3.VaultSecretSink), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.VenafiCloud)(nil), (*certmanager.VenafiCloud)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VenafiCloud_To_certmanager_VenafiCloud(a.(*v1alpha3.VenafiCloud), b.(*certmanager.VenafiCloud), scope)
 	}); err != nil {
@@ -319,6 +519,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.VenafiCloudOAuth)(nil), (*certmanager.VenafiCloudOAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(a.(*v1alpha3.VenafiCloudOAuth), b.(*certmanager.VenafiCloudOAuth), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCloudOAuth)(nil), (*v1alpha3.VenafiCloudOAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCloudOAuth_To_v1alpha3_VenafiCloudOAuth(a.(*certmanager.VenafiCloudOAuth), b.(*v1alpha3.VenafiCloudOAuth), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1alpha3.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -377,10 +587,60 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha3_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(in *v1alpha3.AWSSecretsManagerSecretSink, out *certmanager.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	out.Region = in.Region
+	out.SecretID = in.SecretID
+	out.AccessKeyID = in.AccessKeyID
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretAccessKey = nil
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_v1alpha3_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink is an autogenerated conversion function.
+func Convert_v1alpha3_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(in *v1alpha3.AWSSecretsManagerSecretSink, out *certmanager.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	return autoConvert_v1alpha3_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(in, out, s)
+}
+
+func autoConvert_certmanager_AWSSecretsManagerSecretSink_To_v1alpha3_AWSSecretsManagerSecretSink(in *certmanager.AWSSecretsManagerSecretSink, out *v1alpha3.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	out.Region = in.Region
+	out.SecretID = in.SecretID
+	out.AccessKeyID = in.AccessKeyID
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretAccessKey = nil
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_certmanager_AWSSecretsManagerSecretSink_To_v1alpha3_AWSSecretsManagerSecretSink is an autogenerated conversion function.
+func Convert_certmanager_AWSSecretsManagerSecretSink_To_v1alpha3_AWSSecretsManagerSecretSink(in *certmanager.AWSSecretsManagerSecretSink, out *v1alpha3.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	return autoConvert_certmanager_AWSSecretsManagerSecretSink_To_v1alpha3_AWSSecretsManagerSecretSink(in, out, s)
+}
+
 func autoConvert_v1alpha3_CAIssuer_To_certmanager_CAIssuer(in *v1alpha3.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.CRL = (*certmanager.CAIssuerCRL)(unsafe.Pointer(in.CRL))
+	out.CertificateTransparency = (*certmanager.CAIssuerCertificateTransparency)(unsafe.Pointer(in.CertificateTransparency))
+	out.SerialNumber = (*certmanager.CAIssuerSerialNumber)(unsafe.Pointer(in.SerialNumber))
+	out.FetchIntermediateCertificates = in.FetchIntermediateCertificates
+	out.NextSecretName = in.NextSecretName
+	out.ReissueOnRotation = in.ReissueOnRotation
 	return nil
 }
 
@@ -393,6 +653,12 @@ func autoConvert_certmanager_CAIssuer_To_v1alpha3_CAIssuer(in *certmanager.CAIss
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.CRL = (*v1alpha3.CAIssuerCRL)(unsafe.Pointer(in.CRL))
+	out.CertificateTransparency = (*v1alpha3.CAIssuerCertificateTransparency)(unsafe.Pointer(in.CertificateTransparency))
+	out.SerialNumber = (*v1alpha3.CAIssuerSerialNumber)(unsafe.Pointer(in.SerialNumber))
+	out.FetchIntermediateCertificates = in.FetchIntermediateCertificates
+	out.NextSecretName = in.NextSecretName
+	out.ReissueOnRotation = in.ReissueOnRotation
 	return nil
 }
 
@@ -401,6 +667,122 @@ func Convert_certmanager_CAIssuer_To_v1alpha3_CAIssuer(in *certmanager.CAIssuer,
 	return autoConvert_certmanager_CAIssuer_To_v1alpha3_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1alpha3.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.ConfigMapName = in.ConfigMapName
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL is an autogenerated conversion function.
+func Convert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1alpha3.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1alpha3.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.ConfigMapName = in.ConfigMapName
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1alpha3.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(in, out, s)
+}
+
+func autoConvert_v1alpha3_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(in *v1alpha3.CAIssuerCertificateTransparency, out *certmanager.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_v1alpha3_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency is an autogenerated conversion function.
+func Convert_v1alpha3_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(in *v1alpha3.CAIssuerCertificateTransparency, out *certmanager.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerCertificateTransparency_To_v1alpha3_CAIssuerCertificateTransparency(in *certmanager.CAIssuerCertificateTransparency, out *v1alpha3.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerCertificateTransparency_To_v1alpha3_CAIssuerCertificateTransparency is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerCertificateTransparency_To_v1alpha3_CAIssuerCertificateTransparency(in *certmanager.CAIssuerCertificateTransparency, out *v1alpha3.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerCertificateTransparency_To_v1alpha3_CAIssuerCertificateTransparency(in, out, s)
+}
+
+func autoConvert_v1alpha3_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(in *v1alpha3.CAIssuerSerialNumber, out *certmanager.CAIssuerSerialNumber, s conversion.Scope) error {
+	out.Type = certmanager.CAIssuerSerialNumberType(in.Type)
+	out.Length = in.Length
+	out.CounterConfigMapName = in.CounterConfigMapName
+	return nil
+}
+
+// Convert_v1alpha3_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber is an autogenerated conversion function.
+func Convert_v1alpha3_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(in *v1alpha3.CAIssuerSerialNumber, out *certmanager.CAIssuerSerialNumber, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerSerialNumber_To_v1alpha3_CAIssuerSerialNumber(in *certmanager.CAIssuerSerialNumber, out *v1alpha3.CAIssuerSerialNumber, s conversion.Scope) error {
+	out.Type = v1alpha3.CAIssuerSerialNumberType(in.Type)
+	out.Length = in.Length
+	out.CounterConfigMapName = in.CounterConfigMapName
+	return nil
+}
+
+// Convert_certmanager_CAIssuerSerialNumber_To_v1alpha3_CAIssuerSerialNumber is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerSerialNumber_To_v1alpha3_CAIssuerSerialNumber(in *certmanager.CAIssuerSerialNumber, out *v1alpha3.CAIssuerSerialNumber, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerSerialNumber_To_v1alpha3_CAIssuerSerialNumber(in, out, s)
+}
+
+func autoConvert_v1alpha3_CAIssuerStatus_To_certmanager_CAIssuerStatus(in *v1alpha3.CAIssuerStatus, out *certmanager.CAIssuerStatus, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]certmanager.CATrustAnchor)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_v1alpha3_CAIssuerStatus_To_certmanager_CAIssuerStatus is an autogenerated conversion function.
+func Convert_v1alpha3_CAIssuerStatus_To_certmanager_CAIssuerStatus(in *v1alpha3.CAIssuerStatus, out *certmanager.CAIssuerStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CAIssuerStatus_To_certmanager_CAIssuerStatus(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerStatus_To_v1alpha3_CAIssuerStatus(in *certmanager.CAIssuerStatus, out *v1alpha3.CAIssuerStatus, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]v1alpha3.CATrustAnchor)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerStatus_To_v1alpha3_CAIssuerStatus is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerStatus_To_v1alpha3_CAIssuerStatus(in *certmanager.CAIssuerStatus, out *v1alpha3.CAIssuerStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerStatus_To_v1alpha3_CAIssuerStatus(in, out, s)
+}
+
+func autoConvert_v1alpha3_CATrustAnchor_To_certmanager_CATrustAnchor(in *v1alpha3.CATrustAnchor, out *certmanager.CATrustAnchor, s conversion.Scope) error {
+	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
+	out.Next = in.Next
+	out.NotBefore = in.NotBefore
+	out.NotAfter = in.NotAfter
+	return nil
+}
+
+// Convert_v1alpha3_CATrustAnchor_To_certmanager_CATrustAnchor is an autogenerated conversion function.
+func Convert_v1alpha3_CATrustAnchor_To_certmanager_CATrustAnchor(in *v1alpha3.CATrustAnchor, out *certmanager.CATrustAnchor, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CATrustAnchor_To_certmanager_CATrustAnchor(in, out, s)
+}
+
+func autoConvert_certmanager_CATrustAnchor_To_v1alpha3_CATrustAnchor(in *certmanager.CATrustAnchor, out *v1alpha3.CATrustAnchor, s conversion.Scope) error {
+	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
+	out.Next = in.Next
+	out.NotBefore = in.NotBefore
+	out.NotAfter = in.NotAfter
+	return nil
+}
+
+// Convert_certmanager_CATrustAnchor_To_v1alpha3_CATrustAnchor is an autogenerated conversion function.
+func Convert_certmanager_CATrustAnchor_To_v1alpha3_CATrustAnchor(in *certmanager.CATrustAnchor, out *v1alpha3.CATrustAnchor, s conversion.Scope) error {
+	return autoConvert_certmanager_CATrustAnchor_To_v1alpha3_CATrustAnchor(in, out, s)
+}
+
 func autoConvert_v1alpha3_Certificate_To_certmanager_Certificate(in *v1alpha3.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -433,10 +815,60 @@ func Convert_certmanager_Certificate_To_v1alpha3_Certificate(in *certmanager.Cer
 	return autoConvert_certmanager_Certificate_To_v1alpha3_Certificate(in, out, s)
 }
 
+func autoConvert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1alpha3.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = certmanager.CertificateOutputFormatType(in.Type)
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1alpha3.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1alpha3.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = v1alpha3.CertificateOutputFormatType(in.Type)
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1alpha3.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(in, out, s)
+}
+
+func autoConvert_v1alpha3_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(in *v1alpha3.CertificateCAConstraints, out *certmanager.CertificateCAConstraints, s conversion.Scope) error {
+	out.MaxPathLen = (*int)(unsafe.Pointer(in.MaxPathLen))
+	out.NameConstraints = (*certmanager.NameConstraints)(unsafe.Pointer(in.NameConstraints))
+	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	return nil
+}
+
+// Convert_v1alpha3_CertificateCAConstraints_To_certmanager_CertificateCAConstraints is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(in *v1alpha3.CertificateCAConstraints, out *certmanager.CertificateCAConstraints, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateCAConstraints_To_v1alpha3_CertificateCAConstraints(in *certmanager.CertificateCAConstraints, out *v1alpha3.CertificateCAConstraints, s conversion.Scope) error {
+	out.MaxPathLen = (*int)(unsafe.Pointer(in.MaxPathLen))
+	out.NameConstraints = (*v1alpha3.NameConstraints)(unsafe.Pointer(in.NameConstraints))
+	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	return nil
+}
+
+// Convert_certmanager_CertificateCAConstraints_To_v1alpha3_CertificateCAConstraints is an autogenerated conversion function.
+func Convert_certmanager_CertificateCAConstraints_To_v1alpha3_CertificateCAConstraints(in *certmanager.CertificateCAConstraints, out *v1alpha3.CertificateCAConstraints, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateCAConstraints_To_v1alpha3_CertificateCAConstraints(in, out, s)
+}
+
 func autoConvert_v1alpha3_CertificateCondition_To_certmanager_CertificateCondition(in *v1alpha3.CertificateCondition, out *certmanager.CertificateCondition, s conversion.Scope) error {
 	out.Type = certmanager.CertificateConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -451,7 +883,7 @@ func Convert_v1alpha3_CertificateCondition_To_certmanager_CertificateCondition(i
 func autoConvert_certmanager_CertificateCondition_To_v1alpha3_CertificateCondition(in *certmanager.CertificateCondition, out *v1alpha3.CertificateCondition, s conversion.Scope) error {
 	out.Type = v1alpha3.CertificateConditionType(in.Type)
 	out.Status = metav1.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -561,6 +993,15 @@ func Convert_certmanager_CertificateList_To_v1alpha3_CertificateList(in *certman
 
 func autoConvert_v1alpha3_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(in *v1alpha3.CertificatePrivateKey, out *certmanager.CertificatePrivateKey, s conversion.Scope) error {
 	out.RotationPolicy = certmanager.PrivateKeyRotationPolicy(in.RotationPolicy)
+	if in.ExternalRef != nil {
+		in, out := &in.ExternalRef, &out.ExternalRef
+		*out = new(certmanager.PrivateKeyExternalRef)
+		if err := Convert_v1alpha3_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalRef = nil
+	}
 	return nil
 }
 
@@ -574,9 +1015,42 @@ func autoConvert_certmanager_CertificatePrivateKey_To_v1alpha3_CertificatePrivat
 	// WARNING: in.Encoding requires manual conversion: does not exist in peer-type
 	// WARNING: in.Algorithm requires manual conversion: does not exist in peer-type
 	// WARNING: in.Size requires manual conversion: does not exist in peer-type
+	if in.ExternalRef != nil {
+		in, out := &in.ExternalRef, &out.ExternalRef
+		*out = new(v1alpha3.PrivateKeyExternalRef)
+		if err := Convert_certmanager_PrivateKeyExternalRef_To_v1alpha3_PrivateKeyExternalRef(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalRef = nil
+	}
+	return nil
+}
+
+func autoConvert_v1alpha3_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(in *v1alpha3.CertificateRenewalRequest, out *certmanager.CertificateRenewalRequest, s conversion.Scope) error {
+	out.Reason = in.Reason
+	out.Username = in.Username
+	out.Time = (*apismetav1.Time)(unsafe.Pointer(in.Time))
+	return nil
+}
+
+// Convert_v1alpha3_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(in *v1alpha3.CertificateRenewalRequest, out *certmanager.CertificateRenewalRequest, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalRequest_To_v1alpha3_CertificateRenewalRequest(in *certmanager.CertificateRenewalRequest, out *v1alpha3.CertificateRenewalRequest, s conversion.Scope) error {
+	out.Reason = in.Reason
+	out.Username = in.Username
+	out.Time = (*apismetav1.Time)(unsafe.Pointer(in.Time))
 	return nil
 }
 
+// Convert_certmanager_CertificateRenewalRequest_To_v1alpha3_CertificateRenewalRequest is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalRequest_To_v1alpha3_CertificateRenewalRequest(in *certmanager.CertificateRenewalRequest, out *v1alpha3.CertificateRenewalRequest, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalRequest_To_v1alpha3_CertificateRenewalRequest(in, out, s)
+}
+
 func autoConvert_v1alpha3_CertificateRequest_To_certmanager_CertificateRequest(in *v1alpha3.CertificateRequest, out *certmanager.CertificateRequest, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -609,10 +1083,40 @@ func Convert_certmanager_CertificateRequest_To_v1alpha3_CertificateRequest(in *c
 	return autoConvert_certmanager_CertificateRequest_To_v1alpha3_CertificateRequest(in, out, s)
 }
 
+func autoConvert_v1alpha3_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(in *v1alpha3.CertificateRequestApprovalRecord, out *certmanager.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	out.Type = certmanager.CertificateRequestConditionType(in.Type)
+	out.Username = in.Username
+	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_v1alpha3_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(in *v1alpha3.CertificateRequestApprovalRecord, out *certmanager.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRequestApprovalRecord_To_v1alpha3_CertificateRequestApprovalRecord(in *certmanager.CertificateRequestApprovalRecord, out *v1alpha3.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	out.Type = v1alpha3.CertificateRequestConditionType(in.Type)
+	out.Username = in.Username
+	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_certmanager_CertificateRequestApprovalRecord_To_v1alpha3_CertificateRequestApprovalRecord is an autogenerated conversion function.
+func Convert_certmanager_CertificateRequestApprovalRecord_To_v1alpha3_CertificateRequestApprovalRecord(in *certmanager.CertificateRequestApprovalRecord, out *v1alpha3.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRequestApprovalRecord_To_v1alpha3_CertificateRequestApprovalRecord(in, out, s)
+}
+
 func autoConvert_v1alpha3_CertificateRequestCondition_To_certmanager_CertificateRequestCondition(in *v1alpha3.CertificateRequestCondition, out *certmanager.CertificateRequestCondition, s conversion.Scope) error {
 	out.Type = certmanager.CertificateRequestConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	return nil
@@ -626,7 +1130,7 @@ func Convert_v1alpha3_CertificateRequestCondition_To_certmanager_CertificateRequ
 func autoConvert_certmanager_CertificateRequestCondition_To_v1alpha3_CertificateRequestCondition(in *certmanager.CertificateRequestCondition, out *v1alpha3.CertificateRequestCondition, s conversion.Scope) error {
 	out.Type = v1alpha3.CertificateRequestConditionType(in.Type)
 	out.Status = metav1.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	return nil
@@ -679,9 +1183,33 @@ func Convert_certmanager_CertificateRequestList_To_v1alpha3_CertificateRequestLi
 	return autoConvert_certmanager_CertificateRequestList_To_v1alpha3_CertificateRequestList(in, out, s)
 }
 
+func autoConvert_v1alpha3_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(in *v1alpha3.CertificateRequestRequesterIdentity, out *certmanager.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.ServiceAccount = in.ServiceAccount
+	out.Pod = in.Pod
+	return nil
+}
+
+// Convert_v1alpha3_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(in *v1alpha3.CertificateRequestRequesterIdentity, out *certmanager.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRequestRequesterIdentity_To_v1alpha3_CertificateRequestRequesterIdentity(in *certmanager.CertificateRequestRequesterIdentity, out *v1alpha3.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.ServiceAccount = in.ServiceAccount
+	out.Pod = in.Pod
+	return nil
+}
+
+// Convert_certmanager_CertificateRequestRequesterIdentity_To_v1alpha3_CertificateRequestRequesterIdentity is an autogenerated conversion function.
+func Convert_certmanager_CertificateRequestRequesterIdentity_To_v1alpha3_CertificateRequestRequesterIdentity(in *certmanager.CertificateRequestRequesterIdentity, out *v1alpha3.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRequestRequesterIdentity_To_v1alpha3_CertificateRequestRequesterIdentity(in, out, s)
+}
+
 func autoConvert_v1alpha3_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(in *v1alpha3.CertificateRequestSpec, out *certmanager.CertificateRequestSpec, s conversion.Scope) error {
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	if err := apismetav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	if err := v1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	// WARNING: in.CSRPEM requires manual conversion: does not exist in peer-type
@@ -691,12 +1219,13 @@ func autoConvert_v1alpha3_CertificateRequestSpec_To_certmanager_CertificateReque
 	out.UID = in.UID
 	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
 	out.Extra = *(*map[string][]string)(unsafe.Pointer(&in.Extra))
+	out.RequesterIdentity = (*certmanager.CertificateRequestRequesterIdentity)(unsafe.Pointer(in.RequesterIdentity))
 	return nil
 }
 
 func autoConvert_certmanager_CertificateRequestSpec_To_v1alpha3_CertificateRequestSpec(in *certmanager.CertificateRequestSpec, out *v1alpha3.CertificateRequestSpec, s conversion.Scope) error {
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	if err := apismetav1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	if err := v1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	// WARNING: in.Request requires manual conversion: does not exist in peer-type
@@ -706,6 +1235,7 @@ func autoConvert_certmanager_CertificateRequestSpec_To_v1alpha3_CertificateReque
 	out.UID = in.UID
 	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
 	out.Extra = *(*map[string][]string)(unsafe.Pointer(&in.Extra))
+	out.RequesterIdentity = (*v1alpha3.CertificateRequestRequesterIdentity)(unsafe.Pointer(in.RequesterIdentity))
 	return nil
 }
 
@@ -713,7 +1243,8 @@ func autoConvert_v1alpha3_CertificateRequestStatus_To_certmanager_CertificateReq
 	out.Conditions = *(*[]certmanager.CertificateRequestCondition)(unsafe.Pointer(&in.Conditions))
 	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
 	out.CA = *(*[]byte)(unsafe.Pointer(&in.CA))
-	out.FailureTime = (*v1.Time)(unsafe.Pointer(in.FailureTime))
+	out.FailureTime = (*apismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.ApprovalHistory = *(*[]certmanager.CertificateRequestApprovalRecord)(unsafe.Pointer(&in.ApprovalHistory))
 	return nil
 }
 
@@ -726,7 +1257,8 @@ func autoConvert_certmanager_CertificateRequestStatus_To_v1alpha3_CertificateReq
 	out.Conditions = *(*[]v1alpha3.CertificateRequestCondition)(unsafe.Pointer(&in.Conditions))
 	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
 	out.CA = *(*[]byte)(unsafe.Pointer(&in.CA))
-	out.FailureTime = (*v1.Time)(unsafe.Pointer(in.FailureTime))
+	out.FailureTime = (*apismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.ApprovalHistory = *(*[]v1alpha3.CertificateRequestApprovalRecord)(unsafe.Pointer(&in.ApprovalHistory))
 	return nil
 }
 
@@ -735,6 +1267,62 @@ func Convert_certmanager_CertificateRequestStatus_To_v1alpha3_CertificateRequest
 	return autoConvert_certmanager_CertificateRequestStatus_To_v1alpha3_CertificateRequestStatus(in, out, s)
 }
 
+func autoConvert_v1alpha3_CertificateSecretSink_To_certmanager_CertificateSecretSink(in *v1alpha3.CertificateSecretSink, out *certmanager.CertificateSecretSink, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(certmanager.VaultSecretSink)
+		if err := Convert_v1alpha3_VaultSecretSink_To_certmanager_VaultSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	if in.AWSSecretsManager != nil {
+		in, out := &in.AWSSecretsManager, &out.AWSSecretsManager
+		*out = new(certmanager.AWSSecretsManagerSecretSink)
+		if err := Convert_v1alpha3_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSSecretsManager = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_CertificateSecretSink_To_certmanager_CertificateSecretSink is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateSecretSink_To_certmanager_CertificateSecretSink(in *v1alpha3.CertificateSecretSink, out *certmanager.CertificateSecretSink, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateSecretSink_To_certmanager_CertificateSecretSink(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateSecretSink_To_v1alpha3_CertificateSecretSink(in *certmanager.CertificateSecretSink, out *v1alpha3.CertificateSecretSink, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(v1alpha3.VaultSecretSink)
+		if err := Convert_certmanager_VaultSecretSink_To_v1alpha3_VaultSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	if in.AWSSecretsManager != nil {
+		in, out := &in.AWSSecretsManager, &out.AWSSecretsManager
+		*out = new(v1alpha3.AWSSecretsManagerSecretSink)
+		if err := Convert_certmanager_AWSSecretsManagerSecretSink_To_v1alpha3_AWSSecretsManagerSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSSecretsManager = nil
+	}
+	return nil
+}
+
+// Convert_certmanager_CertificateSecretSink_To_v1alpha3_CertificateSecretSink is an autogenerated conversion function.
+func Convert_certmanager_CertificateSecretSink_To_v1alpha3_CertificateSecretSink(in *certmanager.CertificateSecretSink, out *v1alpha3.CertificateSecretSink, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateSecretSink_To_v1alpha3_CertificateSecretSink(in, out, s)
+}
+
 func autoConvert_v1alpha3_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1alpha3.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
@@ -768,8 +1356,8 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 		out.Subject = nil
 	}
 	out.CommonName = in.CommonName
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
@@ -785,10 +1373,24 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 	} else {
 		out.Keystores = nil
 	}
-	if err := apismetav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.AdditionalOutputFormats = *(*[]certmanager.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
+	out.NormalizePEM = in.NormalizePEM
+	if in.SecretSinks != nil {
+		in, out := &in.SecretSinks, &out.SecretSinks
+		*out = make([]certmanager.CertificateSecretSink, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_CertificateSecretSink_To_certmanager_CertificateSecretSink(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SecretSinks = nil
+	}
+	if err := v1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.CAConstraints = (*certmanager.CertificateCAConstraints)(unsafe.Pointer(in.CAConstraints))
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
 	// WARNING: in.KeySize requires manual conversion: does not exist in peer-type
 	// WARNING: in.KeyAlgorithm requires manual conversion: does not exist in peer-type
@@ -804,6 +1406,9 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.Revoke = in.Revoke
+	out.RevokeOnDelete = in.RevokeOnDelete
+	out.AllowPartialSANs = in.AllowPartialSANs
 	return nil
 }
 
@@ -818,8 +1423,8 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 		out.Subject = nil
 	}
 	out.CommonName = in.CommonName
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
@@ -835,10 +1440,24 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 	} else {
 		out.Keystores = nil
 	}
-	if err := apismetav1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.AdditionalOutputFormats = *(*[]v1alpha3.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
+	out.NormalizePEM = in.NormalizePEM
+	if in.SecretSinks != nil {
+		in, out := &in.SecretSinks, &out.SecretSinks
+		*out = make([]v1alpha3.CertificateSecretSink, len(*in))
+		for i := range *in {
+			if err := Convert_certmanager_CertificateSecretSink_To_v1alpha3_CertificateSecretSink(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SecretSinks = nil
+	}
+	if err := v1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.CAConstraints = (*v1alpha3.CertificateCAConstraints)(unsafe.Pointer(in.CAConstraints))
 	out.Usages = *(*[]v1alpha3.KeyUsage)(unsafe.Pointer(&in.Usages))
 	if in.PrivateKey != nil {
 		in, out := &in.PrivateKey, &out.PrivateKey
@@ -851,17 +1470,23 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.Revoke = in.Revoke
+	out.RevokeOnDelete = in.RevokeOnDelete
+	out.AllowPartialSANs = in.AllowPartialSANs
 	return nil
 }
 
 func autoConvert_v1alpha3_CertificateStatus_To_certmanager_CertificateStatus(in *v1alpha3.CertificateStatus, out *certmanager.CertificateStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]certmanager.CertificateCondition)(unsafe.Pointer(&in.Conditions))
-	out.LastFailureTime = (*v1.Time)(unsafe.Pointer(in.LastFailureTime))
-	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
-	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
-	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
+	out.LastFailureTime = (*apismetav1.Time)(unsafe.Pointer(in.LastFailureTime))
+	out.NotBefore = (*apismetav1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*apismetav1.Time)(unsafe.Pointer(in.NotAfter))
+	out.RenewalTime = (*apismetav1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	out.RevokedSerialNumber = in.RevokedSerialNumber
+	out.DroppedSANs = *(*[]string)(unsafe.Pointer(&in.DroppedSANs))
+	out.RenewalRequest = (*certmanager.CertificateRenewalRequest)(unsafe.Pointer(in.RenewalRequest))
 	return nil
 }
 
@@ -872,12 +1497,15 @@ func Convert_v1alpha3_CertificateStatus_To_certmanager_CertificateStatus(in *v1a
 
 func autoConvert_certmanager_CertificateStatus_To_v1alpha3_CertificateStatus(in *certmanager.CertificateStatus, out *v1alpha3.CertificateStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]v1alpha3.CertificateCondition)(unsafe.Pointer(&in.Conditions))
-	out.LastFailureTime = (*v1.Time)(unsafe.Pointer(in.LastFailureTime))
-	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
-	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
-	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
+	out.LastFailureTime = (*apismetav1.Time)(unsafe.Pointer(in.LastFailureTime))
+	out.NotBefore = (*apismetav1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*apismetav1.Time)(unsafe.Pointer(in.NotAfter))
+	out.RenewalTime = (*apismetav1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	out.RevokedSerialNumber = in.RevokedSerialNumber
+	out.DroppedSANs = *(*[]string)(unsafe.Pointer(&in.DroppedSANs))
+	out.RenewalRequest = (*v1alpha3.CertificateRenewalRequest)(unsafe.Pointer(in.RenewalRequest))
 	return nil
 }
 
@@ -995,7 +1623,7 @@ func Convert_certmanager_Issuer_To_v1alpha3_Issuer(in *certmanager.Issuer, out *
 func autoConvert_v1alpha3_IssuerCondition_To_certmanager_IssuerCondition(in *v1alpha3.IssuerCondition, out *certmanager.IssuerCondition, s conversion.Scope) error {
 	out.Type = certmanager.IssuerConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -1010,7 +1638,7 @@ func Convert_v1alpha3_IssuerCondition_To_certmanager_IssuerCondition(in *v1alpha
 func autoConvert_certmanager_IssuerCondition_To_v1alpha3_IssuerCondition(in *certmanager.IssuerCondition, out *v1alpha3.IssuerCondition, s conversion.Scope) error {
 	out.Type = v1alpha3.IssuerConditionType(in.Type)
 	out.Status = metav1.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -1144,6 +1772,8 @@ func autoConvert_v1alpha3_IssuerSpec_To_certmanager_IssuerSpec(in *v1alpha3.Issu
 	if err := Convert_v1alpha3_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowWildcards = (*bool)(unsafe.Pointer(in.AllowWildcards))
+	out.SPIFFE = (*certmanager.SPIFFEPolicy)(unsafe.Pointer(in.SPIFFE))
 	return nil
 }
 
@@ -1156,6 +1786,8 @@ func autoConvert_certmanager_IssuerSpec_To_v1alpha3_IssuerSpec(in *certmanager.I
 	if err := Convert_certmanager_IssuerConfig_To_v1alpha3_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowWildcards = (*bool)(unsafe.Pointer(in.AllowWildcards))
+	out.SPIFFE = (*v1alpha3.SPIFFEPolicy)(unsafe.Pointer(in.SPIFFE))
 	return nil
 }
 
@@ -1167,6 +1799,7 @@ func Convert_certmanager_IssuerSpec_To_v1alpha3_IssuerSpec(in *certmanager.Issue
 func autoConvert_v1alpha3_IssuerStatus_To_certmanager_IssuerStatus(in *v1alpha3.IssuerStatus, out *certmanager.IssuerStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]certmanager.IssuerCondition)(unsafe.Pointer(&in.Conditions))
 	out.ACME = (*acme.ACMEIssuerStatus)(unsafe.Pointer(in.ACME))
+	out.CA = (*certmanager.CAIssuerStatus)(unsafe.Pointer(in.CA))
 	return nil
 }
 
@@ -1178,6 +1811,7 @@ func Convert_v1alpha3_IssuerStatus_To_certmanager_IssuerStatus(in *v1alpha3.Issu
 func autoConvert_certmanager_IssuerStatus_To_v1alpha3_IssuerStatus(in *certmanager.IssuerStatus, out *v1alpha3.IssuerStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]v1alpha3.IssuerCondition)(unsafe.Pointer(&in.Conditions))
 	out.ACME = (*apisacmev1alpha3.ACMEIssuerStatus)(unsafe.Pointer(in.ACME))
+	out.CA = (*v1alpha3.CAIssuerStatus)(unsafe.Pointer(in.CA))
 	return nil
 }
 
@@ -1188,9 +1822,18 @@ func Convert_certmanager_IssuerStatus_To_v1alpha3_IssuerStatus(in *certmanager.I
 
 func autoConvert_v1alpha3_JKSKeystore_To_certmanager_JKSKeystore(in *v1alpha3.JKSKeystore, out *certmanager.JKSKeystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
+	if in.Truststore != nil {
+		in, out := &in.Truststore, &out.Truststore
+		*out = new(certmanager.JKSTruststore)
+		if err := Convert_v1alpha3_JKSTruststore_To_certmanager_JKSTruststore(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Truststore = nil
+	}
 	return nil
 }
 
@@ -1201,9 +1844,18 @@ func Convert_v1alpha3_JKSKeystore_To_certmanager_JKSKeystore(in *v1alpha3.JKSKey
 
 func autoConvert_certmanager_JKSKeystore_To_v1alpha3_JKSKeystore(in *certmanager.JKSKeystore, out *v1alpha3.JKSKeystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
+	if in.Truststore != nil {
+		in, out := &in.Truststore, &out.Truststore
+		*out = new(v1alpha3.JKSTruststore)
+		if err := Convert_certmanager_JKSTruststore_To_v1alpha3_JKSTruststore(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Truststore = nil
+	}
 	return nil
 }
 
@@ -1212,9 +1864,83 @@ func Convert_certmanager_JKSKeystore_To_v1alpha3_JKSKeystore(in *certmanager.JKS
 	return autoConvert_certmanager_JKSKeystore_To_v1alpha3_JKSKeystore(in, out, s)
 }
 
+func autoConvert_v1alpha3_JKSTruststore_To_certmanager_JKSTruststore(in *v1alpha3.JKSTruststore, out *certmanager.JKSTruststore, s conversion.Scope) error {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_JKSTruststore_To_certmanager_JKSTruststore is an autogenerated conversion function.
+func Convert_v1alpha3_JKSTruststore_To_certmanager_JKSTruststore(in *v1alpha3.JKSTruststore, out *certmanager.JKSTruststore, s conversion.Scope) error {
+	return autoConvert_v1alpha3_JKSTruststore_To_certmanager_JKSTruststore(in, out, s)
+}
+
+func autoConvert_certmanager_JKSTruststore_To_v1alpha3_JKSTruststore(in *certmanager.JKSTruststore, out *v1alpha3.JKSTruststore, s conversion.Scope) error {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_JKSTruststore_To_v1alpha3_JKSTruststore is an autogenerated conversion function.
+func Convert_certmanager_JKSTruststore_To_v1alpha3_JKSTruststore(in *certmanager.JKSTruststore, out *v1alpha3.JKSTruststore, s conversion.Scope) error {
+	return autoConvert_certmanager_JKSTruststore_To_v1alpha3_JKSTruststore(in, out, s)
+}
+
+func autoConvert_v1alpha3_NameConstraintItem_To_certmanager_NameConstraintItem(in *v1alpha3.NameConstraintItem, out *certmanager.NameConstraintItem, s conversion.Scope) error {
+	out.DNSDomains = *(*[]string)(unsafe.Pointer(&in.DNSDomains))
+	out.IPRanges = *(*[]string)(unsafe.Pointer(&in.IPRanges))
+	out.EmailAddresses = *(*[]string)(unsafe.Pointer(&in.EmailAddresses))
+	out.URIDomains = *(*[]string)(unsafe.Pointer(&in.URIDomains))
+	return nil
+}
+
+// Convert_v1alpha3_NameConstraintItem_To_certmanager_NameConstraintItem is an autogenerated conversion function.
+func Convert_v1alpha3_NameConstraintItem_To_certmanager_NameConstraintItem(in *v1alpha3.NameConstraintItem, out *certmanager.NameConstraintItem, s conversion.Scope) error {
+	return autoConvert_v1alpha3_NameConstraintItem_To_certmanager_NameConstraintItem(in, out, s)
+}
+
+func autoConvert_certmanager_NameConstraintItem_To_v1alpha3_NameConstraintItem(in *certmanager.NameConstraintItem, out *v1alpha3.NameConstraintItem, s conversion.Scope) error {
+	out.DNSDomains = *(*[]string)(unsafe.Pointer(&in.DNSDomains))
+	out.IPRanges = *(*[]string)(unsafe.Pointer(&in.IPRanges))
+	out.EmailAddresses = *(*[]string)(unsafe.Pointer(&in.EmailAddresses))
+	out.URIDomains = *(*[]string)(unsafe.Pointer(&in.URIDomains))
+	return nil
+}
+
+// Convert_certmanager_NameConstraintItem_To_v1alpha3_NameConstraintItem is an autogenerated conversion function.
+func Convert_certmanager_NameConstraintItem_To_v1alpha3_NameConstraintItem(in *certmanager.NameConstraintItem, out *v1alpha3.NameConstraintItem, s conversion.Scope) error {
+	return autoConvert_certmanager_NameConstraintItem_To_v1alpha3_NameConstraintItem(in, out, s)
+}
+
+func autoConvert_v1alpha3_NameConstraints_To_certmanager_NameConstraints(in *v1alpha3.NameConstraints, out *certmanager.NameConstraints, s conversion.Scope) error {
+	out.Critical = in.Critical
+	out.Permitted = (*certmanager.NameConstraintItem)(unsafe.Pointer(in.Permitted))
+	out.Excluded = (*certmanager.NameConstraintItem)(unsafe.Pointer(in.Excluded))
+	return nil
+}
+
+// Convert_v1alpha3_NameConstraints_To_certmanager_NameConstraints is an autogenerated conversion function.
+func Convert_v1alpha3_NameConstraints_To_certmanager_NameConstraints(in *v1alpha3.NameConstraints, out *certmanager.NameConstraints, s conversion.Scope) error {
+	return autoConvert_v1alpha3_NameConstraints_To_certmanager_NameConstraints(in, out, s)
+}
+
+func autoConvert_certmanager_NameConstraints_To_v1alpha3_NameConstraints(in *certmanager.NameConstraints, out *v1alpha3.NameConstraints, s conversion.Scope) error {
+	out.Critical = in.Critical
+	out.Permitted = (*v1alpha3.NameConstraintItem)(unsafe.Pointer(in.Permitted))
+	out.Excluded = (*v1alpha3.NameConstraintItem)(unsafe.Pointer(in.Excluded))
+	return nil
+}
+
+// Convert_certmanager_NameConstraints_To_v1alpha3_NameConstraints is an autogenerated conversion function.
+func Convert_certmanager_NameConstraints_To_v1alpha3_NameConstraints(in *certmanager.NameConstraints, out *v1alpha3.NameConstraints, s conversion.Scope) error {
+	return autoConvert_certmanager_NameConstraints_To_v1alpha3_NameConstraints(in, out, s)
+}
+
 func autoConvert_v1alpha3_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1alpha3.PKCS12Keystore, out *certmanager.PKCS12Keystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
 	return nil
@@ -1227,7 +1953,7 @@ func Convert_v1alpha3_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1alpha3.
 
 func autoConvert_certmanager_PKCS12Keystore_To_v1alpha3_PKCS12Keystore(in *certmanager.PKCS12Keystore, out *v1alpha3.PKCS12Keystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
 	return nil
@@ -1238,6 +1964,68 @@ func Convert_certmanager_PKCS12Keystore_To_v1alpha3_PKCS12Keystore(in *certmanag
 	return autoConvert_certmanager_PKCS12Keystore_To_v1alpha3_PKCS12Keystore(in, out, s)
 }
 
+func autoConvert_v1alpha3_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(in *v1alpha3.PrivateKeyExternalRef, out *certmanager.PrivateKeyExternalRef, s conversion.Scope) error {
+	out.SignerURL = in.SignerURL
+	out.KeyID = in.KeyID
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TokenSecretRef = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef is an autogenerated conversion function.
+func Convert_v1alpha3_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(in *v1alpha3.PrivateKeyExternalRef, out *certmanager.PrivateKeyExternalRef, s conversion.Scope) error {
+	return autoConvert_v1alpha3_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(in, out, s)
+}
+
+func autoConvert_certmanager_PrivateKeyExternalRef_To_v1alpha3_PrivateKeyExternalRef(in *certmanager.PrivateKeyExternalRef, out *v1alpha3.PrivateKeyExternalRef, s conversion.Scope) error {
+	out.SignerURL = in.SignerURL
+	out.KeyID = in.KeyID
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TokenSecretRef = nil
+	}
+	return nil
+}
+
+// Convert_certmanager_PrivateKeyExternalRef_To_v1alpha3_PrivateKeyExternalRef is an autogenerated conversion function.
+func Convert_certmanager_PrivateKeyExternalRef_To_v1alpha3_PrivateKeyExternalRef(in *certmanager.PrivateKeyExternalRef, out *v1alpha3.PrivateKeyExternalRef, s conversion.Scope) error {
+	return autoConvert_certmanager_PrivateKeyExternalRef_To_v1alpha3_PrivateKeyExternalRef(in, out, s)
+}
+
+func autoConvert_v1alpha3_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(in *v1alpha3.SPIFFEPolicy, out *certmanager.SPIFFEPolicy, s conversion.Scope) error {
+	out.TrustDomains = *(*[]string)(unsafe.Pointer(&in.TrustDomains))
+	return nil
+}
+
+// Convert_v1alpha3_SPIFFEPolicy_To_certmanager_SPIFFEPolicy is an autogenerated conversion function.
+func Convert_v1alpha3_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(in *v1alpha3.SPIFFEPolicy, out *certmanager.SPIFFEPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(in, out, s)
+}
+
+func autoConvert_certmanager_SPIFFEPolicy_To_v1alpha3_SPIFFEPolicy(in *certmanager.SPIFFEPolicy, out *v1alpha3.SPIFFEPolicy, s conversion.Scope) error {
+	out.TrustDomains = *(*[]string)(unsafe.Pointer(&in.TrustDomains))
+	return nil
+}
+
+// Convert_certmanager_SPIFFEPolicy_To_v1alpha3_SPIFFEPolicy is an autogenerated conversion function.
+func Convert_certmanager_SPIFFEPolicy_To_v1alpha3_SPIFFEPolicy(in *certmanager.SPIFFEPolicy, out *v1alpha3.SPIFFEPolicy, s conversion.Scope) error {
+	return autoConvert_certmanager_SPIFFEPolicy_To_v1alpha3_SPIFFEPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha3_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1alpha3.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	return nil
@@ -1258,12 +2046,35 @@ func Convert_certmanager_SelfSignedIssuer_To_v1alpha3_SelfSignedIssuer(in *certm
 	return autoConvert_certmanager_SelfSignedIssuer_To_v1alpha3_SelfSignedIssuer(in, out, s)
 }
 
+func autoConvert_v1alpha3_ServiceAccountRef_To_certmanager_ServiceAccountRef(in *v1alpha3.ServiceAccountRef, out *certmanager.ServiceAccountRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Audiences = *(*[]string)(unsafe.Pointer(&in.Audiences))
+	return nil
+}
+
+// Convert_v1alpha3_ServiceAccountRef_To_certmanager_ServiceAccountRef is an autogenerated conversion function.
+func Convert_v1alpha3_ServiceAccountRef_To_certmanager_ServiceAccountRef(in *v1alpha3.ServiceAccountRef, out *certmanager.ServiceAccountRef, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ServiceAccountRef_To_certmanager_ServiceAccountRef(in, out, s)
+}
+
+func autoConvert_certmanager_ServiceAccountRef_To_v1alpha3_ServiceAccountRef(in *certmanager.ServiceAccountRef, out *v1alpha3.ServiceAccountRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Audiences = *(*[]string)(unsafe.Pointer(&in.Audiences))
+	return nil
+}
+
+// Convert_certmanager_ServiceAccountRef_To_v1alpha3_ServiceAccountRef is an autogenerated conversion function.
+func Convert_certmanager_ServiceAccountRef_To_v1alpha3_ServiceAccountRef(in *certmanager.ServiceAccountRef, out *v1alpha3.ServiceAccountRef, s conversion.Scope) error {
+	return autoConvert_certmanager_ServiceAccountRef_To_v1alpha3_ServiceAccountRef(in, out, s)
+}
+
 func autoConvert_v1alpha3_VaultAppRole_To_certmanager_VaultAppRole(in *v1alpha3.VaultAppRole, out *certmanager.VaultAppRole, s conversion.Scope) error {
 	out.Path = in.Path
 	out.RoleId = in.RoleId
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.SecretIDRotation = (*certmanager.VaultAppRoleSecretIDRotation)(unsafe.Pointer(in.SecretIDRotation))
 	return nil
 }
 
@@ -1275,9 +2086,10 @@ func Convert_v1alpha3_VaultAppRole_To_certmanager_VaultAppRole(in *v1alpha3.Vaul
 func autoConvert_certmanager_VaultAppRole_To_v1alpha3_VaultAppRole(in *certmanager.VaultAppRole, out *v1alpha3.VaultAppRole, s conversion.Scope) error {
 	out.Path = in.Path
 	out.RoleId = in.RoleId
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.SecretIDRotation = (*v1alpha3.VaultAppRoleSecretIDRotation)(unsafe.Pointer(in.SecretIDRotation))
 	return nil
 }
 
@@ -1286,11 +2098,33 @@ func Convert_certmanager_VaultAppRole_To_v1alpha3_VaultAppRole(in *certmanager.V
 	return autoConvert_certmanager_VaultAppRole_To_v1alpha3_VaultAppRole(in, out, s)
 }
 
+func autoConvert_v1alpha3_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(in *v1alpha3.VaultAppRoleSecretIDRotation, out *certmanager.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	out.RoleName = in.RoleName
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
+	return nil
+}
+
+// Convert_v1alpha3_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation is an autogenerated conversion function.
+func Convert_v1alpha3_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(in *v1alpha3.VaultAppRoleSecretIDRotation, out *certmanager.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	return autoConvert_v1alpha3_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(in, out, s)
+}
+
+func autoConvert_certmanager_VaultAppRoleSecretIDRotation_To_v1alpha3_VaultAppRoleSecretIDRotation(in *certmanager.VaultAppRoleSecretIDRotation, out *v1alpha3.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	out.RoleName = in.RoleName
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
+	return nil
+}
+
+// Convert_certmanager_VaultAppRoleSecretIDRotation_To_v1alpha3_VaultAppRoleSecretIDRotation is an autogenerated conversion function.
+func Convert_certmanager_VaultAppRoleSecretIDRotation_To_v1alpha3_VaultAppRoleSecretIDRotation(in *certmanager.VaultAppRoleSecretIDRotation, out *v1alpha3.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	return autoConvert_certmanager_VaultAppRoleSecretIDRotation_To_v1alpha3_VaultAppRoleSecretIDRotation(in, out, s)
+}
+
 func autoConvert_v1alpha3_VaultAuth_To_certmanager_VaultAuth(in *v1alpha3.VaultAuth, out *certmanager.VaultAuth, s conversion.Scope) error {
 	if in.TokenSecretRef != nil {
 		in, out := &in.TokenSecretRef, &out.TokenSecretRef
 		*out = new(meta.SecretKeySelector)
-		if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
@@ -1326,7 +2160,7 @@ func autoConvert_certmanager_VaultAuth_To_v1alpha3_VaultAuth(in *certmanager.Vau
 	if in.TokenSecretRef != nil {
 		in, out := &in.TokenSecretRef, &out.TokenSecretRef
 		*out = new(metav1.SecretKeySelector)
-		if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
@@ -1363,8 +2197,13 @@ func autoConvert_v1alpha3_VaultIssuer_To_certmanager_VaultIssuer(in *v1alpha3.Va
 		return err
 	}
 	out.Server = in.Server
+	out.AdditionalServers = *(*[]string)(unsafe.Pointer(&in.AdditionalServers))
+	out.ReadYourWrites = in.ReadYourWrites
 	out.Path = in.Path
 	out.Namespace = in.Namespace
+	out.AllowedNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedNamespaces))
+	out.SignVerbatim = in.SignVerbatim
+	out.SignVerbatimPath = in.SignVerbatimPath
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
 	return nil
 }
@@ -1379,8 +2218,13 @@ func autoConvert_certmanager_VaultIssuer_To_v1alpha3_VaultIssuer(in *certmanager
 		return err
 	}
 	out.Server = in.Server
+	out.AdditionalServers = *(*[]string)(unsafe.Pointer(&in.AdditionalServers))
+	out.ReadYourWrites = in.ReadYourWrites
 	out.Path = in.Path
 	out.Namespace = in.Namespace
+	out.AllowedNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedNamespaces))
+	out.SignVerbatim = in.SignVerbatim
+	out.SignVerbatimPath = in.SignVerbatimPath
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
 	return nil
 }
@@ -1392,9 +2236,10 @@ func Convert_certmanager_VaultIssuer_To_v1alpha3_VaultIssuer(in *certmanager.Vau
 
 func autoConvert_v1alpha3_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth(in *v1alpha3.VaultKubernetesAuth, out *certmanager.VaultKubernetesAuth, s conversion.Scope) error {
 	out.Path = in.Path
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.ServiceAccountRef = (*certmanager.ServiceAccountRef)(unsafe.Pointer(in.ServiceAccountRef))
 	out.Role = in.Role
 	return nil
 }
@@ -1406,9 +2251,10 @@ func Convert_v1alpha3_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth(in
 
 func autoConvert_certmanager_VaultKubernetesAuth_To_v1alpha3_VaultKubernetesAuth(in *certmanager.VaultKubernetesAuth, out *v1alpha3.VaultKubernetesAuth, s conversion.Scope) error {
 	out.Path = in.Path
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.ServiceAccountRef = (*v1alpha3.ServiceAccountRef)(unsafe.Pointer(in.ServiceAccountRef))
 	out.Role = in.Role
 	return nil
 }
@@ -1418,11 +2264,46 @@ func Convert_certmanager_VaultKubernetesAuth_To_v1alpha3_VaultKubernetesAuth(in
 	return autoConvert_certmanager_VaultKubernetesAuth_To_v1alpha3_VaultKubernetesAuth(in, out, s)
 }
 
+func autoConvert_v1alpha3_VaultSecretSink_To_certmanager_VaultSecretSink(in *v1alpha3.VaultSecretSink, out *certmanager.VaultSecretSink, s conversion.Scope) error {
+	if err := v1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+		return err
+	}
+	out.Path = in.Path
+	return nil
+}
+
+// Convert_v1alpha3_VaultSecretSink_To_certmanager_VaultSecretSink is an autogenerated conversion function.
+func Convert_v1alpha3_VaultSecretSink_To_certmanager_VaultSecretSink(in *v1alpha3.VaultSecretSink, out *certmanager.VaultSecretSink, s conversion.Scope) error {
+	return autoConvert_v1alpha3_VaultSecretSink_To_certmanager_VaultSecretSink(in, out, s)
+}
+
+func autoConvert_certmanager_VaultSecretSink_To_v1alpha3_VaultSecretSink(in *certmanager.VaultSecretSink, out *v1alpha3.VaultSecretSink, s conversion.Scope) error {
+	if err := v1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+		return err
+	}
+	out.Path = in.Path
+	return nil
+}
+
+// Convert_certmanager_VaultSecretSink_To_v1alpha3_VaultSecretSink is an autogenerated conversion function.
+func Convert_certmanager_VaultSecretSink_To_v1alpha3_VaultSecretSink(in *certmanager.VaultSecretSink, out *v1alpha3.VaultSecretSink, s conversion.Scope) error {
+	return autoConvert_certmanager_VaultSecretSink_To_v1alpha3_VaultSecretSink(in, out, s)
+}
+
 func autoConvert_v1alpha3_VenafiCloud_To_certmanager_VenafiCloud(in *v1alpha3.VenafiCloud, out *certmanager.VenafiCloud, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
 		return err
 	}
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(certmanager.VenafiCloudOAuth)
+		if err := Convert_v1alpha3_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OAuth = nil
+	}
 	return nil
 }
 
@@ -1433,9 +2314,18 @@ func Convert_v1alpha3_VenafiCloud_To_certmanager_VenafiCloud(in *v1alpha3.Venafi
 
 func autoConvert_certmanager_VenafiCloud_To_v1alpha3_VenafiCloud(in *certmanager.VenafiCloud, out *v1alpha3.VenafiCloud, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
 		return err
 	}
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(v1alpha3.VenafiCloudOAuth)
+		if err := Convert_certmanager_VenafiCloudOAuth_To_v1alpha3_VenafiCloudOAuth(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OAuth = nil
+	}
 	return nil
 }
 
@@ -1444,6 +2334,66 @@ func Convert_certmanager_VenafiCloud_To_v1alpha3_VenafiCloud(in *certmanager.Ven
 	return autoConvert_certmanager_VenafiCloud_To_v1alpha3_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1alpha3_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(in *v1alpha3.VenafiCloudOAuth, out *certmanager.VenafiCloudOAuth, s conversion.Scope) error {
+	out.TokenURL = in.TokenURL
+	out.ClientID = in.ClientID
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClientSecretRef = nil
+	}
+	if in.FederatedTokenSecretRef != nil {
+		in, out := &in.FederatedTokenSecretRef, &out.FederatedTokenSecretRef
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.FederatedTokenSecretRef = nil
+	}
+	out.Scopes = *(*[]string)(unsafe.Pointer(&in.Scopes))
+	return nil
+}
+
+// Convert_v1alpha3_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth is an autogenerated conversion function.
+func Convert_v1alpha3_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(in *v1alpha3.VenafiCloudOAuth, out *certmanager.VenafiCloudOAuth, s conversion.Scope) error {
+	return autoConvert_v1alpha3_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCloudOAuth_To_v1alpha3_VenafiCloudOAuth(in *certmanager.VenafiCloudOAuth, out *v1alpha3.VenafiCloudOAuth, s conversion.Scope) error {
+	out.TokenURL = in.TokenURL
+	out.ClientID = in.ClientID
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClientSecretRef = nil
+	}
+	if in.FederatedTokenSecretRef != nil {
+		in, out := &in.FederatedTokenSecretRef, &out.FederatedTokenSecretRef
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.FederatedTokenSecretRef = nil
+	}
+	out.Scopes = *(*[]string)(unsafe.Pointer(&in.Scopes))
+	return nil
+}
+
+// Convert_certmanager_VenafiCloudOAuth_To_v1alpha3_VenafiCloudOAuth is an autogenerated conversion function.
+func Convert_certmanager_VenafiCloudOAuth_To_v1alpha3_VenafiCloudOAuth(in *certmanager.VenafiCloudOAuth, out *v1alpha3.VenafiCloudOAuth, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCloudOAuth_To_v1alpha3_VenafiCloudOAuth(in, out, s)
+}
+
 func autoConvert_v1alpha3_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1alpha3.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	if in.TPP != nil {
@@ -1464,6 +2414,7 @@ func autoConvert_v1alpha3_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1alpha3.
 	} else {
 		out.Cloud = nil
 	}
+	out.CustomFields = *(*map[string]string)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -1492,6 +2443,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1alpha3_VenafiIssuer(in *certmanag
 	} else {
 		out.Cloud = nil
 	}
+	out.CustomFields = *(*map[string]string)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -1502,7 +2454,7 @@ func Convert_certmanager_VenafiIssuer_To_v1alpha3_VenafiIssuer(in *certmanager.V
 
 func autoConvert_v1alpha3_VenafiTPP_To_certmanager_VenafiTPP(in *v1alpha3.VenafiTPP, out *certmanager.VenafiTPP, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_v1_LocalObjectReference_To_meta_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
+	if err := v1.Convert_v1_LocalObjectReference_To_meta_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
 		return err
 	}
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
@@ -1516,7 +2468,7 @@ func Convert_v1alpha3_VenafiTPP_To_certmanager_VenafiTPP(in *v1alpha3.VenafiTPP,
 
 func autoConvert_certmanager_VenafiTPP_To_v1alpha3_VenafiTPP(in *certmanager.VenafiTPP, out *v1alpha3.VenafiTPP, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_meta_LocalObjectReference_To_v1_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
+	if err := v1.Convert_meta_LocalObjectReference_To_v1_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
 		return err
 	}
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
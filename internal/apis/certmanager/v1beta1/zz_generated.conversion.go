@@ -28,11 +28,11 @@ import (
 	acmev1beta1 "github.com/jetstack/cert-manager/internal/apis/acme/v1beta1"
 	certmanager "github.com/jetstack/cert-manager/internal/apis/certmanager"
 	meta "github.com/jetstack/cert-manager/internal/apis/meta"
-	apismetav1 "github.com/jetstack/cert-manager/internal/apis/meta/v1"
+	v1 "github.com/jetstack/cert-manager/internal/apis/meta/v1"
 	apisacmev1beta1 "github.com/jetstack/cert-manager/pkg/apis/acme/v1beta1"
 	v1beta1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1beta1"
 	metav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apismetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -44,6 +44,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AWSSecretsManagerSecretSink)(nil), (*certmanager.AWSSecretsManagerSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(a.(*v1beta1.AWSSecretsManagerSecretSink), b.(*certmanager.AWSSecretsManagerSecretSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.AWSSecretsManagerSecretSink)(nil), (*v1beta1.AWSSecretsManagerSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_AWSSecretsManagerSecretSink_To_v1beta1_AWSSecretsManagerSecretSink(a.(*certmanager.AWSSecretsManagerSecretSink), b.(*v1beta1.AWSSecretsManagerSecretSink), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CAIssuer_To_certmanager_CAIssuer(a.(*v1beta1.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -54,6 +64,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuerCRL)(nil), (*certmanager.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(a.(*v1beta1.CAIssuerCRL), b.(*certmanager.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerCRL)(nil), (*v1beta1.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(a.(*certmanager.CAIssuerCRL), b.(*v1beta1.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuerCertificateTransparency)(nil), (*certmanager.CAIssuerCertificateTransparency)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(a.(*v1beta1.CAIssuerCertificateTransparency), b.(*certmanager.CAIssuerCertificateTransparency), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerCertificateTransparency)(nil), (*v1beta1.CAIssuerCertificateTransparency)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerCertificateTransparency_To_v1beta1_CAIssuerCertificateTransparency(a.(*certmanager.CAIssuerCertificateTransparency), b.(*v1beta1.CAIssuerCertificateTransparency), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuerSerialNumber)(nil), (*certmanager.CAIssuerSerialNumber)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(a.(*v1beta1.CAIssuerSerialNumber), b.(*certmanager.CAIssuerSerialNumber), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerSerialNumber)(nil), (*v1beta1.CAIssuerSerialNumber)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerSerialNumber_To_v1beta1_CAIssuerSerialNumber(a.(*certmanager.CAIssuerSerialNumber), b.(*v1beta1.CAIssuerSerialNumber), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuerStatus)(nil), (*certmanager.CAIssuerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CAIssuerStatus_To_certmanager_CAIssuerStatus(a.(*v1beta1.CAIssuerStatus), b.(*certmanager.CAIssuerStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerStatus)(nil), (*v1beta1.CAIssuerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerStatus_To_v1beta1_CAIssuerStatus(a.(*certmanager.CAIssuerStatus), b.(*v1beta1.CAIssuerStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CATrustAnchor)(nil), (*certmanager.CATrustAnchor)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CATrustAnchor_To_certmanager_CATrustAnchor(a.(*v1beta1.CATrustAnchor), b.(*certmanager.CATrustAnchor), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CATrustAnchor)(nil), (*v1beta1.CATrustAnchor)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CATrustAnchor_To_v1beta1_CATrustAnchor(a.(*certmanager.CATrustAnchor), b.(*v1beta1.CATrustAnchor), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Certificate_To_certmanager_Certificate(a.(*v1beta1.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -64,6 +124,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateAdditionalOutputFormat)(nil), (*certmanager.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(a.(*v1beta1.CertificateAdditionalOutputFormat), b.(*certmanager.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateAdditionalOutputFormat)(nil), (*v1beta1.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(a.(*certmanager.CertificateAdditionalOutputFormat), b.(*v1beta1.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateCAConstraints)(nil), (*certmanager.CertificateCAConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(a.(*v1beta1.CertificateCAConstraints), b.(*certmanager.CertificateCAConstraints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateCAConstraints)(nil), (*v1beta1.CertificateCAConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateCAConstraints_To_v1beta1_CertificateCAConstraints(a.(*certmanager.CertificateCAConstraints), b.(*v1beta1.CertificateCAConstraints), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateCondition)(nil), (*certmanager.CertificateCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateCondition_To_certmanager_CertificateCondition(a.(*v1beta1.CertificateCondition), b.(*certmanager.CertificateCondition), scope)
 	}); err != nil {
@@ -104,6 +184,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRenewalRequest)(nil), (*certmanager.CertificateRenewalRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(a.(*v1beta1.CertificateRenewalRequest), b.(*certmanager.CertificateRenewalRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalRequest)(nil), (*v1beta1.CertificateRenewalRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalRequest_To_v1beta1_CertificateRenewalRequest(a.(*certmanager.CertificateRenewalRequest), b.(*v1beta1.CertificateRenewalRequest), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRequest)(nil), (*certmanager.CertificateRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateRequest_To_certmanager_CertificateRequest(a.(*v1beta1.CertificateRequest), b.(*certmanager.CertificateRequest), scope)
 	}); err != nil {
@@ -114,6 +204,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRequestApprovalRecord)(nil), (*certmanager.CertificateRequestApprovalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(a.(*v1beta1.CertificateRequestApprovalRecord), b.(*certmanager.CertificateRequestApprovalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRequestApprovalRecord)(nil), (*v1beta1.CertificateRequestApprovalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRequestApprovalRecord_To_v1beta1_CertificateRequestApprovalRecord(a.(*certmanager.CertificateRequestApprovalRecord), b.(*v1beta1.CertificateRequestApprovalRecord), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRequestCondition)(nil), (*certmanager.CertificateRequestCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateRequestCondition_To_certmanager_CertificateRequestCondition(a.(*v1beta1.CertificateRequestCondition), b.(*certmanager.CertificateRequestCondition), scope)
 	}); err != nil {
@@ -134,6 +234,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRequestRequesterIdentity)(nil), (*certmanager.CertificateRequestRequesterIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(a.(*v1beta1.CertificateRequestRequesterIdentity), b.(*certmanager.CertificateRequestRequesterIdentity), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRequestRequesterIdentity)(nil), (*v1beta1.CertificateRequestRequesterIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRequestRequesterIdentity_To_v1beta1_CertificateRequestRequesterIdentity(a.(*certmanager.CertificateRequestRequesterIdentity), b.(*v1beta1.CertificateRequestRequesterIdentity), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRequestSpec)(nil), (*certmanager.CertificateRequestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(a.(*v1beta1.CertificateRequestSpec), b.(*certmanager.CertificateRequestSpec), scope)
 	}); err != nil {
@@ -154,6 +264,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateSecretSink)(nil), (*certmanager.CertificateSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateSecretSink_To_certmanager_CertificateSecretSink(a.(*v1beta1.CertificateSecretSink), b.(*certmanager.CertificateSecretSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateSecretSink)(nil), (*v1beta1.CertificateSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateSecretSink_To_v1beta1_CertificateSecretSink(a.(*certmanager.CertificateSecretSink), b.(*v1beta1.CertificateSecretSink), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateSecretTemplate)(nil), (*certmanager.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(a.(*v1beta1.CertificateSecretTemplate), b.(*certmanager.CertificateSecretTemplate), scope)
 	}); err != nil {
@@ -274,6 +394,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.JKSTruststore)(nil), (*certmanager.JKSTruststore)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_JKSTruststore_To_certmanager_JKSTruststore(a.(*v1beta1.JKSTruststore), b.(*certmanager.JKSTruststore), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.JKSTruststore)(nil), (*v1beta1.JKSTruststore)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_JKSTruststore_To_v1beta1_JKSTruststore(a.(*certmanager.JKSTruststore), b.(*v1beta1.JKSTruststore), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NameConstraintItem)(nil), (*certmanager.NameConstraintItem)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NameConstraintItem_To_certmanager_NameConstraintItem(a.(*v1beta1.NameConstraintItem), b.(*certmanager.NameConstraintItem), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.NameConstraintItem)(nil), (*v1beta1.NameConstraintItem)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_NameConstraintItem_To_v1beta1_NameConstraintItem(a.(*certmanager.NameConstraintItem), b.(*v1beta1.NameConstraintItem), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NameConstraints)(nil), (*certmanager.NameConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NameConstraints_To_certmanager_NameConstraints(a.(*v1beta1.NameConstraints), b.(*certmanager.NameConstraints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.NameConstraints)(nil), (*v1beta1.NameConstraints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_NameConstraints_To_v1beta1_NameConstraints(a.(*certmanager.NameConstraints), b.(*v1beta1.NameConstraints), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.PKCS12Keystore)(nil), (*certmanager.PKCS12Keystore)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(a.(*v1beta1.PKCS12Keystore), b.(*certmanager.PKCS12Keystore), scope)
 	}); err != nil {
@@ -284,6 +434,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.PrivateKeyExternalRef)(nil), (*certmanager.PrivateKeyExternalRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(a.(*v1beta1.PrivateKeyExternalRef), b.(*certmanager.PrivateKeyExternalRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.PrivateKeyExternalRef)(nil), (*v1beta1.PrivateKeyExternalRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_PrivateKeyExternalRef_To_v1beta1_PrivateKeyExternalRef(a.(*certmanager.PrivateKeyExternalRef), b.(*v1beta1.PrivateKeyExternalRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SPIFFEPolicy)(nil), (*certmanager.SPIFFEPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(a.(*v1beta1.SPIFFEPolicy), b.(*certmanager.SPIFFEPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SPIFFEPolicy)(nil), (*v1beta1.SPIFFEPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SPIFFEPolicy_To_v1beta1_SPIFFEPolicy(a.(*certmanager.SPIFFEPolicy), b.(*v1beta1.SPIFFEPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.SelfSignedIssuer)(nil), (*certmanager.SelfSignedIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(a.(*v1beta1.SelfSignedIssuer), b.(*certmanager.SelfSignedIssuer), scope)
 	}); err != nil {
@@ -294,6 +464,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ServiceAccountRef)(nil), (*certmanager.ServiceAccountRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ServiceAccountRef_To_certmanager_ServiceAccountRef(a.(*v1beta1.ServiceAccountRef), b.(*certmanager.ServiceAccountRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ServiceAccountRef)(nil), (*v1beta1.ServiceAccountRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ServiceAccountRef_To_v1beta1_ServiceAccountRef(a.(*certmanager.ServiceAccountRef), b.(*v1beta1.ServiceAccountRef), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VaultAppRole)(nil), (*certmanager.VaultAppRole)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VaultAppRole_To_certmanager_VaultAppRole(a.(*v1beta1.VaultAppRole), b.(*certmanager.VaultAppRole), scope)
 	}); err != nil {
@@ -304,6 +484,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VaultAppRoleSecretIDRotation)(nil), (*certmanager.VaultAppRoleSecretIDRotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(a.(*v1beta1.VaultAppRoleSecretIDRotation), b.(*certmanager.VaultAppRoleSecretIDRotation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VaultAppRoleSecretIDRotation)(nil), (*v1beta1.VaultAppRoleSecretIDRotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VaultAppRoleSecretIDRotation_To_v1beta1_VaultAppRoleSecretIDRotation(a.(*certmanager.VaultAppRoleSecretIDRotation), b.(*v1beta1.VaultAppRoleSecretIDRotation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VaultAuth)(nil), (*certmanager.VaultAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VaultAuth_To_certmanager_VaultAuth(a.(*v1beta1.VaultAuth), b.(*certmanager.VaultAuth), scope)
 	}); err != nil {
@@ -334,6 +524,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VaultSecretSink)(nil), (*certmanager.VaultSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VaultSecretSink_To_certmanager_VaultSecretSink(a.(*v1beta1.VaultSecretSink), b.(*certmanager.VaultSecretSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VaultSecretSink)(nil), (*v1beta1.VaultSecretSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VaultSecretSink_To_v1beta1_VaultSecretSink(a.(*certmanager.VaultSecretSink), b.(*v1beta1.VaultSecretSink), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiCloud)(nil), (*certmanager.VenafiCloud)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VenafiCloud_To_certmanager_VenafiCloud(a.(*v1beta1.VenafiCloud), b.(*certmanager.VenafiCloud), scope)
 	}); err != nil {
@@ -344,6 +544,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiCloudOAuth)(nil), (*certmanager.VenafiCloudOAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(a.(*v1beta1.VenafiCloudOAuth), b.(*certmanager.VenafiCloudOAuth), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCloudOAuth)(nil), (*v1beta1.VenafiCloudOAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCloudOAuth_To_v1beta1_VenafiCloudOAuth(a.(*certmanager.VenafiCloudOAuth), b.(*v1beta1.VenafiCloudOAuth), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1beta1.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -377,10 +587,60 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1beta1_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(in *v1beta1.AWSSecretsManagerSecretSink, out *certmanager.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	out.Region = in.Region
+	out.SecretID = in.SecretID
+	out.AccessKeyID = in.AccessKeyID
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretAccessKey = nil
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_v1beta1_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink is an autogenerated conversion function.
+func Convert_v1beta1_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(in *v1beta1.AWSSecretsManagerSecretSink, out *certmanager.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	return autoConvert_v1beta1_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(in, out, s)
+}
+
+func autoConvert_certmanager_AWSSecretsManagerSecretSink_To_v1beta1_AWSSecretsManagerSecretSink(in *certmanager.AWSSecretsManagerSecretSink, out *v1beta1.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	out.Region = in.Region
+	out.SecretID = in.SecretID
+	out.AccessKeyID = in.AccessKeyID
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretAccessKey = nil
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_certmanager_AWSSecretsManagerSecretSink_To_v1beta1_AWSSecretsManagerSecretSink is an autogenerated conversion function.
+func Convert_certmanager_AWSSecretsManagerSecretSink_To_v1beta1_AWSSecretsManagerSecretSink(in *certmanager.AWSSecretsManagerSecretSink, out *v1beta1.AWSSecretsManagerSecretSink, s conversion.Scope) error {
+	return autoConvert_certmanager_AWSSecretsManagerSecretSink_To_v1beta1_AWSSecretsManagerSecretSink(in, out, s)
+}
+
 func autoConvert_v1beta1_CAIssuer_To_certmanager_CAIssuer(in *v1beta1.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.CRL = (*certmanager.CAIssuerCRL)(unsafe.Pointer(in.CRL))
+	out.CertificateTransparency = (*certmanager.CAIssuerCertificateTransparency)(unsafe.Pointer(in.CertificateTransparency))
+	out.SerialNumber = (*certmanager.CAIssuerSerialNumber)(unsafe.Pointer(in.SerialNumber))
+	out.FetchIntermediateCertificates = in.FetchIntermediateCertificates
+	out.NextSecretName = in.NextSecretName
+	out.ReissueOnRotation = in.ReissueOnRotation
 	return nil
 }
 
@@ -393,6 +653,12 @@ func autoConvert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in *certmanager.CAIssu
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.CRL = (*v1beta1.CAIssuerCRL)(unsafe.Pointer(in.CRL))
+	out.CertificateTransparency = (*v1beta1.CAIssuerCertificateTransparency)(unsafe.Pointer(in.CertificateTransparency))
+	out.SerialNumber = (*v1beta1.CAIssuerSerialNumber)(unsafe.Pointer(in.SerialNumber))
+	out.FetchIntermediateCertificates = in.FetchIntermediateCertificates
+	out.NextSecretName = in.NextSecretName
+	out.ReissueOnRotation = in.ReissueOnRotation
 	return nil
 }
 
@@ -401,6 +667,122 @@ func Convert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in *certmanager.CAIssuer,
 	return autoConvert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1beta1.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.ConfigMapName = in.ConfigMapName
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL is an autogenerated conversion function.
+func Convert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1beta1.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1beta1.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.ConfigMapName = in.ConfigMapName
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1beta1.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(in, out, s)
+}
+
+func autoConvert_v1beta1_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(in *v1beta1.CAIssuerCertificateTransparency, out *certmanager.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_v1beta1_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency is an autogenerated conversion function.
+func Convert_v1beta1_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(in *v1beta1.CAIssuerCertificateTransparency, out *certmanager.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	return autoConvert_v1beta1_CAIssuerCertificateTransparency_To_certmanager_CAIssuerCertificateTransparency(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerCertificateTransparency_To_v1beta1_CAIssuerCertificateTransparency(in *certmanager.CAIssuerCertificateTransparency, out *v1beta1.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerCertificateTransparency_To_v1beta1_CAIssuerCertificateTransparency is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerCertificateTransparency_To_v1beta1_CAIssuerCertificateTransparency(in *certmanager.CAIssuerCertificateTransparency, out *v1beta1.CAIssuerCertificateTransparency, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerCertificateTransparency_To_v1beta1_CAIssuerCertificateTransparency(in, out, s)
+}
+
+func autoConvert_v1beta1_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(in *v1beta1.CAIssuerSerialNumber, out *certmanager.CAIssuerSerialNumber, s conversion.Scope) error {
+	out.Type = certmanager.CAIssuerSerialNumberType(in.Type)
+	out.Length = in.Length
+	out.CounterConfigMapName = in.CounterConfigMapName
+	return nil
+}
+
+// Convert_v1beta1_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber is an autogenerated conversion function.
+func Convert_v1beta1_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(in *v1beta1.CAIssuerSerialNumber, out *certmanager.CAIssuerSerialNumber, s conversion.Scope) error {
+	return autoConvert_v1beta1_CAIssuerSerialNumber_To_certmanager_CAIssuerSerialNumber(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerSerialNumber_To_v1beta1_CAIssuerSerialNumber(in *certmanager.CAIssuerSerialNumber, out *v1beta1.CAIssuerSerialNumber, s conversion.Scope) error {
+	out.Type = v1beta1.CAIssuerSerialNumberType(in.Type)
+	out.Length = in.Length
+	out.CounterConfigMapName = in.CounterConfigMapName
+	return nil
+}
+
+// Convert_certmanager_CAIssuerSerialNumber_To_v1beta1_CAIssuerSerialNumber is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerSerialNumber_To_v1beta1_CAIssuerSerialNumber(in *certmanager.CAIssuerSerialNumber, out *v1beta1.CAIssuerSerialNumber, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerSerialNumber_To_v1beta1_CAIssuerSerialNumber(in, out, s)
+}
+
+func autoConvert_v1beta1_CAIssuerStatus_To_certmanager_CAIssuerStatus(in *v1beta1.CAIssuerStatus, out *certmanager.CAIssuerStatus, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]certmanager.CATrustAnchor)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_v1beta1_CAIssuerStatus_To_certmanager_CAIssuerStatus is an autogenerated conversion function.
+func Convert_v1beta1_CAIssuerStatus_To_certmanager_CAIssuerStatus(in *v1beta1.CAIssuerStatus, out *certmanager.CAIssuerStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_CAIssuerStatus_To_certmanager_CAIssuerStatus(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerStatus_To_v1beta1_CAIssuerStatus(in *certmanager.CAIssuerStatus, out *v1beta1.CAIssuerStatus, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]v1beta1.CATrustAnchor)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerStatus_To_v1beta1_CAIssuerStatus is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerStatus_To_v1beta1_CAIssuerStatus(in *certmanager.CAIssuerStatus, out *v1beta1.CAIssuerStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerStatus_To_v1beta1_CAIssuerStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_CATrustAnchor_To_certmanager_CATrustAnchor(in *v1beta1.CATrustAnchor, out *certmanager.CATrustAnchor, s conversion.Scope) error {
+	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
+	out.Next = in.Next
+	out.NotBefore = in.NotBefore
+	out.NotAfter = in.NotAfter
+	return nil
+}
+
+// Convert_v1beta1_CATrustAnchor_To_certmanager_CATrustAnchor is an autogenerated conversion function.
+func Convert_v1beta1_CATrustAnchor_To_certmanager_CATrustAnchor(in *v1beta1.CATrustAnchor, out *certmanager.CATrustAnchor, s conversion.Scope) error {
+	return autoConvert_v1beta1_CATrustAnchor_To_certmanager_CATrustAnchor(in, out, s)
+}
+
+func autoConvert_certmanager_CATrustAnchor_To_v1beta1_CATrustAnchor(in *certmanager.CATrustAnchor, out *v1beta1.CATrustAnchor, s conversion.Scope) error {
+	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
+	out.Next = in.Next
+	out.NotBefore = in.NotBefore
+	out.NotAfter = in.NotAfter
+	return nil
+}
+
+// Convert_certmanager_CATrustAnchor_To_v1beta1_CATrustAnchor is an autogenerated conversion function.
+func Convert_certmanager_CATrustAnchor_To_v1beta1_CATrustAnchor(in *certmanager.CATrustAnchor, out *v1beta1.CATrustAnchor, s conversion.Scope) error {
+	return autoConvert_certmanager_CATrustAnchor_To_v1beta1_CATrustAnchor(in, out, s)
+}
+
 func autoConvert_v1beta1_Certificate_To_certmanager_Certificate(in *v1beta1.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -433,10 +815,60 @@ func Convert_certmanager_Certificate_To_v1beta1_Certificate(in *certmanager.Cert
 	return autoConvert_certmanager_Certificate_To_v1beta1_Certificate(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1beta1.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = certmanager.CertificateOutputFormatType(in.Type)
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1beta1.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1beta1.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = v1beta1.CertificateOutputFormatType(in.Type)
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1beta1.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(in, out, s)
+}
+
+func autoConvert_v1beta1_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(in *v1beta1.CertificateCAConstraints, out *certmanager.CertificateCAConstraints, s conversion.Scope) error {
+	out.MaxPathLen = (*int)(unsafe.Pointer(in.MaxPathLen))
+	out.NameConstraints = (*certmanager.NameConstraints)(unsafe.Pointer(in.NameConstraints))
+	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	return nil
+}
+
+// Convert_v1beta1_CertificateCAConstraints_To_certmanager_CertificateCAConstraints is an autogenerated conversion function.
+func Convert_v1beta1_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(in *v1beta1.CertificateCAConstraints, out *certmanager.CertificateCAConstraints, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateCAConstraints_To_certmanager_CertificateCAConstraints(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateCAConstraints_To_v1beta1_CertificateCAConstraints(in *certmanager.CertificateCAConstraints, out *v1beta1.CertificateCAConstraints, s conversion.Scope) error {
+	out.MaxPathLen = (*int)(unsafe.Pointer(in.MaxPathLen))
+	out.NameConstraints = (*v1beta1.NameConstraints)(unsafe.Pointer(in.NameConstraints))
+	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	return nil
+}
+
+// Convert_certmanager_CertificateCAConstraints_To_v1beta1_CertificateCAConstraints is an autogenerated conversion function.
+func Convert_certmanager_CertificateCAConstraints_To_v1beta1_CertificateCAConstraints(in *certmanager.CertificateCAConstraints, out *v1beta1.CertificateCAConstraints, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateCAConstraints_To_v1beta1_CertificateCAConstraints(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateCondition_To_certmanager_CertificateCondition(in *v1beta1.CertificateCondition, out *certmanager.CertificateCondition, s conversion.Scope) error {
 	out.Type = certmanager.CertificateConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -451,7 +883,7 @@ func Convert_v1beta1_CertificateCondition_To_certmanager_CertificateCondition(in
 func autoConvert_certmanager_CertificateCondition_To_v1beta1_CertificateCondition(in *certmanager.CertificateCondition, out *v1beta1.CertificateCondition, s conversion.Scope) error {
 	out.Type = v1beta1.CertificateConditionType(in.Type)
 	out.Status = metav1.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -564,6 +996,15 @@ func autoConvert_v1beta1_CertificatePrivateKey_To_certmanager_CertificatePrivate
 	out.Encoding = certmanager.PrivateKeyEncoding(in.Encoding)
 	out.Algorithm = certmanager.PrivateKeyAlgorithm(in.Algorithm)
 	out.Size = in.Size
+	if in.ExternalRef != nil {
+		in, out := &in.ExternalRef, &out.ExternalRef
+		*out = new(certmanager.PrivateKeyExternalRef)
+		if err := Convert_v1beta1_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalRef = nil
+	}
 	return nil
 }
 
@@ -577,6 +1018,15 @@ func autoConvert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivate
 	out.Encoding = v1beta1.PrivateKeyEncoding(in.Encoding)
 	out.Algorithm = v1beta1.PrivateKeyAlgorithm(in.Algorithm)
 	out.Size = in.Size
+	if in.ExternalRef != nil {
+		in, out := &in.ExternalRef, &out.ExternalRef
+		*out = new(v1beta1.PrivateKeyExternalRef)
+		if err := Convert_certmanager_PrivateKeyExternalRef_To_v1beta1_PrivateKeyExternalRef(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ExternalRef = nil
+	}
 	return nil
 }
 
@@ -585,6 +1035,30 @@ func Convert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivateKey(
 	return autoConvert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivateKey(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(in *v1beta1.CertificateRenewalRequest, out *certmanager.CertificateRenewalRequest, s conversion.Scope) error {
+	out.Reason = in.Reason
+	out.Username = in.Username
+	out.Time = (*apismetav1.Time)(unsafe.Pointer(in.Time))
+	return nil
+}
+
+// Convert_v1beta1_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest is an autogenerated conversion function.
+func Convert_v1beta1_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(in *v1beta1.CertificateRenewalRequest, out *certmanager.CertificateRenewalRequest, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateRenewalRequest_To_certmanager_CertificateRenewalRequest(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalRequest_To_v1beta1_CertificateRenewalRequest(in *certmanager.CertificateRenewalRequest, out *v1beta1.CertificateRenewalRequest, s conversion.Scope) error {
+	out.Reason = in.Reason
+	out.Username = in.Username
+	out.Time = (*apismetav1.Time)(unsafe.Pointer(in.Time))
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalRequest_To_v1beta1_CertificateRenewalRequest is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalRequest_To_v1beta1_CertificateRenewalRequest(in *certmanager.CertificateRenewalRequest, out *v1beta1.CertificateRenewalRequest, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalRequest_To_v1beta1_CertificateRenewalRequest(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateRequest_To_certmanager_CertificateRequest(in *v1beta1.CertificateRequest, out *certmanager.CertificateRequest, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -617,10 +1091,40 @@ func Convert_certmanager_CertificateRequest_To_v1beta1_CertificateRequest(in *ce
 	return autoConvert_certmanager_CertificateRequest_To_v1beta1_CertificateRequest(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(in *v1beta1.CertificateRequestApprovalRecord, out *certmanager.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	out.Type = certmanager.CertificateRequestConditionType(in.Type)
+	out.Username = in.Username
+	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_v1beta1_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord is an autogenerated conversion function.
+func Convert_v1beta1_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(in *v1beta1.CertificateRequestApprovalRecord, out *certmanager.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateRequestApprovalRecord_To_certmanager_CertificateRequestApprovalRecord(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRequestApprovalRecord_To_v1beta1_CertificateRequestApprovalRecord(in *certmanager.CertificateRequestApprovalRecord, out *v1beta1.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	out.Type = v1beta1.CertificateRequestConditionType(in.Type)
+	out.Username = in.Username
+	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_certmanager_CertificateRequestApprovalRecord_To_v1beta1_CertificateRequestApprovalRecord is an autogenerated conversion function.
+func Convert_certmanager_CertificateRequestApprovalRecord_To_v1beta1_CertificateRequestApprovalRecord(in *certmanager.CertificateRequestApprovalRecord, out *v1beta1.CertificateRequestApprovalRecord, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRequestApprovalRecord_To_v1beta1_CertificateRequestApprovalRecord(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateRequestCondition_To_certmanager_CertificateRequestCondition(in *v1beta1.CertificateRequestCondition, out *certmanager.CertificateRequestCondition, s conversion.Scope) error {
 	out.Type = certmanager.CertificateRequestConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	return nil
@@ -634,7 +1138,7 @@ func Convert_v1beta1_CertificateRequestCondition_To_certmanager_CertificateReque
 func autoConvert_certmanager_CertificateRequestCondition_To_v1beta1_CertificateRequestCondition(in *certmanager.CertificateRequestCondition, out *v1beta1.CertificateRequestCondition, s conversion.Scope) error {
 	out.Type = v1beta1.CertificateRequestConditionType(in.Type)
 	out.Status = metav1.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	return nil
@@ -687,9 +1191,33 @@ func Convert_certmanager_CertificateRequestList_To_v1beta1_CertificateRequestLis
 	return autoConvert_certmanager_CertificateRequestList_To_v1beta1_CertificateRequestList(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(in *v1beta1.CertificateRequestRequesterIdentity, out *certmanager.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.ServiceAccount = in.ServiceAccount
+	out.Pod = in.Pod
+	return nil
+}
+
+// Convert_v1beta1_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity is an autogenerated conversion function.
+func Convert_v1beta1_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(in *v1beta1.CertificateRequestRequesterIdentity, out *certmanager.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateRequestRequesterIdentity_To_certmanager_CertificateRequestRequesterIdentity(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRequestRequesterIdentity_To_v1beta1_CertificateRequestRequesterIdentity(in *certmanager.CertificateRequestRequesterIdentity, out *v1beta1.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.ServiceAccount = in.ServiceAccount
+	out.Pod = in.Pod
+	return nil
+}
+
+// Convert_certmanager_CertificateRequestRequesterIdentity_To_v1beta1_CertificateRequestRequesterIdentity is an autogenerated conversion function.
+func Convert_certmanager_CertificateRequestRequesterIdentity_To_v1beta1_CertificateRequestRequesterIdentity(in *certmanager.CertificateRequestRequesterIdentity, out *v1beta1.CertificateRequestRequesterIdentity, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRequestRequesterIdentity_To_v1beta1_CertificateRequestRequesterIdentity(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(in *v1beta1.CertificateRequestSpec, out *certmanager.CertificateRequestSpec, s conversion.Scope) error {
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	if err := apismetav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	if err := v1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	out.Request = *(*[]byte)(unsafe.Pointer(&in.Request))
@@ -699,6 +1227,7 @@ func autoConvert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateReques
 	out.UID = in.UID
 	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
 	out.Extra = *(*map[string][]string)(unsafe.Pointer(&in.Extra))
+	out.RequesterIdentity = (*certmanager.CertificateRequestRequesterIdentity)(unsafe.Pointer(in.RequesterIdentity))
 	return nil
 }
 
@@ -708,8 +1237,8 @@ func Convert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateRequestSpe
 }
 
 func autoConvert_certmanager_CertificateRequestSpec_To_v1beta1_CertificateRequestSpec(in *certmanager.CertificateRequestSpec, out *v1beta1.CertificateRequestSpec, s conversion.Scope) error {
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	if err := apismetav1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	if err := v1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	out.Request = *(*[]byte)(unsafe.Pointer(&in.Request))
@@ -719,6 +1248,7 @@ func autoConvert_certmanager_CertificateRequestSpec_To_v1beta1_CertificateReques
 	out.UID = in.UID
 	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
 	out.Extra = *(*map[string][]string)(unsafe.Pointer(&in.Extra))
+	out.RequesterIdentity = (*v1beta1.CertificateRequestRequesterIdentity)(unsafe.Pointer(in.RequesterIdentity))
 	return nil
 }
 
@@ -731,7 +1261,8 @@ func autoConvert_v1beta1_CertificateRequestStatus_To_certmanager_CertificateRequ
 	out.Conditions = *(*[]certmanager.CertificateRequestCondition)(unsafe.Pointer(&in.Conditions))
 	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
 	out.CA = *(*[]byte)(unsafe.Pointer(&in.CA))
-	out.FailureTime = (*v1.Time)(unsafe.Pointer(in.FailureTime))
+	out.FailureTime = (*apismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.ApprovalHistory = *(*[]certmanager.CertificateRequestApprovalRecord)(unsafe.Pointer(&in.ApprovalHistory))
 	return nil
 }
 
@@ -744,7 +1275,8 @@ func autoConvert_certmanager_CertificateRequestStatus_To_v1beta1_CertificateRequ
 	out.Conditions = *(*[]v1beta1.CertificateRequestCondition)(unsafe.Pointer(&in.Conditions))
 	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
 	out.CA = *(*[]byte)(unsafe.Pointer(&in.CA))
-	out.FailureTime = (*v1.Time)(unsafe.Pointer(in.FailureTime))
+	out.FailureTime = (*apismetav1.Time)(unsafe.Pointer(in.FailureTime))
+	out.ApprovalHistory = *(*[]v1beta1.CertificateRequestApprovalRecord)(unsafe.Pointer(&in.ApprovalHistory))
 	return nil
 }
 
@@ -753,6 +1285,62 @@ func Convert_certmanager_CertificateRequestStatus_To_v1beta1_CertificateRequestS
 	return autoConvert_certmanager_CertificateRequestStatus_To_v1beta1_CertificateRequestStatus(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateSecretSink_To_certmanager_CertificateSecretSink(in *v1beta1.CertificateSecretSink, out *certmanager.CertificateSecretSink, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(certmanager.VaultSecretSink)
+		if err := Convert_v1beta1_VaultSecretSink_To_certmanager_VaultSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	if in.AWSSecretsManager != nil {
+		in, out := &in.AWSSecretsManager, &out.AWSSecretsManager
+		*out = new(certmanager.AWSSecretsManagerSecretSink)
+		if err := Convert_v1beta1_AWSSecretsManagerSecretSink_To_certmanager_AWSSecretsManagerSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSSecretsManager = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_CertificateSecretSink_To_certmanager_CertificateSecretSink is an autogenerated conversion function.
+func Convert_v1beta1_CertificateSecretSink_To_certmanager_CertificateSecretSink(in *v1beta1.CertificateSecretSink, out *certmanager.CertificateSecretSink, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateSecretSink_To_certmanager_CertificateSecretSink(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateSecretSink_To_v1beta1_CertificateSecretSink(in *certmanager.CertificateSecretSink, out *v1beta1.CertificateSecretSink, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(v1beta1.VaultSecretSink)
+		if err := Convert_certmanager_VaultSecretSink_To_v1beta1_VaultSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	if in.AWSSecretsManager != nil {
+		in, out := &in.AWSSecretsManager, &out.AWSSecretsManager
+		*out = new(v1beta1.AWSSecretsManagerSecretSink)
+		if err := Convert_certmanager_AWSSecretsManagerSecretSink_To_v1beta1_AWSSecretsManagerSecretSink(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSSecretsManager = nil
+	}
+	return nil
+}
+
+// Convert_certmanager_CertificateSecretSink_To_v1beta1_CertificateSecretSink is an autogenerated conversion function.
+func Convert_certmanager_CertificateSecretSink_To_v1beta1_CertificateSecretSink(in *certmanager.CertificateSecretSink, out *v1beta1.CertificateSecretSink, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateSecretSink_To_v1beta1_CertificateSecretSink(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1beta1.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
@@ -778,8 +1366,8 @@ func Convert_certmanager_CertificateSecretTemplate_To_v1beta1_CertificateSecretT
 func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1beta1.CertificateSpec, out *certmanager.CertificateSpec, s conversion.Scope) error {
 	out.Subject = (*certmanager.X509Subject)(unsafe.Pointer(in.Subject))
 	out.CommonName = in.CommonName
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
@@ -795,14 +1383,39 @@ func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1be
 	} else {
 		out.Keystores = nil
 	}
-	if err := apismetav1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.AdditionalOutputFormats = *(*[]certmanager.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
+	out.NormalizePEM = in.NormalizePEM
+	if in.SecretSinks != nil {
+		in, out := &in.SecretSinks, &out.SecretSinks
+		*out = make([]certmanager.CertificateSecretSink, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_CertificateSecretSink_To_certmanager_CertificateSecretSink(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SecretSinks = nil
+	}
+	if err := v1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.CAConstraints = (*certmanager.CertificateCAConstraints)(unsafe.Pointer(in.CAConstraints))
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
-	out.PrivateKey = (*certmanager.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
+	if in.PrivateKey != nil {
+		in, out := &in.PrivateKey, &out.PrivateKey
+		*out = new(certmanager.CertificatePrivateKey)
+		if err := Convert_v1beta1_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PrivateKey = nil
+	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.Revoke = in.Revoke
+	out.RevokeOnDelete = in.RevokeOnDelete
+	out.AllowPartialSANs = in.AllowPartialSANs
 	return nil
 }
 
@@ -814,8 +1427,8 @@ func Convert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1beta1.
 func autoConvert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *certmanager.CertificateSpec, out *v1beta1.CertificateSpec, s conversion.Scope) error {
 	out.Subject = (*v1beta1.X509Subject)(unsafe.Pointer(in.Subject))
 	out.CommonName = in.CommonName
-	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
-	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.Duration = (*apismetav1.Duration)(unsafe.Pointer(in.Duration))
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
@@ -831,14 +1444,39 @@ func autoConvert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *cert
 	} else {
 		out.Keystores = nil
 	}
-	if err := apismetav1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+	out.AdditionalOutputFormats = *(*[]v1beta1.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
+	out.NormalizePEM = in.NormalizePEM
+	if in.SecretSinks != nil {
+		in, out := &in.SecretSinks, &out.SecretSinks
+		*out = make([]v1beta1.CertificateSecretSink, len(*in))
+		for i := range *in {
+			if err := Convert_certmanager_CertificateSecretSink_To_v1beta1_CertificateSecretSink(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SecretSinks = nil
+	}
+	if err := v1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.CAConstraints = (*v1beta1.CertificateCAConstraints)(unsafe.Pointer(in.CAConstraints))
 	out.Usages = *(*[]v1beta1.KeyUsage)(unsafe.Pointer(&in.Usages))
-	out.PrivateKey = (*v1beta1.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
+	if in.PrivateKey != nil {
+		in, out := &in.PrivateKey, &out.PrivateKey
+		*out = new(v1beta1.CertificatePrivateKey)
+		if err := Convert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivateKey(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PrivateKey = nil
+	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.Revoke = in.Revoke
+	out.RevokeOnDelete = in.RevokeOnDelete
+	out.AllowPartialSANs = in.AllowPartialSANs
 	return nil
 }
 
@@ -849,12 +1487,15 @@ func Convert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *certmana
 
 func autoConvert_v1beta1_CertificateStatus_To_certmanager_CertificateStatus(in *v1beta1.CertificateStatus, out *certmanager.CertificateStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]certmanager.CertificateCondition)(unsafe.Pointer(&in.Conditions))
-	out.LastFailureTime = (*v1.Time)(unsafe.Pointer(in.LastFailureTime))
-	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
-	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
-	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
+	out.LastFailureTime = (*apismetav1.Time)(unsafe.Pointer(in.LastFailureTime))
+	out.NotBefore = (*apismetav1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*apismetav1.Time)(unsafe.Pointer(in.NotAfter))
+	out.RenewalTime = (*apismetav1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	out.RevokedSerialNumber = in.RevokedSerialNumber
+	out.DroppedSANs = *(*[]string)(unsafe.Pointer(&in.DroppedSANs))
+	out.RenewalRequest = (*certmanager.CertificateRenewalRequest)(unsafe.Pointer(in.RenewalRequest))
 	return nil
 }
 
@@ -865,12 +1506,15 @@ func Convert_v1beta1_CertificateStatus_To_certmanager_CertificateStatus(in *v1be
 
 func autoConvert_certmanager_CertificateStatus_To_v1beta1_CertificateStatus(in *certmanager.CertificateStatus, out *v1beta1.CertificateStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]v1beta1.CertificateCondition)(unsafe.Pointer(&in.Conditions))
-	out.LastFailureTime = (*v1.Time)(unsafe.Pointer(in.LastFailureTime))
-	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
-	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
-	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
+	out.LastFailureTime = (*apismetav1.Time)(unsafe.Pointer(in.LastFailureTime))
+	out.NotBefore = (*apismetav1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*apismetav1.Time)(unsafe.Pointer(in.NotAfter))
+	out.RenewalTime = (*apismetav1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	out.RevokedSerialNumber = in.RevokedSerialNumber
+	out.DroppedSANs = *(*[]string)(unsafe.Pointer(&in.DroppedSANs))
+	out.RenewalRequest = (*v1beta1.CertificateRenewalRequest)(unsafe.Pointer(in.RenewalRequest))
 	return nil
 }
 
@@ -988,7 +1632,7 @@ func Convert_certmanager_Issuer_To_v1beta1_Issuer(in *certmanager.Issuer, out *v
 func autoConvert_v1beta1_IssuerCondition_To_certmanager_IssuerCondition(in *v1beta1.IssuerCondition, out *certmanager.IssuerCondition, s conversion.Scope) error {
 	out.Type = certmanager.IssuerConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -1003,7 +1647,7 @@ func Convert_v1beta1_IssuerCondition_To_certmanager_IssuerCondition(in *v1beta1.
 func autoConvert_certmanager_IssuerCondition_To_v1beta1_IssuerCondition(in *certmanager.IssuerCondition, out *v1beta1.IssuerCondition, s conversion.Scope) error {
 	out.Type = v1beta1.IssuerConditionType(in.Type)
 	out.Status = metav1.ConditionStatus(in.Status)
-	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
 	out.Reason = in.Reason
 	out.Message = in.Message
 	out.ObservedGeneration = in.ObservedGeneration
@@ -1137,6 +1781,8 @@ func autoConvert_v1beta1_IssuerSpec_To_certmanager_IssuerSpec(in *v1beta1.Issuer
 	if err := Convert_v1beta1_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowWildcards = (*bool)(unsafe.Pointer(in.AllowWildcards))
+	out.SPIFFE = (*certmanager.SPIFFEPolicy)(unsafe.Pointer(in.SPIFFE))
 	return nil
 }
 
@@ -1149,6 +1795,8 @@ func autoConvert_certmanager_IssuerSpec_To_v1beta1_IssuerSpec(in *certmanager.Is
 	if err := Convert_certmanager_IssuerConfig_To_v1beta1_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowWildcards = (*bool)(unsafe.Pointer(in.AllowWildcards))
+	out.SPIFFE = (*v1beta1.SPIFFEPolicy)(unsafe.Pointer(in.SPIFFE))
 	return nil
 }
 
@@ -1160,6 +1808,7 @@ func Convert_certmanager_IssuerSpec_To_v1beta1_IssuerSpec(in *certmanager.Issuer
 func autoConvert_v1beta1_IssuerStatus_To_certmanager_IssuerStatus(in *v1beta1.IssuerStatus, out *certmanager.IssuerStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]certmanager.IssuerCondition)(unsafe.Pointer(&in.Conditions))
 	out.ACME = (*acme.ACMEIssuerStatus)(unsafe.Pointer(in.ACME))
+	out.CA = (*certmanager.CAIssuerStatus)(unsafe.Pointer(in.CA))
 	return nil
 }
 
@@ -1171,6 +1820,7 @@ func Convert_v1beta1_IssuerStatus_To_certmanager_IssuerStatus(in *v1beta1.Issuer
 func autoConvert_certmanager_IssuerStatus_To_v1beta1_IssuerStatus(in *certmanager.IssuerStatus, out *v1beta1.IssuerStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]v1beta1.IssuerCondition)(unsafe.Pointer(&in.Conditions))
 	out.ACME = (*apisacmev1beta1.ACMEIssuerStatus)(unsafe.Pointer(in.ACME))
+	out.CA = (*v1beta1.CAIssuerStatus)(unsafe.Pointer(in.CA))
 	return nil
 }
 
@@ -1181,9 +1831,18 @@ func Convert_certmanager_IssuerStatus_To_v1beta1_IssuerStatus(in *certmanager.Is
 
 func autoConvert_v1beta1_JKSKeystore_To_certmanager_JKSKeystore(in *v1beta1.JKSKeystore, out *certmanager.JKSKeystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
+	if in.Truststore != nil {
+		in, out := &in.Truststore, &out.Truststore
+		*out = new(certmanager.JKSTruststore)
+		if err := Convert_v1beta1_JKSTruststore_To_certmanager_JKSTruststore(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Truststore = nil
+	}
 	return nil
 }
 
@@ -1194,9 +1853,18 @@ func Convert_v1beta1_JKSKeystore_To_certmanager_JKSKeystore(in *v1beta1.JKSKeyst
 
 func autoConvert_certmanager_JKSKeystore_To_v1beta1_JKSKeystore(in *certmanager.JKSKeystore, out *v1beta1.JKSKeystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
+	if in.Truststore != nil {
+		in, out := &in.Truststore, &out.Truststore
+		*out = new(v1beta1.JKSTruststore)
+		if err := Convert_certmanager_JKSTruststore_To_v1beta1_JKSTruststore(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Truststore = nil
+	}
 	return nil
 }
 
@@ -1205,9 +1873,83 @@ func Convert_certmanager_JKSKeystore_To_v1beta1_JKSKeystore(in *certmanager.JKSK
 	return autoConvert_certmanager_JKSKeystore_To_v1beta1_JKSKeystore(in, out, s)
 }
 
+func autoConvert_v1beta1_JKSTruststore_To_certmanager_JKSTruststore(in *v1beta1.JKSTruststore, out *certmanager.JKSTruststore, s conversion.Scope) error {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_JKSTruststore_To_certmanager_JKSTruststore is an autogenerated conversion function.
+func Convert_v1beta1_JKSTruststore_To_certmanager_JKSTruststore(in *v1beta1.JKSTruststore, out *certmanager.JKSTruststore, s conversion.Scope) error {
+	return autoConvert_v1beta1_JKSTruststore_To_certmanager_JKSTruststore(in, out, s)
+}
+
+func autoConvert_certmanager_JKSTruststore_To_v1beta1_JKSTruststore(in *certmanager.JKSTruststore, out *v1beta1.JKSTruststore, s conversion.Scope) error {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_JKSTruststore_To_v1beta1_JKSTruststore is an autogenerated conversion function.
+func Convert_certmanager_JKSTruststore_To_v1beta1_JKSTruststore(in *certmanager.JKSTruststore, out *v1beta1.JKSTruststore, s conversion.Scope) error {
+	return autoConvert_certmanager_JKSTruststore_To_v1beta1_JKSTruststore(in, out, s)
+}
+
+func autoConvert_v1beta1_NameConstraintItem_To_certmanager_NameConstraintItem(in *v1beta1.NameConstraintItem, out *certmanager.NameConstraintItem, s conversion.Scope) error {
+	out.DNSDomains = *(*[]string)(unsafe.Pointer(&in.DNSDomains))
+	out.IPRanges = *(*[]string)(unsafe.Pointer(&in.IPRanges))
+	out.EmailAddresses = *(*[]string)(unsafe.Pointer(&in.EmailAddresses))
+	out.URIDomains = *(*[]string)(unsafe.Pointer(&in.URIDomains))
+	return nil
+}
+
+// Convert_v1beta1_NameConstraintItem_To_certmanager_NameConstraintItem is an autogenerated conversion function.
+func Convert_v1beta1_NameConstraintItem_To_certmanager_NameConstraintItem(in *v1beta1.NameConstraintItem, out *certmanager.NameConstraintItem, s conversion.Scope) error {
+	return autoConvert_v1beta1_NameConstraintItem_To_certmanager_NameConstraintItem(in, out, s)
+}
+
+func autoConvert_certmanager_NameConstraintItem_To_v1beta1_NameConstraintItem(in *certmanager.NameConstraintItem, out *v1beta1.NameConstraintItem, s conversion.Scope) error {
+	out.DNSDomains = *(*[]string)(unsafe.Pointer(&in.DNSDomains))
+	out.IPRanges = *(*[]string)(unsafe.Pointer(&in.IPRanges))
+	out.EmailAddresses = *(*[]string)(unsafe.Pointer(&in.EmailAddresses))
+	out.URIDomains = *(*[]string)(unsafe.Pointer(&in.URIDomains))
+	return nil
+}
+
+// Convert_certmanager_NameConstraintItem_To_v1beta1_NameConstraintItem is an autogenerated conversion function.
+func Convert_certmanager_NameConstraintItem_To_v1beta1_NameConstraintItem(in *certmanager.NameConstraintItem, out *v1beta1.NameConstraintItem, s conversion.Scope) error {
+	return autoConvert_certmanager_NameConstraintItem_To_v1beta1_NameConstraintItem(in, out, s)
+}
+
+func autoConvert_v1beta1_NameConstraints_To_certmanager_NameConstraints(in *v1beta1.NameConstraints, out *certmanager.NameConstraints, s conversion.Scope) error {
+	out.Critical = in.Critical
+	out.Permitted = (*certmanager.NameConstraintItem)(unsafe.Pointer(in.Permitted))
+	out.Excluded = (*certmanager.NameConstraintItem)(unsafe.Pointer(in.Excluded))
+	return nil
+}
+
+// Convert_v1beta1_NameConstraints_To_certmanager_NameConstraints is an autogenerated conversion function.
+func Convert_v1beta1_NameConstraints_To_certmanager_NameConstraints(in *v1beta1.NameConstraints, out *certmanager.NameConstraints, s conversion.Scope) error {
+	return autoConvert_v1beta1_NameConstraints_To_certmanager_NameConstraints(in, out, s)
+}
+
+func autoConvert_certmanager_NameConstraints_To_v1beta1_NameConstraints(in *certmanager.NameConstraints, out *v1beta1.NameConstraints, s conversion.Scope) error {
+	out.Critical = in.Critical
+	out.Permitted = (*v1beta1.NameConstraintItem)(unsafe.Pointer(in.Permitted))
+	out.Excluded = (*v1beta1.NameConstraintItem)(unsafe.Pointer(in.Excluded))
+	return nil
+}
+
+// Convert_certmanager_NameConstraints_To_v1beta1_NameConstraints is an autogenerated conversion function.
+func Convert_certmanager_NameConstraints_To_v1beta1_NameConstraints(in *certmanager.NameConstraints, out *v1beta1.NameConstraints, s conversion.Scope) error {
+	return autoConvert_certmanager_NameConstraints_To_v1beta1_NameConstraints(in, out, s)
+}
+
 func autoConvert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1beta1.PKCS12Keystore, out *certmanager.PKCS12Keystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
 	return nil
@@ -1220,7 +1962,7 @@ func Convert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1beta1.PK
 
 func autoConvert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in *certmanager.PKCS12Keystore, out *v1beta1.PKCS12Keystore, s conversion.Scope) error {
 	out.Create = in.Create
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.PasswordSecretRef, &out.PasswordSecretRef, s); err != nil {
 		return err
 	}
 	return nil
@@ -1231,6 +1973,68 @@ func Convert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in *certmanage
 	return autoConvert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in, out, s)
 }
 
+func autoConvert_v1beta1_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(in *v1beta1.PrivateKeyExternalRef, out *certmanager.PrivateKeyExternalRef, s conversion.Scope) error {
+	out.SignerURL = in.SignerURL
+	out.KeyID = in.KeyID
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TokenSecretRef = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef is an autogenerated conversion function.
+func Convert_v1beta1_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(in *v1beta1.PrivateKeyExternalRef, out *certmanager.PrivateKeyExternalRef, s conversion.Scope) error {
+	return autoConvert_v1beta1_PrivateKeyExternalRef_To_certmanager_PrivateKeyExternalRef(in, out, s)
+}
+
+func autoConvert_certmanager_PrivateKeyExternalRef_To_v1beta1_PrivateKeyExternalRef(in *certmanager.PrivateKeyExternalRef, out *v1beta1.PrivateKeyExternalRef, s conversion.Scope) error {
+	out.SignerURL = in.SignerURL
+	out.KeyID = in.KeyID
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TokenSecretRef = nil
+	}
+	return nil
+}
+
+// Convert_certmanager_PrivateKeyExternalRef_To_v1beta1_PrivateKeyExternalRef is an autogenerated conversion function.
+func Convert_certmanager_PrivateKeyExternalRef_To_v1beta1_PrivateKeyExternalRef(in *certmanager.PrivateKeyExternalRef, out *v1beta1.PrivateKeyExternalRef, s conversion.Scope) error {
+	return autoConvert_certmanager_PrivateKeyExternalRef_To_v1beta1_PrivateKeyExternalRef(in, out, s)
+}
+
+func autoConvert_v1beta1_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(in *v1beta1.SPIFFEPolicy, out *certmanager.SPIFFEPolicy, s conversion.Scope) error {
+	out.TrustDomains = *(*[]string)(unsafe.Pointer(&in.TrustDomains))
+	return nil
+}
+
+// Convert_v1beta1_SPIFFEPolicy_To_certmanager_SPIFFEPolicy is an autogenerated conversion function.
+func Convert_v1beta1_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(in *v1beta1.SPIFFEPolicy, out *certmanager.SPIFFEPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_SPIFFEPolicy_To_certmanager_SPIFFEPolicy(in, out, s)
+}
+
+func autoConvert_certmanager_SPIFFEPolicy_To_v1beta1_SPIFFEPolicy(in *certmanager.SPIFFEPolicy, out *v1beta1.SPIFFEPolicy, s conversion.Scope) error {
+	out.TrustDomains = *(*[]string)(unsafe.Pointer(&in.TrustDomains))
+	return nil
+}
+
+// Convert_certmanager_SPIFFEPolicy_To_v1beta1_SPIFFEPolicy is an autogenerated conversion function.
+func Convert_certmanager_SPIFFEPolicy_To_v1beta1_SPIFFEPolicy(in *certmanager.SPIFFEPolicy, out *v1beta1.SPIFFEPolicy, s conversion.Scope) error {
+	return autoConvert_certmanager_SPIFFEPolicy_To_v1beta1_SPIFFEPolicy(in, out, s)
+}
+
 func autoConvert_v1beta1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1beta1.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	return nil
@@ -1251,12 +2055,35 @@ func Convert_certmanager_SelfSignedIssuer_To_v1beta1_SelfSignedIssuer(in *certma
 	return autoConvert_certmanager_SelfSignedIssuer_To_v1beta1_SelfSignedIssuer(in, out, s)
 }
 
+func autoConvert_v1beta1_ServiceAccountRef_To_certmanager_ServiceAccountRef(in *v1beta1.ServiceAccountRef, out *certmanager.ServiceAccountRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Audiences = *(*[]string)(unsafe.Pointer(&in.Audiences))
+	return nil
+}
+
+// Convert_v1beta1_ServiceAccountRef_To_certmanager_ServiceAccountRef is an autogenerated conversion function.
+func Convert_v1beta1_ServiceAccountRef_To_certmanager_ServiceAccountRef(in *v1beta1.ServiceAccountRef, out *certmanager.ServiceAccountRef, s conversion.Scope) error {
+	return autoConvert_v1beta1_ServiceAccountRef_To_certmanager_ServiceAccountRef(in, out, s)
+}
+
+func autoConvert_certmanager_ServiceAccountRef_To_v1beta1_ServiceAccountRef(in *certmanager.ServiceAccountRef, out *v1beta1.ServiceAccountRef, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Audiences = *(*[]string)(unsafe.Pointer(&in.Audiences))
+	return nil
+}
+
+// Convert_certmanager_ServiceAccountRef_To_v1beta1_ServiceAccountRef is an autogenerated conversion function.
+func Convert_certmanager_ServiceAccountRef_To_v1beta1_ServiceAccountRef(in *certmanager.ServiceAccountRef, out *v1beta1.ServiceAccountRef, s conversion.Scope) error {
+	return autoConvert_certmanager_ServiceAccountRef_To_v1beta1_ServiceAccountRef(in, out, s)
+}
+
 func autoConvert_v1beta1_VaultAppRole_To_certmanager_VaultAppRole(in *v1beta1.VaultAppRole, out *certmanager.VaultAppRole, s conversion.Scope) error {
 	out.Path = in.Path
 	out.RoleId = in.RoleId
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.SecretIDRotation = (*certmanager.VaultAppRoleSecretIDRotation)(unsafe.Pointer(in.SecretIDRotation))
 	return nil
 }
 
@@ -1268,9 +2095,10 @@ func Convert_v1beta1_VaultAppRole_To_certmanager_VaultAppRole(in *v1beta1.VaultA
 func autoConvert_certmanager_VaultAppRole_To_v1beta1_VaultAppRole(in *certmanager.VaultAppRole, out *v1beta1.VaultAppRole, s conversion.Scope) error {
 	out.Path = in.Path
 	out.RoleId = in.RoleId
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.SecretIDRotation = (*v1beta1.VaultAppRoleSecretIDRotation)(unsafe.Pointer(in.SecretIDRotation))
 	return nil
 }
 
@@ -1279,11 +2107,33 @@ func Convert_certmanager_VaultAppRole_To_v1beta1_VaultAppRole(in *certmanager.Va
 	return autoConvert_certmanager_VaultAppRole_To_v1beta1_VaultAppRole(in, out, s)
 }
 
+func autoConvert_v1beta1_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(in *v1beta1.VaultAppRoleSecretIDRotation, out *certmanager.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	out.RoleName = in.RoleName
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
+	return nil
+}
+
+// Convert_v1beta1_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation is an autogenerated conversion function.
+func Convert_v1beta1_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(in *v1beta1.VaultAppRoleSecretIDRotation, out *certmanager.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	return autoConvert_v1beta1_VaultAppRoleSecretIDRotation_To_certmanager_VaultAppRoleSecretIDRotation(in, out, s)
+}
+
+func autoConvert_certmanager_VaultAppRoleSecretIDRotation_To_v1beta1_VaultAppRoleSecretIDRotation(in *certmanager.VaultAppRoleSecretIDRotation, out *v1beta1.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	out.RoleName = in.RoleName
+	out.RenewBefore = (*apismetav1.Duration)(unsafe.Pointer(in.RenewBefore))
+	return nil
+}
+
+// Convert_certmanager_VaultAppRoleSecretIDRotation_To_v1beta1_VaultAppRoleSecretIDRotation is an autogenerated conversion function.
+func Convert_certmanager_VaultAppRoleSecretIDRotation_To_v1beta1_VaultAppRoleSecretIDRotation(in *certmanager.VaultAppRoleSecretIDRotation, out *v1beta1.VaultAppRoleSecretIDRotation, s conversion.Scope) error {
+	return autoConvert_certmanager_VaultAppRoleSecretIDRotation_To_v1beta1_VaultAppRoleSecretIDRotation(in, out, s)
+}
+
 func autoConvert_v1beta1_VaultAuth_To_certmanager_VaultAuth(in *v1beta1.VaultAuth, out *certmanager.VaultAuth, s conversion.Scope) error {
 	if in.TokenSecretRef != nil {
 		in, out := &in.TokenSecretRef, &out.TokenSecretRef
 		*out = new(meta.SecretKeySelector)
-		if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
@@ -1319,7 +2169,7 @@ func autoConvert_certmanager_VaultAuth_To_v1beta1_VaultAuth(in *certmanager.Vaul
 	if in.TokenSecretRef != nil {
 		in, out := &in.TokenSecretRef, &out.TokenSecretRef
 		*out = new(metav1.SecretKeySelector)
-		if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
@@ -1356,8 +2206,13 @@ func autoConvert_v1beta1_VaultIssuer_To_certmanager_VaultIssuer(in *v1beta1.Vaul
 		return err
 	}
 	out.Server = in.Server
+	out.AdditionalServers = *(*[]string)(unsafe.Pointer(&in.AdditionalServers))
+	out.ReadYourWrites = in.ReadYourWrites
 	out.Path = in.Path
 	out.Namespace = in.Namespace
+	out.AllowedNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedNamespaces))
+	out.SignVerbatim = in.SignVerbatim
+	out.SignVerbatimPath = in.SignVerbatimPath
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
 	return nil
 }
@@ -1372,8 +2227,13 @@ func autoConvert_certmanager_VaultIssuer_To_v1beta1_VaultIssuer(in *certmanager.
 		return err
 	}
 	out.Server = in.Server
+	out.AdditionalServers = *(*[]string)(unsafe.Pointer(&in.AdditionalServers))
+	out.ReadYourWrites = in.ReadYourWrites
 	out.Path = in.Path
 	out.Namespace = in.Namespace
+	out.AllowedNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedNamespaces))
+	out.SignVerbatim = in.SignVerbatim
+	out.SignVerbatimPath = in.SignVerbatimPath
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
 	return nil
 }
@@ -1385,9 +2245,10 @@ func Convert_certmanager_VaultIssuer_To_v1beta1_VaultIssuer(in *certmanager.Vaul
 
 func autoConvert_v1beta1_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth(in *v1beta1.VaultKubernetesAuth, out *certmanager.VaultKubernetesAuth, s conversion.Scope) error {
 	out.Path = in.Path
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.ServiceAccountRef = (*certmanager.ServiceAccountRef)(unsafe.Pointer(in.ServiceAccountRef))
 	out.Role = in.Role
 	return nil
 }
@@ -1399,9 +2260,10 @@ func Convert_v1beta1_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth(in *
 
 func autoConvert_certmanager_VaultKubernetesAuth_To_v1beta1_VaultKubernetesAuth(in *certmanager.VaultKubernetesAuth, out *v1beta1.VaultKubernetesAuth, s conversion.Scope) error {
 	out.Path = in.Path
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.SecretRef, &out.SecretRef, s); err != nil {
 		return err
 	}
+	out.ServiceAccountRef = (*v1beta1.ServiceAccountRef)(unsafe.Pointer(in.ServiceAccountRef))
 	out.Role = in.Role
 	return nil
 }
@@ -1411,11 +2273,46 @@ func Convert_certmanager_VaultKubernetesAuth_To_v1beta1_VaultKubernetesAuth(in *
 	return autoConvert_certmanager_VaultKubernetesAuth_To_v1beta1_VaultKubernetesAuth(in, out, s)
 }
 
+func autoConvert_v1beta1_VaultSecretSink_To_certmanager_VaultSecretSink(in *v1beta1.VaultSecretSink, out *certmanager.VaultSecretSink, s conversion.Scope) error {
+	if err := v1.Convert_v1_ObjectReference_To_meta_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+		return err
+	}
+	out.Path = in.Path
+	return nil
+}
+
+// Convert_v1beta1_VaultSecretSink_To_certmanager_VaultSecretSink is an autogenerated conversion function.
+func Convert_v1beta1_VaultSecretSink_To_certmanager_VaultSecretSink(in *v1beta1.VaultSecretSink, out *certmanager.VaultSecretSink, s conversion.Scope) error {
+	return autoConvert_v1beta1_VaultSecretSink_To_certmanager_VaultSecretSink(in, out, s)
+}
+
+func autoConvert_certmanager_VaultSecretSink_To_v1beta1_VaultSecretSink(in *certmanager.VaultSecretSink, out *v1beta1.VaultSecretSink, s conversion.Scope) error {
+	if err := v1.Convert_meta_ObjectReference_To_v1_ObjectReference(&in.IssuerRef, &out.IssuerRef, s); err != nil {
+		return err
+	}
+	out.Path = in.Path
+	return nil
+}
+
+// Convert_certmanager_VaultSecretSink_To_v1beta1_VaultSecretSink is an autogenerated conversion function.
+func Convert_certmanager_VaultSecretSink_To_v1beta1_VaultSecretSink(in *certmanager.VaultSecretSink, out *v1beta1.VaultSecretSink, s conversion.Scope) error {
+	return autoConvert_certmanager_VaultSecretSink_To_v1beta1_VaultSecretSink(in, out, s)
+}
+
 func autoConvert_v1beta1_VenafiCloud_To_certmanager_VenafiCloud(in *v1beta1.VenafiCloud, out *certmanager.VenafiCloud, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
+	if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
 		return err
 	}
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(certmanager.VenafiCloudOAuth)
+		if err := Convert_v1beta1_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OAuth = nil
+	}
 	return nil
 }
 
@@ -1426,9 +2323,18 @@ func Convert_v1beta1_VenafiCloud_To_certmanager_VenafiCloud(in *v1beta1.VenafiCl
 
 func autoConvert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in *certmanager.VenafiCloud, out *v1beta1.VenafiCloud, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
+	if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(&in.APITokenSecretRef, &out.APITokenSecretRef, s); err != nil {
 		return err
 	}
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(v1beta1.VenafiCloudOAuth)
+		if err := Convert_certmanager_VenafiCloudOAuth_To_v1beta1_VenafiCloudOAuth(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OAuth = nil
+	}
 	return nil
 }
 
@@ -1437,6 +2343,66 @@ func Convert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in *certmanager.Vena
 	return autoConvert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1beta1_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(in *v1beta1.VenafiCloudOAuth, out *certmanager.VenafiCloudOAuth, s conversion.Scope) error {
+	out.TokenURL = in.TokenURL
+	out.ClientID = in.ClientID
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClientSecretRef = nil
+	}
+	if in.FederatedTokenSecretRef != nil {
+		in, out := &in.FederatedTokenSecretRef, &out.FederatedTokenSecretRef
+		*out = new(meta.SecretKeySelector)
+		if err := v1.Convert_v1_SecretKeySelector_To_meta_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.FederatedTokenSecretRef = nil
+	}
+	out.Scopes = *(*[]string)(unsafe.Pointer(&in.Scopes))
+	return nil
+}
+
+// Convert_v1beta1_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth is an autogenerated conversion function.
+func Convert_v1beta1_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(in *v1beta1.VenafiCloudOAuth, out *certmanager.VenafiCloudOAuth, s conversion.Scope) error {
+	return autoConvert_v1beta1_VenafiCloudOAuth_To_certmanager_VenafiCloudOAuth(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCloudOAuth_To_v1beta1_VenafiCloudOAuth(in *certmanager.VenafiCloudOAuth, out *v1beta1.VenafiCloudOAuth, s conversion.Scope) error {
+	out.TokenURL = in.TokenURL
+	out.ClientID = in.ClientID
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClientSecretRef = nil
+	}
+	if in.FederatedTokenSecretRef != nil {
+		in, out := &in.FederatedTokenSecretRef, &out.FederatedTokenSecretRef
+		*out = new(metav1.SecretKeySelector)
+		if err := v1.Convert_meta_SecretKeySelector_To_v1_SecretKeySelector(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.FederatedTokenSecretRef = nil
+	}
+	out.Scopes = *(*[]string)(unsafe.Pointer(&in.Scopes))
+	return nil
+}
+
+// Convert_certmanager_VenafiCloudOAuth_To_v1beta1_VenafiCloudOAuth is an autogenerated conversion function.
+func Convert_certmanager_VenafiCloudOAuth_To_v1beta1_VenafiCloudOAuth(in *certmanager.VenafiCloudOAuth, out *v1beta1.VenafiCloudOAuth, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCloudOAuth_To_v1beta1_VenafiCloudOAuth(in, out, s)
+}
+
 func autoConvert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1beta1.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	if in.TPP != nil {
@@ -1457,6 +2423,7 @@ func autoConvert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1beta1.Ve
 	} else {
 		out.Cloud = nil
 	}
+	out.CustomFields = *(*map[string]string)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -1485,6 +2452,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1beta1_VenafiIssuer(in *certmanage
 	} else {
 		out.Cloud = nil
 	}
+	out.CustomFields = *(*map[string]string)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -1495,7 +2463,7 @@ func Convert_certmanager_VenafiIssuer_To_v1beta1_VenafiIssuer(in *certmanager.Ve
 
 func autoConvert_v1beta1_VenafiTPP_To_certmanager_VenafiTPP(in *v1beta1.VenafiTPP, out *certmanager.VenafiTPP, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_v1_LocalObjectReference_To_meta_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
+	if err := v1.Convert_v1_LocalObjectReference_To_meta_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
 		return err
 	}
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
@@ -1509,7 +2477,7 @@ func Convert_v1beta1_VenafiTPP_To_certmanager_VenafiTPP(in *v1beta1.VenafiTPP, o
 
 func autoConvert_certmanager_VenafiTPP_To_v1beta1_VenafiTPP(in *certmanager.VenafiTPP, out *v1beta1.VenafiTPP, s conversion.Scope) error {
 	out.URL = in.URL
-	if err := apismetav1.Convert_meta_LocalObjectReference_To_v1_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
+	if err := v1.Convert_meta_LocalObjectReference_To_v1_LocalObjectReference(&in.CredentialsRef, &out.CredentialsRef, s); err != nil {
 		return err
 	}
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
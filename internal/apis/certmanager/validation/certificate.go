@@ -17,15 +17,21 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/mail"
+	"net/url"
+	"sort"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/internal/api/validation"
@@ -33,6 +39,8 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
 	"github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	venafiapi "github.com/jetstack/cert-manager/pkg/issuer/venafi/client/api"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 // Validation functions for cert-manager Certificate types
@@ -77,6 +85,9 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 		default:
 			el = append(el, field.Invalid(fldPath.Child("privateKey", "algorithm"), crt.PrivateKey.Algorithm, "must be either empty or one of rsa or ecdsa"))
 		}
+		if crt.PrivateKey.ExternalRef != nil {
+			el = append(el, validatePrivateKeyExternalRef(crt, fldPath)...)
+		}
 	}
 
 	if crt.Duration != nil || crt.RenewBefore != nil {
@@ -85,6 +96,9 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 	if len(crt.Usages) > 0 {
 		el = append(el, validateUsages(crt, fldPath)...)
 	}
+	if crt.CAConstraints != nil {
+		el = append(el, validateCAConstraints(crt, fldPath)...)
+	}
 	if crt.RevisionHistoryLimit != nil && *crt.RevisionHistoryLimit < 1 {
 		el = append(el, field.Invalid(fldPath.Child("revisionHistoryLimit"), *crt.RevisionHistoryLimit, "must not be less than 1"))
 	}
@@ -98,12 +112,31 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 		}
 	}
 
+	if crt.Keystores != nil {
+		el = append(el, validateKeystores(crt, fldPath)...)
+	}
+
+	if len(crt.AdditionalOutputFormats) > 0 {
+		el = append(el, validateAdditionalOutputFormats(crt, fldPath)...)
+	}
+
+	if len(crt.SecretSinks) > 0 {
+		el = append(el, validateSecretSinks(crt, fldPath)...)
+	}
+
+	// Normalized PEM data always contains a PKCS#8 private key, which
+	// conflicts with explicitly requesting PKCS#1.
+	if crt.NormalizePEM && crt.PrivateKey != nil && crt.PrivateKey.Encoding == internalcmapi.PKCS1 {
+		el = append(el, field.Invalid(fldPath.Child("privateKey", "encoding"), crt.PrivateKey.Encoding, "must be PKCS8 or unset when normalizePEM is true"))
+	}
+
 	return el
 }
 
 func ValidateCertificate(a *admissionv1.AdmissionRequest, obj runtime.Object) (field.ErrorList, validation.WarningList) {
 	crt := obj.(*internalcmapi.Certificate)
 	allErrs := ValidateCertificateSpec(&crt.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, ValidateVenafiCustomFieldsAnnotation(crt.Annotations, field.NewPath("metadata", "annotations"))...)
 	w := validateAPIVersion(a.RequestKind)
 	return allErrs, w
 }
@@ -111,10 +144,48 @@ func ValidateCertificate(a *admissionv1.AdmissionRequest, obj runtime.Object) (f
 func ValidateUpdateCertificate(a *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) (field.ErrorList, validation.WarningList) {
 	crt := obj.(*internalcmapi.Certificate)
 	allErrs := ValidateCertificateSpec(&crt.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, ValidateVenafiCustomFieldsAnnotation(crt.Annotations, field.NewPath("metadata", "annotations"))...)
 	w := validateAPIVersion(a.RequestKind)
 	return allErrs, w
 }
 
+// ValidateVenafiCustomFieldsAnnotation validates the custom fields passed to
+// Venafi issuers through the "venafi.cert-manager.io/custom-fields"
+// annotation, so that malformed custom fields are rejected before a request
+// is made.
+func ValidateVenafiCustomFieldsAnnotation(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	value, ok := annotations[internalcmapi.VenafiCustomFieldsAnnotationKey]
+	if !ok || value == "" {
+		return el
+	}
+
+	path := fldPath.Key(internalcmapi.VenafiCustomFieldsAnnotationKey)
+
+	var customFields []venafiapi.CustomField
+	if err := json.Unmarshal([]byte(value), &customFields); err != nil {
+		return append(el, field.Invalid(path, value, fmt.Sprintf("must be a JSON encoded list of custom fields: %s", err)))
+	}
+
+	names := make(map[string]bool, len(customFields))
+	for i, customField := range customFields {
+		switch {
+		case customField.Name == "":
+			el = append(el, field.Invalid(path, value, fmt.Sprintf("custom field %d: name must not be empty", i)))
+		case names[customField.Name]:
+			el = append(el, field.Invalid(path, value, fmt.Sprintf("custom field %d: duplicate name %q", i, customField.Name)))
+		}
+		names[customField.Name] = true
+
+		if customField.Type != "" && customField.Type != venafiapi.CustomFieldTypePlain {
+			el = append(el, field.NotSupported(path, customField.Type, []string{string(venafiapi.CustomFieldTypePlain)}))
+		}
+	}
+
+	return el
+}
+
 func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
@@ -122,7 +193,8 @@ func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) fi
 	if issuerRef.Name == "" {
 		el = append(el, field.Required(issuerRefPath.Child("name"), "must be specified"))
 	}
-	if issuerRef.Group == "" || issuerRef.Group == internalcmapi.SchemeGroupVersion.Group {
+	isCertManagerIssuer := issuerRef.Group == "" || issuerRef.Group == internalcmapi.SchemeGroupVersion.Group
+	if isCertManagerIssuer {
 		switch issuerRef.Kind {
 		case "":
 		case "Issuer", "ClusterIssuer":
@@ -130,6 +202,68 @@ func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) fi
 			el = append(el, field.Invalid(issuerRefPath.Child("kind"), issuerRef.Kind, "must be one of Issuer or ClusterIssuer"))
 		}
 	}
+	if issuerRef.Namespace != "" {
+		switch {
+		case !isCertManagerIssuer || (issuerRef.Kind != "" && issuerRef.Kind != "Issuer"):
+			el = append(el, field.Forbidden(issuerRefPath.Child("namespace"), "may only be set when referencing an Issuer"))
+		default:
+			for _, msg := range utilvalidation.IsDNS1123Label(issuerRef.Namespace) {
+				el = append(el, field.Invalid(issuerRefPath.Child("namespace"), issuerRef.Namespace, msg))
+			}
+		}
+	}
+	return el
+}
+
+func validateCAConstraints(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	fldPath = fldPath.Child("caConstraints")
+	constraints := crt.CAConstraints
+
+	if !crt.IsCA {
+		el = append(el, field.Forbidden(fldPath, "may only be set when isCA is true"))
+	}
+	if constraints.MaxPathLen != nil && *constraints.MaxPathLen < 0 {
+		el = append(el, field.Invalid(fldPath.Child("maxPathLen"), *constraints.MaxPathLen, "must not be negative"))
+	}
+
+	if nc := constraints.NameConstraints; nc != nil {
+		ncPath := fldPath.Child("nameConstraints")
+		if nc.Permitted == nil && nc.Excluded == nil {
+			el = append(el, field.Required(ncPath, "at least one of permitted or excluded must be set"))
+		}
+		el = append(el, validateNameConstraintItem(nc.Permitted, ncPath.Child("permitted"))...)
+		el = append(el, validateNameConstraintItem(nc.Excluded, ncPath.Child("excluded"))...)
+	}
+
+	el = append(el, validateURLs(constraints.CRLDistributionPoints, fldPath.Child("crlDistributionPoints"))...)
+	el = append(el, validateURLs(constraints.IssuingCertificateURLs, fldPath.Child("issuingCertificateURLs"))...)
+	el = append(el, validateURLs(constraints.OCSPServers, fldPath.Child("ocspServers"))...)
+
+	return el
+}
+
+func validateNameConstraintItem(item *internalcmapi.NameConstraintItem, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if item == nil {
+		return el
+	}
+	for i, cidr := range item.IPRanges {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("ipRanges").Index(i), cidr, "must be a valid IP range in CIDR notation"))
+		}
+	}
+	return el
+}
+
+func validateURLs(urls []string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	for i, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			el = append(el, field.Invalid(fldPath.Index(i), u, "must be a valid absolute URL"))
+		}
+	}
 	return el
 }
 
@@ -195,6 +329,224 @@ func validateSecretTemplateAnnotations(crt *internalcmapi.CertificateSpec, fldPa
 	return el
 }
 
+// validatePrivateKeyExternalRef validates a reference to a private key held
+// by an external signer. As cert-manager neither generates nor stores such
+// keys, the other private key options and keystores cannot be used with it.
+func validatePrivateKeyExternalRef(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	ref := crt.PrivateKey.ExternalRef
+	pkPath := fldPath.Child("privateKey")
+	refPath := pkPath.Child("externalRef")
+
+	if len(ref.SignerURL) == 0 {
+		el = append(el, field.Required(refPath.Child("signerURL"), "must be specified"))
+	} else if u, err := url.Parse(ref.SignerURL); err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+		el = append(el, field.Invalid(refPath.Child("signerURL"), ref.SignerURL, "must be a valid https URL"))
+	}
+	if len(ref.KeyID) == 0 {
+		el = append(el, field.Required(refPath.Child("keyID"), "must be specified"))
+	}
+	if len(ref.CABundle) > 0 {
+		if _, err := pki.DecodeX509CertificateChainBytes(ref.CABundle); err != nil {
+			el = append(el, field.Invalid(refPath.Child("caBundle"), "", fmt.Sprintf("must contain PEM encoded certificates: %v", err)))
+		}
+	}
+	if ref.TokenSecretRef != nil {
+		if len(ref.TokenSecretRef.Name) == 0 {
+			el = append(el, field.Required(refPath.Child("tokenSecretRef", "name"), "must be specified"))
+		}
+		if len(ref.TokenSecretRef.Key) == 0 {
+			el = append(el, field.Required(refPath.Child("tokenSecretRef", "key"), "must be specified"))
+		}
+	}
+
+	const notSupported = "not supported when privateKey.externalRef is set"
+	if len(crt.PrivateKey.RotationPolicy) > 0 {
+		el = append(el, field.Forbidden(pkPath.Child("rotationPolicy"), notSupported))
+	}
+	if len(crt.PrivateKey.Encoding) > 0 {
+		el = append(el, field.Forbidden(pkPath.Child("encoding"), notSupported))
+	}
+	if len(crt.PrivateKey.Algorithm) > 0 {
+		el = append(el, field.Forbidden(pkPath.Child("algorithm"), notSupported))
+	}
+	if crt.PrivateKey.Size > 0 {
+		el = append(el, field.Forbidden(pkPath.Child("size"), notSupported))
+	}
+	if crt.Keystores != nil {
+		if jks := crt.Keystores.JKS; jks != nil && jks.Create {
+			el = append(el, field.Forbidden(fldPath.Child("keystores", "jks"), notSupported))
+		}
+		if pkcs12 := crt.Keystores.PKCS12; pkcs12 != nil && pkcs12.Create {
+			el = append(el, field.Forbidden(fldPath.Child("keystores", "pkcs12"), notSupported))
+		}
+	}
+	if len(crt.AdditionalOutputFormats) > 0 {
+		el = append(el, field.Forbidden(fldPath.Child("additionalOutputFormats"), notSupported))
+	}
+
+	return el
+}
+
+// outputFormatDefaultKeys contains the Secret data key each additional output
+// format type is written to if no key is specified.
+var outputFormatDefaultKeys = map[internalcmapi.CertificateOutputFormatType]string{
+	internalcmapi.CertificateOutputFormatDER:         internalcmapi.CertificateOutputFormatDERKey,
+	internalcmapi.CertificateOutputFormatCombinedPEM: internalcmapi.CertificateOutputFormatCombinedPEMKey,
+}
+
+// reservedSecretKeys are the Secret data keys that cert-manager writes the
+// certificate, private key and keystores to.
+var reservedSecretKeys = sets.NewString(
+	corev1.TLSCertKey, corev1.TLSPrivateKeyKey, cmmeta.TLSCAKey,
+	"keystore.jks", "truststore.jks", "keystore.p12", "truststore.p12",
+)
+
+// validateAdditionalOutputFormats validates the additional output formats
+// written to the Certificate's Secret. Each format must be written to a valid
+// Secret data key that is not used by any other entry managed by cert-manager.
+func validateAdditionalOutputFormats(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	supportedTypes := make([]string, 0, len(outputFormatDefaultKeys))
+	for formatType := range outputFormatDefaultKeys {
+		supportedTypes = append(supportedTypes, string(formatType))
+	}
+	sort.Strings(supportedTypes)
+
+	keys := sets.NewString()
+	for i, format := range crt.AdditionalOutputFormats {
+		formatPath := fldPath.Child("additionalOutputFormats").Index(i)
+		defaultKey, ok := outputFormatDefaultKeys[format.Type]
+		if !ok {
+			el = append(el, field.NotSupported(formatPath.Child("type"), format.Type, supportedTypes))
+			continue
+		}
+
+		key := format.Key
+		if len(key) == 0 {
+			key = defaultKey
+		} else {
+			for _, msg := range utilvalidation.IsConfigMapKey(key) {
+				el = append(el, field.Invalid(formatPath.Child("key"), key, msg))
+			}
+		}
+		if reservedSecretKeys.Has(key) {
+			el = append(el, field.Invalid(formatPath.Child("key"), key, "must not be a key used for the certificate, private key or keystores"))
+		} else if keys.Has(key) {
+			el = append(el, field.Duplicate(formatPath.Child("key"), key))
+		}
+		keys.Insert(key)
+	}
+
+	return el
+}
+
+// validateKeystores validates the keystores that will be written to the
+// Certificate's Secret. Fields are only validated if the keystore has `create`
+// set to true, as the keystore configuration is ignored otherwise. A separately
+// configured JKS truststore is always validated as it is created regardless.
+func validateKeystores(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	keystoresPath := fldPath.Child("keystores")
+	if jks := crt.Keystores.JKS; jks != nil {
+		if jks.Create {
+			el = append(el, validateKeystorePasswordSecretRef(jks.PasswordSecretRef, "must be specified when create is true", keystoresPath.Child("jks", "passwordSecretRef"))...)
+		}
+		if jks.Truststore != nil {
+			el = append(el, validateKeystorePasswordSecretRef(jks.Truststore.PasswordSecretRef, "must be specified", keystoresPath.Child("jks", "truststore", "passwordSecretRef"))...)
+		}
+	}
+	if pkcs12 := crt.Keystores.PKCS12; pkcs12 != nil && pkcs12.Create {
+		el = append(el, validateKeystorePasswordSecretRef(pkcs12.PasswordSecretRef, "must be specified when create is true", keystoresPath.Child("pkcs12", "passwordSecretRef"))...)
+	}
+
+	return el
+}
+
+func validateKeystorePasswordSecretRef(ref cmmeta.SecretKeySelector, requiredMsg string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	if len(ref.Name) == 0 {
+		el = append(el, field.Required(fldPath.Child("name"), requiredMsg))
+	}
+	if len(ref.Key) == 0 {
+		el = append(el, field.Required(fldPath.Child("key"), requiredMsg))
+	}
+
+	return el
+}
+
+// validateSecretSinks validates the external secret stores that the
+// Certificate's data is written to. Each sink must have a unique name and
+// configure exactly one secret store.
+func validateSecretSinks(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, sink := range crt.SecretSinks {
+		sinkPath := fldPath.Child("secretSinks").Index(i)
+		if len(sink.Name) == 0 {
+			el = append(el, field.Required(sinkPath.Child("name"), "must be specified"))
+		} else if names.Has(sink.Name) {
+			el = append(el, field.Duplicate(sinkPath.Child("name"), sink.Name))
+		}
+		names.Insert(sink.Name)
+
+		numStores := 0
+		if vault := sink.Vault; vault != nil {
+			numStores++
+			vaultPath := sinkPath.Child("vault")
+			el = append(el, validateIssuerRef(vault.IssuerRef, vaultPath)...)
+			// The credentials of ClusterIssuers, and of Issuers in other
+			// namespaces, are not owned by the namespace of the Certificate,
+			// so may not be used to write to arbitrary Vault paths.
+			if vault.IssuerRef.Kind == internalcmapi.ClusterIssuerKind {
+				el = append(el, field.Forbidden(vaultPath.Child("issuerRef", "kind"), "secret sinks may only reference an Issuer in the namespace of the Certificate"))
+			}
+			if len(vault.IssuerRef.Group) > 0 && vault.IssuerRef.Group != internalcmapi.SchemeGroupVersion.Group {
+				el = append(el, field.Invalid(vaultPath.Child("issuerRef", "group"), vault.IssuerRef.Group, "must be cert-manager.io"))
+			}
+			if len(vault.IssuerRef.Namespace) > 0 {
+				el = append(el, field.Forbidden(vaultPath.Child("issuerRef", "namespace"), "secret sinks may only reference an Issuer in the namespace of the Certificate"))
+			}
+			if len(vault.Path) == 0 {
+				el = append(el, field.Required(vaultPath.Child("path"), "must be specified"))
+			}
+		}
+		if aws := sink.AWSSecretsManager; aws != nil {
+			numStores++
+			awsPath := sinkPath.Child("awsSecretsManager")
+			if len(aws.Region) == 0 {
+				el = append(el, field.Required(awsPath.Child("region"), "must be specified"))
+			}
+			if len(aws.SecretID) == 0 {
+				el = append(el, field.Required(awsPath.Child("secretID"), "must be specified"))
+			}
+			if len(aws.AccessKeyID) > 0 && aws.SecretAccessKey == nil {
+				el = append(el, field.Required(awsPath.Child("secretAccessKeySecretRef"), "must be specified when accessKeyID is set"))
+			}
+			if aws.SecretAccessKey != nil {
+				if len(aws.AccessKeyID) == 0 {
+					el = append(el, field.Required(awsPath.Child("accessKeyID"), "must be specified when secretAccessKeySecretRef is set"))
+				}
+				if len(aws.SecretAccessKey.Name) == 0 {
+					el = append(el, field.Required(awsPath.Child("secretAccessKeySecretRef", "name"), "must be specified"))
+				}
+				if len(aws.SecretAccessKey.Key) == 0 {
+					el = append(el, field.Required(awsPath.Child("secretAccessKeySecretRef", "key"), "must be specified"))
+				}
+			}
+		}
+		if numStores != 1 {
+			el = append(el, field.Invalid(sinkPath, "", "exactly one of vault or awsSecretsManager must be specified"))
+		}
+	}
+
+	return el
+}
+
 func ValidateDuration(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
@@ -44,6 +44,8 @@ func ValidateCertificateRequest(a *admissionv1.AdmissionRequest, obj runtime.Obj
 	allErrs := ValidateCertificateRequestSpec(&cr.Spec, field.NewPath("spec"), true)
 	allErrs = append(allErrs,
 		ValidateCertificateRequestApprovalCondition(cr.Status.Conditions, field.NewPath("status", "conditions"))...)
+	allErrs = append(allErrs,
+		ValidateVenafiCustomFieldsAnnotation(cr.Annotations, field.NewPath("metadata", "annotations"))...)
 
 	w := validateAPIVersion(a.RequestKind)
 
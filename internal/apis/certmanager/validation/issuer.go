@@ -19,11 +19,15 @@ package validation
 import (
 	"crypto/x509"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/internal/api/validation"
@@ -31,6 +35,8 @@ import (
 	"github.com/jetstack/cert-manager/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/internal/apis/certmanager/validation/util"
 	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+	"github.com/jetstack/cert-manager/internal/serial"
+	"github.com/jetstack/cert-manager/pkg/util/spiffe"
 )
 
 // Validation functions for cert-manager Issuer types.
@@ -51,7 +57,24 @@ func ValidateUpdateIssuer(a *admissionv1.AdmissionRequest, oldObj, obj runtime.O
 }
 
 func ValidateIssuerSpec(iss *certmanager.IssuerSpec, fldPath *field.Path) (field.ErrorList, validation.WarningList) {
-	return ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
+	el, warnings := ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
+	if iss.SPIFFE != nil {
+		el = append(el, ValidateSPIFFEPolicy(iss.SPIFFE, fldPath.Child("spiffe"))...)
+	}
+	return el, warnings
+}
+
+func ValidateSPIFFEPolicy(policy *certmanager.SPIFFEPolicy, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(policy.TrustDomains) == 0 {
+		el = append(el, field.Required(fldPath.Child("trustDomains"), "at least one trust domain must be specified"))
+	}
+	for i, trustDomain := range policy.TrustDomains {
+		if err := spiffe.ValidateTrustDomain(trustDomain); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("trustDomains").Index(i), trustDomain, err.Error()))
+		}
+	}
+	return el
 }
 
 func ValidateIssuerConfig(iss *certmanager.IssuerConfig, fldPath *field.Path) (field.ErrorList, validation.WarningList) {
@@ -131,6 +154,33 @@ func ValidateACMEIssuerConfig(iss *cmacme.ACMEIssuer, fldPath *field.Path) (fiel
 
 	for i, sol := range iss.Solvers {
 		el = append(el, ValidateACMEIssuerChallengeSolverConfig(&sol, fldPath.Child("solvers").Index(i))...)
+
+		if sol.HTTP01 != nil && sol.HTTP01.GatewayHTTPRoute != nil {
+			gateway := sol.HTTP01.GatewayHTTPRoute
+			if len(gateway.ParentRefs) == 0 && len(gateway.Labels) > 0 {
+				warnings = append(warnings, deprecatedACMEHTTP01GatewayLabelsOnly)
+			}
+		}
+	}
+
+	for i, window := range iss.MaintenanceWindows {
+		windowFldPath := fldPath.Child("maintenanceWindows").Index(i)
+		if window.Start.IsZero() {
+			el = append(el, field.Required(windowFldPath.Child("start"), "the start of a maintenance window is required"))
+		}
+		if window.End != nil && !window.End.After(window.Start.Time) {
+			el = append(el, field.Invalid(windowFldPath.Child("end"), window.End.Time, "must be after the start of the maintenance window"))
+		}
+	}
+
+	if preflight := iss.Preflight; preflight != nil {
+		preflightFldPath := fldPath.Child("preflight", "server")
+		switch {
+		case len(preflight.Server) == 0:
+			el = append(el, field.Required(preflightFldPath, "the preflight acme server URL is required when using preflight"))
+		case preflight.Server == iss.Server:
+			el = append(el, field.Invalid(preflightFldPath, preflight.Server, "must be different to the acme server URL"))
+		}
 	}
 
 	return el, warnings
@@ -152,6 +202,14 @@ func ValidateACMEIssuerChallengeSolverConfig(sol *cmacme.ACMEChallengeSolver, fl
 			el = append(el, ValidateACMEChallengeSolverDNS01(sol.DNS01, fldPath.Child("dns01"))...)
 		}
 	}
+	if sol.TLSALPN01 != nil {
+		if numProviders > 0 {
+			el = append(el, field.Forbidden(fldPath, "may not specify more than one solver type in a single solver"))
+		} else {
+			numProviders++
+			el = append(el, ValidateACMEIssuerChallengeSolverTLSALPN01Config(sol.TLSALPN01, fldPath.Child("tlsalpn01"))...)
+		}
+	}
 	if numProviders == 0 {
 		el = append(el, field.Required(fldPath, "no solver type configured"))
 	}
@@ -188,9 +246,12 @@ func ValidateACMEIssuerChallengeSolverHTTP01IngressConfig(ingress *cmacme.ACMECh
 		el = append(el, field.Forbidden(fldPath, "only one of 'name' or 'class' should be specified"))
 	}
 	switch ingress.ServiceType {
-	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort:
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
 	default:
-		el = append(el, field.Invalid(fldPath.Child("serviceType"), ingress.ServiceType, `must be empty, "ClusterIP" or "NodePort"`))
+		el = append(el, field.Invalid(fldPath.Child("serviceType"), ingress.ServiceType, `must be empty, "ClusterIP", "NodePort" or "LoadBalancer"`))
+	}
+	if ingress.ServiceTemplate != nil {
+		el = append(el, validateHTTP01ServiceTemplate(ingress.ServiceType, ingress.ServiceTemplate, fldPath.Child("serviceTemplate"))...)
 	}
 
 	return el
@@ -199,14 +260,89 @@ func ValidateACMEIssuerChallengeSolverHTTP01IngressConfig(ingress *cmacme.ACMECh
 func ValidateACMEIssuerChallengeSolverHTTP01GatewayConfig(gateway *cmacme.ACMEChallengeSolverHTTP01GatewayHTTPRoute, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
-	if len(gateway.Labels) == 0 {
-		el = append(el, field.Required(fldPath.Child("labels"), `labels must be set`))
+	// Solvers that only set labels are still accepted, with a deprecation
+	// warning, so that Issuers written for networking.x-k8s.io Gateways keep
+	// validating.
+	if len(gateway.ParentRefs) == 0 && len(gateway.Labels) == 0 {
+		el = append(el, field.Required(fldPath.Child("parentRefs"), `at least one parentRef must be set`))
 	}
 	switch gateway.ServiceType {
-	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort:
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		el = append(el, field.Invalid(fldPath.Child("serviceType"), gateway.ServiceType, `must be empty, "ClusterIP", "NodePort" or "LoadBalancer"`))
+	}
+	if gateway.ServiceTemplate != nil {
+		el = append(el, validateHTTP01ServiceTemplate(gateway.ServiceType, gateway.ServiceTemplate, fldPath.Child("serviceTemplate"))...)
+	}
+	for i, ref := range gateway.ParentRefs {
+		if len(ref.Name) == 0 {
+			el = append(el, field.Required(fldPath.Child("parentRefs").Index(i).Child("name"), "name must be set"))
+		}
+	}
+	return el
+}
+
+// validateHTTP01ServiceTemplate validates the overrides of an HTTP01 solver
+// service template against the type of the solver service, which defaults to
+// NodePort.
+func validateHTTP01ServiceTemplate(serviceType corev1.ServiceType, template *cmacme.ACMEChallengeSolverHTTP01ServiceTemplate, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeNodePort
+	}
+	exposedOnNodes := serviceType == corev1.ServiceTypeNodePort || serviceType == corev1.ServiceTypeLoadBalancer
+
+	specPath := fldPath.Child("spec")
+	spec := template.Spec
+	if spec.NodePortRange != nil {
+		rangePath := specPath.Child("nodePortRange")
+		if !exposedOnNodes {
+			el = append(el, field.Forbidden(rangePath, "may only be set if serviceType is NodePort or LoadBalancer"))
+		} else {
+			if spec.NodePortRange.Min < 1 || spec.NodePortRange.Min > 65535 {
+				el = append(el, field.Invalid(rangePath.Child("min"), spec.NodePortRange.Min, "must be between 1 and 65535"))
+			}
+			if spec.NodePortRange.Max < 1 || spec.NodePortRange.Max > 65535 {
+				el = append(el, field.Invalid(rangePath.Child("max"), spec.NodePortRange.Max, "must be between 1 and 65535"))
+			}
+			if spec.NodePortRange.Max < spec.NodePortRange.Min {
+				el = append(el, field.Invalid(rangePath.Child("max"), spec.NodePortRange.Max, "must not be less than min"))
+			}
+		}
+	}
+
+	switch spec.ExternalTrafficPolicy {
+	case "":
+	case corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal:
+		if !exposedOnNodes {
+			el = append(el, field.Forbidden(specPath.Child("externalTrafficPolicy"), "may only be set if serviceType is NodePort or LoadBalancer"))
+		}
+	default:
+		el = append(el, field.Invalid(specPath.Child("externalTrafficPolicy"), spec.ExternalTrafficPolicy, `must be empty, "Cluster" or "Local"`))
+	}
+
+	if spec.LoadBalancerClass != nil {
+		if serviceType != corev1.ServiceTypeLoadBalancer {
+			el = append(el, field.Forbidden(specPath.Child("loadBalancerClass"), "may only be set if serviceType is LoadBalancer"))
+		} else if len(*spec.LoadBalancerClass) == 0 {
+			el = append(el, field.Invalid(specPath.Child("loadBalancerClass"), *spec.LoadBalancerClass, "must not be empty"))
+		}
+	}
+
+	return el
+}
+
+func ValidateACMEIssuerChallengeSolverTLSALPN01Config(tlsalpn01 *cmacme.ACMEChallengeSolverTLSALPN01, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	switch tlsalpn01.ServiceType {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
 	default:
-		el = append(el, field.Invalid(fldPath.Child("serviceType"), gateway.ServiceType, `must be empty, "ClusterIP" or "NodePort"`))
+		el = append(el, field.Invalid(fldPath.Child("serviceType"), tlsalpn01.ServiceType, `must be empty, "ClusterIP", "NodePort" or "LoadBalancer"`))
 	}
+	el = append(el, metav1validation.ValidateLabels(tlsalpn01.ServiceLabels, fldPath.Child("serviceLabels"))...)
+
 	return el
 }
 
@@ -220,6 +356,68 @@ func ValidateCAIssuerConfig(iss *certmanager.CAIssuer, fldPath *field.Path) fiel
 			el = append(el, field.Invalid(fldPath.Child("ocspServer").Index(i), ocspURL, "must be a valid URL, e.g., http://ocsp.int-x3.letsencrypt.org"))
 		}
 	}
+	if iss.CRL != nil {
+		el = append(el, validateCAIssuerCRL(iss.CRL, iss.SecretName, fldPath.Child("crl"))...)
+	}
+	if iss.CertificateTransparency != nil {
+		el = append(el, validateCAIssuerCertificateTransparency(iss.CertificateTransparency, fldPath.Child("certificateTransparency"))...)
+	}
+	if iss.SerialNumber != nil {
+		el = append(el, validateCAIssuerSerialNumber(iss.SerialNumber, fldPath.Child("serialNumber"))...)
+	}
+	return el
+}
+
+// minCRLDuration is the shortest validity period that may be configured for
+// the CRL of a CA issuer, to avoid CRLs being regenerated in a tight loop.
+const minCRLDuration = time.Hour
+
+func validateCAIssuerCRL(crl *certmanager.CAIssuerCRL, caSecretName string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(crl.SecretName) == 0 && len(crl.ConfigMapName) == 0 {
+		el = append(el, field.Required(fldPath, "at least one of secretName or configMapName must be specified"))
+	}
+	if len(crl.SecretName) > 0 && crl.SecretName == caSecretName {
+		el = append(el, field.Invalid(fldPath.Child("secretName"), crl.SecretName, "must not be the Secret containing the CA key pair"))
+	}
+	if crl.Duration != nil && crl.Duration.Duration < minCRLDuration {
+		el = append(el, field.Invalid(fldPath.Child("duration"), crl.Duration.Duration.String(), fmt.Sprintf("must be at least %s", minCRLDuration)))
+	}
+	return el
+}
+
+func validateCAIssuerCertificateTransparency(ct *certmanager.CAIssuerCertificateTransparency, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(ct.URL) == 0 {
+		el = append(el, field.Required(fldPath.Child("url"), ""))
+	} else if u, err := url.Parse(ct.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		el = append(el, field.Invalid(fldPath.Child("url"), ct.URL, "must be a valid http or https URL"))
+	}
+	if len(ct.CABundle) > 0 && !x509.NewCertPool().AppendCertsFromPEM(ct.CABundle) {
+		el = append(el, field.Invalid(fldPath.Child("caBundle"), "", "Specified CA bundle is invalid"))
+	}
+	return el
+}
+
+func validateCAIssuerSerialNumber(sn *certmanager.CAIssuerSerialNumber, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	switch sn.Type {
+	case certmanager.CAIssuerSerialNumberRandom, "":
+		if len(sn.CounterConfigMapName) > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("counterConfigMapName"), "may only be set for Monotonic serial numbers"))
+		}
+	case certmanager.CAIssuerSerialNumberMonotonic:
+		if len(sn.CounterConfigMapName) == 0 {
+			el = append(el, field.Required(fldPath.Child("counterConfigMapName"), "required for Monotonic serial numbers"))
+		}
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("type"), sn.Type, []string{
+			string(certmanager.CAIssuerSerialNumberRandom), string(certmanager.CAIssuerSerialNumberMonotonic),
+		}))
+	}
+	if sn.Length != 0 && (sn.Length < serial.MinLength || sn.Length > serial.MaxLength) {
+		el = append(el, field.Invalid(fldPath.Child("length"), sn.Length, fmt.Sprintf("must be between %d and %d", serial.MinLength, serial.MaxLength)))
+	}
 	return el
 }
 
@@ -236,6 +434,25 @@ func ValidateVaultIssuerConfig(iss *certmanager.VaultIssuer, fldPath *field.Path
 		el = append(el, field.Required(fldPath.Child("path"), ""))
 	}
 
+	if len(iss.SignVerbatimPath) > 0 && !iss.SignVerbatim {
+		el = append(el, field.Forbidden(fldPath.Child("signVerbatimPath"), "may only be set if signVerbatim is true"))
+	}
+	if iss.SignVerbatim && len(iss.SignVerbatimPath) == 0 && len(iss.Path) > 0 && !strings.Contains("/"+iss.Path+"/", "/sign/") {
+		el = append(el, field.Required(fldPath.Child("signVerbatimPath"), "must be set if path does not contain a sign segment"))
+	}
+
+	servers := map[string]bool{iss.Server: true}
+	for i, server := range iss.AdditionalServers {
+		serverPath := fldPath.Child("additionalServers").Index(i)
+		switch {
+		case len(server) == 0:
+			el = append(el, field.Required(serverPath, ""))
+		case servers[server]:
+			el = append(el, field.Duplicate(serverPath, server))
+		}
+		servers[server] = true
+	}
+
 	// check if caBundle is valid
 	certs := iss.CABundle
 	if len(certs) > 0 {
@@ -246,10 +463,62 @@ func ValidateVaultIssuerConfig(iss *certmanager.VaultIssuer, fldPath *field.Path
 		}
 	}
 
+	if appRole := iss.Auth.AppRole; appRole != nil && appRole.SecretIDRotation != nil {
+		rotationPath := fldPath.Child("auth", "appRole", "secretIDRotation")
+		if len(appRole.SecretIDRotation.RoleName) == 0 {
+			el = append(el, field.Required(rotationPath.Child("roleName"), ""))
+		}
+		if rb := appRole.SecretIDRotation.RenewBefore; rb != nil && rb.Duration <= 0 {
+			el = append(el, field.Invalid(rotationPath.Child("renewBefore"), rb.Duration, "must be greater than zero"))
+		}
+	}
+
+	if kubeAuth := iss.Auth.Kubernetes; kubeAuth != nil {
+		el = append(el, validateVaultKubernetesAuth(kubeAuth, fldPath.Child("auth", "kubernetes"))...)
+	}
+
 	return el
 	// TODO: add validation for Vault authentication types
 }
 
+// validateVaultKubernetesAuth validates that exactly one of the Secret and the
+// ServiceAccount used to log in to Vault is referenced, by a valid name.
+func validateVaultKubernetesAuth(kubeAuth *certmanager.VaultKubernetesAuth, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	saRef := kubeAuth.ServiceAccountRef
+	switch {
+	case saRef != nil && len(kubeAuth.SecretRef.Name) > 0:
+		el = append(el, field.Forbidden(fldPath, "please supply one of: secretRef, serviceAccountRef"))
+	case saRef == nil && len(kubeAuth.SecretRef.Name) == 0:
+		el = append(el, field.Required(fldPath, "please supply one of: secretRef, serviceAccountRef"))
+	}
+
+	if len(kubeAuth.SecretRef.Name) > 0 {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(kubeAuth.SecretRef.Name) {
+			el = append(el, field.Invalid(fldPath.Child("secretRef", "name"), kubeAuth.SecretRef.Name, msg))
+		}
+	}
+
+	if saRef != nil {
+		saPath := fldPath.Child("serviceAccountRef")
+		if len(saRef.Name) == 0 {
+			el = append(el, field.Required(saPath.Child("name"), ""))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(saRef.Name) {
+				el = append(el, field.Invalid(saPath.Child("name"), saRef.Name, msg))
+			}
+		}
+		for i, audience := range saRef.Audiences {
+			if len(audience) == 0 {
+				el = append(el, field.Required(saPath.Child("audiences").Index(i), ""))
+			}
+		}
+	}
+
+	return el
+}
+
 func ValidateVenafiTPP(tpp *certmanager.VenafiTPP, fldPath *field.Path) (el field.ErrorList) {
 	if tpp.URL == "" {
 		el = append(el, field.Required(fldPath.Child("url"), ""))
@@ -258,6 +527,48 @@ func ValidateVenafiTPP(tpp *certmanager.VenafiTPP, fldPath *field.Path) (el fiel
 }
 
 func ValidateVenafiCloud(c *certmanager.VenafiCloud, fldPath *field.Path) (el field.ErrorList) {
+	if c.OAuth == nil {
+		if len(c.APITokenSecretRef.Name) == 0 {
+			el = append(el, field.Required(fldPath.Child("apiTokenSecretRef", "name"), "please supply one of: apiTokenSecretRef, oauth"))
+		}
+		return el
+	}
+
+	if len(c.APITokenSecretRef.Name) > 0 {
+		el = append(el, field.Forbidden(fldPath, "please supply one of: apiTokenSecretRef, oauth"))
+	}
+	el = append(el, ValidateVenafiCloudOAuth(c.OAuth, fldPath.Child("oauth"))...)
+
+	return el
+}
+
+func ValidateVenafiCloudOAuth(o *certmanager.VenafiCloudOAuth, fldPath *field.Path) (el field.ErrorList) {
+	if len(o.TokenURL) == 0 {
+		el = append(el, field.Required(fldPath.Child("tokenURL"), ""))
+	} else if u, err := url.Parse(o.TokenURL); err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		el = append(el, field.Invalid(fldPath.Child("tokenURL"), o.TokenURL, "must be an absolute URL"))
+	}
+
+	unionCount := 0
+	if o.ClientSecretRef != nil {
+		unionCount++
+		if len(o.ClientID) == 0 {
+			el = append(el, field.Required(fldPath.Child("clientID"), "clientID is required when using clientSecretRef"))
+		}
+		el = append(el, ValidateSecretKeySelector(o.ClientSecretRef, fldPath.Child("clientSecretRef"))...)
+	}
+	if o.FederatedTokenSecretRef != nil {
+		unionCount++
+		el = append(el, ValidateSecretKeySelector(o.FederatedTokenSecretRef, fldPath.Child("federatedTokenSecretRef"))...)
+	}
+
+	if unionCount == 0 {
+		el = append(el, field.Required(fldPath, "please supply one of: clientSecretRef, federatedTokenSecretRef"))
+	}
+	if unionCount > 1 {
+		el = append(el, field.Forbidden(fldPath, "please supply one of: clientSecretRef, federatedTokenSecretRef"))
+	}
+
 	return el
 }
 
@@ -282,9 +593,37 @@ func ValidateVenafiIssuerConfig(iss *certmanager.VenafiIssuer, fldPath *field.Pa
 		el = append(el, field.Forbidden(fldPath, "please supply one of: tpp, cloud"))
 	}
 
+	if iss.Zone != "" && unionCount == 1 {
+		el = append(el, validateVenafiZone(iss.Zone, iss.Cloud != nil, fldPath.Child("zone"))...)
+	}
+
+	if _, ok := iss.CustomFields[""]; ok {
+		el = append(el, field.Invalid(fldPath.Child("customFields"), "", "custom field names must not be empty"))
+	}
+
 	return el
 }
 
+// validateVenafiZone checks the format of a Venafi zone, so that a zone that
+// the Venafi platform cannot resolve is rejected when the issuer is created
+// rather than when it is first used to sign a request.
+func validateVenafiZone(zone string, cloud bool, fldPath *field.Path) field.ErrorList {
+	if cloud {
+		segments := strings.Split(zone, `\`)
+		if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+			return field.ErrorList{field.Invalid(fldPath, zone, `must be of the form "<application name>\<issuing template alias>"`)}
+		}
+		return nil
+	}
+
+	for _, segment := range strings.Split(strings.TrimPrefix(zone, `\`), `\`) {
+		if segment == "" {
+			return field.ErrorList{field.Invalid(fldPath, zone, "must be a policy folder path without empty segments")}
+		}
+	}
+	return nil
+}
+
 // This list must be kept in sync with pkg/issuer/acme/dns/rfc2136/rfc2136.go
 var supportedTSIGAlgorithms = []string{
 	"HMACMD5",
@@ -302,8 +641,17 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 		switch p.CNAMEStrategy {
 		case cmacme.NoneStrategy:
 		case cmacme.FollowStrategy:
+		case cmacme.DelegateStrategy:
 		default:
-			el = append(el, field.Invalid(fldPath.Child("cnameStrategy"), p.CNAMEStrategy, fmt.Sprintf("must be one of %q or %q", cmacme.NoneStrategy, cmacme.FollowStrategy)))
+			el = append(el, field.Invalid(fldPath.Child("cnameStrategy"), p.CNAMEStrategy, fmt.Sprintf("must be one of %q, %q or %q", cmacme.NoneStrategy, cmacme.FollowStrategy, cmacme.DelegateStrategy)))
+		}
+	}
+	if len(p.DelegatedZones) > 0 && p.CNAMEStrategy != cmacme.DelegateStrategy {
+		el = append(el, field.Forbidden(fldPath.Child("delegatedZones"), fmt.Sprintf("may only be set if cnameStrategy is %q", cmacme.DelegateStrategy)))
+	}
+	for i, zone := range p.DelegatedZones {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(zone) {
+			el = append(el, field.Invalid(fldPath.Child("delegatedZones").Index(i), zone, msg))
 		}
 	}
 	numProviders := 0
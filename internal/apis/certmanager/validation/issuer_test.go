@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/internal/api/validation"
@@ -94,6 +96,133 @@ func TestValidateVaultIssuerConfig(t *testing.T) {
 				field.Invalid(fldPath.Child("caBundle"), "", "Specified CA bundle is invalid"),
 			},
 		},
+		"vault issuer using sign-verbatim with a derived path": {
+			spec: &cmapi.VaultIssuer{
+				Server:       "something",
+				Path:         "pki/sign/role",
+				SignVerbatim: true,
+			},
+		},
+		"vault issuer using sign-verbatim without a sign segment in path": {
+			spec: &cmapi.VaultIssuer{
+				Server:       "something",
+				Path:         "a/b/c",
+				SignVerbatim: true,
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("signVerbatimPath"), "must be set if path does not contain a sign segment"),
+			},
+		},
+		"vault issuer with a sign-verbatim path but sign-verbatim disabled": {
+			spec: &cmapi.VaultIssuer{
+				Server:           "something",
+				Path:             "pki/sign/role",
+				SignVerbatimPath: "pki/sign-verbatim/role",
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("signVerbatimPath"), "may only be set if signVerbatim is true"),
+			},
+		},
+		"vault issuer with app role secret ID rotation missing the role name": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					AppRole: &cmapi.VaultAppRole{
+						RoleId:    "role-id",
+						SecretRef: cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "secret"}, Key: "secret-id"},
+						SecretIDRotation: &cmapi.VaultAppRoleSecretIDRotation{
+							RenewBefore: &metav1.Duration{Duration: -time.Hour},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("auth", "appRole", "secretIDRotation", "roleName"), ""),
+				field.Invalid(fldPath.Child("auth", "appRole", "secretIDRotation", "renewBefore"), -time.Hour, "must be greater than zero"),
+			},
+		},
+		"vault issuer with kubernetes auth using a service account": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role:              "role",
+						ServiceAccountRef: &cmapi.ServiceAccountRef{Name: "vault-sa"},
+					},
+				},
+			},
+		},
+		"vault issuer with kubernetes auth using both a secret and a service account": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role:              "role",
+						SecretRef:         cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "secret"}},
+						ServiceAccountRef: &cmapi.ServiceAccountRef{},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("auth", "kubernetes"), "please supply one of: secretRef, serviceAccountRef"),
+				field.Required(fldPath.Child("auth", "kubernetes", "serviceAccountRef", "name"), ""),
+			},
+		},
+		"vault issuer with kubernetes auth without a secret or a service account": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role: "role",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("auth", "kubernetes"), "please supply one of: secretRef, serviceAccountRef"),
+			},
+		},
+		"vault issuer with kubernetes auth referencing invalid names": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role: "role",
+						ServiceAccountRef: &cmapi.ServiceAccountRef{
+							Name:      "Vault_SA",
+							Audiences: []string{"https://vault.example.com", ""},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("auth", "kubernetes", "serviceAccountRef", "name"), "Vault_SA", utilvalidation.IsDNS1123Subdomain("Vault_SA")[0]),
+				field.Required(fldPath.Child("auth", "kubernetes", "serviceAccountRef", "audiences").Index(1), ""),
+			},
+		},
+		"vault issuer with additional servers": {
+			spec: &cmapi.VaultIssuer{
+				Server:            "https://vault-0.example.com:8200",
+				AdditionalServers: []string{"https://vault-1.example.com:8200", "https://vault-2.example.com:8200"},
+				Path:              "a/b/c",
+			},
+		},
+		"vault issuer with empty or duplicate additional servers": {
+			spec: &cmapi.VaultIssuer{
+				Server:            "https://vault-0.example.com:8200",
+				AdditionalServers: []string{"", "https://vault-0.example.com:8200", "https://vault-1.example.com:8200", "https://vault-1.example.com:8200"},
+				Path:              "a/b/c",
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("additionalServers").Index(0), ""),
+				field.Duplicate(fldPath.Child("additionalServers").Index(1), "https://vault-0.example.com:8200"),
+				field.Duplicate(fldPath.Child("additionalServers").Index(3), "https://vault-1.example.com:8200"),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -129,6 +258,62 @@ func TestValidateACMEIssuerConfig(t *testing.T) {
 				field.Required(fldPath.Child("server"), "acme server URL is a required field"),
 			},
 		},
+		"acme issuer with valid maintenance windows": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				MaintenanceWindows: []cmacme.ACMEMaintenanceWindow{
+					{Start: metav1.NewTime(time.Date(2021, 6, 1, 22, 0, 0, 0, time.UTC)), End: &metav1.Time{Time: time.Date(2021, 6, 2, 2, 0, 0, 0, time.UTC)}},
+					{Start: metav1.NewTime(time.Date(2021, 7, 1, 22, 0, 0, 0, time.UTC)), Reason: "registrar migration"},
+				},
+			},
+		},
+		"acme issuer with invalid maintenance windows": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				MaintenanceWindows: []cmacme.ACMEMaintenanceWindow{
+					{End: &metav1.Time{Time: time.Date(2021, 6, 2, 2, 0, 0, 0, time.UTC)}},
+					{Start: metav1.NewTime(time.Date(2021, 6, 1, 22, 0, 0, 0, time.UTC)), End: &metav1.Time{Time: time.Date(2021, 6, 1, 22, 0, 0, 0, time.UTC)}},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("maintenanceWindows").Index(0).Child("start"), "the start of a maintenance window is required"),
+				field.Invalid(fldPath.Child("maintenanceWindows").Index(1).Child("end"), time.Date(2021, 6, 1, 22, 0, 0, 0, time.UTC), "must be after the start of the maintenance window"),
+			},
+		},
+		"acme issuer with a valid preflight server": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Preflight:  &cmacme.ACMEIssuerPreflight{Server: "valid-staging-server"},
+			},
+		},
+		"acme issuer with a missing preflight server": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Preflight:  &cmacme.ACMEIssuerPreflight{},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("preflight", "server"), "the preflight acme server URL is required when using preflight"),
+			},
+		},
+		"acme issuer with a preflight server that is the same as the acme server": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Preflight:  &cmacme.ACMEIssuerPreflight{Server: "valid-server"},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("preflight", "server"), "valid-server", "must be different to the acme server URL"),
+			},
+		},
 		"acme solver without any config": {
 			spec: &cmacme.ACMEIssuer{
 				Email:      "valid-email",
@@ -255,6 +440,9 @@ func TestValidateACMEIssuerConfig(t *testing.T) {
 								Labels: map[string]string{
 									"key": "value",
 								},
+								ParentRefs: []cmacme.ACMEChallengeSolverHTTP01GatewayParentRef{
+									{Name: "gateway"},
+								},
 							},
 						},
 					},
@@ -276,11 +464,114 @@ func TestValidateACMEIssuerConfig(t *testing.T) {
 			},
 			errs: []*field.Error{
 				field.Required(
-					fldPath.Child("solvers").Index(0).Child("http01", "gateway").Child("labels"),
-					"labels must be set",
+					fldPath.Child("solvers").Index(0).Child("http01", "gateway").Child("parentRefs"),
+					"at least one parentRef must be set",
+				),
+			},
+		},
+		"acme solver with http01 gateway config setting only labels": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Solvers: []cmacme.ACMEChallengeSolver{
+					{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							GatewayHTTPRoute: &cmacme.ACMEChallengeSolverHTTP01GatewayHTTPRoute{
+								Labels: map[string]string{
+									"key": "value",
+								},
+							},
+						},
+					},
+				},
+			},
+			warnings: validation.WarningList{deprecatedACMEHTTP01GatewayLabelsOnly},
+		},
+		"acme solver with http01 gateway parentRef missing a name": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Solvers: []cmacme.ACMEChallengeSolver{
+					{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							GatewayHTTPRoute: &cmacme.ACMEChallengeSolverHTTP01GatewayHTTPRoute{
+								Labels: map[string]string{
+									"key": "value",
+								},
+								ParentRefs: []cmacme.ACMEChallengeSolverHTTP01GatewayParentRef{
+									{Name: "gateway"},
+									{Namespace: "gateways"},
+								},
+							},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(
+					fldPath.Child("solvers").Index(0).Child("http01", "gateway").Child("parentRefs").Index(1).Child("name"),
+					"name must be set",
+				),
+			},
+		},
+		"acme solver with valid tlsalpn01 config": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Solvers: []cmacme.ACMEChallengeSolver{
+					{
+						TLSALPN01: &cmacme.ACMEChallengeSolverTLSALPN01{
+							ServiceType: corev1.ServiceTypeLoadBalancer,
+							ServiceLabels: map[string]string{
+								"passthrough": "true",
+							},
+						},
+					},
+				},
+			},
+		},
+		"acme solver with invalid tlsalpn01 service type": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Solvers: []cmacme.ACMEChallengeSolver{
+					{
+						TLSALPN01: &cmacme.ACMEChallengeSolverTLSALPN01{
+							ServiceType: corev1.ServiceTypeExternalName,
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(
+					fldPath.Child("solvers").Index(0).Child("tlsalpn01", "serviceType"),
+					corev1.ServiceTypeExternalName,
+					`must be empty, "ClusterIP", "NodePort" or "LoadBalancer"`,
 				),
 			},
 		},
+		"acme solver with both http01 and tlsalpn01 configured": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				Solvers: []cmacme.ACMEChallengeSolver{
+					{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+						},
+						TLSALPN01: &cmacme.ACMEChallengeSolverTLSALPN01{},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("solvers").Index(0), "may not specify more than one solver type in a single solver"),
+			},
+		},
 		"acme solver with multiple http01 solver configs": {
 			spec: &cmacme.ACMEIssuer{
 				Email:      "valid-email",
@@ -294,6 +585,9 @@ func TestValidateACMEIssuerConfig(t *testing.T) {
 								Labels: map[string]string{
 									"a": "b",
 								},
+								ParentRefs: []cmacme.ACMEChallengeSolverHTTP01GatewayParentRef{
+									{Name: "gateway"},
+								},
 							},
 						},
 					},
@@ -423,6 +717,40 @@ func TestValidateIssuerSpec(t *testing.T) {
 			},
 			errs: []*field.Error{},
 		},
+		"valid ca issuer with a spiffe policy": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+					},
+				},
+				SPIFFE: &cmapi.SPIFFEPolicy{TrustDomains: []string{"cluster.local"}},
+			},
+			errs: []*field.Error{},
+		},
+		"spiffe policy without trust domains": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+					},
+				},
+				SPIFFE: &cmapi.SPIFFEPolicy{},
+			},
+			errs: []*field.Error{field.Required(fldPath.Child("spiffe", "trustDomains"), "at least one trust domain must be specified")},
+		},
+		"spiffe policy with an invalid trust domain": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+					},
+				},
+				SPIFFE: &cmapi.SPIFFEPolicy{TrustDomains: []string{"cluster.local", "Example.org"}},
+			},
+			errs: []*field.Error{field.Invalid(fldPath.Child("spiffe", "trustDomains").Index(1), "Example.org",
+				`trust domain "Example.org" must only contain lowercase letters, digits, dots, dashes and underscores`)},
+		},
 		"ca issuer without secret name specified": {
 			spec: &cmapi.IssuerSpec{
 				IssuerConfig: cmapi.IssuerConfig{
@@ -431,6 +759,167 @@ func TestValidateIssuerSpec(t *testing.T) {
 			},
 			errs: []*field.Error{field.Required(fldPath.Child("ca", "secretName"), "")},
 		},
+		"valid ca issuer with crl": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CRL: &cmapi.CAIssuerCRL{
+							ConfigMapName: "ca-crl",
+							Duration:      &metav1.Duration{Duration: 7 * 24 * time.Hour},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{},
+		},
+		"ca issuer with crl without a secret or configmap name specified": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CRL:        &cmapi.CAIssuerCRL{},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("ca", "crl"), "at least one of secretName or configMapName must be specified"),
+			},
+		},
+		"ca issuer with crl duration too short": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CRL: &cmapi.CAIssuerCRL{
+							SecretName: "ca-crl",
+							Duration:   &metav1.Duration{Duration: time.Minute},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("ca", "crl", "duration"), "1m0s", "must be at least 1h0m0s"),
+			},
+		},
+		"ca issuer with crl written to the ca secret": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CRL:        &cmapi.CAIssuerCRL{SecretName: "valid"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("ca", "crl", "secretName"), "valid", "must not be the Secret containing the CA key pair"),
+			},
+		},
+		"valid ca issuer with certificate transparency": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CertificateTransparency: &cmapi.CAIssuerCertificateTransparency{
+							URL: "https://ct-hook.example.com/add-pre-chain",
+						},
+					},
+				},
+			},
+			errs: []*field.Error{},
+		},
+		"ca issuer with certificate transparency without a url": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName:              "valid",
+						CertificateTransparency: &cmapi.CAIssuerCertificateTransparency{},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("ca", "certificateTransparency", "url"), ""),
+			},
+		},
+		"ca issuer with certificate transparency with an invalid url and ca bundle": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CertificateTransparency: &cmapi.CAIssuerCertificateTransparency{
+							URL:      "ct-hook.example.com",
+							CABundle: []byte("not a certificate"),
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("ca", "certificateTransparency", "url"), "ct-hook.example.com", "must be a valid http or https URL"),
+				field.Invalid(fldPath.Child("ca", "certificateTransparency", "caBundle"), "", "Specified CA bundle is invalid"),
+			},
+		},
+		"valid ca issuer with monotonic serial numbers": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						SerialNumber: &cmapi.CAIssuerSerialNumber{
+							Type:                 cmapi.CAIssuerSerialNumberMonotonic,
+							Length:               8,
+							CounterConfigMapName: "serial-counter",
+						},
+					},
+				},
+			},
+			errs: []*field.Error{},
+		},
+		"ca issuer with monotonic serial numbers without a counter configmap": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						SerialNumber: &cmapi.CAIssuerSerialNumber{
+							Type: cmapi.CAIssuerSerialNumberMonotonic,
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("ca", "serialNumber", "counterConfigMapName"), "required for Monotonic serial numbers"),
+			},
+		},
+		"ca issuer with random serial numbers with a counter configmap and an invalid length": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						SerialNumber: &cmapi.CAIssuerSerialNumber{
+							Length:               21,
+							CounterConfigMapName: "serial-counter",
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("ca", "serialNumber", "counterConfigMapName"), "may only be set for Monotonic serial numbers"),
+				field.Invalid(fldPath.Child("ca", "serialNumber", "length"), 21, "must be between 8 and 20"),
+			},
+		},
+		"ca issuer with an unsupported serial number type": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						SerialNumber: &cmapi.CAIssuerSerialNumber{
+							Type: "Sequential",
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("ca", "serialNumber", "type"), cmapi.CAIssuerSerialNumberType("Sequential"), []string{"Random", "Monotonic"}),
+			},
+		},
 		"valid self signed issuer": {
 			spec: &cmapi.IssuerSpec{
 				IssuerConfig: cmapi.IssuerConfig{
@@ -577,7 +1066,95 @@ func TestValidateACMEIssuerHTTP01Config(t *testing.T) {
 				},
 			},
 			errs: []*field.Error{
-				field.Invalid(fldPath.Child("ingress", "serviceType"), corev1.ServiceType("InvalidServiceType"), `must be empty, "ClusterIP" or "NodePort"`),
+				field.Invalid(fldPath.Child("ingress", "serviceType"), corev1.ServiceType("InvalidServiceType"), `must be empty, "ClusterIP", "NodePort" or "LoadBalancer"`),
+			},
+		},
+		"acme issuer with valid http01 service config serviceType LoadBalancer": {
+			cfg: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+					ServiceType: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+		},
+		"acme issuer with valid http01 service template for the default NodePort service": {
+			cfg: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+					ServiceTemplate: &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+						Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+							NodePortRange:         &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 30080, Max: 30089},
+							ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+						},
+					},
+				},
+			},
+		},
+		"acme issuer with valid http01 gateway service template for a LoadBalancer service": {
+			cfg: &cmacme.ACMEChallengeSolverHTTP01{
+				GatewayHTTPRoute: &cmacme.ACMEChallengeSolverHTTP01GatewayHTTPRoute{
+					Labels:      map[string]string{"a": "b"},
+					ParentRefs:  []cmacme.ACMEChallengeSolverHTTP01GatewayParentRef{{Name: "gateway"}},
+					ServiceType: corev1.ServiceTypeLoadBalancer,
+					ServiceTemplate: &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+						Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+							NodePortRange:         &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 30080, Max: 30089},
+							ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+							LoadBalancerClass:     strPtr("example.com/lb"),
+						},
+					},
+				},
+			},
+		},
+		"acme issuer with http01 service template overrides not applicable to a ClusterIP service": {
+			cfg: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+					ServiceType: corev1.ServiceTypeClusterIP,
+					ServiceTemplate: &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+						Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+							NodePortRange:         &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 30080, Max: 30089},
+							ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+							LoadBalancerClass:     strPtr("example.com/lb"),
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("ingress", "serviceTemplate", "spec", "nodePortRange"), "may only be set if serviceType is NodePort or LoadBalancer"),
+				field.Forbidden(fldPath.Child("ingress", "serviceTemplate", "spec", "externalTrafficPolicy"), "may only be set if serviceType is NodePort or LoadBalancer"),
+				field.Forbidden(fldPath.Child("ingress", "serviceTemplate", "spec", "loadBalancerClass"), "may only be set if serviceType is LoadBalancer"),
+			},
+		},
+		"acme issuer with invalid http01 service template values": {
+			cfg: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+					ServiceType: corev1.ServiceTypeLoadBalancer,
+					ServiceTemplate: &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+						Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+							NodePortRange:         &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 0, Max: 70000},
+							ExternalTrafficPolicy: "Nearest",
+							LoadBalancerClass:     strPtr(""),
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("ingress", "serviceTemplate", "spec", "nodePortRange", "min"), int32(0), "must be between 1 and 65535"),
+				field.Invalid(fldPath.Child("ingress", "serviceTemplate", "spec", "nodePortRange", "max"), int32(70000), "must be between 1 and 65535"),
+				field.Invalid(fldPath.Child("ingress", "serviceTemplate", "spec", "externalTrafficPolicy"), corev1.ServiceExternalTrafficPolicyType("Nearest"), `must be empty, "Cluster" or "Local"`),
+				field.Invalid(fldPath.Child("ingress", "serviceTemplate", "spec", "loadBalancerClass"), "", "must not be empty"),
+			},
+		},
+		"acme issuer with an empty http01 service template node port range": {
+			cfg: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+					ServiceTemplate: &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+						Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+							NodePortRange: &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 30089, Max: 30080},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("ingress", "serviceTemplate", "spec", "nodePortRange", "max"), int32(30080), "must not be less than min"),
 			},
 		},
 	}
@@ -1110,6 +1687,47 @@ func TestValidateACMEIssuerDNS01Config(t *testing.T) {
 				field.Required(fldPath.Child("rfc2136", "tsigKeyName"), ""),
 			},
 		},
+		"delegate cname strategy": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CNAMEStrategy: cmacme.DelegateStrategy,
+				CloudDNS: &cmacme.ACMEIssuerDNS01ProviderCloudDNS{
+					Project: "valid",
+				},
+			},
+		},
+		"delegate cname strategy with delegated zones": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CNAMEStrategy:  cmacme.DelegateStrategy,
+				DelegatedZones: []string{"acme.example.net"},
+				CloudDNS: &cmacme.ACMEIssuerDNS01ProviderCloudDNS{
+					Project: "valid",
+				},
+			},
+		},
+		"delegated zones without the delegate cname strategy": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CNAMEStrategy:  cmacme.FollowStrategy,
+				DelegatedZones: []string{"acme.example.net", "Not A Zone"},
+				CloudDNS: &cmacme.ACMEIssuerDNS01ProviderCloudDNS{
+					Project: "valid",
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("delegatedZones"), `may only be set if cnameStrategy is "Delegate"`),
+				field.Invalid(fldPath.Child("delegatedZones").Index(1), "Not A Zone", utilvalidation.IsDNS1123Subdomain("Not A Zone")[0]),
+			},
+		},
+		"unknown cname strategy": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CNAMEStrategy: "Unknown",
+				CloudDNS: &cmacme.ACMEIssuerDNS01ProviderCloudDNS{
+					Project: "valid",
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("cnameStrategy"), cmacme.CNAMEStrategy("Unknown"), `must be one of "None", "Follow" or "Delegate"`),
+			},
+		},
 		"multiple providers configured": {
 			cfg: &cmacme.ACMEChallengeSolverDNS01{
 				CloudDNS: &cmacme.ACMEIssuerDNS01ProviderCloudDNS{
@@ -1243,12 +1861,79 @@ func TestValidateVenafiIssuerConfig(t *testing.T) {
 				TPP: &cmapi.VenafiTPP{
 					URL: "https://tpp.example.com/vedsdk",
 				},
-				Cloud: &cmapi.VenafiCloud{},
+				Cloud: &cmapi.VenafiCloud{
+					APITokenSecretRef: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "api-token"},
+					},
+				},
 			},
 			errs: []*field.Error{
 				field.Forbidden(fldPath, "please supply one of: tpp, cloud"),
 			},
 		},
+		"valid TPP zone with policy root": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "\\VED\\Policy\\devops\\cert-manager",
+				TPP: &cmapi.VenafiTPP{
+					URL: "https://tpp.example.com/vedsdk",
+				},
+			},
+		},
+		"TPP zone with empty segment": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "devops\\\\cert-manager\\",
+				TPP: &cmapi.VenafiTPP{
+					URL: "https://tpp.example.com/vedsdk",
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("zone"), "devops\\\\cert-manager\\", "must be a policy folder path without empty segments"),
+			},
+		},
+		"valid cloud zone": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "My Application\\Default",
+				Cloud: &cmapi.VenafiCloud{
+					APITokenSecretRef: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "api-token"},
+					},
+				},
+			},
+		},
+		"cloud zone without issuing template alias": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "My Application",
+				Cloud: &cmapi.VenafiCloud{
+					APITokenSecretRef: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "api-token"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("zone"), "My Application", `must be of the form "<application name>\<issuing template alias>"`),
+			},
+		},
+		"valid custom fields": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "a\\b\\c",
+				TPP: &cmapi.VenafiTPP{
+					URL: "https://tpp.example.com/vedsdk",
+				},
+				CustomFields: map[string]string{"field-a": "a"},
+			},
+		},
+		"empty custom field name": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "a\\b\\c",
+				TPP: &cmapi.VenafiTPP{
+					URL: "https://tpp.example.com/vedsdk",
+				},
+				CustomFields: map[string]string{"": "a"},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("customFields"), "", "custom field names must not be empty"),
+			},
+		},
 	}
 
 	for n, s := range scenarios {
@@ -1302,6 +1987,139 @@ func TestValidateVenafiTPP(t *testing.T) {
 	}
 }
 
+func TestValidateVenafiCloud(t *testing.T) {
+	fldPath := field.NewPath("test")
+	scenarios := map[string]struct {
+		cfg  *cmapi.VenafiCloud
+		errs []*field.Error
+	}{
+		"valid api token": {
+			cfg: &cmapi.VenafiCloud{
+				APITokenSecretRef: cmmeta.SecretKeySelector{
+					LocalObjectReference: cmmeta.LocalObjectReference{Name: "api-token"},
+				},
+			},
+		},
+		"missing api token and oauth": {
+			cfg: &cmapi.VenafiCloud{},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("apiTokenSecretRef", "name"), "please supply one of: apiTokenSecretRef, oauth"),
+			},
+		},
+		"valid oauth client credentials": {
+			cfg: &cmapi.VenafiCloud{
+				OAuth: &cmapi.VenafiCloudOAuth{
+					TokenURL: "https://idp.example.com/oauth/token",
+					ClientID: "cert-manager",
+					ClientSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "oauth"},
+						Key:                  "client-secret",
+					},
+				},
+			},
+		},
+		"valid oauth federated token": {
+			cfg: &cmapi.VenafiCloud{
+				OAuth: &cmapi.VenafiCloudOAuth{
+					TokenURL: "https://idp.example.com/oauth/token",
+					FederatedTokenSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "federated"},
+						Key:                  "token",
+					},
+				},
+			},
+		},
+		"api token and oauth both set": {
+			cfg: &cmapi.VenafiCloud{
+				APITokenSecretRef: cmmeta.SecretKeySelector{
+					LocalObjectReference: cmmeta.LocalObjectReference{Name: "api-token"},
+				},
+				OAuth: &cmapi.VenafiCloudOAuth{
+					TokenURL: "https://idp.example.com/oauth/token",
+					FederatedTokenSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "federated"},
+						Key:                  "token",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath, "please supply one of: apiTokenSecretRef, oauth"),
+			},
+		},
+		"oauth missing token url and credentials": {
+			cfg: &cmapi.VenafiCloud{
+				OAuth: &cmapi.VenafiCloudOAuth{},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("oauth", "tokenURL"), ""),
+				field.Required(fldPath.Child("oauth"), "please supply one of: clientSecretRef, federatedTokenSecretRef"),
+			},
+		},
+		"oauth relative token url": {
+			cfg: &cmapi.VenafiCloud{
+				OAuth: &cmapi.VenafiCloudOAuth{
+					TokenURL: "/oauth/token",
+					FederatedTokenSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "federated"},
+						Key:                  "token",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("oauth", "tokenURL"), "/oauth/token", "must be an absolute URL"),
+			},
+		},
+		"oauth client secret without client id": {
+			cfg: &cmapi.VenafiCloud{
+				OAuth: &cmapi.VenafiCloudOAuth{
+					TokenURL: "https://idp.example.com/oauth/token",
+					ClientSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "oauth"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("oauth", "clientID"), "clientID is required when using clientSecretRef"),
+				field.Required(fldPath.Child("oauth", "clientSecretRef", "key"), "secret key is required"),
+			},
+		},
+		"oauth client secret and federated token both set": {
+			cfg: &cmapi.VenafiCloud{
+				OAuth: &cmapi.VenafiCloudOAuth{
+					TokenURL: "https://idp.example.com/oauth/token",
+					ClientID: "cert-manager",
+					ClientSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "oauth"},
+						Key:                  "client-secret",
+					},
+					FederatedTokenSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "federated"},
+						Key:                  "token",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("oauth"), "please supply one of: clientSecretRef, federatedTokenSecretRef"),
+			},
+		},
+	}
+
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			errs := ValidateVenafiCloud(s.cfg, fldPath)
+			if len(errs) != len(s.errs) {
+				t.Fatalf("Expected %v but got %v", s.errs, errs)
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateIssuer(t *testing.T) {
 	baseIssuerConfig := cmapi.IssuerSpec{
 		IssuerConfig: cmapi.IssuerConfig{
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+// acmeCompatibility is responsible for warning about Certificates that
+// reference an ACME issuer but request features that ACME servers cannot
+// provide. Such Certificates are otherwise only reported by a failing Order,
+// or silently issued without the requested features.
+// Issuers that cannot be read at admission time are not checked.
+type acmeCompatibility struct {
+	cmClient cmclient.Interface
+}
+
+func newACMECompatibility() *acmeCompatibility {
+	return &acmeCompatibility{}
+}
+
+func (a *acmeCompatibility) Init(_ context.Context, _ kubernetes.Interface, cmClient cmclient.Interface, _ Options) {
+	a.cmClient = cmClient
+}
+
+// Validate will warn about the given Certificate if it references an ACME
+// issuer and requests a CA certificate, email SANs, subject fields other than
+// the common name, or a duration longer than ACME servers typically issue.
+// Updates are only checked if they change the spec, so that existing
+// Certificates do not warn on every update by the controller.
+func (a *acmeCompatibility) Validate(ctx context.Context, req *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) (*field.Error, validation.WarningList) {
+	if a.cmClient == nil || req.SubResource != "" {
+		return nil, nil
+	}
+
+	crt, ok := obj.(*internalcmapi.Certificate)
+	if !ok {
+		return nil, nil
+	}
+	switch req.Operation {
+	case admissionv1.Create:
+	case admissionv1.Update:
+		if oldCrt, ok := oldObj.(*internalcmapi.Certificate); ok && apiequality.Semantic.DeepEqual(oldCrt.Spec, crt.Spec) {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	warnings := acmeIncompatibilities(&crt.Spec)
+	if len(warnings) == 0 {
+		return nil, nil
+	}
+
+	issuerRef := crt.Spec.IssuerRef
+	if issuerRef.Group != "" && issuerRef.Group != certmanager.GroupName {
+		return nil, nil
+	}
+	issuer, err := getIssuer(ctx, a.cmClient, issuerRef, req.Namespace)
+	if err != nil || issuer.GetSpec().ACME == nil {
+		return nil, nil
+	}
+
+	return nil, warnings
+}
+
+// acmeIncompatibilities returns a warning for each field of the given spec
+// that an ACME issuer cannot honour.
+func acmeIncompatibilities(spec *internalcmapi.CertificateSpec) validation.WarningList {
+	fldPath := field.NewPath("spec")
+	var warnings validation.WarningList
+	warn := func(fldPath *field.Path, message string) {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", fldPath, message))
+	}
+
+	if spec.IsCA {
+		warn(fldPath.Child("isCA"), "ACME issuers cannot issue CA certificates")
+	}
+	if len(spec.EmailSANs) > 0 {
+		warn(fldPath.Child("emailSANs"), "ACME issuers cannot issue certificates with email address SANs")
+	}
+	if hasSubjectFields(spec.Subject) {
+		warn(fldPath.Child("subject"), "ACME issuers do not include subject fields other than the common name in issued certificates")
+	}
+	if spec.Duration != nil && spec.Duration.Duration > cmapi.DefaultCertificateDuration {
+		warn(fldPath.Child("duration"), fmt.Sprintf("ACME servers typically issue certificates valid for at most %s, so the requested duration of %s may not be honoured",
+			cmapi.DefaultCertificateDuration, spec.Duration.Duration))
+	}
+
+	return warnings
+}
+
+func hasSubjectFields(subject *internalcmapi.X509Subject) bool {
+	if subject == nil {
+		return false
+	}
+	return len(subject.Organizations) > 0 ||
+		len(subject.Countries) > 0 ||
+		len(subject.OrganizationalUnits) > 0 ||
+		len(subject.Localities) > 0 ||
+		len(subject.Provinces) > 0 ||
+		len(subject.StreetAddresses) > 0 ||
+		len(subject.PostalCodes) > 0 ||
+		len(subject.SerialNumber) > 0
+}
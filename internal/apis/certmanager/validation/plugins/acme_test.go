@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestACMECompatibilityValidate(t *testing.T) {
+	issuers := []runtime.Object{
+		gen.Issuer("acme", gen.SetIssuerNamespace("testns"), gen.SetIssuerACME(cmacme.ACMEIssuer{})),
+		gen.ClusterIssuer("acme", gen.SetIssuerACME(cmacme.ACMEIssuer{})),
+		gen.Issuer("selfsigned", gen.SetIssuerNamespace("testns"), gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{})),
+	}
+
+	certificate := func(issuerRef cmmeta.ObjectReference, mods ...func(*internalcmapi.CertificateSpec)) *internalcmapi.Certificate {
+		crt := &internalcmapi.Certificate{
+			Spec: internalcmapi.CertificateSpec{
+				DNSNames:  []string{"example.com"},
+				IssuerRef: issuerRef,
+			},
+		}
+		for _, mod := range mods {
+			mod(&crt.Spec)
+		}
+		return crt
+	}
+	isCA := func(spec *internalcmapi.CertificateSpec) { spec.IsCA = true }
+	incompatible := func(spec *internalcmapi.CertificateSpec) {
+		spec.IsCA = true
+		spec.EmailSANs = []string{"alice@example.com"}
+		spec.Subject = &internalcmapi.X509Subject{Organizations: []string{"example"}}
+		spec.Duration = &metav1.Duration{Duration: 365 * 24 * time.Hour}
+	}
+
+	const isCAWarning = "spec.isCA: ACME issuers cannot issue CA certificates"
+
+	tests := map[string]struct {
+		operation   admissionv1.Operation
+		subResource string
+		oldObj      runtime.Object
+		obj         runtime.Object
+		expWarnings validation.WarningList
+	}{
+		"should not warn about compatible Certificates for ACME issuers": {
+			operation: admissionv1.Create,
+			obj: certificate(cmmeta.ObjectReference{Name: "acme"}, func(spec *internalcmapi.CertificateSpec) {
+				spec.Subject = &internalcmapi.X509Subject{}
+				spec.Duration = &metav1.Duration{Duration: 90 * 24 * time.Hour}
+			}),
+		},
+		"should warn about every incompatible field for Issuers": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "acme"}, incompatible),
+			expWarnings: validation.WarningList{
+				isCAWarning,
+				"spec.emailSANs: ACME issuers cannot issue certificates with email address SANs",
+				"spec.subject: ACME issuers do not include subject fields other than the common name in issued certificates",
+				"spec.duration: ACME servers typically issue certificates valid for at most 2160h0m0s, so the requested duration of 8760h0m0s may not be honoured",
+			},
+		},
+		"should warn about incompatible Certificates for ClusterIssuers": {
+			operation:   admissionv1.Create,
+			obj:         certificate(cmmeta.ObjectReference{Name: "acme", Kind: "ClusterIssuer"}, isCA),
+			expWarnings: validation.WarningList{isCAWarning},
+		},
+		"should warn about updates that change the spec": {
+			operation:   admissionv1.Update,
+			oldObj:      certificate(cmmeta.ObjectReference{Name: "acme"}),
+			obj:         certificate(cmmeta.ObjectReference{Name: "acme"}, isCA),
+			expWarnings: validation.WarningList{isCAWarning},
+		},
+		"should not warn about updates that do not change the spec": {
+			operation: admissionv1.Update,
+			oldObj:    certificate(cmmeta.ObjectReference{Name: "acme"}, isCA),
+			obj:       certificate(cmmeta.ObjectReference{Name: "acme"}, isCA),
+		},
+		"should not warn about status updates": {
+			operation:   admissionv1.Update,
+			subResource: "status",
+			obj:         certificate(cmmeta.ObjectReference{Name: "acme"}, isCA),
+		},
+		"should not warn about Certificates for other issuer types": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "selfsigned"}, incompatible),
+		},
+		"should not warn if the issuer cannot be read": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "not-found"}, isCA),
+		},
+		"should not warn about Certificates for external issuers": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "acme", Group: "example.com"}, isCA),
+		},
+		"should not validate on delete": {
+			operation: admissionv1.Delete,
+			obj:       certificate(cmmeta.ObjectReference{Name: "acme"}, isCA),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newACMECompatibility()
+			a.Init(context.TODO(), nil, cmfake.NewSimpleClientset(issuers...), Options{})
+
+			err, warnings := a.Validate(context.TODO(), &admissionv1.AdmissionRequest{
+				Operation:   test.operation,
+				SubResource: test.subResource,
+				Namespace:   "testns",
+			}, test.oldObj, test.obj)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.expWarnings, warnings) {
+				t.Errorf("unexpected warnings, exp=%q got=%q", test.expWarnings, warnings)
+			}
+		})
+	}
+}
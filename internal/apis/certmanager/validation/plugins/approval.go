@@ -31,10 +31,12 @@ import (
 	"k8s.io/client-go/kubernetes"
 	authzclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
 
+	"github.com/jetstack/cert-manager/internal/api/validation"
 	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/internal/apis/certmanager/validation/util"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 )
 
 // approval is responsible for reviewing whether users attempting to approve or
@@ -52,9 +54,10 @@ type signerResource struct {
 	group      string
 	namespaced bool
 
-	// name of the object for this signer
-	signerName       string
-	requestNamespace string
+	// name and namespace of the object for this signer. The namespace is
+	// empty if the signer is not namespaced.
+	signerName      string
+	signerNamespace string
 }
 
 func newApproval(scheme *runtime.Scheme) *approval {
@@ -63,7 +66,7 @@ func newApproval(scheme *runtime.Scheme) *approval {
 	}
 }
 
-func (a *approval) Init(client kubernetes.Interface) {
+func (a *approval) Init(_ context.Context, client kubernetes.Interface, _ cmclient.Interface, _ Options) {
 	a.sarclient = client.AuthorizationV1().SubjectAccessReviews()
 	a.discoverclient = client.Discovery()
 }
@@ -74,20 +77,20 @@ func (a *approval) Init(client kubernetes.Interface) {
 // will be returned if the SubjectAccessReview fails, or if they do not have
 // permissions to perform the approval/denial. The request will also fail if
 // the referenced signer doesn't exist in this cluster.
-func (a *approval) Validate(ctx context.Context, req *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) *field.Error {
+func (a *approval) Validate(ctx context.Context, req *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) (*field.Error, validation.WarningList) {
 	// Only perform validation on UPDATE operations
 	if req.Operation != admissionv1.Update {
-		return nil
+		return nil, nil
 	}
 
 	// Only Validate over CertificateRequest resources
 	if req.RequestKind.Group != certmanager.GroupName || req.RequestKind.Kind != cmapi.CertificateRequestKind {
-		return nil
+		return nil, nil
 	}
 
 	// Error if the clients are not initialised
 	if a.sarclient == nil || a.discoverclient == nil {
-		return internalError(errors.New("approval validation not initialised"))
+		return internalError(errors.New("approval validation not initialised")), nil
 	}
 
 	gvk := schema.GroupVersionKind{
@@ -102,11 +105,11 @@ func (a *approval) Validate(ctx context.Context, req *admissionv1.AdmissionReque
 	for _, obj := range []runtime.Object{oldObj, obj} {
 		internalObj, err := a.scheme.New(gvk)
 		if err != nil {
-			return internalError(err)
+			return internalError(err), nil
 		}
 
 		if err := a.scheme.Convert(obj, internalObj, nil); err != nil {
-			return internalError(err)
+			return internalError(err), nil
 		}
 	}
 
@@ -115,17 +118,17 @@ func (a *approval) Validate(ctx context.Context, req *admissionv1.AdmissionReque
 
 	// If the request is not for approval, exit early
 	if !isApprovalRequest(oldCR, newCR) {
-		return nil
+		return nil, nil
 	}
 
 	// Get the referenced signer signer definition
 	signer, ok, err := a.signerResource(newCR)
 	if err != nil {
-		return internalError(err)
+		return internalError(err), nil
 	}
 	if !ok {
 		return field.Forbidden(field.NewPath("spec.issuerRef"),
-			fmt.Sprintf("referenced signer resource does not exist: %v", newCR.Spec.IssuerRef))
+			fmt.Sprintf("referenced signer resource does not exist: %s", issuerRefString(newCR))), nil
 	}
 
 	// Construct the signer resource names that permissions should be granted
@@ -136,12 +139,48 @@ func (a *approval) Validate(ctx context.Context, req *admissionv1.AdmissionReque
 	// given signer names
 	ok, err = a.reviewRequest(ctx, req, names)
 	if err != nil {
-		return internalError(err)
+		return internalError(err), nil
 	}
 
 	if !ok {
 		return field.Forbidden(field.NewPath("status.conditions"),
-			fmt.Sprintf("user %q does not have permissions to set approved/denied conditions for issuer %v", req.UserInfo.Username, newCR.Spec.IssuerRef))
+			fmt.Sprintf("user %q does not have permissions to set approved/denied conditions for issuer %s", req.UserInfo.Username, issuerRefString(newCR))), nil
+	}
+
+	return nil, nil
+}
+
+// AuditAnnotations returns annotations recording the decision made by a
+// request approving or denying a CertificateRequest, so that issuance
+// decisions can be traced in the Kubernetes audit log.
+func (a *approval) AuditAnnotations(req *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) map[string]string {
+	if req.Operation != admissionv1.Update {
+		return nil
+	}
+
+	oldCR, ok := oldObj.(*internalcmapi.CertificateRequest)
+	if !ok {
+		return nil
+	}
+	newCR, ok := obj.(*internalcmapi.CertificateRequest)
+	if !ok || !isApprovalRequest(oldCR, newCR) {
+		return nil
+	}
+
+	for _, condType := range []internalcmapi.CertificateRequestConditionType{internalcmapi.CertificateRequestConditionApproved, internalcmapi.CertificateRequestConditionDenied} {
+		if util.GetCertificateRequestCondition(oldCR.Status.Conditions, condType) != nil {
+			continue
+		}
+		cond := util.GetCertificateRequestCondition(newCR.Status.Conditions, condType)
+		if cond == nil {
+			continue
+		}
+
+		return map[string]string{
+			"certificaterequest-decision": string(condType),
+			"certificaterequest-reason":   cond.Reason,
+			"certificaterequest-issuer":   issuerRefString(newCR),
+		}
 	}
 
 	return nil
@@ -217,7 +256,7 @@ func (a *approval) signerResourceNames(signer *signerResource) []string {
 
 	named := fmt.Sprintf("%s.%s", signer.name, signer.group)
 	if signer.namespaced {
-		named = fmt.Sprintf("%s/%s.%s", named, signer.requestNamespace, signer.signerName)
+		named = fmt.Sprintf("%s/%s.%s", named, signer.signerNamespace, signer.signerName)
 	} else {
 		named = fmt.Sprintf("%s/%s", named, signer.signerName)
 	}
@@ -244,20 +283,19 @@ func (a *approval) signerResource(cr *internalcmapi.CertificateRequest) (*signer
 		switch kind {
 		case cmapi.IssuerKind:
 			return &signerResource{
-				name:             "issuers",
-				group:            group,
-				namespaced:       true,
-				signerName:       cr.Spec.IssuerRef.Name,
-				requestNamespace: cr.Namespace,
+				name:            "issuers",
+				group:           group,
+				namespaced:      true,
+				signerName:      cr.Spec.IssuerRef.Name,
+				signerNamespace: issuerNamespace(cr),
 			}, true, nil
 
 		case cmapi.ClusterIssuerKind:
 			return &signerResource{
-				name:             "clusterissuers",
-				group:            group,
-				namespaced:       false,
-				signerName:       cr.Spec.IssuerRef.Name,
-				requestNamespace: cr.Namespace,
+				name:       "clusterissuers",
+				group:      group,
+				namespaced: false,
+				signerName: cr.Spec.IssuerRef.Name,
 			}, true, nil
 		}
 	}
@@ -280,13 +318,16 @@ func (a *approval) signerResource(cr *internalcmapi.CertificateRequest) (*signer
 
 			for _, resource := range resources.APIResources {
 				if resource.Kind == kind {
-					return &signerResource{
-						name:             resource.Name,
-						group:            group,
-						namespaced:       resource.Namespaced,
-						requestNamespace: cr.Namespace,
-						signerName:       cr.Spec.IssuerRef.Name,
-					}, true, nil
+					signer := &signerResource{
+						name:       resource.Name,
+						group:      group,
+						namespaced: resource.Namespaced,
+						signerName: cr.Spec.IssuerRef.Name,
+					}
+					if resource.Namespaced {
+						signer.signerNamespace = issuerNamespace(cr)
+					}
+					return signer, true, nil
 				}
 			}
 		}
@@ -295,6 +336,27 @@ func (a *approval) signerResource(cr *internalcmapi.CertificateRequest) (*signer
 	return nil, false, nil
 }
 
+// issuerNamespace returns the namespace of the namespaced issuer referenced by
+// the CertificateRequest, which is the namespace of the CertificateRequest
+// unless the issuerRef references an issuer in another namespace.
+func issuerNamespace(cr *internalcmapi.CertificateRequest) string {
+	if len(cr.Spec.IssuerRef.Namespace) > 0 {
+		return cr.Spec.IssuerRef.Namespace
+	}
+	return cr.Namespace
+}
+
+// issuerRefString returns the issuer referenced by the CertificateRequest in
+// the form "<kind>.<group>/[<namespace>/]<name>", where the namespace is only
+// included if it was set on the issuerRef.
+func issuerRefString(cr *internalcmapi.CertificateRequest) string {
+	ref := cr.Spec.IssuerRef
+	if len(ref.Namespace) > 0 {
+		return fmt.Sprintf("%s.%s/%s/%s", ref.Kind, ref.Group, ref.Namespace, ref.Name)
+	}
+	return fmt.Sprintf("%s.%s/%s", ref.Kind, ref.Group, ref.Name)
+}
+
 func internalError(err error) *field.Error {
 	return field.InternalError(field.NewPath("status.conditions"), err)
 }
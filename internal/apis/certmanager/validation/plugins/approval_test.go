@@ -159,7 +159,7 @@ func TestValidate(t *testing.T) {
 					WithServerResourcesForGroupVersion(expNoServerResourcesForGroupVersion(t))
 			},
 			expErr: field.Forbidden(field.NewPath("spec.issuerRef"),
-				"referenced signer resource does not exist: {my-issuer Issuer example.io}"),
+				"referenced signer resource does not exist: Issuer.example.io/my-issuer"),
 		},
 		"if the CertificateRequest references a signer that the approver doesn't have permissions for, error": {
 			req: &admissionv1.AdmissionRequest{
@@ -213,7 +213,7 @@ func TestValidate(t *testing.T) {
 				}
 			},
 			expErr: field.Forbidden(field.NewPath("status.conditions"),
-				`user "user-1" does not have permissions to set approved/denied conditions for issuer {my-issuer Issuer example.io}`),
+				`user "user-1" does not have permissions to set approved/denied conditions for issuer Issuer.example.io/my-issuer`),
 		},
 		"if the CertificateRequest references a signer that the approver has permissions for, return nil": {
 			req: &admissionv1.AdmissionRequest{
@@ -281,7 +281,7 @@ func TestValidate(t *testing.T) {
 				discoverclient: test.discoverclient(t),
 			}
 
-			err := a.Validate(context.TODO(), test.req, test.oldCR, test.newCR)
+			err, _ := a.Validate(context.TODO(), test.req, test.oldCR, test.newCR)
 			if !reflect.DeepEqual(test.expErr, err) {
 				t.Errorf("unexpected error, exp=%#+v got=%#+v",
 					test.expErr, err)
@@ -542,11 +542,11 @@ func TestSignerResource(t *testing.T) {
 			},
 			client: expNoDiscovery,
 			expSigner: &signerResource{
-				name:             "issuers",
-				group:            "cert-manager.io",
-				namespaced:       true,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:            "issuers",
+				group:           "cert-manager.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "test-ns",
 			},
 			expOK:  true,
 			expErr: false,
@@ -565,11 +565,11 @@ func TestSignerResource(t *testing.T) {
 			},
 			client: expNoDiscovery,
 			expSigner: &signerResource{
-				name:             "issuers",
-				group:            "cert-manager.io",
-				namespaced:       true,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:            "issuers",
+				group:           "cert-manager.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "test-ns",
 			},
 			expOK:  true,
 			expErr: false,
@@ -588,11 +588,11 @@ func TestSignerResource(t *testing.T) {
 			},
 			client: expNoDiscovery,
 			expSigner: &signerResource{
-				name:             "issuers",
-				group:            "cert-manager.io",
-				namespaced:       true,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:            "issuers",
+				group:           "cert-manager.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "test-ns",
 			},
 			expOK:  true,
 			expErr: false,
@@ -612,11 +612,36 @@ func TestSignerResource(t *testing.T) {
 			},
 			client: expNoDiscovery,
 			expSigner: &signerResource{
-				name:             "issuers",
-				group:            "cert-manager.io",
-				namespaced:       true,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:            "issuers",
+				group:           "cert-manager.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "test-ns",
+			},
+			expOK:  true,
+			expErr: false,
+		},
+		"if Issuer in another namespace, return internal signer resource in the namespace of the Issuer": {
+			request: &internalcmapi.CertificateRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-ns",
+				},
+				Spec: internalcmapi.CertificateRequestSpec{
+					IssuerRef: internalcmmeta.ObjectReference{
+						Group:     "cert-manager.io",
+						Kind:      "Issuer",
+						Name:      "my-issuer",
+						Namespace: "issuer-ns",
+					},
+				},
+			},
+			client: expNoDiscovery,
+			expSigner: &signerResource{
+				name:            "issuers",
+				group:           "cert-manager.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "issuer-ns",
 			},
 			expOK:  true,
 			expErr: false,
@@ -635,11 +660,10 @@ func TestSignerResource(t *testing.T) {
 			},
 			client: expNoDiscovery,
 			expSigner: &signerResource{
-				name:             "clusterissuers",
-				group:            "cert-manager.io",
-				namespaced:       false,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:       "clusterissuers",
+				group:      "cert-manager.io",
+				namespaced: false,
+				signerName: "my-issuer",
 			},
 			expOK:  true,
 			expErr: false,
@@ -658,11 +682,10 @@ func TestSignerResource(t *testing.T) {
 				},
 			},
 			expSigner: &signerResource{
-				name:             "clusterissuers",
-				group:            "cert-manager.io",
-				namespaced:       false,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:       "clusterissuers",
+				group:      "cert-manager.io",
+				namespaced: false,
+				signerName: "my-issuer",
 			},
 			client: expNoDiscovery,
 			expOK:  true,
@@ -856,11 +879,11 @@ func TestSignerResource(t *testing.T) {
 					})
 			},
 			expSigner: &signerResource{
-				name:             "issuers",
-				group:            "example.io",
-				namespaced:       true,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:            "issuers",
+				group:           "example.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "test-ns",
 			},
 			expOK:  true,
 			expErr: false,
@@ -912,11 +935,10 @@ func TestSignerResource(t *testing.T) {
 					})
 			},
 			expSigner: &signerResource{
-				name:             "issuers",
-				group:            "example.io",
-				namespaced:       false,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:       "issuers",
+				group:      "example.io",
+				namespaced: false,
+				signerName: "my-issuer",
 			},
 			expOK:  true,
 			expErr: false,
@@ -955,11 +977,11 @@ func TestSignerResourceNames(t *testing.T) {
 	}{
 		"if namespaced, should return a wildcard and namespaced signer name": {
 			signer: &signerResource{
-				name:             "exampleissuers",
-				group:            "my-group.io",
-				namespaced:       true,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:            "exampleissuers",
+				group:           "my-group.io",
+				namespaced:      true,
+				signerName:      "my-issuer",
+				signerNamespace: "test-ns",
 			},
 			expNames: []string{
 				"exampleissuers.my-group.io/*",
@@ -968,11 +990,10 @@ func TestSignerResourceNames(t *testing.T) {
 		},
 		"if cluster scoped, should return a wildcard and non namespaced signer name": {
 			signer: &signerResource{
-				name:             "exampleissuers",
-				group:            "my-group.io",
-				namespaced:       false,
-				signerName:       "my-issuer",
-				requestNamespace: "test-ns",
+				name:       "exampleissuers",
+				group:      "my-group.io",
+				namespaced: false,
+				signerName: "my-issuer",
 			},
 			expNames: []string{
 				"exampleissuers.my-group.io/*",
@@ -991,3 +1012,80 @@ func TestSignerResourceNames(t *testing.T) {
 		})
 	}
 }
+
+func TestAuditAnnotations(t *testing.T) {
+	cr := func(conditions ...internalcmapi.CertificateRequestCondition) *internalcmapi.CertificateRequest {
+		return &internalcmapi.CertificateRequest{
+			Spec: internalcmapi.CertificateRequestSpec{
+				IssuerRef: internalcmmeta.ObjectReference{
+					Name:  "my-issuer",
+					Kind:  "Issuer",
+					Group: "cert-manager.io",
+				},
+			},
+			Status: internalcmapi.CertificateRequestStatus{Conditions: conditions},
+		}
+	}
+	approved := internalcmapi.CertificateRequestCondition{
+		Type:   internalcmapi.CertificateRequestConditionApproved,
+		Status: internalcmmeta.ConditionTrue,
+		Reason: "cert-manager.io",
+	}
+	denied := internalcmapi.CertificateRequestCondition{
+		Type:   internalcmapi.CertificateRequestConditionDenied,
+		Status: internalcmmeta.ConditionTrue,
+		Reason: "policy.cert-manager.io",
+	}
+
+	tests := map[string]struct {
+		operation      admissionv1.Operation
+		oldCR, newCR   *internalcmapi.CertificateRequest
+		expAnnotations map[string]string
+	}{
+		"should not annotate requests that do not approve or deny": {
+			operation: admissionv1.Update,
+			oldCR:     cr(),
+			newCR:     cr(),
+		},
+		"should not annotate requests that do not change an existing decision": {
+			operation: admissionv1.Update,
+			oldCR:     cr(approved),
+			newCR:     cr(approved),
+		},
+		"should not annotate create requests": {
+			operation: admissionv1.Create,
+			oldCR:     cr(),
+			newCR:     cr(approved),
+		},
+		"should annotate requests that approve": {
+			operation: admissionv1.Update,
+			oldCR:     cr(),
+			newCR:     cr(approved),
+			expAnnotations: map[string]string{
+				"certificaterequest-decision": "Approved",
+				"certificaterequest-reason":   "cert-manager.io",
+				"certificaterequest-issuer":   "Issuer.cert-manager.io/my-issuer",
+			},
+		},
+		"should annotate requests that deny": {
+			operation: admissionv1.Update,
+			oldCR:     cr(),
+			newCR:     cr(denied),
+			expAnnotations: map[string]string{
+				"certificaterequest-decision": "Denied",
+				"certificaterequest-reason":   "policy.cert-manager.io",
+				"certificaterequest-issuer":   "Issuer.cert-manager.io/my-issuer",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newApproval(webhook.Scheme)
+			annotations := a.AuditAnnotations(&admissionv1.AdmissionRequest{Operation: test.operation}, test.oldCR, test.newCR)
+			if !reflect.DeepEqual(test.expAnnotations, annotations) {
+				t.Errorf("unexpected audit annotations, exp=%v got=%v", test.expAnnotations, annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+// issuerTypes is responsible for rejecting Issuers and ClusterIssuers whose
+// type is not allowed by the cluster's issuer type policy. Existing issuers
+// are also marked as not Ready by the issuer controllers.
+type issuerTypes struct {
+	policy apiutil.IssuerTypePolicy
+}
+
+func newIssuerTypes() *issuerTypes {
+	return &issuerTypes{}
+}
+
+func (i *issuerTypes) Init(_ context.Context, _ kubernetes.Interface, _ cmclient.Interface, opts Options) {
+	i.policy = opts.IssuerTypes
+}
+
+// Validate will reject the given Issuer or ClusterIssuer if its type is not
+// allowed. Updates to the status subresource are always allowed, so that the
+// controller can report the issuer as not Ready.
+func (i *issuerTypes) Validate(_ context.Context, req *admissionv1.AdmissionRequest, _, obj runtime.Object) (*field.Error, validation.WarningList) {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return nil, nil
+	}
+	if req.SubResource != "" {
+		return nil, nil
+	}
+
+	var spec internalcmapi.IssuerConfig
+	switch o := obj.(type) {
+	case *internalcmapi.Issuer:
+		spec = o.Spec.IssuerConfig
+	case *internalcmapi.ClusterIssuer:
+		spec = o.Spec.IssuerConfig
+	default:
+		return nil, nil
+	}
+
+	issuerType, fldPath := issuerTypeFor(spec)
+	if issuerType == "" || i.policy.Allows(issuerType) {
+		return nil, nil
+	}
+
+	return field.Forbidden(fldPath, fmt.Sprintf("issuer type %q is not allowed in this cluster", issuerType)), nil
+}
+
+// issuerTypeFor returns the name of the type of the given issuer, matching
+// apiutil.NameForIssuer, and the path of the field that configures it.
+func issuerTypeFor(spec internalcmapi.IssuerConfig) (string, *field.Path) {
+	fldPath := field.NewPath("spec")
+	switch {
+	case spec.ACME != nil:
+		return apiutil.IssuerACME, fldPath.Child("acme")
+	case spec.CA != nil:
+		return apiutil.IssuerCA, fldPath.Child("ca")
+	case spec.Vault != nil:
+		return apiutil.IssuerVault, fldPath.Child("vault")
+	case spec.SelfSigned != nil:
+		return apiutil.IssuerSelfSigned, fldPath.Child("selfSigned")
+	case spec.Venafi != nil:
+		return apiutil.IssuerVenafi, fldPath.Child("venafi")
+	}
+	return "", fldPath
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+)
+
+func TestIssuerTypesValidate(t *testing.T) {
+	selfSignedIssuer := &internalcmapi.Issuer{
+		Spec: internalcmapi.IssuerSpec{IssuerConfig: internalcmapi.IssuerConfig{
+			SelfSigned: &internalcmapi.SelfSignedIssuer{},
+		}},
+	}
+	selfSignedClusterIssuer := &internalcmapi.ClusterIssuer{
+		Spec: internalcmapi.IssuerSpec{IssuerConfig: internalcmapi.IssuerConfig{
+			SelfSigned: &internalcmapi.SelfSignedIssuer{},
+		}},
+	}
+	caIssuer := &internalcmapi.Issuer{
+		Spec: internalcmapi.IssuerSpec{IssuerConfig: internalcmapi.IssuerConfig{
+			CA: &internalcmapi.CAIssuer{SecretName: "ca"},
+		}},
+	}
+
+	tests := map[string]struct {
+		policy      apiutil.IssuerTypePolicy
+		operation   admissionv1.Operation
+		subResource string
+		obj         runtime.Object
+		expErr      *field.Error
+	}{
+		"should allow all issuers with an empty policy": {
+			operation: admissionv1.Create,
+			obj:       selfSignedIssuer,
+		},
+		"should reject Issuers of a denied type": {
+			policy:    apiutil.IssuerTypePolicy{Denied: []string{apiutil.IssuerSelfSigned}},
+			operation: admissionv1.Create,
+			obj:       selfSignedIssuer,
+			expErr:    field.Forbidden(field.NewPath("spec", "selfSigned"), `issuer type "selfsigned" is not allowed in this cluster`),
+		},
+		"should reject ClusterIssuers of a denied type on update": {
+			policy:    apiutil.IssuerTypePolicy{Denied: []string{apiutil.IssuerSelfSigned}},
+			operation: admissionv1.Update,
+			obj:       selfSignedClusterIssuer,
+			expErr:    field.Forbidden(field.NewPath("spec", "selfSigned"), `issuer type "selfsigned" is not allowed in this cluster`),
+		},
+		"should reject Issuers of a type that is not allowed": {
+			policy:    apiutil.IssuerTypePolicy{Allowed: []string{apiutil.IssuerACME}},
+			operation: admissionv1.Create,
+			obj:       caIssuer,
+			expErr:    field.Forbidden(field.NewPath("spec", "ca"), `issuer type "ca" is not allowed in this cluster`),
+		},
+		"should allow Issuers of an allowed type": {
+			policy:    apiutil.IssuerTypePolicy{Allowed: []string{apiutil.IssuerCA}},
+			operation: admissionv1.Create,
+			obj:       caIssuer,
+		},
+		"should allow status updates of Issuers of a denied type": {
+			policy:      apiutil.IssuerTypePolicy{Denied: []string{apiutil.IssuerSelfSigned}},
+			operation:   admissionv1.Update,
+			subResource: "status",
+			obj:         selfSignedIssuer,
+		},
+		"should not validate on delete": {
+			policy:    apiutil.IssuerTypePolicy{Denied: []string{apiutil.IssuerSelfSigned}},
+			operation: admissionv1.Delete,
+			obj:       selfSignedIssuer,
+		},
+		"should ignore other resources": {
+			policy:    apiutil.IssuerTypePolicy{Allowed: []string{apiutil.IssuerACME}},
+			operation: admissionv1.Create,
+			obj:       &internalcmapi.Certificate{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			i := newIssuerTypes()
+			i.Init(context.TODO(), nil, nil, Options{IssuerTypes: test.policy})
+
+			err, _ := i.Validate(context.TODO(), &admissionv1.AdmissionRequest{
+				Operation:   test.operation,
+				SubResource: test.subResource,
+			}, nil, test.obj)
+			if test.expErr == nil && err != nil || test.expErr != nil && (err == nil || err.Error() != test.expErr.Error()) {
+				t.Errorf("unexpected error, exp=%v got=%v", test.expErr, err)
+			}
+		})
+	}
+}
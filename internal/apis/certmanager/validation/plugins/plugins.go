@@ -18,21 +18,92 @@ package plugins
 
 import (
 	"context"
+	"fmt"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 )
 
+// SecretNameCollisionPolicy determines how Certificates whose secretName is
+// already used by another Certificate in the same namespace are admitted.
+type SecretNameCollisionPolicy string
+
+const (
+	// SecretNameCollisionIgnore admits colliding Certificates without
+	// checking for collisions.
+	SecretNameCollisionIgnore SecretNameCollisionPolicy = "Ignore"
+
+	// SecretNameCollisionWarn admits colliding Certificates, returning an
+	// admission warning to the client.
+	SecretNameCollisionWarn SecretNameCollisionPolicy = "Warn"
+
+	// SecretNameCollisionDeny rejects colliding Certificates.
+	SecretNameCollisionDeny SecretNameCollisionPolicy = "Deny"
+)
+
+// Options configures the optional behaviour of admission plugins.
+type Options struct {
+	// SecretNameCollisions determines how Certificates whose secretName
+	// collides with that of another Certificate are admitted.
+	// Defaults to SecretNameCollisionIgnore.
+	SecretNameCollisions SecretNameCollisionPolicy
+
+	// IssuerTypes restricts the types of Issuers and ClusterIssuers that may
+	// be created. All types are allowed by default.
+	IssuerTypes apiutil.IssuerTypePolicy
+}
+
 // Plugin is an admission plugin that will run during admission webhook events.
+// Plugins may start informers in Init, which are stopped when ctx is done.
 type Plugin interface {
-	Init(client kubernetes.Interface)
-	Validate(ctx context.Context, admissionSpec *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) *field.Error
+	Init(ctx context.Context, client kubernetes.Interface, cmClient cmclient.Interface, opts Options)
+	Validate(ctx context.Context, admissionSpec *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) (*field.Error, validation.WarningList)
+}
+
+// AuditAnnotator is implemented by plugins which annotate the audit events of
+// the admission requests they allow.
+type AuditAnnotator interface {
+	AuditAnnotations(admissionSpec *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) map[string]string
 }
 
 func All(scheme *runtime.Scheme) []Plugin {
 	return []Plugin{
 		newApproval(scheme),
+		newWildcards(),
+		newSecretNameCollisions(),
+		newIssuerTypes(),
+		newACMECompatibility(),
+	}
+}
+
+// getIssuer returns the Issuer or ClusterIssuer referenced by ref, with its
+// kind set.
+func getIssuer(ctx context.Context, cmClient cmclient.Interface, ref cmmeta.ObjectReference, namespace string) (cmapi.GenericIssuer, error) {
+	switch ref.Kind {
+	case "", cmapi.IssuerKind:
+		issuer, err := cmClient.CertmanagerV1().Issuers(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		issuer.SetGroupVersionKind(cmapi.SchemeGroupVersion.WithKind(cmapi.IssuerKind))
+		return issuer, nil
+	case cmapi.ClusterIssuerKind:
+		issuer, err := cmClient.CertmanagerV1().ClusterIssuers().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		issuer.SetGroupVersionKind(cmapi.SchemeGroupVersion.WithKind(cmapi.ClusterIssuerKind))
+		return issuer, nil
+	default:
+		return nil, fmt.Errorf("unknown issuer kind %q", ref.Kind)
 	}
 }
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+)
+
+// secretNameIndex indexes Certificates by "<namespace>/<secretName>".
+const secretNameIndex = "secretName"
+
+// secretNameCollisions is responsible for warning about, or rejecting,
+// Certificates whose secretName is already used by another Certificate in the
+// same namespace. Such Certificates repeatedly overwrite each other's Secret.
+// Certificates are read from an informer, so collisions with Certificates
+// that have not yet been observed by the webhook are not detected.
+type secretNameCollisions struct {
+	policy SecretNameCollisionPolicy
+
+	indexer   cache.Indexer
+	hasSynced cache.InformerSynced
+}
+
+func newSecretNameCollisions() *secretNameCollisions {
+	return &secretNameCollisions{}
+}
+
+// Init starts an informer indexing Certificates by their secretName, unless
+// the configured policy is to ignore collisions.
+func (s *secretNameCollisions) Init(ctx context.Context, _ kubernetes.Interface, cmClient cmclient.Interface, opts Options) {
+	s.policy = opts.SecretNameCollisions
+	if s.policy == "" || s.policy == SecretNameCollisionIgnore || cmClient == nil {
+		return
+	}
+
+	factory := cminformers.NewSharedInformerFactory(cmClient, 0)
+	informer := factory.Certmanager().V1().Certificates().Informer()
+	// AddIndexers only errors if the informer has already been started.
+	utilruntime.Must(informer.AddIndexers(cache.Indexers{secretNameIndex: certificateSecretNameIndexFunc}))
+	s.indexer = informer.GetIndexer()
+	s.hasSynced = informer.HasSynced
+
+	factory.Start(ctx.Done())
+}
+
+// Validate will warn about, or reject, a Certificate whose secretName is
+// used by another Certificate in the same namespace. Updates are only
+// checked if they change the secretName, so that existing collisions do not
+// block unrelated updates.
+func (s *secretNameCollisions) Validate(_ context.Context, req *admissionv1.AdmissionRequest, oldObj, obj runtime.Object) (*field.Error, validation.WarningList) {
+	if s.indexer == nil || !s.hasSynced() {
+		return nil, nil
+	}
+
+	crt, ok := obj.(*internalcmapi.Certificate)
+	if !ok {
+		return nil, nil
+	}
+	switch req.Operation {
+	case admissionv1.Create:
+	case admissionv1.Update:
+		if oldCrt, ok := oldObj.(*internalcmapi.Certificate); ok && oldCrt.Spec.SecretName == crt.Spec.SecretName {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	objs, err := s.indexer.ByIndex(secretNameIndex, req.Namespace+"/"+crt.Spec.SecretName)
+	if err != nil {
+		return nil, nil
+	}
+
+	var others []string
+	for _, obj := range objs {
+		other := obj.(*cmapi.Certificate)
+		if other.Name == req.Name {
+			continue
+		}
+		others = append(others, fmt.Sprintf("%q", other.Name))
+	}
+	if len(others) == 0 {
+		return nil, nil
+	}
+	sort.Strings(others)
+
+	fldPath := field.NewPath("spec", "secretName")
+	message := fmt.Sprintf("secretName %q is already used by Certificate %s in namespace %q; Certificates sharing a Secret will repeatedly overwrite it",
+		crt.Spec.SecretName, strings.Join(others, ", "), req.Namespace)
+	if s.policy == SecretNameCollisionDeny {
+		return field.Forbidden(fldPath, message), nil
+	}
+
+	return nil, validation.WarningList{fmt.Sprintf("%s: %s", fldPath, message)}
+}
+
+func certificateSecretNameIndexFunc(obj interface{}) ([]string, error) {
+	crt, ok := obj.(*cmapi.Certificate)
+	if !ok {
+		return nil, nil
+	}
+	return []string{crt.Namespace + "/" + crt.Spec.SecretName}, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestSecretNameCollisionsValidate(t *testing.T) {
+	existing := []runtime.Object{
+		gen.Certificate("existing-1", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("shared")),
+		gen.Certificate("existing-2", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("shared")),
+		gen.Certificate("other-namespace", gen.SetCertificateNamespace("otherns"), gen.SetCertificateSecretName("unique")),
+	}
+
+	certificate := func(secretName string) *internalcmapi.Certificate {
+		return &internalcmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "testns"},
+			Spec:       internalcmapi.CertificateSpec{SecretName: secretName},
+		}
+	}
+
+	const collisionMessage = `secretName "shared" is already used by Certificate "existing-1", "existing-2" in namespace "testns"; Certificates sharing a Secret will repeatedly overwrite it`
+
+	tests := map[string]struct {
+		policy      SecretNameCollisionPolicy
+		operation   admissionv1.Operation
+		name        string
+		oldObj      runtime.Object
+		obj         runtime.Object
+		expErr      *field.Error
+		expWarnings validation.WarningList
+	}{
+		"should not check collisions if the policy is Ignore": {
+			policy:    SecretNameCollisionIgnore,
+			operation: admissionv1.Create,
+			obj:       certificate("shared"),
+		},
+		"should allow Certificates with a unique secretName": {
+			policy:    SecretNameCollisionDeny,
+			operation: admissionv1.Create,
+			obj:       certificate("unique"),
+		},
+		"should warn about colliding Certificates if the policy is Warn": {
+			policy:      SecretNameCollisionWarn,
+			operation:   admissionv1.Create,
+			obj:         certificate("shared"),
+			expWarnings: validation.WarningList{"spec.secretName: " + collisionMessage},
+		},
+		"should reject colliding Certificates if the policy is Deny": {
+			policy:    SecretNameCollisionDeny,
+			operation: admissionv1.Create,
+			obj:       certificate("shared"),
+			expErr:    field.Forbidden(field.NewPath("spec", "secretName"), collisionMessage),
+		},
+		"should reject updates changing the secretName to a colliding one": {
+			policy:    SecretNameCollisionDeny,
+			operation: admissionv1.Update,
+			oldObj:    certificate("unique"),
+			obj:       certificate("shared"),
+			expErr:    field.Forbidden(field.NewPath("spec", "secretName"), collisionMessage),
+		},
+		"should allow updates that do not change a colliding secretName": {
+			policy:    SecretNameCollisionDeny,
+			operation: admissionv1.Update,
+			oldObj:    certificate("shared"),
+			obj:       certificate("shared"),
+		},
+		"should not report a Certificate as colliding with itself": {
+			policy:      SecretNameCollisionWarn,
+			operation:   admissionv1.Update,
+			name:        "existing-1",
+			oldObj:      certificate("other"),
+			obj:         certificate("shared"),
+			expWarnings: validation.WarningList{`spec.secretName: secretName "shared" is already used by Certificate "existing-2" in namespace "testns"; Certificates sharing a Secret will repeatedly overwrite it`},
+		},
+		"should not validate on delete": {
+			policy:    SecretNameCollisionDeny,
+			operation: admissionv1.Delete,
+			obj:       certificate("shared"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			s := newSecretNameCollisions()
+			s.Init(ctx, nil, cmfake.NewSimpleClientset(existing...), Options{SecretNameCollisions: test.policy})
+			if s.hasSynced != nil && !cache.WaitForCacheSync(ctx.Done(), s.hasSynced) {
+				t.Fatal("timed out waiting for informer to sync")
+			}
+
+			reqName := test.name
+			if reqName == "" {
+				reqName = "new"
+			}
+			err, warnings := s.Validate(ctx, &admissionv1.AdmissionRequest{
+				Operation: test.operation,
+				Namespace: "testns",
+				Name:      reqName,
+			}, test.oldObj, test.obj)
+			if !reflect.DeepEqual(test.expErr, err) {
+				t.Errorf("unexpected error, exp=%v got=%v", test.expErr, err)
+			}
+			if !reflect.DeepEqual(test.expWarnings, warnings) {
+				t.Errorf("unexpected warnings, exp=%q got=%q", test.expWarnings, warnings)
+			}
+		})
+	}
+}
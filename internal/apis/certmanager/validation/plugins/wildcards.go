@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// wildcards is responsible for rejecting Certificates and CertificateRequests
+// that request wildcard DNS names from an issuer that does not allow them.
+// Issuers that cannot be read at admission time are not checked, as the
+// policy is also enforced when the request is signed.
+type wildcards struct {
+	cmClient cmclient.Interface
+}
+
+func newWildcards() *wildcards {
+	return &wildcards{}
+}
+
+func (w *wildcards) Init(_ context.Context, _ kubernetes.Interface, cmClient cmclient.Interface, _ Options) {
+	w.cmClient = cmClient
+}
+
+// Validate will reject the given Certificate or CertificateRequest if it
+// contains a wildcard DNS name and the referenced issuer has allowWildcards
+// set to false.
+func (w *wildcards) Validate(ctx context.Context, req *admissionv1.AdmissionRequest, _, obj runtime.Object) (*field.Error, validation.WarningList) {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return nil, nil
+	}
+	if w.cmClient == nil {
+		return nil, nil
+	}
+
+	var issuerRef cmmeta.ObjectReference
+	var names []string
+	var fldPath *field.Path
+	switch o := obj.(type) {
+	case *internalcmapi.Certificate:
+		issuerRef = o.Spec.IssuerRef
+		names = append([]string{o.Spec.CommonName}, o.Spec.DNSNames...)
+		fldPath = field.NewPath("spec", "dnsNames")
+	case *internalcmapi.CertificateRequest:
+		csr, err := pki.DecodeX509CertificateRequestBytes(o.Spec.Request)
+		if err != nil {
+			// Invalid requests are rejected by the CertificateRequest validation.
+			return nil, nil
+		}
+		issuerRef = o.Spec.IssuerRef
+		names = append([]string{csr.Subject.CommonName}, csr.DNSNames...)
+		fldPath = field.NewPath("spec", "request")
+	default:
+		return nil, nil
+	}
+
+	wildcardNames := apiutil.WildcardDNSNames(names)
+	if len(wildcardNames) == 0 {
+		return nil, nil
+	}
+	if issuerRef.Group != "" && issuerRef.Group != certmanager.GroupName {
+		return nil, nil
+	}
+
+	issuer, err := getIssuer(ctx, w.cmClient, issuerRef, req.Namespace)
+	if err != nil || apiutil.IssuerAllowsWildcards(issuer) {
+		return nil, nil
+	}
+
+	return field.Forbidden(fldPath, fmt.Sprintf("wildcard DNS names are not allowed by %s %q: %s",
+		issuer.GetObjectKind().GroupVersionKind().Kind, issuerRef.Name, strings.Join(wildcardNames, ", "))), nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	internalcmapi "github.com/jetstack/cert-manager/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/internal/apis/meta"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestWildcardsValidate(t *testing.T) {
+	csrWildcard, _, err := gen.CSR(x509.RSA, gen.SetCSRDNSNames("*.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuers := []runtime.Object{
+		gen.Issuer("allow", gen.SetIssuerNamespace("testns")),
+		gen.Issuer("deny", gen.SetIssuerNamespace("testns"), gen.SetIssuerAllowWildcards(false)),
+		gen.ClusterIssuer("deny", gen.SetIssuerAllowWildcards(false)),
+	}
+
+	certificate := func(issuerRef cmmeta.ObjectReference, dnsNames ...string) *internalcmapi.Certificate {
+		return &internalcmapi.Certificate{
+			Spec: internalcmapi.CertificateSpec{
+				DNSNames:  dnsNames,
+				IssuerRef: issuerRef,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		operation admissionv1.Operation
+		obj       runtime.Object
+		expErr    *field.Error
+	}{
+		"should allow wildcard Certificates for issuers that allow wildcards": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "allow"}, "*.example.com"),
+		},
+		"should allow Certificates without wildcards for issuers that do not allow wildcards": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "deny"}, "example.com"),
+		},
+		"should reject wildcard Certificates for Issuers that do not allow wildcards": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "deny"}, "example.com", "*.example.com"),
+			expErr:    field.Forbidden(field.NewPath("spec", "dnsNames"), `wildcard DNS names are not allowed by Issuer "deny": *.example.com`),
+		},
+		"should reject wildcard Certificates for ClusterIssuers that do not allow wildcards on update": {
+			operation: admissionv1.Update,
+			obj:       certificate(cmmeta.ObjectReference{Name: "deny", Kind: "ClusterIssuer"}, "*.example.com"),
+			expErr:    field.Forbidden(field.NewPath("spec", "dnsNames"), `wildcard DNS names are not allowed by ClusterIssuer "deny": *.example.com`),
+		},
+		"should allow wildcard Certificates if the issuer cannot be read": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "not-found"}, "*.example.com"),
+		},
+		"should allow wildcard Certificates for external issuers": {
+			operation: admissionv1.Create,
+			obj:       certificate(cmmeta.ObjectReference{Name: "deny", Group: "example.com"}, "*.example.com"),
+		},
+		"should reject wildcard CertificateRequests for Issuers that do not allow wildcards": {
+			operation: admissionv1.Create,
+			obj: &internalcmapi.CertificateRequest{
+				Spec: internalcmapi.CertificateRequestSpec{
+					Request:   csrWildcard,
+					IssuerRef: cmmeta.ObjectReference{Name: "deny"},
+				},
+			},
+			expErr: field.Forbidden(field.NewPath("spec", "request"), `wildcard DNS names are not allowed by Issuer "deny": *.example.com`),
+		},
+		"should not validate on delete": {
+			operation: admissionv1.Delete,
+			obj:       certificate(cmmeta.ObjectReference{Name: "deny"}, "*.example.com"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := newWildcards()
+			w.Init(context.TODO(), nil, cmfake.NewSimpleClientset(issuers...), Options{})
+
+			err, _ := w.Validate(context.TODO(), &admissionv1.AdmissionRequest{
+				Operation: test.operation,
+				Namespace: "testns",
+			}, nil, test.obj)
+			if test.expErr == nil && err != nil || test.expErr != nil && (err == nil || err.Error() != test.expErr.Error()) {
+				t.Errorf("unexpected error, exp=%v got=%v", test.expErr, err)
+			}
+		})
+	}
+}
@@ -21,4 +21,6 @@ package validation
 const (
 	// deprecatedACMEEABKeyAlgorithmField is raised when the deprecated keyAlgorithm field for an ACME issuer's external account binding (EAB) is set.
 	deprecatedACMEEABKeyAlgorithmField = "ACME issuer spec field 'externalAccount.keyAlgorithm' is deprecated. The value of this field will be ignored."
+	// deprecatedACMEHTTP01GatewayLabelsOnly is raised when an ACME issuer's HTTP01 gatewayHTTPRoute solver sets labels but no parentRefs.
+	deprecatedACMEHTTP01GatewayLabelsOnly = "ACME issuer spec field 'http01.gatewayHTTPRoute.labels' without 'parentRefs' is deprecated. gateway.networking.k8s.io Gateways do not select HTTPRoutes by label, so set 'parentRefs' to the Gateways that serve the HTTP01 challenge."
 )
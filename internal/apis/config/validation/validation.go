@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation validates the configuration files of the cert-manager
+// components. The settings are validated again, together with the command
+// line flags, once they have been applied to the flags of a component.
+package validation
+
+import (
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+var loggingFormats = []string{logf.TextFormat, logf.JSONFormat}
+
+// ValidateControllerConfiguration validates a defaulted configuration file
+// of the cert-manager controller.
+func ValidateControllerConfiguration(cfg *configv1alpha1.ControllerConfiguration) field.ErrorList {
+	var el field.ErrorList
+
+	if cfg.KubernetesAPIQPS != nil && *cfg.KubernetesAPIQPS <= 0 {
+		el = append(el, field.Invalid(field.NewPath("kubernetesAPIQPS"), *cfg.KubernetesAPIQPS, "must be greater than 0"))
+	}
+	if cfg.KubernetesAPIBurst != nil && *cfg.KubernetesAPIBurst <= 0 {
+		el = append(el, field.Invalid(field.NewPath("kubernetesAPIBurst"), *cfg.KubernetesAPIBurst, "must be greater than 0"))
+	}
+
+	for i, namespace := range cfg.Namespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			el = append(el, field.Invalid(field.NewPath("namespaces").Index(i), namespace, msg))
+		}
+	}
+	if cfg.NamespaceSelector != nil {
+		if _, err := labels.Parse(*cfg.NamespaceSelector); err != nil {
+			el = append(el, field.Invalid(field.NewPath("namespaceSelector"), *cfg.NamespaceSelector, err.Error()))
+		}
+	}
+
+	el = append(el, validateLeaderElection(cfg.LeaderElection, field.NewPath("leaderElection"))...)
+
+	if cfg.ShardCount != nil {
+		fldPath := field.NewPath("shardCount")
+		switch {
+		case *cfg.ShardCount < 1:
+			el = append(el, field.Invalid(fldPath, *cfg.ShardCount, "must be at least 1"))
+		case *cfg.ShardCount > 1 && (cfg.LeaderElection == nil || cfg.LeaderElection.Enabled == nil || !*cfg.LeaderElection.Enabled):
+			el = append(el, field.Invalid(fldPath, *cfg.ShardCount, "can only be greater than 1 if leader election is enabled"))
+		}
+	}
+
+	for name, workers := range cfg.ControllerWorkers {
+		if workers < 1 {
+			el = append(el, field.Invalid(field.NewPath("controllerWorkers").Key(name), workers, "must be at least 1"))
+		}
+	}
+	for name, backoff := range cfg.ControllerBackoff {
+		el = append(el, validateControllerBackoff(backoff, field.NewPath("controllerBackoff").Key(name))...)
+	}
+	if cfg.MaxConcurrentChallenges != nil && *cfg.MaxConcurrentChallenges < 1 {
+		el = append(el, field.Invalid(field.NewPath("maxConcurrentChallenges"), *cfg.MaxConcurrentChallenges, "must be at least 1"))
+	}
+
+	if cfg.MetricsListenAddress != nil {
+		if _, _, err := net.SplitHostPort(*cfg.MetricsListenAddress); err != nil {
+			el = append(el, field.Invalid(field.NewPath("metricsListenAddress"), *cfg.MetricsListenAddress, err.Error()))
+		}
+	}
+
+	el = append(el, validateLogging(cfg.Logging, field.NewPath("logging"))...)
+	el = append(el, validatePositiveDuration(cfg.IssuerSetupTimeout, field.NewPath("issuerSetupTimeout"))...)
+	el = append(el, validatePositiveDuration(cfg.IssuerSignTimeout, field.NewPath("issuerSignTimeout"))...)
+	el = append(el, validateIssuerTypes(cfg.AllowedIssuerTypes, field.NewPath("allowedIssuerTypes"))...)
+	el = append(el, validateIssuerTypes(cfg.DeniedIssuerTypes, field.NewPath("deniedIssuerTypes"))...)
+
+	if dns01 := cfg.ACMEDNS01; dns01 != nil {
+		fldPath := field.NewPath("acmeDNS01")
+		for i, server := range dns01.RecursiveNameservers {
+			if _, _, err := net.SplitHostPort(server); err != nil {
+				el = append(el, field.Invalid(fldPath.Child("recursiveNameservers").Index(i), server, err.Error()))
+			}
+		}
+		el = append(el, validatePositiveDuration(dns01.CheckRetryPeriod, fldPath.Child("checkRetryPeriod"))...)
+	}
+
+	return el
+}
+
+// ValidateWebhookConfiguration validates a defaulted configuration file of
+// the cert-manager webhook.
+func ValidateWebhookConfiguration(cfg *configv1alpha1.WebhookConfiguration) field.ErrorList {
+	var el field.ErrorList
+
+	el = append(el, validatePort(cfg.SecurePort, field.NewPath("securePort"))...)
+	el = append(el, validatePort(cfg.HealthzPort, field.NewPath("healthzPort"))...)
+
+	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
+		fldPath := field.NewPath("tlsConfig")
+		certSet := tlsConfig.CertFile != nil && len(*tlsConfig.CertFile) > 0
+		keySet := tlsConfig.KeyFile != nil && len(*tlsConfig.KeyFile) > 0
+		if certSet != keySet {
+			el = append(el, field.Invalid(fldPath, "", "certFile and keyFile must be set together"))
+		}
+	}
+
+	el = append(el, validateLogging(cfg.Logging, field.NewPath("logging"))...)
+	el = append(el, validateIssuerTypes(cfg.AllowedIssuerTypes, field.NewPath("allowedIssuerTypes"))...)
+	el = append(el, validateIssuerTypes(cfg.DeniedIssuerTypes, field.NewPath("deniedIssuerTypes"))...)
+
+	return el
+}
+
+// ValidateCAInjectorConfiguration validates a defaulted configuration file of
+// the cert-manager cainjector.
+func ValidateCAInjectorConfiguration(cfg *configv1alpha1.CAInjectorConfiguration) field.ErrorList {
+	var el field.ErrorList
+
+	if cfg.Namespace != nil && len(*cfg.Namespace) > 0 {
+		for _, msg := range validation.IsDNS1123Label(*cfg.Namespace) {
+			el = append(el, field.Invalid(field.NewPath("namespace"), *cfg.Namespace, msg))
+		}
+	}
+
+	el = append(el, validateLeaderElection(cfg.LeaderElection, field.NewPath("leaderElection"))...)
+
+	if cfg.ShardCount != nil && *cfg.ShardCount < 1 {
+		el = append(el, field.Invalid(field.NewPath("shardCount"), *cfg.ShardCount, "must be at least 1"))
+	} else if cfg.ShardCount != nil && cfg.ShardIndex != nil && (*cfg.ShardIndex < 0 || *cfg.ShardIndex >= *cfg.ShardCount) {
+		el = append(el, field.Invalid(field.NewPath("shardIndex"), *cfg.ShardIndex, "must be at least 0 and less than shardCount"))
+	}
+
+	el = append(el, validateLogging(cfg.Logging, field.NewPath("logging"))...)
+
+	return el
+}
+
+func validateLeaderElection(cfg *configv1alpha1.LeaderElectionConfig, fldPath *field.Path) field.ErrorList {
+	if cfg == nil {
+		return nil
+	}
+
+	var el field.ErrorList
+	el = append(el, validatePositiveDuration(cfg.LeaseDuration, fldPath.Child("leaseDuration"))...)
+	el = append(el, validatePositiveDuration(cfg.RenewDeadline, fldPath.Child("renewDeadline"))...)
+	el = append(el, validatePositiveDuration(cfg.RetryPeriod, fldPath.Child("retryPeriod"))...)
+
+	if cfg.LeaseDuration != nil && cfg.RenewDeadline != nil && cfg.RenewDeadline.Duration > cfg.LeaseDuration.Duration {
+		el = append(el, field.Invalid(fldPath.Child("renewDeadline"), cfg.RenewDeadline.Duration.String(), "must not be greater than leaseDuration"))
+	}
+
+	return el
+}
+
+func validateControllerBackoff(cfg configv1alpha1.ControllerBackoffConfig, fldPath *field.Path) field.ErrorList {
+	var el field.ErrorList
+	el = append(el, validatePositiveDuration(cfg.BaseDelay, fldPath.Child("baseDelay"))...)
+	el = append(el, validatePositiveDuration(cfg.MaxDelay, fldPath.Child("maxDelay"))...)
+
+	if cfg.BaseDelay != nil && cfg.MaxDelay != nil && cfg.MaxDelay.Duration < cfg.BaseDelay.Duration {
+		el = append(el, field.Invalid(fldPath.Child("maxDelay"), cfg.MaxDelay.Duration.String(), "must not be less than baseDelay"))
+	}
+	if cfg.Jitter != nil && *cfg.Jitter < 0 {
+		el = append(el, field.Invalid(fldPath.Child("jitter"), *cfg.Jitter, "must not be negative"))
+	}
+
+	return el
+}
+
+func validateIssuerTypes(issuerTypes []string, fldPath *field.Path) field.ErrorList {
+	var el field.ErrorList
+	for i, issuerType := range issuerTypes {
+		supported := false
+		for _, t := range apiutil.IssuerTypes {
+			if issuerType == t {
+				supported = true
+			}
+		}
+		if !supported {
+			el = append(el, field.NotSupported(fldPath.Index(i), issuerType, apiutil.IssuerTypes))
+		}
+	}
+	return el
+}
+
+func validateLogging(cfg *configv1alpha1.LoggingConfig, fldPath *field.Path) field.ErrorList {
+	if cfg == nil {
+		return nil
+	}
+
+	var el field.ErrorList
+	if cfg.Verbosity != nil && *cfg.Verbosity < 0 {
+		el = append(el, field.Invalid(fldPath.Child("verbosity"), *cfg.Verbosity, "must not be negative"))
+	}
+	if cfg.Format != nil {
+		supported := false
+		for _, format := range loggingFormats {
+			if *cfg.Format == format {
+				supported = true
+			}
+		}
+		if !supported {
+			el = append(el, field.NotSupported(fldPath.Child("format"), *cfg.Format, loggingFormats))
+		}
+	}
+
+	return el
+}
+
+func validatePort(port *int32, fldPath *field.Path) field.ErrorList {
+	if port == nil {
+		return nil
+	}
+
+	var el field.ErrorList
+	for _, msg := range validation.IsValidPortNum(int(*port)) {
+		el = append(el, field.Invalid(fldPath, *port, msg))
+	}
+	return el
+}
+
+func validatePositiveDuration(d *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	if d != nil && d.Duration <= 0 {
+		return field.ErrorList{field.Invalid(fldPath, d.Duration.String(), "must be greater than 0")}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+)
+
+func TestValidateControllerConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		cfg     *configv1alpha1.ControllerConfiguration
+		expErrs field.ErrorList
+	}{
+		"the defaults are valid": {
+			cfg: &configv1alpha1.ControllerConfiguration{},
+		},
+		"valid namespaces, sharding and workers": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				Namespaces:        []string{"team-a", "team-b"},
+				NamespaceSelector: pointer.String("tenant=a"),
+				ShardCount:        pointer.Int32(3),
+				ControllerWorkers: map[string]int32{"*": 2, "issuers": 10},
+			},
+		},
+		"invalid namespace and namespace selector": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				Namespaces:        []string{"Team-A"},
+				NamespaceSelector: pointer.String("tenant in"),
+			},
+			expErrs: field.ErrorList{
+				field.Invalid(field.NewPath("namespaces").Index(0), "Team-A", ""),
+				field.Invalid(field.NewPath("namespaceSelector"), "tenant in", ""),
+			},
+		},
+		"sharding requires leader election": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				LeaderElection: &configv1alpha1.LeaderElectionConfig{Enabled: pointer.Bool(false)},
+				ShardCount:     pointer.Int32(2),
+			},
+			expErrs: field.ErrorList{
+				field.Invalid(field.NewPath("shardCount"), int32(2), ""),
+			},
+		},
+		"invalid workers, leader election and timeouts": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				ControllerWorkers: map[string]int32{"issuers": 0},
+				LeaderElection: &configv1alpha1.LeaderElectionConfig{
+					LeaseDuration: &metav1.Duration{Duration: 10 * time.Second},
+					RenewDeadline: &metav1.Duration{Duration: 20 * time.Second},
+				},
+				IssuerSignTimeout: &metav1.Duration{},
+			},
+			expErrs: field.ErrorList{
+				field.Invalid(field.NewPath("leaderElection", "renewDeadline"), "20s", ""),
+				field.Invalid(field.NewPath("controllerWorkers").Key("issuers"), int32(0), ""),
+				field.Invalid(field.NewPath("issuerSignTimeout"), "0s", ""),
+			},
+		},
+		"valid controller backoff and issuer types": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				ControllerBackoff: map[string]configv1alpha1.ControllerBackoffConfig{
+					"*": {Jitter: pointer.Float32(0.2)},
+					"certificaterequests-issuer-venafi": {
+						BaseDelay: &metav1.Duration{Duration: 30 * time.Second},
+						MaxDelay:  &metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+				AllowedIssuerTypes: []string{"acme", "ca"},
+				DeniedIssuerTypes:  []string{"selfsigned"},
+			},
+		},
+		"invalid controller backoff and issuer types": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				ControllerBackoff: map[string]configv1alpha1.ControllerBackoffConfig{
+					"issuers": {
+						BaseDelay: &metav1.Duration{Duration: time.Minute},
+						MaxDelay:  &metav1.Duration{Duration: time.Second},
+						Jitter:    pointer.Float32(-1),
+					},
+				},
+				DeniedIssuerTypes: []string{"selfsigned", "self-signed"},
+			},
+			expErrs: field.ErrorList{
+				field.Invalid(field.NewPath("controllerBackoff").Key("issuers").Child("maxDelay"), "1s", ""),
+				field.Invalid(field.NewPath("controllerBackoff").Key("issuers").Child("jitter"), float32(-1), ""),
+				field.NotSupported(field.NewPath("deniedIssuerTypes").Index(1), "self-signed", apiutil.IssuerTypes),
+			},
+		},
+		"invalid DNS01 nameservers and logging format": {
+			cfg: &configv1alpha1.ControllerConfiguration{
+				ACMEDNS01: &configv1alpha1.ACMEDNS01Config{RecursiveNameservers: []string{"8.8.8.8"}},
+				Logging:   &configv1alpha1.LoggingConfig{Format: pointer.String("xml")},
+			},
+			expErrs: field.ErrorList{
+				field.NotSupported(field.NewPath("logging", "format"), "xml", loggingFormats),
+				field.Invalid(field.NewPath("acmeDNS01", "recursiveNameservers").Index(0), "8.8.8.8", ""),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configv1alpha1.SetObjectDefaults_ControllerConfiguration(test.cfg)
+			assertErrors(t, test.expErrs, ValidateControllerConfiguration(test.cfg))
+		})
+	}
+}
+
+func TestValidateWebhookConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		cfg     *configv1alpha1.WebhookConfiguration
+		expErrs field.ErrorList
+	}{
+		"the defaults are valid": {
+			cfg: &configv1alpha1.WebhookConfiguration{},
+		},
+		"invalid port and partial TLS configuration": {
+			cfg: &configv1alpha1.WebhookConfiguration{
+				SecurePort: pointer.Int32(70000),
+				TLSConfig:  &configv1alpha1.TLSConfig{CertFile: pointer.String("tls.crt")},
+			},
+			expErrs: field.ErrorList{
+				field.Invalid(field.NewPath("securePort"), int32(70000), ""),
+				field.Invalid(field.NewPath("tlsConfig"), "", ""),
+			},
+		},
+		"unknown issuer type": {
+			cfg: &configv1alpha1.WebhookConfiguration{
+				AllowedIssuerTypes: []string{"acme", "letsencrypt"},
+			},
+			expErrs: field.ErrorList{
+				field.NotSupported(field.NewPath("allowedIssuerTypes").Index(1), "letsencrypt", apiutil.IssuerTypes),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configv1alpha1.SetObjectDefaults_WebhookConfiguration(test.cfg)
+			assertErrors(t, test.expErrs, ValidateWebhookConfiguration(test.cfg))
+		})
+	}
+}
+
+func TestValidateCAInjectorConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		cfg     *configv1alpha1.CAInjectorConfiguration
+		expErrs field.ErrorList
+	}{
+		"the defaults are valid": {
+			cfg: &configv1alpha1.CAInjectorConfiguration{},
+		},
+		"valid shard": {
+			cfg: &configv1alpha1.CAInjectorConfiguration{
+				ShardCount: pointer.Int32(3),
+				ShardIndex: pointer.Int32(2),
+			},
+		},
+		"shard index out of range": {
+			cfg: &configv1alpha1.CAInjectorConfiguration{
+				ShardCount: pointer.Int32(3),
+				ShardIndex: pointer.Int32(3),
+			},
+			expErrs: field.ErrorList{
+				field.Invalid(field.NewPath("shardIndex"), int32(3), ""),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configv1alpha1.SetObjectDefaults_CAInjectorConfiguration(test.cfg)
+			assertErrors(t, test.expErrs, ValidateCAInjectorConfiguration(test.cfg))
+		})
+	}
+}
+
+// assertErrors compares the type, field and value of errors, but not their
+// detail messages.
+func assertErrors(t *testing.T, exp, got field.ErrorList) {
+	t.Helper()
+	type summary struct {
+		Type     field.ErrorType
+		Field    string
+		BadValue interface{}
+	}
+	summarize := func(el field.ErrorList) []summary {
+		var s []summary
+		for _, err := range el {
+			s = append(s, summary{err.Type, err.Field, err.BadValue})
+		}
+		return s
+	}
+	if !reflect.DeepEqual(summarize(exp), summarize(got)) {
+		t.Errorf("unexpected errors, exp=%v got=%v", exp, got)
+	}
+}
@@ -55,6 +55,9 @@ type ObjectReference struct {
 	Kind string
 	// Group of the resource being referred to.
 	Group string
+	// Namespace of the resource being referred to, if it is an Issuer in a
+	// namespace other than that of the referencing resource.
+	Namespace string
 }
 
 // A reference to a specific 'key' within a Secret resource.
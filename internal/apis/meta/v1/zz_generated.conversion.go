@@ -82,6 +82,7 @@ func autoConvert_v1_ObjectReference_To_meta_ObjectReference(in *v1.ObjectReferen
 	out.Name = in.Name
 	out.Kind = in.Kind
 	out.Group = in.Group
+	out.Namespace = in.Namespace
 	return nil
 }
 
@@ -89,6 +90,7 @@ func autoConvert_meta_ObjectReference_To_v1_ObjectReference(in *meta.ObjectRefer
 	out.Name = in.Name
 	out.Kind = in.Kind
 	out.Group = in.Group
+	out.Namespace = in.Namespace
 	return nil
 }
 
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ct implements logging of precertificates to the SCT hook of a CA
+// issuer, so that the returned signed certificate timestamps (SCTs) can be
+// embedded in the issued certificate.
+package ct
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// hookTimeout is the maximum time a single request to an SCT hook may take.
+const hookTimeout = 30 * time.Second
+
+// maxResponseSize is the maximum size of a response read from an SCT hook.
+const maxResponseSize = 1 << 20
+
+var _ Interface = &Client{}
+
+// ClientBuilder is a function type that returns a new Interface.
+// Can be used in tests to create a mock SCT hook.
+type ClientBuilder func(cfg *v1.CAIssuerCertificateTransparency) (Interface, error)
+
+// Interface logs precertificates to an SCT hook.
+type Interface interface {
+	// AddPreChain logs the given DER encoded precertificate chain and returns
+	// the TLS serialized SignedCertificateTimestamp structures obtained for
+	// it.
+	AddPreChain(ctx context.Context, chain [][]byte) ([][]byte, error)
+}
+
+// Client is an Interface which sends RFC 6962 add-pre-chain requests to the
+// SCT hook configured on a CA issuer.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// addPreChainRequest is the request body sent to an SCT hook.
+type addPreChainRequest struct {
+	// Chain is the precertificate, followed by the CA certificates needed to
+	// verify it.
+	Chain [][]byte `json:"chain"`
+}
+
+// addPreChainResponse is the response body returned by an SCT hook.
+type addPreChainResponse struct {
+	// SCTs are the TLS serialized SignedCertificateTimestamp structures
+	// obtained for the precertificate.
+	SCTs [][]byte `json:"scts"`
+}
+
+// New returns a Client for the SCT hook described by cfg.
+func New(cfg *v1.CAIssuerCertificateTransparency) (Interface, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(cfg.CABundle) > 0 {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM(cfg.CABundle); !ok {
+			return nil, errors.New("error loading SCT hook CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: caCertPool}
+	}
+
+	return &Client{
+		url: cfg.URL,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   hookTimeout,
+		},
+	}, nil
+}
+
+// AddPreChain POSTs the chain to the SCT hook and returns the SCTs in its
+// response.
+func (c *Client) AddPreChain(ctx context.Context, chain [][]byte) ([][]byte, error) {
+	body, err := json.Marshal(addPreChainRequest{Chain: chain})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call SCT hook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SCT hook response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SCT hook returned unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	var response addPreChainResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode SCT hook response: %w", err)
+	}
+
+	return response.SCTs, nil
+}
+
+// EmbedSCTs signs a precertificate for template using the CA, logs it to the
+// SCT hook and appends the returned SCTs to the extensions of template. The
+// certificate that is later signed from template shares the serial number and
+// contents of the logged precertificate, as required by RFC 6962.
+func EmbedSCTs(ctx context.Context, client Interface, caCerts []*x509.Certificate, caKey crypto.Signer, template *x509.Certificate) error {
+	if len(caCerts) == 0 {
+		return errors.New("no CA certificates given to sign precertificate")
+	}
+
+	precertTemplate := *template
+	precertTemplate.ExtraExtensions = append(append([]pkix.Extension{}, template.ExtraExtensions...), pki.CTPoisonExtension())
+
+	_, precert, err := pki.SignCertificate(&precertTemplate, caCerts[0], template.PublicKey, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign precertificate: %w", err)
+	}
+
+	chain := [][]byte{precert.Raw}
+	for _, caCert := range caCerts {
+		chain = append(chain, caCert.Raw)
+	}
+
+	scts, err := client.AddPreChain(ctx, chain)
+	if err != nil {
+		return err
+	}
+	if len(scts) == 0 {
+		return errors.New("SCT hook returned no SCTs")
+	}
+
+	ext, err := pki.SCTListExtension(scts)
+	if err != nil {
+		return fmt.Errorf("SCT hook returned invalid SCTs: %w", err)
+	}
+	template.ExtraExtensions = append(template.ExtraExtensions, ext)
+
+	return nil
+}
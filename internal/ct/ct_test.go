@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ct
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+type fakeClient struct {
+	chain [][]byte
+	scts  [][]byte
+	err   error
+}
+
+func (f *fakeClient) AddPreChain(_ context.Context, chain [][]byte) ([][]byte, error) {
+	f.chain = chain
+	return f.scts, f.err
+}
+
+func TestAddPreChain(t *testing.T) {
+	chain := [][]byte{[]byte("precert"), []byte("ca")}
+	scts := [][]byte{[]byte("sct-1"), []byte("sct-2")}
+
+	tests := map[string]struct {
+		handler  http.HandlerFunc
+		expSCTs  [][]byte
+		expError string
+	}{
+		"returns the SCTs returned by the hook": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				var req addPreChainRequest
+				if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil || !reflect.DeepEqual(req.Chain, chain) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				json.NewEncoder(w).Encode(addPreChainResponse{SCTs: scts})
+			},
+			expSCTs: scts,
+		},
+		"errors if the hook returns an unexpected status code": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "log unavailable", http.StatusServiceUnavailable)
+			},
+			expError: "SCT hook returned unexpected status code 503: log unavailable",
+		},
+		"errors if the hook returns an invalid response": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("not json"))
+			},
+			expError: "failed to decode SCT hook response",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewTLSServer(test.handler)
+			defer server.Close()
+
+			client, err := New(&v1.CAIssuerCertificateTransparency{
+				URL:      server.URL,
+				CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := client.AddPreChain(context.Background(), chain)
+			switch {
+			case test.expError != "":
+				if err == nil || !strings.Contains(err.Error(), test.expError) {
+					t.Errorf("unexpected error, exp=%q got=%v", test.expError, err)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !reflect.DeepEqual(got, test.expSCTs):
+				t.Errorf("unexpected SCTs, exp=%q got=%q", test.expSCTs, got)
+			}
+		})
+	}
+}
+
+func TestNewInvalidCABundle(t *testing.T) {
+	_, err := New(&v1.CAIssuerCertificateTransparency{URL: "https://example.com", CABundle: []byte("invalid")})
+	if err == nil {
+		t.Error("expected an error for an invalid CA bundle")
+	}
+}
+
+func TestEmbedSCTs(t *testing.T) {
+	caKey, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	_, caCert, err := pki.SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTemplate := func() *x509.Certificate {
+		key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &x509.Certificate{
+			SerialNumber: big.NewInt(42),
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+			DNSNames:     []string{"example.com"},
+			PublicKey:    key.Public(),
+		}
+	}
+
+	t.Run("logs a precertificate and embeds the returned SCTs", func(t *testing.T) {
+		template := newTemplate()
+		client := &fakeClient{scts: [][]byte{{0xaa, 0xbb}}}
+		if err := EmbedSCTs(context.Background(), client, []*x509.Certificate{caCert}, caKey, template); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(client.chain) != 2 || !reflect.DeepEqual(client.chain[1], caCert.Raw) {
+			t.Fatalf("expected precertificate chain to contain the CA certificate")
+		}
+		precert, err := x509.ParseCertificate(client.chain[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if precert.SerialNumber.Cmp(template.SerialNumber) != 0 {
+			t.Errorf("expected precertificate to share the serial number of the certificate")
+		}
+		if !hasExtension(precert.Extensions, pki.OIDExtensionCTPoison) {
+			t.Errorf("expected precertificate to contain the poison extension")
+		}
+		if hasExtension(template.ExtraExtensions, pki.OIDExtensionCTPoison) {
+			t.Errorf("expected template to not contain the poison extension")
+		}
+
+		_, cert, err := pki.SignCertificate(template, caCert, template.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hasExtension(cert.Extensions, pki.OIDExtensionCTSCTList) {
+			t.Errorf("expected certificate to contain the SCT list extension")
+		}
+	})
+
+	t.Run("errors if the hook fails", func(t *testing.T) {
+		template := newTemplate()
+		client := &fakeClient{err: errors.New("hook failed")}
+		if err := EmbedSCTs(context.Background(), client, []*x509.Certificate{caCert}, caKey, template); err == nil || err.Error() != "hook failed" {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(template.ExtraExtensions) > 0 {
+			t.Errorf("expected template to not be modified")
+		}
+	})
+
+	t.Run("errors if the hook returns no SCTs", func(t *testing.T) {
+		template := newTemplate()
+		if err := EmbedSCTs(context.Background(), &fakeClient{}, []*x509.Certificate{caCert}, caKey, template); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func hasExtension(exts []pkix.Extension, oid asn1.ObjectIdentifier) bool {
+	for _, ext := range exts {
+		if ext.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package revocation looks up the CertificateRevocations of CA issuers, so
+// that the OCSP responder and the CRL controller report the same revoked
+// certificates.
+package revocation
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	revocationapi "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	revocationlisters "github.com/jetstack/cert-manager/pkg/client/listers/revocation/v1alpha1"
+)
+
+// Entry is a revoked certificate.
+type Entry struct {
+	SerialNumber   *big.Int
+	RevocationTime time.Time
+	// Reason is the RFC 5280 reason code of the revocation.
+	Reason int
+}
+
+// ForIssuer returns the certificates revoked by CertificateRevocations that
+// reference the given issuer, keyed by the decimal string of their serial
+// number. CertificateRevocations for a ClusterIssuer are only honoured in the
+// cluster resource namespace, so that users that may only create resources
+// in their own namespace cannot revoke certificates of other namespaces.
+// CertificateRevocations with an invalid serial number are ignored.
+func ForIssuer(lister revocationlisters.CertificateRevocationLister, iss cmapi.GenericIssuer, clusterResourceNamespace string) (map[string]Entry, error) {
+	namespace := iss.GetObjectMeta().Namespace
+	_, isClusterIssuer := iss.(*cmapi.ClusterIssuer)
+	if isClusterIssuer {
+		namespace = clusterResourceNamespace
+	}
+
+	revocations, err := lister.CertificateRevocations(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]Entry)
+	for _, rev := range revocations {
+		if !referencesIssuer(rev.Spec.IssuerRef.Group, rev.Spec.IssuerRef.Kind, rev.Spec.IssuerRef.Name, iss) {
+			continue
+		}
+		serial, err := ParseSerialNumber(rev.Spec.SerialNumber)
+		if err != nil {
+			continue
+		}
+		revokedAt := rev.CreationTimestamp.Time
+		if rev.Spec.RevocationTime != nil {
+			revokedAt = rev.Spec.RevocationTime.Time
+		}
+		entry := Entry{
+			SerialNumber:   serial,
+			RevocationTime: revokedAt.UTC(),
+			Reason:         ReasonCode(rev.Spec.Reason),
+		}
+		// If a certificate was revoked more than once, the earliest
+		// revocation is reported.
+		if existing, ok := entries[serial.String()]; ok && !entry.RevocationTime.Before(existing.RevocationTime) {
+			continue
+		}
+		entries[serial.String()] = entry
+	}
+
+	return entries, nil
+}
+
+// ReferencesIssuer returns true if the CertificateRequest references the
+// given issuer, either in its own namespace or in the namespace named by the
+// issuer reference.
+func ReferencesIssuer(cr *cmapi.CertificateRequest, iss cmapi.GenericIssuer) bool {
+	issuerNamespace := cr.Namespace
+	if cr.Spec.IssuerRef.Namespace != "" {
+		issuerNamespace = cr.Spec.IssuerRef.Namespace
+	}
+	if _, ok := iss.(*cmapi.ClusterIssuer); !ok && issuerNamespace != iss.GetObjectMeta().Namespace {
+		return false
+	}
+	return referencesIssuer(cr.Spec.IssuerRef.Group, cr.Spec.IssuerRef.Kind, cr.Spec.IssuerRef.Name, iss)
+}
+
+func referencesIssuer(group, kind, name string, iss cmapi.GenericIssuer) bool {
+	if group != "" && group != certmanager.GroupName {
+		return false
+	}
+	if name != iss.GetObjectMeta().Name {
+		return false
+	}
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	if _, ok := iss.(*cmapi.ClusterIssuer); ok {
+		return kind == cmapi.ClusterIssuerKind
+	}
+	return kind == cmapi.IssuerKind
+}
+
+// ParseSerialNumber parses a hexadecimal serial number whose bytes may
+// optionally be separated by colons.
+func ParseSerialNumber(s string) (*big.Int, error) {
+	raw := strings.ReplaceAll(strings.TrimSpace(s), ":", "")
+	if len(raw)%2 == 1 {
+		raw = "0" + raw
+	}
+	b, err := hex.DecodeString(raw)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid serial number %q: must be hexadecimal", s)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// ReasonCode returns the RFC 5280 reason code of the given revocation
+// reason. Unknown reasons are reported as unspecified.
+func ReasonCode(reason revocationapi.RevocationReason) int {
+	switch reason {
+	case revocationapi.RevocationReasonKeyCompromise:
+		return ocsp.KeyCompromise
+	case revocationapi.RevocationReasonCACompromise:
+		return ocsp.CACompromise
+	case revocationapi.RevocationReasonAffiliationChanged:
+		return ocsp.AffiliationChanged
+	case revocationapi.RevocationReasonSuperseded:
+		return ocsp.Superseded
+	case revocationapi.RevocationReasonCessationOfOperation:
+		return ocsp.CessationOfOperation
+	case revocationapi.RevocationReasonCertificateHold:
+		return ocsp.CertificateHold
+	case revocationapi.RevocationReasonPrivilegeWithdrawn:
+		return ocsp.PrivilegeWithdrawn
+	case revocationapi.RevocationReasonAACompromise:
+		return ocsp.AACompromise
+	default:
+		return ocsp.Unspecified
+	}
+}
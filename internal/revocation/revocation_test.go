@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"testing"
+)
+
+func TestParseSerialNumber(t *testing.T) {
+	tests := map[string]struct {
+		serial string
+		exp    string
+		expErr bool
+	}{
+		"parses hexadecimal serial numbers": {
+			serial: "4f1a09",
+			exp:    "4f1a09",
+		},
+		"parses colon separated serial numbers": {
+			serial: "4F:1A:09",
+			exp:    "4f1a09",
+		},
+		"parses serial numbers with an odd number of digits": {
+			serial: "f1a09",
+			exp:    "f1a09",
+		},
+		"rejects non hexadecimal serial numbers": {
+			serial: "serial",
+			expErr: true,
+		},
+		"rejects empty serial numbers": {
+			serial: "::",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serial, err := ParseSerialNumber(test.serial)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if err == nil && serial.Text(16) != test.exp {
+				t.Errorf("unexpected serial number, exp=%s got=%s", test.exp, serial.Text(16))
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+// Package serial generates the serial numbers of certificates signed by a CA
+// issuer, according to the serial number policy configured on the issuer.
+package serial
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+const (
+	// DefaultLength is the length, in bytes, of serial numbers when no length
+	// is configured.
+	DefaultLength = 16
+
+	// MinLength and MaxLength bound the configurable length of serial
+	// numbers. RFC 5280 limits serial numbers to 20 octets.
+	MinLength = 8
+	MaxLength = 20
+
+	// CounterKey is the ConfigMap key under which the counter of monotonic
+	// serial numbers is persisted.
+	CounterKey = "counter"
+)
+
+// Generator generates serial numbers for CA issuers.
+type Generator struct {
+	kubeClient kubernetes.Interface
+	rand       io.Reader
+}
+
+// New returns a Generator which persists the counters of monotonic serial
+// numbers using the given client.
+func New(kubeClient kubernetes.Interface) *Generator {
+	return &Generator{
+		kubeClient: kubeClient,
+		rand:       rand.Reader,
+	}
+}
+
+// Next returns the serial number of the next certificate signed by a CA
+// issuer with the given serial number policy. The namespace is the one in
+// which the counter ConfigMap of monotonic serial numbers is stored.
+// Errors persisting the counter are transient and should be retried.
+func (g *Generator) Next(ctx context.Context, namespace string, policy *v1.CAIssuerSerialNumber) (*big.Int, error) {
+	length := policy.Length
+	if length == 0 {
+		length = DefaultLength
+	}
+	if length < MinLength || length > MaxLength {
+		return nil, fmt.Errorf("serial number length must be between %d and %d bytes, got %d", MinLength, MaxLength, length)
+	}
+
+	switch policy.Type {
+	case v1.CAIssuerSerialNumberRandom, "":
+		return random(g.rand, length)
+	case v1.CAIssuerSerialNumberMonotonic:
+		counter, err := g.incrementCounter(ctx, namespace, policy.CounterConfigMapName)
+		if err != nil {
+			return nil, err
+		}
+		return monotonic(length, counter)
+	default:
+		return nil, fmt.Errorf("unsupported serial number type %q", policy.Type)
+	}
+}
+
+// random returns a random serial number encoded in exactly length bytes. The
+// most significant bit is cleared so that the serial number is positive, and
+// the next bit is set so that it is not encoded in fewer bytes.
+func random(r io.Reader, length int) (*big.Int, error) {
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	b[0] = b[0]&0x3f | 0x40
+	return new(big.Int).SetBytes(b), nil
+}
+
+// monotonic returns the serial number for the given counter value, offset so
+// that it is positive and encoded in exactly length bytes.
+func monotonic(length int, counter uint64) (*big.Int, error) {
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(8*length-2))
+	n := new(big.Int).SetUint64(counter)
+	if n.Cmp(offset) >= 0 {
+		return nil, fmt.Errorf("serial number counter %d does not fit in %d bytes", counter, length)
+	}
+	return n.Add(n, offset), nil
+}
+
+// incrementCounter increments the counter persisted in the named ConfigMap,
+// creating it if it does not exist, and returns the new value. Concurrent
+// increments are detected through the ConfigMap's resource version, so that
+// no value is ever returned twice.
+func (g *Generator) incrementCounter(ctx context.Context, namespace, name string) (uint64, error) {
+	if len(name) == 0 {
+		return 0, fmt.Errorf("a counter ConfigMap name is required for monotonic serial numbers")
+	}
+
+	cm, err := g.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err := g.kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string]string{CounterKey: "1"},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create serial number counter ConfigMap %s/%s: %w", namespace, name, err)
+		}
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get serial number counter ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	counter, err := strconv.ParseUint(cm.Data[CounterKey], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse serial number counter in ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	if counter == ^uint64(0) {
+		return 0, fmt.Errorf("serial number counter in ConfigMap %s/%s is exhausted", namespace, name)
+	}
+	counter++
+
+	cm = cm.DeepCopy()
+	cm.Data[CounterKey] = strconv.FormatUint(counter, 10)
+	if _, err := g.kubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return 0, fmt.Errorf("failed to update serial number counter ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	return counter, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package serial
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestNext(t *testing.T) {
+	counterConfigMap := func(counter string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "serial-counter", Namespace: "ns"},
+			Data:       map[string]string{CounterKey: counter},
+		}
+	}
+
+	tests := map[string]struct {
+		policy         *v1.CAIssuerSerialNumber
+		existing       []runtime.Object
+		updateErr      error
+		expSerial      *big.Int
+		expCounter     string
+		expErr         bool
+		expSerialBytes int
+	}{
+		"random serial numbers should default to 16 bytes": {
+			policy:         &v1.CAIssuerSerialNumber{},
+			expSerialBytes: 16,
+		},
+		"random serial numbers should be encoded in the configured length": {
+			policy:         &v1.CAIssuerSerialNumber{Type: v1.CAIssuerSerialNumberRandom, Length: 8},
+			expSerialBytes: 8,
+		},
+		"an out of range length should error": {
+			policy: &v1.CAIssuerSerialNumber{Length: 21},
+			expErr: true,
+		},
+		"a missing counter ConfigMap should be created starting at 1": {
+			policy:     &v1.CAIssuerSerialNumber{Type: v1.CAIssuerSerialNumberMonotonic, Length: 8, CounterConfigMapName: "serial-counter"},
+			expSerial:  big.NewInt(0x4000000000000001),
+			expCounter: "1",
+		},
+		"an existing counter should be incremented": {
+			policy:     &v1.CAIssuerSerialNumber{Type: v1.CAIssuerSerialNumberMonotonic, Length: 8, CounterConfigMapName: "serial-counter"},
+			existing:   []runtime.Object{counterConfigMap("41")},
+			expSerial:  big.NewInt(0x400000000000002a),
+			expCounter: "42",
+		},
+		"a counter that no longer fits in the configured length should error": {
+			policy:     &v1.CAIssuerSerialNumber{Type: v1.CAIssuerSerialNumberMonotonic, Length: 8, CounterConfigMapName: "serial-counter"},
+			existing:   []runtime.Object{counterConfigMap("4611686018427387903")},
+			expCounter: "4611686018427387904",
+			expErr:     true,
+		},
+		"a malformed counter should error": {
+			policy:     &v1.CAIssuerSerialNumber{Type: v1.CAIssuerSerialNumberMonotonic, CounterConfigMapName: "serial-counter"},
+			existing:   []runtime.Object{counterConfigMap("foo")},
+			expCounter: "foo",
+			expErr:     true,
+		},
+		"a failure to persist the counter should error": {
+			policy:     &v1.CAIssuerSerialNumber{Type: v1.CAIssuerSerialNumberMonotonic, CounterConfigMapName: "serial-counter"},
+			existing:   []runtime.Object{counterConfigMap("41")},
+			updateErr:  errors.New("conflict"),
+			expCounter: "41",
+			expErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(test.existing...)
+			if test.updateErr != nil {
+				kubeClient.PrependReactor("update", "configmaps", func(coretesting.Action) (bool, runtime.Object, error) {
+					return true, nil, test.updateErr
+				})
+			}
+
+			serial, err := New(kubeClient).Next(context.TODO(), "ns", test.policy)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			if test.expSerial != nil && serial.Cmp(test.expSerial) != 0 {
+				t.Errorf("unexpected serial number, exp=%x got=%x", test.expSerial, serial)
+			}
+			if test.expSerialBytes > 0 {
+				if serial.Sign() <= 0 {
+					t.Errorf("expected a positive serial number, got %x", serial)
+				}
+				if l := len(serial.Bytes()); l != test.expSerialBytes || serial.Bytes()[0]&0x80 != 0 {
+					t.Errorf("expected serial number to be encoded in %d bytes, got %d bytes: %x", test.expSerialBytes, l, serial)
+				}
+			}
+
+			if test.policy.Type == v1.CAIssuerSerialNumberMonotonic {
+				cm, err := kubeClient.CoreV1().ConfigMaps("ns").Get(context.TODO(), "serial-counter", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cm.Data[CounterKey] != test.expCounter {
+					t.Errorf("unexpected persisted counter, exp=%q got=%q", test.expCounter, cm.Data[CounterKey])
+				}
+			}
+		})
+	}
+}
+
+func TestRandomEncoding(t *testing.T) {
+	// The extremes of random input should still produce positive serial
+	// numbers encoded in exactly the requested length.
+	a, err := random(bytes.NewReader(bytes.Repeat([]byte{0xff}, 16)), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := random(bytes.NewReader(bytes.Repeat([]byte{0x00}, 16)), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Bytes()) != 16 || len(b.Bytes()) != 16 {
+		t.Errorf("expected serial numbers to be encoded in 16 bytes, got %x and %x", a, b)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package fake
 
 import (
+	"context"
 	"errors"
 
 	vault "github.com/hashicorp/vault/api"
@@ -61,7 +62,7 @@ func (c *Client) Token() string {
 	return c.token
 }
 
-func (c *Client) RawRequest(r *vault.Request) (*vault.Response, error) {
+func (c *Client) RawRequestWithContext(_ context.Context, r *vault.Request) (*vault.Response, error) {
 	return c.RawRequestFn(r)
 }
 
@@ -18,6 +18,7 @@ limitations under the License.
 package fake
 
 import (
+	"context"
 	"time"
 
 	vault "github.com/hashicorp/vault/api"
@@ -29,14 +30,17 @@ import (
 // Vault is a mock implementation of the Vault interface
 type Vault struct {
 	NewFn                           func(string, corelisters.SecretLister, v1.GenericIssuer) (*Vault, error)
-	SignFn                          func([]byte, time.Duration) ([]byte, []byte, error)
+	SignFn                          func([]byte, time.Duration, string) ([]byte, []byte, error)
 	IsVaultInitializedAndUnsealedFn func() error
+	RotateAppRoleSecretIDFn         func(context.Context, time.Time, func(string) error) (bool, error)
+	RevokeFn                        func(context.Context, string) error
+	WriteKVFn                       func(context.Context, string, map[string]string) error
 }
 
 // New returns a new fake Vault
 func New() *Vault {
 	v := &Vault{
-		SignFn: func([]byte, time.Duration) ([]byte, []byte, error) {
+		SignFn: func([]byte, time.Duration, string) ([]byte, []byte, error) {
 			return nil, nil, nil
 		},
 		IsVaultInitializedAndUnsealedFn: func() error {
@@ -52,13 +56,13 @@ func New() *Vault {
 }
 
 // Sign implements `vault.Interface`.
-func (v *Vault) Sign(csrPEM []byte, duration time.Duration) ([]byte, []byte, error) {
-	return v.SignFn(csrPEM, duration)
+func (v *Vault) Sign(_ context.Context, csrPEM []byte, duration time.Duration, namespace string) ([]byte, []byte, error) {
+	return v.SignFn(csrPEM, duration, namespace)
 }
 
 // WithSign sets the fake Vault's Sign function.
 func (v *Vault) WithSign(certPEM, caPEM []byte, err error) *Vault {
-	v.SignFn = func([]byte, time.Duration) ([]byte, []byte, error) {
+	v.SignFn = func([]byte, time.Duration, string) ([]byte, []byte, error) {
 		return certPEM, caPEM, err
 	}
 	return v
@@ -85,7 +89,32 @@ func (v *Vault) Sys() *vault.Sys {
 	return new(vault.Sys)
 }
 
+// RotateAppRoleSecretID calls RotateAppRoleSecretIDFn if set, and otherwise
+// never rotates the secret ID.
+func (v *Vault) RotateAppRoleSecretID(ctx context.Context, now time.Time, store func(secretID string) error) (bool, error) {
+	if v.RotateAppRoleSecretIDFn == nil {
+		return false, nil
+	}
+	return v.RotateAppRoleSecretIDFn(ctx, now, store)
+}
+
+// Revoke calls RevokeFn if set, and otherwise always succeeds.
+func (v *Vault) Revoke(ctx context.Context, serialNumber string) error {
+	if v.RevokeFn == nil {
+		return nil
+	}
+	return v.RevokeFn(ctx, serialNumber)
+}
+
+// WriteKV calls WriteKVFn if set, and otherwise always succeeds.
+func (v *Vault) WriteKV(ctx context.Context, secretPath string, data map[string]string) error {
+	if v.WriteKVFn == nil {
+		return nil
+	}
+	return v.WriteKVFn(ctx, secretPath, data)
+}
+
 // IsVaultInitializedAndUnsealed always returns nil
-func (v *Vault) IsVaultInitializedAndUnsealed() error {
+func (v *Vault) IsVaultInitializedAndUnsealed(context.Context) error {
 	return nil
 }
@@ -17,7 +17,9 @@ limitations under the License.
 package vault
 
 import (
+	"context"
 	"crypto/x509"
+	"encoding/asn1"
 	"errors"
 	"fmt"
 	"net/http"
@@ -28,6 +30,8 @@ import (
 
 	vault "github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/sdk/helper/certutil"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -38,23 +42,45 @@ var _ Interface = &Vault{}
 
 // ClientBuilder is a function type that returns a new Interface.
 // Can be used in tests to create a mock signer of Vault certificate requests.
-type ClientBuilder func(namespace string, secretsLister corelisters.SecretLister,
-	issuer v1.GenericIssuer) (Interface, error)
+type ClientBuilder func(ctx context.Context, namespace string, createTokenFn func(ns string) CreateToken,
+	secretsLister corelisters.SecretLister, issuer v1.GenericIssuer) (Interface, error)
+
+// CreateToken requests a bound ServiceAccount token using the TokenRequest
+// API. It has the same signature as the CreateToken method of the typed
+// ServiceAccounts client.
+type CreateToken func(ctx context.Context, saName string, req *authv1.TokenRequest,
+	opts metav1.CreateOptions) (*authv1.TokenRequest, error)
+
+// boundTokenExpirationSeconds is the lifetime requested for bound
+// ServiceAccount tokens. 10 minutes is the shortest lifetime accepted by the
+// Kubernetes API server.
+const boundTokenExpirationSeconds = 600
+
+// defaultSecretIDRenewBefore is how long before its expiry an App Role secret
+// ID is rotated if the issuer does not configure renewBefore.
+const defaultSecretIDRenewBefore = 24 * time.Hour
+
+// secretIDWrapTTL is the lifetime of the response-wrapping token returned
+// when generating a new App Role secret ID.
+const secretIDWrapTTL = "60s"
 
 // Interface implements various high level functionality related to connecting
 // with a Vault server, verifying its status and signing certificate request for
 // Vault's certificate.
 // TODO: Sys() is duplicated here and in Client interface
 type Interface interface {
-	Sign(csrPEM []byte, duration time.Duration) (certPEM []byte, caPEM []byte, err error)
+	Sign(ctx context.Context, csrPEM []byte, duration time.Duration, namespace string) (certPEM []byte, caPEM []byte, err error)
 	Sys() *vault.Sys
-	IsVaultInitializedAndUnsealed() error
+	IsVaultInitializedAndUnsealed(ctx context.Context) error
+	RotateAppRoleSecretID(ctx context.Context, now time.Time, store func(secretID string) error) (rotated bool, err error)
+	Revoke(ctx context.Context, serialNumber string) error
+	WriteKV(ctx context.Context, secretPath string, data map[string]string) error
 }
 
 // Client implements functionality to talk to a Vault server.
 type Client interface {
 	NewRequest(method, requestPath string) *vault.Request
-	RawRequest(r *vault.Request) (*vault.Response, error)
+	RawRequestWithContext(ctx context.Context, r *vault.Request) (*vault.Response, error)
 	SetToken(v string)
 	Token() string
 	Sys() *vault.Sys
@@ -63,19 +89,25 @@ type Client interface {
 // Vault implements Interface and holds a Vault issuer, secrets lister and a
 // Vault client.
 type Vault struct {
+	createToken   CreateToken
 	secretsLister corelisters.SecretLister
 	issuer        v1.GenericIssuer
 	namespace     string
 
 	client Client
+
+	// vaultIndex is the Vault state returned when logging in, which is
+	// required on subsequent requests if ReadYourWrites is enabled.
+	vaultIndex string
 }
 
 // New returns a new Vault instance with the given namespace, issuer and
 // secrets lister.
 // Returned errors may be network failures and should be considered for
 // retrying.
-func New(namespace string, secretsLister corelisters.SecretLister, issuer v1.GenericIssuer) (Interface, error) {
+func New(ctx context.Context, namespace string, createTokenFn func(ns string) CreateToken, secretsLister corelisters.SecretLister, issuer v1.GenericIssuer) (Interface, error) {
 	v := &Vault{
+		createToken:   createTokenFn(namespace),
 		secretsLister: secretsLister,
 		namespace:     namespace,
 		issuer:        issuer,
@@ -91,7 +123,13 @@ func New(namespace string, secretsLister corelisters.SecretLister, issuer v1.Gen
 		return nil, fmt.Errorf("error initializing Vault client: %s", err.Error())
 	}
 
-	if err := v.setToken(client); err != nil {
+	if len(issuer.GetSpec().Vault.AdditionalServers) > 0 {
+		if err := v.selectHealthyServer(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.setToken(ctx, client); err != nil {
 		return nil, err
 	}
 
@@ -100,37 +138,79 @@ func New(namespace string, secretsLister corelisters.SecretLister, issuer v1.Gen
 	return v, nil
 }
 
+// RequestedNamespace checks the Vault namespace requested by a
+// CertificateRequest or CertificateSigningRequest against the issuer's
+// allowedNamespaces. An empty requested namespace is always allowed and means
+// that the issuer's namespace is used.
+func RequestedNamespace(vaultIssuer *v1.VaultIssuer, requested string) (string, error) {
+	if requested == "" {
+		return "", nil
+	}
+
+	for _, allowed := range vaultIssuer.AllowedNamespaces {
+		if requested == allowed {
+			return requested, nil
+		}
+	}
+
+	return "", fmt.Errorf("Vault namespace %q is not listed in the issuer's allowedNamespaces", requested)
+}
+
 // Sign will connect to a Vault instance to sign a certificate signing request.
-func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []byte, err error) {
+// If namespace is not empty, the request is sent to that Vault namespace
+// instead of the issuer's namespace.
+func (v *Vault) Sign(ctx context.Context, csrPEM []byte, duration time.Duration, namespace string) (cert []byte, ca []byte, err error) {
 	csr, err := pki.DecodeX509CertificateRequestBytes(csrPEM)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to decode CSR for signing: %s", err)
 	}
 
-	parameters := map[string]string{
-		"common_name": csr.Subject.CommonName,
-		"alt_names":   strings.Join(csr.DNSNames, ","),
-		"ip_sans":     strings.Join(pki.IPAddressesToString(csr.IPAddresses), ","),
-		"uri_sans":    strings.Join(pki.URLsToString(csr.URIs), ","),
-		"ttl":         duration.String(),
-		"csr":         string(csrPEM),
+	vaultIssuer := v.issuer.GetSpec().Vault
 
-		"exclude_cn_from_sans": "true",
+	var parameters map[string]string
+	var url string
+	if vaultIssuer.SignVerbatim {
+		parameters, err = signVerbatimParameters(csr, csrPEM, duration)
+		if err != nil {
+			return nil, nil, err
+		}
+		url = path.Join("/v1", signVerbatimPath(vaultIssuer))
+	} else {
+		parameters = map[string]string{
+			"common_name": csr.Subject.CommonName,
+			"alt_names":   strings.Join(csr.DNSNames, ","),
+			"ip_sans":     strings.Join(pki.IPAddressesToString(csr.IPAddresses), ","),
+			"uri_sans":    strings.Join(pki.URLsToString(csr.URIs), ","),
+			"ttl":         duration.String(),
+			"csr":         string(csrPEM),
+
+			"exclude_cn_from_sans": "true",
+		}
+		url = path.Join("/v1", vaultIssuer.Path)
 	}
 
-	vaultIssuer := v.issuer.GetSpec().Vault
-	url := path.Join("/v1", vaultIssuer.Path)
-
 	request := v.client.NewRequest("POST", url)
 
 	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+
+	if namespace != "" {
+		if request.Headers == nil {
+			request.Headers = http.Header{}
+		}
+		request.Headers.Set("X-VAULT-NAMESPACE", namespace)
+	}
 
 	if err := request.SetJSONBody(parameters); err != nil {
 		return nil, nil, fmt.Errorf("failed to build vault request: %s", err)
 	}
 
-	resp, err := v.client.RawRequest(request)
+	resp, err := v.client.RawRequestWithContext(ctx, request)
 	if err != nil {
+		var respErr *vault.ResponseError
+		if errors.As(err, &respErr) && isPolicyViolation(respErr) {
+			return nil, nil, &PolicyError{StatusCode: respErr.StatusCode, Errors: respErr.Errors}
+		}
 		return nil, nil, fmt.Errorf("failed to sign certificate by vault: %s", err)
 	}
 
@@ -145,7 +225,7 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 	return extractCertificatesFromVaultCertificateSecret(&vaultResult)
 }
 
-func (v *Vault) setToken(client Client) error {
+func (v *Vault) setToken(ctx context.Context, client Client) error {
 	tokenRef := v.issuer.GetSpec().Vault.Auth.TokenSecretRef
 	if tokenRef != nil {
 		token, err := v.tokenRef(tokenRef.Name, v.namespace, tokenRef.Key)
@@ -159,7 +239,7 @@ func (v *Vault) setToken(client Client) error {
 
 	appRole := v.issuer.GetSpec().Vault.Auth.AppRole
 	if appRole != nil {
-		token, err := v.requestTokenWithAppRoleRef(client, appRole)
+		token, err := v.requestTokenWithAppRoleRef(ctx, client, appRole)
 		if err != nil {
 			return err
 		}
@@ -170,8 +250,11 @@ func (v *Vault) setToken(client Client) error {
 
 	kubernetesAuth := v.issuer.GetSpec().Vault.Auth.Kubernetes
 	if kubernetesAuth != nil {
-		token, err := v.requestTokenWithKubernetesAuth(client, kubernetesAuth)
+		token, err := v.requestTokenWithKubernetesAuth(ctx, client, kubernetesAuth)
 		if err != nil {
+			if kubernetesAuth.ServiceAccountRef != nil {
+				return fmt.Errorf("error logging in to Vault using a bound token for service account %s: %s", kubernetesAuth.ServiceAccountRef.Name, err.Error())
+			}
 			return fmt.Errorf("error reading Kubernetes service account token from %s: %s", kubernetesAuth.SecretRef.Name, err.Error())
 		}
 		client.SetToken(token)
@@ -181,6 +264,31 @@ func (v *Vault) setToken(client Client) error {
 	return fmt.Errorf("error initializing Vault client: tokenSecretRef, appRoleSecretRef, or Kubernetes auth role not set")
 }
 
+// selectHealthyServer points the client at the first of the issuer's Vault
+// servers that is initialized and unsealed.
+func (v *Vault) selectHealthyServer(ctx context.Context, client *vault.Client) error {
+	vaultIssuer := v.issuer.GetSpec().Vault
+	servers := append([]string{vaultIssuer.Server}, vaultIssuer.AdditionalServers...)
+
+	var errs []string
+	for _, server := range servers {
+		if err := client.SetAddress(server); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", server, err))
+			continue
+		}
+
+		v.client = client
+		if err := v.IsVaultInitializedAndUnsealed(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", server, err))
+			continue
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no healthy Vault server available: %s", strings.Join(errs, "; "))
+}
+
 func (v *Vault) newConfig() (*vault.Config, error) {
 	cfg := vault.DefaultConfig()
 	cfg.Address = v.issuer.GetSpec().Vault.Server
@@ -243,7 +351,7 @@ func (v *Vault) appRoleRef(appRole *v1.VaultAppRole) (roleId, secretId string, e
 	return roleId, secretId, nil
 }
 
-func (v *Vault) requestTokenWithAppRoleRef(client Client, appRole *v1.VaultAppRole) (string, error) {
+func (v *Vault) requestTokenWithAppRoleRef(ctx context.Context, client Client, appRole *v1.VaultAppRole) (string, error) {
 	roleId, secretId, err := v.appRoleRef(appRole)
 	if err != nil {
 		return "", err
@@ -269,13 +377,15 @@ func (v *Vault) requestTokenWithAppRoleRef(client Client, appRole *v1.VaultAppRo
 	}
 
 	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
 
-	resp, err := client.RawRequest(request)
+	resp, err := client.RawRequestWithContext(ctx, request)
 	if err != nil {
 		return "", fmt.Errorf("error logging in to Vault server: %s", err.Error())
 	}
 
 	defer resp.Body.Close()
+	v.recordVaultIndex(resp)
 
 	vaultResult := vault.Secret{}
 	if err := resp.DecodeJSON(&vaultResult); err != nil {
@@ -294,7 +404,29 @@ func (v *Vault) requestTokenWithAppRoleRef(client Client, appRole *v1.VaultAppRo
 	return token, nil
 }
 
-func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+// kubernetesAuthJWT returns the ServiceAccount JWT used to log in to Vault,
+// either read from the referenced Secret or requested as a short-lived bound
+// token using the TokenRequest API.
+func (v *Vault) kubernetesAuthJWT(ctx context.Context, kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+	if saRef := kubernetesAuth.ServiceAccountRef; saRef != nil {
+		// The issuer-scoped audience is always requested, so that the token
+		// can be bound to this issuer by the Vault role.
+		audiences := append([]string{v.issuerTokenAudience()}, saRef.Audiences...)
+
+		expirationSeconds := int64(boundTokenExpirationSeconds)
+		tokenRequest, err := v.createToken(ctx, saRef.Name, &authv1.TokenRequest{
+			Spec: authv1.TokenRequestSpec{
+				Audiences:         audiences,
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return "", fmt.Errorf("while requesting a token for the service account %s/%s: %s", v.namespace, saRef.Name, err.Error())
+		}
+
+		return tokenRequest.Status.Token, nil
+	}
+
 	secret, err := v.secretsLister.Secrets(v.namespace).Get(kubernetesAuth.SecretRef.Name)
 	if err != nil {
 		return "", err
@@ -310,7 +442,24 @@ func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1
 		return "", fmt.Errorf("no data for %q in secret '%s/%s'", key, v.namespace, kubernetesAuth.SecretRef.Name)
 	}
 
-	jwt := string(keyBytes)
+	return string(keyBytes), nil
+}
+
+// issuerTokenAudience returns the audience included in every bound
+// ServiceAccount token: "vault://<namespace>/<issuer-name>" for Issuers and
+// "vault://<issuer-name>" for ClusterIssuers.
+func (v *Vault) issuerTokenAudience() string {
+	if ns := v.issuer.GetObjectMeta().Namespace; ns != "" {
+		return fmt.Sprintf("vault://%s/%s", ns, v.issuer.GetObjectMeta().Name)
+	}
+	return fmt.Sprintf("vault://%s", v.issuer.GetObjectMeta().Name)
+}
+
+func (v *Vault) requestTokenWithKubernetesAuth(ctx context.Context, client Client, kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+	jwt, err := v.kubernetesAuthJWT(ctx, kubernetesAuth)
+	if err != nil {
+		return "", err
+	}
 
 	parameters := map[string]string{
 		"role": kubernetesAuth.Role,
@@ -330,13 +479,15 @@ func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1
 	}
 
 	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
 
-	resp, err := client.RawRequest(request)
+	resp, err := client.RawRequestWithContext(ctx, request)
 	if err != nil {
 		return "", fmt.Errorf("error calling Vault server: %s", err.Error())
 	}
 
 	defer resp.Body.Close()
+	v.recordVaultIndex(resp)
 	vaultResult := vault.Secret{}
 	err = resp.DecodeJSON(&vaultResult)
 	if err != nil {
@@ -379,10 +530,10 @@ func extractCertificatesFromVaultCertificateSecret(secret *certutil.Secret) ([]b
 	return bundle.ChainPEM, bundle.CAPEM, nil
 }
 
-func (v *Vault) IsVaultInitializedAndUnsealed() error {
+func (v *Vault) IsVaultInitializedAndUnsealed(ctx context.Context) error {
 	healthURL := path.Join("/v1", "sys", "health")
 	healthRequest := v.client.NewRequest("GET", healthURL)
-	healthResp, err := v.client.RawRequest(healthRequest)
+	healthResp, err := v.client.RawRequestWithContext(ctx, healthRequest)
 
 	if healthResp != nil {
 		defer healthResp.Body.Close()
@@ -417,3 +568,391 @@ func (v *Vault) addVaultNamespaceToRequest(request *vault.Request) {
 		}
 	}
 }
+
+func (v *Vault) addConsistencyHeadersToRequest(request *vault.Request) {
+	vaultIssuer := v.issuer.GetSpec().Vault
+	if vaultIssuer == nil || !vaultIssuer.ReadYourWrites {
+		return
+	}
+
+	if request.Headers == nil {
+		request.Headers = http.Header{}
+	}
+	if v.vaultIndex != "" {
+		request.Headers.Set("X-Vault-Index", v.vaultIndex)
+	}
+	request.Headers.Set("X-Vault-Inconsistent", "forward-active-node")
+}
+
+func (v *Vault) recordVaultIndex(resp *vault.Response) {
+	vaultIssuer := v.issuer.GetSpec().Vault
+	if vaultIssuer == nil || !vaultIssuer.ReadYourWrites || resp.Response == nil {
+		return
+	}
+
+	if index := resp.Header.Get("X-Vault-Index"); index != "" {
+		v.vaultIndex = index
+	}
+}
+
+// PolicyError is returned by Sign if Vault refused to sign the certificate
+// signing request because it violates the constraints of the role, e.g. a
+// common name or subject alternative name that the role does not allow.
+// Retrying such a request is not expected to succeed.
+type PolicyError struct {
+	// StatusCode is the HTTP status code of the response returned by Vault.
+	StatusCode int
+
+	// Errors are the error messages returned by Vault.
+	Errors []string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("vault refused to sign the request (HTTP %d): %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// policyViolationMessages are fragments of the error messages returned by the
+// Vault PKI backend if a request violates the constraints of the role.
+var policyViolationMessages = []string{
+	"not allowed by this role",
+	"not allowed in this role",
+	"role requires",
+}
+
+// isPolicyViolation returns true if Vault rejected the request because it is
+// not permitted by the role. Other errors, including permission denied errors
+// which may be caused by an expired token or a Vault policy that has yet to be
+// updated, are expected to be retried.
+func isPolicyViolation(respErr *vault.ResponseError) bool {
+	if respErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	for _, msg := range respErr.Errors {
+		for _, violation := range policyViolationMessages {
+			if strings.Contains(msg, violation) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// signVerbatimPath returns the path of the `sign-verbatim` endpoint used by
+// the issuer. Unless configured explicitly, it is derived from the path of the
+// role's `sign` endpoint.
+func signVerbatimPath(vaultIssuer *v1.VaultIssuer) string {
+	if len(vaultIssuer.SignVerbatimPath) > 0 {
+		return vaultIssuer.SignVerbatimPath
+	}
+
+	segments := strings.Split(vaultIssuer.Path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] == "sign" {
+			segments[i] = "sign-verbatim"
+			break
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// revokePath returns the path of the `revoke` endpoint of the PKI secrets
+// engine mount used by the issuer. The mount is the part of the issuer's path
+// preceding its last `sign`, `sign-verbatim` or `issue` segment, or otherwise
+// the parent of the issuer's path.
+func revokePath(vaultIssuer *v1.VaultIssuer) string {
+	segments := strings.Split(vaultIssuer.Path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		switch segments[i] {
+		case "sign", "sign-verbatim", "issue":
+			return strings.Join(append(segments[:i:i], "revoke"), "/")
+		}
+	}
+
+	return path.Join(path.Dir(vaultIssuer.Path), "revoke")
+}
+
+// Revoke revokes the certificate with the given serial number, in Vault's
+// colon separated hexadecimal format, using the `revoke` endpoint of the PKI
+// secrets engine that issued it.
+func (v *Vault) Revoke(ctx context.Context, serialNumber string) error {
+	request := v.client.NewRequest("POST", path.Join("/v1", revokePath(v.issuer.GetSpec().Vault)))
+	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+
+	if err := request.SetJSONBody(map[string]string{"serial_number": serialNumber}); err != nil {
+		return fmt.Errorf("failed to build vault request: %s", err)
+	}
+
+	resp, err := v.client.RawRequestWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to revoke certificate by vault: %s", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// WriteKV writes data to the secret at secretPath in a KV version 2 secrets
+// engine, creating a new version of the secret. secretPath must include the
+// mount of the secrets engine and its `data` prefix.
+func (v *Vault) WriteKV(ctx context.Context, secretPath string, data map[string]string) error {
+	request := v.client.NewRequest("POST", path.Join("/v1", secretPath))
+	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+
+	if err := request.SetJSONBody(map[string]interface{}{"data": data}); err != nil {
+		return fmt.Errorf("failed to build vault request: %s", err)
+	}
+
+	resp, err := v.client.RawRequestWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to write secret %q to vault: %s", secretPath, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// vaultKeyUsages are the names used by Vault for each key usage, indexed by
+// the bit of the usage in the ASN.1 key usage extension (RFC 5280, 4.2.1.3).
+var vaultKeyUsages = []string{
+	"DigitalSignature",
+	"ContentCommitment",
+	"KeyEncipherment",
+	"DataEncipherment",
+	"KeyAgreement",
+	"CertSign",
+	"CRLSign",
+	"EncipherOnly",
+	"DecipherOnly",
+}
+
+// vaultExtKeyUsages are the names used by Vault for each extended key usage.
+var vaultExtKeyUsages = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "Any",
+	x509.ExtKeyUsageServerAuth:                     "ServerAuth",
+	x509.ExtKeyUsageClientAuth:                     "ClientAuth",
+	x509.ExtKeyUsageCodeSigning:                    "CodeSigning",
+	x509.ExtKeyUsageEmailProtection:                "EmailProtection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "IPSECEndSystem",
+	x509.ExtKeyUsageIPSECTunnel:                    "IPSECTunnel",
+	x509.ExtKeyUsageIPSECUser:                      "IPSECUser",
+	x509.ExtKeyUsageTimeStamping:                   "TimeStamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSPSigning",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "MicrosoftServerGatedCrypto",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "NetscapeServerGatedCrypto",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "MicrosoftCommercialCodeSigning",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "MicrosoftKernelCodeSigning",
+}
+
+// signVerbatimParameters builds the parameters of a request to the
+// `sign-verbatim` endpoint. Vault takes the subject and subject alternative
+// names from the CSR itself, while the key usages requested in the CSR
+// extensions have to be passed explicitly for Vault not to replace them with
+// its defaults.
+func signVerbatimParameters(csr *x509.CertificateRequest, csrPEM []byte, duration time.Duration) (map[string]string, error) {
+	parameters := map[string]string{
+		"csr": string(csrPEM),
+		"ttl": duration.String(),
+	}
+
+	for _, ext := range csr.Extensions {
+		switch {
+		case ext.Id.Equal(pki.OIDExtensionKeyUsage):
+			var bits asn1.BitString
+			if _, err := asn1.Unmarshal(ext.Value, &bits); err != nil {
+				return nil, fmt.Errorf("failed to decode CSR key usages: %s", err)
+			}
+
+			var names []string
+			for i, name := range vaultKeyUsages {
+				if bits.At(i) != 0 {
+					names = append(names, name)
+				}
+			}
+			parameters["key_usage"] = strings.Join(names, ",")
+
+		case ext.Id.Equal(pki.OIDExtensionExtendedKeyUsage):
+			var oids []asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(ext.Value, &oids); err != nil {
+				return nil, fmt.Errorf("failed to decode CSR extended key usages: %s", err)
+			}
+
+			var names, unknownOIDs []string
+			for _, oid := range oids {
+				if eku, ok := pki.ExtKeyUsageFromOID(oid); ok {
+					names = append(names, vaultExtKeyUsages[eku])
+				} else {
+					unknownOIDs = append(unknownOIDs, oid.String())
+				}
+			}
+			parameters["ext_key_usage"] = strings.Join(names, ",")
+			if len(unknownOIDs) > 0 {
+				parameters["ext_key_usage_oids"] = strings.Join(unknownOIDs, ",")
+			}
+		}
+	}
+
+	return parameters, nil
+}
+
+// RotateAppRoleSecretID generates a new App Role secret ID if secret ID
+// rotation is configured and the secret ID currently stored in the referenced
+// Secret expires within renewBefore of now, and passes it to store to be
+// stored in the Secret. Once it has been stored the previous secret ID is
+// destroyed, and if it cannot be stored the new secret ID is destroyed
+// instead. It returns true if the new secret ID was stored, which may be
+// along with an error if the previous secret ID could not be destroyed.
+func (v *Vault) RotateAppRoleSecretID(ctx context.Context, now time.Time, store func(secretID string) error) (bool, error) {
+	appRole := v.issuer.GetSpec().Vault.Auth.AppRole
+	if appRole == nil || appRole.SecretIDRotation == nil {
+		return false, nil
+	}
+	rotation := appRole.SecretIDRotation
+
+	_, secretID, err := v.appRoleRef(appRole)
+	if err != nil {
+		return false, err
+	}
+
+	rolePath := v.appRolePath(appRole)
+	expiry, previousAccessor, err := v.lookupSecretID(ctx, path.Join(rolePath, "secret-id", "lookup"), secretID)
+	if err != nil {
+		return false, err
+	}
+
+	renewBefore := defaultSecretIDRenewBefore
+	if rotation.RenewBefore != nil {
+		renewBefore = rotation.RenewBefore.Duration
+	}
+	if expiry.IsZero() || expiry.Sub(now) > renewBefore {
+		return false, nil
+	}
+
+	// Generate the new secret ID wrapped in a single use token, then unwrap it.
+	request := v.client.NewRequest("POST", path.Join(rolePath, "secret-id"))
+	request.WrapTTL = secretIDWrapTTL
+	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+	wrapped, err := v.rawSecret(ctx, request)
+	if err != nil {
+		return false, fmt.Errorf("failed to generate App Role secret ID: %s", err)
+	}
+	if wrapped.WrapInfo == nil || wrapped.WrapInfo.Token == "" {
+		return false, errors.New("failed to generate App Role secret ID: Vault did not return a wrapped response")
+	}
+
+	request = v.client.NewRequest("POST", path.Join("/v1", "sys", "wrapping", "unwrap"))
+	// the unwrapped response must not be wrapped again
+	request.WrapTTL = ""
+	request.ClientToken = wrapped.WrapInfo.Token
+	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+	unwrapped, err := v.rawSecret(ctx, request)
+	if err != nil {
+		return false, fmt.Errorf("failed to unwrap App Role secret ID: %s", err)
+	}
+
+	newSecretID, _ := unwrapped.Data["secret_id"].(string)
+	if newSecretID == "" {
+		return false, errors.New("failed to unwrap App Role secret ID: no secret ID returned")
+	}
+	accessor, _ := unwrapped.Data["secret_id_accessor"].(string)
+
+	if err := store(newSecretID); err != nil {
+		// Don't leave a secret ID behind that nothing knows about.
+		if accessor != "" {
+			if destroyErr := v.destroyAppRoleSecretID(ctx, rolePath, accessor); destroyErr != nil {
+				return false, fmt.Errorf("failed to store App Role secret ID: %s, and %s", err, destroyErr)
+			}
+		}
+		return false, fmt.Errorf("failed to store App Role secret ID: %s", err)
+	}
+
+	if previousAccessor != "" {
+		if err := v.destroyAppRoleSecretID(ctx, rolePath, previousAccessor); err != nil {
+			return true, fmt.Errorf("failed to destroy previous App Role secret ID: %s", err)
+		}
+	}
+
+	return true, nil
+}
+
+// destroyAppRoleSecretID destroys the secret ID of the App Role at rolePath
+// with the given accessor, so that it can no longer be used to log in.
+func (v *Vault) destroyAppRoleSecretID(ctx context.Context, rolePath, accessor string) error {
+	request := v.client.NewRequest("POST", path.Join(rolePath, "secret-id-accessor", "destroy"))
+	if err := request.SetJSONBody(map[string]string{"secret_id_accessor": accessor}); err != nil {
+		return fmt.Errorf("error encoding Vault parameters: %s", err.Error())
+	}
+	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+
+	resp, err := v.client.RawRequestWithContext(ctx, request)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// appRolePath returns the path of the role whose secret IDs are rotated.
+func (v *Vault) appRolePath(appRole *v1.VaultAppRole) string {
+	authPath := appRole.Path
+	if authPath == "" {
+		authPath = "approle"
+	}
+	return path.Join("/v1", "auth", authPath, "role", appRole.SecretIDRotation.RoleName)
+}
+
+// lookupSecretID returns the expiry time of an App Role secret ID, or the
+// zero time if it does not expire, and its accessor.
+func (v *Vault) lookupSecretID(ctx context.Context, lookupPath, secretID string) (time.Time, string, error) {
+	request := v.client.NewRequest("POST", lookupPath)
+	if err := request.SetJSONBody(map[string]string{"secret_id": secretID}); err != nil {
+		return time.Time{}, "", fmt.Errorf("error encoding Vault parameters: %s", err.Error())
+	}
+	v.addVaultNamespaceToRequest(request)
+	v.addConsistencyHeadersToRequest(request)
+
+	lookup, err := v.rawSecret(ctx, request)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("failed to look up App Role secret ID: %s", err)
+	}
+
+	accessor, _ := lookup.Data["secret_id_accessor"].(string)
+	expiration, _ := lookup.Data["expiration_time"].(string)
+	if expiration == "" {
+		return time.Time{}, accessor, nil
+	}
+	expiry, err := time.Parse(time.RFC3339Nano, expiration)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("failed to parse App Role secret ID expiration time %q: %s", expiration, err)
+	}
+	// Vault reports the zero time for secret IDs without a TTL.
+	if expiry.Year() <= 1 {
+		return time.Time{}, accessor, nil
+	}
+
+	return expiry, accessor, nil
+}
+
+// rawSecret sends a request to Vault and decodes the returned secret.
+func (v *Vault) rawSecret(ctx context.Context, request *vault.Request) (*vault.Secret, error) {
+	resp, err := v.client.RawRequestWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret := &vault.Secret{}
+	if err := resp.DecodeJSON(secret); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON payload: %s", err.Error())
+	}
+
+	return secret, nil
+}
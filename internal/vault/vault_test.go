@@ -18,6 +18,7 @@ package vault
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
@@ -29,6 +30,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -36,7 +39,9 @@ import (
 	vault "github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/sdk/helper/certutil"
 	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vaultfake "github.com/jetstack/cert-manager/internal/vault/fake"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -298,7 +303,7 @@ func TestSign(t *testing.T) {
 			client:        test.fakeClient,
 		}
 
-		cert, ca, err := v.Sign(test.csrPEM, time.Minute)
+		cert, ca, err := v.Sign(context.TODO(), test.csrPEM, time.Minute, "")
 		if ((test.expectedErr == nil) != (err == nil)) &&
 			test.expectedErr != nil &&
 			test.expectedErr.Error() != err.Error() {
@@ -331,6 +336,263 @@ func TestSign(t *testing.T) {
 	}
 }
 
+func TestSignInRequestedNamespace(t *testing.T) {
+	privatekey := generateRSAPrivateKey(t)
+	csrPEM := generateCSR(t, privatekey)
+
+	bundleData, err := bundlePEM(testIntermediateCa)
+	if err != nil {
+		t.Fatalf("failed to encode bundle for testing: %s", err)
+	}
+
+	tests := map[string]struct {
+		requested         string
+		expectedNamespace string
+	}{
+		"no requested namespace should use the issuer's namespace": {
+			expectedNamespace: "issuer-ns",
+		},
+		"a requested namespace should be used instead of the issuer's namespace": {
+			requested:         "tenant-a",
+			expectedNamespace: "tenant-a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotNamespaces []string
+			fakeClient := vaultfake.NewFakeClient()
+			fakeClient.RawRequestFn = func(r *vault.Request) (*vault.Response, error) {
+				gotNamespaces = r.Headers.Values("X-VAULT-NAMESPACE")
+				return &vault.Response{Response: &http.Response{
+					Body: io.NopCloser(bytes.NewReader(bundleData)),
+				}}, nil
+			}
+
+			v := &Vault{
+				namespace: "test-namespace",
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(cmapi.VaultIssuer{Namespace: "issuer-ns"}),
+				),
+				client: fakeClient,
+			}
+
+			if _, _, err := v.Sign(context.TODO(), csrPEM, time.Minute, test.requested); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(gotNamespaces, []string{test.expectedNamespace}) {
+				t.Errorf("unexpected Vault namespace header, exp=%s got=%v", test.expectedNamespace, gotNamespaces)
+			}
+		})
+	}
+}
+
+func TestSignVerbatim(t *testing.T) {
+	privatekey := generateRSAPrivateKey(t)
+	template, err := pki.GenerateCSR(gen.Certificate("test",
+		gen.SetCertificateCommonName("test"),
+		gen.SetCertificateDNSNames("example.com"),
+		gen.SetCertificateKeyUsages(cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageServerAuth, cmapi.UsageClientAuth),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrDER, err := pki.EncodeCSR(template, privatekey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	bundleData, err := bundlePEM(testIntermediateCa)
+	if err != nil {
+		t.Fatalf("failed to encode bundle for testing: %s", err)
+	}
+
+	tests := map[string]struct {
+		resp          *vault.Response
+		respErr       error
+		expParameters map[string]string
+		expErr        error
+	}{
+		"should request the key usages of the CSR": {
+			resp: &vault.Response{Response: &http.Response{
+				Body: io.NopCloser(bytes.NewReader(bundleData)),
+			}},
+			expParameters: map[string]string{
+				"csr":           string(csrPEM),
+				"ttl":           "1m0s",
+				"key_usage":     "DigitalSignature,KeyEncipherment",
+				"ext_key_usage": "ServerAuth,ClientAuth",
+			},
+		},
+		"should return a policy error if the request is not allowed by the role": {
+			respErr: &vault.ResponseError{
+				StatusCode: http.StatusBadRequest,
+				Errors:     []string{"common name test not allowed by this role"},
+			},
+			expErr: &PolicyError{
+				StatusCode: http.StatusBadRequest,
+				Errors:     []string{"common name test not allowed by this role"},
+			},
+		},
+		"should not return a policy error if permission is denied": {
+			respErr: &vault.ResponseError{
+				StatusCode: http.StatusForbidden,
+				Errors:     []string{"1 error occurred:", "permission denied"},
+			},
+			expErr: errors.New("failed to sign certificate by vault: Error making API request.\n\nURL:  \nCode: 403. Errors:\n\n* 1 error occurred:* permission denied"),
+		},
+		"should not return a policy error if the request is invalid for another reason": {
+			respErr: &vault.ResponseError{
+				StatusCode: http.StatusBadRequest,
+				Errors:     []string{"error parsing JSON"},
+			},
+			expErr: errors.New("failed to sign certificate by vault: Error making API request.\n\nURL:  \nCode: 400. Errors:\n\n* error parsing JSON"),
+		},
+		"should not return a policy error if Vault fails": {
+			respErr: &vault.ResponseError{
+				StatusCode: http.StatusInternalServerError,
+				Errors:     []string{"internal error"},
+			},
+			expErr: errors.New("failed to sign certificate by vault: Error making API request.\n\nURL:  \nCode: 500. Errors:\n\n* internal error"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotParameters map[string]string
+			fakeClient := vaultfake.NewFakeClient()
+			fakeClient.RawRequestFn = func(r *vault.Request) (*vault.Response, error) {
+				gotParameters = r.Obj.(map[string]string)
+				return test.resp, test.respErr
+			}
+
+			v := &Vault{
+				namespace: "test-namespace",
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(cmapi.VaultIssuer{Path: "pki/sign/role", SignVerbatim: true}),
+				),
+				client: fakeClient,
+			}
+
+			_, _, err := v.Sign(context.TODO(), csrPEM, time.Minute, "")
+			if !reflect.DeepEqual(test.expErr, err) {
+				t.Errorf("unexpected error, exp=%#v got=%#v", test.expErr, err)
+			}
+			if test.expParameters != nil && !reflect.DeepEqual(test.expParameters, gotParameters) {
+				t.Errorf("unexpected parameters, exp=%v got=%v", test.expParameters, gotParameters)
+			}
+		})
+	}
+}
+
+func TestSignVerbatimPath(t *testing.T) {
+	tests := map[string]struct {
+		vaultIssuer cmapi.VaultIssuer
+		expPath     string
+	}{
+		"should derive the path from the sign endpoint of the role": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "pki/sign/role"},
+			expPath:     "pki/sign-verbatim/role",
+		},
+		"should only replace the sign segment": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "sign/sign/sign-role"},
+			expPath:     "sign/sign-verbatim/sign-role",
+		},
+		"should use an explicitly configured path": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "pki/sign/role", SignVerbatimPath: "other/sign-verbatim"},
+			expPath:     "other/sign-verbatim",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := signVerbatimPath(&test.vaultIssuer); got != test.expPath {
+				t.Errorf("unexpected path, exp=%s got=%s", test.expPath, got)
+			}
+		})
+	}
+}
+
+func TestRevokePath(t *testing.T) {
+	tests := map[string]struct {
+		vaultIssuer cmapi.VaultIssuer
+		expPath     string
+	}{
+		"should use the mount of the sign endpoint": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "pki/sign/role"},
+			expPath:     "pki/revoke",
+		},
+		"should use the mount of the sign-verbatim endpoint": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "intermediate/pki/sign-verbatim/role"},
+			expPath:     "intermediate/pki/revoke",
+		},
+		"should only strip the last sign segment": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "sign/sign/sign-role"},
+			expPath:     "sign/revoke",
+		},
+		"should use the mount of the issue endpoint": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "pki/issue/role"},
+			expPath:     "pki/revoke",
+		},
+		"should fall back to the parent of the path": {
+			vaultIssuer: cmapi.VaultIssuer{Path: "pki/custom"},
+			expPath:     "pki/revoke",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := revokePath(&test.vaultIssuer); got != test.expPath {
+				t.Errorf("unexpected path, exp=%s got=%s", test.expPath, got)
+			}
+		})
+	}
+}
+
+func TestRequestedNamespace(t *testing.T) {
+	vaultIssuer := &cmapi.VaultIssuer{
+		Namespace:         "admin",
+		AllowedNamespaces: []string{"admin/tenant-a", "admin/tenant-b"},
+	}
+
+	tests := map[string]struct {
+		requested   string
+		expected    string
+		expectedErr bool
+	}{
+		"no requested namespace is allowed": {
+			requested: "",
+			expected:  "",
+		},
+		"an allowed namespace is returned": {
+			requested: "admin/tenant-b",
+			expected:  "admin/tenant-b",
+		},
+		"a namespace that is not allowed returns an error": {
+			requested:   "admin/tenant-c",
+			expectedErr: true,
+		},
+		"the issuer's own namespace must also be allowed explicitly": {
+			requested:   "admin",
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := RequestedNamespace(vaultIssuer, test.requested)
+			if test.expectedErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.expected {
+				t.Errorf("unexpected namespace, exp=%q got=%q", test.expected, got)
+			}
+		})
+	}
+}
+
 type testExtractCertificatesFromVaultCertT struct {
 	secret       *certutil.Secret
 	expectedCert string
@@ -661,7 +923,7 @@ func TestSetToken(t *testing.T) {
 				issuer:        test.issuer,
 			}
 
-			err := v.setToken(test.fakeClient)
+			err := v.setToken(context.TODO(), test.fakeClient)
 			if ((test.expectedErr == nil) != (err == nil)) &&
 				test.expectedErr != nil &&
 				test.expectedErr.Error() != err.Error() {
@@ -677,6 +939,129 @@ func TestSetToken(t *testing.T) {
 	}
 }
 
+func TestRequestTokenWithKubernetesAuthServiceAccountRef(t *testing.T) {
+	loginResponse := `{"auth":{"client_token":"my-token","lease_duration":3600}}`
+
+	tests := map[string]struct {
+		issuer           cmapi.GenericIssuer
+		createTokenErr   error
+		expectedAudience []string
+		expectedErr      string
+	}{
+		"an Issuer without audiences should request a token for the issuer audience": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerNamespace("test-namespace"),
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					Auth: cmapi.VaultAuth{
+						Kubernetes: &cmapi.VaultKubernetesAuth{
+							Role:              "kube-vault-role",
+							ServiceAccountRef: &cmapi.ServiceAccountRef{Name: "vault-sa"},
+						},
+					},
+				}),
+			),
+			expectedAudience: []string{"vault://test-namespace/vault-issuer"},
+		},
+		"a ClusterIssuer without audiences should request a token for the issuer audience": {
+			issuer: gen.ClusterIssuer("vault-issuer",
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					Auth: cmapi.VaultAuth{
+						Kubernetes: &cmapi.VaultKubernetesAuth{
+							Role:              "kube-vault-role",
+							ServiceAccountRef: &cmapi.ServiceAccountRef{Name: "vault-sa"},
+						},
+					},
+				}),
+			),
+			expectedAudience: []string{"vault://vault-issuer"},
+		},
+		"configured audiences should be requested in addition to the issuer audience": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerNamespace("test-namespace"),
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					Auth: cmapi.VaultAuth{
+						Kubernetes: &cmapi.VaultKubernetesAuth{
+							Role: "kube-vault-role",
+							ServiceAccountRef: &cmapi.ServiceAccountRef{
+								Name:      "vault-sa",
+								Audiences: []string{"https://vault.example.com"},
+							},
+						},
+					},
+				}),
+			),
+			expectedAudience: []string{"vault://test-namespace/vault-issuer", "https://vault.example.com"},
+		},
+		"a failed token request should return an error": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerNamespace("test-namespace"),
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					Auth: cmapi.VaultAuth{
+						Kubernetes: &cmapi.VaultKubernetesAuth{
+							Role:              "kube-vault-role",
+							ServiceAccountRef: &cmapi.ServiceAccountRef{Name: "vault-sa"},
+						},
+					},
+				}),
+			),
+			createTokenErr:   errors.New("forbidden"),
+			expectedAudience: []string{"vault://test-namespace/vault-issuer"},
+			expectedErr:      "while requesting a token for the service account test-namespace/vault-sa: forbidden",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var loginParameters map[string]string
+			fakeClient := vaultfake.NewFakeClient()
+			fakeClient.RawRequestFn = func(r *vault.Request) (*vault.Response, error) {
+				loginParameters = r.Obj.(map[string]string)
+				return &vault.Response{Response: &http.Response{
+					Body: io.NopCloser(strings.NewReader(loginResponse)),
+				}}, nil
+			}
+
+			v := &Vault{
+				namespace: "test-namespace",
+				issuer:    test.issuer,
+				createToken: func(_ context.Context, saName string, req *authv1.TokenRequest, _ metav1.CreateOptions) (*authv1.TokenRequest, error) {
+					if saName != "vault-sa" {
+						t.Errorf("unexpected service account name: %s", saName)
+					}
+					if !reflect.DeepEqual(req.Spec.Audiences, test.expectedAudience) {
+						t.Errorf("unexpected audiences, exp=%v got=%v", test.expectedAudience, req.Spec.Audiences)
+					}
+					if req.Spec.ExpirationSeconds == nil || *req.Spec.ExpirationSeconds != boundTokenExpirationSeconds {
+						t.Errorf("unexpected expiration seconds: %v", req.Spec.ExpirationSeconds)
+					}
+					if test.createTokenErr != nil {
+						return nil, test.createTokenErr
+					}
+					return &authv1.TokenRequest{Status: authv1.TokenRequestStatus{Token: "bound-jwt"}}, nil
+				},
+			}
+
+			token, err := v.requestTokenWithKubernetesAuth(context.TODO(), fakeClient, test.issuer.GetSpec().Vault.Auth.Kubernetes)
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("unexpected error, exp=%s got=%v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if token != "my-token" {
+				t.Errorf("unexpected token, exp=my-token got=%s", token)
+			}
+			if loginParameters["jwt"] != "bound-jwt" {
+				t.Errorf("expected the bound token to be used to log in, got %q", loginParameters["jwt"])
+			}
+		})
+	}
+}
+
 type testAppRoleRefT struct {
 	expectedRoleID   string
 	expectedSecretID string
@@ -1066,7 +1451,7 @@ func TestRequestTokenWithAppRoleRef(t *testing.T) {
 				),
 			}
 
-			token, err := v.requestTokenWithAppRoleRef(test.client, test.appRole)
+			token, err := v.requestTokenWithAppRoleRef(context.TODO(), test.client, test.appRole)
 			if ((test.expectedErr == nil) != (err == nil)) &&
 				test.expectedErr != nil &&
 				test.expectedErr.Error() != err.Error() {
@@ -1081,3 +1466,366 @@ func TestRequestTokenWithAppRoleRef(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectHealthyServer(t *testing.T) {
+	sealed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 501 = not initialized, which is not retried by the Vault client
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer sealed.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	tests := map[string]struct {
+		server            string
+		additionalServers []string
+		expectedAddress   string
+		expectedErr       bool
+	}{
+		"the primary server is used if it is healthy": {
+			server:            healthy.URL,
+			additionalServers: []string{sealed.URL},
+			expectedAddress:   healthy.URL,
+		},
+		"the first healthy additional server is used if the primary is unhealthy": {
+			server:            sealed.URL,
+			additionalServers: []string{sealed.URL, healthy.URL},
+			expectedAddress:   healthy.URL,
+		},
+		"an error is returned if no server is healthy": {
+			server:            sealed.URL,
+			additionalServers: []string{sealed.URL},
+			expectedErr:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := &Vault{
+				namespace: "test-namespace",
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Server:            test.server,
+						AdditionalServers: test.additionalServers,
+					}),
+				),
+			}
+
+			client, err := vault.NewClient(vault.DefaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = v.selectHealthyServer(context.TODO(), client)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+			if test.expectedErr {
+				return
+			}
+
+			if client.Address() != test.expectedAddress {
+				t.Errorf("unexpected Vault address, exp=%q got=%q", test.expectedAddress, client.Address())
+			}
+		})
+	}
+}
+
+func TestReadYourWrites(t *testing.T) {
+	privatekey := generateRSAPrivateKey(t)
+	csrPEM := generateCSR(t, privatekey)
+
+	bundleData, err := bundlePEM(testIntermediateCa)
+	if err != nil {
+		t.Fatalf("failed to encode bundle for testing: %s", err)
+	}
+
+	appRole := &cmapi.VaultAppRole{
+		RoleId: "test-role-id",
+		SecretRef: cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{
+				Name: "test-secret",
+			},
+			Key: "my-key",
+		},
+	}
+	secretsLister := listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+		listers.SetFakeSecretNamespaceListerGet(
+			&corev1.Secret{
+				Data: map[string][]byte{
+					"my-key": []byte("my-key-data"),
+				},
+			}, nil),
+	)
+
+	for _, readYourWrites := range []bool{true, false} {
+		t.Run(fmt.Sprintf("readYourWrites=%t", readYourWrites), func(t *testing.T) {
+			// the fake client returns the same Request for every call, so
+			// headers are copied as each request is sent
+			var requests []http.Header
+			client := vaultfake.NewFakeClient()
+			client.RawRequestFn = func(r *vault.Request) (*vault.Response, error) {
+				requests = append(requests, r.Headers.Clone())
+				if len(requests) == 1 {
+					return &vault.Response{
+						Response: &http.Response{
+							Header: http.Header{"X-Vault-Index": []string{"login-index"}},
+							Body: io.NopCloser(strings.NewReader(
+								`{"auth":{"client_token":"my-client-token"}}`)),
+						},
+					}, nil
+				}
+				return &vault.Response{
+					Response: &http.Response{
+						Body: io.NopCloser(bytes.NewReader(bundleData)),
+					},
+				}, nil
+			}
+
+			v := &Vault{
+				namespace:     "test-namespace",
+				secretsLister: secretsLister,
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						ReadYourWrites: readYourWrites,
+						Auth:           cmapi.VaultAuth{AppRole: appRole},
+					}),
+				),
+			}
+
+			if err := v.setToken(context.TODO(), client); err != nil {
+				t.Fatal(err)
+			}
+			v.client = client
+			if _, _, err := v.Sign(context.TODO(), csrPEM, time.Minute, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(requests) != 2 {
+				t.Fatalf("expected a login and a sign request, got %d requests", len(requests))
+			}
+
+			expLoginInconsistent, expSignInconsistent, expSignIndex := "", "", ""
+			if readYourWrites {
+				expLoginInconsistent, expSignInconsistent, expSignIndex = "forward-active-node", "forward-active-node", "login-index"
+			}
+			if got := requests[0].Get("X-Vault-Inconsistent"); got != expLoginInconsistent {
+				t.Errorf("unexpected X-Vault-Inconsistent header on login, exp=%q got=%q", expLoginInconsistent, got)
+			}
+			if got := requests[0].Get("X-Vault-Index"); got != "" {
+				t.Errorf("unexpected X-Vault-Index header on login, got=%q", got)
+			}
+			if got := requests[1].Get("X-Vault-Inconsistent"); got != expSignInconsistent {
+				t.Errorf("unexpected X-Vault-Inconsistent header on sign, exp=%q got=%q", expSignInconsistent, got)
+			}
+			if got := requests[1].Get("X-Vault-Index"); got != expSignIndex {
+				t.Errorf("unexpected X-Vault-Index header on sign, exp=%q got=%q", expSignIndex, got)
+			}
+		})
+	}
+}
+
+func TestSignHonoursContext(t *testing.T) {
+	privatekey := generateRSAPrivateKey(t)
+	csrPEM := generateCSR(t, privatekey)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := vault.DefaultConfig()
+	cfg.Address = server.URL
+	cfg.MaxRetries = 0
+	client, err := vault.NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := &Vault{
+		namespace: "test-namespace",
+		issuer: gen.Issuer("vault-issuer",
+			gen.SetIssuerVault(cmapi.VaultIssuer{Server: server.URL, Path: "pki/sign/role"}),
+		),
+		client: client,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = v.Sign(ctx, csrPEM, time.Minute, "")
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("expected the deadline of the context to be exceeded, got=%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected Sign to return once the deadline was exceeded, took %s", elapsed)
+	}
+}
+
+func TestRotateAppRoleSecretID(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	secretsLister := listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+		listers.SetFakeSecretNamespaceListerGet(
+			&corev1.Secret{
+				Data: map[string][]byte{
+					"my-key": []byte("current-secret-id"),
+				},
+			}, nil),
+	)
+	appRole := func(rotation *cmapi.VaultAppRoleSecretIDRotation) *cmapi.VaultAppRole {
+		return &cmapi.VaultAppRole{
+			RoleId: "test-role-id",
+			SecretRef: cmmeta.SecretKeySelector{
+				LocalObjectReference: cmmeta.LocalObjectReference{
+					Name: "test-secret",
+				},
+				Key: "my-key",
+			},
+			SecretIDRotation: rotation,
+		}
+	}
+	lookup := func(expiration string) string {
+		return fmt.Sprintf(`{"data":{"expiration_time":%q}}`, expiration)
+	}
+	lookupWithAccessor := func(expiration string) string {
+		return fmt.Sprintf(`{"data":{"expiration_time":%q,"secret_id_accessor":"previous-accessor"}}`, expiration)
+	}
+	const (
+		wrapped   = `{"wrap_info":{"token":"wrapping-token"}}`
+		unwrapped = `{"data":{"secret_id":"new-secret-id","secret_id_accessor":"accessor"}}`
+		destroyed = `{}`
+	)
+
+	tests := map[string]struct {
+		appRole      *cmapi.VaultAppRole
+		responses    []string
+		storeErr     error
+		expTokens    []string
+		expSecretID  string
+		expRotated   bool
+		expDestroyed []string
+		expErr       string
+		expRequested int
+	}{
+		"should not rotate if rotation is not configured": {
+			appRole: appRole(nil),
+		},
+		"should not rotate a secret ID that does not expire": {
+			appRole:      appRole(&cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"}),
+			responses:    []string{lookup("0001-01-01T00:00:00Z")},
+			expRequested: 1,
+		},
+		"should not rotate a secret ID that is not about to expire": {
+			appRole:      appRole(&cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"}),
+			responses:    []string{lookup(now.Add(48 * time.Hour).Format(time.RFC3339))},
+			expRequested: 1,
+		},
+		"should rotate a secret ID expiring within the default renew before": {
+			appRole:      appRole(&cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"}),
+			responses:    []string{lookup(now.Add(time.Hour).Format(time.RFC3339)), wrapped, unwrapped},
+			expTokens:    []string{"", "", "wrapping-token"},
+			expSecretID:  "new-secret-id",
+			expRotated:   true,
+			expRequested: 3,
+		},
+		"should rotate a secret ID expiring within the configured renew before": {
+			appRole: appRole(&cmapi.VaultAppRoleSecretIDRotation{
+				RoleName:    "my-role",
+				RenewBefore: &metav1.Duration{Duration: 72 * time.Hour},
+			}),
+			responses:    []string{lookup(now.Add(48 * time.Hour).Format(time.RFC3339)), wrapped, unwrapped},
+			expTokens:    []string{"", "", "wrapping-token"},
+			expSecretID:  "new-secret-id",
+			expRotated:   true,
+			expRequested: 3,
+		},
+		"should destroy the previous secret ID once the new secret ID is stored": {
+			appRole:      appRole(&cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"}),
+			responses:    []string{lookupWithAccessor(now.Add(time.Hour).Format(time.RFC3339)), wrapped, unwrapped, destroyed},
+			expSecretID:  "new-secret-id",
+			expRotated:   true,
+			expDestroyed: []string{"previous-accessor"},
+			expRequested: 4,
+		},
+		"should destroy the new secret ID if it cannot be stored": {
+			appRole:      appRole(&cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"}),
+			responses:    []string{lookupWithAccessor(now.Add(time.Hour).Format(time.RFC3339)), wrapped, unwrapped, destroyed},
+			storeErr:     errors.New("conflict"),
+			expSecretID:  "new-secret-id",
+			expDestroyed: []string{"accessor"},
+			expErr:       "failed to store App Role secret ID: conflict",
+			expRequested: 4,
+		},
+		"should fail if Vault does not wrap the new secret ID": {
+			appRole:      appRole(&cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"}),
+			responses:    []string{lookup(now.Add(time.Hour).Format(time.RFC3339)), `{"data":{"secret_id":"unwrapped"}}`},
+			expErr:       "failed to generate App Role secret ID: Vault did not return a wrapped response",
+			expRequested: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requested int
+			var tokens, destroyed []string
+			client := vaultfake.NewFakeClient()
+			client.RawRequestFn = func(r *vault.Request) (*vault.Response, error) {
+				if requested >= len(test.responses) {
+					t.Fatalf("unexpected request %d", requested+1)
+				}
+				tokens = append(tokens, r.ClientToken)
+				if body, ok := r.Obj.(map[string]string); ok && body["secret_id_accessor"] != "" {
+					destroyed = append(destroyed, body["secret_id_accessor"])
+				}
+				resp := test.responses[requested]
+				requested++
+				return &vault.Response{Response: &http.Response{
+					Body: io.NopCloser(strings.NewReader(resp)),
+				}}, nil
+			}
+
+			v := &Vault{
+				namespace:     "test-namespace",
+				secretsLister: secretsLister,
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Auth: cmapi.VaultAuth{AppRole: test.appRole},
+					}),
+				),
+				client: client,
+			}
+
+			var secretID string
+			rotated, err := v.RotateAppRoleSecretID(context.TODO(), now, func(s string) error {
+				secretID = s
+				return test.storeErr
+			})
+			if (err == nil && test.expErr != "") || (err != nil && err.Error() != test.expErr) {
+				t.Errorf("unexpected error, exp=%q got=%v", test.expErr, err)
+			}
+			if rotated != test.expRotated {
+				t.Errorf("unexpected rotated, exp=%t got=%t", test.expRotated, rotated)
+			}
+			if secretID != test.expSecretID {
+				t.Errorf("unexpected stored secret ID, exp=%q got=%q", test.expSecretID, secretID)
+			}
+			if !reflect.DeepEqual(test.expDestroyed, destroyed) {
+				t.Errorf("unexpected destroyed accessors, exp=%q got=%q", test.expDestroyed, destroyed)
+			}
+			if requested != test.expRequested {
+				t.Errorf("unexpected number of requests, exp=%d got=%d", test.expRequested, requested)
+			}
+			if test.expTokens != nil && !reflect.DeepEqual(test.expTokens, tokens) {
+				t.Errorf("unexpected client tokens, exp=%q got=%q", test.expTokens, tokens)
+			}
+		})
+	}
+}
@@ -177,3 +177,9 @@ func (r *registry) ListClients() map[string]acmecl.Interface {
 	}
 	return out
 }
+
+// PreflightUID returns the UID that the ACME client for the preflight server
+// of the issuer with the given UID is registered under.
+func PreflightUID(uid string) string {
+	return uid + "/preflight"
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+
+	"golang.org/x/crypto/acme"
+
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+// This file implements the account key rollover flow described in RFC 8555
+// section 7.3.5, which is not currently supported by golang.org/x/crypto/acme.
+
+const (
+	badNonceErrorType = "urn:ietf:params:acme:error:badNonce"
+
+	// maxKeyChangeAttempts is the number of times a key change request will
+	// be sent if the ACME server rejects the nonce used to sign it.
+	maxKeyChangeAttempts = 3
+)
+
+// AccountKeyRolloverFunc is a function type for changing the key of an ACME
+// account.
+type AccountKeyRolloverFunc func(ctx context.Context, httpClient *http.Client, dir acme.Directory, accountURL string, oldKey, newKey *rsa.PrivateKey) error
+
+var _ AccountKeyRolloverFunc = AccountKeyRollover
+
+// AccountKeyRollover requests that the ACME server replaces the key of the
+// account at accountURL, currently oldKey, with newKey.
+// If newKey is already the key of the account, for example because a previous
+// rollover succeeded but the new key could not be persisted, nil is returned.
+func AccountKeyRollover(ctx context.Context, httpClient *http.Client, dir acme.Directory, accountURL string, oldKey, newKey *rsa.PrivateKey) error {
+	if dir.KeyChangeURL == "" {
+		return fmt.Errorf("ACME server does not support account key rollover")
+	}
+
+	// The inner JWS is signed by the new key and is not replay protected,
+	// so it does not need to be re-signed if the nonce is rejected.
+	inner, err := signJWS(newKey, map[string]interface{}{
+		"alg": "RS256",
+		"jwk": rsaJWK(&newKey.PublicKey),
+		"url": dir.KeyChangeURL,
+	}, map[string]interface{}{
+		"account": accountURL,
+		"oldKey":  rsaJWK(&oldKey.PublicKey),
+	})
+	if err != nil {
+		return err
+	}
+
+	nonce, err := fetchNonce(ctx, httpClient, dir.NonceURL)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		outer, err := signJWS(oldKey, map[string]interface{}{
+			"alg":   "RS256",
+			"kid":   accountURL,
+			"nonce": nonce,
+			"url":   dir.KeyChangeURL,
+		}, json.RawMessage(inner))
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, dir.KeyChangeURL, bytes.NewReader(outer))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/jose+json")
+		req.Header.Set("User-Agent", util.CertManagerUserAgent)
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return nil
+		case resp.StatusCode == http.StatusConflict && resp.Header.Get("Location") == accountURL:
+			// The new key is already registered to this account.
+			return nil
+		}
+
+		acmeErr := responseError(resp, body)
+		if acmeErr.ProblemType != badNonceErrorType || attempt >= maxKeyChangeAttempts {
+			return acmeErr
+		}
+		if nonce = resp.Header.Get("Replay-Nonce"); nonce == "" {
+			if nonce, err = fetchNonce(ctx, httpClient, dir.NonceURL); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// fetchNonce retrieves a fresh anti-replay nonce from the ACME server.
+func fetchNonce(ctx context.Context, httpClient *http.Client, nonceURL string) (string, error) {
+	if nonceURL == "" {
+		return "", fmt.Errorf("ACME server directory does not contain a newNonce URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, nonceURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", util.CertManagerUserAgent)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	nonce := resp.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		if resp.StatusCode >= 400 {
+			return "", responseError(resp, nil)
+		}
+		return "", fmt.Errorf("ACME server did not return a nonce")
+	}
+
+	return nonce, nil
+}
+
+// responseError converts an error response from the ACME server into an
+// *acme.Error, decoding the problem document in the body if there is one.
+func responseError(resp *http.Response, body []byte) *acme.Error {
+	var problem struct {
+		Type   string `json:"type"`
+		Detail string `json:"detail"`
+	}
+	// Errors are ignored, as the server may not have returned a problem
+	// document.
+	_ = json.Unmarshal(body, &problem)
+
+	return &acme.Error{
+		StatusCode:  resp.StatusCode,
+		ProblemType: problem.Type,
+		Detail:      problem.Detail,
+		Header:      resp.Header,
+	}
+}
+
+// signJWS returns a JWS in flattened JSON serialization, signed with key
+// using RS256.
+func signJWS(key *rsa.PrivateKey, protected map[string]interface{}, payload interface{}) ([]byte, error) {
+	protectedJSON, err := json.Marshal(protected)
+	if err != nil {
+		return nil, err
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedProtected := base64.RawURLEncoding.EncodeToString(protectedJSON)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payloadJSON)
+
+	digest := sha256.Sum256([]byte(encodedProtected + "." + encodedPayload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]string{
+		"protected": encodedProtected,
+		"payload":   encodedPayload,
+		"signature": base64.RawURLEncoding.EncodeToString(sig),
+	})
+}
+
+// rsaJWK returns the JSON Web Key representation of an RSA public key.
+func rsaJWK(pub *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+	}
+}
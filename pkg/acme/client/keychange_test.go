@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/acme"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+type flattenedJWS struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// verifyJWS verifies the signature of a flattened JWS and decodes its
+// protected header and payload.
+func verifyJWS(t *testing.T, raw []byte, pub *rsa.PublicKey, header, payload interface{}) {
+	t.Helper()
+
+	var jws flattenedJWS
+	if err := json.Unmarshal(raw, &jws); err != nil {
+		t.Fatalf("failed to decode JWS: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(jws.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(jws.Protected + "." + jws.Payload))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("invalid JWS signature: %v", err)
+	}
+	for encoded, into := range map[string]interface{}{jws.Protected: header, jws.Payload: payload} {
+		decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(decoded, into); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAccountKeyRollover(t *testing.T) {
+	oldKey := mustGenerateRSAKey(t)
+	newKey := mustGenerateRSAKey(t)
+
+	type protectedHeader struct {
+		Alg   string            `json:"alg"`
+		Kid   string            `json:"kid"`
+		Nonce string            `json:"nonce"`
+		URL   string            `json:"url"`
+		JWK   map[string]string `json:"jwk"`
+	}
+
+	tests := map[string]struct {
+		// responses returned by the key change endpoint, in order.
+		responses []int
+		// location header returned alongside a conflict response.
+		conflictLocation string
+		expectedRequests int
+		expectedErr      error
+	}{
+		"key is changed": {
+			responses:        []int{http.StatusOK},
+			expectedRequests: 1,
+		},
+		"bad nonce is retried": {
+			responses:        []int{http.StatusBadRequest, http.StatusOK},
+			expectedRequests: 2,
+		},
+		"key already belongs to the account": {
+			responses:        []int{http.StatusConflict},
+			conflictLocation: "self",
+			expectedRequests: 1,
+		},
+		"key belongs to a different account": {
+			responses:        []int{http.StatusConflict},
+			conflictLocation: "/acct/2",
+			expectedRequests: 1,
+			expectedErr:      &acme.Error{StatusCode: http.StatusConflict, ProblemType: "urn:ietf:params:acme:error:malformed", Detail: "key in use"},
+		},
+		"bad nonce is not retried forever": {
+			responses:        []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest, http.StatusOK},
+			expectedRequests: maxKeyChangeAttempts,
+			expectedErr:      &acme.Error{StatusCode: http.StatusBadRequest, ProblemType: badNonceErrorType, Detail: "bad nonce"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var server *httptest.Server
+			requests := 0
+			nonces := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("/nonce", func(w http.ResponseWriter, r *http.Request) {
+				nonces++
+				w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", nonces))
+			})
+			mux.HandleFunc("/key-change", func(w http.ResponseWriter, r *http.Request) {
+				keyChangeURL := server.URL + "/key-change"
+				accountURL := server.URL + "/acct/1"
+
+				var outerHeader protectedHeader
+				var innerRaw json.RawMessage
+				body := new(json.RawMessage)
+				if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+					t.Fatal(err)
+				}
+				verifyJWS(t, *body, &oldKey.PublicKey, &outerHeader, &innerRaw)
+
+				expectedNonce := fmt.Sprintf("nonce-%d", requests+1)
+				if outerHeader.Kid != accountURL || outerHeader.URL != keyChangeURL || outerHeader.Nonce != expectedNonce {
+					t.Errorf("unexpected outer JWS header: %+v", outerHeader)
+				}
+
+				var innerHeader protectedHeader
+				var innerPayload struct {
+					Account string            `json:"account"`
+					OldKey  map[string]string `json:"oldKey"`
+				}
+				verifyJWS(t, innerRaw, &newKey.PublicKey, &innerHeader, &innerPayload)
+				if innerHeader.URL != keyChangeURL || !reflect.DeepEqual(innerHeader.JWK, rsaJWK(&newKey.PublicKey)) {
+					t.Errorf("unexpected inner JWS header: %+v", innerHeader)
+				}
+				if innerPayload.Account != accountURL || !reflect.DeepEqual(innerPayload.OldKey, rsaJWK(&oldKey.PublicKey)) {
+					t.Errorf("unexpected inner JWS payload: %+v", innerPayload)
+				}
+
+				status := test.responses[requests]
+				requests++
+				switch status {
+				case http.StatusBadRequest:
+					w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", requests+1))
+					w.WriteHeader(status)
+					fmt.Fprintf(w, `{"type":%q,"detail":"bad nonce"}`, badNonceErrorType)
+				case http.StatusConflict:
+					location := test.conflictLocation
+					if location == "self" {
+						location = accountURL
+					} else {
+						location = server.URL + location
+					}
+					w.Header().Set("Location", location)
+					w.WriteHeader(status)
+					fmt.Fprint(w, `{"type":"urn:ietf:params:acme:error:malformed","detail":"key in use"}`)
+				default:
+					w.WriteHeader(status)
+				}
+			})
+			server = httptest.NewServer(mux)
+			defer server.Close()
+
+			dir := acme.Directory{
+				NonceURL:     server.URL + "/nonce",
+				KeyChangeURL: server.URL + "/key-change",
+			}
+			err := AccountKeyRollover(context.Background(), server.Client(), dir, server.URL+"/acct/1", oldKey, newKey)
+
+			if requests != test.expectedRequests {
+				t.Errorf("expected %d key change requests, got %d", test.expectedRequests, requests)
+			}
+			if test.expectedErr == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			acmeErr, ok := err.(*acme.Error)
+			if !ok {
+				t.Fatalf("expected an ACME error, got: %v", err)
+			}
+			acmeErr.Header = nil
+			if !reflect.DeepEqual(acmeErr, test.expectedErr) {
+				t.Errorf("expected error %#v, got %#v", test.expectedErr, acmeErr)
+			}
+		})
+	}
+}
+
+func TestAccountKeyRolloverNotSupported(t *testing.T) {
+	err := AccountKeyRollover(context.Background(), http.DefaultClient, acme.Directory{}, "https://example.com/acct/1", mustGenerateRSAKey(t), mustGenerateRSAKey(t))
+	if err == nil {
+		t.Error("expected an error when the directory has no keyChange URL")
+	}
+}
+
+func mustGenerateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+// This file implements the renewalInfo resource described in
+// draft-ietf-acme-ari, which is not currently supported by
+// golang.org/x/crypto/acme.
+
+const (
+	// DefaultRenewalInfoRetryAfter is how long renewal information is
+	// considered fresh for if the ACME server does not send a Retry-After
+	// header.
+	DefaultRenewalInfoRetryAfter = 6 * time.Hour
+
+	// minRenewalInfoRetryAfter and maxRenewalInfoRetryAfter bound the
+	// Retry-After value sent by the ACME server, so that a misbehaving server
+	// can neither cause renewal information to be polled in a tight loop nor
+	// cause incidents to go unnoticed for days.
+	minRenewalInfoRetryAfter = time.Minute
+	maxRenewalInfoRetryAfter = 24 * time.Hour
+)
+
+// ErrRenewalInfoNotSupported is returned by GetRenewalInfo if the ACME
+// server's directory does not advertise a renewalInfo endpoint.
+var ErrRenewalInfoNotSupported = errors.New("ACME server does not support ACME Renewal Information")
+
+// RenewalWindow is the time window during which the ACME server suggests a
+// certificate is renewed.
+type RenewalWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// RenewalInfo is the renewal information published by an ACME server for a
+// single certificate.
+type RenewalInfo struct {
+	// SuggestedWindow is the window during which the certificate should be
+	// renewed. A window in the past indicates the certificate should be
+	// renewed immediately, e.g. because it is going to be revoked.
+	SuggestedWindow RenewalWindow `json:"suggestedWindow"`
+
+	// ExplanationURL optionally points to a page explaining why the
+	// suggested window was chosen. It is usually only set by ACME servers
+	// when an incident requires certificates to be renewed early.
+	ExplanationURL string `json:"explanationURL,omitempty"`
+
+	// RetryAfter is how long the renewal information should be considered
+	// fresh for before polling the ACME server again.
+	RetryAfter time.Duration `json:"-"`
+}
+
+// RenewalInfoFunc is a function type for fetching the renewal information
+// for a certificate from an ACME server.
+type RenewalInfoFunc func(ctx context.Context, httpClient *http.Client, directoryURL string, cert *x509.Certificate) (*RenewalInfo, error)
+
+var _ RenewalInfoFunc = GetRenewalInfo
+
+// GetRenewalInfo fetches the renewal information for the given certificate
+// from the ACME server with the given directory URL. ErrRenewalInfoNotSupported
+// is returned if the ACME server does not implement ACME Renewal Information.
+func GetRenewalInfo(ctx context.Context, httpClient *http.Client, directoryURL string, cert *x509.Certificate) (*RenewalInfo, error) {
+	certID, err := RenewalInfoCertID(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	var dir struct {
+		RenewalInfo string `json:"renewalInfo"`
+	}
+	if _, err := getJSON(ctx, httpClient, directoryURL, &dir); err != nil {
+		return nil, fmt.Errorf("failed to fetch ACME server directory: %w", err)
+	}
+	if dir.RenewalInfo == "" {
+		return nil, ErrRenewalInfoNotSupported
+	}
+
+	info := &RenewalInfo{}
+	resp, err := getJSON(ctx, httpClient, strings.TrimSuffix(dir.RenewalInfo, "/")+"/"+certID, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch renewal information: %w", err)
+	}
+	if info.SuggestedWindow.Start.IsZero() || !info.SuggestedWindow.End.After(info.SuggestedWindow.Start) {
+		return nil, fmt.Errorf("ACME server returned an invalid suggested renewal window [%s, %s]",
+			info.SuggestedWindow.Start.Format(time.RFC3339), info.SuggestedWindow.End.Format(time.RFC3339))
+	}
+	info.RetryAfter = ParseRenewalInfoRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+	return info, nil
+}
+
+// RenewalInfoCertID returns the unique identifier used to request renewal
+// information for a certificate: the base64url-encoded key identifier of its
+// Authority Key Identifier extension and the base64url-encoded DER bytes of
+// its serial number, joined by a '.'.
+func RenewalInfoCertID(cert *x509.Certificate) (string, error) {
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", fmt.Errorf("certificate does not have an Authority Key Identifier")
+	}
+	if cert.SerialNumber == nil || cert.SerialNumber.Sign() <= 0 {
+		return "", fmt.Errorf("certificate does not have a valid serial number")
+	}
+
+	// The serial number is a positive INTEGER, so its DER encoding is
+	// prefixed with a zero byte if the most significant bit is set.
+	serial := cert.SerialNumber.Bytes()
+	if serial[0]&0x80 != 0 {
+		serial = append([]byte{0}, serial...)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cert.AuthorityKeyId) + "." +
+		base64.RawURLEncoding.EncodeToString(serial), nil
+}
+
+// getJSON performs an unauthenticated GET request to the given URL and
+// decodes the JSON response into v.
+func getJSON(ctx context.Context, httpClient *http.Client, url string, v interface{}) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", util.CertManagerUserAgent)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return resp, nil
+}
+
+// ParseRenewalInfoRetryAfter parses the value of a Retry-After header sent
+// by an ACME server in response to a renewal information request, which is
+// either a number of seconds or an HTTP date, and bounds it to a sensible
+// range.
+func ParseRenewalInfoRetryAfter(value string, now time.Time) time.Duration {
+	retryAfter := DefaultRenewalInfoRetryAfter
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = date.Sub(now)
+	}
+
+	switch {
+	case retryAfter < minRenewalInfoRetryAfter:
+		return minRenewalInfoRetryAfter
+	case retryAfter > maxRenewalInfoRetryAfter:
+		return maxRenewalInfoRetryAfter
+	}
+	return retryAfter
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+func TestRenewalInfoCertID(t *testing.T) {
+	tests := map[string]struct {
+		cert   *x509.Certificate
+		exp    string
+		expErr bool
+	}{
+		// Example taken from draft-ietf-acme-ari.
+		"should encode key identifier and serial number": {
+			cert: &x509.Certificate{
+				AuthorityKeyId: []byte{0x69, 0x88, 0x5b, 0x6b, 0x87, 0x46, 0x40, 0x41, 0xe1, 0xb3, 0x7b, 0x84, 0x7b, 0xa0, 0xae, 0x2c, 0xde, 0x01, 0xc8, 0xd4},
+				SerialNumber:   new(big.Int).SetBytes([]byte{0x00, 0x87, 0x65, 0x43, 0x21}),
+			},
+			exp: "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE",
+		},
+		"should not prefix serial number without most significant bit set": {
+			cert: &x509.Certificate{
+				AuthorityKeyId: []byte{0x01},
+				SerialNumber:   big.NewInt(0x7f),
+			},
+			exp: "AQ.fw",
+		},
+		"should error if there is no authority key identifier": {
+			cert:   &x509.Certificate{SerialNumber: big.NewInt(1)},
+			expErr: true,
+		},
+		"should error if there is no serial number": {
+			cert:   &x509.Certificate{AuthorityKeyId: []byte{0x01}},
+			expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := RenewalInfoCertID(test.cert)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if got != test.exp {
+				t.Errorf("unexpected cert ID, exp=%q got=%q", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestGetRenewalInfo(t *testing.T) {
+	cert := &x509.Certificate{
+		AuthorityKeyId: []byte{0x01},
+		SerialNumber:   big.NewInt(0x7f),
+	}
+	start := time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		directory  string
+		status     int
+		retryAfter string
+		body       string
+
+		expInfo *RenewalInfo
+		expErr  error
+	}{
+		"should return the suggested window": {
+			directory:  `{"renewalInfo": "%s/renewal-info/"}`,
+			status:     http.StatusOK,
+			retryAfter: "3600",
+			body:       `{"suggestedWindow": {"start": "2021-01-03T00:00:00Z", "end": "2021-01-07T00:00:00Z"}, "explanationURL": "https://example.com/incident"}`,
+			expInfo: &RenewalInfo{
+				SuggestedWindow: RenewalWindow{Start: start, End: end},
+				ExplanationURL:  "https://example.com/incident",
+				RetryAfter:      time.Hour,
+			},
+		},
+		"should default and bound Retry-After": {
+			directory:  `{"renewalInfo": "%s/renewal-info"}`,
+			status:     http.StatusOK,
+			retryAfter: "1",
+			body:       `{"suggestedWindow": {"start": "2021-01-03T00:00:00Z", "end": "2021-01-07T00:00:00Z"}}`,
+			expInfo: &RenewalInfo{
+				SuggestedWindow: RenewalWindow{Start: start, End: end},
+				RetryAfter:      minRenewalInfoRetryAfter,
+			},
+		},
+		"should return ErrRenewalInfoNotSupported if not in the directory": {
+			directory: `{"newNonce": "%s/nonce"}`,
+			expErr:    ErrRenewalInfoNotSupported,
+		},
+		"should return an ACME error if the certificate is not known": {
+			directory: `{"renewalInfo": "%s/renewal-info"}`,
+			status:    http.StatusNotFound,
+			body:      `{"type": "urn:ietf:params:acme:error:malformed", "detail": "unknown certificate"}`,
+			expErr: &acme.Error{
+				StatusCode:  http.StatusNotFound,
+				ProblemType: "urn:ietf:params:acme:error:malformed",
+				Detail:      "unknown certificate",
+			},
+		},
+		"should error if the suggested window is invalid": {
+			directory: `{"renewalInfo": "%s/renewal-info"}`,
+			status:    http.StatusOK,
+			body:      `{"suggestedWindow": {"start": "2021-01-07T00:00:00Z", "end": "2021-01-03T00:00:00Z"}}`,
+			expErr:    errors.New("ACME server returned an invalid suggested renewal window [2021-01-07T00:00:00Z, 2021-01-03T00:00:00Z]"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/directory":
+					fmt.Fprintf(w, test.directory, server.URL)
+				case "/renewal-info/AQ.fw":
+					if test.retryAfter != "" {
+						w.Header().Set("Retry-After", test.retryAfter)
+					}
+					w.WriteHeader(test.status)
+					fmt.Fprint(w, test.body)
+				default:
+					t.Errorf("unexpected request to %q", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			info, err := GetRenewalInfo(context.TODO(), server.Client(), server.URL+"/directory", cert)
+			switch {
+			case test.expErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case test.expErr != nil:
+				if err == nil {
+					t.Fatalf("expected error %v, got none", test.expErr)
+				}
+				var acmeErr *acme.Error
+				if expACMEErr, ok := test.expErr.(*acme.Error); ok {
+					if !errors.As(err, &acmeErr) || acmeErr.StatusCode != expACMEErr.StatusCode ||
+						acmeErr.ProblemType != expACMEErr.ProblemType || acmeErr.Detail != expACMEErr.Detail {
+						t.Errorf("unexpected error, exp=%v got=%v", test.expErr, err)
+					}
+				} else if !errors.Is(err, test.expErr) && err.Error() != test.expErr.Error() {
+					t.Errorf("unexpected error, exp=%v got=%v", test.expErr, err)
+				}
+				return
+			}
+
+			if !info.SuggestedWindow.Start.Equal(test.expInfo.SuggestedWindow.Start) ||
+				!info.SuggestedWindow.End.Equal(test.expInfo.SuggestedWindow.End) ||
+				info.ExplanationURL != test.expInfo.ExplanationURL ||
+				info.RetryAfter != test.expInfo.RetryAfter {
+				t.Errorf("unexpected renewal info, exp=%+v got=%+v", test.expInfo, info)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		value string
+		exp   time.Duration
+	}{
+		"empty value uses the default":   {value: "", exp: DefaultRenewalInfoRetryAfter},
+		"invalid value uses the default": {value: "soon", exp: DefaultRenewalInfoRetryAfter},
+		"seconds":                        {value: "7200", exp: 2 * time.Hour},
+		"HTTP date":                      {value: "Fri, 01 Jan 2021 03:00:00 GMT", exp: 3 * time.Hour},
+		"bounded to a minimum":           {value: "0", exp: minRenewalInfoRetryAfter},
+		"bounded to a maximum":           {value: "604800", exp: maxRenewalInfoRetryAfter},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ParseRenewalInfoRetryAfter(test.value, now); got != test.exp {
+				t.Errorf("unexpected duration, exp=%s got=%s", test.exp, got)
+			}
+		})
+	}
+}
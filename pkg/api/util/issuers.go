@@ -18,9 +18,12 @@ package util
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/spiffe"
 )
 
 const (
@@ -36,6 +39,9 @@ const (
 	IssuerVenafi string = "venafi"
 )
 
+// IssuerTypes is the list of the names of all built-in issuer types.
+var IssuerTypes = []string{IssuerACME, IssuerCA, IssuerVault, IssuerSelfSigned, IssuerVenafi}
+
 // NameForIssuer determines the name of the Issuer implementation given an
 // Issuer resource.
 func NameForIssuer(i cmapi.GenericIssuer) (string, error) {
@@ -61,3 +67,111 @@ func IssuerKind(ref cmmeta.ObjectReference) string {
 	}
 	return ref.Kind
 }
+
+// IssuerAllowsWildcards returns whether the given issuer may issue
+// certificates for wildcard DNS names.
+func IssuerAllowsWildcards(i cmapi.GenericIssuer) bool {
+	allow := i.GetSpec().AllowWildcards
+	return allow == nil || *allow
+}
+
+// WildcardDNSNames returns the DNS names in the given list that contain a
+// wildcard label.
+func WildcardDNSNames(dnsNames []string) []string {
+	var wildcards []string
+	for _, name := range dnsNames {
+		if strings.Contains(name, "*") {
+			wildcards = append(wildcards, name)
+		}
+	}
+	return wildcards
+}
+
+// IssuerTypePolicy restricts the issuer types that may be used in a cluster.
+type IssuerTypePolicy struct {
+	// Allowed is the list of issuer types that may be used. All issuer types
+	// are allowed if it is empty.
+	Allowed []string
+
+	// Denied is the list of issuer types that may not be used. It takes
+	// precedence over Allowed.
+	Denied []string
+}
+
+// Allows returns whether issuers of the given type may be used.
+func (p IssuerTypePolicy) Allows(issuerType string) bool {
+	for _, t := range p.Denied {
+		if t == issuerType {
+			return false
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return true
+	}
+	for _, t := range p.Allowed {
+		if t == issuerType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error if the policy refers to an unknown issuer type.
+func (p IssuerTypePolicy) Validate() error {
+	for _, list := range [][]string{p.Allowed, p.Denied} {
+		for _, t := range list {
+			if !isIssuerType(t) {
+				return fmt.Errorf("unknown issuer type %q: must be one of %s", t, strings.Join(IssuerTypes, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func isIssuerType(name string) bool {
+	for _, t := range IssuerTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SPIFFEPolicyViolations returns the reasons that the given issuer may not
+// issue a certificate with the given URI SANs according to its SPIFFE policy.
+// It returns nil if the issuer does not have a SPIFFE policy.
+func SPIFFEPolicyViolations(i cmapi.GenericIssuer, uris []*url.URL) []string {
+	policy := i.GetSpec().SPIFFE
+	if policy == nil {
+		return nil
+	}
+
+	var violations []string
+	var ids int
+	for _, uri := range uris {
+		if !spiffe.IsID(uri) {
+			continue
+		}
+		ids++
+		if err := spiffe.ValidateID(uri); err != nil {
+			violations = append(violations, err.Error())
+			continue
+		}
+		if !containsString(policy.TrustDomains, uri.Host) {
+			violations = append(violations, fmt.Sprintf("SPIFFE ID %q is not in an allowed trust domain", uri))
+		}
+	}
+	if ids > 0 && len(uris) > 1 {
+		violations = append(violations, "an X.509 SVID must have exactly one URI SAN")
+	}
+	return violations
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net/url"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestIssuerTypePolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy  IssuerTypePolicy
+		allowed []string
+		denied  []string
+		expErr  bool
+	}{
+		"empty policy allows all issuer types": {
+			allowed: IssuerTypes,
+		},
+		"denied types are not allowed": {
+			policy:  IssuerTypePolicy{Denied: []string{IssuerSelfSigned}},
+			allowed: []string{IssuerACME, IssuerCA, IssuerVault, IssuerVenafi},
+			denied:  []string{IssuerSelfSigned},
+		},
+		"only allowed types are allowed": {
+			policy:  IssuerTypePolicy{Allowed: []string{IssuerACME, IssuerVault}},
+			allowed: []string{IssuerACME, IssuerVault},
+			denied:  []string{IssuerCA, IssuerSelfSigned, IssuerVenafi},
+		},
+		"denied types take precedence over allowed types": {
+			policy:  IssuerTypePolicy{Allowed: []string{IssuerACME, IssuerCA}, Denied: []string{IssuerCA}},
+			allowed: []string{IssuerACME},
+			denied:  []string{IssuerCA, IssuerSelfSigned},
+		},
+		"unknown allowed types are invalid": {
+			policy: IssuerTypePolicy{Allowed: []string{"SelfSigned"}},
+			expErr: true,
+		},
+		"unknown denied types are invalid": {
+			policy: IssuerTypePolicy{Denied: []string{"foo"}},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.policy.Validate()
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			for _, issuerType := range test.allowed {
+				if !test.policy.Allows(issuerType) {
+					t.Errorf("expected %q to be allowed", issuerType)
+				}
+			}
+			for _, issuerType := range test.denied {
+				if test.policy.Allows(issuerType) {
+					t.Errorf("expected %q to be denied", issuerType)
+				}
+			}
+		})
+	}
+}
+
+func TestSPIFFEPolicyViolations(t *testing.T) {
+	policy := &cmapi.SPIFFEPolicy{TrustDomains: []string{"cluster.local"}}
+
+	tests := map[string]struct {
+		policy        *cmapi.SPIFFEPolicy
+		uris          []string
+		expViolations int
+	}{
+		"no policy allows any SPIFFE ID": {
+			uris: []string{"spiffe://example.org/ns/sandbox/sa/httpbin", "https://example.org"},
+		},
+		"an ID in an allowed trust domain is allowed": {
+			policy: policy,
+			uris:   []string{"spiffe://cluster.local/ns/sandbox/sa/httpbin"},
+		},
+		"URIs that are not SPIFFE IDs are allowed": {
+			policy: policy,
+			uris:   []string{"https://example.org", "urn:uuid:b3e2b1de-27d0-4a0a-b1e4-1b9c1c5d1d0e"},
+		},
+		"an ID in another trust domain is not allowed": {
+			policy:        policy,
+			uris:          []string{"spiffe://example.org/ns/sandbox/sa/httpbin"},
+			expViolations: 1,
+		},
+		"an invalid ID is not allowed": {
+			policy:        policy,
+			uris:          []string{"spiffe://cluster.local"},
+			expViolations: 1,
+		},
+		"an ID with other URI SANs is not allowed": {
+			policy:        policy,
+			uris:          []string{"spiffe://cluster.local/ns/sandbox/sa/httpbin", "https://example.org"},
+			expViolations: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var uris []*url.URL
+			for _, s := range test.uris {
+				uri, err := url.Parse(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				uris = append(uris, uri)
+			}
+			issuer := &cmapi.Issuer{Spec: cmapi.IssuerSpec{SPIFFE: test.policy}}
+			if violations := SPIFFEPolicyViolations(issuer, uris); len(violations) != test.expViolations {
+				t.Errorf("unexpected violations, exp=%d got=%v", test.expViolations, violations)
+			}
+		})
+	}
+}
@@ -49,6 +49,13 @@ const (
 	// SolverIdentificationLabelKey is added to the labels of a Pod serving an ACME challenge.
 	// Its value will be the "true" if the Pod is an HTTP-01 solver.
 	SolverIdentificationLabelKey = "acme.cert-manager.io/http01-solver"
+
+	// AccountKeyRolloverAnnotationKey can be added to an ACME Issuer or
+	// ClusterIssuer to request that the private key of its ACME account is
+	// replaced with a newly generated key, as described in RFC 8555 section
+	// 7.3.5. Its value is an arbitrary identifier for the rollover, and a new
+	// rollover is performed each time the value changes.
+	AccountKeyRolloverAnnotationKey = "acme.cert-manager.io/account-key-rollover"
 )
 
 const (
@@ -124,6 +124,38 @@ type OrderStatus struct {
 	// This is used to influence garbage collection and back-off.
 	// +optional
 	FailureTime *metav1.Time `json:"failureTime,omitempty"`
+
+	// Preflight contains the status of the Order placed against the issuer's
+	// preflight ACME server before this Order was placed against the issuer's
+	// server. It is only set if the issuer has a preflight server configured
+	// and this is the first Order for the Certificate.
+	// +optional
+	Preflight *OrderPreflightStatus `json:"preflight,omitempty"`
+}
+
+// OrderPreflightStatus is the status of an Order placed against the issuer's
+// preflight ACME server.
+type OrderPreflightStatus struct {
+	// URL of the Order on the preflight ACME server.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Authorizations contains data returned from the preflight ACME server on
+	// what authorizations must be completed in order to validate the DNS names
+	// specified on the Order.
+	// +optional
+	Authorizations []ACMEAuthorization `json:"authorizations,omitempty"`
+
+	// State contains the current state of the Order on the preflight ACME
+	// server. Once it is 'ready', the Order is placed against the issuer's
+	// server.
+	// +optional
+	State State `json:"state,omitempty"`
+
+	// Reason optionally provides more information about why the preflight
+	// Order is in the current state.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // ACMEAuthorization contains data returned from the ACME server on an
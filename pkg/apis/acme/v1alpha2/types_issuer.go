@@ -19,6 +19,7 @@ package v1alpha2
 import (
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -101,6 +102,51 @@ type ACMEIssuer struct {
 	// Defaults to false.
 	// +optional
 	EnableDurationFeature bool `json:"enableDurationFeature,omitempty"`
+
+	// MaintenanceWindows are periods during which DNS01 challenges for this
+	// issuer are not presented, e.g. because the DNS provider is undergoing
+	// scheduled maintenance. Affected challenges remain pending with a reason
+	// describing the maintenance window, and are presented once it has ended.
+	// Challenges that have already been presented are not affected.
+	// +optional
+	MaintenanceWindows []ACMEMaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Preflight configures a second ACME server, such as a staging
+	// environment, against which Orders are placed before being placed
+	// against this issuer's server, if they contain identifiers that no
+	// earlier valid Order for this issuer in the same namespace contained.
+	// This allows misconfigured solvers to be detected without consuming the
+	// rate limits of the production server. The preflight account is
+	// registered using the same private key as this issuer's account.
+	// +optional
+	Preflight *ACMEIssuerPreflight `json:"preflight,omitempty"`
+}
+
+// ACMEIssuerPreflight configures the ACME server that Orders are validated
+// against before being placed against the issuer's server.
+type ACMEIssuerPreflight struct {
+	// Server is the URL used to access the preflight ACME server's 'directory'
+	// endpoint. For example, for Let's Encrypt's staging endpoint, you would
+	// use: "https://acme-staging-v02.api.letsencrypt.org/directory".
+	Server string `json:"server"`
+}
+
+// ACMEMaintenanceWindow is a period during which DNS01 challenges are not
+// presented.
+type ACMEMaintenanceWindow struct {
+	// Start is the time at which the maintenance window begins.
+	Start metav1.Time `json:"start"`
+
+	// End is the time at which the maintenance window ends. If not set, DNS01
+	// challenges are not presented from the start of the window until the
+	// window is removed from the issuer.
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Reason is a human readable description of the maintenance, which is
+	// included in the reason of deferred challenges.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // ACMEExternalAccountBinding is a reference to a CA external account of the ACME
@@ -157,6 +203,13 @@ type ACMEChallengeSolver struct {
 	// performing the DNS01 challenge flow.
 	// +optional
 	DNS01 *ACMEChallengeSolverDNS01 `json:"dns01,omitempty"`
+
+	// Configures cert-manager to attempt to complete authorizations by
+	// performing the TLS-ALPN-01 challenge flow.
+	// It is not possible to obtain certificates for wildcard domain names
+	// (e.g. `*.example.com`) using the TLS-ALPN-01 challenge mechanism.
+	// +optional
+	TLSALPN01 *ACMEChallengeSolverTLSALPN01 `json:"tlsalpn01,omitempty"`
 }
 
 // CertificateDomainSelector selects certificates using a label selector, and
@@ -208,7 +261,8 @@ type ACMEChallengeSolverHTTP01 struct {
 
 	// The Gateway API is a sig-network community API that models service networking
 	// in Kubernetes (https://gateway-api.sigs.k8s.io/). The Gateway solver will
-	// create HTTPRoutes with the specified labels in the same namespace as the challenge.
+	// create HTTPRoutes with the specified labels in the same namespace as the challenge,
+	// attached to the Gateways named in parentRefs.
 	// This solver is experimental, and fields / behaviour may change in the future.
 	// +optional
 	GatewayHTTPRoute *ACMEChallengeSolverHTTP01GatewayHTTPRoute `json:"gatewayHTTPRoute,omitempty"`
@@ -216,7 +270,8 @@ type ACMEChallengeSolverHTTP01 struct {
 
 type ACMEChallengeSolverHTTP01Ingress struct {
 	// Optional service type for Kubernetes solver service. Supported values
-	// are NodePort or ClusterIP. If unset, defaults to NodePort.
+	// are NodePort, ClusterIP or LoadBalancer. If unset, defaults to
+	// NodePort.
 	// +optional
 	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
 
@@ -243,18 +298,71 @@ type ACMEChallengeSolverHTTP01Ingress struct {
 	// ingress used for HTTP01 challenges
 	// +optional
 	IngressTemplate *ACMEChallengeSolverHTTP01IngressTemplate `json:"ingressTemplate,omitempty"`
+
+	// Optional service template used to configure the ACME challenge solver
+	// service used for HTTP01 challenges.
+	// +optional
+	ServiceTemplate *ACMEChallengeSolverHTTP01ServiceTemplate `json:"serviceTemplate,omitempty"`
 }
 
 type ACMEChallengeSolverHTTP01GatewayHTTPRoute struct {
 	// Optional service type for Kubernetes solver service. Supported values
-	// are NodePort or ClusterIP. If unset, defaults to NodePort.
+	// are NodePort, ClusterIP or LoadBalancer. If unset, defaults to
+	// NodePort.
 	// +optional
 	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
 
 	// The labels that cert-manager will use when creating the temporary
-	// HTTPRoute needed for solving the HTTP-01 challenge. These labels
-	// must match the label selector of at least one Gateway.
+	// HTTPRoute needed for solving the HTTP-01 challenge.
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// ParentRefs are the Gateways that the temporary HTTPRoute needed for
+	// solving the HTTP-01 challenge will be attached to. Gateways in the
+	// gateway.networking.k8s.io/v1alpha2 API do not select HTTPRoutes by
+	// label, so solvers that only set labels are deprecated and should set
+	// at least one parentRef.
+	ParentRefs []ACMEChallengeSolverHTTP01GatewayParentRef `json:"parentRefs,omitempty"`
+
+	// Optional service template used to configure the ACME challenge solver
+	// service used for HTTP01 challenges.
+	// +optional
+	ServiceTemplate *ACMEChallengeSolverHTTP01ServiceTemplate `json:"serviceTemplate,omitempty"`
+}
+
+// ACMEChallengeSolverHTTP01GatewayParentRef identifies a Gateway that the
+// HTTPRoute used to solve an HTTP-01 challenge should be attached to.
+type ACMEChallengeSolverHTTP01GatewayParentRef struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. If unset, defaults to the namespace of the
+	// Challenge.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ACMEChallengeSolverTLSALPN01 contains configuration detailing how to solve
+// TLS-ALPN-01 challenges within a Kubernetes cluster.
+// The solver pod serves the 'acme-tls/1' certificate for the domain being
+// validated behind a Service exposing port 443. The infrastructure in front
+// of the cluster must pass TLS connections for the domain through to this
+// Service without terminating them.
+type ACMEChallengeSolverTLSALPN01 struct {
+	// Optional service type for Kubernetes solver service. Supported values
+	// are ClusterIP, NodePort or LoadBalancer. If unset, defaults to ClusterIP.
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// Labels that will be added to the Service created to route TLS-ALPN-01
+	// validation requests to the solver pod, so that TLS passthrough
+	// proxies can discover it.
+	// +optional
+	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
+
+	// Optional pod template used to configure the ACME challenge solver pods
+	// used for TLS-ALPN-01 challenges.
+	// +optional
+	PodTemplate *ACMEChallengeSolverHTTP01IngressPodTemplate `json:"podTemplate,omitempty"`
 }
 
 type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
@@ -326,6 +434,45 @@ type ACMEChallengeSolverHTTP01IngressObjectMeta struct {
 	Labels map[string]string `json:"labels,omitempty"`
 }
 
+// ACMEChallengeSolverHTTP01ServiceTemplate configures the Service created to
+// route HTTP01 challenge requests to the solver pod.
+type ACMEChallengeSolverHTTP01ServiceTemplate struct {
+	// Spec defines overrides for the HTTP01 challenge solver service.
+	// +optional
+	Spec ACMEChallengeSolverHTTP01ServiceSpec `json:"spec,omitempty"`
+}
+
+type ACMEChallengeSolverHTTP01ServiceSpec struct {
+	// NodePortRange is the range of ports from which the node port of the
+	// solver service is allocated if its type is NodePort or LoadBalancer.
+	// Each solver service is allocated a port of the range that is not in use
+	// by another service, so the range must be large enough for all challenges
+	// that are solved at the same time and must be within the cluster's node
+	// port range. If unset, a port is allocated by Kubernetes.
+	// +optional
+	NodePortRange *ACMEChallengeSolverHTTP01NodePortRange `json:"nodePortRange,omitempty"`
+
+	// ExternalTrafficPolicy of the solver service if its type is NodePort or
+	// LoadBalancer. Supported values are Cluster or Local. If unset, defaults
+	// to Cluster.
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+
+	// LoadBalancerClass of the solver service if its type is LoadBalancer.
+	// If unset, the cluster's default load balancer implementation is used.
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+}
+
+// ACMEChallengeSolverHTTP01NodePortRange is an inclusive range of node ports.
+type ACMEChallengeSolverHTTP01NodePortRange struct {
+	// Min is the first port of the range.
+	Min int32 `json:"min"`
+
+	// Max is the last port of the range.
+	Max int32 `json:"max"`
+}
+
 // Used to configure a DNS01 challenge provider to be used when solving DNS01
 // challenges.
 // Only one DNS provider may be configured per solver.
@@ -335,6 +482,22 @@ type ACMEChallengeSolverDNS01 struct {
 	// +optional
 	CNAMEStrategy CNAMEStrategy `json:"cnameStrategy,omitempty"`
 
+	// DelegatedZones is a list of DNS zones that challenge records may be
+	// presented in using the Delegate CNAME strategy, by the DNS01 solver
+	// whose dnsZones selector matches the zone. Records may always be
+	// presented by a solver whose dnsZones selector also matches the domain
+	// being validated.
+	// +optional
+	DelegatedZones []string `json:"delegatedZones,omitempty"`
+
+	// If true, the Secrets referenced by this solver are read from the
+	// namespace of the Challenge being solved rather than the cluster
+	// resource namespace. This allows each namespace to provide its own DNS
+	// provider credentials when using a shared ClusterIssuer.
+	// It has no effect on namespaced Issuers. Defaults to false.
+	// +optional
+	SecretsFromChallengeNamespace bool `json:"secretsFromChallengeNamespace,omitempty"`
+
 	// Use the Akamai DNS zone management API to manage DNS01 challenge records.
 	// +optional
 	Akamai *ACMEIssuerDNS01ProviderAkamai `json:"akamai,omitempty"`
@@ -378,7 +541,7 @@ type ACMEChallengeSolverDNS01 struct {
 // CNAMEStrategy configures how the DNS01 provider should handle CNAME records
 // when found in DNS zones.
 // By default, the None strategy will be applied (i.e. do not follow CNAMEs).
-// +kubebuilder:validation:Enum=None;Follow
+// +kubebuilder:validation:Enum=None;Follow;Delegate
 type CNAMEStrategy string
 
 const (
@@ -392,6 +555,16 @@ const (
 	// root DNS zone, and instead delegate the _acme-challenge.example.com
 	// subdomain to some other, less privileged domain.
 	FollowStrategy = "Follow"
+
+	// DelegateStrategy behaves like FollowStrategy, but additionally
+	// determines the zone that the resolved name has been delegated to (by
+	// following CNAME and NS delegation) and presents the record using the
+	// DNS01 solver on the issuer whose dnsZones selector best matches that
+	// zone. Only solvers whose dnsZones selector also matches the domain
+	// being validated, or zones listed in delegatedZones, are considered.
+	// This allows the _acme-challenge record to live in a zone managed by a
+	// different DNS provider to the one hosting the original domain.
+	DelegateStrategy = "Delegate"
 )
 
 // ACMEIssuerDNS01ProviderAkamai is a structure containing the DNS
@@ -594,4 +767,22 @@ type ACMEIssuerStatus struct {
 	// associated with the  Issuer
 	// +optional
 	LastRegisteredEmail string `json:"lastRegisteredEmail,omitempty"`
+
+	// LastRegisteredEABKeyHash is a hash of the External Account Binding key
+	// ID and HMAC key that were used when the ACME account was last
+	// registered. It is used to detect rotation of the referenced EAB Secret,
+	// in which case the account is registered again using the new key.
+	// +optional
+	LastRegisteredEABKeyHash string `json:"lastRegisteredEABKeyHash,omitempty"`
+
+	// LastAccountKeyRollover is the value of the
+	// `acme.cert-manager.io/account-key-rollover` annotation for which the
+	// ACME account key was last rolled over.
+	// +optional
+	LastAccountKeyRollover string `json:"lastAccountKeyRollover,omitempty"`
+
+	// PreflightURI is the unique account identifier of the ACME account
+	// registered with the preflight ACME server.
+	// +optional
+	PreflightURI string `json:"preflightURI,omitempty"`
 }
@@ -72,6 +72,128 @@ const (
 	// controller only processes Ingresses with this annotation either unset, or
 	// set to either the configured value or the empty string.
 	IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+
+	// IngressCertificateCleanupPolicyAnnotationKey controls what happens to
+	// the Certificates created for an Ingress or Gateway once its
+	// cert-manager.io/issuer or cert-manager.io/cluster-issuer annotation is
+	// removed. It can be set to "Retain" (the default) or "Delete".
+	IngressCertificateCleanupPolicyAnnotationKey = "cert-manager.io/certificate-cleanup-policy"
+
+	// IngressCertificateEditPolicyAnnotationKey controls whether edits made
+	// directly to the Certificates created for an Ingress or Gateway are
+	// reverted ("Revert", the default) or kept until the Ingress or Gateway
+	// itself changes ("Allow").
+	IngressCertificateEditPolicyAnnotationKey = "cert-manager.io/certificate-edit-policy"
+
+	// IngressCertificateSpecHashAnnotationKey is set on Certificates created
+	// with the "Allow" edit policy and records a hash of the Certificate as it
+	// was last derived from its Ingress or Gateway.
+	IngressCertificateSpecHashAnnotationKey = "cert-manager.io/ingress-spec-hash"
+
+	// SubjectOrganizationsAnnotationKey sets the organizations of the subject
+	// of Certificates created for an Ingress or Gateway. The value is a JSON
+	// list of Go templates evaluated against the namespace of the Ingress or
+	// Gateway, e.g. `["{{ .Namespace.Labels.org }}", "Example, Inc."]`.
+	SubjectOrganizationsAnnotationKey = "cert-manager.io/subject-organizations"
+
+	// SubjectOrganizationalUnitsAnnotationKey sets the organizational units
+	// of the subject of Certificates created for an Ingress or Gateway, in
+	// the same format as SubjectOrganizationsAnnotationKey.
+	SubjectOrganizationalUnitsAnnotationKey = "cert-manager.io/subject-organizationalunits"
+
+	// PKCS12SecretPasswordRefAnnotationKey enables a PKCS#12 keystore on
+	// Certificates created for an Ingress or Gateway. The value is a
+	// reference to the password of the keystore of the form
+	// "<secret name>/<key>".
+	PKCS12SecretPasswordRefAnnotationKey = "cert-manager.io/pkcs12-secret-password-ref"
+
+	// JKSSecretPasswordRefAnnotationKey enables a JKS keystore on
+	// Certificates created for an Ingress or Gateway, in the same format as
+	// PKCS12SecretPasswordRefAnnotationKey.
+	JKSSecretPasswordRefAnnotationKey = "cert-manager.io/jks-secret-password-ref"
+
+	// AdditionalOutputFormatsAnnotationKey sets the additional output
+	// formats of Certificates created for an Ingress or Gateway. The value is
+	// a comma separated list of format types, e.g. "DER,CombinedPEM".
+	AdditionalOutputFormatsAnnotationKey = "cert-manager.io/additional-output-formats"
+)
+
+// Values accepted by the IngressCertificateCleanupPolicyAnnotationKey and
+// IngressCertificateEditPolicyAnnotationKey annotations.
+const (
+	CertificateCleanupPolicyRetain = "Retain"
+	CertificateCleanupPolicyDelete = "Delete"
+
+	CertificateEditPolicyRevert = "Revert"
+	CertificateEditPolicyAllow  = "Allow"
+)
+
+// Issuance audit annotations written to the Secret of a Certificate each time
+// a certificate is issued into it, so that the Secret can be traced back to the
+// issuance that produced it. Together with the issuer name, kind and group
+// annotations they identify the CertificateRequest and issuer that signed the
+// stored certificate. They are removed when the Secret holds a certificate
+// that was not issued by cert-manager, such as a temporary certificate or an
+// adopted Secret.
+const (
+	// IssuanceRevisionAnnotationKey is the revision of the Certificate, as a
+	// decimal integer, that the stored certificate was issued for.
+	IssuanceRevisionAnnotationKey = "cert-manager.io/issuance-revision"
+
+	// IssuanceRequestNameAnnotationKey is the name of the CertificateRequest,
+	// in the namespace of the Secret, that the stored certificate was issued
+	// for.
+	IssuanceRequestNameAnnotationKey = "cert-manager.io/issuance-request-name"
+
+	// IssuanceTimeAnnotationKey is the time, in RFC 3339 format, at which the
+	// stored certificate was written to the Secret.
+	IssuanceTimeAnnotationKey = "cert-manager.io/issuance-time"
+
+	// IssuanceChainFingerprintAnnotationKey is the lowercase hex encoded
+	// SHA-256 digest of the DER encoded certificates stored in tls.crt,
+	// concatenated in order. It changes whenever any certificate in the chain
+	// changes.
+	IssuanceChainFingerprintAnnotationKey = "cert-manager.io/issuance-chain-fingerprint"
+)
+
+// CATrustAnchorsAnnotationKey is set on Secrets whose ca.crt contains more
+// than one CA certificate, such as the current and next CA of a planned CA
+// rollover. It is a comma separated list, in the order of ca.crt, of
+// `<fingerprint>=<notBefore>/<notAfter>` entries, where the fingerprint is the
+// lowercase hex encoded SHA-256 digest of the DER encoded certificate, and
+// the validity window is in RFC 3339 format.
+const CATrustAnchorsAnnotationKey = "cert-manager.io/ca-trust-anchors"
+
+// KeystoresHashAnnotationKey is set on Secrets containing PKCS12 or JKS
+// keystores. Its value is a hash of the keystore passwords and of the data
+// the keystores were encoded from, so that the keystores are only re-encoded
+// when either has changed.
+const KeystoresHashAnnotationKey = "cert-manager.io/keystores-hash"
+
+// AdditionalOutputFormatKeysAnnotationKey is the comma separated list of
+// data keys that the additional output formats of a Certificate were last
+// written to in its Secret. It is used to remove the data of formats that are
+// removed from the Certificate, including those written to custom keys.
+const AdditionalOutputFormatKeysAnnotationKey = "cert-manager.io/additional-output-format-keys"
+
+// Annotation names for the Secrets of Certificates when the controller is run
+// with strict Secret ownership.
+const (
+	// SecretUnmanagedDataKeysAnnotationKey is a comma separated list of data
+	// keys of a Certificate's Secret that are not managed by cert-manager, and
+	// so are kept in the Secret rather than being pruned.
+	SecretUnmanagedDataKeysAnnotationKey = "cert-manager.io/unmanaged-data-keys"
+
+	// SecretTemplateLabelsAnnotationKey is the comma separated list of labels
+	// that were last set on a Secret from the Certificate's secretTemplate. It
+	// is used to remove the labels that are removed from the secretTemplate.
+	SecretTemplateLabelsAnnotationKey = "cert-manager.io/secret-template-labels"
+
+	// SecretTemplateAnnotationsAnnotationKey is the comma separated list of
+	// annotations that were last set on a Secret from the Certificate's
+	// secretTemplate. It is used to remove the annotations that are removed
+	// from the secretTemplate.
+	SecretTemplateAnnotationsAnnotationKey = "cert-manager.io/secret-template-annotations"
 )
 
 // Annotation names for CertificateRequests
@@ -85,6 +207,32 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// CertificateRequestIssuerUIDAnnotationKey is the annotation key used to
+	// record the UID of the Issuer or ClusterIssuer that a CertificateRequest
+	// was created for. It is used to detect when an issuer has been deleted
+	// and recreated with the same name.
+	CertificateRequestIssuerUIDAnnotationKey = "cert-manager.io/issuer-uid"
+
+	// CertificateRequestDurationAnnotationKey is the annotation key used to
+	// request a particular duration, represented as a Go Duration, for
+	// CertificateRequests that do not set spec.duration.
+	// It is currently only honoured by the 'self signing' issuer type.
+	CertificateRequestDurationAnnotationKey = "cert-manager.io/request-duration"
+
+	// CertificateRequestMaxPathLenAnnotationKey is the annotation key used to
+	// request the maximum number of intermediate CAs that may follow a CA
+	// certificate in a chain. It may only be set when requesting a CA.
+	// It is currently only honoured by the 'self signing' issuer type.
+	CertificateRequestMaxPathLenAnnotationKey = "cert-manager.io/request-max-path-len"
+
+	// CertificateRequestRenewalReasonAnnotationKey and
+	// CertificateRequestRenewalRequestedByAnnotationKey are added to
+	// CertificateRequests that were created for a manually triggered renewal,
+	// to record the reason for and the requester of the renewal as recorded
+	// in the `status.renewalRequest` of the Certificate.
+	CertificateRequestRenewalReasonAnnotationKey      = "cert-manager.io/renewal-reason"
+	CertificateRequestRenewalRequestedByAnnotationKey = "cert-manager.io/renewal-requested-by"
 )
 
 const (
@@ -94,6 +242,38 @@ const (
 	// stored in the target Secret resource whilst the real Issuer is processing
 	// the certificate request.
 	IssueTemporaryCertificateAnnotation = "cert-manager.io/issue-temporary-certificate"
+
+	// AdoptExistingSecretAnnotation is an annotation that can be added to
+	// Certificate resources.
+	// If set to "true" and the Certificate has not yet been issued, an existing
+	// certificate and private key in the target Secret resource will be
+	// adopted without re-issuance, provided they are valid and match the
+	// Certificate's spec.
+	AdoptExistingSecretAnnotation = "cert-manager.io/adopt-existing-secret"
+
+	// RevokeCertificateAnnotation is an annotation that can be added to
+	// CertificateRequest resources.
+	// If set to "true", the certificate issued for the CertificateRequest is
+	// added to the certificate revocation list of its CA issuer, if the
+	// issuer is configured to maintain one.
+	RevokeCertificateAnnotation = "cert-manager.io/revoke"
+
+	// PrivateKeyExternalRefAnnotationKey is set on CertificateRequests and
+	// Secrets for certificates whose private key is held by an external
+	// signer. Its value identifies the external key that the certificate was
+	// requested or issued for.
+	PrivateKeyExternalRefAnnotationKey = "cert-manager.io/private-key-external-ref"
+
+	// SecretSinkHashesAnnotationKey is set on Certificate resources that have
+	// `spec.secretSinks`. Its value is a JSON object mapping the name of each
+	// secret sink to a hash of the data and configuration last written to it,
+	// so that sinks are only written again when either has changed.
+	SecretSinkHashesAnnotationKey = "cert-manager.io/secret-sink-hashes"
+
+	// RevokeOnDeleteFinalizer is added to Certificate resources that have
+	// `spec.revokeOnDelete` set, so that their certificate can be revoked
+	// before they are deleted.
+	RevokeOnDeleteFinalizer = "cert-manager.io/revoke-on-delete"
 )
 
 // Common/known resource kinds.
@@ -127,6 +307,68 @@ const (
 	// If an injectable references a Secret that does NOT have this annotation,
 	// the cainjector will refuse to inject the secret.
 	AllowsInjectionFromSecretAnnotation = "cert-manager.io/allow-direct-injection"
+
+	// WantInjectIntoFieldAnnotation is the annotation that specifies the field
+	// of a generic injection target that the CA is injected into, e.g.
+	// `spec.caBundle`. Generic injection targets are resources whose kind has
+	// been passed to the cainjector using the `--inject-ca-into-kinds` flag.
+	WantInjectIntoFieldAnnotation = "cert-manager.io/inject-ca-into"
+
+	// WantInjectFormatAnnotation is the annotation that specifies how the CA is
+	// encoded when it is injected into the field of a generic injection target.
+	// One of `base64` (the default, matching the encoding of []byte fields such
+	// as `caBundle`) or `pem`.
+	WantInjectFormatAnnotation = "cert-manager.io/inject-ca-format"
+)
+
+// Pod certificate annotations and conditions
+const (
+	// PodCertificateAnnotationKey is the annotation on a Pod that names a
+	// Certificate in the namespace of the Pod. If pod certificate injection is
+	// enabled in the webhook, the Secret of the Certificate is mounted into
+	// every container of the Pod, and a readiness gate is added to the Pod
+	// that is only satisfied once the Certificate is Ready.
+	PodCertificateAnnotationKey = "cert-manager.io/pod-certificate"
+
+	// PodCertificateMountPathAnnotationKey is the annotation on a Pod that sets
+	// the path that the Secret of the Certificate named by the
+	// `cert-manager.io/pod-certificate` annotation is mounted at.
+	// Defaults to `/var/run/secrets/cert-manager.io/certificate`.
+	PodCertificateMountPathAnnotationKey = "cert-manager.io/pod-certificate-mount-path"
+
+	// PodConditionCertificateReady is the type of the Pod readiness gate
+	// condition that reflects whether the Certificate named by the
+	// `cert-manager.io/pod-certificate` annotation is Ready.
+	PodConditionCertificateReady = "cert-manager.io/certificate-ready"
+)
+
+// Secret update notification annotations
+const (
+	// RestartOnSecretUpdateAnnotationKey is the annotation on a Deployment or
+	// StatefulSet that lists the names of Secrets in the same namespace,
+	// separated by commas. If the secret update notifier controller is
+	// enabled, it records a hash of the certificates in those Secrets as an
+	// annotation on the Pod template of the workload, so that the workload is
+	// rolled out whenever one of the certificates is renewed.
+	RestartOnSecretUpdateAnnotationKey = "cert-manager.io/restart-on-secret-update"
+)
+
+// Controller sharding labels
+const (
+	// ShardLabelKey is the label on a Namespace that assigns the resources in
+	// the namespace to the controller shard with the given index, instead of
+	// the shard chosen by hashing the name of the namespace. It is ignored if
+	// the controller is not sharded or the index is out of range.
+	ShardLabelKey = "cert-manager.io/shard"
+)
+
+// Secret caching labels
+const (
+	// PartOfCertManagerControllerLabelKey is added to the Secrets that
+	// cert-manager creates or updates, e.g. the Secrets of Certificates, so
+	// that the controller can be configured to only cache Secrets with this
+	// label.
+	PartOfCertManagerControllerLabelKey = "controller.cert-manager.io/fao"
 )
 
 // Issuer specific Annotations
@@ -141,6 +383,11 @@ const (
 	// Venafi Pickup ID of a certificate signing request that has been submitted
 	// to the Venafi API for collection later.
 	VenafiPickupIDAnnotationKey = "venafi.cert-manager.io/pickup-id"
+
+	// VaultNamespaceAnnotationKey is the annotation that selects the Vault
+	// namespace a CertificateRequest is signed in. The namespace must be
+	// listed in the Vault issuer's allowedNamespaces.
+	VaultNamespaceAnnotationKey = "vault.cert-manager.io/namespace"
 )
 
 // KeyUsage specifies valid usage contexts for keys.
@@ -91,6 +91,31 @@ type IssuerList struct {
 // configuration required for the issuer.
 type IssuerSpec struct {
 	IssuerConfig `json:",inline"`
+
+	// AllowWildcards controls whether this issuer may issue certificates for
+	// wildcard DNS names, such as `*.example.com`.
+	// Requests for wildcard certificates are failed during issuance if this
+	// is set to false, and are rejected when a Certificate or
+	// CertificateRequest is created if the issuer can be read at that time.
+	// Defaults to true.
+	// +optional
+	AllowWildcards *bool `json:"allowWildcards,omitempty"`
+
+	// SPIFFE restricts the SPIFFE IDs that this issuer may issue X.509 SVIDs
+	// for. Requests with a URI SAN using the spiffe scheme are failed during
+	// issuance if the SPIFFE ID is not in one of the allowed trust domains,
+	// or if the request has any other URI SANs.
+	// If not set, SPIFFE IDs are not restricted.
+	// +optional
+	SPIFFE *SPIFFEPolicy `json:"spiffe,omitempty"`
+}
+
+// SPIFFEPolicy restricts the SPIFFE IDs that an issuer may issue X.509 SVIDs
+// for.
+type SPIFFEPolicy struct {
+	// TrustDomains are the SPIFFE trust domains that the issuer may issue
+	// X.509 SVIDs for, such as `cluster.local`.
+	TrustDomains []string `json:"trustDomains"`
 }
 
 // The configuration for the issuer.
@@ -129,6 +154,10 @@ type VenafiIssuer struct {
 	// Zone is the Venafi Policy Zone to use for this issuer.
 	// All requests made to the Venafi platform will be restricted by the named
 	// zone policy.
+	// For Trust Protection Platform, the zone is the path of a policy folder
+	// with its segments separated by backslashes. For Venafi Cloud, it is the
+	// application name and the issuing template alias separated by a
+	// backslash.
 	// This field is required.
 	Zone string `json:"zone"`
 
@@ -141,6 +170,14 @@ type VenafiIssuer struct {
 	// Only one of TPP or Cloud may be specified.
 	// +optional
 	Cloud *VenafiCloud `json:"cloud,omitempty"`
+
+	// CustomFields are Venafi custom fields, keyed by name, that are sent with
+	// every request made by this issuer. Custom fields set on a Certificate
+	// using the "venafi.cert-manager.io/custom-fields" annotation take
+	// precedence over those with the same name set here.
+	// This will only work with Venafi TPP v19.3 and higher.
+	// +optional
+	CustomFields map[string]string `json:"customFields,omitempty"`
 }
 
 // VenafiTPP defines connection configuration details for a Venafi TPP instance
@@ -172,7 +209,49 @@ type VenafiCloud struct {
 	URL string `json:"url,omitempty"`
 
 	// APITokenSecretRef is a secret key selector for the Venafi Cloud API token.
-	APITokenSecretRef cmmeta.SecretKeySelector `json:"apiTokenSecretRef"`
+	// Only one of APITokenSecretRef or OAuth may be specified.
+	// +optional
+	APITokenSecretRef cmmeta.SecretKeySelector `json:"apiTokenSecretRef,omitempty"`
+
+	// OAuth configures cert-manager to obtain short-lived Venafi Cloud
+	// credentials from an OAuth 2.0 token endpoint instead of reading a static
+	// API token from a Secret. Fetched credentials are cached and refreshed
+	// before they expire.
+	// Only one of APITokenSecretRef or OAuth may be specified.
+	// +optional
+	OAuth *VenafiCloudOAuth `json:"oauth,omitempty"`
+}
+
+// VenafiCloudOAuth configures how cert-manager fetches Venafi Cloud
+// credentials from an OAuth 2.0 token endpoint.
+// Exactly one of ClientSecretRef or FederatedTokenSecretRef must be specified.
+type VenafiCloudOAuth struct {
+	// TokenURL is the OAuth 2.0 token endpoint that issues Venafi Cloud
+	// credentials.
+	TokenURL string `json:"tokenURL"`
+
+	// ClientID is the OAuth 2.0 client identifier registered with the token
+	// endpoint.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// ClientSecretRef is a reference to a key in a Secret containing the
+	// OAuth 2.0 client secret. If set, credentials are requested using the
+	// client credentials grant.
+	// +optional
+	ClientSecretRef *cmmeta.SecretKeySelector `json:"clientSecretRef,omitempty"`
+
+	// FederatedTokenSecretRef is a reference to a key in a Secret containing
+	// a JWT issued by an identity provider that the token endpoint trusts,
+	// such as a ServiceAccount token kept up to date by a workload identity
+	// agent. If set, the JWT is exchanged for Venafi Cloud credentials using
+	// the JWT bearer grant (RFC 7523).
+	// +optional
+	FederatedTokenSecretRef *cmmeta.SecretKeySelector `json:"federatedTokenSecretRef,omitempty"`
+
+	// Scopes is an optional list of OAuth 2.0 scopes to request.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // Configures an issuer to 'self sign' certificates using the
@@ -194,6 +273,21 @@ type VaultIssuer struct {
 	// Server is the connection address for the Vault server, e.g: "https://vault.example.com:8200".
 	Server string `json:"server"`
 
+	// AdditionalServers are the connection addresses of further nodes of the
+	// same Vault cluster, e.g: "https://vault-1.example.com:8200". When set,
+	// the health of Server and then each of the AdditionalServers is checked
+	// in order and the first node that is initialized and unsealed is used,
+	// so that issuance continues while a single Vault node is unavailable.
+	// +optional
+	AdditionalServers []string `json:"additionalServers,omitempty"`
+
+	// ReadYourWrites enables the consistency headers of Vault Enterprise.
+	// The index of the Vault state returned when logging in is required on
+	// subsequent requests, and performance standby nodes that have not yet
+	// replicated that state forward the request to the active node.
+	// +optional
+	ReadYourWrites bool `json:"readYourWrites,omitempty"`
+
 	// Path is the mount path of the Vault PKI backend's `sign` endpoint, e.g:
 	// "my_pki_mount/sign/my-role-name".
 	Path string `json:"path"`
@@ -203,6 +297,32 @@ type VaultIssuer struct {
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
 
+	// AllowedNamespaces is the list of Vault namespaces that
+	// CertificateRequests and CertificateSigningRequests may select using the
+	// "vault.cert-manager.io/namespace" and
+	// "vault.experimental.cert-manager.io/namespace" annotations. Signing
+	// requests are sent to the selected namespace, while logging in still
+	// uses the namespace above. Requests selecting a namespace that is not in
+	// this list are failed.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// SignVerbatim signs certificates using the `sign-verbatim` endpoint of
+	// the Vault PKI backend instead of the `sign` endpoint of the role, so
+	// that the subject, subject alternative names and key usages of the
+	// issued certificate are taken exactly as requested in the CSR. The
+	// Vault policy of cert-manager must allow updating the `sign-verbatim`
+	// endpoint.
+	// +optional
+	SignVerbatim bool `json:"signVerbatim,omitempty"`
+
+	// SignVerbatimPath is the path of the Vault PKI backend's `sign-verbatim`
+	// endpoint, e.g: "my_pki_mount/sign-verbatim/my-role-name". Defaults to
+	// Path with its `sign` segment replaced by `sign-verbatim`. Only used if
+	// SignVerbatim is set.
+	// +optional
+	SignVerbatimPath string `json:"signVerbatimPath,omitempty"`
+
 	// PEM-encoded CA bundle (base64-encoded) used to validate Vault server
 	// certificate. Only used if the Server URL is using HTTPS protocol. This
 	// parameter is ignored for plain HTTP protocol connection. If not set the
@@ -245,6 +365,31 @@ type VaultAppRole struct {
 	// The `key` field must be specified and denotes which entry within the Secret
 	// resource is used as the app role secret.
 	SecretRef cmmeta.SecretKeySelector `json:"secretRef"`
+
+	// SecretIDRotation configures cert-manager to replace the App Role secret
+	// ID stored in the referenced Secret before it expires. New secret IDs are
+	// generated using response wrapping, so that the secret ID itself is only
+	// ever returned by Vault to the holder of the wrapping token.
+	// +optional
+	SecretIDRotation *VaultAppRoleSecretIDRotation `json:"secretIDRotation,omitempty"`
+}
+
+// VaultAppRoleSecretIDRotation configures the rotation of an App Role secret
+// ID. The Vault policy attached to the App Role must allow updating the
+// `role/<roleName>/secret-id`, `role/<roleName>/secret-id/lookup` and
+// `role/<roleName>/secret-id-accessor/destroy` endpoints of the App Role
+// authentication backend. Secret IDs that have been replaced are destroyed
+// once the new secret ID has been stored in the Secret.
+type VaultAppRoleSecretIDRotation struct {
+	// RoleName is the name of the App Role in the App Role authentication
+	// backend, used to generate new secret IDs.
+	RoleName string `json:"roleName"`
+
+	// RenewBefore is how long before the expiry of the current secret ID a
+	// new secret ID is generated. Defaults to 24 hours. Secret IDs that do
+	// not expire are never rotated.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 }
 
 // Authenticate against Vault using a Kubernetes ServiceAccount token stored in
@@ -257,16 +402,43 @@ type VaultKubernetesAuth struct {
 	// +optional
 	Path string `json:"mountPath,omitempty"`
 
-	// The required Secret field containing a Kubernetes ServiceAccount JWT used
-	// for authenticating with Vault. Use of 'ambient credentials' is not
-	// supported.
-	SecretRef cmmeta.SecretKeySelector `json:"secretRef"`
+	// The Secret field containing a Kubernetes ServiceAccount JWT used for
+	// authenticating with Vault. Use of 'ambient credentials' is not
+	// supported. Exactly one of secretRef or serviceAccountRef must be set.
+	// +optional
+	SecretRef cmmeta.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// A reference to a ServiceAccount for which cert-manager requests a
+	// short-lived bound token using the TokenRequest API. The token is
+	// requested again every time cert-manager logs in to Vault. The
+	// cert-manager controller must be allowed to "create" the
+	// "serviceaccounts/token" subresource of this ServiceAccount, for example
+	// using a Role and RoleBinding. Exactly one of secretRef or
+	// serviceAccountRef must be set.
+	// +optional
+	ServiceAccountRef *ServiceAccountRef `json:"serviceAccountRef,omitempty"`
 
 	// A required field containing the Vault Role to assume. A Role binds a
 	// Kubernetes ServiceAccount with a set of Vault policies.
 	Role string `json:"role"`
 }
 
+// ServiceAccountRef is a ServiceAccount used by cert-manager to request
+// short-lived bound tokens.
+type ServiceAccountRef struct {
+	// Name of the ServiceAccount used to request a token. The ServiceAccount
+	// must live in the same namespace as the Issuer, or in the cluster
+	// resource namespace for ClusterIssuers.
+	Name string `json:"name"`
+
+	// Additional audiences of the requested token. The token always has the
+	// audience "vault://<namespace>/<issuer-name>" for Issuers or
+	// "vault://<issuer-name>" for ClusterIssuers, which the Vault role should
+	// be bound to.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+}
+
 type CAIssuer struct {
 	// SecretName is the name of the secret used to sign Certificates issued
 	// by this Issuer.
@@ -285,8 +457,144 @@ type CAIssuer struct {
 	// OCSP server URL could be "http://ocsp.int-x3.letsencrypt.org".
 	// +optional
 	OCSPServers []string `json:"ocspServers,omitempty"`
+
+	// CRL configures cert-manager to maintain a certificate revocation list
+	// for certificates signed by this issuer. The CRL is only maintained if
+	// the optional `issuers-ca-crl` controller is enabled.
+	// +optional
+	CRL *CAIssuerCRL `json:"crl,omitempty"`
+
+	// CertificateTransparency configures the issuer to log a precertificate
+	// for each certificate it signs, and to embed the signed certificate
+	// timestamps (SCTs) that are returned in the issued certificate.
+	// If not set, certificates are issued without SCTs.
+	// +optional
+	CertificateTransparency *CAIssuerCertificateTransparency `json:"certificateTransparency,omitempty"`
+
+	// SerialNumber configures how the serial numbers of certificates signed
+	// by this issuer are generated.
+	// If not set, 128 bit random serial numbers are used.
+	// +optional
+	SerialNumber *CAIssuerSerialNumber `json:"serialNumber,omitempty"`
+
+	// FetchIntermediateCertificates configures the issuer to complete the
+	// certificate chain returned in `tls.crt` and `ca.crt` by fetching any
+	// CA certificates missing from its Secret, using the CA Issuers URLs of
+	// the authority information access extension. Fetched certificates must
+	// be DER or PEM encoded, and are cached by the controller.
+	// Fetching may be disabled for all issuers with the controller's
+	// `--enable-ca-issuer-aia-fetching=false` flag, e.g. in air-gapped
+	// clusters.
+	// +optional
+	FetchIntermediateCertificates bool `json:"fetchIntermediateCertificates,omitempty"`
+
+	// NextSecretName is the name of a Secret containing, under `tls.crt`, the
+	// certificate of the CA that will replace the signing CA in a planned
+	// rollover. The certificate is added to the `ca.crt` of the Secrets of
+	// Certificates signed by this issuer, after the certificate of the
+	// current CA, so that clients trust the next CA before the rollover.
+	// +optional
+	NextSecretName string `json:"nextSecretName,omitempty"`
+
+	// ReissueOnRotation configures the certificates-trigger controller to
+	// re-issue Certificates signed by this issuer when the key of its CA
+	// certificate changes, so that they chain to the new CA certificate.
+	// Renewing the CA certificate with the same key does not trigger
+	// re-issuance. Defaults to false.
+	// +optional
+	ReissueOnRotation bool `json:"reissueOnRotation,omitempty"`
+}
+
+// CAIssuerCRL configures where the certificate revocation list (CRL) of a CA
+// issuer is written to.
+// Certificates are revoked by adding the `cert-manager.io/revoke: "true"`
+// annotation to the CertificateRequest that they were issued for.
+type CAIssuerCRL struct {
+	// SecretName is the name of a Secret to which the PEM encoded CRL is
+	// written under the `ca.crl` key.
+	// The Secret is created in the same namespace as the Issuer, or in the
+	// cluster resource namespace for ClusterIssuers.
+	// It must not be the CA Secret, or a Secret containing a certificate or
+	// private key.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// ConfigMapName is the name of a ConfigMap to which the PEM encoded CRL
+	// is written under the `ca.crl` key.
+	// The ConfigMap is created in the same namespace as the Issuer, or in the
+	// cluster resource namespace for ClusterIssuers.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Duration is how long each generated CRL is valid for, i.e. the time
+	// between its thisUpdate and nextUpdate fields. A new CRL is generated
+	// once two thirds of this duration has elapsed, or as soon as a
+	// certificate is revoked. Defaults to 24 hours.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// CAIssuerCertificateTransparency configures the SCT hook used by a CA issuer
+// to log precertificates to a (private) certificate transparency program.
+// Before signing a certificate, the issuer signs a precertificate containing
+// the RFC 6962 poison extension and POSTs it to the hook as an RFC 6962
+// add-pre-chain request, i.e. `{"chain": [<precertificate>, <CA>...]}` with
+// each certificate base64 DER encoded. The hook must log the precertificate
+// and respond with `{"scts": [...]}`, a list of base64 encoded TLS
+// serialized SignedCertificateTimestamp structures, which are embedded in the
+// issued certificate.
+type CAIssuerCertificateTransparency struct {
+	// URL of the SCT hook, e.g. "https://ct-hook.example.com/add-pre-chain".
+	URL string `json:"url"`
+
+	// PEM encoded CA bundle used to validate the SCT hook's serving
+	// certificate. If not set, the system trust roots are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+// CAIssuerSerialNumber configures how a CA issuer generates the serial
+// numbers of the certificates it signs. Generated serial numbers are always
+// positive and encoded in exactly the configured number of bytes.
+type CAIssuerSerialNumber struct {
+	// Type is the serial number generation strategy, one of `Random` or
+	// `Monotonic`.
+	// `Random` serial numbers are drawn from a cryptographically secure random
+	// source, as recommended by RFC 5280.
+	// `Monotonic` serial numbers are consecutive, and are derived from a
+	// counter persisted in the ConfigMap named by counterConfigMapName.
+	// Defaults to `Random`.
+	// +optional
+	Type CAIssuerSerialNumberType `json:"type,omitempty"`
+
+	// Length is the length, in bytes, of generated serial numbers, between 8
+	// and 20. Random serial numbers contain 8*length-2 bits of entropy.
+	// Defaults to 16.
+	// +optional
+	Length int `json:"length,omitempty"`
+
+	// CounterConfigMapName is the name of the ConfigMap in which the counter
+	// of `Monotonic` serial numbers is persisted, under the `counter` key.
+	// The ConfigMap is created in the same namespace as the Issuer, or in the
+	// cluster resource namespace for ClusterIssuers.
+	// Required for `Monotonic` serial numbers.
+	// +optional
+	CounterConfigMapName string `json:"counterConfigMapName,omitempty"`
 }
 
+// CAIssuerSerialNumberType is a serial number generation strategy.
+// +kubebuilder:validation:Enum=Random;Monotonic
+type CAIssuerSerialNumberType string
+
+const (
+	// CAIssuerSerialNumberRandom generates random serial numbers.
+	CAIssuerSerialNumberRandom CAIssuerSerialNumberType = "Random"
+
+	// CAIssuerSerialNumberMonotonic generates consecutive serial numbers from
+	// a persisted counter.
+	CAIssuerSerialNumberMonotonic CAIssuerSerialNumberType = "Monotonic"
+)
+
 // IssuerStatus contains status information about an Issuer
 type IssuerStatus struct {
 	// List of status conditions to indicate the status of a CertificateRequest.
@@ -299,6 +607,38 @@ type IssuerStatus struct {
 	// server to issue certificates.
 	// +optional
 	ACME *cmacme.ACMEIssuerStatus `json:"acme,omitempty"`
+
+	// CA specific status options.
+	// This field should only be set if the Issuer is configured to sign
+	// certificates using a CA keypair.
+	// +optional
+	CA *CAIssuerStatus `json:"ca,omitempty"`
+}
+
+// CAIssuerStatus contains status information about a CA issuer.
+type CAIssuerStatus struct {
+	// TrustAnchors are the CA certificates that certificates signed by the
+	// issuer should be verified with: the certificate of the signing CA,
+	// followed by the certificate of the next CA during a planned rollover.
+	// +optional
+	TrustAnchors []CATrustAnchor `json:"trustAnchors,omitempty"`
+}
+
+// CATrustAnchor is a CA certificate that is advertised by a CA issuer.
+type CATrustAnchor struct {
+	// Certificate is the PEM encoded CA certificate.
+	Certificate []byte `json:"certificate"`
+
+	// Next is true if this is the certificate of the CA that will replace
+	// the signing CA in a planned rollover.
+	// +optional
+	Next bool `json:"next,omitempty"`
+
+	// NotBefore is the time from which the certificate is valid.
+	NotBefore metav1.Time `json:"notBefore"`
+
+	// NotAfter is the time at which the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
 }
 
 // IssuerCondition contains condition information for an Issuer.
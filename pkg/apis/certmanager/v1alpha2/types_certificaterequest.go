@@ -122,6 +122,11 @@ type CertificateRequestSpec struct {
 	// Populated by the cert-manager webhook on creation and immutable.
 	// +optional
 	Extra map[string][]string `json:"extra,omitempty"`
+	// RequesterIdentity holds attributes of the identity of the user that
+	// created the CertificateRequest, if it is a ServiceAccount.
+	// Populated by the cert-manager webhook on creation and immutable.
+	// +optional
+	RequesterIdentity *CertificateRequestRequesterIdentity `json:"requesterIdentity,omitempty"`
 }
 
 // CertificateRequestStatus defines the observed state of CertificateRequest and
@@ -129,6 +134,8 @@ type CertificateRequestSpec struct {
 type CertificateRequestStatus struct {
 	// List of status conditions to indicate the status of a CertificateRequest.
 	// Known condition types are `Ready` and `InvalidRequest`.
+	// +listType=map
+	// +listMapKey=type
 	// +optional
 	Conditions []CertificateRequestCondition `json:"conditions,omitempty"`
 
@@ -151,6 +158,56 @@ type CertificateRequestStatus struct {
 	// used to influence garbage collection and back-off.
 	// +optional
 	FailureTime *metav1.Time `json:"failureTime,omitempty"`
+
+	// ApprovalHistory records each decision to approve or deny this
+	// CertificateRequest, in the order they were made. Records are added by
+	// the cert-manager webhook using the identity of the user or controller
+	// that set the `Approved` or `Denied` condition, and cannot be modified.
+	// +optional
+	ApprovalHistory []CertificateRequestApprovalRecord `json:"approvalHistory,omitempty"`
+}
+
+// CertificateRequestRequesterIdentity holds attributes of the identity of the
+// ServiceAccount that created a CertificateRequest.
+type CertificateRequestRequesterIdentity struct {
+	// Namespace of the ServiceAccount that created the CertificateRequest.
+	Namespace string `json:"namespace"`
+
+	// ServiceAccount is the name of the ServiceAccount that created the
+	// CertificateRequest.
+	ServiceAccount string `json:"serviceAccount"`
+
+	// Pod is the name of the Pod that the requester's bound ServiceAccount
+	// token was issued for. It is empty if the requester did not use a bound
+	// token.
+	// +optional
+	Pod string `json:"pod,omitempty"`
+}
+
+// CertificateRequestApprovalRecord records who approved or denied a
+// CertificateRequest, when, and why.
+type CertificateRequestApprovalRecord struct {
+	// Type of the decision, either `Approved` or `Denied`.
+	Type CertificateRequestConditionType `json:"type"`
+
+	// Username of the user or controller that made the decision.
+	Username string `json:"username"`
+
+	// Groups of the user or controller that made the decision.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// Reason of the Approved or Denied condition, identifying the approver.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message of the Approved or Denied condition, e.g. the policy that
+	// permitted or rejected the request.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time at which the decision was made.
+	Time metav1.Time `json:"time"`
 }
 
 // CertificateRequestCondition contains condition information for a CertificateRequest.
@@ -83,6 +83,18 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// CertificateRequestDurationAnnotationKey is the annotation key used to
+	// request a particular duration, represented as a Go Duration, for
+	// CertificateRequests that do not set spec.duration.
+	// It is currently only honoured by the 'self signing' issuer type.
+	CertificateRequestDurationAnnotationKey = "cert-manager.io/request-duration"
+
+	// CertificateRequestMaxPathLenAnnotationKey is the annotation key used to
+	// request the maximum number of intermediate CAs that may follow a CA
+	// certificate in a chain. It may only be set when requesting a CA.
+	// It is currently only honoured by the 'self signing' issuer type.
+	CertificateRequestMaxPathLenAnnotationKey = "cert-manager.io/request-max-path-len"
 )
 
 const (
@@ -92,6 +104,27 @@ const (
 	// stored in the target Secret resource whilst the real Issuer is processing
 	// the certificate request.
 	IssueTemporaryCertificateAnnotation = "cert-manager.io/issue-temporary-certificate"
+
+	// AdoptExistingSecretAnnotation is an annotation that can be added to
+	// Certificate resources.
+	// If set to "true" and the Certificate has not yet been issued, an existing
+	// certificate and private key in the target Secret resource will be
+	// adopted without re-issuance, provided they are valid and match the
+	// Certificate's spec.
+	AdoptExistingSecretAnnotation = "cert-manager.io/adopt-existing-secret"
+
+	// RevokeCertificateAnnotation is an annotation that can be added to
+	// CertificateRequest resources.
+	// If set to "true", the certificate issued for the CertificateRequest is
+	// added to the certificate revocation list of its CA issuer, if the
+	// issuer is configured to maintain one.
+	RevokeCertificateAnnotation = "cert-manager.io/revoke"
+
+	// PrivateKeyExternalRefAnnotationKey is set on CertificateRequests and
+	// Secrets for certificates whose private key is held by an external
+	// signer. Its value identifies the external key that the certificate was
+	// requested or issued for.
+	PrivateKeyExternalRefAnnotationKey = "cert-manager.io/private-key-external-ref"
 )
 
 // Common/known resource kinds.
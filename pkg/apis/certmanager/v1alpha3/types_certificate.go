@@ -145,6 +145,32 @@ type CertificateSpec struct {
 	// +optional
 	Keystores *CertificateKeystores `json:"keystores,omitempty"`
 
+	// AdditionalOutputFormats defines extra output formats of the private key
+	// and signed certificate chain to be written to this Certificate's target
+	// Secret.
+	// +optional
+	AdditionalOutputFormats []CertificateAdditionalOutputFormat `json:"additionalOutputFormats,omitempty"`
+
+	// NormalizePEM, if true, normalizes the PEM data written to the
+	// `tls.crt`, `tls.key` and `ca.crt` entries of the target Secret for
+	// parsers that only accept a single layout. The private key is always
+	// written as an unencrypted PKCS#8 `PRIVATE KEY` block regardless of the
+	// configured private key encoding, every PEM block is wrapped at 64
+	// columns with LF line endings, and each entry ends with exactly one
+	// newline. Any data between PEM blocks is dropped.
+	// +optional
+	NormalizePEM bool `json:"normalizePEM,omitempty"`
+
+	// SecretSinks are external secret stores that the signed certificate,
+	// private key and CA are copied to, for workloads that read TLS material
+	// directly from those stores. Sinks are only ever written in addition to
+	// the target Secret: the target Secret, including the private key, is
+	// always stored in Kubernetes and remains the source of truth, and the
+	// sinks are written whenever its data is issued or updated. Keeping key
+	// material out of Kubernetes Secrets is not supported.
+	// +optional
+	SecretSinks []CertificateSecretSink `json:"secretSinks,omitempty"`
+
 	// IssuerRef is a reference to the issuer for this certificate.
 	// If the `kind` field is not set, or set to `Issuer`, an Issuer resource
 	// with the given name in the same namespace as the Certificate will be used.
@@ -158,6 +184,13 @@ type CertificateSpec struct {
 	// +optional
 	IsCA bool `json:"isCA,omitempty"`
 
+	// CAConstraints are additional X.509 constraints and extensions encoded
+	// into the certificate when `isCA` is true, such as the maximum path
+	// length, name constraints, and CRL distribution point and authority
+	// information access URLs. Currently honoured by the SelfSigned issuer.
+	// +optional
+	CAConstraints *CertificateCAConstraints `json:"caConstraints,omitempty"`
+
 	// Usages is the set of x509 usages that are requested for the certificate.
 	// Defaults to `digital signature` and `key encipherment` if not specified.
 	// +optional
@@ -206,6 +239,102 @@ type CertificateSpec struct {
 	// +kubebuilder:validation:ExclusiveMaximum=false
 	// +optional
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"` // Validated by the validating webhook.
+
+	// Revoke, if true, requests that the certificate currently stored in the
+	// target Secret is revoked by the issuer that issued it. Once revoked, the
+	// certificate will not be renewed or re-issued until this field is unset.
+	// The outcome is reported by the `Revoked` condition. Revocation is only
+	// supported by ACME, CA, Vault and Venafi TPP issuers. Only certificates
+	// that were issued by one of this Certificate's CertificateRequests are
+	// revoked.
+	// +optional
+	Revoke bool `json:"revoke,omitempty"`
+
+	// RevokeOnDelete, if true, requests that the certificate currently stored
+	// in the target Secret is revoked by the issuer that issued it when this
+	// Certificate is deleted. A finalizer is added to the Certificate to
+	// revoke the certificate before the Certificate is removed. If revocation
+	// fails, an event is recorded and the Certificate is removed regardless.
+	// +optional
+	RevokeOnDelete bool `json:"revokeOnDelete,omitempty"`
+
+	// AllowPartialSANs, if true, accepts certificates that the issuer signed
+	// without some of the requested subject alternative names, for CAs that
+	// remove names they are not allowed to issue for and sign the rest. The
+	// names that were removed are listed in `status.droppedSANs`. If false,
+	// issuance fails if any requested name is missing from the certificate.
+	// +optional
+	AllowPartialSANs bool `json:"allowPartialSANs,omitempty"`
+}
+
+// CertificateCAConstraints are additional X.509 constraints and extensions
+// for a CA certificate.
+type CertificateCAConstraints struct {
+	// MaxPathLen is the maximum number of intermediate CA certificates that may
+	// follow this certificate in a valid certification path, encoded as the
+	// pathLenConstraint of the basic constraints extension.
+	// A value of `0` means that only end-entity certificates may be issued.
+	// If unset, no path length constraint is encoded.
+	// +optional
+	MaxPathLen *int `json:"maxPathLen,omitempty"`
+
+	// NameConstraints restrict the names which may appear in certificates
+	// issued beneath this CA.
+	// +optional
+	NameConstraints *NameConstraints `json:"nameConstraints,omitempty"`
+
+	// CRLDistributionPoints are the URLs of the CRL distribution points
+	// extension, from which a CRL for this certificate may be retrieved.
+	// +optional
+	CRLDistributionPoints []string `json:"crlDistributionPoints,omitempty"`
+
+	// IssuingCertificateURLs are the `caIssuers` URLs of the authority
+	// information access extension, from which the issuer of this certificate
+	// may be retrieved.
+	// +optional
+	IssuingCertificateURLs []string `json:"issuingCertificateURLs,omitempty"`
+
+	// OCSPServers are the `ocsp` URLs of the authority information access
+	// extension, from which the revocation status of this certificate may be
+	// checked.
+	// +optional
+	OCSPServers []string `json:"ocspServers,omitempty"`
+}
+
+// NameConstraints are the X.509 name constraints of a CA certificate,
+// see RFC 5280, 4.2.1.10.
+type NameConstraints struct {
+	// Critical marks the name constraints extension as critical.
+	// +optional
+	Critical bool `json:"critical,omitempty"`
+
+	// Permitted are the names which are allowed in issued certificates.
+	// +optional
+	Permitted *NameConstraintItem `json:"permitted,omitempty"`
+
+	// Excluded are the names which are disallowed in issued certificates.
+	// +optional
+	Excluded *NameConstraintItem `json:"excluded,omitempty"`
+}
+
+// NameConstraintItem is a set of names for a name constraint.
+type NameConstraintItem struct {
+	// DNSDomains is a list of DNS domains.
+	// +optional
+	DNSDomains []string `json:"dnsDomains,omitempty"`
+
+	// IPRanges is a list of IP address ranges in CIDR notation.
+	// +optional
+	IPRanges []string `json:"ipRanges,omitempty"`
+
+	// EmailAddresses is a list of email addresses, or domains for email
+	// addresses.
+	// +optional
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
+
+	// URIDomains is a list of domains for URIs.
+	// +optional
+	URIDomains []string `json:"uriDomains,omitempty"`
 }
 
 // CertificatePrivateKey contains configuration options for private keys
@@ -223,6 +352,44 @@ type CertificatePrivateKey struct {
 	// Default is 'Never' for backward compatibility.
 	// +optional
 	RotationPolicy PrivateKeyRotationPolicy `json:"rotationPolicy,omitempty"`
+
+	// ExternalRef references a private key that is held by an external
+	// signer service, e.g. one backed by an HSM or a cloud KMS, instead of
+	// being generated by cert-manager.
+	// If set, the certificate signing request is signed by the external
+	// signer and no private key is stored in the Secret named by
+	// `spec.secretName`. The other private key options, as well as
+	// keystores, are not supported when an external key is used.
+	// +optional
+	ExternalRef *PrivateKeyExternalRef `json:"externalRef,omitempty"`
+}
+
+// PrivateKeyExternalRef references a private key held by an external signer
+// service.
+// The signer service must serve the public key of the key at
+// `GET <signerURL>/keys/<keyID>` and sign digests with it at
+// `POST <signerURL>/keys/<keyID>/sign`.
+type PrivateKeyExternalRef struct {
+	// SignerURL is the base URL of the external signer service.
+	// It must be allowed by the --external-key-signer-urls flag of the
+	// cert-manager controller.
+	SignerURL string `json:"signerURL"`
+
+	// KeyID identifies the private key within the signer service, e.g. the
+	// ID or ARN of a KMS key.
+	KeyID string `json:"keyID"`
+
+	// CABundle is a PEM encoded bundle of CA certificates used to validate
+	// the signer service's TLS certificate. If not set, the system trust
+	// store is used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// TokenSecretRef references a key in a Secret, in the same namespace as
+	// the Certificate, containing a bearer token that is sent to the signer
+	// service in the Authorization header.
+	// +optional
+	TokenSecretRef *cmmeta.SecretKeySelector `json:"tokenSecretRef,omitempty"`
 }
 
 // Denotes how private keys should be generated or sourced when a Certificate
@@ -296,6 +463,24 @@ type JKSKeystore struct {
 
 	// PasswordSecretRef is a reference to a key in a Secret resource
 	// containing the password used to encrypt the JKS keystore.
+	// Required if `create` is true.
+	// +optional
+	PasswordSecretRef cmmeta.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Truststore configures a separate `truststore.jks` file in the target
+	// Secret resource containing only the issuing Certificate Authority,
+	// encrypted using its own password. If set, the truststore is created
+	// even if `create` is false, so that a CA-only store can be mounted
+	// without the private key.
+	// +optional
+	Truststore *JKSTruststore `json:"truststore,omitempty"`
+}
+
+// JKSTruststore configures options for storing a JKS truststore containing
+// only the CA chain in the `spec.secretName` Secret resource.
+type JKSTruststore struct {
+	// PasswordSecretRef is a reference to a key in a Secret resource
+	// containing the password used to encrypt the JKS truststore.
 	PasswordSecretRef cmmeta.SecretKeySelector `json:"passwordSecretRef"`
 }
 
@@ -317,10 +502,113 @@ type PKCS12Keystore struct {
 	PasswordSecretRef cmmeta.SecretKeySelector `json:"passwordSecretRef"`
 }
 
+// CertificateOutputFormatType specifies an additional output format of the
+// private key and signed certificate chain to write to a Certificate's target
+// Secret.
+// +kubebuilder:validation:Enum=DER;CombinedPEM
+type CertificateOutputFormatType string
+
+const (
+	// CertificateOutputFormatDER writes the private key as binary DER to an
+	// entry in the Secret, for applications that cannot parse PEM.
+	CertificateOutputFormatDER CertificateOutputFormatType = "DER"
+
+	// CertificateOutputFormatDERKey is the default Secret data key the DER
+	// output format is written to.
+	CertificateOutputFormatDERKey = "key.der"
+
+	// CertificateOutputFormatCombinedPEM writes the PEM encoded private key
+	// followed by the PEM encoded signed certificate chain to a single entry
+	// in the Secret, for applications that expect them in the same file.
+	CertificateOutputFormatCombinedPEM CertificateOutputFormatType = "CombinedPEM"
+
+	// CertificateOutputFormatCombinedPEMKey is the default Secret data key the
+	// CombinedPEM output format is written to.
+	CertificateOutputFormatCombinedPEMKey = "tls-combined.pem"
+)
+
+// CertificateAdditionalOutputFormat defines an additional output format of a
+// Certificate's private key and signed certificate chain.
+type CertificateAdditionalOutputFormat struct {
+	// Type is the name of the format that should be written to the
+	// Certificate's target Secret.
+	Type CertificateOutputFormatType `json:"type"`
+
+	// Key is the name of the Secret data entry the format is written to.
+	// Defaults to a name specific to the format type, i.e. `key.der` for
+	// the DER format and `tls-combined.pem` for the CombinedPEM format.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// CertificateSecretSink is an external secret store that a Certificate's
+// signed certificate, private key and CA are written to. Exactly one store
+// must be configured.
+type CertificateSecretSink struct {
+	// Name identifies the sink in events. It must be unique within the
+	// Certificate.
+	Name string `json:"name"`
+
+	// Vault writes the data to a secret in a Vault KV version 2 secrets
+	// engine.
+	// +optional
+	Vault *VaultSecretSink `json:"vault,omitempty"`
+
+	// AWSSecretsManager writes the data to a secret in AWS Secrets Manager.
+	// +optional
+	AWSSecretsManager *AWSSecretsManagerSecretSink `json:"awsSecretsManager,omitempty"`
+}
+
+// VaultSecretSink writes a Certificate's data to a Vault KV version 2
+// secrets engine. The data is written with the same keys as the target
+// Secret, i.e. `tls.crt`, `tls.key` and `ca.crt`.
+type VaultSecretSink struct {
+	// IssuerRef references the Vault Issuer whose server and authentication
+	// are used to connect to Vault. The Issuer must be in the namespace of the
+	// Certificate; ClusterIssuers and Issuers in other namespaces are not
+	// supported, since their Vault credentials are not owned by the namespace
+	// of the Certificate. The Vault role it authenticates with must be
+	// allowed to write to Path.
+	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
+
+	// Path is the path of the secret to write, including the mount of the KV
+	// secrets engine and its `data` prefix, e.g. `secret/data/my-app/tls`.
+	Path string `json:"path"`
+}
+
+// AWSSecretsManagerSecretSink writes a Certificate's data to AWS Secrets
+// Manager. The secret value is a JSON object with the same keys as the target
+// Secret, i.e. `tls.crt`, `tls.key` and `ca.crt`.
+type AWSSecretsManagerSecretSink struct {
+	// Region is the AWS region of the secret.
+	Region string `json:"region"`
+
+	// SecretID is the name or ARN of the secret to write. The secret is
+	// created if it does not exist.
+	SecretID string `json:"secretID"`
+
+	// AccessKeyID is used for authentication. If neither this nor
+	// SecretAccessKey are set, ambient credentials are used if the controller
+	// allows ambient credentials for namespaced Issuers.
+	// +optional
+	AccessKeyID string `json:"accessKeyID,omitempty"`
+
+	// SecretAccessKey references a Secret in the Certificate's namespace
+	// containing the secret access key used for authentication.
+	// +optional
+	SecretAccessKey *cmmeta.SecretKeySelector `json:"secretAccessKeySecretRef,omitempty"`
+
+	// Role is the ARN of a role to assume using the configured credentials.
+	// +optional
+	Role string `json:"role,omitempty"`
+}
+
 // CertificateStatus defines the observed state of Certificate
 type CertificateStatus struct {
 	// List of status conditions to indicate the status of certificates.
 	// Known condition types are `Ready` and `Issuing`.
+	// +listType=map
+	// +listMapKey=type
 	// +optional
 	Conditions []CertificateCondition `json:"conditions,omitempty"`
 
@@ -373,11 +661,45 @@ type CertificateStatus struct {
 	// not set or False.
 	// +optional
 	NextPrivateKeySecretName *string `json:"nextPrivateKeySecretName,omitempty"`
+
+	// The serial number of the certificate that was last revoked by
+	// cert-manager, as requested by `spec.revoke` or `spec.revokeOnDelete`.
+	// +optional
+	RevokedSerialNumber string `json:"revokedSerialNumber,omitempty"`
+
+	// The subject alternative names that were requested but are missing from
+	// the certificate stored in the target Secret. Only set if
+	// `spec.allowPartialSANs` is true.
+	// +optional
+	DroppedSANs []string `json:"droppedSANs,omitempty"`
+
+	// The last manual request to renew the certificate, e.g. by
+	// `cmctl renew`. Set by setting the `Issuing` condition to `True` with
+	// the reason `ManuallyTriggered`, along with an optional reason for the
+	// renewal. The requester and time of the request are recorded by the
+	// cert-manager webhook.
+	// +optional
+	RenewalRequest *CertificateRenewalRequest `json:"renewalRequest,omitempty"`
+}
+
+// CertificateRenewalRequest records a manual request to renew a certificate.
+type CertificateRenewalRequest struct {
+	// Reason given for requesting the renewal.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Username of the user that requested the renewal.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Time at which the renewal was requested.
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
 }
 
 // CertificateCondition contains condition information for an Certificate.
 type CertificateCondition struct {
-	// Type of the condition, known values are (`Ready`, `Issuing`).
+	// Type of the condition, known values are (`Ready`, `Issuing`, `Adopted`).
 	Type CertificateConditionType `json:"type"`
 
 	// Status of the condition, one of (`True`, `False`, `Unknown`).
@@ -436,6 +758,31 @@ const (
 	//
 	// It will be removed by the 'issuing' controller upon completing issuance.
 	CertificateConditionIssuing CertificateConditionType = "Issuing"
+
+	// A condition added to Certificate resources when the certificate stored
+	// in the target Secret was adopted rather than issued by cert-manager.
+	// This condition is only added if the Certificate has the
+	// `cert-manager.io/adopt-existing-secret` annotation set to "true".
+	//
+	// It will be removed by the 'issuing' controller upon completing the next
+	// issuance.
+	CertificateConditionAdopted CertificateConditionType = "Adopted"
+
+	// A condition added to Certificate resources when revocation of the
+	// certificate stored in the target Secret has been requested, using
+	// `spec.revoke` or `spec.revokeOnDelete`. It is set to `True` once the
+	// issuer has revoked the certificate, and to `False` if revocation failed
+	// or is not supported by the issuer.
+	CertificateConditionRevoked CertificateConditionType = "Revoked"
+
+	// A condition added to Certificate resources by the 'issuing' controller
+	// to reflect whether the latest issued certificate, private key and any
+	// additional output formats such as keystores have been written to the
+	// target Secret.
+	// Unlike `Ready`, which reflects the contents of the Secret, this
+	// condition is set to `False` if writing the Secret failed, e.g. because
+	// cert-manager is not permitted to update it.
+	CertificateConditionSecretSynced CertificateConditionType = "SecretSynced"
 )
 
 // CertificateSecretTemplate defines the default labels and annotations
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+)
+
+// The default values of the configuration file fields, which are also the
+// default values of the corresponding command line flags.
+const (
+	DefaultLeaderElect                 = true
+	DefaultLeaderElectionNamespace     = "kube-system"
+	DefaultLeaderElectionLeaseDuration = 60 * time.Second
+	DefaultLeaderElectionRenewDeadline = 40 * time.Second
+	DefaultLeaderElectionRetryPeriod   = 15 * time.Second
+
+	DefaultKubernetesAPIQPS   float32 = 20
+	DefaultKubernetesAPIBurst         = 50
+
+	DefaultClusterResourceNamespace = "kube-system"
+
+	DefaultShardCount = 1
+
+	DefaultMaxConcurrentChallenges = 60
+
+	DefaultMetricsListenAddress = "0.0.0.0:9402"
+
+	DefaultIssuerAmbientCredentials        = false
+	DefaultClusterIssuerAmbientCredentials = true
+
+	DefaultIssuerSetupTimeout = 10 * time.Second
+	DefaultIssuerSignTimeout  = 2 * time.Minute
+
+	DefaultEnableCAIssuerAIAFetching = true
+
+	DefaultIngressShimIssuerKind  = "Issuer"
+	DefaultIngressShimIssuerGroup = certmanager.GroupName
+
+	DefaultDNS01RecursiveNameserversOnly = false
+	DefaultDNS01CheckRetryPeriod         = 10 * time.Second
+
+	DefaultWebhookSecurePort  = 6443
+	DefaultWebhookHealthzPort = 6080
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+func SetDefaults_ControllerConfiguration(obj *ControllerConfiguration) {
+	if obj.KubernetesAPIQPS == nil {
+		qps := DefaultKubernetesAPIQPS
+		obj.KubernetesAPIQPS = &qps
+	}
+	if obj.KubernetesAPIBurst == nil {
+		obj.KubernetesAPIBurst = pointer.Int32(DefaultKubernetesAPIBurst)
+	}
+	if obj.ClusterResourceNamespace == nil {
+		obj.ClusterResourceNamespace = pointer.String(DefaultClusterResourceNamespace)
+	}
+	if obj.LeaderElection == nil {
+		obj.LeaderElection = &LeaderElectionConfig{}
+	}
+	if obj.ShardCount == nil {
+		obj.ShardCount = pointer.Int32(DefaultShardCount)
+	}
+	if obj.MaxConcurrentChallenges == nil {
+		obj.MaxConcurrentChallenges = pointer.Int32(DefaultMaxConcurrentChallenges)
+	}
+	if obj.MetricsListenAddress == nil {
+		obj.MetricsListenAddress = pointer.String(DefaultMetricsListenAddress)
+	}
+	if obj.IssuerAmbientCredentials == nil {
+		obj.IssuerAmbientCredentials = pointer.Bool(DefaultIssuerAmbientCredentials)
+	}
+	if obj.ClusterIssuerAmbientCredentials == nil {
+		obj.ClusterIssuerAmbientCredentials = pointer.Bool(DefaultClusterIssuerAmbientCredentials)
+	}
+	if obj.IssuerSetupTimeout == nil {
+		obj.IssuerSetupTimeout = &metav1.Duration{Duration: DefaultIssuerSetupTimeout}
+	}
+	if obj.IssuerSignTimeout == nil {
+		obj.IssuerSignTimeout = &metav1.Duration{Duration: DefaultIssuerSignTimeout}
+	}
+	if obj.EnableCAIssuerAIAFetching == nil {
+		obj.EnableCAIssuerAIAFetching = pointer.Bool(DefaultEnableCAIssuerAIAFetching)
+	}
+	if obj.IngressShim == nil {
+		obj.IngressShim = &IngressShimConfig{}
+	}
+	if obj.ACMEDNS01 == nil {
+		obj.ACMEDNS01 = &ACMEDNS01Config{}
+	}
+}
+
+func SetDefaults_IngressShimConfig(obj *IngressShimConfig) {
+	if obj.DefaultIssuerKind == nil {
+		obj.DefaultIssuerKind = pointer.String(DefaultIngressShimIssuerKind)
+	}
+	if obj.DefaultIssuerGroup == nil {
+		obj.DefaultIssuerGroup = pointer.String(DefaultIngressShimIssuerGroup)
+	}
+}
+
+func SetDefaults_ACMEDNS01Config(obj *ACMEDNS01Config) {
+	if obj.RecursiveNameserversOnly == nil {
+		obj.RecursiveNameserversOnly = pointer.Bool(DefaultDNS01RecursiveNameserversOnly)
+	}
+	if obj.CheckRetryPeriod == nil {
+		obj.CheckRetryPeriod = &metav1.Duration{Duration: DefaultDNS01CheckRetryPeriod}
+	}
+}
+
+func SetDefaults_WebhookConfiguration(obj *WebhookConfiguration) {
+	if obj.SecurePort == nil {
+		obj.SecurePort = pointer.Int32(DefaultWebhookSecurePort)
+	}
+	if obj.HealthzPort == nil {
+		obj.HealthzPort = pointer.Int32(DefaultWebhookHealthzPort)
+	}
+}
+
+func SetDefaults_CAInjectorConfiguration(obj *CAInjectorConfiguration) {
+	if obj.LeaderElection == nil {
+		obj.LeaderElection = &LeaderElectionConfig{}
+	}
+	if obj.ShardCount == nil {
+		obj.ShardCount = pointer.Int32(DefaultShardCount)
+	}
+	if obj.ShardIndex == nil {
+		obj.ShardIndex = pointer.Int32(0)
+	}
+}
+
+func SetDefaults_LeaderElectionConfig(obj *LeaderElectionConfig) {
+	if obj.Enabled == nil {
+		obj.Enabled = pointer.Bool(DefaultLeaderElect)
+	}
+	if obj.Namespace == nil {
+		obj.Namespace = pointer.String(DefaultLeaderElectionNamespace)
+	}
+	if obj.LeaseDuration == nil {
+		obj.LeaseDuration = &metav1.Duration{Duration: DefaultLeaderElectionLeaseDuration}
+	}
+	if obj.RenewDeadline == nil {
+		obj.RenewDeadline = &metav1.Duration{Duration: DefaultLeaderElectionRenewDeadline}
+	}
+	if obj.RetryPeriod == nil {
+		obj.RetryPeriod = &metav1.Duration{Duration: DefaultLeaderElectionRetryPeriod}
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Every field of a configuration file corresponds to a command line flag of
+// the component, which is named in the field's documentation. Fields that
+// are not set are defaulted to the default value of their flag, and flags that
+// are set on the command line take precedence over the configuration file.
+//
+// Only the settings that are expected to be managed as configuration are part
+// of the configuration file. Flags that locate the apiserver (--master and
+// --kubeconfig), the profiling and tracing flags, and the flags configuring
+// the ACME HTTP01 solver Pods can only be set on the command line.
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ControllerConfiguration configures the cert-manager controller.
+type ControllerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// KubernetesAPIQPS is the maximum queries-per-second of requests sent to
+	// the Kubernetes apiserver (--kube-api-qps).
+	// +optional
+	KubernetesAPIQPS *float32 `json:"kubernetesAPIQPS,omitempty"`
+
+	// KubernetesAPIBurst is the maximum burst of requests sent to the
+	// Kubernetes apiserver (--kube-api-burst).
+	// +optional
+	KubernetesAPIBurst *int32 `json:"kubernetesAPIBurst,omitempty"`
+
+	// ClusterResourceNamespace is the namespace that stores the Secrets
+	// referenced by ClusterIssuers (--cluster-resource-namespace).
+	// +optional
+	ClusterResourceNamespace *string `json:"clusterResourceNamespace,omitempty"`
+
+	// Namespaces limits the controller to the given namespaces. If unset, all
+	// namespaces are watched (--namespace).
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector is a label selector that namespaces must match for
+	// their resources to be processed (--namespace-selector).
+	// +optional
+	NamespaceSelector *string `json:"namespaceSelector,omitempty"`
+
+	// LeaderElection configures leader election between replicas.
+	// +optional
+	LeaderElection *LeaderElectionConfig `json:"leaderElection,omitempty"`
+
+	// ShardCount is the number of shards that namespaced resources are split
+	// between. Requires leader election to be enabled (--shard-count).
+	// +optional
+	ShardCount *int32 `json:"shardCount,omitempty"`
+
+	// Controllers is the list of controllers to enable or disable
+	// (--controllers).
+	// +optional
+	Controllers []string `json:"controllers,omitempty"`
+
+	// FeatureGates enables or disables alpha and beta features
+	// (--feature-gates).
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// ControllerWorkers overrides the number of items a controller processes
+	// concurrently, keyed by the name of the controller, or '*' for all
+	// controllers (--controller-workers).
+	// +optional
+	ControllerWorkers map[string]int32 `json:"controllerWorkers,omitempty"`
+
+	// ControllerBackoff overrides the exponential backoff applied by
+	// controller workqueues when an item fails to sync, keyed by the name of
+	// the controller, or '*' for all controllers.
+	// +optional
+	ControllerBackoff map[string]ControllerBackoffConfig `json:"controllerBackoff,omitempty"`
+
+	// MaxConcurrentChallenges is the maximum number of ACME challenges that
+	// are processed at once (--max-concurrent-challenges).
+	// +optional
+	MaxConcurrentChallenges *int32 `json:"maxConcurrentChallenges,omitempty"`
+
+	// MetricsListenAddress is the host and port that the metrics endpoint
+	// listens on (--metrics-listen-address).
+	// +optional
+	MetricsListenAddress *string `json:"metricsListenAddress,omitempty"`
+
+	// Logging configures the log output.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+
+	// IssuerAmbientCredentials determines whether Issuers may use ambient
+	// credentials, such as those of the Pod's cloud provider identity
+	// (--issuer-ambient-credentials).
+	// +optional
+	IssuerAmbientCredentials *bool `json:"issuerAmbientCredentials,omitempty"`
+
+	// ClusterIssuerAmbientCredentials determines whether ClusterIssuers may
+	// use ambient credentials (--cluster-issuer-ambient-credentials).
+	// +optional
+	ClusterIssuerAmbientCredentials *bool `json:"clusterIssuerAmbientCredentials,omitempty"`
+
+	// IssuerSetupTimeout is the time allowed for an issuer to be set up
+	// (--issuer-setup-timeout).
+	// +optional
+	IssuerSetupTimeout *metav1.Duration `json:"issuerSetupTimeout,omitempty"`
+
+	// IssuerSignTimeout is the time allowed for an issuer to sign a
+	// certificate (--issuer-sign-timeout).
+	// +optional
+	IssuerSignTimeout *metav1.Duration `json:"issuerSignTimeout,omitempty"`
+
+	// EnableCAIssuerAIAFetching determines whether CA issuers fetch missing
+	// intermediate certificates using the Authority Information Access
+	// extension (--enable-ca-issuer-aia-fetching).
+	// +optional
+	EnableCAIssuerAIAFetching *bool `json:"enableCAIssuerAIAFetching,omitempty"`
+
+	// AllowedIssuerTypes are the types of Issuers and ClusterIssuers that
+	// may be used (--allowed-issuer-types).
+	// +optional
+	AllowedIssuerTypes []string `json:"allowedIssuerTypes,omitempty"`
+
+	// DeniedIssuerTypes are the types of Issuers and ClusterIssuers that may
+	// not be used (--denied-issuer-types).
+	// +optional
+	DeniedIssuerTypes []string `json:"deniedIssuerTypes,omitempty"`
+
+	// IngressShim configures the default issuer of Certificates created
+	// for annotated Ingresses and Gateways.
+	// +optional
+	IngressShim *IngressShimConfig `json:"ingressShim,omitempty"`
+
+	// ACMEDNS01 configures the DNS01 challenge self check of ACME issuers.
+	// +optional
+	ACMEDNS01 *ACMEDNS01Config `json:"acmeDNS01,omitempty"`
+}
+
+// ControllerBackoffConfig configures the backoff applied by the workqueue of
+// a controller when an item fails to sync.
+type ControllerBackoffConfig struct {
+	// BaseDelay is the delay before an item is first retried. It doubles on
+	// each subsequent failure (--controller-backoff-base-delay).
+	// +optional
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the maximum delay between retries of an item
+	// (--controller-backoff-max-delay).
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+
+	// Jitter is the fraction of each retry delay that is added to it at
+	// random (--controller-backoff-jitter).
+	// +optional
+	Jitter *float32 `json:"jitter,omitempty"`
+}
+
+// IngressShimConfig configures the default issuer of Certificates created
+// for annotated Ingresses and Gateways.
+type IngressShimConfig struct {
+	// DefaultIssuerName is the name of the issuer used when an Ingress or
+	// Gateway does not name one (--default-issuer-name).
+	// +optional
+	DefaultIssuerName *string `json:"defaultIssuerName,omitempty"`
+
+	// DefaultIssuerKind is the kind of the default issuer
+	// (--default-issuer-kind).
+	// +optional
+	DefaultIssuerKind *string `json:"defaultIssuerKind,omitempty"`
+
+	// DefaultIssuerGroup is the group of the default issuer
+	// (--default-issuer-group).
+	// +optional
+	DefaultIssuerGroup *string `json:"defaultIssuerGroup,omitempty"`
+
+	// IngressClassDefaultIssuers are the default issuers of Ingresses of a
+	// given IngressClass, keyed by the IngressClass name, in the form
+	// 'Kind[.group]/name' (--ingress-class-default-issuers).
+	// +optional
+	IngressClassDefaultIssuers map[string]string `json:"ingressClassDefaultIssuers,omitempty"`
+}
+
+// ACMEDNS01Config configures the DNS01 challenge self check of ACME issuers.
+type ACMEDNS01Config struct {
+	// RecursiveNameservers is the list of host:port nameservers used for the
+	// DNS01 self check (--dns01-recursive-nameservers).
+	// +optional
+	RecursiveNameservers []string `json:"recursiveNameservers,omitempty"`
+
+	// RecursiveNameserversOnly determines whether only the recursive
+	// nameservers are queried, rather than the authoritative nameservers
+	// (--dns01-recursive-nameservers-only).
+	// +optional
+	RecursiveNameserversOnly *bool `json:"recursiveNameserversOnly,omitempty"`
+
+	// CheckRetryPeriod is the time between DNS01 self checks
+	// (--dns01-check-retry-period).
+	// +optional
+	CheckRetryPeriod *metav1.Duration `json:"checkRetryPeriod,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebhookConfiguration configures the cert-manager webhook.
+type WebhookConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// SecurePort is the port that the webhook serves TLS connections on
+	// (--secure-port).
+	// +optional
+	SecurePort *int32 `json:"securePort,omitempty"`
+
+	// HealthzPort is the port that the webhook serves health checks on
+	// (--healthz-port).
+	// +optional
+	HealthzPort *int32 `json:"healthzPort,omitempty"`
+
+	// TLSConfig configures the serving certificate and TLS parameters.
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+
+	// Logging configures the log output.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+
+	// AllowedIssuerTypes are the types of Issuers and ClusterIssuers that
+	// may be created (--allowed-issuer-types).
+	// +optional
+	AllowedIssuerTypes []string `json:"allowedIssuerTypes,omitempty"`
+
+	// DeniedIssuerTypes are the types of Issuers and ClusterIssuers that may
+	// not be created (--denied-issuer-types).
+	// +optional
+	DeniedIssuerTypes []string `json:"deniedIssuerTypes,omitempty"`
+}
+
+// TLSConfig configures the serving certificate and TLS parameters of the
+// webhook.
+type TLSConfig struct {
+	// CertFile is the path to the serving certificate (--tls-cert-file).
+	// +optional
+	CertFile *string `json:"certFile,omitempty"`
+
+	// KeyFile is the path to the serving private key
+	// (--tls-private-key-file).
+	// +optional
+	KeyFile *string `json:"keyFile,omitempty"`
+
+	// CipherSuites is the list of allowed cipher suites
+	// (--tls-cipher-suites).
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+
+	// MinTLSVersion is the minimum TLS version supported
+	// (--tls-min-version).
+	// +optional
+	MinTLSVersion *string `json:"minTLSVersion,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CAInjectorConfiguration configures the cert-manager cainjector.
+type CAInjectorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Namespace limits the cainjector to a single namespace (--namespace).
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// LeaderElection configures leader election between replicas.
+	// +optional
+	LeaderElection *LeaderElectionConfig `json:"leaderElection,omitempty"`
+
+	// ShardCount is the number of shards that injection targets are split
+	// into (--shard-count).
+	// +optional
+	ShardCount *int32 `json:"shardCount,omitempty"`
+
+	// ShardIndex is the shard of injection targets that this instance
+	// injects CAs into (--shard-index).
+	// +optional
+	ShardIndex *int32 `json:"shardIndex,omitempty"`
+
+	// Logging configures the log output.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+}
+
+// LeaderElectionConfig configures leader election between replicas of a
+// component.
+type LeaderElectionConfig struct {
+	// Enabled determines whether leader election is performed
+	// (--leader-elect).
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Namespace is the namespace of the leader election lease
+	// (--leader-election-namespace).
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// LeaseDuration is the duration that non-leader candidates wait before
+	// acquiring an unrenewed lease (--leader-election-lease-duration).
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is the duration that the leader retries renewing the
+	// lease before giving it up (--leader-election-renew-deadline).
+	// +optional
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+
+	// RetryPeriod is the duration between attempts to acquire or renew the
+	// lease (--leader-election-retry-period).
+	// +optional
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// LoggingConfig configures the log output of a component.
+type LoggingConfig struct {
+	// Verbosity is the log level verbosity (-v). It is applied without a
+	// restart when the configuration file changes.
+	// +optional
+	Verbosity *int32 `json:"verbosity,omitempty"`
+
+	// Format is the format of log output, one of "text" or "json"
+	// (--logging-format).
+	// +optional
+	Format *string `json:"format,omitempty"`
+}
@@ -0,0 +1,60 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by defaulter-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// RegisterDefaults adds defaulters functions to the given scheme.
+// Public to allow building arbitrary schemes.
+// All generated defaulters are covering - they call all nested defaulters.
+func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&CAInjectorConfiguration{}, func(obj interface{}) { SetObjectDefaults_CAInjectorConfiguration(obj.(*CAInjectorConfiguration)) })
+	scheme.AddTypeDefaultingFunc(&ControllerConfiguration{}, func(obj interface{}) { SetObjectDefaults_ControllerConfiguration(obj.(*ControllerConfiguration)) })
+	scheme.AddTypeDefaultingFunc(&WebhookConfiguration{}, func(obj interface{}) { SetObjectDefaults_WebhookConfiguration(obj.(*WebhookConfiguration)) })
+	return nil
+}
+
+func SetObjectDefaults_CAInjectorConfiguration(in *CAInjectorConfiguration) {
+	SetDefaults_CAInjectorConfiguration(in)
+	if in.LeaderElection != nil {
+		SetDefaults_LeaderElectionConfig(in.LeaderElection)
+	}
+}
+
+func SetObjectDefaults_ControllerConfiguration(in *ControllerConfiguration) {
+	SetDefaults_ControllerConfiguration(in)
+	if in.LeaderElection != nil {
+		SetDefaults_LeaderElectionConfig(in.LeaderElection)
+	}
+	if in.IngressShim != nil {
+		SetDefaults_IngressShimConfig(in.IngressShim)
+	}
+	if in.ACMEDNS01 != nil {
+		SetDefaults_ACMEDNS01Config(in.ACMEDNS01)
+	}
+}
+
+func SetObjectDefaults_WebhookConfiguration(in *WebhookConfiguration) {
+	SetDefaults_WebhookConfiguration(in)
+}
@@ -26,6 +26,11 @@ const (
 	// CertificateSigningRequestIsCAAnnotationKey is the annotation key used to
 	// request whether the certificate should be marked as CA.
 	CertificateSigningRequestIsCAAnnotationKey = "experimental.cert-manager.io/request-is-ca"
+
+	// CertificateSigningRequestMaxPathLenAnnotationKey is the annotation key
+	// used to request the maximum number of intermediate CAs that may follow
+	// a CA certificate in a chain. It may only be set when requesting a CA.
+	CertificateSigningRequestMaxPathLenAnnotationKey = "experimental.cert-manager.io/request-max-path-len"
 )
 
 // SelfSigned Issuer specific Annotations
@@ -52,3 +57,11 @@ const (
 	// has been submitted to the Venafi API for collection later.
 	CertificateSigningRequestVenafiPickupIDAnnotationKey = "venafi.experimental.cert-manager.io/pickup-id"
 )
+
+// Vault Issuer specific Annotations
+const (
+	// CertificateSigningRequestVaultNamespaceAnnotationKey is the annotation
+	// that selects the Vault namespace a CertificateSigningRequest is signed
+	// in. The namespace must be listed in the Vault issuer's allowedNamespaces.
+	CertificateSigningRequestVaultNamespaceAnnotationKey = "vault.experimental.cert-manager.io/namespace"
+)
@@ -58,6 +58,12 @@ type ObjectReference struct {
 	// Group of the resource being referred to.
 	// +optional
 	Group string `json:"group,omitempty"`
+	// Namespace of the resource being referred to, if it is an Issuer in a
+	// namespace other than that of the referencing resource. The Issuer must
+	// be granted to the namespace of the referencing resource by an
+	// IssuerGrant in the namespace of the Issuer.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // A reference to a specific 'key' within a Secret resource.
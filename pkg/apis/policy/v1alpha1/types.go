@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+const (
+	// CertificateRequestPolicyKind is the kind name of CertificateRequestPolicy.
+	CertificateRequestPolicyKind = "CertificateRequestPolicy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A CertificateRequestPolicy restricts the CertificateRequests that the
+// ServiceAccounts it selects are allowed to create.
+//
+// A CertificateRequest created by a selected ServiceAccount is approved if
+// it is permitted by at least one of the policies selecting that
+// ServiceAccount, and is denied otherwise. CertificateRequests created by
+// ServiceAccounts that no policy selects are left pending until a policy
+// selecting them is created. CertificateRequestPolicies are only evaluated if
+// the certificaterequests-policy-approver controller is enabled.
+type CertificateRequestPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Desired state of the CertificateRequestPolicy resource.
+	Spec CertificateRequestPolicySpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateRequestPolicyList is a list of CertificateRequestPolicies
+type CertificateRequestPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CertificateRequestPolicy `json:"items"`
+}
+
+// CertificateRequestPolicySpec defines the CertificateRequests that may be
+// created by the selected ServiceAccounts.
+type CertificateRequestPolicySpec struct {
+	// ServiceAccounts selects the ServiceAccounts whose CertificateRequests
+	// are evaluated against this policy.
+	// +kubebuilder:validation:MinItems=1
+	ServiceAccounts []ServiceAccountSelector `json:"serviceAccounts"`
+
+	// Allowed are the attributes that a CertificateRequest may request.
+	// An attribute that is not listed here must not be requested.
+	// +optional
+	Allowed *CertificateRequestPolicyAllowed `json:"allowed,omitempty"`
+
+	// MinDuration is the shortest duration a CertificateRequest may request.
+	// +optional
+	MinDuration *metav1.Duration `json:"minDuration,omitempty"`
+
+	// MaxDuration is the longest duration a CertificateRequest may request.
+	// CertificateRequests that do not request a duration are evaluated
+	// against the default duration of 90 days.
+	// +optional
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+
+	// IssuerRefs are the issuers that a CertificateRequest may reference.
+	// If empty, any issuer may be referenced.
+	// +optional
+	IssuerRefs []IssuerSelector `json:"issuerRefs,omitempty"`
+}
+
+// ServiceAccountSelector selects ServiceAccounts by namespace and name.
+type ServiceAccountSelector struct {
+	// Namespace of the selected ServiceAccounts. `*` selects ServiceAccounts
+	// in all namespaces.
+	Namespace string `json:"namespace"`
+
+	// Name of the selected ServiceAccount. `*` selects all ServiceAccounts
+	// in the namespace.
+	Name string `json:"name"`
+}
+
+// CertificateRequestPolicyAllowed lists the attributes that a
+// CertificateRequest may request. Values may contain `*` wildcards that match
+// any sequence of characters, e.g. `*.example.com`. Values may also reference
+// the identity of the requester with `$(namespace)`, `$(serviceAccount)` and
+// `$(pod)`, e.g. `$(serviceAccount).$(namespace).svc`. Values referencing an
+// identity that could not be resolved never match.
+type CertificateRequestPolicyAllowed struct {
+	// CommonName are the allowed common names.
+	// +optional
+	CommonName []string `json:"commonName,omitempty"`
+
+	// Subject are the allowed values of other subject attributes.
+	// +optional
+	Subject *CertificateRequestPolicyAllowedSubject `json:"subject,omitempty"`
+
+	// DNSNames are the allowed DNS subject alternative names.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IPAddresses are the allowed IP address subject alternative names.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// URIs are the allowed URI subject alternative names.
+	// +optional
+	URIs []string `json:"uris,omitempty"`
+
+	// EmailAddresses are the allowed email subject alternative names.
+	// +optional
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
+
+	// Usages are the allowed key usages and extended key usages.
+	// CertificateRequests that do not request usages are evaluated against
+	// the default usages of `digital signature` and `key encipherment`.
+	// +optional
+	Usages []cmapi.KeyUsage `json:"usages,omitempty"`
+
+	// IsCA allows CertificateRequests to request a CA certificate.
+	// +optional
+	IsCA bool `json:"isCA,omitempty"`
+}
+
+// CertificateRequestPolicyAllowedSubject lists the allowed values of X.509
+// subject attributes other than the common name.
+type CertificateRequestPolicyAllowedSubject struct {
+	// +optional
+	Organizations []string `json:"organizations,omitempty"`
+	// +optional
+	Countries []string `json:"countries,omitempty"`
+	// +optional
+	OrganizationalUnits []string `json:"organizationalUnits,omitempty"`
+	// +optional
+	Localities []string `json:"localities,omitempty"`
+	// +optional
+	Provinces []string `json:"provinces,omitempty"`
+	// +optional
+	StreetAddresses []string `json:"streetAddresses,omitempty"`
+	// +optional
+	PostalCodes []string `json:"postalCodes,omitempty"`
+	// +optional
+	SerialNumber []string `json:"serialNumber,omitempty"`
+}
+
+// IssuerSelector selects issuers that may be referenced by a
+// CertificateRequest. An empty field matches any value, and values may
+// contain `*` wildcards.
+type IssuerSelector struct {
+	// Name of the issuer.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Kind of the issuer, e.g. `Issuer` or `ClusterIssuer`.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer, e.g. `cert-manager.io`.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IssuerGrantKind is the kind name of IssuerGrant.
+	IssuerGrantKind = "IssuerGrant"
+)
+
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// An IssuerGrant allows Certificates and CertificateRequests in other
+// namespaces to reference an Issuer in the namespace of the IssuerGrant.
+//
+// Resources reference an Issuer in another namespace by setting
+// `issuerRef.namespace`. The reference is only resolved if an IssuerGrant in
+// the namespace of the Issuer names both the Issuer and the namespace of the
+// referencing resource.
+type IssuerGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Desired state of the IssuerGrant resource.
+	Spec IssuerGrantSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IssuerGrantList is a list of IssuerGrants
+type IssuerGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []IssuerGrant `json:"items"`
+}
+
+// IssuerGrantSpec defines the Issuer that is granted and the namespaces it is
+// granted to.
+type IssuerGrantSpec struct {
+	// IssuerName is the name of the Issuer, in the namespace of the
+	// IssuerGrant, that may be referenced from the granted namespaces.
+	IssuerName string `json:"issuerName"`
+
+	// Namespaces are the names of the namespaces whose Certificates and
+	// CertificateRequests may reference the Issuer.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+const (
+	// CertificateRevocationKind is the kind name of CertificateRevocation.
+	CertificateRevocationKind = "CertificateRevocation"
+)
+
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A CertificateRevocation revokes a certificate that was issued by a CA
+// issuer. Revoked certificates are reported by the OCSP responder and are
+// added to the CRL of the issuer, if it is configured to maintain one.
+//
+// A CertificateRevocation referencing an Issuer must be created in the
+// namespace of that Issuer. A CertificateRevocation referencing a
+// ClusterIssuer must be created in the cluster resource namespace.
+type CertificateRevocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Desired state of the CertificateRevocation resource.
+	Spec CertificateRevocationSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateRevocationList is a list of CertificateRevocations
+type CertificateRevocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CertificateRevocation `json:"items"`
+}
+
+// CertificateRevocationSpec identifies the revoked certificate.
+type CertificateRevocationSpec struct {
+	// IssuerRef references the CA issuer that issued the revoked certificate.
+	// If the `kind` field is not set, or set to `Issuer`, an Issuer resource
+	// with the given name in the same namespace as the CertificateRevocation
+	// will be used. If the `kind` field is set to `ClusterIssuer`, a
+	// ClusterIssuer with the provided name will be used.
+	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
+
+	// SerialNumber is the serial number of the revoked certificate, encoded
+	// as hexadecimal. Bytes may optionally be separated by colons, e.g.
+	// `4f:1a:09`.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F:]+$`
+	SerialNumber string `json:"serialNumber"`
+
+	// Reason is the reason for the revocation.
+	// Defaults to `Unspecified`.
+	// +optional
+	Reason RevocationReason `json:"reason,omitempty"`
+
+	// RevocationTime is the time at which the certificate was revoked.
+	// Defaults to the creation time of the CertificateRevocation.
+	// +optional
+	RevocationTime *metav1.Time `json:"revocationTime,omitempty"`
+}
+
+// RevocationReason is a certificate revocation reason as defined in RFC 5280,
+// section 5.3.1.
+// +kubebuilder:validation:Enum=Unspecified;KeyCompromise;CACompromise;AffiliationChanged;Superseded;CessationOfOperation;CertificateHold;PrivilegeWithdrawn;AACompromise
+type RevocationReason string
+
+const (
+	RevocationReasonUnspecified          RevocationReason = "Unspecified"
+	RevocationReasonKeyCompromise        RevocationReason = "KeyCompromise"
+	RevocationReasonCACompromise         RevocationReason = "CACompromise"
+	RevocationReasonAffiliationChanged   RevocationReason = "AffiliationChanged"
+	RevocationReasonSuperseded           RevocationReason = "Superseded"
+	RevocationReasonCessationOfOperation RevocationReason = "CessationOfOperation"
+	RevocationReasonCertificateHold      RevocationReason = "CertificateHold"
+	RevocationReasonPrivilegeWithdrawn   RevocationReason = "PrivilegeWithdrawn"
+	RevocationReasonAACompromise         RevocationReason = "AACompromise"
+)
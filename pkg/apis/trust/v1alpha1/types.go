@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+const (
+	// BundleKind is the kind name of Bundle.
+	BundleKind = "Bundle"
+
+	// BundleLabelKey is the label set on every ConfigMap and Secret written
+	// by the bundles controller. Its value is the name of the Bundle.
+	BundleLabelKey = "trust.cert-manager.io/bundle"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A Bundle aggregates CA certificates from a number of sources and
+// distributes them to ConfigMaps and/or Secrets in the selected namespaces.
+//
+// Sources are read from the cluster resource namespace of cert-manager.
+// Targets are named after the Bundle and are only maintained if the optional
+// `bundles` controller is enabled.
+type Bundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Desired state of the Bundle resource.
+	Spec BundleSpec `json:"spec"`
+
+	// Status of the Bundle. This is set and managed automatically.
+	// +optional
+	Status BundleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BundleList is a list of Bundles
+type BundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Bundle `json:"items"`
+}
+
+// BundleSpec defines the sources of a Bundle and where it is distributed to.
+type BundleSpec struct {
+	// Sources is the set of sources whose CA certificates are combined into
+	// the Bundle. Duplicate certificates are only included once.
+	// +kubebuilder:validation:MinItems=1
+	Sources []BundleSource `json:"sources"`
+
+	// Target is where the Bundle is written to.
+	Target BundleTarget `json:"target"`
+}
+
+// BundleSource is a source of PEM encoded CA certificates. Exactly one of
+// the fields must be set.
+type BundleSource struct {
+	// Secret selects a key of a Secret in the cluster resource namespace,
+	// e.g. the `ca.crt` key of a Secret managed by a CA Certificate.
+	// +optional
+	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
+
+	// ConfigMap selects a key of a ConfigMap in the cluster resource
+	// namespace.
+	// +optional
+	ConfigMap *SourceObjectKeySelector `json:"configMap,omitempty"`
+
+	// InLine is a PEM encoded set of CA certificates.
+	// +optional
+	InLine *string `json:"inLine,omitempty"`
+}
+
+// SourceObjectKeySelector selects a key of a Secret or ConfigMap.
+type SourceObjectKeySelector struct {
+	// Name of the source object.
+	Name string `json:"name"`
+
+	// Key of the source object that holds the PEM encoded CA certificates.
+	Key string `json:"key"`
+}
+
+// BundleTarget is where a Bundle is written to. At least one of ConfigMap
+// and Secret must be set.
+type BundleTarget struct {
+	// ConfigMap writes the Bundle to the given key of a ConfigMap named after
+	// the Bundle in every selected namespace.
+	// +optional
+	ConfigMap *KeySelector `json:"configMap,omitempty"`
+
+	// Secret writes the Bundle to the given key of a Secret named after the
+	// Bundle in every selected namespace.
+	// +optional
+	Secret *KeySelector `json:"secret,omitempty"`
+
+	// NamespaceSelector selects the namespaces that the Bundle is written to.
+	// If not set, the Bundle is written to all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// KeySelector selects the key of a target object.
+type KeySelector struct {
+	// Key of the target object that the Bundle is written to.
+	Key string `json:"key"`
+}
+
+// BundleStatus defines the observed state of a Bundle.
+type BundleStatus struct {
+	// List of status conditions to indicate the status of the Bundle.
+	// Known condition types are `Synced`.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []BundleCondition `json:"conditions,omitempty"`
+
+	// Namespaces is the number of namespaces the Bundle was last written to.
+	// +optional
+	Namespaces int32 `json:"namespaces,omitempty"`
+}
+
+// BundleCondition contains condition information for a Bundle.
+type BundleCondition struct {
+	// Type of the condition, known values are (`Synced`).
+	Type BundleConditionType `json:"type"`
+
+	// Status of the condition, one of (`True`, `False`, `Unknown`).
+	Status cmmeta.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// If set, this represents the .metadata.generation that the condition was
+	// set based upon.
+	// For instance, if .metadata.generation is currently 12, but the
+	// .status.condition[x].observedGeneration is 9, the condition is out of date
+	// with respect to the current state of the Bundle.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// BundleConditionType represents a Bundle condition value.
+type BundleConditionType string
+
+const (
+	// BundleConditionSynced indicates that the Bundle has been written to all
+	// selected namespaces.
+	BundleConditionSynced BundleConditionType = "Synced"
+)
@@ -0,0 +1,168 @@
+/*
+Copyright The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	scheme "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CertificateRequestPoliciesGetter has a method to return a CertificateRequestPolicyInterface.
+// A group's client should implement this interface.
+type CertificateRequestPoliciesGetter interface {
+	CertificateRequestPolicies() CertificateRequestPolicyInterface
+}
+
+// CertificateRequestPolicyInterface has methods to work with CertificateRequestPolicy resources.
+type CertificateRequestPolicyInterface interface {
+	Create(ctx context.Context, certificateRequestPolicy *v1alpha1.CertificateRequestPolicy, opts v1.CreateOptions) (*v1alpha1.CertificateRequestPolicy, error)
+	Update(ctx context.Context, certificateRequestPolicy *v1alpha1.CertificateRequestPolicy, opts v1.UpdateOptions) (*v1alpha1.CertificateRequestPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.CertificateRequestPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.CertificateRequestPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.CertificateRequestPolicy, err error)
+	CertificateRequestPolicyExpansion
+}
+
+// certificateRequestPolicies implements CertificateRequestPolicyInterface
+type certificateRequestPolicies struct {
+	client rest.Interface
+}
+
+// newCertificateRequestPolicies returns a CertificateRequestPolicies
+func newCertificateRequestPolicies(c *PolicyV1alpha1Client) *certificateRequestPolicies {
+	return &certificateRequestPolicies{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the certificateRequestPolicy, and returns the corresponding certificateRequestPolicy object, and an error if there is any.
+func (c *certificateRequestPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.CertificateRequestPolicy, err error) {
+	result = &v1alpha1.CertificateRequestPolicy{}
+	err = c.client.Get().
+		Resource("certificaterequestpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CertificateRequestPolicies that match those selectors.
+func (c *certificateRequestPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.CertificateRequestPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.CertificateRequestPolicyList{}
+	err = c.client.Get().
+		Resource("certificaterequestpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested certificateRequestPolicies.
+func (c *certificateRequestPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("certificaterequestpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a certificateRequestPolicy and creates it.  Returns the server's representation of the certificateRequestPolicy, and an error, if there is any.
+func (c *certificateRequestPolicies) Create(ctx context.Context, certificateRequestPolicy *v1alpha1.CertificateRequestPolicy, opts v1.CreateOptions) (result *v1alpha1.CertificateRequestPolicy, err error) {
+	result = &v1alpha1.CertificateRequestPolicy{}
+	err = c.client.Post().
+		Resource("certificaterequestpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(certificateRequestPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a certificateRequestPolicy and updates it. Returns the server's representation of the certificateRequestPolicy, and an error, if there is any.
+func (c *certificateRequestPolicies) Update(ctx context.Context, certificateRequestPolicy *v1alpha1.CertificateRequestPolicy, opts v1.UpdateOptions) (result *v1alpha1.CertificateRequestPolicy, err error) {
+	result = &v1alpha1.CertificateRequestPolicy{}
+	err = c.client.Put().
+		Resource("certificaterequestpolicies").
+		Name(certificateRequestPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(certificateRequestPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the certificateRequestPolicy and deletes it. Returns an error if one occurs.
+func (c *certificateRequestPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("certificaterequestpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *certificateRequestPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("certificaterequestpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched certificateRequestPolicy.
+func (c *certificateRequestPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.CertificateRequestPolicy, err error) {
+	result = &v1alpha1.CertificateRequestPolicy{}
+	err = c.client.Patch(pt).
+		Resource("certificaterequestpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
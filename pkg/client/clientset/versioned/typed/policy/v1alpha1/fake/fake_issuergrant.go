@@ -0,0 +1,130 @@
+/*
+Copyright The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeIssuerGrants implements IssuerGrantInterface
+type FakeIssuerGrants struct {
+	Fake *FakePolicyV1alpha1
+	ns   string
+}
+
+var issuergrantsResource = schema.GroupVersionResource{Group: "policy.cert-manager.io", Version: "v1alpha1", Resource: "issuergrants"}
+
+var issuergrantsKind = schema.GroupVersionKind{Group: "policy.cert-manager.io", Version: "v1alpha1", Kind: "IssuerGrant"}
+
+// Get takes name of the issuerGrant, and returns the corresponding issuerGrant object, and an error if there is any.
+func (c *FakeIssuerGrants) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.IssuerGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(issuergrantsResource, c.ns, name), &v1alpha1.IssuerGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IssuerGrant), err
+}
+
+// List takes label and field selectors, and returns the list of IssuerGrants that match those selectors.
+func (c *FakeIssuerGrants) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.IssuerGrantList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(issuergrantsResource, issuergrantsKind, c.ns, opts), &v1alpha1.IssuerGrantList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.IssuerGrantList{ListMeta: obj.(*v1alpha1.IssuerGrantList).ListMeta}
+	for _, item := range obj.(*v1alpha1.IssuerGrantList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested issuerGrants.
+func (c *FakeIssuerGrants) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(issuergrantsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a issuerGrant and creates it.  Returns the server's representation of the issuerGrant, and an error, if there is any.
+func (c *FakeIssuerGrants) Create(ctx context.Context, issuerGrant *v1alpha1.IssuerGrant, opts v1.CreateOptions) (result *v1alpha1.IssuerGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(issuergrantsResource, c.ns, issuerGrant), &v1alpha1.IssuerGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IssuerGrant), err
+}
+
+// Update takes the representation of a issuerGrant and updates it. Returns the server's representation of the issuerGrant, and an error, if there is any.
+func (c *FakeIssuerGrants) Update(ctx context.Context, issuerGrant *v1alpha1.IssuerGrant, opts v1.UpdateOptions) (result *v1alpha1.IssuerGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(issuergrantsResource, c.ns, issuerGrant), &v1alpha1.IssuerGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IssuerGrant), err
+}
+
+// Delete takes name of the issuerGrant and deletes it. Returns an error if one occurs.
+func (c *FakeIssuerGrants) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(issuergrantsResource, c.ns, name), &v1alpha1.IssuerGrant{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeIssuerGrants) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(issuergrantsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.IssuerGrantList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched issuerGrant.
+func (c *FakeIssuerGrants) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.IssuerGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(issuergrantsResource, c.ns, name, pt, data, subresources...), &v1alpha1.IssuerGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IssuerGrant), err
+}
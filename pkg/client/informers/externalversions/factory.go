@@ -27,6 +27,9 @@ import (
 	acme "github.com/jetstack/cert-manager/pkg/client/informers/externalversions/acme"
 	certmanager "github.com/jetstack/cert-manager/pkg/client/informers/externalversions/certmanager"
 	internalinterfaces "github.com/jetstack/cert-manager/pkg/client/informers/externalversions/internalinterfaces"
+	policy "github.com/jetstack/cert-manager/pkg/client/informers/externalversions/policy"
+	revocation "github.com/jetstack/cert-manager/pkg/client/informers/externalversions/revocation"
+	trust "github.com/jetstack/cert-manager/pkg/client/informers/externalversions/trust"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -175,6 +178,9 @@ type SharedInformerFactory interface {
 
 	Acme() acme.Interface
 	Certmanager() certmanager.Interface
+	Policy() policy.Interface
+	Revocation() revocation.Interface
+	Trust() trust.Interface
 }
 
 func (f *sharedInformerFactory) Acme() acme.Interface {
@@ -184,3 +190,15 @@ func (f *sharedInformerFactory) Acme() acme.Interface {
 func (f *sharedInformerFactory) Certmanager() certmanager.Interface {
 	return certmanager.New(f, f.namespace, f.tweakListOptions)
 }
+
+func (f *sharedInformerFactory) Policy() policy.Interface {
+	return policy.New(f, f.namespace, f.tweakListOptions)
+}
+
+func (f *sharedInformerFactory) Revocation() revocation.Interface {
+	return revocation.New(f, f.namespace, f.tweakListOptions)
+}
+
+func (f *sharedInformerFactory) Trust() trust.Interface {
+	return trust.New(f, f.namespace, f.tweakListOptions)
+}
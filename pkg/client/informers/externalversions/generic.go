@@ -29,6 +29,9 @@ import (
 	certmanagerv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	certmanagerv1alpha3 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha3"
 	certmanagerv1beta1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1beta1"
+	v1alpha1 "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	revocationv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	trustv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/trust/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -123,6 +126,20 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case certmanagerv1beta1.SchemeGroupVersion.WithResource("issuers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1beta1().Issuers().Informer()}, nil
 
+		// Group=policy.cert-manager.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("certificaterequestpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().CertificateRequestPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("issuergrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().IssuerGrants().Informer()}, nil
+
+		// Group=revocation.cert-manager.io, Version=v1alpha1
+	case revocationv1alpha1.SchemeGroupVersion.WithResource("certificaterevocations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Revocation().V1alpha1().CertificateRevocations().Informer()}, nil
+
+		// Group=trust.cert-manager.io, Version=v1alpha1
+	case trustv1alpha1.SchemeGroupVersion.WithResource("bundles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trust().V1alpha1().Bundles().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -0,0 +1,99 @@
+/*
+Copyright The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// IssuerGrantLister helps list IssuerGrants.
+// All objects returned here must be treated as read-only.
+type IssuerGrantLister interface {
+	// List lists all IssuerGrants in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.IssuerGrant, err error)
+	// IssuerGrants returns an object that can list and get IssuerGrants.
+	IssuerGrants(namespace string) IssuerGrantNamespaceLister
+	IssuerGrantListerExpansion
+}
+
+// issuerGrantLister implements the IssuerGrantLister interface.
+type issuerGrantLister struct {
+	indexer cache.Indexer
+}
+
+// NewIssuerGrantLister returns a new IssuerGrantLister.
+func NewIssuerGrantLister(indexer cache.Indexer) IssuerGrantLister {
+	return &issuerGrantLister{indexer: indexer}
+}
+
+// List lists all IssuerGrants in the indexer.
+func (s *issuerGrantLister) List(selector labels.Selector) (ret []*v1alpha1.IssuerGrant, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.IssuerGrant))
+	})
+	return ret, err
+}
+
+// IssuerGrants returns an object that can list and get IssuerGrants.
+func (s *issuerGrantLister) IssuerGrants(namespace string) IssuerGrantNamespaceLister {
+	return issuerGrantNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// IssuerGrantNamespaceLister helps list and get IssuerGrants.
+// All objects returned here must be treated as read-only.
+type IssuerGrantNamespaceLister interface {
+	// List lists all IssuerGrants in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.IssuerGrant, err error)
+	// Get retrieves the IssuerGrant from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.IssuerGrant, error)
+	IssuerGrantNamespaceListerExpansion
+}
+
+// issuerGrantNamespaceLister implements the IssuerGrantNamespaceLister
+// interface.
+type issuerGrantNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all IssuerGrants in the indexer for a given namespace.
+func (s issuerGrantNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.IssuerGrant, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.IssuerGrant))
+	})
+	return ret, err
+}
+
+// Get retrieves the IssuerGrant from the indexer for a given namespace and name.
+func (s issuerGrantNamespaceLister) Get(name string) (*v1alpha1.IssuerGrant, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("issuergrant"), name)
+	}
+	return obj.(*v1alpha1.IssuerGrant), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CertificateRevocationLister helps list CertificateRevocations.
+// All objects returned here must be treated as read-only.
+type CertificateRevocationLister interface {
+	// List lists all CertificateRevocations in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.CertificateRevocation, err error)
+	// CertificateRevocations returns an object that can list and get CertificateRevocations.
+	CertificateRevocations(namespace string) CertificateRevocationNamespaceLister
+	CertificateRevocationListerExpansion
+}
+
+// certificateRevocationLister implements the CertificateRevocationLister interface.
+type certificateRevocationLister struct {
+	indexer cache.Indexer
+}
+
+// NewCertificateRevocationLister returns a new CertificateRevocationLister.
+func NewCertificateRevocationLister(indexer cache.Indexer) CertificateRevocationLister {
+	return &certificateRevocationLister{indexer: indexer}
+}
+
+// List lists all CertificateRevocations in the indexer.
+func (s *certificateRevocationLister) List(selector labels.Selector) (ret []*v1alpha1.CertificateRevocation, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.CertificateRevocation))
+	})
+	return ret, err
+}
+
+// CertificateRevocations returns an object that can list and get CertificateRevocations.
+func (s *certificateRevocationLister) CertificateRevocations(namespace string) CertificateRevocationNamespaceLister {
+	return certificateRevocationNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CertificateRevocationNamespaceLister helps list and get CertificateRevocations.
+// All objects returned here must be treated as read-only.
+type CertificateRevocationNamespaceLister interface {
+	// List lists all CertificateRevocations in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.CertificateRevocation, err error)
+	// Get retrieves the CertificateRevocation from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.CertificateRevocation, error)
+	CertificateRevocationNamespaceListerExpansion
+}
+
+// certificateRevocationNamespaceLister implements the CertificateRevocationNamespaceLister
+// interface.
+type certificateRevocationNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CertificateRevocations in the indexer for a given namespace.
+func (s certificateRevocationNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.CertificateRevocation, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.CertificateRevocation))
+	})
+	return ret, err
+}
+
+// Get retrieves the CertificateRevocation from the indexer for a given namespace and name.
+func (s certificateRevocationNamespaceLister) Get(name string) (*v1alpha1.CertificateRevocation, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("certificaterevocation"), name)
+	}
+	return obj.(*v1alpha1.CertificateRevocation), nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/jetstack/cert-manager/pkg/apis/trust/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// BundleLister helps list Bundles.
+// All objects returned here must be treated as read-only.
+type BundleLister interface {
+	// List lists all Bundles in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.Bundle, err error)
+	// Get retrieves the Bundle from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.Bundle, error)
+	BundleListerExpansion
+}
+
+// bundleLister implements the BundleLister interface.
+type bundleLister struct {
+	indexer cache.Indexer
+}
+
+// NewBundleLister returns a new BundleLister.
+func NewBundleLister(indexer cache.Indexer) BundleLister {
+	return &bundleLister{indexer: indexer}
+}
+
+// List lists all Bundles in the indexer.
+func (s *bundleLister) List(selector labels.Selector) (ret []*v1alpha1.Bundle, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.Bundle))
+	})
+	return ret, err
+}
+
+// Get retrieves the Bundle from the index for a given name.
+func (s *bundleLister) Get(name string) (*v1alpha1.Bundle, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("bundle"), name)
+	}
+	return obj.(*v1alpha1.Bundle), nil
+}
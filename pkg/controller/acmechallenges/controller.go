@@ -23,22 +23,26 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/jetstack/cert-manager/internal/ingress"
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	cmacmelisters "github.com/jetstack/cert-manager/pkg/client/listers/acme/v1"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/acmechallenges/scheduler"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/tlsalpn"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
 )
 
 type controller struct {
@@ -57,8 +61,9 @@ type controller struct {
 	// ACME challenge solvers are instantiated once at the time of controller
 	// construction.
 	// This also allows for easy mocking of the different challenge mechanisms.
-	dnsSolver  solver
-	httpSolver solver
+	dnsSolver     solver
+	httpSolver    solver
+	tlsalpnSolver solver
 	// scheduler marks challenges as Processing=true if they can be scheduled
 	// for processing. This job runs periodically every N seconds, so it cannot
 	// be constructed as a traditional controller.
@@ -66,9 +71,16 @@ type controller struct {
 
 	// used to record Events about resources to the API
 	recorder record.EventRecorder
+	// used to determine whether an issuer maintenance window is active
+	clock clock.Clock
 	// clientset used to update cert-manager API resources
 	cmClient cmclient.Interface
 
+	// statusPatcher and fieldManager are used to apply the status of
+	// Challenges when the ServerSideApply feature is enabled
+	statusPatcher *statuspatch.Patcher
+	fieldManager  string
+
 	// maintain a reference to the workqueue for this controller
 	// so the handleOwnedResource method can enqueue resources
 	queue workqueue.RateLimitingInterface
@@ -76,6 +88,10 @@ type controller struct {
 	// logger to be used by this controller
 	log logr.Logger
 
+	// metrics records the self checks of challenges and errors returned by
+	// ACME servers
+	metrics *metrics.Metrics
+
 	dns01Nameservers []string
 
 	DNS01CheckRetryPeriod time.Duration
@@ -86,14 +102,17 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
+	c.queue = workqueue.NewNamedRateLimitingQueue(ctx.WorkqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 5,
+		MaxDelay:  time.Minute * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	challengeInformer := ctx.SharedInformerFactory.Acme().V1().Challenges()
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
 	secretInformer := ctx.KubeSharedInformerFactory.Core().V1().Secrets()
-	// we register these informers here so the HTTP01 solver has a synced
-	// cache when managing pod/service/ingress resources
+	// we register these informers here so the HTTP01 and TLSALPN01 solvers
+	// have a synced cache when managing pod/service/ingress resources
 	podInformer := ctx.KubeSharedInformerFactory.Core().V1().Pods()
 	serviceInformer := ctx.KubeSharedInformerFactory.Core().V1().Services()
 
@@ -114,7 +133,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	}
 
 	if ctx.GatewaySolverEnabled {
-		gwAPIHTTPRouteInformer := ctx.GWShared.Networking().V1alpha1().HTTPRoutes()
+		gwAPIHTTPRouteInformer := ctx.GWShared.Gateway().V1alpha2().HTTPRoutes()
 		mustSync = append(mustSync, gwAPIHTTPRouteInformer.Informer().HasSynced)
 	}
 
@@ -133,11 +152,23 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// register handler functions
 	challengeInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: c.queue})
-
-	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister)
-	c.scheduler = scheduler.New(logf.NewContext(ctx.RootContext, c.log), c.challengeLister, ctx.SchedulerOptions.MaxConcurrentChallenges)
+	ctx.RequeueOnNamespaceChange(challengeInformer.Informer(), (&controllerpkg.QueuingEventHandler{Queue: c.queue}).Enqueue)
+
+	issuerGrantInformer := ctx.SharedInformerFactory.Policy().V1alpha1().IssuerGrants()
+	mustSync = append(mustSync, issuerGrantInformer.Informer().HasSynced)
+	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister, issuerGrantInformer.Lister())
+	// Only schedule the Challenges that are processed by this replica, so
+	// that Challenges in other shards are not marked as processing here, and
+	// that are not deferred by a maintenance window of their issuer.
+	c.scheduler = scheduler.New(logf.NewContext(ctx.RootContext, c.log), c.challengeLister, ctx.SchedulerOptions.MaxConcurrentChallenges, func(ch *cmacme.Challenge) bool {
+		return ctx.OwnsKey(ch.Namespace+"/"+ch.Name) && c.schedulable(ch)
+	})
 	c.recorder = ctx.Recorder
+	c.clock = ctx.Clock
+	c.metrics = ctx.Metrics
 	c.cmClient = ctx.CMClient
+	c.statusPatcher = ctx.StatusPatcher
+	c.fieldManager = controllerpkg.FieldManager(ControllerName)
 	c.accountRegistry = ctx.ACMEOptions.AccountRegistry
 
 	c.httpSolver, err = http.NewSolver(ctx)
@@ -148,6 +179,10 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	if err != nil {
 		return nil, nil, err
 	}
+	c.tlsalpnSolver, err = tlsalpn.NewSolver(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// read options from context
 	c.dns01Nameservers = ctx.ACMEOptions.DNS01Nameservers
@@ -177,12 +212,12 @@ func (c *controller) runScheduler(ctx context.Context) {
 		return
 	}
 
-	for _, ch := range toSchedule {
-		log := logf.WithResource(log, ch)
-		ch = ch.DeepCopy()
+	for _, oldChal := range toSchedule {
+		log := logf.WithResource(log, oldChal)
+		ch := oldChal.DeepCopy()
 		ch.Status.Processing = true
 
-		_, err := c.cmClient.AcmeV1().Challenges(ch.Namespace).UpdateStatus(ctx, ch, metav1.UpdateOptions{})
+		err := c.updateStatus(ctx, oldChal, ch)
 		if err != nil {
 			log.Error(err, "error scheduling challenge for processing")
 			return
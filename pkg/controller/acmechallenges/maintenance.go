@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+const reasonMaintenanceWindow = "MaintenanceWindow"
+
+// activeMaintenanceWindow returns the first maintenance window of the ACME
+// issuer that contains the given time, or nil if there is none.
+func activeMaintenanceWindow(iss cmapi.GenericIssuer, now time.Time) *cmacme.ACMEMaintenanceWindow {
+	acme := iss.GetSpec().ACME
+	if acme == nil {
+		return nil
+	}
+
+	for i, window := range acme.MaintenanceWindows {
+		if now.Before(window.Start.Time) {
+			continue
+		}
+		if window.End != nil && !now.Before(window.End.Time) {
+			continue
+		}
+		return &acme.MaintenanceWindows[i]
+	}
+
+	return nil
+}
+
+// deferredByMaintenanceWindow returns true if presenting the challenge is
+// deferred by an active maintenance window of its issuer, in which case the
+// challenge must not be scheduled for processing.
+func deferredByMaintenanceWindow(ch *cmacme.Challenge, iss cmapi.GenericIssuer, now time.Time) bool {
+	if ch.Spec.Type != cmacme.ACMEChallengeTypeDNS01 || ch.Status.Presented {
+		return false
+	}
+	return activeMaintenanceWindow(iss, now) != nil
+}
+
+// schedulable is used by the scheduler to skip the challenges that are
+// deferred by a maintenance window, so that they do not hold one of the
+// slots limited by --max-concurrent-challenges until the window has ended.
+func (c *controller) schedulable(ch *cmacme.Challenge) bool {
+	iss, err := c.helper.GetGenericIssuer(ch.Spec.IssuerRef, ch.Namespace)
+	if err != nil {
+		// the error is reported once the challenge is synced
+		return true
+	}
+	return !deferredByMaintenanceWindow(ch, iss, c.clock.Now())
+}
+
+// deferForMaintenanceWindow records on the challenge that presenting it has
+// been deferred by the given maintenance window, and releases its scheduler
+// slot. The scheduler schedules the challenge again once the window has
+// ended.
+func (c *controller) deferForMaintenanceWindow(ch *cmacme.Challenge, window *cmacme.ACMEMaintenanceWindow) error {
+	reason := "Presenting challenge deferred by issuer maintenance window"
+	if window.End != nil {
+		reason = fmt.Sprintf("%s until %s", reason, window.End.UTC().Format(time.RFC3339))
+	}
+	if window.Reason != "" {
+		reason = fmt.Sprintf("%s: %s", reason, window.Reason)
+	}
+
+	// only fire an event the first time the challenge is deferred by this
+	// window to avoid spamming events if it is deferred again
+	if ch.Status.Reason != reason {
+		c.recorder.Event(ch, corev1.EventTypeNormal, reasonMaintenanceWindow, reason)
+		ch.Status.Reason = reason
+	}
+	ch.Status.Processing = false
+
+	return nil
+}
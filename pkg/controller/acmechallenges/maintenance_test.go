@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestDeferredByMaintenanceWindow(t *testing.T) {
+	now := time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC)
+	end := metav1.NewTime(now.Add(time.Hour))
+	issuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		MaintenanceWindows: []cmacme.ACMEMaintenanceWindow{
+			{Start: metav1.NewTime(now.Add(-time.Hour)), End: &end},
+		},
+	}))
+	dns01 := gen.Challenge("testchal", gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01))
+
+	tests := map[string]struct {
+		challenge *cmacme.Challenge
+		now       time.Time
+		expected  bool
+	}{
+		"DNS01 challenge during the maintenance window": {
+			challenge: dns01,
+			now:       now,
+			expected:  true,
+		},
+		"DNS01 challenge after the maintenance window": {
+			challenge: dns01,
+			now:       end.Time,
+			expected:  false,
+		},
+		"presented DNS01 challenge during the maintenance window": {
+			challenge: gen.ChallengeFrom(dns01, gen.SetChallengePresented(true)),
+			now:       now,
+			expected:  false,
+		},
+		"HTTP01 challenge during the maintenance window": {
+			challenge: gen.Challenge("testchal", gen.SetChallengeType(cmacme.ACMEChallengeTypeHTTP01)),
+			now:       now,
+			expected:  false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := deferredByMaintenanceWindow(test.challenge, issuer, test.now); got != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, got)
+			}
+		})
+	}
+}
@@ -36,12 +36,17 @@ type Scheduler struct {
 	log                     logr.Logger
 	challengeLister         cmacmelisters.ChallengeLister
 	maxConcurrentChallenges int
+	// owns reports whether a challenge should be considered by this
+	// scheduler. If nil, all challenges are considered.
+	owns func(*cmacme.Challenge) bool
 }
 
-// New will construct a new instance of a scheduler
-func New(ctx context.Context, l cmacmelisters.ChallengeLister, maxConcurrentChallenges int) *Scheduler {
+// New will construct a new instance of a scheduler.
+// If owns is not nil, only challenges for which it returns true will be
+// scheduled or counted towards maxConcurrentChallenges.
+func New(ctx context.Context, l cmacmelisters.ChallengeLister, maxConcurrentChallenges int, owns func(*cmacme.Challenge) bool) *Scheduler {
 	log := logs.FromContext(ctx, "challenge-scheduler")
-	return &Scheduler{log: log, challengeLister: l, maxConcurrentChallenges: maxConcurrentChallenges}
+	return &Scheduler{log: log, challengeLister: l, maxConcurrentChallenges: maxConcurrentChallenges, owns: owns}
 }
 
 // ScheduleN will return a maximum of N challenge resources that should be
@@ -54,6 +59,9 @@ func (s *Scheduler) ScheduleN(n int) ([]*cmacme.Challenge, error) {
 	if err != nil {
 		return nil, err
 	}
+	if s.owns != nil {
+		allChallenges = filterChallenges(allChallenges, s.owns)
+	}
 
 	return s.scheduleN(n, allChallenges)
 }
@@ -124,6 +124,7 @@ func TestScheduleN(t *testing.T) {
 		name       string
 		n          int
 		challenges []*cmacme.Challenge
+		owns       func(*cmacme.Challenge) bool
 		expected   []*cmacme.Challenge
 		err        bool
 	}{
@@ -298,6 +299,23 @@ func TestScheduleN(t *testing.T) {
 					gen.SetChallengeProcessing(true)),
 			},
 		},
+		{
+			name: "only schedule challenges that are owned by the scheduler",
+			n:    5,
+			challenges: []*cmacme.Challenge{
+				gen.Challenge("test",
+					gen.SetChallengeDNSName("example.com")),
+				gen.Challenge("test2",
+					gen.SetChallengeDNSName("example2.com")),
+			},
+			owns: func(ch *cmacme.Challenge) bool {
+				return ch.Name == "test2"
+			},
+			expected: []*cmacme.Challenge{
+				gen.Challenge("test2",
+					gen.SetChallengeDNSName("example2.com")),
+			},
+		},
 		{
 			name: "don't schedule anything if all challenges are in a final state",
 			n:    5,
@@ -319,7 +337,7 @@ func TestScheduleN(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			s := New(context.Background(), challengesInformer.Lister(), maxConcurrentChallenges)
+			s := New(context.Background(), challengesInformer.Lister(), maxConcurrentChallenges, test.owns)
 
 			if test.expected == nil {
 				test.expected = []*cmacme.Challenge{}
@@ -25,9 +25,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/jetstack/cert-manager/pkg/acme"
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -35,6 +37,8 @@ import (
 	"github.com/jetstack/cert-manager/pkg/feature"
 	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/tracing"
 	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
 
@@ -68,6 +72,9 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	oldChal := ch
 	ch = ch.DeepCopy()
 
+	ctx, span := tracing.Start(ctx, "SyncChallenge", ch)
+	defer func() { tracing.End(span, err) }()
+
 	if ch.DeletionTimestamp != nil {
 		return c.handleFinalizer(ctx, ch)
 	}
@@ -76,7 +83,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		if apiequality.Semantic.DeepEqual(oldChal.Status, ch.Status) && len(oldChal.Finalizers) == len(ch.Finalizers) {
 			return
 		}
-		_, updateErr := c.cmClient.AcmeV1().Challenges(ch.Namespace).UpdateStatus(ctx, ch, metav1.UpdateOptions{})
+		updateErr := c.updateStatus(ctx, oldChal, ch)
 		if updateErr != nil {
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 		}
@@ -119,7 +126,13 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		return nil
 	}
 
-	cl, err := c.accountRegistry.GetClient(string(genericIssuer.GetUID()))
+	uid := string(genericIssuer.GetUID())
+	if ch.Spec.Preflight {
+		// challenges for the preflight server are accepted using the
+		// issuer's preflight account
+		uid = accounts.PreflightUID(uid)
+	}
+	cl, err := c.accountRegistry.GetClient(uid)
 	if err != nil {
 		return err
 	}
@@ -127,7 +140,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	if ch.Status.State == "" {
 		err := c.syncChallengeStatus(ctx, cl, ch)
 		if err != nil {
-			return handleError(ch, err)
+			return c.handleError(ch, err)
 		}
 
 		// if the state has not changed, return an error
@@ -150,7 +163,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		// Find out which identity the ACME server says it will use.
 		dir, err := cl.Discover(ctx)
 		if err != nil {
-			return handleError(ch, err)
+			return c.handleError(ch, err)
 		}
 		// TODO(dmo): figure out if missing CAA identity in directory
 		// means no CAA check is performed by ACME server or if any valid
@@ -170,7 +183,15 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	}
 
 	if !ch.Status.Presented {
-		err := solver.Present(ctx, genericIssuer, ch)
+		if ch.Spec.Type == cmacme.ACMEChallengeTypeDNS01 {
+			if window := activeMaintenanceWindow(genericIssuer, c.clock.Now()); window != nil {
+				return c.deferForMaintenanceWindow(ch, window)
+			}
+		}
+
+		presentCtx, presentSpan := tracing.Start(ctx, "PresentChallenge", ch)
+		err := solver.Present(presentCtx, genericIssuer, ch)
+		tracing.End(presentSpan, err)
 		if err != nil {
 			c.recorder.Eventf(ch, corev1.EventTypeWarning, reasonPresentError, "Error presenting challenge: %v", err)
 			ch.Status.Reason = err.Error()
@@ -181,8 +202,12 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		c.recorder.Eventf(ch, corev1.EventTypeNormal, reasonPresented, "Presented challenge using %s challenge mechanism", ch.Spec.Type)
 	}
 
-	err = solver.Check(ctx, genericIssuer, ch)
+	metricsIssuer := metrics.ACMEIssuerFor(genericIssuer, ch.Spec.Preflight)
+	checkCtx, checkSpan := tracing.Start(ctx, "CheckChallenge", ch)
+	err = solver.Check(checkCtx, genericIssuer, ch)
+	tracing.End(checkSpan, err)
 	if err != nil {
+		c.metrics.IncrementACMEChallengeSelfCheckCount(metricsIssuer, ch, metrics.SelfCheckResultFailed)
 		log.Error(err, "propagation check failed")
 		ch.Status.Reason = fmt.Sprintf("Waiting for %s challenge propagation: %s", ch.Spec.Type, err)
 
@@ -196,8 +221,12 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 
 		return nil
 	}
+	c.metrics.IncrementACMEChallengeSelfCheckCount(metricsIssuer, ch, metrics.SelfCheckResultPassed)
+	c.metrics.ObserveACMEChallengePropagationDuration(metricsIssuer, ch)
 
-	err = c.acceptChallenge(ctx, cl, ch)
+	acceptCtx, acceptSpan := tracing.Start(ctx, "AcceptChallenge", ch)
+	err = c.acceptChallenge(acceptCtx, cl, ch)
+	tracing.End(acceptSpan, err)
 	if err != nil {
 		return err
 	}
@@ -208,11 +237,13 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 // handleError will handle ACME error types, updating the challenge resource
 // with any new information found whilst inspecting the error response.
 // This may include marking the challenge as expired.
-func handleError(ch *cmacme.Challenge, err error) error {
+func (c *controller) handleError(ch *cmacme.Challenge, err error) error {
 	if err == nil {
 		return nil
 	}
 
+	c.recordACMEError(ch, err)
+
 	var acmeErr *acmeapi.Error
 	var ok bool
 	if acmeErr, ok = err.(*acmeapi.Error); !ok {
@@ -239,6 +270,32 @@ func handleError(ch *cmacme.Challenge, err error) error {
 	return err
 }
 
+// recordACMEError records an error returned by the ACME server that the
+// Challenge is being completed with.
+func (c *controller) recordACMEError(ch *cmacme.Challenge, err error) {
+	genericIssuer, getErr := c.helper.GetGenericIssuer(ch.Spec.IssuerRef, ch.Namespace)
+	if getErr != nil {
+		return
+	}
+	c.metrics.IncrementACMEErrorCount(metrics.ACMEIssuerFor(genericIssuer, ch.Spec.Preflight), err)
+}
+
+// updateStatus writes the status of ch to the apiserver. If the
+// ServerSideApply feature is enabled the fields changed from oldChal are
+// applied, otherwise the status is updated.
+func (c *controller) updateStatus(ctx context.Context, oldChal, ch *cmacme.Challenge) error {
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		key := "challenges/" + ch.Namespace + "/" + ch.Name
+		gvk := cmacme.SchemeGroupVersion.WithKind(cmacme.ChallengeKind)
+		return c.statusPatcher.Apply(ctx, key, gvk, oldChal, c.fieldManager, oldChal.Status, ch.Status, func(ctx context.Context, pt types.PatchType, patch []byte, opts metav1.PatchOptions) error {
+			_, err := c.cmClient.AcmeV1().Challenges(ch.Namespace).Patch(ctx, ch.Name, pt, patch, opts, "status")
+			return err
+		})
+	}
+	_, err := c.cmClient.AcmeV1().Challenges(ch.Namespace).UpdateStatus(ctx, ch, metav1.UpdateOptions{})
+	return err
+}
+
 // handleFinalizer will attempt to 'finalize' the Challenge resource by calling
 // CleanUp if the resource is in a 'processing' state.
 func (c *controller) handleFinalizer(ctx context.Context, ch *cmacme.Challenge) (err error) {
@@ -377,7 +434,7 @@ func (c *controller) acceptChallenge(ctx context.Context, cl acmecl.Interface, c
 	if err != nil {
 		log.Error(err, "error accepting challenge")
 		ch.Status.Reason = fmt.Sprintf("Error accepting challenge: %v", err)
-		return handleError(ch, err)
+		return c.handleError(ch, err)
 	}
 
 	log.V(logf.DebugLevel).Info("waiting for authorization for domain")
@@ -397,7 +454,7 @@ func (c *controller) acceptChallenge(ctx context.Context, cl acmecl.Interface, c
 func (c *controller) handleAuthorizationError(ch *cmacme.Challenge, err error) error {
 	authErr, ok := err.(*acmeapi.AuthorizationError)
 	if !ok {
-		return handleError(ch, err)
+		return c.handleError(ch, err)
 	}
 
 	// TODO: the AuthorizationError above could technically contain the final
@@ -421,6 +478,8 @@ func (c *controller) solverFor(challengeType cmacme.ACMEChallengeType) (solver,
 		return c.httpSolver, nil
 	case cmacme.ACMEChallengeTypeDNS01:
 		return c.dnsSolver, nil
+	case cmacme.ACMEChallengeTypeTLSALPN01:
+		return c.tlsalpnSolver, nil
 	}
 	return nil, fmt.Errorf("no solver for %q implemented", challengeType)
 }
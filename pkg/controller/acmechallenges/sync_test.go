@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	acmeapi "golang.org/x/crypto/acme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
 
 	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
@@ -85,7 +88,108 @@ func TestSyncHappyPath(t *testing.T) {
 		}),
 	)
 
+	fixedClockStart := time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC)
+	testIssuerDNS01Maintenance := func(start, end time.Time) *v1.Issuer {
+		endTime := metav1.NewTime(end)
+		return gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+			Solvers: []cmacme.ACMEChallengeSolver{
+				{
+					DNS01: &cmacme.ACMEChallengeSolverDNS01{
+						Webhook: &cmacme.ACMEIssuerDNS01ProviderWebhook{},
+					},
+				},
+			},
+			MaintenanceWindows: []cmacme.ACMEMaintenanceWindow{
+				{
+					Start:  metav1.NewTime(start),
+					End:    &endTime,
+					Reason: "provider upgrade",
+				},
+			},
+		}))
+	}
+
 	tests := map[string]testT{
+		"defer presenting a DNS01 challenge during an issuer maintenance window": {
+			challenge: gen.ChallengeFrom(baseChallenge,
+				gen.SetChallengeProcessing(true),
+				gen.SetChallengeURL("testurl"),
+				gen.SetChallengeState(cmacme.Pending),
+				gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+			),
+			dnsSolver: &fakeSolver{
+				fakePresent: func(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
+					return fmt.Errorf("unexpected call to Present")
+				},
+			},
+			builder: &testpkg.Builder{
+				Clock: fakeclock.NewFakeClock(fixedClockStart),
+				CertManagerObjects: []runtime.Object{gen.ChallengeFrom(baseChallenge,
+					gen.SetChallengeProcessing(true),
+					gen.SetChallengeURL("testurl"),
+					gen.SetChallengeState(cmacme.Pending),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+				), testIssuerDNS01Maintenance(fixedClockStart.Add(-time.Hour), fixedClockStart.Add(time.Hour))},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.ChallengeFrom(baseChallenge,
+							gen.SetChallengeProcessing(false),
+							gen.SetChallengeURL("testurl"),
+							gen.SetChallengeState(cmacme.Pending),
+							gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+							gen.SetChallengeReason("Presenting challenge deferred by issuer maintenance window until 2021-10-01T13:00:00Z: provider upgrade"),
+						))),
+				},
+				ExpectedEvents: []string{
+					"Normal MaintenanceWindow Presenting challenge deferred by issuer maintenance window until 2021-10-01T13:00:00Z: provider upgrade",
+				},
+			},
+		},
+		"present a DNS01 challenge once the issuer maintenance window has ended": {
+			challenge: gen.ChallengeFrom(baseChallenge,
+				gen.SetChallengeProcessing(true),
+				gen.SetChallengeURL("testurl"),
+				gen.SetChallengeState(cmacme.Pending),
+				gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+				gen.SetChallengeReason("Presenting challenge deferred by issuer maintenance window until 2021-10-01T12:00:00Z: provider upgrade"),
+			),
+			dnsSolver: &fakeSolver{
+				fakePresent: func(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
+					return nil
+				},
+				fakeCheck: func(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
+					return fmt.Errorf("some error")
+				},
+			},
+			builder: &testpkg.Builder{
+				Clock: fakeclock.NewFakeClock(fixedClockStart),
+				CertManagerObjects: []runtime.Object{gen.ChallengeFrom(baseChallenge,
+					gen.SetChallengeProcessing(true),
+					gen.SetChallengeURL("testurl"),
+					gen.SetChallengeState(cmacme.Pending),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+					gen.SetChallengeReason("Presenting challenge deferred by issuer maintenance window until 2021-10-01T12:00:00Z: provider upgrade"),
+				), testIssuerDNS01Maintenance(fixedClockStart.Add(-time.Hour), fixedClockStart)},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.ChallengeFrom(baseChallenge,
+							gen.SetChallengeProcessing(true),
+							gen.SetChallengeURL("testurl"),
+							gen.SetChallengeState(cmacme.Pending),
+							gen.SetChallengePresented(true),
+							gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+							gen.SetChallengeReason("Waiting for DNS-01 challenge propagation: some error"),
+						))),
+				},
+				ExpectedEvents: []string{
+					"Normal Presented Presented challenge using DNS-01 challenge mechanism",
+				},
+			},
+		},
 		"if GetAuthorization doesn't return challenge, error": {
 			challenge: gen.ChallengeFrom(baseChallenge,
 				gen.SetChallengeProcessing(true),
@@ -480,6 +584,7 @@ func runTest(t *testing.T, test testT) {
 	c.helper = issuer.NewHelper(
 		test.builder.SharedInformerFactory.Certmanager().V1().Issuers().Lister(),
 		test.builder.SharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister(),
+		test.builder.SharedInformerFactory.Policy().V1alpha1().IssuerGrants().Lister(),
 	)
 	c.accountRegistry = &accountstest.FakeRegistry{
 		GetClientFunc: func(_ string) (acmecl.Interface, error) {
@@ -35,8 +35,10 @@ import (
 	cmacmelisters "github.com/jetstack/cert-manager/pkg/client/listers/acme/v1"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 	"github.com/jetstack/cert-manager/pkg/scheduler"
 )
 
@@ -63,6 +65,11 @@ type controller struct {
 	// clientset used to update cert-manager API resources
 	cmClient cmclient.Interface
 
+	// statusPatcher and fieldManager are used to apply the status of Orders
+	// when the ServerSideApply feature is enabled
+	statusPatcher *statuspatch.Patcher
+	fieldManager  string
+
 	// maintain a reference to the workqueue for this controller
 	// so the handleOwnedResource method can enqueue resources
 	queue workqueue.RateLimitingInterface
@@ -72,6 +79,10 @@ type controller struct {
 
 	// logger to be used by this controller
 	log logr.Logger
+
+	// metrics records the lifecycle of Orders and errors returned by ACME
+	// servers
+	metrics *metrics.Metrics
 }
 
 // NewController constructs an orders controller using the provided options.
@@ -83,12 +94,18 @@ func NewController(
 	accountRegistry accounts.Getter,
 	recorder record.EventRecorder,
 	clock clock.Clock,
+	metrics *metrics.Metrics,
+	statusPatcher *statuspatch.Patcher,
 	isNamespaced bool,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 
 	// Create a queue used to queue up Orders to be processed.
 	queue := workqueue.NewNamedRateLimitingQueue(
-		workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30),
+		workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+			BaseDelay: time.Second * 5,
+			MaxDelay:  time.Minute * 30,
+		}),
 		ControllerName,
 	)
 
@@ -98,6 +115,7 @@ func NewController(
 	// Obtain references to all the informers used by this controller.
 	orderInformer := cmInformerFactory.Acme().V1().Orders()
 	issuerInformer := cmInformerFactory.Certmanager().V1().Issuers()
+	issuerGrantInformer := cmInformerFactory.Policy().V1alpha1().IssuerGrants()
 	challengeInformer := cmInformerFactory.Acme().V1().Challenges()
 	secretInformer := kubeInformerFactory.Core().V1().Secrets()
 
@@ -106,6 +124,7 @@ func NewController(
 	mustSync := []cache.InformerSynced{
 		orderInformer.Informer().HasSynced,
 		issuerInformer.Informer().HasSynced,
+		issuerGrantInformer.Informer().HasSynced,
 		challengeInformer.Informer().HasSynced,
 		secretInformer.Informer().HasSynced,
 	}
@@ -149,10 +168,13 @@ func NewController(
 		challengeLister:     challengeLister,
 		secretLister:        secretLister,
 		clusterIssuerLister: clusterIssuerLister,
-		helper:              issuer.NewHelper(issuerLister, clusterIssuerLister),
+		helper:              issuer.NewHelper(issuerLister, clusterIssuerLister, issuerGrantInformer.Lister()),
 		recorder:            recorder,
 		cmClient:            cmClient,
+		statusPatcher:       statusPatcher,
+		fieldManager:        controllerpkg.FieldManager(ControllerName),
 		accountRegistry:     accountRegistry,
+		metrics:             metrics,
 	}, queue, mustSync
 
 }
@@ -216,10 +238,15 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.ACMEOptions.AccountRegistry,
 		ctx.Recorder,
 		ctx.Clock,
+		ctx.Metrics,
+		ctx.StatusPatcher,
 		isNamespaced,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Acme().V1().Orders().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
 	return queue, mustSync, nil
 }
 
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmeorders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	acmeapi "golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+)
+
+const (
+	reasonRateLimited = "RateLimited"
+)
+
+// ACME problem types that are retried using a policy specific to the class
+// of error.
+// https://datatracker.ietf.org/doc/html/rfc8555#section-6.7
+const (
+	problemTypeBadCSR                = "urn:ietf:params:acme:error:badCSR"
+	problemTypeBadNonce              = "urn:ietf:params:acme:error:badNonce"
+	problemTypeCAA                   = "urn:ietf:params:acme:error:caa"
+	problemTypeRateLimited           = "urn:ietf:params:acme:error:rateLimited"
+	problemTypeRejectedIdentifier    = "urn:ietf:params:acme:error:rejectedIdentifier"
+	problemTypeServerInternal        = "urn:ietf:params:acme:error:serverInternal"
+	problemTypeUnauthorized          = "urn:ietf:params:acme:error:unauthorized"
+	problemTypeUnsupportedIdentifier = "urn:ietf:params:acme:error:unsupportedIdentifier"
+)
+
+var (
+	// RateLimitedRequeuePeriod is the period after which an Order is re-queued
+	// when the ACME server rate limits a request without specifying when it
+	// may be retried.
+	// It can be overriden in tests.
+	RateLimitedRequeuePeriod time.Duration = time.Hour
+)
+
+// retryPolicy describes how an Order is retried after a request to the ACME
+// server has failed.
+type retryPolicy int
+
+const (
+	// retryWithBackoff re-queues the Order using the rate limiter of the
+	// controller's workqueue.
+	retryWithBackoff retryPolicy = iota
+	// retryAfterDelay re-queues the Order after the delay returned alongside
+	// the policy.
+	retryAfterDelay
+	// retryNever marks the Order as errored. It will not be retried.
+	retryNever
+)
+
+// retryPolicyForError classifies the given error returned by the ACME server
+// and returns the policy that should be used to retry the Order. If the policy
+// is retryAfterDelay, the delay before retrying is also returned.
+// Only the problem types that cannot succeed without the Order or the Issuer
+// being changed are not retried. All other errors, including 4xx errors with
+// an unknown problem type and badNonce errors (which the ACME client has
+// already retried), are retried with backoff so that the Order is never
+// re-queued in a hot loop.
+func retryPolicyForError(err error, now time.Time) (retryPolicy, time.Duration) {
+	var acmeErr *acmeapi.Error
+	if !errors.As(err, &acmeErr) {
+		return retryWithBackoff, 0
+	}
+
+	switch acmeErr.ProblemType {
+	case problemTypeBadNonce, problemTypeServerInternal:
+		return retryWithBackoff, 0
+	case problemTypeRateLimited:
+		return retryAfterDelay, retryAfter(acmeErr.Header, now)
+	case problemTypeUnauthorized, problemTypeRejectedIdentifier, problemTypeUnsupportedIdentifier, problemTypeBadCSR, problemTypeCAA:
+		return retryNever, 0
+	}
+
+	// fall back to the status code for all other problem types
+	if acmeErr.StatusCode == http.StatusTooManyRequests {
+		return retryAfterDelay, retryAfter(acmeErr.Header, now)
+	}
+
+	return retryWithBackoff, 0
+}
+
+// retryAfter returns the delay requested by the Retry-After header, which may
+// either be a number of seconds or an HTTP date. RateLimitedRequeuePeriod is
+// returned if the header is not set or cannot be parsed.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return RateLimitedRequeuePeriod
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return RateLimitedRequeuePeriod
+}
+
+// handleACMEError applies the retry policy for the class of the given error
+// to the Order, and returns the error that should be returned from Sync.
+// If the error cannot be retried, the Order is marked as errored using reason
+// to describe the request that failed.
+func (c *controller) handleACMEError(ctx context.Context, o *cmacme.Order, err error, reason string) error {
+	log := logf.FromContext(ctx)
+
+	c.recordACMEError(o, err)
+
+	policy, delay := retryPolicyForError(err, c.clock.Now())
+	switch policy {
+	case retryNever:
+		var acmeErr *acmeapi.Error
+		errors.As(err, &acmeErr)
+		log.Error(err, "request to ACME server failed with a non-retryable error, marking Order as failed")
+		c.setOrderState(&o.Status, string(cmacme.Errored))
+		o.Status.Reason = fmt.Sprintf("%s: %v", reason, acmeErr)
+		return nil
+
+	case retryAfterDelay:
+		key, keyErr := keyFunc(o)
+		if keyErr != nil {
+			log.Error(keyErr, "failed to construct key for Order")
+			return err
+		}
+		c.recorder.Eventf(o, corev1.EventTypeWarning, reasonRateLimited, "Request to ACME server was rate limited, retrying in %s", delay)
+		log.V(logf.DebugLevel).Info("request to ACME server failed, scheduling Order to be retried", "error", err.Error(), "delay", delay)
+		c.scheduledWorkQueue.Add(key, delay)
+		return nil
+	}
+
+	return err
+}
+
+// recordACMEError records an error returned by the ACME server that requests
+// for the Order are being made to.
+func (c *controller) recordACMEError(o *cmacme.Order, err error) {
+	genericIssuer, getErr := c.helper.GetGenericIssuer(o.Spec.IssuerRef, o.Namespace)
+	if getErr != nil {
+		return
+	}
+	preflight, _ := c.needsPreflight(genericIssuer, o)
+	c.metrics.IncrementACMEErrorCount(metrics.ACMEIssuerFor(genericIssuer, preflight), err)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmeorders
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	acmeapi "golang.org/x/crypto/acme"
+)
+
+func TestRetryPolicyForError(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	acmeError := func(status int, problemType string, header http.Header) error {
+		return &acmeapi.Error{StatusCode: status, ProblemType: problemType, Header: header}
+	}
+
+	tests := map[string]struct {
+		err    error
+		policy retryPolicy
+		delay  time.Duration
+	}{
+		"non-ACME errors are retried with backoff": {
+			err:    errors.New("connection refused"),
+			policy: retryWithBackoff,
+		},
+		"badNonce errors are retried with backoff": {
+			err:    acmeError(http.StatusBadRequest, problemTypeBadNonce, nil),
+			policy: retryWithBackoff,
+		},
+		"rateLimited errors are retried after the Retry-After seconds": {
+			err:    acmeError(http.StatusTooManyRequests, problemTypeRateLimited, http.Header{"Retry-After": []string{"30"}}),
+			policy: retryAfterDelay,
+			delay:  time.Second * 30,
+		},
+		"rateLimited errors are retried after the Retry-After date": {
+			err:    acmeError(http.StatusTooManyRequests, problemTypeRateLimited, http.Header{"Retry-After": []string{now.Add(time.Minute * 10).Format(http.TimeFormat)}}),
+			policy: retryAfterDelay,
+			delay:  time.Minute * 10,
+		},
+		"rateLimited errors with a Retry-After date in the past are retried immediately": {
+			err:    acmeError(http.StatusTooManyRequests, problemTypeRateLimited, http.Header{"Retry-After": []string{now.Add(-time.Minute).Format(http.TimeFormat)}}),
+			policy: retryAfterDelay,
+		},
+		"rateLimited errors without Retry-After are retried after the default period": {
+			err:    acmeError(http.StatusTooManyRequests, problemTypeRateLimited, nil),
+			policy: retryAfterDelay,
+			delay:  RateLimitedRequeuePeriod,
+		},
+		"rateLimited errors with an invalid Retry-After are retried after the default period": {
+			err:    acmeError(http.StatusTooManyRequests, problemTypeRateLimited, http.Header{"Retry-After": []string{"soon"}}),
+			policy: retryAfterDelay,
+			delay:  RateLimitedRequeuePeriod,
+		},
+		"429 errors without a problem type are retried after a delay": {
+			err:    acmeError(http.StatusTooManyRequests, "", nil),
+			policy: retryAfterDelay,
+			delay:  RateLimitedRequeuePeriod,
+		},
+		"serverInternal errors are retried with backoff": {
+			err:    acmeError(http.StatusInternalServerError, problemTypeServerInternal, nil),
+			policy: retryWithBackoff,
+		},
+		"unauthorized errors are not retried": {
+			err:    acmeError(http.StatusForbidden, problemTypeUnauthorized, nil),
+			policy: retryNever,
+		},
+		"rejectedIdentifier errors are not retried": {
+			err:    acmeError(http.StatusBadRequest, problemTypeRejectedIdentifier, nil),
+			policy: retryNever,
+		},
+		"caa errors are not retried": {
+			err:    acmeError(http.StatusForbidden, problemTypeCAA, nil),
+			policy: retryNever,
+		},
+		"other 4xx errors are retried with backoff": {
+			err:    acmeError(http.StatusNotFound, "urn:ietf:params:acme:error:malformed", nil),
+			policy: retryWithBackoff,
+		},
+		"4xx errors without a problem type are retried with backoff": {
+			err:    acmeError(http.StatusBadRequest, "", nil),
+			policy: retryWithBackoff,
+		},
+		"other 5xx errors are retried with backoff": {
+			err:    acmeError(http.StatusBadGateway, "", nil),
+			policy: retryWithBackoff,
+		},
+		"wrapped ACME errors are classified": {
+			err:    fmt.Errorf("error creating new order: %w", acmeError(http.StatusForbidden, problemTypeUnauthorized, nil)),
+			policy: retryNever,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, delay := retryPolicyForError(test.err, now)
+			if policy != test.policy {
+				t.Errorf("unexpected policy, exp=%v got=%v", test.policy, policy)
+			}
+			if delay != test.delay {
+				t.Errorf("unexpected delay, exp=%s got=%s", test.delay, delay)
+			}
+		})
+	}
+}
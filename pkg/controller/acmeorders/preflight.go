@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmeorders
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/jetstack/cert-manager/pkg/acme"
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	"github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+)
+
+const (
+	reasonPreflight = "Preflight"
+)
+
+// needsPreflight returns true if the Order must be completed against the
+// preflight server of the issuer before it is placed against the issuer's
+// server. Only Orders containing identifiers that no earlier valid Order for
+// the same issuer in the namespace contained are validated, as the solvers
+// for the other identifiers have already been shown to work. This applies
+// equally to Orders for Certificates, CertificateSigningRequests and
+// standalone CertificateRequests.
+func (c *controller) needsPreflight(issuer cmapi.GenericIssuer, o *cmacme.Order) (bool, error) {
+	if issuer.GetSpec().ACME == nil || issuer.GetSpec().ACME.Preflight == nil {
+		return false, nil
+	}
+	if o.Status.URL != "" || preflightSucceeded(o) {
+		return false, nil
+	}
+	// Once placed, the preflight Order is completed even if another Order
+	// has since validated its identifiers.
+	if o.Status.Preflight != nil {
+		return true, nil
+	}
+	return c.hasNewIdentifiers(o)
+}
+
+// hasNewIdentifiers returns true if the Order contains identifiers that are
+// not contained by any valid Order for the same issuer in its namespace.
+func (c *controller) hasNewIdentifiers(o *cmacme.Order) (bool, error) {
+	orders, err := c.orderLister.Orders(o.Namespace).List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+
+	identifiers := orderIdentifierSet(o)
+	for _, other := range orders {
+		if other.Name == o.Name || other.Status.State != cmacme.Valid || other.Spec.IssuerRef != o.Spec.IssuerRef {
+			continue
+		}
+		identifiers.Delete(orderIdentifierSet(other).UnsortedList()...)
+	}
+	return identifiers.Len() > 0, nil
+}
+
+// orderIdentifierSet returns the identifiers of the Order in the form
+// `<type>:<value>`.
+func orderIdentifierSet(o *cmacme.Order) sets.String {
+	identifiers := sets.NewString()
+	for _, id := range orderIdentifiers(o) {
+		identifiers.Insert(id.Type + ":" + id.Value)
+	}
+	return identifiers
+}
+
+func preflightSucceeded(o *cmacme.Order) bool {
+	if o.Status.Preflight == nil {
+		return false
+	}
+	return o.Status.Preflight.State == cmacme.Ready || o.Status.Preflight.State == cmacme.Valid
+}
+
+// syncPreflight places the Order against the preflight server of the issuer
+// and completes its authorizations. If the preflight Order cannot be
+// completed the Order is marked as errored, so that the issuer's server is
+// only used once the solvers have been shown to work.
+func (c *controller) syncPreflight(ctx context.Context, issuer cmapi.GenericIssuer, o *cmacme.Order) error {
+	log := logf.FromContext(ctx, "preflight")
+
+	cl, err := c.accountRegistry.GetClient(accounts.PreflightUID(string(issuer.GetUID())))
+	if err != nil {
+		return err
+	}
+
+	if o.Status.Preflight == nil || o.Status.Preflight.URL == "" {
+		log.V(logf.DebugLevel).Info("Creating new ACME order on the preflight server")
+		return c.createPreflightOrder(ctx, cl, issuer, o)
+	}
+
+	requiredChallenges, err := buildRequiredPreflightChallenges(ctx, cl, issuer, o)
+	if err != nil {
+		log.Error(err, "Failed to determine the list of Challenge resources needed for the preflight Order")
+		c.recorder.Eventf(o, corev1.EventTypeWarning, reasonSolver, "Failed to determine a valid solver configuration for the set of domains on the preflight Order: %v", err)
+		return nil
+	}
+
+	needToCreateChallenges, err := c.anyRequiredChallengesDoNotExist(requiredChallenges)
+	if err != nil {
+		return err
+	}
+	if needToCreateChallenges {
+		log.V(logf.DebugLevel).Info("Creating Challenge resources to complete the preflight Order")
+		return c.createRequiredChallenges(ctx, o, requiredChallenges)
+	}
+
+	challenges, err := c.listOwnedPreflightChallenges(o)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range challenges {
+		if acme.IsFailureState(ch.Status.State) {
+			c.failPreflight(o, fmt.Sprintf("challenge for %q failed: %s", ch.Spec.DNSName, ch.Status.Reason))
+			return nil
+		}
+	}
+	if !allChallengesFinal(challenges) {
+		log.V(logf.DebugLevel).Info("Waiting for preflight Challenges to reach a final state")
+		return nil
+	}
+
+	acmeOrder, err := cl.GetOrder(ctx, o.Status.Preflight.URL)
+	if err != nil {
+		return c.handleACMEError(ctx, o, err, "Failed to retrieve preflight Order")
+	}
+	o.Status.Preflight.State = cmacme.State(acmeOrder.Status)
+
+	switch o.Status.Preflight.State {
+	case cmacme.Ready, cmacme.Valid:
+		c.recorder.Eventf(o, corev1.EventTypeNormal, reasonPreflight, "Order was completed on the preflight server %q, placing the Order with the ACME server", issuer.GetSpec().ACME.Preflight.Server)
+		return c.deleteChallenges(ctx, challenges)
+
+	case cmacme.Pending:
+		// all challenges are final, but the preflight server has not yet
+		// updated the state of the order
+		key, err := keyFunc(o)
+		if err != nil {
+			log.Error(err, "failed to construct key for pending Order")
+			return nil
+		}
+		c.scheduledWorkQueue.Add(key, RequeuePeriod)
+		return nil
+	}
+
+	c.failPreflight(o, fmt.Sprintf("order is in state %q", acmeOrder.Status))
+	return nil
+}
+
+func (c *controller) createPreflightOrder(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order) error {
+	acmeOrder, err := cl.AuthorizeOrder(ctx, orderIdentifiers(o))
+	if err != nil {
+		return c.handleACMEError(ctx, o, fmt.Errorf("error creating new preflight order: %w", err), "Failed to create preflight Order")
+	}
+	c.metrics.IncrementACMEOrderCreatedCount(metrics.ACMEIssuerFor(issuer, true))
+
+	preflight := &cmacme.OrderPreflightStatus{
+		URL:   acmeOrder.URI,
+		State: cmacme.State(acmeOrder.Status),
+	}
+	for _, url := range acmeOrder.AuthzURLs {
+		acmeAuthz, err := cl.GetAuthorization(ctx, url)
+		if err != nil {
+			return c.handleACMEError(ctx, o, err, "Failed to fetch preflight authorization")
+		}
+		authz := cmacme.ACMEAuthorization{URL: url}
+		populateAuthorization(&authz, acmeAuthz)
+		preflight.Authorizations = append(preflight.Authorizations, authz)
+	}
+
+	o.Status.Preflight = preflight
+	c.recorder.Eventf(o, corev1.EventTypeNormal, reasonPreflight, "Created Order on the preflight server %q", issuer.GetSpec().ACME.Preflight.Server)
+	return nil
+}
+
+// buildRequiredPreflightChallenges returns the Challenges that must be
+// completed for the authorizations of the preflight Order.
+func buildRequiredPreflightChallenges(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order) ([]cmacme.Challenge, error) {
+	preflightOrder := o.DeepCopy()
+	preflightOrder.Status.Authorizations = o.Status.Preflight.Authorizations
+
+	chs, err := buildRequiredChallenges(ctx, cl, issuer, preflightOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range chs {
+		chs[i].Spec.Preflight = true
+		chs[i].Name, err = util.ComputeName(o.Name, chs[i].Spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return chs, nil
+}
+
+func (c *controller) listOwnedPreflightChallenges(o *cmacme.Order) ([]*cmacme.Challenge, error) {
+	owned, err := c.listAllOwnedChallenges(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var chs []*cmacme.Challenge
+	for _, ch := range owned {
+		if ch.Spec.Preflight {
+			chs = append(chs, ch)
+		}
+	}
+	return chs, nil
+}
+
+// failPreflight marks the preflight Order and the Order as failed.
+func (c *controller) failPreflight(o *cmacme.Order, reason string) {
+	o.Status.Preflight.State = cmacme.Invalid
+	o.Status.Preflight.Reason = reason
+	c.setOrderState(&o.Status, string(cmacme.Errored))
+	o.Status.Reason = "Preflight Order failed: " + reason
+	c.recorder.Event(o, corev1.EventTypeWarning, reasonPreflight, o.Status.Reason)
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmeorders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	acmeapi "golang.org/x/crypto/acme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestSyncPreflight(t *testing.T) {
+	nowTime := time.Now()
+	nowMetaTime := metav1.NewTime(nowTime)
+	fixedClock := fakeclock.NewFakeClock(nowTime)
+
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Server:    "https://acme.example.com/directory",
+		Preflight: &cmacme.ACMEIssuerPreflight{Server: "https://staging.example.com/directory"},
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+				},
+			},
+		},
+	}))
+
+	testOrder := gen.Order("testorder",
+		gen.SetOrderCommonName("test.com"),
+		gen.SetOrderIssuer(cmmeta.ObjectReference{Name: testIssuer.Name}),
+	)
+	// testOrderPrevious is an earlier valid Order for the same identifiers,
+	// such as the Order of the previous revision of a Certificate.
+	testOrderPrevious := gen.OrderFrom(testOrder,
+		gen.SetOrderName("testorder-previous"),
+		gen.SetOrderState(cmacme.Valid),
+	)
+	testOrderNewDNSName := gen.OrderFrom(testOrder, gen.SetOrderDNSNames("test.com", "new.test.com"))
+	testOrderOtherIssuer := gen.OrderFrom(testOrderPrevious, gen.SetOrderIssuer(cmmeta.ObjectReference{Name: "otherissuer"}))
+
+	wildcard := false
+	pendingPreflight := &cmacme.OrderPreflightStatus{
+		URL:   "https://staging.example.com/order/1",
+		State: cmacme.Pending,
+		Authorizations: []cmacme.ACMEAuthorization{
+			{
+				URL:          "https://staging.example.com/authz/1",
+				Identifier:   "test.com",
+				Wildcard:     &wildcard,
+				InitialState: cmacme.Pending,
+				Challenges: []cmacme.ACMEChallenge{
+					{
+						URL:   "https://staging.example.com/chal/1",
+						Token: "token",
+						Type:  "http-01",
+					},
+				},
+			},
+		},
+	}
+	testOrderPreflightPending := gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{Preflight: pendingPreflight}))
+
+	readyPreflight := pendingPreflight.DeepCopy()
+	readyPreflight.State = cmacme.Ready
+	testOrderPreflightReady := gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{Preflight: readyPreflight}))
+
+	failedPreflight := pendingPreflight.DeepCopy()
+	failedPreflight.State = cmacme.Invalid
+	failedPreflight.Reason = `challenge for "test.com" failed: unauthorized`
+	testOrderPreflightFailed := gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+		Preflight:   failedPreflight,
+		State:       cmacme.Errored,
+		Reason:      `Preflight Order failed: challenge for "test.com" failed: unauthorized`,
+		FailureTime: &nowMetaTime,
+	}))
+
+	fakeHTTP01ACMECl := &acmecl.FakeACME{
+		FakeHTTP01ChallengeResponse: func(s string) (string, error) {
+			return "key", nil
+		},
+	}
+	preflightChallenges, err := buildRequiredPreflightChallenges(context.TODO(), fakeHTTP01ACMECl, testIssuer, testOrderPreflightPending)
+	if err != nil {
+		t.Fatalf("error building Challenge resource test fixture: %v", err)
+	}
+	testPreflightChallenge := &preflightChallenges[0]
+	testPreflightChallengeValid := testPreflightChallenge.DeepCopy()
+	testPreflightChallengeValid.Status.State = cmacme.Valid
+	testPreflightChallengeInvalid := testPreflightChallenge.DeepCopy()
+	testPreflightChallengeInvalid.Status.State = cmacme.Invalid
+	testPreflightChallengeInvalid.Status.Reason = "unauthorized"
+
+	testACMEOrderPending := &acmeapi.Order{
+		URI:         "https://acme.example.com/order/1",
+		FinalizeURL: "https://acme.example.com/order/1/finalize",
+		AuthzURLs:   []string{"https://acme.example.com/authz/1"},
+		Status:      acmeapi.StatusPending,
+	}
+	testOrderPending := gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+		URL:            testACMEOrderPending.URI,
+		FinalizeURL:    testACMEOrderPending.FinalizeURL,
+		State:          cmacme.Pending,
+		Authorizations: []cmacme.ACMEAuthorization{{URL: "https://acme.example.com/authz/1"}},
+	}))
+
+	preflightClient := &acmecl.FakeACME{
+		FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+			return &acmeapi.Order{
+				URI:       pendingPreflight.URL,
+				AuthzURLs: []string{"https://staging.example.com/authz/1"},
+				Status:    acmeapi.StatusPending,
+			}, nil
+		},
+		FakeGetAuthorization: func(ctx context.Context, url string) (*acmeapi.Authorization, error) {
+			return &acmeapi.Authorization{
+				URI:        url,
+				Status:     acmeapi.StatusPending,
+				Identifier: acmeapi.AuthzID{Type: "dns", Value: "test.com"},
+				Challenges: []*acmeapi.Challenge{
+					{URI: "https://staging.example.com/chal/1", Type: "http-01", Token: "token"},
+				},
+			}, nil
+		},
+		FakeGetOrder: func(ctx context.Context, url string) (*acmeapi.Order, error) {
+			return &acmeapi.Order{URI: url, Status: acmeapi.StatusReady}, nil
+		},
+		FakeHTTP01ChallengeResponse: func(s string) (string, error) {
+			return "key", nil
+		},
+	}
+	acmeClient := &acmecl.FakeACME{
+		FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+			return testACMEOrderPending, nil
+		},
+	}
+
+	tests := map[string]testT{
+		"create an order on the preflight server for the first order for its identifiers": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrder},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						testOrderPreflightPending)),
+				},
+				ExpectedEvents: []string{
+					`Normal Preflight Created Order on the preflight server "https://staging.example.com/directory"`,
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"create challenge resources for the preflight order": {
+			order: testOrderPreflightPending,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPreflightPending},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(cmacme.SchemeGroupVersion.WithResource("challenges"), testPreflightChallenge.Namespace, testPreflightChallenge)),
+				},
+				ExpectedEvents: []string{
+					`Normal Created Created Challenge resource "` + testPreflightChallenge.Name + `" for domain "test.com"`,
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"do nothing while the preflight challenges are not final": {
+			order: testOrderPreflightPending,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPreflightPending, testPreflightChallenge},
+				ExpectedEvents:     []string{},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"fail the order if a preflight challenge has failed": {
+			order: testOrderPreflightPending,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPreflightPending, testPreflightChallengeInvalid},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						testOrderPreflightFailed)),
+				},
+				ExpectedEvents: []string{
+					`Warning Preflight Preflight Order failed: challenge for "test.com" failed: unauthorized`,
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"record the preflight order as ready and delete its challenges once they are valid": {
+			order: testOrderPreflightPending,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPreflightPending, testPreflightChallengeValid},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewDeleteAction(cmacme.SchemeGroupVersion.WithResource("challenges"), testPreflightChallenge.Namespace, testPreflightChallenge.Name)),
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						testOrderPreflightReady)),
+				},
+				ExpectedEvents: []string{
+					`Normal Preflight Order was completed on the preflight server "https://staging.example.com/directory", placing the Order with the ACME server`,
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"create the order with the acme server once the preflight order is ready": {
+			order: testOrderPreflightReady,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPreflightReady},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						gen.OrderFrom(testOrderPending, gen.SetOrderStatus(cmacme.OrderStatus{
+							URL:            testOrderPending.Status.URL,
+							FinalizeURL:    testOrderPending.Status.FinalizeURL,
+							State:          cmacme.Pending,
+							Authorizations: testOrderPending.Status.Authorizations,
+							Preflight:      readyPreflight,
+						})))),
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"create the order with the acme server without preflight if its identifiers were validated by an earlier order": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPrevious, testOrder},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						gen.OrderFrom(testOrder, gen.SetOrderStatus(testOrderPending.Status)))),
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"create an order on the preflight server if it contains a dns name that no earlier order contained": {
+			order: testOrderNewDNSName,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderPrevious, testOrderNewDNSName},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						gen.OrderFrom(testOrderNewDNSName, gen.SetOrderStatus(cmacme.OrderStatus{Preflight: pendingPreflight})))),
+				},
+				ExpectedEvents: []string{
+					`Normal Preflight Created Order on the preflight server "https://staging.example.com/directory"`,
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+		"create an order on the preflight server if its identifiers were only validated for another issuer": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuer, testOrderOtherIssuer, testOrder},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						testOrderPreflightPending)),
+				},
+				ExpectedEvents: []string{
+					`Normal Preflight Created Order on the preflight server "https://staging.example.com/directory"`,
+				},
+			},
+			acmeClient:          acmeClient,
+			preflightACMEClient: preflightClient,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fixedClock.SetTime(nowTime)
+			test.builder.Clock = fixedClock
+			runTest(t, test)
+		})
+	}
+}
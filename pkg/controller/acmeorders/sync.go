@@ -30,6 +30,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
@@ -38,7 +39,11 @@ import (
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/tracing"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
 
 const (
@@ -59,19 +64,23 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	oldOrder := o
 	o = o.DeepCopy()
 
+	ctx, span := tracing.Start(ctx, "SyncOrder", o)
+	defer func() { tracing.End(span, err) }()
+
 	defer func() {
 		if apiequality.Semantic.DeepEqual(oldOrder.Status, o.Status) {
 			dbg.Info("skipping updating resource as new status == existing status")
 			return
 		}
 		log.V(logf.DebugLevel).Info("updating Order resource status")
-		_, updateErr := c.cmClient.AcmeV1().Orders(o.Namespace).UpdateStatus(ctx, o, metav1.UpdateOptions{})
+		updateErr := c.updateStatus(ctx, oldOrder, o)
 		if updateErr != nil {
 			log.Error(err, "failed to update status")
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 			return
 		}
 		dbg.Info("updated Order resource status successfully")
+		c.observeOrderState(oldOrder, o)
 	}()
 
 	genericIssuer, err := c.helper.GetGenericIssuer(o.Spec.IssuerRef, o.Namespace)
@@ -83,26 +92,29 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 		return err
 	}
 
+	preflight, err := c.needsPreflight(genericIssuer, o)
+	if err != nil {
+		return err
+	}
+
 	switch {
 	case acme.IsFailureState(o.Status.State):
 		log.V(logf.DebugLevel).Info("Doing nothing as Order is in a failed state")
 		// if the Order is failed there's nothing left for us to do, return nil
 		return nil
+	case preflight:
+		log.V(logf.DebugLevel).Info("Completing Order on the preflight ACME server before creating it")
+		return c.syncPreflight(ctx, genericIssuer, o)
 	case o.Status.URL == "":
 		log.V(logf.DebugLevel).Info("Creating new ACME order as status.url is not set")
-		return c.createOrder(ctx, cl, o)
+		return c.createOrder(ctx, cl, genericIssuer, o)
 	case o.Status.FinalizeURL == "":
 		log.V(logf.DebugLevel).Info("Updating Order status as status.finalizeURL is not set")
 		_, err := c.updateOrderStatus(ctx, cl, o)
-		if acmeErr, ok := err.(*acmeapi.Error); ok {
-			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-				log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
-				o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
-				return nil
-			}
+		if err != nil {
+			return c.handleACMEError(ctx, o, err, "Failed to retrieve Order resource")
 		}
-		return err
+		return nil
 	case anyAuthorizationsMissingMetadata(o):
 		log.V(logf.DebugLevel).Info("Fetching Authorizations from ACME server as status.authorizations contains unpopulated authorizations")
 		return c.fetchMetadataForAuthorizations(ctx, o, cl)
@@ -153,23 +165,19 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	}
 
 	acmeOrder, err := getACMEOrder(ctx, cl, o)
-	// Order probably has been deleted, we cannot recover here.
-	if acmeErr, ok := err.(*acmeapi.Error); ok {
-		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-			log.Error(err, "failed to retrieve the ACME order (4xx error) marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
-			o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
-			return nil
-		}
-	}
 	if err != nil {
-		return err
+		// a 4xx error probably means the Order has been deleted, in which
+		// case we cannot recover here.
+		return c.handleACMEError(ctx, o, err, "Failed to retrieve Order resource")
 	}
 
 	switch {
 	case o.Status.State == cmacme.Ready:
 		log.V(logf.DebugLevel).Info("Finalizing Order as order state is 'Ready'")
-		return c.finalizeOrder(ctx, cl, o, genericIssuer)
+		ctx, span := tracing.Start(ctx, "FinalizeOrder", o)
+		err := c.finalizeOrder(ctx, cl, o, genericIssuer)
+		tracing.End(span, err)
+		return err
 
 	case anyChallengesFailed(challenges):
 		// TODO (@munnerz): instead of waiting for the ACME server to mark this
@@ -177,15 +185,10 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 		//  no way that we will attempt and continue the order anyway.
 		log.V(logf.DebugLevel).Info("Update Order status as at least one Challenge has failed")
 		_, err := c.updateOrderStatus(ctx, cl, o)
-		if acmeErr, ok := err.(*acmeapi.Error); ok {
-			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-				log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
-				o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
-				return nil
-			}
+		if err != nil {
+			return c.handleACMEError(ctx, o, err, "Failed to retrieve Order resource")
 		}
-		return err
+		return nil
 
 	// anyChallengesFailed(challenges) == false is already implied by the above
 	// case, but explicitly check it in the following cases for if anything changes in future.
@@ -217,15 +220,10 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	case !anyChallengesFailed(challenges) && allChallengesFinal(challenges):
 		log.V(logf.DebugLevel).Info("All challenges are in a final state, updating order state")
 		_, err := c.updateOrderStatus(ctx, cl, o)
-		if acmeErr, ok := err.(*acmeapi.Error); ok {
-			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-				log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
-				o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
-				return nil
-			}
+		if err != nil {
+			return c.handleACMEError(ctx, o, err, "Failed to retrieve Order resource")
 		}
-		return err
+		return nil
 	}
 
 	log.V(logf.DebugLevel).Info("No action taken")
@@ -233,7 +231,7 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	return nil
 }
 
-func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cmacme.Order) error {
+func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order) error {
 	log := logf.FromContext(ctx)
 
 	if o.Status.URL != "" {
@@ -241,17 +239,9 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	}
 	log.V(logf.DebugLevel).Info("order URL not set, submitting Order to ACME server")
 
-	dnsIdentifierSet := sets.NewString(o.Spec.DNSNames...)
-	if o.Spec.CommonName != "" {
-		dnsIdentifierSet.Insert(o.Spec.CommonName)
-	}
-	log.V(logf.DebugLevel).Info("build set of domains for Order", "domains", dnsIdentifierSet.List())
-
-	ipIdentifierSet := sets.NewString(o.Spec.IPAddresses...)
-	log.V(logf.DebugLevel).Info("build set of IPs for Order", "domains", dnsIdentifierSet.List())
+	authzIDs := orderIdentifiers(o)
+	log.V(logf.DebugLevel).Info("build set of identifiers for Order", "identifiers", authzIDs)
 
-	authzIDs := acmeapi.DomainIDs(dnsIdentifierSet.List()...)
-	authzIDs = append(authzIDs, acmeapi.IPIDs(ipIdentifierSet.List()...)...)
 	// create a new order with the acme server
 
 	var options []acmeapi.OrderOption
@@ -259,18 +249,11 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 		options = append(options, acmeapi.WithOrderNotAfter(c.clock.Now().Add(o.Spec.Duration.Duration)))
 	}
 	acmeOrder, err := cl.AuthorizeOrder(ctx, authzIDs, options...)
-	if acmeErr, ok := err.(*acmeapi.Error); ok {
-		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-			log.Error(err, "failed to create Order resource due to bad request, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
-			o.Status.Reason = fmt.Sprintf("Failed to create Order: %v", err)
-			return nil
-		}
-	}
 	if err != nil {
-		return fmt.Errorf("error creating new order: %v", err)
+		return c.handleACMEError(ctx, o, fmt.Errorf("error creating new order: %w", err), "Failed to create Order")
 	}
 	log.V(logf.DebugLevel).Info("submitted Order to ACME server")
+	c.metrics.IncrementACMEOrderCreatedCount(metrics.ACMEIssuerFor(issuer, false))
 
 	o.Status.URL = acmeOrder.URI
 	o.Status.FinalizeURL = acmeOrder.FinalizeURL
@@ -280,6 +263,19 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	return nil
 }
 
+// orderIdentifiers returns the DNS and IP identifiers that must be authorized
+// to complete the Order.
+func orderIdentifiers(o *cmacme.Order) []acmeapi.AuthzID {
+	dnsIdentifierSet := sets.NewString(o.Spec.DNSNames...)
+	if o.Spec.CommonName != "" {
+		dnsIdentifierSet.Insert(o.Spec.CommonName)
+	}
+	ipIdentifierSet := sets.NewString(o.Spec.IPAddresses...)
+
+	authzIDs := acmeapi.DomainIDs(dnsIdentifierSet.List()...)
+	return append(authzIDs, acmeapi.IPIDs(ipIdentifierSet.List()...)...)
+}
+
 func (c *controller) updateOrderStatus(ctx context.Context, cl acmecl.Interface, o *cmacme.Order) (*acmeapi.Order, error) {
 	acmeOrder, err := getACMEOrder(ctx, cl, o)
 	if err != nil {
@@ -315,6 +311,34 @@ func (c *controller) setOrderState(o *cmacme.OrderStatus, s string) {
 	}
 }
 
+// updateStatus writes the status of o to the apiserver. If the ServerSideApply
+// feature is enabled the fields changed from oldOrder are applied, otherwise
+// the status is updated.
+func (c *controller) updateStatus(ctx context.Context, oldOrder, o *cmacme.Order) error {
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		key := "orders/" + o.Namespace + "/" + o.Name
+		return c.statusPatcher.Apply(ctx, key, orderGvk, oldOrder, c.fieldManager, oldOrder.Status, o.Status, func(ctx context.Context, pt types.PatchType, patch []byte, opts metav1.PatchOptions) error {
+			_, err := c.cmClient.AcmeV1().Orders(o.Namespace).Patch(ctx, o.Name, pt, patch, opts, "status")
+			return err
+		})
+	}
+	_, err := c.cmClient.AcmeV1().Orders(o.Namespace).UpdateStatus(ctx, o, metav1.UpdateOptions{})
+	return err
+}
+
+// observeOrderState records the time taken for an Order to complete when it
+// has reached a final state.
+func (c *controller) observeOrderState(old, new *cmacme.Order) {
+	if acme.IsFinalState(old.Status.State) || !acme.IsFinalState(new.Status.State) {
+		return
+	}
+	genericIssuer, err := c.helper.GetGenericIssuer(new.Spec.IssuerRef, new.Namespace)
+	if err != nil {
+		return
+	}
+	c.metrics.ObserveACMEOrderDuration(metrics.ACMEIssuerFor(genericIssuer, false), new)
+}
+
 // constructAuthorizations will construct a slice of ACMEAuthorizations must be
 // completed for the given ACME order.
 // It does *not* perform a query against the ACME server for each authorization
@@ -338,7 +362,6 @@ func anyAuthorizationsMissingMetadata(o *cmacme.Order) bool {
 }
 
 func (c *controller) fetchMetadataForAuthorizations(ctx context.Context, o *cmacme.Order, cl acmecl.Interface) error {
-	log := logf.FromContext(ctx)
 	for i, authz := range o.Status.Authorizations {
 		// only fetch metadata for each authorization once
 		if authz.Identifier != "" {
@@ -346,32 +369,30 @@ func (c *controller) fetchMetadataForAuthorizations(ctx context.Context, o *cmac
 		}
 
 		acmeAuthz, err := cl.GetAuthorization(ctx, authz.URL)
-		if acmeErr, ok := err.(*acmeapi.Error); ok {
-			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-				log.Error(err, "failed to fetch authorization metadata from acme server")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
-				o.Status.Reason = fmt.Sprintf("Failed to fetch authorization: %v", err)
-				return nil
-			}
-		}
 		if err != nil {
-			return err
+			return c.handleACMEError(ctx, o, err, "Failed to fetch authorization")
 		}
 
-		authz.InitialState = cmacme.State(acmeAuthz.Status)
-		authz.Identifier = acmeAuthz.Identifier.Value
-		authz.Wildcard = &acmeAuthz.Wildcard
-		authz.Challenges = make([]cmacme.ACMEChallenge, len(acmeAuthz.Challenges))
-		for i, acmech := range acmeAuthz.Challenges {
-			authz.Challenges[i].URL = acmech.URI
-			authz.Challenges[i].Token = acmech.Token
-			authz.Challenges[i].Type = acmech.Type
-		}
+		populateAuthorization(&authz, acmeAuthz)
 		o.Status.Authorizations[i] = authz
 	}
 	return nil
 }
 
+// populateAuthorization sets the metadata of authz from the authorization
+// returned by the ACME server.
+func populateAuthorization(authz *cmacme.ACMEAuthorization, acmeAuthz *acmeapi.Authorization) {
+	authz.InitialState = cmacme.State(acmeAuthz.Status)
+	authz.Identifier = acmeAuthz.Identifier.Value
+	authz.Wildcard = &acmeAuthz.Wildcard
+	authz.Challenges = make([]cmacme.ACMEChallenge, len(acmeAuthz.Challenges))
+	for i, acmech := range acmeAuthz.Challenges {
+		authz.Challenges[i].URL = acmech.URI
+		authz.Challenges[i].Token = acmech.Token
+		authz.Challenges[i].Type = acmech.Type
+	}
+}
+
 func (c *controller) anyRequiredChallengesDoNotExist(requiredChallenges []cmacme.Challenge) (bool, error) {
 	for _, ch := range requiredChallenges {
 		_, err := c.challengeLister.Challenges(ch.Namespace).Get(ch.Name)
@@ -387,6 +408,7 @@ func (c *controller) anyRequiredChallengesDoNotExist(requiredChallenges []cmacme
 
 func (c *controller) createRequiredChallenges(ctx context.Context, o *cmacme.Order, requiredChallenges []cmacme.Challenge) error {
 	for _, ch := range requiredChallenges {
+		tracing.Inject(ctx, &ch)
 		_, err := c.cmClient.AcmeV1().Challenges(ch.Namespace).Create(ctx, &ch, metav1.CreateOptions{})
 		if apierrors.IsAlreadyExists(err) {
 			continue
@@ -424,17 +446,20 @@ func (c *controller) deleteLeftoverChallenges(ctx context.Context, o *cmacme.Ord
 }
 
 func (c *controller) deleteAllChallenges(ctx context.Context, o *cmacme.Order) error {
-	challenges, err := c.listOwnedChallenges(o)
+	challenges, err := c.listAllOwnedChallenges(o)
 	if err != nil {
 		return err
 	}
 
-	for _, ch := range challenges {
+	return c.deleteChallenges(ctx, challenges)
+}
+
+func (c *controller) deleteChallenges(ctx context.Context, chs []*cmacme.Challenge) error {
+	for _, ch := range chs {
 		if err := c.cmClient.AcmeV1().Challenges(ch.Namespace).Delete(ctx, ch.Name, metav1.DeleteOptions{}); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
@@ -460,7 +485,27 @@ func (c *controller) determineLeftoverChallenges(o *cmacme.Order, requiredChalle
 	return leftover, nil
 }
 
+// listOwnedChallenges returns the Challenges of the Order for its
+// authorizations with the ACME server, excluding those of its preflight
+// Order.
 func (c *controller) listOwnedChallenges(o *cmacme.Order) ([]*cmacme.Challenge, error) {
+	owned, err := c.listAllOwnedChallenges(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var chs []*cmacme.Challenge
+	for _, ch := range owned {
+		if !ch.Spec.Preflight {
+			chs = append(chs, ch)
+		}
+	}
+	return chs, nil
+}
+
+// listAllOwnedChallenges returns all Challenges controlled by the Order,
+// including those of its preflight Order.
+func (c *controller) listAllOwnedChallenges(o *cmacme.Order) ([]*cmacme.Challenge, error) {
 	chs, err := c.challengeLister.Challenges(o.Namespace).List(labels.Everything())
 	if err != nil {
 		return nil, err
@@ -496,14 +541,12 @@ func (c *controller) finalizeOrder(ctx context.Context, cl acmecl.Interface, o *
 	}
 
 	certSlice, certURL, err := cl.CreateOrderCert(ctx, o.Status.FinalizeURL, derBytes, true)
-	// if an ACME error is returned and it's a 4xx error, mark this Order as
-	// failed and do not retry it until after applying the global backoff.
-	if acmeErr, ok := err.(*acmeapi.Error); ok {
-		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-			log.Error(err, "failed to finalize Order resource due to bad request, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
-			o.Status.Reason = fmt.Sprintf("Failed to finalize Order: %v", err)
-			return nil
+	// if the ACME error returned is not retried with backoff (e.g. the CSR
+	// was rejected and the Order should be marked as failed, or the request
+	// was rate limited), handle it without updating the order status.
+	if err != nil {
+		if policy, _ := retryPolicyForError(err, c.clock.Now()); policy != retryWithBackoff {
+			return c.handleACMEError(ctx, o, err, "Failed to finalize Order")
 		}
 	}
 	// even if any other kind of error occurred, we always update the order
@@ -514,16 +557,8 @@ func (c *controller) finalizeOrder(ctx context.Context, cl acmecl.Interface, o *
 	// if it is already in the 'valid' state, as upon retry we will
 	// then retrieve the Certificate resource.
 	_, errUpdate := c.updateOrderStatus(ctx, cl, o)
-	if acmeErr, ok := err.(*acmeapi.Error); ok {
-		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-			log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
-			o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
-			return nil
-		}
-	}
 	if errUpdate != nil {
-		return fmt.Errorf("error syncing order status: %v", errUpdate)
+		return c.handleACMEError(ctx, o, fmt.Errorf("error syncing order status: %w", errUpdate), "Failed to retrieve Order resource")
 	}
 	// check for errors from FinalizeOrder
 	if err != nil {
@@ -586,16 +621,8 @@ func (c *controller) storeCertificateOnStatus(ctx context.Context, o *cmacme.Ord
 func (c *controller) fetchCertificateData(ctx context.Context, cl acmecl.Interface, o *cmacme.Order) error {
 	log := logf.FromContext(ctx)
 	acmeOrder, err := c.updateOrderStatus(ctx, cl, o)
-	if acmeErr, ok := err.(*acmeapi.Error); ok {
-		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-			log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
-			o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
-			return nil
-		}
-	}
 	if err != nil {
-		return err
+		return c.handleACMEError(ctx, o, err, "Failed to retrieve Order resource")
 	}
 	if acmeOrder == nil {
 		log.V(logf.WarnLevel).Info("Failed to fetch Order from ACME server as it no longer exists. Not retrying.")
@@ -610,16 +637,8 @@ func (c *controller) fetchCertificateData(ctx context.Context, cl acmecl.Interfa
 	}
 
 	certs, err := cl.FetchCert(ctx, acmeOrder.CertURL, true)
-	if acmeErr, ok := err.(*acmeapi.Error); ok {
-		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
-			log.Error(err, "failed to retrieve issued certificate from ACME server")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
-			o.Status.Reason = fmt.Sprintf("Failed to retrieve signed certificate: %v", err)
-			return nil
-		}
-	}
 	if err != nil {
-		return err
+		return c.handleACMEError(ctx, o, err, "Failed to retrieve signed certificate")
 	}
 
 	err = c.storeCertificateOnStatus(ctx, o, certs)
@@ -21,6 +21,8 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
 	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
@@ -182,6 +185,9 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 	testAuthorizationChallengeValid.Status.State = cmacme.Valid
 	testAuthorizationChallengeInvalid := testAuthorizationChallenge.DeepCopy()
 	testAuthorizationChallengeInvalid.Status.State = cmacme.Invalid
+	testPreflightChallengeInvalid := testAuthorizationChallengeInvalid.DeepCopy()
+	testPreflightChallengeInvalid.Name = "testpreflightchallenge"
+	testPreflightChallengeInvalid.Spec.Preflight = true
 
 	testACMEAuthorizationPending := &acmeapi.Authorization{
 		URI:    "http://authzurl",
@@ -222,6 +228,28 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 	*testACMEOrderInvalid = *testACMEOrderPending
 	testACMEOrderInvalid.Status = acmeapi.StatusInvalid
 
+	testACMEErrorRateLimited := &acmeapi.Error{
+		StatusCode:  http.StatusTooManyRequests,
+		ProblemType: "urn:ietf:params:acme:error:rateLimited",
+		Detail:      "too many new orders recently",
+		Header:      http.Header{"Retry-After": []string{"120"}},
+	}
+	testACMEErrorBadNonce := &acmeapi.Error{
+		StatusCode:  http.StatusBadRequest,
+		ProblemType: "urn:ietf:params:acme:error:badNonce",
+		Detail:      "JWS has an invalid anti-replay nonce",
+	}
+	testACMEErrorServerInternal := &acmeapi.Error{
+		StatusCode:  http.StatusInternalServerError,
+		ProblemType: "urn:ietf:params:acme:error:serverInternal",
+		Detail:      "internal error",
+	}
+	testACMEErrorUnauthorized := &acmeapi.Error{
+		StatusCode:  http.StatusForbidden,
+		ProblemType: "urn:ietf:params:acme:error:unauthorized",
+		Detail:      "account is not authorized",
+	}
+
 	tests := map[string]testT{
 		"create a new order with the acme server, set the order url on the status resource and return nil to avoid cache timing issues": {
 			order: testOrder,
@@ -467,6 +495,21 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 				},
 			},
 		},
+		"ignore leftover preflight challenges once the order has been created": {
+			order: testOrderPending,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrderPending, testAuthorizationChallenge, testPreflightChallengeInvalid},
+				ExpectedActions:    []testpkg.Action{},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(_ context.Context, url string) (*acmeapi.Order, error) {
+					return testACMEOrderPending, nil
+				},
+				FakeHTTP01ChallengeResponse: func(s string) (string, error) {
+					return "key", nil
+				},
+			},
+		},
 		"call GetOrder and update the order state to 'ready' if all challenges are 'valid'": {
 			order: testOrderPending,
 			builder: &testpkg.Builder{
@@ -599,6 +642,96 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 				},
 			},
 		},
+		"requeue the order after the Retry-After period if the acme server rate limits creating the order": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrder},
+				ExpectedActions:    []testpkg.Action{},
+				ExpectedEvents:     []string{"Warning RateLimited Request to ACME server was rate limited, retrying in 2m0s"},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					return nil, testACMEErrorRateLimited
+				},
+			},
+			shouldSchedule: true,
+		},
+		"retry with backoff if the acme server returns a badNonce error": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrder},
+				ExpectedActions:    []testpkg.Action{},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					return nil, testACMEErrorBadNonce
+				},
+			},
+			expectErr: true,
+		},
+		"retry with backoff if the acme server returns a serverInternal error": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrder},
+				ExpectedActions:    []testpkg.Action{},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					return nil, testACMEErrorServerInternal
+				},
+			},
+			expectErr: true,
+		},
+		"mark the order as errored if the acme server returns an unauthorized error": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrder},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+							State:       cmacme.Errored,
+							Reason:      "Failed to create Order: " + testACMEErrorUnauthorized.Error(),
+							FailureTime: &nowMetaTime,
+						})))),
+				},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					return nil, testACMEErrorUnauthorized
+				},
+			},
+		},
+		"mark the order as errored if finalizing the order returns an unauthorized error": {
+			order: testOrderReady,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrderReady, testAuthorizationChallengeValid},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrderReady.Namespace,
+						gen.OrderFrom(testOrderReady, gen.SetOrderStatus(func() cmacme.OrderStatus {
+							status := *testOrderReady.Status.DeepCopy()
+							status.State = cmacme.Errored
+							status.Reason = "Failed to finalize Order: " + testACMEErrorUnauthorized.Error()
+							status.FailureTime = &nowMetaTime
+							return status
+						}())))),
+				},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(_ context.Context, url string) (*acmeapi.Order, error) {
+					return testACMEOrderReady, nil
+				},
+				FakeCreateOrderCert: func(_ context.Context, url string, csr []byte, bundle bool) ([][]byte, string, error) {
+					return nil, "", testACMEErrorUnauthorized
+				},
+				FakeHTTP01ChallengeResponse: func(s string) (string, error) {
+					return "key", nil
+				},
+			},
+		},
 		"do nothing if the order is valid": {
 			order: testOrderValid,
 			builder: &testpkg.Builder{
@@ -639,11 +772,12 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 }
 
 type testT struct {
-	order          *cmacme.Order
-	builder        *testpkg.Builder
-	acmeClient     acmecl.Interface
-	shouldSchedule bool
-	expectErr      bool
+	order               *cmacme.Order
+	builder             *testpkg.Builder
+	acmeClient          acmecl.Interface
+	preflightACMEClient acmecl.Interface
+	shouldSchedule      bool
+	expectErr           bool
 }
 
 func runTest(t *testing.T, test testT) {
@@ -659,7 +793,10 @@ func runTest(t *testing.T, test testT) {
 
 	// Set some fields on the embedded controller.
 	cw.accountRegistry = &accountstest.FakeRegistry{
-		GetClientFunc: func(_ string) (acmecl.Interface, error) {
+		GetClientFunc: func(uid string) (acmecl.Interface, error) {
+			if test.preflightACMEClient != nil && strings.HasSuffix(uid, accounts.PreflightUID("")) {
+				return test.preflightACMEClient, nil
+			}
 			return test.acmeClient, nil
 		},
 	}
@@ -49,6 +49,10 @@ type Builder struct {
 	// runDurationFuncs are a list of functions that will be called every
 	// 'duration'
 	runDurationFuncs []runDurationFunc
+
+	// allShards is set if the controller processes the resources of every
+	// shard rather than only those belonging to the shard of this replica
+	allShards bool
 }
 
 // New creates a basic Builder, setting the sync call to the one given
@@ -85,6 +89,14 @@ func (b *Builder) First(function func(context.Context)) *Builder {
 	return b
 }
 
+// ProcessAllShards will cause the controller to process the resources of
+// every shard. This is useful for controllers that set up state needed by
+// every replica, such as the ClusterIssuers controller.
+func (b *Builder) ProcessAllShards() *Builder {
+	b.allShards = true
+	return b
+}
+
 func (b *Builder) Complete() (Interface, error) {
 	if b.context == nil {
 		return nil, fmt.Errorf("controller context must be non-nil")
@@ -97,5 +109,29 @@ func (b *Builder) Complete() (Interface, error) {
 		return nil, fmt.Errorf("error registering controller: %v", err)
 	}
 
-	return NewController(b.ctx, b.name, b.context.Metrics, b.impl.ProcessItem, mustSync, b.runDurationFuncs, queue), nil
+	// Resources outside of the namespace scope or of other shards are still
+	// enqueued by the shared informers, but are skipped here.
+	syncFunc := b.impl.ProcessItem
+	if scope := b.context.NamespaceScope; scope != nil {
+		mustSync = append(mustSync, scope.HasSynced)
+		syncFunc = skipKeys(syncFunc, scope.OwnsKey, "skipping item outside of the selected namespaces")
+	}
+	if shard := b.context.Shard; shard != nil && !b.allShards {
+		mustSync = append(mustSync, shard.HasSynced)
+		syncFunc = skipKeys(syncFunc, shard.OwnsKey, "skipping item belonging to another shard")
+	}
+
+	return NewController(b.ctx, b.name, b.context.Metrics, syncFunc, mustSync, b.runDurationFuncs, queue), nil
+}
+
+// skipKeys wraps syncFunc so that it is only called for the keys that owns
+// returns true for.
+func skipKeys(syncFunc func(context.Context, string) error, owns func(key string) bool, msg string) func(context.Context, string) error {
+	return func(ctx context.Context, key string) error {
+		if !owns(key) {
+			logf.FromContext(ctx).V(logf.DebugLevel).Info(msg, "key", key)
+			return nil
+		}
+		return syncFunc(ctx, key)
+	}
 }
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundles
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	trustapi "github.com/jetstack/cert-manager/pkg/apis/trust/v1alpha1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	trustlisters "github.com/jetstack/cert-manager/pkg/client/listers/trust/v1alpha1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	// ControllerName is the name of the controller that distributes trust
+	// Bundles. It is not enabled by default.
+	ControllerName = "bundles"
+)
+
+// This controller combines the CA certificates of the sources of every
+// Bundle and writes them to a ConfigMap and/or Secret named after the Bundle
+// in every selected namespace. Targets are labelled with the name of the
+// Bundle and owned by it, so that they are garbage collected once the Bundle
+// is deleted, and are removed from namespaces that are no longer selected.
+type controller struct {
+	bundleLister    trustlisters.BundleLister
+	namespaceLister corelisters.NamespaceLister
+	configMapLister corelisters.ConfigMapLister
+	secretLister    corelisters.SecretLister
+	kubeClient      kubernetes.Interface
+	cmClient        cmclient.Interface
+	recorder        record.EventRecorder
+	clock           clock.Clock
+
+	// trustNamespace is the namespace that Secret and ConfigMap sources are
+	// read from.
+	trustNamespace string
+
+	// namespace, if set, restricts the targets to a single namespace.
+	namespace string
+}
+
+func NewController(
+	log logr.Logger,
+	ctx *controllerpkg.Context,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(ctx.WorkqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 5,
+		MaxDelay:  time.Minute * 5,
+	}), ControllerName)
+
+	bundleInformer := ctx.SharedInformerFactory.Trust().V1alpha1().Bundles()
+	namespaceInformer := ctx.KubeSharedInformerFactory.Core().V1().Namespaces()
+	configMapInformer := ctx.KubeSharedInformerFactory.Core().V1().ConfigMaps()
+	secretsInformer := ctx.KubeSharedInformerFactory.Core().V1().Secrets()
+
+	c := &controller{
+		bundleLister:    bundleInformer.Lister(),
+		namespaceLister: namespaceInformer.Lister(),
+		configMapLister: configMapInformer.Lister(),
+		secretLister:    secretsInformer.Lister(),
+		kubeClient:      ctx.Client,
+		cmClient:        ctx.CMClient,
+		recorder:        ctx.Recorder,
+		clock:           ctx.Clock,
+		trustNamespace:  ctx.IssuerOptions.ClusterResourceNamespace,
+		namespace:       ctx.Namespace,
+	}
+
+	bundleInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	namespaceInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: c.enqueueAllBundles(log, queue),
+	})
+	configMapInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: c.enqueueBundlesForObject(log, queue),
+	})
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: c.enqueueBundlesForObject(log, queue),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		bundleInformer.Informer().HasSynced,
+		namespaceInformer.Informer().HasSynced,
+		configMapInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	return c, queue, mustSync
+}
+
+// enqueueAllBundles returns a function that enqueues every Bundle. It is
+// called when a namespace changes, as the namespace may have started or
+// stopped matching the namespace selector of any Bundle.
+func (c *controller) enqueueAllBundles(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		bundles, err := c.bundleLister.List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list bundles")
+			return
+		}
+		for _, bundle := range bundles {
+			queue.Add(bundle.Name)
+		}
+	}
+}
+
+// enqueueBundlesForObject returns a function that enqueues the Bundles that
+// either use the given ConfigMap or Secret as a source, or that own it as a
+// target.
+func (c *controller) enqueueBundlesForObject(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		metaObj, ok := obj.(metav1.Object)
+		if !ok {
+			log.Error(nil, "object does not implement metav1.Object")
+			return
+		}
+
+		if name, ok := metaObj.GetLabels()[trustapi.BundleLabelKey]; ok {
+			queue.Add(name)
+		}
+
+		if metaObj.GetNamespace() != c.trustNamespace {
+			return
+		}
+
+		bundles, err := c.bundleLister.List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list bundles")
+			return
+		}
+		for _, bundle := range bundles {
+			for _, source := range bundle.Spec.Sources {
+				if (source.Secret != nil && source.Secret.Name == metaObj.GetName()) ||
+					(source.ConfigMap != nil && source.ConfigMap.Name == metaObj.GetName()) {
+					queue.Add(bundle.Name)
+					break
+				}
+			}
+		}
+	}
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log, ctx)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundles
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	trustapi "github.com/jetstack/cert-manager/pkg/apis/trust/v1alpha1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	reasonSynced        = "Synced"
+	reasonSourceError   = "SourceError"
+	reasonInvalidTarget = "InvalidTarget"
+	reasonSyncFailed    = "SyncFailed"
+)
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	ctx = logf.NewContext(ctx, log)
+
+	bundle, err := c.bundleLister.Get(key)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	bundle = bundle.DeepCopy()
+	syncErr := c.sync(ctx, bundle)
+	if err := c.updateStatus(ctx, bundle); err != nil {
+		return utilerrors.NewAggregate([]error{syncErr, err})
+	}
+
+	return syncErr
+}
+
+// sync writes the Bundle to all selected namespaces and removes it from all
+// others. The Synced condition and the namespace count in the status of the
+// given Bundle are updated accordingly. An error is only returned if the sync
+// should be retried.
+func (c *controller) sync(ctx context.Context, bundle *trustapi.Bundle) error {
+	log := logf.FromContext(ctx)
+	target := bundle.Spec.Target
+
+	if target.ConfigMap == nil && target.Secret == nil {
+		c.setFailed(bundle, reasonInvalidTarget, "At least one of spec.target.configMap and spec.target.secret must be set")
+		return nil
+	}
+	selector := labels.Everything()
+	if target.NamespaceSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(target.NamespaceSelector)
+		if err != nil {
+			c.setFailed(bundle, reasonInvalidTarget, fmt.Sprintf("Invalid namespace selector: %v", err))
+			return nil
+		}
+	}
+
+	data, err := c.buildBundle(bundle.Spec.Sources)
+	if err != nil {
+		c.setFailed(bundle, reasonSourceError, err.Error())
+		return nil
+	}
+
+	namespaces, err := c.selectedNamespaces(selector)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var updated int
+	for _, namespace := range namespaces.List() {
+		if target.ConfigMap != nil {
+			changed, err := c.syncConfigMap(ctx, bundle, namespace, target.ConfigMap.Key, data)
+			if err != nil {
+				errs = append(errs, err)
+			} else if changed {
+				updated++
+			}
+		}
+		if target.Secret != nil {
+			changed, err := c.syncSecret(ctx, bundle, namespace, target.Secret.Key, data)
+			if err != nil {
+				errs = append(errs, err)
+			} else if changed {
+				updated++
+			}
+		}
+	}
+
+	if err := c.cleanupTargets(ctx, bundle, namespaces); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		log.Error(err, "failed to sync bundle")
+		c.setFailed(bundle, reasonSyncFailed, fmt.Sprintf("Failed to write bundle: %v", err))
+		return err
+	}
+
+	if updated > 0 {
+		c.recorder.Eventf(bundle, corev1.EventTypeNormal, reasonSynced, "Updated %d target(s)", updated)
+	}
+	bundle.Status.Namespaces = int32(namespaces.Len())
+	setCondition(bundle, c.clock.Now(), trustapi.BundleConditionSynced, cmmeta.ConditionTrue, reasonSynced,
+		fmt.Sprintf("Bundle written to %d namespace(s)", namespaces.Len()))
+
+	return nil
+}
+
+// setFailed marks the Bundle as not synced and fires a warning event.
+func (c *controller) setFailed(bundle *trustapi.Bundle, reason, message string) {
+	c.recorder.Event(bundle, corev1.EventTypeWarning, reason, message)
+	setCondition(bundle, c.clock.Now(), trustapi.BundleConditionSynced, cmmeta.ConditionFalse, reason, message)
+}
+
+// buildBundle returns the PEM encoded, de-duplicated CA certificates of all
+// sources, in the order that they are listed.
+func (c *controller) buildBundle(sources []trustapi.BundleSource) ([]byte, error) {
+	var buf bytes.Buffer
+	seen := sets.NewString()
+
+	for i, source := range sources {
+		var data []byte
+		switch {
+		case source.Secret != nil:
+			secret, err := c.secretLister.Secrets(c.trustNamespace).Get(source.Secret.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get Secret %s/%s: %v", c.trustNamespace, source.Secret.Name, err)
+			}
+			var ok bool
+			if data, ok = secret.Data[source.Secret.Key]; !ok {
+				return nil, fmt.Errorf("Secret %s/%s has no key %q", c.trustNamespace, source.Secret.Name, source.Secret.Key)
+			}
+		case source.ConfigMap != nil:
+			cm, err := c.configMapLister.ConfigMaps(c.trustNamespace).Get(source.ConfigMap.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %v", c.trustNamespace, source.ConfigMap.Name, err)
+			}
+			value, ok := cm.Data[source.ConfigMap.Key]
+			if !ok {
+				return nil, fmt.Errorf("ConfigMap %s/%s has no key %q", c.trustNamespace, source.ConfigMap.Name, source.ConfigMap.Key)
+			}
+			data = []byte(value)
+		case source.InLine != nil:
+			data = []byte(*source.InLine)
+		default:
+			return nil, fmt.Errorf("source %d does not set secret, configMap or inLine", i)
+		}
+
+		certs, err := pki.DecodeX509CertificateChainBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode certificates of source %d: %v", i, err)
+		}
+		for _, cert := range certs {
+			if seen.Has(string(cert.Raw)) {
+				continue
+			}
+			seen.Insert(string(cert.Raw))
+			certPEM, err := pki.EncodeX509(cert)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(certPEM)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// selectedNamespaces returns the names of all namespaces that match the
+// selector and are not being terminated.
+func (c *controller) selectedNamespaces(selector labels.Selector) (sets.String, error) {
+	namespaces, err := c.namespaceLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := sets.NewString()
+	for _, ns := range namespaces {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		if c.namespace != "" && ns.Name != c.namespace {
+			continue
+		}
+		selected.Insert(ns.Name)
+	}
+
+	return selected, nil
+}
+
+// targetMeta returns the metadata of a target object of the Bundle in the
+// given namespace.
+func targetMeta(bundle *trustapi.Bundle, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            bundle.Name,
+		Namespace:       namespace,
+		Labels:          map[string]string{trustapi.BundleLabelKey: bundle.Name},
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind(trustapi.BundleKind))},
+	}
+}
+
+// syncConfigMap writes the Bundle to its ConfigMap in the given namespace.
+// It returns true if the ConfigMap was created or updated.
+func (c *controller) syncConfigMap(ctx context.Context, bundle *trustapi.Bundle, namespace, key string, data []byte) (bool, error) {
+	cm, err := c.configMapLister.ConfigMaps(namespace).Get(bundle.Name)
+	if apierrors.IsNotFound(err) {
+		_, err = c.kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: targetMeta(bundle, namespace),
+			Data:       map[string]string{key: string(data)},
+		}, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+	if !metav1.IsControlledBy(cm, bundle) {
+		return false, fmt.Errorf("ConfigMap %s/%s already exists and is not owned by the Bundle", namespace, bundle.Name)
+	}
+
+	want := map[string]string{key: string(data)}
+	if reflect.DeepEqual(cm.Data, want) && cm.Labels[trustapi.BundleLabelKey] == bundle.Name {
+		return false, nil
+	}
+
+	cm = cm.DeepCopy()
+	cm.Data = want
+	if cm.Labels == nil {
+		cm.Labels = make(map[string]string)
+	}
+	cm.Labels[trustapi.BundleLabelKey] = bundle.Name
+	_, err = c.kubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err == nil, err
+}
+
+// syncSecret writes the Bundle to its Secret in the given namespace. It
+// returns true if the Secret was created or updated.
+func (c *controller) syncSecret(ctx context.Context, bundle *trustapi.Bundle, namespace, key string, data []byte) (bool, error) {
+	secret, err := c.secretLister.Secrets(namespace).Get(bundle.Name)
+	if apierrors.IsNotFound(err) {
+		_, err = c.kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: targetMeta(bundle, namespace),
+			Data:       map[string][]byte{key: data},
+		}, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+	if !metav1.IsControlledBy(secret, bundle) {
+		return false, fmt.Errorf("Secret %s/%s already exists and is not owned by the Bundle", namespace, bundle.Name)
+	}
+
+	want := map[string][]byte{key: data}
+	if reflect.DeepEqual(secret.Data, want) && secret.Labels[trustapi.BundleLabelKey] == bundle.Name {
+		return false, nil
+	}
+
+	secret = secret.DeepCopy()
+	secret.Data = want
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+	secret.Labels[trustapi.BundleLabelKey] = bundle.Name
+	_, err = c.kubeClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err == nil, err
+}
+
+// cleanupTargets deletes the targets owned by the Bundle that are in
+// namespaces that are no longer selected, or whose kind is no longer a
+// target of the Bundle.
+func (c *controller) cleanupTargets(ctx context.Context, bundle *trustapi.Bundle, namespaces sets.String) error {
+	selector := labels.SelectorFromSet(labels.Set{trustapi.BundleLabelKey: bundle.Name})
+	var errs []error
+
+	configMaps, err := c.configMapLister.List(selector)
+	if err != nil {
+		return err
+	}
+	for _, cm := range configMaps {
+		if !metav1.IsControlledBy(cm, bundle) || (bundle.Spec.Target.ConfigMap != nil && namespaces.Has(cm.Namespace)) {
+			continue
+		}
+		if err := c.kubeClient.CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	secrets, err := c.secretLister.List(selector)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if !metav1.IsControlledBy(secret, bundle) || (bundle.Spec.Target.Secret != nil && namespaces.Has(secret.Namespace)) {
+			continue
+		}
+		if err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// updateStatus writes the status of the Bundle if it has changed.
+func (c *controller) updateStatus(ctx context.Context, bundle *trustapi.Bundle) error {
+	existing, err := c.bundleLister.Get(bundle.Name)
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(existing.Status, bundle.Status) {
+		return nil
+	}
+	_, err = c.cmClient.TrustV1alpha1().Bundles().UpdateStatus(ctx, bundle, metav1.UpdateOptions{})
+	return err
+}
+
+// setCondition sets the condition of the given type on the Bundle. The last
+// transition time is only updated if the status of the condition changes.
+func setCondition(bundle *trustapi.Bundle, now time.Time, conditionType trustapi.BundleConditionType, status cmmeta.ConditionStatus, reason, message string) {
+	newCondition := trustapi.BundleCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: &metav1.Time{Time: now},
+		ObservedGeneration: bundle.Generation,
+	}
+
+	for i, cond := range bundle.Status.Conditions {
+		if cond.Type != conditionType {
+			continue
+		}
+		if cond.Status == status {
+			newCondition.LastTransitionTime = cond.LastTransitionTime
+		}
+		bundle.Status.Conditions[i] = newCondition
+		return
+	}
+
+	bundle.Status.Conditions = append(bundle.Status.Conditions, newCondition)
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundles
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	trustapi "github.com/jetstack/cert-manager/pkg/apis/trust/v1alpha1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func mustCreateCA(t *testing.T, commonName string, now time.Time) []byte {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		PublicKey:             key.Public(),
+	}
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	fixedClock := fakeclock.NewFakeClock(now)
+
+	const trustNamespace = "cert-manager"
+	rootA := mustCreateCA(t, "root-a", now)
+	rootB := mustCreateCA(t, "root-b", now)
+	inLine := string(rootB) + string(rootA)
+
+	namespace := func(name string, lbls map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: lbls}}
+	}
+	terminating := namespace("terminating", map[string]string{"trust": "true"})
+	terminating.Status.Phase = corev1.NamespaceTerminating
+	namespaces := []runtime.Object{
+		namespace(trustNamespace, nil),
+		namespace("app-a", map[string]string{"trust": "true"}),
+		namespace("app-b", map[string]string{"trust": "true"}),
+		namespace("other", nil),
+		terminating,
+	}
+	sourceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: "root-a"},
+		Data:       map[string][]byte{"ca.crt": rootA},
+	}
+	sourceConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: "root-b"},
+		Data:       map[string]string{"ca.crt": string(rootB)},
+	}
+
+	bundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "roots", UID: "bundle-uid", Generation: 2},
+		Spec: trustapi.BundleSpec{
+			Sources: []trustapi.BundleSource{
+				{Secret: &trustapi.SourceObjectKeySelector{Name: "root-a", Key: "ca.crt"}},
+				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "root-b", Key: "ca.crt"}},
+				{InLine: &inLine},
+			},
+			Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.KeySelector{Key: "ca-bundle.crt"},
+				Secret:            &trustapi.KeySelector{Key: "ca.crt"},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+			},
+		},
+	}
+	bundleWith := func(mod func(*trustapi.Bundle)) *trustapi.Bundle {
+		b := bundle.DeepCopy()
+		mod(b)
+		return b
+	}
+	ownedConfigMap := func(namespace string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: targetMeta(bundle, namespace),
+			Data:       map[string]string{"ca-bundle.crt": "stale"},
+		}
+	}
+	expectedData := string(rootA) + string(rootB)
+
+	tests := map[string]struct {
+		kubeObjects []runtime.Object
+		bundle      *trustapi.Bundle
+
+		expConfigMaps []string
+		expSecrets    []string
+		expCondition  trustapi.BundleCondition
+		expEvent      string
+		expErr        bool
+	}{
+		"writes the de-duplicated sources to all selected namespaces": {
+			kubeObjects:   []runtime.Object{sourceSecret, sourceConfigMap},
+			bundle:        bundle,
+			expConfigMaps: []string{"app-a", "app-b"},
+			expSecrets:    []string{"app-a", "app-b"},
+			expCondition:  trustapi.BundleCondition{Status: cmmeta.ConditionTrue, Reason: reasonSynced, Message: "Bundle written to 2 namespace(s)"},
+			expEvent:      "Normal Synced Updated 4 target(s)",
+		},
+		"writes to all namespaces if no selector is set": {
+			kubeObjects: []runtime.Object{sourceSecret, sourceConfigMap},
+			bundle: bundleWith(func(b *trustapi.Bundle) {
+				b.Spec.Target.Secret = nil
+				b.Spec.Target.NamespaceSelector = nil
+			}),
+			expConfigMaps: []string{"app-a", "app-b", trustNamespace, "other"},
+			expCondition:  trustapi.BundleCondition{Status: cmmeta.ConditionTrue, Reason: reasonSynced, Message: "Bundle written to 4 namespace(s)"},
+			expEvent:      "Normal Synced Updated 4 target(s)",
+		},
+		"updates stale targets and removes targets from namespaces that are no longer selected": {
+			kubeObjects: []runtime.Object{sourceSecret, sourceConfigMap, ownedConfigMap("app-a"), ownedConfigMap("other")},
+			bundle: bundleWith(func(b *trustapi.Bundle) {
+				b.Spec.Target.Secret = nil
+			}),
+			expConfigMaps: []string{"app-a", "app-b"},
+			expCondition:  trustapi.BundleCondition{Status: cmmeta.ConditionTrue, Reason: reasonSynced, Message: "Bundle written to 2 namespace(s)"},
+			expEvent:      "Normal Synced Updated 2 target(s)",
+		},
+		"removes targets whose kind is no longer a target": {
+			kubeObjects: []runtime.Object{sourceSecret, sourceConfigMap, ownedConfigMap("app-a")},
+			bundle: bundleWith(func(b *trustapi.Bundle) {
+				b.Spec.Target.ConfigMap = nil
+			}),
+			expSecrets:   []string{"app-a", "app-b"},
+			expCondition: trustapi.BundleCondition{Status: cmmeta.ConditionTrue, Reason: reasonSynced, Message: "Bundle written to 2 namespace(s)"},
+			expEvent:     "Normal Synced Updated 2 target(s)",
+		},
+		"does not overwrite objects that are not owned by the Bundle": {
+			kubeObjects: []runtime.Object{sourceSecret, sourceConfigMap, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-a", Name: "roots"},
+				Data:       map[string]string{"ca-bundle.crt": "user data"},
+			}},
+			bundle: bundleWith(func(b *trustapi.Bundle) {
+				b.Spec.Target.Secret = nil
+			}),
+			expConfigMaps: []string{"app-b"},
+			expCondition:  trustapi.BundleCondition{Status: cmmeta.ConditionFalse, Reason: reasonSyncFailed, Message: "Failed to write bundle: ConfigMap app-a/roots already exists and is not owned by the Bundle"},
+			expEvent:      "Warning SyncFailed Failed to write bundle: ConfigMap app-a/roots already exists and is not owned by the Bundle",
+			expErr:        true,
+		},
+		"does not write targets if a source does not exist": {
+			kubeObjects:  []runtime.Object{sourceSecret},
+			bundle:       bundle,
+			expCondition: trustapi.BundleCondition{Status: cmmeta.ConditionFalse, Reason: reasonSourceError, Message: `failed to get ConfigMap cert-manager/root-b: configmap "root-b" not found`},
+			expEvent:     `Warning SourceError failed to get ConfigMap cert-manager/root-b: configmap "root-b" not found`,
+		},
+		"does not write targets if a source does not contain certificates": {
+			kubeObjects: []runtime.Object{sourceSecret, sourceConfigMap},
+			bundle: bundleWith(func(b *trustapi.Bundle) {
+				invalid := "not a certificate"
+				b.Spec.Sources = append(b.Spec.Sources, trustapi.BundleSource{InLine: &invalid})
+			}),
+			expCondition: trustapi.BundleCondition{Status: cmmeta.ConditionFalse, Reason: reasonSourceError, Message: "failed to decode certificates of source 3: error decoding certificate PEM block"},
+			expEvent:     "Warning SourceError failed to decode certificates of source 3: error decoding certificate PEM block",
+		},
+		"reports an invalid target if neither a ConfigMap nor a Secret is set": {
+			kubeObjects: []runtime.Object{sourceSecret, sourceConfigMap},
+			bundle: bundleWith(func(b *trustapi.Bundle) {
+				b.Spec.Target = trustapi.BundleTarget{}
+			}),
+			expCondition: trustapi.BundleCondition{Status: cmmeta.ConditionFalse, Reason: reasonInvalidTarget, Message: "At least one of spec.target.configMap and spec.target.secret must be set"},
+			expEvent:     "Warning InvalidTarget At least one of spec.target.configMap and spec.target.secret must be set",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				KubeObjects:        append(test.kubeObjects, namespaces...),
+				CertManagerObjects: []runtime.Object{test.bundle},
+			}
+			builder.Init()
+			builder.Context.IssuerOptions = controllerpkg.IssuerOptions{ClusterResourceNamespace: trustNamespace}
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			err := w.ProcessItem(context.Background(), test.bundle.Name)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			configMaps, err := builder.Client.CoreV1().ConfigMaps("").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotConfigMaps []string
+			for _, cm := range configMaps.Items {
+				if cm.Name != "roots" || !metav1.IsControlledBy(&cm, test.bundle) {
+					continue
+				}
+				gotConfigMaps = append(gotConfigMaps, cm.Namespace)
+				if cm.Data[test.bundle.Spec.Target.ConfigMap.Key] != expectedData {
+					t.Errorf("unexpected data in ConfigMap %s/%s: %q", cm.Namespace, cm.Name, cm.Data)
+				}
+			}
+			assertNamespaces(t, "ConfigMaps", test.expConfigMaps, gotConfigMaps)
+
+			secrets, err := builder.Client.CoreV1().Secrets("").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotSecrets []string
+			for _, secret := range secrets.Items {
+				if secret.Name != "roots" || !metav1.IsControlledBy(&secret, test.bundle) {
+					continue
+				}
+				gotSecrets = append(gotSecrets, secret.Namespace)
+				if string(secret.Data[test.bundle.Spec.Target.Secret.Key]) != expectedData {
+					t.Errorf("unexpected data in Secret %s/%s", secret.Namespace, secret.Name)
+				}
+			}
+			assertNamespaces(t, "Secrets", test.expSecrets, gotSecrets)
+
+			got, err := builder.CMClient.TrustV1alpha1().Bundles().Get(context.Background(), test.bundle.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Status.Conditions) != 1 {
+				t.Fatalf("expected exactly one condition, got %v", got.Status.Conditions)
+			}
+			cond := got.Status.Conditions[0]
+			if cond.Type != trustapi.BundleConditionSynced || cond.Status != test.expCondition.Status ||
+				cond.Reason != test.expCondition.Reason || cond.Message != test.expCondition.Message {
+				t.Errorf("unexpected condition, exp=%+v got=%+v", test.expCondition, cond)
+			}
+			if cond.ObservedGeneration != test.bundle.Generation {
+				t.Errorf("unexpected observed generation %d", cond.ObservedGeneration)
+			}
+
+			events := builder.Events()
+			if test.expEvent == "" && len(events) > 0 {
+				t.Errorf("expected no events, got %v", events)
+			}
+			if test.expEvent != "" && (len(events) != 1 || events[0] != test.expEvent) {
+				t.Errorf("unexpected events, exp=%q got=%v", test.expEvent, events)
+			}
+		})
+	}
+}
+
+func assertNamespaces(t *testing.T, kind string, exp, got []string) {
+	sort.Strings(got)
+	if len(exp) != len(got) {
+		t.Errorf("unexpected namespaces of %s, exp=%v got=%v", kind, exp, got)
+		return
+	}
+	for i := range exp {
+		if exp[i] != got[i] {
+			t.Errorf("unexpected namespaces of %s, exp=%v got=%v", kind, exp, got)
+			return
+		}
+	}
+}
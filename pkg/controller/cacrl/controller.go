@@ -0,0 +1,254 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacrl
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	revocationapi "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	revocationlisters "github.com/jetstack/cert-manager/pkg/client/listers/revocation/v1alpha1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/scheduler"
+)
+
+const (
+	// ControllerName is the name of the controller that maintains certificate
+	// revocation lists for CA issuers. It is not enabled by default.
+	ControllerName = "issuers-ca-crl"
+
+	// CRLKey is the key in the configured Secret and ConfigMap resources
+	// that the PEM encoded CRL is written to.
+	CRLKey = "ca.crl"
+)
+
+// This controller maintains a certificate revocation list (CRL) for every CA
+// Issuer and ClusterIssuer that has `spec.ca.crl` set. A certificate is
+// revoked by creating a CertificateRevocation for its serial number, or by
+// adding the `cert-manager.io/revoke: "true"` annotation to the
+// CertificateRequest that it was issued for.
+// Revoked certificates are kept in the CRL even if their CertificateRequest
+// is later deleted, as the previous CRL is read back when generating a new
+// one.
+type controller struct {
+	issuerLister             cmlisters.IssuerLister
+	clusterIssuerLister      cmlisters.ClusterIssuerLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	revocationLister         revocationlisters.CertificateRevocationLister
+	secretLister             corelisters.SecretLister
+	kubeClient               kubernetes.Interface
+	recorder                 record.EventRecorder
+	scheduledWorkQueue       scheduler.ScheduledWorkQueue
+	clock                    clock.Clock
+
+	// clusterResourceNamespace is the namespace in which the CA Secret and
+	// the CRL of ClusterIssuers are stored.
+	clusterResourceNamespace string
+}
+
+func NewController(
+	log logr.Logger,
+	ctx *controllerpkg.Context,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(ctx.WorkqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 5,
+		MaxDelay:  time.Minute * 5,
+	}), ControllerName)
+
+	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
+	certificateRequestInformer := ctx.SharedInformerFactory.Certmanager().V1().CertificateRequests()
+	revocationInformer := ctx.SharedInformerFactory.Revocation().V1alpha1().CertificateRevocations()
+	secretsInformer := ctx.KubeSharedInformerFactory.Core().V1().Secrets()
+
+	c := &controller{
+		issuerLister:             issuerInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		revocationLister:         revocationInformer.Lister(),
+		secretLister:             secretsInformer.Lister(),
+		kubeClient:               ctx.Client,
+		recorder:                 ctx.Recorder,
+		scheduledWorkQueue:       scheduler.NewScheduledWorkQueue(ctx.Clock, queue.Add),
+		clock:                    ctx.Clock,
+		clusterResourceNamespace: ctx.IssuerOptions.ClusterResourceNamespace,
+	}
+
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	ctx.RequeueOnNamespaceChange(issuerInformer.Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: c.enqueueIssuerForRevokedRequest(log, queue),
+	})
+	revocationInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: c.enqueueIssuerForRevocation(log, queue),
+	})
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: c.enqueueIssuersForSecret(log, queue),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		issuerInformer.Informer().HasSynced,
+		certificateRequestInformer.Informer().HasSynced,
+		revocationInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	// ClusterIssuers are only watched if not scoped to a single namespace.
+	if ctx.Namespace == "" {
+		clusterIssuerInformer := ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers()
+		c.clusterIssuerLister = clusterIssuerInformer.Lister()
+		clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+
+	return c, queue, mustSync
+}
+
+// enqueueIssuerForRevokedRequest returns a function that enqueues the issuer
+// referenced by a CertificateRequest if it has been marked as revoked.
+func (c *controller) enqueueIssuerForRevokedRequest(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		cr, ok := obj.(*cmapi.CertificateRequest)
+		if !ok {
+			log.Error(nil, "object is not a CertificateRequest object")
+			return
+		}
+		if cr.Annotations[cmapi.RevokeCertificateAnnotation] != "true" {
+			return
+		}
+
+		ref := cr.Spec.IssuerRef
+		if ref.Group != "" && ref.Group != certmanager.GroupName {
+			return
+		}
+		switch ref.Kind {
+		case "", cmapi.IssuerKind:
+			namespace := cr.Namespace
+			if ref.Namespace != "" {
+				namespace = ref.Namespace
+			}
+			queue.Add(namespace + "/" + ref.Name)
+		case cmapi.ClusterIssuerKind:
+			queue.Add(ref.Name)
+		}
+	}
+}
+
+// enqueueIssuerForRevocation returns a function that enqueues the issuer
+// referenced by a CertificateRevocation.
+func (c *controller) enqueueIssuerForRevocation(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		rev, ok := obj.(*revocationapi.CertificateRevocation)
+		if !ok {
+			log.Error(nil, "object is not a CertificateRevocation object")
+			return
+		}
+
+		ref := rev.Spec.IssuerRef
+		if ref.Group != "" && ref.Group != certmanager.GroupName {
+			return
+		}
+		switch ref.Kind {
+		case "", cmapi.IssuerKind:
+			queue.Add(rev.Namespace + "/" + ref.Name)
+		case cmapi.ClusterIssuerKind:
+			if rev.Namespace == c.clusterResourceNamespace {
+				queue.Add(ref.Name)
+			}
+		}
+	}
+}
+
+// enqueueIssuersForSecret returns a function that enqueues all CA issuers
+// that use the given Secret either as their CA or to store their CRL.
+func (c *controller) enqueueIssuersForSecret(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			log.Error(nil, "object is not a Secret object")
+			return
+		}
+
+		usesSecret := func(iss cmapi.GenericIssuer) bool {
+			ca := iss.GetSpec().CA
+			return ca != nil && ca.CRL != nil && (ca.SecretName == secret.Name || ca.CRL.SecretName == secret.Name)
+		}
+
+		issuers, err := c.issuerLister.Issuers(secret.Namespace).List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list issuers")
+			return
+		}
+		for _, iss := range issuers {
+			if usesSecret(iss) {
+				queue.Add(iss.Namespace + "/" + iss.Name)
+			}
+		}
+
+		if c.clusterIssuerLister == nil || secret.Namespace != c.clusterResourceNamespace {
+			return
+		}
+		clusterIssuers, err := c.clusterIssuerLister.List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list clusterissuers")
+			return
+		}
+		for _, iss := range clusterIssuers {
+			if usesSecret(iss) {
+				queue.Add(iss.Name)
+			}
+		}
+	}
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log, ctx)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacrl
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/jetstack/cert-manager/internal/revocation"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/errors"
+	"github.com/jetstack/cert-manager/pkg/util/kube"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	reasonCRLUpdated = "CRLUpdated"
+	reasonCRLError   = "CRLError"
+
+	// crlPEMType is the PEM block type used when encoding CRLs.
+	crlPEMType = "X509 CRL"
+)
+
+// oidExtensionReasonCode is the OID of the CRL entry extension that records
+// the reason for a revocation.
+var oidExtensionReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+// defaultCRLDuration is the validity of generated CRLs if not set on the
+// issuer.
+var defaultCRLDuration = 24 * time.Hour
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	var iss cmapi.GenericIssuer
+	resourceNamespace := namespace
+	if namespace == "" {
+		if c.clusterIssuerLister == nil {
+			return nil
+		}
+		iss, err = c.clusterIssuerLister.Get(name)
+		resourceNamespace = c.clusterResourceNamespace
+	} else {
+		iss, err = c.issuerLister.Issuers(namespace).Get(name)
+	}
+	if apierrors.IsNotFound(err) {
+		c.scheduledWorkQueue.Forget(key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ca := iss.GetSpec().CA
+	if ca == nil || ca.CRL == nil {
+		c.scheduledWorkQueue.Forget(key)
+		return nil
+	}
+
+	refreshIn, err := c.syncCRL(ctx, iss, resourceNamespace)
+	if err != nil {
+		c.recorder.Eventf(iss, corev1.EventTypeWarning, reasonCRLError, "Failed to update CRL: %v", err)
+		return err
+	}
+	if refreshIn > 0 {
+		c.scheduledWorkQueue.Add(key, refreshIn)
+	}
+
+	return nil
+}
+
+// syncCRL ensures that the CRL of the given CA issuer is up to date and
+// stored in all configured resources. It returns the duration after which
+// the CRL should be regenerated.
+func (c *controller) syncCRL(ctx context.Context, iss cmapi.GenericIssuer, resourceNamespace string) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+	ca := iss.GetSpec().CA
+
+	caCerts, caKey, err := kube.SecretTLSKeyPair(ctx, c.secretLister, resourceNamespace, ca.SecretName)
+	if apierrors.IsNotFound(err) || errors.IsInvalidData(err) {
+		// The issuers controller reports problems with the CA Secret on the
+		// issuer's Ready condition. The CRL will be regenerated once the
+		// Secret has been fixed.
+		log.V(logf.DebugLevel).Info("CA secret not usable, not generating CRL", "error", err.Error())
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	caCert := caCerts[0]
+	if caCert.KeyUsage != 0 && caCert.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		// Retrying will not help until the CA certificate is replaced.
+		c.recorder.Event(iss, corev1.EventTypeWarning, reasonCRLError, "CA certificate does not have the cRLSign key usage set, cannot generate CRL")
+		return 0, nil
+	}
+
+	if ok, err := c.crlSecretUsable(ca, resourceNamespace); err != nil {
+		return 0, err
+	} else if !ok {
+		// Retrying will not help until the issuer or the Secret is changed.
+		c.recorder.Eventf(iss, corev1.EventTypeWarning, reasonCRLError, "Secret %q contains a certificate or private key, refusing to write the CRL to it", ca.CRL.SecretName)
+		return 0, nil
+	}
+
+	duration := defaultCRLDuration
+	if ca.CRL.Duration != nil {
+		duration = ca.CRL.Duration.Duration
+	}
+
+	existingPEM, err := c.existingCRL(ctx, ca.CRL, resourceNamespace)
+	if err != nil {
+		return 0, err
+	}
+
+	now := c.clock.Now()
+	var existing *pkix.CertificateList
+	if existingPEM != nil {
+		existing = parseCRLSignedBy(existingPEM, caCert)
+	}
+
+	revoked, err := c.revokedCertificates(iss, caCert, existing, now)
+	if err != nil {
+		return 0, err
+	}
+
+	crlPEM := existingPEM
+	if existing == nil || !sameEntries(existing.TBSCertList.RevokedCertificates, revoked) || !now.Before(refreshTime(existing)) {
+		crlPEM, err = generateCRL(caCert, caKey, revoked, now, duration)
+		if err != nil {
+			return 0, err
+		}
+		existing, err = x509.ParseCRL(crlPEM)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	updated, err := c.storeCRL(ctx, ca.CRL, resourceNamespace, crlPEM)
+	if err != nil {
+		return 0, err
+	}
+	if updated {
+		c.recorder.Eventf(iss, corev1.EventTypeNormal, reasonCRLUpdated, "CRL updated with %d revoked certificate(s)", len(revoked))
+	}
+
+	return refreshTime(existing).Sub(now), nil
+}
+
+// crlSecretUsable returns false if the CRL is configured to be written to a
+// Secret holding certificate data, such as the CA Secret itself or the
+// Secret of a Certificate, so that the CRL is never written alongside a
+// certificate or private key. The webhook rejects the CA Secret, but issuers
+// may have been created before it did.
+func (c *controller) crlSecretUsable(ca *cmapi.CAIssuer, namespace string) (bool, error) {
+	if len(ca.CRL.SecretName) == 0 {
+		return true, nil
+	}
+	if ca.CRL.SecretName == ca.SecretName {
+		return false, nil
+	}
+	secret, err := c.secretLister.Secrets(namespace).Get(ca.CRL.SecretName)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, cmmeta.TLSCAKey} {
+		if _, ok := secret.Data[key]; ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// existingCRL returns the PEM encoded CRL currently stored in the configured
+// Secret, or in the ConfigMap if no Secret is configured.
+func (c *controller) existingCRL(ctx context.Context, crl *cmapi.CAIssuerCRL, namespace string) ([]byte, error) {
+	if len(crl.SecretName) > 0 {
+		secret, err := c.secretLister.Secrets(namespace).Get(crl.SecretName)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return secret.Data[CRLKey], nil
+	}
+
+	cm, err := c.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, crl.ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := cm.Data[CRLKey]; ok {
+		return []byte(data), nil
+	}
+	return nil, nil
+}
+
+// revokedCertificates returns the list of certificates that should be
+// contained in the CRL. Entries from a previous CRL signed by the same CA are
+// retained so that revocations are not lost when a CertificateRequest or
+// CertificateRevocation is deleted.
+func (c *controller) revokedCertificates(iss cmapi.GenericIssuer, caCert *x509.Certificate, existing *pkix.CertificateList, now time.Time) ([]pkix.RevokedCertificate, error) {
+	entries := make(map[string]pkix.RevokedCertificate)
+	if existing != nil {
+		for _, entry := range existing.TBSCertList.RevokedCertificates {
+			entries[entry.SerialNumber.String()] = pkix.RevokedCertificate{
+				SerialNumber:   entry.SerialNumber,
+				RevocationTime: entry.RevocationTime,
+				Extensions:     entry.Extensions,
+			}
+		}
+	}
+
+	revocations, err := revocation.ForIssuer(c.revocationLister, iss, c.clusterResourceNamespace)
+	if err != nil {
+		return nil, err
+	}
+	for key, rev := range revocations {
+		if _, ok := entries[key]; ok {
+			continue
+		}
+		entry := pkix.RevokedCertificate{
+			SerialNumber:   rev.SerialNumber,
+			RevocationTime: rev.RevocationTime,
+		}
+		if rev.Reason != ocsp.Unspecified {
+			ext, err := reasonCodeExtension(rev.Reason)
+			if err != nil {
+				return nil, err
+			}
+			entry.Extensions = []pkix.Extension{ext}
+		}
+		entries[key] = entry
+	}
+
+	// CertificateRequests for ClusterIssuers, and for Issuers granted to
+	// other namespaces, may live in any namespace.
+	requests, err := c.certificateRequestLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, cr := range requests {
+		if cr.Annotations[cmapi.RevokeCertificateAnnotation] != "true" || len(cr.Status.Certificate) == 0 {
+			continue
+		}
+		if !revocation.ReferencesIssuer(cr, iss) {
+			continue
+		}
+		cert, err := pki.DecodeX509CertificateBytes(cr.Status.Certificate)
+		if err != nil {
+			continue
+		}
+		// Only certificates that were signed by the current CA may be
+		// revoked by its CRL.
+		if err := cert.CheckSignatureFrom(caCert); err != nil {
+			continue
+		}
+		if _, ok := entries[cert.SerialNumber.String()]; ok {
+			continue
+		}
+		entries[cert.SerialNumber.String()] = pkix.RevokedCertificate{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: now.UTC(),
+		}
+	}
+
+	revoked := make([]pkix.RevokedCertificate, 0, len(entries))
+	for _, entry := range entries {
+		revoked = append(revoked, entry)
+	}
+	sort.Slice(revoked, func(i, j int) bool {
+		return revoked[i].SerialNumber.Cmp(revoked[j].SerialNumber) < 0
+	})
+
+	return revoked, nil
+}
+
+// reasonCodeExtension returns the CRL entry extension that records the given
+// RFC 5280 revocation reason.
+func reasonCodeExtension(reason int) (pkix.Extension, error) {
+	value, err := asn1.Marshal(asn1.Enumerated(reason))
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{Id: oidExtensionReasonCode, Value: value}, nil
+}
+
+// storeCRL writes the CRL to the configured Secret and ConfigMap, creating
+// them if needed. It returns true if any resource was changed.
+func (c *controller) storeCRL(ctx context.Context, crl *cmapi.CAIssuerCRL, namespace string, crlPEM []byte) (bool, error) {
+	var updated bool
+
+	if len(crl.SecretName) > 0 {
+		secret, err := c.secretLister.Secrets(namespace).Get(crl.SecretName)
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = c.kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: crl.SecretName, Namespace: namespace},
+				Data:       map[string][]byte{CRLKey: crlPEM},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return false, err
+			}
+			updated = true
+		case err != nil:
+			return false, err
+		case !bytes.Equal(secret.Data[CRLKey], crlPEM):
+			secret = secret.DeepCopy()
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			secret.Data[CRLKey] = crlPEM
+			if _, err := c.kubeClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+				return false, err
+			}
+			updated = true
+		}
+	}
+
+	if len(crl.ConfigMapName) > 0 {
+		cm, err := c.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, crl.ConfigMapName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = c.kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: crl.ConfigMapName, Namespace: namespace},
+				Data:       map[string]string{CRLKey: string(crlPEM)},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return false, err
+			}
+			updated = true
+		case err != nil:
+			return false, err
+		case cm.Data[CRLKey] != string(crlPEM):
+			cm = cm.DeepCopy()
+			if cm.Data == nil {
+				cm.Data = make(map[string]string)
+			}
+			cm.Data[CRLKey] = string(crlPEM)
+			if _, err := c.kubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+				return false, err
+			}
+			updated = true
+		}
+	}
+
+	return updated, nil
+}
+
+// parseCRLSignedBy decodes the PEM encoded CRL and returns it only if it was
+// signed by the given CA certificate.
+func parseCRLSignedBy(crlPEM []byte, caCert *x509.Certificate) *pkix.CertificateList {
+	crl, err := x509.ParseCRL(crlPEM)
+	if err != nil {
+		return nil
+	}
+	if err := caCert.CheckCRLSignature(crl); err != nil {
+		return nil
+	}
+	return crl
+}
+
+// refreshTime returns the time at which a new CRL should be generated, which
+// is two thirds through the validity of the given CRL.
+func refreshTime(crl *pkix.CertificateList) time.Time {
+	thisUpdate := crl.TBSCertList.ThisUpdate
+	validity := crl.TBSCertList.NextUpdate.Sub(thisUpdate)
+	return thisUpdate.Add(validity * 2 / 3)
+}
+
+func sameEntries(a, b []pkix.RevokedCertificate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	serials := make(map[string]struct{}, len(a))
+	for _, entry := range a {
+		serials[entry.SerialNumber.String()] = struct{}{}
+	}
+	for _, entry := range b {
+		if _, ok := serials[entry.SerialNumber.String()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// generateCRL creates a new PEM encoded CRL signed by the given CA that is
+// valid for the given duration.
+func generateCRL(caCert *x509.Certificate, caKey crypto.Signer, revoked []pkix.RevokedCertificate, now time.Time, duration time.Duration) ([]byte, error) {
+	template := &x509.RevocationList{
+		RevokedCertificates: revoked,
+		// The CRL number must increase monotonically. The generation time is
+		// used so that the number does not need to be stored separately.
+		Number:     big.NewInt(now.UnixNano()),
+		ThisUpdate: now,
+		NextUpdate: now.Add(duration),
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("error signing CRL: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: crlPEMType, Bytes: der}), nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacrl
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	revocationapi "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func mustCreateCA(t *testing.T, keyUsage x509.KeyUsage, now time.Time) (*x509.Certificate, crypto.Signer, []byte, []byte) {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := pki.EncodePrivateKey(key, cmapi.PKCS8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              keyUsage,
+		PublicKey:             key.Public(),
+	}
+	certPEM, cert, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key, certPEM, keyPEM
+}
+
+func mustSignLeaf(t *testing.T, serial int64, caCert *x509.Certificate, caKey crypto.Signer, now time.Time) []byte {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		PublicKey:    key.Public(),
+	}
+	certPEM, _, err := pki.SignCertificate(template, caCert, key.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	fixedClock := fakeclock.NewFakeClock(now)
+
+	caCert, caKey, caPEM, caKeyPEM := mustCreateCA(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign, now)
+	_, _, noCRLSignPEM, noCRLSignKeyPEM := mustCreateCA(t, x509.KeyUsageCertSign, now)
+
+	caSecret := func(certPEM, keyPEM []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "ca"},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			},
+		}
+	}
+	existingCRL, err := generateCRL(caCert, caKey, []pkix.RevokedCertificate{
+		{SerialNumber: big.NewInt(99), RevocationTime: now.Add(-time.Hour).UTC()},
+	}, now.Add(-time.Hour), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crlSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crl"},
+		Data:       map[string][]byte{CRLKey: existingCRL},
+	}
+
+	issuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{
+			SecretName: "ca",
+			CRL: &cmapi.CAIssuerCRL{
+				SecretName:    "crl",
+				ConfigMapName: "crl",
+			},
+		}),
+	)
+	baseCR := gen.CertificateRequest("cr",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: cmapi.IssuerKind}),
+		gen.SetCertificateRequestCertificate(mustSignLeaf(t, 10, caCert, caKey, now)),
+	)
+	revokedCR := gen.CertificateRequestFrom(baseCR,
+		gen.AddCertificateRequestAnnotations(map[string]string{cmapi.RevokeCertificateAnnotation: "true"}),
+	)
+
+	tests := map[string]struct {
+		kubeObjects []runtime.Object
+		cmObjects   []runtime.Object
+		// expSerials is nil if no CRL is expected to be written
+		expSerials []int64
+		expEvent   string
+	}{
+		"writes an empty CRL if no certificates are revoked": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM)},
+			cmObjects:   []runtime.Object{issuer, baseCR},
+			expSerials:  []int64{},
+			expEvent:    "Normal CRLUpdated CRL updated with 0 revoked certificate(s)",
+		},
+		"adds certificates of annotated CertificateRequests": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM)},
+			cmObjects:   []runtime.Object{issuer, revokedCR},
+			expSerials:  []int64{10},
+			expEvent:    "Normal CRLUpdated CRL updated with 1 revoked certificate(s)",
+		},
+		"ignores annotated CertificateRequests for other issuers": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM)},
+			cmObjects: []runtime.Object{issuer, gen.CertificateRequestFrom(revokedCR,
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "other-issuer", Kind: cmapi.IssuerKind}),
+			)},
+			expSerials: []int64{},
+			expEvent:   "Normal CRLUpdated CRL updated with 0 revoked certificate(s)",
+		},
+		"adds serial numbers of CertificateRevocations": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM)},
+			cmObjects: []runtime.Object{issuer, revokedCR, &revocationapi.CertificateRevocation{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "revoked"},
+				Spec: revocationapi.CertificateRevocationSpec{
+					IssuerRef:    cmmeta.ObjectReference{Name: "ca-issuer"},
+					SerialNumber: "20",
+					Reason:       revocationapi.RevocationReasonKeyCompromise,
+				},
+			}},
+			expSerials: []int64{10, 32},
+			expEvent:   "Normal CRLUpdated CRL updated with 2 revoked certificate(s)",
+		},
+		"ignores CertificateRevocations for other issuers": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM)},
+			cmObjects: []runtime.Object{issuer, &revocationapi.CertificateRevocation{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "revoked"},
+				Spec: revocationapi.CertificateRevocationSpec{
+					IssuerRef:    cmmeta.ObjectReference{Name: "other-issuer"},
+					SerialNumber: "20",
+				},
+			}},
+			expSerials: []int64{},
+			expEvent:   "Normal CRLUpdated CRL updated with 0 revoked certificate(s)",
+		},
+		"keeps entries of an existing CRL signed by the same CA": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM), crlSecret},
+			cmObjects:   []runtime.Object{issuer, revokedCR},
+			expSerials:  []int64{10, 99},
+			expEvent:    "Normal CRLUpdated CRL updated with 2 revoked certificate(s)",
+		},
+		"does not write a CRL if the CA certificate cannot sign CRLs": {
+			kubeObjects: []runtime.Object{caSecret(noCRLSignPEM, noCRLSignKeyPEM)},
+			cmObjects:   []runtime.Object{issuer, revokedCR},
+			expEvent:    "Warning CRLError CA certificate does not have the cRLSign key usage set, cannot generate CRL",
+		},
+		"does not write a CRL to a Secret holding a certificate": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crl"},
+				Data:       map[string][]byte{corev1.TLSCertKey: caPEM},
+			}},
+			cmObjects: []runtime.Object{issuer, revokedCR},
+			expEvent:  `Warning CRLError Secret "crl" contains a certificate or private key, refusing to write the CRL to it`,
+		},
+		"does nothing if the CA secret does not exist": {
+			cmObjects: []runtime.Object{issuer, revokedCR},
+		},
+		"does nothing if the issuer has no CRL configured": {
+			kubeObjects: []runtime.Object{caSecret(caPEM, caKeyPEM)},
+			cmObjects: []runtime.Object{gen.IssuerFrom(issuer, gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"})),
+				revokedCR},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				KubeObjects:        test.kubeObjects,
+				CertManagerObjects: test.cmObjects,
+			}
+			builder.Init()
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.ProcessItem(context.Background(), "testns/ca-issuer"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			secret, secretErr := builder.Client.CoreV1().Secrets("testns").Get(context.Background(), "crl", metav1.GetOptions{})
+			cm, cmErr := builder.Client.CoreV1().ConfigMaps("testns").Get(context.Background(), "crl", metav1.GetOptions{})
+			if test.expSerials == nil {
+				if cmErr == nil {
+					t.Errorf("expected no CRL ConfigMap to be written")
+				}
+				if secretErr == nil && len(secret.Data[CRLKey]) > 0 && string(secret.Data[CRLKey]) != string(existingCRL) {
+					t.Errorf("expected no CRL Secret to be written")
+				}
+			} else {
+				if secretErr != nil || cmErr != nil {
+					t.Fatalf("expected CRL to be written, got errors %v, %v", secretErr, cmErr)
+				}
+				if string(secret.Data[CRLKey]) != cm.Data[CRLKey] {
+					t.Errorf("expected Secret and ConfigMap to contain the same CRL")
+				}
+				crl := parseCRLSignedBy(secret.Data[CRLKey], caCert)
+				if crl == nil {
+					t.Fatalf("CRL was not signed by the CA")
+				}
+				var serials []int64
+				for _, entry := range crl.TBSCertList.RevokedCertificates {
+					serials = append(serials, entry.SerialNumber.Int64())
+				}
+				if len(serials) != len(test.expSerials) {
+					t.Fatalf("unexpected revoked serials, exp=%v got=%v", test.expSerials, serials)
+				}
+				for i := range serials {
+					if serials[i] != test.expSerials[i] {
+						t.Errorf("unexpected revoked serials, exp=%v got=%v", test.expSerials, serials)
+					}
+				}
+				if !crl.TBSCertList.NextUpdate.Equal(now.Add(defaultCRLDuration)) {
+					t.Errorf("unexpected nextUpdate %s", crl.TBSCertList.NextUpdate)
+				}
+			}
+
+			events := builder.Events()
+			if test.expEvent == "" {
+				if len(events) > 0 {
+					t.Errorf("expected no events, got %v", events)
+				}
+			} else if len(events) != 1 || events[0] != test.expEvent {
+				t.Errorf("unexpected events, exp=%q got=%v", test.expEvent, events)
+			}
+		})
+	}
+}
@@ -107,6 +107,9 @@ type genericInjectReconciler struct {
 	client.Client
 
 	resourceName string // just used for logging
+
+	// shard is the subset of targets that this reconciler injects CAs into.
+	shard Shard
 }
 
 // splitNamespacedName turns the string form of a namespaced name
@@ -125,6 +128,12 @@ func (r *genericInjectReconciler) Reconcile(_ context.Context, req ctrl.Request)
 	ctx := context.Background()
 	log := r.log.WithValues(r.resourceName, req.NamespacedName)
 
+	// targets of other shards are injected by other cainjector instances
+	if !r.shard.Owns(req.NamespacedName) {
+		log.V(logf.DebugLevel).Info("ignoring", "reason", "target belongs to another shard")
+		return ctrl.Result{}, nil
+	}
+
 	// fetch the target object
 	target := r.injector.NewTarget()
 	if err := r.Client.Get(ctx, req.NamespacedName, target.AsObject()); err != nil {
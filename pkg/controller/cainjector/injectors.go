@@ -17,10 +17,17 @@ limitations under the License.
 package cainjector
 
 import (
+	"encoding/base64"
+	"strings"
+
 	admissionreg "k8s.io/api/admissionregistration/v1"
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	apireg "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 )
 
 // this contains implementations of CertInjector (and dependents)
@@ -140,3 +147,77 @@ func (t *crdConversionTarget) SetCA(data []byte) {
 	}
 	t.obj.Spec.Conversion.Webhook.ClientConfig.CABundle = data
 }
+
+// genericInjector knows how to create an InjectTarget for an arbitrary kind,
+// e.g. a custom resource of an operator. The field that the CA is injected
+// into is given by the `cert-manager.io/inject-ca-into` annotation.
+type genericInjector struct {
+	gvk schema.GroupVersionKind
+}
+
+func (i genericInjector) NewTarget() InjectTarget {
+	t := &genericTarget{}
+	t.obj.SetGroupVersionKind(i.gvk)
+	return t
+}
+
+// IsAlpha returns true so that the cainjector still starts if the kind is not
+// (yet) served by the API server.
+func (i genericInjector) IsAlpha() bool {
+	return true
+}
+
+// genericTarget knows how to set CA data for the field of an unstructured
+// object named by its `cert-manager.io/inject-ca-into` annotation.
+type genericTarget struct {
+	obj unstructured.Unstructured
+}
+
+func (t *genericTarget) AsObject() client.Object {
+	return &t.obj
+}
+
+func (t *genericTarget) SetCA(data []byte) {
+	annotations := t.obj.GetAnnotations()
+	fields := injectFieldPath(annotations[cmapi.WantInjectIntoFieldAnnotation])
+	if len(fields) == 0 {
+		return
+	}
+
+	var value string
+	switch annotations[cmapi.WantInjectFormatAnnotation] {
+	case "pem":
+		value = string(data)
+	case "", "base64":
+		value = base64.StdEncoding.EncodeToString(data)
+	default:
+		return
+	}
+
+	// an error is only returned if one of the parent fields is not an
+	// object, in which case there is nowhere to inject the CA into
+	_ = unstructured.SetNestedField(t.obj.Object, value, fields...)
+}
+
+// injectFieldPath splits the given field path, e.g. `spec.caBundle`, into its
+// fields. The path may be given as a simple JSONPath, e.g. `{.spec.caBundle}`.
+// Paths into the metadata of the object are refused.
+func injectFieldPath(path string) []string {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil
+	}
+
+	fields := strings.Split(path, ".")
+	for _, field := range fields {
+		if field == "" {
+			return nil
+		}
+	}
+	if fields[0] == "metadata" || fields[0] == "apiVersion" || fields[0] == "kind" {
+		return nil
+	}
+
+	return fields
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cainjector
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestGenericTargetSetCA(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"}
+	ca := []byte("-----BEGIN CERTIFICATE-----")
+
+	tests := map[string]struct {
+		annotations map[string]string
+		spec        map[string]interface{}
+		expSpec     map[string]interface{}
+	}{
+		"no field annotation does not inject": {
+			annotations: map[string]string{},
+			expSpec:     nil,
+		},
+		"inject base64 encoded CA by default": {
+			annotations: map[string]string{cmapi.WantInjectIntoFieldAnnotation: "spec.caBundle"},
+			expSpec:     map[string]interface{}{"caBundle": "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t"},
+		},
+		"inject PEM encoded CA into a nested field given as JSONPath": {
+			annotations: map[string]string{
+				cmapi.WantInjectIntoFieldAnnotation: "{.spec.tls.ca}",
+				cmapi.WantInjectFormatAnnotation:    "pem",
+			},
+			spec: map[string]interface{}{"replicas": int64(1)},
+			expSpec: map[string]interface{}{
+				"replicas": int64(1),
+				"tls":      map[string]interface{}{"ca": "-----BEGIN CERTIFICATE-----"},
+			},
+		},
+		"unknown format does not inject": {
+			annotations: map[string]string{
+				cmapi.WantInjectIntoFieldAnnotation: "spec.caBundle",
+				cmapi.WantInjectFormatAnnotation:    "der",
+			},
+			expSpec: nil,
+		},
+		"refuse to inject into metadata": {
+			annotations: map[string]string{cmapi.WantInjectIntoFieldAnnotation: "metadata.labels.ca"},
+			expSpec:     nil,
+		},
+		"refuse to inject into an empty field": {
+			annotations: map[string]string{cmapi.WantInjectIntoFieldAnnotation: "spec..caBundle"},
+			expSpec:     nil,
+		},
+		"do not inject if a parent field is not an object": {
+			annotations: map[string]string{cmapi.WantInjectIntoFieldAnnotation: "spec.replicas.ca"},
+			spec:        map[string]interface{}{"replicas": int64(1)},
+			expSpec:     map[string]interface{}{"replicas": int64(1)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			target := genericInjector{gvk: gvk}.NewTarget()
+			obj := target.AsObject().(*unstructured.Unstructured)
+			obj.SetAnnotations(test.annotations)
+			if test.spec != nil {
+				obj.Object["spec"] = test.spec
+			}
+
+			target.SetCA(ca)
+
+			if obj.GroupVersionKind() != gvk {
+				t.Errorf("unexpected kind of target, exp=%s got=%s", gvk, obj.GroupVersionKind())
+			}
+			spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec, test.expSpec) {
+				t.Errorf("unexpected spec after injection, exp=%#v got=%#v", test.expSpec, spec)
+			}
+		})
+	}
+}
@@ -20,13 +20,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"golang.org/x/sync/errgroup"
 	admissionreg "k8s.io/api/admissionregistration/v1"
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	apireg "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -73,21 +76,50 @@ var (
 	ControllerNames []string
 )
 
+// Options configures the injection controllers.
+type Options struct {
+	// GenericKinds are additional kinds that CAs are injected into, see
+	// genericInjector.
+	GenericKinds []schema.GroupVersionKind
+
+	// Shard is the subset of injection targets that CAs are injected into.
+	Shard Shard
+}
+
+// genericSetup returns the setup of the injector controller for generic
+// injection targets of the given kind.
+func genericSetup(gvk schema.GroupVersionKind) injectorSetup {
+	listType := &unstructured.UnstructuredList{}
+	listType.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	return injectorSetup{
+		resourceName: strings.ToLower(gvk.GroupKind().String()),
+		injector:     genericInjector{gvk: gvk},
+		listType:     listType,
+	}
+}
+
 // registerAllInjectors registers all injectors and based on the
 // graduation state of the injector decides how to log no kind/resource match errors
-func registerAllInjectors(ctx context.Context, groupName string, mgr ctrl.Manager, sources []caDataSource, client client.Client, ca cache.Cache) error {
-	controllers := make([]controller.Controller, len(injectorSetups))
-	for i, setup := range injectorSetups {
-		controller, err := newGenericInjectionController(ctx, groupName, mgr, setup, sources, ca, client)
+func registerAllInjectors(ctx context.Context, groupName string, mgr ctrl.Manager, sources []caDataSource, client client.Client, ca cache.Cache, opts Options) error {
+	setups := append([]injectorSetup{}, injectorSetups...)
+	for _, gvk := range opts.GenericKinds {
+		setups = append(setups, genericSetup(gvk))
+	}
+
+	var controllers []controller.Controller
+	for _, setup := range setups {
+		controller, err := newGenericInjectionController(ctx, groupName, mgr, setup, sources, ca, client, opts.Shard)
 		if err != nil {
 			if !meta.IsNoMatchError(err) || !setup.injector.IsAlpha() {
 				return err
 			}
-			ctrl.Log.V(logf.WarnLevel).Info("unable to register injector which is still in an alpha phase."+
-				" Enable the feature on the API server in order to use this injector",
+			ctrl.Log.V(logf.WarnLevel).Info("unable to register injector as its kind is not served by the API server."+
+				" Enable the feature or install the CRD on the API server in order to use this injector",
 				"injector", setup.resourceName)
+			continue
 		}
-		controllers[i] = controller
+		controllers = append(controllers, controller)
 	}
 	g, gctx := errgroup.WithContext(ctx)
 
@@ -124,7 +156,7 @@ func registerAllInjectors(ctx context.Context, groupName string, mgr ctrl.Manage
 // * https://github.com/kubernetes-sigs/controller-runtime/issues/764
 func newGenericInjectionController(ctx context.Context, groupName string, mgr ctrl.Manager,
 	setup injectorSetup, sources []caDataSource, ca cache.Cache,
-	client client.Client) (controller.Controller, error) {
+	client client.Client, shard Shard) (controller.Controller, error) {
 	log := ctrl.Log.WithName(groupName).WithName(setup.resourceName)
 	typ := setup.injector.NewTarget().AsObject()
 
@@ -138,6 +170,7 @@ func newGenericInjectionController(ctx context.Context, groupName string, mgr ct
 				log:          log.WithName("generic-inject-reconciler"),
 				resourceName: setup.resourceName,
 				injector:     setup.injector,
+				shard:        shard,
 			},
 			Log: log,
 		})
@@ -178,7 +211,7 @@ func dataFromSliceOrFile(data []byte, file string) ([]byte, error) {
 // indices.
 // The registered controllers require the cert-manager API to be available
 // in order to run.
-func RegisterCertificateBased(ctx context.Context, mgr ctrl.Manager) error {
+func RegisterCertificateBased(ctx context.Context, mgr ctrl.Manager, opts Options) error {
 	cache, client, err := newIndependentCacheAndDelegatingClient(mgr)
 	if err != nil {
 		return err
@@ -192,6 +225,7 @@ func RegisterCertificateBased(ctx context.Context, mgr ctrl.Manager) error {
 		},
 		client,
 		cache,
+		opts,
 	)
 }
 
@@ -200,7 +234,7 @@ func RegisterCertificateBased(ctx context.Context, mgr ctrl.Manager) error {
 // indices.
 // The registered controllers only require the corev1 APi to be available in
 // order to run.
-func RegisterSecretBased(ctx context.Context, mgr ctrl.Manager) error {
+func RegisterSecretBased(ctx context.Context, mgr ctrl.Manager, opts Options) error {
 	cache, client, err := newIndependentCacheAndDelegatingClient(mgr)
 	if err != nil {
 		return err
@@ -215,6 +249,7 @@ func RegisterSecretBased(ctx context.Context, mgr ctrl.Manager) error {
 		},
 		client,
 		cache,
+		opts,
 	)
 }
 
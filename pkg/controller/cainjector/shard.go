@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cainjector
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Shard identifies the subset of injection targets that an instance of the
+// cainjector is responsible for. Targets are assigned to one of Count shards
+// by the hash of their namespace and name, so that the injection work of very
+// large clusters can be spread across multiple cainjector instances.
+type Shard struct {
+	// Index of this shard, from 0 to Count-1.
+	Index int
+
+	// Count is the total number of shards. Sharding is disabled if Count is
+	// less than 2.
+	Count int
+}
+
+// Validate checks that the shard count is positive and that the index of the
+// shard is within the shard count.
+func (s Shard) Validate() error {
+	if s.Count < 1 {
+		return fmt.Errorf("shard count must be at least 1, got %d", s.Count)
+	}
+	if s.Index < 0 || s.Index >= s.Count {
+		return fmt.Errorf("shard index must be between 0 and %d, got %d", s.Count-1, s.Index)
+	}
+	return nil
+}
+
+// Enabled returns true if targets are split across more than one shard.
+func (s Shard) Enabled() bool {
+	return s.Count > 1
+}
+
+// Owns returns true if the injection target with the given name is assigned
+// to this shard.
+func (s Shard) Owns(name types.NamespacedName) bool {
+	if !s.Enabled() {
+		return true
+	}
+	h := fnv.New32a()
+	// writing to a hash never returns an error
+	_, _ = h.Write([]byte(name.String()))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
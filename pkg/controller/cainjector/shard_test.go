@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cainjector
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestShardValidate(t *testing.T) {
+	tests := map[string]struct {
+		shard  Shard
+		expErr bool
+	}{
+		"a single shard is valid": {
+			shard: Shard{Index: 0, Count: 1},
+		},
+		"the last of multiple shards is valid": {
+			shard: Shard{Index: 2, Count: 3},
+		},
+		"a shard count of zero is invalid": {
+			shard:  Shard{Index: 0, Count: 0},
+			expErr: true,
+		},
+		"a negative shard index is invalid": {
+			shard:  Shard{Index: -1, Count: 3},
+			expErr: true,
+		},
+		"a shard index equal to the shard count is invalid": {
+			shard:  Shard{Index: 3, Count: 3},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.shard.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestShardOwns(t *testing.T) {
+	var names []types.NamespacedName
+	for i := 0; i < 100; i++ {
+		names = append(names,
+			types.NamespacedName{Name: fmt.Sprintf("webhook-%d", i)},
+			types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("target-%d", i)},
+		)
+	}
+
+	for _, count := range []int{1, 2, 5} {
+		t.Run(fmt.Sprintf("%d shards", count), func(t *testing.T) {
+			owned := make([]int, count)
+			for _, name := range names {
+				owners := 0
+				for index := 0; index < count; index++ {
+					if (Shard{Index: index, Count: count}).Owns(name) {
+						owners++
+						owned[index]++
+					}
+				}
+				if owners != 1 {
+					t.Errorf("expected %s to be owned by exactly one shard, got %d", name, owners)
+				}
+			}
+			for index, n := range owned {
+				if n == 0 {
+					t.Errorf("expected shard %d to own at least one of %d targets", index, len(names))
+				}
+			}
+		})
+	}
+
+	if !(Shard{}).Owns(names[0]) {
+		t.Errorf("expected the zero value of Shard to own every target")
+	}
+}
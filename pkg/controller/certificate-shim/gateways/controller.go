@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
-	gwlisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1alpha1"
+	gwlisters "sigs.k8s.io/gateway-api/pkg/client/listers/gateway/apis/v1alpha2"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
@@ -53,16 +55,18 @@ type controller struct {
 }
 
 func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
-	c.gatewayLister = ctx.GWShared.Networking().V1alpha1().Gateways().Lister()
+	c.gatewayLister = ctx.GWShared.Gateway().V1alpha2().Gateways().Lister()
 	log := logf.FromContext(ctx.RootContext, ControllerName)
-	c.sync = shimhelper.SyncFnFor(ctx.Recorder, log, ctx.CMClient, ctx.SharedInformerFactory.Certmanager().V1().Certificates().Lister(), ctx.IngressShimOptions)
+	namespaceInformer := ctx.KubeSharedInformerFactory.Core().V1().Namespaces()
+	c.sync = shimhelper.SyncFnFor(ctx.Recorder, log, ctx.CMClient, ctx.SharedInformerFactory.Certmanager().V1().Certificates().Lister(), namespaceInformer.Lister(), ctx.IngressShimOptions)
 
 	// We don't need to requeue Gateways on "Deleted" events, since our Sync
 	// function does nothing when the Gateway lister returns "not found". But we
 	// still do it for consistency with the rest of the controllers.
-	ctx.GWShared.Networking().V1alpha1().Gateways().Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{
+	ctx.GWShared.Gateway().V1alpha2().Gateways().Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{
 		Queue: c.queue,
 	})
+	ctx.RequeueOnNamespaceChange(ctx.GWShared.Gateway().V1alpha2().Gateways().Informer(), (&controllerpkg.QueuingEventHandler{Queue: c.queue}).Enqueue)
 
 	// Even thought the Gateway controller already re-queues the Gateway after
 	// creating a child Certificate, we still re-queue the Gateway when we
@@ -78,9 +82,17 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 		WorkFunc: certificateHandler(c.queue),
 	})
 
+	// The subject of Certificates may be templated from the labels and
+	// annotations of the namespace, so all Gateways in a namespace are
+	// re-queued when it changes.
+	namespaceInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: namespaceHandler(c.gatewayLister, c.queue),
+	})
+
 	mustSync := []cache.InformerSynced{
-		ctx.GWShared.Networking().V1alpha1().Gateways().Informer().HasSynced,
+		ctx.GWShared.Gateway().V1alpha2().Gateways().Informer().HasSynced,
 		ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer().HasSynced,
+		namespaceInformer.Informer().HasSynced,
 	}
 
 	return c.queue, mustSync, nil
@@ -117,7 +129,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 //       namespace: cert-1                                 reference does not
 //       ownerReferences:                                  have a namespace,
 //       - controller: true                                since owner refs
-//         apiVersion: networking.x-k8s.io/v1alpha1        only work inside
+//         apiVersion: gateway.networking.k8s.io/v1alpha2  only work inside
 //         kind: Gateway                                   the same namespace.
 //         name: gateway-1
 //         blockOwnerDeletion: true
@@ -137,7 +149,7 @@ func certificateHandler(queue workqueue.RateLimitingInterface) func(obj interfac
 			return
 		}
 
-		// We don't check the apiVersion e.g. "networking.x-k8s.io/v1alpha1"
+		// We don't check the apiVersion e.g. "gateway.networking.k8s.io/v1alpha2"
 		// because there is no chance that another object called "Gateway" be
 		// the controller of a Certificate.
 		if ref.Kind != "Gateway" {
@@ -148,10 +160,31 @@ func certificateHandler(queue workqueue.RateLimitingInterface) func(obj interfac
 	}
 }
 
+// namespaceHandler re-queues every Gateway in a namespace whenever the
+// namespace is added, updated or deleted.
+func namespaceHandler(lister gwlisters.GatewayLister, queue workqueue.RateLimitingInterface) func(obj interface{}) {
+	return func(obj interface{}) {
+		ns, ok := obj.(*corev1.Namespace)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("not a Namespace object: %#v", obj))
+			return
+		}
+
+		gateways, err := lister.Gateways(ns.Name).List(labels.Everything())
+		if err != nil {
+			runtime.HandleError(fmt.Errorf("failed to list Gateways in namespace %q: %w", ns.Name, err))
+			return
+		}
+		for _, gw := range gateways {
+			queue.Add(gw.Namespace + "/" + gw.Name)
+		}
+	}
+}
+
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controller{queue: workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)}).
+			For(&controller{queue: workqueue.NewNamedRateLimitingQueue(ctx.WorkqueueOptions.RateLimiter(ControllerName, controllerpkg.DefaultRateLimiterOptions), ControllerName)}).
 			Complete()
 	})
 }
@@ -17,8 +17,11 @@ limitations under the License.
 package shimhelper
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +29,7 @@ import (
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
 
 var (
@@ -43,6 +47,8 @@ var (
 //       cert-manager.io/duration: 2160h
 //       cert-manager.io/renew-before: 1440h
 //       cert-manager.io/usages: "digital signature,key encipherment"
+//       cert-manager.io/pkcs12-secret-password-ref: keystore-password/password
+//       cert-manager.io/additional-output-formats: DER
 //
 // is mapped to the following Certificate:
 //
@@ -54,6 +60,14 @@ var (
 //     usages:
 //       - digital signature
 //       - key encipherment
+//     keystores:
+//       pkcs12:
+//         create: true
+//         passwordSecretRef:
+//           name: keystore-password
+//           key: password
+//     additionalOutputFormats:
+//       - type: DER
 func translateAnnotations(crt *cmapi.Certificate, ingLikeAnnotations map[string]string) error {
 	if crt == nil {
 		return errNilCertificate
@@ -92,5 +106,103 @@ func translateAnnotations(crt *cmapi.Certificate, ingLikeAnnotations map[string]
 		}
 		crt.Spec.Usages = newUsages
 	}
+
+	if ref, found := ingLikeAnnotations[cmapi.PKCS12SecretPasswordRefAnnotationKey]; found {
+		selector, err := parseSecretKeySelector(ref)
+		if err != nil {
+			return fmt.Errorf("%w %q: %v", errInvalidIngressAnnotation, cmapi.PKCS12SecretPasswordRefAnnotationKey, err)
+		}
+		if crt.Spec.Keystores == nil {
+			crt.Spec.Keystores = &cmapi.CertificateKeystores{}
+		}
+		crt.Spec.Keystores.PKCS12 = &cmapi.PKCS12Keystore{Create: true, PasswordSecretRef: selector}
+	}
+
+	if ref, found := ingLikeAnnotations[cmapi.JKSSecretPasswordRefAnnotationKey]; found {
+		selector, err := parseSecretKeySelector(ref)
+		if err != nil {
+			return fmt.Errorf("%w %q: %v", errInvalidIngressAnnotation, cmapi.JKSSecretPasswordRefAnnotationKey, err)
+		}
+		if crt.Spec.Keystores == nil {
+			crt.Spec.Keystores = &cmapi.CertificateKeystores{}
+		}
+		crt.Spec.Keystores.JKS = &cmapi.JKSKeystore{Create: true, PasswordSecretRef: selector}
+	}
+
+	if formats, found := ingLikeAnnotations[cmapi.AdditionalOutputFormatsAnnotationKey]; found {
+		var newFormats []cmapi.CertificateAdditionalOutputFormat
+		for _, formatName := range strings.Split(formats, ",") {
+			format := cmapi.CertificateOutputFormatType(strings.Trim(formatName, " "))
+			switch format {
+			case cmapi.CertificateOutputFormatDER, cmapi.CertificateOutputFormatCombinedPEM:
+			default:
+				return fmt.Errorf("%w %q: invalid output format %q", errInvalidIngressAnnotation, cmapi.AdditionalOutputFormatsAnnotationKey, formatName)
+			}
+			newFormats = append(newFormats, cmapi.CertificateAdditionalOutputFormat{Type: format})
+		}
+		crt.Spec.AdditionalOutputFormats = newFormats
+	}
 	return nil
 }
+
+// parseSecretKeySelector parses a reference to a key in a Secret of the form
+// "<secret name>/<key>".
+func parseSecretKeySelector(ref string) (cmmeta.SecretKeySelector, error) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return cmmeta.SecretKeySelector{}, fmt.Errorf("%q must be of the form <secret name>/<key>", ref)
+	}
+	return cmmeta.SecretKeySelector{
+		LocalObjectReference: cmmeta.LocalObjectReference{Name: parts[0]},
+		Key:                  parts[1],
+	}, nil
+}
+
+// cleanupPolicyFor returns the value of the certificate-cleanup-policy
+// annotation on the ingress-like object, defaulting to "Retain".
+func cleanupPolicyFor(ingLikeAnnotations map[string]string) (string, error) {
+	policy, found := ingLikeAnnotations[cmapi.IngressCertificateCleanupPolicyAnnotationKey]
+	if !found {
+		return cmapi.CertificateCleanupPolicyRetain, nil
+	}
+	switch policy {
+	case cmapi.CertificateCleanupPolicyRetain, cmapi.CertificateCleanupPolicyDelete:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w %q: must be one of %q or %q, got %q", errInvalidIngressAnnotation,
+		cmapi.IngressCertificateCleanupPolicyAnnotationKey, cmapi.CertificateCleanupPolicyRetain, cmapi.CertificateCleanupPolicyDelete, policy)
+}
+
+// editPolicyFor returns the value of the certificate-edit-policy annotation
+// on the ingress-like object, defaulting to "Revert".
+func editPolicyFor(ingLikeAnnotations map[string]string) (string, error) {
+	policy, found := ingLikeAnnotations[cmapi.IngressCertificateEditPolicyAnnotationKey]
+	if !found {
+		return cmapi.CertificateEditPolicyRevert, nil
+	}
+	switch policy {
+	case cmapi.CertificateEditPolicyRevert, cmapi.CertificateEditPolicyAllow:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w %q: must be one of %q or %q, got %q", errInvalidIngressAnnotation,
+		cmapi.IngressCertificateEditPolicyAnnotationKey, cmapi.CertificateEditPolicyRevert, cmapi.CertificateEditPolicyAllow, policy)
+}
+
+// certificateSpecHash hashes the parts of the Certificate that are derived
+// from the ingress-like object. It is used with the "Allow" edit policy to
+// tell apart changes made to the Ingress or Gateway from edits made directly
+// to the Certificate.
+func certificateSpecHash(crt *cmapi.Certificate) (string, error) {
+	b, err := json.Marshal(struct {
+		Labels map[string]string     `json:"labels,omitempty"`
+		Spec   cmapi.CertificateSpec `json:"spec"`
+	}{crt.Labels, crt.Spec})
+	if err != nil {
+		return "", err
+	}
+	hashF := fnv.New32()
+	if _, err := hashF.Write(b); err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(uint64(hashF.Sum32()), 10), nil
+}
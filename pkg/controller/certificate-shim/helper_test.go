@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -94,6 +95,52 @@ func Test_translateAnnotations(t *testing.T) {
 			},
 			expectedError: errInvalidIngressAnnotation,
 		},
+		"keystores and additional output formats": {
+			crt: gen.Certificate("example-cert"),
+			annotations: map[string]string{
+				cmapi.PKCS12SecretPasswordRefAnnotationKey: "pkcs12-password/password",
+				cmapi.JKSSecretPasswordRefAnnotationKey:    "jks-password/pass",
+				cmapi.AdditionalOutputFormatsAnnotationKey: "DER, CombinedPEM",
+			},
+			check: func(a *assert.Assertions, crt *cmapi.Certificate) {
+				a.Equal(&cmapi.CertificateKeystores{
+					PKCS12: &cmapi.PKCS12Keystore{
+						Create: true,
+						PasswordSecretRef: cmmeta.SecretKeySelector{
+							LocalObjectReference: cmmeta.LocalObjectReference{Name: "pkcs12-password"},
+							Key:                  "password",
+						},
+					},
+					JKS: &cmapi.JKSKeystore{
+						Create: true,
+						PasswordSecretRef: cmmeta.SecretKeySelector{
+							LocalObjectReference: cmmeta.LocalObjectReference{Name: "jks-password"},
+							Key:                  "pass",
+						},
+					},
+				}, crt.Spec.Keystores)
+				a.Equal([]cmapi.CertificateAdditionalOutputFormat{
+					{Type: cmapi.CertificateOutputFormatDER},
+					{Type: cmapi.CertificateOutputFormatCombinedPEM},
+				}, crt.Spec.AdditionalOutputFormats)
+			},
+		},
+		"bad keystore password ref": {
+			crt:         gen.Certificate("example-cert"),
+			annotations: validAnnotations(),
+			mutate: func(tc *testCase) {
+				tc.annotations[cmapi.PKCS12SecretPasswordRefAnnotationKey] = "missing-key"
+			},
+			expectedError: errInvalidIngressAnnotation,
+		},
+		"bad additional output format": {
+			crt:         gen.Certificate("example-cert"),
+			annotations: validAnnotations(),
+			mutate: func(tc *testCase) {
+				tc.annotations[cmapi.AdditionalOutputFormatsAnnotationKey] = "DER,PFX"
+			},
+			expectedError: errInvalidIngressAnnotation,
+		},
 		"bad usage list": {
 			crt:         gen.Certificate("example-cert"),
 			annotations: validAnnotations(),
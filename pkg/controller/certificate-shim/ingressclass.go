@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimhelper
+
+import (
+	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// ParseIngressClassDefaultIssuers parses a map of IngressClass names to
+// issuer references. Each issuer reference has the form
+// "<kind>[.<group>]/<name>", for example:
+//
+//   nginx=ClusterIssuer/letsencrypt
+//   internal=Issuer/ca
+//   venafi=VenafiClusterIssuer.jetstack.io/tpp
+//
+// The group defaults to cert-manager.io, in which case the kind must be
+// either Issuer or ClusterIssuer.
+func ParseIngressClassDefaultIssuers(in map[string]string) (map[string]cmmeta.ObjectReference, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]cmmeta.ObjectReference, len(in))
+	for class, value := range in {
+		if len(class) == 0 {
+			return nil, fmt.Errorf("ingress class name must not be empty")
+		}
+		ref, err := parseIssuerRef(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issuer for ingress class %q: %w", class, err)
+		}
+		out[class] = ref
+	}
+	return out, nil
+}
+
+func parseIssuerRef(value string) (cmmeta.ObjectReference, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return cmmeta.ObjectReference{}, fmt.Errorf("%q must be of the form <kind>[.<group>]/<name>", value)
+	}
+	name := parts[1]
+	kind, group := parts[0], cmapi.SchemeGroupVersion.Group
+	if i := strings.Index(parts[0], "."); i >= 0 {
+		kind, group = parts[0][:i], parts[0][i+1:]
+	}
+	if len(group) == 0 {
+		return cmmeta.ObjectReference{}, fmt.Errorf("%q has an empty group", value)
+	}
+	if group == cmapi.SchemeGroupVersion.Group && kind != cmapi.IssuerKind && kind != cmapi.ClusterIssuerKind {
+		return cmmeta.ObjectReference{}, fmt.Errorf("kind must be either %q or %q, got %q", cmapi.IssuerKind, cmapi.ClusterIssuerKind, kind)
+	}
+	return cmmeta.ObjectReference{Name: name, Kind: kind, Group: group}, nil
+}
+
+// ingressClassFor returns the class of an Ingress, read from
+// spec.ingressClassName or, for older Ingresses, the
+// kubernetes.io/ingress.class annotation. Other ingress-like objects have no
+// class.
+func ingressClassFor(ingLike metav1.Object) string {
+	ing, ok := ingLike.(*networkingv1.Ingress)
+	if !ok {
+		return ""
+	}
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName
+	}
+	return ing.Annotations[cmapi.IngressClassAnnotationKey]
+}
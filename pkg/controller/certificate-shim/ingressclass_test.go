@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestParseIngressClassDefaultIssuers(t *testing.T) {
+	tests := map[string]struct {
+		in     map[string]string
+		exp    map[string]cmmeta.ObjectReference
+		expErr bool
+	}{
+		"nil input returns nil": {},
+		"cert-manager kinds default to the cert-manager.io group": {
+			in: map[string]string{
+				"nginx":    "ClusterIssuer/letsencrypt",
+				"internal": "Issuer/ca",
+			},
+			exp: map[string]cmmeta.ObjectReference{
+				"nginx":    {Name: "letsencrypt", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+				"internal": {Name: "ca", Kind: "Issuer", Group: "cert-manager.io"},
+			},
+		},
+		"external issuers include their group": {
+			in: map[string]string{"venafi": "VenafiClusterIssuer.jetstack.io/tpp"},
+			exp: map[string]cmmeta.ObjectReference{
+				"venafi": {Name: "tpp", Kind: "VenafiClusterIssuer", Group: "jetstack.io"},
+			},
+		},
+		"missing kind is an error": {
+			in:     map[string]string{"nginx": "letsencrypt"},
+			expErr: true,
+		},
+		"missing name is an error": {
+			in:     map[string]string{"nginx": "ClusterIssuer/"},
+			expErr: true,
+		},
+		"unknown cert-manager.io kind is an error": {
+			in:     map[string]string{"nginx": "Certificate/letsencrypt"},
+			expErr: true,
+		},
+		"empty group is an error": {
+			in:     map[string]string{"nginx": "ClusterIssuer./letsencrypt"},
+			expErr: true,
+		},
+		"empty class is an error": {
+			in:     map[string]string{"": "ClusterIssuer/letsencrypt"},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseIngressClassDefaultIssuers(test.in)
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			assert.Equal(t, test.exp, got)
+		})
+	}
+}
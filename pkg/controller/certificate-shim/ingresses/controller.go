@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -53,13 +55,15 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.ingressLister = internalIngressLister
 
 	log := logf.FromContext(ctx.RootContext, ControllerName)
-	c.sync = shimhelper.SyncFnFor(ctx.Recorder, log, ctx.CMClient, cmShared.Certmanager().V1().Certificates().Lister(), ctx.IngressShimOptions)
+	namespaceInformer := ctx.KubeSharedInformerFactory.Core().V1().Namespaces()
+	c.sync = shimhelper.SyncFnFor(ctx.Recorder, log, ctx.CMClient, cmShared.Certmanager().V1().Certificates().Lister(), namespaceInformer.Lister(), ctx.IngressShimOptions)
 
-	queue := workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(ctx.WorkqueueOptions.RateLimiter(ControllerName, controllerpkg.DefaultRateLimiterOptions), ControllerName)
 
 	mustSync := []cache.InformerSynced{
 		internalIngressInformer.HasSynced,
 		cmShared.Certmanager().V1().Certificates().Informer().HasSynced,
+		namespaceInformer.Informer().HasSynced,
 	}
 
 	// We still requeue on "Deleted" for consistency with the rest of the
@@ -71,6 +75,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	internalIngressInformer.AddEventHandler(&controllerpkg.QueuingEventHandler{
 		Queue: queue,
 	})
+	ctx.RequeueOnNamespaceChange(internalIngressInformer, (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
 
 	// We still re-queue on "Add" because the workqueue will remove any
 	// duplicate key, although the Ingress controller already re-queues the
@@ -85,6 +90,13 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 		WorkFunc: certificateHandler(queue),
 	})
 
+	// The subject of Certificates may be templated from the labels and
+	// annotations of the namespace, so all Ingresses in a namespace are
+	// re-queued when it changes.
+	namespaceInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: namespaceHandler(internalIngressLister, queue),
+	})
+
 	return queue, mustSync, nil
 }
 
@@ -150,6 +162,27 @@ func certificateHandler(queue workqueue.RateLimitingInterface) func(obj interfac
 	}
 }
 
+// namespaceHandler re-queues every Ingress in a namespace whenever the
+// namespace is added, updated or deleted.
+func namespaceHandler(lister ingress.InternalIngressLister, queue workqueue.RateLimitingInterface) func(obj interface{}) {
+	return func(obj interface{}) {
+		ns, ok := obj.(*corev1.Namespace)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("not a Namespace object: %#v", obj))
+			return
+		}
+
+		ingresses, err := lister.Ingresses(ns.Name).List(labels.Everything())
+		if err != nil {
+			runtime.HandleError(fmt.Errorf("failed to list Ingresses in namespace %q: %w", ns.Name, err))
+			return
+		}
+		for _, ing := range ingresses {
+			queue.Add(ing.Namespace + "/" + ing.Name)
+		}
+	}
+}
+
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
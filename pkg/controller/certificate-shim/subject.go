@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimhelper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+// subjectTemplateData is the data that subject templates are evaluated
+// against. For example, the template:
+//
+//   {{ .Namespace.Labels.team }}
+//
+// evaluates to the value of the "team" label on the namespace of the
+// ingress-like object.
+type subjectTemplateData struct {
+	Namespace subjectTemplateNamespace
+}
+
+type subjectTemplateNamespace struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// ValidateSubjectTemplates returns an error if any of the given subject
+// templates cannot be parsed.
+func ValidateSubjectTemplates(templates []string) error {
+	for _, text := range templates {
+		if _, err := parseSubjectTemplate(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSubjectTemplate(text string) (*template.Template, error) {
+	// Missing labels and annotations evaluate to an empty string, rather than
+	// "<no value>", so that they are dropped from the subject.
+	return template.New("subject").Option("missingkey=zero").Parse(text)
+}
+
+// setSubjectFromTemplates sets the organizations and organizational units of
+// the Certificate's subject. They are read from the following annotations on
+// the Ingress or Gateway, falling back to the controller defaults:
+//
+//   cert-manager.io/subject-organizations: '["{{ .Namespace.Labels.org }}", "Example, Inc."]'
+//   cert-manager.io/subject-organizationalunits: '["{{ index .Namespace.Annotations \"example.com/team\" }}"]'
+//
+// Each annotation is a JSON list of templates that are evaluated against the
+// namespace of the Ingress or Gateway, so that templates may contain commas.
+// Templates that evaluate to an empty string are dropped, and the subject is
+// left unset if no templates are configured.
+func setSubjectFromTemplates(crt *cmapi.Certificate, ingLikeAnnotations map[string]string, defaults controller.IngressShimOptions, namespaceLister corelisters.NamespaceLister) error {
+	orgTemplates, err := subjectTemplatesFor(ingLikeAnnotations, cmapi.SubjectOrganizationsAnnotationKey, defaults.DefaultSubjectOrganizations)
+	if err != nil {
+		return err
+	}
+	ouTemplates, err := subjectTemplatesFor(ingLikeAnnotations, cmapi.SubjectOrganizationalUnitsAnnotationKey, defaults.DefaultSubjectOrganizationalUnits)
+	if err != nil {
+		return err
+	}
+	if len(orgTemplates) == 0 && len(ouTemplates) == 0 {
+		return nil
+	}
+
+	ns, err := namespaceLister.Get(crt.Namespace)
+	if err != nil {
+		return err
+	}
+	data := subjectTemplateData{Namespace: subjectTemplateNamespace{
+		Name:        ns.Name,
+		Labels:      ns.Labels,
+		Annotations: ns.Annotations,
+	}}
+
+	orgs, err := renderSubjectTemplates(orgTemplates, data)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", errInvalidIngressAnnotation, cmapi.SubjectOrganizationsAnnotationKey, err)
+	}
+	ous, err := renderSubjectTemplates(ouTemplates, data)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", errInvalidIngressAnnotation, cmapi.SubjectOrganizationalUnitsAnnotationKey, err)
+	}
+	if len(orgs) == 0 && len(ous) == 0 {
+		return nil
+	}
+
+	if crt.Spec.Subject == nil {
+		crt.Spec.Subject = &cmapi.X509Subject{}
+	}
+	crt.Spec.Subject.Organizations = orgs
+	crt.Spec.Subject.OrganizationalUnits = ous
+	return nil
+}
+
+func renderSubjectTemplates(templates []string, data subjectTemplateData) ([]string, error) {
+	var values []string
+	for _, text := range templates {
+		tmpl, err := parseSubjectTemplate(text)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		if value := strings.TrimSpace(buf.String()); len(value) > 0 {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// subjectTemplatesFor returns the templates given by the annotation with the
+// given key, or the defaults if the annotation is not set.
+func subjectTemplatesFor(ingLikeAnnotations map[string]string, key string, defaults []string) ([]string, error) {
+	value, found := ingLikeAnnotations[key]
+	if !found {
+		return defaults, nil
+	}
+
+	var templates []string
+	if err := json.Unmarshal([]byte(value), &templates); err != nil {
+		return nil, fmt.Errorf("%w %q: must be a JSON list of templates: %v", errInvalidIngressAnnotation, key, err)
+	}
+	return templates, nil
+}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/go-logr/logr"
@@ -43,8 +44,9 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/controller"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
 	"k8s.io/apimachinery/pkg/util/validation/field"
-	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha1"
+	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 const (
@@ -74,6 +76,7 @@ func SyncFnFor(
 	log logr.Logger,
 	cmClient clientset.Interface,
 	cmLister cmlisters.CertificateLister,
+	namespaceLister corelisters.NamespaceLister,
 	defaults controller.IngressShimOptions,
 ) SyncFn {
 	return func(ctx context.Context, ingLike metav1.Object) error {
@@ -96,8 +99,33 @@ func SyncFnFor(
 		}
 
 		if !hasShimAnnotation(ingLike, autoAnnotations) {
-			logf.V(logf.DebugLevel).Infof("not syncing ingress resource as it does not contain a %q or %q annotation",
-				cmapi.IngressIssuerNameAnnotationKey, cmapi.IngressClusterIssuerNameAnnotationKey)
+			cleanupPolicy, err := cleanupPolicyFor(ingLike.GetAnnotations())
+			if err != nil {
+				rec.Eventf(ingLikeObj, corev1.EventTypeWarning, reasonBadConfig, err.Error())
+				return nil
+			}
+			if cleanupPolicy != cmapi.CertificateCleanupPolicyDelete {
+				logf.V(logf.DebugLevel).Infof("not syncing ingress resource as it does not contain a %q or %q annotation",
+					cmapi.IngressIssuerNameAnnotationKey, cmapi.IngressClusterIssuerNameAnnotationKey)
+				return nil
+			}
+
+			// With the "Delete" cleanup policy, the Certificates created for
+			// this object are removed as soon as the issuer annotations are.
+			certs, err := cmLister.Certificates(ingLike.GetNamespace()).List(labels.Everything())
+			if err != nil {
+				return err
+			}
+			for _, crt := range certs {
+				if !metav1.IsControlledBy(crt, ingLike) {
+					continue
+				}
+				err = cmClient.CertmanagerV1().Certificates(crt.Namespace).Delete(ctx, crt.Name, metav1.DeleteOptions{})
+				if err != nil {
+					return err
+				}
+				rec.Eventf(ingLikeObj, corev1.EventTypeNormal, reasonDeleteCertificate, "Successfully deleted Certificate %q as the issuer annotation was removed", crt.Name)
+			}
 			return nil
 		}
 
@@ -115,7 +143,13 @@ func SyncFnFor(
 			return nil
 		}
 
-		newCrts, updateCrts, err := buildCertificates(rec, log, cmLister, ingLike, issuerName, issuerKind, issuerGroup)
+		editPolicy, err := editPolicyFor(ingLike.GetAnnotations())
+		if err != nil {
+			rec.Eventf(ingLikeObj, corev1.EventTypeWarning, reasonBadConfig, err.Error())
+			return nil
+		}
+
+		newCrts, updateCrts, err := buildCertificates(rec, log, cmLister, namespaceLister, defaults, ingLike, issuerName, issuerKind, issuerGroup, editPolicy)
 		if err != nil {
 			return err
 		}
@@ -219,7 +253,7 @@ func validateIngressTLSBlock(path *field.Path, tlsBlock networkingv1.IngressTLS)
 	return errs
 }
 
-func validateGatewayListenerBlock(path *field.Path, l gwapi.Listener) field.ErrorList {
+func validateGatewayListenerBlock(path *field.Path, namespace string, l gwapi.Listener) field.ErrorList {
 	var errs field.ErrorList
 
 	if l.Hostname == nil || *l.Hostname == "" {
@@ -231,24 +265,36 @@ func validateGatewayListenerBlock(path *field.Path, l gwapi.Listener) field.Erro
 		return errs
 	}
 
-	if l.TLS.CertificateRef == nil {
-		errs = append(errs, field.Required(path.Child("tls").Child("certificateRef"),
-			"listener is missing a certificateRef"))
-	} else {
-		if l.TLS.CertificateRef.Group != "core" {
-			errs = append(errs, field.NotSupported(path.Child("tls").Child("certificateRef").Child("group"),
-				l.TLS.CertificateRef.Group, []string{"core"}))
+	if len(l.TLS.CertificateRefs) == 0 {
+		errs = append(errs, field.Required(path.Child("tls").Child("certificateRefs"),
+			"listener has no certificateRefs"))
+	}
+	for i, ref := range l.TLS.CertificateRefs {
+		refPath := path.Child("tls").Child("certificateRefs").Index(i)
+		if ref == nil {
+			errs = append(errs, field.Required(refPath, "the certificateRef cannot be empty"))
+			continue
 		}
 
-		if l.TLS.CertificateRef.Kind != "Secret" {
-			errs = append(errs, field.NotSupported(path.Child("tls").Child("certificateRef").Child("kind"),
-				l.TLS.CertificateRef.Kind, []string{"Secret"}))
+		if ref.Group != nil && *ref.Group != "" && *ref.Group != "core" {
+			errs = append(errs, field.NotSupported(refPath.Child("group"),
+				*ref.Group, []string{"", "core"}))
 		}
 
-		if l.TLS.CertificateRef.Name == "" {
-			errs = append(errs, field.Required(path.Child("tls").Child("certificateRef").Child("name"),
+		if ref.Kind != nil && *ref.Kind != "" && *ref.Kind != "Secret" {
+			errs = append(errs, field.NotSupported(refPath.Child("kind"),
+				*ref.Kind, []string{"", "Secret"}))
+		}
+
+		if ref.Name == "" {
+			errs = append(errs, field.Required(refPath.Child("name"),
 				"the Secret name cannot be empty"))
 		}
+
+		if ref.Namespace != nil && string(*ref.Namespace) != namespace {
+			errs = append(errs, field.Invalid(refPath.Child("namespace"),
+				*ref.Namespace, "cross-namespace Secret references are not supported"))
+		}
 	}
 
 	if l.TLS.Mode == nil {
@@ -268,8 +314,11 @@ func buildCertificates(
 	rec record.EventRecorder,
 	log logr.Logger,
 	cmLister cmlisters.CertificateLister,
+	namespaceLister corelisters.NamespaceLister,
+	defaults controller.IngressShimOptions,
 	ingLike metav1.Object,
 	issuerName, issuerKind, issuerGroup string,
+	editPolicy string,
 ) (new, update []*cmapi.Certificate, _ error) {
 
 	var newCrts []*cmapi.Certificate
@@ -292,19 +341,33 @@ func buildCertificates(
 		}
 	case *gwapi.Gateway:
 		for i, l := range ingLike.Spec.Listeners {
-			err := validateGatewayListenerBlock(field.NewPath("spec", "listeners").Index(i), l).ToAggregate()
+			// Listeners that do not terminate TLS, such as the plain HTTP
+			// listener of a Gateway that also serves HTTPS, need no
+			// Certificate.
+			if l.TLS == nil && l.Protocol != gwapi.HTTPSProtocolType && l.Protocol != gwapi.TLSProtocolType {
+				continue
+			}
+
+			err := validateGatewayListenerBlock(field.NewPath("spec", "listeners").Index(i), ingLike.Namespace, l).ToAggregate()
 			if err != nil {
 				rec.Eventf(ingLike, corev1.EventTypeWarning, reasonBadConfig, "Skipped a listener block: "+err.Error())
 				continue
 			}
 
+			// Only the first certificateRef of a listener is managed by
+			// cert-manager. Further references are left to the user, e.g.
+			// to serve certificates with different key types.
 			secretRef := corev1.ObjectReference{
 				Namespace: ingLike.Namespace,
-				Name:      l.TLS.CertificateRef.Name,
+				Name:      string(l.TLS.CertificateRefs[0].Name),
 			}
 			// Gateway API hostname explicitly disallows IP addresses, so this
-			// should be OK.
-			tlsHosts[secretRef] = append(tlsHosts[secretRef], fmt.Sprintf("%s", *l.Hostname))
+			// should be OK. Listeners for the same hostname on different
+			// ports share a single DNS name.
+			hostname := string(*l.Hostname)
+			if !util.Contains(tlsHosts[secretRef], hostname) {
+				tlsHosts[secretRef] = append(tlsHosts[secretRef], hostname)
+			}
 		}
 	default:
 		return nil, nil, fmt.Errorf("buildCertificates: expected ingress or gateway, got %T", ingLike)
@@ -359,6 +422,26 @@ func buildCertificates(
 			return nil, nil, err
 		}
 
+		if err := setSubjectFromTemplates(crt, ingLike.GetAnnotations(), defaults, namespaceLister); err != nil {
+			return nil, nil, err
+		}
+
+		// With the "Allow" edit policy, we record what the Certificate looked
+		// like when it was derived from the ingress-like object so that edits
+		// made directly to the Certificate are kept until the ingress-like
+		// object itself changes.
+		var specHash string
+		if editPolicy == cmapi.CertificateEditPolicyAllow {
+			specHash, err = certificateSpecHash(crt)
+			if err != nil {
+				return nil, nil, err
+			}
+			if crt.Annotations == nil {
+				crt.Annotations = make(map[string]string)
+			}
+			crt.Annotations[cmapi.IngressCertificateSpecHashAnnotationKey] = specHash
+		}
+
 		// check if a Certificate for this TLS entry already exists, and if it
 		// does then skip this entry
 		if existingCrt != nil {
@@ -375,7 +458,14 @@ func buildCertificates(
 				continue
 			}
 
-			if !certNeedsUpdate(existingCrt, crt) {
+			if editPolicy == cmapi.CertificateEditPolicyAllow && existingCrt.Annotations[cmapi.IngressCertificateSpecHashAnnotationKey] == specHash {
+				log.V(logf.DebugLevel).Info("object has not changed since the certificate resource was last updated, keeping any edits made to the certificate resource")
+				continue
+			}
+
+			// Certificates using the "Allow" edit policy are always updated
+			// when their hash differs so that the new hash gets recorded.
+			if editPolicy != cmapi.CertificateEditPolicyAllow && !certNeedsUpdate(existingCrt, crt) {
 				log.V(logf.DebugLevel).Info("certificate resource is already up to date for object")
 				continue
 			}
@@ -384,6 +474,12 @@ func buildCertificates(
 
 			updateCrt.Spec = crt.Spec
 			updateCrt.Labels = crt.Labels
+			if specHash != "" {
+				if updateCrt.Annotations == nil {
+					updateCrt.Annotations = make(map[string]string)
+				}
+				updateCrt.Annotations[cmapi.IngressCertificateSpecHashAnnotationKey] = specHash
+			}
 
 			setIssuerSpecificConfig(crt, ingLike)
 
@@ -419,11 +515,13 @@ func secretNameUsedIn(secretName string, ingLike metav1.Object) bool {
 		}
 	case *gwapi.Gateway:
 		for _, l := range o.Spec.Listeners {
-			if l.TLS == nil || l.TLS.CertificateRef == nil {
+			if l.TLS == nil {
 				continue
 			}
-			if secretName == l.TLS.CertificateRef.Name {
-				return true
+			for _, ref := range l.TLS.CertificateRefs {
+				if ref != nil && secretName == string(ref.Name) {
+					return true
+				}
 			}
 		}
 	}
@@ -463,6 +561,18 @@ func certNeedsUpdate(a, b *cmapi.Certificate) bool {
 		return true
 	}
 
+	if !reflect.DeepEqual(a.Spec.Subject, b.Spec.Subject) {
+		return true
+	}
+
+	if !reflect.DeepEqual(a.Spec.Keystores, b.Spec.Keystores) {
+		return true
+	}
+
+	if !reflect.DeepEqual(a.Spec.AdditionalOutputFormats, b.Spec.AdditionalOutputFormats) {
+		return true
+	}
+
 	if a.Spec.IssuerRef.Name != b.Spec.IssuerRef.Name {
 		return true
 	}
@@ -563,8 +673,9 @@ func hasShimAnnotation(ingLike metav1.Object, autoCertificateAnnotations []strin
 
 // issuerForIngressLike determines the Issuer that should be specified on a
 // Certificate created for the given ingress-like resource. If one is not set,
-// the default issuer given to the controller is used. We look up the following
-// Ingress annotations:
+// the default issuer given to the controller for the class of the Ingress is
+// used, falling back to the controller's global default issuer. We look up the
+// following Ingress annotations:
 //
 //   cert-manager.io/cluster-issuer
 //   cert-manager.io/issuer
@@ -577,6 +688,10 @@ func issuerForIngressLike(defaults controller.IngressShimOptions, ingLike metav1
 	kind = defaults.DefaultIssuerKind
 	group = defaults.DefaultIssuerGroup
 
+	if ref, ok := defaults.IngressClassDefaultIssuers[ingressClassFor(ingLike)]; ok {
+		name, kind, group = ref.Name, ref.Kind, ref.Group
+	}
+
 	annotations := ingLike.GetAnnotations()
 
 	if annotations == nil {
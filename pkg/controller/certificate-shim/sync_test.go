@@ -24,13 +24,14 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	coretesting "k8s.io/client-go/testing"
-	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha1"
+	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -77,21 +78,42 @@ func TestSync(t *testing.T) {
 		gen.SetIssuerACME(cmacme.ACMEIssuer{}))
 	acmeClusterIssuer := gen.ClusterIssuer("issuer-name",
 		gen.SetIssuerACME(cmacme.ACMEIssuer{}))
+	// desiredExistingCrt is the Certificate derived from an Ingress with a
+	// single "example.com" host using the "existing-crt" secret and the
+	// "issuer-name" Issuer.
+	desiredExistingCrt := &cmapi.Certificate{
+		Spec: cmapi.CertificateSpec{
+			DNSNames:   []string{"example.com"},
+			SecretName: "existing-crt",
+			IssuerRef: cmmeta.ObjectReference{
+				Name: "issuer-name",
+				Kind: "Issuer",
+			},
+			Usages: cmapi.DefaultKeyUsages(),
+		},
+	}
+	desiredExistingCrtHash, err := certificateSpecHash(desiredExistingCrt)
+	if err != nil {
+		t.Fatal(err)
+	}
 	type testT struct {
-		Name                string
-		IngressLike         metav1.Object
-		Issuer              cmapi.GenericIssuer
-		IssuerLister        []runtime.Object
-		ClusterIssuerLister []runtime.Object
-		CertificateLister   []runtime.Object
-		DefaultIssuerName   string
-		DefaultIssuerKind   string
-		DefaultIssuerGroup  string
-		Err                 bool
-		ExpectedCreate      []*cmapi.Certificate
-		ExpectedUpdate      []*cmapi.Certificate
-		ExpectedDelete      []*cmapi.Certificate
-		ExpectedEvents      []string
+		Name                              string
+		IngressLike                       metav1.Object
+		Issuer                            cmapi.GenericIssuer
+		IssuerLister                      []runtime.Object
+		ClusterIssuerLister               []runtime.Object
+		CertificateLister                 []runtime.Object
+		DefaultIssuerName                 string
+		DefaultIssuerKind                 string
+		DefaultIssuerGroup                string
+		DefaultSubjectOrganizations       []string
+		DefaultSubjectOrganizationalUnits []string
+		KubeObjects                       []runtime.Object
+		Err                               bool
+		ExpectedCreate                    []*cmapi.Certificate
+		ExpectedUpdate                    []*cmapi.Certificate
+		ExpectedDelete                    []*cmapi.Certificate
+		ExpectedEvents                    []string
 	}
 	testIngressShim := []testT{
 		{
@@ -987,6 +1009,230 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:         "should not delete a Certificate when the issuer annotation is removed and no cleanup policy is set",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					UID:       types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "existing-crt",
+						},
+					},
+				},
+			},
+			CertificateLister: []runtime.Object{
+				buildCertificate("existing-crt",
+					gen.DefaultTestNamespace,
+					buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+				),
+			},
+		},
+		{
+			Name:         "should delete owned Certificates when the issuer annotation is removed and the cleanup policy is Delete",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressCertificateCleanupPolicyAnnotationKey: cmapi.CertificateCleanupPolicyDelete,
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "existing-crt",
+						},
+					},
+				},
+			},
+			CertificateLister: []runtime.Object{
+				buildCertificate("existing-crt",
+					gen.DefaultTestNamespace,
+					buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+				),
+				buildCertificate("not-owned-crt",
+					gen.DefaultTestNamespace,
+					buildIngressOwnerReferences("not-ingress-name", gen.DefaultTestNamespace),
+				),
+			},
+			ExpectedEvents: []string{`Normal DeleteCertificate Successfully deleted Certificate "existing-crt" as the issuer annotation was removed`},
+			ExpectedDelete: []*cmapi.Certificate{
+				buildCertificate("existing-crt",
+					gen.DefaultTestNamespace,
+					buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+				),
+			},
+		},
+		{
+			Name:         "should not delete Certificates when the cleanup policy annotation is invalid",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressCertificateCleanupPolicyAnnotationKey: "Orphan",
+					},
+					UID: types.UID("ingress-name"),
+				},
+			},
+			CertificateLister: []runtime.Object{
+				buildCertificate("existing-crt",
+					gen.DefaultTestNamespace,
+					buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+				),
+			},
+			ExpectedEvents: []string{`Warning BadConfig invalid ingress annotation "cert-manager.io/certificate-cleanup-policy": must be one of "Retain" or "Delete", got "Orphan"`},
+		},
+		{
+			Name:         "should keep edits made to a Certificate when the edit policy is Allow and the ingress has not changed",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey:            "issuer-name",
+						cmapi.IngressCertificateEditPolicyAnnotationKey: cmapi.CertificateEditPolicyAllow,
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "existing-crt",
+						},
+					},
+				},
+			},
+			DefaultIssuerKind: "Issuer",
+			CertificateLister: []runtime.Object{
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "existing-crt",
+						Namespace: gen.DefaultTestNamespace,
+						Labels: map[string]string{
+							"added-by-user": "true",
+						},
+						Annotations: map[string]string{
+							cmapi.IngressCertificateSpecHashAnnotationKey: desiredExistingCrtHash,
+						},
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com", "www.example.com"},
+						CommonName: "example.com",
+						SecretName: "existing-crt",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+		},
+		{
+			Name:         "should update an edited Certificate when the edit policy is Allow and the ingress has changed",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey:            "issuer-name",
+						cmapi.IngressCertificateEditPolicyAnnotationKey: cmapi.CertificateEditPolicyAllow,
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "existing-crt",
+						},
+					},
+				},
+			},
+			DefaultIssuerKind: "Issuer",
+			CertificateLister: []runtime.Object{
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "existing-crt",
+						Namespace: gen.DefaultTestNamespace,
+						Annotations: map[string]string{
+							cmapi.IngressCertificateSpecHashAnnotationKey: "stale-hash",
+						},
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com", "www.example.com"},
+						SecretName: "existing-crt",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+			ExpectedEvents: []string{`Normal UpdateCertificate Successfully updated Certificate "existing-crt"`},
+			ExpectedUpdate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "existing-crt",
+						Namespace: gen.DefaultTestNamespace,
+						Annotations: map[string]string{
+							cmapi.IngressCertificateSpecHashAnnotationKey: desiredExistingCrtHash,
+						},
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: desiredExistingCrt.Spec,
+				},
+			},
+		},
+		{
+			Name:         "should not sync an ingress with an invalid edit policy annotation",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey:            "issuer-name",
+						cmapi.IngressCertificateEditPolicyAnnotationKey: "Ignore",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "existing-crt",
+						},
+					},
+				},
+			},
+			DefaultIssuerKind: "Issuer",
+			ExpectedEvents:    []string{`Warning BadConfig invalid ingress annotation "cert-manager.io/certificate-edit-policy": must be one of "Revert" or "Allow", got "Ignore"`},
+		},
 		{
 			Name:         "if an ingress contains multiple tls entries that specify the same secretName, an error should be logged and no action taken",
 			Issuer:       acmeIssuer,
@@ -1011,26 +1257,293 @@ func TestSync(t *testing.T) {
 							Hosts:      []string{"example.com"},
 							SecretName: "example-com-tls",
 						},
-						{
-							Hosts:      []string{"notexample.com"},
-							SecretName: "example-com-tls",
+						{
+							Hosts:      []string{"notexample.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Failure to translateIngressAnnotations",
+			Issuer: acmeIssuer,
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey: "issuer-name",
+						cmapi.IssuerKindAnnotationKey:        "Issuer",
+						cmapi.IssuerGroupAnnotationKey:       "cert-manager.io",
+						cmapi.RenewBeforeAnnotationKey:       "invalid renew before value",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			Err: true,
+		},
+		{
+			Name:   "return a single Certificate for an ingress with a single valid TLS entry with common-name and keyusage annotation",
+			Issuer: acmeClusterIssuer,
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Labels: map[string]string{
+						"my-test-label": "should be copied",
+					},
+					Annotations: map[string]string{
+						cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name",
+						cmapi.CommonNameAnnotationKey:               "my-cn",
+						"cert-manager.io/usages":                    "signing,digital signature,content commitment",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com", "www.example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			ClusterIssuerLister: []runtime.Object{acmeClusterIssuer},
+			ExpectedEvents:      []string{`Normal CreateCertificate Successfully created Certificate "example-com-tls"`},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "example-com-tls",
+						Namespace: gen.DefaultTestNamespace,
+						Labels: map[string]string{
+							"my-test-label": "should be copied",
+						},
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com", "www.example.com"},
+						CommonName: "my-cn",
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "ClusterIssuer",
+						},
+						Usages: []cmapi.KeyUsage{
+							cmapi.UsageSigning,
+							cmapi.UsageDigitalSignature,
+							cmapi.UsageContentCommitment,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "should set the subject of a Certificate from the namespace labels using the default templates",
+			Issuer: acmeIssuer,
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey: "issuer-name",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			IssuerLister: []runtime.Object{acmeIssuer},
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        gen.DefaultTestNamespace,
+						Labels:      map[string]string{"org": "Example Corp", "team": "payments"},
+						Annotations: map[string]string{"example.com/cost-center": "cc-1234"},
+					},
+				},
+			},
+			DefaultIssuerKind:                 "Issuer",
+			DefaultSubjectOrganizations:       []string{"{{ .Namespace.Labels.org }}"},
+			DefaultSubjectOrganizationalUnits: []string{"{{ .Namespace.Labels.team }}", "{{ .Namespace.Labels.missing }}"},
+			ExpectedEvents:                    []string{`Normal CreateCertificate Successfully created Certificate "example-com-tls"`},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+						Subject: &cmapi.X509Subject{
+							Organizations:       []string{"Example Corp"},
+							OrganizationalUnits: []string{"payments"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "should prefer subject annotations on the ingress over the default templates",
+			Issuer: acmeIssuer,
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey:          "issuer-name",
+						cmapi.SubjectOrganizationalUnitsAnnotationKey: `["{{ index .Namespace.Annotations \"example.com/cost-center\" }}", "static-ou"]`,
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			IssuerLister: []runtime.Object{acmeIssuer},
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        gen.DefaultTestNamespace,
+						Labels:      map[string]string{"org": "Example Corp", "team": "payments"},
+						Annotations: map[string]string{"example.com/cost-center": "cc-1234"},
+					},
+				},
+			},
+			DefaultIssuerKind:                 "Issuer",
+			DefaultSubjectOrganizationalUnits: []string{"{{ .Namespace.Labels.team }}"},
+			ExpectedEvents:                    []string{`Normal CreateCertificate Successfully created Certificate "example-com-tls"`},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+						Subject: &cmapi.X509Subject{
+							OrganizationalUnits: []string{"cc-1234", "static-ou"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "should update the subject of an existing Certificate when the namespace labels change",
+			Issuer: acmeIssuer,
+			IngressLike: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey: "issuer-name",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "existing-crt",
+						},
+					},
+				},
+			},
+			IssuerLister: []runtime.Object{acmeIssuer},
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        gen.DefaultTestNamespace,
+						Labels:      map[string]string{"org": "Example Corp", "team": "payments"},
+						Annotations: map[string]string{"example.com/cost-center": "cc-1234"},
+					},
+				},
+			},
+			CertificateLister: []runtime.Object{
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "existing-crt",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "existing-crt",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages:  cmapi.DefaultKeyUsages(),
+						Subject: &cmapi.X509Subject{OrganizationalUnits: []string{"checkout"}},
+					},
+				},
+			},
+			DefaultIssuerKind:                 "Issuer",
+			DefaultSubjectOrganizationalUnits: []string{"{{ .Namespace.Labels.team }}"},
+			ExpectedEvents:                    []string{`Normal UpdateCertificate Successfully updated Certificate "existing-crt"`},
+			ExpectedUpdate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "existing-crt",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "existing-crt",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
 						},
+						Usages:  cmapi.DefaultKeyUsages(),
+						Subject: &cmapi.X509Subject{OrganizationalUnits: []string{"payments"}},
 					},
 				},
 			},
 		},
 		{
-			Name:   "Failure to translateIngressAnnotations",
+			Name:   "should not create a Certificate if a subject annotation is not a valid template",
 			Issuer: acmeIssuer,
 			IngressLike: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "ingress-name",
 					Namespace: gen.DefaultTestNamespace,
 					Annotations: map[string]string{
-						cmapi.IngressIssuerNameAnnotationKey: "issuer-name",
-						cmapi.IssuerKindAnnotationKey:        "Issuer",
-						cmapi.IssuerGroupAnnotationKey:       "cert-manager.io",
-						cmapi.RenewBeforeAnnotationKey:       "invalid renew before value",
+						cmapi.IngressIssuerNameAnnotationKey:    "issuer-name",
+						cmapi.SubjectOrganizationsAnnotationKey: `["{{ .Namespace.Labels.org"]`,
 					},
 					UID: types.UID("ingress-name"),
 				},
@@ -1043,14 +1556,28 @@ func TestSync(t *testing.T) {
 					},
 				},
 			},
-			Err: true,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        gen.DefaultTestNamespace,
+						Labels:      map[string]string{"org": "Example Corp", "team": "payments"},
+						Annotations: map[string]string{"example.com/cost-center": "cc-1234"},
+					},
+				},
+			},
+			DefaultIssuerKind: "Issuer",
+			Err:               true,
 		},
+	}
+
+	testGatewayShim := []testT{
 		{
-			Name:   "return a single Certificate for an ingress with a single valid TLS entry with common-name and keyusage annotation",
+			Name:   "return a single Certificate for a Gateway with a single valid TLS entry and common-name annotation",
 			Issuer: acmeClusterIssuer,
-			IngressLike: &networkingv1.Ingress{
+			IngressLike: &gwapi.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "ingress-name",
+					Name:      "gateway-name",
 					Namespace: gen.DefaultTestNamespace,
 					Labels: map[string]string{
 						"my-test-label": "should be copied",
@@ -1058,15 +1585,24 @@ func TestSync(t *testing.T) {
 					Annotations: map[string]string{
 						cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name",
 						cmapi.CommonNameAnnotationKey:               "my-cn",
-						"cert-manager.io/usages":                    "signing,digital signature,content commitment",
 					},
-					UID: types.UID("ingress-name"),
+					UID: types.UID("gateway-name"),
 				},
-				Spec: networkingv1.IngressSpec{
-					TLS: []networkingv1.IngressTLS{
+				Spec: gwapi.GatewaySpec{
+					GatewayClassName: "test-gateway",
+					Listeners: []gwapi.Listener{
 						{
-							Hosts:      []string{"example.com", "www.example.com"},
-							SecretName: "example-com-tls",
+							Hostname: ptrHostname("example.com"),
+							Port:     443,
+							Protocol: "HTTPS",
+							TLS: &gwapi.GatewayTLSConfig{
+								Mode: ptrMode(gwapi.TLSModeTerminate),
+								CertificateRefs: []*gwapi.SecretObjectReference{{
+									Group: ptrGroup("core"),
+									Kind:  ptrKind("Secret"),
+									Name:  "example-com-tls",
+								}},
+							},
 						},
 					},
 				},
@@ -1081,58 +1617,65 @@ func TestSync(t *testing.T) {
 						Labels: map[string]string{
 							"my-test-label": "should be copied",
 						},
-						OwnerReferences: buildIngressOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+						OwnerReferences: buildGatewayOwnerReferences("gateway-name", gen.DefaultTestNamespace),
 					},
 					Spec: cmapi.CertificateSpec{
-						DNSNames:   []string{"example.com", "www.example.com"},
+						DNSNames:   []string{"example.com"},
 						CommonName: "my-cn",
 						SecretName: "example-com-tls",
 						IssuerRef: cmmeta.ObjectReference{
 							Name: "issuer-name",
 							Kind: "ClusterIssuer",
 						},
-						Usages: []cmapi.KeyUsage{
-							cmapi.UsageSigning,
-							cmapi.UsageDigitalSignature,
-							cmapi.UsageContentCommitment,
-						},
+						Usages: cmapi.DefaultKeyUsages(),
 					},
 				},
 			},
 		},
-	}
-
-	testGatewayShim := []testT{
 		{
-			Name:   "return a single Certificate for a Gateway with a single valid TLS entry and common-name annotation",
+			Name:   "return a single Certificate without duplicate DNS names for a Gateway with HTTP and HTTPS listeners",
 			Issuer: acmeClusterIssuer,
 			IngressLike: &gwapi.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "gateway-name",
 					Namespace: gen.DefaultTestNamespace,
-					Labels: map[string]string{
-						"my-test-label": "should be copied",
-					},
 					Annotations: map[string]string{
 						cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name",
-						cmapi.CommonNameAnnotationKey:               "my-cn",
 					},
 					UID: types.UID("gateway-name"),
 				},
 				Spec: gwapi.GatewaySpec{
 					GatewayClassName: "test-gateway",
 					Listeners: []gwapi.Listener{
+						{
+							Hostname: ptrHostname("example.com"),
+							Port:     80,
+							Protocol: "HTTP",
+						},
 						{
 							Hostname: ptrHostname("example.com"),
 							Port:     443,
 							Protocol: "HTTPS",
 							TLS: &gwapi.GatewayTLSConfig{
 								Mode: ptrMode(gwapi.TLSModeTerminate),
-								CertificateRef: &gwapi.LocalObjectReference{
-									Group: "core",
-									Kind:  "Secret",
+								CertificateRefs: []*gwapi.SecretObjectReference{{
+									Group: ptrGroup("core"),
+									Kind:  ptrKind("Secret"),
+									Name:  "example-com-tls",
+								}},
+							},
+						},
+						{
+							Hostname: ptrHostname("example.com"),
+							Port:     8443,
+							Protocol: "HTTPS",
+							TLS: &gwapi.GatewayTLSConfig{
+								Mode: ptrMode(gwapi.TLSModeTerminate),
+								CertificateRefs: []*gwapi.SecretObjectReference{{
+									Group: ptrGroup("core"),
+									Kind:  ptrKind("Secret"),
 									Name:  "example-com-tls",
-								},
+								}},
 							},
 						},
 					},
@@ -1143,16 +1686,12 @@ func TestSync(t *testing.T) {
 			ExpectedCreate: []*cmapi.Certificate{
 				{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "example-com-tls",
-						Namespace: gen.DefaultTestNamespace,
-						Labels: map[string]string{
-							"my-test-label": "should be copied",
-						},
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
 						OwnerReferences: buildGatewayOwnerReferences("gateway-name", gen.DefaultTestNamespace),
 					},
 					Spec: cmapi.CertificateSpec{
 						DNSNames:   []string{"example.com"},
-						CommonName: "my-cn",
 						SecretName: "example-com-tls",
 						IssuerRef: cmmeta.ObjectReference{
 							Name: "issuer-name",
@@ -1187,11 +1726,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1248,11 +1787,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1305,11 +1844,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1356,11 +1895,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1408,11 +1947,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1463,11 +2002,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1513,11 +2052,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1567,11 +2106,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1602,11 +2141,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}, {
 						Hostname: nil, // 🔥
@@ -1614,11 +2153,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls-invalid",
-							},
+							}},
 						},
 					}},
 				},
@@ -1647,7 +2186,7 @@ func TestSync(t *testing.T) {
 			Issuer:       acmeIssuer,
 			IssuerLister: []runtime.Object{acmeIssuer},
 			ExpectedEvents: []string{
-				`Warning BadConfig Skipped a listener block: spec.listeners[0].tls.certificateRef: Required value: listener is missing a certificateRef`,
+				`Warning BadConfig Skipped a listener block: spec.listeners[0].tls.certificateRefs: Required value: listener has no certificateRefs`,
 				`Normal CreateCertificate Successfully created Certificate "example-com-tls"`,
 			},
 			IngressLike: &gwapi.Gateway{
@@ -1666,8 +2205,8 @@ func TestSync(t *testing.T) {
 						Port:     443,
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
-							Mode:           ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: nil, // 🔥
+							Mode:            ptrMode(gwapi.TLSModeTerminate),
+							CertificateRefs: nil, // 🔥
 						},
 					}, {
 						Hostname: ptrHostname("www.example.com"),
@@ -1675,11 +2214,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -1737,11 +2276,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "existing-crt",
-							},
+							}},
 						},
 					}},
 				},
@@ -1789,11 +2328,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "existing-crt",
-							},
+							}},
 						},
 					}},
 				},
@@ -1849,11 +2388,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "cert-secret-name",
-							},
+							}},
 						},
 					}},
 				},
@@ -1925,11 +2464,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "existing-crt",
-							},
+							}},
 						},
 					}},
 				},
@@ -1975,11 +2514,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "existing-crt",
-							},
+							}},
 						},
 					}},
 				},
@@ -2077,11 +2616,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -2150,11 +2689,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}, {
 						Hostname: ptrHostname("www.example.com"),
@@ -2162,11 +2701,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}, {
 						Hostname: ptrHostname("foo.example.com"),
@@ -2174,11 +2713,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -2229,11 +2768,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "foo-example-com-tls",
-							},
+							}},
 						},
 					}, {
 						Hostname: ptrHostname("bar.example.com"),
@@ -2241,11 +2780,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "bar-example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -2314,11 +2853,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -2350,11 +2889,11 @@ func TestSync(t *testing.T) {
 						Protocol: "HTTPS",
 						TLS: &gwapi.GatewayTLSConfig{
 							Mode: ptrMode(gwapi.TLSModeTerminate),
-							CertificateRef: &gwapi.LocalObjectReference{
-								Group: "core",
-								Kind:  "Secret",
+							CertificateRefs: []*gwapi.SecretObjectReference{{
+								Group: ptrGroup("core"),
+								Kind:  ptrKind("Secret"),
 								Name:  "example-com-tls",
-							},
+							}},
 						},
 					}},
 				},
@@ -2388,6 +2927,115 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:   "should set the subject of a Certificate for a Gateway from templates containing commas",
+			Issuer: acmeClusterIssuer,
+			IngressLike: &gwapi.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gateway-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name",
+						cmapi.SubjectOrganizationsAnnotationKey:     `["{{ .Namespace.Labels.org }}", "Example, Inc."]`,
+					},
+					UID: types.UID("gateway-name"),
+				},
+				Spec: gwapi.GatewaySpec{
+					GatewayClassName: "test-gateway",
+					Listeners: []gwapi.Listener{
+						{
+							Hostname: ptrHostname("example.com"),
+							Port:     443,
+							Protocol: "HTTPS",
+							TLS: &gwapi.GatewayTLSConfig{
+								Mode: ptrMode(gwapi.TLSModeTerminate),
+								CertificateRefs: []*gwapi.SecretObjectReference{{
+									Group: ptrGroup("core"),
+									Kind:  ptrKind("Secret"),
+									Name:  "example-com-tls",
+								}},
+							},
+						},
+					},
+				},
+			},
+			ClusterIssuerLister: []runtime.Object{acmeClusterIssuer},
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   gen.DefaultTestNamespace,
+						Labels: map[string]string{"org": "Example Corp", "team": "payments"},
+					},
+				},
+			},
+			DefaultSubjectOrganizationalUnits: []string{"{{ .Namespace.Labels.team }}, {{ .Namespace.Name }}"},
+			ExpectedEvents:                    []string{`Normal CreateCertificate Successfully created Certificate "example-com-tls"`},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildGatewayOwnerReferences("gateway-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "ClusterIssuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+						Subject: &cmapi.X509Subject{
+							Organizations:       []string{"Example Corp", "Example, Inc."},
+							OrganizationalUnits: []string{"payments, " + gen.DefaultTestNamespace},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "should not create a Certificate for a Gateway if a subject annotation is not a JSON list",
+			Issuer: acmeClusterIssuer,
+			IngressLike: &gwapi.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gateway-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name",
+						cmapi.SubjectOrganizationsAnnotationKey:     "{{ .Namespace.Labels.org }}",
+					},
+					UID: types.UID("gateway-name"),
+				},
+				Spec: gwapi.GatewaySpec{
+					GatewayClassName: "test-gateway",
+					Listeners: []gwapi.Listener{
+						{
+							Hostname: ptrHostname("example.com"),
+							Port:     443,
+							Protocol: "HTTPS",
+							TLS: &gwapi.GatewayTLSConfig{
+								Mode: ptrMode(gwapi.TLSModeTerminate),
+								CertificateRefs: []*gwapi.SecretObjectReference{{
+									Group: ptrGroup("core"),
+									Kind:  ptrKind("Secret"),
+									Name:  "example-com-tls",
+								}},
+							},
+						},
+					},
+				},
+			},
+			ClusterIssuerLister: []runtime.Object{acmeClusterIssuer},
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   gen.DefaultTestNamespace,
+						Labels: map[string]string{"org": "Example Corp"},
+					},
+				},
+			},
+			Err: true,
+		},
 	}
 
 	testFn := func(test testT) func(t *testing.T) {
@@ -2426,16 +3074,19 @@ func TestSync(t *testing.T) {
 			b := &testpkg.Builder{
 				T:                  t,
 				CertManagerObjects: allCMObjects,
+				KubeObjects:        test.KubeObjects,
 				ExpectedActions:    expectedActions,
 				ExpectedEvents:     test.ExpectedEvents,
 			}
 			b.Init()
 			defer b.Stop()
-			sync := SyncFnFor(b.Recorder, logr.DiscardLogger{}, b.CMClient, b.SharedInformerFactory.Certmanager().V1().Certificates().Lister(), controller.IngressShimOptions{
+			sync := SyncFnFor(b.Recorder, logr.DiscardLogger{}, b.CMClient, b.SharedInformerFactory.Certmanager().V1().Certificates().Lister(), b.KubeSharedInformerFactory.Core().V1().Namespaces().Lister(), controller.IngressShimOptions{
 				DefaultIssuerName:                 test.DefaultIssuerName,
 				DefaultIssuerKind:                 test.DefaultIssuerKind,
 				DefaultIssuerGroup:                test.DefaultIssuerGroup,
 				DefaultAutoCertificateAnnotations: []string{"kubernetes.io/tls-acme"},
+				DefaultSubjectOrganizations:       test.DefaultSubjectOrganizations,
+				DefaultSubjectOrganizationalUnits: test.DefaultSubjectOrganizationalUnits,
 			})
 			b.Start()
 
@@ -2488,6 +3139,7 @@ func TestIssuerForIngress(t *testing.T) {
 		DefaultName   string
 		DefaultKind   string
 		DefaultGroup  string
+		ClassDefaults map[string]cmmeta.ObjectReference
 		ExpectedName  string
 		ExpectedKind  string
 		ExpectedGroup string
@@ -2522,6 +3174,66 @@ func TestIssuerForIngress(t *testing.T) {
 			ExpectedKind:  "ClusterIssuer",
 			ExpectedGroup: "cert-manager.io",
 		},
+		{
+			Ingress: buildIngress("name", "namespace", map[string]string{
+				"kubernetes.io/tls-acme":        "true",
+				cmapi.IngressClassAnnotationKey: "nginx",
+			}),
+			DefaultName:  "default-name",
+			DefaultKind:  "ClusterIssuer",
+			DefaultGroup: "cert-manager.io",
+			ClassDefaults: map[string]cmmeta.ObjectReference{
+				"nginx": {Name: "nginx-issuer", Kind: "Issuer", Group: "cert-manager.io"},
+			},
+			ExpectedName:  "nginx-issuer",
+			ExpectedKind:  "Issuer",
+			ExpectedGroup: "cert-manager.io",
+		},
+		{
+			Ingress: func() *networkingv1.Ingress {
+				ing := buildIngress("name", "namespace", map[string]string{
+					"kubernetes.io/tls-acme":        "true",
+					cmapi.IngressClassAnnotationKey: "nginx",
+				})
+				class := "internal"
+				ing.Spec.IngressClassName = &class
+				return ing
+			}(),
+			ClassDefaults: map[string]cmmeta.ObjectReference{
+				"nginx":    {Name: "nginx-issuer", Kind: "Issuer", Group: "cert-manager.io"},
+				"internal": {Name: "tpp", Kind: "VenafiClusterIssuer", Group: "jetstack.io"},
+			},
+			ExpectedName:  "tpp",
+			ExpectedKind:  "VenafiClusterIssuer",
+			ExpectedGroup: "jetstack.io",
+		},
+		{
+			Ingress: buildIngress("name", "namespace", map[string]string{
+				"kubernetes.io/tls-acme":        "true",
+				cmapi.IngressClassAnnotationKey: "traefik",
+			}),
+			DefaultName:  "default-name",
+			DefaultKind:  "ClusterIssuer",
+			DefaultGroup: "cert-manager.io",
+			ClassDefaults: map[string]cmmeta.ObjectReference{
+				"nginx": {Name: "nginx-issuer", Kind: "Issuer", Group: "cert-manager.io"},
+			},
+			ExpectedName:  "default-name",
+			ExpectedKind:  "ClusterIssuer",
+			ExpectedGroup: "cert-manager.io",
+		},
+		{
+			Ingress: buildIngress("name", "namespace", map[string]string{
+				cmapi.IngressClusterIssuerNameAnnotationKey: "clusterissuer",
+				cmapi.IngressClassAnnotationKey:             "nginx",
+			}),
+			ClassDefaults: map[string]cmmeta.ObjectReference{
+				"nginx": {Name: "nginx-issuer", Kind: "Issuer", Group: "cert-manager.io"},
+			},
+			ExpectedName:  "clusterissuer",
+			ExpectedKind:  "ClusterIssuer",
+			ExpectedGroup: "cert-manager.io",
+		},
 		{
 			Ingress:       buildIngress("name", "namespace", nil),
 			ExpectedError: errors.New("failed to determine issuer name to be used for ingress resource"),
@@ -2546,6 +3258,8 @@ func TestIssuerForIngress(t *testing.T) {
 			DefaultIssuerKind:  test.DefaultKind,
 			DefaultIssuerName:  test.DefaultName,
 			DefaultIssuerGroup: test.DefaultGroup,
+
+			IngressClassDefaultIssuers: test.ClassDefaults,
 		}
 		name, kind, group, err := issuerForIngressLike(defaults, test.Ingress)
 		if err != nil {
@@ -2622,6 +3336,18 @@ func ptrHostname(hostname string) *gwapi.Hostname {
 	return &h
 }
 
+func ptrGroup(group gwapi.Group) *gwapi.Group {
+	return &group
+}
+
+func ptrKind(kind gwapi.Kind) *gwapi.Kind {
+	return &kind
+}
+
+func ptrNamespace(namespace gwapi.Namespace) *gwapi.Namespace {
+	return &namespace
+}
+
 func ptrMode(mode gwapi.TLSModeType) *gwapi.TLSModeType {
 	return &mode
 }
@@ -2649,11 +3375,11 @@ func Test_validateGatewayListenerBlock(t *testing.T) {
 				Protocol: gwapi.HTTPSProtocolType,
 				TLS: &gwapi.GatewayTLSConfig{
 					Mode: ptrMode(gwapi.TLSModeTerminate),
-					CertificateRef: &gwapi.LocalObjectReference{
-						Group: "core",
-						Kind:  "Secret",
+					CertificateRefs: []*gwapi.SecretObjectReference{{
+						Group: ptrGroup("core"),
+						Kind:  ptrKind("Secret"),
 						Name:  "example-com",
-					},
+					}},
 				},
 			},
 			wantErr: "spec.listeners[0].hostname: Required value: the hostname cannot be empty",
@@ -2666,14 +3392,14 @@ func Test_validateGatewayListenerBlock(t *testing.T) {
 				Protocol: gwapi.HTTPSProtocolType,
 				TLS: &gwapi.GatewayTLSConfig{
 					Mode: ptrMode(gwapi.TLSModeTerminate),
-					CertificateRef: &gwapi.LocalObjectReference{
-						Group: "",
-						Kind:  "Secret",
+					CertificateRefs: []*gwapi.SecretObjectReference{{
+						Group: ptrGroup(""),
+						Kind:  ptrKind("Secret"),
 						Name:  "example-com",
-					},
+					}},
 				},
 			},
-			wantErr: "spec.listeners[0].tls.certificateRef.group: Unsupported value: \"\": supported values: \"core\"",
+			wantErr: "",
 		},
 		{
 			name: "unsupported group",
@@ -2683,14 +3409,14 @@ func Test_validateGatewayListenerBlock(t *testing.T) {
 				Protocol: gwapi.HTTPSProtocolType,
 				TLS: &gwapi.GatewayTLSConfig{
 					Mode: ptrMode(gwapi.TLSModeTerminate),
-					CertificateRef: &gwapi.LocalObjectReference{
-						Group: "invalid",
-						Kind:  "Secret",
+					CertificateRefs: []*gwapi.SecretObjectReference{{
+						Group: ptrGroup("invalid"),
+						Kind:  ptrKind("Secret"),
 						Name:  "example-com",
-					},
+					}},
 				},
 			},
-			wantErr: "spec.listeners[0].tls.certificateRef.group: Unsupported value: \"invalid\": supported values: \"core\"",
+			wantErr: "spec.listeners[0].tls.certificateRefs[0].group: Unsupported value: \"invalid\": supported values: \"\", \"core\"",
 		},
 		{
 			name: "unsupported kind",
@@ -2700,19 +3426,35 @@ func Test_validateGatewayListenerBlock(t *testing.T) {
 				Protocol: gwapi.HTTPSProtocolType,
 				TLS: &gwapi.GatewayTLSConfig{
 					Mode: ptrMode(gwapi.TLSModeTerminate),
-					CertificateRef: &gwapi.LocalObjectReference{
-						Group: "core",
-						Kind:  "SomeOtherKind",
+					CertificateRefs: []*gwapi.SecretObjectReference{{
+						Group: ptrGroup("core"),
+						Kind:  ptrKind("SomeOtherKind"),
 						Name:  "example-com",
-					},
+					}},
+				},
+			},
+			wantErr: "spec.listeners[0].tls.certificateRefs[0].kind: Unsupported value: \"SomeOtherKind\": supported values: \"\", \"Secret\"",
+		},
+		{
+			name: "cross-namespace secret reference",
+			listener: gwapi.Listener{
+				Hostname: ptrHostname("example.com"),
+				Port:     gwapi.PortNumber(443),
+				Protocol: gwapi.HTTPSProtocolType,
+				TLS: &gwapi.GatewayTLSConfig{
+					Mode: ptrMode(gwapi.TLSModeTerminate),
+					CertificateRefs: []*gwapi.SecretObjectReference{{
+						Name:      "example-com",
+						Namespace: ptrNamespace("other"),
+					}},
 				},
 			},
-			wantErr: "spec.listeners[0].tls.certificateRef.kind: Unsupported value: \"SomeOtherKind\": supported values: \"Secret\"",
+			wantErr: "spec.listeners[0].tls.certificateRefs[0].namespace: Invalid value: \"other\": cross-namespace Secret references are not supported",
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotErr := validateGatewayListenerBlock(field.NewPath("spec", "listeners").Index(0), test.listener).ToAggregate()
+			gotErr := validateGatewayListenerBlock(field.NewPath("spec", "listeners").Index(0), "default", test.listener).ToAggregate()
 			if test.wantErr == "" {
 				assert.NoError(t, gotErr)
 			} else {
@@ -2793,7 +3535,7 @@ func Test_findCertificatesToBeRemoved(t *testing.T) {
 			ingLike: &gwapi.Gateway{
 				ObjectMeta: metav1.ObjectMeta{Name: "gw-2", Namespace: "default", UID: "gw-2"},
 				Spec: gwapi.GatewaySpec{Listeners: []gwapi.Listener{{
-					TLS: &gwapi.GatewayTLSConfig{CertificateRef: &gwapi.LocalObjectReference{Name: "secret-name"}},
+					TLS: &gwapi.GatewayTLSConfig{CertificateRefs: []*gwapi.SecretObjectReference{{Name: "secret-name"}}},
 				}}},
 			},
 			wantToBeRemoved: nil,
@@ -2812,7 +3554,7 @@ func Test_findCertificatesToBeRemoved(t *testing.T) {
 			ingLike: &gwapi.Gateway{
 				ObjectMeta: metav1.ObjectMeta{Name: "gw-1", Namespace: "default", UID: "gw-1"},
 				Spec: gwapi.GatewaySpec{Listeners: []gwapi.Listener{
-					{TLS: &gwapi.GatewayTLSConfig{CertificateRef: &gwapi.LocalObjectReference{Name: "not-secret-name"}}},
+					{TLS: &gwapi.GatewayTLSConfig{CertificateRefs: []*gwapi.SecretObjectReference{{Name: "not-secret-name"}}}},
 				}},
 			},
 			wantToBeRemoved: []string{"cert-1"},
@@ -2831,7 +3573,7 @@ func Test_findCertificatesToBeRemoved(t *testing.T) {
 			ingLike: &gwapi.Gateway{
 				ObjectMeta: metav1.ObjectMeta{Name: "gw-1", Namespace: "default", UID: "gw-1"},
 				Spec: gwapi.GatewaySpec{Listeners: []gwapi.Listener{
-					{TLS: &gwapi.GatewayTLSConfig{CertificateRef: &gwapi.LocalObjectReference{Name: "secret-name"}}},
+					{TLS: &gwapi.GatewayTLSConfig{CertificateRefs: []*gwapi.SecretObjectReference{{Name: "secret-name"}}}},
 				}},
 			},
 			wantToBeRemoved: nil,
@@ -2850,8 +3592,8 @@ func Test_secretNameUsedIn_nilPointerGateway(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "gw-1", Namespace: "default", UID: "gw-1"},
 		Spec: gwapi.GatewaySpec{Listeners: []gwapi.Listener{
 			{TLS: nil},
-			{TLS: &gwapi.GatewayTLSConfig{CertificateRef: nil}},
-			{TLS: &gwapi.GatewayTLSConfig{CertificateRef: &gwapi.LocalObjectReference{Name: "secret-name"}}},
+			{TLS: &gwapi.GatewayTLSConfig{CertificateRefs: nil}},
+			{TLS: &gwapi.GatewayTLSConfig{CertificateRefs: []*gwapi.SecretObjectReference{{Name: "secret-name"}}}},
 		}},
 	})
 	assert.Equal(t, true, got)
@@ -2860,7 +3602,7 @@ func Test_secretNameUsedIn_nilPointerGateway(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "gw-1", Namespace: "default", UID: "gw-1"},
 		Spec: gwapi.GatewaySpec{Listeners: []gwapi.Listener{
 			{TLS: nil},
-			{TLS: &gwapi.GatewayTLSConfig{CertificateRef: nil}},
+			{TLS: &gwapi.GatewayTLSConfig{CertificateRefs: nil}},
 		}},
 	})
 	assert.Equal(t, false, got)
@@ -21,10 +21,14 @@ import (
 	"crypto"
 	"crypto/x509"
 	"fmt"
+	"math/big"
 
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
+	"github.com/jetstack/cert-manager/internal/aia"
+	"github.com/jetstack/cert-manager/internal/ct"
+	"github.com/jetstack/cert-manager/internal/serial"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
@@ -43,6 +47,8 @@ const (
 
 type templateGenerator func(*cmapi.CertificateRequest) (*x509.Certificate, error)
 type signingFn func([]*x509.Certificate, crypto.Signer, *x509.Certificate) (pki.PEMBundle, error)
+type serialNumberFn func(context.Context, string, *cmapi.CAIssuerSerialNumber) (*big.Int, error)
+type chainCompleterFn func(context.Context, []*x509.Certificate) ([]*x509.Certificate, error)
 
 type CA struct {
 	issuerOptions controllerpkg.IssuerOptions
@@ -50,6 +56,16 @@ type CA struct {
 
 	reporter *crutil.Reporter
 
+	ctClientBuilder ct.ClientBuilder
+
+	// serialNumberFn generates serial numbers for issuers that configure a
+	// serial number policy.
+	serialNumberFn serialNumberFn
+
+	// chainCompleterFn completes the CA chain for issuers that fetch
+	// intermediate certificates. It is nil if AIA fetching is disabled.
+	chainCompleterFn chainCompleterFn
+
 	// Used for testing to get reproducible resulting certificates
 	templateGenerator templateGenerator
 	signingFn         signingFn
@@ -65,10 +81,18 @@ func init() {
 }
 
 func NewCA(ctx *controllerpkg.Context) *CA {
+	var completeChain chainCompleterFn
+	if ctx.IssuerOptions.EnableCAIssuerAIAFetching {
+		completeChain = aia.New().CompleteChain
+	}
+
 	return &CA{
 		issuerOptions:     ctx.IssuerOptions,
 		secretsLister:     ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:          crutil.NewReporter(ctx.Clock, ctx.Recorder),
+		ctClientBuilder:   ct.New,
+		serialNumberFn:    serial.New(ctx.Client).Next,
+		chainCompleterFn:  completeChain,
 		templateGenerator: pki.GenerateTemplateFromCertificateRequest,
 		signingFn:         pki.SignCSRTemplate,
 	}
@@ -118,9 +142,58 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		return nil, nil
 	}
 
+	// A certificate is not valid beyond the expiry of the CA that signed it,
+	// so don't claim otherwise. This matters for short-lived certificates
+	// signed by a CA which is itself a delegation certificate that is
+	// renewed from an upstream issuer.
+	if caCerts[0].NotAfter.Before(template.NotAfter) {
+		template.NotAfter = caCerts[0].NotAfter
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().CA.CRLDistributionPoints
 	template.OCSPServer = issuerObj.GetSpec().CA.OCSPServers
 
+	if policy := issuerObj.GetSpec().CA.SerialNumber; policy != nil {
+		template.SerialNumber, err = c.serialNumberFn(ctx, resourceNamespace, policy)
+		if err != nil {
+			message := "Error generating certificate serial number"
+			c.reporter.Pending(cr, err, "SerialNumberError", message)
+			log.Error(err, message)
+			return nil, err
+		}
+	}
+
+	if issuerObj.GetSpec().CA.FetchIntermediateCertificates {
+		if c.chainCompleterFn == nil {
+			log.V(logf.DebugLevel).Info("not fetching intermediate certificates as AIA fetching is disabled")
+		} else {
+			caCerts, err = c.chainCompleterFn(ctx, caCerts)
+			if err != nil {
+				message := "Error fetching intermediate CA certificates"
+				c.reporter.Pending(cr, err, "AIAFetchError", message)
+				log.Error(err, message)
+				return nil, err
+			}
+		}
+	}
+
+	if ctConfig := issuerObj.GetSpec().CA.CertificateTransparency; ctConfig != nil {
+		ctClient, err := c.ctClientBuilder(ctConfig)
+		if err != nil {
+			message := "Error initializing SCT hook client"
+			c.reporter.Pending(cr, err, "SCTHookError", message)
+			log.Error(err, message)
+			return nil, nil
+		}
+
+		if err := ct.EmbedSCTs(ctx, ctClient, caCerts, caKey, template); err != nil {
+			message := "Error obtaining SCTs from SCT hook"
+			c.reporter.Pending(cr, err, "SCTHookError", message)
+			log.Error(err, message)
+			return nil, err
+		}
+	}
+
 	bundle, err := c.signingFn(caCerts, caKey, template)
 	if err != nil {
 		message := "Error signing certificate"
@@ -133,6 +206,6 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 
 	return &issuerpkg.IssueResponse{
 		Certificate: bundle.ChainPEM,
-		CA:          bundle.CAPEM,
+		CA:          pki.WithNextTrustAnchors(bundle.CAPEM, issuerObj.GetStatus().CA),
 	}, nil
 }
@@ -36,10 +36,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	clientcorev1 "k8s.io/client-go/listers/core/v1"
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
+	"github.com/jetstack/cert-manager/internal/ct"
+	"github.com/jetstack/cert-manager/internal/serial"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -86,7 +89,7 @@ func generateSelfSignedCACert(t *testing.T, key crypto.Signer, name string) (*x5
 			CommonName: name,
 		},
 		NotBefore: time.Now(),
-		NotAfter:  time.Now().Add(time.Minute),
+		NotAfter:  time.Now().Add(time.Hour),
 		KeyUsage:  x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		PublicKey: key.Public(),
 		IsCA:      true,
@@ -476,6 +479,8 @@ func TestCA_Sign(t *testing.T) {
 		givenCASecret    *corev1.Secret
 		givenCAIssuer    cmapi.GenericIssuer
 		givenCR          *cmapi.CertificateRequest
+		givenSCTHook     *fakeSCTHook
+		givenChain       chainCompleterFn
 		assertSignedCert func(t *testing.T, got *x509.Certificate)
 		wantErr          string
 	}{
@@ -520,6 +525,26 @@ func TestCA_Sign(t *testing.T) {
 				assert.LessOrEqualf(t, deltaSec, 1., "expected a time delta lower than 1 second. Time expected='%s', got='%s'", expectNotAfter.String(), got.NotAfter.String())
 			},
 		},
+		"when the CertificateRequest has a duration longer than the CA's remaining validity, notAfter should be that of the CA": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+				gen.SetCertificateRequestDuration(&metav1.Duration{
+					Duration: 48 * time.Hour,
+				}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, rootCert.NotAfter, got.NotAfter)
+			},
+		},
 		"when the CertificateRequest has the isCA field set, it should appear on the signed ca": {
 			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
 			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
@@ -575,6 +600,110 @@ func TestCA_Sign(t *testing.T) {
 				assert.Equal(t, []string{"http://www.example.com/crl/test.crl"}, gotCA.CRLDistributionPoints)
 			},
 		},
+		"when the Issuer has certificateTransparency set, the SCTs returned by the hook should be embedded in the signed certificate": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:              "secret-1",
+				CertificateTransparency: &cmapi.CAIssuerCertificateTransparency{URL: "https://ct-hook.example.com"},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenSCTHook: &fakeSCTHook{scts: [][]byte{{0xaa, 0xbb}}},
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				var sctList []byte
+				for _, ext := range got.Extensions {
+					if ext.Id.Equal(pki.OIDExtensionCTSCTList) {
+						_, err := asn1.Unmarshal(ext.Value, &sctList)
+						require.NoError(t, err)
+					}
+				}
+				assert.Equal(t, []byte{0x00, 0x04, 0x00, 0x02, 0xaa, 0xbb}, sctList)
+			},
+		},
+		"when the Issuer has a monotonic serialNumber policy set, the serial number should come from the persisted counter": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+				SerialNumber: &cmapi.CAIssuerSerialNumber{
+					Type:                 cmapi.CAIssuerSerialNumberMonotonic,
+					Length:               8,
+					CounterConfigMapName: "serial-counter",
+				},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, big.NewInt(0x4000000000000001), got.SerialNumber)
+			},
+		},
+		"when the SCT hook fails, it should return an error": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:              "secret-1",
+				CertificateTransparency: &cmapi.CAIssuerCertificateTransparency{URL: "https://ct-hook.example.com"},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenSCTHook: &fakeSCTHook{err: errors.New("log unavailable")},
+			wantErr:      "log unavailable",
+		},
+		"when the Issuer fetches intermediate certificates and fetching fails, it should return an error": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:                    "secret-1",
+				FetchIntermediateCertificates: true,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenChain: func(context.Context, []*x509.Certificate) ([]*x509.Certificate, error) {
+				return nil, errors.New("fetch failed")
+			},
+			wantErr: "fetch failed",
+		},
+		"when the Issuer does not fetch intermediate certificates, the chain should not be completed": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenChain: func(context.Context, []*x509.Certificate) ([]*x509.Certificate, error) {
+				return nil, errors.New("unexpected fetch")
+			},
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, rootCert.Subject.String(), got.Issuer.String())
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -590,6 +719,11 @@ func TestCA_Sign(t *testing.T) {
 				secretsLister: testlisters.FakeSecretListerFrom(testlisters.NewFakeSecretLister(),
 					testlisters.SetFakeSecretNamespaceListerGet(test.givenCASecret, nil),
 				),
+				ctClientBuilder: func(*cmapi.CAIssuerCertificateTransparency) (ct.Interface, error) {
+					return test.givenSCTHook, nil
+				},
+				serialNumberFn:    serial.New(kubefake.NewSimpleClientset()).Next,
+				chainCompleterFn:  test.givenChain,
 				templateGenerator: pki.GenerateTemplateFromCertificateRequest,
 				signingFn:         pki.SignCSRTemplate,
 			}
@@ -610,6 +744,15 @@ func TestCA_Sign(t *testing.T) {
 	}
 }
 
+type fakeSCTHook struct {
+	scts [][]byte
+	err  error
+}
+
+func (f *fakeSCTHook) AddPreChain(context.Context, [][]byte) ([][]byte, error) {
+	return f.scts, f.err
+}
+
 // Returns a map that is meant to be used for creating a certificate Secret
 // that contains the fields "tls.crt" and "tls.key".
 func secretDataFor(t *testing.T, caKey *ecdsa.PrivateKey, caCrt *x509.Certificate) (secretData map[string][]byte) {
@@ -19,9 +19,11 @@ package certificaterequests
 import (
 	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	policyapi "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
 
@@ -66,7 +68,11 @@ func (c *Controller) certificatesRequestsForGenericIssuer(iss cmapi.GenericIssue
 			continue
 		}
 		if !isClusterIssuer {
-			if crt.Namespace != iss.GetObjectMeta().Namespace {
+			issuerNamespace := crt.Namespace
+			if crt.Spec.IssuerRef.Namespace != "" {
+				issuerNamespace = crt.Spec.IssuerRef.Namespace
+			}
+			if issuerNamespace != iss.GetObjectMeta().Namespace {
 				continue
 			}
 		}
@@ -78,3 +84,28 @@ func (c *Controller) certificatesRequestsForGenericIssuer(iss cmapi.GenericIssue
 
 	return affected, nil
 }
+
+// handleIssuerGrant re-queues the CertificateRequests referencing the Issuer
+// named in an IssuerGrant, so that requests waiting on a grant are processed
+// as soon as it is created, and requests are re-checked when it is revoked.
+func (c *Controller) handleIssuerGrant(obj interface{}) {
+	log := c.log.WithName("handleIssuerGrant")
+
+	grant, ok := obj.(*policyapi.IssuerGrant)
+	if !ok {
+		log.Error(nil, "object is not an IssuerGrant")
+		return
+	}
+
+	log = logf.WithResource(log, grant)
+	iss, err := c.issuerLister.Issuers(grant.Namespace).Get(grant.Spec.IssuerName)
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "error looking up issuer referenced by issuer grant")
+		return
+	}
+
+	c.handleGenericIssuer(iss)
+}
@@ -32,8 +32,10 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
 const (
@@ -57,6 +59,11 @@ type Controller struct {
 	// clientset used to update cert-manager API resources
 	cmClient cmclient.Interface
 
+	// statusPatcher and fieldManager are used to apply the status of
+	// certificate requests when the ServerSideApply feature is enabled
+	statusPatcher *statuspatch.Patcher
+	fieldManager  string
+
 	certificateRequestLister cmlisters.CertificateRequestLister
 
 	queue workqueue.RateLimitingInterface
@@ -85,6 +92,12 @@ type Controller struct {
 	clock clock.Clock
 
 	reporter *util.Reporter
+
+	// issuerOptions bounds the time taken by the issuer to sign each request
+	issuerOptions controllerpkg.IssuerOptions
+
+	// metrics records the time taken to issue certificate requests
+	metrics *metrics.Metrics
 }
 
 // New will construct a new certificaterequest controller using the given
@@ -113,7 +126,14 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	// the rate limiter is configured using the name this controller is
+	// registered under, e.g. certificaterequests-issuer-ca
+	controllerName := fmt.Sprintf("%s-issuer-%s", ControllerName, c.issuerType)
+	rateLimiter := ctx.WorkqueueOptions.RateLimiter(controllerName, controllerpkg.DefaultRateLimiterOptions)
+	c.queue = workqueue.NewNamedRateLimitingQueue(rateLimiter, ControllerName)
+
+	c.statusPatcher = ctx.StatusPatcher
+	c.fieldManager = controllerpkg.FieldManager(controllerName)
 
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
 	c.issuerLister = issuerInformer.Lister()
@@ -130,9 +150,12 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 		extraInformersMustSync = append(extraInformersMustSync, i.HasSynced)
 	}
 
+	issuerGrantInformer := ctx.SharedInformerFactory.Policy().V1alpha1().IssuerGrants()
+
 	mustSync := append([]cache.InformerSynced{
 		certificateRequestInformer.Informer().HasSynced,
 		issuerInformer.Informer().HasSynced,
+		issuerGrantInformer.Informer().HasSynced,
 	}, extraInformersMustSync...)
 
 	// if scoped to a single namespace
@@ -151,7 +174,9 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// register handler functions
 	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: c.queue})
+	ctx.RequeueOnNamespaceChange(certificateRequestInformer.Informer(), (&controllerpkg.QueuingEventHandler{Queue: c.queue}).Enqueue)
 	issuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.handleGenericIssuer})
+	issuerGrantInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.handleIssuerGrant})
 
 	// Ensure we catch extra informers that are owned by certificate requests
 	for _, i := range c.extraInformers {
@@ -161,14 +186,16 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	}
 
 	// create an issuer helper for reading generic issuers
-	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister)
+	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister, issuerGrantInformer.Lister())
 
 	// clock is used to set the FailureTime of failed CertificateRequests
 	c.clock = ctx.Clock
 	// recorder records events about resources to the Kubernetes api
 	c.recorder = ctx.Recorder
 	c.reporter = util.NewReporter(c.clock, c.recorder)
+	c.issuerOptions = ctx.IssuerOptions
 	c.cmClient = ctx.CMClient
+	c.metrics = ctx.Metrics
 
 	c.log.V(logf.DebugLevel).Info("new certificate request controller registered",
 		"type", c.issuerType)
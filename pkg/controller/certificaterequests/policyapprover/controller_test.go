@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyapprover
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/workqueue"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	policyapi "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+func TestProcessItem(t *testing.T) {
+	// now time is the current time at the start of the test (the clock is fixed)
+	now := time.Now()
+	metaNow := metav1.NewTime(now)
+
+	const username = "system:serviceaccount:team-a:builder"
+	csrPEM := mustGenerateCSR(t, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "app.example.com"},
+		DNSNames: []string{"app.example.com"},
+	})
+	request := func(conditions ...cmapi.CertificateRequestCondition) *cmapi.CertificateRequest {
+		return &cmapi.CertificateRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "test"},
+			Spec: cmapi.CertificateRequestSpec{
+				Request:   csrPEM,
+				Username:  username,
+				IssuerRef: cmmeta.ObjectReference{Name: "team-ca", Kind: "ClusterIssuer"},
+			},
+			Status: cmapi.CertificateRequestStatus{Conditions: conditions},
+		}
+	}
+	policy := func(name, namespace string, dnsNames ...string) *policyapi.CertificateRequestPolicy {
+		return &policyapi.CertificateRequestPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: policyapi.CertificateRequestPolicySpec{
+				ServiceAccounts: []policyapi.ServiceAccountSelector{{Namespace: namespace, Name: "*"}},
+				Allowed: &policyapi.CertificateRequestPolicyAllowed{
+					CommonName: dnsNames,
+					DNSNames:   dnsNames,
+					Usages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		// key that should be passed to ProcessItem.
+		// if not set, the 'namespace/name' of the 'CertificateRequest' field will be used.
+		// if neither is set, the key will be ""
+		key string
+
+		// CertificateRequest to be synced for the test.
+		// if not set, the 'key' will be passed to ProcessItem instead.
+		request *cmapi.CertificateRequest
+
+		// policies that exist in the cluster.
+		policies []*policyapi.CertificateRequestPolicy
+
+		// expectedEvent, if set, is an 'event string' that is expected to be fired.
+		expectedEvent string
+
+		// expectedCondition is the condition expected to be appended to the
+		// CertificateRequest resource if an Update is made.
+		// If nil, no update is expected.
+		expectedCondition *cmapi.CertificateRequestCondition
+
+		// err is the expected error text returned by the controller, if any.
+		err string
+	}{
+		"do nothing if an empty 'key' is used": {},
+		"do nothing if a key references a CertificateRequest that does not exist": {
+			key: "namespace/name",
+		},
+		"do nothing if CertificateRequest already has 'Approved' True condition": {
+			request: request(cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionApproved,
+				Status: cmmeta.ConditionTrue,
+			}),
+			policies: []*policyapi.CertificateRequestPolicy{policy("team-a", "team-a", "*.example.com")},
+		},
+		"do nothing if CertificateRequest already has 'Denied' True condition": {
+			request: request(cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionDenied,
+				Status: cmmeta.ConditionTrue,
+			}),
+			policies: []*policyapi.CertificateRequestPolicy{policy("team-a", "team-a", "*.example.com")},
+		},
+		"do nothing if CertificateRequest already has 'Ready' Issued condition": {
+			request: request(cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionReady,
+				Status: cmmeta.ConditionTrue,
+				Reason: cmapi.CertificateRequestReasonIssued,
+			}),
+		},
+		"approve CertificateRequest if a selecting policy permits it": {
+			request: request(),
+			policies: []*policyapi.CertificateRequestPolicy{
+				policy("a-restrictive", "team-a", "app.internal"),
+				policy("b-permissive", "team-a", "*.example.com"),
+			},
+			expectedCondition: &cmapi.CertificateRequestCondition{
+				Type:               cmapi.CertificateRequestConditionApproved,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             "policy.cert-manager.io",
+				Message:            `Approved by CertificateRequestPolicy "b-permissive"`,
+				LastTransitionTime: &metaNow,
+			},
+			expectedEvent: `Normal policy.cert-manager.io Approved by CertificateRequestPolicy "b-permissive"`,
+		},
+		"leave CertificateRequest pending if no policy selects the requester": {
+			request:  request(),
+			policies: []*policyapi.CertificateRequestPolicy{policy("team-b", "team-b", "*.example.com")},
+		},
+		"deny CertificateRequest with the violations of every selecting policy": {
+			request: request(),
+			policies: []*policyapi.CertificateRequestPolicy{
+				policy("team-a", "team-a", "app.internal"),
+				policy("team-b", "team-b", "*.example.com"),
+			},
+			expectedCondition: &cmapi.CertificateRequestCondition{
+				Type:               cmapi.CertificateRequestConditionDenied,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             "policy.cert-manager.io",
+				Message:            `Denied by all CertificateRequestPolicies selecting requester "system:serviceaccount:team-a:builder": CertificateRequestPolicy "team-a": commonName: "app.example.com" is not allowed, dnsNames: "app.example.com" is not allowed`,
+				LastTransitionTime: &metaNow,
+			},
+			expectedEvent: `Warning policy.cert-manager.io Denied by all CertificateRequestPolicies selecting requester "system:serviceaccount:team-a:builder": CertificateRequestPolicy "team-a": commonName: "app.example.com" is not allowed, dnsNames: "app.example.com" is not allowed`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:     t,
+				Clock: fakeclock.NewFakeClock(now),
+			}
+			if test.request != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.request)
+			}
+			for _, p := range test.policies {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, runtime.Object(p))
+			}
+			builder.Init()
+
+			c := new(Controller)
+			_, _, err := c.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.expectedCondition != nil {
+				if test.request == nil {
+					t.Fatal("cannot expect an Update operation if test.request is nil")
+				}
+				expectedRequest := test.request.DeepCopy()
+				expectedRequest.Status.Conditions = append(expectedRequest.Status.Conditions, *test.expectedCondition)
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						test.request.Namespace,
+						expectedRequest,
+					)),
+				)
+			}
+			if test.expectedEvent != "" {
+				builder.ExpectedEvents = []string{test.expectedEvent}
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key := test.key
+			if key == "" && test.request != nil {
+				key, err = controllerpkg.KeyFunc(test.request)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Call ProcessItem
+			err = c.ProcessItem(context.Background(), key)
+			switch {
+			case err != nil:
+				if test.err != err.Error() {
+					t.Errorf("error text did not match, got=%s, exp=%s", err.Error(), test.err)
+				}
+			default:
+				if test.err != "" {
+					t.Errorf("got no error but expected: %s", test.err)
+				}
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllReactorsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
+
+func TestHandlePolicy(t *testing.T) {
+	request := func(name string, conditions ...cmapi.CertificateRequestCondition) *cmapi.CertificateRequest {
+		return &cmapi.CertificateRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name},
+			Status:     cmapi.CertificateRequestStatus{Conditions: conditions},
+		}
+	}
+
+	builder := &testpkg.Builder{
+		T: t,
+		CertManagerObjects: []runtime.Object{
+			request("pending"),
+			request("approved", cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionApproved,
+				Status: cmmeta.ConditionTrue,
+			}),
+			request("denied", cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionDenied,
+				Status: cmmeta.ConditionTrue,
+			}),
+		},
+	}
+	builder.Init()
+
+	certificateRequestInformer := builder.SharedInformerFactory.Certmanager().V1().CertificateRequests()
+	c := &Controller{
+		log:                      logf.Log,
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		queue:                    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	defer c.queue.ShutDown()
+	certificateRequestInformer.Informer()
+
+	builder.Start()
+	defer builder.Stop()
+
+	// A policy being created re-queues the CertificateRequests that have
+	// been left pending, and only those.
+	c.handlePolicy(&policyapi.CertificateRequestPolicy{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+
+	if l := c.queue.Len(); l != 1 {
+		t.Fatalf("expected 1 queued CertificateRequest, got %d", l)
+	}
+	if key, _ := c.queue.Get(); key != "team-a/pending" {
+		t.Errorf("expected the pending CertificateRequest to be queued, got %q", key)
+	}
+}
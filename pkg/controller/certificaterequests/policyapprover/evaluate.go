@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyapprover
+
+import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	policyapi "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+)
+
+// variableReference matches a `$(variable)` reference in an allowed pattern.
+var variableReference = regexp.MustCompile(`\$\([a-zA-Z]+\)`)
+
+// defaultUsages are the key usages of a CertificateRequest that does not
+// request any usages.
+var defaultUsages = []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment}
+
+// selectsRequester returns true if the policy selects the ServiceAccount with
+// the given username.
+func selectsRequester(policy *policyapi.CertificateRequestPolicy, username string) bool {
+	namespace, name, err := serviceaccount.SplitUsername(username)
+	if err != nil {
+		return false
+	}
+
+	for _, sa := range policy.Spec.ServiceAccounts {
+		if (sa.Namespace == "*" || sa.Namespace == namespace) && (sa.Name == "*" || sa.Name == name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evaluate returns the reasons why the given CertificateRequest is not
+// permitted by the policy. The request is permitted if no violations are
+// returned.
+func evaluate(policy *policyapi.CertificateRequestPolicy, cr *cmapi.CertificateRequest, csr *x509.CertificateRequest) []string {
+	allowed := policy.Spec.Allowed
+	if allowed == nil {
+		allowed = &policyapi.CertificateRequestPolicyAllowed{}
+	}
+	subject := allowed.Subject
+	if subject == nil {
+		subject = &policyapi.CertificateRequestPolicyAllowedSubject{}
+	}
+
+	vars := identityVariables(cr)
+	var violations []string
+	checkValues := func(field string, values, patterns []string) {
+		patterns = expandPatterns(patterns, vars)
+		for _, value := range values {
+			if !matchesAny(patterns, value) {
+				violations = append(violations, fmt.Sprintf("%s: %q is not allowed", field, value))
+			}
+		}
+	}
+
+	if len(csr.Subject.CommonName) > 0 {
+		checkValues("commonName", []string{csr.Subject.CommonName}, allowed.CommonName)
+	}
+	checkValues("subject.organizations", csr.Subject.Organization, subject.Organizations)
+	checkValues("subject.countries", csr.Subject.Country, subject.Countries)
+	checkValues("subject.organizationalUnits", csr.Subject.OrganizationalUnit, subject.OrganizationalUnits)
+	checkValues("subject.localities", csr.Subject.Locality, subject.Localities)
+	checkValues("subject.provinces", csr.Subject.Province, subject.Provinces)
+	checkValues("subject.streetAddresses", csr.Subject.StreetAddress, subject.StreetAddresses)
+	checkValues("subject.postalCodes", csr.Subject.PostalCode, subject.PostalCodes)
+	if len(csr.Subject.SerialNumber) > 0 {
+		checkValues("subject.serialNumber", []string{csr.Subject.SerialNumber}, subject.SerialNumber)
+	}
+
+	checkValues("dnsNames", csr.DNSNames, allowed.DNSNames)
+	for _, ip := range csr.IPAddresses {
+		checkValues("ipAddresses", []string{ip.String()}, allowed.IPAddresses)
+	}
+	for _, uri := range csr.URIs {
+		checkValues("uris", []string{uri.String()}, allowed.URIs)
+	}
+	checkValues("emailAddresses", csr.EmailAddresses, allowed.EmailAddresses)
+
+	usages := cr.Spec.Usages
+	if len(usages) == 0 {
+		usages = defaultUsages
+	}
+	for _, usage := range usages {
+		if !containsUsage(allowed.Usages, usage) {
+			violations = append(violations, fmt.Sprintf("usages: %q is not allowed", usage))
+		}
+	}
+
+	if cr.Spec.IsCA && !allowed.IsCA {
+		violations = append(violations, "isCA: CA certificates are not allowed")
+	}
+
+	duration := cmapi.DefaultCertificateDuration
+	if cr.Spec.Duration != nil {
+		duration = cr.Spec.Duration.Duration
+	}
+	if min := policy.Spec.MinDuration; min != nil && duration < min.Duration {
+		violations = append(violations, fmt.Sprintf("duration: %s is shorter than the minimum of %s", duration, min.Duration))
+	}
+	if max := policy.Spec.MaxDuration; max != nil && duration > max.Duration {
+		violations = append(violations, fmt.Sprintf("duration: %s is longer than the maximum of %s", duration, max.Duration))
+	}
+
+	if len(policy.Spec.IssuerRefs) > 0 && !selectsIssuer(policy.Spec.IssuerRefs, cr) {
+		ref := cr.Spec.IssuerRef
+		violations = append(violations, fmt.Sprintf("issuerRef: %s %q in group %q is not allowed",
+			defaultString(ref.Kind, cmapi.IssuerKind), ref.Name, defaultString(ref.Group, certmanager.GroupName)))
+	}
+
+	return violations
+}
+
+// identityVariables returns the values of the identity variables that may be
+// referenced by the allowed patterns of a policy. The identity is taken from
+// the requester identity recorded in the status of the CertificateRequest,
+// falling back to the username of the requester.
+func identityVariables(cr *cmapi.CertificateRequest) map[string]string {
+	vars := make(map[string]string)
+	if identity := cr.Spec.RequesterIdentity; identity != nil {
+		vars["namespace"] = identity.Namespace
+		vars["serviceAccount"] = identity.ServiceAccount
+		vars["pod"] = identity.Pod
+	} else if namespace, name, err := serviceaccount.SplitUsername(cr.Spec.Username); err == nil {
+		vars["namespace"] = namespace
+		vars["serviceAccount"] = name
+	}
+
+	for k, v := range vars {
+		if len(v) == 0 {
+			delete(vars, k)
+		}
+	}
+
+	return vars
+}
+
+// expandPatterns replaces the `$(variable)` references in each pattern with
+// the value of the variable. Patterns that reference a variable with no value
+// are dropped so that they never match.
+func expandPatterns(patterns []string, vars map[string]string) []string {
+	var expanded []string
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "$(") {
+			expanded = append(expanded, pattern)
+			continue
+		}
+
+		resolved := true
+		pattern = variableReference.ReplaceAllStringFunc(pattern, func(ref string) string {
+			value, ok := vars[ref[2:len(ref)-1]]
+			if !ok {
+				resolved = false
+			}
+			return value
+		})
+		if resolved {
+			expanded = append(expanded, pattern)
+		}
+	}
+
+	return expanded
+}
+
+func selectsIssuer(selectors []policyapi.IssuerSelector, cr *cmapi.CertificateRequest) bool {
+	ref := cr.Spec.IssuerRef
+	kind := defaultString(ref.Kind, cmapi.IssuerKind)
+	group := defaultString(ref.Group, certmanager.GroupName)
+
+	for _, sel := range selectors {
+		if matchesOptional(sel.Name, ref.Name) && matchesOptional(sel.Kind, kind) && matchesOptional(sel.Group, group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsUsage(usages []cmapi.KeyUsage, usage cmapi.KeyUsage) bool {
+	for _, u := range usages {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesOptional returns true if the pattern is empty or matches value.
+func matchesOptional(pattern, value string) bool {
+	return len(pattern) == 0 || wildcardMatch(pattern, value)
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch returns true if value matches the pattern, where each `*` in
+// the pattern matches any sequence of characters.
+func wildcardMatch(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
+}
+
+func defaultString(s, def string) string {
+	if len(s) == 0 {
+		return def
+	}
+	return s
+}
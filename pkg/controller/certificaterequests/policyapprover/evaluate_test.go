@@ -0,0 +1,279 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyapprover
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	policyapi "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func mustGenerateCSR(t *testing.T, template *x509.CertificateRequest) []byte {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestSelectsRequester(t *testing.T) {
+	policy := &policyapi.CertificateRequestPolicy{
+		Spec: policyapi.CertificateRequestPolicySpec{
+			ServiceAccounts: []policyapi.ServiceAccountSelector{
+				{Namespace: "cert-manager", Name: "cert-manager"},
+				{Namespace: "team-a", Name: "*"},
+			},
+		},
+	}
+
+	tests := map[string]bool{
+		"system:serviceaccount:cert-manager:cert-manager": true,
+		"system:serviceaccount:cert-manager:other":        false,
+		"system:serviceaccount:team-a:builder":            true,
+		"system:serviceaccount:team-b:builder":            false,
+		"jane@example.com":                                false,
+	}
+	for username, exp := range tests {
+		t.Run(username, func(t *testing.T) {
+			if got := selectsRequester(policy, username); got != exp {
+				t.Errorf("unexpected result, exp=%t got=%t", exp, got)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	uri, _ := url.Parse("spiffe://cluster.local/ns/team-a/sa/builder")
+	csrPEM := mustGenerateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   "app.example.com",
+			Organization: []string{"Example"},
+		},
+		DNSNames:    []string{"app.example.com", "api.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:        []*url.URL{uri},
+	})
+	csr, err := pki.DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permissive := policyapi.CertificateRequestPolicySpec{
+		Allowed: &policyapi.CertificateRequestPolicyAllowed{
+			CommonName:  []string{"*.example.com"},
+			Subject:     &policyapi.CertificateRequestPolicyAllowedSubject{Organizations: []string{"Example"}},
+			DNSNames:    []string{"*.example.com"},
+			IPAddresses: []string{"10.0.0.*"},
+			URIs:        []string{"spiffe://cluster.local/ns/team-a/*"},
+			Usages:      []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageServerAuth},
+		},
+		MaxDuration: &metav1.Duration{Duration: 90 * 24 * time.Hour},
+		IssuerRefs:  []policyapi.IssuerSelector{{Name: "team-*", Kind: "ClusterIssuer"}},
+	}
+	request := cmapi.CertificateRequestSpec{
+		Request:   csrPEM,
+		IssuerRef: cmmeta.ObjectReference{Name: "team-ca", Kind: "ClusterIssuer"},
+	}
+
+	tests := map[string]struct {
+		spec          func(*policyapi.CertificateRequestPolicySpec)
+		request       func(*cmapi.CertificateRequestSpec)
+		expViolations []string
+	}{
+		"a permitted request has no violations": {},
+		"a policy without allowed attributes denies every requested attribute": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.Allowed = nil
+			},
+			expViolations: []string{
+				`commonName: "app.example.com" is not allowed`,
+				`subject.organizations: "Example" is not allowed`,
+				`dnsNames: "app.example.com" is not allowed`,
+				`dnsNames: "api.example.com" is not allowed`,
+				`ipAddresses: "10.0.0.1" is not allowed`,
+				`uris: "spiffe://cluster.local/ns/team-a/sa/builder" is not allowed`,
+				`usages: "digital signature" is not allowed`,
+				`usages: "key encipherment" is not allowed`,
+			},
+		},
+		"DNS names that do not match a pattern are denied": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.Allowed.DNSNames = []string{"app.example.com"}
+			},
+			expViolations: []string{`dnsNames: "api.example.com" is not allowed`},
+		},
+		"usages that are not allowed are denied": {
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.Usages = []cmapi.KeyUsage{cmapi.UsageServerAuth, cmapi.UsageClientAuth}
+			},
+			expViolations: []string{`usages: "client auth" is not allowed`},
+		},
+		"CA certificates are denied unless allowed": {
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.IsCA = true
+			},
+			expViolations: []string{"isCA: CA certificates are not allowed"},
+		},
+		"durations outside of the bounds are denied": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.MinDuration = &metav1.Duration{Duration: 24 * time.Hour}
+			},
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.Duration = &metav1.Duration{Duration: time.Hour}
+			},
+			expViolations: []string{"duration: 1h0m0s is shorter than the minimum of 24h0m0s"},
+		},
+		"the default duration is evaluated against the maximum": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.MaxDuration = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+			},
+			expViolations: []string{"duration: 2160h0m0s is longer than the maximum of 720h0m0s"},
+		},
+		"issuers that are not selected are denied": {
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.IssuerRef = cmmeta.ObjectReference{Name: "team-ca"}
+			},
+			expViolations: []string{`issuerRef: Issuer "team-ca" in group "cert-manager.io" is not allowed`},
+		},
+		"patterns may reference the identity of the requester": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.Allowed.URIs = []string{"spiffe://cluster.local/ns/$(namespace)/sa/$(serviceAccount)"}
+			},
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.Username = "system:serviceaccount:team-a:builder"
+			},
+		},
+		"patterns referencing the identity of another requester are denied": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.Allowed.URIs = []string{"spiffe://cluster.local/ns/$(namespace)/sa/$(serviceAccount)"}
+			},
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.Username = "system:serviceaccount:team-b:builder"
+			},
+			expViolations: []string{`uris: "spiffe://cluster.local/ns/team-a/sa/builder" is not allowed`},
+		},
+		"patterns referencing an unresolved identity never match": {
+			spec: func(spec *policyapi.CertificateRequestPolicySpec) {
+				spec.Allowed.URIs = []string{"spiffe://cluster.local/ns/$(namespace)/*"}
+			},
+			request: func(req *cmapi.CertificateRequestSpec) {
+				req.Username = "jane@example.com"
+			},
+			expViolations: []string{`uris: "spiffe://cluster.local/ns/team-a/sa/builder" is not allowed`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := &policyapi.CertificateRequestPolicy{Spec: *permissive.DeepCopy()}
+			if test.spec != nil {
+				test.spec(&policy.Spec)
+			}
+			cr := &cmapi.CertificateRequest{Spec: *request.DeepCopy()}
+			if test.request != nil {
+				test.request(&cr.Spec)
+			}
+
+			violations := evaluate(policy, cr, csr)
+			if !reflect.DeepEqual(violations, test.expViolations) {
+				t.Errorf("unexpected violations, exp=%q got=%q", test.expViolations, violations)
+			}
+		})
+	}
+}
+
+func TestExpandPatterns(t *testing.T) {
+	cr := &cmapi.CertificateRequest{
+		Spec: cmapi.CertificateRequestSpec{
+			Username: "system:serviceaccount:team-b:other",
+			RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+				Namespace:      "team-a",
+				ServiceAccount: "builder",
+				Pod:            "builder-1",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		patterns, exp []string
+	}{
+		"patterns without references are unchanged": {
+			patterns: []string{"*.example.com", "app.example.com"},
+			exp:      []string{"*.example.com", "app.example.com"},
+		},
+		"references are replaced with the recorded requester identity": {
+			patterns: []string{"$(pod).$(serviceAccount).$(namespace).svc"},
+			exp:      []string{"builder-1.builder.team-a.svc"},
+		},
+		"patterns with unknown references are dropped": {
+			patterns: []string{"$(node).example.com", "$(namespace).example.com"},
+			exp:      []string{"team-a.example.com"},
+		},
+	}
+
+	vars := identityVariables(cr)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := expandPatterns(test.patterns, vars); !reflect.DeepEqual(got, test.exp) {
+				t.Errorf("unexpected patterns, exp=%q got=%q", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		exp            bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "www.example.com", false},
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*", "anything", true},
+		{"app-*.example.com", "app-1.example.com", true},
+		{"app-*.example.com", "web-1.example.com", false},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*bc", "abc", true},
+		{"ab*bc", "abc", false},
+	}
+	for _, test := range tests {
+		if got := wildcardMatch(test.pattern, test.value); got != test.exp {
+			t.Errorf("wildcardMatch(%q, %q): exp=%t got=%t", test.pattern, test.value, test.exp, got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyapprover
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/apis/policy"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// Reason is the reason of the Approved and Denied conditions set by this
+	// controller.
+	Reason = policy.GroupName
+)
+
+// Sync evaluates synced CertificateRequests against the
+// CertificateRequestPolicies selecting the requester, and sets the "Approved"
+// condition to True if any policy permits the request, or the "Denied"
+// condition to True with the violations of each policy otherwise. Requests
+// that no policy selects are left pending, so that they are approved or
+// denied once a policy selecting the requester is created. If the "Denied",
+// "Approved" or "Ready" condition already exists, exit early.
+func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (err error) {
+	log := logf.FromContext(ctx, "policy-approver")
+
+	switch {
+	case
+		// If the CertificateRequest has already been approved, exit early.
+		apiutil.CertificateRequestIsApproved(cr),
+
+		// If the CertificateRequest has already been denied, exit early.
+		apiutil.CertificateRequestIsDenied(cr),
+
+		// If the CertificateRequest is "Issued" or "Failed", exit early.
+		apiutil.CertificateRequestReadyReason(cr) == cmapi.CertificateRequestReasonFailed,
+		apiutil.CertificateRequestReadyReason(cr) == cmapi.CertificateRequestReasonIssued:
+		return nil
+	}
+
+	policies, err := c.policyLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	csr, err := pki.DecodeX509CertificateRequestBytes(cr.Spec.Request)
+	if err != nil {
+		return c.deny(ctx, cr, fmt.Sprintf("Failed to decode certificate request: %s", err))
+	}
+
+	var denials []string
+	for _, policy := range policies {
+		if !selectsRequester(policy, cr.Spec.Username) {
+			continue
+		}
+
+		violations := evaluate(policy, cr, csr)
+		if len(violations) == 0 {
+			log.V(logf.DebugLevel).Info("approving certificate request", "policy", policy.Name)
+			return c.approve(ctx, cr, fmt.Sprintf("Approved by CertificateRequestPolicy %q", policy.Name))
+		}
+
+		denials = append(denials, fmt.Sprintf("CertificateRequestPolicy %q: %s", policy.Name, strings.Join(violations, ", ")))
+	}
+
+	if len(denials) == 0 {
+		log.V(logf.DebugLevel).Info("no certificate request policy selects the requester, leaving certificate request pending", "username", cr.Spec.Username)
+		return nil
+	}
+
+	return c.deny(ctx, cr, fmt.Sprintf("Denied by all CertificateRequestPolicies selecting requester %q: %s", cr.Spec.Username, strings.Join(denials, "; ")))
+}
+
+func (c *Controller) approve(ctx context.Context, cr *cmapi.CertificateRequest, message string) error {
+	return c.setCondition(ctx, cr, cmapi.CertificateRequestConditionApproved, corev1.EventTypeNormal, message)
+}
+
+func (c *Controller) deny(ctx context.Context, cr *cmapi.CertificateRequest, message string) error {
+	logf.FromContext(ctx).V(logf.DebugLevel).Info("denying certificate request", "reason", message)
+	return c.setCondition(ctx, cr, cmapi.CertificateRequestConditionDenied, corev1.EventTypeWarning, message)
+}
+
+func (c *Controller) setCondition(ctx context.Context, cr *cmapi.CertificateRequest, conditionType cmapi.CertificateRequestConditionType, eventType, message string) error {
+	cr = cr.DeepCopy()
+	apiutil.SetCertificateRequestCondition(cr, conditionType, cmmeta.ConditionTrue, Reason, message)
+
+	if _, err := c.cmClient.CertmanagerV1().CertificateRequests(cr.Namespace).UpdateStatus(ctx, cr, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	c.recorder.Event(cr, eventType, Reason, message)
+
+	return nil
+}
@@ -130,6 +130,13 @@ func (s *SelfSigned) Sign(ctx context.Context, cr *cmapi.CertificateRequest, iss
 		return nil, nil
 	}
 
+	if err := applyRequestedParameters(template, cr); err != nil {
+		message := "Error applying requested certificate parameters"
+		s.reporter.Failed(cr, err, "ErrorGenerating", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().SelfSigned.CRLDistributionPoints
 
 	if template.Subject.String() == "" {
@@ -181,3 +188,27 @@ func (s *SelfSigned) Sign(ctx context.Context, cr *cmapi.CertificateRequest, iss
 		CA:          certPem,
 	}, nil
 }
+
+// applyRequestedParameters updates the template with the duration requested
+// on the CertificateRequest, and with the usages and CA constraints requested
+// by its CSR and annotations. Since the requester holds the signing key of a
+// self-signed certificate, these are honoured in full.
+func applyRequestedParameters(template *x509.Certificate, cr *cmapi.CertificateRequest) error {
+	duration, err := pki.DurationFromCertificateRequest(cr)
+	if err != nil {
+		return err
+	}
+	template.NotAfter = template.NotBefore.Add(duration)
+
+	if err := pki.ApplyCSRRequestedExtensions(template, cr.Spec.Request); err != nil {
+		return err
+	}
+
+	if maxPathLen, ok := cr.Annotations[cmapi.CertificateRequestMaxPathLenAnnotationKey]; ok {
+		if err := pki.ApplyMaxPathLen(template, maxPathLen); err != nil {
+			return fmt.Errorf("annotation %q: %w", cmapi.CertificateRequestMaxPathLenAnnotationKey, err)
+		}
+	}
+
+	return nil
+}
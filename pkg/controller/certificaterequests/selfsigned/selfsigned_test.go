@@ -170,6 +170,12 @@ func TestSign(t *testing.T) {
 		gen.SetCertificateRequestCSR(csrEmptyCertPEM),
 	)
 
+	maxPathLenCR := gen.CertificateRequestFrom(baseCR,
+		gen.AddCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestMaxPathLenAnnotationKey: "1",
+		}),
+	)
+
 	templateRSA, err := pki.GenerateTemplateFromCertificateRequest(baseCR)
 	if err != nil {
 		t.Error(err)
@@ -449,6 +455,33 @@ func TestSign(t *testing.T) {
 				},
 			},
 		},
+		"a CertificateRequest requesting a maximum path length for a non-CA certificate should fail": {
+			certificateRequest: maxPathLenCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				KubeObjects:        []runtime.Object{rsaKeySecret},
+				CertManagerObjects: []runtime.Object{maxPathLenCR.DeepCopy(), baseIssuer},
+				ExpectedEvents: []string{
+					`Warning ErrorGenerating Error applying requested certificate parameters: annotation "cert-manager.io/request-max-path-len": a maximum path length can only be set on CA certificates`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(maxPathLenCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            `Error applying requested certificate parameters: annotation "cert-manager.io/request-max-path-len": a maximum path length can only be set on CA certificates`,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+		},
 		"if signing fails then should report failure": {
 			certificateRequest: baseCR.DeepCopy(),
 			signingFn: func(*x509.Certificate, *x509.Certificate, crypto.PublicKey, interface{}) ([]byte, *x509.Certificate, error) {
@@ -649,3 +682,89 @@ func runTest(t *testing.T, test testT) {
 
 	test.builder.CheckAndFinish(err)
 }
+
+func TestApplyRequestedParameters(t *testing.T) {
+	sk, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := generateCSR(t, sk, x509.ECDSAWithSHA256, "test")
+
+	tests := map[string]struct {
+		cr             *cmapi.CertificateRequest
+		expNotAfter    time.Duration
+		expIsCA        bool
+		expMaxPathLen  int
+		expMaxPathZero bool
+		expErr         bool
+	}{
+		"spec.duration should be used over the duration annotation": {
+			cr: gen.CertificateRequest("test",
+				gen.SetCertificateRequestCSR(csrPEM),
+				gen.SetCertificateRequestDuration(&metav1.Duration{Duration: time.Hour}),
+				gen.AddCertificateRequestAnnotations(map[string]string{
+					cmapi.CertificateRequestDurationAnnotationKey: "2h",
+				}),
+			),
+			expNotAfter: time.Hour,
+		},
+		"the duration annotation should be used if spec.duration is not set": {
+			cr: gen.CertificateRequest("test",
+				gen.SetCertificateRequestCSR(csrPEM),
+				gen.AddCertificateRequestAnnotations(map[string]string{
+					cmapi.CertificateRequestDurationAnnotationKey: "2h",
+				}),
+			),
+			expNotAfter: 2 * time.Hour,
+		},
+		"an invalid duration annotation should error": {
+			cr: gen.CertificateRequest("test",
+				gen.SetCertificateRequestCSR(csrPEM),
+				gen.AddCertificateRequestAnnotations(map[string]string{
+					cmapi.CertificateRequestDurationAnnotationKey: "foo",
+				}),
+			),
+			expErr: true,
+		},
+		"the maximum path length annotation should be set on a CA certificate": {
+			cr: gen.CertificateRequest("test",
+				gen.SetCertificateRequestCSR(csrPEM),
+				gen.SetCertificateRequestIsCA(true),
+				gen.AddCertificateRequestAnnotations(map[string]string{
+					cmapi.CertificateRequestMaxPathLenAnnotationKey: "0",
+				}),
+			),
+			expNotAfter:    cmapi.DefaultCertificateDuration,
+			expIsCA:        true,
+			expMaxPathZero: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			template, err := pki.GenerateTemplateFromCertificateRequest(test.cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = applyRequestedParameters(template, test.cr)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+
+			if got := template.NotAfter.Sub(template.NotBefore); got != test.expNotAfter {
+				t.Errorf("unexpected duration, exp=%s got=%s", test.expNotAfter, got)
+			}
+			if template.IsCA != test.expIsCA {
+				t.Errorf("unexpected isCA, exp=%t got=%t", test.expIsCA, template.IsCA)
+			}
+			if template.MaxPathLen != test.expMaxPathLen || template.MaxPathLenZero != test.expMaxPathZero {
+				t.Errorf("unexpected maximum path length, exp=%d/%t got=%d/%t",
+					test.expMaxPathLen, test.expMaxPathZero, template.MaxPathLen, template.MaxPathLenZero)
+			}
+		})
+	}
+}
@@ -18,20 +18,29 @@ package certificaterequests
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/kr/pretty"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/tracing"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -53,7 +62,9 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 	defer func() {
 		if _, saveErr := c.updateCertificateRequestStatusAndAnnotations(ctx, cr, crCopy); saveErr != nil {
 			err = utilerrors.NewAggregate([]error{saveErr, err})
+			return
 		}
+		c.observeIssuance(ctx, cr, crCopy)
 	}()
 
 	// If CertificateRequest has been denied, mark the CertificateRequest as
@@ -88,6 +99,13 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 		return nil
 	}
 
+	if errors.Is(err, issuer.ErrIssuerNotGranted) {
+		c.reporter.Pending(crCopy, err, "IssuerNotGranted",
+			fmt.Sprintf("Referenced %q in namespace %q has not been granted to this namespace by an IssuerGrant",
+				apiutil.IssuerKind(crCopy.Spec.IssuerRef), crCopy.Spec.IssuerRef.Namespace))
+		return nil
+	}
+
 	if err != nil {
 		log.Error(err, "failed to get issuer")
 		return err
@@ -111,6 +129,12 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 		return nil
 	}
 
+	if err := c.issuerOptions.CheckIssuerType(issuerObj); err != nil {
+		c.reporter.Pending(crCopy, err, controllerpkg.ReasonIssuerTypeNotAllowed,
+			"Referenced issuer type is not allowed in this cluster")
+		return nil
+	}
+
 	// check ready condition
 	if !apiutil.IssuerHasCondition(issuerObj, cmapi.IssuerCondition{
 		Type:   cmapi.IssuerConditionReady,
@@ -128,10 +152,38 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 		return nil
 	}
 
+	if !apiutil.IssuerAllowsWildcards(issuerObj) {
+		// Requests that cannot be decoded are left for the issuer to reject.
+		if csr, err := pki.DecodeX509CertificateRequestBytes(crCopy.Spec.Request); err == nil {
+			if wildcards := apiutil.WildcardDNSNames(append([]string{csr.Subject.CommonName}, csr.DNSNames...)); len(wildcards) > 0 {
+				err := fmt.Errorf("wildcard DNS names are not allowed by the issuer: %s", strings.Join(wildcards, ", "))
+				c.reporter.Failed(crCopy, err, "WildcardNotAllowed", "Referenced issuer does not allow wildcard certificates")
+				return nil
+			}
+		}
+	}
+
+	if issuerObj.GetSpec().SPIFFE != nil {
+		// Requests that cannot be decoded are left for the issuer to reject.
+		if csr, err := pki.DecodeX509CertificateRequestBytes(crCopy.Spec.Request); err == nil {
+			if violations := apiutil.SPIFFEPolicyViolations(issuerObj, csr.URIs); len(violations) > 0 {
+				err := fmt.Errorf("SPIFFE IDs are not allowed by the issuer: %s", strings.Join(violations, "; "))
+				c.reporter.Failed(crCopy, err, "SPIFFEIDNotAllowed", "Referenced issuer does not allow the requested SPIFFE IDs")
+				return nil
+			}
+		}
+	}
+
 	dbg.Info("invoking sign function as existing certificate does not exist")
 
-	// Attempt to call the Sign function on our issuer
-	resp, err := c.issuer.Sign(ctx, crCopy, issuerObj)
+	// Attempt to call the Sign function on our issuer. The span covers the
+	// time spent waiting on the CA, and is the parent of any resources the
+	// issuer creates to complete the request.
+	ctx, span := tracing.Start(ctx, "SignCertificateRequest", crCopy)
+	signCtx, cancel := c.issuerOptions.SignContext(ctx)
+	defer cancel()
+	resp, err := c.issuer.Sign(signCtx, crCopy, issuerObj)
+	tracing.End(span, err)
 	if err != nil {
 		log.Error(err, "error issuing certificate request")
 		return err
@@ -161,6 +213,23 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 	return nil
 }
 
+// observeIssuance records the issuance duration of a CertificateRequest once
+// it has been issued or has failed. Denied requests are not recorded, since
+// they are never passed to an issuer.
+func (c *Controller) observeIssuance(ctx context.Context, old, new *cmapi.CertificateRequest) {
+	reason := apiutil.CertificateRequestReadyReason(new)
+	if reason == apiutil.CertificateRequestReadyReason(old) {
+		return
+	}
+
+	switch reason {
+	case cmapi.CertificateRequestReasonIssued:
+		c.metrics.ObserveCertificateRequestIssuance(ctx, new, metrics.IssuanceResultIssued)
+	case cmapi.CertificateRequestReasonFailed:
+		c.metrics.ObserveCertificateRequestIssuance(ctx, new, metrics.IssuanceResultFailed)
+	}
+}
+
 func (c *Controller) updateCertificateRequestStatusAndAnnotations(ctx context.Context, old, new *cmapi.CertificateRequest) (*cmapi.CertificateRequest, error) {
 	log := logf.FromContext(ctx, "updateStatus")
 
@@ -175,5 +244,12 @@ func (c *Controller) updateCertificateRequestStatusAndAnnotations(ctx context.Co
 	}
 
 	log.V(logf.DebugLevel).Info("updating resource due to change in status", "diff", pretty.Diff(old.Status, new.Status))
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		key := "certificaterequests/" + old.Namespace + "/" + old.Name
+		return nil, c.statusPatcher.Apply(ctx, key, certificateRequestGvk, old, c.fieldManager, old.Status, new.Status, func(ctx context.Context, pt types.PatchType, patch []byte, opts metav1.PatchOptions) error {
+			_, err := c.cmClient.CertmanagerV1().CertificateRequests(old.Namespace).Patch(ctx, old.Name, pt, patch, opts, "status")
+			return err
+		})
+	}
 	return c.cmClient.CertmanagerV1().CertificateRequests(new.Namespace).UpdateStatus(ctx, new, metav1.UpdateOptions{})
 }
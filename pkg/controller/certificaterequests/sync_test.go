@@ -26,6 +26,7 @@ import (
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
+	"net/url"
 	"testing"
 	"time"
 
@@ -168,6 +169,24 @@ func TestSync(t *testing.T) {
 	certECPEM := generateSelfSignedCert(t, baseCREC, skEC, fixedClockStart, fixedClockStart.Add(time.Hour*12))
 	certECPEMExpired := generateSelfSignedCert(t, baseCREC, skEC, fixedClockStart.Add(-time.Hour*13), fixedClockStart.Add(-time.Hour*12))
 
+	csrWildcardPEM, _, err := gen.CSR(x509.RSA, gen.SetCSRDNSNames("example.com", "*.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	noWildcardsIssuer := gen.IssuerFrom(baseIssuer, gen.SetIssuerAllowWildcards(false))
+	baseCRWildcard := gen.CertificateRequestFrom(baseCR,
+		gen.SetCertificateRequestCSR(csrWildcardPEM),
+	)
+
+	csrSPIFFEPEM, _, err := gen.CSR(x509.RSA, gen.SetCSRURIs(&url.URL{Scheme: "spiffe", Host: "example.org", Path: "/ns/default/sa/default"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spiffeIssuer := gen.IssuerFrom(baseIssuer, gen.SetIssuerSPIFFETrustDomains("cluster.local"))
+	baseCRSPIFFE := gen.CertificateRequestFrom(baseCR,
+		gen.SetCertificateRequestCSR(csrSPIFFEPEM),
+	)
+
 	tests := map[string]testT{
 		"should return nil (no action) if group name if not 'cert-manager.io' or ''": {
 			certificateRequest: gen.CertificateRequestFrom(baseCR,
@@ -561,6 +580,84 @@ func TestSync(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		"should fail if the request contains a wildcard DNS name and the issuer does not allow wildcards": {
+			certificateRequest: baseCRWildcard.DeepCopy(),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{noWildcardsIssuer, baseCRWildcard.DeepCopy()},
+				ExpectedEvents: []string{
+					"Warning WildcardNotAllowed Referenced issuer does not allow wildcard certificates: wildcard DNS names are not allowed by the issuer: *.example.com",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCRWildcard,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            "Referenced issuer does not allow wildcard certificates: wildcard DNS names are not allowed by the issuer: *.example.com",
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
+		"should call sign if the request does not contain a wildcard DNS name and the issuer does not allow wildcards": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return nil, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{noWildcardsIssuer, baseCR.DeepCopy()},
+				ExpectedEvents:     []string{},
+				ExpectedActions:    []testpkg.Action{},
+			},
+		},
+		"should fail if the request contains a SPIFFE ID in a trust domain that the issuer does not allow": {
+			certificateRequest: baseCRSPIFFE.DeepCopy(),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{spiffeIssuer, baseCRSPIFFE.DeepCopy()},
+				ExpectedEvents: []string{
+					`Warning SPIFFEIDNotAllowed Referenced issuer does not allow the requested SPIFFE IDs: SPIFFE IDs are not allowed by the issuer: SPIFFE ID "spiffe://example.org/ns/default/sa/default" is not in an allowed trust domain`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCRSPIFFE,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            `Referenced issuer does not allow the requested SPIFFE IDs: SPIFFE IDs are not allowed by the issuer: SPIFFE ID "spiffe://example.org/ns/default/sa/default" is not in an allowed trust domain`,
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
+		"should call sign if the request does not contain a SPIFFE ID and the issuer has a SPIFFE policy": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return nil, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{spiffeIssuer, baseCR.DeepCopy()},
+				ExpectedEvents:     []string{},
+				ExpectedActions:    []testpkg.Action{},
+			},
+		},
 		"if calling sign returns a response but the certificate is badly formed then we fail": {
 			certificateRequest: baseCR.DeepCopy(),
 			issuerImpl: &fake.Issuer{
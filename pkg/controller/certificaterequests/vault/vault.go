@@ -18,6 +18,7 @@ package vault
 
 import (
 	"context"
+	"errors"
 
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -41,6 +42,7 @@ const (
 // pkg/controller/certificaterequests.Issuer interface.
 type Vault struct {
 	issuerOptions controllerpkg.IssuerOptions
+	createTokenFn func(ns string) vaultinternal.CreateToken
 	secretsLister corelisters.SecretLister
 	reporter      *crutil.Reporter
 
@@ -59,7 +61,10 @@ func init() {
 // NewVault returns a new Vault instance with the given controller context.
 func NewVault(ctx *controllerpkg.Context) *Vault {
 	return &Vault{
-		issuerOptions:      ctx.IssuerOptions,
+		issuerOptions: ctx.IssuerOptions,
+		createTokenFn: func(ns string) vaultinternal.CreateToken {
+			return ctx.Client.CoreV1().ServiceAccounts(ns).CreateToken
+		},
 		secretsLister:      ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:           crutil.NewReporter(ctx.Clock, ctx.Recorder),
 		vaultClientBuilder: vaultinternal.New,
@@ -72,9 +77,19 @@ func (v *Vault) Sign(ctx context.Context, cr *v1.CertificateRequest, issuerObj v
 	log := logf.FromContext(ctx, "sign")
 	log = logf.WithRelatedResource(log, issuerObj)
 
+	vaultNamespace, err := vaultinternal.RequestedNamespace(issuerObj.GetSpec().Vault, cr.Annotations[v1.VaultNamespaceAnnotationKey])
+	if err != nil {
+		message := "Requested Vault namespace is not allowed"
+
+		v.reporter.Failed(cr, err, "VaultNamespaceNotAllowed", message)
+		log.Error(err, message)
+
+		return nil, nil
+	}
+
 	resourceNamespace := v.issuerOptions.ResourceNamespace(issuerObj)
 
-	client, err := v.vaultClientBuilder(resourceNamespace, v.secretsLister, issuerObj)
+	client, err := v.vaultClientBuilder(ctx, resourceNamespace, v.createTokenFn, v.secretsLister, issuerObj)
 	if k8sErrors.IsNotFound(err) {
 		message := "Required secret resource not found"
 
@@ -92,7 +107,16 @@ func (v *Vault) Sign(ctx context.Context, cr *v1.CertificateRequest, issuerObj v
 	}
 
 	certDuration := apiutil.DefaultCertDuration(cr.Spec.Duration)
-	certPem, caPem, err := client.Sign(cr.Spec.Request, certDuration)
+	certPem, caPem, err := client.Sign(ctx, cr.Spec.Request, certDuration, vaultNamespace)
+	var policyErr *vaultinternal.PolicyError
+	if errors.As(err, &policyErr) {
+		message := "Vault refused to sign certificate"
+
+		v.reporter.Failed(cr, err, "VaultPolicyViolation", message)
+		log.Error(err, message)
+
+		return nil, nil
+	}
 	if err != nil {
 		message := "Vault failed to sign certificate"
 
@@ -414,6 +414,95 @@ func TestSign(t *testing.T) {
 			},
 			fakeVault: fakevault.New().WithSign(nil, nil, errors.New("failed to sign")),
 		},
+		"a client that is refused by the Vault policy should report the policy violation": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{tokenSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), gen.IssuerFrom(baseIssuer,
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Auth: cmapi.VaultAuth{
+							TokenSecretRef: &cmmeta.SecretKeySelector{
+								Key: "my-token-key",
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "token-secret",
+								},
+							},
+						},
+					}),
+				)},
+				ExpectedEvents: []string{
+					"Warning VaultPolicyViolation Vault refused to sign certificate: vault refused to sign the request (HTTP 400): common name example.com not allowed by this role",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            "Vault refused to sign certificate: vault refused to sign the request (HTTP 400): common name example.com not allowed by this role",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+			fakeVault: fakevault.New().WithSign(nil, nil, &internalvault.PolicyError{
+				StatusCode: 400,
+				Errors:     []string{"common name example.com not allowed by this role"},
+			}),
+		},
+		"a request selecting a Vault namespace that is not allowed should report fail": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR,
+				gen.AddCertificateRequestAnnotations(map[string]string{cmapi.VaultNamespaceAnnotationKey: "tenant-b"}),
+			),
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{tokenSecret},
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateRequestFrom(baseCR,
+						gen.AddCertificateRequestAnnotations(map[string]string{cmapi.VaultNamespaceAnnotationKey: "tenant-b"}),
+					),
+					gen.IssuerFrom(baseIssuer,
+						gen.SetIssuerVault(cmapi.VaultIssuer{
+							AllowedNamespaces: []string{"tenant-a"},
+							Auth: cmapi.VaultAuth{
+								TokenSecretRef: &cmmeta.SecretKeySelector{
+									Key: "my-token-key",
+									LocalObjectReference: cmmeta.LocalObjectReference{
+										Name: "token-secret",
+									},
+								},
+							},
+						}),
+					)},
+				ExpectedEvents: []string{
+					`Warning VaultNamespaceNotAllowed Requested Vault namespace is not allowed: Vault namespace "tenant-b" is not listed in the issuer's allowedNamespaces`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.AddCertificateRequestAnnotations(map[string]string{cmapi.VaultNamespaceAnnotationKey: "tenant-b"}),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            `Requested Vault namespace is not allowed: Vault namespace "tenant-b" is not listed in the issuer's allowedNamespaces`,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+			fakeVault: fakevault.New(),
+		},
 		"a client with a token secret referenced with token and signs should return certificate": {
 			certificateRequest: baseCR,
 			builder: &testpkg.Builder{
@@ -525,7 +614,7 @@ func runTest(t *testing.T, test testT) {
 	vault := NewVault(test.builder.Context)
 
 	if test.fakeVault != nil {
-		vault.vaultClientBuilder = func(ns string, sl corelisters.SecretLister,
+		vault.vaultClientBuilder = func(_ context.Context, ns string, _ func(ns string) internalvault.CreateToken, sl corelisters.SecretLister,
 			iss cmapi.GenericIssuer) (internalvault.Interface, error) {
 			return test.fakeVault.New(ns, sl, iss)
 		}
@@ -67,7 +67,7 @@ func NewVenafi(ctx *controllerpkg.Context) *Venafi {
 		issuerOptions: ctx.IssuerOptions,
 		secretsLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:      crutil.NewReporter(ctx.Clock, ctx.Recorder),
-		clientBuilder: venaficlient.New,
+		clientBuilder: venaficlient.NewBuilder(ctx.Client.CoreV1()),
 		cmClient:      ctx.CMClient,
 	}
 }
@@ -113,7 +113,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 
 	// check if the pickup ID annotation is there, if not set it up.
 	if pickupID == "" {
-		pickupID, err = client.RequestCertificate(cr.Spec.Request, duration, customFields)
+		pickupID, err = client.RequestCertificate(ctx, cr.Spec.Request, duration, customFields)
 		// Check some known error types
 		if err != nil {
 			switch err.(type) {
@@ -124,6 +124,12 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 
 				return nil, nil
 
+			case venaficlient.ErrPolicyViolation:
+				v.reporter.Failed(cr, err, "PolicyViolation", err.Error())
+				log.Error(err, err.Error())
+
+				return nil, nil
+
 			default:
 				message := "Failed to request venafi certificate"
 
@@ -141,7 +147,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 		return nil, nil
 	}
 
-	certPem, err := client.RetrieveCertificate(pickupID, cr.Spec.Request, duration, customFields)
+	certPem, err := client.RetrieveCertificate(ctx, pickupID, cr.Spec.Request, duration, customFields)
 	if err != nil {
 		switch err.(type) {
 		case endpoint.ErrCertificatePending, endpoint.ErrRetrieveCertificateTimeout:
@@ -271,6 +271,12 @@ func TestSign(t *testing.T) {
 		},
 	}
 
+	clientReturnsPolicyViolation := &internalvenafifake.Venafi{
+		RequestCertificateFn: func(csrPEM []byte, duration time.Duration, fields []api.CustomField) (string, error) {
+			return "", client.ErrPolicyViolation{Zone: "test-zone", Err: errors.New("common name does not match")}
+		},
+	}
+
 	tests := map[string]testT{
 		"a CertificateRequest without an approved condition should do nothing": {
 			certificateRequest: baseCRNotApproved.DeepCopy(),
@@ -791,6 +797,35 @@ func TestSign(t *testing.T) {
 			fakeClient:       clientReturnsInvalidCustomFieldType,
 			expectedErr:      false,
 		},
+		"tpp: a request that violates the zone policy should hard fail": {
+			certificateRequest: tppCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				CertManagerObjects: []runtime.Object{tppCR.DeepCopy(), tppIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					`Warning PolicyViolation certificate request does not satisfy the policy of Venafi zone "test-zone": common name does not match: certificate request does not satisfy the policy of Venafi zone "test-zone": common name does not match`,
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(tppCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            `certificate request does not satisfy the policy of Venafi zone "test-zone": common name does not match: certificate request does not satisfy the policy of Venafi zone "test-zone": common name does not match`,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+			fakeSecretLister: failGetSecretLister,
+			fakeClient:       clientReturnsPolicyViolation,
+			expectedErr:      false,
+		},
 	}
 
 	for name, test := range tests {
@@ -0,0 +1,313 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externalkey implements a crypto.Signer for private keys that are
+// held by an external signer service, as referenced by a Certificate's
+// `spec.privateKey.externalRef` field.
+package externalkey
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// SignerFunc returns a crypto.Signer for the external private key referenced
+// by the given Certificate. It is the extension point used by the
+// certificates controllers to delegate signing of certificate signing
+// requests to an external key holder.
+type SignerFunc func(ctx context.Context, crt *cmapi.Certificate) (crypto.Signer, error)
+
+// publicKeyResponse is returned by the signer service for
+// `GET <signerURL>/keys/<keyID>`.
+type publicKeyResponse struct {
+	// PublicKey is the PEM encoded public key of the private key.
+	PublicKey string `json:"publicKey"`
+}
+
+// signRequest is sent to the signer service as the body of
+// `POST <signerURL>/keys/<keyID>/sign`.
+type signRequest struct {
+	// Digest is the digest to be signed. It is empty for Ed25519 keys, in
+	// which case Message is set.
+	Digest []byte `json:"digest,omitempty"`
+	// Message is the message to be signed for Ed25519 keys.
+	Message []byte `json:"message,omitempty"`
+	// Hash is the name of the hash function used to compute the digest,
+	// e.g. "SHA-256".
+	Hash string `json:"hash,omitempty"`
+}
+
+// signResponse is returned by the signer service for a signRequest.
+type signResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// Ref returns the external private key reference of the given Certificate
+// spec, or nil if the private key is generated by cert-manager.
+func Ref(spec cmapi.CertificateSpec) *cmapi.PrivateKeyExternalRef {
+	if spec.PrivateKey == nil {
+		return nil
+	}
+	return spec.PrivateKey.ExternalRef
+}
+
+// KeyURL returns the URL identifying the external key within its signer
+// service. It is used to record which external key a certificate was issued
+// for.
+func KeyURL(ref *cmapi.PrivateKeyExternalRef) string {
+	return strings.TrimSuffix(ref.SignerURL, "/") + "/keys/" + url.PathEscape(ref.KeyID)
+}
+
+// SignerURLAllowed returns true if the given signer URL is one of, or is
+// below the path of one of, the allowed signer URLs. The scheme and host of
+// the URLs must match exactly.
+func SignerURLAllowed(signerURL string, allowed []string) bool {
+	u, err := url.Parse(signerURL)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		au, err := url.Parse(a)
+		if err != nil || au.Scheme != u.Scheme || au.Host != u.Host {
+			continue
+		}
+		prefix := strings.TrimSuffix(au.Path, "/")
+		if path := strings.TrimSuffix(u.Path, "/"); path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// publicKeyCacheTTL is how long the public key of an external key is cached
+// for before it is fetched again from the signer service.
+const publicKeyCacheTTL = 10 * time.Minute
+
+// cachedKey is the public key of an external key, along with the HTTP client
+// used to talk to its signer service.
+type cachedKey struct {
+	httpClient *http.Client
+	publicKey  crypto.PublicKey
+	fetched    time.Time
+}
+
+// NewSignerFunc returns a SignerFunc that reads bearer tokens using the
+// given Secret lister and talks to signer services over HTTP. The public keys
+// of external keys are cached, so that they are not fetched again each time
+// a certificate signing request is signed.
+func NewSignerFunc(secretLister corelisters.SecretLister) SignerFunc {
+	var lock sync.Mutex
+	cache := make(map[string]cachedKey)
+
+	return func(ctx context.Context, crt *cmapi.Certificate) (crypto.Signer, error) {
+		ref := Ref(crt.Spec)
+		if ref == nil {
+			return nil, fmt.Errorf("certificate does not reference an external private key")
+		}
+
+		var token string
+		if ref.TokenSecretRef != nil {
+			secret, err := secretLister.Secrets(crt.Namespace).Get(ref.TokenSecretRef.Name)
+			if err != nil {
+				return nil, err
+			}
+			data, ok := secret.Data[ref.TokenSecretRef.Key]
+			if !ok {
+				return nil, fmt.Errorf("no data for %q in secret '%s/%s'", ref.TokenSecretRef.Key, crt.Namespace, ref.TokenSecretRef.Name)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+
+		keyURL := KeyURL(ref)
+		// The CA bundle is part of the cache key, so that a public key is
+		// never reused for a certificate that trusts a different signer.
+		cacheKey := keyURL + "\x00" + string(ref.CABundle)
+
+		lock.Lock()
+		cached, ok := cache[cacheKey]
+		lock.Unlock()
+		if ok && time.Since(cached.fetched) < publicKeyCacheTTL {
+			return &Signer{
+				ctx:        ctx,
+				httpClient: cached.httpClient,
+				keyURL:     keyURL,
+				token:      token,
+				publicKey:  cached.publicKey,
+			}, nil
+		}
+
+		httpClient := cached.httpClient
+		if httpClient == nil {
+			var err error
+			httpClient, err = newHTTPClient(ref.CABundle)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		signer, err := NewSigner(ctx, httpClient, keyURL, token)
+		if err != nil {
+			return nil, err
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		for k, v := range cache {
+			if time.Since(v.fetched) >= publicKeyCacheTTL {
+				delete(cache, k)
+			}
+		}
+		cache[cacheKey] = cachedKey{httpClient: httpClient, publicKey: signer.publicKey, fetched: time.Now()}
+
+		return signer, nil
+	}
+}
+
+func newHTTPClient(caBundle []byte) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("failed to parse caBundle of external private key reference")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}, nil
+}
+
+// Signer is a crypto.Signer whose private key is held by an external signer
+// service.
+type Signer struct {
+	ctx        context.Context
+	httpClient *http.Client
+	keyURL     string
+	token      string
+	publicKey  crypto.PublicKey
+}
+
+var _ crypto.Signer = &Signer{}
+
+// NewSigner returns a Signer for the key at the given URL of a signer
+// service. The public key is fetched from the signer service when the Signer
+// is created. The given context is used for all requests made by the Signer.
+func NewSigner(ctx context.Context, httpClient *http.Client, keyURL, token string) (*Signer, error) {
+	s := &Signer{
+		ctx:        ctx,
+		httpClient: httpClient,
+		keyURL:     keyURL,
+		token:      token,
+	}
+
+	var resp publicKeyResponse
+	if err := s.do(http.MethodGet, keyURL, nil, &resp); err != nil {
+		return nil, fmt.Errorf("error fetching public key from external signer %q: %v", keyURL, err)
+	}
+	block, _ := pem.Decode([]byte(resp.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("external signer %q did not return a PEM encoded public key", keyURL)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding public key returned by external signer %q: %v", keyURL, err)
+	}
+	s.publicKey = publicKey
+
+	return s, nil
+}
+
+// Public returns the public key of the external private key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest using the external private key. RSA keys are expected
+// to produce PKCS #1 v1.5 signatures, as PSS is not used by cert-manager.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	req := signRequest{}
+	if hash := opts.HashFunc(); hash == 0 {
+		// Ed25519 keys sign the message itself rather than a digest.
+		req.Message = digest
+	} else {
+		req.Digest = digest
+		req.Hash = hash.String()
+	}
+
+	var resp signResponse
+	if err := s.do(http.MethodPost, s.keyURL+"/sign", req, &resp); err != nil {
+		return nil, fmt.Errorf("error signing with external signer %q: %v", s.keyURL, err)
+	}
+	if len(resp.Signature) == 0 {
+		return nil, fmt.Errorf("external signer %q returned an empty signature", s.keyURL)
+	}
+
+	return resp.Signature, nil
+}
+
+func (s *Signer) do(method, url string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(s.ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(s.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return json.Unmarshal(data, out)
+}
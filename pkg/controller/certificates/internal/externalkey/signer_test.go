@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalkey
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestKeyURL(t *testing.T) {
+	tests := map[string]struct {
+		ref *cmapi.PrivateKeyExternalRef
+		exp string
+	}{
+		"simple key ID": {
+			ref: &cmapi.PrivateKeyExternalRef{SignerURL: "https://signer.example.com", KeyID: "key"},
+			exp: "https://signer.example.com/keys/key",
+		},
+		"trailing slash and key ID with special characters": {
+			ref: &cmapi.PrivateKeyExternalRef{SignerURL: "https://signer.example.com/v1/", KeyID: "arn:aws:kms:eu-west-1:111122223333:key/abc"},
+			exp: "https://signer.example.com/v1/keys/arn:aws:kms:eu-west-1:111122223333:key%2Fabc",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := KeyURL(test.ref); got != test.exp {
+				t.Errorf("unexpected key URL, exp=%q got=%q", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestSigner(t *testing.T) {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/keys/test":
+			json.NewEncoder(w).Encode(publicKeyResponse{PublicKey: string(publicKeyPEM)})
+		case r.Method == http.MethodPost && r.URL.Path == "/keys/test/sign":
+			var req signRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if req.Hash != crypto.SHA256.String() {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			sig, err := key.Sign(rand.Reader, req.Digest, crypto.SHA256)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(signResponse{Signature: sig})
+		default:
+			http.Error(w, "key not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	signer, err := NewSigner(context.Background(), server.Client(), server.URL+"/keys/test", "s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.PublicKey.Equal(signer.Public()) {
+		t.Errorf("signer returned unexpected public key")
+	}
+
+	digest := sha256.Sum256([]byte("hello"))
+	sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig) {
+		t.Errorf("signature returned by signer is not valid")
+	}
+	for _, header := range authHeaders {
+		if header != "Bearer s3cr3t" {
+			t.Errorf("unexpected Authorization header %q", header)
+		}
+	}
+
+	_, err = NewSigner(context.Background(), server.Client(), server.URL+"/keys/missing", "")
+	exp := `error fetching public key from external signer "` + server.URL + `/keys/missing": unexpected status code 404: key not found`
+	if err == nil || err.Error() != exp {
+		t.Errorf("unexpected error, exp=%q got=%v", exp, err)
+	}
+}
+
+func TestSignerURLAllowed(t *testing.T) {
+	allowed := []string{"https://signer.example.com/v1/", "https://kms.example.com"}
+	tests := map[string]struct {
+		signerURL string
+		exp       bool
+	}{
+		"exact match":                  {signerURL: "https://signer.example.com/v1", exp: true},
+		"below the path of an allowed": {signerURL: "https://signer.example.com/v1/team-a", exp: true},
+		"any path of an allowed host":  {signerURL: "https://kms.example.com/keys", exp: true},
+		"partial path segment":         {signerURL: "https://signer.example.com/v10", exp: false},
+		"different host with prefix":   {signerURL: "https://kms.example.com.evil.com", exp: false},
+		"different scheme":             {signerURL: "http://kms.example.com", exp: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := SignerURLAllowed(test.signerURL, allowed); got != test.exp {
+				t.Errorf("unexpected result, exp=%t got=%t", test.exp, got)
+			}
+		})
+	}
+
+	if SignerURLAllowed("https://kms.example.com", nil) {
+		t.Errorf("expected no signer URLs to be allowed by an empty list")
+	}
+}
+
+func TestSignerFuncCachesPublicKey(t *testing.T) {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	var fetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(publicKeyResponse{PublicKey: string(publicKeyPEM)})
+	}))
+	defer server.Close()
+
+	crt := &cmapi.Certificate{Spec: cmapi.CertificateSpec{PrivateKey: &cmapi.CertificatePrivateKey{
+		ExternalRef: &cmapi.PrivateKeyExternalRef{SignerURL: server.URL, KeyID: "test"},
+	}}}
+	signerFunc := NewSignerFunc(nil)
+	for i := 0; i < 3; i++ {
+		signer, err := signerFunc(context.Background(), crt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.PublicKey.Equal(signer.Public()) {
+			t.Errorf("signer returned unexpected public key")
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected the public key to be fetched once, got %d fetches", fetches)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"runtime"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// keystoreCacheSize is the maximum number of encoded keystores that are
+	// kept in memory.
+	keystoreCacheSize = 1024
+	// keystoreCacheTTL is how long an encoded keystore is kept in memory.
+	keystoreCacheTTL = time.Hour
+)
+
+// keystores encodes the keystores of all Certificates, so that the number of
+// keystores encoded at once is bounded across all controllers.
+var keystores = newKeystoreEncoder(runtime.GOMAXPROCS(0), keystoreCacheSize)
+
+// keystoreEncoder encodes PKCS12 and JKS keystores in the background, using
+// at most a fixed number of goroutines at a time. Encoded keystores are cached
+// by a hash of the data they were encoded from, so that a keystore is not
+// encoded again when the same private key, certificates and password are
+// written to a Secret again, and concurrent requests to encode the same
+// keystore are only encoded once.
+type keystoreEncoder struct {
+	workers chan struct{}
+	group   singleflight.Group
+	cache   *cache.LRUExpireCache
+}
+
+// encoding is a keystore being encoded by a keystoreEncoder.
+type encoding struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func newKeystoreEncoder(workers, cacheSize int) *keystoreEncoder {
+	return &keystoreEncoder{
+		workers: make(chan struct{}, workers),
+		cache:   cache.NewLRUExpireCache(cacheSize),
+	}
+}
+
+// start begins encoding a keystore of the given format using encode, which
+// must only depend on the given inputs, including any password. If a keystore
+// of the same format has already been encoded from the same inputs the cached
+// keystore is returned instead.
+func (e *keystoreEncoder) start(format string, inputs [][]byte, encode func() ([]byte, error)) *encoding {
+	key := keystoreCacheKey(format, inputs)
+	enc := &encoding{done: make(chan struct{})}
+	if data, ok := e.cache.Get(key); ok {
+		enc.data = data.([]byte)
+		close(enc.done)
+		return enc
+	}
+
+	go func() {
+		defer close(enc.done)
+		data, err, _ := e.group.Do(key, func() (interface{}, error) {
+			e.workers <- struct{}{}
+			defer func() { <-e.workers }()
+
+			data, err := encode()
+			if err != nil {
+				return nil, err
+			}
+			e.cache.Add(key, data, keystoreCacheTTL)
+			return data, nil
+		})
+		if err != nil {
+			enc.err = err
+			return
+		}
+		enc.data = data.([]byte)
+	}()
+	return enc
+}
+
+// wait returns the encoded keystore once it has been encoded.
+func (e *encoding) wait() ([]byte, error) {
+	<-e.done
+	return e.data, e.err
+}
+
+// encoded returns true if the keystore has been encoded, or failed to be
+// encoded, without waiting for it.
+func (e *encoding) encoded() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// keystoreCacheKey returns a hash of the format and inputs of a keystore.
+// Each input is prefixed with its length so that different inputs cannot be
+// concatenated to the same data.
+func keystoreCacheKey(format string, inputs [][]byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(format))
+	for _, in := range inputs {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(in)))
+		hash.Write(length[:])
+		hash.Write(in)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
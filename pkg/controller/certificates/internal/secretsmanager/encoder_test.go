@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeystoreEncoderCache(t *testing.T) {
+	e := newKeystoreEncoder(2, 10)
+	var calls int32
+	encode := func(data string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			atomic.AddInt32(&calls, 1)
+			return []byte(data), nil
+		}
+	}
+	mustEncode := func(format string, inputs [][]byte, data string) []byte {
+		out, err := e.start(format, inputs, encode(data)).wait()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	first := mustEncode("jks-keystore", [][]byte{[]byte("pw"), []byte("key")}, "first")
+	cached := mustEncode("jks-keystore", [][]byte{[]byte("pw"), []byte("key")}, "second")
+	if string(first) != "first" || string(cached) != "first" {
+		t.Errorf("expected the cached keystore to be returned, got %q and %q", first, cached)
+	}
+	if calls != 1 {
+		t.Errorf("expected keystore to be encoded once, got %d", calls)
+	}
+
+	// Changing the format, any input, or how the inputs are split must
+	// encode the keystore again.
+	mustEncode("pkcs12-keystore", [][]byte{[]byte("pw"), []byte("key")}, "format")
+	mustEncode("jks-keystore", [][]byte{[]byte("pw2"), []byte("key")}, "password")
+	mustEncode("jks-keystore", [][]byte{[]byte("pwk"), []byte("ey")}, "split")
+	if calls != 4 {
+		t.Errorf("expected keystores with different inputs to be encoded, got %d encodings", calls)
+	}
+}
+
+func TestKeystoreEncoderErrorsAreNotCached(t *testing.T) {
+	e := newKeystoreEncoder(1, 10)
+	inputs := [][]byte{[]byte("pw")}
+
+	_, err := e.start("jks-truststore", inputs, func() ([]byte, error) {
+		return nil, errors.New("invalid CA")
+	}).wait()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	data, err := e.start("jks-truststore", inputs, func() ([]byte, error) {
+		return []byte("truststore"), nil
+	}).wait()
+	if err != nil || string(data) != "truststore" {
+		t.Errorf("expected the keystore to be encoded again, got %q, %v", data, err)
+	}
+}
+
+func TestKeystoreEncoderWorkers(t *testing.T) {
+	const workers = 2
+	e := newKeystoreEncoder(workers, 100)
+
+	var lock sync.Mutex
+	running, maxRunning := 0, 0
+	var encodings []*encoding
+	for i := 0; i < 10; i++ {
+		encodings = append(encodings, e.start("pkcs12-keystore", [][]byte{[]byte(fmt.Sprint(i))}, func() ([]byte, error) {
+			lock.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+
+			time.Sleep(time.Millisecond * 10)
+
+			lock.Lock()
+			running--
+			lock.Unlock()
+			return []byte("keystore"), nil
+		}))
+	}
+	for _, enc := range encodings {
+		if _, err := enc.wait(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if maxRunning > workers {
+		t.Errorf("expected at most %d keystores to be encoded at once, got %d", workers, maxRunning)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/x509"
+	"fmt"
 	"time"
 
 	jks "github.com/pavel-v-chernykh/keystore-go/v4"
@@ -78,12 +79,13 @@ func encodePKCS12Keystore(password string, rawKey []byte, certPem []byte, caPem
 }
 
 func encodePKCS12Truststore(password string, caPem []byte) ([]byte, error) {
-	ca, err := pki.DecodeX509CertificateBytes(caPem)
+	// ca.crt may contain the next CA of a planned rollover after the current
+	// CA, both of which should be trusted.
+	cas, err := pki.DecodeX509CertificateChainBytes(caPem)
 	if err != nil {
 		return nil, err
 	}
 
-	var cas = []*x509.Certificate{ca}
 	return pkcs12.EncodeTrustStore(rand.Reader, cas, password)
 }
 
@@ -141,19 +143,26 @@ func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []b
 }
 
 func encodeJKSTruststore(password []byte, caPem []byte) ([]byte, error) {
-	ca, err := pki.DecodeX509CertificateBytes(caPem)
+	cas, err := pki.DecodeX509CertificateChainBytes(caPem)
 	if err != nil {
 		return nil, err
 	}
 
 	ks := jks.New()
-	ks.SetTrustedCertificateEntry("ca", jks.TrustedCertificateEntry{
-		CreationTime: time.Now(),
-		Certificate: jks.Certificate{
-			Type:    "X509",
-			Content: ca.Raw,
-		}},
-	)
+	for i, ca := range cas {
+		// The first CA keeps the "ca" alias it has always been stored under.
+		alias := "ca"
+		if i > 0 {
+			alias = fmt.Sprintf("ca-%d", i)
+		}
+		ks.SetTrustedCertificateEntry(alias, jks.TrustedCertificateEntry{
+			CreationTime: time.Now(),
+			Certificate: jks.Certificate{
+				Type:    "X509",
+				Content: ca.Raw,
+			}},
+		)
+	}
 
 	buf := &bytes.Buffer{}
 	if err := ks.Store(buf, password); err != nil {
@@ -384,6 +384,21 @@ func TestEncodePKCS12Truststore(t *testing.T) {
 				}
 			},
 		},
+		"encode a PKCS12 bundle for the current and next CA": {
+			password: "password",
+			caPEM:    append(mustSelfSignCertificate(t, nil), mustSelfSignCertificate(t, nil)...),
+			verify: func(t *testing.T, caPEM []byte, out []byte, err error) {
+				require.NoError(t, err)
+				certs, err := pkcs12.DecodeTrustStore(out, "password")
+				require.NoError(t, err)
+				cas, err := pki.DecodeX509CertificateChainBytes(caPEM)
+				require.NoError(t, err)
+				if assert.Len(t, certs, 2, "Trusted CA certificates should include 2 entries") {
+					assert.Equal(t, cas[0].Signature, certs[0].Signature, "current CA certificate signature does not match")
+					assert.Equal(t, cas[1].Signature, certs[1].Signature, "next CA certificate signature does not match")
+				}
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+
+	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// normalizePEMData returns a copy of data with the private key re-encoded as
+// PKCS#8, and the certificate chain and CA re-encoded as consistently wrapped
+// PEM blocks, so that the Secret contents are the same regardless of the
+// encoding used by the issuer or the spec.privateKey.encoding of the
+// Certificate.
+func normalizePEMData(data SecretData) (SecretData, error) {
+	var err error
+	if len(data.PrivateKey) > 0 {
+		if data.PrivateKey, err = normalizePrivateKeyPEM(data.PrivateKey); err != nil {
+			return SecretData{}, fmt.Errorf("error normalizing private key: %w", err)
+		}
+	}
+	if data.Certificate, err = normalizePEMBundle(data.Certificate); err != nil {
+		return SecretData{}, fmt.Errorf("error normalizing certificate: %w", err)
+	}
+	if data.CA, err = normalizePEMBundle(data.CA); err != nil {
+		return SecretData{}, fmt.Errorf("error normalizing CA: %w", err)
+	}
+	return data, nil
+}
+
+// normalizePrivateKeyPEM returns the private key as a single unencrypted
+// PKCS#8 PEM block.
+func normalizePrivateKeyPEM(keyPEM []byte) ([]byte, error) {
+	pk, err := utilpki.DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return utilpki.EncodePKCS8PrivateKey(pk)
+}
+
+// normalizePEMBundle re-encodes each PEM block in bundle, which wraps the
+// base64 data at 64 columns and terminates every line with a single LF, and
+// drops any data that is not part of a PEM block. Empty input is returned
+// unchanged.
+func normalizePEMBundle(bundle []byte) ([]byte, error) {
+	if len(bundle) == 0 {
+		return bundle, nil
+	}
+
+	rest := bundle
+	var out bytes.Buffer
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if err := pem.Encode(&out, block); err != nil {
+			return nil, err
+		}
+	}
+	if out.Len() == 0 {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// wrapPEM encodes block with its base64 data wrapped at the given number of
+// columns, and lines terminated by sep, without a trailing line ending.
+func wrapPEM(block *pem.Block, columns int, sep string) []byte {
+	b64 := base64.StdEncoding.EncodeToString(block.Bytes)
+	out := "-----BEGIN " + block.Type + "-----" + sep
+	for len(b64) > columns {
+		out += b64[:columns] + sep
+		b64 = b64[columns:]
+	}
+	return []byte(out + b64 + sep + "-----END " + block.Type + "-----")
+}
+
+func TestNormalizePEMData(t *testing.T) {
+	pkcs1Key := mustGeneratePrivateKey(t, cmapi.PKCS1)
+	pkcs8Key := mustGeneratePrivateKey(t, cmapi.PKCS8)
+	certPEM := mustSelfSignCertificate(t, pkcs8Key)
+	caPEM := mustSelfSignCertificate(t, nil)
+	certBlock, _ := pem.Decode(certPEM)
+	caBlock, _ := pem.Decode(caPEM)
+
+	tests := map[string]struct {
+		data    SecretData
+		expData SecretData
+		expErr  bool
+	}{
+		"already normalized data is unchanged": {
+			data:    SecretData{PrivateKey: pkcs8Key, Certificate: certPEM, CA: caPEM},
+			expData: SecretData{PrivateKey: pkcs8Key, Certificate: certPEM, CA: caPEM},
+		},
+		"a PKCS#1 private key is re-encoded as PKCS#8": {
+			data:    SecretData{PrivateKey: pkcs1Key, Certificate: certPEM},
+			expData: SecretData{PrivateKey: mustReencodePKCS8(t, pkcs1Key), Certificate: certPEM},
+		},
+		"blocks are re-wrapped at 64 columns with LF line endings and a trailing newline": {
+			data: SecretData{
+				PrivateKey:  pkcs8Key,
+				Certificate: bytes.Join([][]byte{wrapPEM(certBlock, 76, "\r\n"), wrapPEM(caBlock, 76, "\r\n")}, []byte("\r\n")),
+				CA:          wrapPEM(caBlock, 72, "\n"),
+			},
+			expData: SecretData{PrivateKey: pkcs8Key, Certificate: append(append([]byte{}, certPEM...), caPEM...), CA: caPEM},
+		},
+		"data between PEM blocks is dropped": {
+			data: SecretData{
+				PrivateKey:  pkcs8Key,
+				Certificate: []byte("subject=CN = example.com\n" + string(certPEM) + "\n\nissuer=CN = ca\n" + string(caPEM) + "\n"),
+			},
+			expData: SecretData{PrivateKey: pkcs8Key, Certificate: append(append([]byte{}, certPEM...), caPEM...)},
+		},
+		"an empty private key is not normalized": {
+			data:    SecretData{PrivateKey: []byte{}, Certificate: certPEM},
+			expData: SecretData{PrivateKey: []byte{}, Certificate: certPEM},
+		},
+		"an invalid private key errors": {
+			data:   SecretData{PrivateKey: []byte("not a key"), Certificate: certPEM},
+			expErr: true,
+		},
+		"a certificate without PEM data errors": {
+			data:   SecretData{PrivateKey: pkcs8Key, Certificate: []byte("not a certificate")},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := normalizePEMData(test.data)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+			if !bytes.Equal(data.PrivateKey, test.expData.PrivateKey) {
+				t.Errorf("unexpected private key, exp=%q got=%q", test.expData.PrivateKey, data.PrivateKey)
+			}
+			if !bytes.Equal(data.Certificate, test.expData.Certificate) {
+				t.Errorf("unexpected certificate, exp=%q got=%q", test.expData.Certificate, data.Certificate)
+			}
+			if !bytes.Equal(data.CA, test.expData.CA) {
+				t.Errorf("unexpected CA, exp=%q got=%q", test.expData.CA, data.CA)
+			}
+		})
+	}
+}
+
+func mustReencodePKCS8(t *testing.T, keyPEM []byte) []byte {
+	pk, err := pki.DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := pki.EncodePKCS8PrivateKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// outputFormat describes how an additional output format is written to a
+// Certificate's Secret.
+type outputFormat struct {
+	// defaultKey is the Secret data key the format is written to if no key is
+	// configured on the Certificate.
+	defaultKey string
+	// encode encodes the format from the data stored in the Secret.
+	encode func(data SecretData) ([]byte, error)
+}
+
+// outputFormats contains all supported additional output formats, keyed by
+// their type.
+var outputFormats = map[cmapi.CertificateOutputFormatType]outputFormat{
+	cmapi.CertificateOutputFormatDER: {
+		defaultKey: cmapi.CertificateOutputFormatDERKey,
+		encode:     encodeDERKey,
+	},
+	cmapi.CertificateOutputFormatCombinedPEM: {
+		defaultKey: cmapi.CertificateOutputFormatCombinedPEMKey,
+		encode:     encodeCombinedPEM,
+	},
+}
+
+// encodeAdditionalOutputFormats returns the Secret data entries for the
+// additional output formats configured on the Certificate.
+func encodeAdditionalOutputFormats(crt *cmapi.Certificate, data SecretData) (map[string][]byte, error) {
+	entries := make(map[string][]byte)
+	for _, f := range crt.Spec.AdditionalOutputFormats {
+		format, ok := outputFormats[f.Type]
+		if !ok {
+			return nil, fmt.Errorf("unsupported additional output format %q", f.Type)
+		}
+		out, err := format.encode(data)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %s additional output format: %w", f.Type, err)
+		}
+		key := f.Key
+		if len(key) == 0 {
+			key = format.defaultKey
+		}
+		entries[key] = out
+	}
+	return entries, nil
+}
+
+// setAdditionalOutputFormats writes the additional output formats configured
+// on the Certificate to the Secret, and records the keys they were written to
+// in an annotation. The entries of formats that are no longer configured are
+// removed from the keys recorded in the annotation, as well as from their
+// default keys for Secrets written before the annotation was introduced.
+func setAdditionalOutputFormats(crt *cmapi.Certificate, secret *corev1.Secret, data SecretData) error {
+	entries, err := encodeAdditionalOutputFormats(crt, data)
+	if err != nil {
+		return err
+	}
+	for _, key := range splitList(secret.Annotations[cmapi.AdditionalOutputFormatKeysAnnotationKey]) {
+		if _, ok := entries[key]; !ok {
+			delete(secret.Data, key)
+		}
+	}
+	for _, format := range outputFormats {
+		if _, ok := entries[format.defaultKey]; !ok {
+			delete(secret.Data, format.defaultKey)
+		}
+	}
+	for key, out := range entries {
+		secret.Data[key] = out
+	}
+
+	if len(entries) == 0 {
+		delete(secret.Annotations, cmapi.AdditionalOutputFormatKeysAnnotationKey)
+		return nil
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[cmapi.AdditionalOutputFormatKeysAnnotationKey] = strings.Join(sets.StringKeySet(entries).List(), ",")
+	return nil
+}
+
+// encodeDERKey returns the private key as binary DER, keeping the PKCS#1 or
+// PKCS#8 encoding it is stored in.
+func encodeDERKey(data SecretData) ([]byte, error) {
+	block, _ := pem.Decode(data.PrivateKey)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode private key PEM data")
+	}
+	return block.Bytes, nil
+}
+
+// encodeCombinedPEM returns the PEM encoded private key followed by the PEM
+// encoded signed certificate chain.
+func encodeCombinedPEM(data SecretData) ([]byte, error) {
+	if len(data.PrivateKey) == 0 {
+		return nil, fmt.Errorf("no private key data available")
+	}
+	out := append([]byte{}, data.PrivateKey...)
+	if out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	return append(out, data.Certificate...), nil
+}
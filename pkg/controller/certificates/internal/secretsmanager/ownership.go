@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// This file implements strict Secret ownership, where cert-manager considers
+// itself the owner of the whole of a Certificate's Secret: data it no longer
+// writes is pruned, and the labels and annotations of the secretTemplate are
+// kept in sync with the Certificate, including removing those that are
+// removed from the template.
+
+// managedDataKeys returns the data keys of the Secret that cert-manager
+// writes for the Certificate, along with the keys listed in the Secret's
+// unmanaged data keys annotation.
+func managedDataKeys(crt *cmapi.Certificate, secret *corev1.Secret) sets.String {
+	keys := sets.NewString(corev1.TLSPrivateKeyKey, corev1.TLSCertKey, cmmeta.TLSCAKey)
+
+	if keystores := crt.Spec.Keystores; keystores != nil {
+		if keystores.PKCS12 != nil && keystores.PKCS12.Create {
+			keys.Insert(pkcs12SecretKey, pkcs12TruststoreKey)
+		}
+		if jks := keystores.JKS; jks != nil {
+			if jks.Create {
+				keys.Insert(jksSecretKey, jksTruststoreKey)
+			}
+			if jks.Truststore != nil {
+				keys.Insert(jksTruststoreKey)
+			}
+		}
+	}
+
+	for _, f := range crt.Spec.AdditionalOutputFormats {
+		key := f.Key
+		if len(key) == 0 {
+			key = outputFormats[f.Type].defaultKey
+		}
+		keys.Insert(key)
+	}
+
+	keys.Insert(splitList(secret.Annotations[cmapi.SecretUnmanagedDataKeysAnnotationKey])...)
+	return keys
+}
+
+// pruneUnmanagedData removes the data keys of the Secret that are not managed
+// by cert-manager for the Certificate.
+func pruneUnmanagedData(crt *cmapi.Certificate, secret *corev1.Secret) {
+	managed := managedDataKeys(crt, secret)
+	for key := range secret.Data {
+		if !managed.Has(key) {
+			delete(secret.Data, key)
+		}
+	}
+}
+
+// setSecretTemplate sets the labels and annotations of the Certificate's
+// secretTemplate on the Secret, and removes those that were last set from the
+// secretTemplate but have since been removed from it. The Secret's labels and
+// annotations must be non-nil.
+func setSecretTemplate(crt *cmapi.Certificate, secret *corev1.Secret) {
+	var labels, annotations map[string]string
+	if crt.Spec.SecretTemplate != nil {
+		labels = crt.Spec.SecretTemplate.Labels
+		annotations = crt.Spec.SecretTemplate.Annotations
+	}
+	setTemplateEntries(secret.Labels, labels, secret.Annotations, cmapi.SecretTemplateLabelsAnnotationKey)
+	setTemplateEntries(secret.Annotations, annotations, secret.Annotations, cmapi.SecretTemplateAnnotationsAnnotationKey)
+}
+
+// setTemplateEntries sets the template entries in entries, and removes the
+// entries that the annotation recordKey records as last set from the template
+// but that are no longer in it. The keys of the template are then recorded in
+// the annotation.
+func setTemplateEntries(entries, template, annotations map[string]string, recordKey string) {
+	for _, key := range splitList(annotations[recordKey]) {
+		if _, ok := template[key]; !ok {
+			delete(entries, key)
+		}
+	}
+	for key, value := range template {
+		entries[key] = value
+	}
+
+	if len(template) == 0 {
+		delete(annotations, recordKey)
+		return
+	}
+	annotations[recordKey] = strings.Join(sets.StringKeySet(template).List(), ",")
+}
+
+// splitList returns the non-empty entries of a comma separated list.
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); len(entry) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
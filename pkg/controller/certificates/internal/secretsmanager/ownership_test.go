@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestPruneUnmanagedData(t *testing.T) {
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		annotations map[string]string
+		data        map[string][]byte
+		expData     map[string][]byte
+	}{
+		"keeps the certificate, private key and CA": {
+			certificate: gen.Certificate("test"),
+			data: map[string][]byte{
+				corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key"), cmmeta.TLSCAKey: []byte("ca"),
+				"other": []byte("other"),
+			},
+			expData: map[string][]byte{
+				corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key"), cmmeta.TLSCAKey: []byte("ca"),
+			},
+		},
+		"removes keystores that are no longer configured": {
+			certificate: gen.Certificate("test", func(crt *cmapi.Certificate) {
+				crt.Spec.Keystores = &cmapi.CertificateKeystores{PKCS12: &cmapi.PKCS12Keystore{Create: true}}
+			}),
+			data: map[string][]byte{
+				pkcs12SecretKey: []byte("p12"), pkcs12TruststoreKey: []byte("p12"),
+				jksSecretKey: []byte("jks"), jksTruststoreKey: []byte("jks"),
+			},
+			expData: map[string][]byte{
+				pkcs12SecretKey: []byte("p12"), pkcs12TruststoreKey: []byte("p12"),
+			},
+		},
+		"keeps additional output formats written to a custom key": {
+			certificate: gen.Certificate("test", func(crt *cmapi.Certificate) {
+				crt.Spec.AdditionalOutputFormats = []cmapi.CertificateAdditionalOutputFormat{
+					{Type: cmapi.CertificateOutputFormatCombinedPEM, Key: "bundle.pem"},
+				}
+			}),
+			data: map[string][]byte{
+				"bundle.pem": []byte("bundle"), cmapi.CertificateOutputFormatCombinedPEMKey: []byte("stale"),
+			},
+			expData: map[string][]byte{"bundle.pem": []byte("bundle")},
+		},
+		"keeps keys listed in the unmanaged data keys annotation": {
+			certificate: gen.Certificate("test"),
+			annotations: map[string]string{cmapi.SecretUnmanagedDataKeysAnnotationKey: "extra, other.key"},
+			data: map[string][]byte{
+				"extra": []byte("extra"), "other.key": []byte("other"), "removed": []byte("removed"),
+			},
+			expData: map[string][]byte{"extra": []byte("extra"), "other.key": []byte("other")},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Data:       test.data,
+			}
+			pruneUnmanagedData(test.certificate, secret)
+			if !reflect.DeepEqual(secret.Data, test.expData) {
+				t.Errorf("unexpected data, exp=%v got=%v", test.expData, secret.Data)
+			}
+		})
+	}
+}
+
+func TestSetSecretTemplate(t *testing.T) {
+	tests := map[string]struct {
+		template       *cmapi.CertificateSecretTemplate
+		labels         map[string]string
+		annotations    map[string]string
+		expLabels      map[string]string
+		expAnnotations map[string]string
+	}{
+		"sets the template and records its keys": {
+			template: &cmapi.CertificateSecretTemplate{
+				Labels:      map[string]string{"b": "1", "a": "2"},
+				Annotations: map[string]string{"c": "3"},
+			},
+			labels:    map[string]string{"other": "label"},
+			expLabels: map[string]string{"a": "2", "b": "1", "other": "label"},
+			expAnnotations: map[string]string{
+				"c":                                     "3",
+				cmapi.SecretTemplateLabelsAnnotationKey: "a,b",
+				cmapi.SecretTemplateAnnotationsAnnotationKey: "c",
+			},
+		},
+		"restores template values that have drifted": {
+			template: &cmapi.CertificateSecretTemplate{Labels: map[string]string{"a": "1"}},
+			labels:   map[string]string{"a": "changed"},
+			annotations: map[string]string{
+				cmapi.SecretTemplateLabelsAnnotationKey: "a",
+			},
+			expLabels:      map[string]string{"a": "1"},
+			expAnnotations: map[string]string{cmapi.SecretTemplateLabelsAnnotationKey: "a"},
+		},
+		"removes entries that were removed from the template": {
+			template: &cmapi.CertificateSecretTemplate{Annotations: map[string]string{"kept": "1"}},
+			labels:   map[string]string{"removed": "1", "other": "label"},
+			annotations: map[string]string{
+				"kept": "1", "removed": "1", "other": "annotation",
+				cmapi.SecretTemplateLabelsAnnotationKey:      "removed",
+				cmapi.SecretTemplateAnnotationsAnnotationKey: "kept,removed",
+			},
+			expLabels: map[string]string{"other": "label"},
+			expAnnotations: map[string]string{
+				"kept": "1", "other": "annotation",
+				cmapi.SecretTemplateAnnotationsAnnotationKey: "kept",
+			},
+		},
+		"removes all recorded entries if the template is removed": {
+			labels: map[string]string{"a": "1"},
+			annotations: map[string]string{
+				"b":                                     "2",
+				cmapi.SecretTemplateLabelsAnnotationKey: "a",
+				cmapi.SecretTemplateAnnotationsAnnotationKey: "b",
+			},
+			expLabels:      map[string]string{},
+			expAnnotations: map[string]string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := gen.Certificate("test", func(crt *cmapi.Certificate) { crt.Spec.SecretTemplate = test.template })
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Labels:      make(map[string]string),
+				Annotations: make(map[string]string),
+			}}
+			for k, v := range test.labels {
+				secret.Labels[k] = v
+			}
+			for k, v := range test.annotations {
+				secret.Annotations[k] = v
+			}
+
+			setSecretTemplate(crt, secret)
+			if !reflect.DeepEqual(secret.Labels, test.expLabels) {
+				t.Errorf("unexpected labels, exp=%v got=%v", test.expLabels, secret.Labels)
+			}
+			if !reflect.DeepEqual(secret.Annotations, test.expAnnotations) {
+				t.Errorf("unexpected annotations, exp=%v got=%v", test.expAnnotations, secret.Annotations)
+			}
+		})
+	}
+}
@@ -19,14 +19,21 @@ package secretsmanager
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -42,34 +49,83 @@ var (
 type SecretsManager struct {
 	kubeClient   kubernetes.Interface
 	secretLister corelisters.SecretLister
+	recorder     record.EventRecorder
 
 	// if true, Secret resources created by the controller will have an
 	// 'owner reference' set, meaning when the Certificate is deleted, the
 	// Secret resource will be automatically deleted.
 	// This option is disabled by default.
 	enableSecretOwnerReferences bool
+
+	// if true, data keys of Secret resources that are not written by the
+	// controller are pruned, and the labels and annotations of the
+	// Certificate's secretTemplate are kept in sync with the Secret.
+	// This option is disabled by default.
+	strictOwnership bool
+
+	// if true, a KeystoresPendingError is returned while keystores are
+	// still being encoded, instead of waiting for them to be encoded.
+	deferKeystores bool
 }
 
 // SecretData is a structure wrapping private key, Certificate and CA data
 type SecretData struct {
 	PrivateKey, Certificate, CA []byte
+
+	// ExternalKeyURL identifies the external private key that Certificate
+	// was issued for. It is only set if the private key is held by an
+	// external signer, in which case PrivateKey is empty.
+	ExternalKeyURL string
+
+	// Issuance identifies the issuance that produced Certificate. It is nil
+	// if Certificate was not issued by cert-manager, in which case any
+	// issuance audit annotations are removed from the Secret.
+	Issuance *IssuanceData
+}
+
+// IssuanceData describes the issuance of a certificate, and is recorded in
+// the issuance audit annotations of the Secret.
+type IssuanceData struct {
+	// Revision is the revision of the Certificate that was issued.
+	Revision int
+	// RequestName is the name of the CertificateRequest that was issued.
+	RequestName string
+	// Time is the time at which the certificate was written to the Secret.
+	Time time.Time
 }
 
 // New returns a new SecretsManager. Setting enableSecretOwnerReferences to
 // true will mean that secrets will be deleted when the corresponding
-// Certificate is deleted.
+// Certificate is deleted. Setting strictOwnership to true will mean that
+// data keys which are not written by cert-manager are pruned from secrets,
+// unless listed in the cert-manager.io/unmanaged-data-keys annotation.
+// Warnings about the size of Secrets are recorded as events on the
+// Certificate using the given recorder.
 func New(
 	kubeClient kubernetes.Interface,
 	secretLister corelisters.SecretLister,
+	recorder record.EventRecorder,
 	enableSecretOwnerReferences bool,
+	strictOwnership bool,
 ) *SecretsManager {
 	return &SecretsManager{
 		kubeClient:                  kubeClient,
 		secretLister:                secretLister,
+		recorder:                    recorder,
 		enableSecretOwnerReferences: enableSecretOwnerReferences,
+		strictOwnership:             strictOwnership,
 	}
 }
 
+// WithDeferredKeystores configures the SecretsManager not to wait for
+// keystores to be encoded. Instead, a KeystoresPendingError is returned while
+// they are encoded in the background, and the keystores are written from the
+// cache of encoded keystores once the Secret is updated again.
+func (s *SecretsManager) WithDeferredKeystores() *SecretsManager {
+	s.deferKeystores = true
+	return s
+}
+
 // UpdateData will ensure the Secret resource contains the given secret
 // data as well as appropriate metadata.
 // If the Secret resource does not exist, it will be created.
@@ -107,6 +163,9 @@ func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate,
 	if err != nil {
 		return err
 	}
+	if err := s.checkSize(crt, secret); err != nil {
+		return err
+	}
 
 	// If secret does not exist then create it
 	if !secretExists {
@@ -120,6 +179,192 @@ func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate,
 	return err
 }
 
+// UpdateDerivedData re-encodes the keystores and additional output formats
+// in the Certificate's Secret if they are out of date with respect to the
+// Certificate, e.g. because a keystore password Secret has changed or an
+// output format has been added since the Secret was last written. If
+// spec.normalizePEM is set, the private key, certificate and CA are also
+// normalized. The certificate and private key data already stored in the
+// Secret is used, so no re-issuance is required. With strict ownership, unmanaged data is also
+// pruned and the labels and annotations of the Certificate's secretTemplate
+// are restored if they have drifted. If the status of the Certificate's CA
+// issuer is given, the next CAs of a planned rollover are added to ca.crt,
+// so that Secrets issued before the rollover was planned trust the next CA
+// without waiting to be renewed. It returns true if the Secret was updated.
+func (s *SecretsManager) UpdateDerivedData(ctx context.Context, crt *cmapi.Certificate, caStatus *cmapi.CAIssuerStatus) (bool, error) {
+	secret, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// Derived data is only written once a certificate has been issued.
+	if secret.Data == nil || len(secret.Data[corev1.TLSCertKey]) == 0 {
+		return false, nil
+	}
+
+	data := secretDataFromSecret(secret)
+	updated := secret.DeepCopy()
+	if crt.Spec.NormalizePEM {
+		if data, err = normalizePEMData(data); err != nil {
+			return false, err
+		}
+		setPEMData(updated, data)
+	}
+	if ca := utilpki.WithNextTrustAnchors(data.CA, caStatus); !bytes.Equal(ca, data.CA) {
+		data.CA = ca
+		updated.Data[cmmeta.TLSCAKey] = ca
+		if updated.Annotations == nil {
+			updated.Annotations = make(map[string]string)
+		}
+		setCATrustAnchorsAnnotation(updated, data)
+	}
+	stale, err := s.keystoresStale(crt, secret, data)
+	if err != nil {
+		return false, err
+	}
+	if stale {
+		if err := s.setKeystores(crt, updated, data); err != nil {
+			return false, err
+		}
+	}
+	if err := setAdditionalOutputFormats(crt, updated, data); err != nil {
+		return false, err
+	}
+	if s.strictOwnership {
+		if updated.Labels == nil {
+			updated.Labels = make(map[string]string)
+		}
+		if updated.Annotations == nil {
+			updated.Annotations = make(map[string]string)
+		}
+		setSecretTemplate(crt, updated)
+		pruneUnmanagedData(crt, updated)
+	}
+
+	if apiequality.Semantic.DeepEqual(secret.Data, updated.Data) &&
+		apiequality.Semantic.DeepEqual(secret.Labels, updated.Labels) &&
+		apiequality.Semantic.DeepEqual(secret.Annotations, updated.Annotations) {
+		return false, nil
+	}
+	if err := s.checkSize(crt, updated); err != nil {
+		return false, err
+	}
+	if _, err := s.kubeClient.CoreV1().Secrets(updated.Namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// secretDataFromSecret returns the certificate, private key and CA data stored
+// in the Secret.
+func secretDataFromSecret(secret *corev1.Secret) SecretData {
+	return SecretData{
+		PrivateKey:  secret.Data[corev1.TLSPrivateKeyKey],
+		Certificate: secret.Data[corev1.TLSCertKey],
+		CA:          secret.Data[cmmeta.TLSCAKey],
+	}
+}
+
+// keystoresStale returns true if the keystores in the Secret are out of date
+// with respect to the Certificate: the hash of the keystore passwords and of
+// the data differs from the hash recorded when they were last written, or a
+// configured keystore is missing from the Secret. The keystores themselves
+// are not decoded.
+func (s *SecretsManager) keystoresStale(crt *cmapi.Certificate, secret *corev1.Secret, data SecretData) (bool, error) {
+	hash, err := s.keystoresHash(crt, data)
+	if err != nil {
+		return false, err
+	}
+	if hash != secret.Annotations[cmapi.KeystoresHashAnnotationKey] {
+		return true, nil
+	}
+	if crt.Spec.Keystores == nil {
+		return false, nil
+	}
+
+	hasCA := len(data.CA) > 0
+	var keys []string
+	if pkcs12 := crt.Spec.Keystores.PKCS12; pkcs12 != nil && pkcs12.Create {
+		keys = append(keys, pkcs12SecretKey)
+		if hasCA {
+			keys = append(keys, pkcs12TruststoreKey)
+		}
+	}
+	if jks := crt.Spec.Keystores.JKS; jks != nil {
+		if jks.Create {
+			keys = append(keys, jksSecretKey)
+		}
+		if hasCA && (jks.Create || jks.Truststore != nil) {
+			keys = append(keys, jksTruststoreKey)
+		}
+	}
+	for _, key := range keys {
+		if len(secret.Data[key]) == 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// keystoresHash returns the hash recorded in the keystores hash annotation
+// for the keystores configured on the Certificate, or an empty string if no
+// keystores are configured. The private key is part of the hashed data, so
+// the passwords cannot be recovered from the hash without it.
+func (s *SecretsManager) keystoresHash(crt *cmapi.Certificate, data SecretData) (string, error) {
+	keystores := crt.Spec.Keystores
+	if keystores == nil {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	configured := false
+	writePassword := func(name string, ref cmmeta.SecretKeySelector, kind string) error {
+		pw, err := s.keystorePassword(crt.Namespace, ref, kind)
+		if err != nil {
+			return err
+		}
+		configured = true
+		writeHashField(hash, []byte(name))
+		writeHashField(hash, pw)
+		return nil
+	}
+	if keystores.PKCS12 != nil && keystores.PKCS12.Create {
+		if err := writePassword("pkcs12", keystores.PKCS12.PasswordSecretRef, "PKCS12 keystore"); err != nil {
+			return "", err
+		}
+	}
+	if jks := keystores.JKS; jks != nil {
+		if jks.Create {
+			if err := writePassword("jks", jks.PasswordSecretRef, "JKS keystore"); err != nil {
+				return "", err
+			}
+		}
+		if jks.Truststore != nil {
+			if err := writePassword("jks-truststore", jks.Truststore.PasswordSecretRef, "JKS truststore"); err != nil {
+				return "", err
+			}
+		}
+	}
+	if !configured {
+		return "", nil
+	}
+
+	writeHashField(hash, data.PrivateKey)
+	writeHashField(hash, data.Certificate)
+	writeHashField(hash, data.CA)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeHashField writes b to the hash prefixed with its length, so that the
+// boundaries between fields are unambiguous.
+func writeHashField(hash io.Writer, b []byte) {
+	fmt.Fprintf(hash, "%d:", len(b))
+	hash.Write(b)
+}
+
 // setValues will update the Secret resource 'secret' with the data contained
 // in the given secretData.
 // It will update labels and annotations on the Secret resource appropriately.
@@ -135,6 +380,13 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		secret.Data = make(map[string][]byte)
 	}
 
+	if crt.Spec.NormalizePEM {
+		var err error
+		if data, err = normalizePEMData(data); err != nil {
+			return err
+		}
+	}
+
 	// Only write a new PKCS12/JKS file if any of the private key/certificate/CA
 	// data has actually changed.
 	if data.PrivateKey != nil && data.Certificate != nil &&
@@ -142,75 +394,15 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 			!bytes.Equal(secret.Data[corev1.TLSCertKey], data.Certificate) ||
 			!bytes.Equal(secret.Data[cmmeta.TLSCAKey], data.CA)) {
 
-		// Handle the experimental PKCS12 support
-		if crt.Spec.Keystores != nil && crt.Spec.Keystores.PKCS12 != nil && crt.Spec.Keystores.PKCS12.Create {
-			ref := crt.Spec.Keystores.PKCS12.PasswordSecretRef
-			pwSecret, err := s.secretLister.Secrets(crt.Namespace).Get(ref.Name)
-			if err != nil {
-				return fmt.Errorf("fetching PKCS12 keystore password from Secret: %v", err)
-			}
-			if pwSecret.Data == nil || len(pwSecret.Data[ref.Key]) == 0 {
-				return fmt.Errorf("PKCS12 keystore password Secret contains no data for key %q", ref.Key)
-			}
-			pw := pwSecret.Data[ref.Key]
-			keystoreData, err := encodePKCS12Keystore(string(pw), data.PrivateKey, data.Certificate, data.CA)
-			if err != nil {
-				return fmt.Errorf("error encoding PKCS12 bundle: %w", err)
-			}
-			// always overwrite the keystore entry for now
-			secret.Data[pkcs12SecretKey] = keystoreData
-
-			if len(data.CA) > 0 {
-				truststoreData, err := encodePKCS12Truststore(string(pw), data.CA)
-				if err != nil {
-					return fmt.Errorf("error encoding PKCS12 trust store bundle: %w", err)
-				}
-				// always overwrite the truststore entry
-				secret.Data[pkcs12TruststoreKey] = truststoreData
-			}
-		} else {
-			delete(secret.Data, pkcs12SecretKey)
-			delete(secret.Data, pkcs12TruststoreKey)
-		}
-
-		// Handle the experimental JKS support
-		if crt.Spec.Keystores != nil && crt.Spec.Keystores.JKS != nil && crt.Spec.Keystores.JKS.Create {
-			ref := crt.Spec.Keystores.JKS.PasswordSecretRef
-			pwSecret, err := s.secretLister.Secrets(crt.Namespace).Get(ref.Name)
-			if err != nil {
-				return fmt.Errorf("fetching JKS keystore password from Secret: %v", err)
-			}
-			if pwSecret.Data == nil || len(pwSecret.Data[ref.Key]) == 0 {
-				return fmt.Errorf("JKS keystore password Secret contains no data for key %q", ref.Key)
-			}
-			pw := pwSecret.Data[ref.Key]
-			keystoreData, err := encodeJKSKeystore(pw, data.PrivateKey, data.Certificate, data.CA)
-			if err != nil {
-				return fmt.Errorf("error encoding JKS bundle: %w", err)
-			}
-			// always overwrite the keystore entry
-			secret.Data[jksSecretKey] = keystoreData
-
-			if len(data.CA) > 0 {
-				truststoreData, err := encodeJKSTruststore(pw, data.CA)
-				if err != nil {
-					return fmt.Errorf("error encoding JKS trust store bundle: %w", err)
-				}
-				// always overwrite the keystore entry
-				secret.Data[jksTruststoreKey] = truststoreData
-			}
-		} else {
-			delete(secret.Data, jksSecretKey)
-			delete(secret.Data, jksTruststoreKey)
+		if err := s.setKeystores(crt, secret, data); err != nil {
+			return err
 		}
 	}
 
-	secret.Data[corev1.TLSPrivateKeyKey] = data.PrivateKey
-	secret.Data[corev1.TLSCertKey] = data.Certificate
-	if len(data.CA) > 0 {
-		secret.Data[cmmeta.TLSCAKey] = data.CA
-	} else {
-		delete(secret.Data, cmmeta.TLSCAKey)
+	setPEMData(secret, data)
+
+	if err := setAdditionalOutputFormats(crt, secret, data); err != nil {
+		return err
 	}
 
 	if secret.Annotations == nil {
@@ -220,13 +412,14 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		secret.Labels = make(map[string]string)
 	}
 
-	// TODO: Labels and annotations are not yet removed from the Secret if removed from the template.
-	// An extra annotation will be required to keep track of which labels and annotations were created
-	// by cert-manager to allow them to be removed/updated safely.
-
-	// See https://github.com/jetstack/cert-manager/issues/4292
-
-	if crt.Spec.SecretTemplate != nil {
+	// With strict ownership, the keys of the labels and annotations set from
+	// the Secret template are recorded on the Secret, so ones removed from the
+	// template are also removed from the Secret. Otherwise they are not
+	// recorded, and labels and annotations are only added or updated so that
+	// ones set by other tools are preserved.
+	if s.strictOwnership {
+		setSecretTemplate(crt, secret)
+	} else if crt.Spec.SecretTemplate != nil {
 		for k, v := range crt.Spec.SecretTemplate.Labels {
 			secret.Labels[k] = v
 		}
@@ -235,10 +428,21 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		}
 	}
 
+	secret.Labels[cmapi.PartOfCertManagerControllerLabelKey] = "true"
+
 	secret.Annotations[cmapi.CertificateNameKey] = crt.Name
 	secret.Annotations[cmapi.IssuerNameAnnotationKey] = crt.Spec.IssuerRef.Name
 	secret.Annotations[cmapi.IssuerKindAnnotationKey] = apiutil.IssuerKind(crt.Spec.IssuerRef)
 	secret.Annotations[cmapi.IssuerGroupAnnotationKey] = crt.Spec.IssuerRef.Group
+	if len(data.ExternalKeyURL) > 0 {
+		secret.Annotations[cmapi.PrivateKeyExternalRefAnnotationKey] = data.ExternalKeyURL
+	} else {
+		delete(secret.Annotations, cmapi.PrivateKeyExternalRefAnnotationKey)
+	}
+	if err := setIssuanceAnnotations(secret, data); err != nil {
+		return err
+	}
+	setCATrustAnchorsAnnotation(secret, data)
 
 	// if the certificate data is empty, clear the subject related annotations
 	if len(data.Certificate) == 0 {
@@ -259,5 +463,253 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		secret.Annotations[cmapi.URISANAnnotationKey] = strings.Join(utilpki.URLsToString(x509Cert.URIs), ",")
 	}
 
+	if s.strictOwnership {
+		pruneUnmanagedData(crt, secret)
+	}
+
+	return nil
+}
+
+// setPEMData writes the private key, certificate and CA to the Secret,
+// removing the CA entry if there is no CA data.
+func setPEMData(secret *corev1.Secret, data SecretData) {
+	secret.Data[corev1.TLSPrivateKeyKey] = data.PrivateKey
+	secret.Data[corev1.TLSCertKey] = data.Certificate
+	if len(data.CA) > 0 {
+		secret.Data[cmmeta.TLSCAKey] = data.CA
+	} else {
+		delete(secret.Data, cmmeta.TLSCAKey)
+	}
+}
+
+// setIssuanceAnnotations sets the issuance audit annotations on the Secret
+// from the given data, or removes them if the data was not issued.
+func setIssuanceAnnotations(secret *corev1.Secret, data SecretData) error {
+	if data.Issuance == nil || len(data.Certificate) == 0 {
+		delete(secret.Annotations, cmapi.IssuanceRevisionAnnotationKey)
+		delete(secret.Annotations, cmapi.IssuanceRequestNameAnnotationKey)
+		delete(secret.Annotations, cmapi.IssuanceTimeAnnotationKey)
+		delete(secret.Annotations, cmapi.IssuanceChainFingerprintAnnotationKey)
+		return nil
+	}
+
+	chain, err := utilpki.DecodeX509CertificateChainBytes(data.Certificate)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	for _, cert := range chain {
+		hash.Write(cert.Raw)
+	}
+
+	secret.Annotations[cmapi.IssuanceRevisionAnnotationKey] = strconv.Itoa(data.Issuance.Revision)
+	secret.Annotations[cmapi.IssuanceRequestNameAnnotationKey] = data.Issuance.RequestName
+	secret.Annotations[cmapi.IssuanceTimeAnnotationKey] = data.Issuance.Time.UTC().Format(time.RFC3339)
+	secret.Annotations[cmapi.IssuanceChainFingerprintAnnotationKey] = hex.EncodeToString(hash.Sum(nil))
 	return nil
 }
+
+// setCATrustAnchorsAnnotation sets the validity windows of the CA
+// certificates in ca.crt on the Secret if there is more than one, or removes
+// the annotation otherwise.
+func setCATrustAnchorsAnnotation(secret *corev1.Secret, data SecretData) {
+	// Issuers don't all validate the CA they return, so don't fail to store
+	// the certificate if it can't be decoded.
+	cas, err := utilpki.DecodeX509CertificateChainBytes(data.CA)
+	if err != nil || len(cas) < 2 {
+		delete(secret.Annotations, cmapi.CATrustAnchorsAnnotationKey)
+		return
+	}
+
+	anchors := make([]string, len(cas))
+	for i, ca := range cas {
+		fingerprint := sha256.Sum256(ca.Raw)
+		anchors[i] = fmt.Sprintf("%s=%s/%s", hex.EncodeToString(fingerprint[:]),
+			ca.NotBefore.UTC().Format(time.RFC3339), ca.NotAfter.UTC().Format(time.RFC3339))
+	}
+	secret.Annotations[cmapi.CATrustAnchorsAnnotationKey] = strings.Join(anchors, ",")
+}
+
+// pendingStore is a keystore which is being encoded, to be written to the
+// given key of a Secret.
+type pendingStore struct {
+	secretKey   string
+	description string
+	encoding    *encoding
+}
+
+// setKeystores (re-)encodes the PKCS12 and JKS keystores configured on the
+// Certificate into the Secret resource using the given data, and removes any
+// keystores that are no longer configured. The keystores are encoded
+// concurrently and written to the Secret once they have all been encoded.
+func (s *SecretsManager) setKeystores(crt *cmapi.Certificate, secret *corev1.Secret, data SecretData) error {
+	hash, err := s.keystoresHash(crt, data)
+	if err != nil {
+		return err
+	}
+
+	var stores []pendingStore
+
+	// Handle the experimental PKCS12 support
+	if crt.Spec.Keystores != nil && crt.Spec.Keystores.PKCS12 != nil && crt.Spec.Keystores.PKCS12.Create {
+		pw, err := s.keystorePassword(crt.Namespace, crt.Spec.Keystores.PKCS12.PasswordSecretRef, "PKCS12 keystore")
+		if err != nil {
+			return err
+		}
+		// always overwrite the keystore entry for now
+		stores = append(stores, pendingStore{pkcs12SecretKey, "PKCS12 bundle",
+			keystores.start("pkcs12-keystore", [][]byte{pw, data.PrivateKey, data.Certificate, data.CA}, func() ([]byte, error) {
+				return encodePKCS12Keystore(string(pw), data.PrivateKey, data.Certificate, data.CA)
+			}),
+		})
+
+		if len(data.CA) > 0 {
+			// always overwrite the truststore entry
+			stores = append(stores, pendingStore{pkcs12TruststoreKey, "PKCS12 trust store bundle",
+				keystores.start("pkcs12-truststore", [][]byte{pw, data.CA}, func() ([]byte, error) {
+					return encodePKCS12Truststore(string(pw), data.CA)
+				}),
+			})
+		}
+	} else {
+		delete(secret.Data, pkcs12SecretKey)
+		delete(secret.Data, pkcs12TruststoreKey)
+	}
+
+	// Handle the experimental JKS support
+	var jks *cmapi.JKSKeystore
+	if crt.Spec.Keystores != nil {
+		jks = crt.Spec.Keystores.JKS
+	}
+	if jks != nil && jks.Create {
+		pw, err := s.keystorePassword(crt.Namespace, jks.PasswordSecretRef, "JKS keystore")
+		if err != nil {
+			return err
+		}
+		// always overwrite the keystore entry
+		stores = append(stores, pendingStore{jksSecretKey, "JKS bundle",
+			keystores.start("jks-keystore", [][]byte{pw, data.PrivateKey, data.Certificate, data.CA}, func() ([]byte, error) {
+				return encodeJKSKeystore(pw, data.PrivateKey, data.Certificate, data.CA)
+			}),
+		})
+
+		// the truststore is encrypted with the keystore password unless
+		// it has been configured with its own password below
+		if jks.Truststore == nil && len(data.CA) > 0 {
+			// always overwrite the truststore entry
+			stores = append(stores, pendingStore{jksTruststoreKey, "JKS trust store bundle", startJKSTruststore(pw, data.CA)})
+		}
+	} else {
+		delete(secret.Data, jksSecretKey)
+	}
+
+	if jks != nil && jks.Truststore != nil {
+		pw, err := s.keystorePassword(crt.Namespace, jks.Truststore.PasswordSecretRef, "JKS truststore")
+		if err != nil {
+			return err
+		}
+		if len(data.CA) > 0 {
+			// always overwrite the truststore entry
+			stores = append(stores, pendingStore{jksTruststoreKey, "JKS trust store bundle", startJKSTruststore(pw, data.CA)})
+		} else {
+			delete(secret.Data, jksTruststoreKey)
+		}
+	} else if jks == nil || !jks.Create {
+		delete(secret.Data, jksTruststoreKey)
+	}
+
+	if s.deferKeystores {
+		var pending []*encoding
+		for _, store := range stores {
+			if !store.encoding.encoded() {
+				pending = append(pending, store.encoding)
+			}
+		}
+		if len(pending) > 0 {
+			return newKeystoresPendingError(pending)
+		}
+	}
+
+	for _, store := range stores {
+		storeData, err := store.encoding.wait()
+		if err != nil {
+			return fmt.Errorf("error encoding %s: %w", store.description, err)
+		}
+		secret.Data[store.secretKey] = storeData
+	}
+
+	if len(hash) > 0 {
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[cmapi.KeystoresHashAnnotationKey] = hash
+	} else {
+		delete(secret.Annotations, cmapi.KeystoresHashAnnotationKey)
+	}
+
+	return nil
+}
+
+// startJKSTruststore begins encoding a JKS truststore containing the CA
+// certificates using the given password.
+func startJKSTruststore(password []byte, caPem []byte) *encoding {
+	return keystores.start("jks-truststore", [][]byte{password, caPem}, func() ([]byte, error) {
+		return encodeJKSTruststore(password, caPem)
+	})
+}
+
+// keystorePassword returns the password stored in the referenced key of a
+// Secret in the given namespace. kind describes the keystore the password is
+// used for in returned errors.
+func (s *SecretsManager) keystorePassword(namespace string, ref cmmeta.SecretKeySelector, kind string) ([]byte, error) {
+	pwSecret, err := s.secretLister.Secrets(namespace).Get(ref.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, &KeystorePasswordError{fmt.Errorf("fetching %s password from Secret: %v", kind, err)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s password from Secret: %v", kind, err)
+	}
+	if pwSecret.Data == nil || len(pwSecret.Data[ref.Key]) == 0 {
+		return nil, &KeystorePasswordError{fmt.Errorf("%s password Secret contains no data for key %q", kind, ref.Key)}
+	}
+	return pwSecret.Data[ref.Key], nil
+}
+
+// KeystorePasswordError is returned when the password Secret of a keystore
+// does not exist or does not contain the password. Retrying will not succeed
+// until the password Secret is changed.
+type KeystorePasswordError struct {
+	err error
+}
+
+func (e *KeystorePasswordError) Error() string {
+	return e.err.Error()
+}
+
+// KeystoresPendingError is returned by a SecretsManager configured with
+// WithDeferredKeystores when the keystores of a Certificate are still being
+// encoded. The Secret has not been updated, and should be updated again once
+// Done is closed, when the encoded keystores have been cached.
+type KeystoresPendingError struct {
+	done chan struct{}
+}
+
+func newKeystoresPendingError(pending []*encoding) *KeystoresPendingError {
+	e := &KeystoresPendingError{done: make(chan struct{})}
+	go func() {
+		defer close(e.done)
+		for _, enc := range pending {
+			<-enc.done
+		}
+	}()
+	return e
+}
+
+func (e *KeystoresPendingError) Error() string {
+	return "keystores are still being encoded"
+}
+
+// Done returns a channel that is closed once the keystores have been encoded.
+func (e *KeystoresPendingError) Done() <-chan struct{} {
+	return e.done
+}
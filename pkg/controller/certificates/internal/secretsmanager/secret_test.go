@@ -17,15 +17,23 @@ limitations under the License.
 package secretsmanager
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	jks "github.com/pavel-v-chernykh/keystore-go/v4"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -63,6 +71,14 @@ func TestSecretsManager(t *testing.T) {
 		gen.SetCertificateDNSNames("example.com"),
 	), fixedClock)
 
+	baseCertFingerprint := sha256.Sum256(baseCertBundle.Cert.Raw)
+	issuanceAnnotations := map[string]string{
+		cmapi.IssuanceRevisionAnnotationKey:         "1",
+		cmapi.IssuanceRequestNameAnnotationKey:      "old-request",
+		cmapi.IssuanceTimeAnnotationKey:             "2021-01-01T00:00:00Z",
+		cmapi.IssuanceChainFingerprintAnnotationKey: "abcd",
+	}
+
 	baseCertWithSecretTemplate := gen.CertificateFrom(baseCertBundle.Certificate,
 		gen.SetCertificateSecretTemplate(map[string]string{
 			"template":  "annotation",
@@ -110,7 +126,7 @@ func TestSecretsManager(t *testing.T) {
 									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(baseCertBundle.Cert.IPAddresses), ","),
 									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(baseCertBundle.Cert.URIs), ","),
 								},
-								Labels:          map[string]string{},
+								Labels:          map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 								OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(baseCertBundle.Certificate, certificateGvk)},
 							},
 							Data: map[string][]byte{
@@ -172,7 +188,7 @@ func TestSecretsManager(t *testing.T) {
 									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(baseCertBundle.Cert.IPAddresses), ","),
 									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(baseCertBundle.Cert.URIs), ","),
 								},
-								Labels:          map[string]string{},
+								Labels:          map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 								OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(baseCertBundle.Certificate, certificateGvk)},
 							},
 							Data: map[string][]byte{
@@ -236,6 +252,7 @@ func TestSecretsManager(t *testing.T) {
 								},
 								Labels: map[string]string{
 									"template": "label",
+									cmapi.PartOfCertManagerControllerLabelKey: "true",
 								},
 								OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(baseCertBundle.Certificate, certificateGvk)},
 							},
@@ -279,7 +296,7 @@ func TestSecretsManager(t *testing.T) {
 									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(baseCertBundle.Cert.IPAddresses), ","),
 									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(baseCertBundle.Cert.URIs), ","),
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       baseCertBundle.CertBytes,
@@ -326,6 +343,7 @@ func TestSecretsManager(t *testing.T) {
 								},
 								Labels: map[string]string{
 									"template": "label",
+									cmapi.PartOfCertManagerControllerLabelKey: "true",
 								},
 							},
 							Data: map[string][]byte{
@@ -386,7 +404,7 @@ func TestSecretsManager(t *testing.T) {
 									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(baseCertBundle.Cert.IPAddresses), ","),
 									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(baseCertBundle.Cert.URIs), ","),
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       baseCertBundle.CertBytes,
@@ -400,6 +418,111 @@ func TestSecretsManager(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+
+		"if the data was issued, record the issuance in the Secret's annotations": {
+			certificate: baseCertBundle.Certificate,
+			SecretData:  SecretData{Certificate: baseCertBundle.CertBytes, PrivateKey: []byte("test-key"), Issuance: &IssuanceData{Revision: 2, RequestName: "test-2", Time: fixedClockStart}},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:   gen.DefaultTestNamespace,
+							Name:        "output",
+							Annotations: issuanceAnnotations,
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: gen.DefaultTestNamespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+									cmapi.CommonNameAnnotationKey: baseCertBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:   strings.Join(baseCertBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(baseCertBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(baseCertBundle.Cert.URIs), ","),
+
+									cmapi.IssuanceRevisionAnnotationKey:         "2",
+									cmapi.IssuanceRequestNameAnnotationKey:      "test-2",
+									cmapi.IssuanceTimeAnnotationKey:             fixedClockStart.UTC().Format(time.RFC3339),
+									cmapi.IssuanceChainFingerprintAnnotationKey: hex.EncodeToString(baseCertFingerprint[:]),
+								},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       baseCertBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+		},
+
+		"if the data was not issued, remove any issuance annotations from the Secret": {
+			certificate: baseCertBundle.Certificate,
+			SecretData:  SecretData{Certificate: baseCertBundle.CertBytes, PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:   gen.DefaultTestNamespace,
+							Name:        "output",
+							Annotations: issuanceAnnotations,
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: gen.DefaultTestNamespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+									cmapi.CommonNameAnnotationKey: baseCertBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:   strings.Join(baseCertBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(baseCertBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(baseCertBundle.Cert.URIs), ","),
+								},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       baseCertBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+		},
 	}
 
 	// TODO: add to these tests once the JKS/PKCS12 support is updated
@@ -418,7 +541,9 @@ func TestSecretsManager(t *testing.T) {
 			testManager := New(
 				kubeClient,
 				secretsLister,
+				test.builder.Recorder,
 				test.certificateOptions.EnableOwnerRef,
+				test.certificateOptions.StrictSecretOwnership,
 			)
 
 			test.builder.Start()
@@ -434,3 +559,467 @@ func TestSecretsManager(t *testing.T) {
 		})
 	}
 }
+
+func TestSetValuesJKSTruststore(t *testing.T) {
+	caPEM := mustSelfSignCertificate(t, nil)
+	keyPEM := mustGeneratePrivateKey(t, cmapi.PKCS8)
+	certPEM := mustSelfSignCertificate(t, keyPEM)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, pw := range map[string]string{"keystore-password": "keystore", "truststore-password": "truststore"} {
+		if err := indexer.Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: name},
+			Data:       map[string][]byte{"password": []byte(pw)},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	passwordRef := func(name string) cmmeta.SecretKeySelector {
+		return cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: name}, Key: "password"}
+	}
+
+	tests := map[string]struct {
+		jks                *cmapi.JKSKeystore
+		ca                 []byte
+		expectedKeystore   string
+		expectedTruststore string
+		expectedErr        bool
+	}{
+		"keystore without a separate truststore uses the keystore password for both": {
+			jks:                &cmapi.JKSKeystore{Create: true, PasswordSecretRef: passwordRef("keystore-password")},
+			ca:                 caPEM,
+			expectedKeystore:   "keystore",
+			expectedTruststore: "keystore",
+		},
+		"keystore with a separate truststore uses the truststore password": {
+			jks: &cmapi.JKSKeystore{
+				Create:            true,
+				PasswordSecretRef: passwordRef("keystore-password"),
+				Truststore:        &cmapi.JKSTruststore{PasswordSecretRef: passwordRef("truststore-password")},
+			},
+			ca:                 caPEM,
+			expectedKeystore:   "keystore",
+			expectedTruststore: "truststore",
+		},
+		"truststore only does not create a keystore": {
+			jks: &cmapi.JKSKeystore{
+				Truststore: &cmapi.JKSTruststore{PasswordSecretRef: passwordRef("truststore-password")},
+			},
+			ca:                 caPEM,
+			expectedTruststore: "truststore",
+		},
+		"truststore only without a CA does not create a truststore": {
+			jks: &cmapi.JKSKeystore{
+				Truststore: &cmapi.JKSTruststore{PasswordSecretRef: passwordRef("truststore-password")},
+			},
+		},
+		"truststore with a missing password Secret errors": {
+			jks: &cmapi.JKSKeystore{
+				Truststore: &cmapi.JKSTruststore{PasswordSecretRef: passwordRef("missing")},
+			},
+			ca:          caPEM,
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := gen.Certificate("test",
+				gen.SetCertificateNamespace(gen.DefaultTestNamespace),
+				gen.SetCertificateSecretName("output"),
+			)
+			crt.Spec.Keystores = &cmapi.CertificateKeystores{JKS: test.jks}
+			secret := &corev1.Secret{
+				Data: map[string][]byte{
+					jksSecretKey:     []byte("stale"),
+					jksTruststoreKey: []byte("stale"),
+				},
+			}
+
+			s := &SecretsManager{secretLister: corelisters.NewSecretLister(indexer)}
+			err := s.setValues(crt, secret, SecretData{PrivateKey: keyPEM, Certificate: certPEM, CA: test.ca})
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+			if test.expectedErr {
+				return
+			}
+
+			checkStore := func(key, password, entry string) {
+				data, ok := secret.Data[key]
+				if len(password) == 0 {
+					if ok {
+						t.Errorf("expected %q to not be present in Secret", key)
+					}
+					return
+				}
+				ks := jks.New()
+				if err := ks.Load(bytes.NewReader(data), []byte(password)); err != nil {
+					t.Fatalf("failed to load %q: %v", key, err)
+				}
+				if entry == "certificate" && !ks.IsPrivateKeyEntry(entry) {
+					t.Errorf("expected %q to contain a private key entry", key)
+				}
+				if entry == "ca" && (!ks.IsTrustedCertificateEntry(entry) || ks.IsPrivateKeyEntry("certificate")) {
+					t.Errorf("expected %q to only contain the CA", key)
+				}
+			}
+			checkStore(jksSecretKey, test.expectedKeystore, "certificate")
+			checkStore(jksTruststoreKey, test.expectedTruststore, "ca")
+		})
+	}
+}
+
+func TestSetValuesDeferredKeystores(t *testing.T) {
+	keyPEM := mustGeneratePrivateKey(t, cmapi.PKCS8)
+	certPEM := mustSelfSignCertificate(t, keyPEM)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: "keystore-password"},
+		Data:       map[string][]byte{"password": []byte("password")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace(gen.DefaultTestNamespace),
+		gen.SetCertificateSecretName("output"),
+	)
+	crt.Spec.Keystores = &cmapi.CertificateKeystores{JKS: &cmapi.JKSKeystore{
+		Create: true,
+		PasswordSecretRef: cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{Name: "keystore-password"},
+			Key:                  "password",
+		},
+	}}
+
+	// Hold the only worker of the encoder, so that the keystore is still
+	// being encoded when the Secret is first updated.
+	oldKeystores := keystores
+	keystores = newKeystoreEncoder(1, 10)
+	defer func() { keystores = oldKeystores }()
+	keystores.workers <- struct{}{}
+
+	s := (&SecretsManager{secretLister: corelisters.NewSecretLister(indexer)}).WithDeferredKeystores()
+	secret := &corev1.Secret{}
+	err := s.setValues(crt, secret, SecretData{PrivateKey: keyPEM, Certificate: certPEM})
+	var pending *KeystoresPendingError
+	if !errors.As(err, &pending) {
+		t.Fatalf("expected a KeystoresPendingError, got %v", err)
+	}
+	if _, ok := secret.Data[jksSecretKey]; ok {
+		t.Errorf("expected the keystore not to be written while it is being encoded")
+	}
+
+	<-keystores.workers
+	select {
+	case <-pending.Done():
+	case <-time.After(time.Minute):
+		t.Fatal("timed out waiting for the keystore to be encoded")
+	}
+
+	// The encoded keystore is written from the cache once it has been encoded.
+	secret = &corev1.Secret{}
+	if err := s.setValues(crt, secret, SecretData{PrivateKey: keyPEM, Certificate: certPEM}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := jks.New().Load(bytes.NewReader(secret.Data[jksSecretKey]), []byte("password")); err != nil {
+		t.Errorf("failed to load the keystore: %v", err)
+	}
+}
+
+func TestSetCATrustAnchorsAnnotation(t *testing.T) {
+	currentPEM := mustSelfSignCertificate(t, nil)
+	nextPEM := mustSelfSignCertificate(t, nil)
+	anchor := func(certPEM []byte) string {
+		cert, err := utilpki.DecodeX509CertificateBytes(certPEM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fingerprint := sha256.Sum256(cert.Raw)
+		return hex.EncodeToString(fingerprint[:]) + "=" +
+			cert.NotBefore.UTC().Format(time.RFC3339) + "/" + cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+
+	tests := map[string]struct {
+		ca       []byte
+		expected string
+	}{
+		"a single CA is not annotated": {
+			ca: currentPEM,
+		},
+		"no CA is not annotated": {},
+		"an invalid CA is not annotated": {
+			ca: []byte("invalid"),
+		},
+		"the current and next CA are annotated in order": {
+			ca:       append(append([]byte{}, currentPEM...), nextPEM...),
+			expected: anchor(currentPEM) + "," + anchor(nextPEM),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{cmapi.CATrustAnchorsAnnotationKey: "stale"},
+			}}
+			setCATrustAnchorsAnnotation(secret, SecretData{CA: test.ca})
+
+			got, ok := secret.Annotations[cmapi.CATrustAnchorsAnnotationKey]
+			if len(test.expected) == 0 && ok {
+				t.Errorf("expected annotation to be removed, got %q", got)
+			}
+			if got != test.expected {
+				t.Errorf("unexpected annotation, exp=%q got=%q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestUpdateDerivedData(t *testing.T) {
+	caPEM := mustSelfSignCertificate(t, nil)
+	keyPEM := mustGeneratePrivateKey(t, cmapi.PKCS8)
+	certPEM := mustSelfSignCertificate(t, keyPEM)
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: "keystore-password"},
+		Data:       map[string][]byte{"password": []byte("current")},
+	}
+	passwordRef := cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "keystore-password"}, Key: "password"}
+	jksCert := gen.Certificate("test",
+		gen.SetCertificateNamespace(gen.DefaultTestNamespace),
+		gen.SetCertificateSecretName("output"),
+	)
+	jksCert.Spec.Keystores = &cmapi.CertificateKeystores{
+		JKS: &cmapi.JKSKeystore{Create: true, PasswordSecretRef: passwordRef},
+	}
+	pkcs12Cert := jksCert.DeepCopy()
+	pkcs12Cert.Spec.Keystores = &cmapi.CertificateKeystores{
+		PKCS12: &cmapi.PKCS12Keystore{Create: true, PasswordSecretRef: passwordRef},
+	}
+	// keystoresHash returns the keystores hash of the Certificate when the
+	// keystores were encoded using the given password.
+	keystoresHash := func(crt *cmapi.Certificate, password string) string {
+		pwSecret := passwordSecret.DeepCopy()
+		pwSecret.Data["password"] = []byte(password)
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		if err := indexer.Add(pwSecret); err != nil {
+			t.Fatal(err)
+		}
+		s := &SecretsManager{secretLister: corelisters.NewSecretLister(indexer)}
+		hash, err := s.keystoresHash(crt, SecretData{PrivateKey: keyPEM, Certificate: certPEM, CA: caPEM})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	outputSecret := func(data map[string][]byte, keystoresHash string) *corev1.Secret {
+		secretData := map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			cmmeta.TLSCAKey:         caPEM,
+		}
+		for k, v := range data {
+			secretData[k] = v
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: "output"},
+			Data:       secretData,
+		}
+		if len(keystoresHash) > 0 {
+			secret.Annotations = map[string]string{cmapi.KeystoresHashAnnotationKey: keystoresHash}
+		}
+		return secret
+	}
+	jksData := map[string][]byte{jksSecretKey: []byte("keystore"), jksTruststoreKey: []byte("truststore")}
+	pkcs12Data := map[string][]byte{pkcs12SecretKey: []byte("keystore"), pkcs12TruststoreKey: []byte("truststore")}
+	nextCAPEM := mustSelfSignCertificate(t, nil)
+	rolloverStatus := &cmapi.CAIssuerStatus{TrustAnchors: []cmapi.CATrustAnchor{
+		{Certificate: caPEM},
+		{Certificate: nextCAPEM, Next: true},
+	}}
+	caWithNextPEM := append(append([]byte{}, caPEM...), nextCAPEM...)
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		secret      *corev1.Secret
+		caStatus    *cmapi.CAIssuerStatus
+		expUpdated  bool
+		// expCA, if set, is the expected ca.crt of the Secret.
+		expCA  []byte
+		expErr bool
+	}{
+		"re-encodes JKS keystores encoded with a previous password": {
+			certificate: jksCert,
+			secret:      outputSecret(jksData, keystoresHash(jksCert, "previous")),
+			expUpdated:  true,
+		},
+		"re-encodes PKCS12 keystores encoded with a previous password": {
+			certificate: pkcs12Cert,
+			secret:      outputSecret(pkcs12Data, keystoresHash(pkcs12Cert, "previous")),
+			expUpdated:  true,
+		},
+		"re-encodes keystores written before their hash was recorded": {
+			certificate: jksCert,
+			secret:      outputSecret(jksData, ""),
+			expUpdated:  true,
+		},
+		"re-encodes a missing keystore": {
+			certificate: jksCert,
+			secret:      outputSecret(map[string][]byte{jksTruststoreKey: []byte("truststore")}, keystoresHash(jksCert, "current")),
+			expUpdated:  true,
+		},
+		"removes keystores that are no longer configured": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) { crt.Spec.Keystores = nil }),
+			secret:      outputSecret(jksData, keystoresHash(jksCert, "current")),
+			expUpdated:  true,
+		},
+		"writes a missing additional output format": {
+			certificate: gen.CertificateFrom(pkcs12Cert, func(crt *cmapi.Certificate) {
+				crt.Spec.AdditionalOutputFormats = []cmapi.CertificateAdditionalOutputFormat{{Type: cmapi.CertificateOutputFormatCombinedPEM}}
+			}),
+			secret:     outputSecret(pkcs12Data, keystoresHash(pkcs12Cert, "current")),
+			expUpdated: true,
+		},
+		"removes an additional output format that is no longer configured": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) { crt.Spec.Keystores = nil }),
+			secret:      outputSecret(map[string][]byte{cmapi.CertificateOutputFormatCombinedPEMKey: []byte("stale")}, ""),
+			expUpdated:  true,
+		},
+		"normalizes the PEM data if normalizePEM is set": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) {
+				crt.Spec.Keystores = nil
+				crt.Spec.NormalizePEM = true
+			}),
+			secret:     outputSecret(map[string][]byte{corev1.TLSPrivateKeyKey: mustGeneratePrivateKey(t, cmapi.PKCS1), corev1.TLSCertKey: bytes.TrimSuffix(certPEM, []byte("\n"))}, ""),
+			expUpdated: true,
+		},
+		"does nothing if the PEM data is already normalized": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) {
+				crt.Spec.Keystores = nil
+				crt.Spec.NormalizePEM = true
+			}),
+			secret: outputSecret(nil, ""),
+		},
+		"does nothing if the keystores hash is up to date": {
+			certificate: pkcs12Cert,
+			secret:      outputSecret(pkcs12Data, keystoresHash(pkcs12Cert, "current")),
+		},
+		"does nothing if no keystores are configured": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) { crt.Spec.Keystores = nil }),
+			secret:      outputSecret(nil, ""),
+		},
+		"adds the next CA of the issuer to ca.crt and re-encodes the keystores": {
+			certificate: pkcs12Cert,
+			secret:      outputSecret(pkcs12Data, keystoresHash(pkcs12Cert, "current")),
+			caStatus:    rolloverStatus,
+			expUpdated:  true,
+			expCA:       caWithNextPEM,
+		},
+		"does nothing if ca.crt already contains the next CA of the issuer": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) { crt.Spec.Keystores = nil }),
+			secret: gen.SecretFrom(outputSecret(map[string][]byte{cmmeta.TLSCAKey: caWithNextPEM}, ""),
+				gen.SetSecretAnnotations(map[string]string{cmapi.CATrustAnchorsAnnotationKey: mustTrustAnchorsAnnotation(t, caWithNextPEM)})),
+			caStatus: rolloverStatus,
+		},
+		"does nothing if the Secret has no certificate yet": {
+			certificate: jksCert,
+			secret:      &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: "output"}},
+		},
+		"errors if the password Secret does not exist": {
+			certificate: gen.CertificateFrom(jksCert, func(crt *cmapi.Certificate) {
+				crt.Spec.Keystores = &cmapi.CertificateKeystores{
+					JKS: &cmapi.JKSKeystore{Create: true, PasswordSecretRef: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "missing"}, Key: "password",
+					}},
+				}
+			}),
+			secret: outputSecret(nil, ""),
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:           t,
+				KubeObjects: []runtime.Object{passwordSecret, test.secret},
+			}
+			builder.Init()
+			defer builder.Stop()
+			testManager := New(builder.Client, builder.KubeSharedInformerFactory.Core().V1().Secrets().Lister(), builder.Recorder, false, false)
+			builder.Start()
+
+			updated, err := testManager.UpdateDerivedData(context.Background(), test.certificate, test.caStatus)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			var passwordErr *KeystorePasswordError
+			if test.expErr && !errors.As(err, &passwordErr) {
+				t.Errorf("expected a KeystorePasswordError, got %v", err)
+			}
+			if updated != test.expUpdated {
+				t.Fatalf("unexpected updated, exp=%t got=%t", test.expUpdated, updated)
+			}
+			if !updated {
+				return
+			}
+
+			secret, err := builder.Client.CoreV1().Secrets(gen.DefaultTestNamespace).Get(context.Background(), "output", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			stale, err := testManager.keystoresStale(test.certificate, secret, secretDataFromSecret(secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stale {
+				t.Errorf("expected keystores to be up to date")
+			}
+			if test.expCA != nil {
+				if !bytes.Equal(secret.Data[cmmeta.TLSCAKey], test.expCA) {
+					t.Errorf("unexpected ca.crt, exp=%q got=%q", test.expCA, secret.Data[cmmeta.TLSCAKey])
+				}
+				if exp := mustTrustAnchorsAnnotation(t, test.expCA); secret.Annotations[cmapi.CATrustAnchorsAnnotationKey] != exp {
+					t.Errorf("unexpected %s annotation, exp=%q got=%q", cmapi.CATrustAnchorsAnnotationKey, exp, secret.Annotations[cmapi.CATrustAnchorsAnnotationKey])
+				}
+			}
+			if test.certificate.Spec.Keystores == nil {
+				if _, ok := secret.Data[jksSecretKey]; ok {
+					t.Errorf("expected unconfigured keystore to be removed")
+				}
+			}
+			if test.certificate.Spec.NormalizePEM {
+				data := secretDataFromSecret(secret)
+				normalized, err := normalizePEMData(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(data, normalized) {
+					t.Errorf("expected the PEM data to be normalized")
+				}
+			}
+			formats, err := encodeAdditionalOutputFormats(test.certificate, secretDataFromSecret(secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range formats {
+				if !bytes.Equal(secret.Data[key], value) {
+					t.Errorf("expected additional output format %q to be up to date", key)
+				}
+			}
+			if _, ok := secret.Data[cmapi.CertificateOutputFormatCombinedPEMKey]; ok && len(formats) == 0 {
+				t.Errorf("expected unconfigured additional output format to be removed")
+			}
+		})
+	}
+}
+
+// mustTrustAnchorsAnnotation returns the value of the trust anchors
+// annotation for the given ca.crt.
+func mustTrustAnchorsAnnotation(t *testing.T, caPEM []byte) string {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	setCATrustAnchorsAnnotation(secret, SecretData{CA: caPEM})
+	if len(secret.Annotations[cmapi.CATrustAnchorsAnnotationKey]) == 0 {
+		t.Fatalf("expected ca.crt to contain more than one CA certificate")
+	}
+	return secret.Annotations[cmapi.CATrustAnchorsAnnotationKey]
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+const (
+	// SecretSizeWarningThreshold is the total size of the data of a Secret
+	// above which a warning is emitted, as further additions such as another
+	// output format or keystore will soon cause writes to be rejected.
+	SecretSizeWarningThreshold = corev1.MaxSecretSize * 9 / 10
+
+	// reasonSecretSizeWarning is the reason of events emitted when the data
+	// of a Secret approaches or exceeds corev1.MaxSecretSize.
+	reasonSecretSizeWarning = "SecretSizeWarning"
+)
+
+// SecretTooLargeError is returned if the data composed for a Secret exceeds
+// the maximum size accepted by the apiserver. The Secret is not written.
+type SecretTooLargeError struct {
+	// Size is the total size of the keys and values of the Secret data.
+	Size int
+}
+
+func (e *SecretTooLargeError) Error() string {
+	return fmt.Sprintf("the data of the Secret, including all keystores and additional output formats, is %d bytes which exceeds the maximum Secret size of %d bytes", e.Size, corev1.MaxSecretSize)
+}
+
+// secretDataSize returns the size of the Secret data as accounted for by the
+// apiserver when validating the maximum size of a Secret.
+func secretDataSize(secret *corev1.Secret) int {
+	var size int
+	for k, v := range secret.Data {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// checkSize returns a SecretTooLargeError if the Secret cannot be written
+// because of its size, and emits a warning event on the Certificate if the
+// Secret is either too large or approaching the limit.
+func (s *SecretsManager) checkSize(crt *cmapi.Certificate, secret *corev1.Secret) error {
+	size := secretDataSize(secret)
+	switch {
+	case size > corev1.MaxSecretSize:
+		err := &SecretTooLargeError{Size: size}
+		s.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSecretSizeWarning, "Not writing Secret %q: %v", secret.Name, err)
+		return err
+	case size > SecretSizeWarningThreshold:
+		s.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSecretSizeWarning,
+			"The data of Secret %q is %d bytes, close to the maximum Secret size of %d bytes; adding keystores or additional output formats may cause writes to fail",
+			secret.Name, size, corev1.MaxSecretSize)
+	}
+	return nil
+}
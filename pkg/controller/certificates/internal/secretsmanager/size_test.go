@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+)
+
+func TestCheckSize(t *testing.T) {
+	crt := &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}
+	secretWithDataSize := func(size int) *corev1.Secret {
+		// The key is accounted for in the size of the Secret.
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output"},
+			Data:       map[string][]byte{"k": make([]byte, size-1)},
+		}
+	}
+
+	tests := map[string]struct {
+		secret    *corev1.Secret
+		expErr    bool
+		expEvents []string
+	}{
+		"Secrets well below the limit are written without warning": {
+			secret: secretWithDataSize(4096),
+		},
+		"Secrets at the warning threshold are written without warning": {
+			secret: secretWithDataSize(SecretSizeWarningThreshold),
+		},
+		"Secrets above the warning threshold are written with a warning": {
+			secret:    secretWithDataSize(SecretSizeWarningThreshold + 1),
+			expEvents: []string{"Warning SecretSizeWarning The data of Secret \"output\" is 943719 bytes, close to the maximum Secret size of 1048576 bytes; adding keystores or additional output formats may cause writes to fail"},
+		},
+		"Secrets at the limit are written with a warning": {
+			secret:    secretWithDataSize(corev1.MaxSecretSize),
+			expEvents: []string{"Warning SecretSizeWarning The data of Secret \"output\" is 1048576 bytes, close to the maximum Secret size of 1048576 bytes; adding keystores or additional output formats may cause writes to fail"},
+		},
+		"Secrets above the limit are not written": {
+			secret:    secretWithDataSize(corev1.MaxSecretSize + 1),
+			expErr:    true,
+			expEvents: []string{"Warning SecretSizeWarning Not writing Secret \"output\": the data of the Secret, including all keystores and additional output formats, is 1048577 bytes which exceeds the maximum Secret size of 1048576 bytes"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := new(testpkg.FakeRecorder)
+			s := &SecretsManager{recorder: recorder}
+
+			err := s.checkSize(crt, test.secret)
+			var tooLarge *SecretTooLargeError
+			if test.expErr != errors.As(err, &tooLarge) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if strings.Join(test.expEvents, "\n") != strings.Join(recorder.Events, "\n") {
+				t.Errorf("unexpected events, exp=%q got=%q", test.expEvents, recorder.Events)
+			}
+		})
+	}
+}
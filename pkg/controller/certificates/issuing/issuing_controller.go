@@ -19,7 +19,9 @@ package issuing
 import (
 	"context"
 	"crypto"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -27,6 +29,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -36,6 +39,7 @@ import (
 	"k8s.io/utils/clock"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -43,8 +47,13 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/externalkey"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/secretsmanager"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/tracing"
 	utilkube "github.com/jetstack/cert-manager/pkg/util/kube"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -52,6 +61,14 @@ import (
 
 const (
 	ControllerName = "certificates-issuing"
+
+	reasonSecretDataUpdated = "SecretDataUpdated"
+	reasonSecretTooLarge    = "SecretTooLarge"
+	reasonSecretSynced      = "Synced"
+	reasonSecretSyncFailed  = "SecretSyncFailed"
+	reasonSANsDropped       = "SANsDropped"
+
+	reasonKeystorePasswordMissing = "KeystorePasswordMissing"
 )
 
 type localTemporarySignerFn func(crt *cmapi.Certificate, pk []byte) ([]byte, error)
@@ -65,13 +82,25 @@ type controller struct {
 	secretLister             corelisters.SecretLister
 	recorder                 record.EventRecorder
 	clock                    clock.Clock
+	metrics                  *metrics.Metrics
 
-	client cmclient.Interface
+	client        cmclient.Interface
+	statusPatcher *statuspatch.Patcher
+	fieldManager  string
 
 	// secretManager is used to create and update Secrets with certificate and key data
 	secretsManager *secretsmanager.SecretsManager
 	// localTemporarySigner signs a certificate that is stored temporarily
 	localTemporarySigner localTemporarySignerFn
+
+	// issuerHelper is used to read the trust anchors of CA issuers, so that
+	// the next CA of a planned rollover is added to the ca.crt of existing
+	// Secrets. It may be nil.
+	issuerHelper issuer.Helper
+
+	// queue is used to process a Certificate again once its keystores have
+	// been encoded in the background.
+	queue workqueue.RateLimitingInterface
 }
 
 func NewController(
@@ -82,11 +111,17 @@ func NewController(
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
 	clock clock.Clock,
+	metrics *metrics.Metrics,
 	certificateControllerOptions controllerpkg.CertificateOptions,
+	statusPatcher *statuspatch.Patcher,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
@@ -108,6 +143,11 @@ func NewController(
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
 			predicate.ExtractResourceName(predicate.CertificateSecretName)),
 	})
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		// Issuer reconciles on changes to the Secrets holding keystore passwords
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateKeystorePasswordSecretName)),
+	})
 
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
@@ -120,22 +160,45 @@ func NewController(
 	secretsManager := secretsmanager.New(
 		kubeClient,
 		secretsInformer.Lister(),
+		recorder,
 		certificateControllerOptions.EnableOwnerRef,
-	)
+		certificateControllerOptions.StrictSecretOwnership,
+	).WithDeferredKeystores()
 
 	return &controller{
 		certificateLister:        certificateInformer.Lister(),
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
+		statusPatcher:            statusPatcher,
+		fieldManager:             controllerpkg.FieldManager(ControllerName),
 		recorder:                 recorder,
 		clock:                    clock,
+		metrics:                  metrics,
 		secretsManager:           secretsManager,
 		localTemporarySigner:     certificates.GenerateLocallySignedTemporaryCertificate,
+		queue:                    queue,
 	}, queue, mustSync
 }
 
 func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	err := c.processItem(ctx, key)
+	var pending *secretsmanager.KeystoresPendingError
+	if errors.As(err, &pending) {
+		// Don't hold up the worker while the keystores are encoded. The
+		// Certificate is processed again once they have been encoded, when
+		// they are written to the Secret from the cache.
+		logf.FromContext(ctx).V(logf.DebugLevel).Info("waiting for keystores to be encoded", "key", key)
+		go func() {
+			<-pending.Done()
+			c.queue.Add(key)
+		}()
+		return nil
+	}
+	return err
+}
+
+func (c *controller) processItem(ctx context.Context, key string) error {
 	// Set context deadline for full sync in 10 seconds
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
@@ -163,43 +226,82 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
 	}) {
-		// Do nothing if an issuance is not in progress.
+		// If an issuance is not in progress, only update the data derived
+		// from the issued certificate in the Secret, in case a keystore
+		// password, the requested output formats, PEM normalization or the
+		// trust anchors of a CA issuer have changed.
+		updated, err := c.secretsManager.UpdateDerivedData(ctx, crt, c.caIssuerStatus(crt))
+		var tooLarge *secretsmanager.SecretTooLargeError
+		if errors.As(err, &tooLarge) {
+			// A warning has already been recorded and retrying will not
+			// succeed until the Certificate or its Secret is changed.
+			log.Error(err, "not updating keystores and additional output formats")
+			return c.setSecretSyncedCondition(ctx, crt, cmmeta.ConditionFalse, reasonSecretTooLarge, err.Error())
+		}
+		var passwordErr *secretsmanager.KeystorePasswordError
+		if errors.As(err, &passwordErr) {
+			// The Certificate is re-queued when the password Secret is
+			// changed, so there is no need to retry until then.
+			message := fmt.Sprintf("Failed to write Secret %q: %v", crt.Spec.SecretName, err)
+			c.recorder.Event(crt, corev1.EventTypeWarning, reasonKeystorePasswordMissing, message)
+			return c.setSecretSyncedCondition(ctx, crt, cmmeta.ConditionFalse, reasonKeystorePasswordMissing, message)
+		}
+		if err != nil {
+			return c.secretSyncFailed(ctx, crt, err)
+		}
+		if updated {
+			c.recorder.Event(crt, corev1.EventTypeNormal, reasonSecretDataUpdated,
+				fmt.Sprintf("Updated the data derived from the issued certificate in Secret %q", crt.Spec.SecretName))
+		}
+		// A SecretSynced=False condition is also reset if there was nothing
+		// to update, e.g. because the option that failed to be written to
+		// the Secret has since been removed from the Certificate.
+		if updated || c.secretSyncRecovered(crt) {
+			return c.setSecretSyncedCondition(ctx, crt, cmmeta.ConditionTrue, reasonSecretSynced, secretSyncedMessage(crt))
+		}
 		return nil
 	}
 
-	if crt.Status.NextPrivateKeySecretName == nil ||
-		len(*crt.Status.NextPrivateKeySecretName) == 0 {
-		// Do nothing if the next private key secret name is not set
-		return nil
-	}
+	// The private key is not available if it is held by an external signer,
+	// in which case the CertificateRequest is checked to have been created
+	// for the external key instead.
+	ref := externalkey.Ref(crt.Spec)
+	var pk crypto.Signer
+	if ref == nil {
+		if crt.Status.NextPrivateKeySecretName == nil ||
+			len(*crt.Status.NextPrivateKeySecretName) == 0 {
+			// Do nothing if the next private key secret name is not set
+			return nil
+		}
 
-	// Fetch and parse the 'next private key secret'
-	nextPrivateKeySecret, err := c.secretLister.Secrets(crt.Namespace).Get(*crt.Status.NextPrivateKeySecretName)
-	if apierrors.IsNotFound(err) {
-		log.V(logf.DebugLevel).Info("Next private key secret does not exist, waiting for keymanager controller")
-		// If secret does not exist, do nothing (keymanager will handle this).
-		return nil
-	}
-	if err != nil {
-		return err
-	}
-	if nextPrivateKeySecret.Data == nil || len(nextPrivateKeySecret.Data[corev1.TLSPrivateKeyKey]) == 0 {
-		logf.WithResource(log, nextPrivateKeySecret).Info("Next private key secret does not contain any private key data, waiting for keymanager controller")
-		return nil
-	}
-	pk, _, err := utilkube.ParseTLSKeyFromSecret(nextPrivateKeySecret, corev1.TLSPrivateKeyKey)
-	if err != nil {
-		// If the private key cannot be parsed here, do nothing as the key manager will handle this.
-		logf.WithResource(log, nextPrivateKeySecret).Error(err, "failed to parse next private key, waiting for keymanager controller")
-		return nil
-	}
-	pkViolations, err := certificates.PrivateKeyMatchesSpec(pk, crt.Spec)
-	if err != nil {
-		return err
-	}
-	if len(pkViolations) > 0 {
-		logf.WithResource(log, nextPrivateKeySecret).Info("stored next private key does not match requirements on Certificate resource, waiting for keymanager controller", "violations", pkViolations)
-		return nil
+		// Fetch and parse the 'next private key secret'
+		nextPrivateKeySecret, err := c.secretLister.Secrets(crt.Namespace).Get(*crt.Status.NextPrivateKeySecretName)
+		if apierrors.IsNotFound(err) {
+			log.V(logf.DebugLevel).Info("Next private key secret does not exist, waiting for keymanager controller")
+			// If secret does not exist, do nothing (keymanager will handle this).
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if nextPrivateKeySecret.Data == nil || len(nextPrivateKeySecret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+			logf.WithResource(log, nextPrivateKeySecret).Info("Next private key secret does not contain any private key data, waiting for keymanager controller")
+			return nil
+		}
+		pk, _, err = utilkube.ParseTLSKeyFromSecret(nextPrivateKeySecret, corev1.TLSPrivateKeyKey)
+		if err != nil {
+			// If the private key cannot be parsed here, do nothing as the key manager will handle this.
+			logf.WithResource(log, nextPrivateKeySecret).Error(err, "failed to parse next private key, waiting for keymanager controller")
+			return nil
+		}
+		pkViolations, err := utilpki.PrivateKeyMatchesSpec(pk, crt.Spec)
+		if err != nil {
+			return err
+		}
+		if len(pkViolations) > 0 {
+			logf.WithResource(log, nextPrivateKeySecret).Info("stored next private key does not match requirements on Certificate resource, waiting for keymanager controller", "violations", pkViolations)
+			return nil
+		}
 	}
 
 	// CertificateRequest revisions begin from 1. If no revision is set on the
@@ -227,7 +329,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 
 	// Verify the CSR options match what is requested in certificate.spec.
 	// If there are violations in the spec, then the requestmanager will handle this.
-	requestViolations, err := certificates.RequestMatchesSpec(req, crt.Spec)
+	requestViolations, err := utilpki.RequestMatchesSpec(req, crt.Spec)
 	if err != nil {
 		return err
 	}
@@ -267,13 +369,20 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
-	publicKeyMatchesCSR, err := utilpki.PublicKeyMatchesCSR(pk.Public(), csr)
-	if err != nil {
-		return err
-	}
-	if !publicKeyMatchesCSR {
-		logf.WithResource(log, nextPrivateKeySecret).Info("next private key does not match CSR public key, waiting for requestmanager controller")
-		return nil
+	if ref != nil {
+		if req.Annotations[cmapi.PrivateKeyExternalRefAnnotationKey] != externalkey.KeyURL(ref) {
+			log.V(logf.DebugLevel).Info("CertificateRequest was not created for the external private key, waiting for requestmanager controller")
+			return nil
+		}
+	} else {
+		publicKeyMatchesCSR, err := utilpki.PublicKeyMatchesCSR(pk.Public(), csr)
+		if err != nil {
+			return err
+		}
+		if !publicKeyMatchesCSR {
+			log.Info("next private key does not match CSR public key, waiting for requestmanager controller")
+			return nil
+		}
 	}
 
 	// If the CertificateRequest is valid and ready, verify its status and issue
@@ -284,9 +393,12 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 
 	// Issue temporary certificate if needed. If a certificate was issued, then
 	// return early - we will sync again since the target Secret has been
-	// updated.
-	if issued, err := c.ensureTemporaryCertificate(ctx, crt, pk); err != nil || issued {
-		return err
+	// updated. Temporary certificates cannot be issued for external private
+	// keys.
+	if pk != nil {
+		if issued, err := c.ensureTemporaryCertificate(ctx, crt, pk); err != nil || issued {
+			return err
+		}
 	}
 
 	// CertificateRequest is not in a final state so do nothing.
@@ -298,6 +410,12 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 // failed, and log an appropriate event. The reason and message of the
 // condition will be that of the CertificateRequest condition passed.
 func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger, crt *cmapi.Certificate, condition *cmapi.CertificateRequestCondition) error {
+	return c.failIssuance(ctx, log, crt, crt.DeepCopy(), condition)
+}
+
+// failIssuance is like failIssueCertificate, but also persists any changes
+// that have already been made to the status of crt, a copy of oldCrt.
+func (c *controller) failIssuance(ctx context.Context, log logr.Logger, oldCrt, crt *cmapi.Certificate, condition *cmapi.CertificateRequestCondition) error {
 	nowTime := metav1.NewTime(c.clock.Now())
 	crt.Status.LastFailureTime = &nowTime
 
@@ -308,10 +426,9 @@ func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger,
 	message = fmt.Sprintf("The certificate request has failed to complete and will be retried: %s",
 		condition.Message)
 
-	crt = crt.DeepCopy()
 	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuing, cmmeta.ConditionFalse, reason, message)
 
-	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	err := certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, oldCrt, crt)
 	if err != nil {
 		return err
 	}
@@ -324,25 +441,58 @@ func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger,
 // issueCertificate will ensure the public key of the CSR matches the signed
 // certificate, and then store the certificate, CA and private key into the
 // Secret in the appropriate format type.
-func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt *cmapi.Certificate, req *cmapi.CertificateRequest, pk crypto.Signer) error {
+func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt *cmapi.Certificate, req *cmapi.CertificateRequest, pk crypto.Signer) (err error) {
+	ctx, span := tracing.Start(ctx, "IssueCertificate", req)
+	defer func() { tracing.End(span, err) }()
+
+	oldCrt := crt
 	crt = crt.DeepCopy()
 	if crt.Spec.PrivateKey == nil {
 		crt.Spec.PrivateKey = &cmapi.CertificatePrivateKey{}
 	}
 
-	pkData, err := utilpki.EncodePrivateKey(pk, crt.Spec.PrivateKey.Encoding)
-	if err != nil {
-		return err
+	dropped := droppedSANs(req)
+	if len(dropped) > 0 && !crt.Spec.AllowPartialSANs {
+		return c.failIssueCertificate(ctx, logf.FromContext(ctx), oldCrt, &cmapi.CertificateRequestCondition{
+			Reason:  reasonSANsDropped,
+			Message: fmt.Sprintf("The issuer signed the certificate without the requested subject alternative names %s, set spec.allowPartialSANs to accept it", strings.Join(dropped, ", ")),
+		})
 	}
+
 	secretData := secretsmanager.SecretData{
-		PrivateKey:  pkData,
 		Certificate: req.Status.Certificate,
 		CA:          req.Status.CA,
+		Issuance: &secretsmanager.IssuanceData{
+			Revision:    nextRevision,
+			RequestName: req.Name,
+			Time:        c.clock.Now(),
+		},
+	}
+	if ref := crt.Spec.PrivateKey.ExternalRef; ref != nil {
+		// The Secret still needs a private key entry to be a valid TLS
+		// Secret.
+		secretData.PrivateKey = []byte{}
+		secretData.ExternalKeyURL = externalkey.KeyURL(ref)
+	} else {
+		pkData, err := utilpki.EncodePrivateKey(pk, crt.Spec.PrivateKey.Encoding)
+		if err != nil {
+			return err
+		}
+		secretData.PrivateKey = pkData
 	}
 
 	err = c.secretsManager.UpdateData(ctx, crt, secretData)
+	var tooLarge *secretsmanager.SecretTooLargeError
+	if errors.As(err, &tooLarge) {
+		crt = oldCrt.DeepCopy()
+		apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionSecretSynced, cmmeta.ConditionFalse, reasonSecretTooLarge, err.Error())
+		return c.failIssuance(ctx, logf.FromContext(ctx), oldCrt, crt, &cmapi.CertificateRequestCondition{
+			Reason:  reasonSecretTooLarge,
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
-		return err
+		return c.secretSyncFailed(ctx, oldCrt, err)
 	}
 
 	//Set status.revision to revision of the CertificateRequest
@@ -351,26 +501,120 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 	// Remove Issuing status condition
 	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuing)
 
+	// The Secret no longer contains an adopted certificate
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionAdopted)
+
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionSecretSynced, cmmeta.ConditionTrue, reasonSecretSynced, secretSyncedMessage(crt))
+
 	//Clear status.lastFailureTime (if set)
 	crt.Status.LastFailureTime = nil
 
-	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	crt.Status.DroppedSANs = dropped
+
+	err = certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, oldCrt, crt)
 	if err != nil {
 		return err
 	}
 
+	c.metrics.ObserveCertificateIssuance(ctx, crt, req)
+
 	message := "The certificate has been successfully issued"
 	c.recorder.Event(crt, corev1.EventTypeNormal, "Issuing", message)
+	if len(dropped) > 0 {
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSANsDropped, "The issuer signed the certificate without the requested subject alternative names %s", strings.Join(dropped, ", "))
+	}
 
 	return nil
 }
 
+// droppedSANs returns the subject alternative names requested by req that
+// the issuer left out of the signed certificate. If the request or
+// certificate cannot be decoded, no names are returned.
+func droppedSANs(req *cmapi.CertificateRequest) []string {
+	csr, err := utilpki.DecodeX509CertificateRequestBytes(req.Spec.Request)
+	if err != nil {
+		return nil
+	}
+	cert, err := utilpki.DecodeX509CertificateBytes(req.Status.Certificate)
+	if err != nil {
+		return nil
+	}
+	return utilpki.DroppedSANs(csr, cert)
+}
+
+// secretSyncFailed sets the SecretSynced condition of the Certificate to
+// False after the target Secret failed to be written, and records an event.
+// The error is returned so that the Certificate is retried.
+func (c *controller) secretSyncFailed(ctx context.Context, crt *cmapi.Certificate, err error) error {
+	var pending *secretsmanager.KeystoresPendingError
+	if errors.As(err, &pending) {
+		// The Secret will be written once the keystores have been encoded.
+		return err
+	}
+	message := fmt.Sprintf("Failed to write Secret %q: %v", crt.Spec.SecretName, err)
+	c.recorder.Event(crt, corev1.EventTypeWarning, reasonSecretSyncFailed, message)
+	if patchErr := c.setSecretSyncedCondition(ctx, crt, cmmeta.ConditionFalse, reasonSecretSyncFailed, message); patchErr != nil {
+		return utilerrors.NewAggregate([]error{err, patchErr})
+	}
+	return err
+}
+
+// setSecretSyncedCondition sets the SecretSynced condition of the Certificate
+// and persists it, unless the condition is already up to date.
+func (c *controller) setSecretSyncedCondition(ctx context.Context, crt *cmapi.Certificate, status cmmeta.ConditionStatus, reason, message string) error {
+	if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionSecretSynced); cond != nil &&
+		cond.Status == status && cond.Reason == reason && cond.Message == message && cond.ObservedGeneration == crt.Generation {
+		return nil
+	}
+
+	newCrt := crt.DeepCopy()
+	apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionSecretSynced, status, reason, message)
+	return certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, crt, newCrt)
+}
+
+// secretSyncRecovered returns true if the SecretSynced condition of the
+// Certificate is False, but its Secret holds an issued certificate whose
+// derived data is up to date.
+func (c *controller) secretSyncRecovered(crt *cmapi.Certificate) bool {
+	if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionSecretSynced,
+		Status: cmmeta.ConditionFalse,
+	}) {
+		return false
+	}
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if err != nil {
+		return false
+	}
+	return len(secret.Data[corev1.TLSCertKey]) > 0
+}
+
+func secretSyncedMessage(crt *cmapi.Certificate) string {
+	return fmt.Sprintf("The certificate data has been written to Secret %q", crt.Spec.SecretName)
+}
+
 // controllerWrapper wraps the `controller` structure to make it implement
 // the controllerpkg.queueingController interface
 type controllerWrapper struct {
 	*controller
 }
 
+// caIssuerStatus returns the status of the CA issuer of the Certificate, or
+// nil if the Certificate is not issued by a CA issuer.
+func (c *controller) caIssuerStatus(crt *cmapi.Certificate) *cmapi.CAIssuerStatus {
+	if c.issuerHelper == nil {
+		return nil
+	}
+	if group := crt.Spec.IssuerRef.Group; group != "" && group != certmanager.GroupName {
+		return nil
+	}
+	genericIssuer, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if err != nil {
+		return nil
+	}
+	return genericIssuer.GetStatus().CA
+}
+
 func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
 	// construct a new named logger to be reused throughout the controller
 	log := logf.FromContext(ctx.RootContext, ControllerName)
@@ -382,10 +626,35 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
 		ctx.Clock,
+		ctx.Metrics,
 		ctx.CertificateOptions,
+		ctx.StatusPatcher,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
+	// Read issuers so that changes to the trust anchors of CA issuers are
+	// written to the Secrets of the Certificates they have already issued.
+	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, ctrl.certificateLister, labels.Everything(), predicate.CertificateIssuer),
+	})
+	mustSync = append(mustSync, issuerInformer.Informer().HasSynced)
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if ctx.Namespace == "" {
+		clusterIssuerInformer := ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+			WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, ctrl.certificateLister, labels.Everything(), predicate.CertificateIssuer),
+		})
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+	issuerGrantInformer := ctx.SharedInformerFactory.Policy().V1alpha1().IssuerGrants()
+	mustSync = append(mustSync, issuerGrantInformer.Informer().HasSynced)
+	ctrl.issuerHelper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister, issuerGrantInformer.Lister())
+
 	return queue, mustSync, nil
 }
 
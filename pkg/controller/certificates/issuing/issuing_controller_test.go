@@ -17,13 +17,18 @@ limitations under the License.
 package issuing
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
@@ -32,8 +37,10 @@ import (
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/secretsmanager"
 	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -54,6 +61,10 @@ func TestIssuingController(t *testing.T) {
 
 		certificate *cmapi.Certificate
 
+		// secretWriteErr, if set, is returned when the target Secret is
+		// created or updated.
+		secretWriteErr error
+
 		expectedErr bool
 	}
 
@@ -71,6 +82,7 @@ func TestIssuingController(t *testing.T) {
 	exampleBundle := internaltest.MustCreateCryptoBundle(t, baseCert.DeepCopy(), fixedClock)
 
 	exampleBundleAlt := internaltest.MustCreateCryptoBundle(t, baseCert.DeepCopy(), fixedClock)
+	exampleChainFingerprint := chainFingerprint(t, exampleBundle.CertificateRequestReady.Status.Certificate)
 
 	issuingCert := gen.CertificateFrom(baseCert.DeepCopy(),
 		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
@@ -81,6 +93,58 @@ func TestIssuingController(t *testing.T) {
 	)
 
 	metaFixedClockStart := metav1.NewTime(fixedClockStart)
+	secretSyncedCondition := gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+		Type:               cmapi.CertificateConditionSecretSynced,
+		Status:             cmmeta.ConditionTrue,
+		Reason:             "Synced",
+		Message:            `The certificate data has been written to Secret "output"`,
+		LastTransitionTime: &metaFixedClockStart,
+		ObservedGeneration: 3,
+	})
+
+	externalKeyRef := &cmapi.PrivateKeyExternalRef{SignerURL: "https://signer.example.com", KeyID: "key"}
+	externalKeyCert := exampleBundle.Certificate.DeepCopy()
+	externalKeyCert.Spec.PrivateKey = &cmapi.CertificatePrivateKey{ExternalRef: externalKeyRef}
+	externalKeyIssuingCert := issuingCert.DeepCopy()
+	externalKeyIssuingCert.Spec.PrivateKey = &cmapi.CertificatePrivateKey{ExternalRef: externalKeyRef}
+
+	oversizedCA := make([]byte, corev1.MaxSecretSize)
+	oversizedSize := len(corev1.TLSCertKey) + len(exampleBundle.CertificateRequestReady.Status.Certificate) +
+		len(corev1.TLSPrivateKeyKey) + len(exampleBundle.PrivateKeyBytes) +
+		len(cmmeta.TLSCAKey) + len(oversizedCA)
+	oversizedMessage := (&secretsmanager.SecretTooLargeError{Size: oversizedSize}).Error()
+
+	// partialBundle requests two DNS names, but its CertificateRequest is
+	// signed for only one of them.
+	partialCert := gen.CertificateFrom(baseCert.DeepCopy(),
+		gen.SetCertificateDNSNames("example.com", "dropped.example.com"),
+	)
+	partialBundle := internaltest.MustCreateCryptoBundle(t, partialCert.DeepCopy(), fixedClock)
+	partialSignedCert := internaltest.MustCreateCert(t, partialBundle.PrivateKeyBytes, baseCert.DeepCopy())
+	partialRequest := gen.CertificateRequestFrom(partialBundle.CertificateRequestReady,
+		gen.SetCertificateRequestCertificate(partialSignedCert),
+		gen.AddCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+		}),
+	)
+	partialIssuingCert := gen.CertificateFrom(issuingCert,
+		gen.SetCertificateDNSNames("example.com", "dropped.example.com"),
+	)
+	allowPartialCert := gen.CertificateFrom(partialBundle.Certificate,
+		gen.SetCertificateAllowPartialSANs(true),
+	)
+	allowPartialIssuingCert := gen.CertificateFrom(partialIssuingCert,
+		gen.SetCertificateAllowPartialSANs(true),
+	)
+	nextPartialPrivateKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextPrivateKeySecretName,
+			Namespace: partialBundle.Certificate.Namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: partialBundle.PrivateKeyBytes,
+		},
+	}
 
 	tests := map[string]testT{
 		"if certificate is not in Issuing state, then do nothing": {
@@ -95,6 +159,43 @@ func TestIssuingController(t *testing.T) {
 			expectedErr: false,
 		},
 
+		"if certificate is not in Issuing state and its Secret is up to date, reset a SecretSynced=False condition": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(baseCert,
+						gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+							Type:               cmapi.CertificateConditionSecretSynced,
+							Status:             cmmeta.ConditionFalse,
+							Reason:             "KeystorePasswordMissing",
+							Message:            `Failed to write Secret "output": keystore password Secret "jks-password" not found`,
+							ObservedGeneration: 3,
+						}),
+					),
+				},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "output",
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewStatusPatchAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						gen.CertificateFrom(baseCert, secretSyncedCondition),
+					),
+				},
+			},
+			expectedErr: false,
+		},
+
 		"if certificate is an Issuing state but is set to False, then do nothing": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
@@ -242,10 +343,8 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
 							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
 								Type:               cmapi.CertificateConditionIssuing,
@@ -257,7 +356,7 @@ func TestIssuingController(t *testing.T) {
 							}),
 							gen.SetCertificateLastFailureTime(metaFixedClockStart),
 						),
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Warning Failed The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
@@ -405,14 +504,13 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
 							gen.SetCertificateRevision(2),
+							secretSyncedCondition,
 						),
-					)),
+					),
 					testpkg.NewAction(coretesting.NewCreateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
@@ -421,16 +519,20 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                    "test",
+									cmapi.IssuerKindAnnotationKey:               "Issuer",
+									cmapi.IssuerNameAnnotationKey:               "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey:              "foo.io",
+									cmapi.CommonNameAnnotationKey:               "",
+									cmapi.AltNamesAnnotationKey:                 "example.com",
+									cmapi.IPSANAnnotationKey:                    "",
+									cmapi.URISANAnnotationKey:                   "",
+									cmapi.IssuanceRevisionAnnotationKey:         "2",
+									cmapi.IssuanceRequestNameAnnotationKey:      exampleBundle.CertificateRequestReady.Name,
+									cmapi.IssuanceTimeAnnotationKey:             fixedClockStart.UTC().Format(time.RFC3339),
+									cmapi.IssuanceChainFingerprintAnnotationKey: exampleChainFingerprint,
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
@@ -447,6 +549,268 @@ func TestIssuingController(t *testing.T) {
 			expectedErr: false,
 		},
 
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, but the issuer dropped a requested SAN, set failed state and log an event": {
+			certificate: partialBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(partialIssuingCert),
+					partialRequest,
+				},
+				KubeObjects: []runtime.Object{nextPartialPrivateKeySecret},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewStatusPatchAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						gen.CertificateFrom(partialBundle.Certificate,
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionIssuing,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "SANsDropped",
+								Message:            "The certificate request has failed to complete and will be retried: The issuer signed the certificate without the requested subject alternative names dropped.example.com, set spec.allowPartialSANs to accept it",
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+						),
+					),
+				},
+				ExpectedEvents: []string{
+					"Warning SANsDropped The certificate request has failed to complete and will be retried: The issuer signed the certificate without the requested subject alternative names dropped.example.com, set spec.allowPartialSANs to accept it",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate allows partial SANs and is in Issuing state, one CertificateRequests, and is ready, but the issuer dropped a requested SAN, store the certificate, record the dropped SANs and log events": {
+			certificate: allowPartialCert,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(allowPartialIssuingCert),
+					partialRequest,
+				},
+				KubeObjects: []runtime.Object{nextPartialPrivateKeySecret},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewStatusPatchAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						gen.CertificateFrom(allowPartialCert,
+							gen.SetCertificateRevision(2),
+							gen.SetCertificateDroppedSANs("dropped.example.com"),
+							secretSyncedCondition,
+						),
+					),
+					testpkg.NewCustomMatch(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						partialBundle.Certificate.Namespace,
+						nil,
+					), func(_, act coretesting.Action) error {
+						// the annotations of the Secret are covered by other cases
+						create, ok := act.(coretesting.CreateAction)
+						if !ok || !act.Matches("create", "secrets") {
+							return fmt.Errorf("expected a create action for a Secret, got %v", act)
+						}
+						secret := create.GetObject().(*corev1.Secret)
+						if !bytes.Equal(secret.Data[corev1.TLSCertKey], partialSignedCert) {
+							return fmt.Errorf("unexpected certificate in created Secret %q", secret.Name)
+						}
+						return nil
+					}),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing The certificate has been successfully issued",
+					"Warning SANsDropped The issuer signed the certificate without the requested subject alternative names dropped.example.com",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, but the Secret cannot be written, set the SecretSynced condition to False and log an event": {
+			certificate:    exampleBundle.Certificate,
+			secretWriteErr: apierrors.NewForbidden(corev1.Resource("secrets"), "output", errors.New("not permitted")),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewCustomMatch(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						nil,
+					), func(_, act coretesting.Action) error {
+						// the contents of the Secret are covered by other cases
+						create, ok := act.(coretesting.CreateAction)
+						if !ok || !act.Matches("create", "secrets") {
+							return fmt.Errorf("expected a create action for a Secret, got %v", act)
+						}
+						if name := create.GetObject().(*corev1.Secret).Name; name != "output" {
+							return fmt.Errorf("unexpected name of created Secret, exp=%q got=%q", "output", name)
+						}
+						return nil
+					}),
+					testpkg.NewStatusPatchAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						gen.CertificateFrom(issuingCert,
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionSecretSynced,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "SecretSyncFailed",
+								Message:            `Failed to write Secret "output": secrets "output" is forbidden: not permitted`,
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+						),
+					),
+				},
+				ExpectedEvents: []string{
+					`Warning SecretSyncFailed Failed to write Secret "output": secrets "output" is forbidden: not permitted`,
+				},
+			},
+			expectedErr: true,
+		},
+
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, but the Secret would exceed the maximum Secret size, set failed state and log events": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+						gen.SetCertificateRequestCA(oversizedCA),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewStatusPatchAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionIssuing,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "SecretTooLarge",
+								Message:            "The certificate request has failed to complete and will be retried: " + oversizedMessage,
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionSecretSynced,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "SecretTooLarge",
+								Message:            oversizedMessage,
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+						),
+					),
+				},
+				ExpectedEvents: []string{
+					`Warning SecretSizeWarning Not writing Secret "output": ` + oversizedMessage,
+					"Warning SecretTooLarge The certificate request has failed to complete and will be retried: " + oversizedMessage,
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate with an external private key is in Issuing state, one CertificateRequest for a different key, do nothing": {
+			certificate: externalKeyCert,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					externalKeyIssuingCert.DeepCopy(),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+							cmapi.PrivateKeyExternalRefAnnotationKey:      "https://signer.example.com/keys/other",
+						}),
+					)},
+				KubeObjects:     []runtime.Object{},
+				ExpectedActions: []testpkg.Action{},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate with an external private key is in Issuing state, one CertificateRequests, and is ready, store the signed certificate and ca without a private key to a new secret, and log an event": {
+			certificate: externalKeyCert,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					externalKeyIssuingCert.DeepCopy(),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+							cmapi.PrivateKeyExternalRefAnnotationKey:      "https://signer.example.com/keys/key",
+						}),
+					)},
+				KubeObjects: []runtime.Object{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewStatusPatchAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						gen.CertificateFrom(externalKeyCert,
+							gen.SetCertificateRevision(2),
+							secretSyncedCondition,
+						),
+					),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:                    "test",
+									cmapi.IssuerKindAnnotationKey:               "Issuer",
+									cmapi.IssuerNameAnnotationKey:               "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey:              "foo.io",
+									cmapi.PrivateKeyExternalRefAnnotationKey:    "https://signer.example.com/keys/key",
+									cmapi.CommonNameAnnotationKey:               "",
+									cmapi.AltNamesAnnotationKey:                 "example.com",
+									cmapi.IPSANAnnotationKey:                    "",
+									cmapi.URISANAnnotationKey:                   "",
+									cmapi.IssuanceRevisionAnnotationKey:         "2",
+									cmapi.IssuanceRequestNameAnnotationKey:      exampleBundle.CertificateRequestReady.Name,
+									cmapi.IssuanceTimeAnnotationKey:             fixedClockStart.UTC().Format(time.RFC3339),
+									cmapi.IssuanceChainFingerprintAnnotationKey: exampleChainFingerprint,
+								},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+								corev1.TLSPrivateKeyKey: {},
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing The certificate has been successfully issued",
+				},
+			},
+			expectedErr: false,
+		},
+
 		"if certificate is in Issuing state, one CertificateRequests, and is ready, store the signed certificate, ca, and private key to an existing secret, and log an event": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
@@ -480,14 +844,13 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
 							gen.SetCertificateRevision(2),
+							secretSyncedCondition,
 						),
-					)),
+					),
 					testpkg.NewAction(coretesting.NewUpdateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
@@ -496,17 +859,21 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom":                    "annotation",
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									"my-custom":                                 "annotation",
+									cmapi.CertificateNameKey:                    "test",
+									cmapi.IssuerGroupAnnotationKey:              "foo.io",
+									cmapi.IssuerKindAnnotationKey:               "Issuer",
+									cmapi.IssuerNameAnnotationKey:               "ca-issuer",
+									cmapi.CommonNameAnnotationKey:               "",
+									cmapi.AltNamesAnnotationKey:                 "example.com",
+									cmapi.IPSANAnnotationKey:                    "",
+									cmapi.URISANAnnotationKey:                   "",
+									cmapi.IssuanceRevisionAnnotationKey:         "2",
+									cmapi.IssuanceRequestNameAnnotationKey:      exampleBundle.CertificateRequestReady.Name,
+									cmapi.IssuanceTimeAnnotationKey:             fixedClockStart.UTC().Format(time.RFC3339),
+									cmapi.IssuanceChainFingerprintAnnotationKey: exampleChainFingerprint,
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
@@ -566,7 +933,7 @@ func TestIssuingController(t *testing.T) {
 									cmapi.IPSANAnnotationKey:       "",
 									cmapi.URISANAnnotationKey:      "",
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
@@ -638,7 +1005,7 @@ func TestIssuingController(t *testing.T) {
 									cmapi.IPSANAnnotationKey:       "",
 									cmapi.URISANAnnotationKey:      "",
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
@@ -759,7 +1126,7 @@ func TestIssuingController(t *testing.T) {
 									cmapi.IPSANAnnotationKey:       "",
 									cmapi.URISANAnnotationKey:      "",
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
@@ -847,17 +1214,16 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
 							gen.AddCertificateAnnotations(map[string]string{
 								cmapi.IssueTemporaryCertificateAnnotation: "true",
 							}),
 							gen.SetCertificateRevision(2),
+							secretSyncedCondition,
 						),
-					)),
+					),
 					testpkg.NewAction(coretesting.NewCreateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
@@ -866,16 +1232,20 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                    "test",
+									cmapi.IssuerGroupAnnotationKey:              "foo.io",
+									cmapi.IssuerKindAnnotationKey:               "Issuer",
+									cmapi.IssuerNameAnnotationKey:               "ca-issuer",
+									cmapi.CommonNameAnnotationKey:               "",
+									cmapi.AltNamesAnnotationKey:                 "example.com",
+									cmapi.IPSANAnnotationKey:                    "",
+									cmapi.URISANAnnotationKey:                   "",
+									cmapi.IssuanceRevisionAnnotationKey:         "2",
+									cmapi.IssuanceRequestNameAnnotationKey:      exampleBundle.CertificateRequestReady.Name,
+									cmapi.IssuanceTimeAnnotationKey:             fixedClockStart.UTC().Format(time.RFC3339),
+									cmapi.IssuanceChainFingerprintAnnotationKey: exampleChainFingerprint,
 								},
-								Labels: map[string]string{},
+								Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							},
 							Data: map[string][]byte{
 								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
@@ -923,10 +1293,8 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
 							gen.AddCertificateAnnotations(map[string]string{
 								cmapi.IssueTemporaryCertificateAnnotation: "true",
@@ -941,7 +1309,7 @@ func TestIssuingController(t *testing.T) {
 							}),
 							gen.SetCertificateLastFailureTime(metaFixedClockStart),
 						),
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Warning Failed The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
@@ -977,10 +1345,8 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
 							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
 								Type:               cmapi.CertificateConditionIssuing,
@@ -992,7 +1358,7 @@ func TestIssuingController(t *testing.T) {
 							}),
 							gen.SetCertificateLastFailureTime(metaFixedClockStart),
 						),
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Warning DeniedReason The certificate request has failed to complete and will be retried: The certificate request has been denied",
@@ -1009,6 +1375,14 @@ func TestIssuingController(t *testing.T) {
 			test.builder.T = t
 			test.builder.Init()
 			defer test.builder.Stop()
+			if test.secretWriteErr != nil {
+				test.builder.FakeKubeClient().PrependReactor("*", "secrets", func(action coretesting.Action) (bool, runtime.Object, error) {
+					if action.GetVerb() != "create" && action.GetVerb() != "update" {
+						return false, nil, nil
+					}
+					return true, nil, test.secretWriteErr
+				})
+			}
 
 			w := controllerWrapper{}
 			_, _, err := w.Register(test.builder.Context)
@@ -1033,3 +1407,17 @@ func TestIssuingController(t *testing.T) {
 		})
 	}
 }
+
+// chainFingerprint returns the expected value of the issuance chain
+// fingerprint annotation for the given PEM encoded certificate chain.
+func chainFingerprint(t *testing.T, chainPEM []byte) string {
+	chain, err := pki.DecodeX509CertificateChainBytes(chainPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.New()
+	for _, cert := range chain {
+		hash.Write(cert.Raw)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
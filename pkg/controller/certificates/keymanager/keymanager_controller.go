@@ -43,6 +43,8 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/externalkey"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -62,6 +64,8 @@ type controller struct {
 	certificateLister cmlisters.CertificateLister
 	secretLister      corelisters.SecretLister
 	client            cmclient.Interface
+	statusPatcher     *statuspatch.Patcher
+	fieldManager      string
 	coreClient        kubernetes.Interface
 	recorder          record.EventRecorder
 }
@@ -73,9 +77,14 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
+	statusPatcher *statuspatch.Patcher,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
@@ -107,6 +116,8 @@ func NewController(
 		certificateLister: certificateInformer.Lister(),
 		secretLister:      secretsInformer.Lister(),
 		client:            client,
+		statusPatcher:     statusPatcher,
+		fieldManager:      controllerpkg.FieldManager(ControllerName),
 		coreClient:        coreClient,
 		recorder:          recorder,
 	}, queue, mustSync
@@ -149,6 +160,16 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	// Private keys held by an external signer are never generated or stored
+	// by cert-manager.
+	if externalkey.Ref(crt.Spec) != nil {
+		log.V(logf.DebugLevel).Info("Cleaning up Secret resources and unsetting nextPrivateKeySecretName as the private key is held by an external signer")
+		if err := c.deleteSecretResources(ctx, secrets); err != nil {
+			return err
+		}
+		return c.setNextPrivateKeySecretName(ctx, crt, nil)
+	}
+
 	if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
@@ -209,7 +230,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return c.deleteSecretResources(ctx, secrets)
 	}
 
-	violations, err := certificates.PrivateKeyMatchesSpec(pk, crt.Spec)
+	violations, err := pki.PrivateKeyMatchesSpec(pk, crt.Spec)
 	if err != nil {
 		log.Error(err, "Internal error verifying if private key matches spec - please open an issue.")
 		return nil
@@ -243,7 +264,7 @@ func (c *controller) createNextPrivateKeyRotationPolicyNever(ctx context.Context
 		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonDecodeFailed, "Failed to decode private key stored in Secret %q - generating new key", crt.Spec.SecretName)
 		return c.createAndSetNextPrivateKey(ctx, crt)
 	}
-	violations, err := certificates.PrivateKeyMatchesSpec(pk, crt.Spec)
+	violations, err := pki.PrivateKeyMatchesSpec(pk, crt.Spec)
 	if err != nil {
 		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonDecodeFailed, "Failed to check if private key stored in Secret %q is up to date - generating new key", crt.Spec.SecretName)
 		return c.createAndSetNextPrivateKey(ctx, crt)
@@ -301,10 +322,10 @@ func (c *controller) setNextPrivateKeySecretName(ctx context.Context, crt *cmapi
 			return nil
 		}
 	}
+	oldCrt := crt
 	crt = crt.DeepCopy()
 	crt.Status.NextPrivateKeySecretName = name
-	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
-	return err
+	return certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, oldCrt, crt)
 }
 
 func (c *controller) createNewPrivateKeySecret(ctx context.Context, crt *cmapi.Certificate, pk crypto.Signer) (*corev1.Secret, error) {
@@ -326,7 +347,8 @@ func (c *controller) createNewPrivateKeySecret(ctx context.Context, crt *cmapi.C
 			Name:            name,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)},
 			Labels: map[string]string{
-				"cert-manager.io/next-private-key": "true",
+				"cert-manager.io/next-private-key":        "true",
+				cmapi.PartOfCertManagerControllerLabelKey: "true",
 			},
 		},
 		Data: map[string][]byte{
@@ -360,9 +382,13 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
+		ctx.StatusPatcher,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
 	return queue, mustSync, nil
 }
 
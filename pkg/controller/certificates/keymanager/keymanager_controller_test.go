@@ -157,10 +157,8 @@ func TestProcessItem(t *testing.T) {
 			},
 			expectedEvents: []string{`Normal Generated Stored new private key in temporary Secret resource "test-notrandom"`},
 			expectedActions: []testpkg.Action{
-				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+				testpkg.NewStatusPatchAction(
 					cmapi.SchemeGroupVersion.WithResource("certificates"),
-					"status",
-					"testns",
 					&cmapi.Certificate{
 						ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
 						Status: cmapi.CertificateStatus{
@@ -173,7 +171,7 @@ func TestProcessItem(t *testing.T) {
 							},
 						},
 					},
-				)),
+				),
 				testpkg.NewCustomMatch(coretesting.NewCreateAction(
 					corev1.SchemeGroupVersion.WithResource("secrets"),
 					"testns",
@@ -181,7 +179,7 @@ func TestProcessItem(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace:       "testns",
 							GenerateName:    "test-",
-							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true", cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
 						},
 						Data: map[string][]byte{"tls.key": nil},
@@ -211,7 +209,7 @@ func TestProcessItem(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace:       "testns",
 							Name:            "fixed-name",
-							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true", cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
 						},
 						Data: map[string][]byte{"tls.key": nil},
@@ -243,7 +241,7 @@ func TestProcessItem(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace:       "testns",
 							Name:            "fixed-name",
-							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true", cmapi.PartOfCertManagerControllerLabelKey: "true"},
 							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
 						},
 						Data: map[string][]byte{"tls.key": nil},
@@ -352,10 +350,8 @@ func TestProcessItem(t *testing.T) {
 				ownedSecretWithName("testns", "fixed-name", "test", nil),
 			},
 			expectedActions: []testpkg.Action{
-				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+				testpkg.NewStatusPatchAction(
 					cmapi.SchemeGroupVersion.WithResource("certificates"),
-					"status",
-					"testns",
 					&cmapi.Certificate{
 						ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: types.UID("test")},
 						Status: cmapi.CertificateStatus{
@@ -368,7 +364,7 @@ func TestProcessItem(t *testing.T) {
 							},
 						},
 					},
-				)),
+				),
 			},
 		},
 		"if an owned secret exists but has a different name to nextPrivateKeySecretName, delete it": {
@@ -55,9 +55,13 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	metrics *metrics.Metrics,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
@@ -111,9 +115,12 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Metrics,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
 	return queue, mustSync, nil
 }
 
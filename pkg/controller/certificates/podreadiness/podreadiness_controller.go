@@ -0,0 +1,269 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podreadiness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "certificates-pod-readiness"
+
+	reasonCertificateReady    = "CertificateReady"
+	reasonCertificateNotReady = "CertificateNotReady"
+	reasonCertificateNotFound = "CertificateNotFound"
+)
+
+// controller sets the 'cert-manager.io/certificate-ready' condition of Pods
+// that reference a Certificate using the 'cert-manager.io/pod-certificate'
+// annotation, so that the readiness gate added by the webhook only passes
+// once the Certificate is Ready.
+type controller struct {
+	podLister         corelisters.PodLister
+	certificateLister cmlisters.CertificateLister
+	client            kubernetes.Interface
+	clock             clock.Clock
+}
+
+func NewController(
+	log logr.Logger,
+	client kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	clock clock.Clock,
+	workqueueOptions controllerpkg.WorkqueueOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	podInformer := factory.Core().V1().Pods()
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+
+	podInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueAnnotatedPod(log, queue),
+	})
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		// Trigger reconciles of the Pods that reference a Certificate when
+		// the Certificate changes
+		WorkFunc: enqueuePodsForCertificate(log, queue, podInformer.Lister()),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		podInformer.Informer().HasSynced,
+		certificateInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		podLister:         podInformer.Lister(),
+		certificateLister: certificateInformer.Lister(),
+		client:            client,
+		clock:             clock,
+	}, queue, mustSync
+}
+
+func enqueueAnnotatedPod(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			// Pod deletions are not interesting to this controller
+			return
+		}
+		if _, ok := pod.Annotations[cmapi.PodCertificateAnnotationKey]; !ok {
+			return
+		}
+		key, err := controllerpkg.KeyFunc(pod)
+		if err != nil {
+			log.Error(err, "error computing key for pod")
+			return
+		}
+		queue.Add(key)
+	}
+}
+
+func enqueuePodsForCertificate(log logr.Logger, queue workqueue.Interface, podLister corelisters.PodLister) func(obj interface{}) {
+	return func(obj interface{}) {
+		key, err := controllerpkg.KeyFunc(obj)
+		if err != nil {
+			log.Error(err, "error computing key for certificate")
+			return
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			log.Error(err, "invalid certificate key")
+			return
+		}
+		pods, err := podLister.Pods(namespace).List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list pods")
+			return
+		}
+		for _, pod := range pods {
+			if pod.Annotations[cmapi.PodCertificateAnnotationKey] != name {
+				continue
+			}
+			podKey, err := controllerpkg.KeyFunc(pod)
+			if err != nil {
+				log.Error(err, "error computing key for pod")
+				continue
+			}
+			queue.Add(podKey)
+		}
+	}
+}
+
+// ProcessItem sets the Certificate readiness condition of the Pod with the
+// given key to match the Ready condition of the Certificate it references.
+// Pods without the readiness gate for the condition are ignored.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	pod, err := c.podLister.Pods(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("pod not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	crtName, ok := pod.Annotations[cmapi.PodCertificateAnnotationKey]
+	if !ok || !hasReadinessGate(pod) {
+		return nil
+	}
+	log = log.WithValues("certificate", crtName)
+
+	condition := corev1.PodCondition{
+		Type:    cmapi.PodConditionCertificateReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  reasonCertificateNotReady,
+		Message: fmt.Sprintf("Certificate %q is not Ready", crtName),
+	}
+	crt, err := c.certificateLister.Certificates(namespace).Get(crtName)
+	switch {
+	case apierrors.IsNotFound(err):
+		condition.Reason = reasonCertificateNotFound
+		condition.Message = fmt.Sprintf("Certificate %q does not exist", crtName)
+	case err != nil:
+		return err
+	case apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionReady,
+		Status: cmmeta.ConditionTrue,
+	}):
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = reasonCertificateReady
+		condition.Message = fmt.Sprintf("Certificate %q is Ready", crtName)
+	}
+
+	pod = pod.DeepCopy()
+	if !c.setCondition(pod, condition) {
+		return nil
+	}
+
+	log.V(logf.DebugLevel).Info("updating pod certificate readiness condition", "status", condition.Status)
+	_, err = c.client.CoreV1().Pods(pod.Namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	return err
+}
+
+func hasReadinessGate(pod *corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == cmapi.PodConditionCertificateReady {
+			return true
+		}
+	}
+	return false
+}
+
+// setCondition sets the given condition on the Pod, and returns false if the
+// Pod already had an identical condition.
+func (c *controller) setCondition(pod *corev1.Pod, condition corev1.PodCondition) bool {
+	condition.LastTransitionTime = metav1.NewTime(c.clock.Now())
+	for i, cond := range pod.Status.Conditions {
+		if cond.Type != condition.Type {
+			continue
+		}
+		if cond.Status == condition.Status && cond.Reason == condition.Reason && cond.Message == condition.Message {
+			return false
+		}
+		if cond.Status == condition.Status {
+			condition.LastTransitionTime = cond.LastTransitionTime
+		}
+		pod.Status.Conditions[i] = condition
+		return true
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, condition)
+	return true
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log, ctx.Client, ctx.KubeSharedInformerFactory, ctx.SharedInformerFactory, ctx.Clock, ctx.WorkqueueOptions)
+	c.controller = ctrl
+
+	ctx.RequeueOnNamespaceChange(ctx.KubeSharedInformerFactory.Core().V1().Pods().Informer(), enqueueAnnotatedPod(log, queue))
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
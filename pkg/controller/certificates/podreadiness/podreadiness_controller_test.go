@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podreadiness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	fixedNow := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(fixedNow.Add(-time.Hour))
+
+	readyCrt := gen.Certificate("test",
+		gen.SetCertificateNamespace("ns"),
+		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}),
+	)
+	notReadyCrt := gen.Certificate("test",
+		gen.SetCertificateNamespace("ns"),
+		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionFalse}),
+	)
+
+	pod := func(gated bool, conditions ...corev1.PodCondition) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Namespace:   "ns",
+				Annotations: map[string]string{cmapi.PodCertificateAnnotationKey: "test"},
+			},
+			Status: corev1.PodStatus{Conditions: conditions},
+		}
+		if gated {
+			pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: cmapi.PodConditionCertificateReady}}
+		}
+		return pod
+	}
+	readyCondition := corev1.PodCondition{
+		Type:               cmapi.PodConditionCertificateReady,
+		Status:             corev1.ConditionTrue,
+		Reason:             reasonCertificateReady,
+		Message:            `Certificate "test" is Ready`,
+		LastTransitionTime: fixedNow,
+	}
+	notReadyCondition := corev1.PodCondition{
+		Type:               cmapi.PodConditionCertificateReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             reasonCertificateNotReady,
+		Message:            `Certificate "test" is not Ready`,
+		LastTransitionTime: earlier,
+	}
+	notFoundCondition := corev1.PodCondition{
+		Type:               cmapi.PodConditionCertificateReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             reasonCertificateNotFound,
+		Message:            `Certificate "test" does not exist`,
+		LastTransitionTime: earlier,
+	}
+	updateStatus := func(pod *corev1.Pod) testpkg.Action {
+		return testpkg.NewAction(coretesting.NewUpdateSubresourceAction(corev1.SchemeGroupVersion.WithResource("pods"), "status", "ns", pod))
+	}
+
+	tests := map[string]struct {
+		pod             *corev1.Pod
+		certificate     *cmapi.Certificate
+		expectedActions []testpkg.Action
+	}{
+		"does nothing if the pod has no readiness gate": {
+			pod:         pod(false),
+			certificate: readyCrt,
+		},
+		"sets the condition to True once the certificate is Ready": {
+			pod:             pod(true, notReadyCondition),
+			certificate:     readyCrt,
+			expectedActions: []testpkg.Action{updateStatus(pod(true, readyCondition))},
+		},
+		"sets the condition to False if the certificate is not Ready": {
+			pod:         pod(true),
+			certificate: notReadyCrt,
+			expectedActions: []testpkg.Action{updateStatus(pod(true, func() corev1.PodCondition {
+				c := notReadyCondition
+				c.LastTransitionTime = fixedNow
+				return c
+			}()))},
+		},
+		"keeps the transition time if only the reason changes": {
+			pod:             pod(true, notFoundCondition),
+			certificate:     notReadyCrt,
+			expectedActions: []testpkg.Action{updateStatus(pod(true, notReadyCondition))},
+		},
+		"does nothing if the condition is up to date": {
+			pod:         pod(true, notReadyCondition),
+			certificate: notReadyCrt,
+		},
+		"sets the condition to False if the certificate does not exist": {
+			pod: pod(true, readyCondition),
+			expectedActions: []testpkg.Action{updateStatus(pod(true, func() corev1.PodCondition {
+				c := notFoundCondition
+				c.LastTransitionTime = fixedNow
+				return c
+			}()))},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:               t,
+				Clock:           fakeclock.NewFakeClock(fixedNow.Time),
+				KubeObjects:     []runtime.Object{test.pod},
+				ExpectedActions: test.expectedActions,
+			}
+			if test.certificate != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.certificate)
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.controller.ProcessItem(context.Background(), "ns/pod"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
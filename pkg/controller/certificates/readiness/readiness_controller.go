@@ -41,6 +41,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -60,6 +61,8 @@ type controller struct {
 	certificateRequestLister cmlisters.CertificateRequestLister
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
+	statusPatcher            *statuspatch.Patcher
+	fieldManager             string
 	gatherer                 *policies.Gatherer
 	// policyEvaluator builds Ready condition of a Certificate based on policy evaluation
 	policyEvaluator policyEvaluatorFunc
@@ -79,9 +82,14 @@ func NewController(
 	chain policies.Chain,
 	renewalTimeCalculator certificates.RenewalTimeFunc,
 	policyEvaluator policyEvaluatorFunc,
+	statusPatcher *statuspatch.Patcher,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
@@ -115,6 +123,8 @@ func NewController(
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
+		statusPatcher:            statusPatcher,
+		fieldManager:             controllerpkg.FieldManager(ControllerName),
 		gatherer: &policies.Gatherer{
 			CertificateRequestLister: certificateRequestInformer.Lister(),
 			SecretLister:             secretsInformer.Lister(),
@@ -187,7 +197,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		log.V(logf.DebugLevel).Info("updating status fields", "notAfter",
 			crt.Status.NotAfter, "notBefore", crt.Status.NotBefore, "renewalTime",
 			crt.Status.RenewalTime)
-		_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+		err = certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, oldCrt, crt)
 		if err != nil {
 			return err
 		}
@@ -223,6 +233,7 @@ func NewReadinessPolicyChain(c clock.Clock) policies.Chain {
 		policies.SecretIsMissingData,
 		policies.SecretPublicKeysDiffer,
 		policies.CurrentCertificateRequestNotValidForSpec,
+		policies.CurrentCertificateRevoked,
 		policies.CurrentCertificateHasExpired(c),
 	}
 }
@@ -244,9 +255,13 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		NewReadinessPolicyChain(ctx.Clock),
 		certificates.RenewalTime,
 		policyEvaluator,
+		ctx.StatusPatcher,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
 	return queue, mustSync, nil
 }
 
@@ -23,7 +23,6 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -291,11 +290,9 @@ func TestProcessItem(t *testing.T) {
 				c.Status.RenewalTime = test.renewalTime
 
 				builder.ExpectedActions = append(builder.ExpectedActions,
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						c.Namespace,
-						c)))
+						c))
 			}
 
 			// Start the informers and begin processing updates.
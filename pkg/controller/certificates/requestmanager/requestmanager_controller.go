@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"strconv"
@@ -46,7 +47,10 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/externalkey"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/tracing"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
@@ -55,6 +59,8 @@ const (
 	ControllerName      = "certificates-request-manager"
 	reasonRequestFailed = "RequestFailed"
 	reasonRequested     = "Requested"
+	reasonExternalKey   = "ExternalKeyFailed"
+	reasonKeyMismatch   = "PrivateKeyMismatch"
 )
 
 var (
@@ -69,6 +75,17 @@ type controller struct {
 	recorder                 record.EventRecorder
 	clock                    clock.Clock
 	copiedAnnotationPrefixes []string
+
+	// externalSigner is used to sign certificate signing requests for
+	// Certificates whose private key is held by an external signer.
+	externalSigner externalkey.SignerFunc
+	// externalSignerURLs are the signer service URLs that external private
+	// keys may be used from.
+	externalSignerURLs []string
+
+	// issuerHelper is used to record the UID of the referenced issuer on
+	// new CertificateRequests. It may be nil.
+	issuerHelper issuer.Helper
 }
 
 func NewController(
@@ -79,9 +96,13 @@ func NewController(
 	recorder record.EventRecorder,
 	clock clock.Clock,
 	certificateControllerOptions controllerpkg.CertificateOptions,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
@@ -118,6 +139,8 @@ func NewController(
 		recorder:                 recorder,
 		clock:                    clock,
 		copiedAnnotationPrefixes: certificateControllerOptions.CopiedAnnotationPrefixes,
+		externalSigner:           externalkey.NewSignerFunc(secretsInformer.Lister()),
+		externalSignerURLs:       certificateControllerOptions.ExternalKeySignerURLs,
 	}, queue, mustSync
 }
 
@@ -147,28 +170,10 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return nil
 	}
 
-	// Check for and fetch the 'status.nextPrivateKeySecretName' secret
-	if crt.Status.NextPrivateKeySecretName == nil {
-		log.V(logf.DebugLevel).Info("status.nextPrivateKeySecretName not yet set, waiting for keymanager before processing certificate")
-		return nil
-	}
-	nextPrivateKeySecret, err := c.secretLister.Secrets(crt.Namespace).Get(*crt.Status.NextPrivateKeySecretName)
-	if apierrors.IsNotFound(err) {
-		log.V(logf.DebugLevel).Info("nextPrivateKeySecretName Secret resource does not exist, waiting for keymanager to create it before continuing")
-		return nil
-	}
-	if err != nil {
+	pk, nextPrivateKeySecretName, err := c.nextPrivateKey(ctx, crt)
+	if err != nil || pk == nil {
 		return err
 	}
-	if nextPrivateKeySecret.Data == nil || len(nextPrivateKeySecret.Data[corev1.TLSPrivateKeyKey]) == 0 {
-		log.V(logf.DebugLevel).Info("Next private key secret does not contain any valid data, waiting for keymanager before processing certificate")
-		return nil
-	}
-	pk, err := pki.DecodePrivateKeyBytes(nextPrivateKeySecret.Data[corev1.TLSPrivateKeyKey])
-	if err != nil {
-		log.Error(err, "Failed to decode next private key secret data, waiting for keymanager before processing certificate")
-		return nil
-	}
 
 	// Discover all 'owned' CertificateRequests
 	requests, err := certificates.ListCertificateRequestsMatchingPredicates(c.certificateRequestLister.CertificateRequests(crt.Namespace), labels.Everything(), predicate.ResourceOwnedBy(crt))
@@ -217,7 +222,57 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return nil
 	}
 
-	return c.createNewCertificateRequest(ctx, crt, pk, nextRevision, nextPrivateKeySecret.Name)
+	return c.createNewCertificateRequest(ctx, crt, pk, nextRevision, nextPrivateKeySecretName)
+}
+
+// nextPrivateKey returns the signer that should be used to sign the next
+// CertificateRequest, along with the name of the 'next private key' Secret
+// it was read from. If the private key is held by an external signer, the
+// Secret name is empty. If the private key is not available yet, a nil
+// signer is returned.
+func (c *controller) nextPrivateKey(ctx context.Context, crt *cmapi.Certificate) (crypto.Signer, string, error) {
+	log := logf.FromContext(ctx)
+
+	if ref := externalkey.Ref(crt.Spec); ref != nil {
+		if !externalkey.SignerURLAllowed(ref.SignerURL, c.externalSignerURLs) {
+			// The Certificate is resynced when it is changed, so there is
+			// no point in retrying until then.
+			log.V(logf.DebugLevel).Info("external signer is not allowed by the controller, not requesting certificate", "signer_url", ref.SignerURL)
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonExternalKey, "External signer %q is not allowed by the --external-key-signer-urls flag of the controller", ref.SignerURL)
+			return nil, "", nil
+		}
+		pk, err := c.externalSigner(ctx, crt)
+		if err != nil {
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonExternalKey, "Failed to use external private key: %v", err)
+			return nil, "", err
+		}
+		return pk, "", nil
+	}
+
+	// Check for and fetch the 'status.nextPrivateKeySecretName' secret
+	if crt.Status.NextPrivateKeySecretName == nil {
+		log.V(logf.DebugLevel).Info("status.nextPrivateKeySecretName not yet set, waiting for keymanager before processing certificate")
+		return nil, "", nil
+	}
+	nextPrivateKeySecret, err := c.secretLister.Secrets(crt.Namespace).Get(*crt.Status.NextPrivateKeySecretName)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("nextPrivateKeySecretName Secret resource does not exist, waiting for keymanager to create it before continuing")
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if nextPrivateKeySecret.Data == nil || len(nextPrivateKeySecret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		log.V(logf.DebugLevel).Info("Next private key secret does not contain any valid data, waiting for keymanager before processing certificate")
+		return nil, "", nil
+	}
+	pk, err := pki.DecodePrivateKeyBytes(nextPrivateKeySecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		log.Error(err, "Failed to decode next private key secret data, waiting for keymanager before processing certificate")
+		return nil, "", nil
+	}
+
+	return pk, nextPrivateKeySecret.Name, nil
 }
 
 func (c *controller) deleteCurrentFailedRequests(ctx context.Context, reqs ...*cmapi.CertificateRequest) ([]*cmapi.CertificateRequest, error) {
@@ -302,7 +357,7 @@ func (c *controller) deleteRequestsNotMatchingSpec(ctx context.Context, crt *cma
 	var remaining []*cmapi.CertificateRequest
 	for _, req := range reqs {
 		log := logf.WithRelatedResource(log, req)
-		violations, err := certificates.RequestMatchesSpec(req, crt.Spec)
+		violations, err := pki.RequestMatchesSpec(req, crt.Spec)
 		if err != nil {
 			log.Error(err, "Failed to check if CertificateRequest matches spec, deleting CertificateRequest")
 			if err := c.client.CertmanagerV1().CertificateRequests(req.Namespace).Delete(ctx, req.Name, metav1.DeleteOptions{}); err != nil {
@@ -327,8 +382,13 @@ func (c *controller) deleteRequestsNotMatchingSpec(ctx context.Context, crt *cma
 			return nil, err
 		}
 		if !matches {
-			log.V(logf.DebugLevel).Info("CertificateRequest contains a CSR that does not have the same public key as the stored next private key secret, deleting CertificateRequest")
-			if err := c.client.CertmanagerV1().CertificateRequests(req.Namespace).Delete(ctx, req.Name, metav1.DeleteOptions{}); err != nil {
+			// This happens if the next private key Secret has been replaced
+			// after the request was created, for example when it has been
+			// restored from a backup. The request can never be used, so
+			// replace it with one signed by the stored key.
+			log.V(logf.InfoLevel).Info("CertificateRequest contains a CSR that does not have the same public key as the stored next private key secret, deleting CertificateRequest")
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonKeyMismatch, "CertificateRequest %q was not signed by the stored next private key, it will be deleted and re-created", req.Name)
+			if err := c.client.CertmanagerV1().CertificateRequests(req.Namespace).Delete(ctx, req.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
 				return nil, err
 			}
 			continue
@@ -338,13 +398,22 @@ func (c *controller) deleteRequestsNotMatchingSpec(ctx context.Context, crt *cma
 	return remaining, nil
 }
 
-func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi.Certificate, pk crypto.Signer, nextRevision int, nextPrivateKeySecretName string) error {
+func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi.Certificate, pk crypto.Signer, nextRevision int, nextPrivateKeySecretName string) (err error) {
+	ctx, span := tracing.StartIssuance(ctx, "CreateCertificateRequest", crt)
+	defer func() { tracing.End(span, err) }()
+
 	log := logf.FromContext(ctx)
 	x509CSR, err := pki.GenerateCSR(crt)
 	if err != nil {
 		log.Error(err, "Failed to generate CSR - will not retry")
 		return nil
 	}
+	if externalkey.Ref(crt.Spec) != nil {
+		// The algorithm of an external key is not known from the spec, so
+		// let the signature algorithm be chosen based on its public key.
+		x509CSR.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+		x509CSR.PublicKeyAlgorithm = x509.UnknownPublicKeyAlgorithm
+	}
 	csrDER, err := pki.EncodeCSR(x509CSR, pk)
 	if err != nil {
 		return err
@@ -358,8 +427,23 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 
 	annotations := controllerpkg.BuildAnnotationsToCopy(crt.Annotations, c.copiedAnnotationPrefixes)
 	annotations[cmapi.CertificateRequestRevisionAnnotationKey] = strconv.Itoa(nextRevision)
-	annotations[cmapi.CertificateRequestPrivateKeyAnnotationKey] = nextPrivateKeySecretName
+	if ref := externalkey.Ref(crt.Spec); ref != nil {
+		annotations[cmapi.PrivateKeyExternalRefAnnotationKey] = externalkey.KeyURL(ref)
+	} else {
+		annotations[cmapi.CertificateRequestPrivateKeyAnnotationKey] = nextPrivateKeySecretName
+	}
 	annotations[cmapi.CertificateNameKey] = crt.Name
+	if uid := c.issuerUID(crt); uid != "" {
+		annotations[cmapi.CertificateRequestIssuerUIDAnnotationKey] = uid
+	}
+	if request := manualRenewalRequest(crt); request != nil {
+		if request.Reason != "" {
+			annotations[cmapi.CertificateRequestRenewalReasonAnnotationKey] = request.Reason
+		}
+		if request.Username != "" {
+			annotations[cmapi.CertificateRequestRenewalRequestedByAnnotationKey] = request.Username
+		}
+	}
 
 	cr := &cmapi.CertificateRequest{
 		ObjectMeta: metav1.ObjectMeta{
@@ -377,6 +461,7 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 			Usages:    crt.Spec.Usages,
 		},
 	}
+	tracing.Inject(ctx, cr)
 
 	cr, err = c.client.CertmanagerV1().CertificateRequests(cr.Namespace).Create(ctx, cr, metav1.CreateOptions{})
 	if err != nil {
@@ -390,6 +475,31 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 	return nil
 }
 
+// manualRenewalRequest returns the renewal request of the Certificate if the
+// issuance in progress was triggered manually.
+func manualRenewalRequest(crt *cmapi.Certificate) *cmapi.CertificateRenewalRequest {
+	cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	if cond == nil || cond.Status != cmmeta.ConditionTrue || cond.Reason != cmapi.CertificateReasonManuallyTriggered {
+		return nil
+	}
+	return crt.Status.RenewalRequest
+}
+
+// issuerUID returns the UID of the cert-manager Issuer or ClusterIssuer
+// referenced by the Certificate, or an empty string if it is an external
+// issuer or cannot be read.
+func (c *controller) issuerUID(crt *cmapi.Certificate) string {
+	ref := crt.Spec.IssuerRef
+	if c.issuerHelper == nil || (ref.Group != "" && ref.Group != cmapi.SchemeGroupVersion.Group) {
+		return ""
+	}
+	genericIssuer, err := c.issuerHelper.GetGenericIssuer(ref, crt.Namespace)
+	if err != nil {
+		return ""
+	}
+	return string(genericIssuer.GetUID())
+}
+
 func (c *controller) waitForCertificateRequestToExist(namespace, name string) error {
 	return wait.Poll(time.Millisecond*100, time.Second*5, func() (bool, error) {
 		_, err := c.certificateRequestLister.CertificateRequests(namespace).Get(name)
@@ -420,9 +530,25 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.Recorder,
 		ctx.Clock,
 		ctx.CertificateOptions,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	// Read issuers so that their UID can be recorded on CertificateRequests.
+	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
+	mustSync = append(mustSync, issuerInformer.Informer().HasSynced)
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if ctx.Namespace == "" {
+		clusterIssuerInformer := ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+	issuerGrantInformer := ctx.SharedInformerFactory.Policy().V1alpha1().IssuerGrants()
+	mustSync = append(mustSync, issuerGrantInformer.Informer().HasSynced)
+	ctrl.issuerHelper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister, issuerGrantInformer.Lister())
+
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
 	return queue, mustSync, nil
 }
 
@@ -18,6 +18,8 @@ package requestmanager
 
 import (
 	"context"
+	"crypto"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -33,6 +35,7 @@ import (
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/externalkey"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
@@ -78,6 +81,22 @@ func TestProcessItem(t *testing.T) {
 		},
 		Spec: cmapi.CertificateSpec{CommonName: "test-bundle-2"}},
 	)
+	issuerBundle := mustCreateCryptoBundle(t, &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testns",
+			Name:      "test",
+			UID:       "test",
+		},
+		Spec: cmapi.CertificateSpec{
+			CommonName: "test-issuer-bundle",
+			IssuerRef:  cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer"},
+		}},
+	)
+	caIssuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerUID("issuer-uid"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"}),
+	)
 	fixedNow := metav1.NewTime(time.Now())
 	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
 	failedCRCondition := cmapi.CertificateRequestCondition{
@@ -86,6 +105,16 @@ func TestProcessItem(t *testing.T) {
 		Reason:             cmapi.CertificateRequestReasonFailed,
 		LastTransitionTime: &metav1.Time{Time: fixedNow.Time.Add(-1 * time.Hour)},
 	}
+	externalKeyCertificate := gen.CertificateFrom(bundle1.certificate,
+		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+	)
+	externalKeyCertificate.Spec.PrivateKey = &cmapi.CertificatePrivateKey{
+		ExternalRef: &cmapi.PrivateKeyExternalRef{SignerURL: "https://signer.example.com", KeyID: "key"},
+	}
+	externalKey, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
 	tests := map[string]struct {
 		// key that should be passed to ProcessItem.
 		// if not set, the 'namespace/name' of the 'Certificate' field will be used.
@@ -101,10 +130,19 @@ func TestProcessItem(t *testing.T) {
 		// Request, if set, will exist in the apiserver before the test is run.
 		requests []runtime.Object
 
+		// issuers, if set, will exist in the apiserver before the test is run.
+		issuers []runtime.Object
+
 		expectedActions []testpkg.Action
 
 		expectedEvents []string
 
+		// externalSigner, if set, is used to sign requests for Certificates
+		// with an external private key.
+		externalSigner externalkey.SignerFunc
+		// externalSignerURLs are the signer URLs allowed by the controller.
+		externalSignerURLs []string
+
 		// err is the expected error text returned by the controller, if any.
 		err string
 	}{
@@ -179,6 +217,78 @@ func TestProcessItem(t *testing.T) {
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
+		"create a CertificateRequest recording the UID of the referenced issuer": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: issuerBundle.certificate.Namespace, Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: issuerBundle.privateKeyBytes},
+				},
+			},
+			issuers: []runtime.Object{caIssuer},
+			certificate: gen.CertificateFrom(issuerBundle.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+			),
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(issuerBundle.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
+							cmapi.CertificateRequestIssuerUIDAnnotationKey:  "issuer-uid",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"create a CertificateRequest recording the reason for and requester of a manual renewal": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: bundle1.certificate.Namespace, Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateRenewalRequest(cmapi.CertificateRenewalRequest{Reason: "key compromise", Username: "alice"}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, Reason: cmapi.CertificateReasonManuallyTriggered}),
+			),
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey:         "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:           "1",
+							cmapi.CertificateRequestRenewalReasonAnnotationKey:      "key compromise",
+							cmapi.CertificateRequestRenewalRequestedByAnnotationKey: "alice",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"do not record a previous manual renewal on CertificateRequests for other issuances": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: bundle1.certificate.Namespace, Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateRenewalRequest(cmapi.CertificateRenewalRequest{Reason: "key compromise", Username: "alice"}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, Reason: "Renewing"}),
+			),
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
 		"delete the owned CertificateRequest and create a new one if existing one does not have the annotation": {
 			secrets: []runtime.Object{
 				&corev1.Secret{
@@ -343,6 +453,7 @@ func TestProcessItem(t *testing.T) {
 			),
 			requests: []runtime.Object{
 				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestName("test"),
 					gen.SetCertificateRequestAnnotations(map[string]string{
 						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 						cmapi.CertificateRequestRevisionAnnotationKey:   "1",
@@ -358,7 +469,10 @@ func TestProcessItem(t *testing.T) {
 					}),
 				),
 			},
-			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedEvents: []string{
+				`Warning PrivateKeyMismatch CertificateRequest "test" was not signed by the stored next private key, it will be deleted and re-created`,
+				`Normal Requested Created new CertificateRequest resource "test-notrandom"`,
+			},
 			expectedActions: []testpkg.Action{
 				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns", "test")),
 				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
@@ -384,6 +498,7 @@ func TestProcessItem(t *testing.T) {
 			),
 			requests: []runtime.Object{
 				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestName("test"),
 					gen.SetCertificateRequestAnnotations(map[string]string{
 						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 						cmapi.CertificateRequestRevisionAnnotationKey:   "6",
@@ -399,7 +514,10 @@ func TestProcessItem(t *testing.T) {
 					}),
 				),
 			},
-			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedEvents: []string{
+				`Warning PrivateKeyMismatch CertificateRequest "test" was not signed by the stored next private key, it will be deleted and re-created`,
+				`Normal Requested Created new CertificateRequest resource "test-notrandom"`,
+			},
 			expectedActions: []testpkg.Action{
 				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns", "test")),
 				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
@@ -425,13 +543,17 @@ func TestProcessItem(t *testing.T) {
 			),
 			requests: []runtime.Object{
 				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestName("test"),
 					gen.SetCertificateRequestAnnotations(map[string]string{
 						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 						cmapi.CertificateRequestRevisionAnnotationKey:   "6",
 					}),
 				),
 			},
-			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedEvents: []string{
+				`Warning PrivateKeyMismatch CertificateRequest "test" was not signed by the stored next private key, it will be deleted and re-created`,
+				`Normal Requested Created new CertificateRequest resource "test-notrandom"`,
+			},
 			expectedActions: []testpkg.Action{
 				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns", "test")),
 				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
@@ -558,6 +680,41 @@ func TestProcessItem(t *testing.T) {
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
+		"create a CertificateRequest signed by the external private key": {
+			certificate:        externalKeyCertificate,
+			externalSignerURLs: []string{"https://signer.example.com"},
+			externalSigner: func(context.Context, *cmapi.Certificate) (crypto.Signer, error) {
+				return externalKey, nil
+			},
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.PrivateKeyExternalRefAnnotationKey:      "https://signer.example.com/keys/key",
+							cmapi.CertificateRequestRevisionAnnotationKey: "1",
+						}),
+						gen.DeleteCertificateRequestAnnotation(cmapi.CertificateRequestPrivateKeyAnnotationKey),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"do nothing if the external signer is not allowed by the controller": {
+			certificate:        externalKeyCertificate,
+			externalSignerURLs: []string{"https://other-signer.example.com"},
+			externalSigner: func(context.Context, *cmapi.Certificate) (crypto.Signer, error) {
+				return externalKey, nil
+			},
+			expectedEvents: []string{`Warning ExternalKeyFailed External signer "https://signer.example.com" is not allowed by the --external-key-signer-urls flag of the controller`},
+		},
+		"return an error if the external private key cannot be used": {
+			certificate:        externalKeyCertificate,
+			externalSignerURLs: []string{"https://signer.example.com"},
+			externalSigner: func(context.Context, *cmapi.Certificate) (crypto.Signer, error) {
+				return nil, errors.New("signer unavailable")
+			},
+			expectedEvents: []string{"Warning ExternalKeyFailed Failed to use external private key: signer unavailable"},
+			err:            "signer unavailable",
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -578,6 +735,7 @@ func TestProcessItem(t *testing.T) {
 			for _, req := range test.requests {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, req)
 			}
+			builder.CertManagerObjects = append(builder.CertManagerObjects, test.issuers...)
 			builder.Init()
 
 			// Register informers used by the controller using the registration wrapper
@@ -586,6 +744,10 @@ func TestProcessItem(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
+			if test.externalSigner != nil {
+				w.controller.externalSigner = test.externalSigner
+			}
+			w.controller.externalSignerURLs = test.externalSignerURLs
 			// Start the informers and begin processing updates
 			builder.Start()
 			defer builder.Stop()
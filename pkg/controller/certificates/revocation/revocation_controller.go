@@ -0,0 +1,425 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	// ControllerName is the name of the certificate revocation controller.
+	ControllerName = "certificates-revocation"
+
+	// RevokedReason is the 'Revoked' condition reason used once the
+	// certificate has been revoked.
+	RevokedReason = "Revoked"
+	// RevocationFailedReason is the 'Revoked' condition reason used when the
+	// issuer failed to revoke the certificate.
+	RevocationFailedReason = "RevocationFailed"
+	// NotSupportedReason is the 'Revoked' condition reason used when the
+	// issuer does not support revocation.
+	NotSupportedReason = "NotSupported"
+	// MissingCertificateReason is the 'Revoked' condition reason used when
+	// there is no certificate stored in the Certificate's Secret.
+	MissingCertificateReason = "MissingCertificate"
+	// NotIssuedReason is the 'Revoked' condition reason used when the
+	// certificate stored in the Certificate's Secret was not issued by any of
+	// the Certificate's CertificateRequests, and so is not revoked.
+	NotIssuedReason = "NotIssued"
+)
+
+// errNotIssued is reported when the certificate stored in a Certificate's
+// Secret was not issued by any of the Certificate's CertificateRequests.
+var errNotIssued = errors.New("the certificate was not issued for this Certificate")
+
+// controller revokes the certificates of Certificate resources with
+// `spec.revoke` set, and manages the finalizer used to revoke the
+// certificates of Certificate resources with `spec.revokeOnDelete` set when
+// they are deleted.
+type controller struct {
+	certificateLister        cmlisters.CertificateLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	secretLister             corelisters.SecretLister
+	client                   cmclient.Interface
+	statusPatcher            *statuspatch.Patcher
+	fieldManager             string
+	recorder                 record.EventRecorder
+
+	// helper is used to read the Issuer or ClusterIssuer of a Certificate
+	helper issuer.Helper
+	// issuerFactory is used to obtain the implementation of an issuer
+	issuerFactory issuer.Factory
+}
+
+// NewController returns a new certificate revocation controller.
+func NewController(
+	log logr.Logger,
+	client cmclient.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	issuerFactory issuer.Factory,
+	statusPatcher *statuspatch.Patcher,
+	isNamespaced bool,
+	workqueueOptions controllerpkg.WorkqueueOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 5,
+		MaxDelay:  time.Minute * 5,
+	}), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
+	issuerGrantInformer := cmFactory.Policy().V1alpha1().IssuerGrants()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Secret resource changes, enqueue any Certificate resources that name it as spec.secretName.
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateSecretName)),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		certificateRequestInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+		issuerGrantInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	// If we are running in non-namespaced mode, we also obtain a lister for
+	// ClusterIssuers.
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if !isNamespaced {
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+
+	return &controller{
+		certificateLister:        certificateInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		secretLister:             secretsInformer.Lister(),
+		client:                   client,
+		statusPatcher:            statusPatcher,
+		fieldManager:             controllerpkg.FieldManager(ControllerName),
+		recorder:                 recorder,
+		helper:                   issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister, issuerGrantInformer.Lister()),
+		issuerFactory:            issuerFactory,
+	}, queue, mustSync
+}
+
+// ProcessItem is a worker function that will be called when a new key
+// corresponding to a Certificate to be re-synced is pulled from the workqueue.
+// ProcessItem revokes the Certificate's certificate if requested, and keeps
+// the revoke-on-delete finalizer of the Certificate up to date.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if crt.DeletionTimestamp != nil {
+		return c.finalize(ctx, crt)
+	}
+
+	if crt.Spec.RevokeOnDelete != hasFinalizer(crt) {
+		return c.updateFinalizer(ctx, crt, crt.Spec.RevokeOnDelete)
+	}
+
+	if !crt.Spec.Revoke {
+		if apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionRevoked) == nil {
+			return nil
+		}
+		newCrt := crt.DeepCopy()
+		apiutil.RemoveCertificateCondition(newCrt, cmapi.CertificateConditionRevoked)
+		return certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, crt, newCrt)
+	}
+
+	return c.revokeRequested(ctx, crt)
+}
+
+// revokeRequested revokes the certificate stored in the Secret of a
+// Certificate with spec.revoke set, unless it has already been revoked, and
+// reports the outcome with the Revoked condition. Only certificates that were
+// issued by one of the Certificate's CertificateRequests are revoked, so that
+// a certificate copied into the Secret is never revoked on its behalf.
+func (c *controller) revokeRequested(ctx context.Context, crt *cmapi.Certificate) error {
+	newCrt := crt.DeepCopy()
+
+	cert, issued, err := c.currentCertificate(crt)
+	if err != nil {
+		return err
+	}
+
+	var revokeErr error
+	switch {
+	case cert == nil:
+		apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionRevoked, cmmeta.ConditionFalse, MissingCertificateReason,
+			fmt.Sprintf("No certificate to revoke was found in Secret %q", crt.Spec.SecretName))
+
+	case !issued:
+		apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionRevoked, cmmeta.ConditionFalse, NotIssuedReason,
+			fmt.Sprintf("Certificate with serial number %s in Secret %q was not issued for this Certificate and will not be revoked", certificates.SerialNumber(cert), crt.Spec.SecretName))
+
+	case certificates.SerialNumber(cert) == crt.Status.RevokedSerialNumber:
+		apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionRevoked, cmmeta.ConditionTrue, RevokedReason,
+			fmt.Sprintf("Certificate with serial number %s has been revoked", crt.Status.RevokedSerialNumber))
+
+	default:
+		serialNumber := certificates.SerialNumber(cert)
+		revokeErr = c.revoke(ctx, crt, cert)
+		switch {
+		case revokeErr == nil:
+			message := fmt.Sprintf("Certificate with serial number %s has been revoked", serialNumber)
+			c.recorder.Event(crt, corev1.EventTypeNormal, RevokedReason, message)
+			newCrt.Status.RevokedSerialNumber = serialNumber
+			apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionRevoked, cmmeta.ConditionTrue, RevokedReason, message)
+
+		case errors.Is(revokeErr, issuer.ErrRevocationNotSupported):
+			message := fmt.Sprintf("Certificate with serial number %s cannot be revoked: %v", serialNumber, revokeErr)
+			c.recorder.Event(crt, corev1.EventTypeWarning, NotSupportedReason, message)
+			apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionRevoked, cmmeta.ConditionFalse, NotSupportedReason, message)
+			// Retrying will not help until the issuer is reconfigured.
+			revokeErr = nil
+
+		default:
+			message := fmt.Sprintf("Failed to revoke certificate with serial number %s: %v", serialNumber, revokeErr)
+			c.recorder.Event(crt, corev1.EventTypeWarning, RevocationFailedReason, message)
+			apiutil.SetCertificateCondition(newCrt, newCrt.Generation, cmapi.CertificateConditionRevoked, cmmeta.ConditionFalse, RevocationFailedReason, message)
+		}
+	}
+
+	if err := certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, crt, newCrt); err != nil {
+		return err
+	}
+
+	return revokeErr
+}
+
+// finalize revokes the certificate of a Certificate that is being deleted if
+// spec.revokeOnDelete is set, and then removes the revoke-on-delete finalizer
+// so that deletion can proceed. Revocation is attempted once; if it fails an
+// event is recorded and deletion proceeds regardless, so that an unavailable
+// issuer cannot block the deletion of the Certificate and its namespace.
+func (c *controller) finalize(ctx context.Context, crt *cmapi.Certificate) error {
+	if !hasFinalizer(crt) {
+		return nil
+	}
+
+	if crt.Spec.RevokeOnDelete {
+		cert, issued, err := c.currentCertificate(crt)
+		if err != nil {
+			return err
+		}
+
+		if cert != nil && certificates.SerialNumber(cert) != crt.Status.RevokedSerialNumber {
+			serialNumber := certificates.SerialNumber(cert)
+			if !issued {
+				err = errNotIssued
+			} else {
+				err = c.revoke(ctx, crt, cert)
+			}
+			switch {
+			case err == nil:
+				c.recorder.Eventf(crt, corev1.EventTypeNormal, RevokedReason, "Certificate with serial number %s has been revoked on deletion", serialNumber)
+
+			case errors.Is(err, errNotIssued):
+				c.recorder.Eventf(crt, corev1.EventTypeWarning, NotIssuedReason, "Certificate with serial number %s was not revoked on deletion: %v", serialNumber, err)
+
+			case errors.Is(err, issuer.ErrRevocationNotSupported) || apierrors.IsNotFound(err):
+				c.recorder.Eventf(crt, corev1.EventTypeWarning, NotSupportedReason, "Certificate with serial number %s cannot be revoked on deletion: %v", serialNumber, err)
+
+			default:
+				c.recorder.Eventf(crt, corev1.EventTypeWarning, RevocationFailedReason, "Failed to revoke certificate with serial number %s on deletion, deletion will proceed: %v", serialNumber, err)
+			}
+		}
+	}
+
+	return c.updateFinalizer(ctx, crt, false)
+}
+
+// revoke revokes the given certificate of the Certificate using its issuer.
+func (c *controller) revoke(ctx context.Context, crt *cmapi.Certificate, cert *x509.Certificate) error {
+	genericIssuer, err := c.helper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if err != nil {
+		return err
+	}
+
+	issuerObj, err := c.issuerFactory.IssuerFor(genericIssuer)
+	if err != nil {
+		return err
+	}
+
+	revoker, ok := issuerObj.(issuer.Revoker)
+	if !ok {
+		return issuer.ErrRevocationNotSupported
+	}
+
+	return revoker.Revoke(ctx, crt, cert)
+}
+
+// currentCertificate returns the certificate stored in the Certificate's
+// Secret, or nil if the Secret does not exist or does not contain a valid
+// certificate. It additionally returns whether the certificate was issued by
+// one of the CertificateRequests owned by the Certificate.
+func (c *controller) currentCertificate(crt *cmapi.Certificate) (*x509.Certificate, bool, error) {
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, false, nil
+	}
+
+	reqs, err := certificates.ListCertificateRequestsMatchingPredicates(c.certificateRequestLister.CertificateRequests(crt.Namespace),
+		labels.Everything(), predicate.ResourceOwnedBy(crt))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, req := range reqs {
+		if len(req.Status.Certificate) == 0 {
+			continue
+		}
+		issued, err := pki.DecodeX509CertificateBytes(req.Status.Certificate)
+		if err != nil {
+			continue
+		}
+		if issued.Equal(cert) {
+			return cert, true, nil
+		}
+	}
+
+	return cert, false, nil
+}
+
+// updateFinalizer adds or removes the revoke-on-delete finalizer of the
+// Certificate.
+func (c *controller) updateFinalizer(ctx context.Context, crt *cmapi.Certificate, add bool) error {
+	crt = crt.DeepCopy()
+	if add {
+		crt.Finalizers = append(crt.Finalizers, cmapi.RevokeOnDeleteFinalizer)
+	} else {
+		var finalizers []string
+		for _, f := range crt.Finalizers {
+			if f != cmapi.RevokeOnDeleteFinalizer {
+				finalizers = append(finalizers, f)
+			}
+		}
+		crt.Finalizers = finalizers
+	}
+
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+func hasFinalizer(crt *cmapi.Certificate) bool {
+	for _, f := range crt.Finalizers {
+		if f == cmapi.RevokeOnDeleteFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.CMClient,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		issuer.NewFactory(ctx),
+		ctx.StatusPatcher,
+		ctx.Namespace != "",
+		ctx.WorkqueueOptions,
+	)
+	c.controller = ctrl
+
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
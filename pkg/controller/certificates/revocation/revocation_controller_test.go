@@ -0,0 +1,290 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	issuerfake "github.com/jetstack/cert-manager/pkg/issuer/fake"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().UTC()
+	metaNow := metav1.NewTime(now)
+
+	baseCrt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-tls"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer"}),
+		gen.SetCertificateUID("test-uid"),
+	)
+	certPEM := internaltest.MustCreateCertWithNotBeforeAfter(t, internaltest.MustCreatePEMPrivateKey(t),
+		gen.CertificateFrom(baseCrt, gen.SetCertificateCommonName("example.com")), now, now.Add(time.Hour))
+	cert, err := pki.DecodeX509CertificateBytes(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serialNumber := fmt.Sprintf("%X", cert.SerialNumber)
+
+	secret := gen.Secret("test-tls",
+		gen.SetSecretNamespace("testns"),
+		gen.SetSecretData(map[string][]byte{corev1.TLSCertKey: certPEM}),
+	)
+	testIssuer := gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"))
+	ownedRequest := gen.CertificateRequest("test-1",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestCertificate(certPEM),
+		gen.AddCertificateRequestOwnerReferences(*metav1.NewControllerRef(baseCrt, cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateKind))),
+	)
+	unownedRequest := gen.CertificateRequestFrom(ownedRequest, func(cr *cmapi.CertificateRequest) {
+		cr.OwnerReferences = nil
+	})
+
+	withFinalizer := func(crt *cmapi.Certificate) {
+		crt.Finalizers = []string{cmapi.RevokeOnDeleteFinalizer}
+	}
+	deleting := func(crt *cmapi.Certificate) {
+		crt.DeletionTimestamp = &metaNow
+	}
+	revokedSerialNumber := func(serialNumber string) gen.CertificateModifier {
+		return func(crt *cmapi.Certificate) {
+			crt.Status.RevokedSerialNumber = serialNumber
+		}
+	}
+	revokedCondition := func(status cmmeta.ConditionStatus, reason, message string) gen.CertificateModifier {
+		return gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+			Type:               cmapi.CertificateConditionRevoked,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: &metaNow,
+		})
+	}
+	noRevocation := func(t *testing.T) func(context.Context, *cmapi.Certificate, *x509.Certificate) error {
+		return func(context.Context, *cmapi.Certificate, *x509.Certificate) error {
+			t.Error("unexpected call to Revoke")
+			return nil
+		}
+	}
+	revokeWith := func(err error) func(t *testing.T) func(context.Context, *cmapi.Certificate, *x509.Certificate) error {
+		return func(t *testing.T) func(context.Context, *cmapi.Certificate, *x509.Certificate) error {
+			return func(_ context.Context, _ *cmapi.Certificate, got *x509.Certificate) error {
+				if got.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+					t.Errorf("unexpected certificate revoked, exp serial=%s got=%s", cert.SerialNumber, got.SerialNumber)
+				}
+				return err
+			}
+		}
+	}
+
+	tests := map[string]struct {
+		// crt is the Certificate to be synced, if it exists.
+		crt *cmapi.Certificate
+		// request is the CertificateRequest that issued the certificate in
+		// the Secret. Defaults to a CertificateRequest owned by crt.
+		request *cmapi.CertificateRequest
+		// revoke builds the Revoke function of the fake issuer. If nil, the
+		// issuer does not support revocation.
+		revoke func(t *testing.T) func(context.Context, *cmapi.Certificate, *x509.Certificate) error
+
+		// expUpdate is the expected Certificate update, if any.
+		expUpdate *cmapi.Certificate
+		// expStatus is the Certificate with the expected status, if the
+		// status is expected to be patched.
+		expStatus *cmapi.Certificate
+		expEvents []string
+		expErr    bool
+	}{
+		"do nothing if the Certificate does not exist": {},
+		"do nothing if revocation has not been requested": {
+			crt:    baseCrt,
+			revoke: noRevocation,
+		},
+		"add the finalizer if revokeOnDelete is set": {
+			crt:       gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true)),
+			revoke:    noRevocation,
+			expUpdate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), withFinalizer),
+		},
+		"remove the finalizer if revokeOnDelete is unset": {
+			crt:       gen.CertificateFrom(baseCrt, withFinalizer),
+			revoke:    noRevocation,
+			expUpdate: baseCrt,
+		},
+		"revoke the certificate if revoke is set": {
+			crt:    gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true)),
+			revoke: revokeWith(nil),
+			expStatus: gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true),
+				revokedSerialNumber(serialNumber),
+				revokedCondition(cmmeta.ConditionTrue, RevokedReason, fmt.Sprintf("Certificate with serial number %s has been revoked", serialNumber)),
+			),
+			expEvents: []string{fmt.Sprintf("Normal Revoked Certificate with serial number %s has been revoked", serialNumber)},
+		},
+		"do not revoke a certificate that has already been revoked": {
+			crt:    gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true), revokedSerialNumber(serialNumber)),
+			revoke: noRevocation,
+			expStatus: gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true),
+				revokedSerialNumber(serialNumber),
+				revokedCondition(cmmeta.ConditionTrue, RevokedReason, fmt.Sprintf("Certificate with serial number %s has been revoked", serialNumber)),
+			),
+		},
+		"set the Revoked condition to False if the issuer does not support revocation": {
+			crt: gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true)),
+			expStatus: gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true),
+				revokedCondition(cmmeta.ConditionFalse, NotSupportedReason, fmt.Sprintf("Certificate with serial number %s cannot be revoked: revocation is not supported by the issuer", serialNumber)),
+			),
+			expEvents: []string{fmt.Sprintf("Warning NotSupported Certificate with serial number %s cannot be revoked: revocation is not supported by the issuer", serialNumber)},
+		},
+		"set the Revoked condition to False and retry if revocation fails": {
+			crt:    gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true)),
+			revoke: revokeWith(errors.New("boom")),
+			expStatus: gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true),
+				revokedCondition(cmmeta.ConditionFalse, RevocationFailedReason, fmt.Sprintf("Failed to revoke certificate with serial number %s: boom", serialNumber)),
+			),
+			expEvents: []string{fmt.Sprintf("Warning RevocationFailed Failed to revoke certificate with serial number %s: boom", serialNumber)},
+			expErr:    true,
+		},
+		"do not revoke a certificate that was not issued for the Certificate": {
+			crt:     gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true)),
+			request: unownedRequest,
+			revoke:  noRevocation,
+			expStatus: gen.CertificateFrom(baseCrt, gen.SetCertificateRevoke(true),
+				revokedCondition(cmmeta.ConditionFalse, NotIssuedReason, fmt.Sprintf("Certificate with serial number %s in Secret \"test-tls\" was not issued for this Certificate and will not be revoked", serialNumber)),
+			),
+		},
+		"remove the Revoked condition once revoke is unset": {
+			crt: gen.CertificateFrom(baseCrt, revokedSerialNumber(serialNumber),
+				revokedCondition(cmmeta.ConditionTrue, RevokedReason, "revoked"),
+			),
+			revoke:    noRevocation,
+			expStatus: gen.CertificateFrom(baseCrt, revokedSerialNumber(serialNumber)),
+		},
+		"revoke the certificate and remove the finalizer on deletion": {
+			crt:       gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), withFinalizer, deleting),
+			revoke:    revokeWith(nil),
+			expUpdate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), deleting),
+			expEvents: []string{fmt.Sprintf("Normal Revoked Certificate with serial number %s has been revoked on deletion", serialNumber)},
+		},
+		"do not revoke an already revoked certificate on deletion": {
+			crt:       gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), withFinalizer, deleting, revokedSerialNumber(serialNumber)),
+			revoke:    noRevocation,
+			expUpdate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), deleting, revokedSerialNumber(serialNumber)),
+		},
+		"remove the finalizer on deletion if the issuer does not support revocation": {
+			crt:       gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), withFinalizer, deleting),
+			expUpdate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), deleting),
+			expEvents: []string{fmt.Sprintf("Warning NotSupported Certificate with serial number %s cannot be revoked on deletion: revocation is not supported by the issuer", serialNumber)},
+		},
+		"remove the finalizer on deletion if revocation fails": {
+			crt:       gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), withFinalizer, deleting),
+			revoke:    revokeWith(errors.New("boom")),
+			expUpdate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), deleting),
+			expEvents: []string{fmt.Sprintf("Warning RevocationFailed Failed to revoke certificate with serial number %s on deletion, deletion will proceed: boom", serialNumber)},
+		},
+		"remove the finalizer on deletion without revoking a certificate that was not issued for the Certificate": {
+			crt:       gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), withFinalizer, deleting),
+			request:   unownedRequest,
+			revoke:    noRevocation,
+			expUpdate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevokeOnDelete(true), deleting),
+			expEvents: []string{fmt.Sprintf("Warning NotIssued Certificate with serial number %s was not revoked on deletion: the certificate was not issued for this Certificate", serialNumber)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := ownedRequest
+			if test.request != nil {
+				request = test.request
+			}
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				KubeObjects:        []runtime.Object{secret},
+				CertManagerObjects: []runtime.Object{testIssuer, request},
+				ExpectedEvents:     test.expEvents,
+			}
+			if test.crt != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.crt)
+			}
+			if test.expUpdate != nil {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						test.expUpdate.Namespace,
+						test.expUpdate,
+					)))
+			}
+			if test.expStatus != nil {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewStatusPatchAction(cmapi.SchemeGroupVersion.WithResource("certificates"), test.expStatus))
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			fakeIssuer := &issuerfake.Issuer{}
+			if test.revoke != nil {
+				fakeIssuer.RevokeFunc = test.revoke(t)
+			}
+			w.controller.issuerFactory = &issuerfake.Factory{
+				IssuerForFunc: func(cmapi.GenericIssuer) (issuer.Interface, error) {
+					return fakeIssuer, nil
+				},
+			}
+
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(baseCrt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.controller.ProcessItem(context.Background(), key)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error: %v, got: %v", test.expErr, err)
+			}
+
+			if err := builder.AllActionsExecuted(); err != nil {
+				t.Error(err)
+			}
+			if err := builder.AllEventsCalled(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretlabeler
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	secret := func(name string, labelled bool) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}}
+		if labelled {
+			s.Labels = map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"}
+		}
+		return s
+	}
+	update := func(name string) testpkg.Action {
+		return testpkg.NewAction(coretesting.NewUpdateAction(corev1.SchemeGroupVersion.WithResource("secrets"), "ns", secret(name, true)))
+	}
+	crt := gen.Certificate("crt",
+		gen.SetCertificateNamespace("ns"),
+		gen.SetCertificateSecretName("tls"),
+	)
+	crtWithNextKey := gen.CertificateFrom(crt,
+		gen.SetCertificateNextPrivateKeySecretName("next"),
+	)
+
+	tests := map[string]struct {
+		certificate     *cmapi.Certificate
+		secrets         []runtime.Object
+		expectedActions []testpkg.Action
+	}{
+		"labels the target secret": {
+			certificate:     crt,
+			secrets:         []runtime.Object{secret("tls", false)},
+			expectedActions: []testpkg.Action{update("tls")},
+		},
+		"labels the target and next private key secrets": {
+			certificate:     crtWithNextKey,
+			secrets:         []runtime.Object{secret("tls", false), secret("next", false)},
+			expectedActions: []testpkg.Action{update("tls"), update("next")},
+		},
+		"does nothing if the secrets are already labelled": {
+			certificate: crtWithNextKey,
+			secrets:     []runtime.Object{secret("tls", true), secret("next", true)},
+		},
+		"does nothing if the secrets do not exist": {
+			certificate: crtWithNextKey,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: []runtime.Object{test.certificate},
+				KubeObjects:        test.secrets,
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.controller.ProcessItem(context.Background(), "ns/crt"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			builder.CheckAndFinish()
+		})
+	}
+}
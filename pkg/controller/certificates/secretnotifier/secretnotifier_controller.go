@@ -0,0 +1,359 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretnotifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "certificates-secret-update-notifier"
+
+	reasonSecretUpdated = "CertificateSecretUpdated"
+)
+
+// controller rolls out Deployments and StatefulSets that reference Secrets
+// using the 'cert-manager.io/restart-on-secret-update' annotation whenever the
+// certificates in those Secrets change. It does this by recording a hash of
+// the certificates as an annotation on the Pod template of the workload.
+type controller struct {
+	secretLister      corelisters.SecretLister
+	deploymentLister  appslisters.DeploymentLister
+	statefulSetLister appslisters.StatefulSetLister
+	client            kubernetes.Interface
+	recorder          record.EventRecorder
+
+	// hashAnnotation is the Pod template annotation that the hash of the
+	// certificates is written to.
+	hashAnnotation string
+}
+
+func NewController(
+	log logr.Logger,
+	client kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	hashAnnotation string,
+	workqueueOptions controllerpkg.WorkqueueOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	secretInformer := factory.Core().V1().Secrets()
+	deploymentInformer := factory.Apps().V1().Deployments()
+	statefulSetInformer := factory.Apps().V1().StatefulSets()
+
+	// Secrets are queued by their 'namespace/name' key, and each sync updates
+	// all of the workloads that reference the Secret.
+	secretInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueManagedSecret(log, queue),
+	})
+	deploymentInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueReferencedSecrets(queue),
+	})
+	statefulSetInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueReferencedSecrets(queue),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		secretInformer.Informer().HasSynced,
+		deploymentInformer.Informer().HasSynced,
+		statefulSetInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		secretLister:      secretInformer.Lister(),
+		deploymentLister:  deploymentInformer.Lister(),
+		statefulSetLister: statefulSetInformer.Lister(),
+		client:            client,
+		recorder:          recorder,
+		hashAnnotation:    hashAnnotation,
+	}, queue, mustSync
+}
+
+// enqueueManagedSecret queues Secrets that contain a certificate issued by
+// cert-manager.
+func enqueueManagedSecret(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		if secret, ok := obj.(*corev1.Secret); ok {
+			if _, ok := secret.Annotations[cmapi.CertificateNameKey]; !ok {
+				return
+			}
+		}
+		key, err := controllerpkg.KeyFunc(obj)
+		if err != nil {
+			log.Error(err, "error computing key for secret")
+			return
+		}
+		queue.Add(key)
+	}
+}
+
+// enqueueReferencedSecrets queues the Secrets listed in the
+// 'cert-manager.io/restart-on-secret-update' annotation of a workload, so
+// that workloads that start referencing a Secret are brought up to date.
+func enqueueReferencedSecrets(queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		meta, ok := obj.(metav1.Object)
+		if !ok {
+			// Workload deletions are not interesting to this controller
+			return
+		}
+		for _, name := range referencedSecrets(meta) {
+			queue.Add(types.NamespacedName{Namespace: meta.GetNamespace(), Name: name}.String())
+		}
+	}
+}
+
+// referencedSecrets returns the sorted names of the Secrets listed in the
+// 'cert-manager.io/restart-on-secret-update' annotation of the object.
+func referencedSecrets(obj metav1.Object) []string {
+	value, ok := obj.GetAnnotations()[cmapi.RestartOnSecretUpdateAnnotationKey]
+	if !ok {
+		return nil
+	}
+	names := sets.NewString()
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names.Insert(name)
+		}
+	}
+	return names.List()
+}
+
+// workload is a Deployment or StatefulSet that references Secrets.
+type workload struct {
+	obj interface {
+		metav1.Object
+		runtime.Object
+	}
+	kind        string
+	template    *corev1.PodTemplateSpec
+	secretNames []string
+	// patch applies a merge patch to the workload
+	patch func(ctx context.Context, data []byte) error
+}
+
+// ProcessItem updates the certificate hash annotation of every workload that
+// references the Secret with the given key.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	workloads, err := c.workloadsReferencing(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, w := range workloads {
+		if err := c.syncWorkload(ctx, log, w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to update workloads referencing secret %q: %v", key, errs)
+	}
+
+	return nil
+}
+
+func (c *controller) workloadsReferencing(namespace, secretName string) ([]workload, error) {
+	var workloads []workload
+
+	deployments, err := c.deploymentLister.Deployments(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		d := d
+		w := workload{
+			obj:         d,
+			kind:        "Deployment",
+			template:    &d.Spec.Template,
+			secretNames: referencedSecrets(d),
+			patch: func(ctx context.Context, data []byte) error {
+				_, err := c.client.AppsV1().Deployments(namespace).Patch(ctx, d.Name, types.MergePatchType, data, metav1.PatchOptions{})
+				return err
+			},
+		}
+		if contains(w.secretNames, secretName) {
+			workloads = append(workloads, w)
+		}
+	}
+
+	statefulSets, err := c.statefulSetLister.StatefulSets(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulSets {
+		s := s
+		w := workload{
+			obj:         s,
+			kind:        "StatefulSet",
+			template:    &s.Spec.Template,
+			secretNames: referencedSecrets(s),
+			patch: func(ctx context.Context, data []byte) error {
+				_, err := c.client.AppsV1().StatefulSets(namespace).Patch(ctx, s.Name, types.MergePatchType, data, metav1.PatchOptions{})
+				return err
+			},
+		}
+		if contains(w.secretNames, secretName) {
+			workloads = append(workloads, w)
+		}
+	}
+
+	return workloads, nil
+}
+
+// syncWorkload patches the Pod template of the workload if the hash of the
+// certificates in the Secrets it references has changed. Secrets that do not
+// exist yet are treated as empty.
+func (c *controller) syncWorkload(ctx context.Context, log logr.Logger, w workload) error {
+	obj := w.obj
+	log = log.WithValues("kind", w.kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+
+	var secrets []*corev1.Secret
+	for _, name := range w.secretNames {
+		secret, err := c.secretLister.Secrets(obj.GetNamespace()).Get(name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	hash := certificatesHash(secrets)
+	if w.template.Annotations[c.hashAnnotation] == hash {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{c.hashAnnotation: hash},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("rolling out workload to pick up updated certificates", "secrets", w.secretNames)
+	if err := w.patch(ctx, patch); err != nil {
+		return err
+	}
+
+	c.recorder.Eventf(obj, corev1.EventTypeNormal, reasonSecretUpdated,
+		"Rolling out to pick up the certificates in Secrets %s", strings.Join(w.secretNames, ", "))
+
+	return nil
+}
+
+// certificatesHash returns a hash of the certificate and CA data of the given
+// Secrets, sorted by name.
+func certificatesHash(secrets []*corev1.Secret) string {
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	h := sha256.New()
+	for _, secret := range secrets {
+		for _, b := range [][]byte{[]byte(secret.Name), secret.Data[corev1.TLSCertKey], secret.Data[cmmeta.TLSCAKey]} {
+			h.Write(b)
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log, ctx.Client, ctx.KubeSharedInformerFactory, ctx.Recorder, ctx.SecretHashAnnotation, ctx.WorkqueueOptions)
+	c.controller = ctrl
+
+	ctx.RequeueOnNamespaceChange(ctx.KubeSharedInformerFactory.Core().V1().Secrets().Informer(), enqueueManagedSecret(log, queue))
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
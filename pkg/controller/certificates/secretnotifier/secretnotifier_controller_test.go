@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretnotifier
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+)
+
+const testHashAnnotation = "example.com/certificate-hash"
+
+func TestProcessItem(t *testing.T) {
+	secret := func(name, crt string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Annotations: map[string]string{cmapi.CertificateNameKey: name},
+			},
+			Data: map[string][]byte{corev1.TLSCertKey: []byte(crt)},
+		}
+	}
+	tlsA, tlsB := secret("a", "cert-a"), secret("b", "cert-b")
+
+	deployment := func(name, secrets, hash string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Annotations: map[string]string{cmapi.RestartOnSecretUpdateAnnotationKey: secrets},
+			},
+		}
+		if hash != "" {
+			d.Spec.Template.Annotations = map[string]string{testHashAnnotation: hash}
+		}
+		return d
+	}
+	statefulSet := func(name, secrets string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Annotations: map[string]string{cmapi.RestartOnSecretUpdateAnnotationKey: secrets},
+			},
+		}
+	}
+	patch := func(resource, name, hash string) testpkg.Action {
+		return testpkg.NewAction(coretesting.NewPatchAction(appsv1.SchemeGroupVersion.WithResource(resource), "ns", name, types.MergePatchType,
+			[]byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, testHashAnnotation, hash))))
+	}
+	hashA := certificatesHash([]*corev1.Secret{tlsA})
+	hashAB := certificatesHash([]*corev1.Secret{tlsB, tlsA})
+
+	tests := map[string]struct {
+		key             string
+		objects         []runtime.Object
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+	}{
+		"rolls out a deployment whose certificate changed": {
+			key:             "ns/a",
+			objects:         []runtime.Object{tlsA, deployment("web", "a", "outdated")},
+			expectedActions: []testpkg.Action{patch("deployments", "web", hashA)},
+			expectedEvents:  []string{"Normal CertificateSecretUpdated Rolling out to pick up the certificates in Secrets a"},
+		},
+		"hashes all secrets referenced by a workload": {
+			key:             "ns/b",
+			objects:         []runtime.Object{tlsA, tlsB, statefulSet("db", " b, a ")},
+			expectedActions: []testpkg.Action{patch("statefulsets", "db", hashAB)},
+			expectedEvents:  []string{"Normal CertificateSecretUpdated Rolling out to pick up the certificates in Secrets a, b"},
+		},
+		"does nothing if the hash is up to date": {
+			key:     "ns/a",
+			objects: []runtime.Object{tlsA, deployment("web", "a", hashA)},
+		},
+		"ignores workloads that do not reference the secret": {
+			key:     "ns/a",
+			objects: []runtime.Object{tlsA, deployment("web", "b", ""), statefulSet("db", "ab")},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:               t,
+				KubeObjects:     test.objects,
+				ExpectedActions: test.expectedActions,
+				ExpectedEvents:  test.expectedEvents,
+			}
+			builder.Init()
+			builder.Context.SecretHashAnnotation = testHashAnnotation
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.controller.ProcessItem(context.Background(), test.key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			builder.CheckAndFinish()
+		})
+	}
+}
+
+func TestReferencedSecrets(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        []string
+	}{
+		"no annotation": {},
+		"empty annotation": {
+			annotations: map[string]string{cmapi.RestartOnSecretUpdateAnnotationKey: ""},
+		},
+		"trims, sorts and de-duplicates names": {
+			annotations: map[string]string{cmapi.RestartOnSecretUpdateAnnotationKey: "b, a,,b"},
+			want:        []string{"a", "b"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := referencedSecrets(&metav1.ObjectMeta{Annotations: test.annotations})
+			if len(got) == 0 && len(test.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected secret names, want=%v, got=%v", test.want, got)
+			}
+		})
+	}
+}
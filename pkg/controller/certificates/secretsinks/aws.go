@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+// awsClientBuilder returns an AWS Secrets Manager client using the given
+// session.
+type awsClientBuilder func(sess *session.Session) secretsmanageriface.SecretsManagerAPI
+
+func newAWSClient(sess *session.Session) secretsmanageriface.SecretsManagerAPI {
+	return secretsmanager.New(sess)
+}
+
+// awsSecretsManagerSink writes to a secret in AWS Secrets Manager.
+type awsSecretsManagerSink struct {
+	client   secretsmanageriface.SecretsManagerAPI
+	secretID string
+}
+
+// awsSecretsManagerSink returns a Sink that authenticates with the
+// credentials configured by cfg, which are read from the namespace of the
+// Certificate. Ambient credentials are only used if they are allowed for
+// namespaced issuers.
+func (b *builder) awsSecretsManagerSink(crt *cmapi.Certificate, cfg *cmapi.AWSSecretsManagerSecretSink) (Sink, error) {
+	var secretAccessKey string
+	if cfg.SecretAccessKey != nil {
+		secret, err := b.secretLister.Secrets(crt.Namespace).Get(cfg.SecretAccessKey.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load secret access key: %w", err)
+		}
+		data, ok := secret.Data[cfg.SecretAccessKey.Key]
+		if !ok {
+			return nil, fmt.Errorf("no key %q in secret %q", cfg.SecretAccessKey.Key, crt.Namespace+"/"+cfg.SecretAccessKey.Name)
+		}
+		secretAccessKey = string(data)
+	}
+
+	sessionOpts := session.Options{
+		Config: *aws.NewConfig().WithRegion(cfg.Region),
+	}
+	switch {
+	case cfg.AccessKeyID != "" && secretAccessKey != "":
+		sessionOpts.Config.Credentials = credentials.NewStaticCredentials(cfg.AccessKeyID, secretAccessKey, "")
+		sessionOpts.SharedConfigState = session.SharedConfigDisable
+	case cfg.AccessKeyID != "" || secretAccessKey != "":
+		return nil, errors.New("only one of access and secret key was provided")
+	case !b.issuerOptions.IssuerAmbientCredentials:
+		return nil, errors.New("no credentials were provided and ambient credentials are not allowed")
+	}
+
+	sess, err := session.NewSessionWithOptions(sessionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create aws session: %s", err)
+	}
+	if cfg.Role != "" {
+		sess = sess.Copy(&aws.Config{Credentials: stscreds.NewCredentials(sess, cfg.Role)})
+	}
+	sess.Handlers.Build.PushBack(request.WithAppendUserAgent(util.CertManagerUserAgent))
+
+	return &awsSecretsManagerSink{client: b.awsClientBuilder(sess), secretID: cfg.SecretID}, nil
+}
+
+// Write stores data as a JSON object in a new version of the secret, and
+// creates the secret if it does not exist.
+func (s *awsSecretsManagerSink) Write(ctx context.Context, data map[string]string) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(s.secretID),
+		SecretString: aws.String(string(value)),
+	})
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != secretsmanager.ErrCodeResourceNotFoundException {
+		return err
+	}
+
+	_, err = s.client.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(s.secretID),
+		SecretString: aws.String(string(value)),
+	})
+	return err
+}
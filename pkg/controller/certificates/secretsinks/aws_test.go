@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	putErr  error
+	puts    []*secretsmanager.PutSecretValueInput
+	creates []*secretsmanager.CreateSecretInput
+}
+
+func (f *fakeSecretsManager) PutSecretValueWithContext(_ aws.Context, in *secretsmanager.PutSecretValueInput, _ ...request.Option) (*secretsmanager.PutSecretValueOutput, error) {
+	f.puts = append(f.puts, in)
+	return &secretsmanager.PutSecretValueOutput{}, f.putErr
+}
+
+func (f *fakeSecretsManager) CreateSecretWithContext(_ aws.Context, in *secretsmanager.CreateSecretInput, _ ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+	f.creates = append(f.creates, in)
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func TestAWSSecretsManagerSinkWrite(t *testing.T) {
+	const expValue = `{"ca.crt":"ca","tls.crt":"cert"}`
+
+	tests := map[string]struct {
+		putErr     error
+		expCreated bool
+		expErr     bool
+	}{
+		"a new version of an existing secret is written": {},
+		"the secret is created if it does not exist": {
+			putErr:     awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "not found", nil),
+			expCreated: true,
+		},
+		"other errors are returned": {
+			putErr: errors.New("access denied"),
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &fakeSecretsManager{putErr: test.putErr}
+			sink := &awsSecretsManagerSink{client: client, secretID: "my-app/tls"}
+
+			err := sink.Write(context.Background(), map[string]string{"tls.crt": "cert", "ca.crt": "ca"})
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error: %v, got: %v", test.expErr, err)
+			}
+
+			if len(client.puts) != 1 || *client.puts[0].SecretId != "my-app/tls" || *client.puts[0].SecretString != expValue {
+				t.Errorf("expected a single secret value to be put, got %v", client.puts)
+			}
+			if !test.expCreated {
+				if len(client.creates) > 0 {
+					t.Errorf("expected the secret not to be created, got %v", client.creates)
+				}
+				return
+			}
+			if len(client.creates) != 1 || *client.creates[0].Name != "my-app/tls" || *client.creates[0].SecretString != expValue {
+				t.Errorf("expected the secret to be created, got %v", client.creates)
+			}
+		})
+	}
+}
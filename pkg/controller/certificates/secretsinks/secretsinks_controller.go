@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vaultinternal "github.com/jetstack/cert-manager/internal/vault"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	// ControllerName is the name of the certificate secret sinks controller.
+	ControllerName = "certificates-secret-sinks"
+
+	reasonSecretSinkWritten = "SecretSinkWritten"
+	reasonSecretSinkFailed  = "SecretSinkFailed"
+)
+
+// controller writes the signed certificate, private key and CA stored in the
+// Secret of each Certificate to the external secret stores listed in the
+// Certificate's `spec.secretSinks`.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	secretLister      corelisters.SecretLister
+	client            cmclient.Interface
+	recorder          record.EventRecorder
+
+	sinkFor SinkBuilder
+}
+
+// NewController returns a new certificate secret sinks controller.
+func NewController(
+	log logr.Logger,
+	kubeClient kubernetes.Interface,
+	client cmclient.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	issuerOptions controllerpkg.IssuerOptions,
+	workqueueOptions controllerpkg.WorkqueueOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 5,
+		MaxDelay:  time.Minute * 5,
+	}), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Secret resource changes, enqueue any Certificate resources that name it as spec.secretName.
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateSecretName)),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	b := &builder{
+		issuerOptions: issuerOptions,
+		secretLister:  secretsInformer.Lister(),
+		issuerLister:  issuerInformer.Lister(),
+		createTokenFn: func(ns string) vaultinternal.CreateToken {
+			return kubeClient.CoreV1().ServiceAccounts(ns).CreateToken
+		},
+		vaultClientBuilder: vaultinternal.New,
+		awsClientBuilder:   newAWSClient,
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		secretLister:      secretsInformer.Lister(),
+		client:            client,
+		recorder:          recorder,
+		sinkFor:           b.sinkFor,
+	}, queue, mustSync
+}
+
+// ProcessItem is a worker function that will be called when a new key
+// corresponding to a Certificate to be re-synced is pulled from the workqueue.
+// ProcessItem writes the data of the Certificate's Secret to each of its
+// secret sinks that has not yet been written with the current data.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	// Writing to external secret stores requires network requests, so bound
+	// the time spent syncing a single Certificate.
+	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(crt.Spec.SecretSinks) == 0 {
+		// Remove the hashes of secret sinks that have since been removed.
+		return c.updateHashes(ctx, crt, nil)
+	}
+
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("secret not found, waiting for the certificate to be issued")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data := sinkData(secret)
+	if len(data[corev1.TLSCertKey]) == 0 {
+		log.V(logf.DebugLevel).Info("secret does not contain a certificate, waiting for the certificate to be issued")
+		return nil
+	}
+
+	// Only the hashes of the current secret sinks are kept, so that the
+	// hashes of removed sinks are pruned.
+	lastWritten := sinkHashes(crt)
+	written := make(map[string]string)
+	var errs []error
+	for _, sink := range crt.Spec.SecretSinks {
+		hash, err := sinkHash(sink, data)
+		if err != nil {
+			return err
+		}
+		if lastWritten[sink.Name] == hash {
+			written[sink.Name] = hash
+			continue
+		}
+
+		if err := c.write(ctx, crt, sink, data); err != nil {
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSecretSinkFailed, "Failed to write Secret %q to secret sink %q: %v", crt.Spec.SecretName, sink.Name, err)
+			errs = append(errs, fmt.Errorf("failed to write to secret sink %q: %w", sink.Name, err))
+			continue
+		}
+
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonSecretSinkWritten, "Wrote Secret %q to secret sink %q", crt.Spec.SecretName, sink.Name)
+		written[sink.Name] = hash
+	}
+
+	if err := c.updateHashes(ctx, crt, written); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// write writes data to the secret store configured by sink.
+func (c *controller) write(ctx context.Context, crt *cmapi.Certificate, sink cmapi.CertificateSecretSink, data map[string]string) error {
+	s, err := c.sinkFor(ctx, crt, sink)
+	if err != nil {
+		return err
+	}
+	return s.Write(ctx, data)
+}
+
+// sinkHash returns a hash of the configuration of sink and the data written
+// to it.
+func sinkHash(sink cmapi.CertificateSecretSink, data map[string]string) (string, error) {
+	b, err := json.Marshal(struct {
+		Sink cmapi.CertificateSecretSink `json:"sink"`
+		Data map[string]string           `json:"data"`
+	}{sink, data})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// sinkHashes returns the hashes recorded in the SecretSinkHashesAnnotationKey
+// annotation of the Certificate. Invalid annotation values are ignored, which
+// causes every secret sink to be written again.
+func sinkHashes(crt *cmapi.Certificate) map[string]string {
+	hashes := make(map[string]string)
+	if value, ok := crt.Annotations[cmapi.SecretSinkHashesAnnotationKey]; ok {
+		_ = json.Unmarshal([]byte(value), &hashes)
+	}
+	return hashes
+}
+
+// updateHashes records the given hashes in the SecretSinkHashesAnnotationKey
+// annotation of the Certificate, removing the annotation if there are none.
+// The Certificate is only updated if the annotation has changed.
+func (c *controller) updateHashes(ctx context.Context, crt *cmapi.Certificate, hashes map[string]string) error {
+	current, hasCurrent := crt.Annotations[cmapi.SecretSinkHashesAnnotationKey]
+
+	crt = crt.DeepCopy()
+	if len(hashes) > 0 {
+		b, err := json.Marshal(hashes)
+		if err != nil {
+			return err
+		}
+		if hasCurrent && current == string(b) {
+			return nil
+		}
+		if crt.Annotations == nil {
+			crt.Annotations = make(map[string]string)
+		}
+		crt.Annotations[cmapi.SecretSinkHashesAnnotationKey] = string(b)
+	} else {
+		if !hasCurrent {
+			return nil
+		}
+		delete(crt.Annotations, cmapi.SecretSinkHashesAnnotationKey)
+	}
+
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		ctx.CMClient,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.IssuerOptions,
+		ctx.WorkqueueOptions,
+	)
+	c.controller = ctrl
+
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+type fakeSink func(ctx context.Context, data map[string]string) error
+
+func (f fakeSink) Write(ctx context.Context, data map[string]string) error {
+	return f(ctx, data)
+}
+
+func mustSinkHash(t *testing.T, sink cmapi.CertificateSecretSink, data map[string]string) string {
+	hash, err := sinkHash(sink, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestProcessItem(t *testing.T) {
+	vaultSink := cmapi.CertificateSecretSink{
+		Name:  "vault",
+		Vault: &cmapi.VaultSecretSink{IssuerRef: cmmeta.ObjectReference{Name: "vault"}, Path: "secret/data/test"},
+	}
+	awsSink := cmapi.CertificateSecretSink{
+		Name:              "aws",
+		AWSSecretsManager: &cmapi.AWSSecretsManagerSecretSink{Region: "eu-west-1", SecretID: "test"},
+	}
+	baseCrt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-tls"),
+	)
+	withSinks := func(sinks ...cmapi.CertificateSecretSink) gen.CertificateModifier {
+		return func(crt *cmapi.Certificate) {
+			crt.Spec.SecretSinks = sinks
+		}
+	}
+	secretData := map[string][]byte{
+		corev1.TLSCertKey:       []byte("cert"),
+		corev1.TLSPrivateKeyKey: []byte("key"),
+		cmmeta.TLSCAKey:         []byte("ca"),
+		"keystore.p12":          []byte("keystore"),
+	}
+	expData := map[string]string{
+		corev1.TLSCertKey:       "cert",
+		corev1.TLSPrivateKeyKey: "key",
+		cmmeta.TLSCAKey:         "ca",
+	}
+	withHashes := func(hashes map[string]string) gen.CertificateModifier {
+		return func(crt *cmapi.Certificate) {
+			b, err := json.Marshal(hashes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			crt.Annotations = map[string]string{cmapi.SecretSinkHashesAnnotationKey: string(b)}
+		}
+	}
+
+	tests := map[string]struct {
+		// crt is the Certificate to be synced, if it exists.
+		crt        *cmapi.Certificate
+		secretData map[string][]byte
+		// writeErrs are the errors returned when writing to each sink.
+		writeErrs map[string]error
+
+		// expWrites is the number of times each sink is expected to be
+		// written when the Certificate is synced twice.
+		expWrites map[string]int
+		// expHashed are the names of the sinks whose hashes are expected to
+		// be recorded on the Certificate.
+		expHashed []string
+		expEvents []string
+		expErr    bool
+	}{
+		"do nothing if the Certificate does not exist": {
+			secretData: secretData,
+		},
+		"do nothing if the Certificate has no secret sinks": {
+			crt:        baseCrt,
+			secretData: secretData,
+		},
+		"do nothing if the Secret does not contain a certificate": {
+			crt:        gen.CertificateFrom(baseCrt, withSinks(vaultSink)),
+			secretData: map[string][]byte{corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		"write the certificate data to each secret sink once": {
+			crt:        gen.CertificateFrom(baseCrt, withSinks(vaultSink, awsSink)),
+			secretData: secretData,
+			expWrites:  map[string]int{"vault": 1, "aws": 1},
+			expHashed:  []string{"aws", "vault"},
+			expEvents: []string{
+				`Normal SecretSinkWritten Wrote Secret "test-tls" to secret sink "vault"`,
+				`Normal SecretSinkWritten Wrote Secret "test-tls" to secret sink "aws"`,
+			},
+		},
+		"do not write secret sinks whose hash is recorded on the Certificate": {
+			crt: gen.CertificateFrom(baseCrt, withSinks(vaultSink, awsSink),
+				withHashes(map[string]string{"vault": mustSinkHash(t, vaultSink, expData)})),
+			secretData: secretData,
+			expWrites:  map[string]int{"aws": 1},
+			expHashed:  []string{"aws", "vault"},
+			expEvents: []string{
+				`Normal SecretSinkWritten Wrote Secret "test-tls" to secret sink "aws"`,
+			},
+		},
+		"remove the hashes of secret sinks that have been removed": {
+			crt: gen.CertificateFrom(baseCrt, withSinks(awsSink),
+				withHashes(map[string]string{"vault": mustSinkHash(t, vaultSink, expData), "aws": mustSinkHash(t, awsSink, expData)})),
+			secretData: secretData,
+			expHashed:  []string{"aws"},
+		},
+		"remove the annotation once all secret sinks have been removed": {
+			crt: gen.CertificateFrom(baseCrt,
+				withHashes(map[string]string{"vault": mustSinkHash(t, vaultSink, expData)})),
+			secretData: secretData,
+		},
+		"retry secret sinks that failed to be written": {
+			crt:        gen.CertificateFrom(baseCrt, withSinks(vaultSink, awsSink)),
+			secretData: secretData,
+			writeErrs:  map[string]error{"vault": errors.New("permission denied")},
+			expWrites:  map[string]int{"vault": 2, "aws": 1},
+			expHashed:  []string{"aws"},
+			expEvents: []string{
+				`Warning SecretSinkFailed Failed to write Secret "test-tls" to secret sink "vault": permission denied`,
+				`Normal SecretSinkWritten Wrote Secret "test-tls" to secret sink "aws"`,
+				`Warning SecretSinkFailed Failed to write Secret "test-tls" to secret sink "vault": permission denied`,
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T: t,
+				KubeObjects: []runtime.Object{gen.Secret("test-tls",
+					gen.SetSecretNamespace("testns"),
+					gen.SetSecretData(test.secretData),
+				)},
+				ExpectedEvents: test.expEvents,
+			}
+			if test.crt != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.crt)
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			writes := make(map[string]int)
+			w.controller.sinkFor = func(_ context.Context, _ *cmapi.Certificate, sink cmapi.CertificateSecretSink) (Sink, error) {
+				return fakeSink(func(_ context.Context, data map[string]string) error {
+					if !reflect.DeepEqual(data, expData) {
+						t.Errorf("unexpected data written to sink %q, exp=%v got=%v", sink.Name, expData, data)
+					}
+					writes[sink.Name]++
+					return test.writeErrs[sink.Name]
+				}), nil
+			}
+
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(baseCrt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 2; i++ {
+				err = w.controller.ProcessItem(context.Background(), key)
+				if test.expErr != (err != nil) {
+					t.Errorf("expected error: %v, got: %v", test.expErr, err)
+				}
+				// Wait for the recorded hashes to be observed by the
+				// Certificate lister before syncing again.
+				builder.Sync()
+			}
+
+			if test.crt != nil {
+				crt, err := builder.FakeCMClient().CertmanagerV1().Certificates(test.crt.Namespace).Get(context.Background(), test.crt.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var hashed []string
+				for name := range sinkHashes(crt) {
+					hashed = append(hashed, name)
+				}
+				sort.Strings(hashed)
+				if !reflect.DeepEqual(hashed, test.expHashed) {
+					t.Errorf("unexpected sinks with recorded hashes, exp=%v got=%v", test.expHashed, hashed)
+				}
+			}
+
+			if len(writes) != len(test.expWrites) || (len(writes) > 0 && !reflect.DeepEqual(writes, test.expWrites)) {
+				t.Errorf("unexpected writes, exp=%v got=%v", test.expWrites, writes)
+			}
+			if err := builder.AllEventsCalled(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
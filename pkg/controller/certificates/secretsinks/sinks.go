@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	vaultinternal "github.com/jetstack/cert-manager/internal/vault"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+)
+
+// Sink writes the data of a Certificate's Secret to an external secret store.
+type Sink interface {
+	// Write replaces the data stored in the external secret store with data,
+	// which is keyed by the Secret data keys it was read from.
+	Write(ctx context.Context, data map[string]string) error
+}
+
+// SinkBuilder returns the Sink configured by sink for the Certificate crt.
+type SinkBuilder func(ctx context.Context, crt *cmapi.Certificate, sink cmapi.CertificateSecretSink) (Sink, error)
+
+// sinkDataKeys are the Secret data keys written to secret sinks.
+var sinkDataKeys = []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, cmmeta.TLSCAKey}
+
+// sinkData returns the data of the Secret that is written to secret sinks.
+func sinkData(secret *corev1.Secret) map[string]string {
+	data := make(map[string]string)
+	for _, key := range sinkDataKeys {
+		if value, ok := secret.Data[key]; ok {
+			data[key] = string(value)
+		}
+	}
+	return data
+}
+
+// builder builds the secret sinks supported by cert-manager.
+type builder struct {
+	issuerOptions controllerpkg.IssuerOptions
+	secretLister  corelisters.SecretLister
+	// issuerLister is used to read the Vault Issuer of a Vault secret sink
+	issuerLister  cmlisters.IssuerLister
+	createTokenFn func(ns string) vaultinternal.CreateToken
+
+	vaultClientBuilder vaultinternal.ClientBuilder
+	awsClientBuilder   awsClientBuilder
+}
+
+// sinkFor returns the Sink for the secret store configured by sink.
+func (b *builder) sinkFor(ctx context.Context, crt *cmapi.Certificate, sink cmapi.CertificateSecretSink) (Sink, error) {
+	switch {
+	case sink.Vault != nil:
+		return b.vaultSink(ctx, crt, sink.Vault)
+	case sink.AWSSecretsManager != nil:
+		return b.awsSecretsManagerSink(crt, sink.AWSSecretsManager)
+	default:
+		return nil, fmt.Errorf("no secret store is configured for secret sink %q", sink.Name)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"fmt"
+
+	vaultinternal "github.com/jetstack/cert-manager/internal/vault"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// vaultSink writes to a secret in a Vault KV version 2 secrets engine.
+type vaultSink struct {
+	client vaultinternal.Interface
+	path   string
+}
+
+// vaultSink returns a Sink that connects to Vault using the server and
+// authentication of the Vault Issuer referenced by cfg. Only Issuers in the
+// namespace of the Certificate may be referenced, so that a Certificate can
+// never write to Vault with credentials that are not owned by its namespace.
+func (b *builder) vaultSink(ctx context.Context, crt *cmapi.Certificate, cfg *cmapi.VaultSecretSink) (Sink, error) {
+	ref := cfg.IssuerRef
+	if (ref.Kind != "" && ref.Kind != cmapi.IssuerKind) ||
+		(ref.Group != "" && ref.Group != certmanager.GroupName) ||
+		(ref.Namespace != "" && ref.Namespace != crt.Namespace) {
+		return nil, fmt.Errorf("secret sinks may only reference a Vault Issuer in the namespace of the Certificate")
+	}
+
+	issuerObj, err := b.issuerLister.Issuers(crt.Namespace).Get(ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	if issuerObj.GetSpec().Vault == nil {
+		return nil, fmt.Errorf("issuer %q is not a Vault issuer", issuerObj.GetObjectMeta().Name)
+	}
+
+	client, err := b.vaultClientBuilder(ctx, b.issuerOptions.ResourceNamespace(issuerObj), b.createTokenFn, b.secretLister, issuerObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise vault client: %w", err)
+	}
+
+	return &vaultSink{client: client, path: cfg.Path}, nil
+}
+
+func (s *vaultSink) Write(ctx context.Context, data map[string]string) error {
+	return s.client.WriteKV(ctx, s.path, data)
+}
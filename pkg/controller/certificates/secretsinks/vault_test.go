@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsinks
+
+import (
+	"context"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestVaultSinkIssuerRef(t *testing.T) {
+	crt := gen.Certificate("test", gen.SetCertificateNamespace("testns"))
+
+	tests := map[string]cmmeta.ObjectReference{
+		"ClusterIssuers are rejected":              {Name: "vault", Kind: cmapi.ClusterIssuerKind},
+		"Issuers in other namespaces are rejected": {Name: "vault", Namespace: "other"},
+		"issuers of other API groups are rejected": {Name: "vault", Kind: cmapi.IssuerKind, Group: "example.com"},
+	}
+
+	for name, ref := range tests {
+		t.Run(name, func(t *testing.T) {
+			// The builder has no listers or clients, so any attempt to read
+			// the issuer or connect to Vault would panic.
+			b := &builder{}
+			if _, err := b.vaultSink(context.Background(), crt, &cmapi.VaultSecretSink{IssuerRef: ref, Path: "secret/data/test"}); err == nil {
+				t.Errorf("expected the issuerRef %+v to be rejected", ref)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
+)
+
+// PatchStatus sends the changes from the status of oldCrt to the status of
+// newCrt to the apiserver using patcher. The patch fails with a conflict if
+// the Certificate has changed since oldCrt was read.
+//
+// If the ServerSideApply feature is enabled, the status fields managed by
+// fieldManager are applied instead, so that controllers writing to different
+// fields of the status do not conflict with each other.
+func PatchStatus(ctx context.Context, patcher *statuspatch.Patcher, client cmclient.Interface, fieldManager string, oldCrt, newCrt *cmapi.Certificate) error {
+	key := "certificates/" + oldCrt.Namespace + "/" + oldCrt.Name
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		gvk := cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateKind)
+		return patcher.Apply(ctx, key, gvk, oldCrt, fieldManager, oldCrt.Status, newCrt.Status, func(ctx context.Context, pt types.PatchType, patch []byte, opts metav1.PatchOptions) error {
+			_, err := client.CertmanagerV1().Certificates(oldCrt.Namespace).Patch(ctx, oldCrt.Name, pt, patch, opts, "status")
+			return err
+		})
+	}
+	return patcher.Patch(ctx, key, oldCrt.ResourceVersion, oldCrt.Status, newCrt.Status, func(ctx context.Context, patch []byte) error {
+		_, err := client.CertmanagerV1().Certificates(oldCrt.Namespace).Patch(ctx, oldCrt.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		return err
+	})
+}
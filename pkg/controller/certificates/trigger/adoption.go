@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/secretsmanager"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	reasonSecretAdopted = "SecretAdopted"
+)
+
+// adoptSecretIfRequested adopts the certificate and private key stored in the
+// Certificate's Secret without issuing a new certificate, if the Certificate
+// has the adopt-existing-secret annotation and has never been issued.
+// Secrets that were issued by a different issuer, or that do not contain a
+// valid certificate matching the Certificate's spec, are never adopted.
+// The Secret is adopted by back-filling the annotations that would have been
+// set had the certificate been issued by cert-manager, and setting the
+// Certificate's revision to 1. It returns true if the Secret was adopted, or
+// will be adopted once its keystores have been encoded.
+func (c *controller) adoptSecretIfRequested(ctx context.Context, input policies.Input) (bool, error) {
+	log := logf.FromContext(ctx)
+	crt := input.Certificate
+
+	if crt.Annotations[cmapi.AdoptExistingSecretAnnotation] != "true" ||
+		crt.Status.Revision != nil ||
+		input.Secret == nil {
+		return false, nil
+	}
+
+	// Secrets issued by a different issuer are re-issued as usual.
+	if _, ok := input.Secret.Annotations[cmapi.IssuerNameAnnotationKey]; ok {
+		if _, message, violation := policies.SecretIssuerAnnotationsNotUpToDate(input); violation {
+			log.V(logf.DebugLevel).Info("Not adopting Secret issued by a different issuer", "message", message)
+			return false, nil
+		}
+	}
+
+	if reason, message, violation := c.canAdoptSecret(input); violation {
+		log.V(logf.InfoLevel).Info("Existing Secret cannot be adopted", "reason", reason, "message", message)
+		return false, nil
+	}
+
+	// Re-write the existing data so that the Secret's annotations, labels and
+	// additional output formats are set as if it had been issued.
+	err := c.secretsManager.UpdateData(ctx, crt, secretsmanager.SecretData{
+		PrivateKey:  input.Secret.Data[corev1.TLSPrivateKeyKey],
+		Certificate: input.Secret.Data[corev1.TLSCertKey],
+		CA:          input.Secret.Data[cmmeta.TLSCAKey],
+	})
+	var pending *secretsmanager.KeystoresPendingError
+	if errors.As(err, &pending) {
+		// Don't hold up the worker while the keystores are encoded. The
+		// Secret is adopted once they have been encoded, when they are
+		// written to the Secret from the cache.
+		log.V(logf.DebugLevel).Info("Waiting for keystores to be encoded before adopting Secret")
+		key, err := controllerpkg.KeyFunc(crt)
+		if err != nil {
+			return false, err
+		}
+		go func() {
+			<-pending.Done()
+			c.queue.Add(key)
+		}()
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	oldCrt := crt
+	crt = crt.DeepCopy()
+	revision := 1
+	crt.Status.Revision = &revision
+	message := fmt.Sprintf("Adopted the existing certificate in Secret %q without re-issuance", crt.Spec.SecretName)
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionAdopted, cmmeta.ConditionTrue, reasonSecretAdopted, message)
+	if err := certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, oldCrt, crt); err != nil {
+		return false, err
+	}
+	c.recorder.Event(crt, corev1.EventTypeNormal, reasonSecretAdopted, message)
+
+	return true, nil
+}
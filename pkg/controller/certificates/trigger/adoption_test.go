@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func Test_controller_adoptSecretIfRequested(t *testing.T) {
+	fixedNow := metav1.NewTime(time.Now())
+	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
+
+	unannotatedCrt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateCommonName("example.com"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "cert-manager.io"}),
+	)
+	baseCrt := gen.CertificateFrom(unannotatedCrt,
+		gen.AddCertificateAnnotations(map[string]string{cmapi.AdoptExistingSecretAnnotation: "true"}),
+	)
+	bundle := internaltest.MustCreateCryptoBundle(t, baseCrt, fixedClock)
+	otherBundle := internaltest.MustCreateCryptoBundle(t, baseCrt, fixedClock)
+
+	secretWith := func(pk, cert []byte, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output", Annotations: annotations},
+			Data: map[string][]byte{
+				corev1.TLSPrivateKeyKey: pk,
+				corev1.TLSCertKey:       cert,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		secret      *corev1.Secret
+		wantAdopted bool
+	}{
+		"adopts a Secret containing a valid certificate matching the spec": {
+			certificate: baseCrt,
+			secret:      secretWith(bundle.PrivateKeyBytes, bundle.CertBytes, nil),
+			wantAdopted: true,
+		},
+		"does not adopt if the annotation is not set": {
+			certificate: unannotatedCrt,
+			secret:      secretWith(bundle.PrivateKeyBytes, bundle.CertBytes, nil),
+		},
+		"does not adopt if the Certificate has already been issued": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateRevision(1)),
+			secret:      secretWith(bundle.PrivateKeyBytes, bundle.CertBytes, nil),
+		},
+		"does not adopt if the Secret does not exist": {
+			certificate: baseCrt,
+		},
+		"does not adopt if the private key does not match the certificate": {
+			certificate: baseCrt,
+			secret:      secretWith(otherBundle.PrivateKeyBytes, bundle.CertBytes, nil),
+		},
+		"does not adopt a Secret issued by a different issuer": {
+			certificate: baseCrt,
+			secret: secretWith(bundle.PrivateKeyBytes, bundle.CertBytes, map[string]string{
+				cmapi.IssuerNameAnnotationKey:  "other-issuer",
+				cmapi.IssuerKindAnnotationKey:  "Issuer",
+				cmapi.IssuerGroupAnnotationKey: "cert-manager.io",
+			}),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				CertManagerObjects: []runtime.Object{test.certificate},
+			}
+			if test.secret != nil {
+				builder.KubeObjects = append(builder.KubeObjects, test.secret)
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			gotAdopted, err := w.adoptSecretIfRequested(context.Background(), policies.Input{
+				Certificate: test.certificate,
+				Secret:      test.secret,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotAdopted != test.wantAdopted {
+				t.Fatalf("unexpected adopted result, exp=%t got=%t", test.wantAdopted, gotAdopted)
+			}
+
+			var updatedCrt *cmapi.Certificate
+			for _, action := range builder.FakeCMClient().Actions() {
+				if _, ok := action.(coretesting.PatchAction); ok && action.GetSubresource() == "status" {
+					updatedCrt, err = builder.FakeCMClient().CertmanagerV1().Certificates(test.certificate.Namespace).Get(context.Background(), test.certificate.Name, metav1.GetOptions{})
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if !test.wantAdopted {
+				if updatedCrt != nil {
+					t.Errorf("expected Certificate status not to be updated, got %+v", updatedCrt.Status)
+				}
+				if len(builder.Events()) > 0 {
+					t.Errorf("expected no events, got %v", builder.Events())
+				}
+				return
+			}
+
+			if updatedCrt == nil {
+				t.Fatal("expected Certificate status to be updated")
+			}
+			if updatedCrt.Status.Revision == nil || *updatedCrt.Status.Revision != 1 {
+				t.Errorf("expected revision 1, got %v", updatedCrt.Status.Revision)
+			}
+			if !apiutil.CertificateHasCondition(updatedCrt, cmapi.CertificateCondition{
+				Type:   cmapi.CertificateConditionAdopted,
+				Status: cmmeta.ConditionTrue,
+			}) {
+				t.Errorf("expected Adopted condition, got %v", updatedCrt.Status.Conditions)
+			}
+
+			secret, err := builder.FakeKubeClient().CoreV1().Secrets("testns").Get(context.Background(), "output", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if secret.Annotations[cmapi.IssuerNameAnnotationKey] != "ca-issuer" {
+				t.Errorf("expected issuer annotations to be back-filled, got %v", secret.Annotations)
+			}
+			if string(secret.Data[corev1.TLSCertKey]) != string(bundle.CertBytes) {
+				t.Errorf("expected existing certificate to be kept")
+			}
+			expEvent := `Normal SecretAdopted Adopted the existing certificate in Secret "output" without re-issuance`
+			if events := builder.Events(); len(events) != 1 || events[0] != expEvent {
+				t.Errorf("unexpected events, exp=%q got=%v", expEvent, events)
+			}
+		})
+	}
+}
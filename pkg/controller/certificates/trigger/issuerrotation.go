@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+// caIssuerSecretIndex is the name of the index on the Issuer and
+// ClusterIssuer informers that maps the namespace/name of a Secret to the CA
+// issuers that use it and have opted in to re-issuance on rotation.
+const caIssuerSecretIndex = "caIssuerSecret"
+
+// issuerCertificateRotated returns true if the Certificate references a CA
+// issuer with ReissueOnRotation set, and the certificate stored in the
+// Certificate's Secret was not signed by the key of the issuer's current CA
+// certificate.
+// This is the case when the CA is a delegation certificate, itself issued by
+// an upstream issuer, that has been re-keyed since. Re-issuing the
+// certificates signed by the delegation certificate keeps the pair rotating
+// together, and ensures that they are never served with a chain that cannot
+// be verified. A CA certificate renewed with the same key still verifies the
+// certificates it signed, so does not trigger re-issuance.
+func (c *controller) issuerCertificateRotated(ctx context.Context, input policies.Input) (reason, message string, rotated bool) {
+	log := logf.FromContext(ctx)
+	crt := input.Certificate
+
+	if c.issuerHelper == nil || input.Secret == nil || (crt.Spec.IssuerRef.Group != "" && crt.Spec.IssuerRef.Group != cmapi.SchemeGroupVersion.Group) {
+		return "", "", false
+	}
+	genericIssuer, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if err != nil {
+		return "", "", false
+	}
+	caIssuer := genericIssuer.GetSpec().CA
+	if caIssuer == nil || !caIssuer.ReissueOnRotation {
+		return "", "", false
+	}
+
+	caSecret, err := c.secretLister.Secrets(c.issuerOptions.ResourceNamespace(genericIssuer)).Get(caIssuer.SecretName)
+	if err != nil {
+		log.V(logf.DebugLevel).Info("Not checking for a rotated CA certificate as the issuer's Secret could not be read", "error", err.Error())
+		return "", "", false
+	}
+	caCert, err := c.caCertificates.get(caSecret)
+	if err != nil {
+		return "", "", false
+	}
+	cert, err := pki.DecodeX509CertificateBytes(input.Secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return "", "", false
+	}
+
+	if err := cert.CheckSignatureFrom(caCert); err != nil {
+		return policies.IssuerRotated, fmt.Sprintf("Re-issuing certificate as the CA certificate of %s %q has been rotated to a new key since it was issued",
+			apiutil.IssuerKind(crt.Spec.IssuerRef), crt.Spec.IssuerRef.Name), true
+	}
+
+	return "", "", false
+}
+
+// caCertificateCache holds the decoded CA certificates of CA issuer Secrets,
+// so that they are only decoded again when the Secret changes.
+type caCertificateCache struct {
+	lock  sync.Mutex
+	certs map[string]cachedCACertificate
+}
+
+type cachedCACertificate struct {
+	resourceVersion string
+	cert            *x509.Certificate
+}
+
+func newCACertificateCache() *caCertificateCache {
+	return &caCertificateCache{certs: make(map[string]cachedCACertificate)}
+}
+
+// get returns the CA certificate stored in the given Secret, decoding it
+// only if the Secret has changed since it was last decoded.
+func (c *caCertificateCache) get(secret *corev1.Secret) (*x509.Certificate, error) {
+	key := secret.Namespace + "/" + secret.Name
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, ok := c.certs[key]; ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.cert, nil
+	}
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		delete(c.certs, key)
+		return nil, err
+	}
+	c.certs[key] = cachedCACertificate{resourceVersion: secret.ResourceVersion, cert: cert}
+	return cert, nil
+}
+
+// forget removes the CA certificate of the given Secret from the cache.
+func (c *caCertificateCache) forget(namespace, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.certs, namespace+"/"+name)
+}
+
+// caIssuerSecretIndexFunc returns an IndexFunc indexing the CA issuers that
+// have opted in to re-issuance on rotation by the namespace/name of their
+// Secret.
+func caIssuerSecretIndexFunc(issuerOptions controllerpkg.IssuerOptions) cache.IndexFunc {
+	return func(obj interface{}) ([]string, error) {
+		iss, ok := obj.(cmapi.GenericIssuer)
+		if !ok {
+			return nil, nil
+		}
+		caIssuer := iss.GetSpec().CA
+		if caIssuer == nil || !caIssuer.ReissueOnRotation {
+			return nil, nil
+		}
+		return []string{issuerOptions.ResourceNamespace(iss) + "/" + caIssuer.SecretName}, nil
+	}
+}
+
+// enqueueCertificatesForCAIssuerSecret returns a function that enqueues the
+// Certificates referencing the CA issuers that use a given Secret, so that
+// they are re-checked promptly when the CA certificate in the Secret is
+// rotated. The issuer indexers must have the caIssuerSecretIndex; the
+// ClusterIssuer indexer may be nil.
+func enqueueCertificatesForCAIssuerSecret(log logr.Logger, queue workqueue.Interface, certificateLister cmlisters.CertificateLister,
+	caCertificates *caCertificateCache, issuerIndexers ...cache.Indexer) func(obj interface{}) {
+	enqueueForIssuer := certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateLister, labels.Everything(), predicate.CertificateIssuer)
+
+	return func(obj interface{}) {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		caCertificates.forget(secret.Namespace, secret.Name)
+
+		for _, indexer := range issuerIndexers {
+			if indexer == nil {
+				continue
+			}
+			issuers, err := indexer.ByIndex(caIssuerSecretIndex, secret.Namespace+"/"+secret.Name)
+			if err != nil {
+				log.Error(err, "failed listing CA issuers using Secret")
+				return
+			}
+			for _, iss := range issuers {
+				enqueueForIssuer(iss)
+			}
+		}
+	}
+}
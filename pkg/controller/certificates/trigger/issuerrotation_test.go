@@ -0,0 +1,269 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	logtest "github.com/go-logr/logr/testing"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func Test_controller_issuerCertificateRotated(t *testing.T) {
+	newKey := func() crypto.Signer {
+		pk, err := pki.GenerateECPrivateKey(256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pk
+	}
+	serial := int64(0)
+	sign := func(name string, isCA bool, pub crypto.PublicKey, parent *x509.Certificate, parentKey crypto.Signer) ([]byte, *x509.Certificate) {
+		serial++
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  isCA,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		if parent == nil {
+			parent = template
+		}
+		certPEM, cert, err := pki.SignCertificate(template, parent, pub, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certPEM, cert
+	}
+
+	rootKey := newKey()
+	rootPEM, rootCert := sign("root", true, rootKey.Public(), nil, rootKey)
+	renewedRootPEM, _ := sign("root", true, rootKey.Public(), nil, rootKey)
+
+	delegationKey := newKey()
+	delegationPEM, delegationCert := sign("delegation", true, delegationKey.Public(), rootCert, rootKey)
+	renewedDelegationPEM, _ := sign("delegation", true, delegationKey.Public(), rootCert, rootKey)
+	rekeyedDelegationKey := newKey()
+	rekeyedDelegationPEM, _ := sign("delegation", true, rekeyedDelegationKey.Public(), rootCert, rootKey)
+
+	leafKey := newKey()
+	leafPEM, _ := sign("leaf", false, leafKey.Public(), delegationCert, delegationKey)
+	rootLeafPEM, _ := sign("leaf", false, leafKey.Public(), rootCert, rootKey)
+
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer"}),
+	)
+	caIssuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca", ReissueOnRotation: true}),
+	)
+	optedOutCAIssuer := gen.Issuer("opted-out-ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"}),
+	)
+	selfSignedIssuer := gen.Issuer("selfsigned-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}),
+	)
+	secret := func(name string, certPEM, caPEM []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: name},
+			Data:       map[string][]byte{corev1.TLSCertKey: certPEM, cmmeta.TLSCAKey: caPEM},
+		}
+	}
+	leafSecret := secret("output", append(append([]byte{}, leafPEM...), delegationPEM...), rootPEM)
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		secret      *corev1.Secret
+		caSecret    *corev1.Secret
+		expRotated  bool
+	}{
+		"should not re-issue if the CA certificate has not changed": {
+			certificate: crt,
+			secret:      leafSecret,
+			caSecret:    secret("ca", delegationPEM, rootPEM),
+		},
+		"should not re-issue if the CA certificate has been renewed with the same key": {
+			certificate: crt,
+			secret:      leafSecret,
+			caSecret:    secret("ca", renewedDelegationPEM, rootPEM),
+		},
+		"should re-issue if the CA certificate has been renewed with a new key": {
+			certificate: crt,
+			secret:      leafSecret,
+			caSecret:    secret("ca", rekeyedDelegationPEM, rootPEM),
+			expRotated:  true,
+		},
+		"should not re-issue if a root CA certificate has not changed": {
+			certificate: crt,
+			secret:      secret("output", rootLeafPEM, rootPEM),
+			caSecret:    secret("ca", rootPEM, rootPEM),
+		},
+		"should not re-issue if a root CA certificate has been renewed with the same key": {
+			certificate: crt,
+			secret:      secret("output", rootLeafPEM, rootPEM),
+			caSecret:    secret("ca", renewedRootPEM, renewedRootPEM),
+		},
+		"should re-issue if the CA certificate has been replaced by a root CA": {
+			certificate: crt,
+			secret:      leafSecret,
+			caSecret:    secret("ca", rootPEM, rootPEM),
+			expRotated:  true,
+		},
+		"should not re-issue if the CA issuer has not opted in to re-issuance on rotation": {
+			certificate: gen.CertificateFrom(crt,
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "opted-out-ca-issuer", Kind: "Issuer"}),
+			),
+			secret:   leafSecret,
+			caSecret: secret("ca", rekeyedDelegationPEM, rootPEM),
+		},
+		"should not re-issue if the CA issuer's Secret does not exist": {
+			certificate: crt,
+			secret:      leafSecret,
+		},
+		"should not re-issue certificates from issuers other than CA issuers": {
+			certificate: gen.CertificateFrom(crt,
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "selfsigned-issuer", Kind: "Issuer"}),
+			),
+			secret:   leafSecret,
+			caSecret: secret("ca", rekeyedDelegationPEM, rootPEM),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeObjects := []runtime.Object{test.secret}
+			if test.caSecret != nil {
+				kubeObjects = append(kubeObjects, test.caSecret)
+			}
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: []runtime.Object{test.certificate, caIssuer, optedOutCAIssuer, selfSignedIssuer},
+				KubeObjects:        kubeObjects,
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+
+			builder.Start()
+			defer builder.Stop()
+
+			input := policies.Input{Certificate: test.certificate, Secret: test.secret}
+			reason, _, rotated := w.issuerCertificateRotated(context.Background(), input)
+			if rotated != test.expRotated {
+				t.Errorf("unexpected result, exp=%t got=%t", test.expRotated, rotated)
+			}
+			if rotated && reason != policies.IssuerRotated {
+				t.Errorf("unexpected reason, exp=%q got=%q", policies.IssuerRotated, reason)
+			}
+		})
+	}
+}
+
+func Test_enqueueCertificatesForCAIssuerSecret(t *testing.T) {
+	issuerOptions := controllerpkg.IssuerOptions{ClusterResourceNamespace: "cert-manager"}
+	newIndexer := func(objs ...runtime.Object) cache.Indexer {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{caIssuerSecretIndex: caIssuerSecretIndexFunc(issuerOptions)})
+		for _, obj := range objs {
+			if err := indexer.Add(obj); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return indexer
+	}
+
+	issuerIndexer := newIndexer(
+		gen.Issuer("opted-in", gen.SetIssuerNamespace("testns"), gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca", ReissueOnRotation: true})),
+		gen.Issuer("opted-out", gen.SetIssuerNamespace("testns"), gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"})),
+	)
+	clusterIssuerIndexer := newIndexer(
+		gen.ClusterIssuer("cluster-opted-in", gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca", ReissueOnRotation: true})),
+	)
+	certificateIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, crt := range []*cmapi.Certificate{
+		gen.Certificate("opted-in", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "opted-in", Kind: "Issuer"})),
+		gen.Certificate("opted-out", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "opted-out", Kind: "Issuer"})),
+		gen.Certificate("cluster-opted-in", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "cluster-opted-in", Kind: "ClusterIssuer"})),
+	} {
+		if err := certificateIndexer.Add(crt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		secret  *corev1.Secret
+		expKeys []string
+	}{
+		"should enqueue Certificates of opted in Issuers using the Secret": {
+			secret:  &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "ca"}},
+			expKeys: []string{"testns/opted-in"},
+		},
+		"should enqueue Certificates of opted in ClusterIssuers using the Secret": {
+			secret:  &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "cert-manager", Name: "ca"}},
+			expKeys: []string{"testns/cluster-opted-in"},
+		},
+		"should not enqueue Certificates for unrelated Secrets": {
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "other"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			queue := workqueue.New()
+			defer queue.ShutDown()
+
+			enqueue := enqueueCertificatesForCAIssuerSecret(logtest.TestLogger{T: t}, queue, cmlisters.NewCertificateLister(certificateIndexer),
+				newCACertificateCache(), issuerIndexer, clusterIssuerIndexer)
+			enqueue(test.secret)
+
+			var keys []string
+			for queue.Len() > 0 {
+				key, _ := queue.Get()
+				keys = append(keys, key.(string))
+				queue.Done(key)
+			}
+			assert.Equal(t, test.expKeys, keys)
+		})
+	}
+}
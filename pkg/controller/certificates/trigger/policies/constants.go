@@ -41,4 +41,16 @@ const (
 	// Expired is a policy violation reason for a scenario where Certificate has
 	// expired.
 	Expired string = "Expired"
+	// EarlyRenewal is a reason for a scenario where the ACME server has
+	// requested that a Certificate is renewed earlier than usual using ACME
+	// Renewal Information, e.g. because of an incident.
+	EarlyRenewal string = "EarlyRenewal"
+	// IssuerRotated is a reason for a scenario where the certificate in the
+	// Certificate's Secret was not signed by the key of its CA issuer's
+	// current CA certificate, e.g. when a delegation certificate has been
+	// re-keyed.
+	IssuerRotated string = "IssuerRotated"
+	// Revoked is a policy violation reason for a scenario where the
+	// certificate stored in the Certificate's Secret has been revoked.
+	Revoked string = "Revoked"
 )
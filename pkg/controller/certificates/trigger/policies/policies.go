@@ -27,6 +27,7 @@ import (
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/externalkey"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -78,6 +79,23 @@ func NewTriggerPolicyChain(c clock.Clock) Chain {
 		SecretPrivateKeyMatchesSpec,
 		SecretIssuerAnnotationsNotUpToDate,
 		CurrentCertificateRequestNotValidForSpec,
+		CurrentCertificateRevoked,
+		CurrentCertificateNearingExpiry(c),
+	}
+}
+
+// NewSecretAdoptionPolicyChain returns a policy chain that can be used to
+// check whether the certificate and private key already stored in a
+// Certificate's Secret can be adopted without issuing a new certificate.
+// Unlike the trigger policy chain, the issuer annotations on the Secret are
+// not considered, as they are back-filled when the Secret is adopted.
+func NewSecretAdoptionPolicyChain(c clock.Clock) Chain {
+	return Chain{
+		SecretDoesNotExist,
+		SecretIsMissingData,
+		SecretPublicKeysDiffer,
+		SecretPrivateKeyMatchesSpec,
+		currentSecretValidForSpec,
 		CurrentCertificateNearingExpiry(c),
 	}
 }
@@ -95,7 +113,9 @@ func SecretIsMissingData(input Input) (string, string, bool) {
 	}
 	pkData := input.Secret.Data[corev1.TLSPrivateKeyKey]
 	certData := input.Secret.Data[corev1.TLSCertKey]
-	if len(pkData) == 0 {
+	// The private key is not stored in the Secret if it is held by an
+	// external signer.
+	if len(pkData) == 0 && externalKeyRef(input) == nil {
 		return MissingData, "Issuing certificate as Secret does not contain a private key", true
 	}
 	if len(certData) == 0 {
@@ -107,6 +127,18 @@ func SecretIsMissingData(input Input) (string, string, bool) {
 func SecretPublicKeysDiffer(input Input) (string, string, bool) {
 	pkData := input.Secret.Data[corev1.TLSPrivateKeyKey]
 	certData := input.Secret.Data[corev1.TLSCertKey]
+	if ref := externalKeyRef(input); ref != nil {
+		// The private key is not available, so rely on the Secret recording
+		// which external key the certificate was issued for.
+		if _, err := pki.DecodeX509CertificateBytes(certData); err != nil {
+			return InvalidKeyPair, fmt.Sprintf("Issuing certificate as Secret contains an invalid certificate: %v", err), true
+		}
+		keyURL := externalkey.KeyURL(ref)
+		if input.Secret.Annotations[cmapi.PrivateKeyExternalRefAnnotationKey] != keyURL {
+			return InvalidKeyPair, fmt.Sprintf("Issuing certificate as Secret was not issued for external private key %q", keyURL), true
+		}
+		return "", "", false
+	}
 	// TODO: replace this with a generic decoder that can handle different
 	//  formats such as JKS, P12 etc (i.e. add proper support for keystores)
 	_, err := tls.X509KeyPair(certData, pkData)
@@ -117,6 +149,11 @@ func SecretPublicKeysDiffer(input Input) (string, string, bool) {
 }
 
 func SecretPrivateKeyMatchesSpec(input Input) (string, string, bool) {
+	if externalKeyRef(input) != nil {
+		// Options for external private keys are checked by
+		// SecretPublicKeysDiffer.
+		return "", "", false
+	}
 	if input.Secret.Data == nil || len(input.Secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
 		return SecretMismatch, "Existing issued Secret does not contain private key data", true
 	}
@@ -127,7 +164,7 @@ func SecretPrivateKeyMatchesSpec(input Input) (string, string, bool) {
 		return SecretMismatch, fmt.Sprintf("Existing issued Secret contains invalid private key data: %v", err), true
 	}
 
-	violations, err := certificates.PrivateKeyMatchesSpec(pk, input.Certificate.Spec)
+	violations, err := pki.PrivateKeyMatchesSpec(pk, input.Certificate.Spec)
 	if err != nil {
 		return SecretMismatch, fmt.Sprintf("Failed to check private key is up to date: %v", err), true
 	}
@@ -160,7 +197,7 @@ func CurrentCertificateRequestNotValidForSpec(input Input) (string, string, bool
 		return currentSecretValidForSpec(input)
 	}
 
-	violations, err := certificates.RequestMatchesSpec(input.CurrentRevisionRequest, input.Certificate.Spec)
+	violations, err := pki.RequestMatchesSpec(input.CurrentRevisionRequest, input.Certificate.Spec)
 	if err != nil {
 		// If parsing the request fails, we don't immediately trigger a re-issuance as
 		// the existing certificate stored in the Secret may still be valid/up to date.
@@ -177,7 +214,13 @@ func CurrentCertificateRequestNotValidForSpec(input Input) (string, string, bool
 // and is instead called by currentCertificateRequestValidForSpec if no there
 // is no existing CertificateRequest resource.
 func currentSecretValidForSpec(input Input) (string, string, bool) {
-	violations, err := certificates.SecretDataAltNamesMatchSpec(input.Secret, input.Certificate.Spec)
+	spec := input.Certificate.Spec
+	if spec.AllowPartialSANs {
+		// The names that the issuer left out of the current certificate have
+		// already been accepted, so they are not a reason to re-issue.
+		spec = pki.WithoutDroppedSANs(spec, input.Certificate.Status.DroppedSANs)
+	}
+	violations, err := pki.SecretDataAltNamesMatchSpec(input.Secret, spec)
 	if err != nil {
 		// This case should never be reached as we already check the certificate data can
 		// be parsed in an earlier policy check, but handle it anyway.
@@ -225,6 +268,26 @@ func CurrentCertificateNearingExpiry(c clock.Clock) Func {
 	}
 }
 
+// CurrentCertificateRevoked checks whether the certificate stored in the
+// Secret has been revoked by cert-manager, in which case it has to be
+// re-issued once revocation is no longer requested.
+func CurrentCertificateRevoked(input Input) (string, string, bool) {
+	revoked := input.Certificate.Status.RevokedSerialNumber
+	if revoked == "" {
+		return "", "", false
+	}
+	cert, err := pki.DecodeX509CertificateBytes(input.Secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		// This case should never happen as it should always be caught by the
+		// secretPublicKeysMatch function beforehand, but handle it just in case.
+		return "InvalidCertificate", fmt.Sprintf("Failed to decode stored certificate: %v", err), true
+	}
+	if certificates.SerialNumber(cert) == revoked {
+		return Revoked, fmt.Sprintf("Certificate with serial number %s has been revoked", revoked), true
+	}
+	return "", "", false
+}
+
 // CurrentCertificateHasExpired is used exclusively to check if the current
 // issued certificate has actually expired rather than just nearing expiry.
 func CurrentCertificateHasExpired(c clock.Clock) Func {
@@ -249,6 +312,15 @@ func CurrentCertificateHasExpired(c clock.Clock) Func {
 	}
 }
 
+// externalKeyRef returns the external private key reference of the input's
+// Certificate, if any.
+func externalKeyRef(input Input) *cmapi.PrivateKeyExternalRef {
+	if input.Certificate == nil {
+		return nil
+	}
+	return externalkey.Ref(input.Certificate.Spec)
+}
+
 func formatIssuerRef(name, kind, group string) string {
 	if group == "" {
 		group = "cert-manager.io"
@@ -17,6 +17,7 @@ limitations under the License.
 package policies
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -26,7 +27,9 @@ import (
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 // Runs a full set of tests against the 'policy chain' once it is composed
@@ -37,6 +40,37 @@ import (
 func TestDefaultPolicyChain(t *testing.T) {
 	clock := &fakeclock.FakeClock{}
 	staticFixedPrivateKey := internaltest.MustCreatePEMPrivateKey(t)
+	externalKeyCertificate := &cmapi.Certificate{
+		Spec: cmapi.CertificateSpec{
+			CommonName: "example.com",
+			IssuerRef:  cmmeta.ObjectReference{Name: "testissuer"},
+			PrivateKey: &cmapi.CertificatePrivateKey{
+				ExternalRef: &cmapi.PrivateKeyExternalRef{
+					SignerURL: "https://signer.example.com",
+					KeyID:     "key",
+				},
+			},
+		},
+	}
+	revokedCertPEM := internaltest.MustCreateCertWithNotBeforeAfter(t, staticFixedPrivateKey,
+		&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+		clock.Now(), clock.Now().Add(time.Hour),
+	)
+	revokedCert, err := pki.DecodeX509CertificateBytes(revokedCertPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revokedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "something",
+			Annotations: map[string]string{
+				cmapi.IssuerNameAnnotationKey: "testissuer",
+			},
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+			corev1.TLSCertKey:       revokedCertPEM,
+		},
+	}
 	tests := map[string]struct {
 		// policy inputs
 		certificate *cmapi.Certificate
@@ -209,7 +243,7 @@ func TestDefaultPolicyChain(t *testing.T) {
 			reissue: true,
 		},
 		// we only have a basic test here for this as unit tests for the
-		// `certificates.RequestMatchesSpec` function cover all other cases.
+		// `pki.RequestMatchesSpec` function cover all other cases.
 		"trigger issuance when CertificateRequest does not match certificate spec": {
 			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
 				CommonName: "new.example.com",
@@ -342,6 +376,38 @@ func TestDefaultPolicyChain(t *testing.T) {
 				},
 			},
 		},
+		"do nothing if signed x509 certificate in Secret only lacks accepted dropped SANs (when request does not exist)": {
+			certificate: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:       "example.com",
+					DNSNames:         []string{"example.com", "dropped.example.com"},
+					AllowPartialSANs: true,
+					IssuerRef: cmmeta.ObjectReference{
+						Name:  "testissuer",
+						Kind:  "IssuerKind",
+						Group: "group.example.com",
+					},
+				},
+				Status: cmapi.CertificateStatus{
+					DroppedSANs: []string{"dropped.example.com"},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "something",
+					Annotations: map[string]string{
+						cmapi.IssuerNameAnnotationKey:  "testissuer",
+						cmapi.IssuerKindAnnotationKey:  "IssuerKind",
+						cmapi.IssuerGroupAnnotationKey: "group.example.com",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+					corev1.TLSCertKey: internaltest.MustCreateCert(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com", DNSNames: []string{"example.com"}}},
+					),
+				},
+			},
+		},
 		"trigger renewal if renewalTime is right now": {
 			certificate: &cmapi.Certificate{
 				Spec: cmapi.CertificateSpec{
@@ -416,6 +482,33 @@ func TestDefaultPolicyChain(t *testing.T) {
 			message: "Renewing certificate as renewal was scheduled at 0000-12-31 23:59:00 +0000 UTC",
 			reissue: true,
 		},
+		"trigger issuance if the certificate in the Secret has been revoked": {
+			certificate: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName: "example.com",
+					IssuerRef:  cmmeta.ObjectReference{Name: "testissuer"},
+				},
+				Status: cmapi.CertificateStatus{
+					RevokedSerialNumber: certificates.SerialNumber(revokedCert),
+				},
+			},
+			secret:  revokedSecret,
+			reason:  Revoked,
+			message: fmt.Sprintf("Certificate with serial number %X has been revoked", revokedCert.SerialNumber),
+			reissue: true,
+		},
+		"does not trigger issuance if a previous certificate has been revoked": {
+			certificate: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName: "example.com",
+					IssuerRef:  cmmeta.ObjectReference{Name: "testissuer"},
+				},
+				Status: cmapi.CertificateStatus{
+					RevokedSerialNumber: "ABCDEF",
+				},
+			},
+			secret: revokedSecret,
+		},
 		"does not trigger renewal if the x509 cert has been re-issued, but Certificate's renewal time has not been updated yet": {
 			certificate: &cmapi.Certificate{
 				Spec: cmapi.CertificateSpec{
@@ -484,6 +577,68 @@ func TestDefaultPolicyChain(t *testing.T) {
 				},
 			},
 		},
+		"does not trigger issuance if Secret was issued for the external private key": {
+			certificate: externalKeyCertificate,
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "something",
+					Annotations: map[string]string{
+						cmapi.IssuerNameAnnotationKey:            "testissuer",
+						cmapi.PrivateKeyExternalRefAnnotationKey: "https://signer.example.com/keys/key",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: {},
+					corev1.TLSCertKey: internaltest.MustCreateCertWithNotBeforeAfter(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+						clock.Now().Add(time.Minute*-30),
+						clock.Now().Add(time.Hour),
+					),
+				},
+			},
+		},
+		"trigger issuance if Secret was issued for a different external private key": {
+			certificate: externalKeyCertificate,
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "something",
+					Annotations: map[string]string{
+						cmapi.IssuerNameAnnotationKey:            "testissuer",
+						cmapi.PrivateKeyExternalRefAnnotationKey: "https://signer.example.com/keys/other",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: {},
+					corev1.TLSCertKey: internaltest.MustCreateCertWithNotBeforeAfter(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+						clock.Now().Add(time.Minute*-30),
+						clock.Now().Add(time.Hour),
+					),
+				},
+			},
+			reason:  InvalidKeyPair,
+			message: `Issuing certificate as Secret was not issued for external private key "https://signer.example.com/keys/key"`,
+			reissue: true,
+		},
+		"trigger issuance if Secret contains an in-cluster private key but an external key is requested": {
+			certificate: externalKeyCertificate,
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "something",
+					Annotations: map[string]string{
+						cmapi.IssuerNameAnnotationKey: "testissuer",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+					corev1.TLSCertKey: internaltest.MustCreateCertWithNotBeforeAfter(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+						clock.Now().Add(time.Minute*-30),
+						clock.Now().Add(time.Hour),
+					),
+				},
+			},
+			reason:  InvalidKeyPair,
+			message: `Issuing certificate as Secret was not issued for external private key "https://signer.example.com/keys/key"`,
+			reissue: true,
+		},
 	}
 	policyChain := NewTriggerPolicyChain(clock)
 	for name, test := range tests {
@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	acmeapi "golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// renewalInfoRetryOnError is how long to wait before polling an ACME
+	// server for renewal information again after a failed request, if the
+	// ACME server did not send a Retry-After header.
+	renewalInfoRetryOnError = time.Hour
+
+	// renewalInfoRetryNotSupported is how long to wait before checking
+	// whether an ACME server that does not support ACME Renewal Information
+	// has started supporting it.
+	renewalInfoRetryNotSupported = 24 * time.Hour
+
+	// renewalInfoTimeout bounds the time taken to fetch renewal information.
+	renewalInfoTimeout = time.Minute
+)
+
+// renewalInfoChecker fetches and caches ACME Renewal Information
+// (draft-ietf-acme-ari) for certificates issued by ACME issuers.
+// Renewal information is fetched in the background so that syncing a
+// Certificate never waits for the ACME server. The Certificate is re-queued
+// once the renewal information has been fetched.
+type renewalInfoChecker struct {
+	// ctx is the context that renewal information is fetched with.
+	ctx          context.Context
+	issuerHelper issuer.Helper
+	clock        clock.Clock
+	// enqueue is called with the key of a Certificate when its renewal
+	// information has been fetched.
+	enqueue func(key string)
+
+	// httpClient returns the HTTP client used to communicate with an ACME
+	// server.
+	httpClient     func(skipTLSVerify bool) *http.Client
+	getRenewalInfo acmecl.RenewalInfoFunc
+	// runAsync runs fn in the background. It can be overridden in tests.
+	runAsync func(fn func())
+
+	lock sync.Mutex
+	// cache holds the latest renewal information for each certificate,
+	// keyed by the ACME server URL and the certificate's ARI identifier.
+	cache map[string]renewalInfoCacheEntry
+	// fetching holds the keys of the cache entries currently being fetched.
+	fetching map[string]struct{}
+}
+
+type renewalInfoCacheEntry struct {
+	// info is nil if the renewal information could not be fetched.
+	info     *acmecl.RenewalInfo
+	nextPoll time.Time
+}
+
+// suggestedRenewal describes when the ACME server suggests a certificate
+// is renewed.
+type suggestedRenewal struct {
+	// renewalTime is a point chosen within the suggested window at which
+	// the certificate should be renewed.
+	renewalTime    time.Time
+	window         acmecl.RenewalWindow
+	explanationURL string
+
+	// nextPoll is when the renewal information should be fetched again.
+	nextPoll time.Time
+}
+
+func newRenewalInfoChecker(ctx context.Context, issuerHelper issuer.Helper, clock clock.Clock, enqueue func(string), httpClient func(bool) *http.Client) *renewalInfoChecker {
+	return &renewalInfoChecker{
+		ctx:            ctx,
+		issuerHelper:   issuerHelper,
+		clock:          clock,
+		enqueue:        enqueue,
+		httpClient:     httpClient,
+		getRenewalInfo: acmecl.GetRenewalInfo,
+		runAsync:       func(fn func()) { go fn() },
+		cache:          make(map[string]renewalInfoCacheEntry),
+		fetching:       make(map[string]struct{}),
+	}
+}
+
+// suggestedRenewal returns the renewal suggested by the ACME server that
+// issued the Certificate's current certificate. It returns nil if the
+// Certificate does not reference an ACME issuer, or if the ACME server does
+// not publish renewal information for the certificate, in which case the
+// Certificate is renewed according to its renewBefore field.
+// If the cached renewal information is missing or should be polled again,
+// it is fetched in the background and key is re-queued once it has been.
+// Until then, the cached renewal information, if any, is used.
+func (r *renewalInfoChecker) suggestedRenewal(ctx context.Context, key string, input policies.Input) *suggestedRenewal {
+	log := logf.FromContext(ctx)
+	crt := input.Certificate
+
+	if input.Secret == nil || (crt.Spec.IssuerRef.Group != "" && crt.Spec.IssuerRef.Group != cmapi.SchemeGroupVersion.Group) {
+		return nil
+	}
+	genericIssuer, err := r.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if err != nil {
+		log.V(logf.DebugLevel).Info("Not checking ACME renewal information as the issuer could not be read", "error", err.Error())
+		return nil
+	}
+	acmeIssuer := genericIssuer.GetSpec().ACME
+	if acmeIssuer == nil {
+		return nil
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(input.Secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil
+	}
+	certID, err := acmecl.RenewalInfoCertID(cert)
+	if err != nil {
+		log.V(logf.DebugLevel).Info("Not checking ACME renewal information for certificate", "error", err.Error())
+		return nil
+	}
+
+	cacheKey := acmeIssuer.Server + "/" + certID
+
+	r.lock.Lock()
+	entry, ok := r.cache[cacheKey]
+	_, fetching := r.fetching[cacheKey]
+	startFetch := (!ok || !r.clock.Now().Before(entry.nextPoll)) && !fetching
+	if startFetch {
+		r.fetching[cacheKey] = struct{}{}
+	}
+	r.lock.Unlock()
+
+	if startFetch {
+		log := log.WithValues("acme_server", acmeIssuer.Server)
+		httpClient := r.httpClient(acmeIssuer.SkipTLSVerify)
+		r.runAsync(func() {
+			r.fetch(log, key, cacheKey, httpClient, acmeIssuer.Server, cert)
+		})
+
+		// the renewal information may have been fetched synchronously
+		r.lock.Lock()
+		entry, ok = r.cache[cacheKey]
+		r.lock.Unlock()
+	}
+
+	if !ok || entry.info == nil {
+		return nil
+	}
+
+	window := clampRenewalWindow(entry.info.SuggestedWindow, cert)
+	return &suggestedRenewal{
+		renewalTime:    renewalTimeInWindow(certID, window),
+		window:         window,
+		explanationURL: entry.info.ExplanationURL,
+		nextPoll:       entry.nextPoll,
+	}
+}
+
+// fetch fetches the renewal information for the certificate, stores it in
+// the cache and re-queues the Certificate with the given key.
+func (r *renewalInfoChecker) fetch(log logr.Logger, key, cacheKey string, httpClient *http.Client, directoryURL string, cert *x509.Certificate) {
+	ctx, cancel := context.WithTimeout(r.ctx, renewalInfoTimeout)
+	defer cancel()
+
+	info, err := r.getRenewalInfo(ctx, httpClient, directoryURL, cert)
+	now := r.clock.Now()
+	var entry renewalInfoCacheEntry
+	var acmeErr *acmeapi.Error
+	switch {
+	case errors.Is(err, acmecl.ErrRenewalInfoNotSupported):
+		entry = renewalInfoCacheEntry{nextPoll: now.Add(renewalInfoRetryNotSupported)}
+	case errors.As(err, &acmeErr) && acmeErr.Header.Get("Retry-After") != "":
+		log.V(logf.WarnLevel).Info("Failed to fetch ACME renewal information, falling back to renewBefore", "error", err.Error())
+		entry = renewalInfoCacheEntry{nextPoll: now.Add(acmecl.ParseRenewalInfoRetryAfter(acmeErr.Header.Get("Retry-After"), now))}
+	case err != nil:
+		log.V(logf.WarnLevel).Info("Failed to fetch ACME renewal information, falling back to renewBefore", "error", err.Error())
+		entry = renewalInfoCacheEntry{nextPoll: now.Add(renewalInfoRetryOnError)}
+	default:
+		entry = renewalInfoCacheEntry{info: info, nextPoll: now.Add(info.RetryAfter)}
+	}
+
+	r.lock.Lock()
+	r.pruneCache(now)
+	r.cache[cacheKey] = entry
+	delete(r.fetching, cacheKey)
+	r.lock.Unlock()
+
+	r.enqueue(key)
+}
+
+// pruneCache removes entries that have not been refreshed for long enough
+// that their certificate is likely to have been renewed or deleted. It must
+// be called with the lock held.
+func (r *renewalInfoChecker) pruneCache(now time.Time) {
+	for key, entry := range r.cache {
+		if now.Sub(entry.nextPoll) > renewalInfoRetryNotSupported {
+			delete(r.cache, key)
+		}
+	}
+}
+
+// clampRenewalWindow restricts the suggested renewal window to the validity
+// period of the certificate, so that a misbehaving ACME server cannot defer
+// renewal until after the certificate has expired. If the window lies
+// entirely outside of the validity period, it is reduced to a single point
+// at the nearest end of the validity period.
+func clampRenewalWindow(window acmecl.RenewalWindow, cert *x509.Certificate) acmecl.RenewalWindow {
+	clamp := func(t time.Time) time.Time {
+		switch {
+		case t.Before(cert.NotBefore):
+			return cert.NotBefore
+		case t.After(cert.NotAfter):
+			return cert.NotAfter
+		}
+		return t
+	}
+	return acmecl.RenewalWindow{Start: clamp(window.Start), End: clamp(window.End)}
+}
+
+// renewalTimeInWindow picks a point in the suggested renewal window. The
+// point is derived from the certificate's identifier rather than chosen
+// at random so that it is stable between polls and controller restarts,
+// whilst still spreading renewals of many certificates across the window.
+func renewalTimeInWindow(certID string, window acmecl.RenewalWindow) time.Time {
+	h := fnv.New64a()
+	h.Write([]byte(certID))
+	fraction := float64(h.Sum64()) / math.MaxUint64
+
+	return window.Start.Add(time.Duration(fraction * float64(window.End.Sub(window.Start))))
+}
+
+// applyRenewalInfo overrides the renewal decision made using the
+// Certificate's renewBefore field with the renewal window suggested by the
+// ACME server, if there is one. If the certificate should not yet be renewed,
+// a re-check is scheduled for when it should be renewed or when the renewal
+// information should be fetched again, whichever comes first.
+func (c *controller) applyRenewalInfo(ctx context.Context, key string, input policies.Input) (reason, message string, reissue, ok bool) {
+	suggested := c.renewalInfo.suggestedRenewal(ctx, key, input)
+	if suggested == nil {
+		return "", "", false, false
+	}
+
+	now := c.clock.Now()
+	if now.Before(suggested.renewalTime) {
+		log := logf.FromContext(ctx)
+		log.V(logf.DebugLevel).Info("Deferring renewal to the window suggested by the ACME server",
+			"renewal_time", suggested.renewalTime, "window_start", suggested.window.Start, "window_end", suggested.window.End)
+
+		recheckAt := suggested.renewalTime
+		if suggested.nextPoll.Before(recheckAt) {
+			recheckAt = suggested.nextPoll
+		}
+		c.scheduleRecheckOfCertificateIfRequired(log, key, recheckAt.Sub(now))
+		return "", "", false, true
+	}
+
+	// The ACME server signals that a certificate must be renewed early, e.g.
+	// because it is about to be revoked, by moving the suggested window into
+	// the past and usually by providing an explanation.
+	if !suggested.window.End.After(now) || suggested.explanationURL != "" {
+		message := "Renewing certificate early as requested by the ACME server"
+		if suggested.explanationURL != "" {
+			message = fmt.Sprintf("%s, see %s", message, suggested.explanationURL)
+		}
+		return policies.EarlyRenewal, message, true, true
+	}
+
+	return policies.Renewing, fmt.Sprintf("Renewing certificate as the renewal window suggested by the ACME server started at %s",
+		suggested.window.Start.Format(time.RFC3339)), true, true
+}
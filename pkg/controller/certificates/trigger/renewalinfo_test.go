@@ -0,0 +1,331 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func Test_controller_applyRenewalInfo(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, feature.ACMERenewalInfo, true)()
+
+	fixedNow := metav1.NewTime(time.Now())
+	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
+
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateCommonName("example.com"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "acme-issuer", Kind: "Issuer"}),
+	)
+	caCrt := gen.CertificateFrom(crt,
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer"}),
+	)
+	acmeIssuer := gen.Issuer("acme-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerACME(cmacme.ACMEIssuer{Server: "https://acme.example.com/directory"}),
+	)
+	caIssuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"}),
+	)
+
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := pki.GenerateTemplate(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.AuthorityKeyId = []byte{0x01, 0x02, 0x03}
+	template.NotBefore = fixedNow.Add(-24 * time.Hour)
+	template.NotAfter = fixedNow.Add(30 * 24 * time.Hour)
+	certPEM, _, err := pki.SignCertificate(template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output"},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM},
+	}
+
+	window := func(start, end time.Duration) acmecl.RenewalWindow {
+		return acmecl.RenewalWindow{Start: fixedNow.Add(start), End: fixedNow.Add(end)}
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		renewalInfo *acmecl.RenewalInfo
+		renewalErr  error
+
+		expCalls   int
+		expOK      bool
+		expReissue bool
+		expReason  string
+		expMessage string
+	}{
+		"should not fetch renewal information for non-ACME issuers": {
+			certificate: caCrt,
+		},
+		"should fall back to renewBefore if the ACME server does not support renewal information": {
+			certificate: crt,
+			renewalErr:  acmecl.ErrRenewalInfoNotSupported,
+			expCalls:    1,
+		},
+		"should defer renewal if the suggested window has not started": {
+			certificate: crt,
+			renewalInfo: &acmecl.RenewalInfo{SuggestedWindow: window(time.Hour, 2*time.Hour), RetryAfter: time.Hour},
+			expCalls:    1,
+			expOK:       true,
+		},
+		"should renew if the suggested window has started": {
+			certificate: crt,
+			renewalInfo: &acmecl.RenewalInfo{SuggestedWindow: window(-2*time.Hour, time.Nanosecond), RetryAfter: time.Hour},
+			expCalls:    1,
+			expOK:       true,
+			expReissue:  true,
+			expReason:   policies.Renewing,
+			expMessage:  "Renewing certificate as the renewal window suggested by the ACME server started at " + fixedNow.Add(-2*time.Hour).Format(time.RFC3339),
+		},
+		"should renew early if the suggested window is in the past": {
+			certificate: crt,
+			renewalInfo: &acmecl.RenewalInfo{SuggestedWindow: window(-2*time.Hour, -time.Hour), RetryAfter: time.Hour},
+			expCalls:    1,
+			expOK:       true,
+			expReissue:  true,
+			expReason:   policies.EarlyRenewal,
+			expMessage:  "Renewing certificate early as requested by the ACME server",
+		},
+		"should include the explanation URL when renewing early": {
+			certificate: crt,
+			renewalInfo: &acmecl.RenewalInfo{
+				SuggestedWindow: window(-2*time.Hour, -time.Hour),
+				ExplanationURL:  "https://example.com/incident",
+				RetryAfter:      time.Hour,
+			},
+			expCalls:   1,
+			expOK:      true,
+			expReissue: true,
+			expReason:  policies.EarlyRenewal,
+			expMessage: "Renewing certificate early as requested by the ACME server, see https://example.com/incident",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				CertManagerObjects: []runtime.Object{test.certificate, acmeIssuer, caIssuer},
+				KubeObjects:        []runtime.Object{secret},
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+
+			// fetch renewal information synchronously so that it is
+			// available on the first sync
+			w.renewalInfo.runAsync = func(fn func()) { fn() }
+			var enqueued []string
+			w.renewalInfo.enqueue = func(key string) { enqueued = append(enqueued, key) }
+			calls := 0
+			w.renewalInfo.getRenewalInfo = func(_ context.Context, _ *http.Client, directoryURL string, _ *x509.Certificate) (*acmecl.RenewalInfo, error) {
+				calls++
+				if directoryURL != acmeIssuer.Spec.ACME.Server {
+					t.Errorf("unexpected directory URL %q", directoryURL)
+				}
+				return test.renewalInfo, test.renewalErr
+			}
+
+			builder.Start()
+			defer builder.Stop()
+
+			input := policies.Input{Certificate: test.certificate, Secret: secret}
+			// Renewal information should be cached between syncs.
+			for i := 0; i < 2; i++ {
+				reason, message, reissue, ok := w.applyRenewalInfo(context.Background(), "testns/test", input)
+				if ok != test.expOK || reissue != test.expReissue || reason != test.expReason || message != test.expMessage {
+					t.Errorf("unexpected result, exp=(%q, %q, %t, %t) got=(%q, %q, %t, %t)",
+						test.expReason, test.expMessage, test.expReissue, test.expOK, reason, message, reissue, ok)
+				}
+			}
+			if calls != test.expCalls {
+				t.Errorf("unexpected number of renewal information requests, exp=%d got=%d", test.expCalls, calls)
+			}
+			if len(enqueued) != test.expCalls {
+				t.Errorf("expected the Certificate to be re-queued after each request, got %v", enqueued)
+			}
+		})
+	}
+}
+
+func Test_renewalInfoChecker_fetchesInBackground(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, feature.ACMERenewalInfo, true)()
+
+	fixedClock := fakeclock.NewFakeClock(time.Now())
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateCommonName("example.com"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "acme-issuer", Kind: "Issuer"}),
+	)
+	acmeIssuer := gen.Issuer("acme-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerACME(cmacme.ACMEIssuer{Server: "https://acme.example.com/directory"}),
+	)
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := pki.GenerateTemplate(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.AuthorityKeyId = []byte{0x01}
+	template.NotBefore = fixedClock.Now().Add(-24 * time.Hour)
+	certPEM, _, err := pki.SignCertificate(template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output"},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM},
+	}
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		Clock:              fixedClock,
+		CertManagerObjects: []runtime.Object{crt, acmeIssuer},
+		KubeObjects:        []runtime.Object{secret},
+	}
+	builder.Init()
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	enqueued := make(chan string, 1)
+	w.renewalInfo.enqueue = func(key string) { enqueued <- key }
+	w.renewalInfo.getRenewalInfo = func(context.Context, *http.Client, string, *x509.Certificate) (*acmecl.RenewalInfo, error) {
+		<-release
+		return &acmecl.RenewalInfo{
+			SuggestedWindow: acmecl.RenewalWindow{Start: fixedClock.Now().Add(-2 * time.Hour), End: fixedClock.Now().Add(-time.Hour)},
+			RetryAfter:      time.Hour,
+		}, nil
+	}
+
+	builder.Start()
+	defer builder.Stop()
+
+	input := policies.Input{Certificate: crt, Secret: secret}
+	if _, _, _, ok := w.applyRenewalInfo(context.Background(), "testns/test", input); ok {
+		t.Errorf("expected renewBefore to be used whilst renewal information is being fetched")
+	}
+
+	close(release)
+	select {
+	case key := <-enqueued:
+		if key != "testns/test" {
+			t.Errorf("unexpected key re-queued: %q", key)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the Certificate to be re-queued")
+	}
+
+	if _, _, reissue, ok := w.applyRenewalInfo(context.Background(), "testns/test", input); !ok || !reissue {
+		t.Errorf("expected the fetched renewal information to be used, got reissue=%t ok=%t", reissue, ok)
+	}
+}
+
+func Test_clampRenewalWindow(t *testing.T) {
+	notBefore := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(90 * 24 * time.Hour)}
+	window := func(start, end time.Duration) acmecl.RenewalWindow {
+		return acmecl.RenewalWindow{Start: notBefore.Add(start), End: notBefore.Add(end)}
+	}
+
+	tests := map[string]struct {
+		window, exp acmecl.RenewalWindow
+	}{
+		"window within the validity period is unchanged": {
+			window: window(60*24*time.Hour, 62*24*time.Hour),
+			exp:    window(60*24*time.Hour, 62*24*time.Hour),
+		},
+		"window ending after expiry ends at expiry": {
+			window: window(89*24*time.Hour, 100*24*time.Hour),
+			exp:    window(89*24*time.Hour, 90*24*time.Hour),
+		},
+		"window after expiry is reduced to expiry": {
+			window: window(100*24*time.Hour, 101*24*time.Hour),
+			exp:    window(90*24*time.Hour, 90*24*time.Hour),
+		},
+		"window starting before issuance starts at issuance": {
+			window: window(-time.Hour, time.Hour),
+			exp:    window(0, time.Hour),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := clampRenewalWindow(test.window, cert)
+			if !got.Start.Equal(test.exp.Start) || !got.End.Equal(test.exp.End) {
+				t.Errorf("unexpected window, exp=[%s, %s] got=[%s, %s]", test.exp.Start, test.exp.End, got.Start, got.End)
+			}
+		})
+	}
+}
+
+func Test_renewalTimeInWindow(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := acmecl.RenewalWindow{Start: start, End: start.Add(48 * time.Hour)}
+
+	first := renewalTimeInWindow("AQ.fw", window)
+	if first.Before(window.Start) || !first.Before(window.End) {
+		t.Errorf("renewal time %s not in window [%s, %s)", first, window.Start, window.End)
+	}
+	if again := renewalTimeInWindow("AQ.fw", window); !again.Equal(first) {
+		t.Errorf("expected renewal time to be stable, got %s and %s", first, again)
+	}
+	if other := renewalTimeInWindow("AQ.AQ", window); other.Equal(first) {
+		t.Errorf("expected different certificates to be spread across the window")
+	}
+}
@@ -18,20 +18,22 @@ package trigger
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/clock"
 
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
@@ -40,9 +42,15 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/secretsmanager"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/scheduler"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
 
@@ -58,26 +66,56 @@ type controller struct {
 	certificateRequestLister cmlisters.CertificateRequestLister
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
+	statusPatcher            *statuspatch.Patcher
+	fieldManager             string
 	recorder                 record.EventRecorder
 	scheduledWorkQueue       scheduler.ScheduledWorkQueue
 
+	// secretsManager is used to back-fill metadata on Secrets that are
+	// adopted by a Certificate
+	secretsManager *secretsmanager.SecretsManager
+	// queue is used to adopt a Secret again once its keystores have been
+	// encoded in the background.
+	queue workqueue.RateLimitingInterface
+
+	// renewalInfo is used to renew certificates issued by ACME issuers
+	// within the window suggested by the ACME server. It is nil unless the
+	// ACMERenewalInfo feature gate is enabled.
+	renewalInfo *renewalInfoChecker
+
+	// issuerHelper is used to detect issuers that have been recreated since
+	// the last issuance attempt, and CA issuers whose CA certificate has been
+	// rotated since the last issuance. It may be nil.
+	issuerHelper  issuer.Helper
+	issuerOptions controllerpkg.IssuerOptions
+	// caCertificates holds the decoded CA certificates of CA issuers.
+	caCertificates *caCertificateCache
+
 	// The following are used for testing purposes.
 	clock              clock.Clock
 	shouldReissue      policies.Func
+	canAdoptSecret     policies.Func
 	dataForCertificate func(context.Context, *cmapi.Certificate) (policies.Input, error)
 }
 
 func NewController(
 	log logr.Logger,
+	kubeClient kubernetes.Interface,
 	client cmclient.Interface,
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
 	clock clock.Clock,
 	shouldReissue policies.Func,
+	certificateControllerOptions controllerpkg.CertificateOptions,
+	statusPatcher *statuspatch.Patcher,
+	workqueueOptions controllerpkg.WorkqueueOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(workqueueOptions.RateLimiter(ControllerName, controllerpkg.RateLimiterOptions{
+		BaseDelay: time.Second * 1,
+		MaxDelay:  time.Second * 30,
+	}), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
@@ -110,12 +148,24 @@ func NewController(
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
+		statusPatcher:            statusPatcher,
+		fieldManager:             controllerpkg.FieldManager(ControllerName),
 		recorder:                 recorder,
 		scheduledWorkQueue:       scheduler.NewScheduledWorkQueue(clock, queue.Add),
+		caCertificates:           newCACertificateCache(),
+		secretsManager: secretsmanager.New(
+			kubeClient,
+			secretsInformer.Lister(),
+			recorder,
+			certificateControllerOptions.EnableOwnerRef,
+			certificateControllerOptions.StrictSecretOwnership,
+		).WithDeferredKeystores(),
+		queue: queue,
 
 		// The following are used for testing purposes.
-		clock:         clock,
-		shouldReissue: shouldReissue,
+		clock:          clock,
+		shouldReissue:  shouldReissue,
+		canAdoptSecret: policies.NewSecretAdoptionPolicyChain(clock).Evaluate,
 		dataForCertificate: (&policies.Gatherer{
 			CertificateRequestLister: certificateRequestInformer.Lister(),
 			SecretLister:             secretsInformer.Lister(),
@@ -147,6 +197,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		// Do nothing if an issuance is already in progress.
 		return nil
 	}
+	if crt.Spec.Revoke {
+		// Do not re-issue certificates that have been revoked on request.
+		log.V(logf.DebugLevel).Info("Not issuing certificate as revocation has been requested")
+		return nil
+	}
 
 	input, err := c.dataForCertificate(ctx, crt)
 	if err != nil {
@@ -156,6 +211,12 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	// Back off from re-issuing immediately when the certificate has been
 	// in failing mode for less than 1 hour.
 	backoff, delay := shouldBackoffReissuingOnFailure(log, c.clock, input.Certificate, input.NextRevisionRequest)
+	if backoff && c.issuerRecreatedSinceRequest(input.Certificate, input.NextRevisionRequest) {
+		// The failed attempt was made against an issuer that no longer
+		// exists, so retry straight away with the recreated issuer.
+		log.V(logf.InfoLevel).Info("Not backing off re-issuing certificate as its issuer has been recreated since the last attempt")
+		backoff = false
+	}
 	if backoff {
 		log.V(logf.InfoLevel).Info("Not re-issuing certificate as an attempt has been made in the last hour", "retry_delay", delay)
 		c.scheduleRecheckOfCertificateIfRequired(log, key, delay)
@@ -168,7 +229,28 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		c.scheduleRecheckOfCertificateIfRequired(log, key, crt.Status.RenewalTime.Time.Sub(c.clock.Now()))
 	}
 
+	// Adopt the existing Secret instead of issuing a new certificate if
+	// requested and possible.
+	if adopted, err := c.adoptSecretIfRequested(ctx, input); err != nil || adopted {
+		return err
+	}
+
 	reason, message, reissue := c.shouldReissue(input)
+
+	// The renewal window suggested by the ACME server takes precedence over
+	// renewBefore, but never over other reasons for re-issuance.
+	if c.renewalInfo != nil && (!reissue || reason == policies.Renewing) {
+		if ariReason, ariMessage, ariReissue, ok := c.applyRenewalInfo(ctx, key, input); ok {
+			reason, message, reissue = ariReason, ariMessage, ariReissue
+		}
+	}
+
+	// Re-issue certificates signed by a CA issuer whose CA certificate has
+	// been rotated so that they chain to the current CA certificate.
+	if !reissue {
+		reason, message, reissue = c.issuerCertificateRotated(ctx, input)
+	}
+
 	if !reissue {
 		// no re-issuance required, return early
 		return nil
@@ -180,9 +262,10 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	// message.
 	log.V(logf.InfoLevel).Info("Certificate must be re-issued", "reason", reason, "message", message)
 
+	oldCrt := crt
 	crt = crt.DeepCopy()
 	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, reason, message)
-	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	err = certificates.PatchStatus(ctx, c.statusPatcher, c.client, c.fieldManager, oldCrt, crt)
 	if err != nil {
 		return err
 	}
@@ -216,7 +299,7 @@ func shouldBackoffReissuingOnFailure(log logr.Logger, c clock.Clock, crt *cmapi.
 	if nextCR == nil {
 		log.V(logf.InfoLevel).Info("next CertificateRequest not available, skipping checking if Certificate matches the CertificateRequest")
 	} else {
-		mismatches, err := certificates.RequestMatchesSpec(nextCR, crt.Spec)
+		mismatches, err := pki.RequestMatchesSpec(nextCR, crt.Spec)
 		if err != nil {
 			log.V(logf.InfoLevel).Info("next CertificateRequest cannot be decoded, skipping checking if Certificate matches the CertificateRequest")
 			return false, 0
@@ -236,6 +319,29 @@ func shouldBackoffReissuingOnFailure(log logr.Logger, c clock.Clock, crt *cmapi.
 	return true, certificates.RetryAfterLastFailure - durationSinceFailure
 }
 
+// issuerRecreatedSinceRequest returns true if the cert-manager Issuer or
+// ClusterIssuer referenced by the Certificate has a different UID to the one
+// recorded on the given CertificateRequest, i.e. the issuer has been deleted
+// and recreated with the same name since the request was created.
+func (c *controller) issuerRecreatedSinceRequest(crt *cmapi.Certificate, req *cmapi.CertificateRequest) bool {
+	if c.issuerHelper == nil || req == nil {
+		return false
+	}
+	uid, ok := req.Annotations[cmapi.CertificateRequestIssuerUIDAnnotationKey]
+	if !ok {
+		return false
+	}
+	ref := crt.Spec.IssuerRef
+	if ref.Group != "" && ref.Group != cmapi.SchemeGroupVersion.Group {
+		return false
+	}
+	genericIssuer, err := c.issuerHelper.GetGenericIssuer(ref, crt.Namespace)
+	if err != nil {
+		return false
+	}
+	return string(genericIssuer.GetUID()) != uid
+}
+
 // scheduleRecheckOfCertificateIfRequired will schedule the resource with the
 // given key to be re-queued for processing after the given amount of time
 // has elapsed.
@@ -268,15 +374,69 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 	log := logf.FromContext(ctx.RootContext, ControllerName)
 
 	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
 		ctx.CMClient,
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
 		ctx.Clock,
 		policies.NewTriggerPolicyChain(ctx.Clock).Evaluate,
+		ctx.CertificateOptions,
+		ctx.StatusPatcher,
+		ctx.WorkqueueOptions,
 	)
 	c.controller = ctrl
 
+	// Read issuers so that ACME Renewal Information can be used for
+	// certificates issued by ACME issuers, and so that certificates are
+	// re-checked promptly when their issuer is recreated.
+	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
+	if err := issuerInformer.Informer().AddIndexers(cache.Indexers{caIssuerSecretIndex: caIssuerSecretIndexFunc(ctx.IssuerOptions)}); err != nil {
+		return nil, nil, err
+	}
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, ctrl.certificateLister, labels.Everything(), predicate.CertificateIssuer),
+	})
+	mustSync = append(mustSync, issuerInformer.Informer().HasSynced)
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	var clusterIssuerIndexer cache.Indexer
+	if ctx.Namespace == "" {
+		clusterIssuerInformer := ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers()
+		if err := clusterIssuerInformer.Informer().AddIndexers(cache.Indexers{caIssuerSecretIndex: caIssuerSecretIndexFunc(ctx.IssuerOptions)}); err != nil {
+			return nil, nil, err
+		}
+		clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+			WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, ctrl.certificateLister, labels.Everything(), predicate.CertificateIssuer),
+		})
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		clusterIssuerIndexer = clusterIssuerInformer.Informer().GetIndexer()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+	issuerGrantInformer := ctx.SharedInformerFactory.Policy().V1alpha1().IssuerGrants()
+	mustSync = append(mustSync, issuerGrantInformer.Informer().HasSynced)
+	ctrl.issuerHelper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister, issuerGrantInformer.Lister())
+	ctrl.issuerOptions = ctx.IssuerOptions
+
+	// When the Secret of a CA issuer changes, enqueue the Certificates
+	// referencing the issuer in case its CA certificate has been rotated.
+	ctx.KubeSharedInformerFactory.Core().V1().Secrets().Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueCertificatesForCAIssuerSecret(log, queue, ctrl.certificateLister, ctrl.caCertificates,
+			issuerInformer.Informer().GetIndexer(), clusterIssuerIndexer),
+	})
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ACMERenewalInfo) {
+		ctrl.renewalInfo = newRenewalInfoChecker(
+			ctx.RootContext,
+			ctrl.issuerHelper,
+			ctx.Clock,
+			func(key string) { queue.Add(key) },
+			func(skipTLSVerify bool) *http.Client {
+				return accounts.BuildHTTPClient(ctx.Metrics, skipTLSVerify)
+			},
+		)
+	}
+
+	ctx.RequeueOnNamespaceChange(ctx.SharedInformerFactory.Certmanager().V1().Certificates().Informer(), (&controllerpkg.QueuingEventHandler{Queue: queue}).Enqueue)
+
 	return queue, mustSync, nil
 }
 
@@ -25,10 +25,11 @@ import (
 	logtest "github.com/go-logr/logr/testing"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	coretesting "k8s.io/client-go/testing"
+	"k8s.io/apimachinery/pkg/runtime"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
@@ -94,6 +95,11 @@ func Test_controller_ProcessItem(t *testing.T) {
 				}),
 			),
 		},
+		"should do nothing if revocation of the Certificate has been requested": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateRevoke(true),
+			),
+		},
 		"should call shouldReissue with the correct cert, secret and current CR": {
 			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
 				gen.SetCertificateSecretName("secret-1"),
@@ -276,12 +282,10 @@ func Test_controller_ProcessItem(t *testing.T) {
 				expectedCert := test.existingCertificate.DeepCopy()
 				expectedCert.Status.Conditions = test.wantConditions
 				builder.ExpectedActions = append(builder.ExpectedActions,
-					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					testpkg.NewStatusPatchAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
-						"status",
-						test.existingCertificate.Namespace,
 						expectedCert,
-					)),
+					),
 				)
 			}
 			if test.wantEvent != "" {
@@ -446,3 +450,78 @@ func Test_shouldBackoffReissuingOnFailure(t *testing.T) {
 		})
 	}
 }
+
+func Test_controller_issuerRecreatedSinceRequest(t *testing.T) {
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer"}),
+	)
+	caIssuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerUID("new-uid"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"}),
+	)
+	requestWithIssuerUID := func(uid string) *cmapi.CertificateRequest {
+		return gen.CertificateRequest("test-1",
+			gen.SetCertificateRequestNamespace("testns"),
+			gen.SetCertificateRequestAnnotations(map[string]string{cmapi.CertificateRequestIssuerUIDAnnotationKey: uid}),
+		)
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		request     *cmapi.CertificateRequest
+		expected    bool
+	}{
+		"should return false if there is no next CertificateRequest": {
+			certificate: crt,
+		},
+		"should return false if the CertificateRequest has no issuer UID annotation": {
+			certificate: crt,
+			request:     gen.CertificateRequest("test-1", gen.SetCertificateRequestNamespace("testns")),
+		},
+		"should return false if the issuer UID has not changed": {
+			certificate: crt,
+			request:     requestWithIssuerUID("new-uid"),
+		},
+		"should return true if the issuer UID has changed": {
+			certificate: crt,
+			request:     requestWithIssuerUID("old-uid"),
+			expected:    true,
+		},
+		"should return false if the issuer does not exist": {
+			certificate: gen.CertificateFrom(crt,
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "missing", Kind: "Issuer"}),
+			),
+			request: requestWithIssuerUID("old-uid"),
+		},
+		"should return false for external issuers": {
+			certificate: gen.CertificateFrom(crt,
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "example.com"}),
+			),
+			request: requestWithIssuerUID("old-uid"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: []runtime.Object{test.certificate, caIssuer},
+			}
+			builder.Init()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+
+			builder.Start()
+			defer builder.Stop()
+
+			got := w.issuerRecreatedSinceRequest(test.certificate, test.request)
+			if got != test.expected {
+				t.Errorf("unexpected result, exp=%t got=%t", test.expected, got)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"crypto"
 	"crypto/x509"
 	"fmt"
+	"math/big"
 
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +31,9 @@ import (
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/record"
 
+	"github.com/jetstack/cert-manager/internal/aia"
+	"github.com/jetstack/cert-manager/internal/ct"
+	"github.com/jetstack/cert-manager/internal/serial"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
@@ -47,6 +51,8 @@ const (
 
 type templateGenerator func(*certificatesv1.CertificateSigningRequest) (*x509.Certificate, error)
 type signingFn func([]*x509.Certificate, crypto.Signer, *x509.Certificate) (pki.PEMBundle, error)
+type serialNumberFn func(context.Context, string, *cmapi.CAIssuerSerialNumber) (*big.Int, error)
+type chainCompleterFn func(context.Context, []*x509.Certificate) ([]*x509.Certificate, error)
 
 // CA is a Kubernetes CertificateSigningRequest controller, responsible for
 // signing CertificateSigningRequests that reference a cert-manager CA Issuer
@@ -59,6 +65,16 @@ type CA struct {
 
 	recorder record.EventRecorder
 
+	ctClientBuilder ct.ClientBuilder
+
+	// serialNumberFn generates serial numbers for issuers that configure a
+	// serial number policy.
+	serialNumberFn serialNumberFn
+
+	// chainCompleterFn completes the CA chain for issuers that fetch
+	// intermediate certificates. It is nil if AIA fetching is disabled.
+	chainCompleterFn chainCompleterFn
+
 	// Used for testing to get reproducible resulting certificates
 	templateGenerator templateGenerator
 	signingFn         signingFn
@@ -74,11 +90,19 @@ func init() {
 }
 
 func NewCA(ctx *controllerpkg.Context) *CA {
+	var completeChain chainCompleterFn
+	if ctx.IssuerOptions.EnableCAIssuerAIAFetching {
+		completeChain = aia.New().CompleteChain
+	}
+
 	return &CA{
 		issuerOptions:     ctx.IssuerOptions,
 		secretsLister:     ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		certClient:        ctx.Client.CertificatesV1().CertificateSigningRequests(),
 		recorder:          ctx.Recorder,
+		ctClientBuilder:   ct.New,
+		serialNumberFn:    serial.New(ctx.Client).Next,
+		chainCompleterFn:  completeChain,
 		templateGenerator: pki.GenerateTemplateFromCertificateSigningRequest,
 		signingFn:         pki.SignCSRTemplate,
 	}
@@ -124,9 +148,50 @@ func (c *CA) Sign(ctx context.Context, csr *certificatesv1.CertificateSigningReq
 		return err
 	}
 
+	// A certificate is not valid beyond the expiry of the CA that signed it,
+	// so don't claim otherwise. This matters for short-lived certificates
+	// signed by a CA which is itself a delegation certificate that is
+	// renewed from an upstream issuer.
+	if caCerts[0].NotAfter.Before(template.NotAfter) {
+		template.NotAfter = caCerts[0].NotAfter
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().CA.CRLDistributionPoints
 	template.OCSPServer = issuerObj.GetSpec().CA.OCSPServers
 
+	if policy := issuerObj.GetSpec().CA.SerialNumber; policy != nil {
+		template.SerialNumber, err = c.serialNumberFn(ctx, resourceNamespace, policy)
+		if err != nil {
+			c.recorder.Eventf(csr, corev1.EventTypeWarning, "SerialNumberError", "Error generating certificate serial number: %s", err)
+			return err
+		}
+	}
+
+	if issuerObj.GetSpec().CA.FetchIntermediateCertificates {
+		if c.chainCompleterFn == nil {
+			log.V(logf.DebugLevel).Info("not fetching intermediate certificates as AIA fetching is disabled")
+		} else {
+			caCerts, err = c.chainCompleterFn(ctx, caCerts)
+			if err != nil {
+				c.recorder.Eventf(csr, corev1.EventTypeWarning, "AIAFetchError", "Error fetching intermediate CA certificates: %s", err)
+				return err
+			}
+		}
+	}
+
+	if ctConfig := issuerObj.GetSpec().CA.CertificateTransparency; ctConfig != nil {
+		ctClient, err := c.ctClientBuilder(ctConfig)
+		if err != nil {
+			c.recorder.Eventf(csr, corev1.EventTypeWarning, "SCTHookError", "Error initializing SCT hook client: %s", err)
+			return nil
+		}
+
+		if err := ct.EmbedSCTs(ctx, ctClient, caCerts, caKey, template); err != nil {
+			c.recorder.Eventf(csr, corev1.EventTypeWarning, "SCTHookError", "Error obtaining SCTs from SCT hook: %s", err)
+			return err
+		}
+	}
+
 	bundle, err := c.signingFn(caCerts, caKey, template)
 	if err != nil {
 		message := fmt.Sprintf("Error signing certificate: %s", err)
@@ -18,6 +18,7 @@ package certificatesigningrequests
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	certificatesv1 "k8s.io/api/certificates/v1"
@@ -83,6 +84,9 @@ type Controller struct {
 
 	// used for testing
 	clock clock.Clock
+
+	// issuerOptions bounds the time taken by the signer to sign each request
+	issuerOptions controllerpkg.IssuerOptions
 }
 
 // New will construct a new certificatesigningrequest controller using the
@@ -109,7 +113,10 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	// the rate limiter is configured using the name this controller is
+	// registered under, e.g. certificatesigningrequests-issuer-ca
+	rateLimiter := ctx.WorkqueueOptions.RateLimiter(fmt.Sprintf("%s-issuer-%s", ControllerName, c.signerType), controllerpkg.DefaultRateLimiterOptions)
+	c.queue = workqueue.NewNamedRateLimitingQueue(rateLimiter, ControllerName)
 
 	c.sarClient = ctx.Client.AuthorizationV1().SubjectAccessReviews()
 
@@ -159,13 +166,16 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 		})
 	}
 
-	// create an issuer helper for reading generic issuers
-	c.helper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerInformer.Lister())
+	// create an issuer helper for reading generic issuers. Signer names
+	// always reference issuers in their own namespace, so IssuerGrants are
+	// never consulted.
+	c.helper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerInformer.Lister(), nil)
 
 	c.clock = ctx.Clock
 	// recorder records events about resources to the Kubernetes api
 	c.recorder = ctx.Recorder
 	c.certClient = ctx.Client.CertificatesV1().CertificateSigningRequests()
+	c.issuerOptions = ctx.IssuerOptions
 
 	c.log.V(logf.DebugLevel).Info("new certificate signing request controller registered",
 		"type", c.signerType)
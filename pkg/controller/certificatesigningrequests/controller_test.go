@@ -18,7 +18,9 @@ package certificatesigningrequests
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
+	"net/url"
 	"testing"
 	"time"
 
@@ -70,6 +72,15 @@ func TestController(t *testing.T) {
 		}
 	}
 
+	csrWildcardPEM, _, err := gen.CSR(x509.RSA, gen.SetCSRDNSNames("*.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrSPIFFEPEM, _, err := gen.CSR(x509.RSA, gen.SetCSRURIs(&url.URL{Scheme: "spiffe", Host: "example.org", Path: "/ns/default/sa/default"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := map[string]struct {
 		// key that should be passed to ProcessItem. If not set, the
 		// 'namespace/name' of the 'CertificateSigningRequest' field will be used.
@@ -438,6 +449,128 @@ func TestController(t *testing.T) {
 				}),
 			),
 		},
+		"if CertificateSigningRequest requests a wildcard DNS name but the Issuer does not allow wildcards, should update Failed": {
+			signerType: apiutil.IssuerCA,
+			existingCSR: gen.CertificateSigningRequest("csr-1",
+				gen.SetCertificateSigningRequestSignerName("issuers.cert-manager.io/hello.world"),
+				gen.SetCertificateSigningRequestRequest(csrWildcardPEM),
+				gen.SetCertificateSigningRequestUsername("user-1"),
+				gen.SetCertificateSigningRequestStatusCondition(certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateApproved,
+					Status:  corev1.ConditionTrue,
+					Reason:  "ApprovedReason",
+					Message: "Approved message",
+				}),
+			),
+			signerImpl:  signerExpectNoCall,
+			sarReaction: sarReactionAllow,
+			wantSARCreation: []*authzv1.SubjectAccessReview{
+				{
+					Spec: authzv1.SubjectAccessReviewSpec{
+						User:  "user-1",
+						Extra: map[string]authzv1.ExtraValue{},
+						ResourceAttributes: &authzv1.ResourceAttributes{
+							Group:     "cert-manager.io",
+							Resource:  "signers",
+							Verb:      "reference",
+							Namespace: "hello",
+							Name:      "world",
+							Version:   "*",
+						},
+					},
+				},
+			},
+			existingIssuer: gen.Issuer("world", gen.SetIssuerNamespace("hello"),
+				gen.SetIssuerCA(cmapi.CAIssuer{
+					SecretName: "tls",
+				}),
+				gen.SetIssuerAllowWildcards(false),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{
+					Type:    cmapi.IssuerConditionReady,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  "IssuerReady",
+					Message: "Issuer ready message",
+				}),
+			),
+			wantEvent: "Warning WildcardNotAllowed Referenced Issuer hello/world does not allow wildcard certificates: *.example.com",
+			wantConditions: []certificatesv1.CertificateSigningRequestCondition{
+				{
+					Type:    certificatesv1.CertificateApproved,
+					Status:  corev1.ConditionTrue,
+					Reason:  "ApprovedReason",
+					Message: "Approved message",
+				},
+				{
+					Type:               certificatesv1.CertificateFailed,
+					Status:             corev1.ConditionTrue,
+					Reason:             "WildcardNotAllowed",
+					Message:            "Referenced Issuer hello/world does not allow wildcard certificates: *.example.com",
+					LastTransitionTime: metaFixedClockStart,
+					LastUpdateTime:     metaFixedClockStart,
+				},
+			},
+		},
+		"if CertificateSigningRequest requests a SPIFFE ID in a trust domain the Issuer does not allow, should update Failed": {
+			signerType: apiutil.IssuerCA,
+			existingCSR: gen.CertificateSigningRequest("csr-1",
+				gen.SetCertificateSigningRequestSignerName("issuers.cert-manager.io/hello.world"),
+				gen.SetCertificateSigningRequestRequest(csrSPIFFEPEM),
+				gen.SetCertificateSigningRequestUsername("user-1"),
+				gen.SetCertificateSigningRequestStatusCondition(certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateApproved,
+					Status:  corev1.ConditionTrue,
+					Reason:  "ApprovedReason",
+					Message: "Approved message",
+				}),
+			),
+			signerImpl:  signerExpectNoCall,
+			sarReaction: sarReactionAllow,
+			wantSARCreation: []*authzv1.SubjectAccessReview{
+				{
+					Spec: authzv1.SubjectAccessReviewSpec{
+						User:  "user-1",
+						Extra: map[string]authzv1.ExtraValue{},
+						ResourceAttributes: &authzv1.ResourceAttributes{
+							Group:     "cert-manager.io",
+							Resource:  "signers",
+							Verb:      "reference",
+							Namespace: "hello",
+							Name:      "world",
+							Version:   "*",
+						},
+					},
+				},
+			},
+			existingIssuer: gen.Issuer("world", gen.SetIssuerNamespace("hello"),
+				gen.SetIssuerCA(cmapi.CAIssuer{
+					SecretName: "tls",
+				}),
+				gen.SetIssuerSPIFFETrustDomains("cluster.local"),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{
+					Type:    cmapi.IssuerConditionReady,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  "IssuerReady",
+					Message: "Issuer ready message",
+				}),
+			),
+			wantEvent: "Warning SPIFFEIDNotAllowed Referenced Issuer hello/world does not allow the requested SPIFFE IDs: SPIFFE ID \"spiffe://example.org/ns/default/sa/default\" is not in an allowed trust domain",
+			wantConditions: []certificatesv1.CertificateSigningRequestCondition{
+				{
+					Type:    certificatesv1.CertificateApproved,
+					Status:  corev1.ConditionTrue,
+					Reason:  "ApprovedReason",
+					Message: "Approved message",
+				},
+				{
+					Type:               certificatesv1.CertificateFailed,
+					Status:             corev1.ConditionTrue,
+					Reason:             "SPIFFEIDNotAllowed",
+					Message:            "Referenced Issuer hello/world does not allow the requested SPIFFE IDs: SPIFFE ID \"spiffe://example.org/ns/default/sa/default\" is not in an allowed trust domain",
+					LastTransitionTime: metaFixedClockStart,
+					LastUpdateTime:     metaFixedClockStart,
+				},
+			},
+		},
 		"if CertificateSigningRequest called invoked sign but it errors, should return error": {
 			signerType: apiutil.IssuerCA,
 			existingCSR: gen.CertificateSigningRequest("csr-1",
@@ -144,6 +144,15 @@ func (s *SelfSigned) Sign(ctx context.Context, csr *certificatesv1.CertificateSi
 		return err
 	}
 
+	if err := applyRequestedParameters(template, csr); err != nil {
+		message := fmt.Sprintf("Error applying requested certificate parameters: %s", err)
+		log.Error(err, message)
+		s.recorder.Event(csr, corev1.EventTypeWarning, "ErrorGenerating", message)
+		util.CertificateSigningRequestSetFailed(csr, "ErrorGenerating", message)
+		_, err = s.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
+		return err
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().SelfSigned.CRLDistributionPoints
 
 	// extract the public component of the key
@@ -193,3 +202,21 @@ func (s *SelfSigned) Sign(ctx context.Context, csr *certificatesv1.CertificateSi
 
 	return nil
 }
+
+// applyRequestedParameters updates the template with the usages and CA
+// constraints requested by the CSR and its annotations. Since the requester
+// holds the signing key of a self-signed certificate, these are honoured in
+// full.
+func applyRequestedParameters(template *x509.Certificate, csr *certificatesv1.CertificateSigningRequest) error {
+	if err := pki.ApplyCSRRequestedExtensions(template, csr.Spec.Request); err != nil {
+		return err
+	}
+
+	if maxPathLen, ok := csr.Annotations[experimentalapi.CertificateSigningRequestMaxPathLenAnnotationKey]; ok {
+		if err := pki.ApplyMaxPathLen(template, maxPathLen); err != nil {
+			return fmt.Errorf("annotation %q: %w", experimentalapi.CertificateSigningRequestMaxPathLenAnnotationKey, err)
+		}
+	}
+
+	return nil
+}
@@ -742,6 +742,23 @@ func TestSign(t *testing.T) {
 				assert.Equal(t, true, got.IsCA)
 			},
 		},
+		"when the CertificateSigningRequest has the maxPathLen annotation set, it should appear on the signed certificate": {
+			csr: gen.CertificateSigningRequest("csr-1",
+				gen.AddCertificateSigningRequestAnnotations(map[string]string{
+					"experimental.cert-manager.io/private-key-secret-name": "test-secret",
+					"experimental.cert-manager.io/request-max-path-len":    "0",
+				}),
+				gen.SetCertificateSigningRequestSignerName("issuers.cert-manager.io/default-unit-test-ns.issuer-1"),
+				gen.SetCertificateSigningRequestRequest(csrBundle.csrPEM),
+				gen.SetCertificateSigningRequestIsCA(true),
+			),
+			issuer: baseIssuer,
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, true, got.IsCA)
+				assert.Equal(t, 0, got.MaxPathLen)
+				assert.Equal(t, true, got.MaxPathLenZero)
+			},
+		},
 		"when the Issuer has crlDistributionPoints set, it should appear on the signed ca ": {
 			csr: gen.CertificateSigningRequest("cr-1",
 				gen.AddCertificateSigningRequestAnnotations(map[string]string{
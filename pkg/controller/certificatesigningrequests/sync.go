@@ -19,6 +19,7 @@ package certificatesigningrequests
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	authzv1 "k8s.io/api/authorization/v1"
 	certificatesv1 "k8s.io/api/certificates/v1"
@@ -30,8 +31,10 @@ import (
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests/util"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 func (c *Controller) Sync(ctx context.Context, csr *certificatesv1.CertificateSigningRequest) error {
@@ -128,6 +131,12 @@ func (c *Controller) Sync(ctx context.Context, csr *certificatesv1.CertificateSi
 	}
 
 	// check ready condition
+	if err := c.issuerOptions.CheckIssuerType(issuerObj); err != nil {
+		c.recorder.Eventf(csr, corev1.EventTypeWarning, controllerpkg.ReasonIssuerTypeNotAllowed, "Referenced %s %s/%s cannot be used: %s",
+			kind, issuerObj.GetNamespace(), issuerObj.GetName(), err)
+		return nil
+	}
+
 	if !apiutil.IssuerHasCondition(issuerObj, cmapi.IssuerCondition{
 		Type:   cmapi.IssuerConditionReady,
 		Status: cmmeta.ConditionTrue,
@@ -137,8 +146,39 @@ func (c *Controller) Sync(ctx context.Context, csr *certificatesv1.CertificateSi
 		return nil
 	}
 
+	if !apiutil.IssuerAllowsWildcards(issuerObj) {
+		// Requests that cannot be decoded are left for the signer to reject.
+		if req, err := pki.DecodeX509CertificateRequestBytes(csr.Spec.Request); err == nil {
+			if wildcards := apiutil.WildcardDNSNames(append([]string{req.Subject.CommonName}, req.DNSNames...)); len(wildcards) > 0 {
+				message := fmt.Sprintf("Referenced %s %s/%s does not allow wildcard certificates: %s",
+					kind, issuerObj.GetNamespace(), issuerObj.GetName(), strings.Join(wildcards, ", "))
+				c.recorder.Event(csr, corev1.EventTypeWarning, "WildcardNotAllowed", message)
+				util.CertificateSigningRequestSetFailed(csr, "WildcardNotAllowed", message)
+				_, err := c.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
+				return err
+			}
+		}
+	}
+
+	if issuerObj.GetSpec().SPIFFE != nil {
+		// Requests that cannot be decoded are left for the signer to reject.
+		if req, err := pki.DecodeX509CertificateRequestBytes(csr.Spec.Request); err == nil {
+			if violations := apiutil.SPIFFEPolicyViolations(issuerObj, req.URIs); len(violations) > 0 {
+				message := fmt.Sprintf("Referenced %s %s/%s does not allow the requested SPIFFE IDs: %s",
+					kind, issuerObj.GetNamespace(), issuerObj.GetName(), strings.Join(violations, "; "))
+				c.recorder.Event(csr, corev1.EventTypeWarning, "SPIFFEIDNotAllowed", message)
+				util.CertificateSigningRequestSetFailed(csr, "SPIFFEIDNotAllowed", message)
+				_, err := c.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
+				return err
+			}
+		}
+	}
+
 	dbg.Info("invoking sign function as existing certificate does not exist")
 
+	ctx, cancel := c.issuerOptions.SignContext(ctx)
+	defer cancel()
+
 	return c.signer.Sign(ctx, csr, issuerObj)
 }
 
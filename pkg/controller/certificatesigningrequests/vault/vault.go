@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/x509"
+	"errors"
 	"fmt"
 
 	certificatesv1 "k8s.io/api/certificates/v1"
@@ -33,6 +34,7 @@ import (
 	internalvault "github.com/jetstack/cert-manager/internal/vault"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	experimentalapi "github.com/jetstack/cert-manager/pkg/apis/experimental/v1alpha1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests"
 	"github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests/util"
@@ -50,6 +52,7 @@ type signingFn func(*x509.Certificate, *x509.Certificate, crypto.PublicKey, inte
 // using Vault Issuers.
 type Vault struct {
 	issuerOptions controllerpkg.IssuerOptions
+	createTokenFn func(ns string) internalvault.CreateToken
 	secretsLister corelisters.SecretLister
 
 	recorder record.EventRecorder
@@ -69,6 +72,9 @@ func init() {
 func NewVault(ctx *controllerpkg.Context) *Vault {
 	return &Vault{
 		issuerOptions: ctx.IssuerOptions,
+		createTokenFn: func(ns string) internalvault.CreateToken {
+			return ctx.Client.CoreV1().ServiceAccounts(ns).CreateToken
+		},
 		secretsLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		recorder:      ctx.Recorder,
 		certClient:    ctx.Client.CertificatesV1().CertificateSigningRequests(),
@@ -84,9 +90,19 @@ func (v *Vault) Sign(ctx context.Context, csr *certificatesv1.CertificateSigning
 	log := logf.FromContext(ctx, "sign")
 	log = logf.WithRelatedResource(log, issuerObj)
 
+	vaultNamespace, err := internalvault.RequestedNamespace(issuerObj.GetSpec().Vault, csr.Annotations[experimentalapi.CertificateSigningRequestVaultNamespaceAnnotationKey])
+	if err != nil {
+		message := fmt.Sprintf("Requested Vault namespace is not allowed: %s", err)
+		log.Error(err, message)
+		v.recorder.Event(csr, corev1.EventTypeWarning, "VaultNamespaceNotAllowed", message)
+		util.CertificateSigningRequestSetFailed(csr, "VaultNamespaceNotAllowed", message)
+		_, err := v.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
+		return err
+	}
+
 	resourceNamespace := v.issuerOptions.ResourceNamespace(issuerObj)
 
-	client, err := v.clientBuilder(resourceNamespace, v.secretsLister, issuerObj)
+	client, err := v.clientBuilder(ctx, resourceNamespace, v.createTokenFn, v.secretsLister, issuerObj)
 	if apierrors.IsNotFound(err) {
 		message := "Required secret resource not found"
 		log.Error(err, message)
@@ -113,7 +129,16 @@ func (v *Vault) Sign(ctx context.Context, csr *certificatesv1.CertificateSigning
 		return err
 	}
 
-	certPEM, _, err := client.Sign(csr.Spec.Request, duration)
+	certPEM, _, err := client.Sign(ctx, csr.Spec.Request, duration, vaultNamespace)
+	var policyErr *internalvault.PolicyError
+	if errors.As(err, &policyErr) {
+		message := fmt.Sprintf("Vault refused to sign: %s", err)
+		log.Error(err, message)
+		v.recorder.Event(csr, corev1.EventTypeWarning, "VaultPolicyViolation", message)
+		util.CertificateSigningRequestSetFailed(csr, "VaultPolicyViolation", message)
+		_, err := v.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
+		return err
+	}
 	if err != nil {
 		message := fmt.Sprintf("Vault failed to sign: %s", err)
 		log.Error(err, message)
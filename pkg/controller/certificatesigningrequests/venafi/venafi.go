@@ -73,7 +73,7 @@ func NewVenafi(ctx *controllerpkg.Context) *Venafi {
 		secretsLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		certClient:    ctx.Client.CertificatesV1().CertificateSigningRequests(),
 		recorder:      ctx.Recorder,
-		clientBuilder: venaficlient.New,
+		clientBuilder: venaficlient.NewBuilder(ctx.Client.CoreV1()),
 	}
 }
 
@@ -135,7 +135,7 @@ func (v *Venafi) Sign(ctx context.Context, csr *certificatesv1.CertificateSignin
 
 	// check if the pickup ID annotation is there, if not set it up.
 	if len(pickupID) == 0 {
-		pickupID, err := client.RequestCertificate(csr.Spec.Request, duration, customFields)
+		pickupID, err := client.RequestCertificate(ctx, csr.Spec.Request, duration, customFields)
 		// Check some known error types
 		if err != nil {
 			switch err.(type) {
@@ -147,6 +147,13 @@ func (v *Venafi) Sign(ctx context.Context, csr *certificatesv1.CertificateSignin
 				_, userr := v.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
 				return userr
 
+			case venaficlient.ErrPolicyViolation:
+				log.Error(err, "")
+				v.recorder.Event(csr, corev1.EventTypeWarning, "ErrorPolicyViolation", err.Error())
+				util.CertificateSigningRequestSetFailed(csr, "ErrorPolicyViolation", err.Error())
+				_, userr := v.certClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{})
+				return userr
+
 			default:
 				message := fmt.Sprintf("Failed to request venafi certificate: %s", err)
 				log.Error(err, message)
@@ -165,7 +172,7 @@ func (v *Venafi) Sign(ctx context.Context, csr *certificatesv1.CertificateSignin
 		return uerr
 	}
 
-	certPem, err := client.RetrieveCertificate(pickupID, csr.Spec.Request, duration, customFields)
+	certPem, err := client.RetrieveCertificate(ctx, pickupID, csr.Spec.Request, duration, customFields)
 	if err != nil {
 		switch err.(type) {
 		case endpoint.ErrCertificatePending:
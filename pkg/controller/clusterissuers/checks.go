@@ -49,7 +49,7 @@ func (c *controller) issuersForSecret(secret *corev1.Secret) ([]*v1.ClusterIssue
 				}
 			}
 		case iss.Spec.CA != nil:
-			if iss.Spec.CA.SecretName == secret.Name {
+			if iss.Spec.CA.SecretName == secret.Name || iss.Spec.CA.NextSecretName == secret.Name {
 				affected = append(affected, iss)
 				continue
 			}
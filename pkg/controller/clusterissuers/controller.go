@@ -30,6 +30,7 @@ import (
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/sharding"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
@@ -58,6 +59,14 @@ type controller struct {
 	// clusterResourceNamespace is the namespace used to store resources
 	// referenced by ClusterIssuer resources, e.g. acme account secrets
 	clusterResourceNamespace string
+
+	// issuerOptions bounds the time taken to set up each issuer
+	issuerOptions controllerpkg.IssuerOptions
+
+	// shard is the shard of this replica, if sharding is enabled. Every
+	// replica sets up each ClusterIssuer, but only the replica owning the
+	// ClusterIssuer updates its status.
+	shard *sharding.Shard
 }
 
 // Register registers and constructs the controller using the provided context.
@@ -68,7 +77,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	c.queue = workqueue.NewNamedRateLimitingQueue(ctx.WorkqueueOptions.RateLimiter(ControllerName, controllerpkg.DefaultRateLimiterOptions), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	clusterIssuerInformer := ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers()
@@ -93,6 +102,8 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.cmClient = ctx.CMClient
 	c.recorder = ctx.Recorder
 	c.clusterResourceNamespace = ctx.IssuerOptions.ClusterResourceNamespace
+	c.issuerOptions = ctx.IssuerOptions
+	c.shard = ctx.Shard
 
 	return c.queue, mustSync, nil
 }
@@ -160,6 +171,7 @@ func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
 			For(&controller{}).
+			ProcessAllShards().
 			Complete()
 	})
 }
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"net/url"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -27,12 +28,16 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
-	gwclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
-	gwinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+	gwclient "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
+	gwinformers "sigs.k8s.io/gateway-api/pkg/client/informers/gateway/externalversions"
 
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	"github.com/jetstack/cert-manager/pkg/controller/sharding"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
@@ -77,6 +82,11 @@ type Context struct {
 	// If unset, operates on all namespaces
 	Namespace string
 
+	// NamespaceScope restricts the namespaces whose resources are processed
+	// when more than one namespace, but not all, are watched. If nil, the
+	// resources in all namespaces watched by the informers are processed.
+	NamespaceScope *NamespaceScope
+
 	// Clock should be used to access the current time instead of relying on
 	// time.Now, to make it easier to test controllers that utilise time
 	Clock clock.Clock
@@ -84,11 +94,21 @@ type Context struct {
 	// Metrics is used for exposing Prometheus metrics across the controllers
 	Metrics *metrics.Metrics
 
+	// StatusPatcher is used to send changes to the status of resources to the
+	// apiserver. It is shared by all controllers to limit the overall rate of
+	// status writes.
+	StatusPatcher *statuspatch.Patcher
+
+	// Shard is the part of the cluster's resources that this replica
+	// processes. If nil, all resources are processed.
+	Shard *sharding.Shard
+
 	IssuerOptions
 	ACMEOptions
 	IngressShimOptions
 	CertificateOptions
 	SchedulerOptions
+	WorkqueueOptions
 }
 
 type IssuerOptions struct {
@@ -104,6 +124,24 @@ type IssuerOptions struct {
 	// IssuerAmbientCredentials controls whether an issuer should pick up ambient
 	// credentials, such as those from metadata services, to construct clients.
 	IssuerAmbientCredentials bool
+
+	// SetupTimeout is the maximum time an issuer may take to set itself up,
+	// e.g. to register an ACME account or to verify its connection to Vault.
+	// Zero means no timeout.
+	SetupTimeout time.Duration
+
+	// SignTimeout is the maximum time an issuer may take to sign a single
+	// CertificateRequest or CertificateSigningRequest, including any calls to
+	// upstream signing services. Zero means no timeout.
+	SignTimeout time.Duration
+
+	// EnableCAIssuerAIAFetching controls whether CA issuers may fetch missing
+	// CA certificates of their chain from AIA CA Issuers URLs.
+	EnableCAIssuerAIAFetching bool
+
+	// IssuerTypes restricts the types of Issuers and ClusterIssuers that may
+	// be set up and used to sign requests.
+	IssuerTypes apiutil.IssuerTypePolicy
 }
 
 type ACMEOptions struct {
@@ -137,8 +175,37 @@ type ACMEOptions struct {
 
 	// DNS01CheckRetryPeriod is the time the controller should wait between checking if a ACME dns entry exists.
 	DNS01CheckRetryPeriod time.Duration
+
+	// DNS01CleanupDryRunProviders are the names of the DNS01 providers whose
+	// challenge records are logged rather than deleted when a challenge is
+	// cleaned up.
+	DNS01CleanupDryRunProviders []string
+
+	// HTTP01SelfCheckMode controls where ACME HTTP01 self-check requests are
+	// sent to.
+	HTTP01SelfCheckMode HTTP01SelfCheckMode
+
+	// HTTP01SelfCheckProxy is the URL of an HTTP proxy that external ACME
+	// HTTP01 self-check requests are sent through. If nil, the proxy
+	// configured in the environment is used.
+	HTTP01SelfCheckProxy *url.URL
 }
 
+// HTTP01SelfCheckMode controls how the ACME HTTP01 self-check verifies that a
+// challenge is being presented before it is accepted.
+type HTTP01SelfCheckMode string
+
+const (
+	// HTTP01SelfCheckExternal sends self-check requests to the challenge's
+	// domain, the same way the ACME server will.
+	HTTP01SelfCheckExternal HTTP01SelfCheckMode = "external"
+
+	// HTTP01SelfCheckLocal sends self-check requests directly to the solver
+	// pod. This only verifies that the solver is answering, and is intended
+	// for clusters that cannot reach their own public addresses.
+	HTTP01SelfCheckLocal HTTP01SelfCheckMode = "local"
+)
+
 // IngressShimOptions contain default Issuer GVK config for the certificate-shim controllers.
 // These are set from the cmd cli flags, allowing the controllers to support legacy annotations
 // such as `kubernetes.io/tls-acme`.
@@ -147,15 +214,38 @@ type IngressShimOptions struct {
 	DefaultIssuerKind                 string
 	DefaultIssuerGroup                string
 	DefaultAutoCertificateAnnotations []string
+	// IngressClassDefaultIssuers maps IngressClass names to the issuer used
+	// for Ingresses of that class, in place of DefaultIssuerName,
+	// DefaultIssuerKind and DefaultIssuerGroup.
+	IngressClassDefaultIssuers map[string]cmmeta.ObjectReference
+	// DefaultSubjectOrganizations and DefaultSubjectOrganizationalUnits are
+	// templates evaluated against the namespace of an ingress-like object to
+	// set the subject of the Certificates created for it, unless overridden
+	// by annotations on the object itself.
+	DefaultSubjectOrganizations       []string
+	DefaultSubjectOrganizationalUnits []string
 }
 
 type CertificateOptions struct {
 	// EnableOwnerRef controls whether the certificate is configured as an owner of
 	// secret where the effective TLS certificate is stored.
 	EnableOwnerRef bool
+	// StrictSecretOwnership controls whether data that is not written by
+	// cert-manager is pruned from the secret where the effective TLS
+	// certificate is stored, and whether the labels and annotations of the
+	// certificate's secretTemplate are kept in sync with the secret.
+	StrictSecretOwnership bool
 	// CopiedAnnotationPrefixes defines which annotations should be copied
 	// Certificate -> CertificateRequest, CertificateRequest -> Order.
 	CopiedAnnotationPrefixes []string
+	// SecretHashAnnotation is the Pod template annotation that the secret
+	// update notifier records the hash of the certificates referenced by a
+	// workload in.
+	SecretHashAnnotation string
+	// ExternalKeySignerURLs are the signer service URLs that Certificates
+	// may use external private keys from. External private keys are
+	// disabled if it is empty.
+	ExternalKeySignerURLs []string
 }
 
 type SchedulerOptions struct {
@@ -163,3 +253,28 @@ type SchedulerOptions struct {
 	// scheduled as 'processing' at once.
 	MaxConcurrentChallenges int
 }
+
+type WorkqueueOptions struct {
+	// RateLimiters contains overrides for the exponential backoff applied by
+	// controller workqueues, keyed by controller name. The key "*" applies to
+	// all controllers, and is itself overridden by controller specific
+	// entries.
+	RateLimiters map[string]RateLimiterOptions
+}
+
+// RateLimiterOptions configures the per-item exponential backoff applied by a
+// controller's workqueue when an item fails to sync. Fields left as their zero
+// value fall back to the controller's defaults.
+type RateLimiterOptions struct {
+	// BaseDelay is the delay before an item is retried for the first time.
+	// The delay doubles on each subsequent failure.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between retries of an item.
+	MaxDelay time.Duration
+
+	// Jitter is the maximum fraction of the computed delay that is randomly
+	// added to it, e.g. 0.1 adds up to 10%. Jitter prevents many failing
+	// items from being retried at the same time.
+	Jitter float64
+}
@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/client-go/tools/cache"
@@ -149,13 +150,16 @@ func (c *controller) worker(ctx context.Context) {
 			if key, ok = obj.(string); !ok {
 				return
 			}
-			log := log.WithValues("key", key)
+			// Every log line written while syncing the item is tagged with
+			// an ID unique to this sync.
+			reconcileLog := log.WithValues(logf.ReconcileIDKey, string(uuid.NewUUID()))
+			log := reconcileLog.WithValues("key", key)
 			log.V(logf.DebugLevel).Info("syncing item")
 
 			// Increase sync count for this controller
 			c.metrics.IncrementSyncCallCount(c.name)
 
-			err := c.syncHandler(ctx, key)
+			err := c.syncHandler(logf.NewContext(ctx, reconcileLog), key)
 			if err != nil {
 				if strings.Contains(err.Error(), genericregistry.OptimisticLockErrorMsg) {
 					log.Info("re-queuing item due to optimistic locking on resource", "error", err.Error())
@@ -17,9 +17,19 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 )
 
+// ReasonIssuerTypeNotAllowed is the reason set on the conditions of issuers,
+// and the requests referencing them, whose type is not allowed by the
+// IssuerTypes policy.
+const ReasonIssuerTypeNotAllowed = "IssuerTypeNotAllowed"
+
 // ResourceNamespace returns the Kubernetes namespace where resources
 // created or read by `iss` are located.
 func (o IssuerOptions) ResourceNamespace(iss cmapi.GenericIssuer) string {
@@ -41,3 +51,36 @@ func (o IssuerOptions) CanUseAmbientCredentials(iss cmapi.GenericIssuer) bool {
 	}
 	return false
 }
+
+// CheckIssuerType returns an error if the type of `iss` is not allowed by the
+// IssuerTypes policy. Issuers without a type are left for the issuer factory
+// to reject.
+func (o IssuerOptions) CheckIssuerType(iss cmapi.GenericIssuer) error {
+	issuerType, err := apiutil.NameForIssuer(iss)
+	if err != nil {
+		return nil
+	}
+	if !o.IssuerTypes.Allows(issuerType) {
+		return fmt.Errorf("issuer type %q is not allowed in this cluster", issuerType)
+	}
+	return nil
+}
+
+// SetupContext returns a context for setting up an issuer, bounded by the
+// SetupTimeout.
+func (o IssuerOptions) SetupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withOptionalTimeout(ctx, o.SetupTimeout)
+}
+
+// SignContext returns a context for signing a single request with an issuer,
+// bounded by the SignTimeout.
+func (o IssuerOptions) SignContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withOptionalTimeout(ctx, o.SignTimeout)
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestIssuerOptionsSignContext(t *testing.T) {
+	tests := map[string]struct {
+		timeout     time.Duration
+		expDeadline bool
+	}{
+		"a zero timeout should not set a deadline": {
+			timeout: 0,
+		},
+		"a timeout should set a deadline": {
+			timeout:     time.Minute,
+			expDeadline: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := IssuerOptions{SignTimeout: test.timeout}.SignContext(context.Background())
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if ok != test.expDeadline {
+				t.Fatalf("unexpected deadline, exp=%t got=%t", test.expDeadline, ok)
+			}
+			if ok && time.Until(deadline) > test.timeout {
+				t.Errorf("deadline %s is further away than the timeout %s", deadline, test.timeout)
+			}
+
+			cancel()
+			if ctx.Err() != context.Canceled {
+				t.Errorf("expected the context to be cancelled, got=%v", ctx.Err())
+			}
+		})
+	}
+}
+
+func TestIssuerOptionsCheckIssuerType(t *testing.T) {
+	selfSigned := &cmapi.ClusterIssuer{Spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{
+		SelfSigned: &cmapi.SelfSignedIssuer{},
+	}}}
+	ca := &cmapi.Issuer{Spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{
+		CA: &cmapi.CAIssuer{},
+	}}}
+
+	tests := map[string]struct {
+		policy apiutil.IssuerTypePolicy
+		issuer cmapi.GenericIssuer
+		expErr bool
+	}{
+		"an empty policy should allow all issuers": {
+			issuer: selfSigned,
+		},
+		"a denied issuer type should be rejected": {
+			policy: apiutil.IssuerTypePolicy{Denied: []string{apiutil.IssuerSelfSigned}},
+			issuer: selfSigned,
+			expErr: true,
+		},
+		"an allowed issuer type should be accepted": {
+			policy: apiutil.IssuerTypePolicy{Allowed: []string{apiutil.IssuerCA}},
+			issuer: ca,
+		},
+		"an issuer type missing from the allowed types should be rejected": {
+			policy: apiutil.IssuerTypePolicy{Allowed: []string{apiutil.IssuerCA}},
+			issuer: selfSigned,
+			expErr: true,
+		},
+		"an issuer without a type should be left to the issuer factory": {
+			policy: apiutil.IssuerTypePolicy{Allowed: []string{apiutil.IssuerCA}},
+			issuer: &cmapi.Issuer{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := IssuerOptions{IssuerTypes: test.policy}.CheckIssuerType(test.issuer)
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
@@ -18,14 +18,16 @@ package issuers
 
 import (
 	"context"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
 
@@ -38,8 +40,7 @@ const (
 func (c *controller) Sync(ctx context.Context, iss *cmapi.Issuer) (err error) {
 	log := logf.FromContext(ctx)
 
-	// allow a maximum of 10s
-	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	ctx, cancel := c.issuerOptions.SetupContext(ctx)
 	defer cancel()
 
 	issuerCopy := iss.DeepCopy()
@@ -49,6 +50,13 @@ func (c *controller) Sync(ctx context.Context, iss *cmapi.Issuer) (err error) {
 		}
 	}()
 
+	if err := c.issuerOptions.CheckIssuerType(issuerCopy); err != nil {
+		log.V(logf.WarnLevel).Info(err.Error())
+		apiutil.SetIssuerCondition(issuerCopy, issuerCopy.Generation, cmapi.IssuerConditionReady, cmmeta.ConditionFalse, controllerpkg.ReasonIssuerTypeNotAllowed, err.Error())
+		c.recorder.Event(issuerCopy, corev1.EventTypeWarning, controllerpkg.ReasonIssuerTypeNotAllowed, err.Error())
+		return nil
+	}
+
 	i, err := c.issuerFactory.IssuerFor(issuerCopy)
 	if err != nil {
 		return err
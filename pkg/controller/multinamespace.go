@@ -0,0 +1,429 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwclient "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
+	gwinformers "sigs.k8s.io/gateway-api/pkg/client/informers/gateway/externalversions"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	policyapi "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
+	revocationapi "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+)
+
+// NewMultiNamespaceKubeInformerFactory returns a SharedInformerFactory whose
+// informers for the namespaced resources read by cert-manager only watch the
+// given namespaces, using one informer per namespace, so that resources in
+// other namespaces are not held in memory.
+// If filterSecretsByLabel is true, Secrets are filtered as in
+// NewSecretFilteringInformerFactory.
+func NewMultiNamespaceKubeInformerFactory(client kubernetes.Interface, resync time.Duration, namespaces []string, filterSecretsByLabel bool) kubeinformers.SharedInformerFactory {
+	factories := make(map[string]kubeinformers.SharedInformerFactory, len(namespaces))
+	for _, namespace := range namespaces {
+		if filterSecretsByLabel {
+			factories[namespace] = NewSecretFilteringInformerFactory(client, resync, namespace)
+		} else {
+			factories[namespace] = kubeinformers.NewSharedInformerFactoryWithOptions(client, resync, kubeinformers.WithNamespace(namespace))
+		}
+	}
+
+	factory := kubeinformers.NewSharedInformerFactory(client, resync)
+	register := func(obj runtime.Object, informer func(kubeinformers.SharedInformerFactory) cache.SharedIndexInformer) {
+		factory.InformerFor(obj, func(kubernetes.Interface, time.Duration) cache.SharedIndexInformer {
+			informers := make(map[string]cache.SharedIndexInformer, len(factories))
+			for namespace, f := range factories {
+				informers[namespace] = informer(f)
+			}
+			return NewMultiNamespaceInformer(informers)
+		})
+	}
+	register(&corev1.Secret{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Secrets().Informer()
+	})
+	register(&corev1.ConfigMap{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().ConfigMaps().Informer()
+	})
+	register(&corev1.Pod{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Pods().Informer()
+	})
+	register(&corev1.Service{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Services().Informer()
+	})
+	register(&networkingv1.Ingress{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Networking().V1().Ingresses().Informer()
+	})
+	register(&networkingv1beta1.Ingress{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Networking().V1beta1().Ingresses().Informer()
+	})
+	register(&appsv1.Deployment{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().Deployments().Informer()
+	})
+	register(&appsv1.StatefulSet{}, func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
+	})
+
+	if filterSecretsByLabel {
+		return &secretFilteringInformerFactory{SharedInformerFactory: factory, client: client}
+	}
+	return factory
+}
+
+// NewMultiNamespaceInformerFactory returns a SharedInformerFactory for
+// cert-manager resources whose informers for namespaced resources only watch
+// the given namespaces.
+func NewMultiNamespaceInformerFactory(client cmclient.Interface, resync time.Duration, namespaces []string) cminformers.SharedInformerFactory {
+	factories := make(map[string]cminformers.SharedInformerFactory, len(namespaces))
+	for _, namespace := range namespaces {
+		factories[namespace] = cminformers.NewSharedInformerFactoryWithOptions(client, resync, cminformers.WithNamespace(namespace))
+	}
+
+	factory := cminformers.NewSharedInformerFactory(client, resync)
+	register := func(obj runtime.Object, informer func(cminformers.SharedInformerFactory) cache.SharedIndexInformer) {
+		factory.InformerFor(obj, func(cmclient.Interface, time.Duration) cache.SharedIndexInformer {
+			informers := make(map[string]cache.SharedIndexInformer, len(factories))
+			for namespace, f := range factories {
+				informers[namespace] = informer(f)
+			}
+			return NewMultiNamespaceInformer(informers)
+		})
+	}
+	register(&cmapi.Certificate{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Certmanager().V1().Certificates().Informer()
+	})
+	register(&cmapi.CertificateRequest{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Certmanager().V1().CertificateRequests().Informer()
+	})
+	register(&cmapi.Issuer{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Certmanager().V1().Issuers().Informer()
+	})
+	register(&cmacme.Order{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Acme().V1().Orders().Informer()
+	})
+	register(&cmacme.Challenge{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Acme().V1().Challenges().Informer()
+	})
+	register(&policyapi.IssuerGrant{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Policy().V1alpha1().IssuerGrants().Informer()
+	})
+	register(&revocationapi.CertificateRevocation{}, func(f cminformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Revocation().V1alpha1().CertificateRevocations().Informer()
+	})
+
+	return factory
+}
+
+// NewMultiNamespaceGatewayInformerFactory returns a SharedInformerFactory for
+// Gateway API resources whose informers for the resources read by
+// cert-manager only watch the given namespaces.
+func NewMultiNamespaceGatewayInformerFactory(client gwclient.Interface, resync time.Duration, namespaces []string) gwinformers.SharedInformerFactory {
+	factories := make(map[string]gwinformers.SharedInformerFactory, len(namespaces))
+	for _, namespace := range namespaces {
+		factories[namespace] = gwinformers.NewSharedInformerFactoryWithOptions(client, resync, gwinformers.WithNamespace(namespace))
+	}
+
+	factory := gwinformers.NewSharedInformerFactory(client, resync)
+	register := func(obj runtime.Object, informer func(gwinformers.SharedInformerFactory) cache.SharedIndexInformer) {
+		factory.InformerFor(obj, func(gwclient.Interface, time.Duration) cache.SharedIndexInformer {
+			informers := make(map[string]cache.SharedIndexInformer, len(factories))
+			for namespace, f := range factories {
+				informers[namespace] = informer(f)
+			}
+			return NewMultiNamespaceInformer(informers)
+		})
+	}
+	register(&gwapi.Gateway{}, func(f gwinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Gateway().V1alpha2().Gateways().Informer()
+	})
+	register(&gwapi.HTTPRoute{}, func(f gwinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Gateway().V1alpha2().HTTPRoutes().Informer()
+	})
+
+	return factory
+}
+
+// NewMultiNamespaceInformer returns a SharedIndexInformer that combines the
+// given informers, keyed by the namespace that each of them watches.
+// Running the returned informer runs each of the given informers, and its
+// indexer reads from their indexers.
+func NewMultiNamespaceInformer(informers map[string]cache.SharedIndexInformer) cache.SharedIndexInformer {
+	return &multiNamespaceInformer{informers: informers}
+}
+
+type multiNamespaceInformer struct {
+	informers map[string]cache.SharedIndexInformer
+}
+
+var _ cache.SharedIndexInformer = &multiNamespaceInformer{}
+
+func (i *multiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+func (i *multiNamespaceInformer) GetStore() cache.Store {
+	return i.GetIndexer()
+}
+
+// GetController returns nil, as each namespace is watched by a separate
+// controller.
+func (i *multiNamespaceInformer) GetController() cache.Controller {
+	return nil
+}
+
+func (i *multiNamespaceInformer) Run(stopCh <-chan struct{}) {
+	for _, informer := range i.informers {
+		go informer.Run(stopCh)
+	}
+	<-stopCh
+}
+
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// LastSyncResourceVersion returns the resource versions last synced by the
+// informer of each namespace, as they are not comparable with each other.
+func (i *multiNamespaceInformer) LastSyncResourceVersion() string {
+	var versions []string
+	for namespace, informer := range i.informers {
+		versions = append(versions, namespace+"="+informer.LastSyncResourceVersion())
+	}
+	return strings.Join(sets.NewString(versions...).List(), ",")
+}
+
+func (i *multiNamespaceInformer) SetWatchErrorHandler(handler cache.WatchErrorHandler) error {
+	for _, informer := range i.informers {
+		if err := informer.SetWatchErrorHandler(handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) AddIndexers(indexers cache.Indexers) error {
+	for _, informer := range i.informers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) GetIndexer() cache.Indexer {
+	indexers := make(map[string]cache.Indexer, len(i.informers))
+	for namespace, informer := range i.informers {
+		indexers[namespace] = informer.GetIndexer()
+	}
+	return &multiNamespaceIndexer{indexers: indexers}
+}
+
+// multiNamespaceIndexer is an Indexer that reads from the indexers of several
+// namespaces. Objects are written to the indexer of their namespace.
+type multiNamespaceIndexer struct {
+	indexers map[string]cache.Indexer
+}
+
+func (i *multiNamespaceIndexer) indexerFor(obj interface{}) (cache.Indexer, error) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	indexer, ok := i.indexers[objMeta.GetNamespace()]
+	if !ok {
+		return nil, fmt.Errorf("namespace %q is not watched", objMeta.GetNamespace())
+	}
+	return indexer, nil
+}
+
+func (i *multiNamespaceIndexer) Add(obj interface{}) error {
+	indexer, err := i.indexerFor(obj)
+	if err != nil {
+		return err
+	}
+	return indexer.Add(obj)
+}
+
+func (i *multiNamespaceIndexer) Update(obj interface{}) error {
+	indexer, err := i.indexerFor(obj)
+	if err != nil {
+		return err
+	}
+	return indexer.Update(obj)
+}
+
+func (i *multiNamespaceIndexer) Delete(obj interface{}) error {
+	indexer, err := i.indexerFor(obj)
+	if err != nil {
+		return err
+	}
+	return indexer.Delete(obj)
+}
+
+func (i *multiNamespaceIndexer) List() []interface{} {
+	var items []interface{}
+	for _, indexer := range i.indexers {
+		items = append(items, indexer.List()...)
+	}
+	return items
+}
+
+func (i *multiNamespaceIndexer) ListKeys() []string {
+	var keys []string
+	for _, indexer := range i.indexers {
+		keys = append(keys, indexer.ListKeys()...)
+	}
+	return keys
+}
+
+func (i *multiNamespaceIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	indexer, err := i.indexerFor(obj)
+	if err != nil {
+		return nil, false, nil
+	}
+	return indexer.Get(obj)
+}
+
+func (i *multiNamespaceIndexer) GetByKey(key string) (interface{}, bool, error) {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	indexer, ok := i.indexers[namespace]
+	if !ok {
+		return nil, false, nil
+	}
+	return indexer.GetByKey(key)
+}
+
+// Replace is not supported, as the contents of each namespace are replaced
+// by the informer watching it.
+func (i *multiNamespaceIndexer) Replace([]interface{}, string) error {
+	return fmt.Errorf("replacing the contents of a multi-namespace indexer is not supported")
+}
+
+func (i *multiNamespaceIndexer) Resync() error {
+	for _, indexer := range i.indexers {
+		if err := indexer.Resync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	var items []interface{}
+	for _, indexer := range i.indexers {
+		indexed, err := indexer.Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, indexed...)
+	}
+	return items, nil
+}
+
+func (i *multiNamespaceIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	var keys []string
+	for _, indexer := range i.indexersForIndex(indexName, indexedValue) {
+		indexed, err := indexer.IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, indexed...)
+	}
+	return keys, nil
+}
+
+func (i *multiNamespaceIndexer) ListIndexFuncValues(indexName string) []string {
+	values := sets.NewString()
+	for _, indexer := range i.indexers {
+		values.Insert(indexer.ListIndexFuncValues(indexName)...)
+	}
+	return values.List()
+}
+
+func (i *multiNamespaceIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	var items []interface{}
+	for _, indexer := range i.indexersForIndex(indexName, indexedValue) {
+		indexed, err := indexer.ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, indexed...)
+	}
+	return items, nil
+}
+
+// indexersForIndex returns the indexers that may contain objects with the
+// given indexed value. Lookups by namespace, as made by listers, only read
+// from the indexer of that namespace.
+func (i *multiNamespaceIndexer) indexersForIndex(indexName, indexedValue string) map[string]cache.Indexer {
+	if indexName != cache.NamespaceIndex {
+		return i.indexers
+	}
+	indexer, ok := i.indexers[indexedValue]
+	if !ok {
+		return nil
+	}
+	return map[string]cache.Indexer{indexedValue: indexer}
+}
+
+func (i *multiNamespaceIndexer) GetIndexers() cache.Indexers {
+	for _, indexer := range i.indexers {
+		return indexer.GetIndexers()
+	}
+	return cache.Indexers{}
+}
+
+func (i *multiNamespaceIndexer) AddIndexers(newIndexers cache.Indexers) error {
+	for _, indexer := range i.indexers {
+		if err := indexer.AddIndexers(newIndexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
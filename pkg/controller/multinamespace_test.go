@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestMultiNamespaceInformerFactory(t *testing.T) {
+	certificate := func(namespace string) *cmapi.Certificate {
+		return &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test"}}
+	}
+	client := cmfake.NewSimpleClientset(certificate("team-a"), certificate("team-b"), certificate("team-c"))
+
+	factory := NewMultiNamespaceInformerFactory(client, 0, []string{"team-a", "team-b"})
+	informer := factory.Certmanager().V1().Certificates()
+
+	var lock sync.Mutex
+	var added []string
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			lock.Lock()
+			defer lock.Unlock()
+			added = append(added, obj.(*cmapi.Certificate).Namespace)
+		},
+	})
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	for typ, synced := range factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			t.Fatalf("informer for %v did not sync", typ)
+		}
+	}
+
+	crts, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var namespaces []string
+	for _, crt := range crts {
+		namespaces = append(namespaces, crt.Namespace)
+	}
+	sort.Strings(namespaces)
+	assert.Equal(t, []string{"team-a", "team-b"}, namespaces)
+
+	if _, err := informer.Lister().Certificates("team-b").Get("test"); err != nil {
+		t.Errorf("unexpected error getting Certificate in a watched namespace: %v", err)
+	}
+	if crts, err := informer.Lister().Certificates("team-c").List(labels.Everything()); err != nil || len(crts) != 0 {
+		t.Errorf("expected no Certificates in a namespace that is not watched, got %d (error: %v)", len(crts), err)
+	}
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(added) == 2
+	}, time.Second, 10*time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	sort.Strings(added)
+	assert.Equal(t, []string{"team-a", "team-b"}, added)
+}
+
+func TestMultiNamespaceKubeInformerFactoryFiltersSecrets(t *testing.T) {
+	secret := func(namespace, name string, partOfCertManager bool) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if partOfCertManager {
+			s.Labels = map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"}
+		}
+		return s
+	}
+	client := fake.NewSimpleClientset(
+		secret("team-a", "labelled", true),
+		secret("team-a", "unlabelled", false),
+		secret("team-c", "labelled", true),
+	)
+
+	factory := NewMultiNamespaceKubeInformerFactory(client, 0, []string{"team-a", "team-b"}, true)
+	lister := factory.Core().V1().Secrets().Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	secrets, err := lister.List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets[0].Namespace != "team-a" || secrets[0].Name != "labelled" {
+		t.Errorf("expected only the labelled Secret in a watched namespace to be cached, got %v", secrets)
+	}
+	// Secrets that are not cached are still read from the apiserver.
+	if _, err := lister.Secrets("team-a").Get("unlabelled"); err != nil {
+		t.Errorf("unexpected error getting an unlabelled Secret: %v", err)
+	}
+}
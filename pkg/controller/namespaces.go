@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceScope is the set of namespaces whose resources are processed when
+// cert-manager watches more than one namespace, but not all of them.
+//
+// The namespaces given by name are also watched by scoped informers, see
+// NewMultiNamespaceInformerFactory, so that resources in other namespaces are
+// not held in memory. A single namespace needs no scope at all.
+type NamespaceScope struct {
+	// names of the namespaces to process. If empty, namespaces are only
+	// chosen by the selector.
+	names sets.String
+	// selector that the labels of a namespace must match for its resources
+	// to be processed.
+	selector labels.Selector
+
+	namespaceLister corelisters.NamespaceLister
+	hasSynced       cache.InformerSynced
+}
+
+// NewNamespaceScope returns a scope containing the namespaces with the given
+// names that match the selector. The labels of namespaces are read using the
+// given informer.
+func NewNamespaceScope(names []string, selector labels.Selector, namespaceInformer coreinformers.NamespaceInformer) *NamespaceScope {
+	return &NamespaceScope{
+		names:           sets.NewString(names...),
+		selector:        selector,
+		namespaceLister: namespaceInformer.Lister(),
+		hasSynced:       namespaceInformer.Informer().HasSynced,
+	}
+}
+
+// HasSynced returns true once the labels of namespaces have been read.
+func (s *NamespaceScope) HasSynced() bool {
+	return s.hasSynced()
+}
+
+// Contains returns true if the resources in the given namespace should be
+// processed.
+func (s *NamespaceScope) Contains(namespace string) bool {
+	if s.names.Len() > 0 && !s.names.Has(namespace) {
+		return false
+	}
+	if s.selector.Empty() {
+		return true
+	}
+	ns, err := s.namespaceLister.Get(namespace)
+	if err != nil {
+		return false
+	}
+	return s.selector.Matches(labels.Set(ns.Labels))
+}
+
+// ContainsNamespace returns true if the resources in the given Namespace
+// should be processed, as Contains does, using the labels of the given
+// Namespace.
+func (s *NamespaceScope) ContainsNamespace(ns *corev1.Namespace) bool {
+	if s.names.Len() > 0 && !s.names.Has(ns.Name) {
+		return false
+	}
+	return s.selector.Matches(labels.Set(ns.Labels))
+}
+
+// OwnsKey returns true if the resource with the given workqueue key should be
+// processed. Cluster scoped resources, such as ClusterIssuers, are always
+// processed.
+func (s *NamespaceScope) OwnsKey(key string) bool {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil || namespace == "" {
+		return true
+	}
+	return s.Contains(namespace)
+}
+
+// OwnsKey returns true if the resource with the given workqueue key is
+// processed by this replica: it is in the namespace scope, if any, and
+// belongs to the shard of this replica, if any.
+func (c *Context) OwnsKey(key string) bool {
+	if c.NamespaceScope != nil && !c.NamespaceScope.OwnsKey(key) {
+		return false
+	}
+	if c.Shard != nil && !c.Shard.OwnsKey(key) {
+		return false
+	}
+	return true
+}
+
+// RequeueOnNamespaceChange calls enqueue with each of the objects of the
+// given informer in a namespace when the resources in the namespace start to
+// be processed by this replica, e.g. because its shard label has changed or
+// it now matches the namespace selector. The keys of these resources have
+// been skipped without being requeued until then.
+func (c *Context) RequeueOnNamespaceChange(informer cache.SharedIndexInformer, enqueue func(obj interface{})) {
+	if c.NamespaceScope == nil && c.Shard == nil {
+		return
+	}
+	c.KubeSharedInformerFactory.Core().V1().Namespaces().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, ok := oldObj.(*corev1.Namespace)
+			if !ok {
+				return
+			}
+			newNs, ok := newObj.(*corev1.Namespace)
+			if !ok || c.ownsNamespace(oldNs) || !c.ownsNamespace(newNs) {
+				return
+			}
+			objs, err := informer.GetIndexer().ByIndex(cache.NamespaceIndex, newNs.Name)
+			if err != nil {
+				utilruntime.HandleError(err)
+				return
+			}
+			for _, obj := range objs {
+				enqueue(obj)
+			}
+		},
+	})
+}
+
+// ownsNamespace returns true if the resources in the given Namespace are
+// processed by this replica.
+func (c *Context) ownsNamespace(ns *corev1.Namespace) bool {
+	if c.NamespaceScope != nil && !c.NamespaceScope.ContainsNamespace(ns) {
+		return false
+	}
+	if c.Shard != nil && c.Shard.ForNamespace(ns) != c.Shard.Index {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNamespaceScopeOwnsKey(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-dev", Labels: map[string]string{"tenant": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "b"}}},
+	)
+
+	tests := map[string]struct {
+		names    []string
+		selector string
+		key      string
+		expOwns  bool
+	}{
+		"a listed namespace is in scope": {
+			names:   []string{"team-a", "team-b"},
+			key:     "team-b/crt",
+			expOwns: true,
+		},
+		"a namespace that is not listed is out of scope": {
+			names:   []string{"team-a", "team-b"},
+			key:     "team-a-dev/crt",
+			expOwns: false,
+		},
+		"a namespace matching the selector is in scope": {
+			selector: "tenant=a",
+			key:      "team-a-dev/crt",
+			expOwns:  true,
+		},
+		"a namespace not matching the selector is out of scope": {
+			selector: "tenant=a",
+			key:      "team-b/crt",
+			expOwns:  false,
+		},
+		"a namespace that does not exist does not match the selector": {
+			selector: "tenant=a",
+			key:      "missing/crt",
+			expOwns:  false,
+		},
+		"a listed namespace must also match the selector": {
+			names:    []string{"team-a", "team-b"},
+			selector: "tenant=a",
+			key:      "team-b/crt",
+			expOwns:  false,
+		},
+		"cluster scoped resources are always in scope": {
+			names:    []string{"team-a"},
+			selector: "tenant=a",
+			key:      "clusterissuer",
+			expOwns:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector, err := labels.Parse(test.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			factory := informers.NewSharedInformerFactory(client, 0)
+			scope := NewNamespaceScope(test.names, selector, factory.Core().V1().Namespaces())
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			factory.Start(stopCh)
+			factory.WaitForCacheSync(stopCh)
+
+			if owns := scope.OwnsKey(test.key); owns != test.expOwns {
+				t.Errorf("unexpected scope of %q, exp=%t got=%t", test.key, test.expOwns, owns)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// secretFallbackTimeout bounds the requests made to the apiserver for
+// Secrets that are not in the cache.
+const secretFallbackTimeout = 10 * time.Second
+
+// NewSecretFilteringInformerFactory returns a SharedInformerFactory whose
+// Secret informer only caches the Secrets labelled with
+// cmapi.PartOfCertManagerControllerLabelKey, rather than every Secret in the
+// watched namespaces.
+//
+// Secrets that are not in the cache are fetched from the apiserver when they
+// are read using the Get method of the factory's Secret lister, so Secrets
+// created by users, such as the key pair of a CA Issuer, can still be read.
+// Listing Secrets and Secret events only include the labelled Secrets. The
+// Secrets of Certificates are labelled, so controllers watching them, such
+// as the secret sinks controller, are unaffected. Controllers that react to
+// changes of user-created Secrets, such as the bundles controller for source
+// Secrets and the cacrl controller for CA key pairs, only see those changes
+// once the Secrets are labelled, or when they next sync for another reason.
+func NewSecretFilteringInformerFactory(client kubernetes.Interface, resync time.Duration, namespace string) kubeinformers.SharedInformerFactory {
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(client, resync, kubeinformers.WithNamespace(namespace))
+	// Registering the filtered informer first means that it is used by all
+	// callers of factory.Core().V1().Secrets().
+	factory.InformerFor(&corev1.Secret{}, func(client kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+		return corev1informers.NewFilteredSecretInformer(client, namespace, resync,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+			func(opts *metav1.ListOptions) {
+				opts.LabelSelector = cmapi.PartOfCertManagerControllerLabelKey + "=true"
+			},
+		)
+	})
+	return &secretFilteringInformerFactory{SharedInformerFactory: factory, client: client}
+}
+
+type secretFilteringInformerFactory struct {
+	kubeinformers.SharedInformerFactory
+	client kubernetes.Interface
+}
+
+func (f *secretFilteringInformerFactory) Core() coreinformers.Interface {
+	return &secretFilteringCoreInformers{Interface: f.SharedInformerFactory.Core(), client: f.client}
+}
+
+type secretFilteringCoreInformers struct {
+	coreinformers.Interface
+	client kubernetes.Interface
+}
+
+func (c *secretFilteringCoreInformers) V1() corev1informers.Interface {
+	return &secretFilteringCoreV1Informers{Interface: c.Interface.V1(), client: c.client}
+}
+
+type secretFilteringCoreV1Informers struct {
+	corev1informers.Interface
+	client kubernetes.Interface
+}
+
+func (c *secretFilteringCoreV1Informers) Secrets() corev1informers.SecretInformer {
+	return &secretFilteringInformer{SecretInformer: c.Interface.Secrets(), client: c.client}
+}
+
+type secretFilteringInformer struct {
+	corev1informers.SecretInformer
+	client kubernetes.Interface
+}
+
+func (i *secretFilteringInformer) Lister() corelisters.SecretLister {
+	return &secretFallbackLister{SecretLister: i.SecretInformer.Lister(), client: i.client}
+}
+
+// secretFallbackLister fetches Secrets that are not in the cache from the
+// apiserver.
+type secretFallbackLister struct {
+	corelisters.SecretLister
+	client kubernetes.Interface
+}
+
+func (l *secretFallbackLister) Secrets(namespace string) corelisters.SecretNamespaceLister {
+	return &secretFallbackNamespaceLister{
+		SecretNamespaceLister: l.SecretLister.Secrets(namespace),
+		namespace:             namespace,
+		client:                l.client,
+	}
+}
+
+type secretFallbackNamespaceLister struct {
+	corelisters.SecretNamespaceLister
+	namespace string
+	client    kubernetes.Interface
+}
+
+func (l *secretFallbackNamespaceLister) Get(name string) (*corev1.Secret, error) {
+	secret, err := l.SecretNamespaceLister.Get(name)
+	if !apierrors.IsNotFound(err) {
+		return secret, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretFallbackTimeout)
+	defer cancel()
+	return l.client.CoreV1().Secrets(l.namespace).Get(ctx, name, metav1.GetOptions{})
+}
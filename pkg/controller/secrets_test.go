@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestSecretFilteringInformerFactory(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "labelled", Namespace: "ns", Labels: map[string]string{cmapi.PartOfCertManagerControllerLabelKey: "true"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unlabelled", Namespace: "ns"}},
+	)
+	factory := NewSecretFilteringInformerFactory(client, 0, "")
+	lister := factory.Core().V1().Secrets().Lister()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	cached, err := lister.List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cached) != 1 || cached[0].Name != "labelled" {
+		t.Errorf("expected only the labelled secret to be cached, got %v", cached)
+	}
+
+	for _, name := range []string{"labelled", "unlabelled"} {
+		if _, err := lister.Secrets("ns").Get(name); err != nil {
+			t.Errorf("unexpected error getting secret %q: %v", name, err)
+		}
+	}
+
+	if _, err := lister.Secrets("ns").Get("missing"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a not found error getting a missing secret, got %v", err)
+	}
+}
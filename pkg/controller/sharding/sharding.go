@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding splits the resources processed by the controller between
+// several replicas, each of which holds the lease of one shard.
+//
+// Resources are assigned to a shard by their namespace, so that a
+// Certificate is always processed by the same replica as the Issuer,
+// CertificateRequests, Orders, Challenges and Secrets in its namespace.
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// Shard is the part of the resources in the cluster that a replica of the
+// controller is responsible for.
+type Shard struct {
+	// Index of this shard, between 0 and Count-1.
+	Index int
+	// Count is the total number of shards.
+	Count int
+
+	namespaceLister corelisters.NamespaceLister
+	hasSynced       cache.InformerSynced
+}
+
+// New returns the shard with the given index. The shard label of namespaces
+// is read using the given informer.
+func New(index, count int, namespaceInformer coreinformers.NamespaceInformer) *Shard {
+	return &Shard{
+		Index:           index,
+		Count:           count,
+		namespaceLister: namespaceInformer.Lister(),
+		hasSynced:       namespaceInformer.Informer().HasSynced,
+	}
+}
+
+// HasSynced returns true once the namespaces used to assign resources to
+// shards have been read.
+func (s *Shard) HasSynced() bool {
+	return s.hasSynced()
+}
+
+// For returns the index of the shard that the resources in the given
+// namespace belong to. This is the value of the cert-manager.io/shard label
+// of the Namespace if it is set to a valid index, otherwise it is chosen by
+// a hash of the name of the namespace.
+func (s *Shard) For(namespace string) int {
+	ns, err := s.namespaceLister.Get(namespace)
+	if err != nil {
+		return HashIndex(namespace, s.Count)
+	}
+	return s.ForNamespace(ns)
+}
+
+// ForNamespace returns the index of the shard that the resources in the given
+// Namespace belong to, as For does, using the labels of the given Namespace.
+func (s *Shard) ForNamespace(ns *corev1.Namespace) int {
+	if index, err := strconv.Atoi(ns.Labels[cmapi.ShardLabelKey]); err == nil && index >= 0 && index < s.Count {
+		return index
+	}
+	return HashIndex(ns.Name, s.Count)
+}
+
+// OwnsKey returns true if the resource with the given workqueue key belongs
+// to this shard.
+// Cluster scoped resources, such as ClusterIssuers and
+// CertificateSigningRequests, belong to the first shard so that they are only
+// processed by a single replica.
+func (s *Shard) OwnsKey(key string) bool {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil || namespace == "" {
+		return s.Index == 0
+	}
+	return s.For(namespace) == s.Index
+}
+
+// HashIndex returns the index of the shard that the given namespace is
+// assigned to when it has no shard label.
+func HashIndex(namespace string, count int) int {
+	h := fnv.New32a()
+	// Writes to a hash never return an error.
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(count))
+}
+
+// LeaseName returns the name of the lease held by the replica processing the
+// shard with the given index.
+func LeaseName(prefix string, index int) string {
+	return fmt.Sprintf("%s-shard-%d", prefix, index)
+}
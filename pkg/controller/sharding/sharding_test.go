@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestOwnsKey(t *testing.T) {
+	const count = 3
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "pinned", Labels: map[string]string{cmapi.ShardLabelKey: "2"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "out-of-range", Labels: map[string]string{cmapi.ShardLabelKey: "3"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "invalid", Labels: map[string]string{cmapi.ShardLabelKey: "two"}}},
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	namespaceInformer := factory.Core().V1().Namespaces()
+	shards := make([]*Shard, count)
+	for i := range shards {
+		shards[i] = New(i, count, namespaceInformer)
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	tests := map[string]struct {
+		key      string
+		expShard int
+	}{
+		"a namespace without a label is assigned by hash": {
+			key:      "unlabelled/crt",
+			expShard: HashIndex("unlabelled", count),
+		},
+		"a namespace with a shard label is assigned to that shard": {
+			key:      "pinned/crt",
+			expShard: 2,
+		},
+		"if the shard label is out of range, assign by hash": {
+			key:      "out-of-range/crt",
+			expShard: HashIndex("out-of-range", count),
+		},
+		"if the shard label is not a number, assign by hash": {
+			key:      "invalid/crt",
+			expShard: HashIndex("invalid", count),
+		},
+		"cluster scoped resources belong to the first shard": {
+			key:      "clusterissuer",
+			expShard: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, shard := range shards {
+				exp := test.expShard == shard.Index
+				if got := shard.OwnsKey(test.key); got != exp {
+					t.Errorf("unexpected ownership of %q by shard %d, exp=%t got=%t", test.key, shard.Index, exp, got)
+				}
+			}
+		})
+	}
+}
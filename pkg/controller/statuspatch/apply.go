@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statuspatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// PatchFunc sends a patch of the given type to the status subresource of a
+// resource.
+type PatchFunc func(ctx context.Context, pt types.PatchType, patch []byte, opts metav1.PatchOptions) error
+
+// conditionsField is the status field holding the conditions of a resource.
+// Conditions are a list map keyed by their type, so each condition is owned
+// separately and is treated as a field of its own.
+const conditionsField = "conditions"
+
+// Apply sends the status fields of newStatus which are managed by
+// fieldManager to obj using server-side apply, forcing any conflicts with
+// other field managers. The fields managed by fieldManager are those which
+// differ from oldStatus, along with those it already owns according to the
+// managed fields of obj, so that fields it no longer sets are removed. Apply
+// does nothing if the statuses are equal.
+//
+// Server-side apply only removes a field when no other field manager owns it,
+// so if newStatus removes a field which fieldManager does not solely own, the
+// change is sent using Patch with the given key instead.
+//
+// Unlike patches, applied changes are not conditional on the resourceVersion
+// of obj and are never merged with each other.
+func (p *Patcher) Apply(ctx context.Context, key string, gvk schema.GroupVersionKind, obj metav1.Object, fieldManager string, oldStatus, newStatus interface{}, patch PatchFunc) error {
+	oldFields, _, err := applyFieldsOf(oldStatus)
+	if err != nil {
+		return err
+	}
+	newFields, conditions, err := applyFieldsOf(newStatus)
+	if err != nil {
+		return err
+	}
+
+	owned, shared := managedStatusFields(obj.GetManagedFields(), fieldManager)
+
+	changed := false
+	for k, v := range newFields {
+		if !bytes.Equal(oldFields[k], v) {
+			changed = true
+		}
+	}
+	for k := range oldFields {
+		if _, ok := newFields[k]; ok {
+			continue
+		}
+		if !owned.Has(k) || shared.Has(k) {
+			return p.Patch(ctx, key, obj.GetResourceVersion(), oldStatus, newStatus, func(ctx context.Context, data []byte) error {
+				return patch(ctx, types.MergePatchType, data, metav1.PatchOptions{FieldManager: fieldManager})
+			})
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	status := make(map[string]interface{})
+	var applied []json.RawMessage
+	for k, v := range newFields {
+		if !owned.Has(k) && bytes.Equal(oldFields[k], v) {
+			continue
+		}
+		if !strings.HasPrefix(k, conditionsField+"/") {
+			status[k] = v
+		}
+	}
+	for _, k := range conditions {
+		if owned.Has(k) || !bytes.Equal(oldFields[k], newFields[k]) {
+			applied = append(applied, newFields[k])
+		}
+	}
+	if len(applied) > 0 {
+		status[conditionsField] = applied
+	}
+
+	metadata := map[string]string{"name": obj.GetName()}
+	if obj.GetNamespace() != "" {
+		metadata["namespace"] = obj.GetNamespace()
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+		"metadata":   metadata,
+		"status":     status,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding apply patch: %w", err)
+	}
+
+	if p.limiter != nil {
+		if err := p.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	force := true
+	return patch(ctx, types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: fieldManager, Force: &force})
+}
+
+// applyFieldsOf returns the top level fields of status, with each of its
+// conditions returned as a separate field keyed by the condition type. The
+// keys of the conditions are also returned in the order they appear.
+func applyFieldsOf(status interface{}) (map[string]json.RawMessage, []string, error) {
+	fields, err := fieldsOf(status)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, ok := fields[conditionsField]
+	if !ok {
+		return fields, nil, nil
+	}
+	delete(fields, conditionsField)
+
+	var conditions []json.RawMessage
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		return nil, nil, fmt.Errorf("error decoding status conditions: %w", err)
+	}
+	var keys []string
+	for _, c := range conditions {
+		var cond struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(c, &cond); err != nil {
+			return nil, nil, fmt.Errorf("error decoding status condition: %w", err)
+		}
+		k := conditionsField + "/" + cond.Type
+		fields[k] = c
+		keys = append(keys, k)
+	}
+	return fields, keys, nil
+}
+
+// managedStatusFields returns the status fields applied by fieldManager, and
+// the status fields owned by any other field manager or operation, keyed in
+// the same way as applyFieldsOf.
+func managedStatusFields(entries []metav1.ManagedFieldsEntry, fieldManager string) (owned, shared sets.String) {
+	owned, shared = sets.NewString(), sets.NewString()
+	for _, e := range entries {
+		if e.FieldsV1 == nil {
+			continue
+		}
+		fields, err := statusFieldsOf(e.FieldsV1.Raw)
+		if err != nil {
+			// Ownership of fields that cannot be parsed is unknown, so they
+			// are never removed using server-side apply.
+			continue
+		}
+		if e.Manager == fieldManager && e.Operation == metav1.ManagedFieldsOperationApply {
+			owned.Insert(fields...)
+		} else {
+			shared.Insert(fields...)
+		}
+	}
+	return owned, shared
+}
+
+// statusFieldsOf returns the status fields in the FieldsV1 encoded set of
+// fields.
+func statusFieldsOf(raw []byte) ([]string, error) {
+	var set struct {
+		Status map[string]json.RawMessage `json:"f:status"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for k, v := range set.Status {
+		name := strings.TrimPrefix(k, "f:")
+		if name == k {
+			continue
+		}
+		if name != conditionsField {
+			fields = append(fields, name)
+			continue
+		}
+
+		var conditions map[string]json.RawMessage
+		if err := json.Unmarshal(v, &conditions); err != nil {
+			return nil, err
+		}
+		for ck := range conditions {
+			if !strings.HasPrefix(ck, "k:") {
+				continue
+			}
+			var cond struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(ck, "k:")), &cond); err != nil {
+				return nil, err
+			}
+			fields = append(fields, conditionsField+"/"+cond.Type)
+		}
+	}
+	return fields, nil
+}
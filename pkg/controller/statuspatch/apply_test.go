@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statuspatch
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type testCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+type testApplyStatus struct {
+	Conditions []testCondition `json:"conditions,omitempty"`
+	Revision   *int            `json:"revision,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+}
+
+type sentPatch struct {
+	patchType types.PatchType
+	patch     string
+	force     bool
+	manager   string
+}
+
+func managedFieldsEntry(manager string, op metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   op,
+		Subresource: "status",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestApply(t *testing.T) {
+	const manager = "cert-manager-test"
+	const header = `{"apiVersion":"example.io/v1","kind":"Test","metadata":{"name":"test","namespace":"ns"},"status":`
+
+	tests := map[string]struct {
+		managedFields        []metav1.ManagedFieldsEntry
+		oldStatus, newStatus testApplyStatus
+
+		expPatches []sentPatch
+	}{
+		"if the status is unchanged, nothing should be sent": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationApply, `{"f:status":{"f:reason":{}}}`),
+			},
+			oldStatus:  testApplyStatus{Reason: "Issuing"},
+			newStatus:  testApplyStatus{Reason: "Issuing"},
+			expPatches: nil,
+		},
+		"changed fields and fields owned by the field manager should be applied": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationApply, `{"f:status":{"f:reason":{}}}`),
+				managedFieldsEntry("other", metav1.ManagedFieldsOperationApply, `{"f:status":{"f:conditions":{"k:{\"type\":\"Issuing\"}":{}}}}`),
+			},
+			oldStatus: testApplyStatus{Reason: "Issuing", Conditions: []testCondition{{"Issuing", "True"}}},
+			newStatus: testApplyStatus{Reason: "Issuing", Revision: intPtr(1), Conditions: []testCondition{{"Issuing", "True"}}},
+			expPatches: []sentPatch{
+				{types.ApplyPatchType, header + `{"reason":"Issuing","revision":1}}`, true, manager},
+			},
+		},
+		"only changed and owned conditions should be applied": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationApply, `{"f:status":{"f:conditions":{".":{},"k:{\"type\":\"Ready\"}":{".":{},"f:status":{}}}}}`),
+			},
+			oldStatus: testApplyStatus{Conditions: []testCondition{{"Ready", "True"}, {"Issuing", "True"}}},
+			newStatus: testApplyStatus{Conditions: []testCondition{{"Ready", "True"}, {"Issuing", "True"}, {"Adopted", "True"}}},
+			expPatches: []sentPatch{
+				{types.ApplyPatchType, header + `{"conditions":[{"type":"Ready","status":"True"},{"type":"Adopted","status":"True"}]}}`, true, manager},
+			},
+		},
+		"fields only owned by the field manager should be removed by applying without them": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationApply, `{"f:status":{"f:reason":{},"f:conditions":{"k:{\"type\":\"Issuing\"}":{}}}}`),
+			},
+			oldStatus: testApplyStatus{Reason: "Issuing", Conditions: []testCondition{{"Issuing", "True"}}},
+			newStatus: testApplyStatus{Reason: "Issuing"},
+			expPatches: []sentPatch{
+				{types.ApplyPatchType, header + `{"reason":"Issuing"}}`, true, manager},
+			},
+		},
+		"removing a field owned by another field manager should be sent as a merge patch": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationApply, `{"f:status":{"f:reason":{}}}`),
+				managedFieldsEntry("other", metav1.ManagedFieldsOperationApply, `{"f:status":{"f:conditions":{"k:{\"type\":\"Issuing\"}":{}}}}`),
+			},
+			oldStatus: testApplyStatus{Reason: "Issuing", Conditions: []testCondition{{"Issuing", "True"}}},
+			newStatus: testApplyStatus{Reason: "Issuing"},
+			expPatches: []sentPatch{
+				{types.MergePatchType, `{"metadata":{"resourceVersion":"1"},"status":{"conditions":null}}`, false, manager},
+			},
+		},
+		"removing a field also owned by an update should be sent as a merge patch": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationApply, `{"f:status":{"f:revision":{}}}`),
+				managedFieldsEntry(manager, metav1.ManagedFieldsOperationUpdate, `{"f:status":{"f:revision":{}}}`),
+			},
+			oldStatus: testApplyStatus{Revision: intPtr(1)},
+			newStatus: testApplyStatus{},
+			expPatches: []sentPatch{
+				{types.MergePatchType, `{"metadata":{"resourceVersion":"1"},"status":{"revision":null}}`, false, manager},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Name:            "test",
+				Namespace:       "ns",
+				ResourceVersion: "1",
+				ManagedFields:   test.managedFields,
+			}
+			gvk := schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Test"}
+
+			var patches []sentPatch
+			err := New(0, 0).Apply(context.TODO(), "test", gvk, obj, manager, test.oldStatus, test.newStatus,
+				func(_ context.Context, pt types.PatchType, patch []byte, opts metav1.PatchOptions) error {
+					patches = append(patches, sentPatch{pt, string(patch), opts.Force != nil && *opts.Force, opts.FieldManager})
+					return nil
+				})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(patches) != len(test.expPatches) {
+				t.Fatalf("unexpected patches, exp=%v got=%v", test.expPatches, patches)
+			}
+			for i := range patches {
+				if patches[i] != test.expPatches[i] {
+					t.Errorf("unexpected patch, exp=%v got=%v", test.expPatches[i], patches[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statuspatch sends changes to the status of resources to the
+// apiserver as rate limited merge patches, merging patches to the same
+// resource that are waiting to be sent, or using server-side apply.
+package statuspatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// ApplyFunc sends a merge patch to the status subresource of a resource.
+type ApplyFunc func(ctx context.Context, patch []byte) error
+
+// Patcher sends merge patches to the status subresource of resources. A
+// single Patcher is shared by all controllers so that the number of status
+// writes sent to the apiserver is limited as a whole.
+//
+// Patches are conditional on the resourceVersion the new status was computed
+// from, so a patch fails with a conflict if the resource has since been
+// changed, in the same way as an update. While a patch is waiting for the
+// rate limiter, patches to the same resource computed from the same
+// resourceVersion are merged into it and sent as a single request, unless
+// they set the same status field to different values.
+type Patcher struct {
+	// limiter limits the rate at which patches are sent. Patches are sent
+	// immediately if it is nil.
+	limiter flowcontrol.RateLimiter
+
+	lock    sync.Mutex
+	pending map[string]*batch
+}
+
+// batch is a patch waiting to be sent, along with the result of sending it.
+type batch struct {
+	resourceVersion string
+	status          map[string]json.RawMessage
+	// patches is the number of patches merged into the batch.
+	patches int
+
+	done chan struct{}
+	err  error
+}
+
+// New returns a Patcher which sends at most qps patches per second on
+// average, with bursts of up to burst patches. If qps is not positive the
+// rate of patches is not limited.
+func New(qps float32, burst int) *Patcher {
+	p := &Patcher{
+		pending: make(map[string]*batch),
+	}
+	if qps > 0 {
+		p.limiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	}
+	return p
+}
+
+// Patch sends the fields of newStatus which differ from oldStatus to the
+// resource identified by key using apply. The key must be unique to the
+// resource, e.g. its kind, namespace and name. Patch does nothing if the
+// statuses are equal. It returns once the patch, or a merged patch including
+// it, has been sent.
+func (p *Patcher) Patch(ctx context.Context, key, resourceVersion string, oldStatus, newStatus interface{}, apply ApplyFunc) error {
+	fields, err := changedFields(oldStatus, newStatus)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	for {
+		p.lock.Lock()
+		b, ok := p.pending[key]
+		if !ok {
+			b = &batch{
+				resourceVersion: resourceVersion,
+				status:          fields,
+				patches:         1,
+				done:            make(chan struct{}),
+			}
+			p.pending[key] = b
+			p.lock.Unlock()
+			return p.send(ctx, key, b, apply)
+		}
+		if b.merge(resourceVersion, fields) {
+			p.lock.Unlock()
+			return b.wait(ctx)
+		}
+		p.lock.Unlock()
+
+		// The pending patch cannot be merged with this one, so wait for it to
+		// be sent before trying again.
+		if err := b.wait(ctx); err != nil && ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// send waits for the rate limiter then sends the batch, which no longer
+// accepts further patches once it is being sent.
+func (p *Patcher) send(ctx context.Context, key string, b *batch, apply ApplyFunc) error {
+	var err error
+	if p.limiter != nil {
+		err = p.limiter.Wait(ctx)
+	}
+
+	p.lock.Lock()
+	delete(p.pending, key)
+	p.lock.Unlock()
+
+	if err == nil {
+		var patch []byte
+		patch, err = b.patch()
+		if err == nil {
+			err = apply(ctx, patch)
+		}
+	}
+
+	b.err = err
+	close(b.done)
+	return err
+}
+
+// merge adds fields to the batch if it was computed from the same
+// resourceVersion and does not conflict with the fields already in the batch.
+// It must be called with the lock of the Patcher held.
+func (b *batch) merge(resourceVersion string, fields map[string]json.RawMessage) bool {
+	if b.resourceVersion != resourceVersion {
+		return false
+	}
+	for k, v := range fields {
+		if existing, ok := b.status[k]; ok && !bytes.Equal(existing, v) {
+			return false
+		}
+	}
+	for k, v := range fields {
+		b.status[k] = v
+	}
+	b.patches++
+	return true
+}
+
+func (b *batch) wait(ctx context.Context) error {
+	select {
+	case <-b.done:
+		return b.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *batch) patch() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]string{
+			"resourceVersion": b.resourceVersion,
+		},
+		"status": b.status,
+	})
+}
+
+// changedFields returns the top level fields of newStatus whose JSON encoding
+// differs from oldStatus. Fields which are only present in oldStatus are
+// returned as null, which removes them when used in a merge patch.
+func changedFields(oldStatus, newStatus interface{}) (map[string]json.RawMessage, error) {
+	oldFields, err := fieldsOf(oldStatus)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := fieldsOf(newStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]json.RawMessage)
+	for k, v := range newFields {
+		if !bytes.Equal(oldFields[k], v) {
+			changed[k] = v
+		}
+	}
+	for k := range oldFields {
+		if _, ok := newFields[k]; !ok {
+			changed[k] = json.RawMessage("null")
+		}
+	}
+
+	return changed, nil
+}
+
+func fieldsOf(status interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding status: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("error decoding status: %w", err)
+	}
+	return fields, nil
+}
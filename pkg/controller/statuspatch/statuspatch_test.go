@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statuspatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+type testStatus struct {
+	Revision *int   `json:"revision,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// blockingLimiter is a rate limiter whose Wait blocks until release is
+// closed.
+type blockingLimiter struct {
+	flowcontrol.RateLimiter
+	release chan struct{}
+}
+
+func (l *blockingLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recorder records the patches sent by a Patcher.
+type recorder struct {
+	lock    sync.Mutex
+	patches []string
+	err     error
+}
+
+func (r *recorder) apply(_ context.Context, patch []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.patches = append(r.patches, string(patch))
+	return r.err
+}
+
+func TestPatch(t *testing.T) {
+	tests := map[string]struct {
+		oldStatus, newStatus testStatus
+		applyErr             error
+
+		expPatches []string
+		expErr     bool
+	}{
+		"if the status is unchanged, nothing should be sent": {
+			oldStatus:  testStatus{Reason: "Issuing"},
+			newStatus:  testStatus{Reason: "Issuing"},
+			expPatches: nil,
+		},
+		"only changed fields should be sent": {
+			oldStatus: testStatus{Reason: "Issuing", Message: "old"},
+			newStatus: testStatus{Reason: "Issuing", Message: "new", Revision: intPtr(2)},
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"message":"new","revision":2}}`,
+			},
+		},
+		"removed fields should be sent as null": {
+			oldStatus: testStatus{Reason: "Issuing", Revision: intPtr(1)},
+			newStatus: testStatus{Reason: "Issuing"},
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"revision":null}}`,
+			},
+		},
+		"an error sending the patch should be returned": {
+			oldStatus: testStatus{},
+			newStatus: testStatus{Reason: "Failed"},
+			applyErr:  errors.New("conflict"),
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"reason":"Failed"}}`,
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{err: test.applyErr}
+			err := New(0, 0).Patch(context.TODO(), "test", "1", test.oldStatus, test.newStatus, r.apply)
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if len(r.patches) != len(test.expPatches) {
+				t.Fatalf("unexpected patches, exp=%q got=%q", test.expPatches, r.patches)
+			}
+			for i := range r.patches {
+				if r.patches[i] != test.expPatches[i] {
+					t.Errorf("unexpected patch, exp=%s got=%s", test.expPatches[i], r.patches[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPatchCoalescing(t *testing.T) {
+	type patch struct {
+		resourceVersion string
+		newStatus       testStatus
+	}
+
+	tests := map[string]struct {
+		first, second patch
+		// merged is true if the second patch is expected to be merged into
+		// the first one while it is waiting for the rate limiter
+		merged bool
+
+		expPatches []string
+	}{
+		"patches to different fields should be merged": {
+			first:  patch{"1", testStatus{Reason: "Issuing"}},
+			second: patch{"1", testStatus{Revision: intPtr(1)}},
+			merged: true,
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"reason":"Issuing","revision":1}}`,
+			},
+		},
+		"patches setting a field to the same value should be merged": {
+			first:  patch{"1", testStatus{Reason: "Issuing", Message: "a"}},
+			second: patch{"1", testStatus{Reason: "Issuing"}},
+			merged: true,
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"message":"a","reason":"Issuing"}}`,
+			},
+		},
+		"patches setting a field to different values should be sent separately": {
+			first:  patch{"1", testStatus{Reason: "Issuing"}},
+			second: patch{"1", testStatus{Reason: "Failed"}},
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"reason":"Issuing"}}`,
+				`{"metadata":{"resourceVersion":"1"},"status":{"reason":"Failed"}}`,
+			},
+		},
+		"patches computed from different resource versions should be sent separately": {
+			first:  patch{"1", testStatus{Reason: "Issuing"}},
+			second: patch{"2", testStatus{Revision: intPtr(1)}},
+			expPatches: []string{
+				`{"metadata":{"resourceVersion":"1"},"status":{"reason":"Issuing"}}`,
+				`{"metadata":{"resourceVersion":"2"},"status":{"revision":1}}`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			limiter := &blockingLimiter{release: make(chan struct{})}
+			p := New(0, 0)
+			p.limiter = limiter
+			r := &recorder{}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+
+			errs := make(chan error, 2)
+			go func() {
+				errs <- p.Patch(ctx, "test", test.first.resourceVersion, testStatus{}, test.first.newStatus, r.apply)
+			}()
+			waitFor(t, func() bool { return p.pendingPatches("test") == 1 })
+
+			go func() {
+				errs <- p.Patch(ctx, "test", test.second.resourceVersion, testStatus{}, test.second.newStatus, r.apply)
+			}()
+			if test.merged {
+				waitFor(t, func() bool { return p.pendingPatches("test") == 2 })
+			} else {
+				// give the second patch time to be (incorrectly) merged
+				time.Sleep(time.Millisecond * 50)
+			}
+			close(limiter.release)
+
+			for i := 0; i < 2; i++ {
+				if err := <-errs; err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+
+			if len(r.patches) != len(test.expPatches) {
+				t.Fatalf("unexpected patches, exp=%q got=%q", test.expPatches, r.patches)
+			}
+			for i := range r.patches {
+				if r.patches[i] != test.expPatches[i] {
+					t.Errorf("unexpected patch, exp=%s got=%s", test.expPatches[i], r.patches[i])
+				}
+			}
+		})
+	}
+}
+
+// pendingPatches returns the number of patches merged into the batch waiting
+// to be sent for key.
+func (p *Patcher) pendingPatches(key string) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	b, ok := p.pending[key]
+	if !ok {
+		return 0
+	}
+	return b.patches
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("timed out waiting for condition")
+}
@@ -17,10 +17,15 @@ limitations under the License.
 package test
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 
 	"github.com/kr/pretty"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	coretesting "k8s.io/client-go/testing"
 )
 
@@ -95,3 +100,60 @@ func (a *action) Matches(act coretesting.Action) error {
 
 	return fmt.Errorf("unexpected difference between actions: %s", pretty.Diff(objExp.GetObject(), objAct.GetObject()))
 }
+
+// NewStatusPatchAction returns an Action that matches a merge patch to the
+// status subresource of obj. The patch matches if every status field it sets
+// has the same value as that field of obj, and every field it removes is
+// unset in obj.
+func NewStatusPatchAction(gvr schema.GroupVersionResource, obj runtime.Object) Action {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		panic(err)
+	}
+	return NewCustomMatch(
+		coretesting.NewPatchSubresourceAction(gvr, m.GetNamespace(), m.GetName(), types.MergePatchType, nil, "status"),
+		func(_, act coretesting.Action) error {
+			patchAction, ok := act.(coretesting.PatchAction)
+			if !ok {
+				return fmt.Errorf("expected a patch action, got %T", act)
+			}
+			if patchAction.GetName() != m.GetName() {
+				return fmt.Errorf("unexpected name in patch action, exp=%q got=%q", m.GetName(), patchAction.GetName())
+			}
+			if patchAction.GetPatchType() != types.MergePatchType {
+				return fmt.Errorf("unexpected patch type, exp=%q got=%q", types.MergePatchType, patchAction.GetPatchType())
+			}
+
+			var patch struct {
+				Status map[string]interface{} `json:"status"`
+			}
+			if err := json.Unmarshal(patchAction.GetPatch(), &patch); err != nil {
+				return fmt.Errorf("failed to decode patch: %w", err)
+			}
+			exp, err := statusFields(obj)
+			if err != nil {
+				return err
+			}
+			for k, v := range patch.Status {
+				if !reflect.DeepEqual(exp[k], v) {
+					return fmt.Errorf("unexpected value of status field %q in patch: %s", k, pretty.Diff(exp[k], v))
+				}
+			}
+			return nil
+		},
+	)
+}
+
+func statusFields(obj runtime.Object) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object: %w", err)
+	}
+	var fields struct {
+		Status map[string]interface{} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode object: %w", err)
+	}
+	return fields.Status, nil
+}
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
@@ -39,10 +40,72 @@ var (
 	KeyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
 )
 
+// DefaultRateLimiterOptions is the workqueue backoff used by controllers that
+// do not define their own defaults: a base delay of 5 seconds and a max delay
+// of 5 minutes.
+var DefaultRateLimiterOptions = RateLimiterOptions{
+	BaseDelay: time.Second * 5,
+	MaxDelay:  time.Minute * 5,
+}
+
+// FieldManager returns the field manager used by the named controller when
+// writing to resources using server-side apply.
+func FieldManager(controllerName string) string {
+	return "cert-manager-" + controllerName
+}
+
 // DefaultItemBasedRateLimiter returns a new rate limiter with base delay of 5
 // seconds, max delay of 5 minutes.
 func DefaultItemBasedRateLimiter() workqueue.RateLimiter {
-	return workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*5)
+	return NewItemBasedRateLimiter(DefaultRateLimiterOptions)
+}
+
+// NewItemBasedRateLimiter returns a new per-item exponential backoff rate
+// limiter, adding random jitter to each delay if configured.
+func NewItemBasedRateLimiter(opts RateLimiterOptions) workqueue.RateLimiter {
+	rl := workqueue.NewItemExponentialFailureRateLimiter(opts.BaseDelay, opts.MaxDelay)
+	if opts.Jitter <= 0 {
+		return rl
+	}
+	return &jitterRateLimiter{RateLimiter: rl, jitter: opts.Jitter}
+}
+
+// RateLimiter returns the workqueue rate limiter for the named controller.
+// The given defaults are overridden by any options configured for all
+// controllers, which are in turn overridden by any options configured for
+// the named controller.
+func (o WorkqueueOptions) RateLimiter(controllerName string, defaults RateLimiterOptions) workqueue.RateLimiter {
+	opts := defaults
+	for _, key := range []string{"*", controllerName} {
+		override, ok := o.RateLimiters[key]
+		if !ok {
+			continue
+		}
+		if override.BaseDelay > 0 {
+			opts.BaseDelay = override.BaseDelay
+		}
+		if override.MaxDelay > 0 {
+			opts.MaxDelay = override.MaxDelay
+		}
+		if override.Jitter > 0 {
+			opts.Jitter = override.Jitter
+		}
+	}
+	if opts.MaxDelay < opts.BaseDelay {
+		opts.MaxDelay = opts.BaseDelay
+	}
+	return NewItemBasedRateLimiter(opts)
+}
+
+// jitterRateLimiter wraps a workqueue.RateLimiter, adding up to jitter*delay
+// of random jitter to each delay.
+type jitterRateLimiter struct {
+	workqueue.RateLimiter
+	jitter float64
+}
+
+func (r *jitterRateLimiter) When(item interface{}) time.Duration {
+	return wait.Jitter(r.RateLimiter.When(item), r.jitter)
 }
 
 // HandleOwnedResourceNamespacedFunc returns a function thataccepts a
@@ -19,6 +19,7 @@ package controller
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestBuildAnnotationsToCopy(t *testing.T) {
@@ -63,3 +64,68 @@ func TestBuildAnnotationsToCopy(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkqueueOptionsRateLimiter(t *testing.T) {
+	defaults := RateLimiterOptions{BaseDelay: time.Second, MaxDelay: time.Second * 30}
+	tests := map[string]struct {
+		rateLimiters map[string]RateLimiterOptions
+		expDelays    []time.Duration
+	}{
+		"no overrides uses defaults": {
+			expDelays: []time.Duration{time.Second, time.Second * 2, time.Second * 4},
+		},
+		"override for all controllers": {
+			rateLimiters: map[string]RateLimiterOptions{
+				"*": {BaseDelay: time.Second * 10},
+			},
+			expDelays: []time.Duration{time.Second * 10, time.Second * 20, time.Second * 30},
+		},
+		"controller specific override takes precedence": {
+			rateLimiters: map[string]RateLimiterOptions{
+				"*":    {BaseDelay: time.Second * 10},
+				"test": {BaseDelay: time.Millisecond * 100, MaxDelay: time.Millisecond * 300},
+			},
+			expDelays: []time.Duration{time.Millisecond * 100, time.Millisecond * 200, time.Millisecond * 300},
+		},
+		"override for another controller is ignored": {
+			rateLimiters: map[string]RateLimiterOptions{
+				"other": {BaseDelay: time.Minute},
+			},
+			expDelays: []time.Duration{time.Second, time.Second * 2, time.Second * 4},
+		},
+		"base delay above default max delay raises max delay": {
+			rateLimiters: map[string]RateLimiterOptions{
+				"test": {BaseDelay: time.Minute},
+			},
+			expDelays: []time.Duration{time.Minute, time.Minute},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rl := WorkqueueOptions{RateLimiters: test.rateLimiters}.RateLimiter("test", defaults)
+			for i, exp := range test.expDelays {
+				if got := rl.When("item"); got != exp {
+					t.Errorf("unexpected delay for failure %d, exp=%s got=%s", i+1, exp, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkqueueOptionsRateLimiterJitter(t *testing.T) {
+	rl := WorkqueueOptions{RateLimiters: map[string]RateLimiterOptions{
+		"test": {Jitter: 0.5},
+	}}.RateLimiter("test", RateLimiterOptions{BaseDelay: time.Second, MaxDelay: time.Second})
+
+	for i := 0; i < 100; i++ {
+		got := rl.When("item")
+		if got < time.Second || got > time.Second*3/2 {
+			t.Fatalf("expected delay between 1s and 1.5s, got=%s", got)
+		}
+	}
+
+	rl.Forget("item")
+	if n := rl.NumRequeues("item"); n != 0 {
+		t.Errorf("expected item to be forgotten, got %d requeues", n)
+	}
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/spiffe"
+)
+
+// Volume attributes that configure the certificate requested for a volume.
+// They are set in the volumeAttributes of a pod's CSI inline volume.
+const (
+	IssuerNameKey  = "csi.cert-manager.io/issuer-name"
+	IssuerKindKey  = "csi.cert-manager.io/issuer-kind"
+	IssuerGroupKey = "csi.cert-manager.io/issuer-group"
+
+	CommonNameKey  = "csi.cert-manager.io/common-name"
+	DNSNamesKey    = "csi.cert-manager.io/dns-names"
+	URISANsKey     = "csi.cert-manager.io/uri-sans"
+	DurationKey    = "csi.cert-manager.io/duration"
+	RenewBeforeKey = "csi.cert-manager.io/renew-before"
+	KeyUsagesKey   = "csi.cert-manager.io/key-usages"
+
+	KeyAlgorithmKey = "csi.cert-manager.io/key-algorithm"
+	KeySizeKey      = "csi.cert-manager.io/key-size"
+	KeyEncodingKey  = "csi.cert-manager.io/key-encoding"
+
+	CertificateFileKey = "csi.cert-manager.io/certificate-file"
+	PrivateKeyFileKey  = "csi.cert-manager.io/privatekey-file"
+	CAFileKey          = "csi.cert-manager.io/ca-file"
+
+	// SPIFFETrustDomainKey requests an X.509 SVID for the SPIFFE ID of the
+	// pod's service account in the given trust domain, rather than a
+	// certificate for the common name and SANs set in the other attributes.
+	SPIFFETrustDomainKey = "csi.cert-manager.io/spiffe-trust-domain"
+)
+
+// Volume attributes set by the kubelet, describing the pod that a volume is
+// mounted into. The pod attributes are only set if the CSIDriver has
+// podInfoOnMount enabled.
+const (
+	ephemeralKey          = "csi.storage.k8s.io/ephemeral"
+	podNameKey            = "csi.storage.k8s.io/pod.name"
+	podNamespaceKey       = "csi.storage.k8s.io/pod.namespace"
+	podUIDKey             = "csi.storage.k8s.io/pod.uid"
+	serviceAccountNameKey = "csi.storage.k8s.io/serviceAccount.name"
+)
+
+const (
+	// DefaultDuration is the duration of certificates for volumes that do
+	// not set a duration. Certificates are renewed in place, so they default
+	// to being short lived.
+	DefaultDuration = 24 * time.Hour
+
+	defaultCertificateFile = "tls.crt"
+	defaultPrivateKeyFile  = "tls.key"
+	defaultCAFile          = "ca.crt"
+)
+
+// podInfo identifies the pod that a volume is mounted into.
+type podInfo struct {
+	Name               string
+	Namespace          string
+	UID                string
+	ServiceAccountName string
+}
+
+// volumeFiles are the names of the files written to a volume.
+type volumeFiles struct {
+	Certificate string
+	PrivateKey  string
+	CA          string
+}
+
+// podInfoForAttributes returns the pod that a volume is mounted into from
+// the attributes set by the kubelet.
+func podInfoForAttributes(attr map[string]string) (podInfo, error) {
+	if attr[ephemeralKey] != "true" {
+		return podInfo{}, fmt.Errorf("only ephemeral inline volumes are supported")
+	}
+	pod := podInfo{
+		Name:               attr[podNameKey],
+		Namespace:          attr[podNamespaceKey],
+		UID:                attr[podUIDKey],
+		ServiceAccountName: attr[serviceAccountNameKey],
+	}
+	if len(pod.Name) == 0 || len(pod.Namespace) == 0 || len(pod.UID) == 0 {
+		return podInfo{}, fmt.Errorf("pod information is missing from the volume attributes, ensure the CSIDriver has podInfoOnMount enabled")
+	}
+	return pod, nil
+}
+
+// filesForAttributes returns the names of the files to write to a volume.
+func filesForAttributes(attr map[string]string) (volumeFiles, error) {
+	files := volumeFiles{
+		Certificate: valueOrDefault(attr[CertificateFileKey], defaultCertificateFile),
+		PrivateKey:  valueOrDefault(attr[PrivateKeyFileKey], defaultPrivateKeyFile),
+		CA:          valueOrDefault(attr[CAFileKey], defaultCAFile),
+	}
+	for key, name := range map[string]string{
+		CertificateFileKey: files.Certificate,
+		PrivateKeyFileKey:  files.PrivateKey,
+		CAFileKey:          files.CA,
+	} {
+		if name == "." || strings.ContainsRune(name, os.PathSeparator) || strings.HasPrefix(name, "..") {
+			return volumeFiles{}, fmt.Errorf("%s: invalid file name %q", key, name)
+		}
+	}
+	if files.Certificate == files.PrivateKey || files.Certificate == files.CA || files.PrivateKey == files.CA {
+		return volumeFiles{}, fmt.Errorf("the certificate, private key and CA file names must be unique")
+	}
+	return files, nil
+}
+
+// certificateForAttributes returns a Certificate describing the certificate
+// requested by a volume. It is never created in the API server, and is only
+// used to generate a private key and certificate signing request. References
+// to the pod in the common name and SANs are expanded.
+func certificateForAttributes(attr map[string]string, pod podInfo) (*cmapi.Certificate, error) {
+	if len(attr[IssuerNameKey]) == 0 {
+		return nil, fmt.Errorf("%s must be set", IssuerNameKey)
+	}
+
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		Spec: cmapi.CertificateSpec{
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  attr[IssuerNameKey],
+				Kind:  valueOrDefault(attr[IssuerKindKey], cmapi.IssuerKind),
+				Group: valueOrDefault(attr[IssuerGroupKey], cmapi.SchemeGroupVersion.Group),
+			},
+			Duration: &metav1.Duration{Duration: DefaultDuration},
+			Usages:   cmapi.DefaultKeyUsages(),
+			PrivateKey: &cmapi.CertificatePrivateKey{
+				Algorithm: cmapi.PrivateKeyAlgorithm(valueOrDefault(attr[KeyAlgorithmKey], string(cmapi.ECDSAKeyAlgorithm))),
+				Encoding:  cmapi.PrivateKeyEncoding(valueOrDefault(attr[KeyEncodingKey], string(cmapi.PKCS1))),
+			},
+		},
+	}
+
+	var err error
+	if trustDomain := attr[SPIFFETrustDomainKey]; len(trustDomain) > 0 {
+		if err := setSVIDForAttributes(crt, attr, pod, trustDomain); err != nil {
+			return nil, err
+		}
+	} else {
+		if crt.Spec.CommonName, err = expandPodInfo(attr[CommonNameKey], pod); err != nil {
+			return nil, fmt.Errorf("%s: %w", CommonNameKey, err)
+		}
+		if crt.Spec.DNSNames, err = expandPodInfoList(attr[DNSNamesKey], pod); err != nil {
+			return nil, fmt.Errorf("%s: %w", DNSNamesKey, err)
+		}
+		if crt.Spec.URIs, err = expandPodInfoList(attr[URISANsKey], pod); err != nil {
+			return nil, fmt.Errorf("%s: %w", URISANsKey, err)
+		}
+		if len(crt.Spec.CommonName) == 0 && len(crt.Spec.DNSNames) == 0 && len(crt.Spec.URIs) == 0 {
+			return nil, fmt.Errorf("at least one of %s, %s or %s must be set", CommonNameKey, DNSNamesKey, URISANsKey)
+		}
+	}
+
+	if duration := attr[DurationKey]; len(duration) > 0 {
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", DurationKey, err)
+		}
+		if d < cmapi.MinimumCertificateDuration {
+			return nil, fmt.Errorf("%s: duration must be at least %s", DurationKey, cmapi.MinimumCertificateDuration)
+		}
+		crt.Spec.Duration.Duration = d
+	}
+	if renewBefore := attr[RenewBeforeKey]; len(renewBefore) > 0 {
+		d, err := time.ParseDuration(renewBefore)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", RenewBeforeKey, err)
+		}
+		if d <= 0 || d >= crt.Spec.Duration.Duration {
+			return nil, fmt.Errorf("%s: renew before must be greater than zero and less than the duration", RenewBeforeKey)
+		}
+		crt.Spec.RenewBefore = &metav1.Duration{Duration: d}
+	}
+
+	if usages := splitList(attr[KeyUsagesKey]); len(usages) > 0 {
+		crt.Spec.Usages = nil
+		for _, usage := range usages {
+			crt.Spec.Usages = append(crt.Spec.Usages, cmapi.KeyUsage(usage))
+		}
+	}
+
+	switch crt.Spec.PrivateKey.Algorithm {
+	case cmapi.RSAKeyAlgorithm, cmapi.ECDSAKeyAlgorithm, cmapi.Ed25519KeyAlgorithm:
+	default:
+		return nil, fmt.Errorf("%s: unsupported private key algorithm %q", KeyAlgorithmKey, crt.Spec.PrivateKey.Algorithm)
+	}
+	if size := attr[KeySizeKey]; len(size) > 0 {
+		if crt.Spec.PrivateKey.Size, err = strconv.Atoi(size); err != nil {
+			return nil, fmt.Errorf("%s: %w", KeySizeKey, err)
+		}
+	}
+	switch crt.Spec.PrivateKey.Encoding {
+	case cmapi.PKCS1, cmapi.PKCS8:
+	default:
+		return nil, fmt.Errorf("%s: unsupported private key encoding %q", KeyEncodingKey, crt.Spec.PrivateKey.Encoding)
+	}
+
+	return crt, nil
+}
+
+// setSVIDForAttributes configures the given Certificate as an X.509 SVID for
+// the SPIFFE ID of the pod's service account in the given trust domain. SVIDs
+// default to a shorter duration, and to the key usages of both TLS servers
+// and clients.
+func setSVIDForAttributes(crt *cmapi.Certificate, attr map[string]string, pod podInfo, trustDomain string) error {
+	if err := spiffe.ValidateTrustDomain(trustDomain); err != nil {
+		return fmt.Errorf("%s: %w", SPIFFETrustDomainKey, err)
+	}
+	for _, key := range []string{CommonNameKey, DNSNamesKey, URISANsKey} {
+		if len(attr[key]) > 0 {
+			return fmt.Errorf("%s cannot be set when %s is set", key, SPIFFETrustDomainKey)
+		}
+	}
+	if len(pod.ServiceAccountName) == 0 {
+		return fmt.Errorf("pod service account is missing from the volume attributes, ensure the CSIDriver has podInfoOnMount enabled")
+	}
+
+	crt.Spec.URIs = []string{spiffe.WorkloadID(trustDomain, pod.Namespace, pod.ServiceAccountName)}
+	crt.Spec.Duration.Duration = spiffe.DefaultSVIDDuration
+	crt.Spec.Usages = spiffe.SVIDKeyUsages()
+	return nil
+}
+
+// expandPodInfo replaces references to ${POD_NAME}, ${POD_NAMESPACE},
+// ${POD_UID} and ${SERVICE_ACCOUNT_NAME} in the given string with the
+// attributes of the pod. References to any other variable are an error.
+func expandPodInfo(s string, pod podInfo) (string, error) {
+	var err error
+	expanded := os.Expand(s, func(name string) string {
+		switch name {
+		case "POD_NAME":
+			return pod.Name
+		case "POD_NAMESPACE":
+			return pod.Namespace
+		case "POD_UID":
+			return pod.UID
+		case "SERVICE_ACCOUNT_NAME":
+			return pod.ServiceAccountName
+		}
+		if err == nil {
+			err = fmt.Errorf("unknown variable %q", name)
+		}
+		return ""
+	})
+	return expanded, err
+}
+
+// expandPodInfoList splits the given comma separated list and expands
+// references to the pod in each of its items.
+func expandPodInfoList(s string, pod podInfo) ([]string, error) {
+	var out []string
+	for _, item := range splitList(s) {
+		expanded, err := expandPodInfo(item, pod)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, expanded)
+	}
+	return out, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func valueOrDefault(value, def string) string {
+	if len(value) == 0 {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+var testPod = podInfo{
+	Name:               "httpbin-7d9c5",
+	Namespace:          "sandbox",
+	UID:                "3a0e4d5c-8c2a-4a8f-9d43-2f0f9e1f6a11",
+	ServiceAccountName: "httpbin",
+}
+
+func testAttributes(attr map[string]string) map[string]string {
+	out := map[string]string{
+		ephemeralKey:          "true",
+		podNameKey:            testPod.Name,
+		podNamespaceKey:       testPod.Namespace,
+		podUIDKey:             testPod.UID,
+		serviceAccountNameKey: testPod.ServiceAccountName,
+		IssuerNameKey:         "ca-issuer",
+	}
+	for k, v := range attr {
+		out[k] = v
+	}
+	return out
+}
+
+func TestPodInfoForAttributes(t *testing.T) {
+	tests := map[string]struct {
+		attr   map[string]string
+		expPod podInfo
+		expErr bool
+	}{
+		"should return the pod of an ephemeral volume": {
+			attr:   testAttributes(nil),
+			expPod: testPod,
+		},
+		"should error for volumes that are not ephemeral": {
+			attr:   testAttributes(map[string]string{ephemeralKey: "false"}),
+			expErr: true,
+		},
+		"should error if pod information is missing": {
+			attr:   map[string]string{ephemeralKey: "true"},
+			expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod, err := podInfoForAttributes(test.attr)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			assert.Equal(t, test.expPod, pod)
+		})
+	}
+}
+
+func TestFilesForAttributes(t *testing.T) {
+	tests := map[string]struct {
+		attr     map[string]string
+		expFiles volumeFiles
+		expErr   bool
+	}{
+		"should default the file names": {
+			attr:     testAttributes(nil),
+			expFiles: volumeFiles{Certificate: "tls.crt", PrivateKey: "tls.key", CA: "ca.crt"},
+		},
+		"should use the given file names": {
+			attr: testAttributes(map[string]string{
+				CertificateFileKey: "cert.pem",
+				PrivateKeyFileKey:  "key.pem",
+				CAFileKey:          "ca.pem",
+			}),
+			expFiles: volumeFiles{Certificate: "cert.pem", PrivateKey: "key.pem", CA: "ca.pem"},
+		},
+		"should error if a file name is a path": {
+			attr:   testAttributes(map[string]string{PrivateKeyFileKey: "../../key.pem"}),
+			expErr: true,
+		},
+		"should error if a file name collides with the data directory": {
+			attr:   testAttributes(map[string]string{CAFileKey: dataDirName}),
+			expErr: true,
+		},
+		"should error if file names are not unique": {
+			attr:   testAttributes(map[string]string{CAFileKey: "tls.crt"}),
+			expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			files, err := filesForAttributes(test.attr)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			assert.Equal(t, test.expFiles, files)
+		})
+	}
+}
+
+func TestCertificateForAttributes(t *testing.T) {
+	defaultSpec := func(mod func(*cmapi.CertificateSpec)) cmapi.CertificateSpec {
+		spec := cmapi.CertificateSpec{
+			IssuerRef:  cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "cert-manager.io"},
+			Duration:   &metav1.Duration{Duration: DefaultDuration},
+			Usages:     cmapi.DefaultKeyUsages(),
+			PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm, Encoding: cmapi.PKCS1},
+		}
+		mod(&spec)
+		return spec
+	}
+
+	tests := map[string]struct {
+		attr    map[string]string
+		expSpec cmapi.CertificateSpec
+		expErr  bool
+	}{
+		"should default the issuer, duration, usages and private key": {
+			attr: testAttributes(map[string]string{DNSNamesKey: "example.com"}),
+			expSpec: defaultSpec(func(spec *cmapi.CertificateSpec) {
+				spec.DNSNames = []string{"example.com"}
+			}),
+		},
+		"should expand references to the pod": {
+			attr: testAttributes(map[string]string{
+				CommonNameKey: "${POD_NAME}",
+				DNSNamesKey:   "${POD_NAME}.${POD_NAMESPACE}.svc, ${POD_UID}.example.com",
+				URISANsKey:    "spiffe://cluster.local/ns/${POD_NAMESPACE}/sa/${SERVICE_ACCOUNT_NAME}",
+			}),
+			expSpec: defaultSpec(func(spec *cmapi.CertificateSpec) {
+				spec.CommonName = testPod.Name
+				spec.DNSNames = []string{testPod.Name + ".sandbox.svc", testPod.UID + ".example.com"}
+				spec.URIs = []string{"spiffe://cluster.local/ns/sandbox/sa/httpbin"}
+			}),
+		},
+		"should use the given issuer, duration, usages and private key": {
+			attr: testAttributes(map[string]string{
+				CommonNameKey:   "example",
+				IssuerKindKey:   "ClusterIssuer",
+				DurationKey:     "2h",
+				RenewBeforeKey:  "30m",
+				KeyUsagesKey:    "digital signature, client auth",
+				KeyAlgorithmKey: "RSA",
+				KeySizeKey:      "4096",
+				KeyEncodingKey:  "PKCS8",
+			}),
+			expSpec: defaultSpec(func(spec *cmapi.CertificateSpec) {
+				spec.CommonName = "example"
+				spec.IssuerRef.Kind = "ClusterIssuer"
+				spec.Duration = &metav1.Duration{Duration: 2 * time.Hour}
+				spec.RenewBefore = &metav1.Duration{Duration: 30 * time.Minute}
+				spec.Usages = []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageClientAuth}
+				spec.PrivateKey = &cmapi.CertificatePrivateKey{Algorithm: cmapi.RSAKeyAlgorithm, Size: 4096, Encoding: cmapi.PKCS8}
+			}),
+		},
+		"should request an SVID for the pod's service account": {
+			attr: testAttributes(map[string]string{SPIFFETrustDomainKey: "cluster.local"}),
+			expSpec: defaultSpec(func(spec *cmapi.CertificateSpec) {
+				spec.URIs = []string{"spiffe://cluster.local/ns/sandbox/sa/httpbin"}
+				spec.Duration = &metav1.Duration{Duration: time.Hour}
+				spec.Usages = []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageServerAuth, cmapi.UsageClientAuth}
+			}),
+		},
+		"should use the given duration and usages for an SVID": {
+			attr: testAttributes(map[string]string{
+				SPIFFETrustDomainKey: "cluster.local",
+				DurationKey:          "2h",
+				KeyUsagesKey:         "digital signature, client auth",
+			}),
+			expSpec: defaultSpec(func(spec *cmapi.CertificateSpec) {
+				spec.URIs = []string{"spiffe://cluster.local/ns/sandbox/sa/httpbin"}
+				spec.Duration = &metav1.Duration{Duration: 2 * time.Hour}
+				spec.Usages = []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageClientAuth}
+			}),
+		},
+		"should error if an SVID also sets SANs": {
+			attr:   testAttributes(map[string]string{SPIFFETrustDomainKey: "cluster.local", DNSNamesKey: "example.com"}),
+			expErr: true,
+		},
+		"should error if the SPIFFE trust domain is invalid": {
+			attr:   testAttributes(map[string]string{SPIFFETrustDomainKey: "Cluster.local"}),
+			expErr: true,
+		},
+		"should error if the issuer name is not set": {
+			attr:   testAttributes(map[string]string{IssuerNameKey: "", CommonNameKey: "example"}),
+			expErr: true,
+		},
+		"should error if no common name or SANs are set": {
+			attr:   testAttributes(nil),
+			expErr: true,
+		},
+		"should error if an unknown variable is referenced": {
+			attr:   testAttributes(map[string]string{CommonNameKey: "${NODE_NAME}"}),
+			expErr: true,
+		},
+		"should error if the duration is too short": {
+			attr:   testAttributes(map[string]string{CommonNameKey: "example", DurationKey: "10m"}),
+			expErr: true,
+		},
+		"should error if renew before is not less than the duration": {
+			attr:   testAttributes(map[string]string{CommonNameKey: "example", RenewBeforeKey: "24h"}),
+			expErr: true,
+		},
+		"should error for an unsupported private key algorithm": {
+			attr:   testAttributes(map[string]string{CommonNameKey: "example", KeyAlgorithmKey: "DSA"}),
+			expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt, err := certificateForAttributes(test.attr, testPod)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+			assert.Equal(t, test.expSpec, crt.Spec)
+			assert.Equal(t, testPod.Namespace, crt.Namespace)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package csi implements a CSI driver that mounts a certificate issued by a
+// cert-manager issuer into each pod that uses one of its ephemeral inline
+// volumes. The certificate is requested using a CertificateRequest in the
+// pod's namespace, and is renewed in place for as long as the pod runs. The
+// private key never leaves the node, and is never stored in a Secret or
+// written to disk.
+package csi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Driver implements the CSI identity and node services. The controller
+// service is not implemented, since only ephemeral inline volumes are
+// supported.
+type Driver struct {
+	csi.UnimplementedIdentityServer
+	csi.UnimplementedNodeServer
+
+	// Name is the name of the driver, which must match the name of the
+	// CSIDriver object.
+	Name string
+	// Version is the version of the driver.
+	Version string
+	// NodeID is the name of the node that the driver is running on.
+	NodeID string
+
+	Manager *Manager
+}
+
+var _ csi.IdentityServer = &Driver{}
+var _ csi.NodeServer = &Driver{}
+
+// NewGRPCServer returns a gRPC server that serves the CSI identity and node
+// services using the given Driver.
+func NewGRPCServer(d *Driver, opts ...grpc.ServerOption) *grpc.Server {
+	gs := grpc.NewServer(opts...)
+	csi.RegisterIdentityServer(gs, d)
+	csi.RegisterNodeServer(gs, d)
+	return gs
+}
+
+func (d *Driver) GetPluginInfo(context.Context, *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
+	return &csi.GetPluginInfoResponse{
+		Name:          d.Name,
+		VendorVersion: d.Version,
+	}, nil
+}
+
+func (d *Driver) GetPluginCapabilities(context.Context, *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
+	return &csi.GetPluginCapabilitiesResponse{}, nil
+}
+
+func (d *Driver) Probe(context.Context, *csi.ProbeRequest) (*csi.ProbeResponse, error) {
+	return &csi.ProbeResponse{Ready: wrapperspb.Bool(true)}, nil
+}
+
+func (d *Driver) NodeGetInfo(context.Context, *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+	return &csi.NodeGetInfoResponse{NodeId: d.NodeID}, nil
+}
+
+func (d *Driver) NodeGetCapabilities(context.Context, *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
+	return &csi.NodeGetCapabilitiesResponse{}, nil
+}
+
+// NodePublishVolume issues a certificate for the volume of a pod and mounts
+// it at the volume's target path.
+func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	if err := validateVolumeID(req.GetVolumeId()); err != nil {
+		return nil, err
+	}
+	if len(req.GetTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "target path must be set")
+	}
+	if req.GetVolumeCapability().GetMount() == nil {
+		return nil, status.Error(codes.InvalidArgument, "only mount volumes are supported")
+	}
+	if !req.GetReadonly() {
+		return nil, status.Error(codes.InvalidArgument, "volumes must be mounted read only")
+	}
+
+	attr := req.GetVolumeContext()
+	pod, err := podInfoForAttributes(attr)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := filesForAttributes(attr); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := certificateForAttributes(attr, pod); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = d.Manager.Publish(ctx, metadata{
+		VolumeID:      req.GetVolumeId(),
+		TargetPath:    req.GetTargetPath(),
+		VolumeContext: attr,
+	})
+	if err != nil {
+		return nil, operationError(err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// NodeUnpublishVolume unmounts the volume of a pod and stops renewing its
+// certificate.
+func (d *Driver) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
+	if err := validateVolumeID(req.GetVolumeId()); err != nil {
+		return nil, err
+	}
+	if len(req.GetTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "target path must be set")
+	}
+
+	if err := d.Manager.Unpublish(req.GetVolumeId(), req.GetTargetPath()); err != nil {
+		return nil, operationError(err)
+	}
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// validateVolumeID ensures the given volume ID can safely be used as the name
+// of the volume's directory in the data root.
+func validateVolumeID(volumeID string) error {
+	if len(volumeID) == 0 {
+		return status.Error(codes.InvalidArgument, "volume ID must be set")
+	}
+	if volumeID == "." || strings.ContainsRune(volumeID, os.PathSeparator) || strings.HasPrefix(volumeID, "..") {
+		return status.Errorf(codes.InvalidArgument, "invalid volume ID %q", volumeID)
+	}
+	return nil
+}
+
+func operationError(err error) error {
+	if errors.Is(err, ErrOperationInProgress) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
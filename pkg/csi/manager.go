@@ -0,0 +1,618 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// Labels and annotations added to the CertificateRequests created for a
+// volume, identifying the pod that requested the certificate. They may be
+// used by approvers to decide whether to approve a request.
+const (
+	PodUIDLabelKey                 = "csi.cert-manager.io/pod-uid"
+	PodNameAnnotationKey           = "csi.cert-manager.io/pod-name"
+	PodServiceAccountAnnotationKey = "csi.cert-manager.io/pod-service-account"
+	VolumeIDAnnotationKey          = "csi.cert-manager.io/volume-id"
+)
+
+const (
+	defaultPollInterval   = time.Second
+	defaultRenewalTimeout = 5 * time.Minute
+
+	initialRenewalRetryInterval = 10 * time.Second
+	maxRenewalRetryInterval     = 5 * time.Minute
+
+	metadataFileName  = "metadata.json"
+	volumeDataDirName = "data"
+)
+
+// ErrOperationInProgress is returned if an operation on a volume is already
+// in progress.
+var ErrOperationInProgress = errors.New("an operation on the volume is already in progress")
+
+// Manager issues the certificates of the volumes published on a node, and
+// renews them in place for as long as the volume is published.
+//
+// The files of each volume are written to a tmpfs mounted in the volume's
+// directory under the data root, which is then bind mounted read-only into
+// the pod. Private keys are therefore never written to disk.
+type Manager struct {
+	client   cmclient.Interface
+	mounter  Mounter
+	dataRoot string
+
+	// preserveCertificateRequests disables the deletion of
+	// CertificateRequests once they have been signed or have failed.
+	preserveCertificateRequests bool
+
+	log logr.Logger
+
+	// pollInterval is how often a CertificateRequest is checked for
+	// completion. Used for testing.
+	pollInterval time.Duration
+
+	lock     sync.Mutex
+	volumes  map[string]*volume
+	inFlight map[string]struct{}
+}
+
+// metadata is the state of a published volume that is persisted in the data
+// root, so that renewal can resume if the driver is restarted.
+type metadata struct {
+	VolumeID      string            `json:"volumeID"`
+	TargetPath    string            `json:"targetPath"`
+	VolumeContext map[string]string `json:"volumeContext"`
+}
+
+// volume is a published volume whose certificate is being managed.
+type volume struct {
+	metadata
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewManager returns a Manager that stores the state and files of volumes in
+// the given data root directory.
+func NewManager(client cmclient.Interface, mounter Mounter, dataRoot string, preserveCertificateRequests bool, log logr.Logger) *Manager {
+	return &Manager{
+		client:                      client,
+		mounter:                     mounter,
+		dataRoot:                    dataRoot,
+		preserveCertificateRequests: preserveCertificateRequests,
+		log:                         log,
+		pollInterval:                defaultPollInterval,
+		volumes:                     make(map[string]*volume),
+		inFlight:                    make(map[string]struct{}),
+	}
+}
+
+// Resume starts managing the volumes that were published before the driver
+// was restarted. It should be called once, before any volumes are published.
+func (m *Manager) Resume() error {
+	entries, err := os.ReadDir(m.dataRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		log := m.log.WithValues("volume_id", entry.Name())
+
+		meta, err := m.readMetadata(entry.Name())
+		if err != nil {
+			log.Error(err, "failed to read volume metadata, skipping volume")
+			continue
+		}
+
+		// If the tmpfs holding the volume's files is no longer mounted, for
+		// example because the node was restarted, its files are gone.
+		// Clean up the volume, it will be published again if it is still
+		// in use.
+		notMnt, err := m.mounter.IsLikelyNotMountPoint(m.dataDir(meta.VolumeID))
+		if err != nil || notMnt {
+			log.V(logf.InfoLevel).Info("volume data is no longer mounted, cleaning up volume")
+			if err := m.cleanup(meta); err != nil {
+				log.Error(err, "failed to clean up volume")
+			}
+			continue
+		}
+
+		var cert *x509.Certificate
+		if files, err := filesForAttributes(meta.VolumeContext); err == nil {
+			if certPEM, err := readFile(m.dataDir(meta.VolumeID), files.Certificate); err == nil {
+				cert, _ = pki.DecodeX509CertificateBytes(certPEM)
+			}
+		}
+		if cert == nil {
+			log.V(logf.InfoLevel).Info("failed to read existing certificate, renewing immediately")
+		}
+
+		log.V(logf.DebugLevel).Info("resuming management of volume")
+		m.startManaging(meta, cert)
+	}
+
+	return nil
+}
+
+// Publish issues a certificate for the given volume and mounts it at the
+// volume's target path. The certificate is then renewed until the volume is
+// unpublished. Publishing a volume that is already published ensures that it
+// is mounted at the target path.
+func (m *Manager) Publish(ctx context.Context, meta metadata) error {
+	if err := m.beginOperation(meta.VolumeID); err != nil {
+		return err
+	}
+	defer m.endOperation(meta.VolumeID)
+
+	log := m.log.WithValues("volume_id", meta.VolumeID)
+
+	if m.isManaged(meta.VolumeID) {
+		return m.mountTarget(meta)
+	}
+
+	cert, err := m.publish(ctx, meta)
+	if err != nil {
+		if err := m.cleanup(meta); err != nil {
+			log.Error(err, "failed to clean up volume after failing to publish it")
+		}
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("published volume")
+	m.startManaging(meta, cert)
+	return nil
+}
+
+func (m *Manager) publish(ctx context.Context, meta metadata) (*x509.Certificate, error) {
+	dataDir := m.dataDir(meta.VolumeID)
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create volume data directory: %w", err)
+	}
+	notMnt, err := m.mounter.IsLikelyNotMountPoint(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check volume data directory mount: %w", err)
+	}
+	if notMnt {
+		if err := m.mounter.Mount("tmpfs", dataDir, "tmpfs", []string{"mode=0755"}); err != nil {
+			return nil, fmt.Errorf("failed to mount volume data directory: %w", err)
+		}
+	}
+
+	if err := m.writeMetadata(meta); err != nil {
+		return nil, err
+	}
+
+	cert, err := m.issue(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.mountTarget(meta); err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// Unpublish stops renewing the certificate of the given volume, unmounts it
+// from its target path, and removes its files.
+func (m *Manager) Unpublish(volumeID, targetPath string) error {
+	if err := m.beginOperation(volumeID); err != nil {
+		return err
+	}
+	defer m.endOperation(volumeID)
+
+	m.lock.Lock()
+	v, ok := m.volumes[volumeID]
+	delete(m.volumes, volumeID)
+	m.lock.Unlock()
+	if ok {
+		close(v.stopCh)
+		<-v.doneCh
+	}
+
+	if err := m.cleanup(metadata{VolumeID: volumeID, TargetPath: targetPath}); err != nil {
+		return err
+	}
+
+	m.log.V(logf.InfoLevel).Info("unpublished volume", "volume_id", volumeID)
+	return nil
+}
+
+// Stop stops renewing the certificates of all volumes, leaving them
+// published. Renewal is resumed by Resume when the driver is restarted.
+func (m *Manager) Stop() {
+	m.lock.Lock()
+	volumes := m.volumes
+	m.volumes = make(map[string]*volume)
+	m.lock.Unlock()
+
+	for _, v := range volumes {
+		close(v.stopCh)
+		<-v.doneCh
+	}
+}
+
+func (m *Manager) beginOperation(volumeID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.inFlight[volumeID]; ok {
+		return ErrOperationInProgress
+	}
+	m.inFlight[volumeID] = struct{}{}
+	return nil
+}
+
+func (m *Manager) endOperation(volumeID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.inFlight, volumeID)
+}
+
+func (m *Manager) isManaged(volumeID string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, ok := m.volumes[volumeID]
+	return ok
+}
+
+// startManaging starts renewing the certificate of the given volume. The
+// certificate is renewed immediately if the current certificate is nil.
+func (m *Manager) startManaging(meta metadata, cert *x509.Certificate) {
+	v := &volume{
+		metadata: meta,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+
+	m.lock.Lock()
+	m.volumes[meta.VolumeID] = v
+	m.lock.Unlock()
+
+	go m.manageVolume(v, cert)
+}
+
+// manageVolume renews the certificate of the given volume until it is
+// stopped. Failed renewals are retried with an exponential backoff.
+func (m *Manager) manageVolume(v *volume, cert *x509.Certificate) {
+	defer close(v.doneCh)
+	log := m.log.WithValues("volume_id", v.VolumeID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-v.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoff := newRenewalBackoff()
+	next := time.Now()
+	if cert != nil {
+		next = m.renewalTime(v.metadata, cert)
+	}
+
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-v.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		log.V(logf.DebugLevel).Info("renewing certificate")
+		issueCtx, issueCancel := context.WithTimeout(ctx, defaultRenewalTimeout)
+		renewed, err := m.issue(issueCtx, v.metadata)
+		issueCancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			delay := backoff.Step()
+			log.Error(err, "failed to renew certificate, retrying", "retry_after", delay)
+			next = time.Now().Add(delay)
+			continue
+		}
+
+		log.V(logf.InfoLevel).Info("renewed certificate", "not_after", renewed.NotAfter)
+		backoff = newRenewalBackoff()
+		next = m.renewalTime(v.metadata, renewed)
+	}
+}
+
+func newRenewalBackoff() *wait.Backoff {
+	return &wait.Backoff{
+		Duration: initialRenewalRetryInterval,
+		Factor:   2,
+		Steps:    10,
+		Cap:      maxRenewalRetryInterval,
+	}
+}
+
+// renewalTime returns the time at which the given certificate of a volume
+// should be renewed, honouring the volume's renew before attribute.
+func (m *Manager) renewalTime(meta metadata, cert *x509.Certificate) time.Time {
+	var renewBefore *metav1.Duration
+	if pod, err := podInfoForAttributes(meta.VolumeContext); err == nil {
+		if crt, err := certificateForAttributes(meta.VolumeContext, pod); err == nil {
+			renewBefore = crt.Spec.RenewBefore
+		}
+	}
+	return certificates.RenewalTime(cert.NotBefore, cert.NotAfter, renewBefore).Time
+}
+
+// issue requests a certificate for the given volume using a new private key,
+// and writes the key, certificate and CA to the volume.
+func (m *Manager) issue(ctx context.Context, meta metadata) (*x509.Certificate, error) {
+	pod, err := podInfoForAttributes(meta.VolumeContext)
+	if err != nil {
+		return nil, err
+	}
+	files, err := filesForAttributes(meta.VolumeContext)
+	if err != nil {
+		return nil, err
+	}
+	crt, err := certificateForAttributes(meta.VolumeContext, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := pki.GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	template, err := pki.GenerateCSR(crt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate signing request: %w", err)
+	}
+	csrDER, err := pki.EncodeCSR(template, pk)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := pki.EncodePrivateKey(pk, crt.Spec.PrivateKey.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	cr, err := m.client.CertmanagerV1().CertificateRequests(pod.Namespace).Create(ctx, &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pod.Name + "-",
+			Namespace:    pod.Namespace,
+			Labels:       map[string]string{PodUIDLabelKey: pod.UID},
+			Annotations: map[string]string{
+				PodNameAnnotationKey:           pod.Name,
+				PodServiceAccountAnnotationKey: pod.ServiceAccountName,
+				VolumeIDAnnotationKey:          meta.VolumeID,
+			},
+			// CertificateRequests are garbage collected along with the pod.
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.Name,
+				UID:        types.UID(pod.UID),
+			}},
+		},
+		Spec: cmapi.CertificateRequestSpec{
+			Request:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+			Duration:  crt.Spec.Duration,
+			IssuerRef: crt.Spec.IssuerRef,
+			Usages:    crt.Spec.Usages,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CertificateRequest: %w", err)
+	}
+	log := m.log.WithValues("volume_id", meta.VolumeID, "certificaterequest", cr.Namespace+"/"+cr.Name)
+	log.V(logf.DebugLevel).Info("created CertificateRequest")
+
+	if !m.preserveCertificateRequests {
+		defer func() {
+			// The context may already be cancelled, so clean up using a
+			// context of its own.
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := m.client.CertmanagerV1().CertificateRequests(cr.Namespace).Delete(ctx, cr.Name, metav1.DeleteOptions{}); err != nil {
+				log.Error(err, "failed to delete CertificateRequest")
+			}
+		}()
+	}
+
+	signed, err := m.waitForCertificateRequest(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(signed.Status.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signed certificate: %w", err)
+	}
+	if ok, err := pki.PublicKeyMatchesCertificate(pk.Public(), cert); err != nil || !ok {
+		return nil, fmt.Errorf("signed certificate does not match the private key")
+	}
+
+	if err := writeFiles(m.dataDir(meta.VolumeID), map[string][]byte{
+		files.Certificate: signed.Status.Certificate,
+		files.PrivateKey:  keyPEM,
+		files.CA:          signed.Status.CA,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write volume files: %w", err)
+	}
+
+	return cert, nil
+}
+
+// waitForCertificateRequest waits for the given CertificateRequest to be
+// signed, returning an error if it fails, is denied or the context is
+// cancelled.
+func (m *Manager) waitForCertificateRequest(ctx context.Context, cr *cmapi.CertificateRequest) (*cmapi.CertificateRequest, error) {
+	var signed *cmapi.CertificateRequest
+	var failure error
+	err := wait.PollImmediateUntil(m.pollInterval, func() (bool, error) {
+		cr, err := m.client.CertmanagerV1().CertificateRequests(cr.Namespace).Get(ctx, cr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case apiutil.CertificateRequestIsDenied(cr):
+			failure = fmt.Errorf("CertificateRequest %s/%s was denied", cr.Namespace, cr.Name)
+			return true, nil
+		case apiutil.CertificateRequestHasInvalidRequest(cr):
+			failure = fmt.Errorf("CertificateRequest %s/%s is invalid: %s", cr.Namespace, cr.Name, apiutil.CertificateRequestInvalidRequestMessage(cr))
+			return true, nil
+		case apiutil.CertificateRequestReadyReason(cr) == cmapi.CertificateRequestReasonFailed:
+			failure = fmt.Errorf("issuer failed to sign CertificateRequest %s/%s", cr.Namespace, cr.Name)
+			return true, nil
+		case apiutil.CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}) && len(cr.Status.Certificate) > 0:
+			signed = cr
+			return true, nil
+		}
+		return false, nil
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		return nil, fmt.Errorf("timed out waiting for CertificateRequest %s/%s to be signed", cr.Namespace, cr.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CertificateRequest %s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	return signed, nil
+}
+
+// mountTarget bind mounts the files of the given volume read-only at its
+// target path, if they are not already mounted.
+func (m *Manager) mountTarget(meta metadata) error {
+	if err := os.MkdirAll(meta.TargetPath, 0750); err != nil {
+		return fmt.Errorf("failed to create target path: %w", err)
+	}
+	notMnt, err := m.mounter.IsLikelyNotMountPoint(meta.TargetPath)
+	if err != nil {
+		return fmt.Errorf("failed to check target path mount: %w", err)
+	}
+	if !notMnt {
+		return nil
+	}
+	if err := m.mounter.Mount(m.dataDir(meta.VolumeID), meta.TargetPath, "", []string{"bind", "ro"}); err != nil {
+		return fmt.Errorf("failed to mount volume at target path: %w", err)
+	}
+	return nil
+}
+
+// cleanup unmounts the given volume from its target path, and unmounts and
+// removes its files and metadata.
+func (m *Manager) cleanup(meta metadata) error {
+	if len(meta.TargetPath) > 0 {
+		if err := m.unmount(meta.TargetPath); err != nil {
+			return fmt.Errorf("failed to unmount target path: %w", err)
+		}
+		if err := os.Remove(meta.TargetPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove target path: %w", err)
+		}
+	}
+	if err := m.unmount(m.dataDir(meta.VolumeID)); err != nil {
+		return fmt.Errorf("failed to unmount volume data directory: %w", err)
+	}
+	if err := os.RemoveAll(m.volumeDir(meta.VolumeID)); err != nil {
+		return fmt.Errorf("failed to remove volume directory: %w", err)
+	}
+	return nil
+}
+
+// unmount unmounts the given path if it exists and is a mount point.
+func (m *Manager) unmount(path string) error {
+	notMnt, err := m.mounter.IsLikelyNotMountPoint(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if notMnt {
+		return nil
+	}
+	return m.mounter.Unmount(path)
+}
+
+func (m *Manager) readMetadata(volumeID string) (metadata, error) {
+	data, err := os.ReadFile(filepath.Join(m.volumeDir(volumeID), metadataFileName))
+	if err != nil {
+		return metadata{}, err
+	}
+	var meta metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return metadata{}, err
+	}
+	if meta.VolumeID != volumeID {
+		return metadata{}, fmt.Errorf("volume ID %q in metadata does not match directory", meta.VolumeID)
+	}
+	return meta, nil
+}
+
+func (m *Manager) writeMetadata(meta metadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(m.volumeDir(meta.VolumeID), metadataFileName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write volume metadata: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) volumeDir(volumeID string) string {
+	return filepath.Join(m.dataRoot, volumeID)
+}
+
+func (m *Manager) dataDir(volumeID string) string {
+	return filepath.Join(m.volumeDir(volumeID), volumeDataDirName)
+}
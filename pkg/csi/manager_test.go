@@ -0,0 +1,273 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	"k8s.io/mount-utils"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// newTestManager returns a Manager whose CertificateRequests are signed by a
+// self-signed CA, or denied if deny is true.
+func newTestManager(t *testing.T, deny bool) (*Manager, *cmfake.Clientset, *mount.FakeMounter) {
+	caKey, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		PublicKey:             caKey.Public(),
+	}
+	caPEM, caCert, err := pki.SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := cmfake.NewSimpleClientset()
+	client.PrependReactor("create", "certificaterequests", func(action coretesting.Action) (bool, runtime.Object, error) {
+		// Act as the issuer, modifying the object stored by the default
+		// reactor.
+		cr := action.(coretesting.CreateAction).GetObject().(*cmapi.CertificateRequest)
+		cr.Name = cr.GenerateName + "1"
+		if deny {
+			apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionDenied, cmmeta.ConditionTrue, "Denied", "denied")
+			return false, nil, nil
+		}
+		template, err := pki.GenerateTemplateFromCertificateRequest(cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certPEM, _, err := pki.SignCertificate(template, caCert, template.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cr.Status.Certificate = certPEM
+		cr.Status.CA = caPEM
+		apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady, cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "issued")
+		return false, nil, nil
+	})
+
+	mounter := mount.NewFakeMounter(nil)
+	m := NewManager(client, mounter, t.TempDir(), false, logf.Log)
+	m.pollInterval = time.Millisecond
+	t.Cleanup(m.Stop)
+	return m, client, mounter
+}
+
+func testMetadata(t *testing.T) metadata {
+	return metadata{
+		VolumeID:   "csi-0123456789abcdef",
+		TargetPath: filepath.Join(t.TempDir(), "mount"),
+		VolumeContext: testAttributes(map[string]string{
+			DNSNamesKey: "${POD_NAME}.${POD_NAMESPACE}.svc",
+		}),
+	}
+}
+
+func isMounted(t *testing.T, mounter *mount.FakeMounter, path string) bool {
+	notMnt, err := mounter.IsLikelyNotMountPoint(path)
+	return err == nil && !notMnt
+}
+
+func TestManagerPublish(t *testing.T) {
+	m, client, mounter := newTestManager(t, false)
+	meta := testMetadata(t)
+
+	if err := m.Publish(context.TODO(), meta); err != nil {
+		t.Fatal(err)
+	}
+
+	dataDir := m.dataDir(meta.VolumeID)
+	if !isMounted(t, mounter, dataDir) {
+		t.Errorf("expected a tmpfs to be mounted at %s", dataDir)
+	}
+	if !isMounted(t, mounter, meta.TargetPath) {
+		t.Errorf("expected the volume to be mounted at %s", meta.TargetPath)
+	}
+
+	certPEM, err := os.ReadFile(filepath.Join(dataDir, "tls.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := pki.DecodeX509CertificateBytes(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "httpbin-7d9c5.sandbox.svc" {
+		t.Errorf("unexpected DNS names %v", cert.DNSNames)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dataDir, "tls.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := pki.DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := pki.PublicKeyMatchesCertificate(key.Public(), cert); err != nil || !ok {
+		t.Errorf("expected the private key to match the certificate")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "ca.crt")); err != nil {
+		t.Errorf("expected the CA to be written: %v", err)
+	}
+
+	var created *cmapi.CertificateRequest
+	for _, action := range client.Actions() {
+		if action.Matches("create", "certificaterequests") {
+			created = action.(coretesting.CreateAction).GetObject().(*cmapi.CertificateRequest)
+		}
+	}
+	if created == nil {
+		t.Fatal("expected a CertificateRequest to be created")
+	}
+	if created.Namespace != testPod.Namespace || created.Labels[PodUIDLabelKey] != testPod.UID ||
+		created.Annotations[VolumeIDAnnotationKey] != meta.VolumeID {
+		t.Errorf("unexpected CertificateRequest metadata %+v", created.ObjectMeta)
+	}
+	if len(created.OwnerReferences) != 1 || string(created.OwnerReferences[0].UID) != testPod.UID {
+		t.Errorf("expected the CertificateRequest to be owned by the pod, got %+v", created.OwnerReferences)
+	}
+	if _, err := client.CertmanagerV1().CertificateRequests(created.Namespace).Get(context.TODO(), created.Name, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the CertificateRequest to be deleted")
+	}
+
+	// Publishing the volume again should not request another certificate.
+	client.ClearActions()
+	if err := m.Publish(context.TODO(), meta); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Errorf("expected no actions publishing a published volume, got %v", client.Actions())
+	}
+
+	if err := m.Unpublish(meta.VolumeID, meta.TargetPath); err != nil {
+		t.Fatal(err)
+	}
+	if isMounted(t, mounter, meta.TargetPath) || isMounted(t, mounter, dataDir) {
+		t.Errorf("expected the volume to be unmounted")
+	}
+	if _, err := os.Stat(m.volumeDir(meta.VolumeID)); !os.IsNotExist(err) {
+		t.Errorf("expected the volume directory to be removed, got %v", err)
+	}
+}
+
+func TestManagerPublishDenied(t *testing.T) {
+	m, _, mounter := newTestManager(t, true)
+	meta := testMetadata(t)
+
+	if err := m.Publish(context.TODO(), meta); err == nil {
+		t.Fatal("expected an error publishing a volume whose CertificateRequest is denied")
+	}
+	if isMounted(t, mounter, meta.TargetPath) {
+		t.Errorf("expected the volume not to be mounted")
+	}
+	if _, err := os.Stat(m.volumeDir(meta.VolumeID)); !os.IsNotExist(err) {
+		t.Errorf("expected the volume directory to be removed, got %v", err)
+	}
+	if m.isManaged(meta.VolumeID) {
+		t.Errorf("expected the volume not to be managed")
+	}
+}
+
+func TestManagerResume(t *testing.T) {
+	m, _, mounter := newTestManager(t, false)
+	mounted, unmounted := testMetadata(t), testMetadata(t)
+	unmounted.VolumeID = "csi-fedcba9876543210"
+
+	for _, meta := range []metadata{mounted, unmounted} {
+		if err := m.Publish(context.TODO(), meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.Stop()
+
+	// Simulate the tmpfs of one volume being lost, such as when the node is
+	// restarted.
+	if err := mounter.Unmount(m.dataDir(unmounted.VolumeID)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if !m.isManaged(mounted.VolumeID) {
+		t.Errorf("expected management of the mounted volume to resume")
+	}
+	if m.isManaged(unmounted.VolumeID) {
+		t.Errorf("expected the unmounted volume not to be managed")
+	}
+	if _, err := os.Stat(m.volumeDir(unmounted.VolumeID)); !os.IsNotExist(err) {
+		t.Errorf("expected the unmounted volume's directory to be removed, got %v", err)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, contents := range []string{"first", "second"} {
+		if err := writeFiles(dir, map[string][]byte{"tls.crt": []byte(contents + " cert"), "tls.key": []byte(contents + " key")}); err != nil {
+			t.Fatal(err)
+		}
+		for name, exp := range map[string]string{"tls.crt": contents + " cert", "tls.key": contents + " key"} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != exp {
+				t.Errorf("unexpected contents of %s, exp=%q got=%q", name, exp, data)
+			}
+		}
+	}
+
+	// Only the ..data link, its target and the links to each file should
+	// remain after the files are replaced.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("expected 4 entries in the volume directory, got %v", names)
+	}
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+// Mounter mounts the files of volumes.
+type Mounter interface {
+	// Mount mounts source at target using the given filesystem type and
+	// options. The "bind" and "ro" options create a read-only bind mount,
+	// any other options are passed to the filesystem.
+	Mount(source, target, fstype string, options []string) error
+	// Unmount unmounts target.
+	Unmount(target string) error
+	// IsLikelyNotMountPoint returns true if file is not a mount point. It
+	// may not detect bind mounts of a directory on the same filesystem.
+	IsLikelyNotMountPoint(file string) (bool, error)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// syscallMounter mounts using system calls rather than the mount binary,
+// which is not available in the driver's image.
+type syscallMounter struct{}
+
+// NewMounter returns the Mounter used to mount volumes on the node.
+func NewMounter() Mounter {
+	return syscallMounter{}
+}
+
+func (syscallMounter) Mount(source, target, fstype string, options []string) error {
+	var bind, readOnly bool
+	var data []string
+	for _, option := range options {
+		switch option {
+		case "bind":
+			bind = true
+		case "ro":
+			readOnly = true
+		default:
+			data = append(data, option)
+		}
+	}
+
+	var flags uintptr
+	if bind {
+		flags |= syscall.MS_BIND
+	} else if readOnly {
+		flags |= syscall.MS_RDONLY
+	}
+	if err := syscall.Mount(source, target, fstype, flags, strings.Join(data, ",")); err != nil {
+		return fmt.Errorf("failed to mount %s at %s: %w", source, target, err)
+	}
+
+	// The read-only flag is ignored when creating a bind mount, so it must
+	// be remounted to make it read-only.
+	if bind && readOnly {
+		if err := syscall.Mount("", target, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+			_ = syscall.Unmount(target, 0)
+			return fmt.Errorf("failed to remount %s read-only: %w", target, err)
+		}
+	}
+
+	return nil
+}
+
+func (syscallMounter) Unmount(target string) error {
+	if err := syscall.Unmount(target, 0); err != nil {
+		return fmt.Errorf("failed to unmount %s: %w", target, err)
+	}
+	return nil
+}
+
+// IsLikelyNotMountPoint compares the device of the given file to that of its
+// parent directory, as mount points are on a different device to their
+// parent.
+func (syscallMounter) IsLikelyNotMountPoint(file string) (bool, error) {
+	stat, err := os.Stat(file)
+	if err != nil {
+		return true, err
+	}
+	parentStat, err := os.Lstat(filepath.Dir(strings.TrimSuffix(file, "/")))
+	if err != nil {
+		return true, err
+	}
+	return stat.Sys().(*syscall.Stat_t).Dev == parentStat.Sys().(*syscall.Stat_t).Dev, nil
+}
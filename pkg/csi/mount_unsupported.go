@@ -0,0 +1,45 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"fmt"
+)
+
+// unsupportedMounter is used on platforms other than Linux, which the driver
+// does not support.
+type unsupportedMounter struct{}
+
+// NewMounter returns the Mounter used to mount volumes on the node.
+func NewMounter() Mounter {
+	return unsupportedMounter{}
+}
+
+func (unsupportedMounter) Mount(source, target, fstype string, options []string) error {
+	return fmt.Errorf("mounting is not supported on this platform")
+}
+
+func (unsupportedMounter) Unmount(target string) error {
+	return fmt.Errorf("unmounting is not supported on this platform")
+}
+
+func (unsupportedMounter) IsLikelyNotMountPoint(file string) (bool, error) {
+	return true, fmt.Errorf("checking mount points is not supported on this platform")
+}
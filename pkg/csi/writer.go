@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// dataDirName is the name of the symlink, in a volume's directory, to
+	// the directory holding the current version of the volume's files.
+	dataDirName    = "..data"
+	newDataDirName = "..data_tmp"
+)
+
+// writeFiles atomically replaces the files in the given directory, so that
+// the workload reading them never sees a mix of old and new files, such as a
+// renewed certificate alongside the previous private key.
+//
+// Like the kubelet does for Secret and ConfigMap volumes, the files are
+// written to a new timestamped directory, and the ..data symlink is then
+// atomically renamed to point at it. Each file in the volume is a symlink
+// through ..data.
+func writeFiles(dir string, files map[string][]byte) error {
+	tsDir, err := os.MkdirTemp(dir, time.Now().UTC().Format("..2006_01_02_15_04_05."))
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tsDirName := filepath.Base(tsDir)
+
+	// The volume may be read by a non-root user in the pod.
+	if err := os.Chmod(tsDir, 0755); err != nil {
+		return fmt.Errorf("failed to set directory permissions: %w", err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tsDir, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	oldTsDirName, err := os.Readlink(filepath.Join(dir, dataDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read data directory link: %w", err)
+	}
+
+	newDataDir := filepath.Join(dir, newDataDirName)
+	if err := os.Remove(newDataDir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Symlink(tsDirName, newDataDir); err != nil {
+		return fmt.Errorf("failed to create data directory link: %w", err)
+	}
+	if err := os.Rename(newDataDir, filepath.Join(dir, dataDirName)); err != nil {
+		return fmt.Errorf("failed to update data directory link: %w", err)
+	}
+
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(dataDirName, name), link); err != nil {
+			return fmt.Errorf("failed to create link for %s: %w", name, err)
+		}
+	}
+
+	if len(oldTsDirName) > 0 && oldTsDirName != tsDirName {
+		if err := os.RemoveAll(filepath.Join(dir, oldTsDirName)); err != nil {
+			return fmt.Errorf("failed to remove previous files: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readFile reads a file written to the given directory by writeFiles.
+func readFile(dir, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(dir, dataDirName, name))
+}
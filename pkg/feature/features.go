@@ -40,6 +40,21 @@ const (
 	// ExperimentalGatewayAPISupport enables the gateway-shim controller and adds support for
 	// the Gateway API to the HTTP-01 challenge solver.
 	ExperimentalGatewayAPISupport featuregate.Feature = "ExperimentalGatewayAPISupport"
+
+	// alpha: v1.6.0
+	//
+	// ServerSideApply makes the controllers write the status of Certificates,
+	// CertificateRequests, Orders and Challenges using server-side apply, with
+	// a field manager per controller, instead of conditional patches and
+	// updates.
+	ServerSideApply featuregate.Feature = "ServerSideApply"
+
+	// alpha: v1.6.0
+	//
+	// ACMERenewalInfo makes Certificates issued by ACME issuers be renewed
+	// within the window suggested by the ACME server using ACME Renewal
+	// Information (draft-ietf-acme-ari), rather than only using renewBefore.
+	ACMERenewalInfo featuregate.Feature = "ACMERenewalInfo"
 )
 
 func init() {
@@ -53,4 +68,6 @@ var defaultCertManagerFeatureGates = map[featuregate.Feature]featuregate.Feature
 	ValidateCAA: {Default: false, PreRelease: featuregate.Alpha},
 	ExperimentalCertificateSigningRequestControllers: {Default: false, PreRelease: featuregate.Alpha},
 	ExperimentalGatewayAPISupport:                    {Default: false, PreRelease: featuregate.Alpha},
+	ServerSideApply:                                  {Default: false, PreRelease: featuregate.Alpha},
+	ACMERenewalInfo:                                  {Default: false, PreRelease: featuregate.Alpha},
 }
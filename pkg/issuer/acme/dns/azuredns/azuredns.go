@@ -13,7 +13,10 @@ package azuredns
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -28,6 +31,11 @@ import (
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
 
+// tokenCache holds the Azure AD tokens shared by all challenges using the
+// same credentials. A cached token refreshes itself when it is used close to
+// its expiry.
+var tokenCache = util.NewCredentialCache()
+
 // DNSProvider implements the util.ChallengeProvider interface
 type DNSProvider struct {
 	dns01Nameservers  []string
@@ -50,7 +58,7 @@ func NewDNSProviderCredentials(environment, clientID, clientSecret, subscription
 		}
 	}
 
-	spt, err := getAuthorization(env, clientID, clientSecret, subscriptionID, tenantID, ambient, managedIdentity)
+	spt, err := getCachedAuthorization(env, clientID, clientSecret, subscriptionID, tenantID, ambient, managedIdentity)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +79,26 @@ func NewDNSProviderCredentials(environment, clientID, clientSecret, subscription
 	}, nil
 }
 
+// getCachedAuthorization returns the token cached for the given credentials,
+// creating a new token when none is cached.
+func getCachedAuthorization(env azure.Environment, clientID, clientSecret, subscriptionID, tenantID string, ambient bool, managedIdentity *cmacme.AzureManagedIdentity) (*adal.ServicePrincipalToken, error) {
+	var identityClientID, identityResourceID string
+	if managedIdentity != nil {
+		identityClientID = managedIdentity.ClientID
+		identityResourceID = managedIdentity.ResourceID
+	}
+
+	key := util.CredentialCacheKey(env.Name, clientID, clientSecret, tenantID, strconv.FormatBool(ambient), identityClientID, identityResourceID)
+	spt, err := tokenCache.Get(key, func() (interface{}, time.Time, error) {
+		spt, err := getAuthorization(env, clientID, clientSecret, subscriptionID, tenantID, ambient, managedIdentity)
+		return spt, time.Time{}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spt.(*adal.ServicePrincipalToken), nil
+}
+
 func getAuthorization(env azure.Environment, clientID, clientSecret, subscriptionID, tenantID string, ambient bool, managedIdentity *cmacme.AzureManagedIdentity) (*adal.ServicePrincipalToken, error) {
 	if clientID != "" {
 		logf.Log.V(logf.InfoLevel).Info("azuredns authenticating with clientID and secret key")
@@ -116,17 +144,44 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 		return err
 	}
 
-	_, err = c.recordClient.Delete(
-		context.TODO(),
-		c.resourceGroupName,
-		z,
-		c.trimFqdn(fqdn, z),
-		dns.TXT, "")
-
+	name := c.trimFqdn(fqdn, z)
+	set, err := c.recordClient.Get(context.TODO(), c.resourceGroupName, z, name, dns.TXT)
 	if err != nil {
+		if detailedErr, ok := err.(autorest.DetailedError); ok && detailedErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
 		return err
 	}
-	return nil
+
+	// Only remove the value presented for this challenge, so that any
+	// other TXT values at the same name are left untouched.
+	var remaining []dns.TxtRecord
+	found := false
+	if set.RecordSetProperties != nil && set.TxtRecords != nil {
+		for _, rec := range *set.TxtRecords {
+			if rec.Value != nil && len(*rec.Value) == 1 && (*rec.Value)[0] == value {
+				found = true
+				continue
+			}
+			remaining = append(remaining, rec)
+		}
+	}
+	if !found {
+		c.log.V(logf.DebugLevel).Info("TXT record does not contain the challenge value, skipping clean up", "fqdn", fqdn)
+		return nil
+	}
+
+	// The etag ensures that changes made to the record set since it was
+	// read are not overwritten.
+	etag := to.String(set.Etag)
+	if len(remaining) == 0 {
+		_, err = c.recordClient.Delete(context.TODO(), c.resourceGroupName, z, name, dns.TXT, etag)
+		return err
+	}
+
+	set.TxtRecords = &remaining
+	_, err = c.recordClient.CreateOrUpdate(context.TODO(), c.resourceGroupName, z, name, dns.TXT, set, etag, "")
+	return err
 }
 
 func (c *DNSProvider) createRecord(fqdn, value string, ttl int) error {
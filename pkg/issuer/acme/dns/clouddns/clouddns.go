@@ -12,7 +12,9 @@ package clouddns
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -27,6 +29,24 @@ import (
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 )
 
+// clientCache holds the authenticated HTTP clients shared by all challenges
+// using the same credentials. Each client reuses its access token until it
+// expires.
+var clientCache = util.NewCredentialCache()
+
+// cachedClient returns the HTTP client cached under the given credential
+// inputs, calling newClient when none is cached.
+func cachedClient(newClient func() (*http.Client, error), credentials ...string) (*http.Client, error) {
+	client, err := clientCache.Get(util.CredentialCacheKey(credentials...), func() (interface{}, time.Time, error) {
+		client, err := newClient()
+		return client, time.Time{}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*http.Client), nil
+}
+
 // DNSProvider is an implementation of the DNSProvider interface.
 type DNSProvider struct {
 	hostedZoneName   string
@@ -76,7 +96,9 @@ func NewDNSProviderCredentials(project string, dns01Nameservers []string, hosted
 	}
 
 	ctx := context.Background()
-	client, err := google.DefaultClient(ctx, dns.NdevClouddnsReadwriteScope)
+	client, err := cachedClient(func() (*http.Client, error) {
+		return google.DefaultClient(ctx, dns.NdevClouddnsReadwriteScope)
+	}, "ambient")
 	if err != nil {
 		return nil, fmt.Errorf("Unable to get Google Cloud client: %v", err)
 	}
@@ -120,14 +142,18 @@ func NewDNSProviderServiceAccountBytes(project string, saBytes []byte, dns01Name
 		return nil, fmt.Errorf("Google Cloud Service Account data missing")
 	}
 
-	conf, err := google.JWTConfigFromJSON(saBytes, dns.NdevClouddnsReadwriteScope)
+	ctx := context.Background()
+	client, err := cachedClient(func() (*http.Client, error) {
+		conf, err := google.JWTConfigFromJSON(saBytes, dns.NdevClouddnsReadwriteScope)
+		if err != nil {
+			return nil, err
+		}
+		return conf.Client(ctx), nil
+	}, "service-account", string(saBytes))
 	if err != nil {
 		return nil, fmt.Errorf("Unable to acquire config: %v", err)
 	}
 
-	ctx := context.Background()
-	client := conf.Client(ctx)
-
 	svc, err := dns.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		return nil, fmt.Errorf("Unable to create Google Cloud DNS service: %v", err)
@@ -199,9 +225,29 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	}
 
 	for _, rec := range records {
+		// Only remove the value presented for this challenge, so that any
+		// other TXT values at the same name are left untouched.
+		var remaining []string
+		for _, data := range rec.Rrdatas {
+			if strings.Trim(data, `"`) != value {
+				remaining = append(remaining, data)
+			}
+		}
+		if len(remaining) == len(rec.Rrdatas) {
+			continue
+		}
+
 		change := &dns.Change{
 			Deletions: []*dns.ResourceRecordSet{rec},
 		}
+		if len(remaining) > 0 {
+			change.Additions = []*dns.ResourceRecordSet{{
+				Name:    rec.Name,
+				Rrdatas: remaining,
+				Ttl:     rec.Ttl,
+				Type:    rec.Type,
+			}}
+		}
 		_, err = c.client.Changes.Create(c.project, zone, change).Do()
 		if err != nil {
 			return err
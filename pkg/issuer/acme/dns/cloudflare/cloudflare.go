@@ -181,18 +181,21 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 
 // CleanUp removes the TXT record matching the specified parameters
 func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
-	record, err := c.findTxtRecord(fqdn)
-	// Nothing to cleanup
-	if err == errNoExistingRecord {
-		return nil
-	}
+	records, err := c.findTxtRecords(fqdn)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.makeRequest("DELETE", fmt.Sprintf("/zones/%s/dns_records/%s", record.ZoneID, record.ID), nil)
-	if err != nil {
-		return err
+	// Only delete the record presented for this challenge, so that any
+	// other TXT records at the same name are left untouched.
+	for _, record := range records {
+		if record.Content != value {
+			continue
+		}
+		_, err = c.makeRequest("DELETE", fmt.Sprintf("/zones/%s/dns_records/%s", record.ZoneID, record.ID), nil)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -209,6 +212,18 @@ func (c *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 var errNoExistingRecord = errors.New("No existing record found")
 
 func (c *DNSProvider) findTxtRecord(fqdn string) (*cloudFlareRecord, error) {
+	records, err := c.findTxtRecords(fqdn)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errNoExistingRecord
+	}
+	return &records[0], nil
+}
+
+// findTxtRecords returns all TXT records with the given name.
+func (c *DNSProvider) findTxtRecords(fqdn string) ([]cloudFlareRecord, error) {
 	zoneID, err := c.getHostedZoneID(fqdn)
 	if err != nil {
 		return nil, err
@@ -229,13 +244,14 @@ func (c *DNSProvider) findTxtRecord(fqdn string) (*cloudFlareRecord, error) {
 		return nil, err
 	}
 
+	var found []cloudFlareRecord
 	for _, rec := range records {
 		if rec.Name == util.UnFqdn(fqdn) {
-			return &rec, nil
+			found = append(found, rec)
 		}
 	}
 
-	return nil, errNoExistingRecord
+	return found, nil
 }
 
 func (c *DNSProvider) makeRequest(method, uri string, body io.Reader) (json.RawMessage, error) {
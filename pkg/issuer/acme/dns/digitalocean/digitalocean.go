@@ -113,7 +113,12 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 		return err
 	}
 
+	// Only delete the record presented for this challenge, so that any
+	// other records at the same name are left untouched.
 	for _, record := range records {
+		if record.Type != "TXT" || record.Data != value {
+			continue
+		}
 		_, err = c.client.Domains.DeleteRecord(context.Background(), util.UnFqdn(zoneName), record.ID)
 
 		if err != nil {
@@ -33,6 +33,7 @@ import (
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/acmeorders/selectors"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/acmedns"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/akamai"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/azuredns"
@@ -53,6 +54,36 @@ type solver interface {
 	CleanUp(domain, fqdn, value string) error
 }
 
+// ProviderNames are the names of the DNS01 providers, as used in the
+// configuration of an ACME issuer's DNS01 solver.
+var ProviderNames = []string{"acmeDNS", "akamai", "azureDNS", "cloudDNS", "cloudflare", "digitalocean", "rfc2136", "route53", "webhook"}
+
+// providerName returns the name of the DNS01 provider configured by the
+// given solver configuration, or an empty string if none is configured.
+func providerName(cfg *cmacme.ACMEChallengeSolverDNS01) string {
+	switch {
+	case cfg.AcmeDNS != nil:
+		return "acmeDNS"
+	case cfg.Akamai != nil:
+		return "akamai"
+	case cfg.AzureDNS != nil:
+		return "azureDNS"
+	case cfg.CloudDNS != nil:
+		return "cloudDNS"
+	case cfg.Cloudflare != nil:
+		return "cloudflare"
+	case cfg.DigitalOcean != nil:
+		return "digitalocean"
+	case cfg.RFC2136 != nil:
+		return "rfc2136"
+	case cfg.Route53 != nil:
+		return "route53"
+	case cfg.Webhook != nil:
+		return "webhook"
+	}
+	return ""
+}
+
 // dnsProviderConstructors defines how each provider may be constructed.
 // It is useful for mocking out a given provider since an alternate set of
 // constructors may be set.
@@ -80,7 +111,16 @@ func (s *Solver) Present(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 	log := logf.WithResource(logf.FromContext(ctx, "Present"), ch).WithValues("domain", ch.Spec.DNSName)
 	ctx = logf.NewContext(ctx, log)
 
-	webhookSolver, req, err := s.prepareChallengeRequest(issuer, ch)
+	providerConfig, fqdn, delegatedZone, err := s.resolveChallenge(ctx, issuer, ch)
+	if err != nil {
+		return err
+	}
+
+	// Record where the record is presented, so that CleanUp removes the same
+	// record using the same solver even if DNS changes in the meantime.
+	ch.Status.DNS01 = &cmacme.ChallengeDNS01Status{FQDN: fqdn, DelegatedZone: delegatedZone}
+
+	webhookSolver, req, err := s.prepareChallengeRequest(issuer, ch, providerConfig, fqdn)
 	if err != nil && err != errNotFound {
 		return err
 	}
@@ -89,12 +129,7 @@ func (s *Solver) Present(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 		return webhookSolver.Present(req)
 	}
 
-	slv, providerConfig, err := s.solverForChallenge(ctx, issuer, ch)
-	if err != nil {
-		return err
-	}
-
-	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(providerConfig.CNAMEStrategy), s.DNS01Nameservers...)
+	slv, _, err := s.solverForConfig(ctx, issuer, ch, providerConfig)
 	if err != nil {
 		return err
 	}
@@ -138,7 +173,18 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 	log := logf.WithResource(logf.FromContext(ctx, "CleanUp"), ch).WithValues("domain", ch.Spec.DNSName)
 	ctx = logf.NewContext(ctx, log)
 
-	webhookSolver, req, err := s.prepareChallengeRequest(issuer, ch)
+	providerConfig, fqdn, err := s.presentedChallenge(ctx, issuer, ch)
+	if err != nil {
+		return err
+	}
+
+	if provider := providerName(providerConfig); s.cleanupDryRun(provider) {
+		log.V(logf.InfoLevel).Info("DNS01 clean up is in dry-run mode for provider, leaving challenge record in place",
+			"provider", provider, "fqdn", fqdn, "value", ch.Spec.Key)
+		return nil
+	}
+
+	webhookSolver, req, err := s.prepareChallengeRequest(issuer, ch, providerConfig, fqdn)
 	if err != nil && err != errNotFound {
 		return err
 	}
@@ -147,21 +193,138 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 		return webhookSolver.CleanUp(req)
 	}
 
-	slv, providerConfig, err := s.solverForChallenge(ctx, issuer, ch)
+	slv, _, err := s.solverForConfig(ctx, issuer, ch, providerConfig)
 	if err != nil {
 		return err
 	}
 
+	return slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+}
+
+// cleanupDryRun returns true if challenge records presented by the given
+// provider should be left in place rather than cleaned up.
+func (s *Solver) cleanupDryRun(provider string) bool {
+	for _, p := range s.DNS01CleanupDryRunProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func followCNAME(strategy cmacme.CNAMEStrategy) bool {
+	return strategy == cmacme.FollowStrategy || strategy == cmacme.DelegateStrategy
+}
+
+// resolveChallenge determines the FQDN that the challenge record should be
+// presented at, along with the DNS01 provider configuration that should be
+// used to manage it.
+// If the challenge's solver uses the Delegate CNAME strategy, the zone the
+// record has been delegated to is looked up and returned, and the issuer's
+// DNS01 solver configured for that zone is used instead of the challenge's
+// own solver, provided the challenge is allowed to delegate to it.
+func (s *Solver) resolveChallenge(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) (*cmacme.ACMEChallengeSolverDNS01, string, string, error) {
+	log := logf.FromContext(ctx, "resolveChallenge")
+
+	providerConfig, err := extractChallengeSolverConfig(ch)
+	if err != nil {
+		return nil, "", "", err
+	}
+
 	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(providerConfig.CNAMEStrategy), s.DNS01Nameservers...)
 	if err != nil {
-		return err
+		return nil, "", "", err
 	}
 
-	return slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	if providerConfig.CNAMEStrategy != cmacme.DelegateStrategy {
+		return providerConfig, fqdn, "", nil
+	}
+
+	zone, err := util.FindZoneByFqdn(fqdn, s.DNS01Nameservers)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	delegated := delegatedSolverConfig(issuer, ch, zone)
+	if delegated == nil {
+		log.V(logf.DebugLevel).Info("no DNS01 solver that the challenge may delegate to is configured for the zone, using challenge solver", "fqdn", fqdn, "zone", zone)
+		return providerConfig, fqdn, "", nil
+	}
+
+	log.V(logf.DebugLevel).Info("using DNS01 solver configured for delegated zone", "fqdn", fqdn, "zone", zone)
+	return delegated, fqdn, zone, nil
 }
 
-func followCNAME(strategy cmacme.CNAMEStrategy) bool {
-	return strategy == cmacme.FollowStrategy
+// presentedChallenge returns the FQDN that the challenge record was presented
+// at, along with the DNS01 provider configuration that was used to present
+// it, as recorded on the challenge by Present. Challenges presented before
+// this was recorded are resolved again.
+func (s *Solver) presentedChallenge(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) (*cmacme.ACMEChallengeSolverDNS01, string, error) {
+	presented := ch.Status.DNS01
+	if presented == nil {
+		providerConfig, fqdn, _, err := s.resolveChallenge(ctx, issuer, ch)
+		return providerConfig, fqdn, err
+	}
+
+	if presented.DelegatedZone == "" {
+		providerConfig, err := extractChallengeSolverConfig(ch)
+		return providerConfig, presented.FQDN, err
+	}
+
+	delegated := delegatedSolverConfig(issuer, ch, presented.DelegatedZone)
+	if delegated == nil {
+		return nil, "", fmt.Errorf("no DNS01 solver that the challenge may delegate to is configured for the zone %q that the record was presented in", presented.DelegatedZone)
+	}
+	return delegated, presented.FQDN, nil
+}
+
+// delegatedSolverConfig returns the DNS01 solver configuration on the given
+// ACME issuer whose dnsZones selector most specifically matches zone, and
+// that the challenge may delegate to: either the selector also matches the
+// domain being validated, or zone is one of the delegatedZones of the
+// challenge's own solver.
+// Solvers without a dnsZones selector are not considered, as they do not
+// identify a zone they are able to manage. If no solver matches, nil is
+// returned.
+func delegatedSolverConfig(issuer v1.GenericIssuer, ch *cmacme.Challenge, zone string) *cmacme.ACMEChallengeSolverDNS01 {
+	acme := issuer.GetSpec().ACME
+	if acme == nil {
+		return nil
+	}
+
+	zone = util.UnFqdn(zone)
+	zoneAllowed := false
+	if ch.Spec.Solver.DNS01 != nil {
+		for _, allowed := range ch.Spec.Solver.DNS01.DelegatedZones {
+			if util.UnFqdn(allowed) == zone {
+				zoneAllowed = true
+				break
+			}
+		}
+	}
+
+	var selected *cmacme.ACMEChallengeSolverDNS01
+	selectedNumMatchingLabels := 0
+	for i := range acme.Solvers {
+		cfg := &acme.Solvers[i]
+		if cfg.DNS01 == nil || cfg.Selector == nil || len(cfg.Selector.DNSZones) == 0 {
+			continue
+		}
+
+		dnsZones := selectors.DNSZones(*cfg.Selector)
+		matches, numMatchingLabels := dnsZones.Matches(*issuer.GetObjectMeta(), zone)
+		if !matches || numMatchingLabels <= selectedNumMatchingLabels {
+			continue
+		}
+		if matchesDomain, _ := dnsZones.Matches(*issuer.GetObjectMeta(), ch.Spec.DNSName); !matchesDomain && !zoneAllowed {
+			continue
+		}
+
+		selected = cfg.DNS01
+		selectedNumMatchingLabels = numMatchingLabels
+	}
+
+	return selected
 }
 
 func extractChallengeSolverConfig(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSolverDNS01, error) {
@@ -176,17 +339,24 @@ func extractChallengeSolverConfig(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSo
 // The providerName is the name of an ACME DNS-01 challenge provider as
 // specified on the Issuer resource for the Solver.
 func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) (solver, *cmacme.ACMEChallengeSolverDNS01, error) {
-	log := logf.FromContext(ctx, "solverForChallenge")
-	dbg := log.V(logf.DebugLevel)
-
-	resourceNamespace := s.ResourceNamespace(issuer)
-	canUseAmbientCredentials := s.CanUseAmbientCredentials(issuer)
-
 	providerConfig, err := extractChallengeSolverConfig(ch)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	return s.solverForConfig(ctx, issuer, ch, providerConfig)
+}
+
+// solverForConfig returns a Solver for the given DNS01 provider configuration,
+// used to solve the given challenge.
+func (s *Solver) solverForConfig(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge, providerConfig *cmacme.ACMEChallengeSolverDNS01) (solver, *cmacme.ACMEChallengeSolverDNS01, error) {
+	log := logf.FromContext(ctx, "solverForChallenge")
+	dbg := log.V(logf.DebugLevel)
+
+	resourceNamespace := s.secretsNamespace(issuer, ch, providerConfig)
+	canUseAmbientCredentials := s.CanUseAmbientCredentials(issuer)
+
+	var err error
 	var impl solver
 	switch {
 	case providerConfig.Akamai != nil:
@@ -377,28 +547,18 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 	return impl, providerConfig, nil
 }
 
-func (s *Solver) prepareChallengeRequest(issuer v1.GenericIssuer, ch *cmacme.Challenge) (webhook.Solver, *whapi.ChallengeRequest, error) {
-	dns01Config, err := extractChallengeSolverConfig(ch)
-	if err != nil {
-		return nil, nil, err
-	}
-
+func (s *Solver) prepareChallengeRequest(issuer v1.GenericIssuer, ch *cmacme.Challenge, dns01Config *cmacme.ACMEChallengeSolverDNS01, fqdn string) (webhook.Solver, *whapi.ChallengeRequest, error) {
 	webhookSolver, cfg, err := s.dns01SolverForConfig(dns01Config)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(dns01Config.CNAMEStrategy), s.DNS01Nameservers...)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	zone, err := util.FindZoneByFqdn(fqdn, s.DNS01Nameservers)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	resourceNamespace := s.ResourceNamespace(issuer)
+	resourceNamespace := s.secretsNamespace(issuer, ch, dns01Config)
 	canUseAmbientCredentials := s.CanUseAmbientCredentials(issuer)
 
 	// construct a ChallengeRequest which can be passed to DNS solvers.
@@ -423,6 +583,17 @@ func (s *Solver) prepareChallengeRequest(issuer v1.GenericIssuer, ch *cmacme.Cha
 	return webhookSolver, req, nil
 }
 
+// secretsNamespace returns the namespace that the Secrets referenced by the
+// DNS01 provider configuration should be read from when solving the given
+// challenge. This is the issuer's resource namespace, unless the solver of a
+// ClusterIssuer reads its Secrets from the namespace of the challenge.
+func (s *Solver) secretsNamespace(issuer v1.GenericIssuer, ch *cmacme.Challenge, providerConfig *cmacme.ACMEChallengeSolverDNS01) string {
+	if _, ok := issuer.(*v1.ClusterIssuer); ok && providerConfig.SecretsFromChallengeNamespace {
+		return ch.Namespace
+	}
+	return s.ResourceNamespace(issuer)
+}
+
 var errNotFound = fmt.Errorf("failed to determine DNS01 solver type")
 
 func (s *Solver) dns01SolverForConfig(config *cmacme.ACMEChallengeSolverDNS01) (webhook.Solver, interface{}, error) {
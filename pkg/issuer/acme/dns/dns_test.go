@@ -351,6 +351,145 @@ func TestSolveForDigitalOcean(t *testing.T) {
 
 }
 
+func TestSolveForChallengeNamespaceSecrets(t *testing.T) {
+	tests := map[string]struct {
+		issuer                        v1.GenericIssuer
+		secretsFromChallengeNamespace bool
+		expectedToken                 string
+	}{
+		"ClusterIssuer reads Secrets from the cluster resource namespace by default": {
+			issuer:        &v1.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			expectedToken: "CLUSTER-TOKEN",
+		},
+		"ClusterIssuer reads Secrets from the challenge namespace if enabled": {
+			issuer:                        &v1.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			secretsFromChallengeNamespace: true,
+			expectedToken:                 "TENANT-TOKEN",
+		},
+		"Issuer reads Secrets from its own namespace even if enabled": {
+			issuer:                        newIssuer("test", "issuer-ns"),
+			secretsFromChallengeNamespace: true,
+			expectedToken:                 "ISSUER-TOKEN",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &solverFixture{
+				Builder: &test.Builder{
+					KubeObjects: []runtime.Object{
+						newSecret("digitalocean", "cert-manager", map[string][]byte{"token": []byte("CLUSTER-TOKEN")}),
+						newSecret("digitalocean", "tenant", map[string][]byte{"token": []byte("TENANT-TOKEN")}),
+						newSecret("digitalocean", "issuer-ns", map[string][]byte{"token": []byte("ISSUER-TOKEN")}),
+					},
+				},
+				Issuer: tt.issuer,
+				Challenge: &cmacme.Challenge{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"},
+					Spec: cmacme.ChallengeSpec{
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								SecretsFromChallengeNamespace: tt.secretsFromChallengeNamespace,
+								DigitalOcean: &cmacme.ACMEIssuerDNS01ProviderDigitalOcean{
+									Token: cmmeta.SecretKeySelector{
+										LocalObjectReference: cmmeta.LocalObjectReference{
+											Name: "digitalocean",
+										},
+										Key: "token",
+									},
+								},
+							},
+						},
+					},
+				},
+				dnsProviders: newFakeDNSProviders(),
+			}
+
+			f.Setup(t)
+			defer f.Finish(t)
+
+			s := f.Solver
+			s.ClusterResourceNamespace = "cert-manager"
+			if _, _, err := s.solverForChallenge(context.Background(), f.Issuer, f.Challenge); err != nil {
+				t.Fatalf("expected solverFor to not error, but got: %s", err)
+			}
+
+			expectedDOCall := []fakeDNSProviderCall{
+				{
+					name: "digitalocean",
+					args: []interface{}{tt.expectedToken, util.RecursiveNameservers},
+				},
+			}
+			if !reflect.DeepEqual(expectedDOCall, f.dnsProviders.calls) {
+				t.Fatalf("expected %+v == %+v", expectedDOCall, f.dnsProviders.calls)
+			}
+		})
+	}
+}
+
+func TestCleanUpDryRun(t *testing.T) {
+	tests := map[string]struct {
+		dryRunProviders []string
+		expectedCalls   []string
+	}{
+		"cleans up records when dry-run is not enabled": {
+			expectedCalls: []string{"acmedns"},
+		},
+		"cleans up records when dry-run is enabled for another provider": {
+			dryRunProviders: []string{"cloudflare"},
+			expectedCalls:   []string{"acmedns"},
+		},
+		"does not clean up records when dry-run is enabled for the provider": {
+			dryRunProviders: []string{"cloudflare", "acmeDNS"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &solverFixture{
+				Builder: &test.Builder{
+					KubeObjects: []runtime.Object{
+						newSecret("acmedns-key", "default", map[string][]byte{
+							"acmedns.json": []byte("{}"),
+						}),
+					},
+				},
+				Issuer: newIssuer("test", "default"),
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						DNSName: "example.com",
+						Key:     "challenge-key",
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								AcmeDNS: &cmacme.ACMEIssuerDNS01ProviderAcmeDNS{
+									Host: "http://127.0.0.1/",
+									AccountSecret: cmmeta.SecretKeySelector{
+										LocalObjectReference: cmmeta.LocalObjectReference{Name: "acmedns-key"},
+										Key:                  "acmedns.json",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			f.Setup(t)
+			defer f.Finish(t)
+			f.Solver.DNS01CleanupDryRunProviders = tt.dryRunProviders
+
+			if err := f.Solver.CleanUp(context.Background(), f.Issuer, f.Challenge); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var calls []string
+			for _, call := range f.dnsProviders.calls {
+				calls = append(calls, call.name)
+			}
+			if !reflect.DeepEqual(tt.expectedCalls, calls) {
+				t.Errorf("unexpected provider calls, exp=%v got=%v", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
 func TestRoute53TrimCreds(t *testing.T) {
 	f := &solverFixture{
 		Builder: &test.Builder{
@@ -584,3 +723,186 @@ func TestRoute53AssumeRole(t *testing.T) {
 		}
 	}
 }
+
+func TestDelegatedSolverConfig(t *testing.T) {
+	cloudflareSolver := &cmacme.ACMEChallengeSolverDNS01{
+		Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{Email: "cloudflare"},
+	}
+	route53Solver := &cmacme.ACMEChallengeSolverDNS01{
+		Route53: &cmacme.ACMEIssuerDNS01ProviderRoute53{Region: "us-west-2"},
+	}
+	acmeDNSSolver := &cmacme.ACMEChallengeSolverDNS01{
+		AcmeDNS: &cmacme.ACMEIssuerDNS01ProviderAcmeDNS{Host: "https://auth.acme-dns.io"},
+	}
+
+	solvers := []cmacme.ACMEChallengeSolver{
+		{
+			Selector: &cmacme.CertificateDNSNameSelector{DNSZones: []string{"example.com"}},
+			DNS01:    cloudflareSolver,
+		},
+		{
+			Selector: &cmacme.CertificateDNSNameSelector{DNSZones: []string{"example.net", "acme.example.org"}},
+			DNS01:    route53Solver,
+		},
+		{
+			Selector: &cmacme.CertificateDNSNameSelector{DNSZones: []string{"delegated.example.net"}},
+			DNS01:    acmeDNSSolver,
+		},
+		{
+			// solvers without a dnsZones selector are never chosen
+			DNS01: cloudflareSolver,
+		},
+		{
+			Selector: &cmacme.CertificateDNSNameSelector{DNSZones: []string{"http.example.org"}},
+			HTTP01:   &cmacme.ACMEChallengeSolverHTTP01{},
+		},
+	}
+
+	tests := map[string]struct {
+		issuer    v1.GenericIssuer
+		challenge *cmacme.Challenge
+		zone      string
+		expected  *cmacme.ACMEChallengeSolverDNS01
+	}{
+		"selects the solver for a delegated zone on the allowlist": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.com", "example.net"),
+			zone:      "example.net.",
+			expected:  route53Solver,
+		},
+		"selects the solver for a parent of the delegated zone": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.com", "acme.example.org"),
+			zone:      "acme.example.org.",
+			expected:  route53Solver,
+		},
+		"prefers the most specific matching zone": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.com", "challenges.delegated.example.net"),
+			zone:      "challenges.delegated.example.net.",
+			expected:  acmeDNSSolver,
+		},
+		"selects a solver that also matches the domain being validated": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.net"),
+			zone:      "acme.example.net.",
+			expected:  route53Solver,
+		},
+		"does not select a solver for a zone that is not allowed": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.com"),
+			zone:      "example.net.",
+		},
+		"selects an allowed solver over a more specific one that is not allowed": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.net"),
+			zone:      "challenges.delegated.example.net.",
+			expected:  route53Solver,
+		},
+		"returns nil if no solver is configured for the zone": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.com", "example.org"),
+			zone:      "example.org.",
+		},
+		"ignores HTTP01 solvers": {
+			issuer:    newIssuerWithSolvers(solvers),
+			challenge: newDelegateChallenge("www.example.com", "http.example.org"),
+			zone:      "http.example.org.",
+		},
+		"returns nil for non-ACME issuers": {
+			issuer:    &v1.Issuer{},
+			challenge: newDelegateChallenge("www.example.com", "example.com"),
+			zone:      "example.com.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := delegatedSolverConfig(test.issuer, test.challenge, test.zone)
+			if cfg != test.expected {
+				t.Errorf("expected solver %+v, got %+v", test.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestPresentedChallenge(t *testing.T) {
+	route53Solver := &cmacme.ACMEChallengeSolverDNS01{
+		Route53: &cmacme.ACMEIssuerDNS01ProviderRoute53{Region: "us-west-2"},
+	}
+	issuer := newIssuerWithSolvers([]cmacme.ACMEChallengeSolver{
+		{
+			Selector: &cmacme.CertificateDNSNameSelector{DNSZones: []string{"example.net"}},
+			DNS01:    route53Solver,
+		},
+	})
+
+	tests := map[string]struct {
+		presented               *cmacme.ChallengeDNS01Status
+		delegatedZones          []string
+		expectedChallengeSolver bool
+		expectedConfig          *cmacme.ACMEChallengeSolverDNS01
+		expectedFQDN            string
+		expectedErr             bool
+	}{
+		"uses the challenge solver if the record was not presented in a delegated zone": {
+			presented:               &cmacme.ChallengeDNS01Status{FQDN: "_acme-challenge.www.example.com."},
+			expectedChallengeSolver: true,
+			expectedFQDN:            "_acme-challenge.www.example.com.",
+		},
+		"uses the solver for the delegated zone the record was presented in": {
+			presented:      &cmacme.ChallengeDNS01Status{FQDN: "www.acme.example.net.", DelegatedZone: "example.net."},
+			delegatedZones: []string{"example.net"},
+			expectedConfig: route53Solver,
+			expectedFQDN:   "www.acme.example.net.",
+		},
+		"errors if the challenge may no longer delegate to the zone the record was presented in": {
+			presented:   &cmacme.ChallengeDNS01Status{FQDN: "www.acme.example.net.", DelegatedZone: "example.net."},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := newDelegateChallenge("www.example.com", test.delegatedZones...)
+			ch.Status.DNS01 = test.presented
+			if test.expectedChallengeSolver {
+				test.expectedConfig = ch.Spec.Solver.DNS01
+			}
+
+			s := &Solver{}
+			cfg, fqdn, err := s.presentedChallenge(context.Background(), issuer, ch)
+			if test.expectedErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+			if cfg != test.expectedConfig {
+				t.Errorf("expected solver %+v, got %+v", test.expectedConfig, cfg)
+			}
+			if fqdn != test.expectedFQDN {
+				t.Errorf("expected fqdn %q, got %q", test.expectedFQDN, fqdn)
+			}
+		})
+	}
+}
+
+// newDelegateChallenge returns a DNS01 challenge for dnsName whose solver uses
+// the Delegate CNAME strategy, and may delegate to the given zones.
+func newDelegateChallenge(dnsName string, delegatedZones ...string) *cmacme.Challenge {
+	return &cmacme.Challenge{
+		Spec: cmacme.ChallengeSpec{
+			DNSName: dnsName,
+			Solver: cmacme.ACMEChallengeSolver{
+				DNS01: &cmacme.ACMEChallengeSolverDNS01{
+					CNAMEStrategy:  cmacme.DelegateStrategy,
+					DelegatedZones: delegatedZones,
+				},
+			},
+		},
+	}
+}
+
+func newIssuerWithSolvers(solvers []cmacme.ACMEChallengeSolver) *v1.Issuer {
+	issuer := newIssuer("test", "default")
+	issuer.Spec.ACME.Solvers = solvers
+	return issuer
+}
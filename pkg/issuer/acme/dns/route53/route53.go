@@ -12,6 +12,7 @@ package route53
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,6 +36,10 @@ const (
 	route53TTL = 10
 )
 
+// sessionCache holds the AWS sessions shared by all challenges using the same
+// credentials, so that roles are not assumed again for every challenge.
+var sessionCache = util.NewCredentialCache()
+
 // DNSProvider implements the util.ChallengeProvider interface
 type DNSProvider struct {
 	dns01Nameservers []string
@@ -54,13 +59,20 @@ type sessionProvider struct {
 }
 
 func (d *sessionProvider) GetSession() (*session.Session, error) {
+	sess, _, err := d.getSession()
+	return sess, err
+}
+
+// getSession returns a new AWS session along with the time at which its
+// credentials expire, or the zero time if they do not expire.
+func (d *sessionProvider) getSession() (*session.Session, time.Time, error) {
 	if d.AccessKeyID == "" && d.SecretAccessKey == "" {
 		if !d.Ambient {
-			return nil, fmt.Errorf("unable to construct route53 provider: empty credentials; perhaps you meant to enable ambient credentials?")
+			return nil, time.Time{}, fmt.Errorf("unable to construct route53 provider: empty credentials; perhaps you meant to enable ambient credentials?")
 		}
 	} else if d.AccessKeyID == "" || d.SecretAccessKey == "" {
 		// It's always an error to set one of those but not the other
-		return nil, fmt.Errorf("unable to construct route53 provider: only one of access and secret key was provided")
+		return nil, time.Time{}, fmt.Errorf("unable to construct route53 provider: only one of access and secret key was provided")
 	}
 
 	useAmbientCredentials := d.Ambient && (d.AccessKeyID == "" && d.SecretAccessKey == "")
@@ -84,9 +96,10 @@ func (d *sessionProvider) GetSession() (*session.Session, error) {
 
 	sess, err := session.NewSessionWithOptions(sessionOpts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create aws session: %s", err)
+		return nil, time.Time{}, fmt.Errorf("unable to create aws session: %s", err)
 	}
 
+	var expiresAt time.Time
 	if d.Role != "" {
 		d.log.V(logf.DebugLevel).WithValues("role", d.Role).Info("assuming role")
 		stsSvc := d.StsProvider(sess)
@@ -95,7 +108,11 @@ func (d *sessionProvider) GetSession() (*session.Session, error) {
 			RoleSessionName: aws.String("cert-manager"),
 		})
 		if err != nil {
-			return nil, fmt.Errorf("unable to assume role: %s", err)
+			return nil, time.Time{}, fmt.Errorf("unable to assume role: %s", err)
+		}
+
+		if result.Credentials.Expiration != nil {
+			expiresAt = *result.Credentials.Expiration
 		}
 
 		creds := credentials.Value{
@@ -107,7 +124,7 @@ func (d *sessionProvider) GetSession() (*session.Session, error) {
 
 		sess, err = session.NewSessionWithOptions(sessionOpts)
 		if err != nil {
-			return nil, fmt.Errorf("unable to create aws session: %s", err)
+			return nil, time.Time{}, fmt.Errorf("unable to create aws session: %s", err)
 		}
 	}
 
@@ -119,7 +136,7 @@ func (d *sessionProvider) GetSession() (*session.Session, error) {
 	}
 
 	sess.Handlers.Build.PushBack(request.WithAppendUserAgent(pkgutil.CertManagerUserAgent))
-	return sess, nil
+	return sess, expiresAt, nil
 }
 
 func newSessionProvider(accessKeyID, secretAccessKey, region, role string, ambient bool) (*sessionProvider, error) {
@@ -147,12 +164,16 @@ func NewDNSProvider(accessKeyID, secretAccessKey, hostedZoneID, region, role str
 		return nil, err
 	}
 
-	sess, err := provider.GetSession()
+	key := util.CredentialCacheKey(accessKeyID, secretAccessKey, region, role, strconv.FormatBool(ambient))
+	sess, err := sessionCache.Get(key, func() (interface{}, time.Time, error) {
+		sess, expiresAt, err := provider.getSession()
+		return sess, expiresAt, err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	client := route53.New(sess)
+	client := route53.New(sess.(*session.Session))
 
 	return &DNSProvider{
 		client:           client,
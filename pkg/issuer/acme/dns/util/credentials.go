@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// credentialMaxAge is the longest time a credential is cached for, even
+	// if it does not expire. This bounds how long credentials built from
+	// rotated or deleted Secrets are kept in memory.
+	credentialMaxAge = time.Hour
+
+	// credentialExpiryWindow is how long before its expiry a credential is
+	// exchanged again, so that a credential returned from the cache is never
+	// about to expire while a challenge is being presented.
+	credentialExpiryWindow = 5 * time.Minute
+)
+
+// CredentialCache caches short-lived DNS provider credentials, such as AWS STS
+// sessions, Azure AD tokens or Google Cloud access tokens, so that they are
+// shared by all the challenges using the same credentials instead of being
+// exchanged again for every challenge.
+type CredentialCache struct {
+	clock clock.Clock
+	group singleflight.Group
+
+	lock    sync.Mutex
+	entries map[string]credentialCacheEntry
+}
+
+type credentialCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// NewCredentialCache returns an empty CredentialCache.
+func NewCredentialCache() *CredentialCache {
+	return &CredentialCache{
+		clock:   clock.RealClock{},
+		entries: make(map[string]credentialCacheEntry),
+	}
+}
+
+// Get returns the credential cached under key. If no credential is cached, or
+// if the cached credential is about to expire, fetch is called to exchange a
+// new credential. fetch returns the credential along with the time at which it
+// expires, or the zero time if it does not expire. Concurrent calls for the
+// same key share a single call to fetch. Errors are not cached.
+func (c *CredentialCache) Get(key string, fetch func() (interface{}, time.Time, error)) (interface{}, error) {
+	if value, ok := c.lookup(key); ok {
+		return value, nil
+	}
+
+	value, err, _ := c.group.Do(key, func() (interface{}, error) {
+		if value, ok := c.lookup(key); ok {
+			return value, nil
+		}
+
+		value, expiresAt, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		now := c.clock.Now()
+		maxExpiresAt := now.Add(credentialMaxAge)
+		if expiresAt.IsZero() || expiresAt.After(maxExpiresAt) {
+			expiresAt = maxExpiresAt
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.entries[key] = credentialCacheEntry{value: value, expiresAt: expiresAt}
+		return value, nil
+	})
+
+	return value, err
+}
+
+// lookup returns the credential cached under key if it is not about to expire,
+// and removes any expired credentials from the cache.
+func (c *CredentialCache) lookup(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt.Add(-credentialExpiryWindow)) {
+			delete(c.entries, k)
+		}
+	}
+
+	entry, ok := c.entries[key]
+	return entry.value, ok
+}
+
+// CredentialCacheKey returns a cache key for the given credential inputs. The
+// inputs are hashed so that secrets are not kept in memory as map keys.
+func CredentialCacheKey(values ...string) string {
+	h := sha256.New()
+	for _, v := range values {
+		// Each value is followed by a NUL byte so that ("ab", "c") and
+		// ("a", "bc") result in different keys.
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func TestCredentialCache(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		// expiresAt is the expiry returned by the first fetch.
+		expiresAt time.Time
+		// elapsed is how much time passes before the second Get.
+		elapsed time.Duration
+		// expFetches is the number of calls to fetch after both Gets.
+		expFetches int
+	}{
+		"a credential without expiry is reused": {
+			elapsed:    time.Minute,
+			expFetches: 1,
+		},
+		"a credential without expiry is exchanged again after the max age": {
+			elapsed:    credentialMaxAge,
+			expFetches: 2,
+		},
+		"a credential far from expiring is reused": {
+			expiresAt:  now.Add(time.Hour),
+			elapsed:    30 * time.Minute,
+			expFetches: 1,
+		},
+		"a credential about to expire is exchanged again": {
+			expiresAt:  now.Add(time.Hour),
+			elapsed:    time.Hour - credentialExpiryWindow,
+			expFetches: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := fakeclock.NewFakeClock(now)
+			cache := NewCredentialCache()
+			cache.clock = clock
+
+			fetches := 0
+			fetch := func() (interface{}, time.Time, error) {
+				fetches++
+				return fetches, test.expiresAt, nil
+			}
+
+			first, err := cache.Get("key", fetch)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			clock.Step(test.elapsed)
+			second, err := cache.Get("key", fetch)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fetches != test.expFetches {
+				t.Errorf("unexpected number of fetches, exp=%d got=%d", test.expFetches, fetches)
+			}
+			if first.(int) != 1 || second.(int) != test.expFetches {
+				t.Errorf("unexpected credentials returned: %v, %v", first, second)
+			}
+		})
+	}
+}
+
+func TestCredentialCacheErrorsAreNotCached(t *testing.T) {
+	cache := NewCredentialCache()
+
+	_, err := cache.Get("key", func() (interface{}, time.Time, error) {
+		return nil, time.Time{}, errors.New("exchange failed")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	value, err := cache.Get("key", func() (interface{}, time.Time, error) {
+		return "credential", time.Time{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "credential" {
+		t.Errorf("unexpected credential: %v", value)
+	}
+}
+
+func TestCredentialCacheKey(t *testing.T) {
+	if CredentialCacheKey("ab", "c") == CredentialCacheKey("a", "bc") {
+		t.Error("expected different inputs to result in different keys")
+	}
+	if CredentialCacheKey("a", "b") != CredentialCacheKey("a", "b") {
+		t.Error("expected identical inputs to result in identical keys")
+	}
+}
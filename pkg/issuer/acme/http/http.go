@@ -24,6 +24,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,7 +32,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	k8snet "k8s.io/utils/net"
-	gwapilisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1alpha1"
+	gwapilisters "sigs.k8s.io/gateway-api/pkg/client/listers/gateway/apis/v1alpha2"
 
 	"github.com/jetstack/cert-manager/internal/ingress"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
@@ -66,9 +67,22 @@ type Solver struct {
 
 	testReachability reachabilityTest
 	requiredPasses   int
+
+	selfCheckMode  controller.HTTP01SelfCheckMode
+	selfCheckProxy func(*http.Request) (*url.URL, error)
+}
+
+// selfCheckRequest describes where a single HTTP01 self-check request is sent.
+type selfCheckRequest struct {
+	url *url.URL
+	// host overrides the Host header of the request if set
+	host string
+	// proxy selects the proxy to send the request through, or nil to send it
+	// directly
+	proxy func(*http.Request) (*url.URL, error)
 }
 
-type reachabilityTest func(ctx context.Context, url *url.URL, key string) error
+type reachabilityTest func(ctx context.Context, req selfCheckRequest, key string) error
 
 // NewSolver returns a new ACME HTTP01 solver for the given *controller.Context.
 func NewSolver(ctx *controller.Context) (*Solver, error) {
@@ -80,15 +94,21 @@ func NewSolver(ctx *controller.Context) (*Solver, error) {
 	if err != nil {
 		return nil, err
 	}
+	selfCheckProxy := http.ProxyFromEnvironment
+	if ctx.ACMEOptions.HTTP01SelfCheckProxy != nil {
+		selfCheckProxy = http.ProxyURL(ctx.ACMEOptions.HTTP01SelfCheckProxy)
+	}
 	return &Solver{
 		Context:              ctx,
 		podLister:            ctx.KubeSharedInformerFactory.Core().V1().Pods().Lister(),
 		serviceLister:        ctx.KubeSharedInformerFactory.Core().V1().Services().Lister(),
 		ingressLister:        ingressLister,
 		ingressCreateUpdater: ingressCreateUpdater,
-		httpRouteLister:      ctx.GWShared.Networking().V1alpha1().HTTPRoutes().Lister(),
+		httpRouteLister:      ctx.GWShared.Gateway().V1alpha2().HTTPRoutes().Lister(),
 		testReachability:     testReachability,
 		requiredPasses:       5,
+		selfCheckMode:        ctx.ACMEOptions.HTTP01SelfCheckMode,
+		selfCheckProxy:       selfCheckProxy,
 	}, nil
 }
 
@@ -114,6 +134,21 @@ func getServiceType(ch *cmacme.Challenge) (corev1.ServiceType, error) {
 	return "", fmt.Errorf("neither HTTP01 Ingress nor Gateway solvers were found")
 }
 
+// getServiceTemplate returns the solver service template of the challenge's
+// HTTP01 solver, if any.
+func getServiceTemplate(ch *cmacme.Challenge) *cmacme.ACMEChallengeSolverHTTP01ServiceTemplate {
+	if ch.Spec.Solver.HTTP01 == nil {
+		return nil
+	}
+	if ch.Spec.Solver.HTTP01.Ingress != nil {
+		return ch.Spec.Solver.HTTP01.Ingress.ServiceTemplate
+	}
+	if ch.Spec.Solver.HTTP01.GatewayHTTPRoute != nil {
+		return ch.Spec.Solver.HTTP01.GatewayHTTPRoute.ServiceTemplate
+	}
+	return nil
+}
+
 // Present will realise the resources required to solve the given HTTP01
 // challenge validation in the apiserver. If those resources already exist, it
 // will return nil (i.e. this function is idempotent).
@@ -167,13 +202,16 @@ func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.
 
 	ctx, cancel := context.WithTimeout(ctx, HTTP01Timeout)
 	defer cancel()
-	url := s.buildChallengeUrl(ch)
-	log = log.WithValues("url", url)
+	req, err := s.buildSelfCheckRequest(ctx, ch)
+	if err != nil {
+		return err
+	}
+	log = log.WithValues("url", req.url)
 	ctx = logf.NewContext(ctx, log)
 
 	log.V(logf.DebugLevel).Info("running self check multiple times to ensure challenge has propagated", "required_passes", s.requiredPasses)
 	for i := 0; i < s.requiredPasses; i++ {
-		err := s.testReachability(ctx, url, ch.Spec.Key)
+		err := s.testReachability(ctx, req, ch.Spec.Key)
 		if err != nil {
 			return err
 		}
@@ -210,17 +248,47 @@ func (s *Solver) buildChallengeUrl(ch *cmacme.Challenge) *url.URL {
 	return url
 }
 
+// buildSelfCheckRequest returns the request used to self-check the given
+// challenge. In local mode, the request is sent straight to the solver pod
+// with the challenge's domain as the Host header, bypassing any proxy, so that
+// clusters which cannot reach their own public address can still make progress.
+func (s *Solver) buildSelfCheckRequest(ctx context.Context, ch *cmacme.Challenge) (selfCheckRequest, error) {
+	url := s.buildChallengeUrl(ch)
+	if s.selfCheckMode != controller.HTTP01SelfCheckLocal {
+		return selfCheckRequest{url: url, proxy: s.selfCheckProxy}, nil
+	}
+
+	pods, err := s.getPodsForChallenge(ctx, ch)
+	if err != nil {
+		return selfCheckRequest{}, err
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Status.PodIP) == 0 {
+			continue
+		}
+		host := url.Host
+		url.Host = net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(acmeSolverListenPort))
+		return selfCheckRequest{url: url, host: host}, nil
+	}
+
+	return selfCheckRequest{}, fmt.Errorf("no running HTTP01 solver pod found for challenge %s/%s", ch.Namespace, ch.Name)
+}
+
 // testReachability will attempt to connect to the 'domain' with 'path' and
 // check if the returned body equals 'key'
-func testReachability(ctx context.Context, url *url.URL, key string) error {
+func testReachability(ctx context.Context, selfCheck selfCheckRequest, key string) error {
 	log := logf.FromContext(ctx)
 	log.V(logf.DebugLevel).Info("performing HTTP01 reachability check")
 
+	url := selfCheck.url
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("User-Agent", pkgutil.CertManagerUserAgent)
+	if len(selfCheck.host) > 0 {
+		req.Host = selfCheck.host
+	}
 
 	// The ACME spec says that a verifier should try on http port 80 first, but to follow any
 	// redirects which may be returned. Let's Encrypt, in practice, follows redirects for HTTP
@@ -248,7 +316,7 @@ func testReachability(ctx context.Context, url *url.URL, key string) error {
 
 	// See https://blog.cloudflare.com/the-complete-guide-to-golang-net-http-timeouts/#clienttimeouts for details on timeouts
 	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
+		Proxy: selfCheck.proxy,
 		// we're only doing 1 request, make the code around this
 		// simpler by disabling keepalives
 		DisableKeepAlives: true,
@@ -19,18 +19,24 @@ package http
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
 )
 
 // countReachabilityTestCalls is a wrapper function that allows us to count the number
 // of calls to a reachabilityTest.
 func countReachabilityTestCalls(counter *int, t reachabilityTest) reachabilityTest {
-	return func(ctx context.Context, url *url.URL, key string) error {
+	return func(ctx context.Context, req selfCheckRequest, key string) error {
 		*counter++
-		return t(ctx, url, key)
+		return t(ctx, req, key)
 	}
 }
 
@@ -44,14 +50,14 @@ func TestCheck(t *testing.T) {
 	tests := []testT{
 		{
 			name: "should pass",
-			reachabilityTest: func(context.Context, *url.URL, string) error {
+			reachabilityTest: func(context.Context, selfCheckRequest, string) error {
 				return nil
 			},
 			expectedErr: false,
 		},
 		{
 			name: "should error",
-			reachabilityTest: func(context.Context, *url.URL, string) error {
+			reachabilityTest: func(context.Context, selfCheckRequest, string) error {
 				return fmt.Errorf("failed")
 			},
 			expectedErr: true,
@@ -87,3 +93,136 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSelfCheckRequest(t *testing.T) {
+	challenge := &cmacme.Challenge{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultTestNamespace, Name: "test"},
+		Spec: cmacme.ChallengeSpec{
+			DNSName: "example.com",
+			Token:   "token",
+			Key:     "key",
+			Solver: cmacme.ACMEChallengeSolver{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+				},
+			},
+		},
+	}
+	createSolverPod := func(t *testing.T, s *solverFixture, phase corev1.PodPhase) {
+		pod := s.Solver.buildPod(s.Challenge)
+		pod.Name = "solver"
+		pod.Status = corev1.PodStatus{Phase: phase, PodIP: "10.0.0.1"}
+		if _, err := s.Builder.FakeKubeClient().CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error preparing test: %v", err)
+		}
+	}
+
+	tests := map[string]solverFixture{
+		"external mode should query the challenge domain through the configured proxy": {
+			Challenge: challenge,
+			PreFn: func(t *testing.T, s *solverFixture) {
+				s.Solver.selfCheckMode = controller.HTTP01SelfCheckExternal
+				s.Solver.selfCheckProxy = http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"})
+			},
+			CheckFn: func(t *testing.T, s *solverFixture, args ...interface{}) {
+				req := args[0].(selfCheckRequest)
+				if req.url.String() != "http://example.com/.well-known/acme-challenge/token" {
+					t.Errorf("unexpected url: %s", req.url)
+				}
+				if len(req.host) > 0 {
+					t.Errorf("unexpected host override: %s", req.host)
+				}
+				proxy, err := req.proxy(&http.Request{URL: req.url})
+				if err != nil || proxy == nil || proxy.Host != "proxy.example.com:3128" {
+					t.Errorf("unexpected proxy: %v, %v", proxy, err)
+				}
+			},
+		},
+		"local mode should query the running solver pod directly": {
+			Challenge: challenge,
+			PreFn: func(t *testing.T, s *solverFixture) {
+				s.Solver.selfCheckMode = controller.HTTP01SelfCheckLocal
+				s.Solver.selfCheckProxy = http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"})
+				createSolverPod(t, s, corev1.PodRunning)
+			},
+			CheckFn: func(t *testing.T, s *solverFixture, args ...interface{}) {
+				req := args[0].(selfCheckRequest)
+				if req.url.String() != "http://10.0.0.1:8089/.well-known/acme-challenge/token" {
+					t.Errorf("unexpected url: %s", req.url)
+				}
+				if req.host != "example.com" {
+					t.Errorf("expected host override example.com but got %q", req.host)
+				}
+				if req.proxy != nil {
+					t.Errorf("expected local self check to not use a proxy")
+				}
+			},
+		},
+		"local mode should error if the solver pod is not running yet": {
+			Challenge: challenge,
+			PreFn: func(t *testing.T, s *solverFixture) {
+				s.Solver.selfCheckMode = controller.HTTP01SelfCheckLocal
+				createSolverPod(t, s, corev1.PodPending)
+			},
+			Err: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.Setup(t)
+			req, err := test.Solver.buildSelfCheckRequest(context.TODO(), test.Challenge)
+			if err != nil && !test.Err {
+				t.Errorf("Expected function to not error, but got: %v", err)
+			}
+			if err == nil && test.Err {
+				t.Errorf("Expected function to get an error, but got: %v", err)
+			}
+			test.Finish(t, req, err)
+		})
+	}
+}
+
+func TestReachabilityHostAndProxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "key")
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challengeURL := &url.URL{Scheme: "http", Host: "example.com", Path: "/.well-known/acme-challenge/token"}
+	directURL := *challengeURL
+	directURL.Host = serverURL.Host
+
+	tests := map[string]struct {
+		req         selfCheckRequest
+		expectedErr bool
+	}{
+		"should send the request through the configured proxy": {
+			req: selfCheckRequest{url: challengeURL, proxy: http.ProxyURL(serverURL)},
+		},
+		"should override the Host header when querying the solver directly": {
+			req: selfCheckRequest{url: &directURL, host: "example.com"},
+		},
+		"should fail if the Host header does not match the challenge domain": {
+			req:         selfCheckRequest{url: &directURL},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := testReachability(context.TODO(), test.req, "key")
+			if (err != nil) != test.expectedErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+		})
+	}
+}
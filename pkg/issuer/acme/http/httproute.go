@@ -25,7 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/pointer"
-	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha1"
+	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -77,7 +77,7 @@ func (s *Solver) getGatewayHTTPRoute(ctx context.Context, ch *cmacme.Challenge)
 	default:
 		for _, httpRoute := range httpRoutes[1:] {
 			log.Info("Deleting extra HTTPRoute", "name", httpRoute.Name, "namespace", httpRoute.Namespace)
-			err := s.GWClient.NetworkingV1alpha1().HTTPRoutes(httpRoute.Namespace).Delete(ctx, httpRoute.Name, metav1.DeleteOptions{})
+			err := s.GWClient.GatewayV1alpha2().HTTPRoutes(httpRoute.Namespace).Delete(ctx, httpRoute.Name, metav1.DeleteOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -102,7 +102,7 @@ func (s *Solver) createGatewayHTTPRoute(ctx context.Context, ch *cmacme.Challeng
 		},
 		Spec: generateHTTPRouteSpec(ch, svcName),
 	}
-	newHTTPRoute, err := s.GWClient.NetworkingV1alpha1().HTTPRoutes(ch.Namespace).Create(ctx, httpRoute, metav1.CreateOptions{})
+	newHTTPRoute, err := s.GWClient.GatewayV1alpha2().HTTPRoutes(ch.Namespace).Create(ctx, httpRoute, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -127,14 +127,14 @@ func (s *Solver) checkAndUpdateGatewayHTTPRoute(ctx context.Context, ch *cmacme.
 	var ret *gwapi.HTTPRoute
 	var err error
 	if err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		oldHTTPRoute, err := s.GWClient.NetworkingV1alpha1().HTTPRoutes(httpRoute.Namespace).Get(ctx, httpRoute.Name, metav1.GetOptions{})
+		oldHTTPRoute, err := s.GWClient.GatewayV1alpha2().HTTPRoutes(httpRoute.Namespace).Get(ctx, httpRoute.Name, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		newHTTPRoute := oldHTTPRoute.DeepCopy()
 		newHTTPRoute.Spec = expectedSpec
 		newHTTPRoute.Labels = expectedLabels
-		ret, err = s.GWClient.NetworkingV1alpha1().HTTPRoutes(newHTTPRoute.Namespace).Update(ctx, newHTTPRoute, metav1.UpdateOptions{})
+		ret, err = s.GWClient.GatewayV1alpha2().HTTPRoutes(newHTTPRoute.Namespace).Update(ctx, newHTTPRoute, metav1.UpdateOptions{})
 		if err != nil {
 			return err
 		}
@@ -147,8 +147,8 @@ func (s *Solver) checkAndUpdateGatewayHTTPRoute(ctx context.Context, ch *cmacme.
 
 func generateHTTPRouteSpec(ch *cmacme.Challenge, svcName string) gwapi.HTTPRouteSpec {
 	return gwapi.HTTPRouteSpec{
-		Gateways: &gwapi.RouteGateways{
-			Allow: func() *gwapi.GatewayAllowType { a := gwapi.GatewayAllowAll; return &a }(),
+		CommonRouteSpec: gwapi.CommonRouteSpec{
+			ParentRefs: generateRouteParentRefs(ch),
 		},
 		Hostnames: []gwapi.Hostname{
 			gwapi.Hostname(ch.Spec.DNSName),
@@ -163,10 +163,14 @@ func generateHTTPRouteSpec(ch *cmacme.Challenge, svcName string) gwapi.HTTPRoute
 						},
 					},
 				},
-				ForwardTo: []gwapi.HTTPRouteForwardTo{
+				BackendRefs: []gwapi.HTTPBackendRef{
 					{
-						ServiceName: pointer.String(svcName),
-						Port:        func() *gwapi.PortNumber { p := gwapi.PortNumber(acmeSolverListenPort); return &p }(),
+						BackendRef: gwapi.BackendRef{
+							BackendObjectReference: gwapi.BackendObjectReference{
+								Name: gwapi.ObjectName(svcName),
+								Port: func() *gwapi.PortNumber { p := gwapi.PortNumber(acmeSolverListenPort); return &p }(),
+							},
+						},
 					},
 				},
 			},
@@ -174,6 +178,29 @@ func generateHTTPRouteSpec(ch *cmacme.Challenge, svcName string) gwapi.HTTPRoute
 	}
 }
 
+// generateRouteParentRefs returns the Gateways the HTTPRoute for the given
+// challenge is attached to. Gateways without a namespace are looked up in the
+// namespace of the HTTPRoute, which is the namespace of the challenge.
+func generateRouteParentRefs(ch *cmacme.Challenge) []gwapi.ParentRef {
+	if ch.Spec.Solver.HTTP01 == nil || ch.Spec.Solver.HTTP01.GatewayHTTPRoute == nil {
+		return nil
+	}
+	parentRefs := ch.Spec.Solver.HTTP01.GatewayHTTPRoute.ParentRefs
+	if len(parentRefs) == 0 {
+		return nil
+	}
+
+	refs := make([]gwapi.ParentRef, len(parentRefs))
+	for i, ref := range parentRefs {
+		refs[i] = gwapi.ParentRef{Name: gwapi.ObjectName(ref.Name)}
+		if len(ref.Namespace) > 0 {
+			namespace := gwapi.Namespace(ref.Namespace)
+			refs[i].Namespace = &namespace
+		}
+	}
+	return refs
+}
+
 func (s *Solver) cleanupGatewayHTTPRoutes(_ context.Context, _ *cmacme.Challenge) error {
 	// Unlike Ingress, we don't modify existing HTTPRoutes so there is nothing to do here.
 	return nil
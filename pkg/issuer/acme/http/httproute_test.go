@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapi "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+func TestGenerateRouteParentRefs(t *testing.T) {
+	gateways := gwapi.Namespace("gateways")
+
+	tests := map[string]struct {
+		parentRefs []cmacme.ACMEChallengeSolverHTTP01GatewayParentRef
+		expected   []gwapi.ParentRef
+	}{
+		"no parentRefs attaches the HTTPRoute to no Gateways": {},
+		"parentRefs attach the HTTPRoute to the named Gateways": {
+			parentRefs: []cmacme.ACMEChallengeSolverHTTP01GatewayParentRef{
+				{Name: "local"},
+				{Name: "shared", Namespace: "gateways"},
+			},
+			expected: []gwapi.ParentRef{
+				{Name: "local"},
+				{Name: "shared", Namespace: &gateways},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := &cmacme.Challenge{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: cmacme.ChallengeSpec{
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							GatewayHTTPRoute: &cmacme.ACMEChallengeSolverHTTP01GatewayHTTPRoute{
+								ParentRefs: test.parentRefs,
+							},
+						},
+					},
+				},
+			}
+			got := generateRouteParentRefs(ch)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected ParentRefs, exp=%+v got=%+v", test.expected, got)
+			}
+		})
+	}
+}
@@ -18,9 +18,11 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -96,7 +98,70 @@ func (s *Solver) createService(ctx context.Context, ch *cmacme.Challenge) (*core
 	if err != nil {
 		return nil, err
 	}
-	return s.Client.CoreV1().Services(ch.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+
+	serviceTemplate := getServiceTemplate(ch)
+	if serviceTemplate == nil || serviceTemplate.Spec.NodePortRange == nil || !exposedOnNodes(svc) {
+		return s.Client.CoreV1().Services(ch.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+	}
+
+	return s.createServiceWithNodePortFromRange(ctx, svc, serviceTemplate.Spec.NodePortRange)
+}
+
+// createServiceWithNodePortFromRange creates the solver service with a node
+// port of the given range. Ports that are in use by services known to the
+// service lister are skipped. As the lister may not know about every service
+// in the cluster, the next free port is tried if the apiserver reports that
+// the port is already allocated.
+func (s *Solver) createServiceWithNodePortFromRange(ctx context.Context, svc *corev1.Service, portRange *cmacme.ACMEChallengeSolverHTTP01NodePortRange) (*corev1.Service, error) {
+	services, err := s.serviceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[int32]bool)
+	for _, service := range services {
+		for _, port := range service.Spec.Ports {
+			used[port.NodePort] = true
+		}
+	}
+
+	for port := portRange.Min; port <= portRange.Max; port++ {
+		if used[port] {
+			continue
+		}
+
+		svc.Spec.Ports[0].NodePort = port
+		created, err := s.Client.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+		if isNodePortAllocatedError(err) {
+			continue
+		}
+		return created, err
+	}
+
+	return nil, fmt.Errorf("no free node port in range %d-%d for the HTTP01 solver service", portRange.Min, portRange.Max)
+}
+
+// isNodePortAllocatedError returns true if the apiserver rejected a service
+// because its node port is invalid, e.g. because it is already allocated.
+func isNodePortAllocatedError(err error) bool {
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return false
+	}
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Field == "spec.ports[0].nodePort" {
+			return true
+		}
+	}
+	return false
+}
+
+// exposedOnNodes returns true if the service is exposed on a port of each
+// node.
+func exposedOnNodes(service *corev1.Service) bool {
+	return service.Spec.Type == corev1.ServiceTypeNodePort || service.Spec.Type == corev1.ServiceTypeLoadBalancer
 }
 
 func buildService(ch *cmacme.Challenge) (*corev1.Service, error) {
@@ -133,9 +198,30 @@ func buildService(ch *cmacme.Challenge) (*corev1.Service, error) {
 		service.Spec.Type = serviceType
 	}
 
+	if serviceTemplate := getServiceTemplate(ch); serviceTemplate != nil {
+		applyServiceSpecTemplate(service, serviceTemplate.Spec)
+	}
+
 	return service, nil
 }
 
+// applyServiceSpecTemplate applies the overrides of a solver service template
+// to the solver service built for a challenge. The external traffic policy is
+// only applied to services that are exposed on each node, i.e. NodePort and
+// LoadBalancer services, and the load balancer class only to LoadBalancer
+// services; overrides that do not apply to the type of the service are
+// ignored. The node port range is not applied here, as a free port of the
+// range is only allocated when the service is created.
+func applyServiceSpecTemplate(service *corev1.Service, spec cmacme.ACMEChallengeSolverHTTP01ServiceSpec) {
+	if exposedOnNodes(service) {
+		service.Spec.ExternalTrafficPolicy = spec.ExternalTrafficPolicy
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		service.Spec.LoadBalancerClass = spec.LoadBalancerClass
+	}
+}
+
 func (s *Solver) cleanupServices(ctx context.Context, ch *cmacme.Challenge) error {
 	log := logf.FromContext(ctx, "cleanupPods")
 
@@ -22,11 +22,15 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	coretesting "k8s.io/client-go/testing"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
 )
 
 func TestEnsureService(t *testing.T) {
@@ -410,6 +414,171 @@ func TestEnsureService(t *testing.T) {
 	}
 }
 
+func TestBuildServiceWithServiceTemplate(t *testing.T) {
+	lbClass := "example.com/lb"
+	template := &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+		Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+			NodePortRange:         &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 30080, Max: 30089},
+			ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+			LoadBalancerClass:     &lbClass,
+		},
+	}
+
+	tests := map[string]struct {
+		http01 *cmacme.ACMEChallengeSolverHTTP01
+
+		expType                  v1.ServiceType
+		expExternalTrafficPolicy v1.ServiceExternalTrafficPolicyType
+		expLoadBalancerClass     *string
+	}{
+		"should default to a NodePort service without overrides": {
+			http01:  &cmacme.ACMEChallengeSolverHTTP01{Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{}},
+			expType: v1.ServiceTypeNodePort,
+		},
+		"should apply the traffic policy override to a NodePort service": {
+			http01: &cmacme.ACMEChallengeSolverHTTP01{Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+				ServiceTemplate: template,
+			}},
+			expType:                  v1.ServiceTypeNodePort,
+			expExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+		},
+		"should apply all overrides to a LoadBalancer service": {
+			http01: &cmacme.ACMEChallengeSolverHTTP01{GatewayHTTPRoute: &cmacme.ACMEChallengeSolverHTTP01GatewayHTTPRoute{
+				ServiceType:     v1.ServiceTypeLoadBalancer,
+				ServiceTemplate: template,
+			}},
+			expType:                  v1.ServiceTypeLoadBalancer,
+			expExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+			expLoadBalancerClass:     &lbClass,
+		},
+		"should ignore overrides that do not apply to a ClusterIP service": {
+			http01: &cmacme.ACMEChallengeSolverHTTP01{Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+				ServiceType:     v1.ServiceTypeClusterIP,
+				ServiceTemplate: template,
+			}},
+			expType: v1.ServiceTypeClusterIP,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := &cmacme.Challenge{
+				Spec: cmacme.ChallengeSpec{
+					DNSName: "example.com",
+					Solver:  cmacme.ACMEChallengeSolver{HTTP01: test.http01},
+				},
+			}
+			svc, err := buildService(ch)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if svc.Spec.Type != test.expType {
+				t.Errorf("unexpected service type, exp=%q got=%q", test.expType, svc.Spec.Type)
+			}
+			if svc.Spec.Ports[0].NodePort != 0 {
+				t.Errorf("unexpected node port, the node port should only be allocated when the service is created, got=%d", svc.Spec.Ports[0].NodePort)
+			}
+			if svc.Spec.ExternalTrafficPolicy != test.expExternalTrafficPolicy {
+				t.Errorf("unexpected external traffic policy, exp=%q got=%q", test.expExternalTrafficPolicy, svc.Spec.ExternalTrafficPolicy)
+			}
+			if !reflect.DeepEqual(svc.Spec.LoadBalancerClass, test.expLoadBalancerClass) {
+				t.Errorf("unexpected load balancer class, exp=%v got=%v", test.expLoadBalancerClass, svc.Spec.LoadBalancerClass)
+			}
+		})
+	}
+}
+
+func TestCreateServiceWithNodePortRange(t *testing.T) {
+	nodePortService := func(name string, nodePort int32) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: name},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeNodePort,
+				Ports: []v1.ServicePort{{Port: 80, NodePort: nodePort}},
+			},
+		}
+	}
+	allocatedErr := apierrors.NewInvalid(v1.SchemeGroupVersion.WithKind("Service").GroupKind(), "", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "ports").Index(0).Child("nodePort"), 30081, "provided port is already allocated"),
+	})
+
+	tests := map[string]struct {
+		services      []runtime.Object
+		allocated     map[int32]bool
+		expNodePort   int32
+		expErr        bool
+		expCreateCall int
+	}{
+		"should allocate the first port of the range": {
+			expNodePort:   30080,
+			expCreateCall: 1,
+		},
+		"should skip ports used by known services": {
+			services:      []runtime.Object{nodePortService("a", 30080), nodePortService("b", 30081)},
+			expNodePort:   30082,
+			expCreateCall: 1,
+		},
+		"should try the next port if the apiserver reports that a port is allocated": {
+			services:      []runtime.Object{nodePortService("a", 30080)},
+			allocated:     map[int32]bool{30081: true},
+			expNodePort:   30082,
+			expCreateCall: 2,
+		},
+		"should fail if there is no free port in the range": {
+			services:      []runtime.Object{nodePortService("a", 30080), nodePortService("b", 30081)},
+			allocated:     map[int32]bool{30082: true},
+			expErr:        true,
+			expCreateCall: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &solverFixture{
+				Builder: &testpkg.Builder{KubeObjects: test.services},
+				Challenge: &cmacme.Challenge{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+					Spec: cmacme.ChallengeSpec{
+						DNSName: "example.com",
+						Solver: cmacme.ACMEChallengeSolver{HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+								ServiceTemplate: &cmacme.ACMEChallengeSolverHTTP01ServiceTemplate{
+									Spec: cmacme.ACMEChallengeSolverHTTP01ServiceSpec{
+										NodePortRange: &cmacme.ACMEChallengeSolverHTTP01NodePortRange{Min: 30080, Max: 30082},
+									},
+								},
+							},
+						}},
+					},
+				},
+			}
+			s.Setup(t)
+			defer s.Builder.Stop()
+
+			createCalls := 0
+			s.Builder.FakeKubeClient().PrependReactor("create", "services", func(action coretesting.Action) (bool, runtime.Object, error) {
+				createCalls++
+				svc := action.(coretesting.CreateAction).GetObject().(*v1.Service)
+				if test.allocated[svc.Spec.Ports[0].NodePort] {
+					return true, nil, allocatedErr
+				}
+				return false, nil, nil
+			})
+
+			svc, err := s.Solver.createService(context.TODO(), s.Challenge)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if createCalls != test.expCreateCall {
+				t.Errorf("unexpected number of create calls, exp=%d got=%d", test.expCreateCall, createCalls)
+			}
+			if err == nil && svc.Spec.Ports[0].NodePort != test.expNodePort {
+				t.Errorf("unexpected node port, exp=%d got=%d", test.expNodePort, svc.Spec.Ports[0].NodePort)
+			}
+		})
+	}
+}
+
 func TestGetServicesForChallenge(t *testing.T) {
 	const createdServiceKey = "createdService"
 	tests := map[string]solverFixture{
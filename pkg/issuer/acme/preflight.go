@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// preflightConfig returns the configuration used to build the ACME client for
+// the preflight server of an issuer. External Account Bindings are specific
+// to a CA, so they are never used with the preflight server.
+func preflightConfig(config cmacme.ACMEIssuer) cmacme.ACMEIssuer {
+	config.Server = config.Preflight.Server
+	config.ExternalAccountBinding = nil
+	return config
+}
+
+// setupPreflight ensures that an ACME account is registered with the
+// issuer's preflight server, if one is configured, and that a client for the
+// account is stored in the account registry. The account uses the same
+// private key as the issuer's account.
+func (a *Acme) setupPreflight(ctx context.Context, httpClient *http.Client, pk *rsa.PrivateKey) error {
+	log := logf.FromContext(ctx)
+
+	uid := accounts.PreflightUID(string(a.issuer.GetUID()))
+	status := a.issuer.GetStatus().ACMEStatus()
+	if a.issuer.GetSpec().ACME.Preflight == nil {
+		a.accountRegistry.RemoveClient(uid)
+		status.PreflightURI = ""
+		return nil
+	}
+
+	config := preflightConfig(*a.issuer.GetSpec().ACME)
+	if !sameHost(status.PreflightURI, config.Server) {
+		log.V(logf.InfoLevel).Info("registering ACME account with preflight server", "server", config.Server)
+		account, err := a.registerAccount(ctx, a.clientBuilder(httpClient, config, pk), nil)
+		if err != nil {
+			return err
+		}
+		status.PreflightURI = account.URI
+	}
+
+	a.accountRegistry.AddClient(httpClient, uid, config, pk)
+	return nil
+}
+
+// sameHost returns true if both URLs can be parsed and have the same host.
+func sameHost(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	parsedA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	parsedB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return parsedA.Host == parsedB.Host
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	fakeregistry "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestAcme_setupPreflight(t *testing.T) {
+	const (
+		stagingServer = "https://acme-staging-v02.api.letsencrypt.org/directory"
+		stagingURI    = "https://acme-staging-v02.api.letsencrypt.org/acme/acct/1"
+	)
+	pk := mustGenerateRSAKey(t).(*rsa.PrivateKey)
+
+	tests := map[string]struct {
+		preflight   *cmacme.ACMEIssuerPreflight
+		existingURI string
+		registerErr error
+
+		expectRegister bool
+		expectAdded    bool
+		expectRemoved  bool
+		expectURI      string
+		expectErr      bool
+	}{
+		"preflight not configured removes the client and clears the account": {
+			existingURI:   stagingURI,
+			expectRemoved: true,
+		},
+		"account is registered with the preflight server": {
+			preflight:      &cmacme.ACMEIssuerPreflight{Server: stagingServer},
+			expectRegister: true,
+			expectAdded:    true,
+			expectURI:      stagingURI,
+		},
+		"existing account on the preflight server is reused": {
+			preflight:   &cmacme.ACMEIssuerPreflight{Server: stagingServer},
+			existingURI: stagingURI,
+			expectAdded: true,
+			expectURI:   stagingURI,
+		},
+		"account is registered again if the preflight server changed": {
+			preflight:      &cmacme.ACMEIssuerPreflight{Server: stagingServer},
+			existingURI:    "https://other.example.com/acct/1",
+			expectRegister: true,
+			expectAdded:    true,
+			expectURI:      stagingURI,
+		},
+		"registration failure is returned": {
+			preflight:      &cmacme.ACMEIssuerPreflight{Server: stagingServer},
+			registerErr:    &acmeapi.Error{StatusCode: http.StatusBadRequest},
+			expectRegister: true,
+			expectErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			issuer := gen.Issuer("test",
+				gen.SetIssuerACMEURL(acmev2Prod),
+				func(iss cmapi.GenericIssuer) {
+					iss.GetSpec().ACME.Preflight = test.preflight
+					iss.GetStatus().ACMEStatus().PreflightURI = test.existingURI
+				})
+
+			registered := false
+			cl := &acmecl.FakeACME{
+				FakeRegister: func(context.Context, *acmeapi.Account, func(string) bool) (*acmeapi.Account, error) {
+					registered = true
+					if test.registerErr != nil {
+						return nil, test.registerErr
+					}
+					return &acmeapi.Account{URI: stagingURI}, nil
+				},
+			}
+
+			var builtServer string
+			added, removed := false, false
+			a := Acme{
+				issuer: issuer,
+				clientBuilder: func(_ *http.Client, config cmacme.ACMEIssuer, _ *rsa.PrivateKey) acmecl.Interface {
+					builtServer = config.Server
+					return cl
+				},
+				accountRegistry: &fakeregistry.FakeRegistry{
+					AddClientFunc: func(uid string, config cmacme.ACMEIssuer, _ *rsa.PrivateKey) {
+						added = true
+						if uid != "/preflight" || config.Server != stagingServer {
+							t.Errorf("unexpected client added for %q with server %q", uid, config.Server)
+						}
+					},
+					RemoveClientFunc: func(uid string) {
+						removed = true
+					},
+				},
+			}
+
+			err := a.setupPreflight(context.Background(), nil, pk)
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected error %v, got %v", test.expectErr, err)
+			}
+			if registered != test.expectRegister {
+				t.Errorf("expected account to be registered: %v, was registered: %v", test.expectRegister, registered)
+			}
+			if registered && builtServer != stagingServer {
+				t.Errorf("expected account to be registered with %q, got %q", stagingServer, builtServer)
+			}
+			if added != test.expectAdded {
+				t.Errorf("expected client to be added: %v, was added: %v", test.expectAdded, added)
+			}
+			if removed != test.expectRemoved {
+				t.Errorf("expected client to be removed: %v, was removed: %v", test.expectRemoved, removed)
+			}
+			if !test.expectErr && issuer.Status.ACME.PreflightURI != test.expectURI {
+				t.Errorf("expected preflight account URI %q, got %q", test.expectURI, issuer.Status.ACME.PreflightURI)
+			}
+		})
+	}
+}
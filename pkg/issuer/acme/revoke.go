@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/acme"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+)
+
+// errAlreadyRevoked is the ACME problem type returned when revoking a
+// certificate that has already been revoked (RFC 8555, 6.7).
+const errAlreadyRevoked = "urn:ietf:params:acme:error:alreadyRevoked"
+
+var _ issuer.Revoker = &Acme{}
+
+// Revoke revokes the given certificate with the ACME server, authorizing the
+// request using the key of the issuer's ACME account.
+func (a *Acme) Revoke(ctx context.Context, _ *cmapi.Certificate, cert *x509.Certificate) error {
+	cl, err := a.accountRegistry.GetClient(string(a.issuer.GetUID()))
+	if err != nil {
+		return fmt.Errorf("ACME client for issuer not initialised/available: %w", err)
+	}
+
+	err = cl.RevokeCert(ctx, nil, cert.Raw, acme.CRLReasonUnspecified)
+	var acmeErr *acme.Error
+	if errors.As(err, &acmeErr) && acmeErr.ProblemType == errAlreadyRevoked {
+		return nil
+	}
+
+	return err
+}
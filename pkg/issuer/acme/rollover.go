@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jetstack/cert-manager/pkg/acme"
+	"github.com/jetstack/cert-manager/pkg/acme/client"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// nextAccountKeySuffix is appended to the account private key's Secret data
+// key to store the new private key while an account key rollover is in
+// progress.
+const nextAccountKeySuffix = ".next"
+
+// rolloverAccountKey replaces the private key of the ACME account at
+// accountURL with a newly generated key and stores the new key in the account
+// private key Secret, returning the new key.
+//
+// The new key is persisted in the Secret before the key change is requested
+// from the ACME server, so that the account is never left with a key that is
+// not stored anywhere. If a previous rollover was interrupted after the ACME
+// server accepted the new key, the rollover is completed using the persisted
+// key.
+func (a *Acme) rolloverAccountKey(ctx context.Context, httpClient *http.Client, cl client.Interface, accountURL string, oldKey *rsa.PrivateKey, sel cmmeta.SecretKeySelector, ns string) (*rsa.PrivateKey, error) {
+	log := logf.FromContext(ctx)
+	sel = acme.PrivateKeySelector(sel)
+	nextKeyName := sel.Key + nextAccountKeySuffix
+
+	secret, err := a.secretsClient.Secrets(ns).Get(ctx, sel.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var newKey *rsa.PrivateKey
+	alreadyChanged := false
+	if data, ok := secret.Data[nextKeyName]; ok {
+		signer, err := pki.DecodePrivateKeyBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode new account private key from key %q: %w", nextKeyName, err)
+		}
+		rsaKey, ok := signer.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("new account private key in key %q is not of type RSA", nextKeyName)
+		}
+		newKey = rsaKey
+
+		// The ACME server may have already accepted the new key if a previous
+		// attempt failed to update the Secret afterwards, in which case the
+		// old key can no longer be used to request the key change.
+		if acc, err := a.clientBuilder(httpClient, *a.issuer.GetSpec().ACME, newKey).GetReg(ctx, ""); err == nil && acc.URI == accountURL {
+			log.V(logf.InfoLevel).Info("ACME account already uses the new private key, completing rollover")
+			alreadyChanged = true
+		}
+	} else {
+		newKey, err = pki.GenerateRSAPrivateKey(pki.MinRSAKeySize)
+		if err != nil {
+			return nil, err
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[nextKeyName] = pki.EncodePKCS1PrivateKey(newKey)
+		secret, err = a.secretsClient.Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store new account private key: %w", err)
+		}
+	}
+
+	if !alreadyChanged {
+		dir, err := cl.Discover(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.accountKeyRollover(ctx, httpClient, dir, accountURL, oldKey, newKey); err != nil {
+			return nil, err
+		}
+	}
+
+	secret.Data[sel.Key] = pki.EncodePKCS1PrivateKey(newKey)
+	delete(secret.Data, nextKeyName)
+	if _, err := a.secretsClient.Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to store new account private key: %w", err)
+	}
+
+	return newKey, nil
+}
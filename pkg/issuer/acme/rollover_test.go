@@ -0,0 +1,314 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fakeregistry "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllertest "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestAcme_rolloverAccountKey(t *testing.T) {
+	const (
+		ns         = "test-ns"
+		accountURL = "https://acme.example.com/acct/1"
+	)
+	sel := cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "account-key"}}
+	oldKey := mustGenerateRSAKey(t).(*rsa.PrivateKey)
+	stagedKey := mustGenerateRSAKey(t).(*rsa.PrivateKey)
+	dir := acmeapi.Directory{KeyChangeURL: "https://acme.example.com/key-change"}
+
+	tests := map[string]struct {
+		// additional data in the account key Secret
+		secretData map[string][]byte
+		// account returned by GetReg for the staged key
+		stagedAccount *acmeapi.Account
+		rolloverErr   error
+
+		expectRolloverCalled bool
+		// whether the returned key is expected to be the staged key
+		expectStagedKey bool
+		expectErr       bool
+	}{
+		"new key is generated and stored": {
+			expectRolloverCalled: true,
+		},
+		"interrupted rollover uses the staged key": {
+			secretData:           map[string][]byte{"tls.key.next": pki.EncodePKCS1PrivateKey(stagedKey)},
+			expectRolloverCalled: true,
+			expectStagedKey:      true,
+		},
+		"rollover already accepted by the ACME server is completed": {
+			secretData:      map[string][]byte{"tls.key.next": pki.EncodePKCS1PrivateKey(stagedKey)},
+			stagedAccount:   &acmeapi.Account{URI: accountURL},
+			expectStagedKey: true,
+		},
+		"key change rejected by the ACME server": {
+			rolloverErr:          &acmeapi.Error{StatusCode: http.StatusBadRequest},
+			expectRolloverCalled: true,
+			expectErr:            true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := map[string][]byte{"tls.key": pki.EncodePKCS1PrivateKey(oldKey)}
+			for k, v := range test.secretData {
+				data[k] = v
+			}
+			cs := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: sel.Name, Namespace: ns},
+				Data:       data,
+			})
+
+			cl := &acmecl.FakeACME{
+				FakeDiscover: func(context.Context) (acmeapi.Directory, error) {
+					return dir, nil
+				},
+				FakeGetReg: func(context.Context, string) (*acmeapi.Account, error) {
+					if test.stagedAccount != nil {
+						return test.stagedAccount, nil
+					}
+					return nil, acmeapi.ErrNoAccount
+				},
+			}
+
+			rolloverCalled := false
+			var rolloverNewKey *rsa.PrivateKey
+			a := Acme{
+				issuer:        gen.Issuer("test", gen.SetIssuerACMEURL(acmev2Prod)),
+				secretsClient: cs.CoreV1(),
+				clientBuilder: clientBuilderMock(cl),
+				accountKeyRollover: func(_ context.Context, _ *http.Client, gotDir acmeapi.Directory, gotURL string, gotOld, gotNew *rsa.PrivateKey) error {
+					rolloverCalled = true
+					rolloverNewKey = gotNew
+					if gotDir.KeyChangeURL != dir.KeyChangeURL || gotURL != accountURL || !gotOld.Equal(oldKey) {
+						t.Errorf("unexpected key change arguments: %v %q", gotDir, gotURL)
+					}
+					return test.rolloverErr
+				},
+			}
+
+			newKey, err := a.rolloverAccountKey(context.Background(), nil, cl, accountURL, oldKey, sel, ns)
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected error %v, got %v", test.expectErr, err)
+			}
+			if rolloverCalled != test.expectRolloverCalled {
+				t.Errorf("expected key change to be requested: %v, was requested: %v", test.expectRolloverCalled, rolloverCalled)
+			}
+
+			secret, err := cs.CoreV1().Secrets(ns).Get(context.Background(), sel.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.expectErr {
+				// The old key must be kept, and the new key retained for
+				// the next attempt.
+				if !bytes.Equal(secret.Data["tls.key"], pki.EncodePKCS1PrivateKey(oldKey)) {
+					t.Errorf("expected old key to remain in the Secret")
+				}
+				if !bytes.Equal(secret.Data["tls.key.next"], pki.EncodePKCS1PrivateKey(rolloverNewKey)) {
+					t.Errorf("expected new key to be staged in the Secret")
+				}
+				return
+			}
+
+			if newKey.Equal(oldKey) {
+				t.Errorf("expected a new key to be returned")
+			}
+			if test.expectStagedKey && !newKey.Equal(stagedKey) {
+				t.Errorf("expected the staged key to be returned")
+			}
+			if rolloverCalled && !newKey.Equal(rolloverNewKey) {
+				t.Errorf("expected the returned key to be the key sent to the ACME server")
+			}
+			if !bytes.Equal(secret.Data["tls.key"], pki.EncodePKCS1PrivateKey(newKey)) {
+				t.Errorf("expected new key to be stored in the Secret")
+			}
+			if _, ok := secret.Data["tls.key.next"]; ok {
+				t.Errorf("expected staged key to be removed from the Secret")
+			}
+		})
+	}
+}
+
+func TestAcme_SetupAccountKeyRollover(t *testing.T) {
+	const accountURL = "https://acme-v02.api.letsencrypt.org/acct/1"
+	oldKey := mustGenerateRSAKey(t).(*rsa.PrivateKey)
+
+	tests := map[string]struct {
+		issuer      cmapi.GenericIssuer
+		rolloverErr error
+
+		expectRollover     bool
+		expectReason       string
+		expectStatus       cmmeta.ConditionStatus
+		expectLastRollover string
+		expectEvents       []string
+		expectErr          bool
+	}{
+		"rollover already performed for annotation value": {
+			issuer: gen.Issuer("test",
+				gen.SetIssuerACMEURL(acmev2Prod),
+				gen.SetIssuerACMEPrivKeyRef("test"),
+				gen.SetIssuerACMEAccountURL(accountURL),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}),
+				func(iss cmapi.GenericIssuer) {
+					iss.GetObjectMeta().Annotations = map[string]string{cmacme.AccountKeyRolloverAnnotationKey: "1"}
+					iss.GetStatus().ACME.LastAccountKeyRollover = "1"
+				}),
+			expectReason:       successAccountRegistered,
+			expectStatus:       cmmeta.ConditionTrue,
+			expectLastRollover: "1",
+		},
+		"rollover requested": {
+			issuer: gen.Issuer("test",
+				gen.SetIssuerACMEURL(acmev2Prod),
+				gen.SetIssuerACMEPrivKeyRef("test"),
+				gen.SetIssuerACMEAccountURL(accountURL),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}),
+				func(iss cmapi.GenericIssuer) {
+					iss.GetObjectMeta().Annotations = map[string]string{cmacme.AccountKeyRolloverAnnotationKey: "2"}
+					iss.GetStatus().ACME.LastAccountKeyRollover = "1"
+				}),
+			expectRollover:     true,
+			expectReason:       successAccountKeyRolledOver,
+			expectStatus:       cmmeta.ConditionTrue,
+			expectLastRollover: "2",
+			expectEvents: []string{
+				fmt.Sprintf("%s %s %s", corev1.EventTypeNormal, successAccountKeyRolledOver, messageAccountKeyRolledOver),
+			},
+		},
+		"rollover rejected by the ACME server": {
+			issuer: gen.Issuer("test",
+				gen.SetIssuerACMEURL(acmev2Prod),
+				gen.SetIssuerACMEPrivKeyRef("test"),
+				gen.SetIssuerACMEAccountURL(accountURL),
+				func(iss cmapi.GenericIssuer) {
+					iss.GetObjectMeta().Annotations = map[string]string{cmacme.AccountKeyRolloverAnnotationKey: "1"}
+				}),
+			rolloverErr:    &acmeapi.Error{StatusCode: http.StatusForbidden},
+			expectRollover: true,
+			expectReason:   errorAccountKeyRolloverFailed,
+			expectStatus:   cmmeta.ConditionFalse,
+			expectEvents: []string{
+				fmt.Sprintf("%s %s %s", corev1.EventTypeWarning, errorAccountKeyRolloverFailed,
+					messageAccountKeyRolloverFailed+(&acmeapi.Error{StatusCode: http.StatusForbidden}).Error()),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: test.issuer.GetObjectMeta().Namespace},
+				Data:       map[string][]byte{"tls.key": pki.EncodePKCS1PrivateKey(oldKey)},
+			})
+
+			cl := &acmecl.FakeACME{
+				FakeRegister: func(context.Context, *acmeapi.Account, func(string) bool) (*acmeapi.Account, error) {
+					return nil, acmeapi.ErrAccountAlreadyExists
+				},
+				FakeGetReg: func(context.Context, string) (*acmeapi.Account, error) {
+					return &acmeapi.Account{URI: accountURL}, nil
+				},
+				FakeDiscover: func(context.Context) (acmeapi.Directory, error) {
+					return acmeapi.Directory{}, nil
+				},
+			}
+
+			var addedKey *rsa.PrivateKey
+			ar := &fakeregistry.FakeRegistry{
+				RemoveClientFunc: func(string) {},
+				AddClientFunc: func(_ string, _ cmacme.ACMEIssuer, key *rsa.PrivateKey) {
+					addedKey = key
+				},
+			}
+
+			rolledOver := false
+			recorder := new(controllertest.FakeRecorder)
+			a := Acme{
+				issuer:          test.issuer,
+				secretsClient:   cs.CoreV1(),
+				accountRegistry: ar,
+				keyFromSecret: func(context.Context, string, string, string) (crypto.Signer, error) {
+					return oldKey, nil
+				},
+				clientBuilder: clientBuilderMock(cl),
+				accountKeyRollover: func(context.Context, *http.Client, acmeapi.Directory, string, *rsa.PrivateKey, *rsa.PrivateKey) error {
+					rolledOver = true
+					return test.rolloverErr
+				},
+				recorder: recorder,
+			}
+
+			err := a.Setup(context.Background())
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected error %v, got %v", test.expectErr, err)
+			}
+			if rolledOver != test.expectRollover {
+				t.Errorf("expected rollover: %v, got: %v", test.expectRollover, rolledOver)
+			}
+
+			cond := a.issuer.GetStatus().Conditions[0]
+			if cond.Reason != test.expectReason || cond.Status != test.expectStatus {
+				t.Errorf("expected Ready condition %s/%s, got %s/%s", test.expectStatus, test.expectReason, cond.Status, cond.Reason)
+			}
+			if got := a.issuer.GetStatus().ACMEStatus().LastAccountKeyRollover; got != test.expectLastRollover {
+				t.Errorf("expected last rollover %q, got %q", test.expectLastRollover, got)
+			}
+			if len(recorder.Events) != len(test.expectEvents) {
+				t.Fatalf("expected events %v, got %v", test.expectEvents, recorder.Events)
+			}
+			for i := range recorder.Events {
+				if recorder.Events[i] != test.expectEvents[i] {
+					t.Errorf("expected event %q, got %q", test.expectEvents[i], recorder.Events[i])
+				}
+			}
+
+			switch {
+			case test.expectReason == errorAccountKeyRolloverFailed:
+				if addedKey != nil {
+					t.Errorf("expected no client to be added to the registry")
+				}
+			case test.expectRollover && (addedKey == nil || addedKey.Equal(oldKey)):
+				t.Errorf("expected client with the new key to be added to the registry")
+			case !test.expectRollover && (addedKey == nil || !addedKey.Equal(oldKey)):
+				t.Errorf("expected client with the existing key to be added to the registry")
+			}
+		})
+	}
+}
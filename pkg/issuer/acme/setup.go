@@ -19,7 +19,9 @@ package acme
 import (
 	"context"
 	"crypto/rsa"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"strings"
@@ -33,6 +35,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
 	"github.com/jetstack/cert-manager/pkg/acme/client"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -44,16 +47,26 @@ const (
 	errorAccountRegistrationFailed = "ErrRegisterACMEAccount"
 	errorAccountVerificationFailed = "ErrVerifyACMEAccount"
 	errorAccountUpdateFailed       = "ErrUpdateACMEAccount"
+	errorAccountKeyRolloverFailed  = "ErrACMEAccountKeyRollover"
 	errorInvalidConfig             = "InvalidConfig"
 	errorInvalidURL                = "InvalidURL"
 
-	successAccountRegistered = "ACMEAccountRegistered"
-	successAccountVerified   = "ACMEAccountVerified"
+	successAccountRegistered    = "ACMEAccountRegistered"
+	successAccountReregistered  = "ACMEAccountReregistered"
+	successAccountVerified      = "ACMEAccountVerified"
+	successAccountKeyRolledOver = "ACMEAccountKeyRolledOver"
+
+	reasonEABKeyRotated = "EABKeyRotated"
 
 	messageAccountRegistrationFailed     = "Failed to register ACME account: "
+	messagePreflightRegistrationFailed   = "Failed to register ACME account with preflight server: "
 	messageAccountVerificationFailed     = "Failed to verify ACME account: "
 	messageAccountUpdateFailed           = "Failed to update ACME account:"
+	messageAccountKeyRolloverFailed      = "Failed to roll over ACME account key: "
+	messageAccountKeyRolledOver          = "The ACME account key was rolled over with the ACME server"
 	messageAccountRegistered             = "The ACME account was registered with the ACME server"
+	messageAccountReregistered           = "The ACME account was registered again with the ACME server after the External Account Binding key changed"
+	messageEABKeyRotated                 = "External Account Binding key has changed, registering the ACME account again"
 	messageAccountVerified               = "The ACME account was verified with the ACME server"
 	messageNoSecretKeyGenerationDisabled = "the ACME issuer config has 'disableAccountKeyGeneration' set to true, but the secret was not found: "
 	messageInvalidPrivateKey             = "Account private key is invalid: "
@@ -115,8 +128,9 @@ func (a *Acme) Setup(ctx context.Context) error {
 			reason = errorAccountRegistrationFailed
 			return fmt.Errorf(msg)
 		}
-		// We clear the ACME account URI as we have generated a new private key
+		// We clear the ACME account URIs as we have generated a new private key
 		a.issuer.GetStatus().ACMEStatus().URI = ""
+		a.issuer.GetStatus().ACMEStatus().PreflightURI = ""
 
 	case a.issuer.GetSpec().ACME.DisableAccountKeyGeneration && apierrors.IsNotFound(err):
 		wrapErr := fmt.Errorf("%s%s%v", messageAccountVerificationFailed,
@@ -158,6 +172,22 @@ func (a *Acme) Setup(ctx context.Context) error {
 	httpClient := accounts.BuildHTTPClient(a.metrics, a.issuer.GetSpec().ACME.SkipTLSVerify)
 	cl := a.clientBuilder(httpClient, *a.issuer.GetSpec().ACME, rsaPk)
 
+	// preflightFailed sets the Ready condition for an issuer whose account
+	// could not be registered with its preflight server, and returns the
+	// error that Setup should return.
+	preflightFailed := func(err error) error {
+		reason = errorAccountRegistrationFailed
+		msg = messagePreflightRegistrationFailed + err.Error()
+		log.Error(err, "failed to register an ACME account with the preflight server")
+		a.recorder.Event(a.issuer, corev1.EventTypeWarning, errorAccountRegistrationFailed, msg)
+
+		// Do not retry if the preflight server rejected the request.
+		if acmeErr, ok := err.(*acmeapi.Error); ok && acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
+			return nil
+		}
+		return err
+	}
+
 	// TODO: perform a complex check to determine whether we need to verify
 	// the existing registration with the ACME server.
 	// This should take into account the ACME server URL, as well as a checksum
@@ -191,17 +221,43 @@ func (a *Acme) Setup(ctx context.Context) error {
 		Status: cmmeta.ConditionTrue,
 	})
 
+	// The External Account Binding key is read before deciding whether the
+	// cached registration can be reused so that a rotated key can be detected.
+	// Errors are only surfaced if the account needs to be registered, as CAs
+	// may invalidate the key (and users may delete the Secret) once it has
+	// been used.
+	eabAccount, eabKeyHash, eabErr := a.externalAccountBinding(ctx, ns)
+	lastEABKeyHash := a.issuer.GetStatus().ACMEStatus().LastRegisteredEABKeyHash
+	eabKeyRotated := eabErr == nil && lastEABKeyHash != "" && lastEABKeyHash != eabKeyHash
+
+	// An account key rollover is requested by setting an annotation to a
+	// value that has not yet been processed.
+	rolloverID := a.issuer.GetObjectMeta().Annotations[cmacme.AccountKeyRolloverAnnotationKey]
+	rolloverPending := rolloverID != "" && rolloverID != a.issuer.GetStatus().ACMEStatus().LastAccountKeyRollover
+
 	// If the Host components of the server URL and the account URL match,
-	// and the cached email matches the registered email, then
-	// we skip re-checking the account status to save excess calls to the
-	// ACME api.
+	// the cached email matches the registered email and the External Account
+	// Binding key has not been rotated, then we skip re-checking the account
+	// status to save excess calls to the ACME api.
 	if hasReadyCondition &&
 		a.issuer.GetStatus().ACMEStatus().URI != "" &&
 		parsedAccountURL.Host == parsedServerURL.Host &&
-		a.issuer.GetStatus().ACMEStatus().LastRegisteredEmail == a.issuer.GetSpec().ACME.Email {
+		a.issuer.GetStatus().ACMEStatus().LastRegisteredEmail == a.issuer.GetSpec().ACME.Email &&
+		!eabKeyRotated &&
+		!rolloverPending {
 		log.V(logf.InfoLevel).Info("skipping re-verifying ACME account as cached registration " +
 			"details look sufficient")
 
+		// Issuers registered before the EAB key hash was recorded adopt the
+		// hash of the current key, rather than registering again.
+		if eabErr == nil && lastEABKeyHash == "" {
+			a.issuer.GetStatus().ACMEStatus().LastRegisteredEABKeyHash = eabKeyHash
+		}
+
+		if err := a.setupPreflight(ctx, httpClient, rsaPk); err != nil {
+			return preflightFailed(err)
+		}
+
 		// Updating issuer's Ready condition here will ensure that observed
 		// generation gets bumped correctly if this re-sync was triggered by a
 		// spec change. Last transition time on the condition will not be modified.
@@ -221,31 +277,57 @@ func (a *Acme) Setup(ctx context.Context) error {
 		a.issuer.GetStatus().ACMEStatus().URI = ""
 	}
 
-	var eabAccount *acmeapi.ExternalAccountBinding
-	if eabObj := a.issuer.GetSpec().ACME.ExternalAccountBinding; eabObj != nil {
-		eabKey, err := a.getEABKey(ctx, ns)
-		switch {
-		// Do not re-try if we fail to get the MAC key as it does not exist at the reference.
-		case apierrors.IsNotFound(err), errors.IsInvalidData(err):
-			log.Error(err, "failed to verify ACME account")
-			reason = errorAccountRegistrationFailed
-			msg = messageAccountRegistrationFailed + err.Error()
-			a.recorder.Event(a.issuer, corev1.EventTypeWarning,
-				errorAccountRegistrationFailed,
-				msg)
-			return nil
-
-		case err != nil:
-			reason = errorAccountRegistrationFailed
-			msg = messageAccountRegistrationFailed + err.Error()
-			return fmt.Errorf(msg)
+	// The account key can only be rolled over once the account is known. If
+	// the account has not been registered yet, the rollover is performed on
+	// the next sync after registration.
+	rolledOver := false
+	if accountURI := a.issuer.GetStatus().ACMEStatus().URI; rolloverPending && accountURI != "" {
+		log.V(logf.InfoLevel).Info("rolling over ACME account private key", "rollover", rolloverID)
+		newPk, err := a.rolloverAccountKey(ctx, httpClient, cl, accountURI, rsaPk, privateKeySelector, ns)
+		if err != nil {
+			reason = errorAccountKeyRolloverFailed
+			msg = messageAccountKeyRolloverFailed + err.Error()
+			log.Error(err, "failed to roll over ACME account private key")
+			a.recorder.Event(a.issuer, corev1.EventTypeWarning, errorAccountKeyRolloverFailed, msg)
+
+			// The account's existing key is still valid, so there is no
+			// point retrying if the ACME server rejected the request.
+			if acmeErr, ok := err.(*acmeapi.Error); ok && acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
+				return nil
+			}
+			return err
 		}
 
-		// set the external account binding
-		eabAccount = &acmeapi.ExternalAccountBinding{
-			KID: eabObj.KeyID,
-			Key: eabKey,
-		}
+		a.recorder.Event(a.issuer, corev1.EventTypeNormal, successAccountKeyRolledOver, messageAccountKeyRolledOver)
+		a.issuer.GetStatus().ACMEStatus().LastAccountKeyRollover = rolloverID
+		// The preflight account was registered using the previous key, so a
+		// new one is registered using the new key.
+		a.issuer.GetStatus().ACMEStatus().PreflightURI = ""
+		rolledOver = true
+		rsaPk = newPk
+		cl = a.clientBuilder(httpClient, *a.issuer.GetSpec().ACME, rsaPk)
+	}
+
+	switch {
+	// Do not re-try if we fail to get the MAC key as it does not exist at the reference.
+	case apierrors.IsNotFound(eabErr), errors.IsInvalidData(eabErr):
+		log.Error(eabErr, "failed to verify ACME account")
+		reason = errorAccountRegistrationFailed
+		msg = messageAccountRegistrationFailed + eabErr.Error()
+		a.recorder.Event(a.issuer, corev1.EventTypeWarning,
+			errorAccountRegistrationFailed,
+			msg)
+		return nil
+
+	case eabErr != nil:
+		reason = errorAccountRegistrationFailed
+		msg = messageAccountRegistrationFailed + eabErr.Error()
+		return fmt.Errorf(msg)
+	}
+
+	if eabKeyRotated {
+		log.V(logf.InfoLevel).Info("External Account Binding key has changed, registering ACME account again")
+		a.recorder.Event(a.issuer, corev1.EventTypeNormal, reasonEABKeyRotated, messageEABKeyRotated)
 	}
 
 	// register an ACME account or retrieve it if it already exists.
@@ -306,12 +388,25 @@ func (a *Acme) Setup(ctx context.Context) error {
 		return err
 	}
 
+	if err := a.setupPreflight(ctx, httpClient, rsaPk); err != nil {
+		return preflightFailed(err)
+	}
+
 	log.V(logf.InfoLevel).Info("verified existing registration with ACME server")
 	status = cmmeta.ConditionTrue
 	reason = successAccountRegistered
 	msg = messageAccountRegistered
+	if eabKeyRotated {
+		reason = successAccountReregistered
+		msg = messageAccountReregistered
+	}
+	if rolledOver {
+		reason = successAccountKeyRolledOver
+		msg = messageAccountKeyRolledOver
+	}
 	a.issuer.GetStatus().ACMEStatus().URI = account.URI
 	a.issuer.GetStatus().ACMEStatus().LastRegisteredEmail = registeredEmail
+	a.issuer.GetStatus().ACMEStatus().LastRegisteredEABKeyHash = eabKeyHash
 	// ensure the cached client in the account registry is up to date
 	a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, rsaPk)
 
@@ -381,6 +476,31 @@ func (a *Acme) registerAccount(ctx context.Context, cl client.Interface, eabAcco
 	return acc, nil
 }
 
+// externalAccountBinding returns the External Account Binding configured on
+// the issuer, along with a hash of its key ID and HMAC key that can be used to
+// detect when the key has been rotated. If no External Account Binding is
+// configured, both return values will be empty.
+func (a *Acme) externalAccountBinding(ctx context.Context, ns string) (*acmeapi.ExternalAccountBinding, string, error) {
+	eabObj := a.issuer.GetSpec().ACME.ExternalAccountBinding
+	if eabObj == nil {
+		return nil, "", nil
+	}
+
+	eabKey, err := a.getEABKey(ctx, ns)
+	if err != nil {
+		return nil, "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", eabObj.KeyID)
+	h.Write(eabKey)
+
+	return &acmeapi.ExternalAccountBinding{
+		KID: eabObj.KeyID,
+		Key: eabKey,
+	}, hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func (a *Acme) getEABKey(ctx context.Context, ns string) ([]byte, error) {
 	eab := a.issuer.GetSpec().ACME.ExternalAccountBinding.Key
 	sec, err := a.secretsClient.Secrets(ns).Get(ctx, eab.Name, metav1.GetOptions{})
@@ -423,6 +543,9 @@ func (a *Acme) createAccountPrivateKey(ctx context.Context, sel cmmeta.SecretKey
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      sel.Name,
 			Namespace: ns,
+			Labels: map[string]string{
+				v1.PartOfCertManagerControllerLabelKey: "true",
+			},
 		},
 		Data: map[string][]byte{
 			sel.Key: pki.EncodePKCS1PrivateKey(accountPrivKey),
@@ -20,6 +20,8 @@ import (
 	"context"
 	"crypto"
 	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -98,6 +100,9 @@ func TestAcme_Setup(t *testing.T) {
 		// This is the decoded EAB key that we send to the ACME server.
 		// TODO: could the newline cause any issues?
 		eabKey = "dGVzdAo=\n"
+		// eabKeyHash is the hash of the EAB key ID and key stored in the
+		// issuer's status after a successful registration.
+		eabKeyHash = mustHashEAB(someString, eabKey)
 	)
 
 	tests := map[string]struct {
@@ -134,6 +139,9 @@ func TestAcme_Setup(t *testing.T) {
 		expectedRegisteredAcc *acmeapi.Account
 		// expected issuer conditions after Setup has been called.
 		expectedConditions []cmapi.IssuerCondition
+		// expected EAB key hash in the issuer's status after Setup has been
+		// called.
+		expectedEABKeyHash string
 		expectedEvents     []string
 		wantsErr           bool
 	}{
@@ -360,6 +368,7 @@ func TestAcme_Setup(t *testing.T) {
 					gen.SetIssuerConditionReason(successAccountRegistered),
 					gen.SetIssuerConditionMessage(messageAccountRegistered)),
 			},
+			expectedEABKeyHash: eabKeyHash,
 		},
 		"ACME account with legacy EAB key algorithm set and with an email is registered successfully": {
 			issuer: gen.IssuerFrom(baseIssuer,
@@ -381,6 +390,96 @@ func TestAcme_Setup(t *testing.T) {
 					gen.SetIssuerConditionReason(successAccountRegistered),
 					gen.SetIssuerConditionMessage(messageAccountRegistered)),
 			},
+			expectedEABKeyHash: eabKeyHash,
+		},
+		"ACME Issuer is ready and EAB key has not changed": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEAccountURL(acmev2Prod),
+				gen.SetIssuerACMEEAB(someString, someString),
+				gen.SetIssuerACMELastRegisteredEABKeyHash(eabKeyHash),
+				gen.AddIssuerCondition(*gen.IssuerConditionFrom(readyTrueCondition))),
+			kfsKey:                     rsaPrivKey,
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			eabSecret:                  eabSecret,
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition)},
+			expectedEABKeyHash: eabKeyHash,
+		},
+		"ACME Issuer is ready and registered before EAB key hash was recorded, adopt current hash": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEAccountURL(acmev2Prod),
+				gen.SetIssuerACMEEAB(someString, someString),
+				gen.AddIssuerCondition(*gen.IssuerConditionFrom(readyTrueCondition))),
+			kfsKey:                     rsaPrivKey,
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			eabSecret:                  eabSecret,
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition)},
+			expectedEABKeyHash: eabKeyHash,
+		},
+		"ACME Issuer is ready and EAB secret has been removed, cached registration is used": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEAccountURL(acmev2Prod),
+				gen.SetIssuerACMEEAB(someString, someString),
+				gen.SetIssuerACMELastRegisteredEABKeyHash(eabKeyHash),
+				gen.AddIssuerCondition(*gen.IssuerConditionFrom(readyTrueCondition))),
+			kfsKey:                     rsaPrivKey,
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			eabSecretGetErr:            notFoundErr,
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition)},
+			expectedEABKeyHash: eabKeyHash,
+		},
+		"ACME Issuer is ready and EAB key has been rotated, account is registered again": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEAccountURL(acmev2Prod),
+				gen.SetIssuerACMEEAB(someString, someString),
+				gen.SetIssuerACMELastRegisteredEABKeyHash(mustHashEAB(someString, "old-key")),
+				gen.AddIssuerCondition(*gen.IssuerConditionFrom(readyTrueCondition))),
+			kfsKey:                     rsaPrivKey,
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			eabSecret:                  eabSecret,
+			expectedRegisteredAcc: &acmeapi.Account{ExternalAccountBinding: &acmeapi.ExternalAccountBinding{
+				KID: someString,
+				Key: []byte(eabKey),
+			}},
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition,
+					gen.SetIssuerConditionReason(successAccountReregistered),
+					gen.SetIssuerConditionMessage(messageAccountReregistered)),
+			},
+			expectedEvents: []string{
+				fmt.Sprintf("%s %s %s", corev1.EventTypeNormal, reasonEABKeyRotated, messageEABKeyRotated),
+			},
+			expectedEABKeyHash: eabKeyHash,
+		},
+		"ACME Issuer is ready and EAB key has been rotated, registration fails": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEAccountURL(acmev2Prod),
+				gen.SetIssuerACMEEAB(someString, someString),
+				gen.SetIssuerACMELastRegisteredEABKeyHash(mustHashEAB(someString, "old-key")),
+				gen.AddIssuerCondition(*gen.IssuerConditionFrom(readyTrueCondition))),
+			kfsKey:                     rsaPrivKey,
+			removeClientShouldBeCalled: true,
+			eabSecret:                  eabSecret,
+			registerErr:                acmeErr450,
+			expectedRegisteredAcc: &acmeapi.Account{ExternalAccountBinding: &acmeapi.ExternalAccountBinding{
+				KID: someString,
+				Key: []byte(eabKey),
+			}},
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyFalseCondition,
+					gen.SetIssuerConditionReason(errorAccountRegistrationFailed),
+					gen.SetIssuerConditionMessage(messageAccountRegistrationFailed+acmeErr450.Error())),
+			},
+			expectedEvents: []string{
+				fmt.Sprintf("%s %s %s", corev1.EventTypeNormal, reasonEABKeyRotated, messageEABKeyRotated),
+			},
+			expectedEABKeyHash: mustHashEAB(someString, "old-key"),
 		},
 	}
 	for name, test := range tests {
@@ -479,6 +578,16 @@ func TestAcme_Setup(t *testing.T) {
 					test.expectedConditions, gotConditions)
 			}
 
+			// Verify the EAB key hash recorded in the issuer's status.
+			var gotEABKeyHash string
+			if acmeStatus := a.issuer.GetStatus().ACME; acmeStatus != nil {
+				gotEABKeyHash = acmeStatus.LastRegisteredEABKeyHash
+			}
+			if gotEABKeyHash != test.expectedEABKeyHash {
+				t.Errorf("Expected EAB key hash in issuer's status: %q, got: %q",
+					test.expectedEABKeyHash, gotEABKeyHash)
+			}
+
 			// Verify that the expected events were recorded.
 			if !util.EqualSorted(test.expectedEvents, recorder.Events) {
 				t.Errorf("Expected events:\n%+#v\ngot:%+#v",
@@ -497,6 +606,12 @@ func keyFromSecretMockBuilder(wasCalled *bool, key crypto.Signer, err error) key
 	}
 }
 
+func mustHashEAB(kid, key string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s", kid, key)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func clientBuilderMock(cl acmecl.Interface) accounts.NewClientFunc {
 	return func(*http.Client, cmacme.ACMEIssuer, *rsa.PrivateKey) acmecl.Interface {
 		return cl
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsalpn
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+func TestBuildService(t *testing.T) {
+	ch := &cmacme.Challenge{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: cmacme.ChallengeSpec{
+			DNSName: "example.com",
+			Token:   "token",
+			Solver: cmacme.ACMEChallengeSolver{
+				TLSALPN01: &cmacme.ACMEChallengeSolverTLSALPN01{
+					ServiceType: corev1.ServiceTypeLoadBalancer,
+					ServiceLabels: map[string]string{
+						"passthrough":        "true",
+						cmacme.TokenLabelKey: "overridden",
+					},
+				},
+			},
+		},
+	}
+
+	svc, err := buildService(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		t.Errorf("unexpected service type %q", svc.Spec.Type)
+	}
+	if svc.Labels["passthrough"] != "true" {
+		t.Errorf("expected service labels to be applied, got %v", svc.Labels)
+	}
+	if svc.Labels[cmacme.TokenLabelKey] != podLabels(ch)[cmacme.TokenLabelKey] {
+		t.Errorf("expected solver labels not to be overridden, got %v", svc.Labels)
+	}
+	if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != 443 || svc.Spec.Ports[0].TargetPort.IntValue() != acmeSolverListenPort {
+		t.Errorf("unexpected service ports %v", svc.Spec.Ports)
+	}
+
+	ch.Spec.Solver.TLSALPN01 = nil
+	if _, err := buildService(ch); err == nil {
+		t.Errorf("expected error building service without TLSALPN01 config")
+	}
+}
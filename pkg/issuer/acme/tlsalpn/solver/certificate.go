@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package solver
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ChallengeCertificate builds the self-signed certificate that must be
+// presented when responding to a tls-alpn-01 challenge for the given domain
+// using the given key authorization.
+func ChallengeCertificate(domain, keyAuthorization string) (*tls.Certificate, error) {
+	ext, err := acmeIdentifierExtension(keyAuthorization)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: "cert-manager tls-alpn-01 challenge solver"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{domain},
+		ExtraExtensions:       []pkix.Extension{ext},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pk.Public(), pk)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  pk,
+		Leaf:        leaf,
+	}, nil
+}
+
+// VerifyChallengeCertificate checks that the given certificate is a valid
+// response to a tls-alpn-01 challenge for the given domain and key
+// authorization.
+func VerifyChallengeCertificate(cert *x509.Certificate, domain, keyAuthorization string) error {
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != domain {
+		return fmt.Errorf("certificate DNS names %v do not match expected domain %q", cert.DNSNames, domain)
+	}
+
+	expected, err := acmeIdentifierExtension(keyAuthorization)
+	if err != nil {
+		return err
+	}
+
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(IDPeACMEIdentifier) {
+			continue
+		}
+		if !ext.Critical {
+			return fmt.Errorf("acmeIdentifier extension is not marked critical")
+		}
+		if !bytes.Equal(ext.Value, expected.Value) {
+			return fmt.Errorf("acmeIdentifier extension does not match the expected key authorization")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("certificate does not contain an acmeIdentifier extension")
+}
+
+// acmeIdentifierExtension returns the critical acmeIdentifier extension
+// containing the SHA-256 digest of the key authorization.
+func acmeIdentifierExtension(keyAuthorization string) (pkix.Extension, error) {
+	digest := sha256.Sum256([]byte(keyAuthorization))
+	value, err := asn1.Marshal(digest[:])
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{
+		Id:       IDPeACMEIdentifier,
+		Critical: true,
+		Value:    value,
+	}, nil
+}
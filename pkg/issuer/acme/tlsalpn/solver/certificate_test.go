@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package solver
+
+import (
+	"testing"
+)
+
+func TestChallengeCertificate(t *testing.T) {
+	cert, err := ChallengeCertificate("example.com", "token.thumbprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		domain, keyAuthorization string
+		expectErr                bool
+	}{
+		"matching domain and key authorization": {
+			domain:           "example.com",
+			keyAuthorization: "token.thumbprint",
+		},
+		"different domain": {
+			domain:           "example.org",
+			keyAuthorization: "token.thumbprint",
+			expectErr:        true,
+		},
+		"different key authorization": {
+			domain:           "example.com",
+			keyAuthorization: "token.other",
+			expectErr:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyChallengeCertificate(cert.Leaf, test.domain, test.keyAuthorization)
+			if test.expectErr != (err != nil) {
+				t.Errorf("unexpected error, expectErr=%t got=%v", test.expectErr, err)
+			}
+		})
+	}
+}
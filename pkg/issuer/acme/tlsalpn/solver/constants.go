@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package solver
+
+import "encoding/asn1"
+
+const (
+	// ACMETLS1Protocol is the ALPN protocol name used for tls-alpn-01
+	// challenge requests, as defined in RFC 8737 section 6.2.
+	ACMETLS1Protocol = "acme-tls/1"
+)
+
+// IDPeACMEIdentifier is the object identifier of the acmeIdentifier
+// certificate extension, as defined in RFC 8737 section 6.1.
+var IDPeACMEIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package solver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// handshakeTimeout is the maximum amount of time a client has to complete
+// the TLS handshake before its connection is closed.
+const handshakeTimeout = 10 * time.Second
+
+type TLSALPN01Solver struct {
+	ListenPort int
+
+	Domain string
+	Key    string
+
+	lock     sync.Mutex
+	listener net.Listener
+	closed   bool
+}
+
+func (s *TLSALPN01Solver) Listen(log logr.Logger) error {
+	log.Info("starting listener",
+		"expected_domain", s.Domain,
+		"expected_key", s.Key,
+		"listen_port", s.ListenPort,
+	)
+
+	cert, err := ChallengeCertificate(s.Domain, s.Key)
+	if err != nil {
+		return fmt.Errorf("failed to generate challenge certificate: %v", err)
+	}
+
+	config := &tls.Config{
+		NextProtos: []string{ACMETLS1Protocol},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			log := log.WithValues("server_name", hello.ServerName, "protocols", hello.SupportedProtos)
+			if !offersACMETLS1(hello.SupportedProtos) {
+				log.Info("client did not offer the acme-tls/1 protocol")
+				return nil, fmt.Errorf("client did not offer the %q protocol", ACMETLS1Protocol)
+			}
+			if hello.ServerName != s.Domain {
+				log.Info("invalid server name", "expected_server_name", s.Domain)
+				return nil, fmt.Errorf("unexpected server name %q", hello.ServerName)
+			}
+			log.Info("got successful challenge request, presenting certificate")
+			return cert, nil
+		},
+	}
+
+	listener, err := tls.Listen("tcp", fmt.Sprintf(":%d", s.ListenPort), config)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.lock.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.lock.Lock()
+			closed := s.closed
+			s.lock.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.handle(log, conn)
+	}
+}
+
+// handle completes the TLS handshake with a client and then closes the
+// connection. Once the acme-tls/1 protocol has been negotiated, no
+// application data is exchanged (RFC 8737 section 3).
+func (s *TLSALPN01Solver) handle(log logr.Logger, conn net.Conn) {
+	defer conn.Close()
+	log = log.WithValues("remote_addr", conn.RemoteAddr().String())
+
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		log.Error(err, "failed to set connection deadline")
+		return
+	}
+	if err := conn.(*tls.Conn).Handshake(); err != nil {
+		log.Info("TLS handshake failed", "error", err.Error())
+		return
+	}
+}
+
+// Shutdown stops the listener. Connections that are mid-handshake are left
+// to complete or time out.
+func (s *TLSALPN01Solver) Shutdown(_ context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+func offersACMETLS1(protos []string) bool {
+	for _, p := range protos {
+		if p == ACMETLS1Protocol {
+			return true
+		}
+	}
+	return false
+}
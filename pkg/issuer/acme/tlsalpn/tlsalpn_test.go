@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsalpn
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/tlsalpn/solver"
+)
+
+func TestVerifyChallengeServer(t *testing.T) {
+	cert, err := solver.ChallengeCertificate("example.com", "token.thumbprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		protos    []string
+		domain    string
+		key       string
+		expectErr bool
+	}{
+		"valid challenge response": {
+			protos: []string{solver.ACMETLS1Protocol},
+			domain: "example.com",
+			key:    "token.thumbprint",
+		},
+		"wrong key authorization": {
+			protos:    []string{solver.ACMETLS1Protocol},
+			domain:    "example.com",
+			key:       "token.other",
+			expectErr: true,
+		},
+		"server does not negotiate acme-tls/1": {
+			protos:    []string{"h2"},
+			domain:    "example.com",
+			key:       "token.thumbprint",
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   test.protos,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					conn.(*tls.Conn).Handshake()
+					conn.Close()
+				}
+			}()
+
+			err = verifyChallengeServer(context.Background(), listener.Addr().String(), test.domain, test.key)
+			if test.expectErr != (err != nil) {
+				t.Errorf("unexpected error, expectErr=%t got=%v", test.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	revocationapi "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+)
+
+var _ issuer.Revoker = &CA{}
+
+// Revoke records the revocation of the given certificate as a
+// CertificateRevocation, which is published in the CRL and by the OCSP
+// responder of the issuer. For ClusterIssuers, the CertificateRevocation is
+// created in the cluster resource namespace.
+func (c *CA) Revoke(ctx context.Context, crt *cmapi.Certificate, cert *x509.Certificate) error {
+	serialNumber := fmt.Sprintf("%X", cert.SerialNumber)
+
+	// The name is derived from the revoked certificate so that retried
+	// revocations do not create duplicate CertificateRevocations.
+	name, err := apiutil.ComputeName(crt.Name, []string{crt.Namespace, serialNumber})
+	if err != nil {
+		return err
+	}
+
+	now := metav1.NewTime(c.Clock.Now())
+	rev := &revocationapi.CertificateRevocation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.resourceNamespace,
+		},
+		Spec: revocationapi.CertificateRevocationSpec{
+			IssuerRef:      crt.Spec.IssuerRef,
+			SerialNumber:   serialNumber,
+			Reason:         revocationapi.RevocationReasonUnspecified,
+			RevocationTime: &now,
+		},
+	}
+
+	_, err = c.CMClient.RevocationV1alpha1().CertificateRevocations(c.resourceNamespace).Create(ctx, rev, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+
+	return err
+}
@@ -18,23 +18,29 @@ package ca
 
 import (
 	"context"
+	"crypto/x509"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/kube"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 const (
 	errorGetKeyPair     = "ErrGetKeyPair"
 	errorInvalidKeyPair = "ErrInvalidKeyPair"
+	errorGetNextCA      = "ErrGetNextCA"
 
 	successKeyPairVerified = "KeyPairVerified"
 
 	messageErrorGetKeyPair = "Error getting keypair for CA issuer: "
+	messageErrorGetNextCA  = "Error getting next CA certificate for CA issuer: "
 
 	messageKeyPairVerified = "Signing CA verified"
 )
@@ -71,9 +77,67 @@ func (c *CA) Setup(ctx context.Context) error {
 		return nil
 	}
 
+	caStatus, err := c.caStatus(ctx)
+	if err != nil {
+		// The issuer can still sign certificates, they just won't include
+		// the next CA in their ca.crt until the next CA is fixed.
+		log.Error(err, "error getting next CA certificate")
+		c.Recorder.Event(c.issuer, corev1.EventTypeWarning, errorGetNextCA, messageErrorGetNextCA+err.Error())
+	}
+	c.issuer.GetStatus().CA = caStatus
+
 	log.V(logf.DebugLevel).Info("signing CA verified")
 	c.Recorder.Event(c.issuer, corev1.EventTypeNormal, successKeyPairVerified, messageKeyPairVerified)
 	apiutil.SetIssuerCondition(c.issuer, c.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionTrue, successKeyPairVerified, messageKeyPairVerified)
 
 	return nil
 }
+
+// caStatus returns the trust anchors of the issuer if it names the Secret of
+// the next CA of a planned rollover, or nil otherwise.
+func (c *CA) caStatus(ctx context.Context) (*v1.CAIssuerStatus, error) {
+	nextSecretName := c.issuer.GetSpec().CA.NextSecretName
+	if len(nextSecretName) == 0 {
+		return nil, nil
+	}
+
+	certs, _, err := kube.SecretTLSKeyPairAndCA(ctx, c.secretsLister, c.resourceNamespace, c.issuer.GetSpec().CA.SecretName)
+	if err != nil {
+		return nil, err
+	}
+	if chain, err := pki.BuildCertificateChain(certs); err == nil {
+		certs = chain
+	}
+	current, err := trustAnchor(certs[len(certs)-1], false)
+	if err != nil {
+		return nil, err
+	}
+
+	nextCerts, err := kube.SecretTLSCertChain(ctx, c.secretsLister, c.resourceNamespace, nextSecretName)
+	if err != nil {
+		return nil, err
+	}
+	if !nextCerts[0].IsCA {
+		return nil, fmt.Errorf("certificate in secret %s/%s is not a CA", c.resourceNamespace, nextSecretName)
+	}
+	next, err := trustAnchor(nextCerts[0], true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{current, next}}, nil
+}
+
+func trustAnchor(cert *x509.Certificate, next bool) (v1.CATrustAnchor, error) {
+	certPEM, err := pki.EncodeX509(cert)
+	if err != nil {
+		return v1.CATrustAnchor{}, err
+	}
+
+	return v1.CATrustAnchor{
+		Certificate: certPEM,
+		Next:        next,
+		NotBefore:   metav1.NewTime(cert.NotBefore),
+		NotAfter:    metav1.NewTime(cert.NotAfter),
+	}, nil
+}
@@ -18,17 +18,20 @@ package fake
 
 import (
 	"context"
+	"crypto/x509"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 )
 
 type Issuer struct {
-	SetupFunc func(context.Context) error
-	IssueFunc func(context.Context, *cmapi.Certificate) (*issuer.IssueResponse, error)
+	SetupFunc  func(context.Context) error
+	IssueFunc  func(context.Context, *cmapi.Certificate) (*issuer.IssueResponse, error)
+	RevokeFunc func(context.Context, *cmapi.Certificate, *x509.Certificate) error
 }
 
 var _ issuer.Interface = &Issuer{}
+var _ issuer.Revoker = &Issuer{}
 
 // Setup initialises the issuer. This may include registering accounts with
 // a service, creating a CA and storing it somewhere, or verifying
@@ -42,3 +45,12 @@ func (i *Issuer) Setup(ctx context.Context) error {
 func (i *Issuer) Issue(ctx context.Context, crt *cmapi.Certificate) (*issuer.IssueResponse, error) {
 	return i.IssueFunc(ctx, crt)
 }
+
+// Revoke revokes the given certificate. If RevokeFunc is not set, the issuer
+// does not support revocation.
+func (i *Issuer) Revoke(ctx context.Context, crt *cmapi.Certificate, cert *x509.Certificate) error {
+	if i.RevokeFunc == nil {
+		return issuer.ErrRevocationNotSupported
+	}
+	return i.RevokeFunc(ctx, crt, cert)
+}
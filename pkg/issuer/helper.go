@@ -17,13 +17,21 @@ limitations under the License.
 package issuer
 
 import (
+	"errors"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/labels"
+
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	policylisters "github.com/jetstack/cert-manager/pkg/client/listers/policy/v1alpha1"
 )
 
+// ErrIssuerNotGranted is returned when an Issuer in another namespace is
+// referenced, but no IssuerGrant grants it to the referencing namespace.
+var ErrIssuerNotGranted = errors.New("issuer has not been granted to the namespace")
+
 // Helper is an interface that defines a method that returns an issuer for the given
 // IssuerRef and namespace.
 type Helper interface {
@@ -36,16 +44,20 @@ type Helper interface {
 type helperImpl struct {
 	issuerLister        cmlisters.IssuerLister
 	clusterIssuerLister cmlisters.ClusterIssuerLister
+	issuerGrantLister   policylisters.IssuerGrantLister
 }
 
 var _ Helper = &helperImpl{}
 
 // NewHelper will construct a new instance of a Helper using values supplied on
 // the provided controller context.
-func NewHelper(issuerLister cmlisters.IssuerLister, clusterIssuerLister cmlisters.ClusterIssuerLister) Helper {
+// If issuerGrantLister is nil, references to Issuers in other namespaces are
+// never resolved.
+func NewHelper(issuerLister cmlisters.IssuerLister, clusterIssuerLister cmlisters.ClusterIssuerLister, issuerGrantLister policylisters.IssuerGrantLister) Helper {
 	return &helperImpl{
 		issuerLister:        issuerLister,
 		clusterIssuerLister: clusterIssuerLister,
+		issuerGrantLister:   issuerGrantLister,
 	}
 }
 
@@ -54,11 +66,22 @@ func NewHelper(issuerLister cmlisters.IssuerLister, clusterIssuerLister cmlister
 // This namespace will be used to read the Issuer resource.
 // In most cases, the ns parameter should be set to the namespace of the resource
 // that defines the IssuerRef (i.e. the namespace of the Certificate resource).
+// If the IssuerRef references an Issuer in another namespace, the Issuer is
+// only returned if an IssuerGrant in that namespace grants it to ns.
 func (h *helperImpl) GetGenericIssuer(ref cmmeta.ObjectReference, ns string) (cmapi.GenericIssuer, error) {
 	switch ref.Kind {
 	case "", cmapi.IssuerKind:
-		return h.issuerLister.Issuers(ns).Get(ref.Name)
+		if ref.Namespace == "" || ref.Namespace == ns {
+			return h.issuerLister.Issuers(ns).Get(ref.Name)
+		}
+		if err := h.checkIssuerGranted(ref, ns); err != nil {
+			return nil, err
+		}
+		return h.issuerLister.Issuers(ref.Namespace).Get(ref.Name)
 	case cmapi.ClusterIssuerKind:
+		if ref.Namespace != "" {
+			return nil, fmt.Errorf("issuerRef.namespace must not be set when referencing a ClusterIssuer")
+		}
 		// handle edge case where the ClusterIssuerLister is not set.
 		// this isn't actually a supported operating mode right now, nor is it
 		// exposed to users.
@@ -72,3 +95,28 @@ func (h *helperImpl) GetGenericIssuer(ref cmmeta.ObjectReference, ns string) (cm
 		return nil, fmt.Errorf(`invalid value %q for issuerRef.kind. Must be empty, %q or %q`, ref.Kind, cmapi.IssuerKind, cmapi.ClusterIssuerKind)
 	}
 }
+
+// checkIssuerGranted returns an error wrapping ErrIssuerNotGranted unless an
+// IssuerGrant in the namespace of the referenced Issuer grants it to ns.
+func (h *helperImpl) checkIssuerGranted(ref cmmeta.ObjectReference, ns string) error {
+	notGranted := fmt.Errorf("%w: Issuer %s/%s is not granted to namespace %q by an IssuerGrant", ErrIssuerNotGranted, ref.Namespace, ref.Name, ns)
+	if h.issuerGrantLister == nil {
+		return notGranted
+	}
+
+	grants, err := h.issuerGrantLister.IssuerGrants(ref.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		if grant.Spec.IssuerName != ref.Name {
+			continue
+		}
+		for _, granted := range grant.Spec.Namespaces {
+			if granted == ns {
+				return nil
+			}
+		}
+	}
+	return notGranted
+}
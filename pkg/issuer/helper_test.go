@@ -17,13 +17,16 @@ limitations under the License.
 package issuer
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	policyapi "github.com/jetstack/cert-manager/pkg/apis/policy/v1alpha1"
 	"github.com/jetstack/cert-manager/pkg/controller/test"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
@@ -31,13 +34,21 @@ import (
 func TestGetGenericIssuer(t *testing.T) {
 	var nilIssuer *v1.Issuer
 	var nilClusterIssuer *v1.ClusterIssuer
+	issuerGrant := func(issuerName string, namespaces ...string) *policyapi.IssuerGrant {
+		return &policyapi.IssuerGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "issuer-ns", Name: "grant-" + issuerName},
+			Spec:       policyapi.IssuerGrantSpec{IssuerName: issuerName, Namespaces: namespaces},
+		}
+	}
 	type testT struct {
 		Name                   string
 		Kind                   string
 		Namespace              string
+		RefNamespace           string
 		CMObjects              []runtime.Object
 		NilClusterIssuerLister bool
 		Err                    bool
+		NotGranted             bool
 		Expected               v1.GenericIssuer
 	}
 	tests := []testT{
@@ -77,6 +88,56 @@ func TestGetGenericIssuer(t *testing.T) {
 			NilClusterIssuerLister: true,
 			Err:                    true,
 		},
+		{
+			Name:         "granted-issuer",
+			Kind:         "Issuer",
+			Namespace:    gen.DefaultTestNamespace,
+			RefNamespace: "issuer-ns",
+			CMObjects: []runtime.Object{
+				gen.Issuer("granted-issuer", gen.SetIssuerNamespace("issuer-ns")),
+				issuerGrant("granted-issuer", "other-ns", gen.DefaultTestNamespace),
+			},
+			Expected: gen.Issuer("granted-issuer", gen.SetIssuerNamespace("issuer-ns")),
+		},
+		{
+			Name:         "issuer-granted-to-other-namespace",
+			Kind:         "Issuer",
+			Namespace:    gen.DefaultTestNamespace,
+			RefNamespace: "issuer-ns",
+			CMObjects: []runtime.Object{
+				gen.Issuer("issuer-granted-to-other-namespace", gen.SetIssuerNamespace("issuer-ns")),
+				issuerGrant("issuer-granted-to-other-namespace", "other-ns"),
+			},
+			Err:        true,
+			NotGranted: true,
+		},
+		{
+			Name:         "other-issuer-granted",
+			Kind:         "Issuer",
+			Namespace:    gen.DefaultTestNamespace,
+			RefNamespace: "issuer-ns",
+			CMObjects: []runtime.Object{
+				gen.Issuer("other-issuer-granted", gen.SetIssuerNamespace("issuer-ns")),
+				issuerGrant("another-issuer", gen.DefaultTestNamespace),
+			},
+			Err:        true,
+			NotGranted: true,
+		},
+		{
+			Name:         "issuer-in-same-namespace",
+			Kind:         "Issuer",
+			Namespace:    gen.DefaultTestNamespace,
+			RefNamespace: gen.DefaultTestNamespace,
+			CMObjects:    []runtime.Object{gen.Issuer("issuer-in-same-namespace")},
+			Expected:     gen.Issuer("issuer-in-same-namespace"),
+		},
+		{
+			Name:         "namespaced-clusterissuer",
+			Kind:         "ClusterIssuer",
+			RefNamespace: "issuer-ns",
+			CMObjects:    []runtime.Object{gen.ClusterIssuer("namespaced-clusterissuer")},
+			Err:          true,
+		},
 	}
 
 	for _, row := range tests {
@@ -88,6 +149,7 @@ func TestGetGenericIssuer(t *testing.T) {
 			c := &helperImpl{
 				issuerLister:        b.FakeCMInformerFactory().Certmanager().V1().Issuers().Lister(),
 				clusterIssuerLister: b.FakeCMInformerFactory().Certmanager().V1().ClusterIssuers().Lister(),
+				issuerGrantLister:   b.FakeCMInformerFactory().Policy().V1alpha1().IssuerGrants().Lister(),
 			}
 			b.Start()
 			defer b.Stop()
@@ -99,10 +161,16 @@ func TestGetGenericIssuer(t *testing.T) {
 			stopCh := make(chan struct{})
 			defer close(stopCh)
 
-			actual, err := c.GetGenericIssuer(cmmeta.ObjectReference{Name: row.Name, Kind: row.Kind}, row.Namespace)
+			actual, err := c.GetGenericIssuer(cmmeta.ObjectReference{Name: row.Name, Kind: row.Kind, Namespace: row.RefNamespace}, row.Namespace)
 			if err != nil && !row.Err {
 				t.Errorf("Expected no error, but got: %s", err)
 			}
+			if err == nil && row.Err {
+				t.Errorf("Expected an error, but got none")
+			}
+			if errors.Is(err, ErrIssuerNotGranted) != row.NotGranted {
+				t.Errorf("Expected issuer not granted error: %t, but got: %v", row.NotGranted, err)
+			}
 			if !reflect.DeepEqual(actual, row.Expected) {
 				t.Errorf("Expected %#v but got %#v", row.Expected, actual)
 			}
@@ -18,6 +18,10 @@ package issuer
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 )
 
 type Interface interface {
@@ -27,6 +31,20 @@ type Interface interface {
 	Setup(ctx context.Context) error
 }
 
+// ErrRevocationNotSupported is returned by a Revoker if the issuer is not able
+// to revoke certificates in its current configuration.
+var ErrRevocationNotSupported = errors.New("revocation is not supported by the issuer")
+
+// Revoker is implemented by issuers that are able to revoke the certificates
+// that they have issued.
+type Revoker interface {
+	// Revoke revokes the given certificate, which was issued for the given
+	// Certificate resource. It is called again if the certificate may not
+	// have been revoked, so revoking a certificate that has already been
+	// revoked must not return an error.
+	Revoke(ctx context.Context, crt *cmapi.Certificate, cert *x509.Certificate) error
+}
+
 type IssueResponse struct {
 	// Certificate is the certificate resource that should be stored in the
 	// target secret.
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/hashicorp/vault/sdk/helper/certutil"
+
+	vaultinternal "github.com/jetstack/cert-manager/internal/vault"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+)
+
+var _ issuer.Revoker = &Vault{}
+
+// Revoke revokes the given certificate using the revoke endpoint of the PKI
+// secrets engine that the issuer signs certificates with.
+func (v *Vault) Revoke(ctx context.Context, _ *cmapi.Certificate, cert *x509.Certificate) error {
+	client, err := v.clientBuilder(ctx, v.resourceNamespace, func(ns string) vaultinternal.CreateToken {
+		return v.Client.CoreV1().ServiceAccounts(ns).CreateToken
+	}, v.secretsLister, v.issuer)
+	if err != nil {
+		return err
+	}
+
+	return client.Revoke(ctx, certutil.GetHexFormatted(cert.SerialNumber.Bytes(), ":"))
+}
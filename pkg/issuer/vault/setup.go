@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	vaultinternal "github.com/jetstack/cert-manager/internal/vault"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -40,9 +43,15 @@ const (
 	messageAuthFieldsRequired            = "Vault tokenSecretRef, appRole, or kubernetes is required"
 	messageMultipleAuthFieldsSet         = "Multiple auth methods cannot be set on the same Vault issuer"
 
-	messageKubeAuthFieldsRequired    = "Vault Kubernetes auth requires both role and secretRef.name"
+	messageKubeAuthFieldsRequired    = "Vault Kubernetes auth requires both role and either secretRef.name or serviceAccountRef.name"
+	messageKubeAuthSingleTokenSource = "Vault Kubernetes auth cannot use both secretRef and serviceAccountRef"
 	messageTokenAuthNameRequired     = "Vault Token auth requires tokenSecretRef.name"
 	messageAppRoleAuthFieldsRequired = "Vault AppRole auth requires both roleId and tokenSecretRef.name"
+
+	reasonSecretIDRotated         = "SecretIDRotated"
+	messageSecretIDRotated        = "Rotated the Vault AppRole secret ID stored in Secret %q"
+	reasonSecretIDRotationFailed  = "SecretIDRotationFailed"
+	messageSecretIDRotationFailed = "Failed to rotate the Vault AppRole secret ID: %v"
 )
 
 // Setup creates a new Vault client and attempts to authenticate with the Vault instance and sets the issuer's conditions to reflect the success of the setup.
@@ -96,13 +105,26 @@ func (v *Vault) Setup(ctx context.Context) error {
 	}
 
 	// check if all mandatory Vault Kubernetes fields are set.
-	if kubeAuth != nil && (len(kubeAuth.SecretRef.Name) == 0 || len(kubeAuth.Role) == 0) {
-		logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, messageKubeAuthFieldsRequired)
-		apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, messageKubeAuthFieldsRequired)
-		return nil
+	if kubeAuth != nil {
+		hasSecretRef := len(kubeAuth.SecretRef.Name) > 0
+		hasServiceAccountRef := kubeAuth.ServiceAccountRef != nil && len(kubeAuth.ServiceAccountRef.Name) > 0
+
+		if len(kubeAuth.Role) == 0 || (!hasSecretRef && !hasServiceAccountRef) {
+			logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, messageKubeAuthFieldsRequired)
+			apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, messageKubeAuthFieldsRequired)
+			return nil
+		}
+
+		if hasSecretRef && kubeAuth.ServiceAccountRef != nil {
+			logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, messageKubeAuthSingleTokenSource)
+			apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, messageKubeAuthSingleTokenSource)
+			return nil
+		}
 	}
 
-	client, err := vaultinternal.New(v.resourceNamespace, v.secretsLister, v.issuer)
+	client, err := vaultinternal.New(ctx, v.resourceNamespace, func(ns string) vaultinternal.CreateToken {
+		return v.Client.CoreV1().ServiceAccounts(ns).CreateToken
+	}, v.secretsLister, v.issuer)
 	if err != nil {
 		s := messageVaultClientInitFailed + err.Error()
 		logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, s)
@@ -110,7 +132,7 @@ func (v *Vault) Setup(ctx context.Context) error {
 		return err
 	}
 
-	if err := client.IsVaultInitializedAndUnsealed(); err != nil {
+	if err := client.IsVaultInitializedAndUnsealed(ctx); err != nil {
 		logf.V(logf.WarnLevel).Infof("%s: %s: error: %s", v.issuer.GetObjectMeta().Name, messageVaultStatusVerificationFailed, err.Error())
 		apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, messageVaultStatusVerificationFailed)
 		return fmt.Errorf(messageVaultStatusVerificationFailed)
@@ -118,5 +140,40 @@ func (v *Vault) Setup(ctx context.Context) error {
 
 	logf.Log.V(logf.DebugLevel).Info(messageVaultVerified)
 	apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionTrue, successVaultVerified, messageVaultVerified)
+
+	v.rotateAppRoleSecretID(ctx, client)
+
 	return nil
 }
+
+// rotateAppRoleSecretID replaces the App Role secret ID stored in the
+// referenced Secret if it is about to expire, and destroys the secret ID it
+// replaces. Failures are recorded as events on the issuer but do not affect
+// its readiness, since the current secret ID remains valid until it expires.
+func (v *Vault) rotateAppRoleSecretID(ctx context.Context, client vaultinternal.Interface) {
+	appRole := v.issuer.GetSpec().Vault.Auth.AppRole
+	if appRole == nil || appRole.SecretIDRotation == nil {
+		return
+	}
+
+	rotated, err := client.RotateAppRoleSecretID(ctx, v.Clock.Now(), func(secretID string) error {
+		secret, err := v.secretsLister.Secrets(v.resourceNamespace).Get(appRole.SecretRef.Name)
+		if err != nil {
+			return err
+		}
+
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[appRole.SecretRef.Key] = []byte(secretID)
+		_, err = v.Client.CoreV1().Secrets(v.resourceNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if rotated {
+		v.Recorder.Eventf(v.issuer, corev1.EventTypeNormal, reasonSecretIDRotated, messageSecretIDRotated, appRole.SecretRef.Name)
+	}
+	if err != nil {
+		v.Recorder.Eventf(v.issuer, corev1.EventTypeWarning, reasonSecretIDRotationFailed, messageSecretIDRotationFailed, err)
+	}
+}
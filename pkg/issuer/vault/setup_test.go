@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	vaultfake "github.com/jetstack/cert-manager/internal/vault/fake"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	controllertest "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+	testlisters "github.com/jetstack/cert-manager/test/unit/listers"
+)
+
+func TestRotateAppRoleSecretID(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "approle", Namespace: "test-namespace"},
+		Data:       map[string][]byte{"secret-id": []byte("current-secret-id")},
+	}
+	issuer := gen.Issuer("vault-issuer",
+		gen.SetIssuerVault(cmapi.VaultIssuer{
+			Auth: cmapi.VaultAuth{
+				AppRole: &cmapi.VaultAppRole{
+					RoleId: "role-id",
+					SecretRef: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "approle"},
+						Key:                  "secret-id",
+					},
+					SecretIDRotation: &cmapi.VaultAppRoleSecretIDRotation{RoleName: "my-role"},
+				},
+			},
+		}),
+	)
+
+	tests := map[string]struct {
+		rotate      func(context.Context, time.Time, func(string) error) (bool, error)
+		expSecretID string
+		expEvents   []string
+	}{
+		"should not update the Secret if no rotation is due": {
+			rotate:      func(context.Context, time.Time, func(string) error) (bool, error) { return false, nil },
+			expSecretID: "current-secret-id",
+		},
+		"should store the new secret ID and record an event": {
+			rotate: func(_ context.Context, t time.Time, store func(string) error) (bool, error) {
+				if !t.Equal(now) {
+					return false, errors.New("unexpected time")
+				}
+				return true, store("new-secret-id")
+			},
+			expSecretID: "new-secret-id",
+			expEvents:   []string{`Normal SecretIDRotated Rotated the Vault AppRole secret ID stored in Secret "approle"`},
+		},
+		"should record both events if the previous secret ID cannot be destroyed": {
+			rotate: func(_ context.Context, _ time.Time, store func(string) error) (bool, error) {
+				if err := store("new-secret-id"); err != nil {
+					return false, err
+				}
+				return true, errors.New("failed to destroy previous App Role secret ID: permission denied")
+			},
+			expSecretID: "new-secret-id",
+			expEvents: []string{
+				`Normal SecretIDRotated Rotated the Vault AppRole secret ID stored in Secret "approle"`,
+				"Warning SecretIDRotationFailed Failed to rotate the Vault AppRole secret ID: failed to destroy previous App Role secret ID: permission denied",
+			},
+		},
+		"should record an event if the rotation fails": {
+			rotate: func(context.Context, time.Time, func(string) error) (bool, error) {
+				return false, errors.New("permission denied")
+			},
+			expSecretID: "current-secret-id",
+			expEvents:   []string{"Warning SecretIDRotationFailed Failed to rotate the Vault AppRole secret ID: permission denied"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := &controllertest.FakeRecorder{}
+			kubeClient := kubefake.NewSimpleClientset(secret.DeepCopy())
+			v := &Vault{
+				Context: &controller.Context{
+					Client:   kubeClient,
+					Recorder: rec,
+					Clock:    fakeclock.NewFakeClock(now),
+				},
+				issuer: issuer,
+				secretsLister: testlisters.FakeSecretListerFrom(testlisters.NewFakeSecretLister(),
+					testlisters.SetFakeSecretNamespaceListerGet(secret, nil),
+				),
+				resourceNamespace: "test-namespace",
+			}
+
+			client := vaultfake.New()
+			client.RotateAppRoleSecretIDFn = test.rotate
+			v.rotateAppRoleSecretID(context.TODO(), client)
+
+			got, err := kubeClient.CoreV1().Secrets("test-namespace").Get(context.TODO(), "approle", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Data["secret-id"]) != test.expSecretID {
+				t.Errorf("unexpected secret ID in Secret, exp=%q got=%q", test.expSecretID, got.Data["secret-id"])
+			}
+			if !reflect.DeepEqual(test.expEvents, rec.Events) {
+				t.Errorf("unexpected events, exp=%q got=%q", test.expEvents, rec.Events)
+			}
+		})
+	}
+}
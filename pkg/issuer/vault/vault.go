@@ -19,6 +19,7 @@ package vault
 import (
 	corelisters "k8s.io/client-go/listers/core/v1"
 
+	vaultinternal "github.com/jetstack/cert-manager/internal/vault"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/controller"
@@ -35,6 +36,10 @@ type Vault struct {
 	// For Issuers, this will be the namespace of the Issuer.
 	// For ClusterIssuers, this will be the cluster resource namespace.
 	resourceNamespace string
+
+	// clientBuilder builds a new Vault client.
+	// It can be stubbed in unit tests.
+	clientBuilder vaultinternal.ClientBuilder
 }
 
 func NewVault(ctx *controller.Context, issuer v1.GenericIssuer) (issuer.Interface, error) {
@@ -45,6 +50,7 @@ func NewVault(ctx *controller.Context, issuer v1.GenericIssuer) (issuer.Interfac
 		issuer:            issuer,
 		secretsLister:     secretsLister,
 		resourceNamespace: ctx.IssuerOptions.ResourceNamespace(issuer),
+		clientBuilder:     vaultinternal.New,
 	}, nil
 }
 
@@ -16,6 +16,8 @@ limitations under the License.
 
 package api
 
+import "sort"
+
 type CustomFieldType string
 
 const (
@@ -28,3 +30,28 @@ type CustomField struct {
 	Name  string          `json:"name"`
 	Value string          `json:"value"`
 }
+
+// MergeCustomFields returns the given custom fields, preceded by each default
+// custom field for which no field with the same name is given. Defaults are
+// sorted by name so that requests are built deterministically.
+func MergeCustomFields(defaults map[string]string, fields []CustomField) []CustomField {
+	if len(defaults) == 0 {
+		return fields
+	}
+
+	given := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		given[field.Name] = true
+	}
+
+	var out []CustomField
+	for name, value := range defaults {
+		if given[name] {
+			continue
+		}
+		out = append(out, CustomField{Type: CustomFieldTypePlain, Name: name, Value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return append(out, fields...)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package fake
 
 import (
+	"context"
 	"time"
 
 	"github.com/Venafi/vcert/v4/pkg/endpoint"
@@ -29,17 +30,18 @@ type Venafi struct {
 	RequestCertificateFn    func(csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error)
 	RetrieveCertificateFn   func(pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error)
 	ReadZoneConfigurationFn func() (*endpoint.ZoneConfiguration, error)
+	RetireCertificateFn     func(certDER []byte) error
 }
 
-func (v *Venafi) Ping() error {
+func (v *Venafi) Ping(context.Context) error {
 	return v.PingFn()
 }
 
-func (v *Venafi) RequestCertificate(csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error) {
+func (v *Venafi) RequestCertificate(_ context.Context, csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error) {
 	return v.RequestCertificateFn(csrPEM, duration, customFields)
 }
 
-func (v *Venafi) RetrieveCertificate(pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error) {
+func (v *Venafi) RetrieveCertificate(_ context.Context, pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error) {
 	return v.RetrieveCertificateFn(pickupID, csrPEM, duration, customFields)
 }
 
@@ -47,4 +49,8 @@ func (v *Venafi) ReadZoneConfiguration() (*endpoint.ZoneConfiguration, error) {
 	return v.ReadZoneConfigurationFn()
 }
 
+func (v *Venafi) RetireCertificate(_ context.Context, certDER []byte) error {
+	return v.RetireCertificateFn(certDER)
+}
+
 func (v *Venafi) SetClient(endpoint.Connector) {}
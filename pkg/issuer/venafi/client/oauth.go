@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+const (
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeJWTBearer         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+	// minTokenRefreshInterval is the shortest amount of time a fetched token
+	// will be cached for, even if it reports a shorter lifetime, to avoid
+	// hammering the token endpoint.
+	minTokenRefreshInterval = 10 * time.Second
+)
+
+// cloudTokens caches Venafi Cloud credentials fetched from OAuth 2.0 token
+// endpoints. A new Venafi client is constructed every time an Issuer or
+// CertificateRequest is synced, so the cache is shared by all clients to
+// avoid requesting a new token on each sync.
+var cloudTokens = newTokenCache(time.Now)
+
+type cachedToken struct {
+	accessToken string
+	refreshAt   time.Time
+}
+
+type tokenCache struct {
+	// group ensures a single request is made to the token endpoint for
+	// each set of credentials at a time, without holding lock while the
+	// request is in flight
+	group singleflight.Group
+
+	lock   sync.Mutex
+	tokens map[string]cachedToken
+	now    func() time.Time
+}
+
+func newTokenCache(now func() time.Time) *tokenCache {
+	return &tokenCache{
+		tokens: make(map[string]cachedToken),
+		now:    now,
+	}
+}
+
+// tokenResponse is the successful response of an OAuth 2.0 token endpoint,
+// as defined in RFC 6749 section 5.1.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokenErrorResponse is the error response of an OAuth 2.0 token endpoint,
+// as defined in RFC 6749 section 5.2.
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// cloudAccessToken returns Venafi Cloud credentials for the given OAuth
+// configuration. Credentials are fetched from the token endpoint if there is
+// no cached token, or once two thirds of the cached token's lifetime has
+// elapsed, so that a token is never used close to its expiry.
+func (c *tokenCache) cloudAccessToken(oauth *cmapi.VenafiCloudOAuth, secretsLister corelisters.SecretLister, namespace string) (string, error) {
+	form := url.Values{}
+	var clientSecret string
+	switch {
+	case oauth.ClientSecretRef != nil:
+		secret, err := readSecretKey(secretsLister, namespace, oauth.ClientSecretRef)
+		if err != nil {
+			return "", err
+		}
+		form.Set("grant_type", grantTypeClientCredentials)
+		clientSecret = secret
+	case oauth.FederatedTokenSecretRef != nil:
+		assertion, err := readSecretKey(secretsLister, namespace, oauth.FederatedTokenSecretRef)
+		if err != nil {
+			return "", err
+		}
+		form.Set("grant_type", grantTypeJWTBearer)
+		form.Set("assertion", assertion)
+		if len(oauth.ClientID) > 0 {
+			form.Set("client_id", oauth.ClientID)
+		}
+	default:
+		// API validation in webhook should make this unreachable in production.
+		return "", fmt.Errorf("neither clientSecretRef or federatedTokenSecretRef configured for Venafi Cloud OAuth")
+	}
+	if len(oauth.Scopes) > 0 {
+		form.Set("scope", strings.Join(oauth.Scopes, " "))
+	}
+
+	// The cache key covers the credentials themselves, so that rotating the
+	// client secret or federated token causes a new token to be fetched.
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s", namespace, oauth.TokenURL, oauth.ClientID, clientSecret, form.Encode())
+	key := hex.EncodeToString(h.Sum(nil))
+
+	if token, ok := c.get(key); ok {
+		return token, nil
+	}
+
+	token, err, _ := c.group.Do(key, func() (interface{}, error) {
+		// another caller may have stored a token while this one was waiting
+		if token, ok := c.get(key); ok {
+			return token, nil
+		}
+
+		resp, err := fetchToken(oauth.TokenURL, oauth.ClientID, clientSecret, form)
+		if err != nil {
+			return "", fmt.Errorf("error fetching Venafi Cloud credentials from %q: %v", oauth.TokenURL, err)
+		}
+
+		refreshAfter := time.Duration(resp.ExpiresIn) * time.Second * 2 / 3
+		if refreshAfter < minTokenRefreshInterval {
+			refreshAfter = minTokenRefreshInterval
+		}
+		c.set(key, resp.AccessToken, refreshAfter)
+		return resp.AccessToken, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token.(string), nil
+}
+
+// get returns the cached token for key, if it does not need to be refreshed.
+func (c *tokenCache) get(key string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tok, ok := c.tokens[key]
+	if !ok || !c.now().Before(tok.refreshAt) {
+		return "", false
+	}
+	return tok.accessToken, true
+}
+
+// set caches the token for key until refreshAfter has elapsed. Tokens that
+// are due to be refreshed are pruned, so that tokens for rotated credentials
+// or deleted issuers are not kept in memory.
+func (c *tokenCache) set(key, accessToken string, refreshAfter time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	for k, tok := range c.tokens {
+		if !now.Before(tok.refreshAt) {
+			delete(c.tokens, k)
+		}
+	}
+	c.tokens[key] = cachedToken{
+		accessToken: accessToken,
+		refreshAt:   now.Add(refreshAfter),
+	}
+}
+
+func fetchToken(tokenURL, clientID, clientSecret string, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if len(clientSecret) > 0 {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp tokenErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && len(errResp.Error) > 0 {
+			return nil, fmt.Errorf("token endpoint returned %d: %s: %s", resp.StatusCode, errResp.Error, errResp.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+
+	var tokResp tokenResponse
+	if err := json.Unmarshal(body, &tokResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if len(tokResp.AccessToken) == 0 {
+		return nil, fmt.Errorf("token response did not contain an access_token")
+	}
+
+	return &tokResp, nil
+}
+
+func readSecretKey(secretsLister corelisters.SecretLister, namespace string, ref *cmmeta.SecretKeySelector) (string, error) {
+	secret, err := secretsLister.Secrets(namespace).Get(ref.Name)
+	if err != nil {
+		return "", err
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("no data for %q in secret '%s/%s'", ref.Key, namespace, ref.Name)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
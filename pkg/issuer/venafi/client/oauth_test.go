@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestCloudAccessToken(t *testing.T) {
+	var requests int
+	var lastForm map[string]string
+	var lastUser, lastPass string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		lastForm = map[string]string{}
+		for k := range r.PostForm {
+			lastForm[k] = r.PostForm.Get(k)
+		}
+		lastUser, lastPass, _ = r.BasicAuth()
+		if lastForm["assertion"] == "rejected" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"token expired"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":300}`, requests)
+	}))
+	defer server.Close()
+
+	secretsLister := generateSecretLister(&corev1.Secret{
+		Data: map[string][]byte{
+			"client-secret": []byte("s3cr3t"),
+			"jwt":           []byte("federated-jwt\n"),
+			"rejected":      []byte("rejected"),
+		},
+	}, nil)
+
+	now := time.Now()
+	cache := newTokenCache(func() time.Time { return now })
+
+	clientCredentials := &cmapi.VenafiCloudOAuth{
+		TokenURL: server.URL,
+		ClientID: "cert-manager",
+		ClientSecretRef: &cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{Name: "oauth"},
+			Key:                  "client-secret",
+		},
+		Scopes: []string{"certificate:request", "certificate:read"},
+	}
+
+	token, err := cache.cloudAccessToken(clientCredentials, secretsLister, "test-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token-1" {
+		t.Errorf("unexpected token: %q", token)
+	}
+	if lastForm["grant_type"] != grantTypeClientCredentials {
+		t.Errorf("unexpected grant_type: %q", lastForm["grant_type"])
+	}
+	if lastForm["scope"] != "certificate:request certificate:read" {
+		t.Errorf("unexpected scope: %q", lastForm["scope"])
+	}
+	if lastUser != "cert-manager" || lastPass != "s3cr3t" {
+		t.Errorf("unexpected client credentials: %q/%q", lastUser, lastPass)
+	}
+
+	// token should be served from the cache until 2/3 of its lifetime elapsed
+	now = now.Add(199 * time.Second)
+	token, err = cache.cloudAccessToken(clientCredentials, secretsLister, "test-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token-1" || requests != 1 {
+		t.Errorf("expected cached token, got %q after %d requests", token, requests)
+	}
+
+	now = now.Add(time.Second)
+	token, err = cache.cloudAccessToken(clientCredentials, secretsLister, "test-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token-2" || requests != 2 {
+		t.Errorf("expected refreshed token, got %q after %d requests", token, requests)
+	}
+
+	federated := &cmapi.VenafiCloudOAuth{
+		TokenURL: server.URL,
+		FederatedTokenSecretRef: &cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{Name: "federated"},
+			Key:                  "jwt",
+		},
+	}
+	token, err = cache.cloudAccessToken(federated, secretsLister, "test-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token-3" {
+		t.Errorf("unexpected token: %q", token)
+	}
+	if len(cache.tokens) != 2 {
+		t.Errorf("expected 2 cached tokens, got %d", len(cache.tokens))
+	}
+	if lastForm["grant_type"] != grantTypeJWTBearer {
+		t.Errorf("unexpected grant_type: %q", lastForm["grant_type"])
+	}
+	if lastForm["assertion"] != "federated-jwt" {
+		t.Errorf("unexpected assertion: %q", lastForm["assertion"])
+	}
+	if lastUser != "" {
+		t.Errorf("expected no basic auth, got user %q", lastUser)
+	}
+
+	// tokens that are due to be refreshed are pruned when a token is stored
+	now = now.Add(time.Hour)
+	if _, err := cache.cloudAccessToken(clientCredentials, secretsLister, "test-namespace"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.tokens) != 1 {
+		t.Errorf("expected stale tokens to be pruned, got %d cached tokens", len(cache.tokens))
+	}
+
+	federated.FederatedTokenSecretRef.Key = "rejected"
+	_, err = cache.cloudAccessToken(federated, secretsLister, "test-namespace")
+	if err == nil {
+		t.Fatal("expected error from token endpoint")
+	}
+	expErr := fmt.Sprintf("error fetching Venafi Cloud credentials from %q: token endpoint returned 400: invalid_grant: token expired", server.URL)
+	if err.Error() != expErr {
+		t.Errorf("unexpected error, exp=%q got=%q", expErr, err)
+	}
+
+	federated.FederatedTokenSecretRef.Key = "missing"
+	_, err = cache.cloudAccessToken(federated, secretsLister, "test-namespace")
+	if err == nil {
+		t.Fatal("expected error for missing secret key")
+	}
+}
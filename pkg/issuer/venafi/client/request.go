@@ -17,7 +17,10 @@ limitations under the License.
 package client
 
 import (
+	"context"
+	"crypto/sha1"
 	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -38,24 +41,57 @@ func (err ErrCustomFieldsType) Error() string {
 	return fmt.Sprintf("certificate request contains an invalid Venafi custom fields type: %q", err.Type)
 }
 
+// ErrPolicyViolation is returned when a request does not satisfy the policy of
+// the Venafi zone. Such requests are not submitted, since Venafi would reject
+// them.
+type ErrPolicyViolation struct {
+	Zone string
+	Err  error
+}
+
+func (err ErrPolicyViolation) Error() string {
+	return fmt.Sprintf("certificate request does not satisfy the policy of Venafi zone %q: %s", err.Zone, err.Err)
+}
+
+func (err ErrPolicyViolation) Unwrap() error {
+	return err.Err
+}
+
 var ErrorMissingSubject = errors.New("Certificate requests submitted to Venafi issuers must have the 'commonName' field or at least one other subject field set.")
 
 // This function sends a request to Venafi to for a signed certificate.
 // The CSR will be decoded to be validated against the zone configuration policy.
 // Upon the template being successfully defaulted and validated, the CSR will be sent, as is.
 // It will return a pickup ID which can be used with RetrieveCertificate to get the certificate
-func (v *Venafi) RequestCertificate(csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error) {
-	vreq, err := v.buildVReq(csrPEM, duration, customFields)
+func (v *Venafi) RequestCertificate(ctx context.Context, csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error) {
+	var vreq *certificate.Request
+	err := v.callWithReauth(ctx, func(client connector) (err error) {
+		vreq, err = v.buildVReq(client, csrPEM, duration, customFields)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
+
 	// Send the certificate signing request to Venafi
-	requestID, err := v.vcertClient.RequestCertificate(vreq)
-	return requestID, err
+	var requestID string
+	err = v.callWithReauth(ctx, func(client connector) (err error) {
+		requestID, err = client.RequestCertificate(vreq)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return requestID, nil
 }
 
-func (v *Venafi) RetrieveCertificate(pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error) {
-	vreq, err := v.buildVReq(csrPEM, duration, customFields)
+func (v *Venafi) RetrieveCertificate(ctx context.Context, pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error) {
+	var vreq *certificate.Request
+	err := v.callWithReauth(ctx, func(client connector) (err error) {
+		vreq, err = v.buildVReq(client, csrPEM, duration, customFields)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +100,11 @@ func (v *Venafi) RetrieveCertificate(pickupID string, csrPEM []byte, duration ti
 	vreq.Timeout = time.Second * 10
 
 	// Retrieve the certificate from request
-	pemCollection, err := v.vcertClient.RetrieveCertificate(vreq)
+	var pemCollection *certificate.PEMCollection
+	err = v.callWithReauth(ctx, func(client connector) (err error) {
+		pemCollection, err = client.RetrieveCertificate(vreq)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +116,28 @@ func (v *Venafi) RetrieveCertificate(pickupID string, csrPEM []byte, duration ti
 	return []byte(chain), nil
 }
 
-func (v *Venafi) buildVReq(csrPEM []byte, duration time.Duration, customFields []api.CustomField) (*certificate.Request, error) {
+// RetireCertificate revokes the given DER encoded certificate and disables it
+// in Venafi so that it is not renewed or provisioned again. The certificate is
+// identified by its SHA-1 thumbprint. Venafi Cloud does not support
+// revocation, so an error is returned for Venafi Cloud issuers.
+func (v *Venafi) RetireCertificate(ctx context.Context, certDER []byte) error {
+	thumbprint := sha1.Sum(certDER)
+	req := &certificate.RevocationRequest{
+		Thumbprint: strings.ToUpper(hex.EncodeToString(thumbprint[:])),
+		Comments:   "Revoked by cert-manager",
+		Disable:    true,
+	}
+
+	return v.callWithReauth(ctx, func(client connector) error {
+		return client.RevokeCertificate(req)
+	})
+}
+
+func (v *Venafi) buildVReq(client connector, csrPEM []byte, duration time.Duration, customFields []api.CustomField) (*certificate.Request, error) {
 	// Retrieve a copy of the Venafi zone.
 	// This contains default values and policy control info that we can apply
 	// and check against locally.
-	zoneCfg, err := v.vcertClient.ReadZoneConfiguration()
+	zoneCfg, err := client.ReadZoneConfiguration()
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +155,7 @@ func (v *Venafi) buildVReq(csrPEM []byte, duration time.Duration, customFields [
 	vreq := newVRequest(tmpl)
 
 	// Convert over custom fields from our struct type to venafi's
-	vfields, err := convertCustomFieldsToVcert(customFields)
+	vfields, err := convertCustomFieldsToVcert(api.MergeCustomFields(v.customFields, customFields))
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +169,7 @@ func (v *Venafi) buildVReq(csrPEM []byte, duration time.Duration, customFields [
 	// however, as this will be done again server side.
 	err = zoneCfg.ValidateCertificateRequest(vreq)
 	if err != nil {
-		return nil, err
+		return nil, ErrPolicyViolation{Zone: v.zone, Err: err}
 	}
 
 	friendlyName, err := getVcertFriendlyName(tmpl)
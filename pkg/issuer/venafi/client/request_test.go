@@ -17,12 +17,14 @@ limitations under the License.
 package client
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -225,7 +227,7 @@ func TestVenafi_RequestCertificate(t *testing.T) {
 					"foo.example.com", "bar.example.com"})
 			}
 
-			got, err := v.RequestCertificate(tt.args.csrPEM, time.Minute, tt.args.customFields)
+			got, err := v.RequestCertificate(context.TODO(), tt.args.csrPEM, time.Minute, tt.args.customFields)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RequestCertificate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -237,6 +239,74 @@ func TestVenafi_RequestCertificate(t *testing.T) {
 	}
 }
 
+func TestVenafi_buildVReq(t *testing.T) {
+	privateKey, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := generateCSR(t, privateKey, "common-name", []string{"foo.example.com"})
+
+	tests := map[string]struct {
+		zoneCfg          *endpoint.ZoneConfiguration
+		issuerFields     map[string]string
+		customFields     []api.CustomField
+		wantCustomFields []certificate.CustomField
+		wantPolicyErr    bool
+	}{
+		"issuer custom fields should be sent, overridden by request custom fields of the same name": {
+			issuerFields: map[string]string{"field-b": "issuer", "field-a": "issuer"},
+			customFields: []api.CustomField{{Name: "field-b", Value: "request"}},
+			wantCustomFields: []certificate.CustomField{
+				{Type: certificate.CustomFieldOrigin, Value: "cert-manager"},
+				{Type: certificate.CustomFieldPlain, Name: "field-a", Value: "issuer"},
+				{Type: certificate.CustomFieldPlain, Name: "field-b", Value: "request"},
+			},
+		},
+		"a request violating the zone policy should return a policy violation error": {
+			zoneCfg: &endpoint.ZoneConfiguration{
+				Policy: endpoint.Policy{
+					SubjectCNRegexes: []string{"foo"},
+				},
+			},
+			wantPolicyErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			connector := internalfake.Connector{}
+			if test.zoneCfg != nil {
+				connector.ReadZoneConfigurationFunc = func() (*endpoint.ZoneConfiguration, error) {
+					return test.zoneCfg, nil
+				}
+			}
+			v := &Venafi{
+				zone:         "test-zone",
+				customFields: test.issuerFields,
+				vcertClient:  connector.Default(),
+			}
+
+			vreq, err := v.buildVReq(v.vcertClient, csrPEM, time.Minute, test.customFields)
+			var policyErr ErrPolicyViolation
+			if errors.As(err, &policyErr) != test.wantPolicyErr {
+				t.Fatalf("unexpected error, wantPolicyErr=%t got=%v", test.wantPolicyErr, err)
+			}
+			if test.wantPolicyErr {
+				if policyErr.Zone != "test-zone" {
+					t.Errorf("unexpected zone in policy error, exp=%q got=%q", "test-zone", policyErr.Zone)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(vreq.CustomFields, test.wantCustomFields) {
+				t.Errorf("unexpected custom fields, exp=%v got=%v", test.wantCustomFields, vreq.CustomFields)
+			}
+		})
+	}
+}
+
 func TestVenafi_RetrieveCertificate(t *testing.T) {
 	privateKey, err := pki.GenerateRSAPrivateKey(2048)
 	if err != nil {
@@ -290,11 +360,11 @@ func TestVenafi_RetrieveCertificate(t *testing.T) {
 			// this is needed to provide the fake venafi client with a "valid" pickup id
 			// testing errors in this should be done in TestVenafi_RequestCertificate
 			// any error returned in these tests is a hard fail
-			pickupID, err := v.RequestCertificate(tt.args.csrPEM, tt.args.duration, tt.args.customFields)
+			pickupID, err := v.RequestCertificate(context.TODO(), tt.args.csrPEM, tt.args.duration, tt.args.customFields)
 			if err != nil {
 				t.Errorf("RequestCertificate() should but error but got error = %v", err)
 			}
-			got, err := v.RetrieveCertificate(pickupID, tt.args.csrPEM, tt.args.duration, tt.args.customFields)
+			got, err := v.RetrieveCertificate(context.TODO(), pickupID, tt.args.csrPEM, tt.args.duration, tt.args.customFields)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RetrieveCertificate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -305,3 +375,52 @@ func TestVenafi_RetrieveCertificate(t *testing.T) {
 		})
 	}
 }
+
+func TestVenafi_RetireCertificate(t *testing.T) {
+	certDER := []byte("certificate")
+	// SHA-1 thumbprint of certDER
+	const thumbprint = "735AD571C189D7BA84464BF4A9F1D2280175B128"
+
+	tests := []struct {
+		name        string
+		revokeErr   error
+		wantErr     bool
+		wantRequest *certificate.RevocationRequest
+	}{
+		{
+			name: "retire the certificate by its thumbprint",
+			wantRequest: &certificate.RevocationRequest{
+				Thumbprint: thumbprint,
+				Comments:   "Revoked by cert-manager",
+				Disable:    true,
+			},
+		},
+		{
+			name:      "error if revoke certificate fails",
+			revokeErr: errors.New("revoke error"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRequest *certificate.RevocationRequest
+			v := &Venafi{
+				vcertClient: internalfake.Connector{
+					RevokeCertificateFunc: func(req *certificate.RevocationRequest) error {
+						gotRequest = req
+						return tt.revokeErr
+					},
+				}.Default(),
+			}
+
+			err := v.RetireCertificate(context.TODO(), certDER)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RetireCertificate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantRequest != nil && !reflect.DeepEqual(gotRequest, tt.wantRequest) {
+				t.Errorf("unexpected revocation request, exp=%+v got=%+v", tt.wantRequest, gotRequest)
+			}
+		})
+	}
+}
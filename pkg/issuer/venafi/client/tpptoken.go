@@ -0,0 +1,414 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	vcert "github.com/Venafi/vcert/v4"
+	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	"github.com/Venafi/vcert/v4/pkg/venafi/tpp"
+	"github.com/Venafi/vcert/v4/pkg/verror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// tppTokenCache caches the TPP tokens obtained by refreshing an access token,
+// keyed by the credentials Secret. A new Venafi client is constructed every
+// time an Issuer or CertificateRequest is synced, and the Secret lister may
+// not yet have observed the refreshed tokens written back to the Secret. TPP
+// can be configured to accept a refresh token only once, so the cache avoids
+// reusing a refresh token that has already been exchanged.
+var tppTokenCache = &refreshedTokenCache{tokens: make(map[string]refreshedTokens)}
+
+type refreshedTokens struct {
+	// previousRefreshToken is the refresh token that was exchanged for
+	// accessToken and refreshToken.
+	previousRefreshToken string
+	accessToken          string
+	refreshToken         string
+}
+
+type refreshedTokenCache struct {
+	lock   sync.Mutex
+	tokens map[string]refreshedTokens
+	// refreshLocks serialize the refreshes of the tokens of each key.
+	refreshLocks map[string]*sync.Mutex
+}
+
+// get returns the tokens that refreshToken was exchanged for, if any.
+func (c *refreshedTokenCache) get(key, refreshToken string) (refreshedTokens, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tokens, ok := c.tokens[key]
+	if !ok || tokens.previousRefreshToken != refreshToken {
+		return refreshedTokens{}, false
+	}
+	return tokens, true
+}
+
+func (c *refreshedTokenCache) set(key string, tokens refreshedTokens) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.tokens[key] = tokens
+}
+
+// lockRefresh blocks until no other client is refreshing the tokens of key,
+// and returns a function releasing the lock.
+func (c *refreshedTokenCache) lockRefresh(key string) func() {
+	c.lock.Lock()
+	if c.refreshLocks == nil {
+		c.refreshLocks = make(map[string]*sync.Mutex)
+	}
+	l, ok := c.refreshLocks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.refreshLocks[key] = l
+	}
+	c.lock.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// tppTokenRefresher exchanges the refresh token stored in the credentials
+// Secret of a TPP issuer for a new access token, and stores the new tokens
+// back in the Secret.
+type tppTokenRefresher struct {
+	namespace  string
+	secretName string
+	// secretsClient is used to persist refreshed tokens. Refreshed tokens are
+	// only kept in memory if it is nil.
+	secretsClient corev1client.SecretsGetter
+	cache         *refreshedTokenCache
+
+	// config is the vcert configuration of the client. It is only updated by
+	// init; clients using a refreshed access token are built from a copy.
+	config *vcert.Config
+	// transport records the status of the responses of TPP, so that a
+	// rejected access token can be detected.
+	transport *statusRecordingTransport
+
+	// lock protects the fields below, which change when the access token is
+	// refreshed.
+	lock         sync.Mutex
+	accessToken  string
+	refreshToken string
+	// client is the vcert client using accessToken, or nil if the access
+	// token has not been refreshed since the Venafi client was built.
+	client connector
+
+	refresh      func(cfg *vcert.Config, refreshToken string) (tpp.OauthRefreshAccessTokenResponse, error)
+	newConnector func(cfg *vcert.Config) (connector, error)
+}
+
+// newTPPTokenRefresher returns a tppTokenRefresher for the issuer, or nil if
+// the issuer does not use TPP or its credentials do not include a refresh
+// token. cfg must be the configuration returned by configForIssuer, and may be
+// updated with a refreshed access token and an HTTP client recording the
+// status of responses.
+func newTPPTokenRefresher(ctx context.Context, namespace string, secretsClient corev1client.SecretsGetter, issuer cmapi.GenericIssuer, cfg *vcert.Config) (*tppTokenRefresher, error) {
+	tppCfg := issuer.GetSpec().Venafi.TPP
+	creds := cfg.Credentials
+	if tppCfg == nil || creds.RefreshToken == "" || (creds.User != "" && creds.Password != "") {
+		return nil, nil
+	}
+
+	transport, err := newStatusRecordingTransport(cfg.ConnectionTrust)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Client = &http.Client{Timeout: 30 * time.Second, Transport: transport}
+
+	r := &tppTokenRefresher{
+		namespace:     namespace,
+		secretName:    tppCfg.CredentialsRef.Name,
+		secretsClient: secretsClient,
+		cache:         tppTokenCache,
+		config:        cfg,
+		transport:     transport,
+		accessToken:   creds.AccessToken,
+		refreshToken:  creds.RefreshToken,
+		refresh:       refreshTPPAccessToken,
+		newConnector:  newVcertConnector,
+	}
+	// vcert exchanges a refresh token for a new access token every time it
+	// authenticates, so only use the refresh token once TPP has rejected the
+	// access token.
+	creds.RefreshToken = ""
+
+	if err := r.init(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// init brings the access token in the configuration up to date. Tokens that
+// were refreshed by an earlier client but are not yet visible in the Secret
+// are used in place of those in the Secret, and a new access token is
+// requested if the Secret only contains a refresh token.
+func (r *tppTokenRefresher) init(ctx context.Context) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if tokens, ok := r.cache.get(r.cacheKey(), r.refreshToken); ok {
+		r.use(tokens)
+	} else if r.accessToken == "" {
+		if err := r.exchangeRefreshToken(ctx); err != nil {
+			return err
+		}
+	} else {
+		return nil
+	}
+	r.config.Credentials.AccessToken = r.accessToken
+
+	return r.persist(ctx)
+}
+
+// current returns the vcert client to use and the access token it uses.
+// defaultClient is returned unless the access token has been refreshed.
+func (r *tppTokenRefresher) current(defaultClient connector) (connector, string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.client != nil {
+		return r.client, r.accessToken
+	}
+	return defaultClient, r.accessToken
+}
+
+// refreshAccessToken replaces the access token rejectedAccessToken, stores the
+// new tokens in the credentials Secret and returns a vcert client using the
+// new access token. If the access token has already been replaced, the client
+// using the current access token is returned without refreshing it again.
+func (r *tppTokenRefresher) refreshAccessToken(ctx context.Context, rejectedAccessToken string) (connector, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.accessToken != rejectedAccessToken && r.client != nil {
+		return r.client, nil
+	}
+
+	if err := r.exchangeRefreshToken(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.persist(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg := *r.config
+	creds := *r.config.Credentials
+	creds.AccessToken = r.accessToken
+	cfg.Credentials = &creds
+	client, err := r.newConnector(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Venafi client: %s", err.Error())
+	}
+	r.client = client
+
+	return client, nil
+}
+
+// exchangeRefreshToken exchanges the refresh token for a new access token and
+// refresh token. Exchanges of the tokens in the same Secret are serialized,
+// and tokens that another client obtained for the same refresh token are used
+// instead of exchanging it again. r.lock must be held.
+func (r *tppTokenRefresher) exchangeRefreshToken(ctx context.Context) error {
+	unlock := r.cache.lockRefresh(r.cacheKey())
+	defer unlock()
+
+	if tokens, ok := r.cache.get(r.cacheKey(), r.refreshToken); ok {
+		r.use(tokens)
+		return nil
+	}
+
+	var resp tpp.OauthRefreshAccessTokenResponse
+	err := callWithContext(ctx, func() (err error) {
+		resp, err = r.refresh(r.config, r.refreshToken)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error refreshing TPP access token: %v", err)
+	}
+	if resp.Access_token == "" {
+		return errors.New("error refreshing TPP access token: no access token returned")
+	}
+
+	tokens := refreshedTokens{
+		previousRefreshToken: r.refreshToken,
+		accessToken:          resp.Access_token,
+		refreshToken:         resp.Refresh_token,
+	}
+	// TPP only returns a new refresh token if it is configured to rotate them.
+	if tokens.refreshToken == "" {
+		tokens.refreshToken = r.refreshToken
+	}
+	r.cache.set(r.cacheKey(), tokens)
+	r.use(tokens)
+
+	return nil
+}
+
+func (r *tppTokenRefresher) use(tokens refreshedTokens) {
+	r.accessToken = tokens.accessToken
+	r.refreshToken = tokens.refreshToken
+}
+
+// persist writes the current access token and refresh token to the
+// credentials Secret. r.lock must be held.
+func (r *tppTokenRefresher) persist(ctx context.Context) error {
+	if r.secretsClient == nil {
+		return nil
+	}
+
+	secret, err := r.secretsClient.Secrets(r.namespace).Get(ctx, r.secretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error storing refreshed TPP access token in Secret %q: %v", r.secretName, err)
+	}
+	if string(secret.Data[tppAccessTokenKey]) == r.accessToken &&
+		string(secret.Data[tppRefreshTokenKey]) == r.refreshToken {
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[tppAccessTokenKey] = []byte(r.accessToken)
+	secret.Data[tppRefreshTokenKey] = []byte(r.refreshToken)
+	if _, err := r.secretsClient.Secrets(r.namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error storing refreshed TPP access token in Secret %q: %v", r.secretName, err)
+	}
+
+	return nil
+}
+
+func (r *tppTokenRefresher) cacheKey() string {
+	return r.namespace + "/" + r.secretName
+}
+
+// unauthorized returns true if err was caused by TPP rejecting the
+// credentials of the client. vcert formats the status of unexpected responses
+// into untyped errors, so the status of the last response is read from the
+// transport of the client instead.
+func (r *tppTokenRefresher) unauthorized(err error) bool {
+	return errors.Is(err, verror.AuthError) || r.transport.lastStatus() == http.StatusUnauthorized
+}
+
+// callWithReauth runs a call to the Venafi API using callWithContext. If TPP
+// rejects the access token and a refresh token is available, the access token
+// is refreshed and the call is run once more with a vcert client using the new
+// access token. The vcert client of v is never replaced; later calls use the
+// client with the refreshed access token.
+func (v *Venafi) callWithReauth(ctx context.Context, call func(client connector) error) error {
+	if v.tppTokens == nil {
+		return callWithContext(ctx, func() error { return call(v.vcertClient) })
+	}
+
+	client, accessToken := v.tppTokens.current(v.vcertClient)
+	v.tppTokens.transport.reset()
+	err := callWithContext(ctx, func() error { return call(client) })
+	if err == nil || !v.tppTokens.unauthorized(err) {
+		return err
+	}
+
+	client, err = v.tppTokens.refreshAccessToken(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	return callWithContext(ctx, func() error { return call(client) })
+}
+
+// statusRecordingTransport is the transport of the HTTP client used by vcert
+// for TPP. It records the status code of the last response.
+type statusRecordingTransport struct {
+	base   http.RoundTripper
+	status int32
+}
+
+// newStatusRecordingTransport returns a statusRecordingTransport trusting the
+// given PEM encoded CA bundle, or the system roots if it is empty.
+func newStatusRecordingTransport(caBundle string) (*statusRecordingTransport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if caBundle != "" {
+		trust := x509.NewCertPool()
+		if !trust.AppendCertsFromPEM([]byte(caBundle)) {
+			return nil, errors.New("failed to parse PEM trust bundle")
+		}
+		if base.TLSClientConfig == nil {
+			base.TLSClientConfig = &tls.Config{}
+		}
+		base.TLSClientConfig.RootCAs = trust
+	}
+
+	return &statusRecordingTransport{base: base}, nil
+}
+
+func (t *statusRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.observe(resp.StatusCode)
+	}
+	return resp, err
+}
+
+func (t *statusRecordingTransport) observe(status int) {
+	atomic.StoreInt32(&t.status, int32(status))
+}
+
+func (t *statusRecordingTransport) reset() {
+	atomic.StoreInt32(&t.status, 0)
+}
+
+func (t *statusRecordingTransport) lastStatus() int {
+	return int(atomic.LoadInt32(&t.status))
+}
+
+func refreshTPPAccessToken(cfg *vcert.Config, refreshToken string) (tpp.OauthRefreshAccessTokenResponse, error) {
+	var trust *x509.CertPool
+	if cfg.ConnectionTrust != "" {
+		trust = x509.NewCertPool()
+		if !trust.AppendCertsFromPEM([]byte(cfg.ConnectionTrust)) {
+			return tpp.OauthRefreshAccessTokenResponse{}, errors.New("failed to parse PEM trust bundle")
+		}
+	}
+
+	c, err := tpp.NewConnector(cfg.BaseUrl, cfg.Zone, cfg.LogVerbose, trust)
+	if err != nil {
+		return tpp.OauthRefreshAccessTokenResponse{}, err
+	}
+	c.SetHTTPClient(cfg.Client)
+
+	return c.RefreshAccessToken(&endpoint.Authentication{
+		RefreshToken: refreshToken,
+		ClientId:     cfg.Credentials.ClientId,
+	})
+}
+
+func newVcertConnector(cfg *vcert.Config) (connector, error) {
+	return vcert.NewClient(cfg)
+}
@@ -0,0 +1,399 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vcert "github.com/Venafi/vcert/v4"
+	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	"github.com/Venafi/vcert/v4/pkg/venafi/tpp"
+	"github.com/Venafi/vcert/v4/pkg/verror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	internalfake "github.com/jetstack/cert-manager/pkg/issuer/venafi/client/fake"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	testSecretName = "tpp-credentials"
+)
+
+func newTestTokenRefresher(kubeClient *kubefake.Clientset, cache *refreshedTokenCache, accessToken, refreshToken string,
+	refresh func(cfg *vcert.Config, refreshToken string) (tpp.OauthRefreshAccessTokenResponse, error)) *tppTokenRefresher {
+	r := &tppTokenRefresher{
+		namespace:  testNamespace,
+		secretName: testSecretName,
+		cache:      cache,
+		config: &vcert.Config{
+			ConnectorType: endpoint.ConnectorTypeTPP,
+			Credentials:   &endpoint.Authentication{AccessToken: accessToken},
+		},
+		transport:    &statusRecordingTransport{},
+		accessToken:  accessToken,
+		refreshToken: refreshToken,
+		refresh:      refresh,
+		newConnector: func(cfg *vcert.Config) (connector, error) {
+			return &tokenConnector{accessToken: cfg.Credentials.AccessToken}, nil
+		},
+	}
+	if kubeClient != nil {
+		r.secretsClient = kubeClient.CoreV1()
+	}
+	return r
+}
+
+// tokenConnector is a fake vcert client recording the access token it was
+// built with.
+type tokenConnector struct {
+	connector
+	accessToken string
+}
+
+func accessTokenOf(c connector) string {
+	if tc, ok := c.(*tokenConnector); ok {
+		return tc.accessToken
+	}
+	return ""
+}
+
+func testCredentialsSecret(accessToken, refreshToken string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+		Data: map[string][]byte{
+			tppAccessTokenKey:  []byte(accessToken),
+			tppRefreshTokenKey: []byte(refreshToken),
+		},
+	}
+}
+
+func checkCredentialsSecret(t *testing.T, kubeClient *kubefake.Clientset, expAccessToken, expRefreshToken string) {
+	secret, err := kubeClient.CoreV1().Secrets(testNamespace).Get(context.TODO(), testSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error getting credentials Secret: %s", err)
+	}
+	if got := string(secret.Data[tppAccessTokenKey]); got != expAccessToken {
+		t.Errorf("unexpected access token in Secret, exp=%q got=%q", expAccessToken, got)
+	}
+	if got := string(secret.Data[tppRefreshTokenKey]); got != expRefreshToken {
+		t.Errorf("unexpected refresh token in Secret, exp=%q got=%q", expRefreshToken, got)
+	}
+}
+
+func refreshTo(accessToken, refreshToken string) func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error) {
+	return func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error) {
+		return tpp.OauthRefreshAccessTokenResponse{Access_token: accessToken, Refresh_token: refreshToken}, nil
+	}
+}
+
+func TestCallWithReauth(t *testing.T) {
+	errUnauthorized := errors.New("Unexpected status code on TPP Certificate Request.\n Status:\n 401 Unauthorized. \n Body:\n \n")
+
+	tests := map[string]struct {
+		// withRefresher configures a tppTokenRefresher on the client
+		withRefresher bool
+		refresh       func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error)
+		callErrs      []error
+
+		expErr         bool
+		expCalls       int
+		expAccessToken string
+		expRefresh     string
+	}{
+		"a successful call should not refresh the access token": {
+			withRefresher:  true,
+			refresh:        refreshTo("new-access", "new-refresh"),
+			callErrs:       []error{nil},
+			expCalls:       1,
+			expAccessToken: "old-access",
+			expRefresh:     "old-refresh",
+		},
+		"an error other than 401 should be returned without refreshing": {
+			withRefresher:  true,
+			refresh:        refreshTo("new-access", "new-refresh"),
+			callErrs:       []error{errors.New("Unexpected status code on TPP Certificate Request. Status: 500 Internal Server Error")},
+			expErr:         true,
+			expCalls:       1,
+			expAccessToken: "old-access",
+			expRefresh:     "old-refresh",
+		},
+		"a 401 without a refresh token should be returned": {
+			withRefresher:  false,
+			callErrs:       []error{errUnauthorized},
+			expErr:         true,
+			expCalls:       1,
+			expAccessToken: "old-access",
+			expRefresh:     "old-refresh",
+		},
+		"a 401 should refresh the tokens, persist them and retry the call": {
+			withRefresher:  true,
+			refresh:        refreshTo("new-access", "new-refresh"),
+			callErrs:       []error{errUnauthorized, nil},
+			expCalls:       2,
+			expAccessToken: "new-access",
+			expRefresh:     "new-refresh",
+		},
+		"if TPP does not rotate the refresh token the existing one should be kept": {
+			withRefresher:  true,
+			refresh:        refreshTo("new-access", ""),
+			callErrs:       []error{errUnauthorized, nil},
+			expCalls:       2,
+			expAccessToken: "new-access",
+			expRefresh:     "old-refresh",
+		},
+		"the call should only be retried once": {
+			withRefresher:  true,
+			refresh:        refreshTo("new-access", "new-refresh"),
+			callErrs:       []error{errUnauthorized, errUnauthorized},
+			expErr:         true,
+			expCalls:       2,
+			expAccessToken: "new-access",
+			expRefresh:     "new-refresh",
+		},
+		"a failure to refresh the access token should be returned": {
+			withRefresher: true,
+			refresh: func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error) {
+				return tpp.OauthRefreshAccessTokenResponse{}, errors.New("refresh token expired")
+			},
+			callErrs:       []error{errUnauthorized},
+			expErr:         true,
+			expCalls:       1,
+			expAccessToken: "old-access",
+			expRefresh:     "old-refresh",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(testCredentialsSecret("old-access", "old-refresh"))
+			v := &Venafi{vcertClient: internalfake.Connector{}.Default()}
+			if test.withRefresher {
+				v.tppTokens = newTestTokenRefresher(kubeClient, &refreshedTokenCache{tokens: make(map[string]refreshedTokens)},
+					"old-access", "old-refresh", test.refresh)
+			}
+
+			calls := 0
+			var clients []connector
+			err := v.callWithReauth(context.TODO(), func(client connector) error {
+				clients = append(clients, client)
+				err := test.callErrs[calls]
+				calls++
+				if err == errUnauthorized && v.tppTokens != nil {
+					v.tppTokens.transport.observe(http.StatusUnauthorized)
+				}
+				return err
+			})
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if calls != test.expCalls {
+				t.Errorf("unexpected number of calls, exp=%d got=%d", test.expCalls, calls)
+			}
+			if clients[0] != v.vcertClient {
+				t.Errorf("expected the first call to use the client of the Venafi client")
+			}
+			if calls > 1 {
+				if got := accessTokenOf(clients[1]); got != test.expAccessToken {
+					t.Errorf("unexpected access token of the client used to retry the call, exp=%q got=%q", test.expAccessToken, got)
+				}
+			}
+
+			checkCredentialsSecret(t, kubeClient, test.expAccessToken, test.expRefresh)
+			if v.tppTokens != nil {
+				if got := v.tppTokens.config.Credentials.AccessToken; got != "old-access" {
+					t.Errorf("expected the configuration of the client to be left unchanged, got access token %q", got)
+				}
+				client, accessToken := v.tppTokens.current(v.vcertClient)
+				if accessToken != test.expAccessToken {
+					t.Errorf("unexpected current access token, exp=%q got=%q", test.expAccessToken, accessToken)
+				}
+				if calls > 1 && client != clients[1] {
+					t.Errorf("expected later calls to use the client with the refreshed access token")
+				}
+			}
+		})
+	}
+}
+
+func TestCallWithReauthDetectsAuthErrors(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		err     error
+		expCall int
+	}{
+		"a 401 response should refresh the access token": {
+			status:  http.StatusUnauthorized,
+			err:     errors.New("Unexpected status code on TPP Certificate Request. Status: 401 Unauthorized"),
+			expCall: 2,
+		},
+		"a vcert authentication error should refresh the access token": {
+			err:     fmt.Errorf("%w: token expired", verror.AuthError),
+			expCall: 2,
+		},
+		"an error mentioning 401 without a 401 response should not refresh the access token": {
+			status:  http.StatusInternalServerError,
+			err:     errors.New("certificate request 401 is pending"),
+			expCall: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := &Venafi{vcertClient: internalfake.Connector{}.Default()}
+			v.tppTokens = newTestTokenRefresher(nil, &refreshedTokenCache{tokens: make(map[string]refreshedTokens)},
+				"old-access", "old-refresh", refreshTo("new-access", "new-refresh"))
+
+			calls := 0
+			_ = v.callWithReauth(context.TODO(), func(connector) error {
+				calls++
+				if calls > 1 {
+					return nil
+				}
+				if test.status != 0 {
+					v.tppTokens.transport.observe(test.status)
+				}
+				return test.err
+			})
+			if calls != test.expCall {
+				t.Errorf("unexpected number of calls, exp=%d got=%d", test.expCall, calls)
+			}
+		})
+	}
+}
+
+func TestRefreshAccessTokenIsSerialized(t *testing.T) {
+	cache := &refreshedTokenCache{tokens: make(map[string]refreshedTokens)}
+	var refreshes int32
+	refresh := func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error) {
+		atomic.AddInt32(&refreshes, 1)
+		time.Sleep(10 * time.Millisecond)
+		return tpp.OauthRefreshAccessTokenResponse{Access_token: "new-access", Refresh_token: "new-refresh"}, nil
+	}
+
+	// Clients built for the same Secret refresh concurrently, as well as
+	// concurrent calls of the same client.
+	refreshers := []*tppTokenRefresher{
+		newTestTokenRefresher(nil, cache, "old-access", "old-refresh", refresh),
+		newTestTokenRefresher(nil, cache, "old-access", "old-refresh", refresh),
+	}
+	var wg sync.WaitGroup
+	for _, r := range append(refreshers, refreshers...) {
+		wg.Add(1)
+		go func(r *tppTokenRefresher) {
+			defer wg.Done()
+			client, err := r.refreshAccessToken(context.TODO(), "old-access")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if got := accessTokenOf(client); got != "new-access" {
+				t.Errorf("unexpected access token, exp=%q got=%q", "new-access", got)
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	if refreshes != 1 {
+		t.Errorf("expected the refresh token to be exchanged once, got %d", refreshes)
+	}
+}
+
+func TestTPPTokenRefresherInit(t *testing.T) {
+	unexpectedRefresh := func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error) {
+		return tpp.OauthRefreshAccessTokenResponse{}, errors.New("unexpected refresh")
+	}
+
+	tests := map[string]struct {
+		accessToken string
+		cached      *refreshedTokens
+		refresh     func(*vcert.Config, string) (tpp.OauthRefreshAccessTokenResponse, error)
+
+		expErr         bool
+		expAccessToken string
+		expRefresh     string
+	}{
+		"an existing access token should be used as is": {
+			accessToken:    "old-access",
+			refresh:        unexpectedRefresh,
+			expAccessToken: "old-access",
+			expRefresh:     "old-refresh",
+		},
+		"a missing access token should be requested using the refresh token": {
+			refresh:        refreshTo("new-access", "new-refresh"),
+			expAccessToken: "new-access",
+			expRefresh:     "new-refresh",
+		},
+		"tokens already refreshed by another client should be used and persisted": {
+			accessToken: "old-access",
+			cached: &refreshedTokens{
+				previousRefreshToken: "old-refresh",
+				accessToken:          "cached-access",
+				refreshToken:         "cached-refresh",
+			},
+			refresh:        unexpectedRefresh,
+			expAccessToken: "cached-access",
+			expRefresh:     "cached-refresh",
+		},
+		"cached tokens refreshed from a different refresh token should be ignored": {
+			accessToken: "old-access",
+			cached: &refreshedTokens{
+				previousRefreshToken: "other-refresh",
+				accessToken:          "cached-access",
+				refreshToken:         "cached-refresh",
+			},
+			refresh:        unexpectedRefresh,
+			expAccessToken: "old-access",
+			expRefresh:     "old-refresh",
+		},
+		"a failure to request a missing access token should be returned": {
+			refresh:        unexpectedRefresh,
+			expErr:         true,
+			expAccessToken: "",
+			expRefresh:     "old-refresh",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(testCredentialsSecret(test.accessToken, "old-refresh"))
+			cache := &refreshedTokenCache{tokens: make(map[string]refreshedTokens)}
+			if test.cached != nil {
+				cache.set(testNamespace+"/"+testSecretName, *test.cached)
+			}
+
+			r := newTestTokenRefresher(kubeClient, cache, test.accessToken, "old-refresh", test.refresh)
+			err := r.init(context.TODO())
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			if got := r.config.Credentials.AccessToken; got != test.expAccessToken {
+				t.Errorf("unexpected access token in client config, exp=%q got=%q", test.expAccessToken, got)
+			}
+			checkCredentialsSecret(t, kubeClient, test.expAccessToken, test.expRefresh)
+		})
+	}
+}
@@ -17,12 +17,14 @@ limitations under the License.
 package client
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	vcert "github.com/Venafi/vcert/v4"
 	"github.com/Venafi/vcert/v4/pkg/certificate"
 	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -33,6 +35,11 @@ const (
 	tppUsernameKey    = "username"
 	tppPasswordKey    = "password"
 	tppAccessTokenKey = "access-token"
+	// tppRefreshTokenKey and tppClientIDKey are optional. When a refresh token
+	// is present, an expired access token is refreshed automatically and the
+	// new tokens are written back to the credentials Secret.
+	tppRefreshTokenKey = "refresh-token"
+	tppClientIDKey     = "client-id"
 
 	defaultAPIKeyKey = "api-key"
 )
@@ -42,10 +49,11 @@ type VenafiClientBuilder func(namespace string, secretsLister corelisters.Secret
 
 // Interface implements a Venafi client
 type Interface interface {
-	RequestCertificate(csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error)
-	RetrieveCertificate(pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error)
-	Ping() error
+	RequestCertificate(ctx context.Context, csrPEM []byte, duration time.Duration, customFields []api.CustomField) (string, error)
+	RetrieveCertificate(ctx context.Context, pickupID string, csrPEM []byte, duration time.Duration, customFields []api.CustomField) ([]byte, error)
+	Ping(ctx context.Context) error
 	ReadZoneConfiguration() (*endpoint.ZoneConfiguration, error)
+	RetireCertificate(ctx context.Context, certDER []byte) error
 	SetClient(endpoint.Connector)
 }
 
@@ -57,7 +65,16 @@ type Venafi struct {
 	namespace     string
 	secretsLister corelisters.SecretLister
 
+	// zone is the Venafi policy zone that requests are made against.
+	zone string
+	// customFields are the default custom fields configured on the issuer.
+	customFields map[string]string
+
 	vcertClient connector
+
+	// tppTokens refreshes the TPP access token when TPP rejects it. It is nil
+	// unless the issuer uses TPP credentials that include a refresh token.
+	tppTokens *tppTokenRefresher
 }
 
 // connector exposes a subset of the vcert Connector interface to make stubbing
@@ -68,16 +85,38 @@ type connector interface {
 	RequestCertificate(req *certificate.Request) (requestID string, err error)
 	RetrieveCertificate(req *certificate.Request) (certificates *certificate.PEMCollection, err error)
 	RenewCertificate(req *certificate.RenewalRequest) (requestID string, err error)
+	RevokeCertificate(req *certificate.RevocationRequest) (err error)
 }
 
 // New constructs a Venafi client Interface. Errors may be network errors and
 // should be considered for retrying.
+// TPP access tokens refreshed by the returned client are not persisted; use
+// NewBuilder to construct clients that write them back to the credentials
+// Secret.
 func New(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.GenericIssuer) (Interface, error) {
+	return newClient(namespace, secretsLister, nil, issuer)
+}
+
+// NewBuilder returns a VenafiClientBuilder constructing clients that use
+// secretsClient to persist refreshed TPP access and refresh tokens to the
+// credentials Secret of the issuer.
+func NewBuilder(secretsClient corev1client.SecretsGetter) VenafiClientBuilder {
+	return func(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.GenericIssuer) (Interface, error) {
+		return newClient(namespace, secretsLister, secretsClient, issuer)
+	}
+}
+
+func newClient(namespace string, secretsLister corelisters.SecretLister, secretsClient corev1client.SecretsGetter, issuer cmapi.GenericIssuer) (Interface, error) {
 	cfg, err := configForIssuer(issuer, secretsLister, namespace)
 	if err != nil {
 		return nil, err
 	}
 
+	tppTokens, err := newTPPTokenRefresher(context.TODO(), namespace, secretsClient, issuer, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	vcertClient, err := vcert.NewClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("error creating Venafi client: %s", err.Error())
@@ -86,7 +125,10 @@ func New(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.
 	return &Venafi{
 		namespace:     namespace,
 		secretsLister: secretsLister,
+		zone:          issuer.GetSpec().Venafi.Zone,
+		customFields:  issuer.GetSpec().Venafi.CustomFields,
 		vcertClient:   vcertClient,
+		tppTokens:     tppTokens,
 	}, nil
 }
 
@@ -105,6 +147,8 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 		username := string(tppSecret.Data[tppUsernameKey])
 		password := string(tppSecret.Data[tppPasswordKey])
 		accessToken := string(tppSecret.Data[tppAccessTokenKey])
+		refreshToken := string(tppSecret.Data[tppRefreshTokenKey])
+		clientID := string(tppSecret.Data[tppClientIDKey])
 		caBundle := string(tpp.CABundle)
 
 		return &vcert.Config{
@@ -115,24 +159,35 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 			LogVerbose:      true,
 			ConnectionTrust: caBundle,
 			Credentials: &endpoint.Authentication{
-				User:        username,
-				Password:    password,
-				AccessToken: accessToken,
+				User:         username,
+				Password:     password,
+				AccessToken:  accessToken,
+				RefreshToken: refreshToken,
+				ClientId:     clientID,
 			},
 		}, nil
 	case venCfg.Cloud != nil:
 		cloud := venCfg.Cloud
-		cloudSecret, err := secretsLister.Secrets(namespace).Get(cloud.APITokenSecretRef.Name)
-		if err != nil {
-			return nil, err
+		var apiKey string
+		if cloud.OAuth != nil {
+			token, err := cloudTokens.cloudAccessToken(cloud.OAuth, secretsLister, namespace)
+			if err != nil {
+				return nil, err
+			}
+			apiKey = token
+		} else {
+			cloudSecret, err := secretsLister.Secrets(namespace).Get(cloud.APITokenSecretRef.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			k := defaultAPIKeyKey
+			if cloud.APITokenSecretRef.Key != "" {
+				k = cloud.APITokenSecretRef.Key
+			}
+			apiKey = string(cloudSecret.Data[k])
 		}
 
-		k := defaultAPIKeyKey
-		if cloud.APITokenSecretRef.Key != "" {
-			k = cloud.APITokenSecretRef.Key
-		}
-		apiKey := string(cloudSecret.Data[k])
-
 		return &vcert.Config{
 			ConnectorType: endpoint.ConnectorTypeCloud,
 			BaseUrl:       cloud.URL,
@@ -149,14 +204,43 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 	return nil, fmt.Errorf("neither Venafi Cloud or TPP configuration found")
 }
 
-func (v *Venafi) Ping() error {
-	return v.vcertClient.Ping()
+func (v *Venafi) Ping(ctx context.Context) error {
+	return v.callWithReauth(ctx, func(client connector) error {
+		return client.Ping()
+	})
 }
 
 func (v *Venafi) ReadZoneConfiguration() (*endpoint.ZoneConfiguration, error) {
-	return v.vcertClient.ReadZoneConfiguration()
+	var zoneCfg *endpoint.ZoneConfiguration
+	err := v.callWithReauth(context.TODO(), func(client connector) (err error) {
+		zoneCfg, err = client.ReadZoneConfiguration()
+		return err
+	})
+	return zoneCfg, err
 }
 
 func (v *Venafi) SetClient(client endpoint.Connector) {
 	v.vcertClient = client
 }
+
+// callWithContext runs a call to the Venafi API, returning the error of the
+// context early if it is cancelled or its deadline is exceeded before the
+// call completes. vcert does not accept a context, so an abandoned call keeps
+// running in the background until the timeout of the vcert HTTP client.
+func callWithContext(ctx context.Context, call func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
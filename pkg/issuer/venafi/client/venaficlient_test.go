@@ -17,8 +17,10 @@ limitations under the License.
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	vcert "github.com/Venafi/vcert/v4"
 	corev1 "k8s.io/api/core/v1"
@@ -142,6 +144,26 @@ func TestConfigForIssuerT(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		"if TPP and secret returns refresh-token and client-id, should return config with those credentials": {
+			iss: tppIssuer,
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					tppAccessTokenKey:  []byte(accessToken),
+					tppRefreshTokenKey: []byte("test-refresh-token"),
+					tppClientIDKey:     []byte("test-client-id"),
+				},
+			}, nil),
+			CheckFn: func(t *testing.T, cnf *vcert.Config) {
+				if refreshToken := cnf.Credentials.RefreshToken; refreshToken != "test-refresh-token" {
+					t.Errorf("got unexpected refreshToken: %q", refreshToken)
+				}
+				if clientID := cnf.Credentials.ClientId; clientID != "test-client-id" {
+					t.Errorf("got unexpected clientID: %q", clientID)
+				}
+				checkZone(t, zone, cnf)
+			},
+			expectedErr: false,
+		},
 		"if Cloud but getting secret fails, should error": {
 			iss:           cloudIssuer,
 			secretsLister: generateSecretLister(nil, errors.New("this is a network error")),
@@ -234,3 +256,49 @@ func (c *testConfigForIssuerT) runTest(t *testing.T) {
 		c.CheckFn(t, resp)
 	}
 }
+
+func TestCallWithContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := map[string]struct {
+		ctx    context.Context
+		call   func() error
+		expErr error
+	}{
+		"should return the error of a completed call": {
+			ctx:    context.Background(),
+			call:   func() error { return errors.New("call failed") },
+			expErr: errors.New("call failed"),
+		},
+		"should not make the call if the context is already cancelled": {
+			ctx: cancelled,
+			call: func() error {
+				t.Error("unexpected call")
+				return nil
+			},
+			expErr: context.Canceled,
+		},
+		"should return early if the deadline of the context is exceeded": {
+			ctx: func() context.Context {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				t.Cleanup(cancel)
+				return ctx
+			}(),
+			call: func() error {
+				time.Sleep(time.Minute)
+				return nil
+			},
+			expErr: context.DeadlineExceeded,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := callWithContext(test.ctx, test.call)
+			if (err == nil) != (test.expErr == nil) || (err != nil && err.Error() != test.expErr.Error()) {
+				t.Errorf("unexpected error, exp=%v got=%v", test.expErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package venafi
+
+import (
+	"context"
+	"crypto/x509"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+)
+
+var _ issuer.Revoker = &Venafi{}
+
+// Revoke retires the given certificate in Venafi TPP, revoking it and
+// preventing it from being renewed. Venafi Cloud does not support revocation.
+func (v *Venafi) Revoke(ctx context.Context, _ *cmapi.Certificate, cert *x509.Certificate) error {
+	if v.issuer.GetSpec().Venafi.TPP == nil {
+		return issuer.ErrRevocationNotSupported
+	}
+
+	client, err := v.clientBuilder(v.resourceNamespace, v.secretsLister, v.issuer)
+	if err != nil {
+		return err
+	}
+
+	return client.RetireCertificate(ctx, cert.Raw)
+}
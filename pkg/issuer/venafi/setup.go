@@ -41,7 +41,7 @@ func (v *Venafi) Setup(ctx context.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("error building client: %v", err)
 	}
-	err = client.Ping()
+	err = client.Ping(ctx)
 	if err != nil {
 		return fmt.Errorf("error pinging Venafi API: %v", err)
 	}
@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioca
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the messages defined in Istio's security/v1alpha1/ca.proto.
+const (
+	requestCSRField              protowire.Number = 1
+	requestValidityDurationField protowire.Number = 3
+	responseCertChainField       protowire.Number = 1
+)
+
+// CertificateRequest is the IstioCertificateRequest message that workloads
+// send to request a certificate. The request metadata is not used and is
+// ignored when decoding.
+type CertificateRequest struct {
+	// CSR is the PEM encoded certificate signing request.
+	CSR string
+	// ValidityDuration is the requested validity of the certificate, in
+	// seconds.
+	ValidityDuration int64
+}
+
+// CertificateResponse is the IstioCertificateResponse message returned to
+// workloads.
+type CertificateResponse struct {
+	// CertChain holds the PEM encoded certificate chain, starting with the
+	// workload certificate and ending with the root certificate.
+	CertChain []string
+}
+
+// message is implemented by the messages of the Istio CA service, so that
+// they can be encoded without generated protobuf code.
+type message interface {
+	marshal() []byte
+	unmarshal([]byte) error
+}
+
+func (r *CertificateRequest) marshal() []byte {
+	var b []byte
+	if len(r.CSR) > 0 {
+		b = protowire.AppendTag(b, requestCSRField, protowire.BytesType)
+		b = protowire.AppendString(b, r.CSR)
+	}
+	if r.ValidityDuration != 0 {
+		b = protowire.AppendTag(b, requestValidityDurationField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.ValidityDuration))
+	}
+	return b
+}
+
+func (r *CertificateRequest) unmarshal(b []byte) error {
+	*r = CertificateRequest{}
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == requestCSRField && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			r.CSR = v
+			return n, nil
+		case num == requestValidityDurationField && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			r.ValidityDuration = int64(v)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+func (r *CertificateResponse) marshal() []byte {
+	var b []byte
+	for _, cert := range r.CertChain {
+		b = protowire.AppendTag(b, responseCertChainField, protowire.BytesType)
+		b = protowire.AppendString(b, cert)
+	}
+	return b
+}
+
+func (r *CertificateResponse) unmarshal(b []byte) error {
+	*r = CertificateResponse{}
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if num == responseCertChainField && typ == protowire.BytesType {
+			v, n := protowire.ConsumeString(b)
+			if n >= 0 {
+				r.CertChain = append(r.CertChain, v)
+			}
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+// consumeFields calls fn with the value of each field in the encoded
+// message b. fn returns the length of the value it consumed, or a negative
+// number if the value is malformed.
+func consumeFields(b []byte, fn func(protowire.Number, protowire.Type, []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("failed to decode field tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		n, err := fn(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return fmt.Errorf("failed to decode field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// codec encodes the messages of the Istio CA service. It is registered under
+// the name of the default protobuf codec, as that is the content type that
+// Istio workloads send.
+type codec struct{}
+
+func (codec) Name() string {
+	return "proto"
+}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal unsupported type %T", v)
+	}
+	return m.marshal(), nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal unsupported type %T", v)
+	}
+	return m.unmarshal(data)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioca
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCodec(t *testing.T) {
+	tests := map[string]struct {
+		in  message
+		out message
+	}{
+		"request": {
+			in:  &CertificateRequest{CSR: "csr", ValidityDuration: 3600},
+			out: &CertificateRequest{},
+		},
+		"empty request": {
+			in:  &CertificateRequest{},
+			out: &CertificateRequest{},
+		},
+		"response": {
+			in:  &CertificateResponse{CertChain: []string{"leaf", "intermediate", "root"}},
+			out: &CertificateResponse{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec{}.Marshal(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := (codec{}).Unmarshal(data, test.out); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.in, test.out)
+		})
+	}
+}
+
+func TestCertificateRequestUnmarshal(t *testing.T) {
+	// Encode the request metadata, a google.protobuf.Struct, as an empty
+	// message. It is not used and must be skipped.
+	var b []byte
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendBytes(b, nil)
+	b = protowire.AppendTag(b, requestCSRField, protowire.BytesType)
+	b = protowire.AppendString(b, "csr")
+	b = protowire.AppendTag(b, requestValidityDurationField, protowire.VarintType)
+	b = protowire.AppendVarint(b, 60)
+
+	var req CertificateRequest
+	if err := req.unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, CertificateRequest{CSR: "csr", ValidityDuration: 60}, req)
+
+	if err := req.unmarshal(b[:len(b)-1]); err == nil {
+		t.Error("expected an error decoding a truncated message")
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package istioca implements the Istio CA (IstioCertificateService) gRPC API
+// backed by cert-manager issuers, so that the certificates of workloads in an
+// Istio service mesh can be signed by any cert-manager issuer instead of
+// istiod's built-in CA.
+package istioca
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/spiffe"
+)
+
+const (
+	// IdentityAnnotationKey is the annotation added to the CertificateRequests
+	// created by the Istio CA, holding the SPIFFE identity of the workload
+	// that requested the certificate.
+	IdentityAnnotationKey = "istio.cert-manager.io/identity"
+
+	defaultPollInterval = time.Second
+)
+
+// Server implements the Istio CA service. Workloads authenticate with a
+// Kubernetes service account token, and may only request a certificate for
+// the SPIFFE identity of that service account.
+type Server struct {
+	KubeClient kubernetes.Interface
+	CMClient   cmclient.Interface
+
+	// Namespace is the namespace that CertificateRequests are created in.
+	Namespace string
+	// IssuerRef is the issuer that signs workload certificates.
+	IssuerRef cmmeta.ObjectReference
+	// TrustDomain is the trust domain of the mesh's SPIFFE identities.
+	TrustDomain string
+	// Audiences are the audiences that a workload's token must be valid for.
+	// The API server's default audiences are used if empty.
+	Audiences []string
+	// MaxDuration is the maximum duration of workload certificates. It is
+	// also used for requests that do not specify a duration.
+	MaxDuration time.Duration
+	// PreserveCertificateRequests disables the deletion of CertificateRequests
+	// once they have been signed or have failed.
+	PreserveCertificateRequests bool
+
+	Log logr.Logger
+
+	// pollInterval is how often a CertificateRequest is checked for
+	// completion. Used for testing.
+	pollInterval time.Duration
+}
+
+var _ certificateService = &Server{}
+
+// CreateCertificate signs the certificate signing request of an
+// authenticated workload using the configured issuer.
+func (s *Server) CreateCertificate(ctx context.Context, req *CertificateRequest) (*CertificateResponse, error) {
+	identity, err := s.authenticate(ctx)
+	if err != nil {
+		s.Log.V(logf.DebugLevel).Info("failed to authenticate workload", "error", err.Error())
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	log := s.Log.WithValues("identity", identity)
+
+	csr, err := pki.DecodeX509CertificateRequestBytes([]byte(req.CSR))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate signing request signature: %v", err)
+	}
+	if err := validateIdentity(csr, identity); err != nil {
+		log.V(logf.InfoLevel).Info("rejecting certificate signing request", "reason", err.Error())
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	duration := s.MaxDuration
+	if requested := time.Duration(req.ValidityDuration) * time.Second; requested > 0 && requested < duration {
+		duration = requested
+	}
+
+	cr, err := s.CMClient.CertmanagerV1().CertificateRequests(s.Namespace).Create(ctx, &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "istio-csr-",
+			Annotations:  map[string]string{IdentityAnnotationKey: identity},
+		},
+		Spec: cmapi.CertificateRequestSpec{
+			Request:   []byte(req.CSR),
+			Duration:  &metav1.Duration{Duration: duration},
+			IssuerRef: s.IssuerRef,
+			Usages: []cmapi.KeyUsage{
+				cmapi.UsageDigitalSignature,
+				cmapi.UsageKeyEncipherment,
+				cmapi.UsageServerAuth,
+				cmapi.UsageClientAuth,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		log.Error(err, "failed to create CertificateRequest")
+		return nil, status.Error(codes.Internal, "failed to create CertificateRequest")
+	}
+	log = log.WithValues("certificaterequest", cr.Name)
+	log.V(logf.DebugLevel).Info("created CertificateRequest")
+
+	if !s.PreserveCertificateRequests {
+		defer func() {
+			// The request context may already be cancelled, so clean up
+			// using a context of its own.
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := s.CMClient.CertmanagerV1().CertificateRequests(cr.Namespace).Delete(ctx, cr.Name, metav1.DeleteOptions{}); err != nil {
+				log.Error(err, "failed to delete CertificateRequest")
+			}
+		}()
+	}
+
+	signed, err := s.waitForCertificateRequest(ctx, cr)
+	if err != nil {
+		log.V(logf.InfoLevel).Info("failed to sign certificate", "reason", err.Error())
+		return nil, err
+	}
+
+	chain, err := certificateChain(signed)
+	if err != nil {
+		log.Error(err, "failed to encode signed certificate chain")
+		return nil, status.Error(codes.Internal, "failed to encode signed certificate chain")
+	}
+
+	log.V(logf.InfoLevel).Info("signed workload certificate")
+	return &CertificateResponse{CertChain: chain}, nil
+}
+
+// authenticate returns the SPIFFE identity of the service account whose
+// token was presented as a bearer token in the request metadata.
+func (s *Server) authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no request metadata")
+	}
+	var token string
+	for _, value := range md.Get("authorization") {
+		if strings.HasPrefix(value, "Bearer ") {
+			token = strings.TrimPrefix(value, "Bearer ")
+			break
+		}
+	}
+	if len(token) == 0 {
+		return "", fmt.Errorf("no bearer token in request")
+	}
+
+	review, err := s.KubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: s.Audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", fmt.Errorf("token is not authenticated: %s", review.Status.Error)
+	}
+
+	// Service account usernames are of the form
+	// system:serviceaccount:<namespace>:<name>.
+	parts := strings.Split(review.Status.User.Username, ":")
+	if len(parts) != 4 || parts[0] != "system" || parts[1] != "serviceaccount" || len(parts[2]) == 0 || len(parts[3]) == 0 {
+		return "", fmt.Errorf("token does not belong to a service account")
+	}
+
+	return spiffe.WorkloadID(s.TrustDomain, parts[2], parts[3]), nil
+}
+
+// validateIdentity ensures that the given certificate signing request is
+// only for the given SPIFFE identity. The subject must be empty, since the
+// issued certificate would otherwise carry names, such as a common name or
+// organization, that were chosen by the workload rather than derived from its
+// identity.
+func validateIdentity(csr *x509.CertificateRequest, identity string) error {
+	if len(csr.Subject.Names) > 0 {
+		return fmt.Errorf("certificate signing request must have an empty subject")
+	}
+	if len(csr.DNSNames) > 0 || len(csr.IPAddresses) > 0 || len(csr.EmailAddresses) > 0 {
+		return fmt.Errorf("certificate signing request must only contain a URI SAN")
+	}
+	if len(csr.URIs) != 1 || csr.URIs[0].String() != identity {
+		return fmt.Errorf("certificate signing request must contain exactly one URI SAN of %q", identity)
+	}
+	return nil
+}
+
+// waitForCertificateRequest waits for the given CertificateRequest to be
+// signed, returning an error if it fails, is denied or the context is
+// cancelled.
+func (s *Server) waitForCertificateRequest(ctx context.Context, cr *cmapi.CertificateRequest) (*cmapi.CertificateRequest, error) {
+	interval := s.pollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	var signed *cmapi.CertificateRequest
+	var failure error
+	err := wait.PollImmediateUntil(interval, func() (bool, error) {
+		cr, err := s.CMClient.CertmanagerV1().CertificateRequests(cr.Namespace).Get(ctx, cr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case apiutil.CertificateRequestIsDenied(cr):
+			failure = status.Error(codes.PermissionDenied, "CertificateRequest was denied")
+			return true, nil
+		case apiutil.CertificateRequestHasInvalidRequest(cr):
+			failure = status.Errorf(codes.InvalidArgument, "CertificateRequest is invalid: %s", apiutil.CertificateRequestInvalidRequestMessage(cr))
+			return true, nil
+		case apiutil.CertificateRequestReadyReason(cr) == cmapi.CertificateRequestReasonFailed:
+			failure = status.Error(codes.Internal, "issuer failed to sign the CertificateRequest")
+			return true, nil
+		case apiutil.CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}) && len(cr.Status.Certificate) > 0:
+			signed = cr
+			return true, nil
+		}
+		return false, nil
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		return nil, status.Error(codes.DeadlineExceeded, "timed out waiting for the CertificateRequest to be signed")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get CertificateRequest: %v", err)
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	return signed, nil
+}
+
+// certificateChain returns the PEM encoded certificates of the signed
+// CertificateRequest, followed by its CA certificate if it is not already the
+// last certificate in the chain.
+func certificateChain(cr *cmapi.CertificateRequest) ([]string, error) {
+	certs, err := pki.DecodeX509CertificateChainBytes(cr.Status.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	if len(cr.Status.CA) > 0 {
+		ca, err := pki.DecodeX509CertificateBytes(cr.Status.CA)
+		if err != nil {
+			return nil, err
+		}
+		if !ca.Equal(certs[len(certs)-1]) {
+			certs = append(certs, ca)
+		}
+	}
+
+	chain := make([]string, 0, len(certs))
+	for _, cert := range certs {
+		certPEM, err := pki.EncodeX509(cert)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, string(certPEM))
+	}
+	return chain, nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioca
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authnv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const workloadIdentity = "spiffe://cluster.local/ns/sandbox/sa/httpbin"
+
+func mustCreateCA(t *testing.T) (*x509.Certificate, crypto.Signer) {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mesh-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		PublicKey:             key.Public(),
+	}
+	_, cert, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func mustCreateCSR(t *testing.T, subject pkix.Name, uris []string, dnsNames []string) string {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.CertificateRequest{Subject: subject, DNSNames: dnsNames}
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template.URIs = append(template.URIs, u)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestCreateCertificate(t *testing.T) {
+	caCert, caKey := mustCreateCA(t)
+	caPEM, err := pki.EncodeX509(caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		token            string
+		csr              string
+		validityDuration int64
+		deny             bool
+		preserve         bool
+
+		expCode     codes.Code
+		expDuration time.Duration
+	}{
+		"should reject requests without a token": {
+			csr:     mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, nil),
+			expCode: codes.Unauthenticated,
+		},
+		"should reject requests with an invalid token": {
+			token:   "invalid",
+			csr:     mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, nil),
+			expCode: codes.Unauthenticated,
+		},
+		"should reject requests with a token that does not belong to a service account": {
+			token:   "node",
+			csr:     mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, nil),
+			expCode: codes.Unauthenticated,
+		},
+		"should reject an invalid certificate signing request": {
+			token:   "httpbin",
+			csr:     "not a csr",
+			expCode: codes.InvalidArgument,
+		},
+		"should reject a certificate signing request for another identity": {
+			token:   "httpbin",
+			csr:     mustCreateCSR(t, pkix.Name{}, []string{"spiffe://cluster.local/ns/sandbox/sa/other"}, nil),
+			expCode: codes.PermissionDenied,
+		},
+		"should reject a certificate signing request with DNS names": {
+			token:   "httpbin",
+			csr:     mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, []string{"example.com"}),
+			expCode: codes.PermissionDenied,
+		},
+		"should reject a certificate signing request with a subject": {
+			token:   "httpbin",
+			csr:     mustCreateCSR(t, pkix.Name{CommonName: "admin", Organization: []string{"system:masters"}}, []string{workloadIdentity}, nil),
+			expCode: codes.PermissionDenied,
+		},
+		"should return an error if the CertificateRequest is denied": {
+			token:   "httpbin",
+			csr:     mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, nil),
+			deny:    true,
+			expCode: codes.PermissionDenied,
+		},
+		"should sign a certificate for the workload's identity": {
+			token:            "httpbin",
+			csr:              mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, nil),
+			validityDuration: 3600,
+			expCode:          codes.OK,
+			expDuration:      time.Hour,
+		},
+		"should cap the duration of certificates and preserve the CertificateRequest if requested": {
+			token:            "httpbin",
+			csr:              mustCreateCSR(t, pkix.Name{}, []string{workloadIdentity}, nil),
+			validityDuration: 7 * 24 * 3600,
+			preserve:         true,
+			expCode:          codes.OK,
+			expDuration:      24 * time.Hour,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "tokenreviews", func(action coretesting.Action) (bool, runtime.Object, error) {
+				review := action.(coretesting.CreateAction).GetObject().(*authnv1.TokenReview)
+				switch review.Spec.Token {
+				case "httpbin":
+					review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: "system:serviceaccount:sandbox:httpbin"}}
+				case "node":
+					review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: "system:node:worker"}}
+				default:
+					review.Status = authnv1.TokenReviewStatus{Error: "invalid token"}
+				}
+				return true, review, nil
+			})
+
+			var created *cmapi.CertificateRequest
+			cmClient := cmfake.NewSimpleClientset()
+			cmClient.PrependReactor("create", "certificaterequests", func(action coretesting.Action) (bool, runtime.Object, error) {
+				// Act as the issuer, modifying the object stored by the
+				// default reactor.
+				cr := action.(coretesting.CreateAction).GetObject().(*cmapi.CertificateRequest)
+				cr.Name = cr.GenerateName + "1"
+				created = cr.DeepCopy()
+				if test.deny {
+					apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionDenied, cmmeta.ConditionTrue, "Denied", "denied")
+					return false, nil, nil
+				}
+				template, err := pki.GenerateTemplateFromCertificateRequest(cr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				certPEM, _, err := pki.SignCertificate(template, caCert, template.PublicKey, caKey)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cr.Status.Certificate = certPEM
+				cr.Status.CA = caPEM
+				apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady, cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "issued")
+				return false, nil, nil
+			})
+
+			s := &Server{
+				KubeClient:                  kubeClient,
+				CMClient:                    cmClient,
+				Namespace:                   "istio-system",
+				IssuerRef:                   cmmeta.ObjectReference{Name: "mesh-ca", Kind: "Issuer"},
+				TrustDomain:                 "cluster.local",
+				MaxDuration:                 24 * time.Hour,
+				PreserveCertificateRequests: test.preserve,
+				Log:                         logf.Log,
+				pollInterval:                time.Millisecond,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if test.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+test.token))
+			}
+
+			resp, err := s.CreateCertificate(ctx, &CertificateRequest{CSR: test.csr, ValidityDuration: test.validityDuration})
+			if code := status.Code(err); code != test.expCode {
+				t.Fatalf("unexpected status code, exp=%s got=%s (%v)", test.expCode, code, err)
+			}
+
+			if created != nil {
+				_, err := cmClient.CertmanagerV1().CertificateRequests("istio-system").Get(context.TODO(), created.Name, metav1.GetOptions{})
+				if test.preserve != (err == nil) || (err != nil && !apierrors.IsNotFound(err)) {
+					t.Errorf("unexpected error getting CertificateRequest, preserve=%t got=%v", test.preserve, err)
+				}
+			}
+			if test.expCode != codes.OK {
+				return
+			}
+
+			if created.Annotations[IdentityAnnotationKey] != workloadIdentity {
+				t.Errorf("unexpected identity annotation %q", created.Annotations[IdentityAnnotationKey])
+			}
+			if created.Spec.Duration.Duration != test.expDuration {
+				t.Errorf("unexpected duration, exp=%s got=%s", test.expDuration, created.Spec.Duration.Duration)
+			}
+			if len(resp.CertChain) != 2 {
+				t.Fatalf("expected a chain of 2 certificates, got %d", len(resp.CertChain))
+			}
+			leaf, err := pki.DecodeX509CertificateBytes([]byte(resp.CertChain[0]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(leaf.URIs) != 1 || leaf.URIs[0].String() != workloadIdentity {
+				t.Errorf("unexpected leaf certificate URIs %v", leaf.URIs)
+			}
+			if resp.CertChain[1] != string(caPEM) {
+				t.Errorf("expected the CA certificate to be last in the chain")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioca
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// certificateService is the IstioCertificateService defined in Istio's
+// security/v1alpha1/ca.proto.
+type certificateService interface {
+	CreateCertificate(context.Context, *CertificateRequest) (*CertificateResponse, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "istio.v1.auth.IstioCertificateService",
+	HandlerType: (*certificateService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCertificate",
+			Handler:    createCertificateHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "security/v1alpha1/ca.proto",
+}
+
+func createCertificateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(certificateService).CreateCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/istio.v1.auth.IstioCertificateService/CreateCertificate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(certificateService).CreateCertificate(ctx, req.(*CertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NewGRPCServer returns a gRPC server that serves the Istio CA service using
+// the given Server.
+func NewGRPCServer(s *Server, opts ...grpc.ServerOption) *grpc.Server {
+	gs := grpc.NewServer(append(opts, grpc.ForceServerCodec(codec{}))...)
+	gs.RegisterService(&serviceDesc, s)
+	return gs
+}
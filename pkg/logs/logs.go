@@ -21,9 +21,13 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -51,6 +55,63 @@ const (
 
 var logFlushFreq = flag.Duration("log-flush-frequency", 5*time.Second, "Maximum number of seconds between log flushes")
 
+// Supported values of the --logging-format flag.
+const (
+	// TextFormat writes logs in the klog text format.
+	TextFormat = "text"
+	// JSONFormat writes each log line as a JSON object, with the key/value
+	// pairs of the logger as fields.
+	JSONFormat = "json"
+)
+
+// loggingFormat is the value of the --logging-format flag. Setting it replaces
+// Log, and routes the output of klog through the new logger so that logs
+// written by client libraries use the same format.
+type loggingFormat string
+
+func (f *loggingFormat) String() string {
+	return string(*f)
+}
+
+func (f *loggingFormat) Set(value string) error {
+	switch value {
+	case TextFormat:
+		Log = klogr.New().WithName("cert-manager")
+		klog.SetLogger(nil)
+	case JSONFormat:
+		Log = newJSONLogger().WithName("cert-manager")
+		klog.SetLogger(newJSONLogger())
+	default:
+		return fmt.Errorf("unsupported logging format %q, must be one of %q or %q", value, TextFormat, JSONFormat)
+	}
+	*f = loggingFormat(value)
+	return nil
+}
+
+// newJSONLogger returns a logger writing JSON to stderr. Its verbosity is
+// controlled by the klog -v and -vmodule flags, as for the text format.
+func newJSONLogger() logr.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "ts"
+	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	encoderConfig.EncodeLevel = func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		// logr verbosity levels map to negative zap levels, which are all
+		// info messages, as in klog.
+		if level < zapcore.InfoLevel {
+			level = zapcore.InfoLevel
+		}
+		zapcore.LowercaseLevelEncoder(level, enc)
+	}
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderConfig),
+		zapcore.Lock(os.Stderr),
+		zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+			return level >= zapcore.InfoLevel || klog.V(klog.Level(-level)).Enabled()
+		}),
+	)
+	return zapr.NewLogger(zap.New(core, zap.AddCaller()))
+}
+
 // GlogWriter serves as a bridge between the standard log package and the glog package.
 type GlogWriter struct{}
 
@@ -68,6 +129,9 @@ func InitLogs(fs *flag.FlagSet) {
 	klog.InitFlags(fs)
 	_ = fs.Set("logtostderr", "true")
 
+	format := loggingFormat(TextFormat)
+	fs.Var(&format, "logging-format", fmt.Sprintf("The format of log output, one of %q or %q.", TextFormat, JSONFormat))
+
 	log.SetOutput(GlogWriter{})
 	log.SetFlags(0)
 
@@ -84,12 +148,20 @@ const (
 	ResourceNameKey      = "resource_name"
 	ResourceNamespaceKey = "resource_namespace"
 	ResourceKindKey      = "resource_kind"
-	ResourceVersionKey   = "resource_version"
-
-	RelatedResourceNameKey      = "related_resource_name"
-	RelatedResourceNamespaceKey = "related_resource_namespace"
-	RelatedResourceKindKey      = "related_resource_kind"
-	RelatedResourceVersionKey   = "related_resource_version"
+	// ResourceVersionKey is the API version of the resource, and
+	// ResourceObjectVersionKey its metadata.resourceVersion.
+	ResourceVersionKey       = "resource_version"
+	ResourceObjectVersionKey = "resource_object_version"
+
+	RelatedResourceNameKey          = "related_resource_name"
+	RelatedResourceNamespaceKey     = "related_resource_namespace"
+	RelatedResourceKindKey          = "related_resource_kind"
+	RelatedResourceVersionKey       = "related_resource_version"
+	RelatedResourceObjectVersionKey = "related_resource_object_version"
+
+	// ReconcileIDKey identifies a single sync of a resource by a controller,
+	// so that the log lines of concurrent syncs can be told apart.
+	ReconcileIDKey = "reconcile_id"
 )
 
 func WithResource(l logr.Logger, obj metav1.Object) logr.Logger {
@@ -107,6 +179,7 @@ func WithResource(l logr.Logger, obj metav1.Object) logr.Logger {
 		ResourceNamespaceKey, obj.GetNamespace(),
 		ResourceKindKey, gvk.Kind,
 		ResourceVersionKey, gvk.Version,
+		ResourceObjectVersionKey, obj.GetResourceVersion(),
 	)
 }
 
@@ -125,6 +198,7 @@ func WithRelatedResource(l logr.Logger, obj metav1.Object) logr.Logger {
 		RelatedResourceNamespaceKey, obj.GetNamespace(),
 		RelatedResourceKindKey, gvk.Kind,
 		RelatedResourceVersionKey, gvk.Version,
+		RelatedResourceObjectVersionKey, obj.GetResourceVersion(),
 	)
 }
 
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"k8s.io/klog/v2/klogr"
+)
+
+func TestLoggingFormatSet(t *testing.T) {
+	defer func() {
+		format := loggingFormat(TextFormat)
+		_ = format.Set(TextFormat)
+	}()
+
+	tests := map[string]struct {
+		value      string
+		expErr     bool
+		expJSONLog bool
+	}{
+		"text format uses klog": {
+			value: TextFormat,
+		},
+		"json format uses zap": {
+			value:      JSONFormat,
+			expJSONLog: true,
+		},
+		"if unknown format, error": {
+			value:  "yaml",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			format := loggingFormat(TextFormat)
+			Log = klogr.New()
+
+			err := format.Set(test.value)
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if err != nil {
+				if format.String() != TextFormat {
+					t.Errorf("expected format to be unchanged, got %q", format.String())
+				}
+				return
+			}
+
+			if format.String() != test.value {
+				t.Errorf("expected format %q, got %q", test.value, format.String())
+			}
+			if _, isJSON := Log.(zapr.Underlier); isJSON != test.expJSONLog {
+				t.Errorf("expected JSON logger=%t, got %T", test.expJSONLog, Log)
+			}
+		})
+	}
+}
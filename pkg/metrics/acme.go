@@ -19,15 +19,89 @@ limitations under the License.
 // certificate_expiration_timestamp_seconds{name, namespace}
 // certificate_renewal_timestamp_seconds{name, namespace}
 // certificate_ready_status{name, namespace, condition}
+// certificate_issuance_duration_seconds{issuer_kind, issuer_group, issuer_name, issuer_namespace}
+// certificaterequest_issuance_duration_seconds{issuer_kind, issuer_group, result}
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// acme_order_created_count{issuer_kind, issuer_name, issuer_namespace, server}
+// acme_order_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, state}
+// acme_challenge_self_check_count{issuer_kind, issuer_name, issuer_namespace, server, type, result}
+// acme_challenge_propagation_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, type}
+// acme_error_count{issuer_kind, issuer_name, issuer_namespace, server, problem_type}
 // controller_sync_call_count{"controller"}
 package metrics
 
 import (
+	"errors"
+	"net/url"
+	"strings"
 	"time"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// Results of the self check of an ACME Challenge, used as the value of the
+// result label of acme_challenge_self_check_count.
+const (
+	SelfCheckResultPassed = "passed"
+	SelfCheckResultFailed = "failed"
 )
 
+// acmeProblemTypePrefix is the prefix of the problem types defined by RFC
+// 8555, which is removed from the problem_type label of acme_error_count.
+const acmeProblemTypePrefix = "urn:ietf:params:acme:error:"
+
+// ACMEIssuer identifies the issuer and the ACME server that an ACME metric is
+// recorded for.
+type ACMEIssuer struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// Server is the host of the ACME server.
+	Server string
+}
+
+// ACMEIssuerFor returns the ACMEIssuer for requests made to the ACME server
+// of the given issuer or, if preflight is true, to its preflight server.
+func ACMEIssuerFor(iss cmapi.GenericIssuer, preflight bool) ACMEIssuer {
+	kind := cmapi.IssuerKind
+	if _, ok := iss.(*cmapi.ClusterIssuer); ok {
+		kind = cmapi.ClusterIssuerKind
+	}
+
+	var server string
+	if acme := iss.GetSpec().ACME; acme != nil {
+		server = acme.Server
+		if preflight && acme.Preflight != nil {
+			server = acme.Preflight.Server
+		}
+	}
+	if u, err := url.Parse(server); err == nil {
+		server = u.Host
+	}
+
+	return ACMEIssuer{
+		Kind:      kind,
+		Name:      iss.GetObjectMeta().Name,
+		Namespace: iss.GetObjectMeta().Namespace,
+		Server:    server,
+	}
+}
+
+// acmeLabelNames returns the names of the labels identifying the ACMEIssuer
+// that an ACME metric is recorded for, followed by the given names.
+func acmeLabelNames(names ...string) []string {
+	return append([]string{"issuer_kind", "issuer_name", "issuer_namespace", "server"}, names...)
+}
+
+// labelValues returns the values of the labels named by acmeLabelNames.
+func (i ACMEIssuer) labelValues(values ...string) []string {
+	return append([]string{i.Kind, i.Name, i.Namespace, i.Server}, values...)
+}
+
 // ObserveACMERequestDuration increases bucket counters for that ACME client duration.
 func (m *Metrics) ObserveACMERequestDuration(duration time.Duration, labels ...string) {
 	m.acmeClientRequestDurationSeconds.WithLabelValues(labels...).Observe(duration.Seconds())
@@ -37,3 +111,54 @@ func (m *Metrics) ObserveACMERequestDuration(duration time.Duration, labels ...s
 func (m *Metrics) IncrementACMERequestCount(labels ...string) {
 	m.acmeClientRequestCount.WithLabelValues(labels...).Inc()
 }
+
+// IncrementACMEOrderCreatedCount increases the counter of orders created with
+// the ACME server.
+func (m *Metrics) IncrementACMEOrderCreatedCount(iss ACMEIssuer) {
+	m.acmeOrderCreatedCount.WithLabelValues(iss.labelValues()...).Inc()
+}
+
+// ObserveACMEOrderDuration records the time taken for the given Order to
+// reach its current, final, state, measured from its creation.
+func (m *Metrics) ObserveACMEOrderDuration(iss ACMEIssuer, o *cmacme.Order) {
+	m.acmeOrderDurationSeconds.WithLabelValues(iss.labelValues(string(o.Status.State))...).
+		Observe(m.secondsSince(o.CreationTimestamp.Time))
+}
+
+// IncrementACMEChallengeSelfCheckCount increases the counter of self checks
+// made for Challenges of the given type, by result.
+func (m *Metrics) IncrementACMEChallengeSelfCheckCount(iss ACMEIssuer, ch *cmacme.Challenge, result string) {
+	m.acmeChallengeSelfCheckCount.WithLabelValues(iss.labelValues(string(ch.Spec.Type), result)...).Inc()
+}
+
+// ObserveACMEChallengePropagationDuration records the time taken for the
+// given Challenge to pass its self check, measured from its creation.
+func (m *Metrics) ObserveACMEChallengePropagationDuration(iss ACMEIssuer, ch *cmacme.Challenge) {
+	m.acmeChallengePropagationDurationSeconds.WithLabelValues(iss.labelValues(string(ch.Spec.Type))...).
+		Observe(m.secondsSince(ch.CreationTimestamp.Time))
+}
+
+// IncrementACMEErrorCount increases the counter of errors returned by the
+// ACME server, by the problem type of the error. Errors that were not
+// returned by the ACME server, such as network errors, are not counted.
+func (m *Metrics) IncrementACMEErrorCount(iss ACMEIssuer, err error) {
+	var acmeErr *acmeapi.Error
+	if !errors.As(err, &acmeErr) {
+		return
+	}
+	problemType := strings.TrimPrefix(acmeErr.ProblemType, acmeProblemTypePrefix)
+	if problemType == "" {
+		problemType = "unknown"
+	}
+	m.acmeErrorCount.WithLabelValues(iss.labelValues(problemType)...).Inc()
+}
+
+// secondsSince returns the number of seconds elapsed since the given time,
+// or zero if it is in the future.
+func (m *Metrics) secondsSince(t time.Time) float64 {
+	seconds := m.clock.Since(t).Seconds()
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
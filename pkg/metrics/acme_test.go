@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	acmeapi "golang.org/x/crypto/acme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestACMEIssuerFor(t *testing.T) {
+	acmeIssuer := cmacme.ACMEIssuer{
+		Server:    "https://acme.example.com/directory",
+		Preflight: &cmacme.ACMEIssuerPreflight{Server: "https://staging.example.com/directory"},
+	}
+
+	tests := map[string]struct {
+		issuer    cmapi.GenericIssuer
+		preflight bool
+		exp       ACMEIssuer
+	}{
+		"should identify an Issuer and its ACME server": {
+			issuer: gen.Issuer("test", gen.SetIssuerNamespace("test-ns"), gen.SetIssuerACME(acmeIssuer)),
+			exp:    ACMEIssuer{Kind: "Issuer", Name: "test", Namespace: "test-ns", Server: "acme.example.com"},
+		},
+		"should identify a ClusterIssuer": {
+			issuer: gen.ClusterIssuer("test", gen.SetIssuerACME(acmeIssuer)),
+			exp:    ACMEIssuer{Kind: "ClusterIssuer", Name: "test", Server: "acme.example.com"},
+		},
+		"should use the preflight server for preflight requests": {
+			issuer:    gen.Issuer("test", gen.SetIssuerNamespace("test-ns"), gen.SetIssuerACME(acmeIssuer)),
+			preflight: true,
+			exp:       ACMEIssuer{Kind: "Issuer", Name: "test", Namespace: "test-ns", Server: "staging.example.com"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ACMEIssuerFor(test.issuer, test.preflight); got != test.exp {
+				t.Errorf("unexpected ACMEIssuer, exp=%+v got=%+v", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestIncrementACMEErrorCount(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t}, fixedClock)
+	iss := ACMEIssuer{Kind: "Issuer", Name: "test", Namespace: "test-ns", Server: "acme.example.com"}
+
+	m.IncrementACMEErrorCount(iss, &acmeapi.Error{StatusCode: 429, ProblemType: "urn:ietf:params:acme:error:rateLimited"})
+	m.IncrementACMEErrorCount(iss, fmt.Errorf("error creating new order: %w", &acmeapi.Error{StatusCode: 429, ProblemType: "urn:ietf:params:acme:error:rateLimited"}))
+	m.IncrementACMEErrorCount(iss, &acmeapi.Error{StatusCode: 500})
+	m.IncrementACMEErrorCount(iss, errors.New("connection refused"))
+
+	if got := testutil.ToFloat64(m.acmeErrorCount.WithLabelValues("Issuer", "test", "test-ns", "acme.example.com", "rateLimited")); got != 2 {
+		t.Errorf("unexpected rateLimited error count, exp=2 got=%v", got)
+	}
+	if got := testutil.ToFloat64(m.acmeErrorCount.WithLabelValues("Issuer", "test", "test-ns", "acme.example.com", "unknown")); got != 1 {
+		t.Errorf("unexpected unknown error count, exp=1 got=%v", got)
+	}
+	if got := testutil.CollectAndCount(m.acmeErrorCount); got != 2 {
+		t.Errorf("unexpected number of series, exp=2 got=%d", got)
+	}
+}
+
+func TestObserveACMEDurations(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t}, fixedClock)
+	iss := ACMEIssuer{Kind: "ClusterIssuer", Name: "test", Server: "acme.example.com"}
+
+	o := &cmacme.Order{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(fixedClock.Now().Add(-time.Minute))},
+		Status:     cmacme.OrderStatus{State: cmacme.Valid},
+	}
+	m.ObserveACMEOrderDuration(iss, o)
+
+	ch := &cmacme.Challenge{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(fixedClock.Now().Add(-30 * time.Second))},
+		Spec:       cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeDNS01},
+	}
+	m.IncrementACMEChallengeSelfCheckCount(iss, ch, SelfCheckResultFailed)
+	m.IncrementACMEChallengeSelfCheckCount(iss, ch, SelfCheckResultPassed)
+	m.ObserveACMEChallengePropagationDuration(iss, ch)
+
+	if got := testutil.ToFloat64(m.acmeChallengeSelfCheckCount.WithLabelValues("ClusterIssuer", "test", "", "acme.example.com", "DNS-01", "failed")); got != 1 {
+		t.Errorf("unexpected failed self check count, exp=1 got=%v", got)
+	}
+
+	if got := testutil.CollectAndCount(m.acmeOrderDurationSeconds); got != 1 {
+		t.Errorf("unexpected number of order duration series, exp=1 got=%d", got)
+	}
+	if err := testutil.CollectAndCompare(m.acmeChallengePropagationDurationSeconds, strings.NewReader(`
+	# HELP certmanager_acme_challenge_propagation_duration_seconds The time taken for ACME Challenges to pass their self check, measured from their creation.
+	# TYPE certmanager_acme_challenge_propagation_duration_seconds histogram
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="1"} 0
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="2"} 0
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="4"} 0
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="8"} 0
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="16"} 0
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="32"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="64"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="128"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="256"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="512"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="1024"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="2048"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_bucket{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01",le="+Inf"} 1
+	certmanager_acme_challenge_propagation_duration_seconds_sum{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01"} 30
+	certmanager_acme_challenge_propagation_duration_seconds_count{issuer_kind="ClusterIssuer",issuer_name="test",issuer_namespace="",server="acme.example.com",type="DNS-01"} 1
+`), "certmanager_acme_challenge_propagation_duration_seconds"); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+}
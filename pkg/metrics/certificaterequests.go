@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains global structures related to metrics collection
+// cert-manager exposes the following metrics:
+// certificate_expiration_timestamp_seconds{name, namespace}
+// certificate_renewal_timestamp_seconds{name, namespace}
+// certificate_ready_status{name, namespace, condition}
+// certificate_issuance_duration_seconds{issuer_kind, issuer_group, issuer_name, issuer_namespace}
+// certificaterequest_issuance_duration_seconds{issuer_kind, issuer_group, result}
+// acme_client_request_count{"scheme", "host", "path", "method", "status"}
+// acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// acme_order_created_count{issuer_kind, issuer_name, issuer_namespace, server}
+// acme_order_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, state}
+// acme_challenge_self_check_count{issuer_kind, issuer_name, issuer_namespace, server, type, result}
+// acme_challenge_propagation_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, type}
+// acme_error_count{issuer_kind, issuer_name, issuer_namespace, server, problem_type}
+// controller_sync_call_count{"controller"}
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// Results of the issuance of a CertificateRequest, used as the value of the
+// result label of certificaterequest_issuance_duration_seconds.
+const (
+	IssuanceResultIssued = "issued"
+	IssuanceResultFailed = "failed"
+)
+
+// traceIDExemplarKey is the name of the exemplar label holding the ID of the
+// trace that an observation was made in.
+const traceIDExemplarKey = "trace_id"
+
+// ObserveCertificateRequestIssuance records the time taken for the given
+// CertificateRequest to reach the given result, measured from its creation.
+// If the context carries a sampled trace, its ID is attached to the
+// observation as an exemplar.
+func (m *Metrics) ObserveCertificateRequestIssuance(ctx context.Context, cr *cmapi.CertificateRequest, result string) {
+	group := cr.Spec.IssuerRef.Group
+	if group == "" {
+		group = certmanager.GroupName
+	}
+
+	observer := m.certificateRequestIssuanceDurationSeconds.WithLabelValues(apiutil.IssuerKind(cr.Spec.IssuerRef), group, result)
+	observeWithTraceExemplar(ctx, observer, m.secondsSince(cr.CreationTimestamp.Time))
+}
+
+// observeWithTraceExemplar observes the given value, attaching the ID of the
+// trace in the context as an exemplar if it has been sampled.
+func observeWithTraceExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	sc := trace.SpanContextFromContext(ctx)
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && sc.HasTraceID() && sc.IsSampled() {
+		exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{traceIDExemplarKey: sc.TraceID().String()})
+		return
+	}
+	observer.Observe(value)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestObserveCertificateRequestIssuance(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t}, fixedClock)
+
+	cr := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestNamespace("test-ns"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer"}),
+	)
+	cr.CreationTimestamp = metav1.NewTime(fixedClock.Now().Add(-3 * time.Second))
+
+	m.ObserveCertificateRequestIssuance(context.TODO(), cr, IssuanceResultIssued)
+
+	if err := testutil.CollectAndCompare(m.certificateRequestIssuanceDurationSeconds, strings.NewReader(`
+	# HELP certmanager_certificaterequest_issuance_duration_seconds The time taken for CertificateRequests to be issued or to fail, measured from their creation.
+	# TYPE certmanager_certificaterequest_issuance_duration_seconds histogram
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="0.25"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="0.5"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="1"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="2"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="4"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="8"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="16"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="32"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="64"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="128"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="256"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="512"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="1024"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="2048"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued",le="+Inf"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_sum{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued"} 3
+	certmanager_certificaterequest_issuance_duration_seconds_count{issuer_group="cert-manager.io",issuer_kind="Issuer",result="issued"} 1
+`), "certmanager_certificaterequest_issuance_duration_seconds"); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+}
+
+func TestObserveCertificateRequestIssuanceExemplar(t *testing.T) {
+	traceID := trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}
+	spanID := trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}
+
+	tests := map[string]struct {
+		flags      trace.TraceFlags
+		expTraceID string
+	}{
+		"should attach the ID of a sampled trace as an exemplar": {
+			flags:      trace.FlagsSampled,
+			expTraceID: traceID.String(),
+		},
+		"should not attach the ID of a trace that is not sampled": {},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New(logtesting.TestLogger{T: t}, fixedClock)
+			cr := gen.CertificateRequest("test-cr", gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer"}))
+			cr.CreationTimestamp = metav1.NewTime(fixedClock.Now().Add(-3 * time.Second))
+
+			ctx := trace.ContextWithSpanContext(context.TODO(), trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: test.flags,
+			}))
+			m.ObserveCertificateRequestIssuance(ctx, cr, IssuanceResultIssued)
+
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(m.certificateRequestIssuanceDurationSeconds)
+			families, err := registry.Gather()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotTraceID string
+			for _, family := range families {
+				for _, metric := range family.GetMetric() {
+					for _, bucket := range metric.GetHistogram().GetBucket() {
+						for _, label := range bucket.GetExemplar().GetLabel() {
+							if label.GetName() == traceIDExemplarKey {
+								gotTraceID = label.GetValue()
+							}
+						}
+					}
+				}
+			}
+			if gotTraceID != test.expTraceID {
+				t.Errorf("unexpected exemplar trace ID, exp=%q got=%q", test.expTraceID, gotTraceID)
+			}
+		})
+	}
+}
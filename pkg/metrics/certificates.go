@@ -19,8 +19,15 @@ limitations under the License.
 // certificate_expiration_timestamp_seconds{name, namespace}
 // certificate_renewal_timestamp_seconds{name, namespace}
 // certificate_ready_status{name, namespace, condition}
+// certificate_issuance_duration_seconds{issuer_kind, issuer_group, issuer_name, issuer_namespace}
+// certificaterequest_issuance_duration_seconds{issuer_kind, issuer_group, result}
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// acme_order_created_count{issuer_kind, issuer_name, issuer_namespace, server}
+// acme_order_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, state}
+// acme_challenge_self_check_count{issuer_kind, issuer_name, issuer_namespace, server, type, result}
+// acme_challenge_propagation_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, type}
+// acme_error_count{issuer_kind, issuer_name, issuer_namespace, server, problem_type}
 // controller_sync_call_count{"controller"}
 package metrics
 
@@ -30,6 +37,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/tools/cache"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -106,6 +115,26 @@ func (m *Metrics) updateCertificateReadyStatus(crt *cmapi.Certificate, current c
 	}
 }
 
+// ObserveCertificateIssuance records the time taken for the given Certificate
+// to be issued using the given CertificateRequest, measured from the creation
+// of the CertificateRequest. If the context carries a sampled trace, its ID is
+// attached to the observation as an exemplar.
+func (m *Metrics) ObserveCertificateIssuance(ctx context.Context, crt *cmapi.Certificate, cr *cmapi.CertificateRequest) {
+	issuerRef := cr.Spec.IssuerRef
+	group := issuerRef.Group
+	if group == "" {
+		group = certmanager.GroupName
+	}
+	kind := apiutil.IssuerKind(issuerRef)
+	namespace := crt.Namespace
+	if kind == cmapi.ClusterIssuerKind {
+		namespace = ""
+	}
+
+	observer := m.certificateIssuanceDurationSeconds.WithLabelValues(kind, group, issuerRef.Name, namespace)
+	observeWithTraceExemplar(ctx, observer, m.secondsSince(cr.CreationTimestamp.Time))
+}
+
 // RemoveCertificate will delete the Certificate metrics from continuing to be
 // exposed.
 func (m *Metrics) RemoveCertificate(key string) {
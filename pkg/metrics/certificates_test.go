@@ -324,3 +324,40 @@ func TestCertificateCache(t *testing.T) {
 		t.Errorf("unexpected collecting result:\n%s", err)
 	}
 }
+
+func TestObserveCertificateIssuance(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t}, fixedClock)
+
+	crt := gen.Certificate("test-certificate", gen.SetCertificateNamespace("test-ns"))
+	cr := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestNamespace("test-ns"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: cmapi.ClusterIssuerKind}),
+	)
+	cr.CreationTimestamp = metav1.NewTime(fixedClock.Now().Add(-42 * time.Second))
+
+	m.ObserveCertificateIssuance(context.TODO(), crt, cr)
+
+	if err := testutil.CollectAndCompare(m.certificateIssuanceDurationSeconds, strings.NewReader(`
+	# HELP certmanager_certificate_issuance_duration_seconds The time taken for Certificates to be issued, measured from the creation of their CertificateRequest until the signed certificate is stored in their Secret.
+	# TYPE certmanager_certificate_issuance_duration_seconds histogram
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="0.25"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="0.5"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="1"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="2"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="4"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="8"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="16"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="32"} 0
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="64"} 1
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="128"} 1
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="256"} 1
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="512"} 1
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="1024"} 1
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="2048"} 1
+	certmanager_certificate_issuance_duration_seconds_bucket{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace="",le="+Inf"} 1
+	certmanager_certificate_issuance_duration_seconds_sum{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace=""} 42
+	certmanager_certificate_issuance_duration_seconds_count{issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",issuer_name="test-issuer",issuer_namespace=""} 1
+`), "certmanager_certificate_issuance_duration_seconds"); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+}
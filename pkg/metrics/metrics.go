@@ -19,8 +19,15 @@ limitations under the License.
 // certificate_expiration_timestamp_seconds{name, namespace}
 // certificate_renewal_timestamp_seconds{name, namespace}
 // certificate_ready_status{name, namespace, condition}
+// certificate_issuance_duration_seconds{issuer_kind, issuer_group, issuer_name, issuer_namespace}
+// certificaterequest_issuance_duration_seconds{issuer_kind, issuer_group, result}
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// acme_order_created_count{issuer_kind, issuer_name, issuer_namespace, server}
+// acme_order_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, state}
+// acme_challenge_self_check_count{issuer_kind, issuer_name, issuer_namespace, server, type, result}
+// acme_challenge_propagation_duration_seconds{issuer_kind, issuer_name, issuer_namespace, server, type}
+// acme_error_count{issuer_kind, issuer_name, issuer_namespace, server, problem_type}
 // controller_sync_call_count{"controller"}
 package metrics
 
@@ -47,19 +54,36 @@ const (
 	prometheusMetricsServerMaxHeaderBytes = 1 << 20 // 1 MiB
 )
 
+// Names of the metrics that are referenced by the Prometheus rules in
+// rules.go, without the namespace prefix.
+const (
+	certificateExpiryTimeSecondsName              = "certificate_expiration_timestamp_seconds"
+	certificateReadyStatusName                    = "certificate_ready_status"
+	certificateRequestIssuanceDurationSecondsName = "certificaterequest_issuance_duration_seconds"
+	acmeOrderDurationSecondsName                  = "acme_order_duration_seconds"
+)
+
 // Metrics is designed to be a shared object for updating the metrics exposed
 // by cert-manager
 type Metrics struct {
 	log      logr.Logger
+	clock    clock.Clock
 	registry *prometheus.Registry
 
-	clockTimeSeconds                 prometheus.CounterFunc
-	certificateExpiryTimeSeconds     *prometheus.GaugeVec
-	certificateRenewalTimeSeconds    *prometheus.GaugeVec
-	certificateReadyStatus           *prometheus.GaugeVec
-	acmeClientRequestDurationSeconds *prometheus.SummaryVec
-	acmeClientRequestCount           *prometheus.CounterVec
-	controllerSyncCallCount          *prometheus.CounterVec
+	clockTimeSeconds                          prometheus.CounterFunc
+	certificateExpiryTimeSeconds              *prometheus.GaugeVec
+	certificateRenewalTimeSeconds             *prometheus.GaugeVec
+	certificateReadyStatus                    *prometheus.GaugeVec
+	certificateIssuanceDurationSeconds        *prometheus.HistogramVec
+	acmeClientRequestDurationSeconds          *prometheus.SummaryVec
+	certificateRequestIssuanceDurationSeconds *prometheus.HistogramVec
+	acmeClientRequestCount                    *prometheus.CounterVec
+	acmeOrderCreatedCount                     *prometheus.CounterVec
+	acmeOrderDurationSeconds                  *prometheus.HistogramVec
+	acmeChallengeSelfCheckCount               *prometheus.CounterVec
+	acmeChallengePropagationDurationSeconds   *prometheus.HistogramVec
+	acmeErrorCount                            *prometheus.CounterVec
+	controllerSyncCallCount                   *prometheus.CounterVec
 }
 
 var readyConditionStatuses = [...]cmmeta.ConditionStatus{cmmeta.ConditionTrue, cmmeta.ConditionFalse, cmmeta.ConditionUnknown}
@@ -81,7 +105,7 @@ func New(log logr.Logger, c clock.Clock) *Metrics {
 		certificateExpiryTimeSeconds = prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
-				Name:      "certificate_expiration_timestamp_seconds",
+				Name:      certificateExpiryTimeSecondsName,
 				Help:      "The date after which the certificate expires. Expressed as a Unix Epoch Time.",
 			},
 			[]string{"name", "namespace"},
@@ -99,7 +123,7 @@ func New(log logr.Logger, c clock.Clock) *Metrics {
 		certificateReadyStatus = prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
-				Name:      "certificate_ready_status",
+				Name:      certificateReadyStatusName,
 				Help:      "The ready status of the certificate.",
 			},
 			[]string{"name", "namespace", "condition"},
@@ -130,6 +154,92 @@ func New(log logr.Logger, c clock.Clock) *Metrics {
 			[]string{"scheme", "host", "path", "method", "status"},
 		)
 
+		// certificateIssuanceDurationSeconds is a Prometheus histogram of the
+		// time taken for Certificates to be issued, measured from the creation of
+		// their CertificateRequest until the signed certificate is stored in
+		// their Secret.
+		certificateIssuanceDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "certificate_issuance_duration_seconds",
+				Help:      "The time taken for Certificates to be issued, measured from the creation of their CertificateRequest until the signed certificate is stored in their Secret.",
+				Buckets:   prometheus.ExponentialBuckets(0.25, 2, 14),
+			},
+			[]string{"issuer_kind", "issuer_group", "issuer_name", "issuer_namespace"},
+		)
+
+		// certificateRequestIssuanceDurationSeconds is a Prometheus histogram of
+		// the time taken for CertificateRequests to be issued or to fail, measured
+		// from their creation.
+		certificateRequestIssuanceDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      certificateRequestIssuanceDurationSecondsName,
+				Help:      "The time taken for CertificateRequests to be issued or to fail, measured from their creation.",
+				Buckets:   prometheus.ExponentialBuckets(0.25, 2, 14),
+			},
+			[]string{"issuer_kind", "issuer_group", "result"},
+		)
+
+		// acmeOrderCreatedCount is a Prometheus counter of the number of orders
+		// created with ACME servers.
+		acmeOrderCreatedCount = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "acme_order_created_count",
+				Help:      "The number of orders created with ACME servers.",
+			},
+			acmeLabelNames(),
+		)
+
+		// acmeOrderDurationSeconds is a Prometheus histogram of the time taken
+		// for Orders to reach a final state, measured from their creation.
+		acmeOrderDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      acmeOrderDurationSecondsName,
+				Help:      "The time taken for ACME Orders to reach a final state, measured from their creation.",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+			},
+			acmeLabelNames("state"),
+		)
+
+		// acmeChallengeSelfCheckCount is a Prometheus counter of the number of
+		// self checks made to determine whether the record or resource
+		// presented for an ACME Challenge has propagated.
+		acmeChallengeSelfCheckCount = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "acme_challenge_self_check_count",
+				Help:      "The number of self checks made for ACME Challenges.",
+			},
+			acmeLabelNames("type", "result"),
+		)
+
+		// acmeChallengePropagationDurationSeconds is a Prometheus histogram of
+		// the time taken for ACME Challenges to pass their self check,
+		// measured from their creation.
+		acmeChallengePropagationDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "acme_challenge_propagation_duration_seconds",
+				Help:      "The time taken for ACME Challenges to pass their self check, measured from their creation.",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+			},
+			acmeLabelNames("type"),
+		)
+
+		// acmeErrorCount is a Prometheus counter of the number of errors
+		// returned by ACME servers, by ACME problem type.
+		acmeErrorCount = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "acme_error_count",
+				Help:      "The number of errors returned by ACME servers.",
+			},
+			acmeLabelNames("problem_type"),
+		)
+
 		controllerSyncCallCount = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
@@ -143,15 +253,23 @@ func New(log logr.Logger, c clock.Clock) *Metrics {
 	// Create server and register Prometheus metrics handler
 	m := &Metrics{
 		log:      log.WithName("metrics"),
+		clock:    c,
 		registry: prometheus.NewRegistry(),
 
-		clockTimeSeconds:                 clockTimeSeconds,
-		certificateExpiryTimeSeconds:     certificateExpiryTimeSeconds,
-		certificateRenewalTimeSeconds:    certificateRenewalTimeSeconds,
-		certificateReadyStatus:           certificateReadyStatus,
-		acmeClientRequestCount:           acmeClientRequestCount,
-		acmeClientRequestDurationSeconds: acmeClientRequestDurationSeconds,
-		controllerSyncCallCount:          controllerSyncCallCount,
+		clockTimeSeconds:                          clockTimeSeconds,
+		certificateExpiryTimeSeconds:              certificateExpiryTimeSeconds,
+		certificateRenewalTimeSeconds:             certificateRenewalTimeSeconds,
+		certificateReadyStatus:                    certificateReadyStatus,
+		certificateIssuanceDurationSeconds:        certificateIssuanceDurationSeconds,
+		acmeClientRequestCount:                    acmeClientRequestCount,
+		acmeClientRequestDurationSeconds:          acmeClientRequestDurationSeconds,
+		certificateRequestIssuanceDurationSeconds: certificateRequestIssuanceDurationSeconds,
+		acmeOrderCreatedCount:                     acmeOrderCreatedCount,
+		acmeOrderDurationSeconds:                  acmeOrderDurationSeconds,
+		acmeChallengeSelfCheckCount:               acmeChallengeSelfCheckCount,
+		acmeChallengePropagationDurationSeconds:   acmeChallengePropagationDurationSeconds,
+		acmeErrorCount:                            acmeErrorCount,
+		controllerSyncCallCount:                   controllerSyncCallCount,
 	}
 
 	return m
@@ -163,12 +281,22 @@ func (m *Metrics) NewServer(ln net.Listener) *http.Server {
 	m.registry.MustRegister(m.certificateExpiryTimeSeconds)
 	m.registry.MustRegister(m.certificateRenewalTimeSeconds)
 	m.registry.MustRegister(m.certificateReadyStatus)
+	m.registry.MustRegister(m.certificateIssuanceDurationSeconds)
 	m.registry.MustRegister(m.acmeClientRequestDurationSeconds)
 	m.registry.MustRegister(m.acmeClientRequestCount)
+	m.registry.MustRegister(m.certificateRequestIssuanceDurationSeconds)
+	m.registry.MustRegister(m.acmeOrderCreatedCount)
+	m.registry.MustRegister(m.acmeOrderDurationSeconds)
+	m.registry.MustRegister(m.acmeChallengeSelfCheckCount)
+	m.registry.MustRegister(m.acmeChallengePropagationDurationSeconds)
+	m.registry.MustRegister(m.acmeErrorCount)
 	m.registry.MustRegister(m.controllerSyncCallCount)
 
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		// Exemplars are only exposed using the OpenMetrics format.
+		EnableOpenMetrics: true,
+	}))
 
 	server := &http.Server{
 		Addr:           ln.Addr().String(),
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/yaml"
+)
+
+// IssuanceObjective is the proportion of CertificateRequests that are
+// expected to be issued rather than fail, which the error budget burn rate
+// alerts are based on.
+const IssuanceObjective = 0.99
+
+// RuleGroup is a group of Prometheus recording and alerting rules, in the
+// format used by Prometheus rule files and PrometheusRule resources.
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a Prometheus recording or alerting rule.
+type Rule struct {
+	Record      string            `json:"record,omitempty"`
+	Alert       string            `json:"alert,omitempty"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// burnRateWindow is a pair of windows over which the error budget of
+// issuance must be burning faster than the factor for an alert to fire. The
+// short window ensures the alert resolves soon after the errors stop.
+type burnRateWindow struct {
+	long, short string
+	factor      float64
+}
+
+var (
+	// pageBurnRateWindows consume 2% of a 30 day error budget in an hour, or
+	// 5% in six hours.
+	pageBurnRateWindows = []burnRateWindow{
+		{long: "1h", short: "5m", factor: 14.4},
+		{long: "6h", short: "30m", factor: 6},
+	}
+	// ticketBurnRateWindows consume 10% of a 30 day error budget in a day or
+	// three days.
+	ticketBurnRateWindows = []burnRateWindow{
+		{long: "1d", short: "2h", factor: 3},
+		{long: "3d", short: "6h", factor: 1},
+	}
+)
+
+// acmeOrderFailureRatio is the proportion of ACME orders completed by an
+// issuer over an hour that must have failed for the
+// CertManagerACMEOrdersFailing alert to fire.
+const acmeOrderFailureRatio = 0.5
+
+// certificateExpiryWarning is how long before the expiry of a Certificate
+// the CertManagerCertificateExpiringSoon alert fires.
+const certificateExpiryWarning = 7 * 24 * time.Hour
+
+func metricName(name string) string {
+	return prometheus.BuildFQName(namespace, "", name)
+}
+
+// issuanceErrorRatioRecord is the name of the recording rule of the ratio of
+// failed issuances over the given window.
+func issuanceErrorRatioRecord(window string) string {
+	return fmt.Sprintf("%s:certificaterequest_issuance_errors:ratio_rate%s", namespace, window)
+}
+
+// RuleGroups returns the recording and alerting rules for the metrics
+// exposed by cert-manager. The recording rules aggregate the metrics for use
+// by dashboards, and the alerting rules fire when issuance is failing faster
+// than IssuanceObjective allows or when Certificates are not ready.
+func RuleGroups() []RuleGroup {
+	return []RuleGroup{
+		{Name: "cert-manager.rules", Rules: recordingRules()},
+		{Name: "cert-manager.alerts", Rules: alertingRules()},
+	}
+}
+
+func recordingRules() []Rule {
+	issuance := metricName(certificateRequestIssuanceDurationSecondsName)
+
+	var rules []Rule
+	for _, window := range []string{"5m", "30m", "1h", "2h", "6h", "1d", "3d"} {
+		rules = append(rules, Rule{
+			Record: issuanceErrorRatioRecord(window),
+			Expr: fmt.Sprintf(`sum by (issuer_kind, issuer_group) (rate(%[1]s_count{result=%[2]q}[%[3]s])) / sum by (issuer_kind, issuer_group) (rate(%[1]s_count[%[3]s]))`,
+				issuance, IssuanceResultFailed, window),
+		})
+	}
+	for _, quantile := range []struct {
+		name  string
+		value float64
+	}{{"p50", 0.5}, {"p99", 0.99}} {
+		rules = append(rules, Rule{
+			Record: fmt.Sprintf("%s:certificaterequest_issuance_duration_seconds:%s_rate5m", namespace, quantile.name),
+			Expr:   fmt.Sprintf(`histogram_quantile(%g, sum by (issuer_kind, issuer_group, le) (rate(%s_bucket{result=%q}[5m])))`, quantile.value, issuance, IssuanceResultIssued),
+		})
+	}
+	rules = append(rules, Rule{
+		Record: fmt.Sprintf("%s:certificate_ready_status:sum", namespace),
+		Expr:   fmt.Sprintf(`sum by (namespace, condition) (%s)`, metricName(certificateReadyStatusName)),
+	})
+
+	return rules
+}
+
+func alertingRules() []Rule {
+	budget := 1 - IssuanceObjective
+	burnRateExpr := func(windows []burnRateWindow) string {
+		var conditions []string
+		for _, w := range windows {
+			threshold := w.factor * budget
+			conditions = append(conditions, fmt.Sprintf("(%s > %.4g and %s > %.4g)",
+				issuanceErrorRatioRecord(w.long), threshold, issuanceErrorRatioRecord(w.short), threshold))
+		}
+		return strings.Join(conditions, " or ")
+	}
+	burnRateAnnotations := map[string]string{
+		"summary": "cert-manager is failing to issue CertificateRequests",
+		"description": fmt.Sprintf("CertificateRequests for {{ $labels.issuer_kind }}s of group {{ $labels.issuer_group }} "+
+			"are failing fast enough to exhaust the error budget of the %g%% issuance objective.", IssuanceObjective*100),
+	}
+
+	return []Rule{
+		{
+			Alert:       "CertManagerIssuanceErrorBudgetBurn",
+			Expr:        burnRateExpr(pageBurnRateWindows),
+			For:         "2m",
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: burnRateAnnotations,
+		},
+		{
+			Alert:       "CertManagerIssuanceErrorBudgetBurn",
+			Expr:        burnRateExpr(ticketBurnRateWindows),
+			For:         "15m",
+			Labels:      map[string]string{"severity": "warning"},
+			Annotations: burnRateAnnotations,
+		},
+		{
+			Alert: "CertManagerACMEOrdersFailing",
+			Expr: fmt.Sprintf(`sum by (issuer_kind, issuer_name, issuer_namespace, server) (rate(%[1]s_count{state!="valid"}[1h])) / sum by (issuer_kind, issuer_name, issuer_namespace, server) (rate(%[1]s_count[1h])) > %[2]g`,
+				metricName(acmeOrderDurationSecondsName), acmeOrderFailureRatio),
+			For:    "15m",
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary": "ACME orders are failing",
+				"description": fmt.Sprintf("More than %g%% of the ACME orders placed by the {{ $labels.issuer_kind }} {{ $labels.issuer_name }} "+
+					"with {{ $labels.server }} in the last hour have failed.", acmeOrderFailureRatio*100),
+			},
+		},
+		{
+			Alert:  "CertManagerCertificateNotReady",
+			Expr:   fmt.Sprintf(`max by (name, namespace) (%s{condition!="True"}) == 1`, metricName(certificateReadyStatusName)),
+			For:    "10m",
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "A Certificate is not ready",
+				"description": "The Certificate {{ $labels.namespace }}/{{ $labels.name }} has not been ready for 10 minutes.",
+			},
+		},
+		{
+			Alert: "CertManagerCertificateExpiringSoon",
+			Expr: fmt.Sprintf(`min by (name, namespace) (%s > 0) - time() < %d`,
+				metricName(certificateExpiryTimeSecondsName), int64(certificateExpiryWarning.Seconds())),
+			For:    "1h",
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "A Certificate is about to expire",
+				"description": "The Certificate {{ $labels.namespace }}/{{ $labels.name }} expires in {{ $value | humanizeDuration }} and has not been renewed.",
+			},
+		},
+	}
+}
+
+// RulesFile returns the rules returned by RuleGroups in the format of a
+// Prometheus rule file.
+func RulesFile() ([]byte, error) {
+	return yaml.Marshal(struct {
+		Groups []RuleGroup `json:"groups"`
+	}{Groups: RuleGroups()})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"flag"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the PrometheusRule template of the Helm chart")
+
+// prometheusRuleTemplate is the template of the Helm chart that is generated
+// from the rules returned by RuleGroups.
+const prometheusRuleTemplate = "../../deploy/charts/cert-manager/templates/prometheusrule.yaml"
+
+const prometheusRuleTemplateHeader = `{{- /* This file is generated from pkg/metrics/rules.go by running: go test ./pkg/metrics -update */ -}}
+{{- if and .Values.prometheus.enabled .Values.prometheus.prometheusrule.enabled }}
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: {{ template "cert-manager.fullname" . }}
+{{- if .Values.prometheus.prometheusrule.namespace }}
+  namespace: {{ .Values.prometheus.prometheusrule.namespace }}
+{{- else }}
+  namespace: {{ .Release.Namespace | quote }}
+{{- end }}
+  labels:
+    app: {{ include "cert-manager.name" . }}
+    app.kubernetes.io/name: {{ include "cert-manager.name" . }}
+    app.kubernetes.io/instance: {{ .Release.Name }}
+    app.kubernetes.io/component: "controller"
+    {{- include "labels" . | nindent 4 }}
+    prometheus: {{ .Values.prometheus.prometheusrule.prometheusInstance }}
+    {{- with .Values.prometheus.prometheusrule.labels }}
+    {{- toYaml . | nindent 4 }}
+    {{- end }}
+spec:
+`
+
+const prometheusRuleTemplateFooter = `{{- end }}
+`
+
+// renderPrometheusRuleTemplate returns the PrometheusRule template of the
+// Helm chart. Template actions in the annotations of the rules are escaped
+// so that they are evaluated by Prometheus rather than Helm.
+func renderPrometheusRuleTemplate(t *testing.T) string {
+	rules, err := RulesFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	b.WriteString(prometheusRuleTemplateHeader)
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(string(rules), "\n"), "\n") {
+		b.WriteString("  " + strings.ReplaceAll(line, "{{", "{{`{{`}}"))
+	}
+	b.WriteString("\n")
+	b.WriteString(prometheusRuleTemplateFooter)
+	return b.String()
+}
+
+func TestPrometheusRuleTemplate(t *testing.T) {
+	exp := renderPrometheusRuleTemplate(t)
+	if *update {
+		if err := os.WriteFile(prometheusRuleTemplate, []byte(exp), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	got, err := os.ReadFile(prometheusRuleTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != exp {
+		t.Errorf("%s is out of date, run `go test ./pkg/metrics -update` to regenerate it", prometheusRuleTemplate)
+	}
+}
+
+func TestRuleGroups(t *testing.T) {
+	recordRef := regexp.MustCompile(namespace + `:[a-z_:0-9]+`)
+
+	recorded := make(map[string]bool)
+	for _, group := range RuleGroups() {
+		for _, rule := range group.Rules {
+			if rule.Record != "" {
+				recorded[rule.Record] = true
+			}
+		}
+	}
+
+	for _, group := range RuleGroups() {
+		for _, rule := range group.Rules {
+			if (rule.Record == "") == (rule.Alert == "") {
+				t.Errorf("rule in group %s must be either a recording or an alerting rule: %+v", group.Name, rule)
+			}
+			for _, ref := range recordRef.FindAllString(rule.Expr, -1) {
+				if !recorded[ref] {
+					t.Errorf("rule %s%s references %s, which is not recorded", rule.Record, rule.Alert, ref)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,448 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ocspresponder implements an OCSP responder (RFC 6960) that serves
+// the revocation status of certificates issued by CA issuers.
+package ocspresponder
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/ocsp"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/jetstack/cert-manager/internal/revocation"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	revocationlisters "github.com/jetstack/cert-manager/pkg/client/listers/revocation/v1alpha1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/kube"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// maxRequestSize is the maximum size of an OCSP request that is read.
+	maxRequestSize = 10 * 1024
+
+	requestContentType  = "application/ocsp-request"
+	responseContentType = "application/ocsp-response"
+
+	// serialNumberIndex indexes CertificateRequests by the decimal serial
+	// number of their issued certificate.
+	serialNumberIndex = "serialNumber"
+)
+
+// errUnknownIssuer is returned if a request is for a certificate that was not
+// issued by any of the known CA issuers.
+var errUnknownIssuer = errors.New("no CA issuer matches the requested issuer")
+
+// Responder is an http.Handler that answers OCSP requests for certificates
+// issued by CA Issuers and ClusterIssuers.
+//
+// A certificate is reported as revoked if a CertificateRevocation for its
+// serial number references the issuer, or if the CertificateRequest that it
+// was issued for has the `cert-manager.io/revoke: "true"` annotation. Other
+// certificates are reported as good if a CertificateRequest for the issuer
+// holds a certificate with the serial number signed by the CA, and as unknown
+// otherwise, since the responder cannot vouch for certificates it has no
+// record of. Responses are signed by the CA itself.
+type Responder struct {
+	IssuerLister     cmlisters.IssuerLister
+	RevocationLister revocationlisters.CertificateRevocationLister
+	SecretLister     corelisters.SecretLister
+
+	// CertificateRequestIndexer is the indexer of a CertificateRequest
+	// informer that the indexes of AddIndexers have been added to.
+	CertificateRequestIndexer cache.Indexer
+
+	// ClusterIssuerLister may be nil if ClusterIssuers are not served.
+	ClusterIssuerLister cmlisters.ClusterIssuerLister
+
+	// ClusterResourceNamespace is the namespace in which the CA Secrets and
+	// CertificateRevocations of ClusterIssuers are stored.
+	ClusterResourceNamespace string
+
+	// ResponseValidity is the duration for which responses are valid, and
+	// may be cached by clients.
+	ResponseValidity time.Duration
+
+	Clock clock.Clock
+	Log   logr.Logger
+
+	// caKeyPairs caches the parsed CA key pairs of CA Secrets, keyed by
+	// namespace and name, so that CA keys are only decoded when their Secret
+	// changes rather than on every request.
+	caKeyPairsLock sync.Mutex
+	caKeyPairs     map[string]*caKeyPair
+}
+
+// AddIndexers adds the indexes used by the Responder to the given
+// CertificateRequest informer. It must be called before the informer is
+// started.
+func AddIndexers(informer cache.SharedIndexInformer) error {
+	return informer.AddIndexers(cache.Indexers{serialNumberIndex: certificateRequestSerialNumberIndexFunc})
+}
+
+// certificateRequestSerialNumberIndexFunc indexes CertificateRequests by the
+// serial number of their issued certificate, so that the certificate is only
+// decoded when the CertificateRequest changes rather than on every request.
+func certificateRequestSerialNumberIndexFunc(obj interface{}) ([]string, error) {
+	cr, ok := obj.(*cmapi.CertificateRequest)
+	if !ok || len(cr.Status.Certificate) == 0 {
+		return nil, nil
+	}
+	cert, err := pki.DecodeX509CertificateBytes(cr.Status.Certificate)
+	if err != nil {
+		return nil, nil
+	}
+	return []string{cert.SerialNumber.String()}, nil
+}
+
+// caIssuer is a CA issuer together with its CA certificate and key.
+type caIssuer struct {
+	issuer cmapi.GenericIssuer
+	cert   *x509.Certificate
+	key    crypto.Signer
+}
+
+// caKeyPair is the CA certificate and key decoded from a given
+// resourceVersion of a CA Secret.
+type caKeyPair struct {
+	resourceVersion string
+
+	cert *x509.Certificate
+	key  crypto.Signer
+	// err is set if the Secret does not hold a valid key pair.
+	err error
+
+	// issuerHashes are the issuer name and key hashes of cert for each hash
+	// algorithm that an OCSP request may use.
+	issuerHashes map[crypto.Hash]issuerHashes
+}
+
+// issuerHashes are the hashes identifying a CA in an OCSP request.
+type issuerHashes struct {
+	name []byte
+	key  []byte
+}
+
+// ocspHashAlgorithms are the hash algorithms that OCSP requests may use to
+// identify the CA.
+var ocspHashAlgorithms = []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512}
+
+func (r *Responder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var der []byte
+	switch req.Method {
+	case http.MethodGet:
+		// The request is the base64 encoding of the DER request, which may
+		// additionally have been URL encoded.
+		path := strings.TrimPrefix(req.URL.Path, "/")
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+		var err error
+		der, err = base64.StdEncoding.DecodeString(path)
+		if err != nil {
+			r.writeResponse(w, ocsp.MalformedRequestErrorResponse, 0)
+			return
+		}
+	case http.MethodPost:
+		if ct := req.Header.Get("Content-Type"); ct != requestContentType {
+			http.Error(w, fmt.Sprintf("unsupported content type %q", ct), http.StatusUnsupportedMediaType)
+			return
+		}
+		var err error
+		der, err = io.ReadAll(io.LimitReader(req.Body, maxRequestSize+1))
+		if err != nil {
+			r.writeResponse(w, ocsp.MalformedRequestErrorResponse, 0)
+			return
+		}
+		if len(der) > maxRequestSize {
+			http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ocspReq, err := ocsp.ParseRequest(der)
+	if err != nil {
+		r.writeResponse(w, ocsp.MalformedRequestErrorResponse, 0)
+		return
+	}
+
+	log := r.Log.WithValues("serial", ocspReq.SerialNumber.Text(16))
+	resp, err := r.Respond(logf.NewContext(req.Context(), log), ocspReq)
+	switch {
+	case errors.Is(err, errUnknownIssuer):
+		log.V(logf.DebugLevel).Info("request for unknown issuer")
+		r.writeResponse(w, ocsp.UnauthorizedErrorResponse, 0)
+	case err != nil:
+		log.Error(err, "failed to create OCSP response")
+		r.writeResponse(w, ocsp.InternalErrorErrorResponse, 0)
+	default:
+		r.writeResponse(w, resp, r.ResponseValidity)
+	}
+}
+
+func (r *Responder) writeResponse(w http.ResponseWriter, resp []byte, maxAge time.Duration) {
+	w.Header().Set("Content-Type", responseContentType)
+	if maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d, public, no-transform, must-revalidate", int(maxAge.Seconds())))
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
+
+// Respond returns the signed DER encoded OCSP response for the given
+// request. It returns errUnknownIssuer if the request is not for a
+// certificate issued by a known CA issuer.
+func (r *Responder) Respond(ctx context.Context, req *ocsp.Request) ([]byte, error) {
+	issuers, err := r.matchingIssuers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(issuers) == 0 {
+		return nil, errUnknownIssuer
+	}
+
+	now := r.Clock.Now().UTC().Truncate(time.Second)
+	template := ocsp.Response{
+		Status:       ocsp.Unknown,
+		SerialNumber: req.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(r.ResponseValidity),
+	}
+
+	// Several issuers may share the same CA, in which case a certificate is
+	// revoked if it was revoked through any of them, and good if it was
+	// issued through any of them.
+	for _, iss := range issuers {
+		status, err := r.certificateStatus(iss, req.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case status.revoked:
+			if template.Status != ocsp.Revoked || status.entry.RevocationTime.Before(template.RevokedAt) {
+				template.Status = ocsp.Revoked
+				template.RevokedAt = status.entry.RevocationTime
+				template.RevocationReason = status.entry.Reason
+			}
+		case status.issued && template.Status == ocsp.Unknown:
+			template.Status = ocsp.Good
+		}
+	}
+
+	signer := issuers[0]
+	return ocsp.CreateResponse(signer.cert, signer.cert, template, signer.key)
+}
+
+// matchingIssuers returns all CA issuers whose CA certificate matches the
+// issuer name and key hashes of the request.
+func (r *Responder) matchingIssuers(ctx context.Context, req *ocsp.Request) ([]caIssuer, error) {
+	if !req.HashAlgorithm.Available() {
+		return nil, errUnknownIssuer
+	}
+
+	var candidates []cmapi.GenericIssuer
+	issuers, err := r.IssuerLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, iss := range issuers {
+		candidates = append(candidates, iss)
+	}
+	if r.ClusterIssuerLister != nil {
+		clusterIssuers, err := r.ClusterIssuerLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, iss := range clusterIssuers {
+			candidates = append(candidates, iss)
+		}
+	}
+
+	r.caKeyPairsLock.Lock()
+	defer r.caKeyPairsLock.Unlock()
+
+	var matching []caIssuer
+	referenced := make(map[string]bool)
+	for _, iss := range candidates {
+		ca := iss.GetSpec().CA
+		if ca == nil {
+			continue
+		}
+		namespace := iss.GetObjectMeta().Namespace
+		if _, ok := iss.(*cmapi.ClusterIssuer); ok {
+			namespace = r.ClusterResourceNamespace
+		}
+		referenced[namespace+"/"+ca.SecretName] = true
+		kp := r.caKeyPair(ctx, namespace, ca.SecretName)
+		if kp == nil || kp.err != nil {
+			// Problems with the CA Secret are reported on the issuer's
+			// Ready condition.
+			continue
+		}
+		hashes := kp.issuerHashes[req.HashAlgorithm]
+		if bytes.Equal(hashes.name, req.IssuerNameHash) && bytes.Equal(hashes.key, req.IssuerKeyHash) {
+			matching = append(matching, caIssuer{issuer: iss, cert: kp.cert, key: kp.key})
+		}
+	}
+
+	// Forget the key pairs of Secrets that are no longer used by any CA
+	// issuer.
+	for key := range r.caKeyPairs {
+		if !referenced[key] {
+			delete(r.caKeyPairs, key)
+		}
+	}
+
+	return matching, nil
+}
+
+// caKeyPair returns the decoded key pair of the given CA Secret, or nil if
+// the Secret does not exist. The key pair is only decoded again once the
+// resourceVersion of the Secret changes. caKeyPairsLock must be held.
+func (r *Responder) caKeyPair(ctx context.Context, namespace, name string) *caKeyPair {
+	secret, err := r.SecretLister.Secrets(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+
+	cacheKey := namespace + "/" + name
+	if kp, ok := r.caKeyPairs[cacheKey]; ok && kp.resourceVersion == secret.ResourceVersion {
+		return kp
+	}
+
+	kp := &caKeyPair{resourceVersion: secret.ResourceVersion}
+	certs, key, err := kube.SecretTLSKeyPair(ctx, r.SecretLister, namespace, name)
+	if err == nil {
+		kp.cert, kp.key = certs[0], key
+		kp.issuerHashes, err = computeIssuerHashes(kp.cert)
+	}
+	kp.err = err
+
+	if r.caKeyPairs == nil {
+		r.caKeyPairs = make(map[string]*caKeyPair)
+	}
+	r.caKeyPairs[cacheKey] = kp
+	return kp
+}
+
+// certificateStatus is the status of a certificate issued by a CA issuer.
+type certificateStatus struct {
+	// issued is true if a CertificateRequest for the issuer holds the
+	// certificate.
+	issued bool
+	// revoked is true if the certificate has been revoked, as recorded in
+	// entry.
+	revoked bool
+	entry   revocation.Entry
+}
+
+// certificateStatus returns whether the certificate with the given serial
+// number was issued by the given issuer, and whether it has been revoked.
+func (r *Responder) certificateStatus(iss caIssuer, serial *big.Int) (certificateStatus, error) {
+	revocations, err := revocation.ForIssuer(r.RevocationLister, iss.issuer, r.ClusterResourceNamespace)
+	if err != nil {
+		return certificateStatus{}, err
+	}
+	if entry, ok := revocations[serial.String()]; ok {
+		return certificateStatus{revoked: true, entry: entry}, nil
+	}
+
+	// CertificateRequests for ClusterIssuers, and for Issuers granted to
+	// other namespaces, may live in any namespace.
+	objs, err := r.CertificateRequestIndexer.ByIndex(serialNumberIndex, serial.String())
+	if err != nil {
+		return certificateStatus{}, err
+	}
+	var status certificateStatus
+	for _, obj := range objs {
+		cr := obj.(*cmapi.CertificateRequest)
+		if len(cr.Status.Certificate) == 0 || !revocation.ReferencesIssuer(cr, iss.issuer) {
+			continue
+		}
+		cert, err := pki.DecodeX509CertificateBytes(cr.Status.Certificate)
+		if err != nil || cert.SerialNumber.Cmp(serial) != 0 {
+			continue
+		}
+		if err := cert.CheckSignatureFrom(iss.cert); err != nil {
+			continue
+		}
+		status.issued = true
+		if cr.Annotations[cmapi.RevokeCertificateAnnotation] == "true" {
+			// The time at which the annotation was added is not recorded,
+			// so the certificate is reported as revoked since it was issued.
+			status.revoked = true
+			status.entry = revocation.Entry{
+				SerialNumber:   serial,
+				RevocationTime: cert.NotBefore.UTC(),
+				Reason:         ocsp.Unspecified,
+			}
+			return status, nil
+		}
+	}
+
+	return status, nil
+}
+
+// computeIssuerHashes returns the issuer name and key hashes that OCSP
+// requests for certificates issued by the given CA certificate contain, for
+// each of the supported hash algorithms.
+func computeIssuerHashes(caCert *x509.Certificate) (map[crypto.Hash]issuerHashes, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(caCert.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[crypto.Hash]issuerHashes, len(ocspHashAlgorithms))
+	for _, alg := range ocspHashAlgorithms {
+		h := alg.New()
+		h.Write(caCert.RawSubject)
+		nameHash := h.Sum(nil)
+
+		h.Reset()
+		h.Write(spki.PublicKey.RightAlign())
+		hashes[alg] = issuerHashes{name: nameHash, key: h.Sum(nil)}
+	}
+	return hashes, nil
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocspresponder
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	revocationapi "github.com/jetstack/cert-manager/pkg/apis/revocation/v1alpha1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func mustCreateCA(t *testing.T, cn string, now time.Time) (*x509.Certificate, crypto.Signer, *corev1.Secret) {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := pki.EncodePrivateKey(key, cmapi.PKCS8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		PublicKey:             key.Public(),
+	}
+	certPEM, cert, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: cn},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+}
+
+func mustSignLeaf(t *testing.T, serial int64, caCert *x509.Certificate, caKey crypto.Signer, now time.Time) []byte {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		PublicKey:    key.Public(),
+	}
+	certPEM, _, err := pki.SignCertificate(template, caCert, key.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
+
+func TestServeHTTP(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	revokedAt := metav1.NewTime(now.Add(-30 * time.Minute))
+
+	caCert, caKey, caSecret := mustCreateCA(t, "test-ca", now)
+	otherCACert, _, _ := mustCreateCA(t, "other-ca", now)
+
+	issuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "test-ca"}),
+	)
+	revokedCR := gen.CertificateRequest("cr",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: cmapi.IssuerKind}),
+		gen.SetCertificateRequestCertificate(mustSignLeaf(t, 10, caCert, caKey, now)),
+		gen.AddCertificateRequestAnnotations(map[string]string{cmapi.RevokeCertificateAnnotation: "true"}),
+	)
+	issuedCR := gen.CertificateRequest("issued",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: cmapi.IssuerKind}),
+		gen.SetCertificateRequestCertificate(mustSignLeaf(t, 5, caCert, caKey, now)),
+	)
+	otherIssuerCR := gen.CertificateRequest("other-issuer",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "other-issuer", Kind: cmapi.IssuerKind}),
+		gen.SetCertificateRequestCertificate(mustSignLeaf(t, 7, caCert, caKey, now)),
+	)
+	certificateRevocation := &revocationapi.CertificateRevocation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "revoked"},
+		Spec: revocationapi.CertificateRevocationSpec{
+			IssuerRef:      cmmeta.ObjectReference{Name: "ca-issuer"},
+			SerialNumber:   "20",
+			Reason:         revocationapi.RevocationReasonKeyCompromise,
+			RevocationTime: &revokedAt,
+		},
+	}
+
+	tests := map[string]struct {
+		method     string
+		serial     int64
+		issuerCert *x509.Certificate
+
+		expStatusCode int
+		// expResponseStatus is the OCSP response status if a successful
+		// response is not expected.
+		expResponseStatus ocsp.ResponseStatus
+		expStatus         int
+		expRevokedAt      time.Time
+		expReason         int
+	}{
+		"reports certificates that have not been revoked as good": {
+			method:        http.MethodPost,
+			serial:        5,
+			issuerCert:    caCert,
+			expStatusCode: http.StatusOK,
+			expStatus:     ocsp.Good,
+		},
+		"reports certificates that were never issued as unknown": {
+			method:        http.MethodPost,
+			serial:        6,
+			issuerCert:    caCert,
+			expStatusCode: http.StatusOK,
+			expStatus:     ocsp.Unknown,
+		},
+		"reports certificates issued through a different issuer as unknown": {
+			method:        http.MethodPost,
+			serial:        7,
+			issuerCert:    caCert,
+			expStatusCode: http.StatusOK,
+			expStatus:     ocsp.Unknown,
+		},
+		"reports certificates revoked by a CertificateRevocation": {
+			method:        http.MethodPost,
+			serial:        0x20,
+			issuerCert:    caCert,
+			expStatusCode: http.StatusOK,
+			expStatus:     ocsp.Revoked,
+			expRevokedAt:  revokedAt.Time,
+			expReason:     ocsp.KeyCompromise,
+		},
+		"reports certificates of annotated CertificateRequests as revoked": {
+			method:        http.MethodGet,
+			serial:        10,
+			issuerCert:    caCert,
+			expStatusCode: http.StatusOK,
+			expStatus:     ocsp.Revoked,
+			expRevokedAt:  now.Add(-time.Hour),
+			expReason:     ocsp.Unspecified,
+		},
+		"answers requests for unknown issuers as unauthorized": {
+			method:            http.MethodPost,
+			serial:            5,
+			issuerCert:        otherCACert,
+			expStatusCode:     http.StatusOK,
+			expResponseStatus: ocsp.Unauthorized,
+		},
+		"rejects other methods": {
+			method:        http.MethodPut,
+			serial:        5,
+			issuerCert:    caCert,
+			expStatusCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				KubeObjects:        []runtime.Object{caSecret},
+				CertManagerObjects: []runtime.Object{issuer, issuedCR, otherIssuerCR, revokedCR, certificateRevocation},
+			}
+			builder.Init()
+			cmInformers := builder.Context.SharedInformerFactory
+			crInformer := cmInformers.Certmanager().V1().CertificateRequests().Informer()
+			if err := AddIndexers(crInformer); err != nil {
+				t.Fatal(err)
+			}
+			r := &Responder{
+				IssuerLister:              cmInformers.Certmanager().V1().Issuers().Lister(),
+				CertificateRequestIndexer: crInformer.GetIndexer(),
+				RevocationLister:          cmInformers.Revocation().V1alpha1().CertificateRevocations().Lister(),
+				SecretLister:              builder.Context.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+				ResponseValidity:          time.Hour,
+				Clock:                     builder.Clock,
+				Log:                       logf.Log,
+			}
+			builder.Start()
+			defer builder.Stop()
+
+			reqDER, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: big.NewInt(test.serial)}, test.issuerCert, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var req *http.Request
+			if test.method == http.MethodGet {
+				req = httptest.NewRequest(test.method, "/"+base64.StdEncoding.EncodeToString(reqDER), nil)
+			} else {
+				req = httptest.NewRequest(test.method, "/", bytes.NewReader(reqDER))
+				req.Header.Set("Content-Type", requestContentType)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != test.expStatusCode {
+				t.Fatalf("unexpected status code, exp=%d got=%d", test.expStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			resp, err := ocsp.ParseResponse(rec.Body.Bytes(), caCert)
+			if test.expResponseStatus != ocsp.Success {
+				respErr, ok := err.(ocsp.ResponseError)
+				if !ok || respErr.Status != test.expResponseStatus {
+					t.Fatalf("expected response status %v, got error %v", test.expResponseStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Status != test.expStatus {
+				t.Errorf("unexpected status, exp=%d got=%d", test.expStatus, resp.Status)
+			}
+			if resp.SerialNumber.Int64() != test.serial {
+				t.Errorf("unexpected serial number %v", resp.SerialNumber)
+			}
+			if !resp.NextUpdate.Equal(now.Add(time.Hour)) {
+				t.Errorf("unexpected nextUpdate %s", resp.NextUpdate)
+			}
+			if test.expStatus == ocsp.Revoked {
+				if !resp.RevokedAt.Equal(test.expRevokedAt) {
+					t.Errorf("unexpected revocation time, exp=%s got=%s", test.expRevokedAt, resp.RevokedAt)
+				}
+				if resp.RevocationReason != test.expReason {
+					t.Errorf("unexpected revocation reason, exp=%d got=%d", test.expReason, resp.RevocationReason)
+				}
+			}
+		})
+	}
+}
+
+func TestCAKeyPairCache(t *testing.T) {
+	_, _, caSecret := mustCreateCA(t, "test-ca", time.Now())
+	caSecret.ResourceVersion = "1"
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(caSecret); err != nil {
+		t.Fatal(err)
+	}
+	r := &Responder{SecretLister: corelisters.NewSecretLister(indexer)}
+
+	kp := r.caKeyPair(context.Background(), "testns", "test-ca")
+	if kp == nil || kp.err != nil {
+		t.Fatalf("expected a valid key pair, got %+v", kp)
+	}
+	if cached := r.caKeyPair(context.Background(), "testns", "test-ca"); cached != kp {
+		t.Errorf("expected the key pair to be decoded only once for the same resourceVersion")
+	}
+
+	updated := caSecret.DeepCopy()
+	updated.ResourceVersion = "2"
+	delete(updated.Data, corev1.TLSPrivateKeyKey)
+	if err := indexer.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	if kp := r.caKeyPair(context.Background(), "testns", "test-ca"); kp == nil || kp.err == nil {
+		t.Errorf("expected the key pair to be decoded again once the resourceVersion changed, got %+v", kp)
+	}
+
+	if err := indexer.Delete(updated); err != nil {
+		t.Fatal(err)
+	}
+	if kp := r.caKeyPair(context.Background(), "testns", "test-ca"); kp != nil {
+		t.Errorf("expected no key pair for a deleted Secret, got %+v", kp)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing records OpenTelemetry traces of the issuance of
+// certificates.
+//
+// An issuance spans several controllers, each of which processes a different
+// resource: the Certificate, its CertificateRequest and, for ACME issuers, an
+// Order and its Challenges. The trace context is carried between them in
+// annotations on the resources, so that the spans recorded by each controller
+// form a single trace. The baggage of the trace holds a UID identifying the
+// issuance request, which is recorded on every span of the issuance.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+const (
+	// TraceParentAnnotationKey is the annotation holding the W3C traceparent
+	// of the span that created the annotated resource.
+	TraceParentAnnotationKey = "tracing.cert-manager.io/traceparent"
+
+	// BaggageAnnotationKey is the annotation holding the W3C baggage of the
+	// trace that the annotated resource is part of.
+	BaggageAnnotationKey = "tracing.cert-manager.io/baggage"
+)
+
+const (
+	// RequestUIDKey is the baggage member and span attribute holding the UID
+	// of the issuance request that a span is part of.
+	RequestUIDKey = attribute.Key("cert-manager.request.uid")
+
+	namespaceKey = attribute.Key("k8s.namespace.name")
+	nameKey      = attribute.Key("cert-manager.resource.name")
+)
+
+// tracerName is the name of the instrumentation library reported with spans.
+const tracerName = "github.com/jetstack/cert-manager"
+
+// propagator reads and writes the trace context and baggage held in
+// annotations, in the same format as the W3C HTTP headers.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Options configure the export of traces.
+type Options struct {
+	// Endpoint is the host and port of an OTLP gRPC collector that traces are
+	// exported to. Tracing is disabled if it is empty.
+	Endpoint string
+
+	// SamplingRatio is the fraction of issuances that are traced.
+	SamplingRatio float64
+
+	// ServiceName is the name of the component recording the traces.
+	ServiceName string
+}
+
+// Setup installs a global tracer provider that exports traces to the
+// collector configured in opts. The returned function flushes any pending
+// spans and stops the exporter. If no endpoint is configured, no provider is
+// installed and spans are discarded.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(
+		otlpgrpc.WithEndpoint(opts.Endpoint),
+		otlpgrpc.WithInsecure(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceNameKey.String(opts.ServiceName),
+		semconv.ServiceVersionKey.String(util.AppVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// StartIssuance starts the root span of a new issuance for the given
+// resource, and assigns the issuance a new request UID.
+func StartIssuance(ctx context.Context, spanName string, obj metav1.Object) (context.Context, trace.Span) {
+	ctx = baggage.ContextWithValues(ctx, RequestUIDKey.String(string(uuid.NewUUID())))
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attributesFor(ctx, obj)...))
+}
+
+// Start starts a span for processing the given resource. If the resource is
+// annotated with the context of a trace, the span is part of that trace.
+func Start(ctx context.Context, spanName string, obj metav1.Object) (context.Context, trace.Span) {
+	ctx = propagator.Extract(ctx, annotationCarrier(obj.GetAnnotations()))
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attributesFor(ctx, obj)...))
+}
+
+// Inject annotates the given resource with the context of the span in ctx, so
+// that spans recorded when processing the resource continue the same trace.
+// The resource is left unchanged if ctx does not hold a span being recorded.
+func Inject(ctx context.Context, obj metav1.Object) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	// Copy the annotations, as they may be shared with another resource.
+	annotations := make(map[string]string, len(obj.GetAnnotations())+2)
+	for k, v := range obj.GetAnnotations() {
+		annotations[k] = v
+	}
+	delete(annotations, BaggageAnnotationKey)
+	propagator.Inject(ctx, annotationCarrier(annotations))
+	obj.SetAnnotations(annotations)
+}
+
+// End records the given error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func attributesFor(ctx context.Context, obj metav1.Object) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		namespaceKey.String(obj.GetNamespace()),
+		nameKey.String(obj.GetName()),
+	}
+	if uid := baggage.Value(ctx, RequestUIDKey); uid.Type() != attribute.INVALID {
+		attrs = append(attrs, RequestUIDKey.String(uid.AsString()))
+	}
+	return attrs
+}
+
+// annotationCarrier stores the trace context in annotations rather than HTTP
+// headers.
+type annotationCarrier map[string]string
+
+var annotationKeys = map[string]string{
+	"traceparent": TraceParentAnnotationKey,
+	"baggage":     BaggageAnnotationKey,
+}
+
+func (a annotationCarrier) Get(key string) string {
+	if k, ok := annotationKeys[key]; ok {
+		return a[k]
+	}
+	return ""
+}
+
+func (a annotationCarrier) Set(key, value string) {
+	if k, ok := annotationKeys[key]; ok {
+		a[k] = value
+	}
+}
+
+func (a annotationCarrier) Keys() []string {
+	var keys []string
+	for key, k := range annotationKeys {
+		if _, ok := a[k]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
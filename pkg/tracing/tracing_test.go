@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInjectWithoutProvider(t *testing.T) {
+	otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctx, span := StartIssuance(context.Background(), "CreateCertificateRequest", &metav1.ObjectMeta{Name: "crt"})
+	defer span.End()
+
+	obj := &metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}}
+	Inject(ctx, obj)
+	if len(obj.Annotations) != 1 {
+		t.Errorf("expected annotations to be unchanged when tracing is disabled, got %v", obj.Annotations)
+	}
+}
+
+func TestIssuanceTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	crt := &metav1.ObjectMeta{Namespace: "ns", Name: "crt"}
+	ctx, crtSpan := StartIssuance(context.Background(), "CreateCertificateRequest", crt)
+	shared := map[string]string{"foo": "bar"}
+	cr := &metav1.ObjectMeta{Namespace: "ns", Name: "cr", Annotations: shared}
+	Inject(ctx, cr)
+	crtSpan.End()
+
+	if len(shared) != 1 {
+		t.Errorf("expected the original annotations not to be modified, got %v", shared)
+	}
+	if cr.Annotations["foo"] != "bar" || cr.Annotations[TraceParentAnnotationKey] == "" || cr.Annotations[BaggageAnnotationKey] == "" {
+		t.Fatalf("expected trace context to be added to the existing annotations, got %v", cr.Annotations)
+	}
+
+	ctx, crSpan := Start(context.Background(), "SignCertificateRequest", cr)
+	order := &metav1.ObjectMeta{Namespace: "ns", Name: "order", Annotations: cr.Annotations}
+	Inject(ctx, order)
+	End(crSpan, errors.New("pending"))
+
+	_, orderSpan := Start(context.Background(), "SyncOrder", order)
+	End(orderSpan, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	root, sign, sync := spans[0], spans[1], spans[2]
+	if sign.Parent.SpanID() != root.SpanContext.SpanID() || sync.Parent.SpanID() != sign.SpanContext.SpanID() {
+		t.Errorf("expected spans to be parented by the span that created their resource")
+	}
+
+	var requestUID string
+	for _, s := range spans {
+		if s.SpanContext.TraceID() != root.SpanContext.TraceID() {
+			t.Errorf("expected span %q to be part of the issuance trace", s.Name)
+		}
+		var uid string
+		for _, attr := range s.Attributes {
+			if attr.Key == RequestUIDKey {
+				uid = attr.Value.AsString()
+			}
+		}
+		if uid == "" || (requestUID != "" && uid != requestUID) {
+			t.Errorf("expected span %q to have the request UID %q, got %q", s.Name, requestUID, uid)
+		}
+		requestUID = uid
+	}
+
+	if sign.StatusCode != codes.Error {
+		t.Errorf("expected the error to be recorded on the span, got status %v", sign.StatusCode)
+	}
+}
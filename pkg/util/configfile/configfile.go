@@ -0,0 +1,326 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configfile loads the configuration files of the cert-manager
+// components, and applies them to the components' command line flags.
+package configfile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// WatchPeriod is the interval at which configuration files are checked for
+// changes.
+const WatchPeriod = 10 * time.Second
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	if err := configv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+}
+
+// Load decodes the configuration file at path into obj, and sets the default
+// values of the fields that are not set. Unknown fields, and files that do not
+// contain the kind of obj, are rejected.
+func Load(path string, obj runtime.Object) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading configuration file: %v", err)
+	}
+	return decode(path, data, obj)
+}
+
+func decode(path string, data []byte, obj runtime.Object) error {
+	kinds, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+
+	decoder := serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDeserializer()
+	_, gvk, err := decoder.Decode(data, nil, obj)
+	if err != nil {
+		return fmt.Errorf("error decoding configuration file %q: %v", path, err)
+	}
+	if *gvk != kinds[0] {
+		return fmt.Errorf("configuration file %q must contain a %s, not a %s", path, kinds[0], gvk)
+	}
+	scheme.Default(obj)
+	return nil
+}
+
+// FlagSetter sets flags to the values of a configuration file, unless they
+// were set on the command line. Errors are collected and returned by Err.
+type FlagSetter struct {
+	fs   *pflag.FlagSet
+	errs []error
+
+	// fromCommandLine records the flags that were set on the command line
+	// before any configuration file was applied.
+	fromCommandLine map[string]bool
+}
+
+// NewFlagSetter returns a FlagSetter for fs. It must be called after the
+// command line has been parsed.
+func NewFlagSetter(fs *pflag.FlagSet) *FlagSetter {
+	fromCommandLine := make(map[string]bool)
+	fs.Visit(func(f *pflag.Flag) {
+		fromCommandLine[f.Name] = true
+	})
+	return &FlagSetter{fs: fs, fromCommandLine: fromCommandLine}
+}
+
+// Set sets the named flag to value, unless it was set on the command line.
+func (s *FlagSetter) Set(name, value string) {
+	flag := s.fs.Lookup(name)
+	if flag == nil {
+		s.errs = append(s.errs, fmt.Errorf("unknown flag %q", name))
+		return
+	}
+	if s.fromCommandLine[name] {
+		return
+	}
+	if err := s.fs.Set(name, value); err != nil {
+		s.errs = append(s.errs, fmt.Errorf("invalid value for %s: %v", name, err))
+	}
+}
+
+func (s *FlagSetter) String(name string, value *string) {
+	if value != nil {
+		s.Set(name, *value)
+	}
+}
+
+func (s *FlagSetter) Bool(name string, value *bool) {
+	if value != nil {
+		s.Set(name, strconv.FormatBool(*value))
+	}
+}
+
+func (s *FlagSetter) Int32(name string, value *int32) {
+	if value != nil {
+		s.Set(name, strconv.FormatInt(int64(*value), 10))
+	}
+}
+
+func (s *FlagSetter) Float32(name string, value *float32) {
+	if value != nil {
+		s.Set(name, strconv.FormatFloat(float64(*value), 'g', -1, 32))
+	}
+}
+
+func (s *FlagSetter) Duration(name string, value *metav1.Duration) {
+	if value != nil {
+		s.Set(name, value.Duration.String())
+	}
+}
+
+func (s *FlagSetter) Strings(name string, value []string) {
+	if value != nil {
+		s.Set(name, strings.Join(value, ","))
+	}
+}
+
+// StringMap sets a flag of the form 'key1=value1,key2=value2', such as
+// --ingress-class-default-issuers.
+func (s *FlagSetter) StringMap(name string, value map[string]string) {
+	if len(value) == 0 {
+		return
+	}
+	pairs := make([]string, 0, len(value))
+	for k, v := range value {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	s.Set(name, strings.Join(pairs, ","))
+}
+
+// Int32Map sets a flag of the form 'key1=1,key2=2', such as
+// --controller-workers.
+func (s *FlagSetter) Int32Map(name string, value map[string]int32) {
+	if len(value) == 0 {
+		return
+	}
+	pairs := make([]string, 0, len(value))
+	for k, v := range value {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", k, v))
+	}
+	sort.Strings(pairs)
+	s.Set(name, strings.Join(pairs, ","))
+}
+
+// BoolMap sets a flag of the form 'key1=true,key2=false', such as
+// --feature-gates.
+func (s *FlagSetter) BoolMap(name string, value map[string]bool) {
+	if len(value) == 0 {
+		return
+	}
+	pairs := make([]string, 0, len(value))
+	for k, v := range value {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", k, v))
+	}
+	sort.Strings(pairs)
+	s.Set(name, strings.Join(pairs, ","))
+}
+
+// LeaderElection sets the leader election flags shared by the components.
+func (s *FlagSetter) LeaderElection(cfg *configv1alpha1.LeaderElectionConfig) {
+	if cfg == nil {
+		return
+	}
+	s.Bool("leader-elect", cfg.Enabled)
+	s.String("leader-election-namespace", cfg.Namespace)
+	s.Duration("leader-election-lease-duration", cfg.LeaseDuration)
+	s.Duration("leader-election-renew-deadline", cfg.RenewDeadline)
+	s.Duration("leader-election-retry-period", cfg.RetryPeriod)
+}
+
+// Logging sets the logging flags shared by the components.
+func (s *FlagSetter) Logging(cfg *configv1alpha1.LoggingConfig) {
+	if cfg == nil {
+		return
+	}
+	s.Int32("v", cfg.Verbosity)
+	s.String("logging-format", cfg.Format)
+}
+
+// ControllerBackoff sets the controller backoff flags of the controller,
+// which are of the form 'controller1=value1,controller2=value2'.
+func (s *FlagSetter) ControllerBackoff(cfg map[string]configv1alpha1.ControllerBackoffConfig) {
+	baseDelay := make(map[string]string)
+	maxDelay := make(map[string]string)
+	jitter := make(map[string]string)
+	for name, backoff := range cfg {
+		if backoff.BaseDelay != nil {
+			baseDelay[name] = backoff.BaseDelay.Duration.String()
+		}
+		if backoff.MaxDelay != nil {
+			maxDelay[name] = backoff.MaxDelay.Duration.String()
+		}
+		if backoff.Jitter != nil {
+			jitter[name] = strconv.FormatFloat(float64(*backoff.Jitter), 'g', -1, 32)
+		}
+	}
+	s.StringMap("controller-backoff-base-delay", baseDelay)
+	s.StringMap("controller-backoff-max-delay", maxDelay)
+	s.StringMap("controller-backoff-jitter", jitter)
+}
+
+// Err returns the errors that occurred while setting flags.
+func (s *FlagSetter) Err() error {
+	return utilerrors.NewAggregate(s.errs)
+}
+
+// Watch reloads the configuration file at path every time it changes, until
+// ctx is done. newObj returns an empty object of the kind of the
+// configuration file, validate validates such an object, and logging returns
+// the address of its logging settings.
+// The log verbosity is the only setting that is applied without a restart,
+// and is not applied if it was set on the command line. A message is logged
+// if any other setting changes. Invalid configuration files are logged and
+// otherwise ignored.
+func (s *FlagSetter) Watch(ctx context.Context, path string, newObj func() runtime.Object, validate func(runtime.Object) error, logging func(runtime.Object) **configv1alpha1.LoggingConfig) {
+	log := logf.FromContext(ctx, "configfile").WithValues("path", path)
+
+	last, err := os.ReadFile(path)
+	if err != nil {
+		log.Error(err, "not watching configuration file as it cannot be read")
+		return
+	}
+	current := newObj()
+	if err := decode(path, last, current); err != nil {
+		log.Error(err, "not watching configuration file as it cannot be loaded")
+		return
+	}
+
+	wait.Until(func() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Error(err, "error reading configuration file")
+			return
+		}
+		if bytes.Equal(data, last) {
+			return
+		}
+		last = data
+
+		obj := newObj()
+		if err := decode(path, data, obj); err != nil {
+			log.Error(err, "not reloading invalid configuration file")
+			return
+		}
+		if err := validate(obj); err != nil {
+			log.Error(err, "not reloading invalid configuration file")
+			return
+		}
+		log.V(logf.InfoLevel).Info("reloading configuration file")
+		if err := s.reload(current, obj, logging); err != nil {
+			log.Error(err, "error reloading configuration file")
+		}
+		current = obj
+	}, WatchPeriod, ctx.Done())
+}
+
+// reload applies the log verbosity of the new configuration, and returns an
+// error if any other setting differs from the old configuration.
+func (s *FlagSetter) reload(old, new runtime.Object, logging func(runtime.Object) **configv1alpha1.LoggingConfig) error {
+	old, new = old.DeepCopyObject(), new.DeepCopyObject()
+	oldVerbosity := clearVerbosity(logging(old))
+	newVerbosity := clearVerbosity(logging(new))
+	if newVerbosity != nil && !s.fromCommandLine["v"] && (oldVerbosity == nil || *oldVerbosity != *newVerbosity) {
+		if err := s.fs.Set("v", strconv.FormatInt(int64(*newVerbosity), 10)); err != nil {
+			return err
+		}
+	}
+
+	if !apiequality.Semantic.DeepEqual(old, new) {
+		return fmt.Errorf("settings other than the log verbosity changed and will only be applied after a restart")
+	}
+	return nil
+}
+
+// clearVerbosity removes the verbosity from the given logging settings, and
+// returns it. Logging settings that are left empty are removed altogether.
+func clearVerbosity(logging **configv1alpha1.LoggingConfig) *int32 {
+	if *logging == nil {
+		return nil
+	}
+	verbosity := (*logging).Verbosity
+	(*logging).Verbosity = nil
+	if **logging == (configv1alpha1.LoggingConfig{}) {
+		*logging = nil
+	}
+	return verbosity
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	configv1alpha1 "github.com/jetstack/cert-manager/pkg/apis/config/v1alpha1"
+)
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		data   string
+		expErr string
+		exp    *configv1alpha1.ControllerConfiguration
+	}{
+		"loads and defaults a valid configuration file": {
+			data: `apiVersion: config.cert-manager.io/v1alpha1
+kind: ControllerConfiguration
+clusterResourceNamespace: cert-manager
+leaderElection:
+  leaseDuration: 30s
+`,
+			exp: &configv1alpha1.ControllerConfiguration{
+				ClusterResourceNamespace: pointer.String("cert-manager"),
+				LeaderElection: &configv1alpha1.LeaderElectionConfig{
+					LeaseDuration: &metav1.Duration{Duration: 30 * time.Second},
+					RenewDeadline: &metav1.Duration{Duration: configv1alpha1.DefaultLeaderElectionRenewDeadline},
+				},
+			},
+		},
+		"rejects unknown fields": {
+			data: `apiVersion: config.cert-manager.io/v1alpha1
+kind: ControllerConfiguration
+clusterResourceNamespaces: cert-manager
+`,
+			expErr: "unknown field: clusterResourceNamespaces",
+		},
+		"rejects configuration files of another kind": {
+			data: `apiVersion: config.cert-manager.io/v1alpha1
+kind: WebhookConfiguration
+`,
+			expErr: "must contain a config.cert-manager.io/v1alpha1, Kind=ControllerConfiguration",
+		},
+		"rejects unknown kinds": {
+			data: `apiVersion: config.cert-manager.io/v1
+kind: ControllerConfiguration
+`,
+			expErr: "no kind",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(test.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &configv1alpha1.ControllerConfiguration{}
+			err := Load(path, cfg)
+			if test.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			test.exp.TypeMeta = cfg.TypeMeta
+			configv1alpha1.SetObjectDefaults_ControllerConfiguration(test.exp)
+			if !reflect.DeepEqual(test.exp, cfg) {
+				t.Errorf("unexpected configuration, exp=%+v got=%+v", test.exp, cfg)
+			}
+		})
+	}
+}
+
+func TestFlagSetter(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	name := fs.String("name", "default", "")
+	kind := fs.String("kind", "default", "")
+	namespaces := fs.StringSlice("namespaces", nil, "")
+	qps := fs.Float32("qps", 20, "")
+	issuers := fs.StringToString("issuers", nil, "")
+	workers := fs.StringToString("workers", nil, "")
+	baseDelay := fs.StringToString("controller-backoff-base-delay", nil, "")
+	maxDelay := fs.StringToString("controller-backoff-max-delay", nil, "")
+	jitter := fs.StringToString("controller-backoff-jitter", nil, "")
+	if err := fs.Parse([]string{"--kind=cli"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewFlagSetter(fs)
+	s.String("name", pointer.String("file"))
+	s.String("kind", pointer.String("file"))
+	s.Strings("namespaces", []string{"a", "b"})
+	s.Float32("qps", nil)
+	s.StringMap("issuers", map[string]string{"nginx": "ClusterIssuer/letsencrypt"})
+	s.Int32Map("workers", map[string]int32{"*": 2, "issuers": 10})
+	s.ControllerBackoff(map[string]configv1alpha1.ControllerBackoffConfig{
+		"*":       {Jitter: pointer.Float32(0.2)},
+		"issuers": {BaseDelay: &metav1.Duration{Duration: 30 * time.Second}},
+	})
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *name != "file" {
+		t.Errorf("expected flag not set on the command line to be set from the file, got %q", *name)
+	}
+	if *kind != "cli" {
+		t.Errorf("expected flag set on the command line to take precedence, got %q", *kind)
+	}
+	if strings.Join(*namespaces, ",") != "a,b" {
+		t.Errorf("unexpected namespaces: %v", *namespaces)
+	}
+	if *qps != 20 {
+		t.Errorf("expected flag unset in the file to keep its default, got %v", *qps)
+	}
+	if exp := map[string]string{"nginx": "ClusterIssuer/letsencrypt"}; !reflect.DeepEqual(*issuers, exp) {
+		t.Errorf("unexpected issuers, exp=%v got=%v", exp, *issuers)
+	}
+	if exp := map[string]string{"*": "2", "issuers": "10"}; !reflect.DeepEqual(*workers, exp) {
+		t.Errorf("unexpected workers, exp=%v got=%v", exp, *workers)
+	}
+	if exp := map[string]string{"issuers": "30s"}; !reflect.DeepEqual(*baseDelay, exp) {
+		t.Errorf("unexpected base delay, exp=%v got=%v", exp, *baseDelay)
+	}
+	if len(*maxDelay) != 0 {
+		t.Errorf("expected max delay unset in the file to keep its default, got %v", *maxDelay)
+	}
+	if exp := map[string]string{"*": "0.2"}; !reflect.DeepEqual(*jitter, exp) {
+		t.Errorf("unexpected jitter, exp=%v got=%v", exp, *jitter)
+	}
+
+	s.Set("unknown", "value")
+	s.Set("qps", "invalid")
+	if err := s.Err(); err == nil || !strings.Contains(err.Error(), `unknown flag "unknown"`) || !strings.Contains(err.Error(), "invalid value for qps") {
+		t.Errorf("expected errors for unknown flag and invalid value, got %v", err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	logging := func(obj runtime.Object) **configv1alpha1.LoggingConfig {
+		return &obj.(*configv1alpha1.ControllerConfiguration).Logging
+	}
+	config := func(verbosity *int32, namespace *string) *configv1alpha1.ControllerConfiguration {
+		cfg := &configv1alpha1.ControllerConfiguration{ClusterResourceNamespace: namespace}
+		if verbosity != nil {
+			cfg.Logging = &configv1alpha1.LoggingConfig{Verbosity: verbosity}
+		}
+		return cfg
+	}
+
+	tests := map[string]struct {
+		args         []string
+		old, new     runtime.Object
+		expVerbosity string
+		expErr       bool
+	}{
+		"applies a changed verbosity": {
+			old:          config(pointer.Int32(2), nil),
+			new:          config(pointer.Int32(4), nil),
+			expVerbosity: "4",
+		},
+		"applies a verbosity added to the file": {
+			old:          config(nil, nil),
+			new:          config(pointer.Int32(4), nil),
+			expVerbosity: "4",
+		},
+		"does not override a verbosity set on the command line": {
+			args:         []string{"--v=1"},
+			old:          config(pointer.Int32(2), nil),
+			new:          config(pointer.Int32(4), nil),
+			expVerbosity: "1",
+		},
+		"returns an error if other settings change": {
+			old:          config(pointer.Int32(2), pointer.String("a")),
+			new:          config(pointer.Int32(4), pointer.String("b")),
+			expVerbosity: "4",
+			expErr:       true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			v := fs.String("v", "0", "")
+			if err := fs.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := NewFlagSetter(fs).reload(test.old, test.new, logging)
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if *v != test.expVerbosity {
+				t.Errorf("unexpected verbosity, exp=%s got=%s", test.expVerbosity, *v)
+			}
+		})
+	}
+}
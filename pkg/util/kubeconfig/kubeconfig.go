@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubeconfig requests client certificates from cert-manager issuers
+// and assembles kubeconfig files that authenticate with them.
+package kubeconfig
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// ClientCertificateOptions describes the client certificate to request.
+type ClientCertificateOptions struct {
+	// Username is the name of the user that the certificate authenticates
+	// as. It is used as the common name of the certificate.
+	Username string
+
+	// Groups are the groups that the user is a member of. They are used as
+	// the organizations of the certificate.
+	Groups []string
+
+	// Duration is the requested validity of the certificate. The issuer's
+	// default is used if zero.
+	Duration time.Duration
+
+	// KeyAlgorithm and KeySize configure the generated private key. An ECDSA
+	// P-256 key is generated if not set.
+	KeyAlgorithm cmapi.PrivateKeyAlgorithm
+	KeySize      int
+
+	// IssuerRef references the issuer that signs the certificate.
+	IssuerRef cmmeta.ObjectReference
+}
+
+// Cluster describes the cluster that the kubeconfig connects to.
+type Cluster struct {
+	// Name of the cluster and context in the kubeconfig.
+	Name string
+
+	// Server is the URL of the Kubernetes API server.
+	Server string
+
+	// CertificateAuthorityData is the PEM encoded bundle used to verify the
+	// API server's serving certificate.
+	CertificateAuthorityData []byte
+
+	// TLSServerName is the server name used to verify the API server's
+	// serving certificate, if it differs from the host of Server.
+	TLSServerName string
+}
+
+// NewCertificateRequest generates a private key and returns a
+// CertificateRequest with the given name for a client certificate that
+// authenticates as the user described by the options, together with the PEM
+// encoded private key.
+func NewCertificateRequest(name string, opts ClientCertificateOptions) (*cmapi.CertificateRequest, []byte, error) {
+	if len(opts.Username) == 0 {
+		return nil, nil, errors.New("a username must be provided")
+	}
+
+	keyAlgorithm := opts.KeyAlgorithm
+	if keyAlgorithm == "" {
+		keyAlgorithm = cmapi.ECDSAKeyAlgorithm
+	}
+	crt := &cmapi.Certificate{
+		Spec: cmapi.CertificateSpec{
+			CommonName: opts.Username,
+			PrivateKey: &cmapi.CertificatePrivateKey{
+				Algorithm: keyAlgorithm,
+				Size:      opts.KeySize,
+			},
+			Usages: []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageClientAuth},
+		},
+	}
+	if len(opts.Groups) > 0 {
+		crt.Spec.Subject = &cmapi.X509Subject{Organizations: opts.Groups}
+	}
+
+	signer, err := pki.GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating private key: %w", err)
+	}
+	keyPEM, err := pki.EncodePrivateKey(signer, cmapi.PKCS1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding private key: %w", err)
+	}
+
+	csr, err := pki.GenerateCSR(crt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating certificate signing request: %w", err)
+	}
+	csrDER, err := pki.EncodeCSR(csr, signer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error signing certificate signing request: %w", err)
+	}
+
+	cr := &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: cmapi.CertificateRequestSpec{
+			Request:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+			IssuerRef: opts.IssuerRef,
+			Usages:    crt.Spec.Usages,
+		},
+	}
+	if opts.Duration > 0 {
+		cr.Spec.Duration = &metav1.Duration{Duration: opts.Duration}
+	}
+
+	return cr, keyPEM, nil
+}
+
+// Build returns a kubeconfig with a single context that connects to the
+// cluster as the given user, authenticating with the given PEM encoded client
+// certificate and private key.
+func Build(cluster Cluster, username string, certPEM, keyPEM []byte) (*clientcmdapi.Config, error) {
+	if len(cluster.Name) == 0 || len(cluster.Server) == 0 {
+		return nil, errors.New("the cluster name and server must be provided")
+	}
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, errors.New("the client certificate and private key must be provided")
+	}
+
+	contextName := username + "@" + cluster.Name
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[cluster.Name] = &clientcmdapi.Cluster{
+		Server:                   cluster.Server,
+		CertificateAuthorityData: cluster.CertificateAuthorityData,
+		TLSServerName:            cluster.TLSServerName,
+	}
+	config.AuthInfos[username] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: certPEM,
+		ClientKeyData:         keyPEM,
+	}
+	config.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:  cluster.Name,
+		AuthInfo: username,
+	}
+	config.CurrentContext = contextName
+
+	return config, nil
+}
+
+// Encode returns the YAML encoding of the kubeconfig.
+func Encode(config *clientcmdapi.Config) ([]byte, error) {
+	var versioned clientcmdapiv1.Config
+	if err := clientcmdlatest.Scheme.Convert(config, &versioned, nil); err != nil {
+		return nil, err
+	}
+	versioned.APIVersion = clientcmdapiv1.SchemeGroupVersion.Version
+	versioned.Kind = "Config"
+	return yaml.Marshal(&versioned)
+}
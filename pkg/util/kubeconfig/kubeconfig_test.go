@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestNewCertificateRequest(t *testing.T) {
+	issuerRef := cmmeta.ObjectReference{Name: "ca", Kind: cmapi.ClusterIssuerKind}
+
+	tests := map[string]struct {
+		opts   ClientCertificateOptions
+		expErr bool
+		// expRSA is true if an RSA rather than an ECDSA key is expected
+		expRSA bool
+	}{
+		"requests an ECDSA client certificate for the user and groups": {
+			opts: ClientCertificateOptions{
+				Username:  "alice",
+				Groups:    []string{"admins", "developers"},
+				Duration:  24 * time.Hour,
+				IssuerRef: issuerRef,
+			},
+		},
+		"generates keys of the requested algorithm": {
+			opts: ClientCertificateOptions{
+				Username:     "alice",
+				KeyAlgorithm: cmapi.RSAKeyAlgorithm,
+				KeySize:      2048,
+				IssuerRef:    issuerRef,
+			},
+			expRSA: true,
+		},
+		"requires a username": {
+			opts:   ClientCertificateOptions{IssuerRef: issuerRef},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr, keyPEM, err := NewCertificateRequest("test", test.opts)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if cr.Name != "test" || cr.Spec.IssuerRef != test.opts.IssuerRef {
+				t.Errorf("unexpected name or issuer reference: %s, %v", cr.Name, cr.Spec.IssuerRef)
+			}
+			if test.opts.Duration > 0 && (cr.Spec.Duration == nil || cr.Spec.Duration.Duration != test.opts.Duration) {
+				t.Errorf("unexpected duration %v", cr.Spec.Duration)
+			}
+			if !reflect.DeepEqual(cr.Spec.Usages, []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageClientAuth}) {
+				t.Errorf("unexpected usages %v", cr.Spec.Usages)
+			}
+
+			csr, err := pki.DecodeX509CertificateRequestBytes(cr.Spec.Request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if csr.Subject.CommonName != test.opts.Username {
+				t.Errorf("unexpected common name %q", csr.Subject.CommonName)
+			}
+			if len(test.opts.Groups) > 0 && !reflect.DeepEqual(csr.Subject.Organization, test.opts.Groups) {
+				t.Errorf("unexpected organizations %v", csr.Subject.Organization)
+			}
+
+			key, err := pki.DecodePrivateKeyBytes(keyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := pki.PublicKeyMatchesCSR(key.Public(), csr); err != nil || !ok {
+				t.Errorf("private key does not match the CSR: %v", err)
+			}
+			switch key.(type) {
+			case *rsa.PrivateKey:
+				if !test.expRSA {
+					t.Errorf("expected an ECDSA key")
+				}
+			case *ecdsa.PrivateKey:
+				if test.expRSA {
+					t.Errorf("expected an RSA key")
+				}
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	cluster := Cluster{
+		Name:                     "prod",
+		Server:                   "https://prod.example.com:6443",
+		CertificateAuthorityData: []byte("ca"),
+	}
+
+	config, err := Build(cluster, "alice", []byte("cert"), []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := clientcmd.Validate(*config); err != nil {
+		t.Fatalf("kubeconfig is invalid: %v", err)
+	}
+	if config.CurrentContext != "alice@prod" {
+		t.Errorf("unexpected current context %q", config.CurrentContext)
+	}
+	if string(config.AuthInfos["alice"].ClientCertificateData) != "cert" || string(config.AuthInfos["alice"].ClientKeyData) != "key" {
+		t.Errorf("client certificate and key are not embedded")
+	}
+	if c := config.Clusters["prod"]; c.Server != cluster.Server || string(c.CertificateAuthorityData) != "ca" {
+		t.Errorf("unexpected cluster %+v", c)
+	}
+
+	data, err := Encode(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := clientcmd.Load(data)
+	if err != nil {
+		t.Fatalf("failed to load encoded kubeconfig: %v", err)
+	}
+	if decoded.CurrentContext != config.CurrentContext || string(decoded.AuthInfos["alice"].ClientKeyData) != "key" {
+		t.Errorf("encoded kubeconfig does not round trip: %+v", decoded)
+	}
+
+	if _, err := Build(Cluster{Name: "prod"}, "alice", []byte("cert"), []byte("key")); err == nil {
+		t.Errorf("expected an error if no server is provided")
+	}
+}
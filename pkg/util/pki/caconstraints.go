@@ -0,0 +1,399 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+var (
+	// OIDExtensionNameConstraints is the OID of the X.509 name constraints
+	// extension, see RFC 5280, 4.2.1.10.
+	OIDExtensionNameConstraints = asn1.ObjectIdentifier{2, 5, 29, 30}
+
+	// OIDExtensionCRLDistributionPoints is the OID of the X.509 CRL
+	// distribution points extension, see RFC 5280, 4.2.1.13.
+	OIDExtensionCRLDistributionPoints = asn1.ObjectIdentifier{2, 5, 29, 31}
+
+	// OIDExtensionAuthorityInfoAccess is the OID of the X.509 authority
+	// information access extension, see RFC 5280, 4.2.2.1.
+	OIDExtensionAuthorityInfoAccess = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 1}
+
+	oidAuthorityInfoAccessOCSP    = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1}
+	oidAuthorityInfoAccessIssuers = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 2}
+)
+
+// GeneralName tags, see RFC 5280, 4.2.1.6.
+const (
+	nameTypeEmail = 1
+	nameTypeDNS   = 2
+	nameTypeURI   = 6
+	nameTypeIP    = 7
+)
+
+// nameConstraints is the ASN.1 structure of the name constraints extension.
+type nameConstraints struct {
+	Permitted []generalSubtree `asn1:"optional,tag:0"`
+	Excluded  []generalSubtree `asn1:"optional,tag:1"`
+}
+
+type generalSubtree struct {
+	Base asn1.RawValue
+}
+
+// distributionPoint is the ASN.1 structure of a CRL distribution point.
+// Copied from x509.go
+type distributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+	Reason            asn1.BitString        `asn1:"optional,tag:1"`
+	CRLIssuer         asn1.RawValue         `asn1:"optional,tag:2"`
+}
+
+type distributionPointName struct {
+	FullName     []asn1.RawValue  `asn1:"optional,tag:0"`
+	RelativeName pkix.RDNSequence `asn1:"optional,tag:1"`
+}
+
+// authorityInfoAccess is the ASN.1 structure of an access description of the
+// authority information access extension.
+// Copied from x509.go
+type authorityInfoAccess struct {
+	Method   asn1.ObjectIdentifier
+	Location asn1.RawValue
+}
+
+// applyCAConstraints sets the fields of the template for the given CA
+// constraints.
+func applyCAConstraints(template *x509.Certificate, constraints *v1.CertificateCAConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+
+	if constraints.MaxPathLen != nil {
+		if *constraints.MaxPathLen < 0 {
+			return fmt.Errorf("invalid maximum path length %d: must be a non-negative integer", *constraints.MaxPathLen)
+		}
+		template.MaxPathLen = *constraints.MaxPathLen
+		template.MaxPathLenZero = *constraints.MaxPathLen == 0
+	}
+
+	if nc := constraints.NameConstraints; nc != nil {
+		template.PermittedDNSDomainsCritical = nc.Critical
+		if p := nc.Permitted; p != nil {
+			ipRanges, err := parseIPRanges(p.IPRanges)
+			if err != nil {
+				return fmt.Errorf("invalid permitted name constraints: %w", err)
+			}
+			template.PermittedDNSDomains = nilIfEmpty(p.DNSDomains)
+			template.PermittedIPRanges = ipRanges
+			template.PermittedEmailAddresses = nilIfEmpty(p.EmailAddresses)
+			template.PermittedURIDomains = nilIfEmpty(p.URIDomains)
+		}
+		if e := nc.Excluded; e != nil {
+			ipRanges, err := parseIPRanges(e.IPRanges)
+			if err != nil {
+				return fmt.Errorf("invalid excluded name constraints: %w", err)
+			}
+			template.ExcludedDNSDomains = nilIfEmpty(e.DNSDomains)
+			template.ExcludedIPRanges = ipRanges
+			template.ExcludedEmailAddresses = nilIfEmpty(e.EmailAddresses)
+			template.ExcludedURIDomains = nilIfEmpty(e.URIDomains)
+		}
+	}
+
+	for _, urls := range [][]string{constraints.CRLDistributionPoints, constraints.IssuingCertificateURLs, constraints.OCSPServers} {
+		for _, u := range urls {
+			if _, err := url.Parse(u); err != nil {
+				return fmt.Errorf("invalid URL %q: %w", u, err)
+			}
+		}
+	}
+	template.CRLDistributionPoints = nilIfEmpty(constraints.CRLDistributionPoints)
+	template.IssuingCertificateURL = nilIfEmpty(constraints.IssuingCertificateURLs)
+	template.OCSPServer = nilIfEmpty(constraints.OCSPServers)
+
+	return nil
+}
+
+// buildCAConstraintsExtensions returns the extensions which request the given
+// CA constraints in a certificate request. Since the standard library only
+// encodes these extensions in certificates, they are marshalled here.
+func buildCAConstraintsExtensions(constraints *v1.CertificateCAConstraints) ([]pkix.Extension, error) {
+	template := &x509.Certificate{IsCA: true, MaxPathLen: -1}
+	if err := applyCAConstraints(template, constraints); err != nil {
+		return nil, err
+	}
+
+	maxPathLen := template.MaxPathLen
+	if maxPathLen == 0 && !template.MaxPathLenZero {
+		maxPathLen = -1
+	}
+	value, err := asn1.Marshal(basicConstraints{IsCA: true, MaxPathLen: maxPathLen})
+	if err != nil {
+		return nil, fmt.Errorf("failed to asn1 encode basic constraints: %w", err)
+	}
+	extensions := []pkix.Extension{{Id: OIDExtensionBasicConstraints, Critical: true, Value: value}}
+
+	if hasNameConstraints(template) {
+		var nc nameConstraints
+		nc.Permitted = buildGeneralSubtrees(template.PermittedDNSDomains, template.PermittedIPRanges, template.PermittedEmailAddresses, template.PermittedURIDomains)
+		nc.Excluded = buildGeneralSubtrees(template.ExcludedDNSDomains, template.ExcludedIPRanges, template.ExcludedEmailAddresses, template.ExcludedURIDomains)
+		value, err := asn1.Marshal(nc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to asn1 encode name constraints: %w", err)
+		}
+		extensions = append(extensions, pkix.Extension{Id: OIDExtensionNameConstraints, Critical: template.PermittedDNSDomainsCritical, Value: value})
+	}
+
+	if len(template.CRLDistributionPoints) > 0 {
+		var points []distributionPoint
+		for _, u := range template.CRLDistributionPoints {
+			points = append(points, distributionPoint{
+				DistributionPoint: distributionPointName{
+					FullName: []asn1.RawValue{{Tag: nameTypeURI, Class: asn1.ClassContextSpecific, Bytes: []byte(u)}},
+				},
+			})
+		}
+		value, err := asn1.Marshal(points)
+		if err != nil {
+			return nil, fmt.Errorf("failed to asn1 encode CRL distribution points: %w", err)
+		}
+		extensions = append(extensions, pkix.Extension{Id: OIDExtensionCRLDistributionPoints, Value: value})
+	}
+
+	if len(template.OCSPServer) > 0 || len(template.IssuingCertificateURL) > 0 {
+		var aia []authorityInfoAccess
+		for _, u := range template.OCSPServer {
+			aia = append(aia, authorityInfoAccess{
+				Method:   oidAuthorityInfoAccessOCSP,
+				Location: asn1.RawValue{Tag: nameTypeURI, Class: asn1.ClassContextSpecific, Bytes: []byte(u)},
+			})
+		}
+		for _, u := range template.IssuingCertificateURL {
+			aia = append(aia, authorityInfoAccess{
+				Method:   oidAuthorityInfoAccessIssuers,
+				Location: asn1.RawValue{Tag: nameTypeURI, Class: asn1.ClassContextSpecific, Bytes: []byte(u)},
+			})
+		}
+		value, err := asn1.Marshal(aia)
+		if err != nil {
+			return nil, fmt.Errorf("failed to asn1 encode authority information access: %w", err)
+		}
+		extensions = append(extensions, pkix.Extension{Id: OIDExtensionAuthorityInfoAccess, Value: value})
+	}
+
+	return extensions, nil
+}
+
+// RequestMatchesCAConstraints returns true if the CA constraints requested by
+// the extensions of the given certificate request are equal to the given
+// constraints. A nil constraints matches a request for no CA constraints.
+func RequestMatchesCAConstraints(csr *x509.CertificateRequest, constraints *v1.CertificateCAConstraints) (bool, error) {
+	expected := &x509.Certificate{MaxPathLen: -1}
+	if err := applyCAConstraints(expected, constraints); err != nil {
+		return false, err
+	}
+
+	requested := &x509.Certificate{MaxPathLen: -1}
+	for _, ext := range csr.Extensions {
+		if err := applyCAConstraintsExtension(requested, ext); err != nil {
+			return false, err
+		}
+	}
+
+	return reflect.DeepEqual(caConstraintsOf(expected), caConstraintsOf(requested)), nil
+}
+
+// caConstraintsOf returns a copy of the template with only the fields set by
+// applyCAConstraints.
+func caConstraintsOf(template *x509.Certificate) x509.Certificate {
+	maxPathLen := template.MaxPathLen
+	if maxPathLen == 0 && !template.MaxPathLenZero {
+		maxPathLen = -1
+	}
+	return x509.Certificate{
+		MaxPathLen:                  maxPathLen,
+		PermittedDNSDomainsCritical: template.PermittedDNSDomainsCritical,
+		PermittedDNSDomains:         template.PermittedDNSDomains,
+		ExcludedDNSDomains:          template.ExcludedDNSDomains,
+		PermittedIPRanges:           template.PermittedIPRanges,
+		ExcludedIPRanges:            template.ExcludedIPRanges,
+		PermittedEmailAddresses:     template.PermittedEmailAddresses,
+		ExcludedEmailAddresses:      template.ExcludedEmailAddresses,
+		PermittedURIDomains:         template.PermittedURIDomains,
+		ExcludedURIDomains:          template.ExcludedURIDomains,
+		CRLDistributionPoints:       template.CRLDistributionPoints,
+		IssuingCertificateURL:       template.IssuingCertificateURL,
+		OCSPServer:                  template.OCSPServer,
+	}
+}
+
+// applyCAConstraintsExtension updates the template with the name
+// constraints, CRL distribution points or authority information access
+// requested by the given certificate request extension. Other extensions are
+// ignored.
+func applyCAConstraintsExtension(template *x509.Certificate, ext pkix.Extension) error {
+	switch {
+	case ext.Id.Equal(OIDExtensionBasicConstraints):
+		var constraints basicConstraints
+		if _, err := asn1.Unmarshal(ext.Value, &constraints); err != nil {
+			return fmt.Errorf("failed to decode csr basic constraints: %w", err)
+		}
+		if constraints.IsCA {
+			template.MaxPathLen = constraints.MaxPathLen
+			template.MaxPathLenZero = constraints.MaxPathLen == 0
+		}
+
+	case ext.Id.Equal(OIDExtensionNameConstraints):
+		var nc nameConstraints
+		if rest, err := asn1.Unmarshal(ext.Value, &nc); err != nil {
+			return fmt.Errorf("failed to decode csr name constraints: %w", err)
+		} else if len(rest) != 0 {
+			return fmt.Errorf("failed to decode csr name constraints: trailing data")
+		}
+		template.PermittedDNSDomainsCritical = ext.Critical
+		var err error
+		template.PermittedDNSDomains, template.PermittedIPRanges, template.PermittedEmailAddresses, template.PermittedURIDomains, err = parseGeneralSubtrees(nc.Permitted)
+		if err != nil {
+			return fmt.Errorf("failed to decode csr permitted name constraints: %w", err)
+		}
+		template.ExcludedDNSDomains, template.ExcludedIPRanges, template.ExcludedEmailAddresses, template.ExcludedURIDomains, err = parseGeneralSubtrees(nc.Excluded)
+		if err != nil {
+			return fmt.Errorf("failed to decode csr excluded name constraints: %w", err)
+		}
+
+	case ext.Id.Equal(OIDExtensionCRLDistributionPoints):
+		var points []distributionPoint
+		if _, err := asn1.Unmarshal(ext.Value, &points); err != nil {
+			return fmt.Errorf("failed to decode csr CRL distribution points: %w", err)
+		}
+		template.CRLDistributionPoints = nil
+		for _, point := range points {
+			for _, name := range point.DistributionPoint.FullName {
+				if name.Class == asn1.ClassContextSpecific && name.Tag == nameTypeURI {
+					template.CRLDistributionPoints = append(template.CRLDistributionPoints, string(name.Bytes))
+				}
+			}
+		}
+
+	case ext.Id.Equal(OIDExtensionAuthorityInfoAccess):
+		var aia []authorityInfoAccess
+		if _, err := asn1.Unmarshal(ext.Value, &aia); err != nil {
+			return fmt.Errorf("failed to decode csr authority information access: %w", err)
+		}
+		template.OCSPServer, template.IssuingCertificateURL = nil, nil
+		for _, a := range aia {
+			if a.Location.Class != asn1.ClassContextSpecific || a.Location.Tag != nameTypeURI {
+				continue
+			}
+			switch {
+			case a.Method.Equal(oidAuthorityInfoAccessOCSP):
+				template.OCSPServer = append(template.OCSPServer, string(a.Location.Bytes))
+			case a.Method.Equal(oidAuthorityInfoAccessIssuers):
+				template.IssuingCertificateURL = append(template.IssuingCertificateURL, string(a.Location.Bytes))
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasNameConstraints(template *x509.Certificate) bool {
+	return len(template.PermittedDNSDomains) > 0 || len(template.PermittedIPRanges) > 0 ||
+		len(template.PermittedEmailAddresses) > 0 || len(template.PermittedURIDomains) > 0 ||
+		len(template.ExcludedDNSDomains) > 0 || len(template.ExcludedIPRanges) > 0 ||
+		len(template.ExcludedEmailAddresses) > 0 || len(template.ExcludedURIDomains) > 0
+}
+
+func buildGeneralSubtrees(dnsDomains []string, ipRanges []*net.IPNet, emails, uriDomains []string) []generalSubtree {
+	var subtrees []generalSubtree
+	add := func(tag int, b []byte) {
+		subtrees = append(subtrees, generalSubtree{
+			Base: asn1.RawValue{Tag: tag, Class: asn1.ClassContextSpecific, Bytes: b},
+		})
+	}
+	for _, domain := range dnsDomains {
+		add(nameTypeDNS, []byte(domain))
+	}
+	for _, ipNet := range ipRanges {
+		ip := ipNet.IP
+		if ip4 := ip.To4(); ip4 != nil && len(ipNet.Mask) == net.IPv4len {
+			ip = ip4
+		}
+		add(nameTypeIP, append(append([]byte{}, ip...), ipNet.Mask...))
+	}
+	for _, email := range emails {
+		add(nameTypeEmail, []byte(email))
+	}
+	for _, domain := range uriDomains {
+		add(nameTypeURI, []byte(domain))
+	}
+	return subtrees
+}
+
+func parseGeneralSubtrees(subtrees []generalSubtree) (dnsDomains []string, ipRanges []*net.IPNet, emails, uriDomains []string, err error) {
+	for _, subtree := range subtrees {
+		base := subtree.Base
+		if base.Class != asn1.ClassContextSpecific {
+			return nil, nil, nil, nil, fmt.Errorf("unexpected general name class %d", base.Class)
+		}
+		switch base.Tag {
+		case nameTypeDNS:
+			dnsDomains = append(dnsDomains, string(base.Bytes))
+		case nameTypeIP:
+			n := len(base.Bytes) / 2
+			if len(base.Bytes) != 2*net.IPv4len && len(base.Bytes) != 2*net.IPv6len {
+				return nil, nil, nil, nil, fmt.Errorf("invalid IP range of length %d", len(base.Bytes))
+			}
+			ipRanges = append(ipRanges, &net.IPNet{IP: net.IP(base.Bytes[:n]), Mask: net.IPMask(base.Bytes[n:])})
+		case nameTypeEmail:
+			emails = append(emails, string(base.Bytes))
+		case nameTypeURI:
+			uriDomains = append(uriDomains, string(base.Bytes))
+		default:
+			return nil, nil, nil, nil, fmt.Errorf("unsupported general name tag %d", base.Tag)
+		}
+	}
+	return dnsDomains, ipRanges, emails, uriDomains, nil
+}
+
+func parseIPRanges(cidrs []string) ([]*net.IPNet, error) {
+	var ipRanges []*net.IPNet
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		ipRanges = append(ipRanges, ipNet)
+	}
+	return ipRanges, nil
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	require.NoError(t, err)
+	return ipNet
+}
+
+func TestCAConstraints(t *testing.T) {
+	zero, one := 0, 1
+
+	tests := map[string]struct {
+		constraints cmapi.CertificateCAConstraints
+		check       func(t *testing.T, cert *x509.Certificate)
+	}{
+		"a path length of zero should be encoded": {
+			constraints: cmapi.CertificateCAConstraints{MaxPathLen: &zero},
+			check: func(t *testing.T, cert *x509.Certificate) {
+				assert.Equal(t, 0, cert.MaxPathLen)
+				assert.True(t, cert.MaxPathLenZero)
+			},
+		},
+		"name constraints, CRL distribution points and authority information access should be encoded": {
+			constraints: cmapi.CertificateCAConstraints{
+				MaxPathLen: &one,
+				NameConstraints: &cmapi.NameConstraints{
+					Critical: true,
+					Permitted: &cmapi.NameConstraintItem{
+						DNSDomains:     []string{"example.com"},
+						IPRanges:       []string{"10.0.0.0/8", "2001:db8::/32"},
+						EmailAddresses: []string{"example.com"},
+						URIDomains:     []string{".example.com"},
+					},
+					Excluded: &cmapi.NameConstraintItem{
+						DNSDomains: []string{"bad.example.com"},
+						IPRanges:   []string{"10.1.0.0/16"},
+					},
+				},
+				CRLDistributionPoints:  []string{"http://crl.example.com/ca.crl"},
+				IssuingCertificateURLs: []string{"http://ca.example.com/ca.crt"},
+				OCSPServers:            []string{"http://ocsp.example.com"},
+			},
+			check: func(t *testing.T, cert *x509.Certificate) {
+				assert.Equal(t, 1, cert.MaxPathLen)
+				assert.True(t, cert.PermittedDNSDomainsCritical)
+				assert.Equal(t, []string{"example.com"}, cert.PermittedDNSDomains)
+				assert.Equal(t, []*net.IPNet{mustParseCIDR(t, "10.0.0.0/8"), mustParseCIDR(t, "2001:db8::/32")}, cert.PermittedIPRanges)
+				assert.Equal(t, []string{"example.com"}, cert.PermittedEmailAddresses)
+				assert.Equal(t, []string{".example.com"}, cert.PermittedURIDomains)
+				assert.Equal(t, []string{"bad.example.com"}, cert.ExcludedDNSDomains)
+				assert.Equal(t, []*net.IPNet{mustParseCIDR(t, "10.1.0.0/16")}, cert.ExcludedIPRanges)
+				assert.Equal(t, []string{"http://crl.example.com/ca.crl"}, cert.CRLDistributionPoints)
+				assert.Equal(t, []string{"http://ca.example.com/ca.crt"}, cert.IssuingCertificateURL)
+				assert.Equal(t, []string{"http://ocsp.example.com"}, cert.OCSPServer)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := buildCertificate("test-ca")
+			crt.Spec.IsCA = true
+			crt.Spec.PrivateKey = &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm}
+			crt.Spec.CAConstraints = &test.constraints
+			pk, err := GenerateECPrivateKey(256)
+			require.NoError(t, err)
+
+			// Self-sign the CSR generated for the Certificate, as the
+			// SelfSigned issuer does.
+			csrTemplate, err := GenerateCSR(crt)
+			require.NoError(t, err)
+			csrDER, err := x509.CreateCertificateRequest(rand.Reader, csrTemplate, pk)
+			require.NoError(t, err)
+			csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+			csr, err := DecodeX509CertificateRequestBytes(csrPEM)
+			require.NoError(t, err)
+			match, err := RequestMatchesCAConstraints(csr, &test.constraints)
+			require.NoError(t, err)
+			assert.True(t, match, "expected the CSR to match the CA constraints")
+
+			template, err := GenerateTemplateFromCSRPEM(csrPEM, cmapi.DefaultCertificateDuration, true)
+			require.NoError(t, err)
+			require.NoError(t, ApplyCSRRequestedExtensions(template, csrPEM))
+			_, cert, err := SignCertificate(template, template, pk.Public(), pk)
+			require.NoError(t, err)
+			test.check(t, cert)
+
+			// The template generated for the Certificate must be equivalent.
+			template, err = GenerateTemplate(crt)
+			require.NoError(t, err)
+			template.PublicKey = pk.Public()
+			_, cert, err = SignCertificate(template, template, pk.Public(), pk)
+			require.NoError(t, err)
+			test.check(t, cert)
+		})
+	}
+}
+
+func TestRequestMatchesCAConstraints(t *testing.T) {
+	zero, one := 0, 1
+	constraints := &cmapi.CertificateCAConstraints{
+		MaxPathLen: &zero,
+		NameConstraints: &cmapi.NameConstraints{
+			Permitted: &cmapi.NameConstraintItem{DNSDomains: []string{"example.com"}},
+		},
+	}
+
+	csrFor := func(constraints *cmapi.CertificateCAConstraints) *x509.CertificateRequest {
+		crt := buildCertificate("test-ca")
+		crt.Spec.IsCA = true
+		crt.Spec.PrivateKey = &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm}
+		crt.Spec.CAConstraints = constraints
+		template, err := GenerateCSR(crt)
+		require.NoError(t, err)
+		pk, err := GenerateECPrivateKey(256)
+		require.NoError(t, err)
+		der, err := x509.CreateCertificateRequest(rand.Reader, template, pk)
+		require.NoError(t, err)
+		csr, err := x509.ParseCertificateRequest(der)
+		require.NoError(t, err)
+		return csr
+	}
+
+	tests := map[string]struct {
+		csr         *x509.CertificateRequest
+		constraints *cmapi.CertificateCAConstraints
+		expMatch    bool
+	}{
+		"a request without constraints should match no constraints": {
+			csr:         csrFor(nil),
+			constraints: nil,
+			expMatch:    true,
+		},
+		"a request without constraints should not match constraints": {
+			csr:         csrFor(nil),
+			constraints: constraints,
+			expMatch:    false,
+		},
+		"a request with constraints should not match no constraints": {
+			csr:         csrFor(constraints),
+			constraints: nil,
+			expMatch:    false,
+		},
+		"a request with a different path length should not match": {
+			csr: csrFor(constraints),
+			constraints: &cmapi.CertificateCAConstraints{
+				MaxPathLen:      &one,
+				NameConstraints: constraints.NameConstraints,
+			},
+			expMatch: false,
+		},
+		"a request with different name constraints should not match": {
+			csr: csrFor(constraints),
+			constraints: &cmapi.CertificateCAConstraints{
+				MaxPathLen: &zero,
+				NameConstraints: &cmapi.NameConstraints{
+					Excluded: &cmapi.NameConstraintItem{DNSDomains: []string{"example.com"}},
+				},
+			},
+			expMatch: false,
+		},
+		"a request with the same constraints should match": {
+			csr:         csrFor(constraints),
+			constraints: constraints,
+			expMatch:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			match, err := RequestMatchesCAConstraints(test.csr, test.constraints)
+			require.NoError(t, err)
+			assert.Equal(t, test.expMatch, match)
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"math/big"
 	"net"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -210,6 +211,14 @@ func GenerateCSR(crt *v1.Certificate) (*x509.CertificateRequest, error) {
 		}
 	}
 
+	if crt.Spec.IsCA && crt.Spec.CAConstraints != nil {
+		caExtensions, err := buildCAConstraintsExtensions(crt.Spec.CAConstraints)
+		if err != nil {
+			return nil, err
+		}
+		extraExtensions = append(extraExtensions, caExtensions...)
+	}
+
 	return &x509.CertificateRequest{
 		// Version 0 is the only one defined in the PKCS#10 standard, RFC2986.
 		// This value isn't used by Go at the time of writing.
@@ -303,7 +312,7 @@ func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
 		return nil, err
 	}
 
-	return &x509.Certificate{
+	template := &x509.Certificate{
 		// Version must be 2 according to RFC5280.
 		// A version value of 2 confusingly means version 3.
 		// This value isn't used by Go at the time of writing.
@@ -333,7 +342,15 @@ func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
 		IPAddresses:    ipAddresses,
 		URIs:           uris,
 		EmailAddresses: crt.Spec.EmailAddresses,
-	}, nil
+	}
+
+	if crt.Spec.IsCA {
+		if err := applyCAConstraints(template, crt.Spec.CAConstraints); err != nil {
+			return nil, err
+		}
+	}
+
+	return template, nil
 }
 
 // GenerateTemplate will create a x509.Certificate for the given
@@ -399,6 +416,138 @@ func GenerateTemplateFromCSRPEMWithUsages(csrPEM []byte, duration time.Duration,
 	}, nil
 }
 
+// OIDExtensionBasicConstraints is the OID of the X.509 basic constraints
+// extension, see RFC 5280, 4.2.1.9.
+var OIDExtensionBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+
+// basicConstraints is the ASN.1 structure of the basic constraints extension.
+// Copied from x509.go
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+// DurationFromCertificateRequest returns the duration requested by the given
+// CertificateRequest. spec.duration takes precedence over the
+// "cert-manager.io/request-duration" annotation, and the cert-manager default
+// certificate duration is returned when neither is set.
+func DurationFromCertificateRequest(cr *v1.CertificateRequest) (time.Duration, error) {
+	if cr.Spec.Duration != nil {
+		return cr.Spec.Duration.Duration, nil
+	}
+
+	requestedDuration, ok := cr.Annotations[v1.CertificateRequestDurationAnnotationKey]
+	if !ok {
+		return v1.DefaultCertificateDuration, nil
+	}
+
+	duration, err := time.ParseDuration(requestedDuration)
+	if err != nil {
+		return -1, fmt.Errorf("failed to parse requested duration on annotation %q: %w",
+			v1.CertificateRequestDurationAnnotationKey, err)
+	}
+	if duration <= 0 {
+		return -1, fmt.Errorf("requested duration on annotation %q must be greater than zero, got %q",
+			v1.CertificateRequestDurationAnnotationKey, requestedDuration)
+	}
+
+	return duration, nil
+}
+
+// ApplyCSRRequestedExtensions updates the template with the key usages,
+// extended key usages, basic constraints, name constraints, CRL distribution
+// points and authority information access requested by the extensions of the
+// given PEM encoded CSR. Requested usages are added to those already present
+// on the template. A CSR requesting a CA certificate marks the template as a
+// CA, along with any maximum path length it requests.
+func ApplyCSRRequestedExtensions(template *x509.Certificate, csrPEM []byte) error {
+	csr, err := DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return err
+	}
+
+	for _, ext := range csr.Extensions {
+		switch {
+		case ext.Id.Equal(OIDExtensionKeyUsage):
+			// RFC 5280, 4.2.1.3
+			var asn1bits asn1.BitString
+			if _, err := asn1.Unmarshal(ext.Value, &asn1bits); err != nil {
+				return fmt.Errorf("failed to decode csr key usages: %w", err)
+			}
+			for i := 0; i < 9; i++ {
+				if asn1bits.At(i) != 0 {
+					template.KeyUsage |= 1 << uint(i)
+				}
+			}
+
+		case ext.Id.Equal(OIDExtensionExtendedKeyUsage):
+			// RFC 5280, 4.2.1.12
+			var oids []asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(ext.Value, &oids); err != nil {
+				return fmt.Errorf("failed to decode csr extended key usages: %w", err)
+			}
+			for _, oid := range oids {
+				eku, ok := ExtKeyUsageFromOID(oid)
+				if !ok {
+					template.UnknownExtKeyUsage = append(template.UnknownExtKeyUsage, oid)
+					continue
+				}
+				if !hasExtKeyUsage(template.ExtKeyUsage, eku) {
+					template.ExtKeyUsage = append(template.ExtKeyUsage, eku)
+				}
+			}
+
+		case ext.Id.Equal(OIDExtensionBasicConstraints):
+			// RFC 5280, 4.2.1.9
+			var constraints basicConstraints
+			if _, err := asn1.Unmarshal(ext.Value, &constraints); err != nil {
+				return fmt.Errorf("failed to decode csr basic constraints: %w", err)
+			}
+			if !constraints.IsCA {
+				continue
+			}
+			template.IsCA = true
+			template.KeyUsage |= x509.KeyUsageCertSign
+			template.MaxPathLen = constraints.MaxPathLen
+			template.MaxPathLenZero = constraints.MaxPathLen == 0
+
+		default:
+			if err := applyCAConstraintsExtension(template, ext); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyMaxPathLen sets the maximum path length of the CA certificate template
+// to the given decimal value. An error is returned if the value is not a
+// non-negative integer, or if the template is not for a CA certificate.
+func ApplyMaxPathLen(template *x509.Certificate, maxPathLen string) error {
+	n, err := strconv.Atoi(maxPathLen)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid maximum path length %q: must be a non-negative integer", maxPathLen)
+	}
+	if !template.IsCA {
+		return errors.New("a maximum path length can only be set on CA certificates")
+	}
+
+	template.MaxPathLen = n
+	template.MaxPathLenZero = n == 0
+
+	return nil
+}
+
+func hasExtKeyUsage(ekus []x509.ExtKeyUsage, eku x509.ExtKeyUsage) bool {
+	for _, e := range ekus {
+		if e == eku {
+			return true
+		}
+	}
+	return false
+}
+
 // SignCertificate returns a signed *x509.Certificate given a template
 // *x509.Certificate crt and an issuer.
 // publicKey is the public key of the signee, and signerKey is the private
@@ -19,9 +19,11 @@ package pki
 import (
 	"bytes"
 	"crypto"
+	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/pem"
 	"math/big"
 	"reflect"
 	"testing"
@@ -29,6 +31,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/util"
@@ -762,3 +765,191 @@ func TestEncodeX509Chain(t *testing.T) {
 		})
 	}
 }
+
+func TestDurationFromCertificateRequest(t *testing.T) {
+	tests := map[string]struct {
+		duration    *metav1.Duration
+		annotations map[string]string
+		expDuration time.Duration
+		expErr      bool
+	}{
+		"no duration or annotation should return the default duration": {
+			expDuration: cmapi.DefaultCertificateDuration,
+		},
+		"spec.duration should take precedence over the annotation": {
+			duration:    &metav1.Duration{Duration: time.Hour},
+			annotations: map[string]string{cmapi.CertificateRequestDurationAnnotationKey: "2h"},
+			expDuration: time.Hour,
+		},
+		"annotation should be used if spec.duration is not set": {
+			annotations: map[string]string{cmapi.CertificateRequestDurationAnnotationKey: "2h"},
+			expDuration: 2 * time.Hour,
+		},
+		"an unparsable annotation should error": {
+			annotations: map[string]string{cmapi.CertificateRequestDurationAnnotationKey: "foo"},
+			expDuration: -1,
+			expErr:      true,
+		},
+		"a negative annotation should error": {
+			annotations: map[string]string{cmapi.CertificateRequestDurationAnnotationKey: "-1h"},
+			expDuration: -1,
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &cmapi.CertificateRequest{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Spec:       cmapi.CertificateRequestSpec{Duration: test.duration},
+			}
+			duration, err := DurationFromCertificateRequest(cr)
+			assert.Equal(t, test.expErr, err != nil, "%v", err)
+			assert.Equal(t, test.expDuration, duration)
+		})
+	}
+}
+
+func TestApplyCSRRequestedExtensions(t *testing.T) {
+	mustMarshal := func(v interface{}) []byte {
+		b, err := asn1.Marshal(v)
+		require.NoError(t, err)
+		return b
+	}
+	mustCSRPEM := func(exts ...pkix.Extension) []byte {
+		pk, err := GenerateECPrivateKey(256)
+		require.NoError(t, err)
+		der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject:         pkix.Name{CommonName: "test"},
+			ExtraExtensions: exts,
+		}, pk)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+	}
+
+	caExt := func(maxPathLen int) pkix.Extension {
+		return pkix.Extension{
+			Id:    OIDExtensionBasicConstraints,
+			Value: mustMarshal(basicConstraints{IsCA: true, MaxPathLen: maxPathLen}),
+		}
+	}
+	kuExt, err := buildASN1KeyUsageRequest(x509.KeyUsageDigitalSignature | x509.KeyUsageCRLSign)
+	require.NoError(t, err)
+	ekuExt := pkix.Extension{
+		Id:    OIDExtensionExtendedKeyUsage,
+		Value: mustMarshal([]asn1.ObjectIdentifier{oidExtKeyUsageServerAuth, oidExtKeyUsageClientAuth}),
+	}
+
+	tests := map[string]struct {
+		csrPEM      []byte
+		template    *x509.Certificate
+		expTemplate *x509.Certificate
+		expErr      bool
+	}{
+		"a CSR without extensions should not change the template": {
+			csrPEM:      mustCSRPEM(),
+			template:    &x509.Certificate{KeyUsage: x509.KeyUsageDigitalSignature},
+			expTemplate: &x509.Certificate{KeyUsage: x509.KeyUsageDigitalSignature},
+		},
+		"requested usages should be added to the template usages": {
+			csrPEM: mustCSRPEM(kuExt, ekuExt),
+			template: &x509.Certificate{
+				KeyUsage:    x509.KeyUsageKeyEncipherment,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			},
+			expTemplate: &x509.Certificate{
+				KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCRLSign,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			},
+		},
+		"a requested CA without a path length should mark the template as a CA": {
+			csrPEM:      mustCSRPEM(caExt(-1)),
+			template:    &x509.Certificate{},
+			expTemplate: &x509.Certificate{IsCA: true, KeyUsage: x509.KeyUsageCertSign, MaxPathLen: -1},
+		},
+		"a requested CA with a path length should copy the path length": {
+			csrPEM:      mustCSRPEM(caExt(2)),
+			template:    &x509.Certificate{},
+			expTemplate: &x509.Certificate{IsCA: true, KeyUsage: x509.KeyUsageCertSign, MaxPathLen: 2},
+		},
+		"a requested CA with a zero path length should set MaxPathLenZero": {
+			csrPEM:      mustCSRPEM(caExt(0)),
+			template:    &x509.Certificate{},
+			expTemplate: &x509.Certificate{IsCA: true, KeyUsage: x509.KeyUsageCertSign, MaxPathLen: 0, MaxPathLenZero: true},
+		},
+		"non-CA basic constraints should not change the template": {
+			csrPEM: mustCSRPEM(pkix.Extension{
+				Id:    OIDExtensionBasicConstraints,
+				Value: mustMarshal(basicConstraints{MaxPathLen: -1}),
+			}),
+			template:    &x509.Certificate{IsCA: true},
+			expTemplate: &x509.Certificate{IsCA: true},
+		},
+		"a malformed extension should error": {
+			csrPEM:      mustCSRPEM(pkix.Extension{Id: OIDExtensionBasicConstraints, Value: []byte("foo")}),
+			template:    &x509.Certificate{},
+			expTemplate: &x509.Certificate{},
+			expErr:      true,
+		},
+		"an invalid CSR should error": {
+			csrPEM:      []byte("foo"),
+			template:    &x509.Certificate{},
+			expTemplate: &x509.Certificate{},
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ApplyCSRRequestedExtensions(test.template, test.csrPEM)
+			assert.Equal(t, test.expErr, err != nil, "%v", err)
+			assert.Equal(t, test.expTemplate, test.template)
+		})
+	}
+}
+
+func TestApplyMaxPathLen(t *testing.T) {
+	tests := map[string]struct {
+		template    *x509.Certificate
+		maxPathLen  string
+		expTemplate *x509.Certificate
+		expErr      bool
+	}{
+		"a positive path length should be set on a CA template": {
+			template:    &x509.Certificate{IsCA: true, MaxPathLen: -1},
+			maxPathLen:  "3",
+			expTemplate: &x509.Certificate{IsCA: true, MaxPathLen: 3},
+		},
+		"a zero path length should set MaxPathLenZero": {
+			template:    &x509.Certificate{IsCA: true, MaxPathLen: -1},
+			maxPathLen:  "0",
+			expTemplate: &x509.Certificate{IsCA: true, MaxPathLen: 0, MaxPathLenZero: true},
+		},
+		"a negative path length should error": {
+			template:    &x509.Certificate{IsCA: true},
+			maxPathLen:  "-1",
+			expTemplate: &x509.Certificate{IsCA: true},
+			expErr:      true,
+		},
+		"a non-integer path length should error": {
+			template:    &x509.Certificate{IsCA: true},
+			maxPathLen:  "foo",
+			expTemplate: &x509.Certificate{IsCA: true},
+			expErr:      true,
+		},
+		"a path length on a non-CA template should error": {
+			template:    &x509.Certificate{},
+			maxPathLen:  "1",
+			expTemplate: &x509.Certificate{},
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ApplyMaxPathLen(test.template, test.maxPathLen)
+			assert.Equal(t, test.expErr, err != nil, "%v", err)
+			assert.Equal(t, test.expTemplate, test.template)
+		})
+	}
+}
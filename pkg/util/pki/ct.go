@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// OIDExtensionCTPoison is the OID of the RFC 6962 precertificate poison
+	// extension, which makes a precertificate unusable as a certificate.
+	OIDExtensionCTPoison = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3}
+
+	// OIDExtensionCTSCTList is the OID of the RFC 6962 extension used to
+	// embed a list of signed certificate timestamps (SCTs) in a certificate.
+	OIDExtensionCTSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+)
+
+// maxTLSVectorLength is the maximum length of a TLS vector with a 2 byte
+// length prefix, as used by the SignedCertificateTimestampList structure.
+const maxTLSVectorLength = 1<<16 - 1
+
+// CTPoisonExtension returns the critical poison extension which must be added
+// to a certificate template to sign a precertificate.
+func CTPoisonExtension() pkix.Extension {
+	return pkix.Extension{
+		Id:       OIDExtensionCTPoison,
+		Critical: true,
+		Value:    asn1.NullBytes,
+	}
+}
+
+// SCTListExtension returns an extension embedding the given TLS serialized
+// SignedCertificateTimestamp structures as a SignedCertificateTimestampList,
+// as defined in section 3.3 of RFC 6962.
+func SCTListExtension(scts [][]byte) (pkix.Extension, error) {
+	if len(scts) == 0 {
+		return pkix.Extension{}, errors.New("at least one SCT must be given")
+	}
+
+	var list []byte
+	for i, sct := range scts {
+		if len(sct) == 0 || len(sct) > maxTLSVectorLength {
+			return pkix.Extension{}, fmt.Errorf("SCT %d has an invalid length of %d bytes", i, len(sct))
+		}
+		list = append(list, uint16Bytes(len(sct))...)
+		list = append(list, sct...)
+	}
+	if len(list) > maxTLSVectorLength {
+		return pkix.Extension{}, fmt.Errorf("SCT list is too long: %d bytes", len(list))
+	}
+
+	value, err := asn1.Marshal(append(uint16Bytes(len(list)), list...))
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    OIDExtensionCTSCTList,
+		Value: value,
+	}, nil
+}
+
+func uint16Bytes(n int) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, uint16(n))
+	return b
+}
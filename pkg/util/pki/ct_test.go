@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"testing"
+)
+
+func TestCTPoisonExtension(t *testing.T) {
+	ext := CTPoisonExtension()
+	if !ext.Id.Equal(OIDExtensionCTPoison) {
+		t.Errorf("unexpected extension id: %s", ext.Id)
+	}
+	if !ext.Critical {
+		t.Error("expected poison extension to be critical")
+	}
+	if !bytes.Equal(ext.Value, []byte{0x05, 0x00}) {
+		t.Errorf("expected poison extension value to be ASN.1 NULL, got %x", ext.Value)
+	}
+}
+
+func TestSCTListExtension(t *testing.T) {
+	tests := map[string]struct {
+		scts     [][]byte
+		expList  []byte
+		expError bool
+	}{
+		"no SCTs": {
+			expError: true,
+		},
+		"an empty SCT": {
+			scts:     [][]byte{{}},
+			expError: true,
+		},
+		"an SCT that is too long": {
+			scts:     [][]byte{make([]byte, maxTLSVectorLength+1)},
+			expError: true,
+		},
+		"a list that is too long": {
+			scts:     [][]byte{make([]byte, maxTLSVectorLength/2), make([]byte, maxTLSVectorLength/2)},
+			expError: true,
+		},
+		"a single SCT": {
+			scts:    [][]byte{{0x00, 0x01, 0x02}},
+			expList: []byte{0x00, 0x05, 0x00, 0x03, 0x00, 0x01, 0x02},
+		},
+		"multiple SCTs": {
+			scts:    [][]byte{{0xaa}, {0xbb, 0xcc}},
+			expList: []byte{0x00, 0x07, 0x00, 0x01, 0xaa, 0x00, 0x02, 0xbb, 0xcc},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ext, err := SCTListExtension(test.scts)
+			if test.expError != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expError, err)
+			}
+			if test.expError {
+				return
+			}
+
+			if !ext.Id.Equal(OIDExtensionCTSCTList) {
+				t.Errorf("unexpected extension id: %s", ext.Id)
+			}
+			if ext.Critical {
+				t.Error("expected SCT list extension to not be critical")
+			}
+			var list []byte
+			if rest, err := asn1.Unmarshal(ext.Value, &list); err != nil || len(rest) > 0 {
+				t.Fatalf("extension value is not a single OCTET STRING: %v", err)
+			}
+			if !bytes.Equal(list, test.expList) {
+				t.Errorf("unexpected SCT list, exp=%x got=%x", test.expList, list)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pki contains helpers for generating, encoding, parsing and
+// validating private keys, certificate signing requests and X.509
+// certificates, including building ordered certificate chains and comparing
+// issued certificates and keys against a Certificate's spec.
+//
+// Unlike most packages in this repository, pki is a supported public API
+// intended for use by external issuers and other projects that integrate
+// with cert-manager. Exported identifiers follow semantic versioning along
+// with cert-manager releases: they will not be removed or have their
+// signatures or documented behaviour changed in a backwards incompatible way
+// outside of a major release. Functionality that is deprecated is marked as
+// such in its doc comment for at least one minor release before removal.
+package pki
@@ -177,6 +177,94 @@ func ParseSingleCertificateChainPEM(pembundle []byte) (PEMBundle, error) {
 // An error is returned if the passed bundle is not a valid flat tree chain,
 // the bundle is malformed, or the chain is broken.
 func ParseSingleCertificateChain(certs []*x509.Certificate) (PEMBundle, error) {
+	chain, err := buildChain(certs)
+	if err != nil {
+		return PEMBundle{}, err
+	}
+
+	return chain.toBundleAndCA()
+}
+
+// BuildCertificateChain orders the given certificates into a single chain,
+// starting with the leaf certificate and ending with the top-most certificate,
+// which may be a root or an intermediate. Unlike ParseSingleCertificateChain,
+// the top-most certificate is always included in the returned chain, even if
+// it is self-signed.
+//
+// Duplicate certificates are removed. The given slice is not modified.
+//
+// An error is returned if the certificates do not form a single unbroken
+// chain.
+func BuildCertificateChain(certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	chain, err := buildChain(certs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ordered []*x509.Certificate
+	for c := chain; c != nil; c = c.issuer {
+		ordered = append(ordered, c.cert)
+	}
+
+	return ordered, nil
+}
+
+// ParseCertificateBundlePEM decodes a PEM encoded bundle of unrelated
+// certificates, such as a bundle of trusted CA certificates. PEM blocks which
+// are not certificates are skipped, and duplicate certificates are removed,
+// keeping the order in which they first appear.
+//
+// An error is returned if a certificate fails to parse, or if the bundle does
+// not contain any certificates.
+func ParseCertificateBundlePEM(bundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.NewInvalidData("error parsing certificate in bundle: %s", err.Error())
+		}
+
+		duplicate := false
+		for _, existing := range certs {
+			if existing.Equal(cert) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			certs = append(certs, cert)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.NewInvalidData("no certificates found in bundle")
+	}
+
+	return certs, nil
+}
+
+// buildChain builds a single chain out of the given certificates, returning
+// the node of the leaf certificate.
+func buildChain(certs []*x509.Certificate) (*chainNode, error) {
+	if len(certs) == 0 {
+		return nil, errors.NewInvalidData("no certificates given")
+	}
+
+	// Copy the certificates so the caller's slice is not modified when
+	// removing duplicates.
+	certs = append([]*x509.Certificate(nil), certs...)
+
 	// De-duplicate certificates. This moves "complicated" logic away from
 	// consumers and into a shared function, who would otherwise have to do this
 	// anyway.
@@ -235,12 +323,12 @@ func ParseSingleCertificateChain(certs []*x509.Certificate) (PEMBundle, error) {
 		// If no chains were merged in this pass, the chain can never be built as a
 		// single list. Error.
 		if lastChainsLength == len(chains) {
-			return PEMBundle{}, errors.NewInvalidData("certificate chain is malformed or broken")
+			return nil, errors.NewInvalidData("certificate chain is malformed or broken")
 		}
 	}
 
-	// There is only a single chain left at index 0. Return chain as PEM.
-	return chains[0].toBundleAndCA()
+	// There is only a single chain left at index 0.
+	return chains[0], nil
 }
 
 // toBundleAndCA will return the PEM bundle of this chain.
@@ -361,3 +361,122 @@ func TestParseSingleCertificateChain(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildCertificateChain(t *testing.T) {
+	root := mustCreateBundle(t, nil, "root")
+	intA1 := mustCreateBundle(t, root, "intA-1")
+	intA2 := mustCreateBundle(t, intA1, "intA-2")
+	leaf := mustCreateBundle(t, intA2, "leaf")
+	random := mustCreateBundle(t, nil, "random")
+
+	tests := map[string]struct {
+		input    []*x509.Certificate
+		expChain []*x509.Certificate
+		expErr   bool
+	}{
+		"if no certificates are given, should error": {
+			input:  nil,
+			expErr: true,
+		},
+		"if a single certificate is given, should return that certificate": {
+			input:    []*x509.Certificate{leaf.cert},
+			expChain: []*x509.Certificate{leaf.cert},
+		},
+		"if an unordered chain with a root is given, should order it from the leaf and include the root": {
+			input:    []*x509.Certificate{intA1.cert, root.cert, leaf.cert, intA2.cert},
+			expChain: []*x509.Certificate{leaf.cert, intA2.cert, intA1.cert, root.cert},
+		},
+		"if a chain without a root is given, should end with the top-most intermediate": {
+			input:    []*x509.Certificate{intA2.cert, leaf.cert, intA1.cert},
+			expChain: []*x509.Certificate{leaf.cert, intA2.cert, intA1.cert},
+		},
+		"if duplicate certificates are given, should remove them": {
+			input:    []*x509.Certificate{leaf.cert, intA2.cert, leaf.cert, intA1.cert, intA2.cert},
+			expChain: []*x509.Certificate{leaf.cert, intA2.cert, intA1.cert},
+		},
+		"if the chain is broken, should error": {
+			input:  []*x509.Certificate{leaf.cert, intA1.cert},
+			expErr: true,
+		},
+		"if an unrelated certificate is given, should error": {
+			input:  []*x509.Certificate{leaf.cert, intA2.cert, random.cert},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := append([]*x509.Certificate(nil), test.input...)
+
+			chain, err := BuildCertificateChain(test.input)
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			if !reflect.DeepEqual(chain, test.expChain) {
+				t.Errorf("unexpected chain, exp=%v got=%v", subjects(test.expChain), subjects(chain))
+			}
+
+			if !reflect.DeepEqual(input, test.input) {
+				t.Errorf("input certificates were modified")
+			}
+		})
+	}
+}
+
+func TestParseCertificateBundlePEM(t *testing.T) {
+	rootA := mustCreateBundle(t, nil, "root-a")
+	rootB := mustCreateBundle(t, nil, "root-b")
+	keyPEM, err := EncodePrivateKey(rootA.pk, v1.PKCS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		inputBundle []byte
+		expCerts    []*x509.Certificate
+		expErr      bool
+	}{
+		"if the bundle is empty, should error": {
+			inputBundle: nil,
+			expErr:      true,
+		},
+		"if the bundle contains no certificates, should error": {
+			inputBundle: keyPEM,
+			expErr:      true,
+		},
+		"if the bundle contains an invalid certificate, should error": {
+			inputBundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")}),
+			expErr:      true,
+		},
+		"if the bundle contains unrelated certificates, should return them in order": {
+			inputBundle: joinPEM(nil, rootB.pem, rootA.pem),
+			expCerts:    []*x509.Certificate{rootB.cert, rootA.cert},
+		},
+		"if the bundle contains duplicates and other PEM blocks, should skip them": {
+			inputBundle: joinPEM(nil, rootA.pem, keyPEM, rootB.pem, rootA.pem),
+			expCerts:    []*x509.Certificate{rootA.cert, rootB.cert},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certs, err := ParseCertificateBundlePEM(test.inputBundle)
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			if !reflect.DeepEqual(certs, test.expCerts) {
+				t.Errorf("unexpected certificates, exp=%v got=%v", subjects(test.expCerts), subjects(certs))
+			}
+		})
+	}
+}
+
+func subjects(certs []*x509.Certificate) []string {
+	var names []string
+	for _, cert := range certs {
+		names = append(names, cert.Subject.CommonName)
+	}
+	return names
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// WithNextTrustAnchors returns the PEM encoded CA certificates of a
+// certificate signed by a CA issuer, followed by the certificates of the next
+// CAs of a planned rollover that are advertised in the issuer's status. Any
+// certificates following the issuer's current CA certificate are replaced, so
+// that the next CAs reflect the issuer's current status. The CA certificates
+// are returned unchanged if they cannot be decoded, or if they don't contain
+// the issuer's current CA certificate, in which case the certificate was
+// signed by another CA.
+func WithNextTrustAnchors(caPEM []byte, status *v1.CAIssuerStatus) []byte {
+	if status == nil || len(status.TrustAnchors) == 0 {
+		return caPEM
+	}
+
+	cas, err := DecodeX509CertificateChainBytes(caPEM)
+	if err != nil {
+		return caPEM
+	}
+
+	end := -1
+	var next []*x509.Certificate
+	for _, anchor := range status.TrustAnchors {
+		cert, err := DecodeX509CertificateBytes(anchor.Certificate)
+		if err != nil {
+			return caPEM
+		}
+		if anchor.Next {
+			next = append(next, cert)
+			continue
+		}
+		for i, ca := range cas {
+			if ca.Equal(cert) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return caPEM
+	}
+
+	anchors := append([]*x509.Certificate{}, cas[:end+1]...)
+	for _, cert := range next {
+		if !containsCertificate(anchors, cert) {
+			anchors = append(anchors, cert)
+		}
+	}
+	if len(anchors) == len(cas) {
+		unchanged := true
+		for i := range anchors {
+			unchanged = unchanged && anchors[i].Equal(cas[i])
+		}
+		if unchanged {
+			return caPEM
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for _, cert := range anchors {
+		if err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return caPEM
+		}
+	}
+	return buf.Bytes()
+}
+
+func containsCertificate(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"testing"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestWithNextTrustAnchors(t *testing.T) {
+	current := mustCreateBundle(t, nil, "current")
+	next := mustCreateBundle(t, nil, "next")
+	other := mustCreateBundle(t, nil, "other")
+	intermediate := mustCreateBundle(t, current, "intermediate")
+
+	tests := map[string]struct {
+		ca       []byte
+		status   *v1.CAIssuerStatus
+		expected []byte
+	}{
+		"no issuer status returns the CA": {
+			ca:       current.pem,
+			expected: current.pem,
+		},
+		"the next CA is appended after the current CA": {
+			ca: current.pem,
+			status: &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{
+				{Certificate: current.pem},
+				{Certificate: next.pem, Next: true},
+			}},
+			expected: joinPEM(current.pem, next.pem),
+		},
+		"the next CA is appended after a CA bundle ending with the current CA": {
+			ca: joinPEM(intermediate.pem, current.pem),
+			status: &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{
+				{Certificate: current.pem},
+				{Certificate: next.pem, Next: true},
+			}},
+			expected: joinPEM(intermediate.pem, current.pem, next.pem),
+		},
+		"the next CA is not appended again": {
+			ca: joinPEM(current.pem, next.pem),
+			status: &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{
+				{Certificate: current.pem},
+				{Certificate: next.pem, Next: true},
+			}},
+			expected: joinPEM(current.pem, next.pem),
+		},
+		"a next CA that has been replaced is removed": {
+			ca: joinPEM(current.pem, other.pem),
+			status: &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{
+				{Certificate: current.pem},
+				{Certificate: next.pem, Next: true},
+			}},
+			expected: joinPEM(current.pem, next.pem),
+		},
+		"the next CA is not appended once it is the signing CA": {
+			ca: next.pem,
+			status: &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{
+				{Certificate: next.pem},
+				{Certificate: next.pem, Next: true},
+			}},
+			expected: next.pem,
+		},
+		"a CA that does not contain the current CA is not changed": {
+			ca: other.pem,
+			status: &v1.CAIssuerStatus{TrustAnchors: []v1.CATrustAnchor{
+				{Certificate: current.pem},
+				{Certificate: next.pem, Next: true},
+			}},
+			expected: other.pem,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ca := append([]byte{}, test.ca...)
+			got := WithNextTrustAnchors(ca, test.status)
+			if !bytes.Equal(test.expected, got) {
+				t.Errorf("unexpected CA, exp=%q got=%q", test.expected, got)
+			}
+			if !bytes.Equal(test.ca, ca) {
+				t.Errorf("the given CA should not be modified")
+			}
+		})
+	}
+}
@@ -44,3 +44,64 @@ func CertificateNextPrivateKeySecretName(name string) Func {
 		return *crt.Status.NextPrivateKeySecretName == name
 	}
 }
+
+// CertificateKeystorePasswordSecretName returns a predicate that used to
+// filter Certificates to only those with a keystore or truststore that is
+// enabled and whose password is stored in the given Secret.
+func CertificateKeystorePasswordSecretName(name string) Func {
+	return func(obj runtime.Object) bool {
+		crt := obj.(*cmapi.Certificate)
+		keystores := crt.Spec.Keystores
+		if keystores == nil {
+			return false
+		}
+		if pkcs12 := keystores.PKCS12; pkcs12 != nil && pkcs12.Create && pkcs12.PasswordSecretRef.Name == name {
+			return true
+		}
+		if jks := keystores.JKS; jks != nil {
+			if jks.Create && jks.PasswordSecretRef.Name == name {
+				return true
+			}
+			if jks.Truststore != nil && jks.Truststore.PasswordSecretRef.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CertificateIssuer returns a predicate that used to filter Certificates to
+// only those that reference the given cert-manager Issuer or ClusterIssuer in
+// 'spec.issuerRef'. Objects that are not issuers match no Certificates.
+func CertificateIssuer(obj runtime.Object) Func {
+	var kind, name, namespace string
+	switch iss := obj.(type) {
+	case *cmapi.Issuer:
+		kind, name, namespace = cmapi.IssuerKind, iss.Name, iss.Namespace
+	case *cmapi.ClusterIssuer:
+		kind, name = cmapi.ClusterIssuerKind, iss.Name
+	default:
+		return func(runtime.Object) bool { return false }
+	}
+	return func(obj runtime.Object) bool {
+		crt := obj.(*cmapi.Certificate)
+		ref := crt.Spec.IssuerRef
+		if ref.Group != "" && ref.Group != cmapi.SchemeGroupVersion.Group {
+			return false
+		}
+		refKind := ref.Kind
+		if refKind == "" {
+			refKind = cmapi.IssuerKind
+		}
+		if refKind != kind || ref.Name != name {
+			return false
+		}
+		if kind == cmapi.ClusterIssuerKind {
+			return true
+		}
+		if ref.Namespace != "" {
+			return ref.Namespace == namespace
+		}
+		return crt.Namespace == namespace
+	}
+}
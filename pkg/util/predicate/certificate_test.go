@@ -19,9 +19,12 @@ package predicate
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
 
 func TestCertificateSecretName(t *testing.T) {
@@ -92,3 +95,143 @@ func TestCertificateNextPrivateKeySecretName(t *testing.T) {
 		})
 	}
 }
+
+func TestCertificateKeystorePasswordSecretName(t *testing.T) {
+	passwordRef := func(name string) cmmeta.SecretKeySelector {
+		return cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: name}, Key: "password"}
+	}
+	certWithKeystores := func(keystores *cmapi.CertificateKeystores) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			Spec: cmapi.CertificateSpec{Keystores: keystores},
+		}
+	}
+	tests := map[string]struct {
+		secretName string
+		cert       *cmapi.Certificate
+		expected   bool
+	}{
+		"returns true if PKCS12 keystore password secret name matches": {
+			secretName: "abc",
+			cert: certWithKeystores(&cmapi.CertificateKeystores{
+				PKCS12: &cmapi.PKCS12Keystore{Create: true, PasswordSecretRef: passwordRef("abc")},
+			}),
+			expected: true,
+		},
+		"returns true if JKS keystore password secret name matches": {
+			secretName: "abc",
+			cert: certWithKeystores(&cmapi.CertificateKeystores{
+				JKS: &cmapi.JKSKeystore{Create: true, PasswordSecretRef: passwordRef("abc")},
+			}),
+			expected: true,
+		},
+		"returns true if JKS truststore password secret name matches": {
+			secretName: "abc",
+			cert: certWithKeystores(&cmapi.CertificateKeystores{
+				JKS: &cmapi.JKSKeystore{Truststore: &cmapi.JKSTruststore{PasswordSecretRef: passwordRef("abc")}},
+			}),
+			expected: true,
+		},
+		"returns false if keystore is not enabled": {
+			secretName: "abc",
+			cert: certWithKeystores(&cmapi.CertificateKeystores{
+				PKCS12: &cmapi.PKCS12Keystore{Create: false, PasswordSecretRef: passwordRef("abc")},
+			}),
+			expected: false,
+		},
+		"returns false if secret name does not match": {
+			secretName: "abc",
+			cert: certWithKeystores(&cmapi.CertificateKeystores{
+				JKS: &cmapi.JKSKeystore{Create: true, PasswordSecretRef: passwordRef("abcd")},
+			}),
+			expected: false,
+		},
+		"returns false if no keystores are configured": {
+			secretName: "abc",
+			cert:       certWithKeystores(nil),
+			expected:   false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := CertificateKeystorePasswordSecretName(test.secretName)(test.cert)
+			if got != test.expected {
+				t.Errorf("unexpected response: got=%t, exp=%t", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCertificateIssuer(t *testing.T) {
+	certWithIssuerRef := func(ns string, ref cmmeta.ObjectReference) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns},
+			Spec:       cmapi.CertificateSpec{IssuerRef: ref},
+		}
+	}
+	issuer := &cmapi.Issuer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "abc"}}
+	clusterIssuer := &cmapi.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "abc"}}
+	tests := map[string]struct {
+		issuer   runtime.Object
+		cert     *cmapi.Certificate
+		expected bool
+	}{
+		"returns true if Issuer matches": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abc", Kind: "Issuer", Group: "cert-manager.io"}),
+			expected: true,
+		},
+		"returns true if Issuer matches with an empty kind and group": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abc"}),
+			expected: true,
+		},
+		"returns false if Issuer is in a different namespace": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("other", cmmeta.ObjectReference{Name: "abc"}),
+			expected: false,
+		},
+		"returns true if Certificate references the Issuer's namespace": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("other", cmmeta.ObjectReference{Name: "abc", Namespace: "ns"}),
+			expected: true,
+		},
+		"returns false if Certificate references an Issuer in a different namespace": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abc", Namespace: "other"}),
+			expected: false,
+		},
+		"returns false if Issuer name does not match": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abcd"}),
+			expected: false,
+		},
+		"returns false if Certificate references a ClusterIssuer": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abc", Kind: "ClusterIssuer"}),
+			expected: false,
+		},
+		"returns false if Certificate references an external issuer": {
+			issuer:   issuer,
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abc", Kind: "Issuer", Group: "example.com"}),
+			expected: false,
+		},
+		"returns true if ClusterIssuer matches in any namespace": {
+			issuer:   clusterIssuer,
+			cert:     certWithIssuerRef("other", cmmeta.ObjectReference{Name: "abc", Kind: "ClusterIssuer"}),
+			expected: true,
+		},
+		"returns false if the object is not an issuer": {
+			issuer:   &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "abc"}},
+			cert:     certWithIssuerRef("ns", cmmeta.ObjectReference{Name: "abc"}),
+			expected: false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := CertificateIssuer(test.issuer)(test.cert)
+			if got != test.expected {
+				t.Errorf("unexpected response: got=%t, exp=%t", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spiffe contains helpers for building and validating SPIFFE IDs and
+// the X.509 SVIDs that identify workloads with them, as described by the
+// SPIFFE specifications at https://github.com/spiffe/spiffe.
+package spiffe
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// Scheme is the URI scheme of SPIFFE IDs.
+const Scheme = "spiffe"
+
+// DefaultSVIDDuration is the duration of X.509 SVIDs that do not set a
+// duration. SVIDs are expected to be rotated frequently by the workloads that
+// use them, so they are short lived.
+const DefaultSVIDDuration = time.Hour
+
+// maxIDLength is the maximum length of a SPIFFE ID, in bytes.
+const maxIDLength = 2048
+
+var (
+	trustDomainRegexp = regexp.MustCompile(`^[a-z0-9._-]+$`)
+	pathSegmentRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+)
+
+// SVIDKeyUsages returns the key usages of X.509 SVIDs, which may be used by
+// workloads to authenticate as both TLS servers and clients.
+func SVIDKeyUsages() []cmapi.KeyUsage {
+	return []cmapi.KeyUsage{
+		cmapi.UsageDigitalSignature,
+		cmapi.UsageKeyEncipherment,
+		cmapi.UsageServerAuth,
+		cmapi.UsageClientAuth,
+	}
+}
+
+// ValidateTrustDomain returns an error if the given name is not a valid
+// SPIFFE trust domain.
+func ValidateTrustDomain(trustDomain string) error {
+	if len(trustDomain) == 0 {
+		return fmt.Errorf("trust domain must not be empty")
+	}
+	if !trustDomainRegexp.MatchString(trustDomain) {
+		return fmt.Errorf("trust domain %q must only contain lowercase letters, digits, dots, dashes and underscores", trustDomain)
+	}
+	return nil
+}
+
+// WorkloadID returns the SPIFFE ID of the workloads running as the given
+// Kubernetes service account, in the form
+// spiffe://<trust domain>/ns/<namespace>/sa/<service account>.
+func WorkloadID(trustDomain, namespace, serviceAccount string) string {
+	return (&url.URL{
+		Scheme: Scheme,
+		Host:   trustDomain,
+		Path:   "/" + strings.Join([]string{"ns", namespace, "sa", serviceAccount}, "/"),
+	}).String()
+}
+
+// IsID returns whether the given URI uses the SPIFFE scheme. It does not
+// validate the rest of the URI.
+func IsID(uri *url.URL) bool {
+	return uri.Scheme == Scheme
+}
+
+// ValidateID returns an error if the given URI is not a valid SPIFFE ID for
+// a workload. The ID must have a path, since the ID of a trust domain itself
+// cannot be used in an X.509 SVID.
+func ValidateID(uri *url.URL) error {
+	if !IsID(uri) {
+		return fmt.Errorf("SPIFFE ID %q must use the %s scheme", uri, Scheme)
+	}
+	if len(uri.String()) > maxIDLength {
+		return fmt.Errorf("SPIFFE ID must not be longer than %d bytes", maxIDLength)
+	}
+	if len(uri.Opaque) > 0 || uri.User != nil || len(uri.Port()) > 0 {
+		return fmt.Errorf("SPIFFE ID %q must only have a trust domain and a path", uri)
+	}
+	if len(uri.RawQuery) > 0 || uri.ForceQuery || len(uri.Fragment) > 0 {
+		return fmt.Errorf("SPIFFE ID %q must not have a query or fragment", uri)
+	}
+	if err := ValidateTrustDomain(uri.Host); err != nil {
+		return fmt.Errorf("SPIFFE ID %q: %w", uri, err)
+	}
+	if len(uri.Path) == 0 || uri.Path == "/" {
+		return fmt.Errorf("SPIFFE ID %q must have a path identifying the workload", uri)
+	}
+	for _, segment := range strings.Split(strings.TrimPrefix(uri.Path, "/"), "/") {
+		if segment == "." || segment == ".." || !pathSegmentRegexp.MatchString(segment) {
+			return fmt.Errorf("SPIFFE ID %q has an invalid path segment %q", uri, segment)
+		}
+	}
+	return nil
+}
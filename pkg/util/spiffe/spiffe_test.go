@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spiffe
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestWorkloadID(t *testing.T) {
+	id := WorkloadID("cluster.local", "sandbox", "httpbin")
+	if id != "spiffe://cluster.local/ns/sandbox/sa/httpbin" {
+		t.Errorf("unexpected workload ID %q", id)
+	}
+	uri, err := url.Parse(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateID(uri); err != nil {
+		t.Errorf("expected the workload ID to be valid: %v", err)
+	}
+}
+
+func TestValidateTrustDomain(t *testing.T) {
+	tests := map[string]bool{
+		"cluster.local":   true,
+		"example_org-1.x": true,
+		"":                false,
+		"Cluster.local":   false,
+		"example.org:443": false,
+	}
+	for trustDomain, valid := range tests {
+		t.Run(trustDomain, func(t *testing.T) {
+			if err := ValidateTrustDomain(trustDomain); (err == nil) != valid {
+				t.Errorf("unexpected result validating %q, exp valid=%t got=%v", trustDomain, valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	tests := map[string]bool{
+		"spiffe://cluster.local/ns/sandbox/sa/httpbin": true,
+		"spiffe://example.org/service":                 true,
+		"https://example.org/service":                  false,
+		"spiffe://example.org":                         false,
+		"spiffe://example.org/":                        false,
+		"spiffe://Example.org/service":                 false,
+		"spiffe://example.org:443/service":             false,
+		"spiffe://user@example.org/service":            false,
+		"spiffe://example.org/service?query=1":         false,
+		"spiffe://example.org/service#fragment":        false,
+		"spiffe://example.org/ns//sa":                  false,
+		"spiffe://example.org/ns/../sa":                false,
+		"spiffe://example.org/ns/sandbox/":             false,
+		"spiffe://example.org/ns/sand%20box":           false,
+	}
+	for id, valid := range tests {
+		t.Run(id, func(t *testing.T) {
+			uri, err := url.Parse(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateID(uri); (err == nil) != valid {
+				t.Errorf("unexpected result validating %q, exp valid=%t got=%v", id, valid, err)
+			}
+		})
+	}
+}
@@ -72,6 +72,17 @@ type DynamicAuthority struct {
 	// Defaults to 7d.
 	LeafDuration time.Duration
 
+	// The amount of time that the previous root CA certificate continues to be
+	// used to sign leaf certificates after the root CA has been rotated.
+	// During this time, and for the same amount of time afterwards, both the
+	// previous and the new root CA certificates are published in the ca.crt
+	// key of the Secret, so that the cainjector can inject a CA bundle that
+	// trusts both before and after the serving certificate is replaced.
+	// This must be greater than the time taken by the cainjector to update the
+	// webhook configurations.
+	// Defaults to 1h.
+	CARotationOverlap time.Duration
+
 	// Logger to write messages to.
 	Log logr.Logger
 
@@ -108,6 +119,9 @@ func (d *DynamicAuthority) Run(stopCh <-chan struct{}) error {
 	if d.LeafDuration == 0 {
 		d.LeafDuration = time.Hour * 24 * 7 // 7d
 	}
+	if d.CARotationOverlap == 0 {
+		d.CARotationOverlap = time.Hour
+	}
 
 	cl, err := kubernetes.NewForConfig(d.RESTConfig)
 	if err != nil {
@@ -249,6 +263,20 @@ func (d *DynamicAuthority) ensureCA(ctx context.Context) error {
 	if d.caRequiresRegeneration(s) {
 		return d.regenerateCA(ctx, s.DeepCopy())
 	}
+	caBundle, err := d.caBundle(s)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(caBundle, s.Data[cmmeta.TLSCAKey]) {
+		d.Log.V(logf.InfoLevel).Info("Removing previous root CA certificate from CA bundle as the rotation overlap has ended")
+		s = s.DeepCopy()
+		s.Data[cmmeta.TLSCAKey] = caBundle
+		_, err := d.client.Update(ctx, s, metav1.UpdateOptions{})
+		return err
+	}
+	if d.rotationDeferred(s) {
+		return nil
+	}
 	d.notifyWatches(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey])
 	return nil
 }
@@ -291,8 +319,10 @@ func (d *DynamicAuthority) caRequiresRegeneration(s *corev1.Secret) bool {
 		d.Log.V(logf.InfoLevel).Info("Missing data in CA secret. Regenerating")
 		return true
 	}
-	// ensure that the ca.crt and tls.crt keys are equal
-	if !bytes.Equal(caData, certData) {
+	// ensure that the ca.crt key starts with the tls.crt key. Any further
+	// certificates in ca.crt are previous root CAs that are still published
+	// during a rotation overlap.
+	if !bytes.HasPrefix(caData, certData) {
 		return true
 	}
 	cert, err := tls.X509KeyPair(certData, pkData)
@@ -318,6 +348,88 @@ func (d *DynamicAuthority) caRequiresRegeneration(s *corev1.Secret) bool {
 	return false
 }
 
+// caBundle returns the data that should be stored in the ca.crt key of the
+// given Secret: the current root CA certificate, followed by the previous root
+// CA certificates that should still be trusted because the current one was
+// only recently rotated.
+// The Secret must have passed the checks in caRequiresRegeneration.
+func (d *DynamicAuthority) caBundle(s *corev1.Secret) ([]byte, error) {
+	certData := s.Data[corev1.TLSCertKey]
+	caData := s.Data[cmmeta.TLSCAKey]
+	if len(caData) == len(certData) {
+		return caData, nil
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(certData)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := pki.DecodeX509CertificateChainBytes(caData[len(certData):])
+	if err != nil {
+		d.Log.Error(err, "Failed to parse previous root CA certificates in CA secret. Removing")
+		return certData, nil
+	}
+
+	// previous root CAs are trusted until the new root CA has been used to
+	// sign leaf certificates for the overlap duration, so that every replica
+	// has stopped serving certificates signed by the previous CA.
+	trustUntil := cert.NotBefore.Add(2 * d.CARotationOverlap)
+	bundle := append([]byte{}, certData...)
+	now := time.Now()
+	for _, prev := range previous {
+		if now.After(trustUntil) || now.After(prev.NotAfter) {
+			continue
+		}
+		prevBytes, err := pki.EncodeX509(prev)
+		if err != nil {
+			return nil, err
+		}
+		bundle = append(bundle, prevBytes...)
+	}
+	return bundle, nil
+}
+
+// rotationDeferred returns true if the authority should continue to sign leaf
+// certificates using the root CA it currently holds, rather than the one
+// stored in the given Secret.
+// This is the case for the rotation overlap duration after the root CA has
+// been rotated, as the cainjector may not yet have injected the new root CA
+// into the webhook configurations. As the overlap is measured from the new root
+// CA's NotBefore time, all replicas switch over at the same time.
+func (d *DynamicAuthority) rotationDeferred(s *corev1.Secret) bool {
+	certData := s.Data[corev1.TLSCertKey]
+	if len(d.currentCertData) == 0 || bytes.Equal(d.currentCertData, certData) {
+		return false
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(certData)
+	if err != nil {
+		return false
+	}
+	useAfter := cert.NotBefore.Add(d.CARotationOverlap)
+	if time.Now().After(useAfter) {
+		return false
+	}
+
+	// only continue to use the current root CA if it is still valid and
+	// still published as a previous root CA
+	current, err := pki.DecodeX509CertificateBytes(d.currentCertData)
+	if err != nil || time.Now().After(current.NotAfter) {
+		return false
+	}
+	previous, err := pki.DecodeX509CertificateChainBytes(s.Data[cmmeta.TLSCAKey][len(certData):])
+	if err != nil {
+		return false
+	}
+	for _, prev := range previous {
+		if prev.Equal(current) {
+			d.Log.V(logf.DebugLevel).Info("Root CA has been rotated, continuing to sign with the previous root CA until the rotation overlap has passed", "use_after", useAfter)
+			return true
+		}
+	}
+	return false
+}
+
 var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
 
 // regenerateCA will regenerate and store a new CA.
@@ -360,6 +472,7 @@ func (d *DynamicAuthority) regenerateCA(ctx context.Context, s *corev1.Secret) e
 	if err != nil {
 		return err
 	}
+	caBytes := append(append([]byte{}, certBytes...), previousCABytes(s)...)
 
 	if s == nil {
 		_, err := d.client.Create(ctx, &corev1.Secret{
@@ -373,7 +486,7 @@ func (d *DynamicAuthority) regenerateCA(ctx context.Context, s *corev1.Secret) e
 			Data: map[string][]byte{
 				corev1.TLSCertKey:       certBytes,
 				corev1.TLSPrivateKeyKey: pkBytes,
-				cmmeta.TLSCAKey:         certBytes,
+				cmmeta.TLSCAKey:         caBytes,
 			},
 		}, metav1.CreateOptions{})
 		return err
@@ -384,7 +497,7 @@ func (d *DynamicAuthority) regenerateCA(ctx context.Context, s *corev1.Secret) e
 	}
 	s.Data[corev1.TLSCertKey] = certBytes
 	s.Data[corev1.TLSPrivateKeyKey] = pkBytes
-	s.Data[cmmeta.TLSCAKey] = certBytes
+	s.Data[cmmeta.TLSCAKey] = caBytes
 	if _, err := d.client.Update(ctx, s, metav1.UpdateOptions{}); err != nil {
 		return err
 	}
@@ -392,6 +505,24 @@ func (d *DynamicAuthority) regenerateCA(ctx context.Context, s *corev1.Secret) e
 	return nil
 }
 
+// previousCABytes returns the root CA certificate stored in the given Secret,
+// if any, so that it can continue to be published alongside a newly generated
+// root CA. Nothing is returned if the Secret does not contain a valid root CA.
+func previousCABytes(s *corev1.Secret) []byte {
+	if s == nil {
+		return nil
+	}
+	certData := s.Data[corev1.TLSCertKey]
+	cert, err := pki.DecodeX509CertificateBytes(certData)
+	if err != nil || !cert.IsCA || time.Now().After(cert.NotAfter) {
+		return nil
+	}
+	if _, err := tls.X509KeyPair(certData, s.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		return nil
+	}
+	return certData
+}
+
 func (d *DynamicAuthority) handleAdd(obj interface{}) {
 	ctx := context.Background()
 	if err := d.ensureCA(ctx); err != nil {
@@ -17,3 +17,188 @@ limitations under the License.
 package authority
 
 // Integration tests for the authority can be found in `test/integration/webhook/dynamic_authority_test.go`.
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+type testCA struct {
+	certData, pkData []byte
+}
+
+func newTestCA(t *testing.T, notBefore time.Time, duration time.Duration) testCA {
+	pk, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkData, err := pki.EncodePrivateKey(pk, cmapi.PKCS8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cert-manager-webhook-ca"},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(duration),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	certData, _, err := pki.SignCertificate(template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testCA{certData: certData, pkData: pkData}
+}
+
+func (ca testCA) secret(previous ...testCA) *corev1.Secret {
+	caData := append([]byte{}, ca.certData...)
+	for _, prev := range previous {
+		caData = append(caData, prev.certData...)
+	}
+	return &corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       ca.certData,
+			corev1.TLSPrivateKeyKey: ca.pkData,
+			cmmeta.TLSCAKey:         caData,
+		},
+	}
+}
+
+func TestDynamicAuthority_caBundle(t *testing.T) {
+	now := time.Now()
+	previous := newTestCA(t, now.Add(-240*time.Hour), 360*time.Hour)
+	expired := newTestCA(t, now.Add(-360*time.Hour), 300*time.Hour)
+	current := newTestCA(t, now.Add(-time.Minute), 360*time.Hour)
+	overlapping := newTestCA(t, now.Add(-90*time.Minute), 360*time.Hour)
+	rotated := newTestCA(t, now.Add(-3*time.Hour), 360*time.Hour)
+
+	tests := map[string]struct {
+		secret *corev1.Secret
+		exp    []byte
+	}{
+		"should return a bundle containing only the current CA": {
+			secret: current.secret(),
+			exp:    current.certData,
+		},
+		"should keep the previous CA during the rotation overlap": {
+			secret: overlapping.secret(previous),
+			exp:    overlapping.secret(previous).Data[cmmeta.TLSCAKey],
+		},
+		"should remove the previous CA once the rotation overlap has ended": {
+			secret: rotated.secret(previous),
+			exp:    rotated.certData,
+		},
+		"should remove an expired previous CA": {
+			secret: current.secret(expired, previous),
+			exp:    current.secret(previous).Data[cmmeta.TLSCAKey],
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := &DynamicAuthority{CARotationOverlap: time.Hour, Log: logtesting.TestLogger{T: t}}
+			if d.caRequiresRegeneration(test.secret) {
+				t.Fatal("expected the CA not to require regeneration")
+			}
+			got, err := d.caBundle(test.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, test.exp) {
+				t.Errorf("unexpected CA bundle, exp=%q got=%q", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestDynamicAuthority_rotationDeferred(t *testing.T) {
+	now := time.Now()
+	previous := newTestCA(t, now.Add(-240*time.Hour), 360*time.Hour)
+	unrelated := newTestCA(t, now.Add(-240*time.Hour), 360*time.Hour)
+	rotated := newTestCA(t, now.Add(-time.Minute), 360*time.Hour)
+	overlapEnded := newTestCA(t, now.Add(-90*time.Minute), 360*time.Hour)
+
+	tests := map[string]struct {
+		current []byte
+		secret  *corev1.Secret
+		exp     bool
+	}{
+		"should not defer if no CA is currently in use": {
+			secret: rotated.secret(previous),
+		},
+		"should not defer if the CA has not changed": {
+			current: rotated.certData,
+			secret:  rotated.secret(previous),
+		},
+		"should defer during the rotation overlap if the current CA is still published": {
+			current: previous.certData,
+			secret:  rotated.secret(previous),
+			exp:     true,
+		},
+		"should not defer once the rotation overlap has passed": {
+			current: previous.certData,
+			secret:  overlapEnded.secret(previous),
+		},
+		"should not defer if the current CA is not published": {
+			current: unrelated.certData,
+			secret:  rotated.secret(previous),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := &DynamicAuthority{CARotationOverlap: time.Hour, Log: logtesting.TestLogger{T: t}, currentCertData: test.current}
+			if got := d.rotationDeferred(test.secret); got != test.exp {
+				t.Errorf("unexpected result, exp=%t got=%t", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestDynamicAuthority_caRequiresRegeneration(t *testing.T) {
+	now := time.Now()
+	previous := newTestCA(t, now.Add(-240*time.Hour), 360*time.Hour)
+	current := newTestCA(t, now.Add(-time.Minute), 360*time.Hour)
+
+	mismatched := current.secret()
+	mismatched.Data[cmmeta.TLSCAKey] = previous.secret(current).Data[cmmeta.TLSCAKey]
+
+	tests := map[string]struct {
+		secret *corev1.Secret
+		exp    bool
+	}{
+		"should not regenerate a CA published on its own": {
+			secret: current.secret(),
+		},
+		"should not regenerate a CA published with a previous CA": {
+			secret: current.secret(previous),
+		},
+		"should regenerate if ca.crt does not start with tls.crt": {
+			secret: mismatched,
+			exp:    true,
+		},
+		"should regenerate a CA nearing expiry": {
+			secret: previous.secret(),
+			exp:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := &DynamicAuthority{CADuration: 360 * time.Hour, Log: logtesting.TestLogger{T: t}}
+			if got := d.caRequiresRegeneration(test.secret); got != test.exp {
+				t.Errorf("unexpected result, exp=%t got=%t", test.exp, got)
+			}
+		})
+	}
+}
@@ -22,6 +22,9 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/internal/apis/certmanager/validation/plugins"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 )
 
 type ValidatingAdmissionHook interface {
@@ -30,8 +33,9 @@ type ValidatingAdmissionHook interface {
 	Validate(ctx context.Context, admissionSpec *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
 
 	// InitPlugins will initialise all plugins which are registered for this
-	// validating admission hook.
-	InitPlugins(client kubernetes.Interface)
+	// validating admission hook. Informers started by plugins are stopped
+	// when ctx is done.
+	InitPlugins(ctx context.Context, client kubernetes.Interface, cmClient cmclient.Interface, opts plugins.Options)
 }
 
 type MutatingAdmissionHook interface {
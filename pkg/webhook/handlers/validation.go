@@ -31,6 +31,7 @@ import (
 
 	"github.com/jetstack/cert-manager/internal/api/validation"
 	"github.com/jetstack/cert-manager/internal/apis/certmanager/validation/plugins"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 )
 
 type registryBackedValidator struct {
@@ -51,9 +52,9 @@ func NewRegistryBackedValidator(log logr.Logger, scheme *runtime.Scheme, registr
 	}
 }
 
-func (r *registryBackedValidator) InitPlugins(client kubernetes.Interface) {
+func (r *registryBackedValidator) InitPlugins(ctx context.Context, client kubernetes.Interface, cmClient cmclient.Interface, opts plugins.Options) {
 	for _, plugin := range r.plugins {
-		plugin.Init(client)
+		plugin.Init(ctx, client, cmClient, opts)
 	}
 }
 
@@ -104,13 +105,14 @@ func (r *registryBackedValidator) Validate(ctx context.Context, admissionSpec *a
 		errs, warnings = append(errs, e...), append(warnings, w...)
 	}
 
-	// TODO: implement warnings for Plugin interface
 	// If no validation errors occurred, perform plugin checks.
 	if len(errs) == 0 {
 		for _, plugin := range r.plugins {
-			if err := plugin.Validate(ctx, admissionSpec, oldObj, obj); err != nil {
+			err, w := plugin.Validate(ctx, admissionSpec, oldObj, obj)
+			if err != nil {
 				errs = append(errs, err)
 			}
+			warnings = append(warnings, w...)
 		}
 	}
 
@@ -126,6 +128,19 @@ func (r *registryBackedValidator) Validate(ctx context.Context, admissionSpec *a
 		return status
 	}
 
+	for _, plugin := range r.plugins {
+		annotator, ok := plugin.(plugins.AuditAnnotator)
+		if !ok {
+			continue
+		}
+		for k, v := range annotator.AuditAnnotations(admissionSpec, oldObj, obj) {
+			if status.AuditAnnotations == nil {
+				status.AuditAnnotations = make(map[string]string)
+			}
+			status.AuditAnnotations[k] = v
+		}
+	}
+
 	status.Allowed = true
 	return status
 }
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podcertificates implements an optional mutating admission hook
+// that mounts the Secret of a Certificate into the Pods that reference it,
+// and holds back the readiness of those Pods until the Certificate is Ready.
+package podcertificates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	// VolumeName is the name of the volume that the Secret of the Certificate
+	// is injected as.
+	VolumeName = "cert-manager-certificate"
+
+	// DefaultMountPath is the path that the Secret of the Certificate is
+	// mounted at, unless the Pod sets a different path using the
+	// 'cert-manager.io/pod-certificate-mount-path' annotation.
+	DefaultMountPath = "/var/run/secrets/cert-manager.io/certificate"
+
+	// getCertificateTimeout is the time allowed for reading the Certificate
+	// referenced by a Pod.
+	getCertificateTimeout = 5 * time.Second
+)
+
+// Mutator is a mutating admission hook for Pods that have the
+// 'cert-manager.io/pod-certificate' annotation.
+type Mutator struct {
+	log      logr.Logger
+	cmClient cmclient.Interface
+}
+
+// NewMutator returns a Mutator that reads the Certificates referenced by Pods
+// using the given client.
+func NewMutator(log logr.Logger, cmClient cmclient.Interface) *Mutator {
+	return &Mutator{
+		log:      log.WithName("pod-certificates"),
+		cmClient: cmClient,
+	}
+}
+
+// Mutate injects the Secret of the Certificate referenced by the Pod as a
+// volume that is mounted into every container, and adds a readiness gate for
+// the Certificate to the Pod. Pods without the annotation are not changed.
+// Pods that reference a Certificate that does not exist are rejected, so that
+// workloads do not start before their Certificate has been created.
+func (m *Mutator) Mutate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+	if req.Operation != admissionv1.Create ||
+		req.Kind.Group != corev1.GroupName || req.Kind.Kind != "Pod" {
+		return resp
+	}
+
+	pod := &corev1.Pod{}
+	if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+		return deny(resp, http.StatusBadRequest, fmt.Sprintf("Failed to decode Pod: %v", err))
+	}
+
+	name, ok := pod.Annotations[cmapi.PodCertificateAnnotationKey]
+	if !ok {
+		return resp
+	}
+
+	// Pods created by controllers do not have a namespace set yet
+	namespace := pod.Namespace
+	if namespace == "" {
+		namespace = req.Namespace
+	}
+	log := m.log.WithValues("namespace", namespace, "certificate", name)
+
+	getCtx, cancel := context.WithTimeout(ctx, getCertificateTimeout)
+	defer cancel()
+	crt, err := m.cmClient.CertmanagerV1().Certificates(namespace).Get(getCtx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return deny(resp, http.StatusForbidden, fmt.Sprintf("Certificate %q referenced by the %q annotation does not exist", name, cmapi.PodCertificateAnnotationKey))
+	}
+	if err != nil {
+		log.Error(err, "failed to get certificate referenced by pod")
+		return deny(resp, http.StatusInternalServerError, fmt.Sprintf("Failed to get Certificate %q: %v", name, err))
+	}
+
+	mutated := pod.DeepCopy()
+	if err := InjectCertificate(mutated, crt); err != nil {
+		return deny(resp, http.StatusForbidden, err.Error())
+	}
+
+	patch, err := createPatch(req.Object.Raw, mutated)
+	if err != nil {
+		return deny(resp, http.StatusInternalServerError, err.Error())
+	}
+
+	patchType := admissionv1.PatchTypeJSONPatch
+	resp.Patch = patch
+	resp.PatchType = &patchType
+
+	log.V(logf.DebugLevel).Info("injected certificate into pod", "patch", string(patch))
+
+	return resp
+}
+
+// InjectCertificate adds the Secret of the given Certificate as a volume to
+// the Pod, mounts it into every container and adds a readiness gate for the
+// Certificate. Injecting the same Certificate more than once has no effect.
+func InjectCertificate(pod *corev1.Pod, crt *cmapi.Certificate) error {
+	mountPath := DefaultMountPath
+	if p, ok := pod.Annotations[cmapi.PodCertificateMountPathAnnotationKey]; ok {
+		if !path.IsAbs(p) {
+			return fmt.Errorf("the %q annotation must be an absolute path, got %q", cmapi.PodCertificateMountPathAnnotationKey, p)
+		}
+		mountPath = path.Clean(p)
+	}
+
+	volume := corev1.Volume{
+		Name: VolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: crt.Spec.SecretName,
+			},
+		},
+	}
+	hasVolume := false
+	for _, v := range pod.Spec.Volumes {
+		if v.Name != VolumeName {
+			continue
+		}
+		if v.Secret == nil || v.Secret.SecretName != crt.Spec.SecretName {
+			return fmt.Errorf("the Pod already has a volume named %q", VolumeName)
+		}
+		hasVolume = true
+	}
+	if !hasVolume {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+	}
+
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = addVolumeMount(pod.Spec.Containers[i].VolumeMounts, mountPath)
+	}
+
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == cmapi.PodConditionCertificateReady {
+			return nil
+		}
+	}
+	pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{
+		ConditionType: cmapi.PodConditionCertificateReady,
+	})
+
+	return nil
+}
+
+func addVolumeMount(mounts []corev1.VolumeMount, mountPath string) []corev1.VolumeMount {
+	for _, mount := range mounts {
+		if mount.Name == VolumeName {
+			return mounts
+		}
+	}
+	return append(mounts, corev1.VolumeMount{
+		Name:      VolumeName,
+		MountPath: mountPath,
+		ReadOnly:  true,
+	})
+}
+
+// createPatch returns a JSON patch that changes the raw Pod into the given
+// Pod.
+func createPatch(raw []byte, pod *corev1.Pod) ([]byte, error) {
+	mutated, err := json.Marshal(pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Pod: %v", err)
+	}
+	ops, err := jsonpatch.CreatePatch(raw, mutated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch: %v", err)
+	}
+	return json.Marshal(ops)
+}
+
+func deny(resp *admissionv1.AdmissionResponse, code int32, message string) *admissionv1.AdmissionResponse {
+	resp.Allowed = false
+	resp.Result = &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    code,
+		Message: message,
+	}
+	return resp
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podcertificates
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestInjectCertificate(t *testing.T) {
+	crt := gen.Certificate("test", gen.SetCertificateNamespace("ns"), gen.SetCertificateSecretName("test-tls"))
+	secretVolume := func(secretName string) corev1.Volume {
+		return corev1.Volume{
+			Name: VolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+			},
+		}
+	}
+	mount := func(path string) corev1.VolumeMount {
+		return corev1.VolumeMount{Name: VolumeName, MountPath: path, ReadOnly: true}
+	}
+	gate := corev1.PodReadinessGate{ConditionType: cmapi.PodConditionCertificateReady}
+
+	tests := map[string]struct {
+		pod     *corev1.Pod
+		wantPod *corev1.Pod
+		wantErr bool
+	}{
+		"injects the volume, mounts and readiness gate": {
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "a"}, {Name: "b"}},
+				},
+			},
+			wantPod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{secretVolume("test-tls")},
+					Containers: []corev1.Container{
+						{Name: "a", VolumeMounts: []corev1.VolumeMount{mount(DefaultMountPath)}},
+						{Name: "b", VolumeMounts: []corev1.VolumeMount{mount(DefaultMountPath)}},
+					},
+					ReadinessGates: []corev1.PodReadinessGate{gate},
+				},
+			},
+		},
+		"uses the mount path from the annotation": {
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{cmapi.PodCertificateMountPathAnnotationKey: "/etc/tls/"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "a"}},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{cmapi.PodCertificateMountPathAnnotationKey: "/etc/tls/"},
+				},
+				Spec: corev1.PodSpec{
+					Volumes:        []corev1.Volume{secretVolume("test-tls")},
+					Containers:     []corev1.Container{{Name: "a", VolumeMounts: []corev1.VolumeMount{mount("/etc/tls")}}},
+					ReadinessGates: []corev1.PodReadinessGate{gate},
+				},
+			},
+		},
+		"does not change a pod that has already been injected": {
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes:        []corev1.Volume{secretVolume("test-tls")},
+					Containers:     []corev1.Container{{Name: "a", VolumeMounts: []corev1.VolumeMount{mount("/custom")}}},
+					ReadinessGates: []corev1.PodReadinessGate{gate},
+				},
+			},
+			wantPod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes:        []corev1.Volume{secretVolume("test-tls")},
+					Containers:     []corev1.Container{{Name: "a", VolumeMounts: []corev1.VolumeMount{mount("/custom")}}},
+					ReadinessGates: []corev1.PodReadinessGate{gate},
+				},
+			},
+		},
+		"rejects a relative mount path": {
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{cmapi.PodCertificateMountPathAnnotationKey: "etc/tls"},
+				},
+			},
+			wantErr: true,
+		},
+		"rejects a pod with a conflicting volume": {
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{secretVolume("other")},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := InjectCertificate(test.pod, crt)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error, wantErr=%t, got: %v", test.wantErr, err)
+			}
+			if test.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(test.pod, test.wantPod) {
+				t.Errorf("unexpected pod, want=%#v, got=%#v", test.wantPod, test.pod)
+			}
+		})
+	}
+}
+
+func TestMutate(t *testing.T) {
+	crt := gen.Certificate("test", gen.SetCertificateNamespace("ns"), gen.SetCertificateSecretName("test-tls"))
+	podKind := metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	rawPod := func(annotations map[string]string) runtime.RawExtension {
+		b, err := json.Marshal(&corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "a"}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runtime.RawExtension{Raw: b}
+	}
+
+	tests := map[string]struct {
+		request     admissionv1.AdmissionRequest
+		wantAllowed bool
+		// wantPaths are the paths changed by the returned patch
+		wantPaths []string
+	}{
+		"ignores pods without the annotation": {
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      podKind,
+				Namespace: "ns",
+				Object:    rawPod(nil),
+			},
+			wantAllowed: true,
+		},
+		"ignores updates": {
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      podKind,
+				Namespace: "ns",
+				Object:    rawPod(map[string]string{cmapi.PodCertificateAnnotationKey: "test"}),
+			},
+			wantAllowed: true,
+		},
+		"denies pods that reference a certificate that does not exist": {
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      podKind,
+				Namespace: "ns",
+				Object:    rawPod(map[string]string{cmapi.PodCertificateAnnotationKey: "missing"}),
+			},
+			wantAllowed: false,
+		},
+		"patches pods that reference a certificate": {
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      podKind,
+				Namespace: "ns",
+				Object:    rawPod(map[string]string{cmapi.PodCertificateAnnotationKey: "test"}),
+			},
+			wantAllowed: true,
+			wantPaths: []string{
+				"/spec/containers/0/volumeMounts",
+				"/spec/readinessGates",
+				"/spec/volumes",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := NewMutator(klogr.New(), cmfake.NewSimpleClientset(crt))
+			resp := m.Mutate(context.TODO(), &test.request)
+			if resp.Allowed != test.wantAllowed {
+				t.Fatalf("unexpected allowed, want=%t, got=%t (%v)", test.wantAllowed, resp.Allowed, resp.Result)
+			}
+			if len(test.wantPaths) == 0 {
+				if resp.Patch != nil {
+					t.Errorf("expected no patch, got: %s", resp.Patch)
+				}
+				return
+			}
+			var ops []jsonpatch.JsonPatchOperation
+			if err := json.Unmarshal(resp.Patch, &ops); err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, op := range ops {
+				paths = append(paths, op.Path)
+			}
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, test.wantPaths) {
+				t.Errorf("unexpected patch paths, want=%v, got=%v", test.wantPaths, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	// selfTestNamespace and selfTestName are used for the resources submitted
+	// by the self-test. They are never persisted, but are chosen to be
+	// unlikely to collide with real resources that validation plugins may
+	// compare them against.
+	selfTestNamespace = "cert-manager-webhook-selftest"
+	selfTestName      = "cert-manager-webhook-selftest"
+)
+
+// SelfTestResult is the outcome of a single self-test scenario.
+type SelfTestResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// SelfTestReport is the response body of the /selftest endpoint.
+type SelfTestReport struct {
+	Passed  bool             `json:"passed"`
+	Results []SelfTestResult `json:"results"`
+}
+
+// selfTest is a scenario that is run against the server's webhooks. It
+// returns an error describing the failure if the webhooks did not behave as
+// expected.
+type selfTest struct {
+	name string
+	run  func(ctx context.Context, s *Server) error
+}
+
+// selfTests are the scenarios run by the /selftest endpoint. They submit
+// requests to the same conversion and validation webhooks that serve the
+// API server, so that a broken scheme registration, conversion function or
+// validation function causes the self-test to fail even though the server is
+// otherwise live.
+var selfTests = []selfTest{
+	{
+		name: "convert Certificate from v1alpha2 to v1",
+		run: func(ctx context.Context, s *Server) error {
+			in := selfTestCertificateV1alpha2()
+			out := &cmapi.Certificate{}
+			if err := s.selfTestConvert(ctx, in, cmapi.SchemeGroupVersion.String(), out); err != nil {
+				return err
+			}
+			if out.Spec.PrivateKey == nil || out.Spec.PrivateKey.Algorithm != cmapi.ECDSAKeyAlgorithm {
+				return fmt.Errorf("expected spec.privateKey.algorithm to be %q, got %+v", cmapi.ECDSAKeyAlgorithm, out.Spec.PrivateKey)
+			}
+			return nil
+		},
+	},
+	{
+		name: "convert Certificate from v1 to v1alpha2",
+		run: func(ctx context.Context, s *Server) error {
+			in := selfTestCertificateV1()
+			out := &cmapiv1alpha2.Certificate{}
+			if err := s.selfTestConvert(ctx, in, cmapiv1alpha2.SchemeGroupVersion.String(), out); err != nil {
+				return err
+			}
+			if out.Spec.KeyAlgorithm != cmapiv1alpha2.ECDSAKeyAlgorithm {
+				return fmt.Errorf("expected spec.keyAlgorithm to be %q, got %q", cmapiv1alpha2.ECDSAKeyAlgorithm, out.Spec.KeyAlgorithm)
+			}
+			return nil
+		},
+	},
+	{
+		name: "admit a valid Certificate",
+		run: func(ctx context.Context, s *Server) error {
+			resp, err := s.selfTestValidate(ctx, selfTestCertificateV1())
+			if err != nil {
+				return err
+			}
+			if !resp.Allowed {
+				return fmt.Errorf("expected the Certificate to be admitted, got: %s", resultMessage(resp.Result))
+			}
+			return nil
+		},
+	},
+	{
+		name: "deny an invalid Certificate",
+		run: func(ctx context.Context, s *Server) error {
+			crt := selfTestCertificateV1()
+			crt.Spec.IssuerRef.Name = ""
+			resp, err := s.selfTestValidate(ctx, crt)
+			if err != nil {
+				return err
+			}
+			if resp.Allowed {
+				return fmt.Errorf("expected a Certificate without spec.issuerRef.name to be denied")
+			}
+			return nil
+		},
+	},
+}
+
+func selfTestCertificateV1() *cmapi.Certificate {
+	return &cmapi.Certificate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: cmapi.SchemeGroupVersion.String(), Kind: cmapi.CertificateKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: selfTestNamespace, Name: selfTestName},
+		Spec: cmapi.CertificateSpec{
+			SecretName: selfTestName,
+			DNSNames:   []string{"selftest.example.com"},
+			PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm},
+			IssuerRef:  cmmeta.ObjectReference{Name: selfTestName},
+		},
+	}
+}
+
+func selfTestCertificateV1alpha2() *cmapiv1alpha2.Certificate {
+	return &cmapiv1alpha2.Certificate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: cmapiv1alpha2.SchemeGroupVersion.String(), Kind: cmapi.CertificateKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: selfTestNamespace, Name: selfTestName},
+		Spec: cmapiv1alpha2.CertificateSpec{
+			SecretName:   selfTestName,
+			DNSNames:     []string{"selftest.example.com"},
+			KeyAlgorithm: cmapiv1alpha2.ECDSAKeyAlgorithm,
+			IssuerRef:    cmmeta.ObjectReference{Name: selfTestName},
+		},
+	}
+}
+
+// selfTestConvert converts in to desiredAPIVersion using the server's
+// conversion webhook, and decodes the converted object into out.
+func (s *Server) selfTestConvert(ctx context.Context, in runtime.Object, desiredAPIVersion string, out runtime.Object) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.convert(ctx, &apiextensionsv1.ConversionReview{
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               types.UID(selfTestName),
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           []runtime.RawExtension{{Raw: raw}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := result.(*apiextensionsv1.ConversionReview).Response
+	if resp == nil {
+		return fmt.Errorf("no conversion response was returned")
+	}
+	if resp.Result.Status != metav1.StatusSuccess {
+		return fmt.Errorf("conversion failed: %s", resp.Result.Message)
+	}
+	if len(resp.ConvertedObjects) != 1 {
+		return fmt.Errorf("expected 1 converted object, got %d", len(resp.ConvertedObjects))
+	}
+	if err := json.Unmarshal(resp.ConvertedObjects[0].Raw, out); err != nil {
+		return fmt.Errorf("failed to decode converted object: %w", err)
+	}
+	if apiVersion := out.GetObjectKind().GroupVersionKind().GroupVersion().String(); apiVersion != desiredAPIVersion {
+		return fmt.Errorf("expected converted object to have apiVersion %q, got %q", desiredAPIVersion, apiVersion)
+	}
+	return nil
+}
+
+// selfTestValidate submits the creation of crt to the server's validating
+// webhook.
+func (s *Server) selfTestValidate(ctx context.Context, crt *cmapi.Certificate) (*admissionv1.AdmissionResponse, error) {
+	raw, err := json.Marshal(crt)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := metav1.GroupVersionKind{Group: cmapi.SchemeGroupVersion.Group, Version: cmapi.SchemeGroupVersion.Version, Kind: cmapi.CertificateKind}
+	gvr := metav1.GroupVersionResource{Group: cmapi.SchemeGroupVersion.Group, Version: cmapi.SchemeGroupVersion.Version, Resource: "certificates"}
+	result, err := s.validate(ctx, &admissionv1.AdmissionReview{
+		Request: &admissionv1.AdmissionRequest{
+			UID:         types.UID(selfTestName),
+			Kind:        gvk,
+			Resource:    gvr,
+			RequestKind: &gvk,
+			Namespace:   crt.Namespace,
+			Name:        crt.Name,
+			Operation:   admissionv1.Create,
+			Object:      runtime.RawExtension{Raw: raw},
+			DryRun:      pointer.BoolPtr(true),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := result.(*admissionv1.AdmissionReview).Response
+	if resp == nil {
+		return nil, fmt.Errorf("no admission response was returned")
+	}
+	return resp, nil
+}
+
+func resultMessage(status *metav1.Status) string {
+	if status == nil {
+		return "no reason given"
+	}
+	return status.Message
+}
+
+// runSelfTests runs each of the self-test scenarios and reports whether they
+// all passed.
+func (s *Server) runSelfTests(ctx context.Context) *SelfTestReport {
+	report := &SelfTestReport{Passed: true}
+	for _, test := range selfTests {
+		result := SelfTestResult{Name: test.name, Passed: true}
+		if err := s.runSelfTest(ctx, test); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// runSelfTest runs a single self-test scenario, treating a panic in the
+// webhooks as a failure so that it is reported rather than crashing the
+// server.
+func (s *Server) runSelfTest(ctx context.Context, test selfTest) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return test.run(ctx, s)
+}
+
+// handleSelfTest runs the self-test scenarios and responds with a JSON
+// SelfTestReport. The response has a 500 status code if any scenario failed.
+func (s *Server) handleSelfTest(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	ctx, cancel := context.WithTimeout(req.Context(), 10*time.Second)
+	defer cancel()
+
+	report := s.runSelfTests(ctx)
+	if !report.Passed {
+		for _, result := range report.Results {
+			if !result.Passed {
+				s.Log.V(logf.WarnLevel).Info("self-test failed", "test", result.Name, "message", result.Message)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Passed {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		s.Log.Error(err, "failed to encode self-test report")
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	testingcmlogs "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/pkg/webhook"
+	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+)
+
+type brokenConverter struct{}
+
+func (brokenConverter) Convert(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	return &apiextensionsv1.ConversionResponse{
+		UID:    req.UID,
+		Result: metav1.Status{Status: metav1.StatusFailure, Message: "no kind is registered"},
+	}
+}
+
+func TestHandleSelfTest(t *testing.T) {
+	tests := map[string]struct {
+		conversionWebhook handlers.ConversionHook
+		expStatus         int
+		expFailed         []string
+	}{
+		"all scenarios pass against the cert-manager webhooks": {
+			conversionWebhook: handlers.NewSchemeBackedConverter(&testingcmlogs.TestLogger{T: t}, webhook.Scheme),
+			expStatus:         http.StatusOK,
+		},
+		"conversion scenarios fail if conversion is broken": {
+			conversionWebhook: brokenConverter{},
+			expStatus:         http.StatusInternalServerError,
+			expFailed: []string{
+				"convert Certificate from v1alpha2 to v1",
+				"convert Certificate from v1 to v1alpha2",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := &testingcmlogs.TestLogger{T: t}
+			s := &Server{
+				ValidationWebhook: handlers.NewRegistryBackedValidator(log, webhook.Scheme, webhook.ValidationRegistry),
+				ConversionWebhook: test.conversionWebhook,
+				Log:               log,
+			}
+
+			rec := httptest.NewRecorder()
+			s.handleSelfTest(rec, httptest.NewRequest(http.MethodGet, "/selftest", nil))
+			assert.Equal(t, test.expStatus, rec.Code)
+
+			var report SelfTestReport
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			assert.Len(t, report.Results, len(selfTests))
+
+			var failed []string
+			for _, result := range report.Results {
+				if !result.Passed {
+					failed = append(failed, result.Name)
+				}
+			}
+			assert.Equal(t, test.expFailed, failed)
+			assert.Equal(t, len(test.expFailed) == 0, report.Passed)
+		})
+	}
+}
@@ -84,6 +84,12 @@ type Server struct {
 	// If not specified, the healthz endpoint will not be exposed.
 	HealthzAddr string
 
+	// EnableSelfTest determines whether the /selftest endpoint is served on
+	// the healthz listener. It runs a set of conversion and validation
+	// scenarios against the webhooks and fails if any of them do not behave
+	// as expected.
+	EnableSelfTest bool
+
 	// PprofAddr is the address the pprof endpoint should be served on if enabled.
 	PprofAddr string
 	// EnablePprof determines whether pprof is enabled.
@@ -103,6 +109,10 @@ type Server struct {
 	MutationWebhook   handlers.MutatingAdmissionHook
 	ConversionWebhook handlers.ConversionHook
 
+	// PodMutationWebhook is an optional mutating admission hook for Pods.
+	// If specified, it is served on the /mutate-pods path.
+	PodMutationWebhook handlers.MutatingAdmissionHook
+
 	// Log is an optional logger to write informational and error messages to.
 	// If not specified, no messages will be logged.
 	Log logr.Logger
@@ -138,6 +148,9 @@ func (s *Server) Run(stopCh <-chan struct{}) error {
 		healthMux := http.NewServeMux()
 		healthMux.HandleFunc("/healthz", s.handleHealthz)
 		healthMux.HandleFunc("/livez", s.handleLivez)
+		if s.EnableSelfTest {
+			healthMux.HandleFunc("/selftest", s.handleSelfTest)
+		}
 		s.Log.V(logf.InfoLevel).Info("listening for insecure healthz connections", "address", s.HealthzAddr)
 		server := &http.Server{
 			Handler: healthMux,
@@ -232,6 +245,9 @@ func (s *Server) Run(stopCh <-chan struct{}) error {
 	serverMux.HandleFunc("/validate", s.handle(s.validate))
 	serverMux.HandleFunc("/mutate", s.handle(s.mutate))
 	serverMux.HandleFunc("/convert", s.handle(s.convert))
+	if s.PodMutationWebhook != nil {
+		serverMux.HandleFunc("/mutate-pods", s.handle(s.mutatePods))
+	}
 	server := &http.Server{
 		Handler: serverMux,
 	}
@@ -293,6 +309,15 @@ func (s *Server) mutate(ctx context.Context, obj runtime.Object) (runtime.Object
 	return review, nil
 }
 
+func (s *Server) mutatePods(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
+	review, isV1 := obj.(*admissionv1.AdmissionReview)
+	if !isV1 {
+		return nil, errors.New("request is not of type apiextensions v1")
+	}
+	review.Response = s.PodMutationWebhook.Mutate(ctx, review.Request)
+	return review, nil
+}
+
 func (s *Server) convert(_ context.Context, obj runtime.Object) (runtime.Object, error) {
 	switch review := obj.(type) {
 	case *apiextensionsv1.ConversionReview:
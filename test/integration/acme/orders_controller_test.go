@@ -33,6 +33,7 @@ import (
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	"github.com/jetstack/cert-manager/pkg/controller/acmeorders"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/metrics"
@@ -119,6 +120,7 @@ func TestAcmeOrdersController(t *testing.T) {
 	}
 
 	// Create a new orders controller.
+	metricsHandler := metrics.New(logf.Log, clock.RealClock{})
 	ctrl, queue, mustSync := acmeorders.NewController(
 		logf.Log,
 		cmCl,
@@ -127,12 +129,15 @@ func TestAcmeOrdersController(t *testing.T) {
 		accountRegistry,
 		framework.NewEventRecorder(t),
 		clock.RealClock{},
+		metricsHandler,
+		statuspatch.New(0, 0),
 		false,
+		controllerpkg.WorkqueueOptions{},
 	)
 	c := controllerpkg.NewController(
 		ctx,
 		"orders_test",
-		metrics.New(logf.Log, clock.RealClock{}),
+		metricsHandler,
 		ctrl.ProcessItem,
 		mustSync,
 		nil,
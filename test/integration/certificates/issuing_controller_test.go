@@ -34,6 +34,7 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/issuing"
+	"github.com/jetstack/cert-manager/pkg/controller/statuspatch"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/metrics"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
@@ -59,7 +60,7 @@ func TestIssuingController(t *testing.T) {
 		EnableOwnerRef: true,
 	}
 
-	ctrl, queue, mustSync := issuing.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), clock.RealClock{}, controllerOptions)
+	ctrl, queue, mustSync := issuing.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), clock.RealClock{}, metrics.New(logf.Log, clock.RealClock{}), controllerOptions, statuspatch.New(0, 0), controllerpkg.WorkqueueOptions{})
 	c := controllerpkg.NewController(
 		ctx,
 		"issuing_test",
@@ -263,7 +264,7 @@ func TestIssuingController_PKCS8_PrivateKey(t *testing.T) {
 		EnableOwnerRef: true,
 	}
 
-	ctrl, queue, mustSync := issuing.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), clock.RealClock{}, controllerOptions)
+	ctrl, queue, mustSync := issuing.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), clock.RealClock{}, metrics.New(logf.Log, clock.RealClock{}), controllerOptions, statuspatch.New(0, 0), controllerpkg.WorkqueueOptions{})
 	c := controllerpkg.NewController(
 		ctx,
 		"issuing_test",
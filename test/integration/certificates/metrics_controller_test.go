@@ -90,7 +90,7 @@ func TestMetricsController(t *testing.T) {
 		}
 	}()
 
-	ctrl, queue, mustSync := controllermetrics.NewController(factory, cmFactory, metricsHandler)
+	ctrl, queue, mustSync := controllermetrics.NewController(factory, cmFactory, metricsHandler, controllerpkg.WorkqueueOptions{})
 	c := controllerpkg.NewController(
 		ctx,
 		"metrics_test",
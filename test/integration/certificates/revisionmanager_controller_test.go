@@ -53,7 +53,7 @@ func TestRevisionManagerController(t *testing.T) {
 	// Build, instantiate and run the revision manager controller.
 	kubeClient, factory, cmCl, cmFactory := framework.NewClients(t, config)
 
-	ctrl, queue, mustSync := revisionmanager.NewController(logf.Log, cmCl, cmFactory)
+	ctrl, queue, mustSync := revisionmanager.NewController(logf.Log, cmCl, cmFactory, controllerpkg.WorkqueueOptions{})
 
 	c := controllerpkg.NewController(
 		ctx,
@@ -23,9 +23,12 @@ import (
 	"testing"
 	"time"
 
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/jetstack/cert-manager/pkg/api"
@@ -195,6 +198,94 @@ func TestValidationCertificateRequests(t *testing.T) {
 	}
 }
 
+// TestCertificateRequestRequesterIdentity ensures that the requester identity
+// set by the mutating webhook is persisted when a CertificateRequest is
+// created by a ServiceAccount, and replaces any identity set by the requester.
+func TestCertificateRequestRequesterIdentity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*40)
+	defer cancel()
+
+	config, stop := framework.RunControlPlane(t, ctx)
+	defer stop()
+
+	framework.WaitForOpenAPIResourcesToBeLoaded(t, ctx, config, certGVK)
+
+	adminClient, err := client.New(config, client.Options{Scheme: api.Scheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// allow the ServiceAccount to create and read CertificateRequests
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "certificaterequest-creator", Namespace: "default"},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{"cert-manager.io"},
+			Resources: []string{"certificaterequests"},
+			Verbs:     []string{"create", "get"},
+		}},
+	}
+	if err := adminClient.Create(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "certificaterequest-creator", Namespace: "default"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: role.Name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "app", Namespace: "default"}},
+	}
+	if err := adminClient.Create(ctx, roleBinding); err != nil {
+		t.Fatal(err)
+	}
+
+	saConfig := rest.CopyConfig(config)
+	saConfig.Impersonate = rest.ImpersonationConfig{
+		UserName: "system:serviceaccount:default:app",
+		Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:default", "system:authenticated"},
+		Extra:    map[string][]string{"authentication.kubernetes.io/pod-name": {"app-1234"}},
+	}
+	saClient, err := client.New(saConfig, client.Options{Scheme: api.Scheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr := &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: cmapi.CertificateRequestSpec{
+			Request: mustGenerateCSR(t, &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					DNSNames: []string{"example.com"},
+				},
+			}),
+			IssuerRef: cmmeta.ObjectReference{Name: "test"},
+			RequesterIdentity: &cmapi.CertificateRequestRequesterIdentity{
+				Namespace:      "other",
+				ServiceAccount: "other",
+			},
+		},
+	}
+	cr.SetGroupVersionKind(certGVK)
+	if err := saClient.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	// read the CertificateRequest back to check what was persisted
+	var got cmapi.CertificateRequest
+	if err := adminClient.Get(ctx, client.ObjectKeyFromObject(cr), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &cmapi.CertificateRequestRequesterIdentity{
+		Namespace:      "default",
+		ServiceAccount: "app",
+		Pod:            "app-1234",
+	}
+	if !apiequality.Semantic.DeepEqual(exp, got.Spec.RequesterIdentity) {
+		t.Errorf("unexpected requester identity, exp=%+v got=%+v", exp, got.Spec.RequesterIdentity)
+	}
+}
+
 func mustGenerateCSR(t *testing.T, cert *cmapi.Certificate) []byte {
 	request, err := pki.GenerateCSR(cert)
 	if err != nil {
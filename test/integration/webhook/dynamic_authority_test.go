@@ -185,9 +185,9 @@ func ensureSecretDataValid(s *corev1.Secret) error {
 	if len(caData) == 0 || len(pkData) == 0 || len(certData) == 0 {
 		return fmt.Errorf("missing data in CA secret")
 	}
-	// ensure that the ca.crt and tls.crt keys are equal
-	if !bytes.Equal(caData, certData) {
-		return fmt.Errorf("expected Secret to contains a self-signed root but ca.crt and tls.crt differ")
+	// ensure that the ca.crt key starts with the tls.crt key
+	if !bytes.HasPrefix(caData, certData) {
+		return fmt.Errorf("expected Secret to contains a self-signed root but ca.crt does not start with tls.crt")
 	}
 	cert, err := tls.X509KeyPair(certData, pkData)
 	if err != nil {
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance contains a suite of tests that exercise the conversion,
+// validation and issuance behaviour of the cert-manager API. It is intended to
+// be run by forks of cert-manager and by external issuers after modifying the
+// API or its validation, to detect changes in behaviour early:
+//
+//	func TestConformance(t *testing.T) {
+//		new(conformance.Suite).Run(t)
+//	}
+//
+// Additional validation scenarios and issuance fixtures can be supplied in
+// the same layout as the testdata directory of this package.
+package conformance
+
+import (
+	"embed"
+	"io/fs"
+	"testing"
+)
+
+// scenarios are the validation scenarios and issuance fixtures shipped with
+// cert-manager.
+//
+//go:embed testdata
+var scenarios embed.FS
+
+// Suite is a conformance test suite for the cert-manager API.
+type Suite struct {
+	// Scenarios contains the validation scenarios in a 'validation' directory
+	// and the issuance fixtures and their golden files in an 'issuance'
+	// directory. Defaults to the scenarios shipped with cert-manager.
+	Scenarios fs.FS
+
+	// UpdateGoldenDir, if set, is the directory that the golden files of the
+	// issuance fixtures are written to, instead of being compared to the
+	// output of the issuance tests.
+	UpdateGoldenDir string
+}
+
+// Run runs all tests of the conformance suite.
+func (s *Suite) Run(t *testing.T) {
+	t.Run("RoundTrip", s.RoundTrip)
+	t.Run("Validation", s.Validation)
+	t.Run("Issuance", s.Issuance)
+}
+
+func (s *Suite) scenarios(t *testing.T) fs.FS {
+	if s.Scenarios != nil {
+		return s.Scenarios
+	}
+	fsys, err := fs.Sub(scenarios, "testdata")
+	if err != nil {
+		t.Fatalf("failed to read embedded scenarios: %v", err)
+	}
+	return fsys
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/yaml"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// Issuance builds the CertificateRequest for the Certificate of every issuance
+// fixture and signs it the way the built-in issuers do, then compares the
+// properties of the resulting certificate to the golden file of the fixture.
+// The golden file of 'issuance/<name>.yaml' is 'issuance/<name>.golden'.
+func (s *Suite) Issuance(t *testing.T) {
+	fsys := s.scenarios(t)
+	files, err := fs.Glob(fsys, "issuance/*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no issuance fixtures found")
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(path.Base(file), ".yaml"), func(t *testing.T) {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var crt cmapi.Certificate
+			if err := yaml.UnmarshalStrict(data, &crt); err != nil {
+				t.Fatalf("failed to parse certificate: %v", err)
+			}
+
+			got, err := issue(&crt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := strings.TrimSuffix(file, ".yaml") + ".golden"
+			if s.UpdateGoldenDir != "" {
+				if err := os.WriteFile(filepath.Join(s.UpdateGoldenDir, filepath.FromSlash(golden)), got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := fs.ReadFile(fsys, golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if !bytes.Equal(want, got) {
+				t.Errorf("issued certificate does not match %s:\n%s", golden, diff.StringDiff(string(want), string(got)))
+			}
+		})
+	}
+}
+
+// issue returns a description of the certificate that an issuer would sign
+// for the CertificateRequest created for the given Certificate.
+func issue(crt *cmapi.Certificate) ([]byte, error) {
+	csrTemplate, err := pki.GenerateCSR(crt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CSR: %v", err)
+	}
+	key, err := pki.GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %v", err)
+	}
+	csrDER, err := pki.EncodeCSR(csrTemplate, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CSR: %v", err)
+	}
+
+	cr := &cmapi.CertificateRequest{
+		Spec: cmapi.CertificateRequestSpec{
+			Request:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+			Duration: crt.Spec.Duration,
+			IsCA:     crt.Spec.IsCA,
+			Usages:   crt.Spec.Usages,
+		},
+	}
+	template, err := pki.GenerateTemplateFromCertificateRequest(cr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate template: %v", err)
+	}
+
+	return describe(template), nil
+}
+
+// describe returns the properties of the certificate template that do not
+// depend on the time or randomness, one per line.
+func describe(cert *x509.Certificate) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "subject: %q\n", cert.Subject.String())
+	fmt.Fprintf(&b, "dnsNames: %q\n", cert.DNSNames)
+	fmt.Fprintf(&b, "ipAddresses: %q\n", pki.IPAddressesToString(cert.IPAddresses))
+	fmt.Fprintf(&b, "uris: %q\n", pki.URLsToString(cert.URIs))
+	fmt.Fprintf(&b, "emailAddresses: %q\n", cert.EmailAddresses)
+	fmt.Fprintf(&b, "publicKeyAlgorithm: %s\n", cert.PublicKeyAlgorithm)
+	// NotBefore and NotAfter are set from separate calls to time.Now
+	fmt.Fprintf(&b, "duration: %s\n", cert.NotAfter.Sub(cert.NotBefore).Round(time.Second))
+	fmt.Fprintf(&b, "isCA: %t\n", cert.IsCA)
+	if cert.IsCA {
+		fmt.Fprintf(&b, "maxPathLen: %d\n", cert.MaxPathLen)
+	}
+	fmt.Fprintf(&b, "usages: %q\n", pki.BuildCertManagerKeyUsages(cert.KeyUsage, cert.ExtKeyUsage))
+	return b.Bytes()
+}
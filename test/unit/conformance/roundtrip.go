@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/roundtrip"
+
+	acmefuzzer "github.com/jetstack/cert-manager/internal/apis/acme/fuzzer"
+	acmeinstall "github.com/jetstack/cert-manager/internal/apis/acme/install"
+	cmfuzzer "github.com/jetstack/cert-manager/internal/apis/certmanager/fuzzer"
+	cminstall "github.com/jetstack/cert-manager/internal/apis/certmanager/install"
+	metafuzzer "github.com/jetstack/cert-manager/internal/apis/meta/fuzzer"
+	metainstall "github.com/jetstack/cert-manager/internal/apis/meta/install"
+)
+
+// RoundTrip fuzzes every type of every API group and checks that converting
+// it between all API versions and serializing it in all supported formats
+// does not lose any information.
+func (s *Suite) RoundTrip(t *testing.T) {
+	t.Run("cert-manager.io", func(t *testing.T) {
+		roundtrip.RoundTripTestForAPIGroup(t, cminstall.Install, cmfuzzer.Funcs)
+	})
+	t.Run("acme.cert-manager.io", func(t *testing.T) {
+		roundtrip.RoundTripTestForAPIGroup(t, acmeinstall.Install, acmefuzzer.Funcs)
+	})
+	t.Run("meta.cert-manager.io", func(t *testing.T) {
+		roundtrip.RoundTripTestForAPIGroup(t, metainstall.Install, metafuzzer.Funcs)
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/yaml"
+
+	"github.com/jetstack/cert-manager/pkg/webhook"
+)
+
+// validationScenario is a resource and the expected result of validating its
+// creation in the webhook.
+type validationScenario struct {
+	// Valid is whether the resource is expected to pass validation.
+	Valid bool `json:"valid"`
+
+	// Errors are substrings of the validation errors that are expected if
+	// the resource is not valid. Every substring must be contained in the
+	// validation errors.
+	Errors []string `json:"errors,omitempty"`
+
+	// Warnings are substrings of the warnings that are expected to be
+	// returned when validating the resource.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Object is the resource to validate, in any API version.
+	Object runtime.RawExtension `json:"object"`
+}
+
+// Validation validates the creation of the resource of every validation
+// scenario as the webhook does, and checks the result against the
+// expectations of the scenario.
+func (s *Suite) Validation(t *testing.T) {
+	fsys := s.scenarios(t)
+	files, err := fs.Glob(fsys, "validation/*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no validation scenarios found")
+	}
+
+	decoder := serializer.NewCodecFactory(webhook.Scheme).UniversalDecoder()
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(path.Base(file), ".yaml"), func(t *testing.T) {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var scenario validationScenario
+			if err := yaml.UnmarshalStrict(data, &scenario); err != nil {
+				t.Fatalf("failed to parse scenario: %v", err)
+			}
+
+			obj, gvk, err := decoder.Decode(scenario.Object.Raw, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to decode object: %v", err)
+			}
+			req := &admissionv1.AdmissionRequest{
+				Operation:   admissionv1.Create,
+				RequestKind: &metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+				Object:      scenario.Object,
+			}
+			errs, warnings := webhook.ValidationRegistry.Validate(req, obj, *gvk)
+
+			if scenario.Valid != (len(errs) == 0) {
+				t.Fatalf("unexpected validation result, valid=%t, errors: %v", scenario.Valid, errs)
+			}
+			var errStr string
+			if agg := errs.ToAggregate(); agg != nil {
+				errStr = agg.Error()
+			}
+			for _, want := range scenario.Errors {
+				if !strings.Contains(errStr, want) {
+					t.Errorf("expected an error containing %q, got: %s", want, errStr)
+				}
+			}
+			warnStr := strings.Join(warnings, "\n")
+			for _, want := range scenario.Warnings {
+				if !strings.Contains(warnStr, want) {
+					t.Errorf("expected a warning containing %q, got: %v", want, warnings)
+				}
+			}
+		})
+	}
+}
@@ -256,6 +256,36 @@ func CertificateRef(certName, certUID string) metav1.OwnerReference {
 	)
 }
 
+func SetCertificateRevoke(revoke bool) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.Revoke = revoke
+	}
+}
+
+func SetCertificateRevokeOnDelete(revokeOnDelete bool) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.RevokeOnDelete = revokeOnDelete
+	}
+}
+
+func SetCertificateAllowPartialSANs(allow bool) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.AllowPartialSANs = allow
+	}
+}
+
+func SetCertificateDroppedSANs(names ...string) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Status.DroppedSANs = names
+	}
+}
+
+func SetCertificateRenewalRequest(request v1.CertificateRenewalRequest) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Status.RenewalRequest = &request
+	}
+}
+
 func SetCertificateRevisionHistoryLimit(limit int32) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.RevisionHistoryLimit = &limit
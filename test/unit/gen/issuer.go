@@ -21,6 +21,7 @@ import (
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type IssuerModifier func(v1.GenericIssuer)
@@ -226,6 +227,16 @@ func SetIssuerACMELastRegisteredEmail(email string) IssuerModifier {
 	}
 }
 
+func SetIssuerACMELastRegisteredEABKeyHash(hash string) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		status := iss.GetStatus()
+		if status.ACME == nil {
+			status.ACME = &cmacme.ACMEIssuerStatus{}
+		}
+		status.ACME.LastRegisteredEABKeyHash = hash
+	}
+}
+
 func SetIssuerCA(a v1.CAIssuer) IssuerModifier {
 	return func(iss v1.GenericIssuer) {
 		iss.GetSpec().CA = &a
@@ -351,3 +362,21 @@ func SetIssuerNamespace(namespace string) IssuerModifier {
 		iss.GetObjectMeta().Namespace = namespace
 	}
 }
+
+func SetIssuerUID(uid types.UID) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		iss.GetObjectMeta().UID = uid
+	}
+}
+
+func SetIssuerAllowWildcards(allow bool) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		iss.GetSpec().AllowWildcards = &allow
+	}
+}
+
+func SetIssuerSPIFFETrustDomains(trustDomains ...string) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		iss.GetSpec().SPIFFE = &v1.SPIFFEPolicy{TrustDomains: trustDomains}
+	}
+}
@@ -100,6 +100,12 @@ func SetOrderCommonName(commonName string) OrderModifier {
 	}
 }
 
+func SetOrderName(name string) OrderModifier {
+	return func(order *cmacme.Order) {
+		order.ObjectMeta.Name = name
+	}
+}
+
 func SetOrderNamespace(namespace string) OrderModifier {
 	return func(order *cmacme.Order) {
 		order.ObjectMeta.Namespace = namespace